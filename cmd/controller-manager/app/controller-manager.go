@@ -17,12 +17,16 @@ limitations under the License.
 package app
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"flag"
 	"fmt"
 	"net/http"
+	"net/http/pprof"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -43,13 +47,28 @@ import (
 	corev1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
 	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
 	"sigs.k8s.io/kubefed/pkg/controller/dnsendpoint"
+	"sigs.k8s.io/kubefed/pkg/controller/federatedevents"
 	"sigs.k8s.io/kubefed/pkg/controller/federatedtypeconfig"
 	"sigs.k8s.io/kubefed/pkg/controller/ingressdns"
+	"sigs.k8s.io/kubefed/pkg/controller/istio"
 	"sigs.k8s.io/kubefed/pkg/controller/kubefedcluster"
+	"sigs.k8s.io/kubefed/pkg/controller/propagationquota"
 	"sigs.k8s.io/kubefed/pkg/controller/schedulingmanager"
 	"sigs.k8s.io/kubefed/pkg/controller/servicedns"
 	"sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/controller/util/apidiscovery"
+	"sigs.k8s.io/kubefed/pkg/controller/util/circuitbreaker"
+	"sigs.k8s.io/kubefed/pkg/controller/util/deletionlimiter"
+	"sigs.k8s.io/kubefed/pkg/controller/util/diagnostics"
+	"sigs.k8s.io/kubefed/pkg/controller/util/dispatchlimiter"
+	"sigs.k8s.io/kubefed/pkg/controller/util/healthcheck"
+	"sigs.k8s.io/kubefed/pkg/controller/util/memberinformer"
+	"sigs.k8s.io/kubefed/pkg/controller/util/memberreads"
+	"sigs.k8s.io/kubefed/pkg/controller/util/notifier"
+	"sigs.k8s.io/kubefed/pkg/controller/util/ownership"
+	"sigs.k8s.io/kubefed/pkg/controller/util/quota"
 	"sigs.k8s.io/kubefed/pkg/features"
+	"sigs.k8s.io/kubefed/pkg/metrics"
 	"sigs.k8s.io/kubefed/pkg/version"
 )
 
@@ -98,8 +117,26 @@ func Run(opts *options.Options, stopChan <-chan struct{}) error {
 	logs.InitLogs()
 	defer logs.FlushLogs()
 
+	opts.Config.HealthChecks = healthcheck.New()
+	if len(opts.AdmissionWebhookHealthURL) > 0 {
+		opts.Config.HealthChecks.AddReadinessCheck("admission-webhook", healthcheck.HTTPGetCheck(opts.AdmissionWebhookHealthURL))
+	}
+
+	opts.Config.Diagnostics = diagnostics.New()
+	opts.Config.APIDiscovery = apidiscovery.New()
+	opts.Config.MemberReads = memberreads.New(util.DefaultMemberReadCacheTTL)
+	opts.Config.PropagationQuotas = quota.NewTracker()
+	opts.Config.OwnershipConflicts = ownership.NewTracker()
+	opts.Config.Diagnostics.AddSource("member-reads", opts.Config.MemberReads.Dump)
+	opts.Config.MemberInformers = memberinformer.New(func(key memberinformer.Key, obj interface{}) {
+		opts.Config.MemberReads.Invalidate(memberreads.Key{Cluster: key.Cluster, Resource: key.Resource}, obj)
+	})
+	opts.Config.Diagnostics.AddSource("member-informers", opts.Config.MemberInformers.Dump)
+	go dumpOnSignal(opts.Config.Diagnostics)
+
 	// TODO: Make healthz endpoint configurable
-	go serveHealthz(":8080")
+	mux := http.NewServeMux()
+	go serveHealthz(":8080", mux, opts.Config.HealthChecks)
 
 	var err error
 	opts.Config.KubeConfig, err = clientcmd.BuildConfigFromFlags(masterURL, kubeconfig)
@@ -107,7 +144,13 @@ func Run(opts *options.Options, stopChan <-chan struct{}) error {
 		panic(err)
 	}
 
-	setOptionsByKubeFedConfig(opts)
+	fedConfig := setOptionsByKubeFedConfig(opts)
+	go watchKubeFedConfig(opts.Config.KubeConfig, fedConfig, stopChan)
+
+	if opts.EnableProfiling {
+		klog.Info("Serving profiles at /debug/pprof/ and a diagnostic dump at /debug/dump")
+		serveDiagnostics(mux, opts.Config.Diagnostics)
+	}
 
 	if err := utilfeature.DefaultFeatureGate.SetFromMap(opts.FeatureGates); err != nil {
 		klog.Fatalf("Invalid Feature Gate: %v", err)
@@ -132,6 +175,10 @@ func Run(opts *options.Options, stopChan <-chan struct{}) error {
 	go func() {
 		select {
 		case <-stopChan:
+			// Leadership is retained while reconciliations in
+			// progress complete so that another replica cannot
+			// start reconciling the same resources.
+			drainReconciles(opts)
 			cancel()
 		case <-ctx.Done():
 		}
@@ -139,22 +186,59 @@ func Run(opts *options.Options, stopChan <-chan struct{}) error {
 
 	elector.Run(ctx)
 
+	select {
+	case <-stopChan:
+		releaseLeaderLock(elector)
+		return nil
+	default:
+	}
+
 	klog.Errorf("lost lease")
 	return errors.New("lost lease")
 }
 
+// drainReconciles prevents the controllers from starting new
+// reconciliations and waits for those in progress to complete.
+func drainReconciles(opts *options.Options) {
+	klog.Infof("Shutting down: waiting up to %v for reconciliations in progress to complete", opts.ShutdownTimeout)
+	if inFlight := util.DrainReconciles(opts.ShutdownTimeout); inFlight > 0 {
+		klog.Warningf("%d reconciliations did not complete before the shutdown timeout", inFlight)
+	}
+}
+
+// releaseLeaderLock releases the leader lock, if held, so that another
+// replica can take over without waiting for the lease to expire.
+func releaseLeaderLock(elector *leaderelection.KubeFedLeaderElector) {
+	released, err := elector.Release()
+	if err != nil {
+		klog.Errorf("Failed to release the leader lock: %v", err)
+		return
+	}
+	if released {
+		klog.Info("Released the leader lock")
+	}
+}
+
 func startControllers(opts *options.Options, stopChan <-chan struct{}) {
+	opts.Config.Notifier.Run(stopChan)
+
+	mgr, err := util.NewManager(opts.Config)
+	if err != nil {
+		klog.Fatalf("Error creating controller manager: %v", err)
+	}
+	opts.Config.Manager = mgr
+
 	if err := kubefedcluster.StartClusterController(opts.Config, opts.ClusterHealthCheckConfig, stopChan); err != nil {
 		klog.Fatalf("Error starting cluster controller: %v", err)
 	}
 
-	if utilfeature.DefaultFeatureGate.Enabled(features.SchedulerPreferences) {
+	if controllerEnabled(opts.Config, features.SchedulerPreferences, corev1b1.SchedulingManagerName) {
 		if _, err := schedulingmanager.StartSchedulingManager(opts.Config, stopChan); err != nil {
 			klog.Fatalf("Error starting scheduling manager: %v", err)
 		}
 	}
 
-	if utilfeature.DefaultFeatureGate.Enabled(features.CrossClusterServiceDiscovery) {
+	if controllerEnabled(opts.Config, features.CrossClusterServiceDiscovery, corev1b1.ServiceDNSName) {
 		if err := servicedns.StartController(opts.Config, stopChan); err != nil {
 			klog.Fatalf("Error starting dns controller: %v", err)
 		}
@@ -164,7 +248,7 @@ func startControllers(opts *options.Options, stopChan <-chan struct{}) {
 		}
 	}
 
-	if utilfeature.DefaultFeatureGate.Enabled(features.FederatedIngress) {
+	if controllerEnabled(opts.Config, features.FederatedIngress, corev1b1.IngressDNSName) {
 		if err := ingressdns.StartController(opts.Config, stopChan); err != nil {
 			klog.Fatalf("Error starting ingress dns controller: %v", err)
 		}
@@ -178,7 +262,35 @@ func startControllers(opts *options.Options, stopChan <-chan struct{}) {
 		if err := federatedtypeconfig.StartController(opts.Config, stopChan); err != nil {
 			klog.Fatalf("Error starting federated type config controller: %v", err)
 		}
+
+		if err := propagationquota.StartController(opts.Config, stopChan); err != nil {
+			klog.Fatalf("Error starting propagation quota controller: %v", err)
+		}
+	}
+
+	if controllerEnabled(opts.Config, features.FederatedEvents, corev1b1.FederatedEventsName) {
+		if err := federatedevents.StartController(opts.Config, stopChan); err != nil {
+			klog.Fatalf("Error starting federated events controller: %v", err)
+		}
 	}
+
+	if controllerEnabled(opts.Config, features.IstioMultiCluster, corev1b1.IstioMultiClusterName) {
+		if err := istio.StartController(opts.Config, stopChan); err != nil {
+			klog.Fatalf("Error starting Istio multi-cluster controller: %v", err)
+		}
+	}
+
+	// The manager is started once all controllers sharing it have
+	// been registered so that its cache starts the informers of all
+	// of them.
+	util.StartManager(mgr, stopChan)
+}
+
+// controllerEnabled indicates whether a controller should be started,
+// i.e. its feature gate is enabled and it is not switched off by the
+// KubeFedConfig.
+func controllerEnabled(config *util.ControllerConfig, feature utilfeature.Feature, name corev1b1.ControllerName) bool {
+	return utilfeature.DefaultFeatureGate.Enabled(feature) && config.ControllerEnabled(name)
 }
 
 func getKubeFedConfig(opts *options.Options) *corev1b1.KubeFedConfig {
@@ -237,8 +349,42 @@ func setInt64(target *int64, defaultValue int64) {
 	}
 }
 
-func setDefaultKubeFedConfig(fedConfig *corev1b1.KubeFedConfig) {
-	spec := &fedConfig.Spec
+// defaultConfigConfigured indicates whether a KubeFedConfig other
+// than that of the control plane is configured to provide defaults.
+func defaultConfigConfigured(opts *options.Options) bool {
+	namespace := opts.DefaultKubeFedConfigNamespace
+	return len(namespace) != 0 && namespace != opts.Config.KubeFedNamespace
+}
+
+// getDefaultKubeFedConfig retrieves the KubeFedConfig providing the
+// values of fields not set by the KubeFedConfig of the control plane,
+// if one is configured.
+func getDefaultKubeFedConfig(opts *options.Options) *corev1b1.KubeFedConfig {
+	if !defaultConfigConfigured(opts) {
+		return nil
+	}
+	namespace := opts.DefaultKubeFedConfigNamespace
+	qualifiedName := util.QualifiedName{
+		Namespace: namespace,
+		Name:      util.KubeFedConfigName,
+	}
+
+	fedConfig := &corev1b1.KubeFedConfig{}
+	client := genericclient.NewForConfigOrDieWithUserAgent(opts.Config.KubeConfig, "kubefedconfig")
+	err := client.Get(context.Background(), fedConfig, qualifiedName.Namespace, qualifiedName.Name)
+	if apierrors.IsNotFound(err) {
+		klog.Warningf("Default KubeFedConfig %q not found. Only the defaults of the controller manager are used.", qualifiedName.String())
+		return nil
+	}
+	if err != nil {
+		klog.Fatalf("Error retrieving default KubeFedConfig %q: %v.", qualifiedName.String(), err)
+	}
+
+	klog.Infof("Using KubeFedConfig %q for values not set by the KubeFedConfig of the control plane", qualifiedName.String())
+	return fedConfig
+}
+
+func setDefaultKubeFedConfig(spec *corev1b1.KubeFedConfigSpec) {
 
 	if len(spec.Scope) == 0 {
 		// TODO(sohankunkerkar) Remove when no longer necessary.
@@ -257,6 +403,9 @@ func setDefaultKubeFedConfig(fedConfig *corev1b1.KubeFedConfig) {
 	duration := &spec.ControllerDuration
 	setDuration(&duration.AvailableDelay, util.DefaultClusterAvailableDelay)
 	setDuration(&duration.UnavailableDelay, util.DefaultClusterUnavailableDelay)
+	if duration.MemberReadCacheTTL == nil {
+		duration.MemberReadCacheTTL = &metav1.Duration{Duration: util.DefaultMemberReadCacheTTL}
+	}
 
 	election := &spec.LeaderElect
 	if len(election.ResourceLock) == 0 {
@@ -275,6 +424,41 @@ func setDefaultKubeFedConfig(fedConfig *corev1b1.KubeFedConfig) {
 	if len(spec.SyncController.AdoptResources) == 0 {
 		spec.SyncController.AdoptResources = corev1b1.AdoptResourcesEnabled
 	}
+
+	if spec.SyncController.PropagationMetadata == nil {
+		spec.SyncController.PropagationMetadata = &corev1b1.PropagationMetadataConfig{}
+	}
+	propagationMetadata := spec.SyncController.PropagationMetadata
+	if len(propagationMetadata.OriginAnnotations) == 0 {
+		propagationMetadata.OriginAnnotations = corev1b1.ConfigurationEnabled
+	}
+	if len(propagationMetadata.TemplateHashAnnotation) == 0 {
+		propagationMetadata.TemplateHashAnnotation = corev1b1.ConfigurationEnabled
+	}
+
+	if deletionLimit := spec.SyncController.DeletionLimit; deletionLimit != nil && deletionLimit.Window == nil {
+		deletionLimit.Window = &metav1.Duration{Duration: deletionlimiter.DefaultWindow}
+	}
+
+	if circuitBreaker := spec.SyncController.CircuitBreaker; circuitBreaker != nil {
+		if circuitBreaker.InitialBackoff == nil {
+			circuitBreaker.InitialBackoff = &metav1.Duration{Duration: circuitbreaker.DefaultInitialBackoff}
+		}
+		if circuitBreaker.MaxBackoff == nil {
+			circuitBreaker.MaxBackoff = &metav1.Duration{Duration: circuitbreaker.DefaultMaxBackoff}
+		}
+	}
+
+	if spec.SyncController.DispatchConcurrency == nil {
+		spec.SyncController.DispatchConcurrency = &corev1b1.DispatchConcurrencyConfig{}
+	}
+	dispatchConcurrency := spec.SyncController.DispatchConcurrency
+	if dispatchConcurrency.MaxPerCluster == 0 {
+		dispatchConcurrency.MaxPerCluster = dispatchlimiter.DefaultMaxPerCluster
+	}
+	if dispatchConcurrency.MaxTotal == 0 {
+		dispatchConcurrency.MaxTotal = dispatchlimiter.DefaultMaxTotal
+	}
 }
 
 func updateKubeFedConfig(config *rest.Config, fedConfig *corev1b1.KubeFedConfig) {
@@ -293,7 +477,8 @@ func updateKubeFedConfig(config *rest.Config, fedConfig *corev1b1.KubeFedConfig)
 	}
 	if apierrors.IsNotFound(err) {
 		// if `--kubefed-config` is specifed but there is not KubeFedConfig resource accordingly
-		err = client.Create(context.Background(), fedConfig)
+		configResource = fedConfig.DeepCopy()
+		err = client.Create(context.Background(), configResource)
 		if err != nil {
 			klog.Fatalf("Error creating KubeFedConfig %q: %v", qualifiedName, err)
 		}
@@ -304,9 +489,23 @@ func updateKubeFedConfig(config *rest.Config, fedConfig *corev1b1.KubeFedConfig)
 			klog.Fatalf("Error updating KubeFedConfig %q: %v", qualifiedName, err)
 		}
 	}
+
+	configResource.Status = fedConfig.Status
+	configResource.Status.ObservedGeneration = configResource.Generation
+	err = updateKubeFedConfigStatus(client, configResource)
+	if err != nil {
+		klog.Fatalf("Error updating status of KubeFedConfig %q: %v", qualifiedName, err)
+	}
+	// The spec as returned by the API is retained for comparison with
+	// subsequent changes.
+	fedConfig.Spec = configResource.Spec
+	fedConfig.Status = configResource.Status
 }
 
-func setOptionsByKubeFedConfig(opts *options.Options) {
+// setOptionsByKubeFedConfig sets the options from the effective
+// configuration of the control plane and returns its KubeFedConfig as
+// updated with the configuration applied.
+func setOptionsByKubeFedConfig(opts *options.Options) *corev1b1.KubeFedConfig {
 	fedConfig := getKubeFedConfig(opts)
 	if fedConfig == nil {
 		// KubeFedConfig could not be sourced from --kubefed-config or from the API.
@@ -325,13 +524,32 @@ func setOptionsByKubeFedConfig(opts *options.Options) {
 		}
 	}
 
-	setDefaultKubeFedConfig(fedConfig)
+	// Fields not set by the KubeFedConfig of the control plane are
+	// sourced from the default KubeFedConfig, if any.  The spec is
+	// only updated with the values in effect if there is no default
+	// so that subsequent changes to the default are observed.
+	effectiveSpec := &fedConfig.Spec
+	fedConfig.Status.DefaultConfig = ""
+	defaultConfig := getDefaultKubeFedConfig(opts)
+	if defaultConfig != nil {
+		effectiveSpec = util.MergeKubeFedConfigSpec(&fedConfig.Spec, &defaultConfig.Spec)
+		fedConfig.Status.DefaultConfig = util.NewQualifiedName(defaultConfig).String()
+	}
+	setDefaultKubeFedConfig(effectiveSpec)
+	fedConfig.Status.EffectiveSpec = effectiveSpec.DeepCopy()
+
+	var featureGates = make(map[string]bool)
+	for _, v := range effectiveSpec.FeatureGates {
+		featureGates[v.Name] = v.Configuration == corev1b1.ConfigurationEnabled
+	}
 
-	spec := fedConfig.Spec
+	spec := *effectiveSpec
 	opts.Scope = spec.Scope
 
 	opts.Config.ClusterAvailableDelay = spec.ControllerDuration.AvailableDelay.Duration
 	opts.Config.ClusterUnavailableDelay = spec.ControllerDuration.UnavailableDelay.Duration
+	opts.Config.StatusUpdateInterval = spec.ControllerDuration.StatusUpdateInterval.Duration
+	opts.Config.MemberReads.SetTTL(spec.ControllerDuration.MemberReadCacheTTL.Duration)
 
 	opts.LeaderElection.ResourceLock = spec.LeaderElect.ResourceLock
 	opts.LeaderElection.RetryPeriod = spec.LeaderElect.RetryPeriod.Duration
@@ -342,21 +560,42 @@ func setOptionsByKubeFedConfig(opts *options.Options) {
 	opts.ClusterHealthCheckConfig.TimeoutSeconds = spec.ClusterHealthCheck.TimeoutSeconds
 	opts.ClusterHealthCheckConfig.FailureThreshold = spec.ClusterHealthCheck.FailureThreshold
 	opts.ClusterHealthCheckConfig.SuccessThreshold = spec.ClusterHealthCheck.SuccessThreshold
+	opts.Config.ClusterLifecycleHooks = spec.ClusterLifecycleHooks
 
 	opts.Config.SkipAdoptingResources = spec.SyncController.AdoptResources == corev1b1.AdoptResourcesDisabled
+	opts.Config.ReportAdoptableResources = spec.SyncController.AdoptResources == corev1b1.AdoptResourcesReportOnly
+	opts.Config.PropagationMetadata = spec.SyncController.PropagationMetadata
+	opts.Config.NamespaceMetadata = spec.SyncController.NamespaceMetadata
+	opts.Config.Scheduling = spec.Scheduling
+	opts.Config.AllowedClusterScopedResources = spec.AllowedClusterScopedResources
+
+	opts.Config.DisabledControllers = make(map[corev1b1.ControllerName]bool)
+	for _, controller := range spec.Controllers {
+		if controller.Configuration == corev1b1.ConfigurationDisabled {
+			klog.Infof("Controller %q is disabled by KubeFedConfig", controller.Name)
+			opts.Config.DisabledControllers[controller.Name] = true
+		}
+	}
+
+	if spec.Diagnostics != nil && spec.Diagnostics.Profiling == corev1b1.ConfigurationEnabled {
+		opts.EnableProfiling = true
+	}
 
+	opts.Config.Notifier = notifier.New(spec.Notifications)
+	opts.Config.DeletionLimiter = deletionlimiter.New(spec.SyncController.DeletionLimit,
+		genericclient.NewForConfigOrDieWithUserAgent(opts.Config.KubeConfig, "deletion-limiter"))
+	opts.Config.CircuitBreaker = circuitbreaker.New(spec.SyncController.CircuitBreaker)
+	opts.Config.DispatchLimiter = dispatchlimiter.New(spec.SyncController.DispatchConcurrency)
+
+	defaultConfigMissing := defaultConfigConfigured(opts) && defaultConfig == nil
+	setKubeFedConfigConditions(&fedConfig.Status, featureGates, defaultConfigMissing)
 	updateKubeFedConfig(opts.Config.KubeConfig, fedConfig)
 
-	var featureGates = make(map[string]bool)
-	for _, v := range fedConfig.Spec.FeatureGates {
-		featureGates[v.Name] = v.Configuration == corev1b1.ConfigurationEnabled
-	}
-	if len(featureGates) == 0 {
-		return
+	if len(featureGates) != 0 {
+		opts.FeatureGates = featureGates
+		klog.V(1).Infof("\"feature-gates\" will be set to %v", featureGates)
 	}
-
-	opts.FeatureGates = featureGates
-	klog.V(1).Infof("\"feature-gates\" will be set to %v", featureGates)
+	return fedConfig
 }
 
 // PrintFlags logs the flags in the flagset
@@ -366,11 +605,41 @@ func PrintFlags(flags *pflag.FlagSet) {
 	})
 }
 
-func serveHealthz(address string) {
-	http.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write([]byte("OK"))
-	})
+// serveHealthz serves the liveness and readiness of the controllers
+// registered with the given health checks.  Until leadership is
+// acquired no controller checks are registered, so that standby
+// replicas are reported healthy.
+//
+// A dedicated mux is used since the default mux serves the profiles
+// of the Go runtime to any importer of net/http/pprof.
+func serveHealthz(address string, mux *http.ServeMux, healthChecks *healthcheck.Registry) {
+	mux.Handle("/healthz", healthChecks.LivenessHandler())
+	mux.Handle("/readyz", healthChecks.ReadinessHandler())
+	mux.Handle("/metrics", metrics.Handler())
+
+	klog.Fatal(http.ListenAndServe(address, mux))
+}
 
-	klog.Fatal(http.ListenAndServe(address, nil))
+// serveDiagnostics adds the profiles of the Go runtime and the
+// diagnostic dump to the given mux.
+func serveDiagnostics(mux *http.ServeMux, registry *diagnostics.Registry) {
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/dump", registry.Handler())
+}
+
+// dumpOnSignal logs the diagnostic dump each time SIGUSR1 is
+// received, so that the state of a stuck replica can be captured
+// without enabling profiling.
+func dumpOnSignal(registry *diagnostics.Registry) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGUSR1)
+	for range signals {
+		var buf bytes.Buffer
+		registry.Dump(&buf)
+		klog.Infof("Diagnostic dump:\n%s", buf.String())
+	}
 }
@@ -0,0 +1,107 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog"
+
+	corev1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/kubefed/pkg/apis/core/v1beta1/validation"
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+// setKubeFedConfigConditions records in the given status whether the
+// effective configuration is valid, that it has been applied by this
+// controller manager, and whether part of it could not be honored.
+func setKubeFedConfigConditions(status *corev1b1.KubeFedConfigStatus, featureGates map[string]bool, defaultConfigMissing bool) {
+	now := metav1.Now()
+
+	errs := validation.ValidateKubeFedConfigSpec(status.EffectiveSpec, field.NewPath("spec"))
+	if err := utilfeature.DefaultFeatureGate.DeepCopy().SetFromMap(featureGates); err != nil {
+		errs = append(errs, field.Invalid(field.NewPath("spec", "featureGates"), featureGates, err.Error()))
+	}
+	if len(errs) == 0 {
+		util.SetKubeFedConfigCondition(status, corev1b1.KubeFedConfigValid, apiv1.ConditionTrue, "ValidationSucceeded", "", now)
+	} else {
+		util.SetKubeFedConfigCondition(status, corev1b1.KubeFedConfigValid, apiv1.ConditionFalse, "ValidationFailed", errs.ToAggregate().Error(), now)
+	}
+
+	hostname, _ := os.Hostname()
+	message := fmt.Sprintf("Applied by controller manager %q", hostname)
+	util.SetKubeFedConfigCondition(status, corev1b1.KubeFedConfigApplied, apiv1.ConditionTrue, "Applied", message, now)
+
+	if defaultConfigMissing {
+		util.SetKubeFedConfigCondition(status, corev1b1.KubeFedConfigDegraded, apiv1.ConditionTrue, "DefaultConfigNotFound",
+			"The default KubeFedConfig was not found. Only the defaults of the controller manager are used.", now)
+	} else {
+		util.SetKubeFedConfigCondition(status, corev1b1.KubeFedConfigDegraded, apiv1.ConditionFalse, "", "", now)
+	}
+}
+
+// updateKubeFedConfigStatus writes the status of the given
+// KubeFedConfig.  The status is written with the rest of the resource
+// if the status subresource is not enabled, as for a CRD installed by
+// a previous release.
+func updateKubeFedConfigStatus(client genericclient.Client, fedConfig *corev1b1.KubeFedConfig) error {
+	err := client.UpdateStatus(context.Background(), fedConfig)
+	if apierrors.IsNotFound(err) {
+		return client.Update(context.Background(), fedConfig)
+	}
+	return err
+}
+
+// watchKubeFedConfig marks the given KubeFedConfig as not applied once
+// its spec differs from the spec applied on startup, since the
+// controller manager does not reload its configuration.
+func watchKubeFedConfig(config *rest.Config, applied *corev1b1.KubeFedConfig, stopChan <-chan struct{}) {
+	client := genericclient.NewForConfigOrDieWithUserAgent(config, "kubefedconfig")
+	qualifiedName := util.NewQualifiedName(applied)
+	_, controller, err := util.NewGenericInformer(config, applied.Namespace, &corev1b1.KubeFedConfig{}, util.NoResyncPeriod,
+		func(obj pkgruntime.Object) {
+			fedConfig, ok := obj.(*corev1b1.KubeFedConfig)
+			if !ok || fedConfig.Name != applied.Name || equality.Semantic.DeepEqual(fedConfig.Spec, applied.Spec) {
+				return
+			}
+			fedConfig = fedConfig.DeepCopy()
+			changed := util.SetKubeFedConfigCondition(&fedConfig.Status, corev1b1.KubeFedConfigApplied, apiv1.ConditionFalse, "RestartRequired",
+				"The spec has changed since it was applied. Restart the controller manager to apply it.", metav1.Now())
+			if !changed {
+				return
+			}
+			if err := updateKubeFedConfigStatus(client, fedConfig); err != nil {
+				klog.Errorf("Error updating status of KubeFedConfig %q: %v", qualifiedName, err)
+			}
+		})
+	if err != nil {
+		klog.Errorf("Error watching KubeFedConfig %q: %v", qualifiedName, err)
+		return
+	}
+	controller.Run(stopChan)
+}
@@ -34,7 +34,21 @@ import (
 	"sigs.k8s.io/kubefed/cmd/controller-manager/app/options"
 )
 
-func NewKubeFedLeaderElector(opts *options.Options, fnStartControllers func(*options.Options, <-chan struct{})) (*leaderelection.LeaderElector, error) {
+// KubeFedLeaderElector is a leader elector whose lock can be released
+// on shutdown so that another replica can take over immediately
+// rather than once the lease has expired.
+type KubeFedLeaderElector struct {
+	*leaderelection.LeaderElector
+	lock *releasableLock
+}
+
+// Release releases the lock if it is held by this replica and
+// returns whether it was.
+func (e *KubeFedLeaderElector) Release() (bool, error) {
+	return e.lock.release()
+}
+
+func NewKubeFedLeaderElector(opts *options.Options, fnStartControllers func(*options.Options, <-chan struct{})) (*KubeFedLeaderElector, error) {
 	const component = "kubefed-controller-manager"
 	restclient.AddUserAgent(opts.Config.KubeConfig, "kubefed-leader-election")
 	leaderElectionClient := kubeclient.NewForConfigOrDie(opts.Config.KubeConfig)
@@ -65,8 +79,9 @@ func NewKubeFedLeaderElector(opts *options.Options, fnStartControllers func(*opt
 		return nil, err
 	}
 
-	return leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
-		Lock:          rl,
+	lock := &releasableLock{Interface: rl}
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:          lock,
 		LeaseDuration: opts.LeaderElection.LeaseDuration,
 		RenewDeadline: opts.LeaderElection.RenewDeadline,
 		RetryPeriod:   opts.LeaderElection.RetryPeriod,
@@ -82,4 +97,8 @@ func NewKubeFedLeaderElector(opts *options.Options, fnStartControllers func(*opt
 			},
 		},
 	})
+	if err != nil {
+		return nil, err
+	}
+	return &KubeFedLeaderElector{LeaderElector: elector, lock: lock}, nil
 }
@@ -0,0 +1,84 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package leaderelection
+
+import (
+	"github.com/pkg/errors"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+// releasableLock is a resource lock that can be released by its
+// holder.  A released lock has no holder, and is reported as not
+// existing to the leader elector so that it is acquired immediately
+// rather than once the lease of the released record has expired.
+type releasableLock struct {
+	resourcelock.Interface
+}
+
+func (l *releasableLock) Get() (*resourcelock.LeaderElectionRecord, error) {
+	record, err := l.Interface.Get()
+	if err != nil {
+		return nil, err
+	}
+	if len(record.HolderIdentity) == 0 {
+		return nil, apierrors.NewNotFound(schema.GroupResource{}, l.Describe())
+	}
+	return record, nil
+}
+
+// Create creates the lock, or acquires it if it exists but has been
+// released.
+func (l *releasableLock) Create(record resourcelock.LeaderElectionRecord) error {
+	existing, err := l.Interface.Get()
+	if apierrors.IsNotFound(err) {
+		return l.Interface.Create(record)
+	}
+	if err != nil {
+		return err
+	}
+	if len(existing.HolderIdentity) != 0 {
+		return errors.Errorf("lock %s is held by %s", l.Describe(), existing.HolderIdentity)
+	}
+	record.LeaderTransitions = existing.LeaderTransitions + 1
+	// The update fails with a conflict if another candidate acquired
+	// the lock since it was retrieved.
+	return l.Interface.Update(record)
+}
+
+// release releases the lock if it is held by this candidate and
+// returns whether it was.
+func (l *releasableLock) release() (bool, error) {
+	record, err := l.Interface.Get()
+	if err != nil {
+		return false, err
+	}
+	if record.HolderIdentity != l.Identity() {
+		return false, nil
+	}
+	now := metav1.Now()
+	err = l.Interface.Update(resourcelock.LeaderElectionRecord{
+		LeaseDurationSeconds: 1,
+		AcquireTime:          now,
+		RenewTime:            now,
+		LeaderTransitions:    record.LeaderTransitions,
+	})
+	return err == nil, err
+}
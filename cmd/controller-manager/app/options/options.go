@@ -18,6 +18,8 @@ limitations under the License.
 package options
 
 import (
+	"time"
+
 	"github.com/spf13/pflag"
 
 	apiextv1b1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
@@ -32,11 +34,31 @@ type Options struct {
 	Scope                    apiextv1b1.ResourceScope
 	LeaderElection           *util.LeaderElectionConfiguration
 	ClusterHealthCheckConfig *util.ClusterHealthCheckConfig
+
+	// The namespace of the KubeFedConfig providing the values of
+	// fields not set by the KubeFedConfig of the control plane.
+	DefaultKubeFedConfigNamespace string
+
+	// The URL of the health endpoint of the KubeFed admission
+	// webhook to include in the readiness of the controller manager.
+	AdmissionWebhookHealthURL string
+
+	// Whether to serve the profiles of the Go runtime and a dump of
+	// the state of the controllers.  Also enabled by the KubeFedConfig.
+	EnableProfiling bool
+
+	// The time to wait on shutdown for reconciliations in progress
+	// to complete before releasing the leader lock.
+	ShutdownTimeout time.Duration
 }
 
 // AddFlags adds flags to fs and binds them to options.
 func (o *Options) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&o.Config.KubeFedNamespace, "kubefed-namespace", util.DefaultKubeFedSystemNamespace, "The namespace the KubeFed control plane is deployed in.")
+	fs.StringVar(&o.DefaultKubeFedConfigNamespace, "default-kubefed-config-namespace", "", "The namespace of a KubeFedConfig providing the values of fields not set by the KubeFedConfig of the control plane.")
+	fs.StringVar(&o.AdmissionWebhookHealthURL, "admission-webhook-health-url", "", "The URL of the health endpoint of the KubeFed admission webhook. If set, /readyz fails while the webhook is not serving.")
+	fs.DurationVar(&o.ShutdownTimeout, "shutdown-timeout", 20*time.Second, "The time to wait on shutdown for reconciliations in progress to complete before releasing the leader lock. Should be less than the termination grace period of the pod.")
+	fs.BoolVar(&o.EnableProfiling, "enable-profiling", false, "Serve the profiles of the Go runtime at /debug/pprof/ and a dump of the state of the controllers at /debug/dump regardless of the KubeFedConfig.")
 }
 
 func NewOptions() *Options {
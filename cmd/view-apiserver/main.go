@@ -0,0 +1,41 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"flag"
+
+	genericapiserver "k8s.io/apiserver/pkg/server"
+	"k8s.io/apiserver/pkg/util/logs"
+	"k8s.io/klog"
+
+	"sigs.k8s.io/kubefed/pkg/views"
+)
+
+func main() {
+	logs.InitLogs()
+	defer logs.FlushLogs()
+
+	stopChan := genericapiserver.SetupSignalHandler()
+
+	cmd := views.NewViewAPIServerCommand(stopChan)
+	cmd.Flags().AddGoFlagSet(flag.CommandLine)
+
+	if err := cmd.Execute(); err != nil {
+		klog.Fatal(err)
+	}
+}
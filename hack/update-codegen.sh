@@ -0,0 +1,57 @@
+#!/usr/bin/env bash
+
+# Copyright 2019 The Kubernetes Authors.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+# This script generates the typed clientset, listers and informers of
+# the KubeFed API types in pkg/client. It must be run from a checkout
+# in the GOPATH.
+
+set -eou pipefail
+
+SCRIPT_ROOT="$(cd "$(dirname "${BASH_SOURCE[0]}")" && pwd)"
+REPO_ROOT="$(cd "${SCRIPT_ROOT}/.." && pwd)"
+pushd ${REPO_ROOT} > /dev/null
+
+ROOT_PACKAGE="sigs.k8s.io/kubefed"
+APIS_PACKAGE="${ROOT_PACKAGE}/pkg/apis"
+CLIENT_PACKAGE="${ROOT_PACKAGE}/pkg/client"
+GROUP_VERSIONS="core/v1alpha1 core/v1beta1 multiclusterdns/v1alpha1 scheduling/v1alpha1"
+BOILERPLATE="${REPO_ROOT}/hack/boilerplate.go.txt"
+
+INPUT_DIRS=""
+for group_version in ${GROUP_VERSIONS}; do
+  INPUT_DIRS="${INPUT_DIRS:+${INPUT_DIRS},}${APIS_PACKAGE}/${group_version}"
+done
+
+go run ./vendor/k8s.io/code-generator/cmd/client-gen \
+  --clientset-name versioned \
+  --input-base "${APIS_PACKAGE}" \
+  --input "$(echo ${GROUP_VERSIONS} | tr ' ' ',')" \
+  --output-package "${CLIENT_PACKAGE}/clientset" \
+  --go-header-file "${BOILERPLATE}"
+
+go run ./vendor/k8s.io/code-generator/cmd/lister-gen \
+  --input-dirs "${INPUT_DIRS}" \
+  --output-package "${CLIENT_PACKAGE}/listers" \
+  --go-header-file "${BOILERPLATE}"
+
+go run ./vendor/k8s.io/code-generator/cmd/informer-gen \
+  --input-dirs "${INPUT_DIRS}" \
+  --versioned-clientset-package "${CLIENT_PACKAGE}/clientset/versioned" \
+  --listers-package "${CLIENT_PACKAGE}/listers" \
+  --output-package "${CLIENT_PACKAGE}/informers" \
+  --go-header-file "${BOILERPLATE}"
+
+popd > /dev/null
@@ -24,6 +24,36 @@ const (
 	ClusterReady ClusterConditionType = "Ready"
 	// ClusterOffline means the cluster is temporarily down or not reachable
 	ClusterOffline ClusterConditionType = "Offline"
+	// ClusterReachable means the API server of the cluster responded
+	// to the most recent health check, whether or not it reported
+	// itself healthy.
+	ClusterReachable ClusterConditionType = "Reachable"
+	// ClusterDegraded means the dispatch of resources to the cluster
+	// is backed off since requests to its API persistently fail, or
+	// that the result of its health check is flapping.
+	ClusterDegraded ClusterConditionType = "Degraded"
+	// ClusterRateLimited means requests to the API server of the
+	// cluster have recently been throttled (e.g. by API priority and
+	// fairness).
+	ClusterRateLimited ClusterConditionType = "RateLimited"
+	// ClusterClockSkew means the clock of the API server of the
+	// cluster differs significantly from that of the controller
+	// manager.
+	ClusterClockSkew ClusterConditionType = "ClockSkew"
+	// ClusterApproved means the cluster has been approved by an
+	// administrator for use by KubeFed.  It is set by administrators
+	// rather than by the cluster controller and is only required if
+	// the ClusterJoinApproval feature is enabled.
+	ClusterApproved ClusterConditionType = "Approved"
+	// ClusterLifecycleHooksSucceeded means the lifecycle hooks run for
+	// the most recent events of the cluster have succeeded.  It is
+	// Unknown while hooks are running, and is only set if lifecycle
+	// hooks are configured.
+	ClusterLifecycleHooksSucceeded ClusterConditionType = "LifecycleHooksSucceeded"
+	// ClusterDeletionsPaused means the sync controller has paused
+	// deletions from the cluster since they exceeded the deletion
+	// limit.  It is removed once the pause is acknowledged.
+	ClusterDeletionsPaused ClusterConditionType = "DeletionsPaused"
 )
 
 const (
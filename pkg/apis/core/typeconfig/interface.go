@@ -18,6 +18,8 @@ package typeconfig
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
 )
 
 // Interface defines how to interact with a FederatedTypeConfig
@@ -26,9 +28,16 @@ type Interface interface {
 	GetTargetType() metav1.APIResource
 	GetNamespaced() bool
 	GetPropagationEnabled() bool
+	GetPropagationPaused() bool
 	GetFederatedType() metav1.APIResource
 	GetStatusType() *metav1.APIResource
 	GetStatusEnabled() bool
 	GetFederatedNamespaced() bool
 	IsNamespace() bool
+	GetDefaultOverrides() []v1beta1.DefaultOverride
+	GetValuesConfig() *v1beta1.ValuesConfig
+	GetLocallyManagedFields() []string
+	GetMemberEventFilter() *v1beta1.MemberEventFilter
+	GetStatusUpdateInterval() *metav1.Duration
+	GetTargetVersions() []string
 }
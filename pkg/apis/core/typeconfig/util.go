@@ -22,6 +22,28 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+const (
+	// GenericFederatedGroup and GenericFederatedKind identify the
+	// generic FederatedObject type.  A FederatedObject can wrap a
+	// target object of any namespaced type that is configured for
+	// propagation by a FederatedTypeConfig whose federated type is
+	// FederatedObject.
+	GenericFederatedGroup   = "types.kubefed.k8s.io"
+	GenericFederatedVersion = "v1beta1"
+	GenericFederatedKind    = "FederatedObject"
+	// GenericFederatedPluralName is the plural name of the generic
+	// FederatedObject type.
+	GenericFederatedPluralName = "federatedobjects"
+)
+
+// IsGenericFederatedType indicates whether the federated type of the
+// given type config is the generic FederatedObject type rather than a
+// type dedicated to its target type.
+func IsGenericFederatedType(typeConfig Interface) bool {
+	federatedType := typeConfig.GetFederatedType()
+	return federatedType.Kind == GenericFederatedKind && federatedType.Group == GenericFederatedGroup
+}
+
 // GroupQualifiedName returns the plural name of the api resource
 // optionally qualified by its group:
 //
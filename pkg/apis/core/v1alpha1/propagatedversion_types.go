@@ -41,6 +41,10 @@ type ClusterObjectVersion struct {
 	// The last version produced for the resource by a KubeFed
 	// operation.
 	Version string `json:"version"`
+	// The name of the resource in the cluster, if it was renamed by
+	// an override.
+	// +optional
+	Name string `json:"name,omitempty"`
 }
 
 // +genclient
@@ -0,0 +1,163 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DispatchPolicySpec defines the checks a resource rendered for a
+// member cluster must pass before it is propagated to the cluster.
+type DispatchPolicySpec struct {
+	// TargetKinds restricts the policy to target resources of the
+	// given kinds (e.g. `Deployment`). If not specified, the policy
+	// applies to resources of all kinds.
+	// +optional
+	TargetKinds []string `json:"targetKinds,omitempty"`
+
+	// ClusterSelector restricts the policy to member clusters whose
+	// labels match the selector. If not specified, the policy applies
+	// to all member clusters.
+	// +optional
+	ClusterSelector *metav1.LabelSelector `json:"clusterSelector,omitempty"`
+
+	// JSONPathRules are checks of the values selected from a resource
+	// by JSONPath expressions that the resource must satisfy to be
+	// propagated.
+	// +optional
+	JSONPathRules []DispatchPolicyJSONPathRule `json:"jsonPathRules,omitempty"`
+
+	// Rules are CEL expressions that must evaluate to true for a
+	// resource to be propagated.  An expression refers to the resource
+	// rendered for a member cluster as `object` and to the
+	// KubeFedCluster of the cluster as `cluster`.
+	// +optional
+	Rules []DispatchPolicyRule `json:"rules,omitempty"`
+
+	// Webhook is an external validating admission webhook (e.g. an
+	// OPA/Gatekeeper service) that must allow a resource for it to be
+	// propagated.  The webhook is only called if the resource
+	// satisfies the JSONPath and CEL rules of the policy.
+	// +optional
+	Webhook *DispatchPolicyWebhook `json:"webhook,omitempty"`
+}
+
+// DispatchPolicyJSONPathRule checks the values of a resource selected
+// by a JSONPath expression.  Rules are limited to matching selected
+// values against a pattern or checking their presence.  Checks that
+// compare values with each other or with the cluster are expressed
+// with CEL rules.
+type DispatchPolicyJSONPathRule struct {
+	// Name identifies the rule in propagation errors.
+	Name string `json:"name"`
+	// Path is a JSONPath expression selecting values of the resource
+	// (e.g. `{.spec.template.spec.containers[*].image}`).
+	Path string `json:"path"`
+	// Operator determines how the selected values are checked.
+	Operator DispatchPolicyOperator `json:"operator"`
+	// Pattern is the regular expression that values are matched
+	// against by the `Matches` and `DoesNotMatch` operators.
+	// +optional
+	Pattern string `json:"pattern,omitempty"`
+	// Message describes the violation of the rule.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// DispatchPolicyRule checks a resource with a CEL expression
+// (https://github.com/google/cel-go).
+type DispatchPolicyRule struct {
+	// Name identifies the rule in propagation errors.
+	Name string `json:"name"`
+	// Expression is a CEL expression evaluating to a bool (e.g.
+	// `object.spec.replicas <= int(cluster.metadata.labels['max-replicas'])`).
+	Expression string `json:"expression"`
+	// Message describes the violation of the rule.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// DispatchPolicyWebhook describes an external validating admission
+// webhook. The webhook is sent an `admission.k8s.io/v1beta1`
+// AdmissionReview for the resource rendered for a member cluster.
+type DispatchPolicyWebhook struct {
+	// URL of the webhook in the form `https://host:port/path`.
+	URL string `json:"url"`
+	// CABundle is a PEM encoded CA bundle used to verify the serving
+	// certificate of the webhook. If not specified, the system trust
+	// roots are used.
+	// +optional
+	CABundle []byte `json:"caBundle,omitempty"`
+	// TimeoutSeconds is the time to wait for a response from the
+	// webhook. Defaults to 10 seconds.
+	// +optional
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+	// FailurePolicy determines whether a resource is propagated if
+	// the webhook cannot be called or returns an invalid response.
+	// `Fail` (default) prevents propagation and `Ignore` allows it.
+	// +optional
+	FailurePolicy DispatchPolicyFailurePolicy `json:"failurePolicy,omitempty"`
+}
+
+type DispatchPolicyFailurePolicy string
+
+const (
+	DispatchPolicyFail   DispatchPolicyFailurePolicy = "Fail"
+	DispatchPolicyIgnore DispatchPolicyFailurePolicy = "Ignore"
+)
+
+type DispatchPolicyOperator string
+
+const (
+	// Every selected value must match the pattern.
+	DispatchPolicyMatches DispatchPolicyOperator = "Matches"
+	// No selected value may match the pattern.
+	DispatchPolicyDoesNotMatch DispatchPolicyOperator = "DoesNotMatch"
+	// At least one value must be selected.
+	DispatchPolicyExists DispatchPolicyOperator = "Exists"
+	// No value may be selected.
+	DispatchPolicyDoesNotExist DispatchPolicyOperator = "DoesNotExist"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// DispatchPolicy is evaluated against resources rendered for member
+// clusters before they are propagated.  A resource that violates the
+// policy is not propagated to the cluster.
+//
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:path=dispatchpolicies
+type DispatchPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec DispatchPolicySpec `json:"spec"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// DispatchPolicyList contains a list of DispatchPolicy
+type DispatchPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DispatchPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DispatchPolicy{}, &DispatchPolicyList{})
+}
@@ -32,7 +32,17 @@ type FederatedTypeConfigSpec struct {
 	// groupName fields will be set from the metadata.name of this resource. The
 	// kind field must be set.
 	TargetType APIResource `json:"targetType"`
+	// Additional versions of the target type that are acceptable in
+	// member clusters, in order of preference. Resources are
+	// propagated to a member cluster with the version of targetType
+	// if the cluster serves it, and otherwise with the first of these
+	// versions that the cluster serves.
+	// +optional
+	TargetVersions []string `json:"targetVersions,omitempty"`
 	// Whether or not propagation to member clusters should be enabled.
+	// Propagation that is Paused continues to observe member clusters
+	// and report the status of federated resources, but does not
+	// write to member clusters until it is Enabled again.
 	Propagation PropagationMode `json:"propagation"`
 	// Configuration for the federated type that defines (via
 	// template, placement and overrides fields) how the target type
@@ -47,8 +57,104 @@ type FederatedTypeConfigSpec struct {
 	// Whether or not Status object should be populated.
 	// +optional
 	StatusCollection *StatusCollectionMode `json:"statusCollection,omitempty"`
+	// Minimum time between updates of the status of the federated
+	// resources of this type, overriding the statusUpdateInterval of
+	// the KubeFedConfig.  Status updates of the type are not
+	// coalesced if set to 0s.
+	// +optional
+	StatusUpdateInterval *metav1.Duration `json:"statusUpdateInterval,omitempty"`
+	// Transformations applied to every resource of the target type
+	// before it is propagated to member clusters.  Default overrides
+	// are applied in order and before the overrides of individual
+	// federated resources.
+	// +optional
+	DefaultOverrides []DefaultOverride `json:"defaultOverrides,omitempty"`
+	// Configuration of the chart values of a target type that
+	// deploys a Helm chart (e.g. a Flux HelmRelease or an Argo CD
+	// Application).  If set, the overrides of a federated resource
+	// may provide a values file per cluster that is merged into the
+	// values of the template.
+	// +optional
+	Values *ValuesConfig `json:"values,omitempty"`
+	// Dot-separated paths (e.g. .spec.replicas or .metadata.finalizers)
+	// of fields that are managed by controllers in member clusters.
+	// The values of these fields in member clusters are preserved when
+	// resources of the target type are updated.
+	// +optional
+	LocallyManagedFields []string `json:"locallyManagedFields,omitempty"`
+	// Filters of the changes of resources of the target type in
+	// member clusters.  A change that is filtered out does not
+	// trigger the reconciliation of the federated resource, but
+	// drift it introduces is still corrected the next time the
+	// resource is reconciled.  If not set, every change triggers
+	// reconciliation.
+	// +optional
+	MemberEventFilter *MemberEventFilter `json:"memberEventFilter,omitempty"`
 }
 
+// MemberEventFilter defines which changes of resources in member
+// clusters are ignored by the sync controller.
+type MemberEventFilter struct {
+	// Whether changes that only affect the status of a resource are
+	// ignored.  Status changes are not ignored while raw resource
+	// status collection is enabled.
+	// +optional
+	IgnoreStatusChanges bool `json:"ignoreStatusChanges,omitempty"`
+	// Keys of the annotations whose changes are ignored
+	// (e.g. those updated by leader election heartbeats).
+	// +optional
+	IgnoredAnnotations []string `json:"ignoredAnnotations,omitempty"`
+}
+
+// DefaultOverride defines a transformation of the target resource
+// that applies to all clusters.
+type DefaultOverride struct {
+	// Dot-separated path of the field in the target resource
+	// (e.g. spec.template.spec.nodeName).
+	Path string `json:"path"`
+	// The operation to perform on the field. Supported options are
+	// `Set` (default) and `Remove`.
+	// +optional
+	Operation DefaultOverrideOperation `json:"operation,omitempty"`
+	// The string value to set. Required for the `Set` operation.
+	// +optional
+	Value *string `json:"value,omitempty"`
+}
+
+// ValuesConfig defines where the chart values of a target resource
+// are stored and how they are validated.
+type ValuesConfig struct {
+	// Dot-separated path of the field holding the chart values
+	// (e.g. spec.values).
+	Path string `json:"path"`
+	// The format of the field. Supported options are `Object`
+	// (default) for a field holding the values as an object and
+	// `YAML` for a string field holding a values file (e.g.
+	// spec.source.helm.values of an Argo CD Application).
+	// +optional
+	Format ValuesFormat `json:"format,omitempty"`
+	// A JSON schema (e.g. the values.schema.json of the chart) that
+	// the values propagated to every cluster must satisfy.
+	// +optional
+	Schema string `json:"schema,omitempty"`
+}
+
+// ValuesFormat defines how chart values are stored in a target resource.
+type ValuesFormat string
+
+const (
+	ValuesFormatObject ValuesFormat = "Object"
+	ValuesFormatYAML   ValuesFormat = "YAML"
+)
+
+// DefaultOverrideOperation defines how a default override modifies a field.
+type DefaultOverrideOperation string
+
+const (
+	DefaultOverrideSet    DefaultOverrideOperation = "Set"
+	DefaultOverrideRemove DefaultOverrideOperation = "Remove"
+)
+
 // APIResource defines how to configure the dynamic client for an API resource.
 type APIResource struct {
 	// metav1.GroupVersion is not used since the json annotation of
@@ -75,6 +181,7 @@ type PropagationMode string
 const (
 	PropagationEnabled  PropagationMode = "Enabled"
 	PropagationDisabled PropagationMode = "Disabled"
+	PropagationPaused   PropagationMode = "Paused"
 )
 
 // StatusCollectionMode defines the state of status collection.
@@ -206,6 +313,10 @@ func (f *FederatedTypeConfig) GetPropagationEnabled() bool {
 	return f.Spec.Propagation == PropagationEnabled
 }
 
+func (f *FederatedTypeConfig) GetPropagationPaused() bool {
+	return f.Spec.Propagation == PropagationPaused
+}
+
 func (f *FederatedTypeConfig) GetFederatedType() metav1.APIResource {
 	return apiResourceToMeta(f.Spec.FederatedType, f.GetFederatedNamespaced())
 }
@@ -239,6 +350,38 @@ func (f *FederatedTypeConfig) GetFederatedNamespaced() bool {
 	return f.GetNamespaced()
 }
 
+func (f *FederatedTypeConfig) GetDefaultOverrides() []DefaultOverride {
+	return f.Spec.DefaultOverrides
+}
+
+func (f *FederatedTypeConfig) GetValuesConfig() *ValuesConfig {
+	return f.Spec.Values
+}
+
+func (f *FederatedTypeConfig) GetLocallyManagedFields() []string {
+	return f.Spec.LocallyManagedFields
+}
+
+func (f *FederatedTypeConfig) GetMemberEventFilter() *MemberEventFilter {
+	return f.Spec.MemberEventFilter
+}
+
+func (f *FederatedTypeConfig) GetStatusUpdateInterval() *metav1.Duration {
+	return f.Spec.StatusUpdateInterval
+}
+
+// GetTargetVersions returns the versions of the target type that are
+// acceptable in member clusters, in order of preference.
+func (f *FederatedTypeConfig) GetTargetVersions() []string {
+	versions := []string{f.Spec.TargetType.Version}
+	for _, version := range f.Spec.TargetVersions {
+		if version != f.Spec.TargetType.Version {
+			versions = append(versions, version)
+		}
+	}
+	return versions
+}
+
 func (f *FederatedTypeConfig) IsNamespace() bool {
 	return f.Name == common.NamespaceName
 }
@@ -29,22 +29,143 @@ type KubeFedClusterSpec struct {
 	// hostname:port, IP or IP:port.
 	APIEndpoint string `json:"apiEndpoint"`
 
+	// SecondaryAPIEndpoints are alternative API endpoints of the member
+	// cluster (e.g. of additional load balancers or of an internal
+	// address) that are used in order when the primary endpoint is not
+	// reachable. The primary endpoint is used again once it becomes
+	// reachable.
+	// +optional
+	SecondaryAPIEndpoints []string `json:"secondaryAPIEndpoints,omitempty"`
+
 	// CABundle contains the certificate authority information.
 	// +optional
 	CABundle []byte `json:"caBundle,omitempty"`
 
 	// Name of the secret containing the token required to access the
 	// member cluster. The secret needs to exist in the same namespace
-	// as the control plane and should have a "token" key.
+	// as the control plane, unless another namespace is given, and
+	// should have a "token" key.  The secret is not used for a local
+	// cluster.
 	SecretRef LocalSecretReference `json:"secretRef"`
+
+	// Local indicates that the member cluster is the host cluster.  A
+	// local cluster is accessed with the in-cluster configuration of
+	// the control plane rather than via its api endpoint and the token
+	// of its secret.
+	// +optional
+	Local bool `json:"local,omitempty"`
+
+	// NamespaceMappings allows resources in a namespace of the host
+	// cluster to be propagated to a namespace with a different name
+	// in the member cluster.  Namespaces that are not mapped are
+	// propagated with the same name.
+	// +optional
+	NamespaceMappings []NamespaceMapping `json:"namespaceMappings,omitempty"`
+
+	// AllowedNamespaces restricts the namespaces of the member cluster
+	// that resources may be propagated to.  If empty, resources may be
+	// propagated to any namespace that is not denied.
+	// +optional
+	AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+
+	// DeniedNamespaces are namespaces of the member cluster that
+	// resources may not be propagated to.  A denied namespace is not
+	// propagated to even if it is also allowed.
+	// +optional
+	DeniedNamespaces []string `json:"deniedNamespaces,omitempty"`
+
+	// Taints of the cluster.  Replicas scheduled by a
+	// ReplicaSchedulingPreference are not assigned to the cluster if
+	// it has a taint with the NoSchedule or NoExecute effect that is
+	// not tolerated by the preference.
+	// +optional
+	Taints []apiv1.Taint `json:"taints,omitempty"`
+
+	// ProfileRef names a ConfigMap in the namespace of the control
+	// plane holding the values of the cluster (e.g. the host of its
+	// image registry or its domain suffix) that the overrides of
+	// federated resources may reference.  The ConfigMap is readable
+	// through the overrides of every federated resource and should
+	// not hold sensitive values.
+	// +optional
+	ProfileRef *apiv1.LocalObjectReference `json:"profileRef,omitempty"`
+
+	// HealthProbes are application-level probes of the cluster that
+	// are run by the cluster health check of a ready cluster in
+	// addition to requesting /healthz.  A cluster whose probe fails
+	// remains ready, so that resources continue to be propagated to
+	// it, but has a Degraded condition, which excludes it from the
+	// scheduling of replicas by the Degraded scheduling filter.
+	// +optional
+	HealthProbes []ClusterHealthProbe `json:"healthProbes,omitempty"`
+}
+
+// ClusterHealthProbe is an application-level probe of a member
+// cluster.  Exactly one of service and deployment must be set.
+type ClusterHealthProbe struct {
+	// Name of the probe, which identifies it in the Degraded
+	// condition of the cluster.
+	Name string `json:"name"`
+	// Service probes a service of the cluster with a GET request
+	// through the proxy of the API server of the cluster.
+	// +optional
+	Service *ClusterServiceProbe `json:"service,omitempty"`
+	// Deployment probes a canary Deployment of the cluster.
+	// +optional
+	Deployment *ClusterDeploymentProbe `json:"deployment,omitempty"`
+}
+
+// ClusterServiceProbe is a probe that succeeds if a service of the
+// cluster responds to a GET request with a 2xx status code.
+type ClusterServiceProbe struct {
+	// Namespace of the service.
+	Namespace string `json:"namespace"`
+	// Name of the service.
+	Name string `json:"name"`
+	// Port of the service, either its name or its number.  Defaults
+	// to the only port of the service.
+	// +optional
+	Port string `json:"port,omitempty"`
+	// Scheme of the request, http or https.  Defaults to http.
+	// +optional
+	Scheme string `json:"scheme,omitempty"`
+	// Path of the request.  Defaults to /.
+	// +optional
+	Path string `json:"path,omitempty"`
+}
+
+// ClusterDeploymentProbe is a probe that succeeds if a Deployment
+// exists in the cluster and all of its replicas are available.
+type ClusterDeploymentProbe struct {
+	// Namespace of the Deployment.
+	Namespace string `json:"namespace"`
+	// Name of the Deployment.
+	Name string `json:"name"`
+}
+
+// NamespaceMapping maps a namespace of the host cluster to a
+// namespace of a member cluster.
+type NamespaceMapping struct {
+	// Name of the namespace in the host cluster.
+	Namespace string `json:"namespace"`
+	// Name of the namespace in the member cluster that resources in
+	// the host cluster namespace will be propagated to.
+	ClusterNamespace string `json:"clusterNamespace"`
 }
 
 // LocalSecretReference is a reference to a secret within the enclosing
-// namespace.
+// namespace or, if one is given, within another namespace of the host
+// cluster.
 type LocalSecretReference struct {
 	// Name of a secret within the enclosing
 	// namespace
 	Name string `json:"name"`
+	// Namespace of the secret, if not the enclosing namespace.  The
+	// user creating or updating the cluster must be permitted to get
+	// the secret, and the controller manager must be granted access
+	// to secrets in the namespace.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
 }
 
 // KubeFedClusterStatus contains information about the current status of a
@@ -58,6 +179,172 @@ type KubeFedClusterStatus struct {
 	// Region is the name of the region in which all of the nodes in the cluster exist.  e.g. 'us-east1'.
 	// +optional
 	Region string `json:"region,omitempty"`
+	// KubernetesVersion is the version reported by the API server of the cluster, e.g. 'v1.13.4'.
+	// +optional
+	KubernetesVersion string `json:"kubernetesVersion,omitempty"`
+	// Provider is the infrastructure provider of the cluster detected from its nodes, e.g. 'gke' or 'aws'.
+	// +optional
+	Provider string `json:"provider,omitempty"`
+	// APIEndpoint is the endpoint last found to be reachable by the
+	// cluster health check, which is used to access the cluster.
+	// +optional
+	APIEndpoint string `json:"apiEndpoint,omitempty"`
+	// APIHealth reports the responsiveness of the API server of the
+	// cluster as observed by the cluster health check.
+	// +optional
+	APIHealth *ClusterAPIHealth `json:"apiHealth,omitempty"`
+	// HealthCheckFailure describes why the most recent health checks
+	// of the cluster failed.  It is set as soon as a health check
+	// fails, before the cluster is considered not ready, and is
+	// removed once a health check succeeds.
+	// +optional
+	HealthCheckFailure *ClusterHealthCheckFailure `json:"healthCheckFailure,omitempty"`
+	// Pressure reports signals that the cluster is unable to run
+	// additional pods.  It is only collected when the ClusterPressure
+	// feature is enabled.
+	// +optional
+	Pressure *ClusterPressure `json:"pressure,omitempty"`
+	// LifecycleHooks records the most recent run of each lifecycle
+	// hook configured by the KubeFedConfig for each of its events.
+	// +optional
+	LifecycleHooks []ClusterLifecycleHookStatus `json:"lifecycleHooks,omitempty"`
+}
+
+// ClusterLifecycleHookStatus describes the run of a lifecycle hook
+// for an event of the cluster.
+type ClusterLifecycleHookStatus struct {
+	// Name of the hook.
+	Name string `json:"name"`
+	// Event that ran the hook.
+	Event ClusterLifecycleEvent `json:"event"`
+	// Phase of the run, Running, Succeeded or Failed.
+	Phase ClusterLifecycleHookPhase `json:"phase"`
+	// Time at which the run started.
+	StartTime metav1.Time `json:"startTime"`
+	// Time at which the run succeeded or failed.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// Name of the Job created by the run of a job hook.
+	// +optional
+	JobName string `json:"jobName,omitempty"`
+	// Human readable message indicating why the run failed.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+type ClusterLifecycleHookPhase string
+
+const (
+	ClusterLifecycleHookRunning   ClusterLifecycleHookPhase = "Running"
+	ClusterLifecycleHookSucceeded ClusterLifecycleHookPhase = "Succeeded"
+	ClusterLifecycleHookFailed    ClusterLifecycleHookPhase = "Failed"
+)
+
+// ClusterAPIHealth describes the latency and error rate of requests
+// to the API server of a member cluster.
+type ClusterAPIHealth struct {
+	// LatencyMilliseconds is the round-trip latency of the most recent
+	// successful health check.
+	LatencyMilliseconds int64 `json:"latencyMilliseconds"`
+	// AverageLatencyMilliseconds is a moving average of the round-trip
+	// latency of successful health checks that favors recent checks.
+	AverageLatencyMilliseconds int64 `json:"averageLatencyMilliseconds"`
+	// ErrorRatePercent is a moving average of the percentage of health
+	// checks that failed to reach the API server that favors recent
+	// checks.
+	ErrorRatePercent int32 `json:"errorRatePercent"`
+}
+
+// ClusterHealthCheckFailure describes the error of the failing health
+// checks of a member cluster.
+type ClusterHealthCheckFailure struct {
+	// Reason classifies the error of the most recent health check.
+	Reason ClusterHealthCheckFailureReason `json:"reason"`
+	// Message is the error of the most recent health check.  If the
+	// cluster has secondary API endpoints, it includes the error of
+	// each endpoint.
+	Message string `json:"message"`
+	// APIEndpoint is the endpoint whose error is classified by the
+	// reason, which is the primary endpoint if no endpoint was
+	// reachable.
+	APIEndpoint string `json:"apiEndpoint"`
+	// ConsecutiveFailures is the number of consecutive health checks
+	// that failed.
+	ConsecutiveFailures int64 `json:"consecutiveFailures"`
+	// Since is the time of the first of the consecutive failed health
+	// checks.
+	Since metav1.Time `json:"since"`
+}
+
+type ClusterHealthCheckFailureReason string
+
+const (
+	// The TLS handshake failed, e.g. because the certificate of the
+	// API server is not signed by the configured CA bundle or does
+	// not match its endpoint.
+	ClusterHealthCheckTLSError ClusterHealthCheckFailureReason = "TLSError"
+	// The API server did not authenticate the credentials of the
+	// cluster, e.g. because the token expired or was revoked.
+	ClusterHealthCheckUnauthorized ClusterHealthCheckFailureReason = "Unauthorized"
+	// The API server authenticated the credentials of the cluster
+	// but did not permit the health check.
+	ClusterHealthCheckForbidden ClusterHealthCheckFailureReason = "Forbidden"
+	// The health check timed out.
+	ClusterHealthCheckTimeout ClusterHealthCheckFailureReason = "Timeout"
+	// The host of the endpoint could not be resolved.
+	ClusterHealthCheckDNSError ClusterHealthCheckFailureReason = "DNSError"
+	// The connection to the endpoint was refused.
+	ClusterHealthCheckConnectionRefused ClusterHealthCheckFailureReason = "ConnectionRefused"
+	// The API server responded with a server error.
+	ClusterHealthCheckServerError ClusterHealthCheckFailureReason = "ServerError"
+	// The API server responded to /healthz without ok.
+	ClusterHealthCheckNotOk ClusterHealthCheckFailureReason = "HealthzNotOk"
+	// The error could not be classified.
+	ClusterHealthCheckUnknownError ClusterHealthCheckFailureReason = "Unknown"
+)
+
+// ClusterPressure describes the pods that a member cluster is unable
+// to schedule and the conditions that prevent it from scheduling them.
+type ClusterPressure struct {
+	// UnschedulablePods is the number of pending pods that the
+	// scheduler of the cluster has found to be unschedulable.
+	UnschedulablePods int32 `json:"unschedulablePods"`
+	// NodesUnderPressure is the number of nodes with a
+	// MemoryPressure, DiskPressure or PIDPressure condition.
+	NodesUnderPressure int32 `json:"nodesUnderPressure"`
+	// LastScaleUpFailureTime is the time of the most recent event of
+	// the cluster autoscaler reporting that a pod did not trigger a
+	// scale-up of the cluster.
+	// +optional
+	LastScaleUpFailureTime *metav1.Time `json:"lastScaleUpFailureTime,omitempty"`
+	// Autoscaler reports whether the cluster autoscaler of the
+	// cluster is able to add nodes to it.  It is not set if the
+	// status of the cluster autoscaler could not be read.
+	// +optional
+	Autoscaler *ClusterAutoscalerStatus `json:"autoscaler,omitempty"`
+}
+
+// ClusterAutoscalerStatus describes the ability of the cluster
+// autoscaler of a member cluster to add nodes to the cluster, as
+// reported by its status ConfigMap.
+type ClusterAutoscalerStatus struct {
+	// ScaleUpDisabled indicates that the cluster autoscaler does not
+	// run in the cluster or does not manage any node group, so that
+	// no nodes can be added to the cluster.
+	// +optional
+	ScaleUpDisabled bool `json:"scaleUpDisabled,omitempty"`
+	// MaxNodesReached indicates that every node group managed by the
+	// cluster autoscaler has reached its maximum size.
+	// +optional
+	MaxNodesReached bool `json:"maxNodesReached,omitempty"`
+	// NodeGroups is the number of node groups managed by the cluster
+	// autoscaler.
+	// +optional
+	NodeGroups int32 `json:"nodeGroups,omitempty"`
+	// NodeGroupsAtMaxSize is the number of node groups whose target
+	// size is their maximum size.
+	// +optional
+	NodeGroupsAtMaxSize int32 `json:"nodeGroupsAtMaxSize,omitempty"`
 }
 
 // +genclient
@@ -71,6 +358,8 @@ type KubeFedClusterStatus struct {
 // +kubebuilder:resource:path=kubefedclusters
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name=ready,type=string,JSONPath=.status.conditions[?(@.type=='Ready')].status
+// +kubebuilder:printcolumn:name=approved,type=string,JSONPath=.status.conditions[?(@.type=='Approved')].status
+// +kubebuilder:printcolumn:name=failure,type=string,JSONPath=.status.healthCheckFailure.reason
 // +kubebuilder:printcolumn:name=age,type=date,JSONPath=.metadata.creationTimestamp
 type KubeFedCluster struct {
 	metav1.TypeMeta   `json:",inline"`
@@ -82,7 +371,8 @@ type KubeFedCluster struct {
 
 // ClusterCondition describes current state of a cluster.
 type ClusterCondition struct {
-	// Type of cluster condition, Ready or Offline.
+	// Type of cluster condition, Ready, Offline, Reachable, Degraded,
+	// RateLimited, ClockSkew, Approved or LifecycleHooksSucceeded.
 	Type common.ClusterConditionType `json:"type"`
 	// Status of the condition, one of True, False, Unknown.
 	Status apiv1.ConditionStatus `json:"status"`
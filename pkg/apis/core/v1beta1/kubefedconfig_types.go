@@ -17,6 +17,8 @@ limitations under the License.
 package v1beta1
 
 import (
+	batchv1b1 "k8s.io/api/batch/v1beta1"
+	apiv1 "k8s.io/api/core/v1"
 	apiextv1b1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -26,19 +28,87 @@ type KubeFedConfigSpec struct {
 	// The scope of the KubeFed control plane should be either
 	// `Namespaced` or `Cluster`. `Namespaced` indicates that the
 	// KubeFed namespace will be the only target of the control plane.
-	Scope              apiextv1b1.ResourceScope `json:"scope"`
-	ControllerDuration DurationConfig           `json:"controllerDuration"`
-	LeaderElect        LeaderElectConfig        `json:"leaderElect"`
-	FeatureGates       []FeatureGatesConfig     `json:"featureGates"`
-	ClusterHealthCheck ClusterHealthCheckConfig `json:"clusterHealthCheck"`
-	SyncController     SyncControllerConfig     `json:"syncController"`
+	// +optional
+	Scope apiextv1b1.ResourceScope `json:"scope,omitempty"`
+	// The cluster-scoped resources that a `Namespaced` control plane
+	// is allowed to propagate. A `Namespaced` control plane does not
+	// propagate any other cluster-scoped resource, and the allowlist
+	// may not be set for a `Cluster` control plane.
+	// +optional
+	AllowedClusterScopedResources []AllowedClusterScopedResource `json:"allowedClusterScopedResources,omitempty"`
+	// +optional
+	ControllerDuration DurationConfig `json:"controllerDuration,omitempty"`
+	// +optional
+	LeaderElect LeaderElectConfig `json:"leaderElect,omitempty"`
+	// +optional
+	FeatureGates []FeatureGatesConfig `json:"featureGates,omitempty"`
+	// Switches that turn individual controllers of the controller
+	// manager on or off independently of the feature gates.
+	// Controllers that are not listed are enabled.
+	// +optional
+	Controllers []ControllerSwitchConfig `json:"controllers,omitempty"`
+	// +optional
+	ClusterHealthCheck ClusterHealthCheckConfig `json:"clusterHealthCheck,omitempty"`
+	// Hooks run by the cluster controller when member clusters are
+	// joined, approved, marked unhealthy or unjoined.
+	// +optional
+	ClusterLifecycleHooks []ClusterLifecycleHook `json:"clusterLifecycleHooks,omitempty"`
+	// +optional
+	SyncController SyncControllerConfig `json:"syncController,omitempty"`
+	// Configuration for notifying external systems of propagation
+	// failures and cluster health transitions.
+	// +optional
+	Notifications *NotificationConfig `json:"notifications,omitempty"`
+	// Configuration of the profiles that workloads can select to
+	// control how their replicas are scheduled to clusters.
+	// +optional
+	Scheduling *SchedulingConfig `json:"scheduling,omitempty"`
+	// Configuration of the diagnostics served by the controller
+	// manager.
+	// +optional
+	Diagnostics *DiagnosticsConfig `json:"diagnostics,omitempty"`
+}
+
+// AllowedClusterScopedResource identifies the resources of a
+// cluster-scoped kind that a namespace-scoped control plane may
+// propagate.
+type AllowedClusterScopedResource struct {
+	// The API group of the kind. Empty for the core API group.
+	// +optional
+	Group string `json:"group,omitempty"`
+	// The kind of the resources (e.g. ClusterRole).
+	Kind string `json:"kind"`
+	// The names of the resources of the kind that may be propagated.
+	Names []string `json:"names"`
+}
+
+type DiagnosticsConfig struct {
+	// Whether to serve the profiles of the Go runtime at /debug/pprof/
+	// and a dump of the state of the controllers at /debug/dump on the
+	// port of the metrics endpoint. Defaults to "Disabled".
+	// +optional
+	Profiling ConfigurationMode `json:"profiling,omitempty"`
 }
 
 type DurationConfig struct {
 	// Time to wait before reconciling on a healthy cluster.
-	AvailableDelay metav1.Duration `json:"availableDelay"`
+	// +optional
+	AvailableDelay metav1.Duration `json:"availableDelay,omitempty"`
 	// Time to wait before giving up on an unhealthy cluster.
-	UnavailableDelay metav1.Duration `json:"unavailableDelay"`
+	// +optional
+	UnavailableDelay metav1.Duration `json:"unavailableDelay,omitempty"`
+	// Minimum time between updates of the status of a federated
+	// resource by the sync and status controllers. Changes observed
+	// within the interval are written in a single update once it
+	// elapses. Status updates are not coalesced if not set.
+	// +optional
+	StatusUpdateInterval metav1.Duration `json:"statusUpdateInterval,omitempty"`
+	// Time for which the gets and lists of member cluster resources
+	// issued by the controllers are reused by other reads of the same
+	// resources, unless a watch observes a change first. Defaults to
+	// 5s. Reads are not cached if set to 0s.
+	// +optional
+	MemberReadCacheTTL *metav1.Duration `json:"memberReadCacheTTL,omitempty"`
 }
 type LeaderElectConfig struct {
 	// The duration that non-leader candidates will wait after observing a leadership
@@ -46,17 +116,21 @@ type LeaderElectConfig struct {
 	// slot. This is effectively the maximum duration that a leader can be stopped
 	// before it is replaced by another candidate. This is only applicable if leader
 	// election is enabled.
-	LeaseDuration metav1.Duration `json:"leaseDuration"`
+	// +optional
+	LeaseDuration metav1.Duration `json:"leaseDuration,omitempty"`
 	// The interval between attempts by the acting master to renew a leadership slot
 	// before it stops leading. This must be less than or equal to the lease duration.
 	// This is only applicable if leader election is enabled.
-	RenewDeadline metav1.Duration `json:"renewDeadline"`
+	// +optional
+	RenewDeadline metav1.Duration `json:"renewDeadline,omitempty"`
 	// The duration the clients should wait between attempting acquisition and renewal
 	// of a leadership. This is only applicable if leader election is enabled.
-	RetryPeriod metav1.Duration `json:"retryPeriod"`
+	// +optional
+	RetryPeriod metav1.Duration `json:"retryPeriod,omitempty"`
 	// The type of resource object that is used for locking during
 	// leader election. Supported options are `configmaps` (default) and `endpoints`.
-	ResourceLock ResourceLockType `json:"resourceLock"`
+	// +optional
+	ResourceLock ResourceLockType `json:"resourceLock,omitempty"`
 }
 
 type ResourceLockType string
@@ -71,6 +145,56 @@ type FeatureGatesConfig struct {
 	Configuration ConfigurationMode `json:"configuration"`
 }
 
+type ControllerSwitchConfig struct {
+	// The name of the controller. Supported names are
+	// `StatusController`, `SchedulingManager`, `ServiceDNS`,
+	// `IngressDNS`, `FederatedEvents`, `AutoFederation`,
+	// `IstioMultiCluster` and `SubmarinerLighthouse`.
+	Name ControllerName `json:"name"`
+	// Whether the controller is started. A controller that is
+	// `Enabled` is still not started if its feature gate is disabled.
+	Configuration ConfigurationMode `json:"configuration"`
+}
+
+type ControllerName string
+
+const (
+	// The controllers collecting the status of federated resources
+	// from member clusters.
+	StatusControllerName ControllerName = "StatusController"
+	// The scheduling manager and the controllers of the scheduling
+	// preferences it manages, e.g. ReplicaSchedulingPreference.
+	SchedulingManagerName ControllerName = "SchedulingManager"
+	// The service DNS controller and its DNS endpoint controller.
+	ServiceDNSName ControllerName = "ServiceDNS"
+	// The ingress DNS controller and its DNS endpoint controller.
+	IngressDNSName ControllerName = "IngressDNS"
+	// The controller mirroring warning events of member clusters.
+	FederatedEventsName ControllerName = "FederatedEvents"
+	// The controllers federating resources created in member
+	// clusters.
+	AutoFederationName ControllerName = "AutoFederation"
+	// The controller maintaining Istio ServiceEntries for services
+	// with cross-cluster discovery enabled.
+	IstioMultiClusterName ControllerName = "IstioMultiCluster"
+	// The controllers exporting federated services to Submariner
+	// Lighthouse.
+	SubmarinerLighthouseName ControllerName = "SubmarinerLighthouse"
+)
+
+// ControllerNames lists the controllers that can be switched on or
+// off by a KubeFedConfig.
+var ControllerNames = []ControllerName{
+	StatusControllerName,
+	SchedulingManagerName,
+	ServiceDNSName,
+	IngressDNSName,
+	FederatedEventsName,
+	AutoFederationName,
+	IstioMultiClusterName,
+	SubmarinerLighthouseName,
+}
+
 type ConfigurationMode string
 
 const (
@@ -80,19 +204,169 @@ const (
 
 type ClusterHealthCheckConfig struct {
 	// How often to monitor the cluster health (in seconds).
-	PeriodSeconds int64 `json:"periodSeconds"`
+	// +optional
+	PeriodSeconds int64 `json:"periodSeconds,omitempty"`
 	// Minimum consecutive failures for the cluster health to be considered failed after having succeeded.
-	FailureThreshold int64 `json:"failureThreshold"`
+	// +optional
+	FailureThreshold int64 `json:"failureThreshold,omitempty"`
 	// Minimum consecutive successes for the cluster health to be considered successful after having failed.
-	SuccessThreshold int64 `json:"successThreshold"`
+	// +optional
+	SuccessThreshold int64 `json:"successThreshold,omitempty"`
 	// Number of seconds after which the cluster health check times out.
-	TimeoutSeconds int64 `json:"timeoutSeconds"`
+	// +optional
+	TimeoutSeconds int64 `json:"timeoutSeconds,omitempty"`
+}
+
+type ClusterLifecycleHook struct {
+	// Name identifies the hook in the status of clusters. It must be
+	// a DNS label of at most 32 characters.
+	Name string `json:"name"`
+	// The events of the lifecycle of a cluster that run the hook.
+	// Supported events are `Joined`, `Approved`, `Unhealthy` and
+	// `Unjoined`.
+	Events []ClusterLifecycleEvent `json:"events"`
+	// A webhook that is POSTed the cluster and the event. Exactly one
+	// of webhook and job must be set.
+	// +optional
+	Webhook *ClusterLifecycleWebhook `json:"webhook,omitempty"`
+	// A Job that is created in the KubeFed namespace of the host
+	// cluster. Exactly one of webhook and job must be set.
+	// +optional
+	Job *ClusterLifecycleJob `json:"job,omitempty"`
+}
+
+type ClusterLifecycleEvent string
+
+const (
+	// The cluster was joined to the control plane.
+	ClusterJoinedEvent ClusterLifecycleEvent = "Joined"
+	// The cluster was approved for use by KubeFed.
+	ClusterApprovedEvent ClusterLifecycleEvent = "Approved"
+	// The cluster was marked not ready by the cluster health check.
+	ClusterUnhealthyEvent ClusterLifecycleEvent = "Unhealthy"
+	// The cluster is being unjoined from the control plane. Its
+	// KubeFedCluster is not removed until the hook has completed.
+	ClusterUnjoinedEvent ClusterLifecycleEvent = "Unjoined"
+)
+
+type ClusterLifecycleWebhook struct {
+	// The URL that the cluster and the event are POSTed to as json.
+	// The hook fails unless the response has a 2xx status code.
+	URL string `json:"url"`
+	// Number of seconds after which the request times out. Defaults
+	// to 10.
+	// +optional
+	TimeoutSeconds int64 `json:"timeoutSeconds,omitempty"`
+}
+
+type ClusterLifecycleJob struct {
+	// The template of the Job. The `KUBEFED_CLUSTER_NAME`,
+	// `KUBEFED_CLUSTER_EVENT` and `KUBEFED_CLUSTER_API_ENDPOINT`
+	// environment variables are set in each of its containers.
+	Template batchv1b1.JobTemplateSpec `json:"template"`
 }
 
 type SyncControllerConfig struct {
 	// Whether to adopt pre-existing resources in member clusters. Defaults to
-	// "Enabled".
-	AdoptResources ResourceAdoption `json:"adoptResources"`
+	// "Enabled". "ReportOnly" reports the pre-existing resources that would
+	// be adopted in the status of federated resources without modifying
+	// them.
+	// +optional
+	AdoptResources ResourceAdoption `json:"adoptResources,omitempty"`
+	// The labels and annotations injected into resources propagated
+	// to member clusters to identify their source.
+	// +optional
+	PropagationMetadata *PropagationMetadataConfig `json:"propagationMetadata,omitempty"`
+	// The labels and annotations of namespaces in the host cluster
+	// that are continuously synced to the namespaces propagated to
+	// member clusters by federated namespaces.
+	// +optional
+	NamespaceMetadata *NamespaceMetadataConfig `json:"namespaceMetadata,omitempty"`
+	// Limits the number of resources the sync controller may delete
+	// from a member cluster within a window of time. Deletions are
+	// not limited if not set.
+	// +optional
+	DeletionLimit *DeletionLimitConfig `json:"deletionLimit,omitempty"`
+	// Backs off the dispatch of resources to a member cluster whose
+	// API persistently fails requests. Dispatch is not backed off if
+	// not set.
+	// +optional
+	CircuitBreaker *CircuitBreakerConfig `json:"circuitBreaker,omitempty"`
+	// Bounds the number of operations the sync controller dispatches
+	// concurrently to member clusters.
+	// +optional
+	DispatchConcurrency *DispatchConcurrencyConfig `json:"dispatchConcurrency,omitempty"`
+}
+
+type PropagationMetadataConfig struct {
+	// Whether to annotate propagated resources with the kind,
+	// namespace and name of the federated resource that manages them.
+	// Defaults to "Enabled".
+	// +optional
+	OriginAnnotations ConfigurationMode `json:"originAnnotations,omitempty"`
+	// Whether to annotate propagated resources with the hash of the
+	// template of the federated resource that manages them. Defaults
+	// to "Enabled".
+	// +optional
+	TemplateHashAnnotation ConfigurationMode `json:"templateHashAnnotation,omitempty"`
+	// The name identifying the host cluster. If provided, propagated
+	// resources will be labeled with the name.
+	// +optional
+	HostClusterName string `json:"hostClusterName,omitempty"`
+}
+
+type NamespaceMetadataConfig struct {
+	// The keys of the labels to sync. A key ending in `*` matches all
+	// keys with the preceding prefix (e.g.
+	// `pod-security.kubernetes.io/*`).
+	// +optional
+	Labels []string `json:"labels,omitempty"`
+	// The keys of the annotations to sync, matched in the same way
+	// as the keys of labels.
+	// +optional
+	Annotations []string `json:"annotations,omitempty"`
+}
+
+type DeletionLimitConfig struct {
+	// The maximum number of resources that may be deleted from a
+	// member cluster within the window. Once the limit is reached,
+	// deletions from the cluster are paused by adding the
+	// `kubefed.k8s.io/deletions-paused` annotation to the
+	// KubeFedCluster, and resume once the annotation is removed.
+	MaxDeletions int64 `json:"maxDeletions"`
+	// The window over which deletions are counted. Defaults to 1m.
+	// +optional
+	Window *metav1.Duration `json:"window,omitempty"`
+}
+
+type CircuitBreakerConfig struct {
+	// The number of consecutive requests to a member cluster that
+	// must fail with a timeout, a server error or a connection error
+	// for dispatch to the cluster to be backed off. While dispatch is
+	// backed off the KubeFedCluster has a Degraded condition, and a
+	// single request is attempted each time the backoff elapses to
+	// probe whether the cluster has recovered.
+	FailureThreshold int64 `json:"failureThreshold"`
+	// The time for which dispatch is backed off once the threshold is
+	// reached. The backoff is doubled each time a probe fails.
+	// Defaults to 10s.
+	// +optional
+	InitialBackoff *metav1.Duration `json:"initialBackoff,omitempty"`
+	// The maximum time for which dispatch is backed off. Defaults to
+	// 5m.
+	// +optional
+	MaxBackoff *metav1.Duration `json:"maxBackoff,omitempty"`
+}
+
+type DispatchConcurrencyConfig struct {
+	// The maximum number of operations in progress against a single
+	// member cluster, across all federated types. Defaults to 30.
+	// +optional
+	MaxPerCluster int64 `json:"maxPerCluster,omitempty"`
+	// The maximum number of operations in progress against all
+	// member clusters. Defaults to 300.
+	// +optional
+	MaxTotal int64 `json:"maxTotal,omitempty"`
 }
 
 type ResourceAdoption string
@@ -100,6 +374,132 @@ type ResourceAdoption string
 const (
 	AdoptResourcesEnabled  ResourceAdoption = "Enabled"
 	AdoptResourcesDisabled ResourceAdoption = "Disabled"
+	// Pre-existing resources are reported as adoptable but are not
+	// modified until adoption is enabled.
+	AdoptResourcesReportOnly ResourceAdoption = "ReportOnly"
+)
+
+type NotificationConfig struct {
+	// The sinks that notifications will be delivered to.
+	Sinks []NotificationSink `json:"sinks"`
+	// The minimum number of clusters a federated resource must fail
+	// to propagate to before a `PropagationFailed` notification is
+	// sent. Defaults to 1.
+	// +optional
+	PropagationFailureThreshold int64 `json:"propagationFailureThreshold,omitempty"`
+}
+
+type NotificationSink struct {
+	// Name identifies the sink in logs.
+	Name string `json:"name"`
+	// The URL that notifications will be POSTed to.
+	URL string `json:"url"`
+	// The format of the notification payload. Supported options are
+	// `Webhook` (default) and `Slack`.
+	// +optional
+	Format NotificationFormat `json:"format,omitempty"`
+	// The notification types to deliver to the sink. All types are
+	// delivered if not specified.
+	// +optional
+	Types []NotificationType `json:"types,omitempty"`
+}
+
+type NotificationFormat string
+
+const (
+	// WebhookNotificationFormat posts the notification as json.
+	WebhookNotificationFormat NotificationFormat = "Webhook"
+	// SlackNotificationFormat posts a Slack-compatible message payload.
+	SlackNotificationFormat NotificationFormat = "Slack"
+)
+
+type NotificationType string
+
+const (
+	// A member cluster transitioned from ready to not ready.
+	ClusterNotReadyNotification NotificationType = "ClusterNotReady"
+	// A member cluster transitioned from not ready to ready.
+	ClusterReadyNotification NotificationType = "ClusterReady"
+	// A federated resource failed to propagate to at least the
+	// configured threshold of clusters.
+	PropagationFailedNotification NotificationType = "PropagationFailed"
+)
+
+type SchedulingConfig struct {
+	// The named scheduling profiles. A profile is selected by a
+	// ReplicaSchedulingPreference, or by the federated resource it
+	// targets, with the `scheduling.kubefed.k8s.io/profile` annotation.
+	// +optional
+	Profiles []SchedulingProfile `json:"profiles,omitempty"`
+	// The name of the profile used when none is selected. If not
+	// set, clusters are filtered with the default filters and not
+	// scored.
+	// +optional
+	DefaultProfile string `json:"defaultProfile,omitempty"`
+}
+
+type SchedulingProfile struct {
+	// Name of the profile.
+	Name string `json:"name"`
+	// The filters that ready clusters must pass to be scheduled to.
+	// Defaults to `Degraded` and `Taints`. `None` disables filtering.
+	// +optional
+	Filters []SchedulingFilter `json:"filters,omitempty"`
+	// The scorers whose combined score scales the weights of the
+	// clusters that pass the filters. Weights are not scaled if no
+	// scorers are configured.
+	// +optional
+	Scorers []SchedulingScorer `json:"scorers,omitempty"`
+}
+
+type SchedulingFilter string
+
+const (
+	// Excludes clusters whose Degraded condition is true, e.g.
+	// because a health probe of the cluster failed.
+	DegradedSchedulingFilter SchedulingFilter = "Degraded"
+	// Excludes clusters with NoSchedule or NoExecute taints that
+	// are not tolerated by the ReplicaSchedulingPreference.
+	TaintsSchedulingFilter SchedulingFilter = "Taints"
+	// Limits the replicas of clusters under pressure, as reported
+	// by the ClusterPressure feature, to the replicas that are
+	// already running in them.
+	PressureSchedulingFilter SchedulingFilter = "Pressure"
+	// Limits the replicas of clusters with unschedulable pods whose
+	// cluster autoscaler cannot add nodes, as reported by the
+	// ClusterPressure feature, to the replicas that are already
+	// running in them.
+	HeadroomSchedulingFilter SchedulingFilter = "Headroom"
+	// Disables filtering, so that replicas are scheduled to every
+	// ready cluster. It cannot be combined with other filters.
+	NoneSchedulingFilter SchedulingFilter = "None"
+)
+
+type SchedulingScorer struct {
+	// The type of the scorer. Supported options are `APILatency`,
+	// `Headroom` and `Label`.
+	Type SchedulingScorerType `json:"type"`
+	// The weight of the score relative to the other scorers of the
+	// profile. Defaults to 1.
+	// +optional
+	Weight int64 `json:"weight,omitempty"`
+	// The key of the KubeFedCluster label holding the score of the
+	// cluster, from 0 to 100. Required for the `Label` scorer.
+	// +optional
+	Label string `json:"label,omitempty"`
+}
+
+type SchedulingScorerType string
+
+const (
+	// Scores clusters by the average latency of their API server
+	// as observed by the cluster health check, favoring the lowest.
+	APILatencySchedulingScorer SchedulingScorerType = "APILatency"
+	// Scores clusters by the share of the node groups of their
+	// cluster autoscaler that can still grow.
+	HeadroomSchedulingScorer SchedulingScorerType = "Headroom"
+	// Scores clusters by the value of a label.
+	LabelSchedulingScorer SchedulingScorerType = "Label"
 )
 
 // +genclient
@@ -108,13 +508,71 @@ const (
 // KubeFedConfig
 // +k8s:openapi-gen=true
 // +kubebuilder:resource:path=kubefedconfigs
+// +kubebuilder:subresource:status
 type KubeFedConfig struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
 	Spec KubeFedConfigSpec `json:"spec"`
+	// +optional
+	Status KubeFedConfigStatus `json:"status,omitempty"`
 }
 
+// KubeFedConfigStatus defines the observed state of KubeFedConfig
+type KubeFedConfigStatus struct {
+	// The namespace-qualified name of the KubeFedConfig providing the
+	// values of fields not set in the spec, if any.
+	// +optional
+	DefaultConfig string `json:"defaultConfig,omitempty"`
+	// The configuration in effect for the control plane, including
+	// values sourced from the default KubeFedConfig and the defaults
+	// of the controller manager.
+	// +optional
+	EffectiveSpec *KubeFedConfigSpec `json:"effectiveSpec,omitempty"`
+	// The generation of the spec that was last applied by the
+	// controller manager.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// The conditions of the configuration, Valid, Applied and
+	// Degraded, maintained by the controller manager.
+	// +optional
+	Conditions []KubeFedConfigCondition `json:"conditions,omitempty"`
+}
+
+// KubeFedConfigCondition describes the state of a KubeFedConfig.
+type KubeFedConfigCondition struct {
+	// Type of the condition, Valid, Applied or Degraded.
+	Type KubeFedConfigConditionType `json:"type"`
+	// Status of the condition, one of True, False, Unknown.
+	Status apiv1.ConditionStatus `json:"status"`
+	// Last time the condition transit from one status to another.
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+	// (brief) reason for the condition's last transition.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Human readable message indicating details about last transition.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+type KubeFedConfigConditionType string
+
+const (
+	// The effective configuration passed validation.  An invalid
+	// configuration is still applied, but the controllers may not
+	// behave as configured.
+	KubeFedConfigValid KubeFedConfigConditionType = "Valid"
+	// The effective configuration is in use by the controller
+	// manager.  The configuration is only applied when the
+	// controller manager starts, so a change to the spec is not
+	// applied until it is restarted.
+	KubeFedConfigApplied KubeFedConfigConditionType = "Applied"
+	// Part of the configuration could not be honored, e.g. the
+	// default KubeFedConfig could not be found.
+	KubeFedConfigDegraded KubeFedConfigConditionType = "Degraded"
+)
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // KubeFedConfigList contains a list of KubeFedConfig
@@ -0,0 +1,70 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PlacementPolicySpec defines the member clusters that federated
+// resources in a set of namespaces may be placed on.
+type PlacementPolicySpec struct {
+	// Namespaces of the host cluster whose federated resources are
+	// restricted by the policy.
+	Namespaces []string `json:"namespaces"`
+
+	// Clusters are the names of the member clusters that the
+	// federated resources may be placed on.
+	// +optional
+	Clusters []string `json:"clusters,omitempty"`
+
+	// ClusterSelector selects member clusters by label that the
+	// federated resources may be placed on, in addition to the named
+	// clusters.  An empty selector selects all clusters.
+	// +optional
+	ClusterSelector *metav1.LabelSelector `json:"clusterSelector,omitempty"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PlacementPolicy restricts the member clusters that federated
+// resources in the given namespaces may be placed on.  Federated
+// resources in a namespace restricted by one or more policies may only
+// be placed on the clusters allowed by at least one of them.
+//
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:path=placementpolicies
+type PlacementPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec PlacementPolicySpec `json:"spec"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PlacementPolicyList contains a list of PlacementPolicy
+type PlacementPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PlacementPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PlacementPolicy{}, &PlacementPolicyList{})
+}
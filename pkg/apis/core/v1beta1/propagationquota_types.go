@@ -0,0 +1,88 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PropagationQuotaSpec defines the limits on the federated resources
+// of a set of namespaces and on the resources they propagate.
+type PropagationQuotaSpec struct {
+	// Namespaces of the host cluster whose federated resources are
+	// counted against the quota.
+	Namespaces []string `json:"namespaces"`
+
+	// MaxFederatedResources limits the number of federated resources
+	// of all types in the namespaces that are propagated.
+	// +optional
+	MaxFederatedResources *int64 `json:"maxFederatedResources,omitempty"`
+
+	// MaxPropagatedObjects limits the total number of resources that
+	// the federated resources in the namespaces propagate to member
+	// clusters, i.e. the sum of the number of clusters in their
+	// placements.
+	// +optional
+	MaxPropagatedObjects *int64 `json:"maxPropagatedObjects,omitempty"`
+}
+
+// PropagationQuotaStatus reports the usage of a PropagationQuota.
+type PropagationQuotaStatus struct {
+	// FederatedResources is the number of federated resources that
+	// are counted against the quota.
+	FederatedResources int64 `json:"federatedResources"`
+	// PropagatedObjects is the number of resources propagated to
+	// member clusters by the federated resources that are counted
+	// against the quota.
+	PropagatedObjects int64 `json:"propagatedObjects"`
+	// RejectedResources is the number of federated resources that are
+	// not propagated since they would exceed the quota.
+	RejectedResources int64 `json:"rejectedResources"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PropagationQuota limits the number of federated resources in the
+// given namespaces that are propagated, and the number of resources
+// they propagate to member clusters.  Federated resources that would
+// exceed the quota are not propagated.
+//
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:path=propagationquotas
+// +kubebuilder:subresource:status
+type PropagationQuota struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec PropagationQuotaSpec `json:"spec"`
+	// +optional
+	Status PropagationQuotaStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PropagationQuotaList contains a list of PropagationQuota
+type PropagationQuotaList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PropagationQuota `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PropagationQuota{}, &PropagationQuotaList{})
+}
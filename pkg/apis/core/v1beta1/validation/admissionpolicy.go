@@ -0,0 +1,577 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	apiextv1b1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	valutil "k8s.io/apimachinery/pkg/util/validation"
+
+	"sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+// Patterns equivalent to the validations of names in
+// k8s.io/apimachinery/pkg/util/validation, which do not export them.
+const (
+	dns1035LabelPattern     = "^[a-z]([-a-z0-9]*[a-z0-9])?$"
+	dns1123LabelPattern     = "^[a-z0-9]([-a-z0-9]*[a-z0-9])?$"
+	dns1123SubdomainPattern = "^[a-z0-9]([-a-z0-9]*[a-z0-9])?([.][a-z0-9]([-a-z0-9]*[a-z0-9])?)*$"
+)
+
+// AdmissionRule is a validation of this package expressed in the
+// common expression language (CEL) for evaluation by the API server.
+type AdmissionRule struct {
+	// FieldPath is the path of the validated field. It matches the
+	// path of the errors returned by the go validation of the field,
+	// excluding list indices.
+	FieldPath string
+	// Expression evaluates to true if the object being admitted,
+	// which is bound to 'object', is valid.
+	Expression string
+	Message    string
+	// Reason is the reason of the rejection of an invalid object,
+	// which is Invalid if not set.
+	Reason string
+}
+
+// AdmissionPolicy is a set of rules validating a resource.
+type AdmissionPolicy struct {
+	Name string
+	// Resource is the plural name of the validated resource,
+	// optionally qualified by a subresource.
+	Resource string
+	Rules    []AdmissionRule
+}
+
+// AdmissionPolicies returns policies implementing the validation of
+// federated type configs, KubeFedClusters and KubeFedConfigs in CEL,
+// for use where admission webhooks are not permitted. The rules share
+// the accepted values and limits of the go validation, which remains
+// the source of truth.
+func AdmissionPolicies() []AdmissionPolicy {
+	return []AdmissionPolicy{
+		{
+			Name:     "federatedtypeconfigs",
+			Resource: "federatedtypeconfigs",
+			Rules:    federatedTypeConfigRules(),
+		},
+		{
+			Name:     "federatedtypeconfigs-status",
+			Resource: "federatedtypeconfigs/status",
+			Rules:    federatedTypeConfigStatusRules(),
+		},
+		{
+			Name:     "kubefedclusters",
+			Resource: "kubefedclusters",
+			Rules:    kubeFedClusterRules(),
+		},
+		{
+			Name:     "kubefedconfigs",
+			Resource: "kubefedconfigs",
+			Rules:    kubeFedConfigRules(),
+		},
+	}
+}
+
+func federatedTypeConfigRules() []AdmissionRule {
+	targetType := []string{"spec", "targetType"}
+	rules := []AdmissionRule{
+		{
+			FieldPath: "metadata.name",
+			Expression: fmt.Sprintf("%s && object.metadata.name == object.spec.targetType.pluralName + (%s && object.spec.targetType.group != '' ? '.' + object.spec.targetType.group : '')",
+				celHas(child(targetType, "pluralName")), celHas(child(targetType, "group"))),
+			Message: federatedTypeConfigNameErrorMsg,
+		},
+	}
+	rules = append(rules, apiResourceRules(targetType, false, false)...)
+	rules = append(rules,
+		enumRule([]string{"spec", "propagation"}, propagationModes, true),
+		AdmissionRule{
+			FieldPath:  "spec.targetVersions",
+			Expression: eachItem([]string{"spec", "targetVersions"}, dnsMatch("item", dns1035LabelPattern, valutil.DNS1035LabelMaxLength)),
+			Message:    "must contain valid DNS-1035 labels",
+		},
+		AdmissionRule{
+			FieldPath: "spec.targetVersions",
+			Expression: eachItem([]string{"spec", "targetVersions"}, fmt.Sprintf("(!(%s) || item != object.spec.targetType.version) && object.spec.targetVersions.exists_one(other, other == item)",
+				celHas(child(targetType, "version")))),
+			Message: "must not contain duplicate versions or the version of spec.targetType",
+		},
+	)
+	rules = append(rules, apiResourceRules([]string{"spec", "federatedType"}, true, false)...)
+	rules = append(rules, apiResourceRules([]string{"spec", "statusType"}, true, true)...)
+	rules = append(rules, AdmissionRule{
+		FieldPath:  "spec.statusType.scope",
+		Expression: optional([]string{"spec", "statusType", "scope"}, "!has(object.spec.federatedType.scope) || %s == object.spec.federatedType.scope"),
+		Message:    "scope " + statusTypeScopeErrorMsg,
+	})
+	rules = append(rules, enumRule([]string{"spec", "statusCollection"}, statusCollectionModes, false))
+	rules = append(rules, durationRule([]string{"spec", "statusUpdateInterval"}, ">=", "must not be negative"))
+
+	overrides := []string{"spec", "defaultOverrides"}
+	values := []string{"spec", "values"}
+	valuesPath := child(values, "path")
+	rules = append(rules,
+		AdmissionRule{
+			FieldPath:  "spec.defaultOverrides.path",
+			Expression: eachItem(overrides, fmt.Sprintf("has(item.path) && item.path != '' && !(item.path in %s)", celList(invalidOverridePaths))),
+			Message:    "path is required and must not be one of " + strings.Join(invalidOverridePaths, ", "),
+		},
+		AdmissionRule{
+			FieldPath:  "spec.defaultOverrides.operation",
+			Expression: eachItem(overrides, fmt.Sprintf("!has(item.operation) || item.operation in %s", celList(append([]string{""}, defaultOverrideOperations...)))),
+			Message:    "operation must be one of " + strings.Join(defaultOverrideOperations, ", "),
+		},
+		AdmissionRule{
+			FieldPath: "spec.defaultOverrides.value",
+			Expression: eachItem(overrides, fmt.Sprintf("(!has(item.operation) || item.operation == '' || item.operation == '%s') ? has(item.value) : (item.operation != '%s' || !has(item.value))",
+				defaultOverrideOperations[0], defaultOverrideOperations[1])),
+			Message: "value is required for the Set operation and must not be set for the Remove operation",
+		},
+		// The schema of the values is only validated by the webhook
+		// since it cannot be parsed in CEL.
+		AdmissionRule{
+			FieldPath: "spec.values.path",
+			Expression: fmt.Sprintf("!(%s) || (%s && %s != '' && !(%s in %s))", celHas(values), celHas(valuesPath), celPath(valuesPath),
+				celPath(valuesPath), celList(invalidOverridePaths)),
+			Message: "path is required and must not be one of " + strings.Join(invalidOverridePaths, ", "),
+		},
+		enumRule(child(values, "format"), valuesFormats, false),
+		AdmissionRule{
+			FieldPath: "spec.locallyManagedFields",
+			Expression: eachItem([]string{"spec", "locallyManagedFields"}, fmt.Sprintf("(item.startsWith('.') ? item.substring(1) : item) != '' && !((item.startsWith('.') ? item.substring(1) : item) in %s)",
+				celList(invalidOverridePaths))),
+			Message: "paths are required and must not be one of " + strings.Join(invalidOverridePaths, ", "),
+		},
+		AdmissionRule{
+			FieldPath:  "spec.memberEventFilter.ignoredAnnotations",
+			Expression: eachItem([]string{"spec", "memberEventFilter", "ignoredAnnotations"}, "item != ''"),
+			Message:    "annotation keys are required",
+		},
+	)
+	return rules
+}
+
+func federatedTypeConfigStatusRules() []AdmissionRule {
+	return []AdmissionRule{
+		{
+			FieldPath:  "status.observedGeneration",
+			Expression: optional([]string{"status", "observedGeneration"}, "%s >= 0"),
+			Message:    "observedGeneration must not be negative",
+		},
+		enumRule([]string{"status", "propagationController"}, controllerStatuses, true),
+		enumRule([]string{"status", "statusController"}, controllerStatuses, false),
+	}
+}
+
+func kubeFedClusterRules() []AdmissionRule {
+	secretNamespace := []string{"spec", "secretRef", "namespace"}
+	endpoints := []string{"spec", "secondaryAPIEndpoints"}
+	mappings := []string{"spec", "namespaceMappings"}
+	profileRef := []string{"spec", "profileRef"}
+	probes := []string{"spec", "healthProbes"}
+	return []AdmissionRule{
+		{
+			FieldPath: "spec.secondaryAPIEndpoints",
+			Expression: eachItem(endpoints, fmt.Sprintf("item != '' && (!(%s) || item != object.spec.apiEndpoint) && object.spec.secondaryAPIEndpoints.exists_one(other, other == item)",
+				celHas([]string{"spec", "apiEndpoint"}))),
+			Message: "secondary endpoints must not be empty or duplicate each other or the api endpoint",
+		},
+		{
+			FieldPath:  "spec.namespaceMappings.namespace",
+			Expression: eachItem(mappings, "has(item.namespace) && "+dnsMatch("item.namespace", dns1123LabelPattern, valutil.DNS1123LabelMaxLength)+" && object.spec.namespaceMappings.exists_one(other, other.namespace == item.namespace)"),
+			Message:    "namespaces must be valid namespace names that are mapped at most once",
+		},
+		{
+			FieldPath:  "spec.namespaceMappings.clusterNamespace",
+			Expression: eachItem(mappings, "has(item.clusterNamespace) && "+dnsMatch("item.clusterNamespace", dns1123LabelPattern, valutil.DNS1123LabelMaxLength)+" && object.spec.namespaceMappings.exists_one(other, other.clusterNamespace == item.clusterNamespace)"),
+			Message:    "cluster namespaces must be valid namespace names that are mapped to at most once",
+		},
+		{
+			FieldPath:  "spec.allowedNamespaces",
+			Expression: eachItem([]string{"spec", "allowedNamespaces"}, dnsMatch("item", dns1123LabelPattern, valutil.DNS1123LabelMaxLength)),
+			Message:    "must contain valid namespace names",
+		},
+		{
+			FieldPath:  "spec.deniedNamespaces",
+			Expression: eachItem([]string{"spec", "deniedNamespaces"}, dnsMatch("item", dns1123LabelPattern, valutil.DNS1123LabelMaxLength)),
+			Message:    "must contain valid namespace names",
+		},
+		{
+			FieldPath:  "spec.secretRef.namespace",
+			Expression: optional(secretNamespace, "%[1]s == '' || "+dnsMatch("%[1]s", dns1123LabelPattern, valutil.DNS1123LabelMaxLength)),
+			Message:    "secret namespace must be a valid namespace name",
+		},
+		{
+			FieldPath:  "spec.profileRef.name",
+			Expression: fmt.Sprintf("!(%s) || %s", celHas(profileRef), required(child(profileRef, "name"), dnsMatch("%[1]s", dns1123SubdomainPattern, valutil.DNS1123SubdomainMaxLength))),
+			Message:    "profile must be the name of a ConfigMap",
+		},
+		// Duplicate probe names are only rejected by the go validation.
+		{
+			FieldPath:  "spec.healthProbes.name",
+			Expression: eachItem(probes, "has(item.name) && "+dnsMatch("item.name", dns1123LabelPattern, valutil.DNS1123LabelMaxLength)),
+			Message:    "probe names are required and must be DNS labels",
+		},
+		{
+			FieldPath:  "spec.healthProbes",
+			Expression: eachItem(probes, "has(item.service) != has(item.deployment)"),
+			Message:    "exactly one of service and deployment must be set for each probe",
+		},
+		{
+			FieldPath:  "spec.healthProbes.service.namespace",
+			Expression: eachItem(probes, "!has(item.service) || (has(item.service.namespace) && "+dnsMatch("item.service.namespace", dns1123LabelPattern, valutil.DNS1123LabelMaxLength)+")"),
+			Message:    "the namespace of a probed service is required and must be a valid namespace name",
+		},
+		{
+			FieldPath:  "spec.healthProbes.service.name",
+			Expression: eachItem(probes, "!has(item.service) || (has(item.service.name) && "+dnsMatch("item.service.name", dns1035LabelPattern, valutil.DNS1035LabelMaxLength)+")"),
+			Message:    "the name of a probed service is required and must be a valid service name",
+		},
+		{
+			FieldPath:  "spec.healthProbes.service.scheme",
+			Expression: eachItem(probes, fmt.Sprintf("!has(item.service) || !has(item.service.scheme) || item.service.scheme == '' || item.service.scheme in %s", celList(healthProbeSchemes))),
+			Message:    "the scheme of a probed service must be one of " + strings.Join(healthProbeSchemes, ", "),
+		},
+		{
+			FieldPath:  "spec.healthProbes.deployment.namespace",
+			Expression: eachItem(probes, "!has(item.deployment) || (has(item.deployment.namespace) && "+dnsMatch("item.deployment.namespace", dns1123LabelPattern, valutil.DNS1123LabelMaxLength)+")"),
+			Message:    "the namespace of a probed Deployment is required and must be a valid namespace name",
+		},
+		{
+			FieldPath:  "spec.healthProbes.deployment.name",
+			Expression: eachItem(probes, "!has(item.deployment) || (has(item.deployment.name) && "+dnsMatch("item.deployment.name", dns1123SubdomainPattern, valutil.DNS1123SubdomainMaxLength)+")"),
+			Message:    "the name of a probed Deployment is required and must be a valid Deployment name",
+		},
+		// Unlike the other rules, the authorization of the requesting
+		// user is not part of the go validation and is performed by
+		// the admission webhook.
+		{
+			FieldPath: "spec.secretRef.namespace",
+			Expression: optional(secretNamespace, fmt.Sprintf("%%[1]s == '' || %%[1]s == object.metadata.namespace || (%s && object.spec.local) || "+
+				"(oldObject != null && %s && oldObject.spec.secretRef == object.spec.secretRef) || "+
+				"authorizer.group('').resource('secrets').namespace(%%[1]s).name(object.spec.secretRef.name).check('get').allowed()",
+				celHas([]string{"spec", "local"}), celHasIn("oldObject", secretNamespace))),
+			Message: "the requesting user must be permitted to get the secret in its namespace",
+			Reason:  "Forbidden",
+		},
+	}
+}
+
+// kubeFedConfigRules validates the fields that are set, since the go
+// validation of a KubeFedConfig applies to its effective configuration
+// after defaults are applied.
+func kubeFedConfigRules() []AdmissionRule {
+	duration := []string{"spec", "controllerDuration"}
+	election := []string{"spec", "leaderElect"}
+	healthCheck := []string{"spec", "clusterHealthCheck"}
+	allowlist := []string{"spec", "allowedClusterScopedResources"}
+	hooks := []string{"spec", "clusterLifecycleHooks"}
+	hookWebhook := child(hooks, "webhook")
+	profiles := []string{"spec", "scheduling", "profiles"}
+	defaultProfile := []string{"spec", "scheduling", "defaultProfile"}
+	sinks := []string{"spec", "notifications", "sinks"}
+	rules := []AdmissionRule{
+		enumRule([]string{"spec", "scope"}, resourceScopes, false),
+		// Duplicate kinds and names are only rejected by the go
+		// validation.
+		{
+			FieldPath:  strings.Join(allowlist, "."),
+			Expression: optional(allowlist, fmt.Sprintf("size(%%s) == 0 || (has(object.spec.scope) && object.spec.scope == '%s')", apiextv1b1.NamespaceScoped)),
+			Message:    fmt.Sprintf("allowedClusterScopedResources may only be set for a %s control plane", apiextv1b1.NamespaceScoped),
+			Reason:     "Forbidden",
+		},
+		{
+			FieldPath:  strings.Join(child(allowlist, "group"), "."),
+			Expression: eachItem(allowlist, "!has(item.group) || item.group == '' || "+dnsMatch("item.group", dns1123SubdomainPattern, valutil.DNS1123SubdomainMaxLength)),
+			Message:    "group must be a valid API group",
+		},
+		{
+			FieldPath:  strings.Join(child(allowlist, "kind"), "."),
+			Expression: eachItem(allowlist, "has(item.kind) && item.kind != '' && !(item.kind == 'Namespace' && (!has(item.group) || item.group == ''))"),
+			Message:    "kind is required and may not be Namespace",
+		},
+		{
+			FieldPath:  strings.Join(child(allowlist, "names"), "."),
+			Expression: eachItem(allowlist, "has(item.names) && size(item.names) > 0 && item.names.all(name, name != '' && name != '.' && name != '..' && !name.contains('/') && !name.contains('%'))"),
+			Message:    "names are required and must be valid resource names",
+		},
+	}
+	for _, name := range []string{"availableDelay", "unavailableDelay", "statusUpdateInterval", "memberReadCacheTTL"} {
+		rules = append(rules, durationRule(child(duration, name), ">=", "must not be negative"))
+	}
+	rules = append(rules, enumRule(child(election, "resourceLock"), resourceLockTypes, false))
+	for _, name := range []string{"leaseDuration", "renewDeadline", "retryPeriod"} {
+		rules = append(rules, durationRule(child(election, name), ">", "must be greater than zero"))
+	}
+	rules = append(rules,
+		AdmissionRule{
+			FieldPath: "spec.leaderElect.renewDeadline",
+			Expression: fmt.Sprintf("!(%s) || !(%s) || duration(object.spec.leaderElect.renewDeadline) < duration(object.spec.leaderElect.leaseDuration)",
+				celHas(child(election, "renewDeadline")), celHas(child(election, "leaseDuration"))),
+			Message: "renewDeadline must be less than leaseDuration",
+		},
+		AdmissionRule{
+			FieldPath:  "spec.featureGates.name",
+			Expression: eachItem([]string{"spec", "featureGates"}, "has(item.name) && item.name != ''"),
+			Message:    "feature gate names are required",
+		},
+		AdmissionRule{
+			FieldPath:  "spec.featureGates.configuration",
+			Expression: eachItem([]string{"spec", "featureGates"}, fmt.Sprintf("has(item.configuration) && item.configuration in %s", celList(configurationModes))),
+			Message:    "feature gate configuration must be one of " + strings.Join(configurationModes, ", "),
+		},
+		AdmissionRule{
+			FieldPath:  "spec.controllers.name",
+			Expression: eachItem([]string{"spec", "controllers"}, fmt.Sprintf("has(item.name) && item.name in %s", celList(controllerNames()))),
+			Message:    "controller name must be one of " + strings.Join(controllerNames(), ", "),
+		},
+		AdmissionRule{
+			FieldPath:  "spec.controllers.configuration",
+			Expression: eachItem([]string{"spec", "controllers"}, fmt.Sprintf("has(item.configuration) && item.configuration in %s", celList(configurationModes))),
+			Message:    "controller configuration must be one of " + strings.Join(configurationModes, ", "),
+		},
+	)
+	for _, name := range []string{"periodSeconds", "failureThreshold", "successThreshold", "timeoutSeconds"} {
+		rules = append(rules, AdmissionRule{
+			FieldPath:  strings.Join(child(healthCheck, name), "."),
+			Expression: optional(child(healthCheck, name), "%s > 0"),
+			Message:    name + " must be greater than zero",
+		})
+	}
+	// Duplicate hook names are only rejected by the go validation.
+	rules = append(rules,
+		AdmissionRule{
+			FieldPath:  strings.Join(child(hooks, "name"), "."),
+			Expression: eachItem(hooks, "has(item.name) && "+dnsMatch("item.name", dns1123LabelPattern, ClusterLifecycleHookNameMaxLength)),
+			Message:    fmt.Sprintf("hook names are required and must be DNS labels of at most %d characters", ClusterLifecycleHookNameMaxLength),
+		},
+		AdmissionRule{
+			FieldPath:  strings.Join(child(hooks, "events"), "."),
+			Expression: eachItem(hooks, fmt.Sprintf("has(item.events) && size(item.events) > 0 && item.events.all(event, event in %s)", celList(clusterLifecycleEvents))),
+			Message:    "hook events are required and must be one of " + strings.Join(clusterLifecycleEvents, ", "),
+		},
+		AdmissionRule{
+			FieldPath:  strings.Join(hooks, "."),
+			Expression: eachItem(hooks, "has(item.webhook) != has(item.job)"),
+			Message:    "exactly one of webhook and job must be set for each hook",
+		},
+		AdmissionRule{
+			FieldPath:  strings.Join(child(hookWebhook, "url"), "."),
+			Expression: eachItem(hooks, "!has(item.webhook) || (has(item.webhook.url) && item.webhook.url != '')"),
+			Message:    "the url of a webhook hook is required",
+		},
+		AdmissionRule{
+			FieldPath:  strings.Join(child(hookWebhook, "timeoutSeconds"), "."),
+			Expression: eachItem(hooks, "!has(item.webhook) || !has(item.webhook.timeoutSeconds) || item.webhook.timeoutSeconds >= 0"),
+			Message:    "the timeoutSeconds of a webhook hook must not be negative",
+		},
+	)
+	rules = append(rules, enumRule([]string{"spec", "syncController", "adoptResources"}, resourceAdoptionModes, false))
+	rules = append(rules,
+		AdmissionRule{
+			FieldPath:  strings.Join(child(profiles, "name"), "."),
+			Expression: eachItem(profiles, fmt.Sprintf("has(item.name) && item.name != '' && %s.exists_one(other, has(other.name) && other.name == item.name)", celPath(profiles))),
+			Message:    "scheduling profile names are required and must be unique",
+		},
+		AdmissionRule{
+			FieldPath:  strings.Join(child(profiles, "filters"), "."),
+			Expression: eachItem(profiles, fmt.Sprintf("!has(item.filters) || item.filters.all(filter, filter in %s)", celList(schedulingFilters))),
+			Message:    "scheduling filters must be one of " + strings.Join(schedulingFilters, ", "),
+		},
+		AdmissionRule{
+			FieldPath:  strings.Join(child(profiles, "filters"), "."),
+			Expression: eachItem(profiles, fmt.Sprintf("!has(item.filters) || !('%s' in item.filters) || size(item.filters) == 1", v1beta1.NoneSchedulingFilter)),
+			Message:    fmt.Sprintf("the %s scheduling filter cannot be combined with other filters", v1beta1.NoneSchedulingFilter),
+		},
+		AdmissionRule{
+			FieldPath:  strings.Join(child(child(profiles, "scorers"), "type"), "."),
+			Expression: eachItem(profiles, fmt.Sprintf("!has(item.scorers) || item.scorers.all(scorer, has(scorer.type) && scorer.type in %s)", celList(schedulingScorerTypes))),
+			Message:    "scheduling scorer types are required and must be one of " + strings.Join(schedulingScorerTypes, ", "),
+		},
+		AdmissionRule{
+			FieldPath: strings.Join(child(child(profiles, "scorers"), "label"), "."),
+			Expression: eachItem(profiles, fmt.Sprintf("!has(item.scorers) || item.scorers.all(scorer, !has(scorer.type) || scorer.type != '%s' || (has(scorer.label) && scorer.label != ''))",
+				v1beta1.LabelSchedulingScorer)),
+			Message: fmt.Sprintf("the label of a %s scorer is required", v1beta1.LabelSchedulingScorer),
+		},
+		AdmissionRule{
+			FieldPath:  strings.Join(child(child(profiles, "scorers"), "weight"), "."),
+			Expression: eachItem(profiles, "!has(item.scorers) || item.scorers.all(scorer, !has(scorer.weight) || scorer.weight >= 0)"),
+			Message:    "the weight of a scheduling scorer must not be negative",
+		},
+		AdmissionRule{
+			FieldPath: strings.Join(defaultProfile, "."),
+			Expression: optional(defaultProfile, fmt.Sprintf("%%[1]s == '' || (%s && %s.exists(profile, has(profile.name) && profile.name == %%[1]s))",
+				celHas(profiles), celPath(profiles))),
+			Message: "defaultProfile must be the name of a scheduling profile",
+		},
+		AdmissionRule{
+			FieldPath:  strings.Join(child(sinks, "name"), "."),
+			Expression: eachItem(sinks, fmt.Sprintf("has(item.name) && item.name != '' && %s.exists_one(other, has(other.name) && other.name == item.name)", celPath(sinks))),
+			Message:    "notification sink names are required and must be unique",
+		},
+		AdmissionRule{
+			FieldPath:  strings.Join(child(sinks, "url"), "."),
+			Expression: eachItem(sinks, "has(item.url) && item.url.matches('^https?://[^/?#]+')"),
+			Message:    "the url of a notification sink is required and must be an http or https url",
+		},
+		AdmissionRule{
+			FieldPath:  strings.Join(child(sinks, "format"), "."),
+			Expression: eachItem(sinks, fmt.Sprintf("!has(item.format) || item.format in %s", celList(notificationFormats))),
+			Message:    "notification sink format must be one of " + strings.Join(notificationFormats, ", "),
+		},
+		AdmissionRule{
+			FieldPath:  strings.Join(child(sinks, "types"), "."),
+			Expression: eachItem(sinks, fmt.Sprintf("!has(item.types) || item.types.all(notificationType, notificationType in %s)", celList(notificationTypes))),
+			Message:    "notification types must be one of " + strings.Join(notificationTypes, ", "),
+		},
+		AdmissionRule{
+			FieldPath:  "spec.notifications.propagationFailureThreshold",
+			Expression: optional([]string{"spec", "notifications", "propagationFailureThreshold"}, "%s >= 0"),
+			Message:    "propagationFailureThreshold must not be negative",
+		},
+	)
+	return rules
+}
+
+// apiResourceRules returns the rules of ValidateAPIResource, and of
+// ValidateFederatedAPIResource if federated is true, for the resource
+// at path. The rules of an optional resource only apply if it is set.
+func apiResourceRules(path []string, federated, optionalResource bool) []AdmissionRule {
+	fieldPath := strings.Join(path, ".")
+	rules := []AdmissionRule{}
+	if federated {
+		rules = append(rules, AdmissionRule{
+			FieldPath:  fieldPath + ".group",
+			Expression: fmt.Sprintf("%s && %s.contains('.')", celHas(child(path, "group")), celPath(child(path, "group"))),
+			Message:    "group is required and " + domainWithAtLeastOneDot,
+		})
+	}
+	rules = append(rules,
+		AdmissionRule{
+			FieldPath:  fieldPath + ".group",
+			Expression: optional(child(path, "group"), "%[1]s == '' || "+dnsMatch("%[1]s", dns1123SubdomainPattern, valutil.DNS1123SubdomainMaxLength)),
+			Message:    "group must be a valid DNS-1123 subdomain",
+		},
+		AdmissionRule{
+			FieldPath:  fieldPath + ".version",
+			Expression: required(child(path, "version"), dnsMatch("%[1]s", dns1035LabelPattern, valutil.DNS1035LabelMaxLength)),
+			Message:    "version is required and must be a valid DNS-1035 label",
+		},
+		AdmissionRule{
+			FieldPath:  fieldPath + ".kind",
+			Expression: required(child(path, "kind"), dnsMatch("%[1]s.lowerAscii()", dns1035LabelPattern, valutil.DNS1035LabelMaxLength)),
+			Message:    "kind is required and must be a valid DNS-1035 label when lower-cased",
+		},
+		AdmissionRule{
+			FieldPath:  fieldPath + ".pluralName",
+			Expression: required(child(path, "pluralName"), dnsMatch("%[1]s", dns1035LabelPattern, valutil.DNS1035LabelMaxLength)),
+			Message:    "pluralName is required and must be a valid DNS-1035 label",
+		},
+		enumRule(child(path, "scope"), resourceScopes, true),
+	)
+	if optionalResource {
+		for i := range rules {
+			rules[i].Expression = fmt.Sprintf("!(%s) || (%s)", celHas(path), rules[i].Expression)
+		}
+	}
+	return rules
+}
+
+func enumRule(path, accepted []string, isRequired bool) AdmissionRule {
+	condition := "%s in " + celList(accepted)
+	rule := AdmissionRule{
+		FieldPath: strings.Join(path, "."),
+		Message:   fmt.Sprintf("%s must be one of %s", path[len(path)-1], strings.Join(accepted, ", ")),
+	}
+	if isRequired {
+		rule.Expression = required(path, condition)
+		rule.Message = fmt.Sprintf("%s is required and must be one of %s", path[len(path)-1], strings.Join(accepted, ", "))
+	} else {
+		rule.Expression = optional(path, condition)
+	}
+	return rule
+}
+
+func durationRule(path []string, operator, message string) AdmissionRule {
+	return AdmissionRule{
+		FieldPath:  strings.Join(path, "."),
+		Expression: optional(path, fmt.Sprintf("duration(%%s) %s duration('0s')", operator)),
+		Message:    path[len(path)-1] + " " + message,
+	}
+}
+
+// dnsMatch returns a format for the condition that the value is
+// matched by the pattern and no longer than maxLength.
+func dnsMatch(value, pattern string, maxLength int) string {
+	return fmt.Sprintf("size(%[1]s) <= %[2]d && %[1]s.matches('%[3]s')", value, maxLength, pattern)
+}
+
+// required returns an expression that is true if the field at path is
+// set and satisfies the condition, a format applied to the selection
+// of the field.
+func required(path []string, condition string) string {
+	return fmt.Sprintf("%s && %s", celHas(path), fmt.Sprintf(condition, celPath(path)))
+}
+
+// optional returns an expression that is true if the field at path is
+// not set or satisfies the condition, a format applied to the
+// selection of the field.
+func optional(path []string, condition string) string {
+	return fmt.Sprintf("!(%s) || %s", celHas(path), fmt.Sprintf(condition, celPath(path)))
+}
+
+// eachItem returns an expression that is true if every item of the
+// list at path, which is bound to 'item', satisfies the condition.
+func eachItem(path []string, condition string) string {
+	return optional(path, "%s.all(item, "+strings.Replace(condition, "%", "%%", -1)+")")
+}
+
+func child(path []string, name string) []string {
+	return append(append([]string{}, path...), name)
+}
+
+func celPath(path []string) string {
+	return "object." + strings.Join(path, ".")
+}
+
+// celHas returns an expression testing that the field at path and
+// each of its parents are set.
+func celHas(path []string) string {
+	return celHasIn("object", path)
+}
+
+// celHasIn returns an expression testing that the field at path and
+// each of its parents are set in the named variable.
+func celHasIn(variable string, path []string) string {
+	tests := []string{}
+	for i := range path {
+		tests = append(tests, fmt.Sprintf("has(%s.%s)", variable, strings.Join(path[:i+1], ".")))
+	}
+	return strings.Join(tests, " && ")
+}
+
+func celList(values []string) string {
+	quoted := []string{}
+	for _, value := range values {
+		quoted = append(quoted, fmt.Sprintf("'%s'", value))
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
@@ -0,0 +1,213 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	valutil "k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+func TestDNSPatterns(t *testing.T) {
+	values := []string{
+		"", "a", "a1", "1a", "a-b", "-a", "a-", "A", "a.b", "a..b", ".a", "a_b",
+		"v1beta1", "apps", "core.kubefed.k8s.io", strings.Repeat("a", 63), strings.Repeat("a", 64),
+	}
+	testCases := map[string]struct {
+		pattern   string
+		maxLength int
+		validate  func(string) []string
+	}{
+		"DNS-1035 label":     {dns1035LabelPattern, valutil.DNS1035LabelMaxLength, valutil.IsDNS1035Label},
+		"DNS-1123 label":     {dns1123LabelPattern, valutil.DNS1123LabelMaxLength, valutil.IsDNS1123Label},
+		"DNS-1123 subdomain": {dns1123SubdomainPattern, valutil.DNS1123SubdomainMaxLength, valutil.IsDNS1123Subdomain},
+	}
+	for name, tc := range testCases {
+		re := regexp.MustCompile(tc.pattern)
+		for _, value := range values {
+			expected := len(tc.validate(value)) == 0
+			matched := len(value) <= tc.maxLength && re.MatchString(value)
+			if matched != expected {
+				t.Errorf("%s: expected match of %q to be %v", name, value, expected)
+			}
+		}
+	}
+}
+
+// TestAdmissionPoliciesMatchValidation checks that the admission
+// policies validate the same fields as the go validation.
+func TestAdmissionPoliciesMatchValidation(t *testing.T) {
+	invalidOperation := v1beta1.DefaultOverrideOperation("Replace")
+	invalidStatusCollection := v1beta1.StatusCollectionMode("Sometimes")
+	invalidControllerStatus := v1beta1.ControllerStatus("Paused")
+	invalidResource := v1beta1.APIResource{Group: "Invalid_Group", Version: "V1", Kind: "Invalid_Kind", PluralName: "Invalid", Scope: "Global"}
+	invalidFederatedResource := v1beta1.APIResource{Group: "nodot", Version: "V1", Kind: "Invalid_Kind", PluralName: "Invalid", Scope: "Global"}
+
+	typeConfig := &v1beta1.FederatedTypeConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "invalid"},
+		Spec: v1beta1.FederatedTypeConfigSpec{
+			TargetType:       invalidResource,
+			TargetVersions:   []string{"V2"},
+			Propagation:      "Sometimes",
+			FederatedType:    invalidFederatedResource,
+			StatusType:       &invalidFederatedResource,
+			StatusCollection: &invalidStatusCollection,
+			DefaultOverrides: []v1beta1.DefaultOverride{
+				{Path: "metadata.name"},
+				{Path: "spec.replicas", Operation: invalidOperation},
+			},
+			Values:               &v1beta1.ValuesConfig{Path: "metadata.namespace", Format: "JSON"},
+			LocallyManagedFields: []string{".metadata.namespace"},
+			MemberEventFilter:    &v1beta1.MemberEventFilter{IgnoredAnnotations: []string{""}},
+			StatusUpdateInterval: &metav1.Duration{Duration: -time.Second},
+		},
+		Status: v1beta1.FederatedTypeConfigStatus{
+			ObservedGeneration:    -1,
+			PropagationController: "Paused",
+			StatusController:      &invalidControllerStatus,
+		},
+	}
+	cluster := &v1beta1.KubeFedCluster{
+		Spec: v1beta1.KubeFedClusterSpec{
+			APIEndpoint:           "https://cluster",
+			SecondaryAPIEndpoints: []string{"https://cluster"},
+			NamespaceMappings:     []v1beta1.NamespaceMapping{{Namespace: "Invalid", ClusterNamespace: "Invalid"}},
+			AllowedNamespaces:     []string{"Invalid"},
+			DeniedNamespaces:      []string{"Invalid"},
+			SecretRef:             v1beta1.LocalSecretReference{Name: "cluster", Namespace: "Invalid"},
+			ProfileRef:            &apiv1.LocalObjectReference{Name: "Invalid"},
+			HealthProbes: []v1beta1.ClusterHealthProbe{
+				{Name: "Invalid"},
+				{Name: "service", Service: &v1beta1.ClusterServiceProbe{Namespace: "Invalid", Name: "Invalid", Scheme: "ftp"}},
+				{Name: "deployment", Deployment: &v1beta1.ClusterDeploymentProbe{Namespace: "Invalid", Name: "Invalid"}},
+			},
+		},
+	}
+	configSpec := &v1beta1.KubeFedConfigSpec{
+		Scope: "Global",
+		AllowedClusterScopedResources: []v1beta1.AllowedClusterScopedResource{
+			{Group: "Invalid_Group", Names: []string{"a/b"}},
+		},
+		ControllerDuration: v1beta1.DurationConfig{
+			AvailableDelay:       metav1.Duration{Duration: -time.Second},
+			UnavailableDelay:     metav1.Duration{Duration: -time.Second},
+			StatusUpdateInterval: metav1.Duration{Duration: -time.Second},
+			MemberReadCacheTTL:   &metav1.Duration{Duration: -time.Second},
+		},
+		LeaderElect: v1beta1.LeaderElectConfig{
+			ResourceLock: "leases",
+		},
+		FeatureGates: []v1beta1.FeatureGatesConfig{{Configuration: "Sometimes"}},
+		Controllers:  []v1beta1.ControllerSwitchConfig{{Name: "Invalid", Configuration: "Sometimes"}},
+		ClusterLifecycleHooks: []v1beta1.ClusterLifecycleHook{
+			{Name: "Invalid", Events: []v1beta1.ClusterLifecycleEvent{"Deleted"}},
+			{Name: "webhook", Events: []v1beta1.ClusterLifecycleEvent{v1beta1.ClusterJoinedEvent},
+				Webhook: &v1beta1.ClusterLifecycleWebhook{TimeoutSeconds: -1}},
+		},
+		SyncController: v1beta1.SyncControllerConfig{
+			AdoptResources: "Sometimes",
+		},
+		Scheduling: &v1beta1.SchedulingConfig{
+			Profiles: []v1beta1.SchedulingProfile{
+				{
+					Filters: []v1beta1.SchedulingFilter{"Unknown"},
+					Scorers: []v1beta1.SchedulingScorer{{Type: "Unknown", Weight: -1}, {Type: v1beta1.LabelSchedulingScorer}},
+				},
+			},
+			DefaultProfile: "undefined",
+		},
+		Notifications: &v1beta1.NotificationConfig{
+			Sinks: []v1beta1.NotificationSink{
+				{URL: "ftp://example.com", Format: "Email", Types: []v1beta1.NotificationType{"ClusterDeleted"}},
+			},
+			PropagationFailureThreshold: -1,
+		},
+	}
+
+	validated := map[string]field.ErrorList{
+		"federatedtypeconfigs":        ValidateFederatedTypeConfig(typeConfig, false),
+		"federatedtypeconfigs/status": ValidateFederatedTypeConfig(typeConfig, true),
+		"kubefedclusters":             ValidateKubeFedCluster(cluster),
+		"kubefedconfigs":              ValidateKubeFedConfigSpec(configSpec, field.NewPath("spec")),
+	}
+	index := regexp.MustCompile(`\[[0-9]+\]`)
+	for _, policy := range AdmissionPolicies() {
+		errs, ok := validated[policy.Resource]
+		if !ok {
+			t.Errorf("No validation is tested for policy %q", policy.Name)
+			continue
+		}
+		validatedPaths := sets.NewString()
+		for _, err := range errs {
+			path := index.ReplaceAllString(err.Field, "")
+			// The go validation reports the name of a type config
+			// without the metadata prefix.
+			if path == "name" {
+				path = "metadata.name"
+			}
+			validatedPaths.Insert(path)
+		}
+		rulePaths := sets.NewString()
+		for _, rule := range policy.Rules {
+			rulePaths.Insert(rule.FieldPath)
+		}
+		if !validatedPaths.Equal(rulePaths) {
+			t.Errorf("Policy %q: expected rules for %v, got %v", policy.Name, sorted(validatedPaths), sorted(rulePaths))
+		}
+	}
+}
+
+func TestAdmissionRuleExpressions(t *testing.T) {
+	for _, policy := range AdmissionPolicies() {
+		for _, rule := range policy.Rules {
+			if strings.Contains(rule.Expression, "%!") {
+				t.Errorf("Policy %q: expression for %s is not formatted correctly: %s", policy.Name, rule.FieldPath, rule.Expression)
+			}
+			depth := 0
+			for _, r := range rule.Expression {
+				switch r {
+				case '(':
+					depth++
+				case ')':
+					depth--
+				}
+			}
+			if depth != 0 {
+				t.Errorf("Policy %q: expression for %s has unbalanced parentheses: %s", policy.Name, rule.FieldPath, rule.Expression)
+			}
+			if len(rule.Message) == 0 {
+				t.Errorf("Policy %q: rule for %s has no message", policy.Name, rule.FieldPath)
+			}
+		}
+	}
+}
+
+func sorted(set sets.String) []string {
+	list := set.List()
+	sort.Strings(list)
+	return list
+}
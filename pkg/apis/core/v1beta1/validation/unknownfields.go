@@ -0,0 +1,200 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// kubefedPackagePrefix identifies the go types whose fields are
+// checked for unknown fields.  Types of other packages (e.g. of the
+// core kubernetes API) may gain fields in API servers newer than the
+// vendored API, so their fields are not checked.
+const kubefedPackagePrefix = "sigs.k8s.io/kubefed/"
+
+var (
+	objectMetaType  = reflect.TypeOf(metav1.ObjectMeta{})
+	unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+)
+
+// UnknownFields returns an error for each field of the given json
+// object that is not a field of the go type of obj.  The API server
+// preserves the unknown fields of the KubeFed CRDs, so a misspelled
+// field (e.g. `featuregates`) would otherwise be silently ignored.
+func UnknownFields(raw []byte, obj interface{}) (field.ErrorList, error) {
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil, errors.Wrap(err, "Failed to decode object")
+	}
+	return unknownFields(value, reflect.TypeOf(obj), nil), nil
+}
+
+// ValidateUnknownFields returns an error for each unknown field of the
+// given json object.  Unknown fields that are also present in the
+// previous version of the object (oldRaw, which may be empty) are
+// instead returned as warnings, so that objects created before unknown
+// fields were rejected can still be updated until they are corrected.
+func ValidateUnknownFields(raw, oldRaw []byte, obj interface{}) (field.ErrorList, []string, error) {
+	errs, err := UnknownFields(raw, obj)
+	if err != nil || len(errs) == 0 || len(oldRaw) == 0 {
+		return errs, nil, err
+	}
+	oldErrs, err := UnknownFields(oldRaw, obj)
+	if err != nil {
+		return errs, nil, nil
+	}
+	oldFields := sets.NewString()
+	for _, oldErr := range oldErrs {
+		oldFields.Insert(oldErr.Field)
+	}
+	var newErrs field.ErrorList
+	var warnings []string
+	for _, err := range errs {
+		if oldFields.Has(err.Field) {
+			warnings = append(warnings, err.Error())
+			continue
+		}
+		newErrs = append(newErrs, err)
+	}
+	return newErrs, warnings, nil
+}
+
+func unknownFields(value interface{}, t reflect.Type, fldPath *field.Path) field.ErrorList {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if !checkedType(t) {
+		return nil
+	}
+
+	errs := field.ErrorList{}
+	switch t.Kind() {
+	case reflect.Struct:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		fields := jsonFields(t)
+		for _, key := range sortedKeys(object) {
+			fieldType, ok := fields[key]
+			if !ok {
+				errs = append(errs, unknownField(fldPath.Child(key), key, fields))
+				continue
+			}
+			errs = append(errs, unknownFields(object[key], fieldType, fldPath.Child(key))...)
+		}
+	case reflect.Slice, reflect.Array:
+		items, ok := value.([]interface{})
+		if !ok {
+			return nil
+		}
+		for i, item := range items {
+			errs = append(errs, unknownFields(item, t.Elem(), fldPath.Index(i))...)
+		}
+	case reflect.Map:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		for _, key := range sortedKeys(object) {
+			errs = append(errs, unknownFields(object[key], t.Elem(), fldPath.Key(key))...)
+		}
+	}
+	return errs
+}
+
+// checkedType returns whether the fields of values of the given type
+// are checked.  Types that decode themselves and types of other
+// packages are treated as opaque.
+func checkedType(t reflect.Type) bool {
+	if t == objectMetaType || t.Kind() == reflect.Interface {
+		return false
+	}
+	if t.Implements(unmarshalerType) || reflect.PtrTo(t).Implements(unmarshalerType) {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		pkgPath := t.PkgPath()
+		return strings.HasPrefix(pkgPath, kubefedPackagePrefix) && !strings.Contains(pkgPath, "/vendor/")
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Ptr:
+		return t.Elem().Kind() != reflect.Uint8
+	}
+	return false
+}
+
+// jsonFields returns the types of the fields of the given struct type
+// by json name, including the fields of inlined structs.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		structField := t.Field(i)
+		tag := structField.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if structField.Anonymous && len(name) == 0 {
+			embedded := structField.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				for key, fieldType := range jsonFields(embedded) {
+					fields[key] = fieldType
+				}
+				continue
+			}
+		}
+		if len(structField.PkgPath) != 0 {
+			// Unexported
+			continue
+		}
+		if len(name) == 0 {
+			name = structField.Name
+		}
+		fields[name] = structField.Type
+	}
+	return fields
+}
+
+func unknownField(fldPath *field.Path, key string, fields map[string]reflect.Type) *field.Error {
+	for name := range fields {
+		if strings.EqualFold(name, key) {
+			return field.Forbidden(fldPath, `unknown field, did you mean "`+name+`"?`)
+		}
+	}
+	return field.Forbidden(fldPath, "unknown field")
+}
+
+func sortedKeys(object map[string]interface{}) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+func TestUnknownFields(t *testing.T) {
+	testCases := []struct {
+		name           string
+		raw            string
+		obj            interface{}
+		expectedFields []string
+	}{
+		{
+			name: "known fields",
+			raw:  `{"apiVersion":"core.kubefed.k8s.io/v1beta1","kind":"KubeFedConfig","metadata":{"name":"kubefed","managedFields":[]},"spec":{"scope":"Namespaced","featureGates":[{"name":"PushReconciler","configuration":"Enabled"}],"controllerDuration":{"availableDelay":"20s"}}}`,
+			obj:  &v1beta1.KubeFedConfig{},
+		},
+		{
+			name:           "misspelled field",
+			raw:            `{"spec":{"featuregates":[{"name":"PushReconciler","configuration":"Enabled"}]}}`,
+			obj:            &v1beta1.KubeFedConfig{},
+			expectedFields: []string{"spec.featuregates"},
+		},
+		{
+			name:           "unknown fields of list items and pointers",
+			raw:            `{"spec":{"featureGates":[{"name":"PushReconciler","enabled":true}],"notifications":{"sinks":[],"url":""}}}`,
+			obj:            &v1beta1.KubeFedConfig{},
+			expectedFields: []string{"spec.featureGates[0].enabled", "spec.notifications.url"},
+		},
+		{
+			name: "fields of other packages are not checked",
+			raw:  `{"spec":{"apiEndpoint":"https://cluster","profileRef":{"name":"profile","namespace":"ignored"}}}`,
+			obj:  &v1beta1.KubeFedCluster{},
+		},
+	}
+
+	for _, test := range testCases {
+		errs, err := UnknownFields([]byte(test.raw), test.obj)
+		if err != nil {
+			t.Fatalf("[%s] unexpected error: %v", test.name, err)
+		}
+		var fields []string
+		for _, err := range errs {
+			fields = append(fields, err.Field)
+		}
+		if strings.Join(fields, ",") != strings.Join(test.expectedFields, ",") {
+			t.Errorf("[%s] unexpected unknown fields: %v, expected: %v", test.name, fields, test.expectedFields)
+		}
+	}
+}
+
+func TestUnknownFieldsOfValidTypeConfig(t *testing.T) {
+	raw, err := json.Marshal(validFederatedTypeConfig())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	errs, err := UnknownFields(raw, &v1beta1.FederatedTypeConfig{})
+	if err != nil || len(errs) != 0 {
+		t.Errorf("Expected no unknown fields, got %v (%v)", errs, err)
+	}
+}
+
+func TestValidateUnknownFields(t *testing.T) {
+	raw := []byte(`{"spec":{"featuregates":[],"scpoe":"Cluster"}}`)
+
+	errs, warnings, err := ValidateUnknownFields(raw, nil, &v1beta1.KubeFedConfig{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(errs) != 2 || len(warnings) != 0 {
+		t.Errorf("Expected 2 errors on create, got %v and warnings %q", errs, warnings)
+	}
+	if !strings.Contains(errs[0].Error(), `did you mean "featureGates"?`) {
+		t.Errorf("Expected a suggestion for %s, got %q", errs[0].Field, errs[0].Error())
+	}
+
+	oldRaw := []byte(`{"spec":{"featuregates":[]}}`)
+	errs, warnings, err = ValidateUnknownFields(raw, oldRaw, &v1beta1.KubeFedConfig{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(errs) != 1 || errs[0].Field != "spec.scpoe" {
+		t.Errorf("Expected an error for spec.scpoe only, got %v", errs)
+	}
+	if len(warnings) != 1 || !strings.HasPrefix(warnings[0], "spec.featuregates") {
+		t.Errorf("Expected a warning for spec.featuregates, got %q", warnings)
+	}
+}
@@ -17,17 +17,58 @@ limitations under the License.
 package validation
 
 import (
+	"fmt"
+	"net/url"
 	"strings"
 
 	apiextv1b1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
 	apimachineryval "k8s.io/apimachinery/pkg/api/validation"
+	"k8s.io/apimachinery/pkg/api/validation/path"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
 	valutil "k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	"sigs.k8s.io/kubefed/pkg/apis/core/typeconfig"
 	"sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/kubefed/pkg/apis/core/v1beta1/validation/valuesschema"
 )
 
+// ClusterLifecycleHookNameMaxLength is the maximum length of the name
+// of a lifecycle hook, which leaves room for the event and a hash in
+// the names of the Jobs of the hook.
+const ClusterLifecycleHookNameMaxLength = 32
+
+// The values accepted for enumerated fields, which are shared with
+// the rules of the admission policies generated from this package.
+var (
+	propagationModes          = []string{string(v1beta1.PropagationEnabled), string(v1beta1.PropagationDisabled), string(v1beta1.PropagationPaused)}
+	statusCollectionModes     = []string{string(v1beta1.StatusCollectionEnabled), string(v1beta1.StatusCollectionDisabled)}
+	controllerStatuses        = []string{string(v1beta1.ControllerStatusRunning), string(v1beta1.ControllerStatusNotRunning)}
+	defaultOverrideOperations = []string{string(v1beta1.DefaultOverrideSet), string(v1beta1.DefaultOverrideRemove)}
+	valuesFormats             = []string{string(v1beta1.ValuesFormatObject), string(v1beta1.ValuesFormatYAML)}
+	resourceScopes            = []string{string(apiextv1b1.ClusterScoped), string(apiextv1b1.NamespaceScoped)}
+	configurationModes        = []string{string(v1beta1.ConfigurationEnabled), string(v1beta1.ConfigurationDisabled)}
+	resourceLockTypes         = []string{string(v1beta1.ConfigMapsResourceLock), string(v1beta1.EndpointsResourceLock)}
+	resourceAdoptionModes     = []string{string(v1beta1.AdoptResourcesEnabled), string(v1beta1.AdoptResourcesDisabled), string(v1beta1.AdoptResourcesReportOnly)}
+	clusterLifecycleEvents    = []string{string(v1beta1.ClusterJoinedEvent), string(v1beta1.ClusterApprovedEvent), string(v1beta1.ClusterUnhealthyEvent), string(v1beta1.ClusterUnjoinedEvent)}
+	healthProbeSchemes        = []string{"http", "https"}
+	schedulingFilters         = []string{string(v1beta1.DegradedSchedulingFilter), string(v1beta1.TaintsSchedulingFilter), string(v1beta1.PressureSchedulingFilter), string(v1beta1.HeadroomSchedulingFilter), string(v1beta1.NoneSchedulingFilter)}
+	schedulingScorerTypes     = []string{string(v1beta1.APILatencySchedulingScorer), string(v1beta1.HeadroomSchedulingScorer), string(v1beta1.LabelSchedulingScorer)}
+	notificationFormats       = []string{string(v1beta1.WebhookNotificationFormat), string(v1beta1.SlackNotificationFormat)}
+	notificationTypes         = []string{string(v1beta1.ClusterNotReadyNotification), string(v1beta1.ClusterReadyNotification), string(v1beta1.PropagationFailedNotification)}
+	notificationURLSchemes    = []string{"http", "https"}
+)
+
+func controllerNames() []string {
+	names := []string{}
+	for _, name := range v1beta1.ControllerNames {
+		names = append(names, string(name))
+	}
+	return names
+}
+
 func ValidateFederatedTypeConfig(obj *v1beta1.FederatedTypeConfig, statusSubResource bool) field.ErrorList {
 	var allErrs field.ErrorList
 	if !statusSubResource {
@@ -39,6 +80,39 @@ func ValidateFederatedTypeConfig(obj *v1beta1.FederatedTypeConfig, statusSubReso
 	return allErrs
 }
 
+// FederatedTypeConfigWarnings returns the problems with the given
+// type config that are not severe enough to reject it.
+func FederatedTypeConfigWarnings(obj *v1beta1.FederatedTypeConfig, statusSubResource bool) []string {
+	if statusSubResource {
+		return nil
+	}
+	var warnings []string
+	targetType := obj.Spec.TargetType
+	gvk := schema.GroupVersionKind{Group: targetType.Group, Version: targetType.Version, Kind: targetType.Kind}
+	if replacement, ok := deprecatedTargetTypes[gvk]; ok {
+		warnings = append(warnings, fmt.Sprintf("spec.targetType: %s %s is deprecated, use %s instead",
+			gvk.GroupVersion(), gvk.Kind, replacement))
+	}
+	return warnings
+}
+
+// Target types served by versions that are deprecated by Kubernetes,
+// mapped to the version that replaces them.
+var deprecatedTargetTypes = map[schema.GroupVersionKind]string{
+	{Group: "extensions", Version: "v1beta1", Kind: "DaemonSet"}:         "apps/v1",
+	{Group: "extensions", Version: "v1beta1", Kind: "Deployment"}:        "apps/v1",
+	{Group: "extensions", Version: "v1beta1", Kind: "Ingress"}:           "networking.k8s.io/v1beta1",
+	{Group: "extensions", Version: "v1beta1", Kind: "NetworkPolicy"}:     "networking.k8s.io/v1",
+	{Group: "extensions", Version: "v1beta1", Kind: "PodSecurityPolicy"}: "policy/v1beta1",
+	{Group: "extensions", Version: "v1beta1", Kind: "ReplicaSet"}:        "apps/v1",
+	{Group: "apps", Version: "v1beta1", Kind: "Deployment"}:              "apps/v1",
+	{Group: "apps", Version: "v1beta1", Kind: "StatefulSet"}:             "apps/v1",
+	{Group: "apps", Version: "v1beta2", Kind: "DaemonSet"}:               "apps/v1",
+	{Group: "apps", Version: "v1beta2", Kind: "Deployment"}:              "apps/v1",
+	{Group: "apps", Version: "v1beta2", Kind: "ReplicaSet"}:              "apps/v1",
+	{Group: "apps", Version: "v1beta2", Kind: "StatefulSet"}:             "apps/v1",
+}
+
 const federatedTypeConfigNameErrorMsg string = "name must be 'TARGET_PLURAL_NAME(.TARGET_GROUP_NAME)'"
 
 func ValidateFederatedTypeConfigName(obj *v1beta1.FederatedTypeConfig) field.ErrorList {
@@ -51,19 +125,121 @@ func ValidateFederatedTypeConfigName(obj *v1beta1.FederatedTypeConfig) field.Err
 
 func ValidateFederatedTypeConfigSpec(spec *v1beta1.FederatedTypeConfigSpec, fldPath *field.Path) field.ErrorList {
 	allErrs := ValidateAPIResource(&spec.TargetType, fldPath.Child("targetType"))
-	allErrs = append(allErrs, validateEnumStrings(fldPath.Child("propagation"), string(spec.Propagation), []string{string(v1beta1.PropagationEnabled), string(v1beta1.PropagationDisabled)})...)
+	allErrs = append(allErrs, validateEnumStrings(fldPath.Child("propagation"), string(spec.Propagation), propagationModes)...)
+	allErrs = append(allErrs, validateTargetVersions(spec.TargetType.Version, spec.TargetVersions, fldPath.Child("targetVersions"))...)
 	allErrs = append(allErrs, ValidateFederatedAPIResource(&spec.FederatedType, fldPath.Child("federatedType"))...)
 	if spec.StatusType != nil {
 		allErrs = append(allErrs, ValidateStatusAPIResource(spec.StatusType, fldPath.Child("statusType"))...)
+		// The status of a federated resource is recorded in an
+		// object of the same name and namespace.
+		if spec.StatusType.Scope != spec.FederatedType.Scope {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("statusType", "scope"), spec.StatusType.Scope, statusTypeScopeErrorMsg))
+		}
 	}
 
 	if spec.StatusCollection != nil {
-		allErrs = append(allErrs, validateEnumStrings(fldPath.Child("statusCollection"), string(*spec.StatusCollection), []string{string(v1beta1.StatusCollectionEnabled), string(v1beta1.StatusCollectionDisabled)})...)
+		allErrs = append(allErrs, validateEnumStrings(fldPath.Child("statusCollection"), string(*spec.StatusCollection), statusCollectionModes)...)
 	}
+	if spec.StatusUpdateInterval != nil {
+		allErrs = append(allErrs, validateNonnegativeDuration(*spec.StatusUpdateInterval, fldPath.Child("statusUpdateInterval"))...)
+	}
+
+	for i, override := range spec.DefaultOverrides {
+		allErrs = append(allErrs, ValidateDefaultOverride(&override, fldPath.Child("defaultOverrides").Index(i))...)
+	}
+
+	if spec.Values != nil {
+		allErrs = append(allErrs, ValidateValuesConfig(spec.Values, fldPath.Child("values"))...)
+	}
+
+	for i, path := range spec.LocallyManagedFields {
+		allErrs = append(allErrs, validateFieldPath(strings.TrimPrefix(path, "."), fldPath.Child("locallyManagedFields").Index(i))...)
+	}
+
+	if spec.MemberEventFilter != nil {
+		for i, key := range spec.MemberEventFilter.IgnoredAnnotations {
+			if len(key) == 0 {
+				allErrs = append(allErrs, field.Required(fldPath.Child("memberEventFilter", "ignoredAnnotations").Index(i), ""))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+func validateTargetVersions(targetVersion string, versions []string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	seen := sets.NewString(targetVersion)
+	for i, version := range versions {
+		versionPath := fldPath.Index(i)
+		if len(version) == 0 {
+			allErrs = append(allErrs, field.Required(versionPath, ""))
+		} else if errs := valutil.IsDNS1035Label(version); len(errs) > 0 {
+			allErrs = append(allErrs, field.Invalid(versionPath, version, strings.Join(errs, ",")))
+		} else if seen.Has(version) {
+			allErrs = append(allErrs, field.Duplicate(versionPath, version))
+		}
+		seen.Insert(version)
+	}
+	return allErrs
+}
+
+// Fields that associate a federated resource with the resources in
+// member clusters cannot be overridden.
+var invalidOverridePaths = []string{
+	"metadata.namespace",
+	"metadata.name",
+	"metadata.generateName",
+}
+
+func validateFieldPath(path string, fldPath *field.Path) field.ErrorList {
+	if len(path) == 0 {
+		return field.ErrorList{field.Required(fldPath, "")}
+	}
+	for _, invalidPath := range invalidOverridePaths {
+		if path == invalidPath {
+			return field.ErrorList{field.Forbidden(fldPath, "path must not be one of "+strings.Join(invalidOverridePaths, ", "))}
+		}
+	}
+	return field.ErrorList{}
+}
+
+func ValidateDefaultOverride(override *v1beta1.DefaultOverride, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	allErrs = append(allErrs, validateFieldPath(override.Path, fldPath.Child("path"))...)
+
+	switch override.Operation {
+	case "", v1beta1.DefaultOverrideSet:
+		if override.Value == nil {
+			allErrs = append(allErrs, field.Required(fldPath.Child("value"), "value is required for the Set operation"))
+		}
+	case v1beta1.DefaultOverrideRemove:
+		if override.Value != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("value"), "value must not be set for the Remove operation"))
+		}
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("operation"), override.Operation, defaultOverrideOperations))
+	}
+
+	return allErrs
+}
 
+func ValidateValuesConfig(config *v1beta1.ValuesConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := validateFieldPath(config.Path, fldPath.Child("path"))
+	if len(config.Format) > 0 {
+		allErrs = append(allErrs, validateEnumStrings(fldPath.Child("format"), string(config.Format), valuesFormats)...)
+	}
+	if len(config.Schema) > 0 {
+		if _, err := valuesschema.Parse(config.Schema); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("schema"), "", err.Error()))
+		}
+	}
 	return allErrs
 }
 
+const statusTypeScopeErrorMsg string = "must match the scope of the federated type"
+
 const domainWithAtLeastOneDot string = "should be a domain with at least one dot"
 
 func ValidateFederatedAPIResource(fedType *v1beta1.APIResource, fldPath *field.Path) field.ErrorList {
@@ -110,7 +286,54 @@ func ValidateAPIResource(obj *v1beta1.APIResource, fldPath *field.Path) field.Er
 		allErrs = append(allErrs, field.Invalid(fldPath.Child("pluralName"), obj.PluralName, strings.Join(errs, ",")))
 	}
 
-	allErrs = append(allErrs, validateEnumStrings(fldPath.Child("scope"), string(obj.Scope), []string{string(apiextv1b1.ClusterScoped), string(apiextv1b1.NamespaceScoped)})...)
+	allErrs = append(allErrs, validateEnumStrings(fldPath.Child("scope"), string(obj.Scope), resourceScopes)...)
+
+	return allErrs
+}
+
+// ValidateTargetTypeServed checks that the given target type is among
+// the resources served by the host cluster for its group version, as
+// returned by API discovery.  A nil resource list indicates that the
+// group version is not served at all.
+func ValidateTargetTypeServed(targetType *v1beta1.APIResource, resources *metav1.APIResourceList, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	groupVersion := schema.GroupVersion{Group: targetType.Group, Version: targetType.Version}.String()
+	if resources == nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("version"), targetType.Version,
+			fmt.Sprintf("%s is not served by the host cluster", groupVersion)))
+		return allErrs
+	}
+
+	var served *metav1.APIResource
+	for i := range resources.APIResources {
+		resource := &resources.APIResources[i]
+		// Subresources (e.g. deployments/status) share the kind of
+		// their parent resource.
+		if resource.Kind == targetType.Kind && !strings.Contains(resource.Name, "/") {
+			served = resource
+			break
+		}
+	}
+	if served == nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("kind"), targetType.Kind,
+			fmt.Sprintf("kind is not served by the host cluster for %s", groupVersion)))
+		return allErrs
+	}
+
+	if served.Name != targetType.PluralName {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("pluralName"), targetType.PluralName,
+			fmt.Sprintf("must match the resource %q served by the host cluster", served.Name)))
+	}
+
+	scope := apiextv1b1.ClusterScoped
+	if served.Namespaced {
+		scope = apiextv1b1.NamespaceScoped
+	}
+	if targetType.Scope != scope {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("scope"), targetType.Scope,
+			fmt.Sprintf("must match the scope %q of the resource served by the host cluster", scope)))
+	}
 
 	return allErrs
 }
@@ -131,15 +354,431 @@ func ValidateFederatedTypeConfigStatus(status *v1beta1.FederatedTypeConfigStatus
 	allErrs := field.ErrorList{}
 
 	allErrs = append(allErrs, apimachineryval.ValidateNonnegativeField(status.ObservedGeneration, fldPath.Child("observedGeneration"))...)
-	allErrs = append(allErrs, validateEnumStrings(fldPath.Child("propagationController"), string(status.PropagationController), []string{string(v1beta1.ControllerStatusRunning), string(v1beta1.ControllerStatusNotRunning)})...)
+	allErrs = append(allErrs, validateEnumStrings(fldPath.Child("propagationController"), string(status.PropagationController), controllerStatuses)...)
 
 	if status.StatusController != nil {
-		allErrs = append(allErrs, validateEnumStrings(fldPath.Child("statusController"), string(*status.StatusController), []string{string(v1beta1.ControllerStatusRunning), string(v1beta1.ControllerStatusNotRunning)})...)
+		allErrs = append(allErrs, validateEnumStrings(fldPath.Child("statusController"), string(*status.StatusController), controllerStatuses)...)
 	}
 	return allErrs
 }
 
 func ValidateKubeFedCluster(object *v1beta1.KubeFedCluster) field.ErrorList {
 	allErrs := field.ErrorList{}
+	allErrs = append(allErrs, validateSecondaryAPIEndpoints(object.Spec.APIEndpoint, object.Spec.SecondaryAPIEndpoints, field.NewPath("spec", "secondaryAPIEndpoints"))...)
+	allErrs = append(allErrs, ValidateNamespaceMappings(object.Spec.NamespaceMappings, field.NewPath("spec", "namespaceMappings"))...)
+	allErrs = append(allErrs, validateNamespaceNames(object.Spec.AllowedNamespaces, field.NewPath("spec", "allowedNamespaces"))...)
+	allErrs = append(allErrs, validateNamespaceNames(object.Spec.DeniedNamespaces, field.NewPath("spec", "deniedNamespaces"))...)
+	if secretNamespace := object.Spec.SecretRef.Namespace; len(secretNamespace) != 0 {
+		allErrs = append(allErrs, validateNamespaceName(secretNamespace, field.NewPath("spec", "secretRef", "namespace"))...)
+	}
+	if profileRef := object.Spec.ProfileRef; profileRef != nil {
+		namePath := field.NewPath("spec", "profileRef", "name")
+		if len(profileRef.Name) == 0 {
+			allErrs = append(allErrs, field.Required(namePath, ""))
+		} else if errs := valutil.IsDNS1123Subdomain(profileRef.Name); len(errs) > 0 {
+			allErrs = append(allErrs, field.Invalid(namePath, profileRef.Name, strings.Join(errs, ",")))
+		}
+	}
+	allErrs = append(allErrs, validateClusterHealthProbes(object.Spec.HealthProbes, field.NewPath("spec", "healthProbes"))...)
+	return allErrs
+}
+
+// validateClusterHealthProbes checks that each health probe has a
+// unique name and probes either a service or a Deployment that is
+// identified by a valid namespace and name.
+func validateClusterHealthProbes(probes []v1beta1.ClusterHealthProbe, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	names := sets.NewString()
+	for i, probe := range probes {
+		probePath := fldPath.Index(i)
+		namePath := probePath.Child("name")
+		switch {
+		case len(probe.Name) == 0:
+			allErrs = append(allErrs, field.Required(namePath, ""))
+		case names.Has(probe.Name):
+			allErrs = append(allErrs, field.Duplicate(namePath, probe.Name))
+		default:
+			for _, msg := range valutil.IsDNS1123Label(probe.Name) {
+				allErrs = append(allErrs, field.Invalid(namePath, probe.Name, msg))
+			}
+		}
+		names.Insert(probe.Name)
+
+		if (probe.Service == nil) == (probe.Deployment == nil) {
+			allErrs = append(allErrs, field.Invalid(probePath, probe.Name, "exactly one of service and deployment must be set"))
+		}
+		if service := probe.Service; service != nil {
+			servicePath := probePath.Child("service")
+			allErrs = append(allErrs, validateProbedObject(service.Namespace, service.Name, valutil.IsDNS1035Label, servicePath)...)
+			if len(service.Scheme) != 0 {
+				allErrs = append(allErrs, validateEnumStrings(servicePath.Child("scheme"), service.Scheme, healthProbeSchemes)...)
+			}
+		}
+		if deployment := probe.Deployment; deployment != nil {
+			allErrs = append(allErrs, validateProbedObject(deployment.Namespace, deployment.Name, valutil.IsDNS1123Subdomain, probePath.Child("deployment"))...)
+		}
+	}
+	return allErrs
+}
+
+// validateProbedObject checks the namespace and the name of an object
+// probed by a health probe, whose name is validated by the given
+// function.
+func validateProbedObject(namespace, name string, validateName func(string) []string, fldPath *field.Path) field.ErrorList {
+	allErrs := validateNamespaceName(namespace, fldPath.Child("namespace"))
+	namePath := fldPath.Child("name")
+	if len(name) == 0 {
+		allErrs = append(allErrs, field.Required(namePath, ""))
+	} else if errs := validateName(name); len(errs) > 0 {
+		allErrs = append(allErrs, field.Invalid(namePath, name, strings.Join(errs, ",")))
+	}
+	return allErrs
+}
+
+// ValidateKubeFedConfigSpec validates the effective configuration of
+// a control plane, i.e. a spec to which defaults have been applied.
+func ValidateKubeFedConfigSpec(spec *v1beta1.KubeFedConfigSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	// A control plane without a scope targets all namespaces.
+	if len(spec.Scope) != 0 {
+		allErrs = append(allErrs, validateEnumStrings(fldPath.Child("scope"), string(spec.Scope), resourceScopes)...)
+	}
+	allErrs = append(allErrs, validateAllowedClusterScopedResources(spec, fldPath.Child("allowedClusterScopedResources"))...)
+
+	durationPath := fldPath.Child("controllerDuration")
+	allErrs = append(allErrs, validateNonnegativeDuration(spec.ControllerDuration.AvailableDelay, durationPath.Child("availableDelay"))...)
+	allErrs = append(allErrs, validateNonnegativeDuration(spec.ControllerDuration.UnavailableDelay, durationPath.Child("unavailableDelay"))...)
+	allErrs = append(allErrs, validateNonnegativeDuration(spec.ControllerDuration.StatusUpdateInterval, durationPath.Child("statusUpdateInterval"))...)
+	if ttl := spec.ControllerDuration.MemberReadCacheTTL; ttl != nil {
+		allErrs = append(allErrs, validateNonnegativeDuration(*ttl, durationPath.Child("memberReadCacheTTL"))...)
+	}
+
+	electionPath := fldPath.Child("leaderElect")
+	election := spec.LeaderElect
+	allErrs = append(allErrs, validateEnumStrings(electionPath.Child("resourceLock"), string(election.ResourceLock), resourceLockTypes)...)
+	allErrs = append(allErrs, validatePositiveDuration(election.LeaseDuration, electionPath.Child("leaseDuration"))...)
+	allErrs = append(allErrs, validatePositiveDuration(election.RenewDeadline, electionPath.Child("renewDeadline"))...)
+	allErrs = append(allErrs, validatePositiveDuration(election.RetryPeriod, electionPath.Child("retryPeriod"))...)
+	if election.RenewDeadline.Duration >= election.LeaseDuration.Duration {
+		allErrs = append(allErrs, field.Invalid(electionPath.Child("renewDeadline"), election.RenewDeadline.Duration.String(), "must be less than leaseDuration"))
+	}
+
+	for i, gate := range spec.FeatureGates {
+		gatePath := fldPath.Child("featureGates").Index(i)
+		if len(gate.Name) == 0 {
+			allErrs = append(allErrs, field.Required(gatePath.Child("name"), ""))
+		}
+		allErrs = append(allErrs, validateEnumStrings(gatePath.Child("configuration"), string(gate.Configuration), configurationModes)...)
+	}
+
+	for i, controller := range spec.Controllers {
+		controllerPath := fldPath.Child("controllers").Index(i)
+		allErrs = append(allErrs, validateEnumStrings(controllerPath.Child("name"), string(controller.Name), controllerNames())...)
+		allErrs = append(allErrs, validateEnumStrings(controllerPath.Child("configuration"), string(controller.Configuration), configurationModes)...)
+	}
+
+	healthCheckPath := fldPath.Child("clusterHealthCheck")
+	healthCheck := spec.ClusterHealthCheck
+	allErrs = append(allErrs, validatePositiveField(healthCheck.PeriodSeconds, healthCheckPath.Child("periodSeconds"))...)
+	allErrs = append(allErrs, validatePositiveField(healthCheck.FailureThreshold, healthCheckPath.Child("failureThreshold"))...)
+	allErrs = append(allErrs, validatePositiveField(healthCheck.SuccessThreshold, healthCheckPath.Child("successThreshold"))...)
+	allErrs = append(allErrs, validatePositiveField(healthCheck.TimeoutSeconds, healthCheckPath.Child("timeoutSeconds"))...)
+
+	allErrs = append(allErrs, validateClusterLifecycleHooks(spec.ClusterLifecycleHooks, fldPath.Child("clusterLifecycleHooks"))...)
+
+	allErrs = append(allErrs, validateEnumStrings(fldPath.Child("syncController", "adoptResources"), string(spec.SyncController.AdoptResources), resourceAdoptionModes)...)
+
+	if spec.Scheduling != nil {
+		allErrs = append(allErrs, validateSchedulingConfig(spec.Scheduling, fldPath.Child("scheduling"))...)
+	}
+
+	if spec.Notifications != nil {
+		allErrs = append(allErrs, ValidateNotificationConfig(spec.Notifications, fldPath.Child("notifications"))...)
+	}
+
+	return allErrs
+}
+
+// ValidateNotificationConfig checks that each notification sink has a
+// unique name, an http(s) url and only supported formats and types.
+func ValidateNotificationConfig(config *v1beta1.NotificationConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	names := sets.NewString()
+	for i, sink := range config.Sinks {
+		sinkPath := fldPath.Child("sinks").Index(i)
+		namePath := sinkPath.Child("name")
+		switch {
+		case len(sink.Name) == 0:
+			allErrs = append(allErrs, field.Required(namePath, ""))
+		case names.Has(sink.Name):
+			allErrs = append(allErrs, field.Duplicate(namePath, sink.Name))
+		}
+		names.Insert(sink.Name)
+
+		urlPath := sinkPath.Child("url")
+		if len(sink.URL) == 0 {
+			allErrs = append(allErrs, field.Required(urlPath, ""))
+		} else if u, err := url.Parse(sink.URL); err != nil {
+			allErrs = append(allErrs, field.Invalid(urlPath, sink.URL, err.Error()))
+		} else if !sets.NewString(notificationURLSchemes...).Has(u.Scheme) || len(u.Host) == 0 {
+			allErrs = append(allErrs, field.Invalid(urlPath, sink.URL, "must be an http or https url"))
+		}
+
+		if len(sink.Format) != 0 {
+			allErrs = append(allErrs, validateEnumStrings(sinkPath.Child("format"), string(sink.Format), notificationFormats)...)
+		}
+		for j, notificationType := range sink.Types {
+			allErrs = append(allErrs, validateEnumStrings(sinkPath.Child("types").Index(j), string(notificationType), notificationTypes)...)
+		}
+	}
+	if config.PropagationFailureThreshold < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("propagationFailureThreshold"), config.PropagationFailureThreshold, "must not be negative"))
+	}
+	return allErrs
+}
+
+// validateSchedulingConfig checks that each scheduling profile has a
+// unique name and only supported filters and scorers, and that the
+// default profile is one of the profiles.
+func validateSchedulingConfig(config *v1beta1.SchedulingConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	names := sets.NewString()
+	for i, profile := range config.Profiles {
+		profilePath := fldPath.Child("profiles").Index(i)
+		namePath := profilePath.Child("name")
+		switch {
+		case len(profile.Name) == 0:
+			allErrs = append(allErrs, field.Required(namePath, ""))
+		case names.Has(profile.Name):
+			allErrs = append(allErrs, field.Duplicate(namePath, profile.Name))
+		}
+		names.Insert(profile.Name)
+
+		for j, filter := range profile.Filters {
+			allErrs = append(allErrs, validateEnumStrings(profilePath.Child("filters").Index(j), string(filter), schedulingFilters)...)
+			if filter == v1beta1.NoneSchedulingFilter && len(profile.Filters) > 1 {
+				allErrs = append(allErrs, field.Invalid(profilePath.Child("filters").Index(j), filter, "cannot be combined with other filters"))
+			}
+		}
+		for j, scorer := range profile.Scorers {
+			scorerPath := profilePath.Child("scorers").Index(j)
+			allErrs = append(allErrs, validateEnumStrings(scorerPath.Child("type"), string(scorer.Type), schedulingScorerTypes)...)
+			if scorer.Type == v1beta1.LabelSchedulingScorer && len(scorer.Label) == 0 {
+				allErrs = append(allErrs, field.Required(scorerPath.Child("label"), fmt.Sprintf("required for the %s scorer", scorer.Type)))
+			}
+			if scorer.Weight < 0 {
+				allErrs = append(allErrs, field.Invalid(scorerPath.Child("weight"), scorer.Weight, "must not be negative"))
+			}
+		}
+	}
+	if len(config.DefaultProfile) > 0 && !names.Has(config.DefaultProfile) {
+		allErrs = append(allErrs, field.NotFound(fldPath.Child("defaultProfile"), config.DefaultProfile))
+	}
+	return allErrs
+}
+
+// validateAllowedClusterScopedResources checks that the allowlist of
+// cluster-scoped resources is only set for a namespace-scoped control
+// plane and that each kind is listed once with the names of its
+// resources.
+func validateAllowedClusterScopedResources(spec *v1beta1.KubeFedConfigSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if len(spec.AllowedClusterScopedResources) == 0 {
+		return allErrs
+	}
+	if spec.Scope != apiextv1b1.NamespaceScoped {
+		allErrs = append(allErrs, field.Forbidden(fldPath, fmt.Sprintf("may only be set for a %s control plane", apiextv1b1.NamespaceScoped)))
+	}
+
+	kinds := sets.NewString()
+	for i, resource := range spec.AllowedClusterScopedResources {
+		resourcePath := fldPath.Index(i)
+		if len(resource.Group) != 0 {
+			if errs := valutil.IsDNS1123Subdomain(resource.Group); len(errs) > 0 {
+				allErrs = append(allErrs, field.Invalid(resourcePath.Child("group"), resource.Group, strings.Join(errs, ",")))
+			}
+		}
+
+		groupKind := schema.GroupKind{Group: resource.Group, Kind: resource.Kind}.String()
+		switch {
+		case len(resource.Kind) == 0:
+			allErrs = append(allErrs, field.Required(resourcePath.Child("kind"), ""))
+		case len(resource.Group) == 0 && resource.Kind == "Namespace":
+			allErrs = append(allErrs, field.Invalid(resourcePath.Child("kind"), resource.Kind, "namespaces are not propagated by a namespace-scoped control plane"))
+		case kinds.Has(groupKind):
+			allErrs = append(allErrs, field.Duplicate(resourcePath.Child("kind"), resource.Kind))
+		}
+		kinds.Insert(groupKind)
+
+		namesPath := resourcePath.Child("names")
+		if len(resource.Names) == 0 {
+			allErrs = append(allErrs, field.Required(namesPath, ""))
+		}
+		names := sets.NewString()
+		for j, name := range resource.Names {
+			if errs := path.IsValidPathSegmentName(name); len(name) == 0 || len(errs) > 0 {
+				allErrs = append(allErrs, field.Invalid(namesPath.Index(j), name, strings.Join(append(errs, "must be the name of a resource"), ",")))
+			} else if names.Has(name) {
+				allErrs = append(allErrs, field.Duplicate(namesPath.Index(j), name))
+			}
+			names.Insert(name)
+		}
+	}
+	return allErrs
+}
+
+// validateClusterLifecycleHooks checks that each lifecycle hook has a
+// unique name that can be part of the name of its Jobs, runs for
+// supported events and is either a webhook or a job.
+func validateClusterLifecycleHooks(hooks []v1beta1.ClusterLifecycleHook, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	names := sets.NewString()
+	for i, hook := range hooks {
+		hookPath := fldPath.Index(i)
+		namePath := hookPath.Child("name")
+		switch {
+		case len(hook.Name) == 0:
+			allErrs = append(allErrs, field.Required(namePath, ""))
+		case len(hook.Name) > ClusterLifecycleHookNameMaxLength:
+			allErrs = append(allErrs, field.TooLong(namePath, hook.Name, ClusterLifecycleHookNameMaxLength))
+		case names.Has(hook.Name):
+			allErrs = append(allErrs, field.Duplicate(namePath, hook.Name))
+		default:
+			for _, msg := range valutil.IsDNS1123Label(hook.Name) {
+				allErrs = append(allErrs, field.Invalid(namePath, hook.Name, msg))
+			}
+		}
+		names.Insert(hook.Name)
+
+		eventsPath := hookPath.Child("events")
+		if len(hook.Events) == 0 {
+			allErrs = append(allErrs, field.Required(eventsPath, ""))
+		}
+		for j, event := range hook.Events {
+			allErrs = append(allErrs, validateEnumStrings(eventsPath.Index(j), string(event), clusterLifecycleEvents)...)
+		}
+
+		if (hook.Webhook == nil) == (hook.Job == nil) {
+			allErrs = append(allErrs, field.Invalid(hookPath, hook.Name, "exactly one of webhook and job must be set"))
+		}
+		if webhook := hook.Webhook; webhook != nil {
+			webhookPath := hookPath.Child("webhook")
+			if len(webhook.URL) == 0 {
+				allErrs = append(allErrs, field.Required(webhookPath.Child("url"), ""))
+			}
+			if webhook.TimeoutSeconds < 0 {
+				allErrs = append(allErrs, field.Invalid(webhookPath.Child("timeoutSeconds"), webhook.TimeoutSeconds, "must not be negative"))
+			}
+		}
+	}
+	return allErrs
+}
+
+func validateNonnegativeDuration(duration metav1.Duration, fldPath *field.Path) field.ErrorList {
+	if duration.Duration < 0 {
+		return field.ErrorList{field.Invalid(fldPath, duration.Duration.String(), "must not be negative")}
+	}
+	return field.ErrorList{}
+}
+
+func validatePositiveDuration(duration metav1.Duration, fldPath *field.Path) field.ErrorList {
+	if duration.Duration <= 0 {
+		return field.ErrorList{field.Invalid(fldPath, duration.Duration.String(), "must be greater than zero")}
+	}
+	return field.ErrorList{}
+}
+
+func validatePositiveField(value int64, fldPath *field.Path) field.ErrorList {
+	if value <= 0 {
+		return field.ErrorList{field.Invalid(fldPath, value, "must be greater than zero")}
+	}
+	return field.ErrorList{}
+}
+
+// KubeFedClusterWarnings returns the problems with the given cluster
+// that are not severe enough to reject it.
+func KubeFedClusterWarnings(object *v1beta1.KubeFedCluster) []string {
+	var warnings []string
+	denied := sets.NewString(object.Spec.DeniedNamespaces...)
+	for i, name := range object.Spec.AllowedNamespaces {
+		if denied.Has(name) {
+			warnings = append(warnings, fmt.Sprintf("spec.allowedNamespaces[%d]: namespace %q is also denied and resources will not be propagated to it", i, name))
+		}
+	}
+	allowed := sets.NewString(object.Spec.AllowedNamespaces...)
+	for i, mapping := range object.Spec.NamespaceMappings {
+		if denied.Has(mapping.ClusterNamespace) || (allowed.Len() > 0 && !allowed.Has(mapping.ClusterNamespace)) {
+			warnings = append(warnings, fmt.Sprintf("spec.namespaceMappings[%d].clusterNamespace: namespace %q is not allowed and resources mapped to it will not be propagated", i, mapping.ClusterNamespace))
+		}
+	}
+	if object.Spec.Local {
+		if len(object.Spec.SecondaryAPIEndpoints) > 0 {
+			warnings = append(warnings, "spec.secondaryAPIEndpoints: secondary endpoints are not used to access a local cluster")
+		}
+		if len(object.Spec.SecretRef.Name) > 0 {
+			warnings = append(warnings, "spec.secretRef: the secret is not used to access a local cluster")
+		}
+	}
+	return warnings
+}
+
+// validateSecondaryAPIEndpoints ensures that secondary endpoints are
+// non-empty and distinct from each other and the primary endpoint.
+func validateSecondaryAPIEndpoints(apiEndpoint string, secondaryAPIEndpoints []string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	endpoints := sets.NewString(apiEndpoint)
+	for i, endpoint := range secondaryAPIEndpoints {
+		idxPath := fldPath.Index(i)
+		if len(endpoint) == 0 {
+			allErrs = append(allErrs, field.Required(idxPath, ""))
+			continue
+		}
+		if endpoints.Has(endpoint) {
+			allErrs = append(allErrs, field.Duplicate(idxPath, endpoint))
+		}
+		endpoints.Insert(endpoint)
+	}
+	return allErrs
+}
+
+// ValidateNamespaceMappings ensures that each mapping names valid
+// namespaces and that no two host cluster namespaces are propagated
+// to the same member cluster namespace.
+func ValidateNamespaceMappings(mappings []v1beta1.NamespaceMapping, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	namespaces := sets.NewString()
+	clusterNamespaces := sets.NewString()
+	for i, mapping := range mappings {
+		idxPath := fldPath.Index(i)
+		allErrs = append(allErrs, validateNamespaceName(mapping.Namespace, idxPath.Child("namespace"))...)
+		allErrs = append(allErrs, validateNamespaceName(mapping.ClusterNamespace, idxPath.Child("clusterNamespace"))...)
+		if namespaces.Has(mapping.Namespace) {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("namespace"), mapping.Namespace))
+		}
+		if clusterNamespaces.Has(mapping.ClusterNamespace) {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("clusterNamespace"), mapping.ClusterNamespace))
+		}
+		namespaces.Insert(mapping.Namespace)
+		clusterNamespaces.Insert(mapping.ClusterNamespace)
+	}
+	return allErrs
+}
+
+func validateNamespaceNames(names []string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for i, name := range names {
+		allErrs = append(allErrs, validateNamespaceName(name, fldPath.Index(i))...)
+	}
+	return allErrs
+}
+
+func validateNamespaceName(name string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if len(name) == 0 {
+		return append(allErrs, field.Required(fldPath, ""))
+	}
+	for _, msg := range apimachineryval.ValidateNamespaceName(name, false) {
+		allErrs = append(allErrs, field.Invalid(fldPath, name, msg))
+	}
 	return allErrs
 }
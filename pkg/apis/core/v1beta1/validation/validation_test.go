@@ -20,7 +20,10 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
+	apiv1 "k8s.io/api/core/v1"
+	apiextv1b1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
@@ -130,11 +133,59 @@ func TestValidateFederatedTypeConfigSpec(t *testing.T) {
 	validPropagation.Spec.Propagation = "InvalidPropagationMode"
 	errorCases["spec.propagation: Unsupported value"] = validPropagation
 
+	duplicateTargetVersion := validFederatedTypeConfig()
+	duplicateTargetVersion.Spec.TargetVersions = []string{duplicateTargetVersion.Spec.TargetType.Version}
+	errorCases["spec.targetVersions[0]: Duplicate value"] = duplicateTargetVersion
+
+	invalidTargetVersion := validFederatedTypeConfig()
+	invalidTargetVersion.Spec.TargetVersions = []string{"V1"}
+	errorCases["spec.targetVersions[0]: Invalid value"] = invalidTargetVersion
+
 	validStatusCollection := validFederatedTypeConfig()
 	var invalidStatusCollectionMode v1beta1.StatusCollectionMode = "InvalidStatusCollectionMode"
 	validStatusCollection.Spec.StatusCollection = &invalidStatusCollectionMode
 	errorCases["spec.statusCollection: Unsupported value"] = validStatusCollection
 
+	defaultOverridePath := validFederatedTypeConfig()
+	defaultOverridePath.Spec.DefaultOverrides = []v1beta1.DefaultOverride{{Path: "metadata.name", Operation: v1beta1.DefaultOverrideRemove}}
+	errorCases["spec.defaultOverrides[0].path: Forbidden"] = defaultOverridePath
+
+	defaultOverrideValue := validFederatedTypeConfig()
+	defaultOverrideValue.Spec.DefaultOverrides = []v1beta1.DefaultOverride{{Path: "spec.template.spec.nodeName"}}
+	errorCases["spec.defaultOverrides[0].value: Required value"] = defaultOverrideValue
+
+	defaultOverrideOperation := validFederatedTypeConfig()
+	defaultOverrideOperation.Spec.DefaultOverrides = []v1beta1.DefaultOverride{{Path: "spec.template.spec.nodeName", Operation: "Append"}}
+	errorCases["spec.defaultOverrides[0].operation: Unsupported value"] = defaultOverrideOperation
+
+	valuesPath := validFederatedTypeConfig()
+	valuesPath.Spec.Values = &v1beta1.ValuesConfig{}
+	errorCases["spec.values.path: Required value"] = valuesPath
+
+	valuesFormat := validFederatedTypeConfig()
+	valuesFormat.Spec.Values = &v1beta1.ValuesConfig{Path: "spec.values", Format: "JSON"}
+	errorCases["spec.values.format: Unsupported value"] = valuesFormat
+
+	valuesSchema := validFederatedTypeConfig()
+	valuesSchema.Spec.Values = &v1beta1.ValuesConfig{Path: "spec.values", Schema: `{"$ref": "#/definitions/values"}`}
+	errorCases["spec.values.schema: Invalid value"] = valuesSchema
+
+	locallyManagedField := validFederatedTypeConfig()
+	locallyManagedField.Spec.LocallyManagedFields = []string{".spec.replicas", ".metadata.namespace"}
+	errorCases["spec.locallyManagedFields[1]: Forbidden"] = locallyManagedField
+
+	ignoredAnnotation := validFederatedTypeConfig()
+	ignoredAnnotation.Spec.MemberEventFilter = &v1beta1.MemberEventFilter{IgnoredAnnotations: []string{"example.com/heartbeat", ""}}
+	errorCases["spec.memberEventFilter.ignoredAnnotations[1]: Required value"] = ignoredAnnotation
+
+	statusTypeScope := validFederatedTypeConfig()
+	statusTypeScope.Spec.StatusType.Scope = apiextv1b1.ClusterScoped
+	errorCases["spec.statusType.scope: Invalid value"] = statusTypeScope
+
+	negativeInterval := validFederatedTypeConfig()
+	negativeInterval.Spec.StatusUpdateInterval = &metav1.Duration{Duration: -time.Second}
+	errorCases["spec.statusUpdateInterval: Invalid value"] = negativeInterval
+
 	for k, v := range errorCases {
 		errs := ValidateFederatedTypeConfigSpec(&v.Spec, field.NewPath("spec"))
 		if len(errs) == 0 {
@@ -215,10 +266,737 @@ func TestValidateFederatedTypeConfigStatus(t *testing.T) {
 
 }
 
+func TestValidateNamespaceMappings(t *testing.T) {
+	testCases := []struct {
+		name           string
+		mappings       []v1beta1.NamespaceMapping
+		expectedErrMsg string
+	}{
+		{
+			name: "valid mappings",
+			mappings: []v1beta1.NamespaceMapping{
+				{Namespace: "team-a", ClusterNamespace: "team-a-prod"},
+				{Namespace: "team-b", ClusterNamespace: "team-b-prod"},
+			},
+		},
+		{
+			name: "ClusterNamespace required",
+			mappings: []v1beta1.NamespaceMapping{
+				{Namespace: "team-a"},
+			},
+			expectedErrMsg: "spec.namespaceMappings[0].clusterNamespace: Required value",
+		},
+		{
+			name: "invalid namespace name",
+			mappings: []v1beta1.NamespaceMapping{
+				{Namespace: "Team_A", ClusterNamespace: "team-a-prod"},
+			},
+			expectedErrMsg: "spec.namespaceMappings[0].namespace: Invalid value",
+		},
+		{
+			name: "duplicate ClusterNamespace",
+			mappings: []v1beta1.NamespaceMapping{
+				{Namespace: "team-a", ClusterNamespace: "prod"},
+				{Namespace: "team-b", ClusterNamespace: "prod"},
+			},
+			expectedErrMsg: "spec.namespaceMappings[1].clusterNamespace: Duplicate value",
+		},
+	}
+
+	for _, test := range testCases {
+		errs := ValidateNamespaceMappings(test.mappings, field.NewPath("spec", "namespaceMappings"))
+		if len(test.expectedErrMsg) == 0 {
+			if len(errs) > 0 {
+				t.Errorf("[%s] unexpected error: %v", test.name, errs)
+			}
+			continue
+		}
+		if len(errs) == 0 {
+			t.Errorf("[%s] expected failure", test.name)
+		} else if !strings.Contains(errs[0].Error(), test.expectedErrMsg) {
+			t.Errorf("[%s] unexpected error: %q, expected: %q", test.name, errs[0].Error(), test.expectedErrMsg)
+		}
+	}
+}
+
+func TestValidateSecondaryAPIEndpoints(t *testing.T) {
+	testCases := []struct {
+		name           string
+		endpoints      []string
+		expectedErrMsg string
+	}{
+		{
+			name:      "valid endpoints",
+			endpoints: []string{"https://10.0.0.1:6443", "https://10.0.0.2:6443"},
+		},
+		{
+			name:           "endpoint required",
+			endpoints:      []string{""},
+			expectedErrMsg: "spec.secondaryAPIEndpoints[0]: Required value",
+		},
+		{
+			name:           "duplicate of primary endpoint",
+			endpoints:      []string{"https://10.0.0.2:6443", "https://cluster1.example.com"},
+			expectedErrMsg: "spec.secondaryAPIEndpoints[1]: Duplicate value",
+		},
+	}
+
+	for _, test := range testCases {
+		errs := validateSecondaryAPIEndpoints("https://cluster1.example.com", test.endpoints, field.NewPath("spec", "secondaryAPIEndpoints"))
+		if len(test.expectedErrMsg) == 0 {
+			if len(errs) > 0 {
+				t.Errorf("[%s] unexpected error: %v", test.name, errs)
+			}
+			continue
+		}
+		if len(errs) == 0 {
+			t.Errorf("[%s] expected failure", test.name)
+		} else if !strings.Contains(errs[0].Error(), test.expectedErrMsg) {
+			t.Errorf("[%s] unexpected error: %q, expected: %q", test.name, errs[0].Error(), test.expectedErrMsg)
+		}
+	}
+}
+
+func TestValidateKubeFedClusterNamespaces(t *testing.T) {
+	cluster := &v1beta1.KubeFedCluster{
+		Spec: v1beta1.KubeFedClusterSpec{
+			APIEndpoint:       "https://cluster1.example.com",
+			AllowedNamespaces: []string{"team-a"},
+			DeniedNamespaces:  []string{"Kube_System"},
+		},
+	}
+	errs := ValidateKubeFedCluster(cluster)
+	expectedErrMsg := "spec.deniedNamespaces[0]: Invalid value"
+	if len(errs) != 1 {
+		t.Fatalf("expected a single error, got: %v", errs)
+	}
+	if !strings.Contains(errs[0].Error(), expectedErrMsg) {
+		t.Errorf("unexpected error: %q, expected: %q", errs[0].Error(), expectedErrMsg)
+	}
+}
+
+func TestValidateKubeFedClusterSecretNamespace(t *testing.T) {
+	cluster := &v1beta1.KubeFedCluster{
+		Spec: v1beta1.KubeFedClusterSpec{
+			APIEndpoint: "https://cluster1.example.com",
+			SecretRef:   v1beta1.LocalSecretReference{Name: "cluster1", Namespace: "member-credentials"},
+		},
+	}
+	if errs := ValidateKubeFedCluster(cluster); len(errs) != 0 {
+		t.Fatalf("expected no errors, got: %v", errs)
+	}
+
+	cluster.Spec.SecretRef.Namespace = "Member_Credentials"
+	errs := ValidateKubeFedCluster(cluster)
+	expectedErrMsg := "spec.secretRef.namespace: Invalid value"
+	if len(errs) != 1 {
+		t.Fatalf("expected a single error, got: %v", errs)
+	}
+	if !strings.Contains(errs[0].Error(), expectedErrMsg) {
+		t.Errorf("unexpected error: %q, expected: %q", errs[0].Error(), expectedErrMsg)
+	}
+}
+
+func TestValidateKubeFedClusterProfileRef(t *testing.T) {
+	cluster := &v1beta1.KubeFedCluster{
+		Spec: v1beta1.KubeFedClusterSpec{
+			APIEndpoint: "https://cluster1.example.com",
+			ProfileRef:  &apiv1.LocalObjectReference{Name: "cluster1-profile"},
+		},
+	}
+	if errs := ValidateKubeFedCluster(cluster); len(errs) != 0 {
+		t.Fatalf("expected no errors, got: %v", errs)
+	}
+
+	cluster.Spec.ProfileRef.Name = ""
+	errs := ValidateKubeFedCluster(cluster)
+	expectedErrMsg := "spec.profileRef.name: Required value"
+	if len(errs) != 1 {
+		t.Fatalf("expected a single error, got: %v", errs)
+	}
+	if !strings.Contains(errs[0].Error(), expectedErrMsg) {
+		t.Errorf("unexpected error: %q, expected: %q", errs[0].Error(), expectedErrMsg)
+	}
+}
+
+func TestValidateKubeFedClusterHealthProbes(t *testing.T) {
+	cluster := &v1beta1.KubeFedCluster{
+		Spec: v1beta1.KubeFedClusterSpec{
+			APIEndpoint: "https://cluster1.example.com",
+			HealthProbes: []v1beta1.ClusterHealthProbe{
+				{Name: "frontend", Service: &v1beta1.ClusterServiceProbe{Namespace: "canary", Name: "frontend", Port: "http", Path: "/ready"}},
+				{Name: "canary", Deployment: &v1beta1.ClusterDeploymentProbe{Namespace: "canary", Name: "canary"}},
+			},
+		},
+	}
+	if errs := ValidateKubeFedCluster(cluster); len(errs) != 0 {
+		t.Fatalf("expected no errors, got: %v", errs)
+	}
+
+	testCases := map[string]struct {
+		mutate        func(probes []v1beta1.ClusterHealthProbe)
+		expectedField string
+	}{
+		"duplicate name": {
+			mutate:        func(probes []v1beta1.ClusterHealthProbe) { probes[1].Name = "frontend" },
+			expectedField: "spec.healthProbes[1].name",
+		},
+		"both service and deployment": {
+			mutate:        func(probes []v1beta1.ClusterHealthProbe) { probes[1].Service = probes[0].Service },
+			expectedField: "spec.healthProbes[1]",
+		},
+		"service without namespace": {
+			mutate:        func(probes []v1beta1.ClusterHealthProbe) { probes[0].Service.Namespace = "" },
+			expectedField: "spec.healthProbes[0].service.namespace",
+		},
+		"unsupported scheme": {
+			mutate:        func(probes []v1beta1.ClusterHealthProbe) { probes[0].Service.Scheme = "ftp" },
+			expectedField: "spec.healthProbes[0].service.scheme",
+		},
+		"invalid deployment name": {
+			mutate:        func(probes []v1beta1.ClusterHealthProbe) { probes[1].Deployment.Name = "Canary" },
+			expectedField: "spec.healthProbes[1].deployment.name",
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			invalid := cluster.DeepCopy()
+			tc.mutate(invalid.Spec.HealthProbes)
+			errs := ValidateKubeFedCluster(invalid)
+			if len(errs) != 1 || errs[0].Field != tc.expectedField {
+				t.Fatalf("expected a single error for %s, got: %v", tc.expectedField, errs)
+			}
+		})
+	}
+}
+
+func TestValidateTargetTypeServed(t *testing.T) {
+	targetType := validFederatedTypeConfig().Spec.TargetType
+	served := &metav1.APIResourceList{
+		GroupVersion: fmt.Sprintf("%s/%s", targetType.Group, targetType.Version),
+		APIResources: []metav1.APIResource{
+			{Name: targetType.PluralName + "/status", Kind: targetType.Kind, Namespaced: true},
+			{Name: targetType.PluralName, Kind: targetType.Kind, Namespaced: true},
+		},
+	}
+	fldPath := field.NewPath("spec", "targetType")
+
+	if errs := ValidateTargetTypeServed(&targetType, served, fldPath); len(errs) != 0 {
+		t.Errorf("expected success for a served type, got: %v", errs)
+	}
+
+	testCases := map[string]struct {
+		mutate    func(*v1beta1.APIResource)
+		resources *metav1.APIResourceList
+		field     string
+	}{
+		"group version not served": {
+			field: "spec.targetType.version",
+		},
+		"kind not served": {
+			mutate:    func(r *v1beta1.APIResource) { r.Kind = "Widget" },
+			resources: served,
+			field:     "spec.targetType.kind",
+		},
+		"plural name of a subresource": {
+			mutate:    func(r *v1beta1.APIResource) { r.PluralName += "/status" },
+			resources: served,
+			field:     "spec.targetType.pluralName",
+		},
+		"mismatched plural name": {
+			mutate:    func(r *v1beta1.APIResource) { r.PluralName = "deploys" },
+			resources: served,
+			field:     "spec.targetType.pluralName",
+		},
+		"mismatched scope": {
+			mutate:    func(r *v1beta1.APIResource) { r.Scope = apiextv1b1.ClusterScoped },
+			resources: served,
+			field:     "spec.targetType.scope",
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			target := targetType
+			if tc.mutate != nil {
+				tc.mutate(&target)
+			}
+			errs := ValidateTargetTypeServed(&target, tc.resources, fldPath)
+			if len(errs) != 1 || errs[0].Field != tc.field {
+				t.Errorf("expected a single error for %s, got: %v", tc.field, errs)
+			}
+		})
+	}
+}
+
+func TestFederatedTypeConfigWarnings(t *testing.T) {
+	if warnings := FederatedTypeConfigWarnings(validFederatedTypeConfig(), false); len(warnings) != 0 {
+		t.Errorf("expected no warnings, got: %v", warnings)
+	}
+
+	ftc := validFederatedTypeConfig()
+	ftc.Spec.TargetType.Group = "extensions"
+	ftc.Spec.TargetType.Version = "v1beta1"
+	warnings := FederatedTypeConfigWarnings(ftc, false)
+	expectedWarning := "spec.targetType: extensions/v1beta1 Deployment is deprecated, use apps/v1 instead"
+	if len(warnings) != 1 || warnings[0] != expectedWarning {
+		t.Errorf("unexpected warnings: %v, expected: %q", warnings, expectedWarning)
+	}
+
+	if warnings := FederatedTypeConfigWarnings(ftc, true); len(warnings) != 0 {
+		t.Errorf("expected no warnings for the status subresource, got: %v", warnings)
+	}
+}
+
+func TestKubeFedClusterWarnings(t *testing.T) {
+	testCases := []struct {
+		name             string
+		spec             v1beta1.KubeFedClusterSpec
+		expectedWarnings []string
+	}{
+		{
+			name: "no restrictions",
+			spec: v1beta1.KubeFedClusterSpec{
+				NamespaceMappings: []v1beta1.NamespaceMapping{{Namespace: "team-a", ClusterNamespace: "team-a-east"}},
+			},
+		},
+		{
+			name: "namespace both allowed and denied",
+			spec: v1beta1.KubeFedClusterSpec{
+				AllowedNamespaces: []string{"team-a", "team-b"},
+				DeniedNamespaces:  []string{"team-b"},
+			},
+			expectedWarnings: []string{
+				`spec.allowedNamespaces[1]: namespace "team-b" is also denied and resources will not be propagated to it`,
+			},
+		},
+		{
+			name: "mappings to namespaces that are not allowed",
+			spec: v1beta1.KubeFedClusterSpec{
+				AllowedNamespaces: []string{"team-a-east"},
+				DeniedNamespaces:  []string{"kube-system"},
+				NamespaceMappings: []v1beta1.NamespaceMapping{
+					{Namespace: "team-a", ClusterNamespace: "team-a-east"},
+					{Namespace: "team-b", ClusterNamespace: "team-b-east"},
+					{Namespace: "system", ClusterNamespace: "kube-system"},
+				},
+			},
+			expectedWarnings: []string{
+				`spec.namespaceMappings[1].clusterNamespace: namespace "team-b-east" is not allowed and resources mapped to it will not be propagated`,
+				`spec.namespaceMappings[2].clusterNamespace: namespace "kube-system" is not allowed and resources mapped to it will not be propagated`,
+			},
+		},
+		{
+			name: "local cluster with a secret and secondary endpoints",
+			spec: v1beta1.KubeFedClusterSpec{
+				Local:                 true,
+				SecondaryAPIEndpoints: []string{"https://10.0.0.1"},
+				SecretRef:             v1beta1.LocalSecretReference{Name: "host-secret"},
+			},
+			expectedWarnings: []string{
+				"spec.secondaryAPIEndpoints: secondary endpoints are not used to access a local cluster",
+				"spec.secretRef: the secret is not used to access a local cluster",
+			},
+		},
+		{
+			name: "local cluster",
+			spec: v1beta1.KubeFedClusterSpec{
+				Local: true,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		warnings := KubeFedClusterWarnings(&v1beta1.KubeFedCluster{Spec: test.spec})
+		if strings.Join(warnings, "\n") != strings.Join(test.expectedWarnings, "\n") {
+			t.Errorf("[%s] unexpected warnings: %q, expected: %q", test.name, warnings, test.expectedWarnings)
+		}
+	}
+}
+
+func TestValidateKubeFedConfigSpec(t *testing.T) {
+	validSpec := func() *v1beta1.KubeFedConfigSpec {
+		return &v1beta1.KubeFedConfigSpec{
+			LeaderElect: v1beta1.LeaderElectConfig{
+				ResourceLock:  v1beta1.ConfigMapsResourceLock,
+				LeaseDuration: metav1.Duration{Duration: 15 * time.Second},
+				RenewDeadline: metav1.Duration{Duration: 10 * time.Second},
+				RetryPeriod:   metav1.Duration{Duration: 5 * time.Second},
+			},
+			FeatureGates: []v1beta1.FeatureGatesConfig{
+				{Name: "PushReconciler", Configuration: v1beta1.ConfigurationEnabled},
+			},
+			ClusterHealthCheck: v1beta1.ClusterHealthCheckConfig{
+				PeriodSeconds:    10,
+				FailureThreshold: 3,
+				SuccessThreshold: 1,
+				TimeoutSeconds:   3,
+			},
+			SyncController: v1beta1.SyncControllerConfig{
+				AdoptResources: v1beta1.AdoptResourcesEnabled,
+			},
+		}
+	}
+
+	testCases := []struct {
+		name           string
+		mutate         func(spec *v1beta1.KubeFedConfigSpec)
+		expectedErrMsg string
+	}{
+		{
+			name:   "valid spec",
+			mutate: func(spec *v1beta1.KubeFedConfigSpec) {},
+		},
+		{
+			name:           "unsupported scope",
+			mutate:         func(spec *v1beta1.KubeFedConfigSpec) { spec.Scope = "Global" },
+			expectedErrMsg: "spec.scope: Unsupported value",
+		},
+		{
+			name: "valid cluster-scoped allowlist",
+			mutate: func(spec *v1beta1.KubeFedConfigSpec) {
+				spec.Scope = apiextv1b1.NamespaceScoped
+				spec.AllowedClusterScopedResources = []v1beta1.AllowedClusterScopedResource{
+					{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole", Names: []string{"system:team-a", "team-b"}},
+				}
+			},
+		},
+		{
+			name: "cluster-scoped allowlist of a cluster-scoped control plane",
+			mutate: func(spec *v1beta1.KubeFedConfigSpec) {
+				spec.AllowedClusterScopedResources = []v1beta1.AllowedClusterScopedResource{
+					{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole", Names: []string{"team-a"}},
+				}
+			},
+			expectedErrMsg: "spec.allowedClusterScopedResources: Forbidden",
+		},
+		{
+			name: "cluster-scoped allowlist without names",
+			mutate: func(spec *v1beta1.KubeFedConfigSpec) {
+				spec.Scope = apiextv1b1.NamespaceScoped
+				spec.AllowedClusterScopedResources = []v1beta1.AllowedClusterScopedResource{
+					{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"},
+				}
+			},
+			expectedErrMsg: "spec.allowedClusterScopedResources[0].names: Required value",
+		},
+		{
+			name: "cluster-scoped allowlist with an invalid name",
+			mutate: func(spec *v1beta1.KubeFedConfigSpec) {
+				spec.Scope = apiextv1b1.NamespaceScoped
+				spec.AllowedClusterScopedResources = []v1beta1.AllowedClusterScopedResource{
+					{Kind: "PersistentVolume", Names: []string{"pv1", "pv/2"}},
+				}
+			},
+			expectedErrMsg: "spec.allowedClusterScopedResources[0].names[1]: Invalid value",
+		},
+		{
+			name: "cluster-scoped allowlist with a duplicate kind",
+			mutate: func(spec *v1beta1.KubeFedConfigSpec) {
+				spec.Scope = apiextv1b1.NamespaceScoped
+				spec.AllowedClusterScopedResources = []v1beta1.AllowedClusterScopedResource{
+					{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole", Names: []string{"team-a"}},
+					{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole", Names: []string{"team-b"}},
+				}
+			},
+			expectedErrMsg: "spec.allowedClusterScopedResources[1].kind: Duplicate value",
+		},
+		{
+			name: "cluster-scoped allowlist of namespaces",
+			mutate: func(spec *v1beta1.KubeFedConfigSpec) {
+				spec.Scope = apiextv1b1.NamespaceScoped
+				spec.AllowedClusterScopedResources = []v1beta1.AllowedClusterScopedResource{
+					{Kind: "Namespace", Names: []string{"team-a"}},
+				}
+			},
+			expectedErrMsg: "spec.allowedClusterScopedResources[0].kind: Invalid value",
+		},
+		{
+			name: "negative duration",
+			mutate: func(spec *v1beta1.KubeFedConfigSpec) {
+				spec.ControllerDuration.StatusUpdateInterval = metav1.Duration{Duration: -time.Second}
+			},
+			expectedErrMsg: "spec.controllerDuration.statusUpdateInterval: Invalid value",
+		},
+		{
+			name: "negative member read cache ttl",
+			mutate: func(spec *v1beta1.KubeFedConfigSpec) {
+				spec.ControllerDuration.MemberReadCacheTTL = &metav1.Duration{Duration: -time.Second}
+			},
+			expectedErrMsg: "spec.controllerDuration.memberReadCacheTTL: Invalid value",
+		},
+		{
+			name:           "unsupported resource lock",
+			mutate:         func(spec *v1beta1.KubeFedConfigSpec) { spec.LeaderElect.ResourceLock = "leases" },
+			expectedErrMsg: "spec.leaderElect.resourceLock: Unsupported value",
+		},
+		{
+			name: "renew deadline exceeds lease duration",
+			mutate: func(spec *v1beta1.KubeFedConfigSpec) {
+				spec.LeaderElect.RenewDeadline = metav1.Duration{Duration: 20 * time.Second}
+			},
+			expectedErrMsg: "spec.leaderElect.renewDeadline: Invalid value",
+		},
+		{
+			name:           "feature gate configuration required",
+			mutate:         func(spec *v1beta1.KubeFedConfigSpec) { spec.FeatureGates[0].Configuration = "" },
+			expectedErrMsg: "spec.featureGates[0].configuration: Required value",
+		},
+		{
+			name: "valid cluster lifecycle hooks",
+			mutate: func(spec *v1beta1.KubeFedConfigSpec) {
+				spec.ClusterLifecycleHooks = []v1beta1.ClusterLifecycleHook{
+					{
+						Name:    "cmdb",
+						Events:  []v1beta1.ClusterLifecycleEvent{v1beta1.ClusterJoinedEvent, v1beta1.ClusterUnjoinedEvent},
+						Webhook: &v1beta1.ClusterLifecycleWebhook{URL: "https://cmdb.example.com/clusters"},
+					},
+					{
+						Name:   "namespaces",
+						Events: []v1beta1.ClusterLifecycleEvent{v1beta1.ClusterApprovedEvent},
+						Job:    &v1beta1.ClusterLifecycleJob{},
+					},
+				}
+			},
+		},
+		{
+			name: "cluster lifecycle hook with an unsupported event",
+			mutate: func(spec *v1beta1.KubeFedConfigSpec) {
+				spec.ClusterLifecycleHooks = []v1beta1.ClusterLifecycleHook{
+					{Name: "cmdb", Events: []v1beta1.ClusterLifecycleEvent{"Deleted"}, Job: &v1beta1.ClusterLifecycleJob{}},
+				}
+			},
+			expectedErrMsg: "spec.clusterLifecycleHooks[0].events[0]: Unsupported value",
+		},
+		{
+			name: "cluster lifecycle hook with a duplicate name",
+			mutate: func(spec *v1beta1.KubeFedConfigSpec) {
+				spec.ClusterLifecycleHooks = []v1beta1.ClusterLifecycleHook{
+					{Name: "cmdb", Events: []v1beta1.ClusterLifecycleEvent{v1beta1.ClusterJoinedEvent}, Job: &v1beta1.ClusterLifecycleJob{}},
+					{Name: "cmdb", Events: []v1beta1.ClusterLifecycleEvent{v1beta1.ClusterUnjoinedEvent}, Job: &v1beta1.ClusterLifecycleJob{}},
+				}
+			},
+			expectedErrMsg: "spec.clusterLifecycleHooks[1].name: Duplicate value",
+		},
+		{
+			name: "cluster lifecycle hook with a webhook and a job",
+			mutate: func(spec *v1beta1.KubeFedConfigSpec) {
+				spec.ClusterLifecycleHooks = []v1beta1.ClusterLifecycleHook{{
+					Name:    "cmdb",
+					Events:  []v1beta1.ClusterLifecycleEvent{v1beta1.ClusterJoinedEvent},
+					Webhook: &v1beta1.ClusterLifecycleWebhook{URL: "https://cmdb.example.com/clusters"},
+					Job:     &v1beta1.ClusterLifecycleJob{},
+				}}
+			},
+			expectedErrMsg: "spec.clusterLifecycleHooks[0]: Invalid value",
+		},
+		{
+			name: "unsupported controller name",
+			mutate: func(spec *v1beta1.KubeFedConfigSpec) {
+				spec.Controllers = []v1beta1.ControllerSwitchConfig{{Name: "SyncController", Configuration: v1beta1.ConfigurationDisabled}}
+			},
+			expectedErrMsg: "spec.controllers[0].name: Unsupported value",
+		},
+		{
+			name: "controller configuration required",
+			mutate: func(spec *v1beta1.KubeFedConfigSpec) {
+				spec.Controllers = []v1beta1.ControllerSwitchConfig{{Name: v1beta1.StatusControllerName}}
+			},
+			expectedErrMsg: "spec.controllers[0].configuration: Required value",
+		},
+		{
+			name:           "health check period required",
+			mutate:         func(spec *v1beta1.KubeFedConfigSpec) { spec.ClusterHealthCheck.PeriodSeconds = 0 },
+			expectedErrMsg: "spec.clusterHealthCheck.periodSeconds: Invalid value",
+		},
+		{
+			name: "valid scheduling profiles",
+			mutate: func(spec *v1beta1.KubeFedConfigSpec) {
+				spec.Scheduling = &v1beta1.SchedulingConfig{
+					Profiles: []v1beta1.SchedulingProfile{
+						{
+							Name:    "latency-first",
+							Scorers: []v1beta1.SchedulingScorer{{Type: v1beta1.APILatencySchedulingScorer}},
+						},
+						{
+							Name:    "batch",
+							Filters: []v1beta1.SchedulingFilter{v1beta1.DegradedSchedulingFilter, v1beta1.PressureSchedulingFilter},
+							Scorers: []v1beta1.SchedulingScorer{{Type: v1beta1.LabelSchedulingScorer, Label: "capacity", Weight: 2}},
+						},
+						{
+							Name:    "unfiltered",
+							Filters: []v1beta1.SchedulingFilter{v1beta1.NoneSchedulingFilter},
+						},
+					},
+					DefaultProfile: "batch",
+				}
+			},
+		},
+		{
+			name: "scheduling profile with a duplicate name",
+			mutate: func(spec *v1beta1.KubeFedConfigSpec) {
+				spec.Scheduling = &v1beta1.SchedulingConfig{
+					Profiles: []v1beta1.SchedulingProfile{{Name: "batch"}, {Name: "batch"}},
+				}
+			},
+			expectedErrMsg: "spec.scheduling.profiles[1].name: Duplicate value",
+		},
+		{
+			name: "scheduling profile with an unknown filter",
+			mutate: func(spec *v1beta1.KubeFedConfigSpec) {
+				spec.Scheduling = &v1beta1.SchedulingConfig{
+					Profiles: []v1beta1.SchedulingProfile{{Name: "batch", Filters: []v1beta1.SchedulingFilter{"Unknown"}}},
+				}
+			},
+			expectedErrMsg: "spec.scheduling.profiles[0].filters[0]: Unsupported value",
+		},
+		{
+			name: "scheduling profile combining the None filter with other filters",
+			mutate: func(spec *v1beta1.KubeFedConfigSpec) {
+				spec.Scheduling = &v1beta1.SchedulingConfig{
+					Profiles: []v1beta1.SchedulingProfile{{Name: "batch", Filters: []v1beta1.SchedulingFilter{v1beta1.TaintsSchedulingFilter, v1beta1.NoneSchedulingFilter}}},
+				}
+			},
+			expectedErrMsg: "spec.scheduling.profiles[0].filters[1]: Invalid value",
+		},
+		{
+			name: "scheduling profile with an unknown scorer",
+			mutate: func(spec *v1beta1.KubeFedConfigSpec) {
+				spec.Scheduling = &v1beta1.SchedulingConfig{
+					Profiles: []v1beta1.SchedulingProfile{{Name: "batch", Scorers: []v1beta1.SchedulingScorer{{Type: "Unknown"}}}},
+				}
+			},
+			expectedErrMsg: "spec.scheduling.profiles[0].scorers[0].type: Unsupported value",
+		},
+		{
+			name: "label scorer without a label",
+			mutate: func(spec *v1beta1.KubeFedConfigSpec) {
+				spec.Scheduling = &v1beta1.SchedulingConfig{
+					Profiles: []v1beta1.SchedulingProfile{{Name: "batch", Scorers: []v1beta1.SchedulingScorer{{Type: v1beta1.LabelSchedulingScorer}}}},
+				}
+			},
+			expectedErrMsg: "spec.scheduling.profiles[0].scorers[0].label: Required value",
+		},
+		{
+			name: "undefined default scheduling profile",
+			mutate: func(spec *v1beta1.KubeFedConfigSpec) {
+				spec.Scheduling = &v1beta1.SchedulingConfig{DefaultProfile: "batch"}
+			},
+			expectedErrMsg: "spec.scheduling.defaultProfile: Not found",
+		},
+		{
+			name: "valid notification sinks",
+			mutate: func(spec *v1beta1.KubeFedConfigSpec) {
+				spec.Notifications = &v1beta1.NotificationConfig{
+					Sinks: []v1beta1.NotificationSink{
+						{Name: "ops", URL: "https://hooks.example.com/ops"},
+						{Name: "slack", URL: "http://slack.example.com", Format: v1beta1.SlackNotificationFormat,
+							Types: []v1beta1.NotificationType{v1beta1.ClusterNotReadyNotification, v1beta1.PropagationFailedNotification}},
+					},
+					PropagationFailureThreshold: 2,
+				}
+			},
+		},
+		{
+			name: "notification sink without a name",
+			mutate: func(spec *v1beta1.KubeFedConfigSpec) {
+				spec.Notifications = &v1beta1.NotificationConfig{
+					Sinks: []v1beta1.NotificationSink{{URL: "https://hooks.example.com"}},
+				}
+			},
+			expectedErrMsg: "spec.notifications.sinks[0].name: Required value",
+		},
+		{
+			name: "duplicate notification sink name",
+			mutate: func(spec *v1beta1.KubeFedConfigSpec) {
+				spec.Notifications = &v1beta1.NotificationConfig{
+					Sinks: []v1beta1.NotificationSink{
+						{Name: "ops", URL: "https://hooks.example.com/a"},
+						{Name: "ops", URL: "https://hooks.example.com/b"},
+					},
+				}
+			},
+			expectedErrMsg: "spec.notifications.sinks[1].name: Duplicate value",
+		},
+		{
+			name: "notification sink without a url",
+			mutate: func(spec *v1beta1.KubeFedConfigSpec) {
+				spec.Notifications = &v1beta1.NotificationConfig{
+					Sinks: []v1beta1.NotificationSink{{Name: "ops"}},
+				}
+			},
+			expectedErrMsg: "spec.notifications.sinks[0].url: Required value",
+		},
+		{
+			name: "notification sink with a non-http url",
+			mutate: func(spec *v1beta1.KubeFedConfigSpec) {
+				spec.Notifications = &v1beta1.NotificationConfig{
+					Sinks: []v1beta1.NotificationSink{{Name: "ops", URL: "ftp://hooks.example.com"}},
+				}
+			},
+			expectedErrMsg: "spec.notifications.sinks[0].url: Invalid value",
+		},
+		{
+			name: "notification sink with an unparseable url",
+			mutate: func(spec *v1beta1.KubeFedConfigSpec) {
+				spec.Notifications = &v1beta1.NotificationConfig{
+					Sinks: []v1beta1.NotificationSink{{Name: "ops", URL: "https://hooks.example.com:port"}},
+				}
+			},
+			expectedErrMsg: "spec.notifications.sinks[0].url: Invalid value",
+		},
+		{
+			name: "notification sink with an unknown format",
+			mutate: func(spec *v1beta1.KubeFedConfigSpec) {
+				spec.Notifications = &v1beta1.NotificationConfig{
+					Sinks: []v1beta1.NotificationSink{{Name: "ops", URL: "https://hooks.example.com", Format: "Email"}},
+				}
+			},
+			expectedErrMsg: "spec.notifications.sinks[0].format: Unsupported value",
+		},
+		{
+			name: "notification sink with an unknown type",
+			mutate: func(spec *v1beta1.KubeFedConfigSpec) {
+				spec.Notifications = &v1beta1.NotificationConfig{
+					Sinks: []v1beta1.NotificationSink{{Name: "ops", URL: "https://hooks.example.com",
+						Types: []v1beta1.NotificationType{v1beta1.ClusterReadyNotification, "ClusterDeleted"}}},
+				}
+			},
+			expectedErrMsg: "spec.notifications.sinks[0].types[1]: Unsupported value",
+		},
+		{
+			name: "negative propagation failure threshold",
+			mutate: func(spec *v1beta1.KubeFedConfigSpec) {
+				spec.Notifications = &v1beta1.NotificationConfig{PropagationFailureThreshold: -1}
+			},
+			expectedErrMsg: "spec.notifications.propagationFailureThreshold: Invalid value",
+		},
+	}
+
+	for _, test := range testCases {
+		spec := validSpec()
+		test.mutate(spec)
+		errs := ValidateKubeFedConfigSpec(spec, field.NewPath("spec"))
+		if len(test.expectedErrMsg) == 0 {
+			if len(errs) > 0 {
+				t.Errorf("[%s] unexpected error: %v", test.name, errs)
+			}
+			continue
+		}
+		if len(errs) == 0 {
+			t.Errorf("[%s] expected failure", test.name)
+		} else if !strings.Contains(errs[0].Error(), test.expectedErrMsg) {
+			t.Errorf("[%s] unexpected error: %q, expected: %q", test.name, errs[0].Error(), test.expectedErrMsg)
+		}
+	}
+}
+
 func successCases() []*v1beta1.FederatedTypeConfig {
+	paused := validFederatedTypeConfig()
+	paused.Spec.Propagation = v1beta1.PropagationPaused
 	return []*v1beta1.FederatedTypeConfig{
 		federatedTypeConfig(apiResourceWithEmptyGroup()),
 		federatedTypeConfig(apiResourceWithNonEmptyGroup()),
+		paused,
 	}
 }
 
@@ -0,0 +1,343 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package valuesschema validates the values of Helm charts against
+// the JSON schema of their chart.
+package valuesschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	apiextv1b1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// Parse parses the JSON schema of the values of a Helm chart
+// (e.g. the content of values.schema.json).  The keywords of the
+// OpenAPI v3 schemas of custom resources are supported, with the
+// exception of references.
+func Parse(schema string) (*apiextv1b1.JSONSchemaProps, error) {
+	props := &apiextv1b1.JSONSchemaProps{}
+	if err := json.Unmarshal([]byte(schema), props); err != nil {
+		return nil, errors.Wrap(err, "Failed to parse the values schema")
+	}
+	if err := checkSchemaSupported(props); err != nil {
+		return nil, err
+	}
+	return props, nil
+}
+
+func checkSchemaSupported(schema *apiextv1b1.JSONSchemaProps) error {
+	if schema.Ref != nil {
+		return errors.Errorf("$ref %q is not supported in a values schema", *schema.Ref)
+	}
+	if len(schema.Pattern) > 0 {
+		if _, err := regexp.Compile(schema.Pattern); err != nil {
+			return errors.Wrapf(err, "pattern %q of the values schema is invalid", schema.Pattern)
+		}
+	}
+	for _, child := range childSchemas(schema) {
+		if err := checkSchemaSupported(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func childSchemas(schema *apiextv1b1.JSONSchemaProps) []*apiextv1b1.JSONSchemaProps {
+	children := []*apiextv1b1.JSONSchemaProps{}
+	for name := range schema.Properties {
+		child := schema.Properties[name]
+		children = append(children, &child)
+	}
+	if schema.Items != nil {
+		if schema.Items.Schema != nil {
+			children = append(children, schema.Items.Schema)
+		}
+		for i := range schema.Items.JSONSchemas {
+			children = append(children, &schema.Items.JSONSchemas[i])
+		}
+	}
+	if schema.AdditionalProperties != nil && schema.AdditionalProperties.Schema != nil {
+		children = append(children, schema.AdditionalProperties.Schema)
+	}
+	for _, schemas := range [][]apiextv1b1.JSONSchemaProps{schema.AllOf, schema.AnyOf, schema.OneOf} {
+		for i := range schemas {
+			children = append(children, &schemas[i])
+		}
+	}
+	if schema.Not != nil {
+		children = append(children, schema.Not)
+	}
+	return children
+}
+
+// Validate validates the given chart values against a schema
+// returned by Parse.
+func Validate(values interface{}, schema *apiextv1b1.JSONSchemaProps, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if values == nil {
+		if len(schema.Type) > 0 && schema.Type != "null" {
+			allErrs = append(allErrs, field.Invalid(fldPath, values, fmt.Sprintf("must be of type %s", schema.Type)))
+		}
+		return allErrs
+	}
+
+	if len(schema.Type) > 0 && !hasType(values, schema.Type) {
+		return append(allErrs, field.Invalid(fldPath, values, fmt.Sprintf("must be of type %s", schema.Type)))
+	}
+
+	if len(schema.Enum) > 0 {
+		allowed := false
+		for _, raw := range schema.Enum {
+			var value interface{}
+			if err := json.Unmarshal(raw.Raw, &value); err == nil && reflect.DeepEqual(normalizeValue(value), normalizeValue(values)) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			allErrs = append(allErrs, field.NotSupported(fldPath, values, enumStrings(schema.Enum)))
+		}
+	}
+
+	switch value := values.(type) {
+	case map[string]interface{}:
+		allErrs = append(allErrs, validateObject(value, schema, fldPath)...)
+	case []interface{}:
+		allErrs = append(allErrs, validateArray(value, schema, fldPath)...)
+	case string:
+		if schema.MinLength != nil && int64(len(value)) < *schema.MinLength {
+			allErrs = append(allErrs, field.Invalid(fldPath, value, fmt.Sprintf("must be at least %d characters long", *schema.MinLength)))
+		}
+		if schema.MaxLength != nil && int64(len(value)) > *schema.MaxLength {
+			allErrs = append(allErrs, field.TooLong(fldPath, value, int(*schema.MaxLength)))
+		}
+		if len(schema.Pattern) > 0 {
+			if pattern, err := regexp.Compile(schema.Pattern); err == nil && !pattern.MatchString(value) {
+				allErrs = append(allErrs, field.Invalid(fldPath, value, fmt.Sprintf("must match the pattern %s", schema.Pattern)))
+			}
+		}
+	default:
+		if number, ok := toFloat(values); ok {
+			allErrs = append(allErrs, validateNumber(number, schema, fldPath)...)
+		}
+	}
+
+	for i := range schema.AllOf {
+		allErrs = append(allErrs, Validate(values, &schema.AllOf[i], fldPath)...)
+	}
+	if len(schema.AnyOf) > 0 && matchingSchemas(values, schema.AnyOf, fldPath) == 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath, values, "must match at least one of the schemas of anyOf"))
+	}
+	if len(schema.OneOf) > 0 && matchingSchemas(values, schema.OneOf, fldPath) != 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath, values, "must match exactly one of the schemas of oneOf"))
+	}
+	if schema.Not != nil && len(Validate(values, schema.Not, fldPath)) == 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath, values, "must not match the schema of not"))
+	}
+	return allErrs
+}
+
+func validateObject(values map[string]interface{}, schema *apiextv1b1.JSONSchemaProps, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for _, name := range schema.Required {
+		if _, ok := values[name]; !ok {
+			allErrs = append(allErrs, field.Required(fldPath.Child(name), ""))
+		}
+	}
+	if schema.MinProperties != nil && int64(len(values)) < *schema.MinProperties {
+		allErrs = append(allErrs, field.Invalid(fldPath, len(values), fmt.Sprintf("must have at least %d properties", *schema.MinProperties)))
+	}
+	if schema.MaxProperties != nil && int64(len(values)) > *schema.MaxProperties {
+		allErrs = append(allErrs, field.Invalid(fldPath, len(values), fmt.Sprintf("must have at most %d properties", *schema.MaxProperties)))
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		childPath := fldPath.Child(name)
+		if property, ok := schema.Properties[name]; ok {
+			allErrs = append(allErrs, Validate(values[name], &property, childPath)...)
+			continue
+		}
+		additional := schema.AdditionalProperties
+		if additional == nil {
+			continue
+		}
+		if additional.Schema != nil {
+			allErrs = append(allErrs, Validate(values[name], additional.Schema, childPath)...)
+		} else if !additional.Allows {
+			allErrs = append(allErrs, field.Forbidden(childPath, "is not a property of the values schema"))
+		}
+	}
+	return allErrs
+}
+
+func validateArray(values []interface{}, schema *apiextv1b1.JSONSchemaProps, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if schema.MinItems != nil && int64(len(values)) < *schema.MinItems {
+		allErrs = append(allErrs, field.Invalid(fldPath, len(values), fmt.Sprintf("must have at least %d items", *schema.MinItems)))
+	}
+	if schema.MaxItems != nil && int64(len(values)) > *schema.MaxItems {
+		allErrs = append(allErrs, field.Invalid(fldPath, len(values), fmt.Sprintf("must have at most %d items", *schema.MaxItems)))
+	}
+	if schema.UniqueItems {
+		for i := range values {
+			for j := 0; j < i; j++ {
+				if reflect.DeepEqual(normalizeValue(values[i]), normalizeValue(values[j])) {
+					allErrs = append(allErrs, field.Duplicate(fldPath.Index(i), values[i]))
+					break
+				}
+			}
+		}
+	}
+	if schema.Items == nil {
+		return allErrs
+	}
+	for i, item := range values {
+		itemSchema := schema.Items.Schema
+		if itemSchema == nil {
+			if i >= len(schema.Items.JSONSchemas) {
+				continue
+			}
+			itemSchema = &schema.Items.JSONSchemas[i]
+		}
+		allErrs = append(allErrs, Validate(item, itemSchema, fldPath.Index(i))...)
+	}
+	return allErrs
+}
+
+func validateNumber(value float64, schema *apiextv1b1.JSONSchemaProps, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if schema.Minimum != nil {
+		if schema.ExclusiveMinimum && value <= *schema.Minimum {
+			allErrs = append(allErrs, field.Invalid(fldPath, value, fmt.Sprintf("must be greater than %v", *schema.Minimum)))
+		} else if value < *schema.Minimum {
+			allErrs = append(allErrs, field.Invalid(fldPath, value, fmt.Sprintf("must be greater than or equal to %v", *schema.Minimum)))
+		}
+	}
+	if schema.Maximum != nil {
+		if schema.ExclusiveMaximum && value >= *schema.Maximum {
+			allErrs = append(allErrs, field.Invalid(fldPath, value, fmt.Sprintf("must be less than %v", *schema.Maximum)))
+		} else if value > *schema.Maximum {
+			allErrs = append(allErrs, field.Invalid(fldPath, value, fmt.Sprintf("must be less than or equal to %v", *schema.Maximum)))
+		}
+	}
+	if schema.MultipleOf != nil && *schema.MultipleOf != 0 {
+		quotient := value / *schema.MultipleOf
+		if quotient != math.Trunc(quotient) {
+			allErrs = append(allErrs, field.Invalid(fldPath, value, fmt.Sprintf("must be a multiple of %v", *schema.MultipleOf)))
+		}
+	}
+	return allErrs
+}
+
+func matchingSchemas(values interface{}, schemas []apiextv1b1.JSONSchemaProps, fldPath *field.Path) int {
+	matches := 0
+	for i := range schemas {
+		if len(Validate(values, &schemas[i], fldPath)) == 0 {
+			matches++
+		}
+	}
+	return matches
+}
+
+func hasType(value interface{}, schemaType string) bool {
+	switch schemaType {
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "number":
+		_, ok := toFloat(value)
+		return ok
+	case "integer":
+		number, ok := toFloat(value)
+		return ok && number == math.Trunc(number)
+	case "null":
+		return value == nil
+	}
+	return false
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch number := value.(type) {
+	case int:
+		return float64(number), true
+	case int32:
+		return float64(number), true
+	case int64:
+		return float64(number), true
+	case float32:
+		return float64(number), true
+	case float64:
+		return number, true
+	case json.Number:
+		f, err := number.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// normalizeValue converts the numbers of the given value to float64
+// so that values decoded from JSON and YAML compare equal.
+func normalizeValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		normalized := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			normalized[key] = normalizeValue(item)
+		}
+		return normalized
+	case []interface{}:
+		normalized := make([]interface{}, len(v))
+		for i, item := range v {
+			normalized[i] = normalizeValue(item)
+		}
+		return normalized
+	}
+	if number, ok := toFloat(value); ok {
+		return number
+	}
+	return value
+}
+
+func enumStrings(enum []apiextv1b1.JSON) []string {
+	values := make([]string, 0, len(enum))
+	for _, value := range enum {
+		values = append(values, string(value.Raw))
+	}
+	return values
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package valuesschema
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+const testValuesSchema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "type": "object",
+  "required": ["image"],
+  "properties": {
+    "replicaCount": {"type": "integer", "minimum": 1},
+    "image": {
+      "type": "object",
+      "additionalProperties": false,
+      "properties": {
+        "repository": {"type": "string", "pattern": "^[a-z0-9./-]+$"},
+        "pullPolicy": {"type": "string", "enum": ["Always", "IfNotPresent", "Never"]}
+      }
+    },
+    "tolerations": {"type": "array", "maxItems": 2, "items": {"type": "object"}},
+    "resources": {"type": "object", "additionalProperties": {"type": "string"}}
+  }
+}`
+
+func TestParse(t *testing.T) {
+	if _, err := Parse(testValuesSchema); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	errorCases := map[string]string{
+		"Failed to parse":    `{"type": ["string", "null"]}`,
+		"is not supported":   `{"properties": {"image": {"$ref": "#/definitions/image"}}}`,
+		"pattern \"[\" of":   `{"type": "string", "pattern": "["}`,
+		"Failed to parse th": `not json`,
+	}
+	for k, schema := range errorCases {
+		_, err := Parse(schema)
+		if err == nil {
+			t.Errorf("[%s] expected failure", k)
+		} else if !strings.Contains(err.Error(), k) {
+			t.Errorf("unexpected error: %q, expected: %q", err.Error(), k)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	schema, err := Parse(testValuesSchema)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	image := func(fields map[string]interface{}) map[string]interface{} {
+		values := map[string]interface{}{"image": map[string]interface{}{"repository": "nginx"}}
+		for key, value := range fields {
+			values[key] = value
+		}
+		return values
+	}
+
+	successCases := []map[string]interface{}{
+		image(nil),
+		// Numbers decoded from YAML are float64 and those of
+		// unstructured objects are int64.
+		image(map[string]interface{}{"replicaCount": float64(3)}),
+		image(map[string]interface{}{"replicaCount": int64(3)}),
+		image(map[string]interface{}{"resources": map[string]interface{}{"cpu": "100m"}}),
+		image(map[string]interface{}{"undeclared": true}),
+		{"image": map[string]interface{}{"pullPolicy": "Always"}},
+	}
+	for _, values := range successCases {
+		if errs := Validate(values, schema, field.NewPath("values")); len(errs) != 0 {
+			t.Errorf("expected success for %v: %v", values, errs)
+		}
+	}
+
+	errorCases := map[string]map[string]interface{}{
+		"values.image: Required value":                        {"replicaCount": int64(1)},
+		"values.replicaCount: Invalid value: 1.5":             image(map[string]interface{}{"replicaCount": 1.5}),
+		"values.replicaCount: Invalid value: \"3\"":           image(map[string]interface{}{"replicaCount": "3"}),
+		"values.replicaCount: Invalid value: 0: must be grea": image(map[string]interface{}{"replicaCount": int64(0)}),
+		"values.image.tag: Forbidden":                         {"image": map[string]interface{}{"tag": "latest"}},
+		"values.image.repository: Invalid value":              {"image": map[string]interface{}{"repository": "Nginx"}},
+		"values.image.pullPolicy: Unsupported value":          {"image": map[string]interface{}{"pullPolicy": "Sometimes"}},
+		"values.tolerations: Invalid value: 3":                image(map[string]interface{}{"tolerations": []interface{}{map[string]interface{}{}, map[string]interface{}{}, map[string]interface{}{}}}),
+		"values.tolerations[0]: Invalid value":                image(map[string]interface{}{"tolerations": []interface{}{"key"}}),
+		"values.resources.cpu: Invalid value":                 image(map[string]interface{}{"resources": map[string]interface{}{"cpu": int64(1)}}),
+	}
+	for k, values := range errorCases {
+		errs := Validate(values, schema, field.NewPath("values"))
+		if len(errs) == 0 {
+			t.Errorf("[%s] expected failure", k)
+		} else if !strings.Contains(errs[0].Error(), k) {
+			t.Errorf("unexpected error: %q, expected: %q", errs[0].Error(), k)
+		}
+	}
+}
@@ -21,6 +21,8 @@ limitations under the License.
 package v1beta1
 
 import (
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -40,6 +42,85 @@ func (in *APIResource) DeepCopy() *APIResource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AllowedClusterScopedResource) DeepCopyInto(out *AllowedClusterScopedResource) {
+	*out = *in
+	if in.Names != nil {
+		in, out := &in.Names, &out.Names
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AllowedClusterScopedResource.
+func (in *AllowedClusterScopedResource) DeepCopy() *AllowedClusterScopedResource {
+	if in == nil {
+		return nil
+	}
+	out := new(AllowedClusterScopedResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CircuitBreakerConfig) DeepCopyInto(out *CircuitBreakerConfig) {
+	*out = *in
+	if in.InitialBackoff != nil {
+		in, out := &in.InitialBackoff, &out.InitialBackoff
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MaxBackoff != nil {
+		in, out := &in.MaxBackoff, &out.MaxBackoff
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CircuitBreakerConfig.
+func (in *CircuitBreakerConfig) DeepCopy() *CircuitBreakerConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(CircuitBreakerConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterAPIHealth) DeepCopyInto(out *ClusterAPIHealth) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterAPIHealth.
+func (in *ClusterAPIHealth) DeepCopy() *ClusterAPIHealth {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterAPIHealth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterAutoscalerStatus) DeepCopyInto(out *ClusterAutoscalerStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterAutoscalerStatus.
+func (in *ClusterAutoscalerStatus) DeepCopy() *ClusterAutoscalerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterAutoscalerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterCondition) DeepCopyInto(out *ClusterCondition) {
 	*out = *in
@@ -48,198 +129,1143 @@ func (in *ClusterCondition) DeepCopyInto(out *ClusterCondition) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterCondition.
-func (in *ClusterCondition) DeepCopy() *ClusterCondition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterCondition.
+func (in *ClusterCondition) DeepCopy() *ClusterCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterDeploymentProbe) DeepCopyInto(out *ClusterDeploymentProbe) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterDeploymentProbe.
+func (in *ClusterDeploymentProbe) DeepCopy() *ClusterDeploymentProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterDeploymentProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterHealthCheckConfig) DeepCopyInto(out *ClusterHealthCheckConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterHealthCheckConfig.
+func (in *ClusterHealthCheckConfig) DeepCopy() *ClusterHealthCheckConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterHealthCheckConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterHealthCheckFailure) DeepCopyInto(out *ClusterHealthCheckFailure) {
+	*out = *in
+	in.Since.DeepCopyInto(&out.Since)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterHealthCheckFailure.
+func (in *ClusterHealthCheckFailure) DeepCopy() *ClusterHealthCheckFailure {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterHealthCheckFailure)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterHealthProbe) DeepCopyInto(out *ClusterHealthProbe) {
+	*out = *in
+	if in.Service != nil {
+		in, out := &in.Service, &out.Service
+		*out = new(ClusterServiceProbe)
+		**out = **in
+	}
+	if in.Deployment != nil {
+		in, out := &in.Deployment, &out.Deployment
+		*out = new(ClusterDeploymentProbe)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterHealthProbe.
+func (in *ClusterHealthProbe) DeepCopy() *ClusterHealthProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterHealthProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterLifecycleHook) DeepCopyInto(out *ClusterLifecycleHook) {
+	*out = *in
+	if in.Events != nil {
+		in, out := &in.Events, &out.Events
+		*out = make([]ClusterLifecycleEvent, len(*in))
+		copy(*out, *in)
+	}
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = new(ClusterLifecycleWebhook)
+		**out = **in
+	}
+	if in.Job != nil {
+		in, out := &in.Job, &out.Job
+		*out = new(ClusterLifecycleJob)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterLifecycleHook.
+func (in *ClusterLifecycleHook) DeepCopy() *ClusterLifecycleHook {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterLifecycleHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterLifecycleHookStatus) DeepCopyInto(out *ClusterLifecycleHookStatus) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterLifecycleHookStatus.
+func (in *ClusterLifecycleHookStatus) DeepCopy() *ClusterLifecycleHookStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterLifecycleHookStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterLifecycleJob) DeepCopyInto(out *ClusterLifecycleJob) {
+	*out = *in
+	in.Template.DeepCopyInto(&out.Template)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterLifecycleJob.
+func (in *ClusterLifecycleJob) DeepCopy() *ClusterLifecycleJob {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterLifecycleJob)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterLifecycleWebhook) DeepCopyInto(out *ClusterLifecycleWebhook) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterLifecycleWebhook.
+func (in *ClusterLifecycleWebhook) DeepCopy() *ClusterLifecycleWebhook {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterLifecycleWebhook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterPressure) DeepCopyInto(out *ClusterPressure) {
+	*out = *in
+	if in.LastScaleUpFailureTime != nil {
+		in, out := &in.LastScaleUpFailureTime, &out.LastScaleUpFailureTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Autoscaler != nil {
+		in, out := &in.Autoscaler, &out.Autoscaler
+		*out = new(ClusterAutoscalerStatus)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterPressure.
+func (in *ClusterPressure) DeepCopy() *ClusterPressure {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterPressure)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterServiceProbe) DeepCopyInto(out *ClusterServiceProbe) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterServiceProbe.
+func (in *ClusterServiceProbe) DeepCopy() *ClusterServiceProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterServiceProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControllerSwitchConfig) DeepCopyInto(out *ControllerSwitchConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControllerSwitchConfig.
+func (in *ControllerSwitchConfig) DeepCopy() *ControllerSwitchConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ControllerSwitchConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DefaultOverride) DeepCopyInto(out *DefaultOverride) {
+	*out = *in
+	if in.Value != nil {
+		in, out := &in.Value, &out.Value
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefaultOverride.
+func (in *DefaultOverride) DeepCopy() *DefaultOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(DefaultOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeletionLimitConfig) DeepCopyInto(out *DeletionLimitConfig) {
+	*out = *in
+	if in.Window != nil {
+		in, out := &in.Window, &out.Window
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeletionLimitConfig.
+func (in *DeletionLimitConfig) DeepCopy() *DeletionLimitConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(DeletionLimitConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiagnosticsConfig) DeepCopyInto(out *DiagnosticsConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiagnosticsConfig.
+func (in *DiagnosticsConfig) DeepCopy() *DiagnosticsConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(DiagnosticsConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DispatchConcurrencyConfig) DeepCopyInto(out *DispatchConcurrencyConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DispatchConcurrencyConfig.
+func (in *DispatchConcurrencyConfig) DeepCopy() *DispatchConcurrencyConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(DispatchConcurrencyConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DispatchPolicy) DeepCopyInto(out *DispatchPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DispatchPolicy.
+func (in *DispatchPolicy) DeepCopy() *DispatchPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(DispatchPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DispatchPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DispatchPolicyJSONPathRule) DeepCopyInto(out *DispatchPolicyJSONPathRule) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DispatchPolicyJSONPathRule.
+func (in *DispatchPolicyJSONPathRule) DeepCopy() *DispatchPolicyJSONPathRule {
+	if in == nil {
+		return nil
+	}
+	out := new(DispatchPolicyJSONPathRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DispatchPolicyList) DeepCopyInto(out *DispatchPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DispatchPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DispatchPolicyList.
+func (in *DispatchPolicyList) DeepCopy() *DispatchPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(DispatchPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DispatchPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DispatchPolicyRule) DeepCopyInto(out *DispatchPolicyRule) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DispatchPolicyRule.
+func (in *DispatchPolicyRule) DeepCopy() *DispatchPolicyRule {
+	if in == nil {
+		return nil
+	}
+	out := new(DispatchPolicyRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DispatchPolicySpec) DeepCopyInto(out *DispatchPolicySpec) {
+	*out = *in
+	if in.TargetKinds != nil {
+		in, out := &in.TargetKinds, &out.TargetKinds
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClusterSelector != nil {
+		in, out := &in.ClusterSelector, &out.ClusterSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.JSONPathRules != nil {
+		in, out := &in.JSONPathRules, &out.JSONPathRules
+		*out = make([]DispatchPolicyJSONPathRule, len(*in))
+		copy(*out, *in)
+	}
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]DispatchPolicyRule, len(*in))
+		copy(*out, *in)
+	}
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = new(DispatchPolicyWebhook)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DispatchPolicySpec.
+func (in *DispatchPolicySpec) DeepCopy() *DispatchPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DispatchPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DispatchPolicyWebhook) DeepCopyInto(out *DispatchPolicyWebhook) {
+	*out = *in
+	if in.CABundle != nil {
+		in, out := &in.CABundle, &out.CABundle
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DispatchPolicyWebhook.
+func (in *DispatchPolicyWebhook) DeepCopy() *DispatchPolicyWebhook {
+	if in == nil {
+		return nil
+	}
+	out := new(DispatchPolicyWebhook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DurationConfig) DeepCopyInto(out *DurationConfig) {
+	*out = *in
+	out.AvailableDelay = in.AvailableDelay
+	out.UnavailableDelay = in.UnavailableDelay
+	out.StatusUpdateInterval = in.StatusUpdateInterval
+	if in.MemberReadCacheTTL != nil {
+		in, out := &in.MemberReadCacheTTL, &out.MemberReadCacheTTL
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DurationConfig.
+func (in *DurationConfig) DeepCopy() *DurationConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(DurationConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FeatureGatesConfig) DeepCopyInto(out *FeatureGatesConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FeatureGatesConfig.
+func (in *FeatureGatesConfig) DeepCopy() *FeatureGatesConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(FeatureGatesConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FederatedTypeConfig) DeepCopyInto(out *FederatedTypeConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FederatedTypeConfig.
+func (in *FederatedTypeConfig) DeepCopy() *FederatedTypeConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(FederatedTypeConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FederatedTypeConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FederatedTypeConfigList) DeepCopyInto(out *FederatedTypeConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]FederatedTypeConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FederatedTypeConfigList.
+func (in *FederatedTypeConfigList) DeepCopy() *FederatedTypeConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(FederatedTypeConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FederatedTypeConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FederatedTypeConfigSpec) DeepCopyInto(out *FederatedTypeConfigSpec) {
+	*out = *in
+	out.TargetType = in.TargetType
+	if in.TargetVersions != nil {
+		in, out := &in.TargetVersions, &out.TargetVersions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	out.FederatedType = in.FederatedType
+	if in.StatusType != nil {
+		in, out := &in.StatusType, &out.StatusType
+		*out = new(APIResource)
+		**out = **in
+	}
+	if in.StatusCollection != nil {
+		in, out := &in.StatusCollection, &out.StatusCollection
+		*out = new(StatusCollectionMode)
+		**out = **in
+	}
+	if in.StatusUpdateInterval != nil {
+		in, out := &in.StatusUpdateInterval, &out.StatusUpdateInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.DefaultOverrides != nil {
+		in, out := &in.DefaultOverrides, &out.DefaultOverrides
+		*out = make([]DefaultOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = new(ValuesConfig)
+		**out = **in
+	}
+	if in.LocallyManagedFields != nil {
+		in, out := &in.LocallyManagedFields, &out.LocallyManagedFields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MemberEventFilter != nil {
+		in, out := &in.MemberEventFilter, &out.MemberEventFilter
+		*out = new(MemberEventFilter)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FederatedTypeConfigSpec.
+func (in *FederatedTypeConfigSpec) DeepCopy() *FederatedTypeConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FederatedTypeConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FederatedTypeConfigStatus) DeepCopyInto(out *FederatedTypeConfigStatus) {
+	*out = *in
+	if in.StatusController != nil {
+		in, out := &in.StatusController, &out.StatusController
+		*out = new(ControllerStatus)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FederatedTypeConfigStatus.
+func (in *FederatedTypeConfigStatus) DeepCopy() *FederatedTypeConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FederatedTypeConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeFedCluster) DeepCopyInto(out *KubeFedCluster) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeFedCluster.
+func (in *KubeFedCluster) DeepCopy() *KubeFedCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeFedCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KubeFedCluster) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeFedClusterList) DeepCopyInto(out *KubeFedClusterList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KubeFedCluster, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeFedClusterList.
+func (in *KubeFedClusterList) DeepCopy() *KubeFedClusterList {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeFedClusterList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KubeFedClusterList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeFedClusterSpec) DeepCopyInto(out *KubeFedClusterSpec) {
+	*out = *in
+	if in.SecondaryAPIEndpoints != nil {
+		in, out := &in.SecondaryAPIEndpoints, &out.SecondaryAPIEndpoints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CABundle != nil {
+		in, out := &in.CABundle, &out.CABundle
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
+	out.SecretRef = in.SecretRef
+	if in.NamespaceMappings != nil {
+		in, out := &in.NamespaceMappings, &out.NamespaceMappings
+		*out = make([]NamespaceMapping, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedNamespaces != nil {
+		in, out := &in.AllowedNamespaces, &out.AllowedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DeniedNamespaces != nil {
+		in, out := &in.DeniedNamespaces, &out.DeniedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Taints != nil {
+		in, out := &in.Taints, &out.Taints
+		*out = make([]corev1.Taint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ProfileRef != nil {
+		in, out := &in.ProfileRef, &out.ProfileRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.HealthProbes != nil {
+		in, out := &in.HealthProbes, &out.HealthProbes
+		*out = make([]ClusterHealthProbe, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeFedClusterSpec.
+func (in *KubeFedClusterSpec) DeepCopy() *KubeFedClusterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeFedClusterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeFedClusterStatus) DeepCopyInto(out *KubeFedClusterStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]ClusterCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.APIHealth != nil {
+		in, out := &in.APIHealth, &out.APIHealth
+		*out = new(ClusterAPIHealth)
+		**out = **in
+	}
+	if in.HealthCheckFailure != nil {
+		in, out := &in.HealthCheckFailure, &out.HealthCheckFailure
+		*out = new(ClusterHealthCheckFailure)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Pressure != nil {
+		in, out := &in.Pressure, &out.Pressure
+		*out = new(ClusterPressure)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LifecycleHooks != nil {
+		in, out := &in.LifecycleHooks, &out.LifecycleHooks
+		*out = make([]ClusterLifecycleHookStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeFedClusterStatus.
+func (in *KubeFedClusterStatus) DeepCopy() *KubeFedClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeFedClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeFedConfig) DeepCopyInto(out *KubeFedConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeFedConfig.
+func (in *KubeFedConfig) DeepCopy() *KubeFedConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeFedConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KubeFedConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeFedConfigCondition) DeepCopyInto(out *KubeFedConfigCondition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeFedConfigCondition.
+func (in *KubeFedConfigCondition) DeepCopy() *KubeFedConfigCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeFedConfigCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeFedConfigList) DeepCopyInto(out *KubeFedConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KubeFedConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeFedConfigList.
+func (in *KubeFedConfigList) DeepCopy() *KubeFedConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeFedConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KubeFedConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeFedConfigSpec) DeepCopyInto(out *KubeFedConfigSpec) {
+	*out = *in
+	if in.AllowedClusterScopedResources != nil {
+		in, out := &in.AllowedClusterScopedResources, &out.AllowedClusterScopedResources
+		*out = make([]AllowedClusterScopedResource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.ControllerDuration.DeepCopyInto(&out.ControllerDuration)
+	out.LeaderElect = in.LeaderElect
+	if in.FeatureGates != nil {
+		in, out := &in.FeatureGates, &out.FeatureGates
+		*out = make([]FeatureGatesConfig, len(*in))
+		copy(*out, *in)
+	}
+	if in.Controllers != nil {
+		in, out := &in.Controllers, &out.Controllers
+		*out = make([]ControllerSwitchConfig, len(*in))
+		copy(*out, *in)
+	}
+	out.ClusterHealthCheck = in.ClusterHealthCheck
+	if in.ClusterLifecycleHooks != nil {
+		in, out := &in.ClusterLifecycleHooks, &out.ClusterLifecycleHooks
+		*out = make([]ClusterLifecycleHook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.SyncController.DeepCopyInto(&out.SyncController)
+	if in.Notifications != nil {
+		in, out := &in.Notifications, &out.Notifications
+		*out = new(NotificationConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Scheduling != nil {
+		in, out := &in.Scheduling, &out.Scheduling
+		*out = new(SchedulingConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Diagnostics != nil {
+		in, out := &in.Diagnostics, &out.Diagnostics
+		*out = new(DiagnosticsConfig)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeFedConfigSpec.
+func (in *KubeFedConfigSpec) DeepCopy() *KubeFedConfigSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterCondition)
+	out := new(KubeFedConfigSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterHealthCheckConfig) DeepCopyInto(out *ClusterHealthCheckConfig) {
+func (in *KubeFedConfigStatus) DeepCopyInto(out *KubeFedConfigStatus) {
 	*out = *in
+	if in.EffectiveSpec != nil {
+		in, out := &in.EffectiveSpec, &out.EffectiveSpec
+		*out = new(KubeFedConfigSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]KubeFedConfigCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterHealthCheckConfig.
-func (in *ClusterHealthCheckConfig) DeepCopy() *ClusterHealthCheckConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeFedConfigStatus.
+func (in *KubeFedConfigStatus) DeepCopy() *KubeFedConfigStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterHealthCheckConfig)
+	out := new(KubeFedConfigStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DurationConfig) DeepCopyInto(out *DurationConfig) {
+func (in *LeaderElectConfig) DeepCopyInto(out *LeaderElectConfig) {
 	*out = *in
-	out.AvailableDelay = in.AvailableDelay
-	out.UnavailableDelay = in.UnavailableDelay
+	out.LeaseDuration = in.LeaseDuration
+	out.RenewDeadline = in.RenewDeadline
+	out.RetryPeriod = in.RetryPeriod
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DurationConfig.
-func (in *DurationConfig) DeepCopy() *DurationConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LeaderElectConfig.
+func (in *LeaderElectConfig) DeepCopy() *LeaderElectConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(DurationConfig)
+	out := new(LeaderElectConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FeatureGatesConfig) DeepCopyInto(out *FeatureGatesConfig) {
+func (in *LocalSecretReference) DeepCopyInto(out *LocalSecretReference) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FeatureGatesConfig.
-func (in *FeatureGatesConfig) DeepCopy() *FeatureGatesConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalSecretReference.
+func (in *LocalSecretReference) DeepCopy() *LocalSecretReference {
 	if in == nil {
 		return nil
 	}
-	out := new(FeatureGatesConfig)
+	out := new(LocalSecretReference)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FederatedTypeConfig) DeepCopyInto(out *FederatedTypeConfig) {
+func (in *MemberEventFilter) DeepCopyInto(out *MemberEventFilter) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	if in.IgnoredAnnotations != nil {
+		in, out := &in.IgnoredAnnotations, &out.IgnoredAnnotations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FederatedTypeConfig.
-func (in *FederatedTypeConfig) DeepCopy() *FederatedTypeConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemberEventFilter.
+func (in *MemberEventFilter) DeepCopy() *MemberEventFilter {
 	if in == nil {
 		return nil
 	}
-	out := new(FederatedTypeConfig)
+	out := new(MemberEventFilter)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *FederatedTypeConfig) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceMapping) DeepCopyInto(out *NamespaceMapping) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceMapping.
+func (in *NamespaceMapping) DeepCopy() *NamespaceMapping {
+	if in == nil {
+		return nil
 	}
-	return nil
+	out := new(NamespaceMapping)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FederatedTypeConfigList) DeepCopyInto(out *FederatedTypeConfigList) {
+func (in *NamespaceMetadataConfig) DeepCopyInto(out *NamespaceMetadataConfig) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	out.ListMeta = in.ListMeta
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]FederatedTypeConfig, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FederatedTypeConfigList.
-func (in *FederatedTypeConfigList) DeepCopy() *FederatedTypeConfigList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceMetadataConfig.
+func (in *NamespaceMetadataConfig) DeepCopy() *NamespaceMetadataConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(FederatedTypeConfigList)
+	out := new(NamespaceMetadataConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *FederatedTypeConfigList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FederatedTypeConfigSpec) DeepCopyInto(out *FederatedTypeConfigSpec) {
+func (in *NotificationConfig) DeepCopyInto(out *NotificationConfig) {
 	*out = *in
-	out.TargetType = in.TargetType
-	out.FederatedType = in.FederatedType
-	if in.StatusType != nil {
-		in, out := &in.StatusType, &out.StatusType
-		*out = new(APIResource)
-		**out = **in
-	}
-	if in.StatusCollection != nil {
-		in, out := &in.StatusCollection, &out.StatusCollection
-		*out = new(StatusCollectionMode)
-		**out = **in
+	if in.Sinks != nil {
+		in, out := &in.Sinks, &out.Sinks
+		*out = make([]NotificationSink, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FederatedTypeConfigSpec.
-func (in *FederatedTypeConfigSpec) DeepCopy() *FederatedTypeConfigSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationConfig.
+func (in *NotificationConfig) DeepCopy() *NotificationConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(FederatedTypeConfigSpec)
+	out := new(NotificationConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FederatedTypeConfigStatus) DeepCopyInto(out *FederatedTypeConfigStatus) {
+func (in *NotificationSink) DeepCopyInto(out *NotificationSink) {
 	*out = *in
-	if in.StatusController != nil {
-		in, out := &in.StatusController, &out.StatusController
-		*out = new(ControllerStatus)
-		**out = **in
+	if in.Types != nil {
+		in, out := &in.Types, &out.Types
+		*out = make([]NotificationType, len(*in))
+		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FederatedTypeConfigStatus.
-func (in *FederatedTypeConfigStatus) DeepCopy() *FederatedTypeConfigStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationSink.
+func (in *NotificationSink) DeepCopy() *NotificationSink {
 	if in == nil {
 		return nil
 	}
-	out := new(FederatedTypeConfigStatus)
+	out := new(NotificationSink)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KubeFedCluster) DeepCopyInto(out *KubeFedCluster) {
+func (in *PlacementPolicy) DeepCopyInto(out *PlacementPolicy) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeFedCluster.
-func (in *KubeFedCluster) DeepCopy() *KubeFedCluster {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementPolicy.
+func (in *PlacementPolicy) DeepCopy() *PlacementPolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(KubeFedCluster)
+	out := new(PlacementPolicy)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *KubeFedCluster) DeepCopyObject() runtime.Object {
+func (in *PlacementPolicy) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -247,13 +1273,13 @@ func (in *KubeFedCluster) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KubeFedClusterList) DeepCopyInto(out *KubeFedClusterList) {
+func (in *PlacementPolicyList) DeepCopyInto(out *PlacementPolicyList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	out.ListMeta = in.ListMeta
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]KubeFedCluster, len(*in))
+		*out = make([]PlacementPolicy, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -261,18 +1287,18 @@ func (in *KubeFedClusterList) DeepCopyInto(out *KubeFedClusterList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeFedClusterList.
-func (in *KubeFedClusterList) DeepCopy() *KubeFedClusterList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementPolicyList.
+func (in *PlacementPolicyList) DeepCopy() *PlacementPolicyList {
 	if in == nil {
 		return nil
 	}
-	out := new(KubeFedClusterList)
+	out := new(PlacementPolicyList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *KubeFedClusterList) DeepCopyObject() runtime.Object {
+func (in *PlacementPolicyList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -280,76 +1306,74 @@ func (in *KubeFedClusterList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KubeFedClusterSpec) DeepCopyInto(out *KubeFedClusterSpec) {
+func (in *PlacementPolicySpec) DeepCopyInto(out *PlacementPolicySpec) {
 	*out = *in
-	if in.CABundle != nil {
-		in, out := &in.CABundle, &out.CABundle
-		*out = make([]byte, len(*in))
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	out.SecretRef = in.SecretRef
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClusterSelector != nil {
+		in, out := &in.ClusterSelector, &out.ClusterSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeFedClusterSpec.
-func (in *KubeFedClusterSpec) DeepCopy() *KubeFedClusterSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementPolicySpec.
+func (in *PlacementPolicySpec) DeepCopy() *PlacementPolicySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(KubeFedClusterSpec)
+	out := new(PlacementPolicySpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KubeFedClusterStatus) DeepCopyInto(out *KubeFedClusterStatus) {
+func (in *PropagationMetadataConfig) DeepCopyInto(out *PropagationMetadataConfig) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]ClusterCondition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.Zones != nil {
-		in, out := &in.Zones, &out.Zones
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeFedClusterStatus.
-func (in *KubeFedClusterStatus) DeepCopy() *KubeFedClusterStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PropagationMetadataConfig.
+func (in *PropagationMetadataConfig) DeepCopy() *PropagationMetadataConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(KubeFedClusterStatus)
+	out := new(PropagationMetadataConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KubeFedConfig) DeepCopyInto(out *KubeFedConfig) {
+func (in *PropagationQuota) DeepCopyInto(out *PropagationQuota) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeFedConfig.
-func (in *KubeFedConfig) DeepCopy() *KubeFedConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PropagationQuota.
+func (in *PropagationQuota) DeepCopy() *PropagationQuota {
 	if in == nil {
 		return nil
 	}
-	out := new(KubeFedConfig)
+	out := new(PropagationQuota)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *KubeFedConfig) DeepCopyObject() runtime.Object {
+func (in *PropagationQuota) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -357,13 +1381,13 @@ func (in *KubeFedConfig) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KubeFedConfigList) DeepCopyInto(out *KubeFedConfigList) {
+func (in *PropagationQuotaList) DeepCopyInto(out *PropagationQuotaList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	out.ListMeta = in.ListMeta
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]KubeFedConfig, len(*in))
+		*out = make([]PropagationQuota, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -371,18 +1395,18 @@ func (in *KubeFedConfigList) DeepCopyInto(out *KubeFedConfigList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeFedConfigList.
-func (in *KubeFedConfigList) DeepCopy() *KubeFedConfigList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PropagationQuotaList.
+func (in *PropagationQuotaList) DeepCopy() *PropagationQuotaList {
 	if in == nil {
 		return nil
 	}
-	out := new(KubeFedConfigList)
+	out := new(PropagationQuotaList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *KubeFedConfigList) DeepCopyObject() runtime.Object {
+func (in *PropagationQuotaList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -390,61 +1414,113 @@ func (in *KubeFedConfigList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KubeFedConfigSpec) DeepCopyInto(out *KubeFedConfigSpec) {
+func (in *PropagationQuotaSpec) DeepCopyInto(out *PropagationQuotaSpec) {
 	*out = *in
-	out.ControllerDuration = in.ControllerDuration
-	out.LeaderElect = in.LeaderElect
-	if in.FeatureGates != nil {
-		in, out := &in.FeatureGates, &out.FeatureGates
-		*out = make([]FeatureGatesConfig, len(*in))
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	out.ClusterHealthCheck = in.ClusterHealthCheck
-	out.SyncController = in.SyncController
+	if in.MaxFederatedResources != nil {
+		in, out := &in.MaxFederatedResources, &out.MaxFederatedResources
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaxPropagatedObjects != nil {
+		in, out := &in.MaxPropagatedObjects, &out.MaxPropagatedObjects
+		*out = new(int64)
+		**out = **in
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeFedConfigSpec.
-func (in *KubeFedConfigSpec) DeepCopy() *KubeFedConfigSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PropagationQuotaSpec.
+func (in *PropagationQuotaSpec) DeepCopy() *PropagationQuotaSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(KubeFedConfigSpec)
+	out := new(PropagationQuotaSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LeaderElectConfig) DeepCopyInto(out *LeaderElectConfig) {
+func (in *PropagationQuotaStatus) DeepCopyInto(out *PropagationQuotaStatus) {
 	*out = *in
-	out.LeaseDuration = in.LeaseDuration
-	out.RenewDeadline = in.RenewDeadline
-	out.RetryPeriod = in.RetryPeriod
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LeaderElectConfig.
-func (in *LeaderElectConfig) DeepCopy() *LeaderElectConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PropagationQuotaStatus.
+func (in *PropagationQuotaStatus) DeepCopy() *PropagationQuotaStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(LeaderElectConfig)
+	out := new(PropagationQuotaStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LocalSecretReference) DeepCopyInto(out *LocalSecretReference) {
+func (in *SchedulingConfig) DeepCopyInto(out *SchedulingConfig) {
 	*out = *in
+	if in.Profiles != nil {
+		in, out := &in.Profiles, &out.Profiles
+		*out = make([]SchedulingProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalSecretReference.
-func (in *LocalSecretReference) DeepCopy() *LocalSecretReference {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulingConfig.
+func (in *SchedulingConfig) DeepCopy() *SchedulingConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(LocalSecretReference)
+	out := new(SchedulingConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchedulingProfile) DeepCopyInto(out *SchedulingProfile) {
+	*out = *in
+	if in.Filters != nil {
+		in, out := &in.Filters, &out.Filters
+		*out = make([]SchedulingFilter, len(*in))
+		copy(*out, *in)
+	}
+	if in.Scorers != nil {
+		in, out := &in.Scorers, &out.Scorers
+		*out = make([]SchedulingScorer, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulingProfile.
+func (in *SchedulingProfile) DeepCopy() *SchedulingProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(SchedulingProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchedulingScorer) DeepCopyInto(out *SchedulingScorer) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulingScorer.
+func (in *SchedulingScorer) DeepCopy() *SchedulingScorer {
+	if in == nil {
+		return nil
+	}
+	out := new(SchedulingScorer)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -452,6 +1528,31 @@ func (in *LocalSecretReference) DeepCopy() *LocalSecretReference {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SyncControllerConfig) DeepCopyInto(out *SyncControllerConfig) {
 	*out = *in
+	if in.PropagationMetadata != nil {
+		in, out := &in.PropagationMetadata, &out.PropagationMetadata
+		*out = new(PropagationMetadataConfig)
+		**out = **in
+	}
+	if in.NamespaceMetadata != nil {
+		in, out := &in.NamespaceMetadata, &out.NamespaceMetadata
+		*out = new(NamespaceMetadataConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DeletionLimit != nil {
+		in, out := &in.DeletionLimit, &out.DeletionLimit
+		*out = new(DeletionLimitConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CircuitBreaker != nil {
+		in, out := &in.CircuitBreaker, &out.CircuitBreaker
+		*out = new(CircuitBreakerConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DispatchConcurrency != nil {
+		in, out := &in.DispatchConcurrency, &out.DispatchConcurrency
+		*out = new(DispatchConcurrencyConfig)
+		**out = **in
+	}
 	return
 }
 
@@ -464,3 +1565,19 @@ func (in *SyncControllerConfig) DeepCopy() *SyncControllerConfig {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ValuesConfig) DeepCopyInto(out *ValuesConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValuesConfig.
+func (in *ValuesConfig) DeepCopy() *ValuesConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ValuesConfig)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -30,6 +30,16 @@ type TTL int64
 // it is then stored in a persistent storage via serialization
 type Labels map[string]string
 
+// ProviderSpecificProperty holds the name and value of a configuration
+// which is specific to an individual DNS provider.
+type ProviderSpecificProperty struct {
+	Name  string `json:"name,omitempty"`
+	Value string `json:"value,omitempty"`
+}
+
+// ProviderSpecific holds configuration which is specific to individual DNS providers.
+type ProviderSpecific []ProviderSpecificProperty
+
 // Endpoint is a high-level association between a service and an IP.
 type Endpoint struct {
 	// The FQDN of the DNS record.
@@ -38,11 +48,18 @@ type Endpoint struct {
 	Targets Targets `json:"targets,omitempty"`
 	// RecordType type of record, e.g. CNAME, A, SRV, TXT etc.
 	RecordType string `json:"recordType,omitempty"`
+	// SetIdentifier distinguishes records sharing the same DNSName
+	// and RecordType, e.g. for weighted or geolocation routing.
+	// +optional
+	SetIdentifier string `json:"setIdentifier,omitempty"`
 	// TTL for the record in seconds.
 	RecordTTL TTL `json:"recordTTL,omitempty"`
 	// Labels stores labels defined for the Endpoint.
 	// +optional
 	Labels Labels `json:"labels,omitempty"`
+	// ProviderSpecific stores provider specific config.
+	// +optional
+	ProviderSpecific ProviderSpecific `json:"providerSpecific,omitempty"`
 }
 
 // DNSEndpointSpec defines the desired state of DNSEndpoint
@@ -34,6 +34,132 @@ type Domain struct {
 	Domain string `json:"domain"`
 	// NameServer is the authoritative DNS name server for the KubeFed domain
 	NameServer string `json:"nameServer,omitempty"`
+	// RecordTTL is the default TTL in seconds for DNS records created in
+	// the domain. It is used for records that do not specify a TTL.
+	RecordTTL TTL `json:"recordTTL,omitempty"`
+	// ProviderSpecific is the provider specific config added to all the
+	// DNS records created in the domain.
+	ProviderSpecific ProviderSpecific `json:"providerSpecific,omitempty"`
+	// Provider is the DNS provider in which KubeFed programs the
+	// records of the domain directly.  If omitted, the records are
+	// only written to DNSEndpoint objects for external-dns to consume.
+	// +optional
+	Provider *DNSProvider `json:"provider,omitempty"`
+	// RecordPolicy configures the type and TTL of the DNS records
+	// created in the domain for ServiceDNSRecord and IngressDNSRecord
+	// objects.
+	// +optional
+	RecordPolicy *RecordPolicy `json:"recordPolicy,omitempty"`
+}
+
+// RecordPolicy configures the DNS records created in a domain.
+type RecordPolicy struct {
+	// RecordTypes are the types of the records that are created, any
+	// of `A`, `AAAA` and `CNAME`.  A and AAAA records hold the IPv4 and
+	// IPv6 addresses of load balancers, and CNAME records point at the
+	// records of an upper level or of a DNS prefix.  Defaults to all.
+	// +optional
+	RecordTypes []string `json:"recordTypes,omitempty"`
+	// RecordTTLs are the default TTLs of the records per record type.
+	// A TTL that is not set defaults to the RecordTTL of the domain.
+	// +optional
+	RecordTTLs RecordTTLs `json:"recordTTLs,omitempty"`
+	// Overrides override the policy for the DNS names with a given
+	// suffix.  The override with the longest matching suffix applies.
+	// +optional
+	Overrides []RecordPolicyOverride `json:"overrides,omitempty"`
+}
+
+// RecordTTLs holds a TTL in seconds per record type.
+type RecordTTLs struct {
+	// +optional
+	A TTL `json:"A,omitempty"`
+	// +optional
+	AAAA TTL `json:"AAAA,omitempty"`
+	// +optional
+	CNAME TTL `json:"CNAME,omitempty"`
+}
+
+// RecordPolicyOverride overrides the record policy of a domain for the
+// DNS names with a given suffix.
+type RecordPolicyOverride struct {
+	// Suffix matches a DNS name that is equal to it or ends with a dot
+	// followed by it, e.g. `internal.example.com`.
+	Suffix string `json:"suffix"`
+	// RecordTypes, if set, replace the record types of the policy.
+	// +optional
+	RecordTypes []string `json:"recordTypes,omitempty"`
+	// RecordTTL, if set, is the TTL of the records of all types.
+	// +optional
+	RecordTTL TTL `json:"recordTTL,omitempty"`
+}
+
+// DNSProvider configures the DNS provider in which the records of a
+// domain are programmed.  Exactly one provider must be configured.
+type DNSProvider struct {
+	// CredentialsSecret is the name of the secret in the namespace of
+	// the Domain that holds the credentials of the provider.
+	// +optional
+	CredentialsSecret string `json:"credentialsSecret,omitempty"`
+	// +optional
+	Route53 *Route53Provider `json:"route53,omitempty"`
+	// +optional
+	CloudDNS *CloudDNSProvider `json:"cloudDNS,omitempty"`
+	// +optional
+	AzureDNS *AzureDNSProvider `json:"azureDNS,omitempty"`
+	// +optional
+	RFC2136 *RFC2136Provider `json:"rfc2136,omitempty"`
+}
+
+// Route53Provider configures an AWS Route53 hosted zone.  The
+// credentials secret holds the `aws_access_key_id`,
+// `aws_secret_access_key` and, optionally, `aws_session_token` keys.
+type Route53Provider struct {
+	// HostedZoneID is the ID of the hosted zone of the domain.
+	HostedZoneID string `json:"hostedZoneID"`
+}
+
+// CloudDNSProvider configures a Google Cloud DNS managed zone.  The
+// credentials secret holds the JSON key of a service account in the
+// `credentials.json` key.
+type CloudDNSProvider struct {
+	// Project is the ID of the project of the managed zone.
+	Project string `json:"project"`
+	// ManagedZone is the name of the managed zone of the domain.
+	ManagedZone string `json:"managedZone"`
+}
+
+// AzureDNSProvider configures an Azure DNS zone.  The credentials
+// secret holds the `tenantID`, `clientID` and `clientSecret` keys of a
+// service principal.
+type AzureDNSProvider struct {
+	// SubscriptionID is the ID of the subscription of the zone.
+	SubscriptionID string `json:"subscriptionID"`
+	// ResourceGroup is the resource group of the zone.
+	ResourceGroup string `json:"resourceGroup"`
+	// ZoneName is the name of the zone.  Defaults to the domain.
+	// +optional
+	ZoneName string `json:"zoneName,omitempty"`
+}
+
+// RFC2136Provider configures a name server accepting dynamic updates
+// as per RFC 2136.  If a TSIG key is configured, the credentials secret
+// holds the base64 encoded key in the `tsigSecret` key.
+type RFC2136Provider struct {
+	// Server is the address of the name server as host:port.
+	Server string `json:"server"`
+	// Zone is the zone that is updated.  Defaults to the domain.
+	// +optional
+	Zone string `json:"zone,omitempty"`
+	// TSIGKeyName is the name of the TSIG key that signs updates.
+	// Updates are not signed if omitted.
+	// +optional
+	TSIGKeyName string `json:"tsigKeyName,omitempty"`
+	// TSIGAlgorithm is the algorithm of the TSIG key, one of
+	// `hmac-sha256`, `hmac-sha512` or `hmac-sha1`.  Defaults to
+	// `hmac-sha256`.
+	// +optional
+	TSIGAlgorithm string `json:"tsigAlgorithm,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -23,10 +23,19 @@ import (
 
 // IngressDNSRecordSpec defines the desired state of IngressDNSRecord
 type IngressDNSRecordSpec struct {
-	// Host from the IngressRule in Cluster Ingress Spec
+	// Hosts for which DNS records are created, e.g. the hosts of the
+	// rules of the ingress.  Wildcard hosts (e.g. *.example.com) are
+	// supported.  If empty, records are created for all the hosts
+	// served by the ingress in member clusters.
 	Hosts []string `json:"hosts,omitempty"`
 	// RecordTTL is the TTL in seconds for DNS records created for the Ingress, if omitted a default would be used
 	RecordTTL TTL `json:"recordTTL,omitempty"`
+	// SetIdentifier when specified, is set on the DNS records created for the Ingress
+	// to distinguish them from the records of the same name managed by other sources.
+	SetIdentifier string `json:"setIdentifier,omitempty"`
+	// ProviderSpecific is the provider specific config added to the DNS records created
+	// for the Ingress.
+	ProviderSpecific ProviderSpecific `json:"providerSpecific,omitempty"`
 }
 
 // IngressDNSRecordStatus defines the observed state of IngressDNSRecord
@@ -41,6 +50,10 @@ type ClusterIngressDNS struct {
 	Cluster string `json:"cluster,omitempty"`
 	// LoadBalancer for the corresponding ingress controller
 	LoadBalancer corev1.LoadBalancerStatus `json:"loadBalancer,omitempty"`
+	// Hosts served by the ingress in the cluster, from its rules and
+	// TLS configuration.  The records of a host only target the
+	// clusters that serve it.
+	Hosts []string `json:"hosts,omitempty"`
 }
 
 // +genclient
@@ -34,13 +34,31 @@ type ServiceDNSRecordSpec struct {
 	ExternalName string `json:"externalName,omitempty"`
 	// AllowServiceWithoutEndpoints allows DNS records to be written for Service shards without endpoints
 	AllowServiceWithoutEndpoints bool `json:"allowServiceWithoutEndpoints,omitempty"`
+	// SetIdentifier when specified, is set on the DNS records created for this Service
+	// to distinguish them from the records of the same name managed by other sources.
+	SetIdentifier string `json:"setIdentifier,omitempty"`
+	// ProviderSpecific is the provider specific config added to the DNS records created
+	// for this Service, in addition to that of the domain.
+	ProviderSpecific ProviderSpecific `json:"providerSpecific,omitempty"`
+	// Regions when specified, restricts the DNS records to the Service shards of the
+	// clusters in the given regions.
+	Regions []string `json:"regions,omitempty"`
+	// Zones when specified, restricts the DNS records to the Service shards of the
+	// clusters in the given zones.  Only records for the given zones are created.
+	Zones []string `json:"zones,omitempty"`
 }
 
 // ServiceDNSRecordStatus defines the observed state of ServiceDNSRecord.
 type ServiceDNSRecordStatus struct {
 	// Domain is the DNS domain of the KubeFed control plane as in Domain API
-	Domain string       `json:"domain,omitempty"`
-	DNS    []ClusterDNS `json:"dns,omitempty"`
+	Domain string `json:"domain,omitempty"`
+	// RecordTTL is the default TTL of DNS records of the domain as in Domain API
+	RecordTTL TTL `json:"recordTTL,omitempty"`
+	// ProviderSpecific is the provider specific config of the domain as in Domain API
+	ProviderSpecific ProviderSpecific `json:"providerSpecific,omitempty"`
+	// DomainAnnotations are the external-dns annotations of the Domain object
+	DomainAnnotations map[string]string `json:"domainAnnotations,omitempty"`
+	DNS               []ClusterDNS      `json:"dns,omitempty"`
 }
 
 // ClusterDNS defines the observed status of LoadBalancer within a cluster.
@@ -24,6 +24,38 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureDNSProvider) DeepCopyInto(out *AzureDNSProvider) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureDNSProvider.
+func (in *AzureDNSProvider) DeepCopy() *AzureDNSProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureDNSProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudDNSProvider) DeepCopyInto(out *CloudDNSProvider) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudDNSProvider.
+func (in *CloudDNSProvider) DeepCopy() *CloudDNSProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudDNSProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterDNS) DeepCopyInto(out *ClusterDNS) {
 	*out = *in
@@ -50,6 +82,11 @@ func (in *ClusterDNS) DeepCopy() *ClusterDNS {
 func (in *ClusterIngressDNS) DeepCopyInto(out *ClusterIngressDNS) {
 	*out = *in
 	in.LoadBalancer.DeepCopyInto(&out.LoadBalancer)
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -167,11 +204,62 @@ func (in *DNSEndpointStatus) DeepCopy() *DNSEndpointStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DNSProvider) DeepCopyInto(out *DNSProvider) {
+	*out = *in
+	if in.Route53 != nil {
+		in, out := &in.Route53, &out.Route53
+		*out = new(Route53Provider)
+		**out = **in
+	}
+	if in.CloudDNS != nil {
+		in, out := &in.CloudDNS, &out.CloudDNS
+		*out = new(CloudDNSProvider)
+		**out = **in
+	}
+	if in.AzureDNS != nil {
+		in, out := &in.AzureDNS, &out.AzureDNS
+		*out = new(AzureDNSProvider)
+		**out = **in
+	}
+	if in.RFC2136 != nil {
+		in, out := &in.RFC2136, &out.RFC2136
+		*out = new(RFC2136Provider)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSProvider.
+func (in *DNSProvider) DeepCopy() *DNSProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(DNSProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Domain) DeepCopyInto(out *Domain) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.ProviderSpecific != nil {
+		in, out := &in.ProviderSpecific, &out.ProviderSpecific
+		*out = make(ProviderSpecific, len(*in))
+		copy(*out, *in)
+	}
+	if in.Provider != nil {
+		in, out := &in.Provider, &out.Provider
+		*out = new(DNSProvider)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RecordPolicy != nil {
+		in, out := &in.RecordPolicy, &out.RecordPolicy
+		*out = new(RecordPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -241,6 +329,11 @@ func (in *Endpoint) DeepCopyInto(out *Endpoint) {
 			(*out)[key] = val
 		}
 	}
+	if in.ProviderSpecific != nil {
+		in, out := &in.ProviderSpecific, &out.ProviderSpecific
+		*out = make(ProviderSpecific, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -323,6 +416,11 @@ func (in *IngressDNSRecordSpec) DeepCopyInto(out *IngressDNSRecordSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ProviderSpecific != nil {
+		in, out := &in.ProviderSpecific, &out.ProviderSpecific
+		*out = make(ProviderSpecific, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -381,12 +479,146 @@ func (in Labels) DeepCopy() Labels {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in ProviderSpecific) DeepCopyInto(out *ProviderSpecific) {
+	{
+		in := &in
+		*out = make(ProviderSpecific, len(*in))
+		copy(*out, *in)
+		return
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderSpecific.
+func (in ProviderSpecific) DeepCopy() ProviderSpecific {
+	if in == nil {
+		return nil
+	}
+	out := new(ProviderSpecific)
+	in.DeepCopyInto(out)
+	return *out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProviderSpecificProperty) DeepCopyInto(out *ProviderSpecificProperty) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderSpecificProperty.
+func (in *ProviderSpecificProperty) DeepCopy() *ProviderSpecificProperty {
+	if in == nil {
+		return nil
+	}
+	out := new(ProviderSpecificProperty)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RFC2136Provider) DeepCopyInto(out *RFC2136Provider) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RFC2136Provider.
+func (in *RFC2136Provider) DeepCopy() *RFC2136Provider {
+	if in == nil {
+		return nil
+	}
+	out := new(RFC2136Provider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecordPolicy) DeepCopyInto(out *RecordPolicy) {
+	*out = *in
+	if in.RecordTypes != nil {
+		in, out := &in.RecordTypes, &out.RecordTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	out.RecordTTLs = in.RecordTTLs
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = make([]RecordPolicyOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecordPolicy.
+func (in *RecordPolicy) DeepCopy() *RecordPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RecordPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecordPolicyOverride) DeepCopyInto(out *RecordPolicyOverride) {
+	*out = *in
+	if in.RecordTypes != nil {
+		in, out := &in.RecordTypes, &out.RecordTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecordPolicyOverride.
+func (in *RecordPolicyOverride) DeepCopy() *RecordPolicyOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(RecordPolicyOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecordTTLs) DeepCopyInto(out *RecordTTLs) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecordTTLs.
+func (in *RecordTTLs) DeepCopy() *RecordTTLs {
+	if in == nil {
+		return nil
+	}
+	out := new(RecordTTLs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Route53Provider) DeepCopyInto(out *Route53Provider) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Route53Provider.
+func (in *Route53Provider) DeepCopy() *Route53Provider {
+	if in == nil {
+		return nil
+	}
+	out := new(Route53Provider)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceDNSRecord) DeepCopyInto(out *ServiceDNSRecord) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 	return
 }
@@ -445,6 +677,21 @@ func (in *ServiceDNSRecordList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceDNSRecordSpec) DeepCopyInto(out *ServiceDNSRecordSpec) {
 	*out = *in
+	if in.ProviderSpecific != nil {
+		in, out := &in.ProviderSpecific, &out.ProviderSpecific
+		*out = make(ProviderSpecific, len(*in))
+		copy(*out, *in)
+	}
+	if in.Regions != nil {
+		in, out := &in.Regions, &out.Regions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -461,6 +708,18 @@ func (in *ServiceDNSRecordSpec) DeepCopy() *ServiceDNSRecordSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceDNSRecordStatus) DeepCopyInto(out *ServiceDNSRecordStatus) {
 	*out = *in
+	if in.ProviderSpecific != nil {
+		in, out := &in.ProviderSpecific, &out.ProviderSpecific
+		*out = make(ProviderSpecific, len(*in))
+		copy(*out, *in)
+	}
+	if in.DomainAnnotations != nil {
+		in, out := &in.DomainAnnotations, &out.DomainAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.DNS != nil {
 		in, out := &in.DNS, &out.DNS
 		*out = make([]ClusterDNS, len(*in))
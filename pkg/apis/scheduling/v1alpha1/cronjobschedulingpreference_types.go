@@ -0,0 +1,97 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CronJobExecutionMode determines which cluster runs the scheduled
+// occurrences of a federated cron job.
+type CronJobExecutionMode string
+
+const (
+	// PinnedExecution runs every occurrence in the first ready cluster
+	// of the preference, failing over to the next ready cluster.
+	PinnedExecution CronJobExecutionMode = "Pinned"
+	// RotatedExecution moves the execution to the next ready cluster
+	// of the preference after each occurrence.
+	RotatedExecution CronJobExecutionMode = "Rotated"
+)
+
+// CronJobSchedulingPreferenceSpec defines the desired state of CronJobSchedulingPreference
+type CronJobSchedulingPreferenceSpec struct {
+	// TargetKind is the kind of the federated cron job targeted by the
+	// preference (FederatedCronJob).  As for a ReplicaSchedulingPreference,
+	// the preference applies to the target resource with the same namespace
+	// and name.
+	TargetKind string `json:"targetKind"`
+
+	// Mode determines which cluster runs the scheduled occurrences of the
+	// cron job, Pinned (default) or Rotated.
+	// +optional
+	Mode CronJobExecutionMode `json:"mode,omitempty"`
+
+	// Clusters are the names of the clusters the cron job is placed in, in
+	// order of preference.  The cron job is suspended in all of them except
+	// the one that runs its occurrences.  If omitted, the cron job is placed
+	// in all clusters, ordered by name.
+	// +optional
+	Clusters []string `json:"clusters,omitempty"`
+}
+
+// CronJobSchedulingPreferenceStatus defines the observed state of CronJobSchedulingPreference
+type CronJobSchedulingPreferenceStatus struct {
+	// ActiveCluster is the name of the cluster in which the cron job is
+	// not suspended.
+	// +optional
+	ActiveCluster string `json:"activeCluster,omitempty"`
+
+	// LastScheduleTime is the last time the cron job was scheduled in the
+	// active cluster when the cluster became active.  A later schedule time
+	// indicates that the active cluster has run an occurrence.
+	// +optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CronJobSchedulingPreference
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:path=cronjobschedulingpreferences
+// +kubebuilder:subresource:status
+type CronJobSchedulingPreference struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CronJobSchedulingPreferenceSpec   `json:"spec,omitempty"`
+	Status CronJobSchedulingPreferenceStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CronJobSchedulingPreferenceList contains a list of CronJobSchedulingPreference
+type CronJobSchedulingPreferenceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CronJobSchedulingPreference `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CronJobSchedulingPreference{}, &CronJobSchedulingPreferenceList{})
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// JobSchedulingPreferenceSpec defines the desired state of JobSchedulingPreference
+type JobSchedulingPreferenceSpec struct {
+	// TargetKind is the kind of the federated job targeted by the preference
+	// (FederatedJob).  As for a ReplicaSchedulingPreference, the preference
+	// applies to the target resource with the same namespace and name.
+	TargetKind string `json:"targetKind"`
+
+	// Total number of successful completions desired across federated clusters.
+	// Completions specified in the spec of the job template are overridden for
+	// the clusters the job is scheduled to.  Since the completions of a job
+	// cannot be changed, completions already assigned to a cluster are never
+	// moved to another cluster.
+	TotalCompletions int32 `json:"totalCompletions"`
+
+	// Total number of pods desired to run in parallel across federated clusters.
+	// Defaults to TotalCompletions.  A cluster never runs more pods in parallel
+	// than the completions assigned to it, and always runs at least one pod
+	// while it has completions assigned.
+	// +optional
+	TotalParallelism *int32 `json:"totalParallelism,omitempty"`
+
+	// A mapping between cluster names and preferences regarding the completions
+	// assigned to the job in these clusters.  MinReplicas and MaxReplicas bound
+	// the completions assigned to a cluster.
+	// "*" (if provided) applies to all clusters if an explicit mapping is not provided.
+	// If omitted, completions are spread evenly across clusters.
+	// +optional
+	Clusters map[string]ClusterPreferences `json:"clusters,omitempty"`
+
+	// Tolerations of the taints of clusters.  Completions are not scheduled to
+	// clusters with a taint with the NoSchedule or NoExecute effect that is not
+	// tolerated.
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+}
+
+// JobSchedulingPreferenceStatus defines the observed state of JobSchedulingPreference
+type JobSchedulingPreferenceStatus struct {
+	// Number of pods of the job that are running across clusters.
+	// +optional
+	Active int32 `json:"active,omitempty"`
+
+	// Number of pods of the job that completed successfully across clusters.
+	// +optional
+	Succeeded int32 `json:"succeeded,omitempty"`
+
+	// Number of pods of the job that failed across clusters.
+	// +optional
+	Failed int32 `json:"failed,omitempty"`
+
+	// Complete is true once the successful completions of the job across
+	// clusters have reached TotalCompletions.
+	// +optional
+	Complete bool `json:"complete,omitempty"`
+
+	// Status of the job in each cluster it is scheduled to.
+	// +optional
+	Clusters []JobClusterStatus `json:"clusters,omitempty"`
+}
+
+// JobClusterStatus is the status of a job in a member cluster.
+type JobClusterStatus struct {
+	// Name of the cluster.
+	Name string `json:"name"`
+
+	// Completions assigned to the job in the cluster.
+	Completions int32 `json:"completions"`
+
+	// Parallelism assigned to the job in the cluster.
+	// +optional
+	Parallelism int32 `json:"parallelism,omitempty"`
+
+	// Number of pods of the job that are running in the cluster.
+	// +optional
+	Active int32 `json:"active,omitempty"`
+
+	// Number of pods of the job that completed successfully in the cluster.
+	// +optional
+	Succeeded int32 `json:"succeeded,omitempty"`
+
+	// Number of pods of the job that failed in the cluster.
+	// +optional
+	Failed int32 `json:"failed,omitempty"`
+
+	// Condition is the type of the finished condition of the job in the cluster
+	// (Complete or Failed), or empty if the job has not finished.
+	// +optional
+	Condition batchv1.JobConditionType `json:"condition,omitempty"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// JobSchedulingPreference
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:path=jobschedulingpreferences
+// +kubebuilder:subresource:status
+type JobSchedulingPreference struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   JobSchedulingPreferenceSpec   `json:"spec,omitempty"`
+	Status JobSchedulingPreferenceStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// JobSchedulingPreferenceList contains a list of JobSchedulingPreference
+type JobSchedulingPreferenceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []JobSchedulingPreference `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&JobSchedulingPreference{}, &JobSchedulingPreferenceList{})
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -51,6 +52,11 @@ type ReplicaSchedulingPreferenceSpec struct {
 	// If omitted, clusters without explicit preferences should not have any replicas scheduled.
 	// +optional
 	Clusters map[string]ClusterPreferences `json:"clusters,omitempty"`
+
+	// Tolerations of the taints of clusters.  Replicas are not scheduled to clusters
+	// with a taint with the NoSchedule or NoExecute effect that is not tolerated.
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
 }
 
 // Preferences regarding number of replicas assigned to a cluster workload object (dep, rs, ..) within
@@ -68,6 +74,12 @@ type ClusterPreferences struct {
 	// A number expressing the preference to put an additional replica to this cluster workload object.
 	// 0 by default.
 	Weight int64 `json:"weight,omitempty"`
+
+	// The key of a label of the KubeFedCluster resource whose value is used as the weight
+	// of this cluster workload object. Weight is used if the cluster does not have the label
+	// or if its value is not a non-negative integer.
+	// +optional
+	WeightFromLabel string `json:"weightFromLabel,omitempty"`
 }
 
 // ReplicaSchedulingPreferenceStatus defines the observed state of ReplicaSchedulingPreference
@@ -0,0 +1,174 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	"time"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+	v1beta1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	scheme "sigs.k8s.io/kubefed/pkg/client/clientset/versioned/scheme"
+)
+
+// DispatchPoliciesGetter has a method to return a DispatchPolicyInterface.
+// A group's client should implement this interface.
+type DispatchPoliciesGetter interface {
+	DispatchPolicies(namespace string) DispatchPolicyInterface
+}
+
+// DispatchPolicyInterface has methods to work with DispatchPolicy resources.
+type DispatchPolicyInterface interface {
+	Create(*v1beta1.DispatchPolicy) (*v1beta1.DispatchPolicy, error)
+	Update(*v1beta1.DispatchPolicy) (*v1beta1.DispatchPolicy, error)
+	Delete(name string, options *v1.DeleteOptions) error
+	DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error
+	Get(name string, options v1.GetOptions) (*v1beta1.DispatchPolicy, error)
+	List(opts v1.ListOptions) (*v1beta1.DispatchPolicyList, error)
+	Watch(opts v1.ListOptions) (watch.Interface, error)
+	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1beta1.DispatchPolicy, err error)
+	DispatchPolicyExpansion
+}
+
+// dispatchPolicies implements DispatchPolicyInterface
+type dispatchPolicies struct {
+	client rest.Interface
+	ns     string
+}
+
+// newDispatchPolicies returns a DispatchPolicies
+func newDispatchPolicies(c *CoreV1beta1Client, namespace string) *dispatchPolicies {
+	return &dispatchPolicies{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the dispatchPolicy, and returns the corresponding dispatchPolicy object, and an error if there is any.
+func (c *dispatchPolicies) Get(name string, options v1.GetOptions) (result *v1beta1.DispatchPolicy, err error) {
+	result = &v1beta1.DispatchPolicy{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("dispatchpolicies").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do().
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of DispatchPolicies that match those selectors.
+func (c *dispatchPolicies) List(opts v1.ListOptions) (result *v1beta1.DispatchPolicyList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1beta1.DispatchPolicyList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("dispatchpolicies").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do().
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested dispatchPolicies.
+func (c *dispatchPolicies) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("dispatchpolicies").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch()
+}
+
+// Create takes the representation of a dispatchPolicy and creates it.  Returns the server's representation of the dispatchPolicy, and an error, if there is any.
+func (c *dispatchPolicies) Create(dispatchPolicy *v1beta1.DispatchPolicy) (result *v1beta1.DispatchPolicy, err error) {
+	result = &v1beta1.DispatchPolicy{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("dispatchpolicies").
+		Body(dispatchPolicy).
+		Do().
+		Into(result)
+	return
+}
+
+// Update takes the representation of a dispatchPolicy and updates it. Returns the server's representation of the dispatchPolicy, and an error, if there is any.
+func (c *dispatchPolicies) Update(dispatchPolicy *v1beta1.DispatchPolicy) (result *v1beta1.DispatchPolicy, err error) {
+	result = &v1beta1.DispatchPolicy{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("dispatchpolicies").
+		Name(dispatchPolicy.Name).
+		Body(dispatchPolicy).
+		Do().
+		Into(result)
+	return
+}
+
+// Delete takes name of the dispatchPolicy and deletes it. Returns an error if one occurs.
+func (c *dispatchPolicies) Delete(name string, options *v1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("dispatchpolicies").
+		Name(name).
+		Body(options).
+		Do().
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *dispatchPolicies) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	var timeout time.Duration
+	if listOptions.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOptions.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("dispatchpolicies").
+		VersionedParams(&listOptions, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(options).
+		Do().
+		Error()
+}
+
+// Patch applies the patch and returns the patched dispatchPolicy.
+func (c *dispatchPolicies) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1beta1.DispatchPolicy, err error) {
+	result = &v1beta1.DispatchPolicy{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("dispatchpolicies").
+		SubResource(subresources...).
+		Name(name).
+		Body(data).
+		Do().
+		Into(result)
+	return
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	rest "k8s.io/client-go/rest"
+	testing "k8s.io/client-go/testing"
+	v1beta1 "sigs.k8s.io/kubefed/pkg/client/clientset/versioned/typed/core/v1beta1"
+)
+
+type FakeCoreV1beta1 struct {
+	*testing.Fake
+}
+
+func (c *FakeCoreV1beta1) DispatchPolicies(namespace string) v1beta1.DispatchPolicyInterface {
+	return &FakeDispatchPolicies{c, namespace}
+}
+
+func (c *FakeCoreV1beta1) FederatedTypeConfigs(namespace string) v1beta1.FederatedTypeConfigInterface {
+	return &FakeFederatedTypeConfigs{c, namespace}
+}
+
+func (c *FakeCoreV1beta1) KubeFedClusters(namespace string) v1beta1.KubeFedClusterInterface {
+	return &FakeKubeFedClusters{c, namespace}
+}
+
+func (c *FakeCoreV1beta1) KubeFedConfigs(namespace string) v1beta1.KubeFedConfigInterface {
+	return &FakeKubeFedConfigs{c, namespace}
+}
+
+func (c *FakeCoreV1beta1) PlacementPolicies(namespace string) v1beta1.PlacementPolicyInterface {
+	return &FakePlacementPolicies{c, namespace}
+}
+
+func (c *FakeCoreV1beta1) PropagationQuotas(namespace string) v1beta1.PropagationQuotaInterface {
+	return &FakePropagationQuotas{c, namespace}
+}
+
+// RESTClient returns a RESTClient that is used to communicate
+// with API server by this client implementation.
+func (c *FakeCoreV1beta1) RESTClient() rest.Interface {
+	var ret *rest.RESTClient
+	return ret
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+	v1beta1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+// FakeDispatchPolicies implements DispatchPolicyInterface
+type FakeDispatchPolicies struct {
+	Fake *FakeCoreV1beta1
+	ns   string
+}
+
+var dispatchpoliciesResource = schema.GroupVersionResource{Group: "core.kubefed.k8s.io", Version: "v1beta1", Resource: "dispatchpolicies"}
+
+var dispatchpoliciesKind = schema.GroupVersionKind{Group: "core.kubefed.k8s.io", Version: "v1beta1", Kind: "DispatchPolicy"}
+
+// Get takes name of the dispatchPolicy, and returns the corresponding dispatchPolicy object, and an error if there is any.
+func (c *FakeDispatchPolicies) Get(name string, options v1.GetOptions) (result *v1beta1.DispatchPolicy, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(dispatchpoliciesResource, c.ns, name), &v1beta1.DispatchPolicy{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.DispatchPolicy), err
+}
+
+// List takes label and field selectors, and returns the list of DispatchPolicies that match those selectors.
+func (c *FakeDispatchPolicies) List(opts v1.ListOptions) (result *v1beta1.DispatchPolicyList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(dispatchpoliciesResource, dispatchpoliciesKind, c.ns, opts), &v1beta1.DispatchPolicyList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1beta1.DispatchPolicyList{ListMeta: obj.(*v1beta1.DispatchPolicyList).ListMeta}
+	for _, item := range obj.(*v1beta1.DispatchPolicyList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested dispatchPolicies.
+func (c *FakeDispatchPolicies) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(dispatchpoliciesResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a dispatchPolicy and creates it.  Returns the server's representation of the dispatchPolicy, and an error, if there is any.
+func (c *FakeDispatchPolicies) Create(dispatchPolicy *v1beta1.DispatchPolicy) (result *v1beta1.DispatchPolicy, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(dispatchpoliciesResource, c.ns, dispatchPolicy), &v1beta1.DispatchPolicy{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.DispatchPolicy), err
+}
+
+// Update takes the representation of a dispatchPolicy and updates it. Returns the server's representation of the dispatchPolicy, and an error, if there is any.
+func (c *FakeDispatchPolicies) Update(dispatchPolicy *v1beta1.DispatchPolicy) (result *v1beta1.DispatchPolicy, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(dispatchpoliciesResource, c.ns, dispatchPolicy), &v1beta1.DispatchPolicy{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.DispatchPolicy), err
+}
+
+// Delete takes name of the dispatchPolicy and deletes it. Returns an error if one occurs.
+func (c *FakeDispatchPolicies) Delete(name string, options *v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(dispatchpoliciesResource, c.ns, name), &v1beta1.DispatchPolicy{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeDispatchPolicies) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(dispatchpoliciesResource, c.ns, listOptions)
+
+	_, err := c.Fake.Invokes(action, &v1beta1.DispatchPolicyList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched dispatchPolicy.
+func (c *FakeDispatchPolicies) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1beta1.DispatchPolicy, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(dispatchpoliciesResource, c.ns, name, pt, data, subresources...), &v1beta1.DispatchPolicy{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.DispatchPolicy), err
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+	v1beta1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+// FakeKubeFedConfigs implements KubeFedConfigInterface
+type FakeKubeFedConfigs struct {
+	Fake *FakeCoreV1beta1
+	ns   string
+}
+
+var kubefedconfigsResource = schema.GroupVersionResource{Group: "core.kubefed.k8s.io", Version: "v1beta1", Resource: "kubefedconfigs"}
+
+var kubefedconfigsKind = schema.GroupVersionKind{Group: "core.kubefed.k8s.io", Version: "v1beta1", Kind: "KubeFedConfig"}
+
+// Get takes name of the kubeFedConfig, and returns the corresponding kubeFedConfig object, and an error if there is any.
+func (c *FakeKubeFedConfigs) Get(name string, options v1.GetOptions) (result *v1beta1.KubeFedConfig, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(kubefedconfigsResource, c.ns, name), &v1beta1.KubeFedConfig{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.KubeFedConfig), err
+}
+
+// List takes label and field selectors, and returns the list of KubeFedConfigs that match those selectors.
+func (c *FakeKubeFedConfigs) List(opts v1.ListOptions) (result *v1beta1.KubeFedConfigList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(kubefedconfigsResource, kubefedconfigsKind, c.ns, opts), &v1beta1.KubeFedConfigList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1beta1.KubeFedConfigList{ListMeta: obj.(*v1beta1.KubeFedConfigList).ListMeta}
+	for _, item := range obj.(*v1beta1.KubeFedConfigList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested kubeFedConfigs.
+func (c *FakeKubeFedConfigs) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(kubefedconfigsResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a kubeFedConfig and creates it.  Returns the server's representation of the kubeFedConfig, and an error, if there is any.
+func (c *FakeKubeFedConfigs) Create(kubeFedConfig *v1beta1.KubeFedConfig) (result *v1beta1.KubeFedConfig, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(kubefedconfigsResource, c.ns, kubeFedConfig), &v1beta1.KubeFedConfig{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.KubeFedConfig), err
+}
+
+// Update takes the representation of a kubeFedConfig and updates it. Returns the server's representation of the kubeFedConfig, and an error, if there is any.
+func (c *FakeKubeFedConfigs) Update(kubeFedConfig *v1beta1.KubeFedConfig) (result *v1beta1.KubeFedConfig, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(kubefedconfigsResource, c.ns, kubeFedConfig), &v1beta1.KubeFedConfig{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.KubeFedConfig), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeKubeFedConfigs) UpdateStatus(kubeFedConfig *v1beta1.KubeFedConfig) (*v1beta1.KubeFedConfig, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(kubefedconfigsResource, "status", c.ns, kubeFedConfig), &v1beta1.KubeFedConfig{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.KubeFedConfig), err
+}
+
+// Delete takes name of the kubeFedConfig and deletes it. Returns an error if one occurs.
+func (c *FakeKubeFedConfigs) Delete(name string, options *v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(kubefedconfigsResource, c.ns, name), &v1beta1.KubeFedConfig{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeKubeFedConfigs) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(kubefedconfigsResource, c.ns, listOptions)
+
+	_, err := c.Fake.Invokes(action, &v1beta1.KubeFedConfigList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched kubeFedConfig.
+func (c *FakeKubeFedConfigs) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1beta1.KubeFedConfig, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(kubefedconfigsResource, c.ns, name, pt, data, subresources...), &v1beta1.KubeFedConfig{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.KubeFedConfig), err
+}
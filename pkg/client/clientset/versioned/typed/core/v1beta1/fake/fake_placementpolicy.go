@@ -0,0 +1,128 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+	v1beta1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+// FakePlacementPolicies implements PlacementPolicyInterface
+type FakePlacementPolicies struct {
+	Fake *FakeCoreV1beta1
+	ns   string
+}
+
+var placementpoliciesResource = schema.GroupVersionResource{Group: "core.kubefed.k8s.io", Version: "v1beta1", Resource: "placementpolicies"}
+
+var placementpoliciesKind = schema.GroupVersionKind{Group: "core.kubefed.k8s.io", Version: "v1beta1", Kind: "PlacementPolicy"}
+
+// Get takes name of the placementPolicy, and returns the corresponding placementPolicy object, and an error if there is any.
+func (c *FakePlacementPolicies) Get(name string, options v1.GetOptions) (result *v1beta1.PlacementPolicy, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(placementpoliciesResource, c.ns, name), &v1beta1.PlacementPolicy{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.PlacementPolicy), err
+}
+
+// List takes label and field selectors, and returns the list of PlacementPolicies that match those selectors.
+func (c *FakePlacementPolicies) List(opts v1.ListOptions) (result *v1beta1.PlacementPolicyList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(placementpoliciesResource, placementpoliciesKind, c.ns, opts), &v1beta1.PlacementPolicyList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1beta1.PlacementPolicyList{ListMeta: obj.(*v1beta1.PlacementPolicyList).ListMeta}
+	for _, item := range obj.(*v1beta1.PlacementPolicyList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested placementPolicies.
+func (c *FakePlacementPolicies) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(placementpoliciesResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a placementPolicy and creates it.  Returns the server's representation of the placementPolicy, and an error, if there is any.
+func (c *FakePlacementPolicies) Create(placementPolicy *v1beta1.PlacementPolicy) (result *v1beta1.PlacementPolicy, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(placementpoliciesResource, c.ns, placementPolicy), &v1beta1.PlacementPolicy{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.PlacementPolicy), err
+}
+
+// Update takes the representation of a placementPolicy and updates it. Returns the server's representation of the placementPolicy, and an error, if there is any.
+func (c *FakePlacementPolicies) Update(placementPolicy *v1beta1.PlacementPolicy) (result *v1beta1.PlacementPolicy, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(placementpoliciesResource, c.ns, placementPolicy), &v1beta1.PlacementPolicy{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.PlacementPolicy), err
+}
+
+// Delete takes name of the placementPolicy and deletes it. Returns an error if one occurs.
+func (c *FakePlacementPolicies) Delete(name string, options *v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(placementpoliciesResource, c.ns, name), &v1beta1.PlacementPolicy{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakePlacementPolicies) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(placementpoliciesResource, c.ns, listOptions)
+
+	_, err := c.Fake.Invokes(action, &v1beta1.PlacementPolicyList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched placementPolicy.
+func (c *FakePlacementPolicies) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1beta1.PlacementPolicy, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(placementpoliciesResource, c.ns, name, pt, data, subresources...), &v1beta1.PlacementPolicy{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.PlacementPolicy), err
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+	v1beta1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+// FakePropagationQuotas implements PropagationQuotaInterface
+type FakePropagationQuotas struct {
+	Fake *FakeCoreV1beta1
+	ns   string
+}
+
+var propagationquotasResource = schema.GroupVersionResource{Group: "core.kubefed.k8s.io", Version: "v1beta1", Resource: "propagationquotas"}
+
+var propagationquotasKind = schema.GroupVersionKind{Group: "core.kubefed.k8s.io", Version: "v1beta1", Kind: "PropagationQuota"}
+
+// Get takes name of the propagationQuota, and returns the corresponding propagationQuota object, and an error if there is any.
+func (c *FakePropagationQuotas) Get(name string, options v1.GetOptions) (result *v1beta1.PropagationQuota, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(propagationquotasResource, c.ns, name), &v1beta1.PropagationQuota{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.PropagationQuota), err
+}
+
+// List takes label and field selectors, and returns the list of PropagationQuotas that match those selectors.
+func (c *FakePropagationQuotas) List(opts v1.ListOptions) (result *v1beta1.PropagationQuotaList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(propagationquotasResource, propagationquotasKind, c.ns, opts), &v1beta1.PropagationQuotaList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1beta1.PropagationQuotaList{ListMeta: obj.(*v1beta1.PropagationQuotaList).ListMeta}
+	for _, item := range obj.(*v1beta1.PropagationQuotaList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested propagationQuotas.
+func (c *FakePropagationQuotas) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(propagationquotasResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a propagationQuota and creates it.  Returns the server's representation of the propagationQuota, and an error, if there is any.
+func (c *FakePropagationQuotas) Create(propagationQuota *v1beta1.PropagationQuota) (result *v1beta1.PropagationQuota, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(propagationquotasResource, c.ns, propagationQuota), &v1beta1.PropagationQuota{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.PropagationQuota), err
+}
+
+// Update takes the representation of a propagationQuota and updates it. Returns the server's representation of the propagationQuota, and an error, if there is any.
+func (c *FakePropagationQuotas) Update(propagationQuota *v1beta1.PropagationQuota) (result *v1beta1.PropagationQuota, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(propagationquotasResource, c.ns, propagationQuota), &v1beta1.PropagationQuota{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.PropagationQuota), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakePropagationQuotas) UpdateStatus(propagationQuota *v1beta1.PropagationQuota) (*v1beta1.PropagationQuota, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(propagationquotasResource, "status", c.ns, propagationQuota), &v1beta1.PropagationQuota{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.PropagationQuota), err
+}
+
+// Delete takes name of the propagationQuota and deletes it. Returns an error if one occurs.
+func (c *FakePropagationQuotas) Delete(name string, options *v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(propagationquotasResource, c.ns, name), &v1beta1.PropagationQuota{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakePropagationQuotas) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(propagationquotasResource, c.ns, listOptions)
+
+	_, err := c.Fake.Invokes(action, &v1beta1.PropagationQuotaList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched propagationQuota.
+func (c *FakePropagationQuotas) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1beta1.PropagationQuota, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(propagationquotasResource, c.ns, name, pt, data, subresources...), &v1beta1.PropagationQuota{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.PropagationQuota), err
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	"time"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+	v1beta1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	scheme "sigs.k8s.io/kubefed/pkg/client/clientset/versioned/scheme"
+)
+
+// PlacementPoliciesGetter has a method to return a PlacementPolicyInterface.
+// A group's client should implement this interface.
+type PlacementPoliciesGetter interface {
+	PlacementPolicies(namespace string) PlacementPolicyInterface
+}
+
+// PlacementPolicyInterface has methods to work with PlacementPolicy resources.
+type PlacementPolicyInterface interface {
+	Create(*v1beta1.PlacementPolicy) (*v1beta1.PlacementPolicy, error)
+	Update(*v1beta1.PlacementPolicy) (*v1beta1.PlacementPolicy, error)
+	Delete(name string, options *v1.DeleteOptions) error
+	DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error
+	Get(name string, options v1.GetOptions) (*v1beta1.PlacementPolicy, error)
+	List(opts v1.ListOptions) (*v1beta1.PlacementPolicyList, error)
+	Watch(opts v1.ListOptions) (watch.Interface, error)
+	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1beta1.PlacementPolicy, err error)
+	PlacementPolicyExpansion
+}
+
+// placementPolicies implements PlacementPolicyInterface
+type placementPolicies struct {
+	client rest.Interface
+	ns     string
+}
+
+// newPlacementPolicies returns a PlacementPolicies
+func newPlacementPolicies(c *CoreV1beta1Client, namespace string) *placementPolicies {
+	return &placementPolicies{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the placementPolicy, and returns the corresponding placementPolicy object, and an error if there is any.
+func (c *placementPolicies) Get(name string, options v1.GetOptions) (result *v1beta1.PlacementPolicy, err error) {
+	result = &v1beta1.PlacementPolicy{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("placementpolicies").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do().
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of PlacementPolicies that match those selectors.
+func (c *placementPolicies) List(opts v1.ListOptions) (result *v1beta1.PlacementPolicyList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1beta1.PlacementPolicyList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("placementpolicies").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do().
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested placementPolicies.
+func (c *placementPolicies) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("placementpolicies").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch()
+}
+
+// Create takes the representation of a placementPolicy and creates it.  Returns the server's representation of the placementPolicy, and an error, if there is any.
+func (c *placementPolicies) Create(placementPolicy *v1beta1.PlacementPolicy) (result *v1beta1.PlacementPolicy, err error) {
+	result = &v1beta1.PlacementPolicy{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("placementpolicies").
+		Body(placementPolicy).
+		Do().
+		Into(result)
+	return
+}
+
+// Update takes the representation of a placementPolicy and updates it. Returns the server's representation of the placementPolicy, and an error, if there is any.
+func (c *placementPolicies) Update(placementPolicy *v1beta1.PlacementPolicy) (result *v1beta1.PlacementPolicy, err error) {
+	result = &v1beta1.PlacementPolicy{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("placementpolicies").
+		Name(placementPolicy.Name).
+		Body(placementPolicy).
+		Do().
+		Into(result)
+	return
+}
+
+// Delete takes name of the placementPolicy and deletes it. Returns an error if one occurs.
+func (c *placementPolicies) Delete(name string, options *v1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("placementpolicies").
+		Name(name).
+		Body(options).
+		Do().
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *placementPolicies) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	var timeout time.Duration
+	if listOptions.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOptions.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("placementpolicies").
+		VersionedParams(&listOptions, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(options).
+		Do().
+		Error()
+}
+
+// Patch applies the patch and returns the patched placementPolicy.
+func (c *placementPolicies) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1beta1.PlacementPolicy, err error) {
+	result = &v1beta1.PlacementPolicy{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("placementpolicies").
+		SubResource(subresources...).
+		Name(name).
+		Body(data).
+		Do().
+		Into(result)
+	return
+}
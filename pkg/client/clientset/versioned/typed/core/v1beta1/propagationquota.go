@@ -0,0 +1,191 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	"time"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+	v1beta1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	scheme "sigs.k8s.io/kubefed/pkg/client/clientset/versioned/scheme"
+)
+
+// PropagationQuotasGetter has a method to return a PropagationQuotaInterface.
+// A group's client should implement this interface.
+type PropagationQuotasGetter interface {
+	PropagationQuotas(namespace string) PropagationQuotaInterface
+}
+
+// PropagationQuotaInterface has methods to work with PropagationQuota resources.
+type PropagationQuotaInterface interface {
+	Create(*v1beta1.PropagationQuota) (*v1beta1.PropagationQuota, error)
+	Update(*v1beta1.PropagationQuota) (*v1beta1.PropagationQuota, error)
+	UpdateStatus(*v1beta1.PropagationQuota) (*v1beta1.PropagationQuota, error)
+	Delete(name string, options *v1.DeleteOptions) error
+	DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error
+	Get(name string, options v1.GetOptions) (*v1beta1.PropagationQuota, error)
+	List(opts v1.ListOptions) (*v1beta1.PropagationQuotaList, error)
+	Watch(opts v1.ListOptions) (watch.Interface, error)
+	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1beta1.PropagationQuota, err error)
+	PropagationQuotaExpansion
+}
+
+// propagationQuotas implements PropagationQuotaInterface
+type propagationQuotas struct {
+	client rest.Interface
+	ns     string
+}
+
+// newPropagationQuotas returns a PropagationQuotas
+func newPropagationQuotas(c *CoreV1beta1Client, namespace string) *propagationQuotas {
+	return &propagationQuotas{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the propagationQuota, and returns the corresponding propagationQuota object, and an error if there is any.
+func (c *propagationQuotas) Get(name string, options v1.GetOptions) (result *v1beta1.PropagationQuota, err error) {
+	result = &v1beta1.PropagationQuota{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("propagationquotas").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do().
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of PropagationQuotas that match those selectors.
+func (c *propagationQuotas) List(opts v1.ListOptions) (result *v1beta1.PropagationQuotaList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1beta1.PropagationQuotaList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("propagationquotas").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do().
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested propagationQuotas.
+func (c *propagationQuotas) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("propagationquotas").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch()
+}
+
+// Create takes the representation of a propagationQuota and creates it.  Returns the server's representation of the propagationQuota, and an error, if there is any.
+func (c *propagationQuotas) Create(propagationQuota *v1beta1.PropagationQuota) (result *v1beta1.PropagationQuota, err error) {
+	result = &v1beta1.PropagationQuota{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("propagationquotas").
+		Body(propagationQuota).
+		Do().
+		Into(result)
+	return
+}
+
+// Update takes the representation of a propagationQuota and updates it. Returns the server's representation of the propagationQuota, and an error, if there is any.
+func (c *propagationQuotas) Update(propagationQuota *v1beta1.PropagationQuota) (result *v1beta1.PropagationQuota, err error) {
+	result = &v1beta1.PropagationQuota{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("propagationquotas").
+		Name(propagationQuota.Name).
+		Body(propagationQuota).
+		Do().
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+
+func (c *propagationQuotas) UpdateStatus(propagationQuota *v1beta1.PropagationQuota) (result *v1beta1.PropagationQuota, err error) {
+	result = &v1beta1.PropagationQuota{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("propagationquotas").
+		Name(propagationQuota.Name).
+		SubResource("status").
+		Body(propagationQuota).
+		Do().
+		Into(result)
+	return
+}
+
+// Delete takes name of the propagationQuota and deletes it. Returns an error if one occurs.
+func (c *propagationQuotas) Delete(name string, options *v1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("propagationquotas").
+		Name(name).
+		Body(options).
+		Do().
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *propagationQuotas) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	var timeout time.Duration
+	if listOptions.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOptions.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("propagationquotas").
+		VersionedParams(&listOptions, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(options).
+		Do().
+		Error()
+}
+
+// Patch applies the patch and returns the patched propagationQuota.
+func (c *propagationQuotas) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1beta1.PropagationQuota, err error) {
+	result = &v1beta1.PropagationQuota{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("propagationquotas").
+		SubResource(subresources...).
+		Name(name).
+		Body(data).
+		Do().
+		Into(result)
+	return
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"time"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+	v1alpha1 "sigs.k8s.io/kubefed/pkg/apis/scheduling/v1alpha1"
+	scheme "sigs.k8s.io/kubefed/pkg/client/clientset/versioned/scheme"
+)
+
+// CronJobSchedulingPreferencesGetter has a method to return a CronJobSchedulingPreferenceInterface.
+// A group's client should implement this interface.
+type CronJobSchedulingPreferencesGetter interface {
+	CronJobSchedulingPreferences(namespace string) CronJobSchedulingPreferenceInterface
+}
+
+// CronJobSchedulingPreferenceInterface has methods to work with CronJobSchedulingPreference resources.
+type CronJobSchedulingPreferenceInterface interface {
+	Create(*v1alpha1.CronJobSchedulingPreference) (*v1alpha1.CronJobSchedulingPreference, error)
+	Update(*v1alpha1.CronJobSchedulingPreference) (*v1alpha1.CronJobSchedulingPreference, error)
+	UpdateStatus(*v1alpha1.CronJobSchedulingPreference) (*v1alpha1.CronJobSchedulingPreference, error)
+	Delete(name string, options *v1.DeleteOptions) error
+	DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error
+	Get(name string, options v1.GetOptions) (*v1alpha1.CronJobSchedulingPreference, error)
+	List(opts v1.ListOptions) (*v1alpha1.CronJobSchedulingPreferenceList, error)
+	Watch(opts v1.ListOptions) (watch.Interface, error)
+	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.CronJobSchedulingPreference, err error)
+	CronJobSchedulingPreferenceExpansion
+}
+
+// cronJobSchedulingPreferences implements CronJobSchedulingPreferenceInterface
+type cronJobSchedulingPreferences struct {
+	client rest.Interface
+	ns     string
+}
+
+// newCronJobSchedulingPreferences returns a CronJobSchedulingPreferences
+func newCronJobSchedulingPreferences(c *SchedulingV1alpha1Client, namespace string) *cronJobSchedulingPreferences {
+	return &cronJobSchedulingPreferences{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the cronJobSchedulingPreference, and returns the corresponding cronJobSchedulingPreference object, and an error if there is any.
+func (c *cronJobSchedulingPreferences) Get(name string, options v1.GetOptions) (result *v1alpha1.CronJobSchedulingPreference, err error) {
+	result = &v1alpha1.CronJobSchedulingPreference{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("cronjobschedulingpreferences").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do().
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of CronJobSchedulingPreferences that match those selectors.
+func (c *cronJobSchedulingPreferences) List(opts v1.ListOptions) (result *v1alpha1.CronJobSchedulingPreferenceList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1alpha1.CronJobSchedulingPreferenceList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("cronjobschedulingpreferences").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do().
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested cronJobSchedulingPreferences.
+func (c *cronJobSchedulingPreferences) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("cronjobschedulingpreferences").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch()
+}
+
+// Create takes the representation of a cronJobSchedulingPreference and creates it.  Returns the server's representation of the cronJobSchedulingPreference, and an error, if there is any.
+func (c *cronJobSchedulingPreferences) Create(cronJobSchedulingPreference *v1alpha1.CronJobSchedulingPreference) (result *v1alpha1.CronJobSchedulingPreference, err error) {
+	result = &v1alpha1.CronJobSchedulingPreference{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("cronjobschedulingpreferences").
+		Body(cronJobSchedulingPreference).
+		Do().
+		Into(result)
+	return
+}
+
+// Update takes the representation of a cronJobSchedulingPreference and updates it. Returns the server's representation of the cronJobSchedulingPreference, and an error, if there is any.
+func (c *cronJobSchedulingPreferences) Update(cronJobSchedulingPreference *v1alpha1.CronJobSchedulingPreference) (result *v1alpha1.CronJobSchedulingPreference, err error) {
+	result = &v1alpha1.CronJobSchedulingPreference{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("cronjobschedulingpreferences").
+		Name(cronJobSchedulingPreference.Name).
+		Body(cronJobSchedulingPreference).
+		Do().
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+
+func (c *cronJobSchedulingPreferences) UpdateStatus(cronJobSchedulingPreference *v1alpha1.CronJobSchedulingPreference) (result *v1alpha1.CronJobSchedulingPreference, err error) {
+	result = &v1alpha1.CronJobSchedulingPreference{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("cronjobschedulingpreferences").
+		Name(cronJobSchedulingPreference.Name).
+		SubResource("status").
+		Body(cronJobSchedulingPreference).
+		Do().
+		Into(result)
+	return
+}
+
+// Delete takes name of the cronJobSchedulingPreference and deletes it. Returns an error if one occurs.
+func (c *cronJobSchedulingPreferences) Delete(name string, options *v1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("cronjobschedulingpreferences").
+		Name(name).
+		Body(options).
+		Do().
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *cronJobSchedulingPreferences) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	var timeout time.Duration
+	if listOptions.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOptions.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("cronjobschedulingpreferences").
+		VersionedParams(&listOptions, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(options).
+		Do().
+		Error()
+}
+
+// Patch applies the patch and returns the patched cronJobSchedulingPreference.
+func (c *cronJobSchedulingPreferences) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.CronJobSchedulingPreference, err error) {
+	result = &v1alpha1.CronJobSchedulingPreference{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("cronjobschedulingpreferences").
+		SubResource(subresources...).
+		Name(name).
+		Body(data).
+		Do().
+		Into(result)
+	return
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+	v1alpha1 "sigs.k8s.io/kubefed/pkg/apis/scheduling/v1alpha1"
+)
+
+// FakeCronJobSchedulingPreferences implements CronJobSchedulingPreferenceInterface
+type FakeCronJobSchedulingPreferences struct {
+	Fake *FakeSchedulingV1alpha1
+	ns   string
+}
+
+var cronjobschedulingpreferencesResource = schema.GroupVersionResource{Group: "scheduling.kubefed.k8s.io", Version: "v1alpha1", Resource: "cronjobschedulingpreferences"}
+
+var cronjobschedulingpreferencesKind = schema.GroupVersionKind{Group: "scheduling.kubefed.k8s.io", Version: "v1alpha1", Kind: "CronJobSchedulingPreference"}
+
+// Get takes name of the cronJobSchedulingPreference, and returns the corresponding cronJobSchedulingPreference object, and an error if there is any.
+func (c *FakeCronJobSchedulingPreferences) Get(name string, options v1.GetOptions) (result *v1alpha1.CronJobSchedulingPreference, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(cronjobschedulingpreferencesResource, c.ns, name), &v1alpha1.CronJobSchedulingPreference{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.CronJobSchedulingPreference), err
+}
+
+// List takes label and field selectors, and returns the list of CronJobSchedulingPreferences that match those selectors.
+func (c *FakeCronJobSchedulingPreferences) List(opts v1.ListOptions) (result *v1alpha1.CronJobSchedulingPreferenceList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(cronjobschedulingpreferencesResource, cronjobschedulingpreferencesKind, c.ns, opts), &v1alpha1.CronJobSchedulingPreferenceList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.CronJobSchedulingPreferenceList{ListMeta: obj.(*v1alpha1.CronJobSchedulingPreferenceList).ListMeta}
+	for _, item := range obj.(*v1alpha1.CronJobSchedulingPreferenceList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested cronJobSchedulingPreferences.
+func (c *FakeCronJobSchedulingPreferences) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(cronjobschedulingpreferencesResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a cronJobSchedulingPreference and creates it.  Returns the server's representation of the cronJobSchedulingPreference, and an error, if there is any.
+func (c *FakeCronJobSchedulingPreferences) Create(cronJobSchedulingPreference *v1alpha1.CronJobSchedulingPreference) (result *v1alpha1.CronJobSchedulingPreference, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(cronjobschedulingpreferencesResource, c.ns, cronJobSchedulingPreference), &v1alpha1.CronJobSchedulingPreference{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.CronJobSchedulingPreference), err
+}
+
+// Update takes the representation of a cronJobSchedulingPreference and updates it. Returns the server's representation of the cronJobSchedulingPreference, and an error, if there is any.
+func (c *FakeCronJobSchedulingPreferences) Update(cronJobSchedulingPreference *v1alpha1.CronJobSchedulingPreference) (result *v1alpha1.CronJobSchedulingPreference, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(cronjobschedulingpreferencesResource, c.ns, cronJobSchedulingPreference), &v1alpha1.CronJobSchedulingPreference{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.CronJobSchedulingPreference), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeCronJobSchedulingPreferences) UpdateStatus(cronJobSchedulingPreference *v1alpha1.CronJobSchedulingPreference) (*v1alpha1.CronJobSchedulingPreference, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(cronjobschedulingpreferencesResource, "status", c.ns, cronJobSchedulingPreference), &v1alpha1.CronJobSchedulingPreference{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.CronJobSchedulingPreference), err
+}
+
+// Delete takes name of the cronJobSchedulingPreference and deletes it. Returns an error if one occurs.
+func (c *FakeCronJobSchedulingPreferences) Delete(name string, options *v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(cronjobschedulingpreferencesResource, c.ns, name), &v1alpha1.CronJobSchedulingPreference{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeCronJobSchedulingPreferences) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(cronjobschedulingpreferencesResource, c.ns, listOptions)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.CronJobSchedulingPreferenceList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched cronJobSchedulingPreference.
+func (c *FakeCronJobSchedulingPreferences) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.CronJobSchedulingPreference, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(cronjobschedulingpreferencesResource, c.ns, name, pt, data, subresources...), &v1alpha1.CronJobSchedulingPreference{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.CronJobSchedulingPreference), err
+}
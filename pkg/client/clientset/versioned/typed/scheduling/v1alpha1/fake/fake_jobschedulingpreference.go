@@ -0,0 +1,140 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+	v1alpha1 "sigs.k8s.io/kubefed/pkg/apis/scheduling/v1alpha1"
+)
+
+// FakeJobSchedulingPreferences implements JobSchedulingPreferenceInterface
+type FakeJobSchedulingPreferences struct {
+	Fake *FakeSchedulingV1alpha1
+	ns   string
+}
+
+var jobschedulingpreferencesResource = schema.GroupVersionResource{Group: "scheduling.kubefed.k8s.io", Version: "v1alpha1", Resource: "jobschedulingpreferences"}
+
+var jobschedulingpreferencesKind = schema.GroupVersionKind{Group: "scheduling.kubefed.k8s.io", Version: "v1alpha1", Kind: "JobSchedulingPreference"}
+
+// Get takes name of the jobSchedulingPreference, and returns the corresponding jobSchedulingPreference object, and an error if there is any.
+func (c *FakeJobSchedulingPreferences) Get(name string, options v1.GetOptions) (result *v1alpha1.JobSchedulingPreference, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(jobschedulingpreferencesResource, c.ns, name), &v1alpha1.JobSchedulingPreference{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.JobSchedulingPreference), err
+}
+
+// List takes label and field selectors, and returns the list of JobSchedulingPreferences that match those selectors.
+func (c *FakeJobSchedulingPreferences) List(opts v1.ListOptions) (result *v1alpha1.JobSchedulingPreferenceList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(jobschedulingpreferencesResource, jobschedulingpreferencesKind, c.ns, opts), &v1alpha1.JobSchedulingPreferenceList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.JobSchedulingPreferenceList{ListMeta: obj.(*v1alpha1.JobSchedulingPreferenceList).ListMeta}
+	for _, item := range obj.(*v1alpha1.JobSchedulingPreferenceList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested jobSchedulingPreferences.
+func (c *FakeJobSchedulingPreferences) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(jobschedulingpreferencesResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a jobSchedulingPreference and creates it.  Returns the server's representation of the jobSchedulingPreference, and an error, if there is any.
+func (c *FakeJobSchedulingPreferences) Create(jobSchedulingPreference *v1alpha1.JobSchedulingPreference) (result *v1alpha1.JobSchedulingPreference, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(jobschedulingpreferencesResource, c.ns, jobSchedulingPreference), &v1alpha1.JobSchedulingPreference{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.JobSchedulingPreference), err
+}
+
+// Update takes the representation of a jobSchedulingPreference and updates it. Returns the server's representation of the jobSchedulingPreference, and an error, if there is any.
+func (c *FakeJobSchedulingPreferences) Update(jobSchedulingPreference *v1alpha1.JobSchedulingPreference) (result *v1alpha1.JobSchedulingPreference, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(jobschedulingpreferencesResource, c.ns, jobSchedulingPreference), &v1alpha1.JobSchedulingPreference{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.JobSchedulingPreference), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeJobSchedulingPreferences) UpdateStatus(jobSchedulingPreference *v1alpha1.JobSchedulingPreference) (*v1alpha1.JobSchedulingPreference, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(jobschedulingpreferencesResource, "status", c.ns, jobSchedulingPreference), &v1alpha1.JobSchedulingPreference{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.JobSchedulingPreference), err
+}
+
+// Delete takes name of the jobSchedulingPreference and deletes it. Returns an error if one occurs.
+func (c *FakeJobSchedulingPreferences) Delete(name string, options *v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(jobschedulingpreferencesResource, c.ns, name), &v1alpha1.JobSchedulingPreference{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeJobSchedulingPreferences) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(jobschedulingpreferencesResource, c.ns, listOptions)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.JobSchedulingPreferenceList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched jobSchedulingPreference.
+func (c *FakeJobSchedulingPreferences) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.JobSchedulingPreference, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(jobschedulingpreferencesResource, c.ns, name, pt, data, subresources...), &v1alpha1.JobSchedulingPreference{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.JobSchedulingPreference), err
+}
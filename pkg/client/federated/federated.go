@@ -0,0 +1,177 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package federated provides typed access to the template, placement
+// and overrides of federated resources.  Federated types are generated
+// for arbitrary target types and have no go types of their own, so
+// federated resources are retrieved as unstructured objects.
+package federated
+
+import (
+	"github.com/pkg/errors"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+// Placement selects the member clusters a federated resource is
+// propagated to.
+type Placement = util.GenericPlacementFields
+
+// ClusterReference identifies a member cluster of a placement by name.
+type ClusterReference = util.GenericClusterReference
+
+// ClusterSelector selects the member clusters of a placement by their
+// labels and the fields of their status.
+type ClusterSelector = util.GenericClusterSelector
+
+// Override holds the overrides of a federated resource for a single
+// member cluster.
+type Override = util.GenericOverrideItem
+
+// ClusterOverride sets the value of the given path of the template of
+// a federated resource in a member cluster.
+type ClusterOverride = util.ClusterOverride
+
+// OverrideValueSource selects the value of a cluster override from a
+// ConfigMap, a Secret or the profile of the member cluster.
+type OverrideValueSource = util.OverrideValueSource
+
+// ClusterPatch is a strategic merge, JSON merge or JSON patch of the
+// template of a federated resource in a member cluster.
+type ClusterPatch = util.ClusterPatch
+
+// GetTemplate decodes the template of the given federated resource
+// into the given object, which is typically a pointer to the go type
+// of the target type (e.g. *appsv1.Deployment).  A compressed template
+// is decompressed.
+func GetTemplate(fedObj *unstructured.Unstructured, template interface{}) error {
+	templateMap, ok, err := util.GetTemplate(fedObj)
+	if err != nil {
+		return errors.Wrap(err, "Failed to retrieve the template")
+	}
+	if !ok {
+		return errors.Errorf("%s %q has no template", fedObj.GetKind(), util.NewQualifiedName(fedObj))
+	}
+	if u, ok := template.(*unstructured.Unstructured); ok {
+		u.SetUnstructuredContent(templateMap)
+		return nil
+	}
+	return errors.Wrap(fromUnstructured(templateMap, template), "Failed to decode the template")
+}
+
+// SetTemplate sets the template of the given federated resource from
+// the given object of the target type.
+func SetTemplate(fedObj *unstructured.Unstructured, template interface{}) error {
+	return setTemplate(fedObj, template, false)
+}
+
+// SetCompressedTemplate sets the compressed template of the given
+// federated resource from the given object of the target type.
+// Compression allows federating objects whose template would
+// otherwise exceed the size limit of the API server.
+func SetCompressedTemplate(fedObj *unstructured.Unstructured, template interface{}) error {
+	return setTemplate(fedObj, template, true)
+}
+
+func setTemplate(fedObj *unstructured.Unstructured, template interface{}, compress bool) error {
+	templateMap, err := toUnstructured(template)
+	if err != nil {
+		return errors.Wrap(err, "Failed to encode the template")
+	}
+	return util.SetTemplate(fedObj, templateMap, compress)
+}
+
+// GetPlacement returns the placement of the given federated resource.
+func GetPlacement(fedObj *unstructured.Unstructured) (*Placement, error) {
+	placement := &Placement{}
+	placementMap, ok, err := unstructured.NestedMap(fedObj.Object, util.SpecField, util.PlacementField)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to retrieve the placement")
+	}
+	if !ok {
+		return placement, nil
+	}
+	err = fromUnstructured(placementMap, placement)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to decode the placement")
+	}
+	return placement, nil
+}
+
+// SetPlacement replaces the placement of the given federated resource.
+func SetPlacement(fedObj *unstructured.Unstructured, placement *Placement) error {
+	placementMap, err := toUnstructured(placement)
+	if err != nil {
+		return errors.Wrap(err, "Failed to encode the placement")
+	}
+	return unstructured.SetNestedField(fedObj.Object, placementMap, util.SpecField, util.PlacementField)
+}
+
+// GetOverrides returns the overrides of the given federated resource
+// in the order they are defined.  An error is returned if the
+// overrides would be rejected by the sync controller.
+func GetOverrides(fedObj *unstructured.Unstructured) ([]Override, error) {
+	if _, err := util.GetOverrides(fedObj); err != nil {
+		return nil, err
+	}
+	rawOverrides, ok, err := unstructured.NestedSlice(fedObj.Object, util.SpecField, util.OverridesField)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to retrieve the overrides")
+	}
+	wrapper := &overridesWrapper{Overrides: []Override{}}
+	if !ok {
+		return wrapper.Overrides, nil
+	}
+	err = fromUnstructured(map[string]interface{}{util.OverridesField: rawOverrides}, wrapper)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to decode the overrides")
+	}
+	return wrapper.Overrides, nil
+}
+
+// SetOverrides replaces the overrides of the given federated
+// resource.  The overrides are removed if none are given.
+func SetOverrides(fedObj *unstructured.Unstructured, overrides []Override) error {
+	if len(overrides) == 0 {
+		unstructured.RemoveNestedField(fedObj.Object, util.SpecField, util.OverridesField)
+		return nil
+	}
+	wrapperMap, err := toUnstructured(&overridesWrapper{Overrides: overrides})
+	if err != nil {
+		return errors.Wrap(err, "Failed to encode the overrides")
+	}
+	return unstructured.SetNestedField(fedObj.Object, wrapperMap[util.OverridesField], util.SpecField, util.OverridesField)
+}
+
+// overridesWrapper allows a slice of overrides to be converted with
+// the unstructured converter, which only converts objects.
+type overridesWrapper struct {
+	Overrides []Override `json:"overrides"`
+}
+
+func fromUnstructured(obj map[string]interface{}, target interface{}) error {
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(obj, target)
+}
+
+func toUnstructured(obj interface{}) (map[string]interface{}, error) {
+	if u, ok := obj.(runtime.Unstructured); ok {
+		return u.UnstructuredContent(), nil
+	}
+	return runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package federated
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+func newFederatedDeployment() *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "types.kubefed.k8s.io/v1beta1",
+		"kind":       "FederatedDeployment",
+		"metadata": map[string]interface{}{
+			"namespace": "ns",
+			"name":      "web",
+		},
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"replicas": int64(3),
+				},
+			},
+			"placement": map[string]interface{}{
+				"clusters": []interface{}{
+					map[string]interface{}{"name": "cluster1"},
+				},
+				"clusterSelector": map[string]interface{}{
+					"matchLabels": map[string]interface{}{"env": "prod"},
+				},
+			},
+			"overrides": []interface{}{
+				map[string]interface{}{
+					"clusterName": "cluster1",
+					"clusterOverrides": []interface{}{
+						map[string]interface{}{"path": "spec.replicas", "value": int64(5)},
+					},
+				},
+			},
+		},
+	}}
+}
+
+func TestTemplate(t *testing.T) {
+	fedObj := newFederatedDeployment()
+
+	deployment := &appsv1.Deployment{}
+	err := GetTemplate(fedObj, deployment)
+	assert.NoError(t, err)
+	if assert.NotNil(t, deployment.Spec.Replicas) {
+		assert.Equal(t, int32(3), *deployment.Spec.Replicas)
+	}
+
+	replicas := int32(7)
+	deployment.Spec.Replicas = &replicas
+	err = SetTemplate(fedObj, deployment)
+	assert.NoError(t, err)
+	value, _, _ := unstructured.NestedInt64(fedObj.Object, "spec", "template", "spec", "replicas")
+	assert.Equal(t, int64(7), value)
+
+	template := &unstructured.Unstructured{}
+	err = GetTemplate(fedObj, template)
+	assert.NoError(t, err)
+	value, _, _ = unstructured.NestedInt64(template.Object, "spec", "replicas")
+	assert.Equal(t, int64(7), value)
+
+	unstructured.RemoveNestedField(fedObj.Object, "spec", "template")
+	assert.Error(t, GetTemplate(fedObj, deployment))
+}
+
+func TestCompressedTemplate(t *testing.T) {
+	fedObj := newFederatedDeployment()
+
+	deployment := &appsv1.Deployment{}
+	assert.NoError(t, GetTemplate(fedObj, deployment))
+	replicas := int32(7)
+	deployment.Spec.Replicas = &replicas
+	assert.NoError(t, SetCompressedTemplate(fedObj, deployment))
+	assert.True(t, util.IsTemplateCompressed(fedObj))
+	_, ok, _ := unstructured.NestedFieldNoCopy(fedObj.Object, "spec", "template")
+	assert.False(t, ok)
+
+	decoded := &appsv1.Deployment{}
+	assert.NoError(t, GetTemplate(fedObj, decoded))
+	if assert.NotNil(t, decoded.Spec.Replicas) {
+		assert.Equal(t, int32(7), *decoded.Spec.Replicas)
+	}
+
+	assert.NoError(t, SetTemplate(fedObj, decoded))
+	assert.False(t, util.IsTemplateCompressed(fedObj))
+}
+
+func TestPlacement(t *testing.T) {
+	fedObj := newFederatedDeployment()
+
+	placement, err := GetPlacement(fedObj)
+	assert.NoError(t, err)
+	assert.Equal(t, []ClusterReference{{Name: "cluster1"}}, placement.Clusters)
+	if assert.NotNil(t, placement.ClusterSelector) {
+		assert.Equal(t, map[string]string{"env": "prod"}, placement.ClusterSelector.MatchLabels)
+	}
+
+	err = SetPlacement(fedObj, &Placement{
+		ClusterSelector: &ClusterSelector{
+			LabelSelector: metav1.LabelSelector{MatchLabels: map[string]string{"env": "dev"}},
+		},
+	})
+	assert.NoError(t, err)
+	clusterNames, err := util.GetClusterNames(fedObj)
+	assert.NoError(t, err)
+	assert.Empty(t, clusterNames)
+	env, _, _ := unstructured.NestedString(fedObj.Object, "spec", "placement", "clusterSelector", "matchLabels", "env")
+	assert.Equal(t, "dev", env)
+
+	unstructured.RemoveNestedField(fedObj.Object, "spec", "placement")
+	placement, err = GetPlacement(fedObj)
+	assert.NoError(t, err)
+	assert.Equal(t, &Placement{}, placement)
+}
+
+func TestOverrides(t *testing.T) {
+	fedObj := newFederatedDeployment()
+
+	overrides, err := GetOverrides(fedObj)
+	assert.NoError(t, err)
+	assert.Equal(t, []Override{{
+		ClusterName:      "cluster1",
+		ClusterOverrides: []ClusterOverride{{Path: "spec.replicas", Value: int64(5)}},
+	}}, overrides)
+
+	overrides = append(overrides, Override{
+		ClusterName:      "cluster2",
+		ClusterOverrides: []ClusterOverride{{Path: "spec.paused", Value: true}},
+	})
+	err = SetOverrides(fedObj, overrides)
+	assert.NoError(t, err)
+	overridesMap, err := util.GetOverrides(fedObj)
+	assert.NoError(t, err)
+	assert.Equal(t, util.OverridesMap{
+		"cluster1": util.ClusterOverridesMap{"spec.replicas": float64(5)},
+		"cluster2": util.ClusterOverridesMap{"spec.paused": true},
+	}, overridesMap)
+
+	err = SetOverrides(fedObj, nil)
+	assert.NoError(t, err)
+	_, ok, _ := unstructured.NestedFieldNoCopy(fedObj.Object, "spec", "overrides")
+	assert.False(t, ok)
+
+	err = SetOverrides(fedObj, []Override{
+		{ClusterName: "cluster1", ClusterOverrides: []ClusterOverride{{Path: "metadata.namespace", Value: "other"}}},
+	})
+	assert.NoError(t, err)
+	_, err = GetOverrides(fedObj)
+	assert.Error(t, err)
+}
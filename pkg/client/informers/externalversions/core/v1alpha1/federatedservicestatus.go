@@ -0,0 +1,89 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	time "time"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	watch "k8s.io/apimachinery/pkg/watch"
+	cache "k8s.io/client-go/tools/cache"
+	corev1alpha1 "sigs.k8s.io/kubefed/pkg/apis/core/v1alpha1"
+	versioned "sigs.k8s.io/kubefed/pkg/client/clientset/versioned"
+	internalinterfaces "sigs.k8s.io/kubefed/pkg/client/informers/externalversions/internalinterfaces"
+	v1alpha1 "sigs.k8s.io/kubefed/pkg/client/listers/core/v1alpha1"
+)
+
+// FederatedServiceStatusInformer provides access to a shared informer and lister for
+// FederatedServiceStatuses.
+type FederatedServiceStatusInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() v1alpha1.FederatedServiceStatusLister
+}
+
+type federatedServiceStatusInformer struct {
+	factory          internalinterfaces.SharedInformerFactory
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+	namespace        string
+}
+
+// NewFederatedServiceStatusInformer constructs a new informer for FederatedServiceStatus type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewFederatedServiceStatusInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+	return NewFilteredFederatedServiceStatusInformer(client, namespace, resyncPeriod, indexers, nil)
+}
+
+// NewFilteredFederatedServiceStatusInformer constructs a new informer for FederatedServiceStatus type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewFilteredFederatedServiceStatusInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers, tweakListOptions internalinterfaces.TweakListOptionsFunc) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options v1.ListOptions) (runtime.Object, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.CoreV1alpha1().FederatedServiceStatuses(namespace).List(options)
+			},
+			WatchFunc: func(options v1.ListOptions) (watch.Interface, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.CoreV1alpha1().FederatedServiceStatuses(namespace).Watch(options)
+			},
+		},
+		&corev1alpha1.FederatedServiceStatus{},
+		resyncPeriod,
+		indexers,
+	)
+}
+
+func (f *federatedServiceStatusInformer) defaultInformer(client versioned.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return NewFilteredFederatedServiceStatusInformer(client, f.namespace, resyncPeriod, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, f.tweakListOptions)
+}
+
+func (f *federatedServiceStatusInformer) Informer() cache.SharedIndexInformer {
+	return f.factory.InformerFor(&corev1alpha1.FederatedServiceStatus{}, f.defaultInformer)
+}
+
+func (f *federatedServiceStatusInformer) Lister() v1alpha1.FederatedServiceStatusLister {
+	return v1alpha1.NewFederatedServiceStatusLister(f.Informer().GetIndexer())
+}
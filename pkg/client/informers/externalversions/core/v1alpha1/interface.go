@@ -0,0 +1,59 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	internalinterfaces "sigs.k8s.io/kubefed/pkg/client/informers/externalversions/internalinterfaces"
+)
+
+// Interface provides access to all the informers in this group version.
+type Interface interface {
+	// ClusterPropagatedVersions returns a ClusterPropagatedVersionInformer.
+	ClusterPropagatedVersions() ClusterPropagatedVersionInformer
+	// FederatedServiceStatuses returns a FederatedServiceStatusInformer.
+	FederatedServiceStatuses() FederatedServiceStatusInformer
+	// PropagatedVersions returns a PropagatedVersionInformer.
+	PropagatedVersions() PropagatedVersionInformer
+}
+
+type version struct {
+	factory          internalinterfaces.SharedInformerFactory
+	namespace        string
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+}
+
+// New returns a new Interface.
+func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakListOptions internalinterfaces.TweakListOptionsFunc) Interface {
+	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
+}
+
+// ClusterPropagatedVersions returns a ClusterPropagatedVersionInformer.
+func (v *version) ClusterPropagatedVersions() ClusterPropagatedVersionInformer {
+	return &clusterPropagatedVersionInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// FederatedServiceStatuses returns a FederatedServiceStatusInformer.
+func (v *version) FederatedServiceStatuses() FederatedServiceStatusInformer {
+	return &federatedServiceStatusInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// PropagatedVersions returns a PropagatedVersionInformer.
+func (v *version) PropagatedVersions() PropagatedVersionInformer {
+	return &propagatedVersionInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	time "time"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	watch "k8s.io/apimachinery/pkg/watch"
+	cache "k8s.io/client-go/tools/cache"
+	corev1beta1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	versioned "sigs.k8s.io/kubefed/pkg/client/clientset/versioned"
+	internalinterfaces "sigs.k8s.io/kubefed/pkg/client/informers/externalversions/internalinterfaces"
+	v1beta1 "sigs.k8s.io/kubefed/pkg/client/listers/core/v1beta1"
+)
+
+// FederatedTypeConfigInformer provides access to a shared informer and lister for
+// FederatedTypeConfigs.
+type FederatedTypeConfigInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() v1beta1.FederatedTypeConfigLister
+}
+
+type federatedTypeConfigInformer struct {
+	factory          internalinterfaces.SharedInformerFactory
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+	namespace        string
+}
+
+// NewFederatedTypeConfigInformer constructs a new informer for FederatedTypeConfig type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewFederatedTypeConfigInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+	return NewFilteredFederatedTypeConfigInformer(client, namespace, resyncPeriod, indexers, nil)
+}
+
+// NewFilteredFederatedTypeConfigInformer constructs a new informer for FederatedTypeConfig type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewFilteredFederatedTypeConfigInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers, tweakListOptions internalinterfaces.TweakListOptionsFunc) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options v1.ListOptions) (runtime.Object, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.CoreV1beta1().FederatedTypeConfigs(namespace).List(options)
+			},
+			WatchFunc: func(options v1.ListOptions) (watch.Interface, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.CoreV1beta1().FederatedTypeConfigs(namespace).Watch(options)
+			},
+		},
+		&corev1beta1.FederatedTypeConfig{},
+		resyncPeriod,
+		indexers,
+	)
+}
+
+func (f *federatedTypeConfigInformer) defaultInformer(client versioned.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return NewFilteredFederatedTypeConfigInformer(client, f.namespace, resyncPeriod, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, f.tweakListOptions)
+}
+
+func (f *federatedTypeConfigInformer) Informer() cache.SharedIndexInformer {
+	return f.factory.InformerFor(&corev1beta1.FederatedTypeConfig{}, f.defaultInformer)
+}
+
+func (f *federatedTypeConfigInformer) Lister() v1beta1.FederatedTypeConfigLister {
+	return v1beta1.NewFederatedTypeConfigLister(f.Informer().GetIndexer())
+}
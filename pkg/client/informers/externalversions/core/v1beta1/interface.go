@@ -0,0 +1,80 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	internalinterfaces "sigs.k8s.io/kubefed/pkg/client/informers/externalversions/internalinterfaces"
+)
+
+// Interface provides access to all the informers in this group version.
+type Interface interface {
+	// DispatchPolicies returns a DispatchPolicyInformer.
+	DispatchPolicies() DispatchPolicyInformer
+	// FederatedTypeConfigs returns a FederatedTypeConfigInformer.
+	FederatedTypeConfigs() FederatedTypeConfigInformer
+	// KubeFedClusters returns a KubeFedClusterInformer.
+	KubeFedClusters() KubeFedClusterInformer
+	// KubeFedConfigs returns a KubeFedConfigInformer.
+	KubeFedConfigs() KubeFedConfigInformer
+	// PlacementPolicies returns a PlacementPolicyInformer.
+	PlacementPolicies() PlacementPolicyInformer
+	// PropagationQuotas returns a PropagationQuotaInformer.
+	PropagationQuotas() PropagationQuotaInformer
+}
+
+type version struct {
+	factory          internalinterfaces.SharedInformerFactory
+	namespace        string
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+}
+
+// New returns a new Interface.
+func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakListOptions internalinterfaces.TweakListOptionsFunc) Interface {
+	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
+}
+
+// DispatchPolicies returns a DispatchPolicyInformer.
+func (v *version) DispatchPolicies() DispatchPolicyInformer {
+	return &dispatchPolicyInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// FederatedTypeConfigs returns a FederatedTypeConfigInformer.
+func (v *version) FederatedTypeConfigs() FederatedTypeConfigInformer {
+	return &federatedTypeConfigInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// KubeFedClusters returns a KubeFedClusterInformer.
+func (v *version) KubeFedClusters() KubeFedClusterInformer {
+	return &kubeFedClusterInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// KubeFedConfigs returns a KubeFedConfigInformer.
+func (v *version) KubeFedConfigs() KubeFedConfigInformer {
+	return &kubeFedConfigInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// PlacementPolicies returns a PlacementPolicyInformer.
+func (v *version) PlacementPolicies() PlacementPolicyInformer {
+	return &placementPolicyInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// PropagationQuotas returns a PropagationQuotaInformer.
+func (v *version) PropagationQuotas() PropagationQuotaInformer {
+	return &propagationQuotaInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
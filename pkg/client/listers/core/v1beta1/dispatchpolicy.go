@@ -0,0 +1,94 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+	v1beta1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+// DispatchPolicyLister helps list DispatchPolicies.
+type DispatchPolicyLister interface {
+	// List lists all DispatchPolicies in the indexer.
+	List(selector labels.Selector) (ret []*v1beta1.DispatchPolicy, err error)
+	// DispatchPolicies returns an object that can list and get DispatchPolicies.
+	DispatchPolicies(namespace string) DispatchPolicyNamespaceLister
+	DispatchPolicyListerExpansion
+}
+
+// dispatchPolicyLister implements the DispatchPolicyLister interface.
+type dispatchPolicyLister struct {
+	indexer cache.Indexer
+}
+
+// NewDispatchPolicyLister returns a new DispatchPolicyLister.
+func NewDispatchPolicyLister(indexer cache.Indexer) DispatchPolicyLister {
+	return &dispatchPolicyLister{indexer: indexer}
+}
+
+// List lists all DispatchPolicies in the indexer.
+func (s *dispatchPolicyLister) List(selector labels.Selector) (ret []*v1beta1.DispatchPolicy, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1beta1.DispatchPolicy))
+	})
+	return ret, err
+}
+
+// DispatchPolicies returns an object that can list and get DispatchPolicies.
+func (s *dispatchPolicyLister) DispatchPolicies(namespace string) DispatchPolicyNamespaceLister {
+	return dispatchPolicyNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// DispatchPolicyNamespaceLister helps list and get DispatchPolicies.
+type DispatchPolicyNamespaceLister interface {
+	// List lists all DispatchPolicies in the indexer for a given namespace.
+	List(selector labels.Selector) (ret []*v1beta1.DispatchPolicy, err error)
+	// Get retrieves the DispatchPolicy from the indexer for a given namespace and name.
+	Get(name string) (*v1beta1.DispatchPolicy, error)
+	DispatchPolicyNamespaceListerExpansion
+}
+
+// dispatchPolicyNamespaceLister implements the DispatchPolicyNamespaceLister
+// interface.
+type dispatchPolicyNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all DispatchPolicies in the indexer for a given namespace.
+func (s dispatchPolicyNamespaceLister) List(selector labels.Selector) (ret []*v1beta1.DispatchPolicy, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1beta1.DispatchPolicy))
+	})
+	return ret, err
+}
+
+// Get retrieves the DispatchPolicy from the indexer for a given namespace and name.
+func (s dispatchPolicyNamespaceLister) Get(name string) (*v1beta1.DispatchPolicy, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1beta1.Resource("dispatchpolicy"), name)
+	}
+	return obj.(*v1beta1.DispatchPolicy), nil
+}
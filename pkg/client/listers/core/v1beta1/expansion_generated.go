@@ -0,0 +1,67 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1beta1
+
+// DispatchPolicyListerExpansion allows custom methods to be added to
+// DispatchPolicyLister.
+type DispatchPolicyListerExpansion interface{}
+
+// DispatchPolicyNamespaceListerExpansion allows custom methods to be added to
+// DispatchPolicyNamespaceLister.
+type DispatchPolicyNamespaceListerExpansion interface{}
+
+// FederatedTypeConfigListerExpansion allows custom methods to be added to
+// FederatedTypeConfigLister.
+type FederatedTypeConfigListerExpansion interface{}
+
+// FederatedTypeConfigNamespaceListerExpansion allows custom methods to be added to
+// FederatedTypeConfigNamespaceLister.
+type FederatedTypeConfigNamespaceListerExpansion interface{}
+
+// KubeFedClusterListerExpansion allows custom methods to be added to
+// KubeFedClusterLister.
+type KubeFedClusterListerExpansion interface{}
+
+// KubeFedClusterNamespaceListerExpansion allows custom methods to be added to
+// KubeFedClusterNamespaceLister.
+type KubeFedClusterNamespaceListerExpansion interface{}
+
+// KubeFedConfigListerExpansion allows custom methods to be added to
+// KubeFedConfigLister.
+type KubeFedConfigListerExpansion interface{}
+
+// KubeFedConfigNamespaceListerExpansion allows custom methods to be added to
+// KubeFedConfigNamespaceLister.
+type KubeFedConfigNamespaceListerExpansion interface{}
+
+// PlacementPolicyListerExpansion allows custom methods to be added to
+// PlacementPolicyLister.
+type PlacementPolicyListerExpansion interface{}
+
+// PlacementPolicyNamespaceListerExpansion allows custom methods to be added to
+// PlacementPolicyNamespaceLister.
+type PlacementPolicyNamespaceListerExpansion interface{}
+
+// PropagationQuotaListerExpansion allows custom methods to be added to
+// PropagationQuotaLister.
+type PropagationQuotaListerExpansion interface{}
+
+// PropagationQuotaNamespaceListerExpansion allows custom methods to be added to
+// PropagationQuotaNamespaceLister.
+type PropagationQuotaNamespaceListerExpansion interface{}
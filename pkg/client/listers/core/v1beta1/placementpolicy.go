@@ -0,0 +1,94 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+	v1beta1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+// PlacementPolicyLister helps list PlacementPolicies.
+type PlacementPolicyLister interface {
+	// List lists all PlacementPolicies in the indexer.
+	List(selector labels.Selector) (ret []*v1beta1.PlacementPolicy, err error)
+	// PlacementPolicies returns an object that can list and get PlacementPolicies.
+	PlacementPolicies(namespace string) PlacementPolicyNamespaceLister
+	PlacementPolicyListerExpansion
+}
+
+// placementPolicyLister implements the PlacementPolicyLister interface.
+type placementPolicyLister struct {
+	indexer cache.Indexer
+}
+
+// NewPlacementPolicyLister returns a new PlacementPolicyLister.
+func NewPlacementPolicyLister(indexer cache.Indexer) PlacementPolicyLister {
+	return &placementPolicyLister{indexer: indexer}
+}
+
+// List lists all PlacementPolicies in the indexer.
+func (s *placementPolicyLister) List(selector labels.Selector) (ret []*v1beta1.PlacementPolicy, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1beta1.PlacementPolicy))
+	})
+	return ret, err
+}
+
+// PlacementPolicies returns an object that can list and get PlacementPolicies.
+func (s *placementPolicyLister) PlacementPolicies(namespace string) PlacementPolicyNamespaceLister {
+	return placementPolicyNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// PlacementPolicyNamespaceLister helps list and get PlacementPolicies.
+type PlacementPolicyNamespaceLister interface {
+	// List lists all PlacementPolicies in the indexer for a given namespace.
+	List(selector labels.Selector) (ret []*v1beta1.PlacementPolicy, err error)
+	// Get retrieves the PlacementPolicy from the indexer for a given namespace and name.
+	Get(name string) (*v1beta1.PlacementPolicy, error)
+	PlacementPolicyNamespaceListerExpansion
+}
+
+// placementPolicyNamespaceLister implements the PlacementPolicyNamespaceLister
+// interface.
+type placementPolicyNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all PlacementPolicies in the indexer for a given namespace.
+func (s placementPolicyNamespaceLister) List(selector labels.Selector) (ret []*v1beta1.PlacementPolicy, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1beta1.PlacementPolicy))
+	})
+	return ret, err
+}
+
+// Get retrieves the PlacementPolicy from the indexer for a given namespace and name.
+func (s placementPolicyNamespaceLister) Get(name string) (*v1beta1.PlacementPolicy, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1beta1.Resource("placementpolicy"), name)
+	}
+	return obj.(*v1beta1.PlacementPolicy), nil
+}
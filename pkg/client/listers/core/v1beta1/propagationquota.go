@@ -0,0 +1,94 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+	v1beta1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+// PropagationQuotaLister helps list PropagationQuotas.
+type PropagationQuotaLister interface {
+	// List lists all PropagationQuotas in the indexer.
+	List(selector labels.Selector) (ret []*v1beta1.PropagationQuota, err error)
+	// PropagationQuotas returns an object that can list and get PropagationQuotas.
+	PropagationQuotas(namespace string) PropagationQuotaNamespaceLister
+	PropagationQuotaListerExpansion
+}
+
+// propagationQuotaLister implements the PropagationQuotaLister interface.
+type propagationQuotaLister struct {
+	indexer cache.Indexer
+}
+
+// NewPropagationQuotaLister returns a new PropagationQuotaLister.
+func NewPropagationQuotaLister(indexer cache.Indexer) PropagationQuotaLister {
+	return &propagationQuotaLister{indexer: indexer}
+}
+
+// List lists all PropagationQuotas in the indexer.
+func (s *propagationQuotaLister) List(selector labels.Selector) (ret []*v1beta1.PropagationQuota, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1beta1.PropagationQuota))
+	})
+	return ret, err
+}
+
+// PropagationQuotas returns an object that can list and get PropagationQuotas.
+func (s *propagationQuotaLister) PropagationQuotas(namespace string) PropagationQuotaNamespaceLister {
+	return propagationQuotaNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// PropagationQuotaNamespaceLister helps list and get PropagationQuotas.
+type PropagationQuotaNamespaceLister interface {
+	// List lists all PropagationQuotas in the indexer for a given namespace.
+	List(selector labels.Selector) (ret []*v1beta1.PropagationQuota, err error)
+	// Get retrieves the PropagationQuota from the indexer for a given namespace and name.
+	Get(name string) (*v1beta1.PropagationQuota, error)
+	PropagationQuotaNamespaceListerExpansion
+}
+
+// propagationQuotaNamespaceLister implements the PropagationQuotaNamespaceLister
+// interface.
+type propagationQuotaNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all PropagationQuotas in the indexer for a given namespace.
+func (s propagationQuotaNamespaceLister) List(selector labels.Selector) (ret []*v1beta1.PropagationQuota, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1beta1.PropagationQuota))
+	})
+	return ret, err
+}
+
+// Get retrieves the PropagationQuota from the indexer for a given namespace and name.
+func (s propagationQuotaNamespaceLister) Get(name string) (*v1beta1.PropagationQuota, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1beta1.Resource("propagationquota"), name)
+	}
+	return obj.(*v1beta1.PropagationQuota), nil
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+	v1alpha1 "sigs.k8s.io/kubefed/pkg/apis/multiclusterdns/v1alpha1"
+)
+
+// DNSEndpointLister helps list DNSEndpoints.
+type DNSEndpointLister interface {
+	// List lists all DNSEndpoints in the indexer.
+	List(selector labels.Selector) (ret []*v1alpha1.DNSEndpoint, err error)
+	// DNSEndpoints returns an object that can list and get DNSEndpoints.
+	DNSEndpoints(namespace string) DNSEndpointNamespaceLister
+	DNSEndpointListerExpansion
+}
+
+// dNSEndpointLister implements the DNSEndpointLister interface.
+type dNSEndpointLister struct {
+	indexer cache.Indexer
+}
+
+// NewDNSEndpointLister returns a new DNSEndpointLister.
+func NewDNSEndpointLister(indexer cache.Indexer) DNSEndpointLister {
+	return &dNSEndpointLister{indexer: indexer}
+}
+
+// List lists all DNSEndpoints in the indexer.
+func (s *dNSEndpointLister) List(selector labels.Selector) (ret []*v1alpha1.DNSEndpoint, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.DNSEndpoint))
+	})
+	return ret, err
+}
+
+// DNSEndpoints returns an object that can list and get DNSEndpoints.
+func (s *dNSEndpointLister) DNSEndpoints(namespace string) DNSEndpointNamespaceLister {
+	return dNSEndpointNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// DNSEndpointNamespaceLister helps list and get DNSEndpoints.
+type DNSEndpointNamespaceLister interface {
+	// List lists all DNSEndpoints in the indexer for a given namespace.
+	List(selector labels.Selector) (ret []*v1alpha1.DNSEndpoint, err error)
+	// Get retrieves the DNSEndpoint from the indexer for a given namespace and name.
+	Get(name string) (*v1alpha1.DNSEndpoint, error)
+	DNSEndpointNamespaceListerExpansion
+}
+
+// dNSEndpointNamespaceLister implements the DNSEndpointNamespaceLister
+// interface.
+type dNSEndpointNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all DNSEndpoints in the indexer for a given namespace.
+func (s dNSEndpointNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.DNSEndpoint, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.DNSEndpoint))
+	})
+	return ret, err
+}
+
+// Get retrieves the DNSEndpoint from the indexer for a given namespace and name.
+func (s dNSEndpointNamespaceLister) Get(name string) (*v1alpha1.DNSEndpoint, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("dnsendpoint"), name)
+	}
+	return obj.(*v1alpha1.DNSEndpoint), nil
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+	v1alpha1 "sigs.k8s.io/kubefed/pkg/apis/scheduling/v1alpha1"
+)
+
+// CronJobSchedulingPreferenceLister helps list CronJobSchedulingPreferences.
+type CronJobSchedulingPreferenceLister interface {
+	// List lists all CronJobSchedulingPreferences in the indexer.
+	List(selector labels.Selector) (ret []*v1alpha1.CronJobSchedulingPreference, err error)
+	// CronJobSchedulingPreferences returns an object that can list and get CronJobSchedulingPreferences.
+	CronJobSchedulingPreferences(namespace string) CronJobSchedulingPreferenceNamespaceLister
+	CronJobSchedulingPreferenceListerExpansion
+}
+
+// cronJobSchedulingPreferenceLister implements the CronJobSchedulingPreferenceLister interface.
+type cronJobSchedulingPreferenceLister struct {
+	indexer cache.Indexer
+}
+
+// NewCronJobSchedulingPreferenceLister returns a new CronJobSchedulingPreferenceLister.
+func NewCronJobSchedulingPreferenceLister(indexer cache.Indexer) CronJobSchedulingPreferenceLister {
+	return &cronJobSchedulingPreferenceLister{indexer: indexer}
+}
+
+// List lists all CronJobSchedulingPreferences in the indexer.
+func (s *cronJobSchedulingPreferenceLister) List(selector labels.Selector) (ret []*v1alpha1.CronJobSchedulingPreference, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.CronJobSchedulingPreference))
+	})
+	return ret, err
+}
+
+// CronJobSchedulingPreferences returns an object that can list and get CronJobSchedulingPreferences.
+func (s *cronJobSchedulingPreferenceLister) CronJobSchedulingPreferences(namespace string) CronJobSchedulingPreferenceNamespaceLister {
+	return cronJobSchedulingPreferenceNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// CronJobSchedulingPreferenceNamespaceLister helps list and get CronJobSchedulingPreferences.
+type CronJobSchedulingPreferenceNamespaceLister interface {
+	// List lists all CronJobSchedulingPreferences in the indexer for a given namespace.
+	List(selector labels.Selector) (ret []*v1alpha1.CronJobSchedulingPreference, err error)
+	// Get retrieves the CronJobSchedulingPreference from the indexer for a given namespace and name.
+	Get(name string) (*v1alpha1.CronJobSchedulingPreference, error)
+	CronJobSchedulingPreferenceNamespaceListerExpansion
+}
+
+// cronJobSchedulingPreferenceNamespaceLister implements the CronJobSchedulingPreferenceNamespaceLister
+// interface.
+type cronJobSchedulingPreferenceNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all CronJobSchedulingPreferences in the indexer for a given namespace.
+func (s cronJobSchedulingPreferenceNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.CronJobSchedulingPreference, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.CronJobSchedulingPreference))
+	})
+	return ret, err
+}
+
+// Get retrieves the CronJobSchedulingPreference from the indexer for a given namespace and name.
+func (s cronJobSchedulingPreferenceNamespaceLister) Get(name string) (*v1alpha1.CronJobSchedulingPreference, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("cronjobschedulingpreference"), name)
+	}
+	return obj.(*v1alpha1.CronJobSchedulingPreference), nil
+}
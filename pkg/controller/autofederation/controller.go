@@ -0,0 +1,386 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autofederation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	kubeclient "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog"
+
+	"sigs.k8s.io/kubefed/pkg/apis/core/typeconfig"
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+const (
+	// FederateLabelKey is the label that opts a resource in the host
+	// cluster into auto-federation.
+	FederateLabelKey   = "kubefed.io/federate"
+	FederateLabelValue = "true"
+
+	// AutoFederatedLabelKey marks a federated resource as having been
+	// created by auto-federation.  Federated resources without the
+	// label are never updated or deleted by the controller.
+	AutoFederatedLabelKey   = "kubefed.io/auto-federated"
+	AutoFederatedLabelValue = "true"
+
+	// PlacementClustersAnnotation is an optional comma-separated list
+	// of the names of the clusters to place a resource on.
+	PlacementClustersAnnotation = "kubefed.io/placement-clusters"
+	// PlacementClusterSelectorAnnotation is an optional label
+	// selector (e.g. 'env=prod,region in (eu,us)') for the clusters
+	// to place a resource on.
+	PlacementClusterSelectorAnnotation = "kubefed.io/placement-cluster-selector"
+
+	// Reasons of the events recorded on resources opted into
+	// auto-federation.
+	EventReasonAutoFederated    = "AutoFederated"
+	EventReasonAutoFederateFail = "AutoFederateFailed"
+)
+
+// Labels and annotations that configure auto-federation rather than
+// the resources propagated to member clusters.
+var (
+	hostOnlyLabels      = []string{FederateLabelKey, util.ManagedByKubeFedLabelKey}
+	hostOnlyAnnotations = []string{PlacementClustersAnnotation, PlacementClusterSelectorAnnotation}
+)
+
+// Controller creates and updates the federated resources of resources
+// of a target type that are labeled for auto-federation in the host
+// cluster, and deletes them when the label or the resource is
+// removed.
+type Controller struct {
+	typeConfig        typeconfig.Interface
+	targetIsNamespace bool
+
+	// Store for the labeled resources of the target type
+	targetStore cache.Store
+	// Informer for the labeled resources of the target type
+	targetController cache.Controller
+
+	// Store for the federated type
+	federatedStore cache.Store
+	// Informer for the federated type
+	federatedController cache.Controller
+
+	worker util.ReconcileWorker
+
+	client        genericclient.Client
+	eventRecorder record.EventRecorder
+}
+
+// StartController starts a new auto-federation controller for a type config.
+func StartController(controllerConfig *util.ControllerConfig, stopChan <-chan struct{}, typeConfig typeconfig.Interface) error {
+	controller, err := newController(controllerConfig, typeConfig)
+	if err != nil {
+		return err
+	}
+	if controllerConfig.MinimizeLatency {
+		controller.minimizeLatency()
+	}
+	klog.Infof("Starting auto-federation controller for %q", typeConfig.GetTargetType().Kind)
+	controller.Run(stopChan)
+	return nil
+}
+
+// newController returns a new auto-federation controller for the type config.
+func newController(controllerConfig *util.ControllerConfig, typeConfig typeconfig.Interface) (*Controller, error) {
+	targetAPIResource := typeConfig.GetTargetType()
+	userAgent := fmt.Sprintf("%s-auto-federation-controller", strings.ToLower(targetAPIResource.Kind))
+
+	client := genericclient.NewForConfigOrDieWithUserAgent(controllerConfig.KubeConfig, userAgent)
+	kubeClient := kubeclient.NewForConfigOrDie(controllerConfig.KubeConfig)
+
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeClient.CoreV1().Events("")})
+	recorder := broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: userAgent})
+
+	c := &Controller{
+		typeConfig:        typeConfig,
+		targetIsNamespace: targetAPIResource.Kind == util.NamespaceKind,
+		client:            client,
+		eventRecorder:     recorder,
+	}
+
+	c.worker = util.NewReconcileWorker(util.WorkerName{Controller: "autofederation"}, c.reconcile, util.WorkerTiming{})
+
+	targetNamespace := controllerConfig.TargetNamespace
+
+	targetClient, err := util.NewResourceClient(controllerConfig.KubeConfig, &targetAPIResource)
+	if err != nil {
+		return nil, err
+	}
+	labelSelector := labels.Set(map[string]string{FederateLabelKey: FederateLabelValue}).AsSelector().String()
+	c.targetStore, c.targetController = util.NewLabeledResourceInformer(targetClient, targetNamespace, labelSelector, c.worker.EnqueueObject)
+
+	federatedAPIResource := typeConfig.GetFederatedType()
+	federatedClient, err := util.NewResourceClient(controllerConfig.KubeConfig, &federatedAPIResource)
+	if err != nil {
+		return nil, err
+	}
+	c.federatedStore, c.federatedController = util.NewResourceInformer(federatedClient, targetNamespace, c.enqueueFederatedResource)
+
+	return c, nil
+}
+
+// minimizeLatency reduces delays and timeouts to make the controller more responsive (useful for testing).
+func (c *Controller) minimizeLatency() {
+	c.worker.SetDelay(50*time.Millisecond, time.Second)
+}
+
+// Run runs the auto-federation controller.
+func (c *Controller) Run(stopChan <-chan struct{}) {
+	go c.targetController.Run(stopChan)
+	go c.federatedController.Run(stopChan)
+	c.worker.Run(stopChan)
+}
+
+func (c *Controller) isSynced() bool {
+	if !c.targetController.HasSynced() {
+		klog.V(2).Infof("Informer for %s not synced", c.typeConfig.GetTargetType().Kind)
+		return false
+	}
+	if !c.federatedController.HasSynced() {
+		klog.V(2).Infof("Informer for %s not synced", c.typeConfig.GetFederatedType().Kind)
+		return false
+	}
+	return true
+}
+
+// enqueueFederatedResource enqueues the target of an auto-federated
+// resource so that the federated resource is reverted if changed
+// and deleted if its target is no longer labeled.
+func (c *Controller) enqueueFederatedResource(obj pkgruntime.Object) {
+	fedObj := obj.(*unstructured.Unstructured)
+	if !c.isAutoFederated(fedObj) {
+		return
+	}
+	qualifiedName := util.NewQualifiedName(fedObj)
+	if c.targetIsNamespace {
+		qualifiedName.Namespace = ""
+	}
+	c.worker.Enqueue(qualifiedName)
+}
+
+func (c *Controller) reconcile(qualifiedName util.QualifiedName) util.ReconciliationStatus {
+	if !c.isSynced() {
+		return util.StatusNotSynced
+	}
+
+	targetKind := c.typeConfig.GetTargetType().Kind
+	federatedKind := c.typeConfig.GetFederatedType().Kind
+	key := qualifiedName.String()
+
+	klog.V(4).Infof("Starting to reconcile auto-federation of %s %q", targetKind, key)
+	startTime := time.Now()
+	defer func() {
+		klog.V(4).Infof("Finished reconciling auto-federation of %s %q (duration: %v)", targetKind, key, time.Since(startTime))
+	}()
+
+	targetObj, err := util.ObjFromCache(c.targetStore, targetKind, key)
+	if err != nil {
+		return util.StatusError
+	}
+
+	federatedName := qualifiedName
+	if c.targetIsNamespace {
+		federatedName.Namespace = qualifiedName.Name
+	}
+	fedObj, err := util.ObjFromCache(c.federatedStore, federatedKind, federatedName.String())
+	if err != nil {
+		return util.StatusError
+	}
+
+	if targetObj == nil || targetObj.GetDeletionTimestamp() != nil {
+		if fedObj == nil || !c.isAutoFederated(fedObj) || fedObj.GetDeletionTimestamp() != nil {
+			return util.StatusAllOK
+		}
+		klog.V(2).Infof("Deleting %s %q since %s %q is no longer labeled for auto-federation", federatedKind, federatedName, targetKind, key)
+		err := c.client.Delete(context.TODO(), fedObj, fedObj.GetNamespace(), fedObj.GetName())
+		if err != nil && !apierrors.IsNotFound(err) {
+			runtime.HandleError(errors.Wrapf(err, "Failed to delete %s %q", federatedKind, federatedName))
+			return util.StatusError
+		}
+		return util.StatusAllOK
+	}
+
+	if fedObj != nil && !c.isAutoFederated(fedObj) {
+		c.eventRecorder.Eventf(targetObj, corev1.EventTypeWarning, EventReasonAutoFederateFail,
+			"%s %q already exists and was not created by auto-federation", federatedKind, federatedName)
+		return util.StatusAllOK
+	}
+	if fedObj != nil && fedObj.GetDeletionTimestamp() != nil {
+		// The federated resource will be recreated once its deletion
+		// has completed.
+		return util.StatusAllOK
+	}
+
+	desiredObj, err := federatedResourceFor(c.typeConfig, targetObj)
+	if err != nil {
+		c.eventRecorder.Eventf(targetObj, corev1.EventTypeWarning, EventReasonAutoFederateFail,
+			"Failed to generate %s: %v", federatedKind, err)
+		return util.StatusAllOK
+	}
+
+	if fedObj == nil {
+		err := c.client.Create(context.TODO(), desiredObj)
+		if err != nil {
+			if apierrors.IsAlreadyExists(err) {
+				return util.StatusNeedsRecheck
+			}
+			runtime.HandleError(errors.Wrapf(err, "Failed to create %s %q", federatedKind, federatedName))
+			return util.StatusError
+		}
+		c.eventRecorder.Eventf(targetObj, corev1.EventTypeNormal, EventReasonAutoFederated, "Created %s %q", federatedKind, federatedName)
+		return util.StatusAllOK
+	}
+
+	if !updateFederatedResource(fedObj, desiredObj) {
+		return util.StatusAllOK
+	}
+	err = c.client.Update(context.TODO(), fedObj)
+	if err != nil {
+		if apierrors.IsConflict(err) {
+			return util.StatusNeedsRecheck
+		}
+		runtime.HandleError(errors.Wrapf(err, "Failed to update %s %q", federatedKind, federatedName))
+		return util.StatusError
+	}
+	klog.V(2).Infof("Updated %s %q from %s %q", federatedKind, federatedName, targetKind, key)
+	return util.StatusAllOK
+}
+
+// isAutoFederated indicates whether the given federated resource was
+// created by the controller for its target type.
+func (c *Controller) isAutoFederated(fedObj *unstructured.Unstructured) bool {
+	if fedObj.GetLabels()[AutoFederatedLabelKey] != AutoFederatedLabelValue {
+		return false
+	}
+	return !typeconfig.IsGenericFederatedType(c.typeConfig) || util.TemplateTargetsType(fedObj, c.typeConfig.GetTargetType())
+}
+
+// federatedResourceFor returns the federated resource for the given
+// resource labeled for auto-federation.
+func federatedResourceFor(typeConfig typeconfig.Interface, targetObj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	placement, err := placementFor(targetObj)
+	if err != nil {
+		return nil, err
+	}
+
+	fedObj, err := util.FederatedResourceFromTargetResource(typeConfig, targetObj)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range hostOnlyLabels {
+		unstructured.RemoveNestedField(fedObj.Object, util.SpecField, util.TemplateField, util.MetadataField, "labels", key)
+	}
+	for _, key := range hostOnlyAnnotations {
+		unstructured.RemoveNestedField(fedObj.Object, util.SpecField, util.TemplateField, util.MetadataField, "annotations", key)
+	}
+	err = unstructured.SetNestedField(fedObj.Object, placement, util.SpecField, util.PlacementField)
+	if err != nil {
+		return nil, err
+	}
+	fedObj.SetLabels(map[string]string{AutoFederatedLabelKey: AutoFederatedLabelValue})
+	return fedObj, nil
+}
+
+// placementFor returns the placement configured by the annotations of
+// the given resource.  A resource without placement annotations is
+// placed on all clusters.
+func placementFor(obj *unstructured.Unstructured) (map[string]interface{}, error) {
+	annotations := obj.GetAnnotations()
+	placement := &util.GenericPlacementFields{}
+	if value := annotations[PlacementClustersAnnotation]; len(value) > 0 {
+		for _, name := range strings.Split(value, ",") {
+			name = strings.TrimSpace(name)
+			if len(name) > 0 {
+				placement.Clusters = append(placement.Clusters, util.GenericClusterReference{Name: name})
+			}
+		}
+	}
+	if value, ok := annotations[PlacementClusterSelectorAnnotation]; ok {
+		selector, err := metav1.ParseToLabelSelector(value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Invalid value for annotation %q", PlacementClusterSelectorAnnotation)
+		}
+		placement.ClusterSelector = &util.GenericClusterSelector{LabelSelector: *selector}
+	}
+	if placement.Clusters == nil && placement.ClusterSelector == nil {
+		placement.ClusterSelector = &util.GenericClusterSelector{}
+	}
+	return pkgruntime.DefaultUnstructuredConverter.ToUnstructured(placement)
+}
+
+// updateFederatedResource updates the given auto-federated resource
+// with the template, placement and labels of the desired federated
+// resource, and indicates whether an update is required.  Overrides
+// and other fields are retained so that they may be managed directly,
+// as is the compression of the template.
+func updateFederatedResource(fedObj, desiredObj *unstructured.Unstructured) bool {
+	updated := false
+	desiredTemplate, _, _ := util.GetTemplate(desiredObj)
+	currentTemplate, _, err := util.GetTemplate(fedObj)
+	if err != nil || !equality.Semantic.DeepEqual(desiredTemplate, currentTemplate) {
+		if err := util.SetTemplate(fedObj, desiredTemplate, util.IsTemplateCompressed(fedObj)); err != nil {
+			runtime.HandleError(err)
+		} else {
+			updated = true
+		}
+	}
+	desired, _, _ := unstructured.NestedFieldNoCopy(desiredObj.Object, util.SpecField, util.PlacementField)
+	current, _, _ := unstructured.NestedFieldNoCopy(fedObj.Object, util.SpecField, util.PlacementField)
+	if !equality.Semantic.DeepEqual(desired, current) {
+		if err := unstructured.SetNestedField(fedObj.Object, desired, util.SpecField, util.PlacementField); err != nil {
+			runtime.HandleError(err)
+		} else {
+			updated = true
+		}
+	}
+	labels := fedObj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	for key, value := range desiredObj.GetLabels() {
+		if labels[key] != value {
+			labels[key] = value
+			updated = true
+		}
+	}
+	if updated {
+		fedObj.SetLabels(labels)
+	}
+	return updated
+}
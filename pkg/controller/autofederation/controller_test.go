@@ -0,0 +1,171 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autofederation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+func newConfigMap(labels, annotations map[string]string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("ConfigMap")
+	obj.SetNamespace("ns")
+	obj.SetName("config")
+	obj.SetResourceVersion("42")
+	obj.SetLabels(labels)
+	obj.SetAnnotations(annotations)
+	obj.Object["data"] = map[string]interface{}{"key": "value"}
+	return obj
+}
+
+func TestPlacementFor(t *testing.T) {
+	testCases := map[string]struct {
+		annotations map[string]string
+		expected    map[string]interface{}
+		expectedErr bool
+	}{
+		"No placement annotations selects all clusters": {
+			expected: map[string]interface{}{
+				"clusterSelector": map[string]interface{}{},
+			},
+		},
+		"Cluster names": {
+			annotations: map[string]string{PlacementClustersAnnotation: "cluster1, cluster2,"},
+			expected: map[string]interface{}{
+				"clusters": []interface{}{
+					map[string]interface{}{"name": "cluster1"},
+					map[string]interface{}{"name": "cluster2"},
+				},
+			},
+		},
+		"Cluster selector": {
+			annotations: map[string]string{PlacementClusterSelectorAnnotation: "env=prod,region in (eu)"},
+			expected: map[string]interface{}{
+				"clusterSelector": map[string]interface{}{
+					"matchLabels": map[string]interface{}{"env": "prod"},
+					"matchExpressions": []interface{}{
+						map[string]interface{}{
+							"key":      "region",
+							"operator": "In",
+							"values":   []interface{}{"eu"},
+						},
+					},
+				},
+			},
+		},
+		"Invalid cluster selector": {
+			annotations: map[string]string{PlacementClusterSelectorAnnotation: "env in prod"},
+			expectedErr: true,
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			placement, err := placementFor(newConfigMap(nil, tc.annotations))
+			if tc.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, placement)
+		})
+	}
+}
+
+func TestFederatedResourceFor(t *testing.T) {
+	typeConfig := &fedv1b1.FederatedTypeConfig{
+		Spec: fedv1b1.FederatedTypeConfigSpec{
+			TargetType:    fedv1b1.APIResource{Version: "v1", Kind: "ConfigMap", Scope: "Namespaced"},
+			FederatedType: fedv1b1.APIResource{Group: "types.kubefed.k8s.io", Version: "v1beta1", Kind: "FederatedConfigMap", Scope: "Namespaced"},
+		},
+	}
+	targetObj := newConfigMap(
+		map[string]string{FederateLabelKey: FederateLabelValue, util.ManagedByKubeFedLabelKey: util.ManagedByKubeFedLabelValue, "app": "web"},
+		map[string]string{PlacementClustersAnnotation: "cluster1", "note": "kept"},
+	)
+
+	fedObj, err := federatedResourceFor(typeConfig, targetObj)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "FederatedConfigMap", fedObj.GetKind())
+	assert.Equal(t, "ns", fedObj.GetNamespace())
+	assert.Equal(t, "config", fedObj.GetName())
+	assert.Equal(t, map[string]string{AutoFederatedLabelKey: AutoFederatedLabelValue}, fedObj.GetLabels())
+
+	labels, _, _ := unstructured.NestedStringMap(fedObj.Object, "spec", "template", "metadata", "labels")
+	assert.Equal(t, map[string]string{"app": "web"}, labels)
+	annotations, _, _ := unstructured.NestedStringMap(fedObj.Object, "spec", "template", "metadata", "annotations")
+	assert.Equal(t, map[string]string{"note": "kept"}, annotations)
+	_, found, _ := unstructured.NestedString(fedObj.Object, "spec", "template", "metadata", "resourceVersion")
+	assert.False(t, found)
+	data, _, _ := unstructured.NestedStringMap(fedObj.Object, "spec", "template", "data")
+	assert.Equal(t, map[string]string{"key": "value"}, data)
+	clusterNames, err := util.GetClusterNames(fedObj)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"cluster1"}, clusterNames)
+}
+
+func TestFederatedResourceForDecryptedSecret(t *testing.T) {
+	typeConfig := &fedv1b1.FederatedTypeConfig{
+		Spec: fedv1b1.FederatedTypeConfigSpec{
+			TargetType:    fedv1b1.APIResource{Version: "v1", Kind: "Secret", Scope: "Namespaced"},
+			FederatedType: fedv1b1.APIResource{Group: "types.kubefed.k8s.io", Version: "v1beta1", Kind: "FederatedSecret", Scope: "Namespaced"},
+		},
+	}
+	targetObj := newConfigMap(map[string]string{FederateLabelKey: FederateLabelValue}, nil)
+	targetObj.SetKind("Secret")
+	targetObj.SetOwnerReferences([]metav1.OwnerReference{{APIVersion: "bitnami.com/v1alpha1", Kind: "SealedSecret", Name: "config"}})
+
+	// A Secret decrypted from a SealedSecret inherits the labels of
+	// its template but must not be propagated in clear.
+	_, err := federatedResourceFor(typeConfig, targetObj)
+	assert.Error(t, err)
+}
+
+func TestUpdateFederatedResource(t *testing.T) {
+	typeConfig := &fedv1b1.FederatedTypeConfig{
+		Spec: fedv1b1.FederatedTypeConfigSpec{
+			TargetType:    fedv1b1.APIResource{Version: "v1", Kind: "ConfigMap", Scope: "Namespaced"},
+			FederatedType: fedv1b1.APIResource{Group: "types.kubefed.k8s.io", Version: "v1beta1", Kind: "FederatedConfigMap", Scope: "Namespaced"},
+		},
+	}
+	desiredObj, err := federatedResourceFor(typeConfig, newConfigMap(nil, nil))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	fedObj := desiredObj.DeepCopy()
+	assert.False(t, updateFederatedResource(fedObj, desiredObj))
+
+	overrides := []interface{}{map[string]interface{}{"clusterName": "cluster1"}}
+	assert.NoError(t, unstructured.SetNestedSlice(fedObj.Object, overrides, "spec", "overrides"))
+	assert.NoError(t, unstructured.SetNestedField(fedObj.Object, "changed", "spec", "template", "data", "key"))
+	assert.True(t, updateFederatedResource(fedObj, desiredObj))
+	value, _, _ := unstructured.NestedString(fedObj.Object, "spec", "template", "data", "key")
+	assert.Equal(t, "value", value)
+	retainedOverrides, _, _ := unstructured.NestedSlice(fedObj.Object, "spec", "overrides")
+	assert.Equal(t, overrides, retainedOverrides)
+}
@@ -19,6 +19,7 @@ package dnsendpoint
 import (
 	"net"
 	"sort"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -33,10 +34,21 @@ const (
 
 	// RecordTypeA is a RecordType enum value
 	RecordTypeA = "A"
+	// RecordTypeAAAA is a RecordType enum value
+	RecordTypeAAAA = "AAAA"
 	// RecordTypeCNAME is a RecordType enum value
 	RecordTypeCNAME = "CNAME"
+
+	// ExternalDNSAnnotationPrefix is the prefix of the annotations of
+	// DNS record and Domain objects that are passed through to the
+	// DNSEndpoint objects consumed by external-dns.
+	ExternalDNSAnnotationPrefix = "external-dns.alpha.kubernetes.io/"
 )
 
+// DomainFunc returns the Domain object that the given DNS name belongs
+// to, or nil if there is none.
+type DomainFunc func(dnsName string) *feddnsv1a1.Domain
+
 // Abstracting away the internet for testing purposes
 type NetWrapper interface {
 	LookupHost(host string) (addrs []string, err error)
@@ -91,6 +103,203 @@ func ExtractLoadBalancerTargets(lbStatus corev1.LoadBalancerStatus) feddnsv1a1.T
 	return targets
 }
 
+// recordTTL returns the first non-zero of the given TTLs, or the
+// default TTL if they are all zero.
+func recordTTL(ttls ...feddnsv1a1.TTL) feddnsv1a1.TTL {
+	for _, ttl := range ttls {
+		if ttl != 0 {
+			return ttl
+		}
+	}
+	return defaultDNSTTL
+}
+
+// matchDomain returns the domain of the given objects that the given
+// DNS name belongs to and that satisfies include, or nil.  If domains
+// are nested, the longest one wins.
+func matchDomain(objs []interface{}, dnsName string, include func(*feddnsv1a1.Domain) bool) *feddnsv1a1.Domain {
+	name := NormalizeHost(dnsName)
+	var match *feddnsv1a1.Domain
+	for _, obj := range objs {
+		domain := obj.(*feddnsv1a1.Domain)
+		if include != nil && !include(domain) {
+			continue
+		}
+		zone := NormalizeHost(domain.Domain)
+		if !hasDNSSuffix(name, zone) {
+			continue
+		}
+		if match == nil || len(zone) > len(NormalizeHost(match.Domain)) {
+			match = domain
+		}
+	}
+	return match
+}
+
+// hasDNSSuffix indicates whether the given normalized DNS name is equal
+// to or a subdomain of the given normalized suffix.
+func hasDNSSuffix(name, suffix string) bool {
+	return name == suffix || strings.HasSuffix(name, "."+suffix)
+}
+
+// applyRecordPolicy applies the record policies of the domains of the
+// given endpoints.  The IPv6 addresses of A records are moved to AAAA
+// records, the records of types the policy does not create are dropped
+// and, unless the DNS object sets a TTL, the TTL of the policy is set.
+func applyRecordPolicy(endpoints []*feddnsv1a1.Endpoint, objectTTL feddnsv1a1.TTL, domainFor DomainFunc) []*feddnsv1a1.Endpoint {
+	var result []*feddnsv1a1.Endpoint
+	for _, endpoint := range splitAddressEndpoints(endpoints) {
+		var domain *feddnsv1a1.Domain
+		if domainFor != nil {
+			domain = domainFor(endpoint.DNSName)
+		}
+		created, ttl := recordParameters(domain, endpoint)
+		if !created {
+			continue
+		}
+		if objectTTL == 0 {
+			endpoint.RecordTTL = recordTTL(ttl, endpoint.RecordTTL)
+		}
+		result = append(result, endpoint)
+	}
+	return result
+}
+
+// recordParameters returns whether the record of the given endpoint is
+// created as per the record policy of the given domain, and the TTL
+// the policy configures for it, if any.
+func recordParameters(domain *feddnsv1a1.Domain, endpoint *feddnsv1a1.Endpoint) (bool, feddnsv1a1.TTL) {
+	if domain == nil {
+		return true, 0
+	}
+	policy := domain.RecordPolicy
+	if policy == nil {
+		return true, domain.RecordTTL
+	}
+
+	recordTypes := policy.RecordTypes
+	var typeTTL, overrideTTL feddnsv1a1.TTL
+	switch endpoint.RecordType {
+	case RecordTypeA:
+		typeTTL = policy.RecordTTLs.A
+	case RecordTypeAAAA:
+		typeTTL = policy.RecordTTLs.AAAA
+	case RecordTypeCNAME:
+		typeTTL = policy.RecordTTLs.CNAME
+	}
+
+	name := NormalizeHost(endpoint.DNSName)
+	matchLength := -1
+	for _, override := range policy.Overrides {
+		suffix := NormalizeHost(override.Suffix)
+		if !hasDNSSuffix(name, suffix) || len(suffix) <= matchLength {
+			continue
+		}
+		matchLength = len(suffix)
+		overrideTTL = override.RecordTTL
+		if len(override.RecordTypes) > 0 {
+			recordTypes = override.RecordTypes
+		} else {
+			recordTypes = policy.RecordTypes
+		}
+	}
+
+	created := len(recordTypes) == 0
+	for _, recordType := range recordTypes {
+		if strings.EqualFold(recordType, endpoint.RecordType) {
+			created = true
+			break
+		}
+	}
+
+	for _, ttl := range []feddnsv1a1.TTL{overrideTTL, typeTTL, domain.RecordTTL} {
+		if ttl != 0 {
+			return created, ttl
+		}
+	}
+	return created, 0
+}
+
+// splitAddressEndpoints moves the IPv6 addresses of the given A
+// endpoints to AAAA endpoints of the same name.
+func splitAddressEndpoints(endpoints []*feddnsv1a1.Endpoint) []*feddnsv1a1.Endpoint {
+	var result []*feddnsv1a1.Endpoint
+	for _, endpoint := range endpoints {
+		if endpoint.RecordType != RecordTypeA {
+			result = append(result, endpoint)
+			continue
+		}
+		var ipv4Targets, ipv6Targets feddnsv1a1.Targets
+		for _, target := range endpoint.Targets {
+			if ip := net.ParseIP(target); ip != nil && ip.To4() == nil {
+				ipv6Targets = append(ipv6Targets, target)
+			} else {
+				ipv4Targets = append(ipv4Targets, target)
+			}
+		}
+		if len(ipv4Targets) > 0 {
+			endpoint.Targets = ipv4Targets
+			result = append(result, endpoint)
+		}
+		if len(ipv6Targets) > 0 {
+			ipv6Endpoint := endpoint.DeepCopy()
+			ipv6Endpoint.RecordType = RecordTypeAAAA
+			ipv6Endpoint.Targets = ipv6Targets
+			result = append(result, ipv6Endpoint)
+		}
+	}
+	return result
+}
+
+// mergeProviderSpecific merges the given provider specific configs.
+// Properties of a later config override those of an earlier config
+// with the same name.
+func mergeProviderSpecific(configs ...feddnsv1a1.ProviderSpecific) feddnsv1a1.ProviderSpecific {
+	var result feddnsv1a1.ProviderSpecific
+	index := make(map[string]int)
+	for _, config := range configs {
+		for _, property := range config {
+			if i, ok := index[property.Name]; ok {
+				result[i] = property
+				continue
+			}
+			index[property.Name] = len(result)
+			result = append(result, property)
+		}
+	}
+	return result
+}
+
+// setRecordProperties sets the set identifier and provider specific
+// config on the given endpoints.
+func setRecordProperties(endpoints []*feddnsv1a1.Endpoint, setIdentifier string, providerSpecific feddnsv1a1.ProviderSpecific) {
+	for _, endpoint := range endpoints {
+		endpoint.SetIdentifier = setIdentifier
+		if len(providerSpecific) > 0 {
+			endpoint.ProviderSpecific = append(feddnsv1a1.ProviderSpecific{}, providerSpecific...)
+		}
+	}
+}
+
+// PassthroughAnnotations returns the external-dns annotations of the
+// given annotation maps.  Annotations of a later map override those of
+// an earlier map.  Nil is returned if there are no such annotations.
+func PassthroughAnnotations(annotationMaps ...map[string]string) map[string]string {
+	var result map[string]string
+	for _, annotations := range annotationMaps {
+		for key, value := range annotations {
+			if !strings.HasPrefix(key, ExternalDNSAnnotationPrefix) {
+				continue
+			}
+			if result == nil {
+				result = make(map[string]string)
+			}
+			result[key] = value
+		}
+	}
+	return result
+}
+
 // Merge and remove duplicate endpoints
 func DedupeAndMergeEndpoints(endpoints []*feddnsv1a1.Endpoint) (result []*feddnsv1a1.Endpoint) {
 	// Sort endpoints by DNSName
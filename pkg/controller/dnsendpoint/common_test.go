@@ -16,7 +16,14 @@ limitations under the License.
 
 package dnsendpoint
 
-import "github.com/pkg/errors"
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	feddnsv1a1 "sigs.k8s.io/kubefed/pkg/apis/multiclusterdns/v1alpha1"
+)
 
 const (
 	name      = "nginx"
@@ -54,3 +61,68 @@ func (mock *NetWrapperMock) AddHost(host string, addrs []string) {
 
 	mock.result[host] = addrs
 }
+
+func TestApplyRecordPolicy(t *testing.T) {
+	domain := &feddnsv1a1.Domain{
+		Domain:    "example.com",
+		RecordTTL: 120,
+		RecordPolicy: &feddnsv1a1.RecordPolicy{
+			RecordTypes: []string{RecordTypeA, RecordTypeAAAA, RecordTypeCNAME},
+			RecordTTLs:  feddnsv1a1.RecordTTLs{CNAME: 600},
+			Overrides: []feddnsv1a1.RecordPolicyOverride{
+				{Suffix: "internal.example.com", RecordTypes: []string{RecordTypeA}},
+				{Suffix: "fast.internal.example.com", RecordTTL: 10},
+			},
+		},
+	}
+	domainFor := func(dnsName string) *feddnsv1a1.Domain {
+		return matchDomain([]interface{}{domain}, dnsName, nil)
+	}
+	newEndpoints := func() []*feddnsv1a1.Endpoint {
+		return []*feddnsv1a1.Endpoint{
+			{DNSName: "a.example.com", RecordType: RecordTypeA, RecordTTL: defaultDNSTTL, Targets: []string{lb1, "2001:db8::1"}},
+			{DNSName: "b.example.com", RecordType: RecordTypeCNAME, RecordTTL: defaultDNSTTL, Targets: []string{"a.example.com"}},
+			{DNSName: "a.internal.example.com", RecordType: RecordTypeA, RecordTTL: defaultDNSTTL, Targets: []string{"2001:db8::2"}},
+			{DNSName: "b.internal.example.com", RecordType: RecordTypeCNAME, RecordTTL: defaultDNSTTL, Targets: []string{"a.example.com"}},
+			{DNSName: "a.fast.internal.example.com", RecordType: RecordTypeCNAME, RecordTTL: defaultDNSTTL, Targets: []string{"a.example.com"}},
+			{DNSName: "a.example.org", RecordType: RecordTypeA, RecordTTL: defaultDNSTTL, Targets: []string{lb2}},
+		}
+	}
+
+	testCases := map[string]struct {
+		objectTTL       feddnsv1a1.TTL
+		expectEndpoints []*feddnsv1a1.Endpoint
+	}{
+		"Policy of the domain applies": {
+			expectEndpoints: []*feddnsv1a1.Endpoint{
+				{DNSName: "a.example.com", RecordType: RecordTypeA, RecordTTL: 120, Targets: []string{lb1}},
+				{DNSName: "a.example.com", RecordType: RecordTypeAAAA, RecordTTL: 120, Targets: []string{"2001:db8::1"}},
+				{DNSName: "b.example.com", RecordType: RecordTypeCNAME, RecordTTL: 600, Targets: []string{"a.example.com"}},
+				{DNSName: "a.fast.internal.example.com", RecordType: RecordTypeCNAME, RecordTTL: 10, Targets: []string{"a.example.com"}},
+				{DNSName: "a.example.org", RecordType: RecordTypeA, RecordTTL: defaultDNSTTL, Targets: []string{lb2}},
+			},
+		},
+		"TTL of the DNS object is retained": {
+			objectTTL: userConfiguredTTL,
+			expectEndpoints: []*feddnsv1a1.Endpoint{
+				{DNSName: "a.example.com", RecordType: RecordTypeA, RecordTTL: defaultDNSTTL, Targets: []string{lb1}},
+				{DNSName: "a.example.com", RecordType: RecordTypeAAAA, RecordTTL: defaultDNSTTL, Targets: []string{"2001:db8::1"}},
+				{DNSName: "b.example.com", RecordType: RecordTypeCNAME, RecordTTL: defaultDNSTTL, Targets: []string{"a.example.com"}},
+				{DNSName: "a.fast.internal.example.com", RecordType: RecordTypeCNAME, RecordTTL: defaultDNSTTL, Targets: []string{"a.example.com"}},
+				{DNSName: "a.example.org", RecordType: RecordTypeA, RecordTTL: defaultDNSTTL, Targets: []string{lb2}},
+			},
+		},
+	}
+
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			endpoints := applyRecordPolicy(newEndpoints(), tc.objectTTL, domainFor)
+			if !reflect.DeepEqual(endpoints, tc.expectEndpoints) {
+				for _, ep := range endpoints {
+					t.Logf("%+v", ep)
+				}
+				t.Fatalf("Does not match expected endpoints")
+			}
+		})
+	}
+}
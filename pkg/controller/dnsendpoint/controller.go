@@ -19,6 +19,7 @@ package dnsendpoint
 import (
 	"context"
 	"reflect"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
@@ -28,9 +29,11 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	pkgruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	feddnsv1a1 "sigs.k8s.io/kubefed/pkg/apis/multiclusterdns/v1alpha1"
 	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
@@ -46,7 +49,13 @@ const (
 	numWorkers = 2
 )
 
-type GetEndpointsFunc func(interface{}) ([]*feddnsv1a1.Endpoint, error)
+// GetEndpointsFunc returns the endpoints of a DNS object, applying the
+// record policies of the domains returned by the given DomainFunc.
+type GetEndpointsFunc func(interface{}, DomainFunc) ([]*feddnsv1a1.Endpoint, error)
+
+// GetAnnotationsFunc returns the annotations to set on the DNSEndpoint
+// object of a DNS object.
+type GetAnnotationsFunc func(interface{}) map[string]string
 
 type controller struct {
 	client genericclient.Client
@@ -54,43 +63,75 @@ type controller struct {
 	dnsObjectStore cache.Store
 	// Informer controller for DNS objects
 	dnsObjectController cache.Controller
+	// Informer controller for Domain objects
+	domainController cache.Controller
+
+	// Informer Store for Domain objects
+	domainStore cache.Store
+
+	// providers programs the records in the domains that configure a
+	// DNS provider.
+	providers *providerRegistry
 
-	dnsObjectKind string
-	getEndpoints  GetEndpointsFunc
+	// The keys of the DNS objects whose records must be programmed in
+	// full since a Domain changed.
+	resyncLock sync.Mutex
+	resync     sets.String
+
+	dnsObjectKind  string
+	getEndpoints   GetEndpointsFunc
+	getAnnotations GetAnnotationsFunc
 
 	queue         workqueue.RateLimitingInterface
 	minRetryDelay time.Duration
 	maxRetryDelay time.Duration
 }
 
-func newDNSEndpointController(config *util.ControllerConfig, objectType pkgruntime.Object, objectKind string,
-	getEndpoints GetEndpointsFunc, minimizeLatency bool) (*controller, error) {
+// newDNSEndpointController returns a controller that watches the DNS
+// objects and Domains of the host cluster with the informers of the
+// given manager.
+func newDNSEndpointController(config *util.ControllerConfig, mgr manager.Manager, objectType pkgruntime.Object, objectKind string,
+	getEndpoints GetEndpointsFunc, getAnnotations GetAnnotationsFunc, minimizeLatency bool) (*controller, error) {
 	client, err := genericclient.New(config.KubeConfig)
 	if err != nil {
 		return nil, err
 	}
 
 	d := &controller{
-		client:        client,
-		dnsObjectKind: objectKind,
-		getEndpoints:  getEndpoints,
-		minRetryDelay: minRetryDelay,
-		maxRetryDelay: maxRetryDelay,
+		client:         client,
+		dnsObjectKind:  objectKind,
+		getEndpoints:   getEndpoints,
+		getAnnotations: getAnnotations,
+		resync:         sets.NewString(),
+		minRetryDelay:  minRetryDelay,
+		maxRetryDelay:  maxRetryDelay,
 	}
 
 	// Start informer for DNS objects
-	// TODO: Change this to shared informer
-	d.dnsObjectStore, d.dnsObjectController, err = util.NewGenericInformer(
-		config.KubeConfig,
+	d.dnsObjectStore, d.dnsObjectController, err = util.NewManagerInformer(
+		mgr,
 		config.TargetNamespace,
 		objectType,
-		util.NoResyncPeriod,
 		d.enqueueObject,
 	)
 	if err != nil {
 		return nil, err
 	}
 
+	// Start informer for Domain objects so that the DNS objects are
+	// reconciled when the record policy or provider of a domain
+	// changes.
+	d.domainStore, d.domainController, err = util.NewManagerInformer(
+		mgr,
+		config.KubeFedNamespace,
+		&feddnsv1a1.Domain{},
+		d.enqueueAllObjects,
+	)
+	if err != nil {
+		return nil, err
+	}
+	d.providers = newProviderRegistry(client, d.domainStore)
+
 	if minimizeLatency {
 		d.minimizeLatency()
 	}
@@ -113,10 +154,8 @@ func (d *controller) Run(stopCh <-chan struct{}) {
 	klog.Infof("Starting %q DNSEndpoint controller", d.dnsObjectKind)
 	defer klog.Infof("Shutting down %q DNSEndpoint controller", d.dnsObjectKind)
 
-	go d.dnsObjectController.Run(stopCh)
-
 	// wait for the caches to synchronize before starting the worker
-	if !cache.WaitForCacheSync(stopCh, d.dnsObjectController.HasSynced) {
+	if !cache.WaitForCacheSync(stopCh, d.dnsObjectController.HasSynced, d.domainController.HasSynced) {
 		runtime.HandleError(errors.New("Timed out waiting for caches to sync"))
 		return
 	}
@@ -139,6 +178,36 @@ func (d *controller) enqueueObject(obj pkgruntime.Object) {
 	d.queue.Add(key)
 }
 
+// enqueueAllObjects enqueues all DNS objects for a full resync of
+// their records, since the provider of a domain may have changed.
+func (d *controller) enqueueAllObjects(_ pkgruntime.Object) {
+	keys := d.dnsObjectStore.ListKeys()
+	d.resyncLock.Lock()
+	d.resync.Insert(keys...)
+	d.resyncLock.Unlock()
+	for _, key := range keys {
+		d.queue.Add(key)
+	}
+}
+
+// takeResync returns whether the records of the DNS object with the
+// given key must be programmed in full, and clears the request.
+func (d *controller) takeResync(key string) bool {
+	d.resyncLock.Lock()
+	defer d.resyncLock.Unlock()
+	if !d.resync.Has(key) {
+		return false
+	}
+	d.resync.Delete(key)
+	return true
+}
+
+// domainFor returns the Domain object that the given DNS name belongs
+// to, or nil.
+func (d *controller) domainFor(dnsName string) *feddnsv1a1.Domain {
+	return matchDomain(d.domainStore.List(), dnsName, nil)
+}
+
 func (d *controller) worker() {
 	// processNextWorkItem will automatically wait until there's work available
 	for d.processNextItem() {
@@ -172,7 +241,17 @@ func (d *controller) processNextItem() bool {
 	return true
 }
 
-func (d *controller) processItem(key string) error {
+func (d *controller) processItem(key string) (err error) {
+	resync := d.takeResync(key)
+	defer func() {
+		// Retry the full resync if the item could not be processed.
+		if err != nil && resync {
+			d.resyncLock.Lock()
+			d.resync.Insert(key)
+			d.resyncLock.Unlock()
+		}
+	}()
+
 	startTime := time.Now()
 	klog.V(4).Infof("Processing change to %q DNSEndpoint %s", d.dnsObjectKind, key)
 	defer func() {
@@ -195,6 +274,18 @@ func (d *controller) processItem(key string) error {
 	if !exists {
 		//delete corresponding DNSEndpoint object
 		dnsEndpointObject := &feddnsv1a1.DNSEndpoint{}
+		err = d.client.Get(context.TODO(), dnsEndpointObject, namespace, name)
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		// Remove the records from the DNS providers first so that
+		// they are not orphaned if the removal fails.
+		if err := d.providers.sync(dnsEndpointObject.Spec.Endpoints, nil, resync); err != nil {
+			return err
+		}
 		err = d.client.Delete(context.TODO(), dnsEndpointObject, namespace, name)
 		if apierrors.IsNotFound(err) {
 			return nil
@@ -202,18 +293,24 @@ func (d *controller) processItem(key string) error {
 		return err
 	}
 
-	dnsEndpoints, err := d.getEndpoints(obj)
+	dnsEndpoints, err := d.getEndpoints(obj, d.domainFor)
 	if err != nil {
 		return err
 	}
 
+	annotations := d.getAnnotations(obj)
+
 	dnsEndpointObject := &feddnsv1a1.DNSEndpoint{}
 	err = d.client.Get(context.TODO(), dnsEndpointObject, namespace, name)
 	if apierrors.IsNotFound(err) {
+		if err := d.providers.sync(nil, dnsEndpoints, resync); err != nil {
+			return err
+		}
 		newDNSEndpointObject := &feddnsv1a1.DNSEndpoint{
 			ObjectMeta: metav1.ObjectMeta{
-				Name:      name,
-				Namespace: namespace,
+				Name:        name,
+				Namespace:   namespace,
+				Annotations: annotations,
 			},
 			Spec: feddnsv1a1.DNSEndpointSpec{
 				Endpoints: dnsEndpoints,
@@ -225,11 +322,40 @@ func (d *controller) processItem(key string) error {
 		return err
 	}
 
-	// Update only if the new endpoints are not equal to the existing ones.
-	if !reflect.DeepEqual(dnsEndpointObject.Spec.Endpoints, dnsEndpoints) {
+	// The records are programmed in the DNS providers before the
+	// DNSEndpoint object is updated, so that the records of endpoints
+	// that are no longer desired can be determined on retry.
+	if err := d.providers.sync(dnsEndpointObject.Spec.Endpoints, dnsEndpoints, resync); err != nil {
+		return err
+	}
+
+	// Update only if the new endpoints or passthrough annotations are
+	// not equal to the existing ones.
+	annotationsChanged := updatePassthroughAnnotations(dnsEndpointObject, annotations)
+	if annotationsChanged || !reflect.DeepEqual(dnsEndpointObject.Spec.Endpoints, dnsEndpoints) {
 		dnsEndpointObject.Spec.Endpoints = dnsEndpoints
 		return d.client.Update(context.TODO(), dnsEndpointObject)
 	}
 
 	return nil
 }
+
+// updatePassthroughAnnotations replaces the external-dns annotations of
+// the DNSEndpoint object with the given annotations, leaving any other
+// annotations untouched.  It returns whether the annotations changed.
+func updatePassthroughAnnotations(dnsEndpointObject *feddnsv1a1.DNSEndpoint, annotations map[string]string) bool {
+	existing := PassthroughAnnotations(dnsEndpointObject.Annotations)
+	if reflect.DeepEqual(existing, annotations) {
+		return false
+	}
+	for key := range existing {
+		delete(dnsEndpointObject.Annotations, key)
+	}
+	if len(annotations) > 0 && dnsEndpointObject.Annotations == nil {
+		dnsEndpointObject.Annotations = make(map[string]string)
+	}
+	for key, value := range annotations {
+		dnsEndpointObject.Annotations[key] = value
+	}
+	return true
+}
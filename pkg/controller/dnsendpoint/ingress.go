@@ -17,8 +17,11 @@ limitations under the License.
 package dnsendpoint
 
 import (
+	"strings"
+
 	"github.com/pkg/errors"
 
+	"k8s.io/apimachinery/pkg/util/sets"
 	restclient "k8s.io/client-go/rest"
 
 	feddnsv1a1 "sigs.k8s.io/kubefed/pkg/apis/multiclusterdns/v1alpha1"
@@ -27,18 +30,25 @@ import (
 
 func StartIngressDNSEndpointController(config *util.ControllerConfig, stopChan <-chan struct{}) error {
 	restclient.AddUserAgent(config.KubeConfig, "Ingress DNSEndpoint")
-	controller, err := newDNSEndpointController(config, &feddnsv1a1.IngressDNSRecord{}, "ingress",
-		getIngressDNSEndpoints, config.MinimizeLatency)
+	mgr, shared, err := util.ManagerFor(config)
+	if err != nil {
+		return err
+	}
+	controller, err := newDNSEndpointController(config, mgr, &feddnsv1a1.IngressDNSRecord{}, "ingress",
+		getIngressDNSEndpoints, getIngressDNSAnnotations, config.MinimizeLatency)
 	if err != nil {
 		return err
 	}
 
 	go controller.Run(stopChan)
+	if !shared {
+		util.StartManager(mgr, stopChan)
+	}
 	return nil
 }
 
 // getIngressDNSEndpoints returns endpoint objects for each IngressDNSRecord object that should be processed.
-func getIngressDNSEndpoints(obj interface{}) ([]*feddnsv1a1.Endpoint, error) {
+func getIngressDNSEndpoints(obj interface{}, domainFor DomainFunc) ([]*feddnsv1a1.Endpoint, error) {
 	var endpoints []*feddnsv1a1.Endpoint
 
 	dnsObject, ok := obj.(*feddnsv1a1.IngressDNSRecord)
@@ -46,14 +56,13 @@ func getIngressDNSEndpoints(obj interface{}) ([]*feddnsv1a1.Endpoint, error) {
 		return nil, errors.Errorf("received event for unknown object %v", obj)
 	}
 
-	ttl := dnsObject.Spec.RecordTTL
-	if ttl == 0 {
-		ttl = defaultDNSTTL
-	}
-	for _, host := range dnsObject.Spec.Hosts {
+	ttl := recordTTL(dnsObject.Spec.RecordTTL)
+	for _, host := range recordHosts(dnsObject) {
 		var targets feddnsv1a1.Targets
 		for _, clusterDNS := range dnsObject.Status.DNS {
-			targets = append(targets, ExtractLoadBalancerTargets(clusterDNS.LoadBalancer)...)
+			if clusterServesHost(clusterDNS, host) {
+				targets = append(targets, ExtractLoadBalancerTargets(clusterDNS.LoadBalancer)...)
+			}
 		}
 		endpoint, err := generateEndpointForIngressDNSObject(host, targets, ttl)
 		if err != nil {
@@ -64,7 +73,75 @@ func getIngressDNSEndpoints(obj interface{}) ([]*feddnsv1a1.Endpoint, error) {
 		}
 	}
 
-	return DedupeAndMergeEndpoints(endpoints), nil
+	endpoints = DedupeAndMergeEndpoints(endpoints)
+	endpoints = applyRecordPolicy(endpoints, dnsObject.Spec.RecordTTL, domainFor)
+	setRecordProperties(endpoints, dnsObject.Spec.SetIdentifier, dnsObject.Spec.ProviderSpecific)
+	return endpoints, nil
+}
+
+// getIngressDNSAnnotations returns the annotations of the DNSEndpoint
+// object for an IngressDNSRecord object.
+func getIngressDNSAnnotations(obj interface{}) map[string]string {
+	dnsObject := obj.(*feddnsv1a1.IngressDNSRecord)
+	return PassthroughAnnotations(dnsObject.Annotations)
+}
+
+// recordHosts returns the normalized hosts for which records are
+// generated: the hosts of the spec, or the hosts served in any cluster
+// if the spec lists none.
+func recordHosts(dnsObject *feddnsv1a1.IngressDNSRecord) []string {
+	hosts := sets.NewString()
+	for _, host := range dnsObject.Spec.Hosts {
+		hosts.Insert(NormalizeHost(host))
+	}
+	if len(dnsObject.Spec.Hosts) == 0 {
+		for _, clusterDNS := range dnsObject.Status.DNS {
+			hosts.Insert(clusterDNS.Hosts...)
+		}
+	}
+	hosts.Delete("")
+	return hosts.List()
+}
+
+// clusterServesHost indicates whether the ingress in the cluster
+// serves the given host.  A cluster whose hosts are not recorded is
+// assumed to serve every host.
+func clusterServesHost(clusterDNS feddnsv1a1.ClusterIngressDNS, host string) bool {
+	if len(clusterDNS.Hosts) == 0 {
+		return true
+	}
+	for _, served := range clusterDNS.Hosts {
+		if HostMatches(NormalizeHost(served), host) {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeHost returns the given host in lower case and without a
+// trailing dot.
+func NormalizeHost(host string) string {
+	return strings.ToLower(strings.TrimSuffix(host, "."))
+}
+
+// HostMatches indicates whether a request for host would be served by
+// an ingress rule for pattern, following the wildcard semantics of
+// ingress hosts: a wildcard pattern (e.g. *.example.com) matches a
+// single leading label.  A wildcard host only matches the same
+// wildcard.  Both hosts must be normalized.
+func HostMatches(pattern, host string) bool {
+	if pattern == host {
+		return true
+	}
+	if !strings.HasPrefix(pattern, "*.") || strings.HasPrefix(host, "*.") {
+		return false
+	}
+	suffix := pattern[1:]
+	if !strings.HasSuffix(host, suffix) {
+		return false
+	}
+	label := strings.TrimSuffix(host, suffix)
+	return len(label) > 0 && !strings.Contains(label, ".")
 }
 
 func generateEndpointForIngressDNSObject(name string, targets feddnsv1a1.Targets, ttl feddnsv1a1.TTL) (ep *feddnsv1a1.Endpoint, err error) {
@@ -71,6 +71,36 @@ func TestGetEndpointsForIngressDNSObject(t *testing.T) {
 			},
 			expectError: false,
 		},
+		"SetIdentifierAndProviderSpecific": {
+			dnsObject: feddnsv1a1.IngressDNSRecord{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: namespace,
+				},
+				Spec: feddnsv1a1.IngressDNSRecordSpec{
+					Hosts:         []string{"foo.bar.test"},
+					SetIdentifier: "kubefed",
+					ProviderSpecific: feddnsv1a1.ProviderSpecific{
+						{Name: "aws/weight", Value: "10"},
+					},
+				},
+				Status: feddnsv1a1.IngressDNSRecordStatus{
+					DNS: []feddnsv1a1.ClusterIngressDNS{
+						{
+							Cluster:      c1,
+							LoadBalancer: v1.LoadBalancerStatus{Ingress: []v1.LoadBalancerIngress{{IP: lb1}}},
+						},
+					},
+				},
+			},
+			expectEndpoints: []*feddnsv1a1.Endpoint{
+				{
+					DNSName: "foo.bar.test", Targets: []string{lb1}, RecordType: RecordTypeA, RecordTTL: defaultDNSTTL,
+					SetIdentifier: "kubefed", ProviderSpecific: feddnsv1a1.ProviderSpecific{{Name: "aws/weight", Value: "10"}},
+				},
+			},
+			expectError: false,
+		},
 		"LBsInBothClusters": {
 			dnsObject: feddnsv1a1.IngressDNSRecord{
 				ObjectMeta: metav1.ObjectMeta{
@@ -177,11 +207,98 @@ func TestGetEndpointsForIngressDNSObject(t *testing.T) {
 			},
 			expectError: false,
 		},
+		"HostsPerCluster": {
+			dnsObject: feddnsv1a1.IngressDNSRecord{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: namespace,
+				},
+				Spec: feddnsv1a1.IngressDNSRecordSpec{
+					Hosts: []string{"foo.bar.test", "Jane.Goodall.test."},
+				},
+				Status: feddnsv1a1.IngressDNSRecordStatus{
+					DNS: []feddnsv1a1.ClusterIngressDNS{
+						{
+							Cluster:      c1,
+							LoadBalancer: v1.LoadBalancerStatus{Ingress: []v1.LoadBalancerIngress{{IP: lb1}}},
+							Hosts:        []string{"foo.bar.test", "jane.goodall.test"},
+						},
+						{
+							Cluster:      c2,
+							LoadBalancer: v1.LoadBalancerStatus{Ingress: []v1.LoadBalancerIngress{{IP: lb2}}},
+							Hosts:        []string{"foo.bar.test"},
+						},
+					},
+				},
+			},
+			expectEndpoints: []*feddnsv1a1.Endpoint{
+				{DNSName: "foo.bar.test", Targets: []string{lb1, lb2}, RecordType: RecordTypeA, RecordTTL: defaultDNSTTL},
+				{DNSName: "jane.goodall.test", Targets: []string{lb1}, RecordType: RecordTypeA, RecordTTL: defaultDNSTTL},
+			},
+			expectError: false,
+		},
+		"WildcardHosts": {
+			dnsObject: feddnsv1a1.IngressDNSRecord{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: namespace,
+				},
+				Spec: feddnsv1a1.IngressDNSRecordSpec{
+					Hosts: []string{"*.bar.test", "foo.bar.test"},
+				},
+				Status: feddnsv1a1.IngressDNSRecordStatus{
+					DNS: []feddnsv1a1.ClusterIngressDNS{
+						{
+							Cluster:      c1,
+							LoadBalancer: v1.LoadBalancerStatus{Ingress: []v1.LoadBalancerIngress{{IP: lb1}}},
+							Hosts:        []string{"*.bar.test"},
+						},
+						{
+							Cluster:      c2,
+							LoadBalancer: v1.LoadBalancerStatus{Ingress: []v1.LoadBalancerIngress{{IP: lb2}}},
+							Hosts:        []string{"foo.bar.test"},
+						},
+					},
+				},
+			},
+			expectEndpoints: []*feddnsv1a1.Endpoint{
+				{DNSName: "*.bar.test", Targets: []string{lb1}, RecordType: RecordTypeA, RecordTTL: defaultDNSTTL},
+				{DNSName: "foo.bar.test", Targets: []string{lb1, lb2}, RecordType: RecordTypeA, RecordTTL: defaultDNSTTL},
+			},
+			expectError: false,
+		},
+		"HostsFromClusters": {
+			dnsObject: feddnsv1a1.IngressDNSRecord{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: namespace,
+				},
+				Status: feddnsv1a1.IngressDNSRecordStatus{
+					DNS: []feddnsv1a1.ClusterIngressDNS{
+						{
+							Cluster:      c1,
+							LoadBalancer: v1.LoadBalancerStatus{Ingress: []v1.LoadBalancerIngress{{IP: lb1}}},
+							Hosts:        []string{"foo.bar.test", "secure.bar.test"},
+						},
+						{
+							Cluster:      c2,
+							LoadBalancer: v1.LoadBalancerStatus{Ingress: []v1.LoadBalancerIngress{{IP: lb2}}},
+							Hosts:        []string{"foo.bar.test"},
+						},
+					},
+				},
+			},
+			expectEndpoints: []*feddnsv1a1.Endpoint{
+				{DNSName: "foo.bar.test", Targets: []string{lb1, lb2}, RecordType: RecordTypeA, RecordTTL: defaultDNSTTL},
+				{DNSName: "secure.bar.test", Targets: []string{lb1}, RecordType: RecordTypeA, RecordTTL: defaultDNSTTL},
+			},
+			expectError: false,
+		},
 	}
 
 	for testName, tc := range testCases {
 		t.Run(testName, func(t *testing.T) {
-			endpoints, err := getIngressDNSEndpoints(&tc.dnsObject)
+			endpoints, err := getIngressDNSEndpoints(&tc.dnsObject, nil)
 			if !tc.expectError && err != nil {
 				t.Fatalf("Unexpected error: %v", err)
 			} else if tc.expectError && err == nil {
@@ -204,3 +321,24 @@ func TestGetEndpointsForIngressDNSObject(t *testing.T) {
 		})
 	}
 }
+
+func TestHostMatches(t *testing.T) {
+	testCases := []struct {
+		pattern, host string
+		expected      bool
+	}{
+		{"foo.bar.test", "foo.bar.test", true},
+		{"foo.bar.test", "baz.bar.test", false},
+		{"*.bar.test", "foo.bar.test", true},
+		{"*.bar.test", "*.bar.test", true},
+		{"*.bar.test", "bar.test", false},
+		{"*.bar.test", "foo.baz.bar.test", false},
+		{"foo.bar.test", "*.bar.test", false},
+		{"*.bar.test", "*.baz.bar.test", false},
+	}
+	for _, tc := range testCases {
+		if actual := HostMatches(tc.pattern, tc.host); actual != tc.expected {
+			t.Errorf("HostMatches(%q, %q): expected %v, got %v", tc.pattern, tc.host, tc.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/adal"
+	"github.com/Azure/go-autorest/autorest/azure"
+	"github.com/pkg/errors"
+
+	feddnsv1a1 "sigs.k8s.io/kubefed/pkg/apis/multiclusterdns/v1alpha1"
+)
+
+const (
+	azureDNSAPIVersion    = "2018-05-01"
+	azureDNSRecordSetPath = "/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}" +
+		"/providers/Microsoft.Network/dnsZones/{zoneName}/{recordType}/{relativeRecordSetName}"
+)
+
+type azureDNSProvider struct {
+	baseURI        string
+	subscriptionID string
+	resourceGroup  string
+	zone           string
+	// client authorizes requests with a token of the service
+	// principal that is refreshed as required.
+	client autorest.Client
+}
+
+func newAzureDNSProvider(config *feddnsv1a1.AzureDNSProvider, domain string, credentials map[string][]byte) (*azureDNSProvider, error) {
+	if len(config.SubscriptionID) == 0 || len(config.ResourceGroup) == 0 {
+		return nil, errors.New("azuredns: subscriptionID and resourceGroup are required")
+	}
+	tenantID, err := credential(credentials, "tenantID")
+	if err != nil {
+		return nil, errors.Wrap(err, "azuredns")
+	}
+	clientID, err := credential(credentials, "clientID")
+	if err != nil {
+		return nil, errors.Wrap(err, "azuredns")
+	}
+	clientSecret, err := credential(credentials, "clientSecret")
+	if err != nil {
+		return nil, errors.Wrap(err, "azuredns")
+	}
+
+	environment := azure.PublicCloud
+	oauthConfig, err := adal.NewOAuthConfig(environment.ActiveDirectoryEndpoint, tenantID)
+	if err != nil {
+		return nil, errors.Wrap(err, "azuredns")
+	}
+	servicePrincipalToken, err := adal.NewServicePrincipalToken(*oauthConfig, clientID, clientSecret, environment.ResourceManagerEndpoint)
+	if err != nil {
+		return nil, errors.Wrap(err, "azuredns")
+	}
+
+	zone := domain
+	if len(config.ZoneName) > 0 {
+		zone = normalizeName(config.ZoneName)
+	}
+	client := autorest.NewClientWithUserAgent("kubefed")
+	client.Authorizer = autorest.NewBearerAuthorizer(servicePrincipalToken)
+	client.Sender = newHTTPClient()
+	return &azureDNSProvider{
+		baseURI:        environment.ResourceManagerEndpoint,
+		subscriptionID: config.SubscriptionID,
+		resourceGroup:  config.ResourceGroup,
+		zone:           zone,
+		client:         client,
+	}, nil
+}
+
+type azureDNSRecordSet struct {
+	Properties azureDNSRecordSetProperties `json:"properties"`
+}
+
+type azureDNSRecordSetProperties struct {
+	TTL         int64                `json:"TTL"`
+	Metadata    map[string]string    `json:"metadata,omitempty"`
+	ARecords    []azureDNSARecord    `json:"ARecords,omitempty"`
+	AAAARecords []azureDNSAAAARecord `json:"AAAARecords,omitempty"`
+	CNAMERecord *azureDNSCNAMERecord `json:"CNAMERecord,omitempty"`
+	TXTRecords  []azureDNSTXTRecord  `json:"TXTRecords,omitempty"`
+}
+
+type azureDNSARecord struct {
+	IPv4Address string `json:"ipv4Address"`
+}
+
+type azureDNSAAAARecord struct {
+	IPv6Address string `json:"ipv6Address"`
+}
+
+type azureDNSCNAMERecord struct {
+	CNAME string `json:"cname"`
+}
+
+type azureDNSTXTRecord struct {
+	Value []string `json:"value"`
+}
+
+// Upsert replaces the record sets of the given endpoints.  Azure DNS
+// does not support weighted record sets, so the set identifier of an
+// endpoint is ignored.
+func (p *azureDNSProvider) Upsert(endpoints []*feddnsv1a1.Endpoint) error {
+	for _, endpoint := range endpoints {
+		recordSet, err := azureDNSRecordSetFor(endpoint)
+		if err != nil {
+			return err
+		}
+		pathParameters, err := p.recordSetPathParameters(endpoint)
+		if err != nil {
+			return err
+		}
+		err = p.do(pathParameters, http.StatusCreated,
+			autorest.AsContentType("application/json; charset=utf-8"),
+			autorest.AsPut(),
+			autorest.WithJSON(recordSet))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *azureDNSProvider) Delete(endpoints []*feddnsv1a1.Endpoint) error {
+	for _, endpoint := range endpoints {
+		pathParameters, err := p.recordSetPathParameters(endpoint)
+		if err != nil {
+			return err
+		}
+		// Deleting a record set that does not exist succeeds.
+		if err := p.do(pathParameters, http.StatusNoContent, autorest.AsDelete()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordSetPathParameters returns the parameters of the path of the
+// record set of the given endpoint.
+func (p *azureDNSProvider) recordSetPathParameters(endpoint *feddnsv1a1.Endpoint) (map[string]interface{}, error) {
+	name := normalizeName(endpoint.DNSName)
+	var relativeName string
+	switch {
+	case name == p.zone:
+		relativeName = "@"
+	case strings.HasSuffix(name, "."+p.zone):
+		relativeName = strings.TrimSuffix(name, "."+p.zone)
+	default:
+		return nil, errors.Errorf("azuredns: %s is not in zone %s", endpoint.DNSName, p.zone)
+	}
+	return map[string]interface{}{
+		"subscriptionId":        autorest.Encode("path", p.subscriptionID),
+		"resourceGroupName":     autorest.Encode("path", p.resourceGroup),
+		"zoneName":              autorest.Encode("path", p.zone),
+		"recordType":            autorest.Encode("path", endpoint.RecordType),
+		"relativeRecordSetName": autorest.Encode("path", relativeName),
+	}, nil
+}
+
+// do sends a request for the record set with the given path parameters,
+// prepared with the given decorators, and returns an error unless the
+// response has an OK status or the given status.
+func (p *azureDNSProvider) do(pathParameters map[string]interface{}, status int, decorators ...autorest.PrepareDecorator) error {
+	decorators = append(decorators,
+		autorest.WithBaseURL(p.baseURI),
+		autorest.WithPathParameters(azureDNSRecordSetPath, pathParameters),
+		autorest.WithQueryParameters(map[string]interface{}{"api-version": azureDNSAPIVersion}))
+	request, err := autorest.Prepare(&http.Request{}, decorators...)
+	if err != nil {
+		return errors.Wrap(err, "azuredns")
+	}
+	response, err := autorest.SendWithSender(p.client, request)
+	if err != nil {
+		return errors.Wrap(err, "azuredns")
+	}
+	err = autorest.Respond(response,
+		p.client.ByInspecting(),
+		azure.WithErrorUnlessStatusCode(http.StatusOK, status),
+		autorest.ByClosing())
+	return errors.Wrap(err, "azuredns")
+}
+
+func azureDNSRecordSetFor(endpoint *feddnsv1a1.Endpoint) (*azureDNSRecordSet, error) {
+	properties := azureDNSRecordSetProperties{TTL: int64(endpoint.RecordTTL)}
+	switch endpoint.RecordType {
+	case RecordTypeA:
+		for _, target := range endpoint.Targets {
+			properties.ARecords = append(properties.ARecords, azureDNSARecord{IPv4Address: target})
+		}
+	case RecordTypeAAAA:
+		for _, target := range endpoint.Targets {
+			properties.AAAARecords = append(properties.AAAARecords, azureDNSAAAARecord{IPv6Address: target})
+		}
+	case RecordTypeCNAME:
+		if len(endpoint.Targets) != 1 {
+			return nil, errors.Errorf("azuredns: CNAME record %s must have exactly one target", endpoint.DNSName)
+		}
+		properties.CNAMERecord = &azureDNSCNAMERecord{CNAME: normalizeName(endpoint.Targets[0])}
+	case RecordTypeTXT:
+		for _, target := range endpoint.Targets {
+			properties.TXTRecords = append(properties.TXTRecords, azureDNSTXTRecord{Value: []string{target}})
+		}
+	default:
+		return nil, errors.Errorf("azuredns: unsupported record type %q of %s", endpoint.RecordType, endpoint.DNSName)
+	}
+	return &azureDNSRecordSet{Properties: properties}, nil
+}
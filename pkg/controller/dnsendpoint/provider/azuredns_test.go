@@ -0,0 +1,134 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/adal"
+
+	feddnsv1a1 "sigs.k8s.io/kubefed/pkg/apis/multiclusterdns/v1alpha1"
+)
+
+func TestAzureDNSProvider(t *testing.T) {
+	type request struct {
+		method    string
+		path      string
+		recordSet azureDNSRecordSet
+	}
+	requests := []request{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" || r.URL.Query().Get("api-version") != azureDNSAPIVersion {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		req := request{method: r.Method, path: r.URL.Path}
+		switch r.Method {
+		case http.MethodPut:
+			body, _ := ioutil.ReadAll(r.Body)
+			if err := json.Unmarshal(body, &req.recordSet); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+			w.Write(body)
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		}
+		requests = append(requests, req)
+	}))
+	defer server.Close()
+
+	p, err := newAzureDNSProvider(&feddnsv1a1.AzureDNSProvider{
+		SubscriptionID: "sub",
+		ResourceGroup:  "rg",
+	}, "example.com", map[string][]byte{
+		"tenantID":     []byte("tenant"),
+		"clientID":     []byte("client"),
+		"clientSecret": []byte("secret"),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	p.baseURI = server.URL
+	p.client.Sender = server.Client()
+	p.client.Authorizer = autorest.NewBearerAuthorizer(&adal.Token{AccessToken: "token"})
+
+	err = p.Upsert([]*feddnsv1a1.Endpoint{
+		{DNSName: "app.example.com", RecordType: RecordTypeA, RecordTTL: 300, Targets: []string{"10.0.0.1", "10.0.0.2"}},
+		{DNSName: "example.com", RecordType: RecordTypeCNAME, RecordTTL: 60, Targets: []string{"lb.example.net."}},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	err = p.Delete([]*feddnsv1a1.Endpoint{{DNSName: "*.example.com", RecordType: RecordTypeTXT}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(requests) != 3 {
+		t.Fatalf("Expected 3 requests, got %d", len(requests))
+	}
+	zonePath := "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/dnsZones/example.com"
+	upsert := requests[0]
+	if upsert.method != http.MethodPut || upsert.path != zonePath+"/A/app" || upsert.recordSet.Properties.TTL != 300 ||
+		len(upsert.recordSet.Properties.ARecords) != 2 || upsert.recordSet.Properties.ARecords[1].IPv4Address != "10.0.0.2" {
+		t.Errorf("Unexpected upsert %+v", upsert)
+	}
+	// The apex of the zone is named @.
+	apex := requests[1]
+	if apex.path != zonePath+"/CNAME/@" || apex.recordSet.Properties.CNAMERecord == nil ||
+		apex.recordSet.Properties.CNAMERecord.CNAME != "lb.example.net" {
+		t.Errorf("Unexpected upsert %+v", apex)
+	}
+	deletion := requests[2]
+	if deletion.method != http.MethodDelete || deletion.path != zonePath+"/TXT/*" {
+		t.Errorf("Unexpected deletion %+v", deletion)
+	}
+
+	err = p.Upsert([]*feddnsv1a1.Endpoint{{DNSName: "app.example.org", RecordType: RecordTypeA, Targets: []string{"10.0.0.1"}}})
+	if err == nil {
+		t.Errorf("Expected an error for a record outside of the zone")
+	}
+}
+
+func TestAzureDNSProviderError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":{"code":"BadRequest","message":"The record set is invalid."}}`))
+	}))
+	defer server.Close()
+
+	p := &azureDNSProvider{
+		baseURI:        server.URL,
+		subscriptionID: "sub",
+		resourceGroup:  "rg",
+		zone:           "example.com",
+		client:         autorest.NewClientWithUserAgent("test"),
+	}
+	p.client.Sender = server.Client()
+
+	err := p.Upsert([]*feddnsv1a1.Endpoint{{DNSName: "app.example.com", RecordType: RecordTypeA, Targets: []string{"10.0.0.1"}}})
+	if err == nil {
+		t.Fatalf("Expected an error for a failed request")
+	}
+}
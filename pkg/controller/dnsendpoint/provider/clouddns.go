@@ -0,0 +1,200 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+
+	feddnsv1a1 "sigs.k8s.io/kubefed/pkg/apis/multiclusterdns/v1alpha1"
+)
+
+const (
+	cloudDNSEndpoint = "https://dns.googleapis.com/dns/v1"
+	cloudDNSScope    = "https://www.googleapis.com/auth/ndev.clouddns.readwrite"
+)
+
+type cloudDNSProvider struct {
+	endpoint    string
+	project     string
+	managedZone string
+	client      *http.Client
+}
+
+func newCloudDNSProvider(config *feddnsv1a1.CloudDNSProvider, credentials map[string][]byte) (*cloudDNSProvider, error) {
+	if len(config.Project) == 0 || len(config.ManagedZone) == 0 {
+		return nil, errors.New("clouddns: project and managedZone are required")
+	}
+	key, ok := credentials["credentials.json"]
+	if !ok || len(key) == 0 {
+		return nil, errors.New(`clouddns: the credentials secret does not contain "credentials.json"`)
+	}
+	jwtConfig, err := google.JWTConfigFromJSON(key, cloudDNSScope)
+	if err != nil {
+		return nil, errors.Wrap(err, "clouddns: invalid service account key")
+	}
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, newHTTPClient())
+	client := jwtConfig.Client(ctx)
+	client.Timeout = requestTimeout
+	return &cloudDNSProvider{
+		endpoint:    cloudDNSEndpoint,
+		project:     config.Project,
+		managedZone: config.ManagedZone,
+		client:      client,
+	}, nil
+}
+
+type cloudDNSRecordSet struct {
+	Name    string   `json:"name"`
+	Type    string   `json:"type"`
+	TTL     int64    `json:"ttl,omitempty"`
+	Rrdatas []string `json:"rrdatas"`
+}
+
+type cloudDNSChange struct {
+	Additions []cloudDNSRecordSet `json:"additions,omitempty"`
+	Deletions []cloudDNSRecordSet `json:"deletions,omitempty"`
+}
+
+type cloudDNSListResponse struct {
+	Rrsets []cloudDNSRecordSet `json:"rrsets"`
+}
+
+// Upsert replaces the record sets of the given endpoints.  Cloud DNS does
+// not support weighted record sets, so the set identifier of an endpoint
+// is ignored.
+func (p *cloudDNSProvider) Upsert(endpoints []*feddnsv1a1.Endpoint) error {
+	change := cloudDNSChange{}
+	for _, endpoint := range endpoints {
+		existing, err := p.getRecordSet(endpoint)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			change.Deletions = append(change.Deletions, *existing)
+		}
+		change.Additions = append(change.Additions, cloudDNSRecordSetFor(endpoint))
+	}
+	return p.applyChange(change)
+}
+
+func (p *cloudDNSProvider) Delete(endpoints []*feddnsv1a1.Endpoint) error {
+	change := cloudDNSChange{}
+	for _, endpoint := range endpoints {
+		existing, err := p.getRecordSet(endpoint)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			change.Deletions = append(change.Deletions, *existing)
+		}
+	}
+	return p.applyChange(change)
+}
+
+func (p *cloudDNSProvider) applyChange(change cloudDNSChange) error {
+	if len(change.Additions) == 0 && len(change.Deletions) == 0 {
+		return nil
+	}
+	body, err := json.Marshal(change)
+	if err != nil {
+		return err
+	}
+	_, err = p.do(http.MethodPost, "/changes", nil, body)
+	return err
+}
+
+// getRecordSet returns the record set of the given endpoint, or nil if
+// it does not exist.
+func (p *cloudDNSProvider) getRecordSet(endpoint *feddnsv1a1.Endpoint) (*cloudDNSRecordSet, error) {
+	query := url.Values{}
+	query.Set("name", fqdn(endpoint.DNSName))
+	query.Set("type", endpoint.RecordType)
+	body, err := p.do(http.MethodGet, "/rrsets", query, nil)
+	if err != nil {
+		return nil, err
+	}
+	response := &cloudDNSListResponse{}
+	if err := json.Unmarshal(body, response); err != nil {
+		return nil, errors.Wrap(err, "clouddns: failed to decode the record sets")
+	}
+	for _, recordSet := range response.Rrsets {
+		if normalizeName(recordSet.Name) == normalizeName(endpoint.DNSName) && recordSet.Type == endpoint.RecordType {
+			return &recordSet, nil
+		}
+	}
+	return nil, nil
+}
+
+func (p *cloudDNSProvider) do(method, path string, query url.Values, body []byte) ([]byte, error) {
+	requestURL := fmt.Sprintf("%s/projects/%s/managedZones/%s%s", p.endpoint,
+		url.PathEscape(p.project), url.PathEscape(p.managedZone), path)
+	if len(query) > 0 {
+		requestURL += "?" + query.Encode()
+	}
+	request, err := http.NewRequest(method, requestURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	response, err := p.client.Do(request)
+	if err != nil {
+		return nil, errors.Wrap(err, "clouddns")
+	}
+	defer response.Body.Close()
+	responseBody, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "clouddns")
+	}
+	if response.StatusCode/100 != 2 {
+		return nil, errors.Wrap(responseError(response, responseBody), "clouddns")
+	}
+	return responseBody, nil
+}
+
+func cloudDNSRecordSetFor(endpoint *feddnsv1a1.Endpoint) cloudDNSRecordSet {
+	recordSet := cloudDNSRecordSet{
+		Name:    fqdn(endpoint.DNSName),
+		Type:    endpoint.RecordType,
+		TTL:     int64(endpoint.RecordTTL),
+		Rrdatas: []string{},
+	}
+	for _, target := range endpoint.Targets {
+		value := target
+		switch endpoint.RecordType {
+		case RecordTypeCNAME:
+			value = fqdn(target)
+		case RecordTypeTXT:
+			value = strconv.Quote(target)
+		}
+		recordSet.Rrdatas = append(recordSet.Rrdatas, value)
+	}
+	return recordSet
+}
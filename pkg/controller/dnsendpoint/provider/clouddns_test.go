@@ -0,0 +1,119 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	feddnsv1a1 "sigs.k8s.io/kubefed/pkg/apis/multiclusterdns/v1alpha1"
+)
+
+func TestCloudDNSProvider(t *testing.T) {
+	changes := []cloudDNSChange{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		const zonePath = "/projects/project/managedZones/zone"
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == zonePath+"/rrsets":
+			// Only the A record set of the wildcard exists.
+			if r.URL.Query().Get("type") != RecordTypeA {
+				fmt.Fprint(w, `{"rrsets":[]}`)
+				return
+			}
+			fmt.Fprint(w, `{"rrsets":[{"name":"*.example.com.","type":"A","ttl":60,"rrdatas":["10.0.0.1"]}]}`)
+		case r.Method == http.MethodPost && r.URL.Path == zonePath+"/changes":
+			body, _ := ioutil.ReadAll(r.Body)
+			change := cloudDNSChange{}
+			if err := json.Unmarshal(body, &change); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			changes = append(changes, change)
+			fmt.Fprint(w, `{"status":"pending"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	p := &cloudDNSProvider{
+		endpoint:    server.URL,
+		project:     "project",
+		managedZone: "zone",
+		client:      server.Client(),
+	}
+
+	err := p.Upsert([]*feddnsv1a1.Endpoint{
+		{DNSName: "*.example.com", RecordType: RecordTypeA, RecordTTL: 300, Targets: []string{"10.0.0.2"}},
+		{DNSName: "app.example.com", RecordType: RecordTypeTXT, RecordTTL: 300, Targets: []string{"owner=kubefed"}},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	err = p.Delete([]*feddnsv1a1.Endpoint{
+		{DNSName: "*.example.com", RecordType: RecordTypeA},
+		{DNSName: "missing.example.com", RecordType: RecordTypeAAAA},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(changes) != 2 {
+		t.Fatalf("Expected 2 changes, got %d", len(changes))
+	}
+	// The existing record set is replaced by deleting it with its
+	// current values.
+	upsert := changes[0]
+	if len(upsert.Deletions) != 1 || upsert.Deletions[0].Rrdatas[0] != "10.0.0.1" || len(upsert.Additions) != 2 ||
+		upsert.Additions[0].Rrdatas[0] != "10.0.0.2" || upsert.Additions[1].Rrdatas[0] != `"owner=kubefed"` {
+		t.Errorf("Unexpected upsert %+v", upsert)
+	}
+	deletion := changes[1]
+	if len(deletion.Additions) != 0 || len(deletion.Deletions) != 1 || deletion.Deletions[0].Name != "*.example.com." {
+		t.Errorf("Unexpected deletion %+v", deletion)
+	}
+}
+
+func TestCloudDNSProviderError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"error":{"code":403,"message":"Forbidden"}}`)
+	}))
+	defer server.Close()
+
+	p := &cloudDNSProvider{
+		endpoint:    server.URL,
+		project:     "project",
+		managedZone: "zone",
+		client:      server.Client(),
+	}
+	err := p.Upsert([]*feddnsv1a1.Endpoint{{DNSName: "app.example.com", RecordType: RecordTypeA, Targets: []string{"10.0.0.1"}}})
+	if err == nil {
+		t.Fatalf("Expected an error for a failed request")
+	}
+}
+
+func TestNewCloudDNSProviderRequiresCredentials(t *testing.T) {
+	_, err := newCloudDNSProvider(&feddnsv1a1.CloudDNSProvider{Project: "project", ManagedZone: "zone"}, nil)
+	if err == nil {
+		t.Errorf("Expected an error for missing credentials")
+	}
+}
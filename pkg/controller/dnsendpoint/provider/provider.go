@@ -0,0 +1,130 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package provider programs the records of DNSEndpoint objects in DNS
+// providers directly, for users that do not run external-dns.
+package provider
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	feddnsv1a1 "sigs.k8s.io/kubefed/pkg/apis/multiclusterdns/v1alpha1"
+)
+
+const (
+	RecordTypeA     = "A"
+	RecordTypeAAAA  = "AAAA"
+	RecordTypeCNAME = "CNAME"
+	RecordTypeTXT   = "TXT"
+
+	// requestTimeout is the timeout of the requests to the API of a
+	// provider.
+	requestTimeout = 30 * time.Second
+)
+
+// Provider programs the record sets of a DNS zone.  A record set is
+// identified by the DNS name, record type and set identifier of an
+// endpoint.
+type Provider interface {
+	// Upsert creates or replaces the record sets of the given
+	// endpoints.
+	Upsert(endpoints []*feddnsv1a1.Endpoint) error
+	// Delete deletes the record sets of the given endpoints.  Record
+	// sets that do not exist are ignored.
+	Delete(endpoints []*feddnsv1a1.Endpoint) error
+}
+
+// New returns the provider configured for the given domain,
+// authenticated with the data of its credentials secret.
+func New(domain *feddnsv1a1.Domain, credentials map[string][]byte) (Provider, error) {
+	config := domain.Provider
+	if config == nil {
+		return nil, errors.Errorf("no provider is configured for domain %q", domain.Name)
+	}
+
+	configured := 0
+	for _, set := range []bool{config.Route53 != nil, config.CloudDNS != nil, config.AzureDNS != nil, config.RFC2136 != nil} {
+		if set {
+			configured++
+		}
+	}
+	if configured != 1 {
+		return nil, errors.Errorf("exactly one provider must be configured for domain %q", domain.Name)
+	}
+
+	zone := normalizeName(domain.Domain)
+	switch {
+	case config.Route53 != nil:
+		return newRoute53Provider(config.Route53, credentials)
+	case config.CloudDNS != nil:
+		return newCloudDNSProvider(config.CloudDNS, credentials)
+	case config.AzureDNS != nil:
+		return newAzureDNSProvider(config.AzureDNS, zone, credentials)
+	default:
+		return newRFC2136Provider(config.RFC2136, zone, credentials)
+	}
+}
+
+// normalizeName returns the given DNS name in lower case without a
+// trailing dot.
+func normalizeName(name string) string {
+	return strings.ToLower(strings.TrimSuffix(name, "."))
+}
+
+// fqdn returns the given DNS name with a trailing dot.
+func fqdn(name string) string {
+	return normalizeName(name) + "."
+}
+
+// credential returns the value of the given key of the credentials, or
+// an error if it is not set.
+func credential(credentials map[string][]byte, key string) (string, error) {
+	value, ok := credentials[key]
+	if !ok || len(value) == 0 {
+		return "", errors.Errorf("the credentials secret does not contain %q", key)
+	}
+	return strings.TrimSpace(string(value)), nil
+}
+
+// providerSpecificValue returns the value of the named provider
+// specific property of the endpoint.
+func providerSpecificValue(endpoint *feddnsv1a1.Endpoint, name string) (string, bool) {
+	for _, property := range endpoint.ProviderSpecific {
+		if property.Name == name {
+			return property.Value, true
+		}
+	}
+	return "", false
+}
+
+func newHTTPClient() *http.Client {
+	return &http.Client{Timeout: requestTimeout}
+}
+
+// responseError returns an error describing the failed response of a
+// provider, including the start of its body.
+func responseError(response *http.Response, body []byte) error {
+	const maxLength = 512
+	if len(body) > maxLength {
+		body = body[:maxLength]
+	}
+	return errors.Errorf("%s %s returned %s: %s", response.Request.Method, response.Request.URL.Path,
+		response.Status, strings.TrimSpace(string(body)))
+}
@@ -0,0 +1,397 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	feddnsv1a1 "sigs.k8s.io/kubefed/pkg/apis/multiclusterdns/v1alpha1"
+)
+
+const (
+	dnsTypeA     = 1
+	dnsTypeCNAME = 5
+	dnsTypeSOA   = 6
+	dnsTypeTXT   = 16
+	dnsTypeAAAA  = 28
+	dnsTypeTSIG  = 250
+
+	dnsClassIN  = 1
+	dnsClassANY = 255
+
+	// dnsFlagsUpdate are the header flags of an UPDATE message, whose
+	// opcode is 5.
+	dnsFlagsUpdate = 5 << 11
+
+	// tsigFudge is the permitted difference in seconds between the
+	// clocks of the controller and the name server.
+	tsigFudge = 300
+)
+
+var dnsRecordTypes = map[string]uint16{
+	RecordTypeA:     dnsTypeA,
+	RecordTypeAAAA:  dnsTypeAAAA,
+	RecordTypeCNAME: dnsTypeCNAME,
+	RecordTypeTXT:   dnsTypeTXT,
+}
+
+var dnsRcodes = map[uint16]string{
+	1:  "FORMERR",
+	2:  "SERVFAIL",
+	3:  "NXDOMAIN",
+	4:  "NOTIMP",
+	5:  "REFUSED",
+	6:  "YXDOMAIN",
+	7:  "YXRRSET",
+	8:  "NXRRSET",
+	9:  "NOTAUTH",
+	10: "NOTZONE",
+}
+
+var tsigAlgorithms = map[string]func() hash.Hash{
+	"hmac-sha256": sha256.New,
+	"hmac-sha512": sha512.New,
+	"hmac-sha1":   sha1.New,
+}
+
+type tsigKey struct {
+	name      string
+	algorithm string
+	secret    []byte
+}
+
+type rfc2136Provider struct {
+	server string
+	zone   string
+	key    *tsigKey
+	now    func() time.Time
+}
+
+func newRFC2136Provider(config *feddnsv1a1.RFC2136Provider, domain string, credentials map[string][]byte) (*rfc2136Provider, error) {
+	if len(config.Server) == 0 {
+		return nil, errors.New("rfc2136: server is required")
+	}
+	if _, _, err := net.SplitHostPort(config.Server); err != nil {
+		return nil, errors.Wrapf(err, "rfc2136: invalid server %q", config.Server)
+	}
+	zone := domain
+	if len(config.Zone) > 0 {
+		zone = normalizeName(config.Zone)
+	}
+	p := &rfc2136Provider{
+		server: config.Server,
+		zone:   zone,
+		now:    time.Now,
+	}
+
+	if len(config.TSIGKeyName) == 0 {
+		return p, nil
+	}
+	algorithm := strings.ToLower(config.TSIGAlgorithm)
+	if len(algorithm) == 0 {
+		algorithm = "hmac-sha256"
+	}
+	if _, ok := tsigAlgorithms[algorithm]; !ok {
+		return nil, errors.Errorf("rfc2136: unsupported TSIG algorithm %q", config.TSIGAlgorithm)
+	}
+	encodedSecret, err := credential(credentials, "tsigSecret")
+	if err != nil {
+		return nil, errors.Wrap(err, "rfc2136")
+	}
+	secret, err := base64.StdEncoding.DecodeString(encodedSecret)
+	if err != nil {
+		return nil, errors.Wrap(err, "rfc2136: the TSIG secret is not base64 encoded")
+	}
+	p.key = &tsigKey{
+		name:      normalizeName(config.TSIGKeyName),
+		algorithm: algorithm,
+		secret:    secret,
+	}
+	return p, nil
+}
+
+// Upsert replaces the record sets of the given endpoints in a single
+// update.  Name servers do not support weighted record sets, so the set
+// identifier of an endpoint is ignored.
+func (p *rfc2136Provider) Upsert(endpoints []*feddnsv1a1.Endpoint) error {
+	return p.update(endpoints, true)
+}
+
+func (p *rfc2136Provider) Delete(endpoints []*feddnsv1a1.Endpoint) error {
+	return p.update(endpoints, false)
+}
+
+func (p *rfc2136Provider) update(endpoints []*feddnsv1a1.Endpoint, add bool) error {
+	if len(endpoints) == 0 {
+		return nil
+	}
+	message, err := p.updateMessage(endpoints, add)
+	if err != nil {
+		return err
+	}
+	return p.exchange(message)
+}
+
+// updateMessage returns an UPDATE message that deletes the record sets
+// of the given endpoints and, if add is true, adds their records.
+func (p *rfc2136Provider) updateMessage(endpoints []*feddnsv1a1.Endpoint, add bool) ([]byte, error) {
+	builder := &dnsBuilder{}
+	updates := uint16(0)
+	for _, endpoint := range endpoints {
+		name := normalizeName(endpoint.DNSName)
+		if name != p.zone && !strings.HasSuffix(name, "."+p.zone) {
+			return nil, errors.Errorf("rfc2136: %s is not in zone %s", endpoint.DNSName, p.zone)
+		}
+		recordType, ok := dnsRecordTypes[endpoint.RecordType]
+		if !ok {
+			return nil, errors.Errorf("rfc2136: unsupported record type %q of %s", endpoint.RecordType, endpoint.DNSName)
+		}
+
+		// Delete the record set.
+		if err := builder.resourceRecord(name, recordType, dnsClassANY, 0, nil); err != nil {
+			return nil, err
+		}
+		updates++
+		if !add {
+			continue
+		}
+		for _, target := range endpoint.Targets {
+			data, err := rdata(recordType, target)
+			if err != nil {
+				return nil, errors.Wrapf(err, "rfc2136: invalid target of %s", endpoint.DNSName)
+			}
+			if err := builder.resourceRecord(name, recordType, dnsClassIN, uint32(endpoint.RecordTTL), data); err != nil {
+				return nil, err
+			}
+			updates++
+		}
+	}
+
+	id := make([]byte, 2)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	header := &dnsBuilder{}
+	header.bytes(id)
+	header.uint16(dnsFlagsUpdate)
+	// One zone, no prerequisites, the updates and no additional
+	// records.
+	header.uint16(1)
+	header.uint16(0)
+	header.uint16(updates)
+	header.uint16(0)
+	if err := header.name(p.zone); err != nil {
+		return nil, err
+	}
+	header.uint16(dnsTypeSOA)
+	header.uint16(dnsClassIN)
+	message := append(header.buf, builder.buf...)
+
+	if p.key != nil {
+		return p.key.sign(message, p.now())
+	}
+	return message, nil
+}
+
+// exchange sends the given message to the name server over TCP and
+// checks the response code of its response.
+func (p *rfc2136Provider) exchange(message []byte) error {
+	conn, err := net.DialTimeout("tcp", p.server, requestTimeout)
+	if err != nil {
+		return errors.Wrap(err, "rfc2136")
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(requestTimeout)); err != nil {
+		return errors.Wrap(err, "rfc2136")
+	}
+
+	request := make([]byte, 2, 2+len(message))
+	binary.BigEndian.PutUint16(request, uint16(len(message)))
+	if _, err := conn.Write(append(request, message...)); err != nil {
+		return errors.Wrap(err, "rfc2136")
+	}
+
+	length := make([]byte, 2)
+	if _, err := io.ReadFull(conn, length); err != nil {
+		return errors.Wrap(err, "rfc2136: failed to read the response")
+	}
+	response := make([]byte, binary.BigEndian.Uint16(length))
+	if _, err := io.ReadFull(conn, response); err != nil {
+		return errors.Wrap(err, "rfc2136: failed to read the response")
+	}
+	if len(response) < 12 || response[0] != message[0] || response[1] != message[1] {
+		return errors.New("rfc2136: invalid response")
+	}
+	rcode := binary.BigEndian.Uint16(response[2:4]) & 0xf
+	if rcode != 0 {
+		name, ok := dnsRcodes[rcode]
+		if !ok {
+			name = fmt.Sprintf("RCODE %d", rcode)
+		}
+		return errors.Errorf("rfc2136: the update of zone %s failed with %s", p.zone, name)
+	}
+	return nil
+}
+
+// sign returns the given message with a TSIG record as per RFC 8945.
+func (k *tsigKey) sign(message []byte, now time.Time) ([]byte, error) {
+	timeSigned := make([]byte, 6)
+	seconds := uint64(now.Unix())
+	for i := 5; i >= 0; i-- {
+		timeSigned[i] = byte(seconds)
+		seconds >>= 8
+	}
+
+	variables := &dnsBuilder{}
+	if err := variables.name(k.name); err != nil {
+		return nil, err
+	}
+	variables.uint16(dnsClassANY)
+	variables.uint32(0)
+	if err := variables.name(k.algorithm); err != nil {
+		return nil, err
+	}
+	variables.bytes(timeSigned)
+	variables.uint16(tsigFudge)
+	// No error and no other data.
+	variables.uint16(0)
+	variables.uint16(0)
+
+	mac := hmac.New(tsigAlgorithms[k.algorithm], k.secret)
+	mac.Write(message)
+	mac.Write(variables.buf)
+	sum := mac.Sum(nil)
+
+	data := &dnsBuilder{}
+	if err := data.name(k.algorithm); err != nil {
+		return nil, err
+	}
+	data.bytes(timeSigned)
+	data.uint16(tsigFudge)
+	data.uint16(uint16(len(sum)))
+	data.bytes(sum)
+	data.bytes(message[0:2])
+	data.uint16(0)
+	data.uint16(0)
+
+	signed := &dnsBuilder{buf: append([]byte{}, message...)}
+	if err := signed.resourceRecord(k.name, dnsTypeTSIG, dnsClassANY, 0, data.buf); err != nil {
+		return nil, err
+	}
+	additional := binary.BigEndian.Uint16(signed.buf[10:12])
+	binary.BigEndian.PutUint16(signed.buf[10:12], additional+1)
+	return signed.buf, nil
+}
+
+// rdata returns the wire format of the given target of a record.
+func rdata(recordType uint16, target string) ([]byte, error) {
+	switch recordType {
+	case dnsTypeA:
+		ip := net.ParseIP(target).To4()
+		if ip == nil {
+			return nil, errors.Errorf("%q is not an IPv4 address", target)
+		}
+		return ip, nil
+	case dnsTypeAAAA:
+		ip := net.ParseIP(target)
+		if ip == nil || ip.To4() != nil {
+			return nil, errors.Errorf("%q is not an IPv6 address", target)
+		}
+		return ip.To16(), nil
+	case dnsTypeCNAME:
+		builder := &dnsBuilder{}
+		if err := builder.name(normalizeName(target)); err != nil {
+			return nil, err
+		}
+		return builder.buf, nil
+	default:
+		// A TXT record holds character strings of up to 255 bytes.
+		builder := &dnsBuilder{}
+		for len(target) > 255 {
+			builder.characterString(target[:255])
+			target = target[255:]
+		}
+		builder.characterString(target)
+		return builder.buf, nil
+	}
+}
+
+// dnsBuilder builds the wire format of a DNS message without name
+// compression.
+type dnsBuilder struct {
+	buf []byte
+}
+
+func (b *dnsBuilder) bytes(data []byte) {
+	b.buf = append(b.buf, data...)
+}
+
+func (b *dnsBuilder) uint16(value uint16) {
+	b.buf = append(b.buf, byte(value>>8), byte(value))
+}
+
+func (b *dnsBuilder) uint32(value uint32) {
+	b.buf = append(b.buf, byte(value>>24), byte(value>>16), byte(value>>8), byte(value))
+}
+
+func (b *dnsBuilder) characterString(value string) {
+	b.buf = append(b.buf, byte(len(value)))
+	b.buf = append(b.buf, value...)
+}
+
+func (b *dnsBuilder) name(name string) error {
+	name = strings.TrimSuffix(name, ".")
+	if len(name) > 253 {
+		return errors.Errorf("DNS name %q is too long", name)
+	}
+	if len(name) > 0 {
+		for _, label := range strings.Split(name, ".") {
+			if len(label) == 0 || len(label) > 63 {
+				return errors.Errorf("DNS name %q has an invalid label", name)
+			}
+			b.characterString(label)
+		}
+	}
+	b.buf = append(b.buf, 0)
+	return nil
+}
+
+func (b *dnsBuilder) resourceRecord(name string, recordType, class uint16, ttl uint32, data []byte) error {
+	if err := b.name(name); err != nil {
+		return err
+	}
+	b.uint16(recordType)
+	b.uint16(class)
+	b.uint32(ttl)
+	b.uint16(uint16(len(data)))
+	b.bytes(data)
+	return nil
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	feddnsv1a1 "sigs.k8s.io/kubefed/pkg/apis/multiclusterdns/v1alpha1"
+)
+
+// fakeNameServer accepts a single update and responds with the given
+// response code.
+func fakeNameServer(t *testing.T, rcode uint16, messages chan<- []byte) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	go func() {
+		defer listener.Close()
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		length := make([]byte, 2)
+		if _, err := io.ReadFull(conn, length); err != nil {
+			return
+		}
+		message := make([]byte, binary.BigEndian.Uint16(length))
+		if _, err := io.ReadFull(conn, message); err != nil {
+			return
+		}
+		messages <- message
+
+		response := &dnsBuilder{}
+		response.uint16(uint16(len(message[:12])))
+		response.bytes(message[0:2])
+		response.uint16(dnsFlagsUpdate | 1<<15 | rcode)
+		response.bytes(make([]byte, 8))
+		conn.Write(response.buf)
+	}()
+	return listener.Addr().String()
+}
+
+func TestRFC2136Provider(t *testing.T) {
+	endpoints := []*feddnsv1a1.Endpoint{{
+		DNSName:    "app.example.com",
+		RecordType: RecordTypeA,
+		RecordTTL:  300,
+		Targets:    []string{"10.0.0.1", "10.0.0.2"},
+	}}
+	config := &feddnsv1a1.RFC2136Provider{TSIGKeyName: "kubefed"}
+	credentials := map[string][]byte{"tsigSecret": []byte("c2VjcmV0")}
+
+	messages := make(chan []byte, 1)
+	config.Server = fakeNameServer(t, 0, messages)
+	p, err := newRFC2136Provider(config, "example.com", credentials)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	now := time.Unix(1500000000, 0)
+	p.now = func() time.Time { return now }
+	if err := p.Upsert(endpoints); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	message := <-messages
+	if flags := binary.BigEndian.Uint16(message[2:4]); flags != dnsFlagsUpdate {
+		t.Errorf("Expected flags %#x, got %#x", dnsFlagsUpdate, flags)
+	}
+	// One deletion of the record set and one addition per target.
+	if updates := binary.BigEndian.Uint16(message[8:10]); updates != 3 {
+		t.Errorf("Expected 3 updates, got %d", updates)
+	}
+	if additional := binary.BigEndian.Uint16(message[10:12]); additional != 1 {
+		t.Fatalf("Expected a TSIG record, got %d additional records", additional)
+	}
+
+	// Verify the MAC of the TSIG record at the end of the message.
+	p.key = nil
+	unsigned, err := p.updateMessage(endpoints, true)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	copy(unsigned[0:2], message[0:2])
+	if !bytes.HasPrefix(message[12:], unsigned[12:]) {
+		t.Fatalf("Expected the signed message to start with the update")
+	}
+	variables := &dnsBuilder{}
+	variables.name("kubefed")
+	variables.uint16(dnsClassANY)
+	variables.uint32(0)
+	variables.name("hmac-sha256")
+	variables.bytes([]byte{0, 0, 0x59, 0x68, 0x2f, 0x00})
+	variables.uint16(tsigFudge)
+	variables.uint16(0)
+	variables.uint16(0)
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(unsigned)
+	mac.Write(variables.buf)
+	if !bytes.Contains(message[len(unsigned):], mac.Sum(nil)) {
+		t.Errorf("Expected the TSIG record to contain a valid MAC")
+	}
+
+	config.Server = fakeNameServer(t, 5, messages)
+	p, err = newRFC2136Provider(config, "example.com", credentials)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	err = p.Delete(endpoints)
+	<-messages
+	if err == nil || !strings.Contains(err.Error(), "REFUSED") {
+		t.Errorf("Expected a REFUSED error, got %v", err)
+	}
+
+	if err := p.Upsert([]*feddnsv1a1.Endpoint{{DNSName: "app.example.org", RecordType: RecordTypeA}}); err == nil {
+		t.Errorf("Expected an error for a name outside of the zone")
+	}
+}
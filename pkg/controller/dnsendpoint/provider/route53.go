@@ -0,0 +1,257 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	awscredentials "github.com/aws/aws-sdk-go/aws/credentials"
+	v4 "github.com/aws/aws-sdk-go/aws/signer/v4"
+	"github.com/pkg/errors"
+
+	feddnsv1a1 "sigs.k8s.io/kubefed/pkg/apis/multiclusterdns/v1alpha1"
+)
+
+const (
+	route53Endpoint   = "https://route53.amazonaws.com"
+	route53APIVersion = "2013-04-01"
+	route53Namespace  = "https://route53.amazonaws.com/doc/2013-04-01/"
+	// The Route53 API is served from us-east-1.
+	route53Region  = "us-east-1"
+	route53Service = "route53"
+
+	// route53WeightProperty is the provider specific property of an
+	// endpoint that sets the weight of its record set, as used by
+	// external-dns.
+	route53WeightProperty = "aws/weight"
+
+	route53ActionUpsert = "UPSERT"
+	route53ActionDelete = "DELETE"
+)
+
+type route53Provider struct {
+	endpoint     string
+	hostedZoneID string
+	signer       *v4.Signer
+	client       *http.Client
+	now          func() time.Time
+}
+
+func newRoute53Provider(config *feddnsv1a1.Route53Provider, credentials map[string][]byte) (*route53Provider, error) {
+	if len(config.HostedZoneID) == 0 {
+		return nil, errors.New("route53: hostedZoneID is required")
+	}
+	accessKeyID, err := credential(credentials, "aws_access_key_id")
+	if err != nil {
+		return nil, errors.Wrap(err, "route53")
+	}
+	secretAccessKey, err := credential(credentials, "aws_secret_access_key")
+	if err != nil {
+		return nil, errors.Wrap(err, "route53")
+	}
+	sessionToken := strings.TrimSpace(string(credentials["aws_session_token"]))
+	return &route53Provider{
+		endpoint:     route53Endpoint,
+		hostedZoneID: strings.TrimPrefix(config.HostedZoneID, "/hostedzone/"),
+		signer:       v4.NewSigner(awscredentials.NewStaticCredentials(accessKeyID, secretAccessKey, sessionToken)),
+		client:       newHTTPClient(),
+		now:          time.Now,
+	}, nil
+}
+
+type route53ChangeRequest struct {
+	XMLName     xml.Name           `xml:"ChangeResourceRecordSetsRequest"`
+	Namespace   string             `xml:"xmlns,attr"`
+	ChangeBatch route53ChangeBatch `xml:"ChangeBatch"`
+}
+
+type route53ChangeBatch struct {
+	Changes []route53Change `xml:"Changes>Change"`
+}
+
+type route53Change struct {
+	Action            string                   `xml:"Action"`
+	ResourceRecordSet route53ResourceRecordSet `xml:"ResourceRecordSet"`
+}
+
+type route53ResourceRecordSet struct {
+	Name            string                  `xml:"Name"`
+	Type            string                  `xml:"Type"`
+	SetIdentifier   string                  `xml:"SetIdentifier,omitempty"`
+	Weight          *int64                  `xml:"Weight,omitempty"`
+	TTL             *int64                  `xml:"TTL,omitempty"`
+	ResourceRecords []route53ResourceRecord `xml:"ResourceRecords>ResourceRecord"`
+	AliasTarget     *route53AliasTarget     `xml:"AliasTarget,omitempty"`
+}
+
+type route53ResourceRecord struct {
+	Value string `xml:"Value"`
+}
+
+type route53AliasTarget struct {
+	HostedZoneID         string `xml:"HostedZoneId"`
+	DNSName              string `xml:"DNSName"`
+	EvaluateTargetHealth bool   `xml:"EvaluateTargetHealth"`
+}
+
+type route53ListResponse struct {
+	ResourceRecordSets []route53ResourceRecordSet `xml:"ResourceRecordSets>ResourceRecordSet"`
+}
+
+func (p *route53Provider) Upsert(endpoints []*feddnsv1a1.Endpoint) error {
+	changes := []route53Change{}
+	for _, endpoint := range endpoints {
+		recordSet, err := route53RecordSet(endpoint)
+		if err != nil {
+			return err
+		}
+		changes = append(changes, route53Change{Action: route53ActionUpsert, ResourceRecordSet: recordSet})
+	}
+	return p.changeRecordSets(changes)
+}
+
+// Delete deletes the record sets of the given endpoints.  Route53 only
+// deletes a record set whose values are given exactly, so the current
+// values of each record set are retrieved first.
+func (p *route53Provider) Delete(endpoints []*feddnsv1a1.Endpoint) error {
+	changes := []route53Change{}
+	for _, endpoint := range endpoints {
+		recordSet, err := p.getRecordSet(endpoint)
+		if err != nil {
+			return err
+		}
+		if recordSet != nil {
+			changes = append(changes, route53Change{Action: route53ActionDelete, ResourceRecordSet: *recordSet})
+		}
+	}
+	return p.changeRecordSets(changes)
+}
+
+func (p *route53Provider) changeRecordSets(changes []route53Change) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	body, err := xml.Marshal(route53ChangeRequest{
+		Namespace:   route53Namespace,
+		ChangeBatch: route53ChangeBatch{Changes: changes},
+	})
+	if err != nil {
+		return err
+	}
+	path := fmt.Sprintf("/%s/hostedzone/%s/rrset/", route53APIVersion, p.hostedZoneID)
+	_, err = p.do(http.MethodPost, path, nil, body)
+	return err
+}
+
+// getRecordSet returns the record set of the given endpoint, or nil if
+// it does not exist.
+func (p *route53Provider) getRecordSet(endpoint *feddnsv1a1.Endpoint) (*route53ResourceRecordSet, error) {
+	query := url.Values{}
+	query.Set("name", fqdn(endpoint.DNSName))
+	query.Set("type", endpoint.RecordType)
+	if len(endpoint.SetIdentifier) > 0 {
+		query.Set("identifier", endpoint.SetIdentifier)
+	}
+	query.Set("maxitems", "1")
+	path := fmt.Sprintf("/%s/hostedzone/%s/rrset", route53APIVersion, p.hostedZoneID)
+	body, err := p.do(http.MethodGet, path, query, nil)
+	if err != nil {
+		return nil, err
+	}
+	response := &route53ListResponse{}
+	if err := xml.Unmarshal(body, response); err != nil {
+		return nil, errors.Wrap(err, "route53: failed to decode the record sets")
+	}
+	for _, recordSet := range response.ResourceRecordSets {
+		// Record sets are listed from the given name on, and the
+		// wildcard of a name is escaped.
+		name := strings.Replace(recordSet.Name, `\052`, "*", -1)
+		if normalizeName(name) == normalizeName(endpoint.DNSName) && recordSet.Type == endpoint.RecordType &&
+			recordSet.SetIdentifier == endpoint.SetIdentifier {
+			return &recordSet, nil
+		}
+	}
+	return nil, nil
+}
+
+func (p *route53Provider) do(method, path string, query url.Values, body []byte) ([]byte, error) {
+	requestURL := p.endpoint + path
+	if len(query) > 0 {
+		requestURL += "?" + query.Encode()
+	}
+	request, err := http.NewRequest(method, requestURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		request.Header.Set("Content-Type", "application/xml")
+	}
+	if _, err := p.signer.Sign(request, bytes.NewReader(body), route53Service, route53Region, p.now()); err != nil {
+		return nil, errors.Wrap(err, "route53: failed to sign the request")
+	}
+
+	response, err := p.client.Do(request)
+	if err != nil {
+		return nil, errors.Wrap(err, "route53")
+	}
+	defer response.Body.Close()
+	responseBody, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "route53")
+	}
+	if response.StatusCode/100 != 2 {
+		return nil, errors.Wrap(responseError(response, responseBody), "route53")
+	}
+	return responseBody, nil
+}
+
+// route53RecordSet returns the record set of the given endpoint.
+func route53RecordSet(endpoint *feddnsv1a1.Endpoint) (route53ResourceRecordSet, error) {
+	ttl := int64(endpoint.RecordTTL)
+	recordSet := route53ResourceRecordSet{
+		Name:          fqdn(endpoint.DNSName),
+		Type:          endpoint.RecordType,
+		SetIdentifier: endpoint.SetIdentifier,
+		TTL:           &ttl,
+	}
+	if value, ok := providerSpecificValue(endpoint, route53WeightProperty); ok {
+		weight, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return recordSet, errors.Errorf("route53: invalid weight %q of %s", value, endpoint.DNSName)
+		}
+		recordSet.Weight = &weight
+	}
+	for _, target := range endpoint.Targets {
+		value := target
+		switch endpoint.RecordType {
+		case RecordTypeCNAME:
+			value = fqdn(target)
+		case RecordTypeTXT:
+			value = strconv.Quote(target)
+		}
+		recordSet.ResourceRecords = append(recordSet.ResourceRecords, route53ResourceRecord{Value: value})
+	}
+	return recordSet, nil
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	feddnsv1a1 "sigs.k8s.io/kubefed/pkg/apis/multiclusterdns/v1alpha1"
+)
+
+func TestRoute53Provider(t *testing.T) {
+	changes := []route53Change{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=key/") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			// Only the A record set of the wildcard exists.
+			if r.URL.Query().Get("type") != RecordTypeA {
+				fmt.Fprint(w, `<ListResourceRecordSetsResponse><ResourceRecordSets></ResourceRecordSets></ListResourceRecordSetsResponse>`)
+				return
+			}
+			fmt.Fprint(w, `<ListResourceRecordSetsResponse><ResourceRecordSets><ResourceRecordSet>`+
+				`<Name>\052.example.com.</Name><Type>A</Type><TTL>60</TTL>`+
+				`<ResourceRecords><ResourceRecord><Value>10.0.0.1</Value></ResourceRecord></ResourceRecords>`+
+				`</ResourceRecordSet></ResourceRecordSets></ListResourceRecordSetsResponse>`)
+		case http.MethodPost:
+			body, _ := ioutil.ReadAll(r.Body)
+			request := &route53ChangeRequest{}
+			if err := xml.Unmarshal(body, request); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			changes = append(changes, request.ChangeBatch.Changes...)
+			fmt.Fprint(w, `<ChangeResourceRecordSetsResponse/>`)
+		}
+	}))
+	defer server.Close()
+
+	p, err := newRoute53Provider(&feddnsv1a1.Route53Provider{HostedZoneID: "/hostedzone/Z1"}, map[string][]byte{
+		"aws_access_key_id":     []byte("key"),
+		"aws_secret_access_key": []byte("secret"),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	p.endpoint = server.URL
+	p.client = server.Client()
+
+	err = p.Upsert([]*feddnsv1a1.Endpoint{{
+		DNSName:          "app.example.com",
+		RecordType:       RecordTypeCNAME,
+		RecordTTL:        300,
+		Targets:          []string{"lb.example.com"},
+		SetIdentifier:    "c1",
+		ProviderSpecific: feddnsv1a1.ProviderSpecific{{Name: route53WeightProperty, Value: "10"}},
+	}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	err = p.Delete([]*feddnsv1a1.Endpoint{
+		{DNSName: "*.example.com", RecordType: RecordTypeA},
+		{DNSName: "missing.example.com", RecordType: RecordTypeAAAA},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(changes) != 2 {
+		t.Fatalf("Expected 2 changes, got %d", len(changes))
+	}
+	upsert := changes[0]
+	if upsert.Action != route53ActionUpsert || upsert.ResourceRecordSet.Name != "app.example.com." ||
+		upsert.ResourceRecordSet.SetIdentifier != "c1" || upsert.ResourceRecordSet.Weight == nil ||
+		*upsert.ResourceRecordSet.Weight != 10 || upsert.ResourceRecordSet.ResourceRecords[0].Value != "lb.example.com." {
+		t.Errorf("Unexpected upsert %+v", upsert)
+	}
+	// The existing record set must be deleted with its current values.
+	deletion := changes[1]
+	if deletion.Action != route53ActionDelete || deletion.ResourceRecordSet.ResourceRecords[0].Value != "10.0.0.1" ||
+		*deletion.ResourceRecordSet.TTL != 60 {
+		t.Errorf("Unexpected deletion %+v", deletion)
+	}
+}
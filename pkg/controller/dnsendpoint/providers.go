@@ -0,0 +1,171 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dnsendpoint
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	feddnsv1a1 "sigs.k8s.io/kubefed/pkg/apis/multiclusterdns/v1alpha1"
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	"sigs.k8s.io/kubefed/pkg/controller/dnsendpoint/provider"
+)
+
+// NewProviderFunc returns the provider of a domain authenticated with
+// the given credentials.
+type NewProviderFunc func(domain *feddnsv1a1.Domain, credentials map[string][]byte) (provider.Provider, error)
+
+type cachedProvider struct {
+	// version identifies the Domain and credentials secret the
+	// provider was created from.
+	version  string
+	provider provider.Provider
+}
+
+// providerRegistry resolves the providers of the domains that configure
+// one, so that the records of DNSEndpoint objects are programmed in the
+// provider directly.
+type providerRegistry struct {
+	client      genericclient.Client
+	domainStore cache.Store
+	newProvider NewProviderFunc
+
+	lock      sync.Mutex
+	providers map[string]cachedProvider
+}
+
+func newProviderRegistry(client genericclient.Client, domainStore cache.Store) *providerRegistry {
+	return &providerRegistry{
+		client:      client,
+		domainStore: domainStore,
+		newProvider: provider.New,
+		providers:   make(map[string]cachedProvider),
+	}
+}
+
+// domainFor returns the domain with a provider that the given DNS name
+// belongs to, or nil.  If domains are nested, the longest one wins.
+func (r *providerRegistry) domainFor(dnsName string) *feddnsv1a1.Domain {
+	return matchDomain(r.domainStore.List(), dnsName, func(domain *feddnsv1a1.Domain) bool {
+		return domain.Provider != nil
+	})
+}
+
+// providerFor returns the provider of the given domain, creating it if
+// the domain or its credentials changed since it was last created.
+func (r *providerRegistry) providerFor(domain *feddnsv1a1.Domain) (provider.Provider, error) {
+	var credentials map[string][]byte
+	version := domain.ResourceVersion
+	if secretName := domain.Provider.CredentialsSecret; len(secretName) > 0 {
+		secret := &corev1.Secret{}
+		err := r.client.Get(context.TODO(), secret, domain.Namespace, secretName)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get the credentials secret of domain %q", domain.Name)
+		}
+		credentials = secret.Data
+		version += "/" + secret.ResourceVersion
+	}
+
+	key := domain.Namespace + "/" + domain.Name
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if cached, ok := r.providers[key]; ok && cached.version == version {
+		return cached.provider, nil
+	}
+	p, err := r.newProvider(domain, credentials)
+	if err != nil {
+		return nil, err
+	}
+	r.providers[key] = cachedProvider{version: version, provider: p}
+	return p, nil
+}
+
+// sync programs the record sets of the desired endpoints that were
+// added or changed since the given existing endpoints were programmed in
+// the providers of their domains, and deletes the record sets of the
+// existing endpoints that are no longer desired.  All desired endpoints
+// are programmed if resync is true, as is required once the provider of
+// a domain may have changed.  Endpoints outside of the domains with a
+// provider are left to external-dns.
+func (r *providerRegistry) sync(existing, desired []*feddnsv1a1.Endpoint, resync bool) error {
+	existingByKey := make(map[string]*feddnsv1a1.Endpoint)
+	for _, endpoint := range existing {
+		existingByKey[endpointKey(endpoint)] = endpoint
+	}
+	desiredKeys := make(map[string]bool)
+	changed := []*feddnsv1a1.Endpoint{}
+	for _, endpoint := range desired {
+		key := endpointKey(endpoint)
+		desiredKeys[key] = true
+		current, ok := existingByKey[key]
+		if resync || !ok || !reflect.DeepEqual(current, endpoint) {
+			changed = append(changed, endpoint)
+		}
+	}
+	removed := []*feddnsv1a1.Endpoint{}
+	for _, endpoint := range existing {
+		if !desiredKeys[endpointKey(endpoint)] {
+			removed = append(removed, endpoint)
+		}
+	}
+
+	if err := r.apply(removed, provider.Provider.Delete); err != nil {
+		return errors.Wrap(err, "failed to delete DNS records")
+	}
+	if err := r.apply(changed, provider.Provider.Upsert); err != nil {
+		return errors.Wrap(err, "failed to program DNS records")
+	}
+	return nil
+}
+
+// apply groups the given endpoints by domain and applies the given
+// operation with the provider of each domain.
+func (r *providerRegistry) apply(endpoints []*feddnsv1a1.Endpoint, operation func(provider.Provider, []*feddnsv1a1.Endpoint) error) error {
+	domains := make(map[string]*feddnsv1a1.Domain)
+	domainEndpoints := make(map[string][]*feddnsv1a1.Endpoint)
+	for _, endpoint := range endpoints {
+		domain := r.domainFor(endpoint.DNSName)
+		if domain == nil {
+			continue
+		}
+		domains[domain.Name] = domain
+		domainEndpoints[domain.Name] = append(domainEndpoints[domain.Name], endpoint)
+	}
+
+	for name, domain := range domains {
+		p, err := r.providerFor(domain)
+		if err != nil {
+			return err
+		}
+		if err := operation(p, domainEndpoints[name]); err != nil {
+			return errors.Wrapf(err, "domain %q", name)
+		}
+	}
+	return nil
+}
+
+// endpointKey identifies the record set of an endpoint.
+func endpointKey(endpoint *feddnsv1a1.Endpoint) string {
+	return strings.Join([]string{NormalizeHost(endpoint.DNSName), endpoint.RecordType, endpoint.SetIdentifier}, "/")
+}
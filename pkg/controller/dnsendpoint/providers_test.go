@@ -0,0 +1,104 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dnsendpoint
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	feddnsv1a1 "sigs.k8s.io/kubefed/pkg/apis/multiclusterdns/v1alpha1"
+	"sigs.k8s.io/kubefed/pkg/controller/dnsendpoint/provider"
+)
+
+type fakeProvider struct {
+	upserted []string
+	deleted  []string
+}
+
+func (p *fakeProvider) Upsert(endpoints []*feddnsv1a1.Endpoint) error {
+	for _, endpoint := range endpoints {
+		p.upserted = append(p.upserted, endpoint.DNSName)
+	}
+	return nil
+}
+
+func (p *fakeProvider) Delete(endpoints []*feddnsv1a1.Endpoint) error {
+	for _, endpoint := range endpoints {
+		p.deleted = append(p.deleted, endpoint.DNSName)
+	}
+	return nil
+}
+
+func TestProviderRegistrySync(t *testing.T) {
+	unchanged := &feddnsv1a1.Endpoint{DNSName: "unchanged.example.com", RecordType: "A", Targets: feddnsv1a1.Targets{lb1}}
+	changed := &feddnsv1a1.Endpoint{DNSName: "changed.example.com", RecordType: "A", Targets: feddnsv1a1.Targets{lb1}}
+	changedUpdate := &feddnsv1a1.Endpoint{DNSName: "changed.example.com", RecordType: "A", Targets: feddnsv1a1.Targets{lb2}}
+	added := &feddnsv1a1.Endpoint{DNSName: "added.example.com", RecordType: "A", Targets: feddnsv1a1.Targets{lb1}}
+	removed := &feddnsv1a1.Endpoint{DNSName: "removed.example.com", RecordType: "A", Targets: feddnsv1a1.Targets{lb1}}
+	external := &feddnsv1a1.Endpoint{DNSName: "added.example.org", RecordType: "A", Targets: feddnsv1a1.Targets{lb1}}
+
+	existing := []*feddnsv1a1.Endpoint{unchanged, changed, removed}
+	desired := []*feddnsv1a1.Endpoint{unchanged, changedUpdate, added, external}
+
+	testCases := map[string]struct {
+		resync           bool
+		expectedUpserted []string
+	}{
+		"Only added and changed endpoints are programmed": {
+			expectedUpserted: []string{changed.DNSName, added.DNSName},
+		},
+		"All endpoints are programmed on resync": {
+			resync:           true,
+			expectedUpserted: []string{unchanged.DNSName, changed.DNSName, added.DNSName},
+		},
+	}
+
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			store := cache.NewStore(cache.MetaNamespaceKeyFunc)
+			store.Add(&feddnsv1a1.Domain{
+				ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "example"},
+				Domain:     "example.com",
+				Provider:   &feddnsv1a1.DNSProvider{Route53: &feddnsv1a1.Route53Provider{HostedZoneID: "zone"}},
+			})
+			store.Add(&feddnsv1a1.Domain{
+				ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "external"},
+				Domain:     "example.org",
+			})
+
+			fake := &fakeProvider{}
+			registry := newProviderRegistry(nil, store)
+			registry.newProvider = func(*feddnsv1a1.Domain, map[string][]byte) (provider.Provider, error) {
+				return fake, nil
+			}
+
+			if err := registry.sync(existing, desired, tc.resync); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(fake.upserted, tc.expectedUpserted) {
+				t.Errorf("Expected upserted records %v, got %v", tc.expectedUpserted, fake.upserted)
+			}
+			expectedDeleted := []string{removed.DNSName}
+			if !reflect.DeepEqual(fake.deleted, expectedDeleted) {
+				t.Errorf("Expected deleted records %v, got %v", expectedDeleted, fake.deleted)
+			}
+		})
+	}
+}
@@ -29,18 +29,25 @@ import (
 
 func StartServiceDNSEndpointController(config *util.ControllerConfig, stopChan <-chan struct{}) error {
 	restclient.AddUserAgent(config.KubeConfig, "Service DNSEndpoint")
-	controller, err := newDNSEndpointController(config, &feddnsv1a1.ServiceDNSRecord{}, "service",
-		getServiceDNSEndpoints, config.MinimizeLatency)
+	mgr, shared, err := util.ManagerFor(config)
+	if err != nil {
+		return err
+	}
+	controller, err := newDNSEndpointController(config, mgr, &feddnsv1a1.ServiceDNSRecord{}, "service",
+		getServiceDNSEndpoints, getServiceDNSAnnotations, config.MinimizeLatency)
 	if err != nil {
 		return err
 	}
 
 	go controller.Run(stopChan)
+	if !shared {
+		util.StartManager(mgr, stopChan)
+	}
 	return nil
 }
 
 // getServiceDNSEndpoints returns endpoint objects for each ServiceDNSRecord object that should be processed.
-func getServiceDNSEndpoints(obj interface{}) ([]*feddnsv1a1.Endpoint, error) {
+func getServiceDNSEndpoints(obj interface{}, domainFor DomainFunc) ([]*feddnsv1a1.Endpoint, error) {
 	var endpoints []*feddnsv1a1.Endpoint
 	var commonPrefix string
 	labels := make(map[string]string)
@@ -58,34 +65,38 @@ func getServiceDNSEndpoints(obj interface{}) ([]*feddnsv1a1.Endpoint, error) {
 		commonPrefix = strings.Join([]string{dnsObject.Name, dnsObject.Namespace, dnsObject.Spec.DomainRef, "svc"}, ".")
 	}
 
-	ttl := dnsObject.Spec.RecordTTL
-	if ttl == 0 {
-		ttl = defaultDNSTTL
-	}
+	ttl := recordTTL(dnsObject.Spec.RecordTTL, dnsObject.Status.RecordTTL)
 
 	for _, clusterDNS := range dnsObject.Status.DNS {
-		var zoneDNSName string
-		regionDNSName := strings.Join([]string{commonPrefix, clusterDNS.Region, dnsObject.Status.Domain}, ".") // region level, one up from zone level
-		globalDNSName := strings.Join([]string{commonPrefix, dnsObject.Status.Domain}, ".")                    // global level, one up from region level
-
-		// Zone endpoints
-		for _, zone := range clusterDNS.Zones {
-			zoneDNSName = strings.Join([]string{commonPrefix, zone, clusterDNS.Region, dnsObject.Status.Domain}, ".")
-			zoneTargets := ExtractLoadBalancerTargets(clusterDNS.LoadBalancer)
-			zoneEndpoint, err := generateEndpointForServiceDNSObject(zoneDNSName, zoneTargets, regionDNSName, ttl, labels)
+		globalDNSName := strings.Join([]string{commonPrefix, dnsObject.Status.Domain}, ".") // global level, one up from region level
+
+		// Only global endpoints are written for a cluster without a region.
+		if region := dnsLabel(clusterDNS.Region); len(region) > 0 {
+			regionDNSName := strings.Join([]string{commonPrefix, region, dnsObject.Status.Domain}, ".") // region level, one up from zone level
+
+			// Zone endpoints
+			for _, zone := range clusterDNS.Zones {
+				zone = dnsLabel(zone)
+				if len(zone) == 0 {
+					continue
+				}
+				zoneDNSName := strings.Join([]string{commonPrefix, zone, region, dnsObject.Status.Domain}, ".")
+				zoneTargets := ExtractLoadBalancerTargets(clusterDNS.LoadBalancer)
+				zoneEndpoint, err := generateEndpointForServiceDNSObject(zoneDNSName, zoneTargets, regionDNSName, ttl, labels)
+				if err != nil {
+					return nil, err
+				}
+				endpoints = append(endpoints, zoneEndpoint)
+			}
+
+			// Region endpoints
+			regionTargets := ExtractLoadBalancerTargets(clusterDNS.LoadBalancer)
+			regionEndpoint, err := generateEndpointForServiceDNSObject(regionDNSName, regionTargets, globalDNSName, ttl, labels)
 			if err != nil {
 				return nil, err
 			}
-			endpoints = append(endpoints, zoneEndpoint)
-		}
-
-		// Region endpoints
-		regionTargets := ExtractLoadBalancerTargets(clusterDNS.LoadBalancer)
-		regionEndpoint, err := generateEndpointForServiceDNSObject(regionDNSName, regionTargets, globalDNSName, ttl, labels)
-		if err != nil {
-			return nil, err
+			endpoints = append(endpoints, regionEndpoint)
 		}
-		endpoints = append(endpoints, regionEndpoint)
 
 		// Global endpoints
 		globalTargets := ExtractLoadBalancerTargets(clusterDNS.LoadBalancer)
@@ -106,7 +117,34 @@ func getServiceDNSEndpoints(obj interface{}) ([]*feddnsv1a1.Endpoint, error) {
 		endpoints = append(endpoints, endpoint)
 	}
 
-	return DedupeAndMergeEndpoints(endpoints), nil
+	endpoints = DedupeAndMergeEndpoints(endpoints)
+	endpoints = applyRecordPolicy(endpoints, dnsObject.Spec.RecordTTL, domainFor)
+	setRecordProperties(endpoints, dnsObject.Spec.SetIdentifier,
+		mergeProviderSpecific(dnsObject.Status.ProviderSpecific, dnsObject.Spec.ProviderSpecific))
+	return endpoints, nil
+}
+
+// getServiceDNSAnnotations returns the annotations of the DNSEndpoint
+// object for a ServiceDNSRecord object.
+func getServiceDNSAnnotations(obj interface{}) map[string]string {
+	dnsObject := obj.(*feddnsv1a1.ServiceDNSRecord)
+	return PassthroughAnnotations(dnsObject.Status.DomainAnnotations, dnsObject.Annotations)
+}
+
+// dnsLabel returns the given zone or region name as a DNS label, i.e.
+// in lower case with characters other than letters, digits and dashes
+// replaced by dashes.
+func dnsLabel(name string) string {
+	label := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r - 'A' + 'a'
+		}
+		return '-'
+	}, name)
+	return strings.Trim(label, "-")
 }
 
 func generateEndpointForServiceDNSObject(name string, targets feddnsv1a1.Targets, uplevelCname string,
@@ -60,6 +60,10 @@ func TestGetEndpointsForServiceDNSObject(t *testing.T) {
 	c3ZoneDNSName := strings.Join([]string{name, c3ZoneDNSPrefix}, ".")
 
 	labels := map[string]string{"serviceName": name}
+	providerSpecific := feddnsv1a1.ProviderSpecific{
+		{Name: "aws/evaluate-target-health", Value: "true"},
+		{Name: "aws/weight", Value: "20"},
+	}
 
 	testCases := map[string]struct {
 		dnsObject       feddnsv1a1.ServiceDNSRecord
@@ -287,6 +291,67 @@ func TestGetEndpointsForServiceDNSObject(t *testing.T) {
 			},
 			expectError: false,
 		},
+		"DomainConfiguredDNSRecordTTL": {
+			dnsObject: feddnsv1a1.ServiceDNSRecord{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: namespace,
+				},
+				Spec: feddnsv1a1.ServiceDNSRecordSpec{
+					DomainRef: federation,
+				},
+				Status: feddnsv1a1.ServiceDNSRecordStatus{
+					Domain:    dnsZone,
+					RecordTTL: userConfiguredTTL,
+					DNS: []feddnsv1a1.ClusterDNS{
+						{
+							Cluster: c1, Zones: []string{c1Zone}, Region: c1Region,
+							LoadBalancer: v1.LoadBalancerStatus{Ingress: []v1.LoadBalancerIngress{{IP: lb1}}},
+						},
+					},
+				},
+			},
+			expectEndpoints: []*feddnsv1a1.Endpoint{
+				{DNSName: globalDNSName, Targets: []string{lb1}, RecordType: RecordTypeA, RecordTTL: userConfiguredTTL},
+				{DNSName: c1RegionDNSName, Targets: []string{lb1}, RecordType: RecordTypeA, RecordTTL: userConfiguredTTL},
+				{DNSName: c1ZoneDNSName, Targets: []string{lb1}, RecordType: RecordTypeA, RecordTTL: userConfiguredTTL},
+			},
+			expectError: false,
+		},
+		"SetIdentifierAndProviderSpecific": {
+			dnsObject: feddnsv1a1.ServiceDNSRecord{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: namespace,
+				},
+				Spec: feddnsv1a1.ServiceDNSRecordSpec{
+					DomainRef:     federation,
+					SetIdentifier: "kubefed",
+					ProviderSpecific: feddnsv1a1.ProviderSpecific{
+						{Name: "aws/weight", Value: "20"},
+					},
+				},
+				Status: feddnsv1a1.ServiceDNSRecordStatus{
+					Domain: dnsZone,
+					ProviderSpecific: feddnsv1a1.ProviderSpecific{
+						{Name: "aws/evaluate-target-health", Value: "true"},
+						{Name: "aws/weight", Value: "10"},
+					},
+					DNS: []feddnsv1a1.ClusterDNS{
+						{
+							Cluster: c1, Zones: []string{c1Zone}, Region: c1Region,
+							LoadBalancer: v1.LoadBalancerStatus{Ingress: []v1.LoadBalancerIngress{{IP: lb1}}},
+						},
+					},
+				},
+			},
+			expectEndpoints: []*feddnsv1a1.Endpoint{
+				{DNSName: globalDNSName, Targets: []string{lb1}, RecordType: RecordTypeA, RecordTTL: defaultDNSTTL, SetIdentifier: "kubefed", ProviderSpecific: providerSpecific},
+				{DNSName: c1RegionDNSName, Targets: []string{lb1}, RecordType: RecordTypeA, RecordTTL: defaultDNSTTL, SetIdentifier: "kubefed", ProviderSpecific: providerSpecific},
+				{DNSName: c1ZoneDNSName, Targets: []string{lb1}, RecordType: RecordTypeA, RecordTTL: defaultDNSTTL, SetIdentifier: "kubefed", ProviderSpecific: providerSpecific},
+			},
+			expectError: false,
+		},
 		"UserConfiguredDNSPrefix": {
 			dnsObject: feddnsv1a1.ServiceDNSRecord{
 				ObjectMeta: metav1.ObjectMeta{
@@ -387,11 +452,41 @@ func TestGetEndpointsForServiceDNSObject(t *testing.T) {
 			},
 			expectError: false,
 		},
+		"ClusterWithoutRegion": {
+			dnsObject: feddnsv1a1.ServiceDNSRecord{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: namespace,
+				},
+				Spec: feddnsv1a1.ServiceDNSRecordSpec{
+					DomainRef: federation,
+				},
+				Status: feddnsv1a1.ServiceDNSRecordStatus{
+					Domain: dnsZone,
+					DNS: []feddnsv1a1.ClusterDNS{
+						{
+							Cluster: c1, Zones: []string{"US1"}, Region: "US",
+							LoadBalancer: v1.LoadBalancerStatus{Ingress: []v1.LoadBalancerIngress{{IP: lb1}}},
+						},
+						{
+							Cluster: c2, Zones: []string{c2Zone},
+							LoadBalancer: v1.LoadBalancerStatus{Ingress: []v1.LoadBalancerIngress{{IP: lb2}}},
+						},
+					},
+				},
+			},
+			expectEndpoints: []*feddnsv1a1.Endpoint{
+				{DNSName: globalDNSName, Targets: []string{lb1, lb2}, RecordType: RecordTypeA, RecordTTL: defaultDNSTTL},
+				{DNSName: c1RegionDNSName, Targets: []string{lb1}, RecordType: RecordTypeA, RecordTTL: defaultDNSTTL},
+				{DNSName: c1ZoneDNSName, Targets: []string{lb1}, RecordType: RecordTypeA, RecordTTL: defaultDNSTTL},
+			},
+			expectError: false,
+		},
 	}
 
 	for testName, tc := range testCases {
 		t.Run(testName, func(t *testing.T) {
-			endpoints, err := getServiceDNSEndpoints(&tc.dnsObject)
+			endpoints, err := getServiceDNSEndpoints(&tc.dnsObject, nil)
 			if !tc.expectError && err != nil {
 				t.Fatalf("Unexpected error: %v", err)
 			} else if tc.expectError && err == nil {
@@ -414,3 +509,30 @@ func TestGetEndpointsForServiceDNSObject(t *testing.T) {
 		})
 	}
 }
+
+func TestGetServiceDNSAnnotations(t *testing.T) {
+	dnsObject := &feddnsv1a1.ServiceDNSRecord{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				"external-dns.alpha.kubernetes.io/controller": "dns-controller",
+				"example.com/ignored":                         "true",
+			},
+		},
+		Status: feddnsv1a1.ServiceDNSRecordStatus{
+			DomainAnnotations: map[string]string{
+				"external-dns.alpha.kubernetes.io/controller":  "default",
+				"external-dns.alpha.kubernetes.io/access-type": "public",
+			},
+		},
+	}
+	expected := map[string]string{
+		"external-dns.alpha.kubernetes.io/controller":  "dns-controller",
+		"external-dns.alpha.kubernetes.io/access-type": "public",
+	}
+	if annotations := getServiceDNSAnnotations(dnsObject); !reflect.DeepEqual(annotations, expected) {
+		t.Fatalf("Expected annotations %v, got %v", expected, annotations)
+	}
+	if annotations := getServiceDNSAnnotations(&feddnsv1a1.ServiceDNSRecord{}); annotations != nil {
+		t.Fatalf("Expected no annotations, got %v", annotations)
+	}
+}
@@ -0,0 +1,449 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package federatedevents
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	kubeclient "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+const (
+	userAgentName = "federated-events-controller"
+
+	minRetryDelay = 5 * time.Second
+	maxRetryDelay = 300 * time.Second
+	maxRetries    = 5
+
+	numWorkers = 2
+
+	// The maximum number of owners followed from the object of an
+	// event to the resource propagated by KubeFed, e.g. from a pod to
+	// its replicaset and on to its deployment.
+	maxOwnerDepth = 3
+)
+
+// mirroredReasons are the reasons of the member cluster events that
+// are mirrored to the host cluster.
+var mirroredReasons = map[string]bool{
+	"FailedCreate":     true,
+	"FailedScheduling": true,
+	"Unhealthy":        true,
+}
+
+// memberEvent identifies an event observed in a member cluster.  It
+// only contains comparable fields so that identical events that are
+// pending mirroring are only mirrored once.
+type memberEvent struct {
+	clusterName    string
+	involvedObject corev1.ObjectReference
+	eventType      string
+	reason         string
+	message        string
+}
+
+// Controller mirrors warning events about resources propagated by
+// KubeFed from member clusters to the federated resources in the host
+// cluster, so that describing a federated resource reveals failures
+// in member clusters.
+type Controller struct {
+	client genericclient.Client
+
+	// Informer for events in member clusters
+	informer util.FederatedInformer
+
+	// Store for the FederatedTypeConfig objects
+	typeConfigStore cache.Store
+	// Informer for the FederatedTypeConfig objects
+	typeConfigController cache.Controller
+
+	// Clients for the resources of member clusters by cluster name
+	clusterClientsLock sync.Mutex
+	clusterClients     map[string]genericclient.Client
+
+	eventRecorder record.EventRecorder
+
+	queue workqueue.RateLimitingInterface
+
+	// Events last observed before the controller started are not
+	// mirrored to avoid mirroring them again on every restart.
+	startTime time.Time
+
+	fedNamespace string
+
+	// Config of the host cluster, which is used to access local
+	// clusters.
+	hostConfig *restclient.Config
+}
+
+// StartController starts the controller mirroring member cluster
+// events to federated resources.
+func StartController(config *util.ControllerConfig, stopChan <-chan struct{}) error {
+	controller, err := newController(config)
+	if err != nil {
+		return err
+	}
+	klog.Infof("Starting federated events controller")
+	controller.Run(stopChan)
+	return nil
+}
+
+func newController(config *util.ControllerConfig) (*Controller, error) {
+	client := genericclient.NewForConfigOrDieWithUserAgent(config.KubeConfig, userAgentName)
+
+	kubeClient := kubeclient.NewForConfigOrDie(config.KubeConfig)
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeClient.CoreV1().Events("")})
+	recorder := broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: userAgentName})
+
+	c := &Controller{
+		client:         client,
+		clusterClients: make(map[string]genericclient.Client),
+		eventRecorder:  recorder,
+		queue: workqueue.NewNamedRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(
+			minRetryDelay, maxRetryDelay), "FederatedEvents"),
+		startTime:    time.Now(),
+		fedNamespace: config.KubeFedNamespace,
+		hostConfig:   config.KubeConfig,
+	}
+
+	var err error
+	c.typeConfigStore, c.typeConfigController, err = util.NewGenericInformer(
+		config.KubeConfig,
+		config.KubeFedNamespace,
+		&fedv1b1.FederatedTypeConfig{},
+		util.NoResyncPeriod,
+		func(pkgruntime.Object) {},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	eventAPIResource := &metav1.APIResource{
+		Name:       "events",
+		Version:    "v1",
+		Kind:       "Event",
+		Namespaced: true,
+	}
+	c.informer, err = util.NewFederatedInformerWithFactory(
+		config,
+		client,
+		eventAPIResource,
+		func(cluster *fedv1b1.KubeFedCluster, client util.ResourceClient) (cache.Store, cache.Controller) {
+			namespace := util.ClusterNamespace(cluster.Spec.NamespaceMappings, config.TargetNamespace)
+			return newWarningEventInformer(client, namespace, cluster.Name, c.observe)
+		},
+		&util.ClusterLifecycleHandlerFuncs{
+			ClusterUnavailable: func(cluster *fedv1b1.KubeFedCluster, _ []interface{}) {
+				c.clusterClientsLock.Lock()
+				defer c.clusterClientsLock.Unlock()
+				delete(c.clusterClients, cluster.Name)
+			},
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// newWarningEventInformer returns an informer for the warning events
+// of the given member cluster that invokes the observe function with
+// events that are added or have recurred.
+func newWarningEventInformer(client util.ResourceClient, namespace, clusterName string,
+	observe func(clusterName string, event *corev1.Event)) (cache.Store, cache.Controller) {
+
+	fieldSelector := fields.OneTermEqualSelector("type", corev1.EventTypeWarning).String()
+	observeObj := func(obj interface{}) {
+		if event, ok := eventFromObject(obj); ok {
+			observe(clusterName, event)
+		}
+	}
+	return cache.NewInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (pkgruntime.Object, error) {
+				options.FieldSelector = fieldSelector
+				return client.Resources(namespace).List(options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				options.FieldSelector = fieldSelector
+				return client.Resources(namespace).Watch(options)
+			},
+		},
+		nil, // Skip checks for expected type since the type will depend on the client
+		util.NoResyncPeriod,
+		&cache.ResourceEventHandlerFuncs{
+			AddFunc: observeObj,
+			UpdateFunc: func(old, cur interface{}) {
+				oldEvent, oldOK := eventFromObject(old)
+				curEvent, curOK := eventFromObject(cur)
+				if oldOK && curOK && oldEvent.Count != curEvent.Count {
+					observe(clusterName, curEvent)
+				}
+			},
+		},
+	)
+}
+
+func eventFromObject(obj interface{}) (*corev1.Event, bool) {
+	unstructuredObj, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, false
+	}
+	event := &corev1.Event{}
+	if err := pkgruntime.DefaultUnstructuredConverter.FromUnstructured(unstructuredObj.Object, event); err != nil {
+		klog.Errorf("Failed to convert event %s/%s: %v", unstructuredObj.GetNamespace(), unstructuredObj.GetName(), err)
+		return nil, false
+	}
+	return event, true
+}
+
+// observe queues the given member cluster event for mirroring if
+// it is to be mirrored.
+func (c *Controller) observe(clusterName string, event *corev1.Event) {
+	if !shouldMirror(event, c.startTime) {
+		return
+	}
+	c.queue.Add(memberEvent{
+		clusterName:    clusterName,
+		involvedObject: event.InvolvedObject,
+		eventType:      event.Type,
+		reason:         event.Reason,
+		message:        event.Message,
+	})
+}
+
+// shouldMirror returns whether the given event is a warning with a
+// mirrored reason that was last observed after the given time.
+func shouldMirror(event *corev1.Event, since time.Time) bool {
+	if event.Type != corev1.EventTypeWarning || !mirroredReasons[event.Reason] {
+		return false
+	}
+	lastObserved := event.LastTimestamp.Time
+	if event.Series != nil {
+		lastObserved = event.Series.LastObservedTime.Time
+	} else if lastObserved.IsZero() {
+		lastObserved = event.EventTime.Time
+	}
+	return !lastObserved.Before(since)
+}
+
+// Run runs the controller until the stop channel is closed.
+func (c *Controller) Run(stopChan <-chan struct{}) {
+	go func() {
+		defer runtime.HandleCrash()
+		defer c.queue.ShutDown()
+
+		go c.typeConfigController.Run(stopChan)
+		c.informer.Start()
+
+		if !cache.WaitForCacheSync(stopChan, c.typeConfigController.HasSynced) {
+			runtime.HandleError(errors.New("Timed out waiting for caches to sync"))
+			return
+		}
+
+		for i := 0; i < numWorkers; i++ {
+			go wait.Until(c.worker, time.Second, stopChan)
+		}
+
+		<-stopChan
+		c.informer.Stop()
+	}()
+}
+
+func (c *Controller) worker() {
+	for c.processNextItem() {
+	}
+}
+
+func (c *Controller) processNextItem() bool {
+	item, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(item)
+
+	event := item.(memberEvent)
+	err := c.mirror(event)
+	if err == nil {
+		c.queue.Forget(item)
+	} else if c.queue.NumRequeues(item) < maxRetries {
+		klog.Errorf("Error mirroring %s event of cluster %q (will retry): %v", event.reason, event.clusterName, err)
+		c.queue.AddRateLimited(item)
+	} else {
+		klog.Errorf("Error mirroring %s event of cluster %q (giving up): %v", event.reason, event.clusterName, err)
+		c.queue.Forget(item)
+		runtime.HandleError(err)
+	}
+	return true
+}
+
+// mirror records the given member cluster event on the federated
+// resource of the propagated resource it relates to.  Events that do
+// not relate to a propagated resource are ignored.
+func (c *Controller) mirror(event memberEvent) error {
+	cluster, ok, err := c.informer.GetReadyCluster(event.clusterName)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return nil
+	}
+	clusterClient, err := c.clusterClient(cluster)
+	if err != nil {
+		return err
+	}
+
+	managedObj, err := managedObjectFor(clusterClient, event.involvedObject)
+	if err != nil || managedObj == nil {
+		return err
+	}
+
+	typeConfigs := []*fedv1b1.FederatedTypeConfig{}
+	for _, obj := range c.typeConfigStore.List() {
+		typeConfigs = append(typeConfigs, obj.(*fedv1b1.FederatedTypeConfig))
+	}
+	fedGVK, fedName, ok := federatedResourceFor(typeConfigs, cluster.Spec.NamespaceMappings, managedObj)
+	if !ok {
+		klog.V(4).Infof("No federated type found for %s %s/%s of cluster %q", managedObj.GetKind(),
+			managedObj.GetNamespace(), managedObj.GetName(), cluster.Name)
+		return nil
+	}
+
+	fedObj := &unstructured.Unstructured{}
+	fedObj.SetGroupVersionKind(fedGVK)
+	err = c.client.Get(context.TODO(), fedObj, fedName.Namespace, fedName.Name)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "Failed to retrieve %s %q", fedGVK.Kind, fedName)
+	}
+
+	ref := event.involvedObject
+	c.eventRecorder.Eventf(fedObj, event.eventType, event.reason, "%s %s in cluster %q: %s",
+		ref.Kind, util.QualifiedName{Namespace: ref.Namespace, Name: ref.Name}, cluster.Name, event.message)
+	return nil
+}
+
+// clusterClient returns a client for the resources of the given
+// member cluster.
+func (c *Controller) clusterClient(cluster *fedv1b1.KubeFedCluster) (genericclient.Client, error) {
+	c.clusterClientsLock.Lock()
+	defer c.clusterClientsLock.Unlock()
+	if client, ok := c.clusterClients[cluster.Name]; ok {
+		return client, nil
+	}
+	config, err := util.BuildClusterConfig(cluster, c.hostConfig, c.client, c.fedNamespace)
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		return nil, errors.Errorf("Unable to load configuration for cluster %q", cluster.Name)
+	}
+	restclient.AddUserAgent(config, userAgentName)
+	client, err := genericclient.New(config)
+	if err != nil {
+		return nil, err
+	}
+	c.clusterClients[cluster.Name] = client
+	return client, nil
+}
+
+// managedObjectFor returns the resource managed by KubeFed that the
+// given object is or is owned by.  Nil is returned if there is no
+// such resource.
+func managedObjectFor(client genericclient.Client, ref corev1.ObjectReference) (*unstructured.Unstructured, error) {
+	gvk := schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind)
+	namespace, name := ref.Namespace, ref.Name
+	for depth := 0; depth <= maxOwnerDepth; depth++ {
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(gvk)
+		err := client.Get(context.TODO(), obj, namespace, name)
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to retrieve %s %q", gvk.Kind, util.QualifiedName{Namespace: namespace, Name: name})
+		}
+		if util.HasManagedLabel(obj) {
+			return obj, nil
+		}
+		owner := metav1.GetControllerOf(obj)
+		if owner == nil {
+			return nil, nil
+		}
+		gvk = schema.FromAPIVersionAndKind(owner.APIVersion, owner.Kind)
+		name = owner.Name
+	}
+	return nil, nil
+}
+
+// federatedResourceFor returns the kind and host cluster name of the
+// federated resource of the given propagated resource.
+func federatedResourceFor(typeConfigs []*fedv1b1.FederatedTypeConfig, mappings []fedv1b1.NamespaceMapping,
+	obj *unstructured.Unstructured) (schema.GroupVersionKind, util.QualifiedName, bool) {
+
+	gvk := obj.GroupVersionKind()
+	for _, typeConfig := range typeConfigs {
+		targetType := typeConfig.GetTargetType()
+		if targetType.Group != gvk.Group || targetType.Kind != gvk.Kind {
+			continue
+		}
+		federatedType := typeConfig.GetFederatedType()
+		fedGVK := schema.GroupVersionKind{
+			Group:   federatedType.Group,
+			Version: federatedType.Version,
+			Kind:    federatedType.Kind,
+		}
+		targetIsNamespace := targetType.Kind == util.NamespaceKind
+		fedName := util.HostQualifiedNameForObject(mappings, targetIsNamespace, obj)
+		if targetIsNamespace {
+			// A federated namespace is contained by the namespace it
+			// propagates.
+			fedName.Namespace = fedName.Name
+		}
+		return fedGVK, fedName, true
+	}
+	return schema.GroupVersionKind{}, util.QualifiedName{}, false
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package federatedevents
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+// getOnlyClient is a generic client that serves gets from a fixed set
+// of objects.
+type getOnlyClient struct {
+	objects []*unstructured.Unstructured
+}
+
+func (c *getOnlyClient) Create(ctx context.Context, obj pkgruntime.Object) error {
+	return errors.New("not implemented")
+}
+
+func (c *getOnlyClient) Get(ctx context.Context, obj pkgruntime.Object, namespace, name string) error {
+	target := obj.(*unstructured.Unstructured)
+	for _, candidate := range c.objects {
+		if candidate.GroupVersionKind() == target.GroupVersionKind() && candidate.GetNamespace() == namespace && candidate.GetName() == name {
+			candidate.DeepCopyInto(target)
+			return nil
+		}
+	}
+	return apierrors.NewNotFound(schema.GroupResource{Resource: target.GetKind()}, name)
+}
+
+func (c *getOnlyClient) Update(ctx context.Context, obj pkgruntime.Object) error {
+	return errors.New("not implemented")
+}
+
+func (c *getOnlyClient) Delete(ctx context.Context, obj pkgruntime.Object, namespace, name string) error {
+	return errors.New("not implemented")
+}
+
+func (c *getOnlyClient) List(ctx context.Context, obj pkgruntime.Object, namespace string) error {
+	return errors.New("not implemented")
+}
+
+func (c *getOnlyClient) UpdateStatus(ctx context.Context, obj pkgruntime.Object) error {
+	return errors.New("not implemented")
+}
+
+func newObject(apiVersion, kind, name string, owner *unstructured.Unstructured) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetNamespace("ns")
+	obj.SetName(name)
+	if owner != nil {
+		isController := true
+		obj.SetOwnerReferences([]metav1.OwnerReference{{
+			APIVersion: owner.GetAPIVersion(),
+			Kind:       owner.GetKind(),
+			Name:       owner.GetName(),
+			Controller: &isController,
+		}})
+	}
+	return obj
+}
+
+func objectReference(obj *unstructured.Unstructured) corev1.ObjectReference {
+	return corev1.ObjectReference{
+		APIVersion: obj.GetAPIVersion(),
+		Kind:       obj.GetKind(),
+		Namespace:  obj.GetNamespace(),
+		Name:       obj.GetName(),
+	}
+}
+
+func TestShouldMirror(t *testing.T) {
+	start := time.Now()
+	before := metav1.NewTime(start.Add(-time.Minute))
+	after := metav1.NewTime(start.Add(time.Minute))
+
+	testCases := map[string]struct {
+		event    corev1.Event
+		expected bool
+	}{
+		"Recent warning with a mirrored reason": {
+			event:    corev1.Event{Type: corev1.EventTypeWarning, Reason: "FailedScheduling", LastTimestamp: after},
+			expected: true,
+		},
+		"Warning with another reason": {
+			event:    corev1.Event{Type: corev1.EventTypeWarning, Reason: "BackOff", LastTimestamp: after},
+			expected: false,
+		},
+		"Normal event with a mirrored reason": {
+			event:    corev1.Event{Type: corev1.EventTypeNormal, Reason: "Unhealthy", LastTimestamp: after},
+			expected: false,
+		},
+		"Warning last observed before the start": {
+			event:    corev1.Event{Type: corev1.EventTypeWarning, Reason: "FailedCreate", LastTimestamp: before},
+			expected: false,
+		},
+		"Warning of a series observed after the start": {
+			event: corev1.Event{
+				Type:          corev1.EventTypeWarning,
+				Reason:        "Unhealthy",
+				LastTimestamp: before,
+				Series:        &corev1.EventSeries{LastObservedTime: metav1.NewMicroTime(after.Time)},
+			},
+			expected: true,
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			assert.Equal(t, tc.expected, shouldMirror(&tc.event, start))
+		})
+	}
+}
+
+func TestManagedObjectFor(t *testing.T) {
+	deployment := newObject("apps/v1", "Deployment", "web", nil)
+	util.AddManagedLabel(deployment)
+	replicaSet := newObject("apps/v1", "ReplicaSet", "web-5d4f", deployment)
+	pod := newObject("v1", "Pod", "web-5d4f-x2k9", replicaSet)
+	unmanagedPod := newObject("v1", "Pod", "standalone", nil)
+	client := &getOnlyClient{objects: []*unstructured.Unstructured{deployment, replicaSet, pod, unmanagedPod}}
+
+	obj, err := managedObjectFor(client, objectReference(pod))
+	assert.NoError(t, err)
+	if assert.NotNil(t, obj) {
+		assert.Equal(t, "Deployment", obj.GetKind())
+		assert.Equal(t, "web", obj.GetName())
+	}
+
+	obj, err = managedObjectFor(client, objectReference(deployment))
+	assert.NoError(t, err)
+	assert.NotNil(t, obj)
+
+	obj, err = managedObjectFor(client, objectReference(unmanagedPod))
+	assert.NoError(t, err)
+	assert.Nil(t, obj)
+
+	obj, err = managedObjectFor(client, corev1.ObjectReference{APIVersion: "v1", Kind: "Pod", Namespace: "ns", Name: "deleted"})
+	assert.NoError(t, err)
+	assert.Nil(t, obj)
+}
+
+func TestFederatedResourceFor(t *testing.T) {
+	typeConfigs := []*fedv1b1.FederatedTypeConfig{
+		{
+			Spec: fedv1b1.FederatedTypeConfigSpec{
+				TargetType:    fedv1b1.APIResource{Group: "apps", Version: "v1", Kind: "Deployment", Scope: "Namespaced"},
+				FederatedType: fedv1b1.APIResource{Group: "types.kubefed.k8s.io", Version: "v1beta1", Kind: "FederatedDeployment", Scope: "Namespaced"},
+			},
+		},
+		{
+			Spec: fedv1b1.FederatedTypeConfigSpec{
+				TargetType:    fedv1b1.APIResource{Version: "v1", Kind: "Namespace", Scope: "Cluster"},
+				FederatedType: fedv1b1.APIResource{Group: "types.kubefed.k8s.io", Version: "v1beta1", Kind: "FederatedNamespace", Scope: "Namespaced"},
+			},
+		},
+	}
+	mappings := []fedv1b1.NamespaceMapping{{Namespace: "ns", ClusterNamespace: "ns-east"}}
+
+	deployment := newObject("apps/v1", "Deployment", "web-east", nil)
+	deployment.SetNamespace("ns-east")
+	deployment.SetAnnotations(map[string]string{util.FederatedNameAnnotation: "web"})
+	gvk, name, ok := federatedResourceFor(typeConfigs, mappings, deployment)
+	assert.True(t, ok)
+	assert.Equal(t, "FederatedDeployment", gvk.Kind)
+	assert.Equal(t, util.QualifiedName{Namespace: "ns", Name: "web"}, name)
+
+	namespace := &unstructured.Unstructured{}
+	namespace.SetAPIVersion("v1")
+	namespace.SetKind("Namespace")
+	namespace.SetName("ns-east")
+	gvk, name, ok = federatedResourceFor(typeConfigs, mappings, namespace)
+	assert.True(t, ok)
+	assert.Equal(t, "FederatedNamespace", gvk.Kind)
+	assert.Equal(t, util.QualifiedName{Namespace: "ns", Name: "ns"}, name)
+
+	_, _, ok = federatedResourceFor(typeConfigs, mappings, newObject("v1", "ConfigMap", "config", nil))
+	assert.False(t, ok)
+}
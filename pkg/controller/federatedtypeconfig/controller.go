@@ -18,28 +18,48 @@ package federatedtypeconfig
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	restclient "k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	crcache "sigs.k8s.io/controller-runtime/pkg/cache"
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	corev1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
 	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	"sigs.k8s.io/kubefed/pkg/controller/autofederation"
+	"sigs.k8s.io/kubefed/pkg/controller/lighthouse"
 	statuscontroller "sigs.k8s.io/kubefed/pkg/controller/status"
 	synccontroller "sigs.k8s.io/kubefed/pkg/controller/sync"
 	"sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/features"
 )
 
 const finalizer string = "core.kubefed.k8s.io/federated-type-config"
 
+// The time a sync controller may take to be started for an enabled
+// FederatedTypeConfig before the controller is reported unhealthy.
+const syncControllerStartTimeout = 2 * time.Minute
+
 // The FederatedTypeConfig controller configures sync and status
 // controllers in response to FederatedTypeConfig resources in the
 // KubeFed system namespace.
@@ -53,27 +73,65 @@ type Controller struct {
 	stopChannels map[string]chan struct{}
 	lock         sync.RWMutex
 
-	// Store for the FederatedTypeConfig objects
-	store cache.Store
+	// The time at which the health check first observed an enabled
+	// FederatedTypeConfig without a running sync controller, keyed
+	// by name.
+	notRunningSince map[string]time.Time
+
+	// Cache of the manager holding the FederatedTypeConfig objects
+	cache crcache.Cache
 	// Informer for the FederatedTypeConfig objects
-	controller cache.Controller
+	informer cache.SharedIndexInformer
 
-	worker util.ReconcileWorker
+	// Events triggering the reconciliation of FederatedTypeConfig
+	// objects other than the one being reconciled
+	events   chan event.GenericEvent
+	stopChan <-chan struct{}
 }
 
-// StartController starts the Controller for managing FederatedTypeConfig objects.
+// StartController starts the Controller for managing FederatedTypeConfig
+// objects.  The controller is registered with the manager of the given
+// config, or with a manager of its own if the config has none.
 func StartController(config *util.ControllerConfig, stopChan <-chan struct{}) error {
-	controller, err := newController(config)
+	mgr, shared, err := util.ManagerFor(config)
+	if err != nil {
+		return err
+	}
+	controller, err := newController(config, mgr, stopChan)
 	if err != nil {
 		return err
 	}
 	klog.Infof("Starting FederatedTypeConfig controller")
-	controller.Run(stopChan)
+	err = builder.ControllerManagedBy(mgr).
+		For(&corev1b1.FederatedTypeConfig{}).
+		Watches(&source.Channel{Source: controller.events}, &handler.EnqueueRequestForObject{}).
+		WithEventFilter(util.NamespacePredicate(config.KubeFedNamespace)).
+		Complete(util.NewReconciler(util.WorkerName{Controller: "federatedtypeconfig"}, controller.reconcile, util.WorkerTiming{}))
+	if err != nil {
+		return errors.Wrap(err, "Failed to register the FederatedTypeConfig controller")
+	}
+
+	// Ensure all goroutines are cleaned up when the stop channel closes
+	go func() {
+		<-stopChan
+		controller.shutDown()
+	}()
+
+	if !shared {
+		util.StartManager(mgr, stopChan)
+		// wait for the cache to synchronize as a shared manager
+		// would before starting its controllers
+		if !cache.WaitForCacheSync(stopChan, controller.informer.HasSynced) {
+			return errors.New("Timed out waiting for cache to sync")
+		}
+	}
+	config.HealthChecks.AddLivenessCheck("sync-controllers", controller.healthCheck)
+	config.Diagnostics.AddSource("federatedtypeconfig-controller", controller.dumpControllers)
 	return nil
 }
 
 // newController returns a new controller to manage FederatedTypeConfig objects.
-func newController(config *util.ControllerConfig) (*Controller, error) {
+func newController(config *util.ControllerConfig, mgr manager.Manager, stopChan <-chan struct{}) (*Controller, error) {
 	userAgent := "FederatedTypeConfig"
 	kubeConfig := restclient.CopyConfig(config.KubeConfig)
 	restclient.AddUserAgent(kubeConfig, userAgent)
@@ -82,48 +140,24 @@ func newController(config *util.ControllerConfig) (*Controller, error) {
 		return nil, err
 	}
 
-	c := &Controller{
-		controllerConfig: config,
-		client:           genericclient,
-		stopChannels:     make(map[string]chan struct{}),
-	}
-
-	c.worker = util.NewReconcileWorker(c.reconcile, util.WorkerTiming{})
-
-	// Only watch the KubeFed namespace to ensure
-	// restrictive authz can be applied to a namespaced
-	// control plane.
-	c.store, c.controller, err = util.NewGenericInformer(
-		kubeConfig,
-		config.KubeFedNamespace,
-		&corev1b1.FederatedTypeConfig{},
-		util.NoResyncPeriod,
-		c.worker.EnqueueObject,
-	)
+	// The cache of the manager only holds objects in the KubeFed
+	// namespace to ensure restrictive authz can be applied to a
+	// namespaced control plane.
+	informer, err := mgr.GetCache().GetInformer(&corev1b1.FederatedTypeConfig{})
 	if err != nil {
-		return nil, err
+		return nil, errors.Wrap(err, "Failed to get the FederatedTypeConfig informer")
 	}
 
-	return c, nil
-}
-
-// Run runs the Controller.
-func (c *Controller) Run(stopChan <-chan struct{}) {
-	go c.controller.Run(stopChan)
-
-	// wait for the caches to synchronize before starting the worker
-	if !cache.WaitForCacheSync(stopChan, c.controller.HasSynced) {
-		runtime.HandleError(errors.New("Timed out waiting for cache to sync"))
-		return
-	}
-
-	c.worker.Run(stopChan)
-
-	// Ensure all goroutines are cleaned up when the stop channel closes
-	go func() {
-		<-stopChan
-		c.shutDown()
-	}()
+	return &Controller{
+		controllerConfig: config,
+		client:           genericclient,
+		stopChannels:     make(map[string]chan struct{}),
+		notRunningSince:  make(map[string]time.Time),
+		cache:            mgr.GetCache(),
+		informer:         informer,
+		events:           make(chan event.GenericEvent),
+		stopChan:         stopChan,
+	}, nil
 }
 
 func (c *Controller) reconcile(qualifiedName util.QualifiedName) util.ReconciliationStatus {
@@ -131,24 +165,38 @@ func (c *Controller) reconcile(qualifiedName util.QualifiedName) util.Reconcilia
 
 	klog.V(3).Infof("Running reconcile FederatedTypeConfig for %q", key)
 
-	cachedObj, err := c.objCopyFromCache(key)
+	typeConfig, err := c.objCopyFromCache(qualifiedName)
 	if err != nil {
 		return util.StatusError
 	}
 
-	if cachedObj == nil {
+	if typeConfig == nil {
 		return util.StatusAllOK
 	}
-	typeConfig := cachedObj.(*corev1b1.FederatedTypeConfig)
 
 	// TODO(marun) Perform this defaulting in a webhook
 	corev1b1.SetFederatedTypeConfigDefaults(typeConfig)
 
-	syncEnabled := typeConfig.GetPropagationEnabled()
-	statusEnabled := typeConfig.GetStatusEnabled()
-
+	// The sync controller of a type whose propagation is paused keeps
+	// running so that its caches and the status of its resources
+	// remain current.
+	syncEnabled := typeConfig.GetPropagationEnabled() || typeConfig.GetPropagationPaused()
+	// A status controller is only required for types that define a
+	// status type.  The status of other types is collected by the sync
+	// controller if raw resource status collection is enabled.
+	statusEnabled := typeConfig.GetStatusEnabled() && typeConfig.GetStatusType() != nil &&
+		c.controllerConfig.ControllerEnabled(corev1b1.StatusControllerName)
+
+	// A namespace-scoped control plane only propagates the resources
+	// of a cluster-scoped type that are allowlisted by the
+	// KubeFedConfig, and does not collect their status.
 	limitedScope := c.controllerConfig.TargetNamespace != metav1.NamespaceAll
-	if limitedScope && syncEnabled && !typeConfig.GetNamespaced() {
+	clusterScopedAllowed := false
+	if limitedScope && !typeConfig.GetNamespaced() {
+		clusterScopedAllowed = c.controllerConfig.AllowedClusterScopedNames(typeConfig.GetTargetType()) != nil
+		statusEnabled = false
+	}
+	if limitedScope && syncEnabled && !typeConfig.GetNamespaced() && !clusterScopedAllowed {
 		_, ok := c.getStopChannel(typeConfig.Name)
 		if !ok {
 			holderChan := make(chan struct{})
@@ -213,6 +261,10 @@ func (c *Controller) reconcile(qualifiedName util.QualifiedName) util.Reconcilia
 
 	startNewSyncController := !syncRunning && syncEnabled
 	stopSyncController := syncRunning && (!syncEnabled || (typeConfig.GetNamespaced() && !c.namespaceFTCExists()))
+	// A running sync controller retains the type config it was
+	// started with and must be restarted to observe changes to the
+	// spec (e.g. to default overrides).
+	restartSyncController := syncRunning && !stopSyncController && typeConfig.Status.ObservedGeneration != typeConfig.Generation
 	if startNewSyncController {
 		if err := c.startSyncController(typeConfig); err != nil {
 			runtime.HandleError(err)
@@ -220,6 +272,13 @@ func (c *Controller) reconcile(qualifiedName util.QualifiedName) util.Reconcilia
 		}
 	} else if stopSyncController {
 		c.stopController(typeConfig.Name, syncStopChan)
+	} else if restartSyncController {
+		klog.Infof("Restarting sync controller for %q to observe an updated spec", key)
+		c.stopController(typeConfig.Name, syncStopChan)
+		if err := c.startSyncController(typeConfig); err != nil {
+			runtime.HandleError(err)
+			return util.StatusError
+		}
 	}
 
 	startNewStatusController := !statusRunning && statusEnabled
@@ -259,17 +318,30 @@ func (c *Controller) reconcile(qualifiedName util.QualifiedName) util.Reconcilia
 	return util.StatusAllOK
 }
 
-func (c *Controller) objCopyFromCache(key string) (pkgruntime.Object, error) {
-	cachedObj, exist, err := c.store.GetByKey(key)
+func (c *Controller) objCopyFromCache(qualifiedName util.QualifiedName) (*corev1b1.FederatedTypeConfig, error) {
+	typeConfig := &corev1b1.FederatedTypeConfig{}
+	key := types.NamespacedName{Namespace: qualifiedName.Namespace, Name: qualifiedName.Name}
+	err := c.cache.Get(context.TODO(), key, typeConfig)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
 	if err != nil {
-		wrappedErr := errors.Wrapf(err, "Failed to query FederatedTypeConfig store for %q", key)
+		wrappedErr := errors.Wrapf(err, "Failed to query FederatedTypeConfig cache for %q", qualifiedName)
 		runtime.HandleError(wrappedErr)
 		return nil, err
 	}
-	if !exist {
-		return nil, nil
+	return typeConfig, nil
+}
+
+// listTypeConfigs returns copies of the FederatedTypeConfig objects in
+// the cache.
+func (c *Controller) listTypeConfigs() ([]corev1b1.FederatedTypeConfig, error) {
+	typeConfigs := &corev1b1.FederatedTypeConfigList{}
+	opts := crclient.InNamespace(c.controllerConfig.KubeFedNamespace)
+	if err := c.cache.List(context.TODO(), opts, typeConfigs); err != nil {
+		return nil, errors.Wrap(err, "Failed to list FederatedTypeConfig objects from the cache")
 	}
-	return cachedObj.(pkgruntime.Object).DeepCopyObject(), nil
+	return typeConfigs.Items, nil
 }
 
 func (c *Controller) shutDown() {
@@ -308,6 +380,28 @@ func (c *Controller) startSyncController(tc *corev1b1.FederatedTypeConfig) error
 		return errors.Wrapf(err, "Error starting sync controller for %q", kind)
 	}
 	klog.Infof("Started sync controller for %q", kind)
+	if utilfeature.DefaultFeatureGate.Enabled(features.AutoFederation) && c.controllerConfig.ControllerEnabled(corev1b1.AutoFederationName) {
+		// The auto-federation controller shares the lifecycle of the
+		// sync controller.
+		err = autofederation.StartController(c.controllerConfig, stopChan, tc)
+		if err != nil {
+			close(stopChan)
+			return errors.Wrapf(err, "Error starting auto-federation controller for %q", kind)
+		}
+	}
+	targetType := tc.GetTargetType()
+	// Lighthouse writes to member clusters, so it is not started while
+	// propagation is paused.
+	if targetType.Kind == util.ServiceKind && targetType.Group == "" && !tc.GetPropagationPaused() &&
+		utilfeature.DefaultFeatureGate.Enabled(features.SubmarinerLighthouse) && c.controllerConfig.ControllerEnabled(corev1b1.SubmarinerLighthouseName) {
+		// The Lighthouse controller of services shares the lifecycle
+		// of their sync controller.
+		err = lighthouse.StartController(c.controllerConfig, stopChan, tc)
+		if err != nil {
+			close(stopChan)
+			return errors.Wrapf(err, "Error starting Lighthouse controller for %q", kind)
+		}
+	}
 	c.lock.Lock()
 	defer c.lock.Unlock()
 	c.stopChannels[tc.Name] = stopChan
@@ -380,24 +474,99 @@ func (c *Controller) getFederatedNamespaceAPIResource() (*metav1.APIResource, er
 		Namespace: c.controllerConfig.KubeFedNamespace,
 		Name:      util.NamespaceName,
 	}
-	key := qualifiedName.String()
-	cachedObj, exists, err := c.store.GetByKey(key)
-	if err != nil {
-		return nil, errors.Wrapf(err, "Error retrieving %q from the informer cache", key)
+	key := types.NamespacedName{Namespace: qualifiedName.Namespace, Name: qualifiedName.Name}
+	namespaceTypeConfig := &corev1b1.FederatedTypeConfig{}
+	err := c.cache.Get(context.TODO(), key, namespaceTypeConfig)
+	if apierrors.IsNotFound(err) {
+		return nil, errors.Errorf("Unable to find %q in the informer cache", qualifiedName)
 	}
-	if !exists {
-		return nil, errors.Errorf("Unable to find %q in the informer cache", key)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error retrieving %q from the informer cache", qualifiedName)
 	}
-	namespaceTypeConfig := cachedObj.(*corev1b1.FederatedTypeConfig)
 	apiResource := namespaceTypeConfig.GetFederatedType()
 	return &apiResource, nil
 }
 
+// healthCheck returns an error if a FederatedTypeConfig with
+// propagation enabled has been without a running sync controller for
+// longer than syncControllerStartTimeout.
+func (c *Controller) healthCheck() error {
+	if !c.informer.HasSynced() {
+		return errors.New("FederatedTypeConfig informer has not synced")
+	}
+	namespaceFTCExists := c.namespaceFTCExists()
+	typeConfigs, err := c.listTypeConfigs()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	notRunning := sets.NewString()
+	failed := sets.NewString()
+	for i := range typeConfigs {
+		typeConfig := &typeConfigs[i]
+		corev1b1.SetFederatedTypeConfigDefaults(typeConfig)
+		if typeConfig.DeletionTimestamp != nil || !(typeConfig.GetPropagationEnabled() || typeConfig.GetPropagationPaused()) {
+			continue
+		}
+		// Namespaced types cannot be propagated without the
+		// FederatedTypeConfig for namespaces.
+		if typeConfig.GetNamespaced() && !namespaceFTCExists {
+			continue
+		}
+		if _, ok := c.stopChannels[typeConfig.Name]; ok {
+			continue
+		}
+		notRunning.Insert(typeConfig.Name)
+		since, ok := c.notRunningSince[typeConfig.Name]
+		if !ok {
+			c.notRunningSince[typeConfig.Name] = now
+			continue
+		}
+		if now.Sub(since) > syncControllerStartTimeout {
+			failed.Insert(typeConfig.Name)
+		}
+	}
+	for name := range c.notRunningSince {
+		if !notRunning.Has(name) {
+			delete(c.notRunningSince, name)
+		}
+	}
+	if len(failed) > 0 {
+		return errors.Errorf("sync controllers are not running for %s", strings.Join(failed.List(), ", "))
+	}
+	return nil
+}
+
+// dumpControllers writes the sync and status controllers that are
+// running to a diagnostic dump.
+func (c *Controller) dumpControllers(w io.Writer) {
+	c.lock.RLock()
+	running := sets.NewString()
+	for key := range c.stopChannels {
+		running.Insert(key)
+	}
+	c.lock.RUnlock()
+	fmt.Fprintf(w, "running controllers: %s\n", strings.Join(running.List(), ", "))
+}
+
 func (c *Controller) reconcileOnNamespaceFTCUpdate() {
-	for _, cachedObj := range c.store.List() {
-		typeConfig := cachedObj.(*corev1b1.FederatedTypeConfig)
-		if typeConfig.GetNamespaced() && !typeConfig.IsNamespace() {
-			c.worker.EnqueueObject(typeConfig)
+	typeConfigs, err := c.listTypeConfigs()
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	for i := range typeConfigs {
+		typeConfig := &typeConfigs[i]
+		if !typeConfig.GetNamespaced() || typeConfig.IsNamespace() {
+			continue
+		}
+		select {
+		case c.events <- event.GenericEvent{Meta: typeConfig, Object: typeConfig}:
+		case <-c.stopChan:
+			return
 		}
 	}
 }
@@ -31,12 +31,15 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	pkgruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
 	dnsv1a1 "sigs.k8s.io/kubefed/pkg/apis/multiclusterdns/v1alpha1"
 	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	"sigs.k8s.io/kubefed/pkg/controller/dnsendpoint"
 	"sigs.k8s.io/kubefed/pkg/controller/util"
 )
 
@@ -69,7 +72,11 @@ type Controller struct {
 
 // StartController starts the Controller for managing IngressDNSRecord objects.
 func StartController(config *util.ControllerConfig, stopChan <-chan struct{}) error {
-	controller, err := newController(config)
+	mgr, shared, err := util.ManagerFor(config)
+	if err != nil {
+		return err
+	}
+	controller, err := newController(config, mgr)
 	if err != nil {
 		return err
 	}
@@ -78,11 +85,16 @@ func StartController(config *util.ControllerConfig, stopChan <-chan struct{}) er
 	}
 	klog.Infof("Starting IngressDNS controller")
 	controller.Run(stopChan)
+	if !shared {
+		util.StartManager(mgr, stopChan)
+	}
 	return nil
 }
 
-// newController returns a new controller to manage IngressDNSRecord objects.
-func newController(config *util.ControllerConfig) (*Controller, error) {
+// newController returns a new controller to manage IngressDNSRecord objects
+// that watches the objects of the host cluster with the informers of the
+// given manager.
+func newController(config *util.ControllerConfig, mgr manager.Manager) (*Controller, error) {
 	client := genericclient.NewForConfigOrDieWithUserAgent(config.KubeConfig, "IngressDNS")
 	s := &Controller{
 		client:                  client,
@@ -91,7 +103,7 @@ func newController(config *util.ControllerConfig) (*Controller, error) {
 		smallDelay:              time.Second * 3,
 	}
 
-	s.worker = util.NewReconcileWorker(s.reconcile, util.WorkerTiming{
+	s.worker = util.NewReconcileWorker(util.WorkerName{Controller: "ingressdns"}, s.reconcile, util.WorkerTiming{
 		ClusterSyncDelay: s.clusterAvailableDelay,
 	})
 
@@ -100,11 +112,10 @@ func newController(config *util.ControllerConfig) (*Controller, error) {
 
 	// Informer for the IngressDNSRecord resources in the host cluster
 	var err error
-	s.ingressDNSStore, s.ingressDNSController, err = util.NewGenericInformer(
-		config.KubeConfig,
+	s.ingressDNSStore, s.ingressDNSController, err = util.NewManagerInformer(
+		mgr,
 		config.TargetNamespace,
 		&dnsv1a1.IngressDNSRecord{},
-		util.NoResyncPeriod,
 		s.worker.EnqueueObject,
 	)
 	if err != nil {
@@ -154,7 +165,6 @@ func (c *Controller) minimizeLatency() {
 
 // Run runs the Controller.
 func (c *Controller) Run(stopChan <-chan struct{}) {
-	go c.ingressDNSController.Run(stopChan)
 	c.ingressFederatedInformer.Start()
 	c.clusterDeliverer.StartWithHandler(func(_ *util.DelayingDelivererItem) {
 		c.reconcileOnClusterChange()
@@ -173,6 +183,10 @@ func (c *Controller) Run(stopChan <-chan struct{}) {
 // Check whether all data stores are in sync. False is returned if any of the ingressFederatedInformer/stores is not yet
 // synced with the corresponding api server.
 func (c *Controller) isSynced() bool {
+	if !c.ingressDNSController.HasSynced() {
+		klog.V(2).Infof("IngressDNSRecord informer not synced")
+		return false
+	}
 	if !c.ingressFederatedInformer.ClustersSynced() {
 		klog.V(2).Infof("Cluster list not synced")
 		return false
@@ -239,11 +253,12 @@ func (c *Controller) reconcile(qualifiedName util.QualifiedName) util.Reconcilia
 			Cluster: cluster.Name,
 		}
 
-		lbStatus, err := c.getIngressStatusInCluster(cluster.Name, key)
+		lbStatus, hosts, err := c.getIngressStatusInCluster(cluster.Name, key)
 		if err != nil {
 			return util.StatusError
 		}
 		clusterDNS.LoadBalancer = *lbStatus
+		clusterDNS.Hosts = hosts
 		newIngressDNS.Status.DNS = append(newIngressDNS.Status.DNS, clusterDNS)
 	}
 
@@ -262,26 +277,28 @@ func (c *Controller) reconcile(qualifiedName util.QualifiedName) util.Reconcilia
 	return util.StatusAllOK
 }
 
-// getIngressStatusInCluster returns ingress status in federated cluster
-func (c *Controller) getIngressStatusInCluster(cluster, key string) (*corev1.LoadBalancerStatus, error) {
+// getIngressStatusInCluster returns ingress status and the hosts
+// served by the ingress in federated cluster
+func (c *Controller) getIngressStatusInCluster(cluster, key string) (*corev1.LoadBalancerStatus, []string, error) {
 	lbStatus := &corev1.LoadBalancerStatus{}
+	var hosts []string
 
 	clusterIngressObj, ingressFound, err := c.ingressFederatedInformer.GetTargetStore().GetByKey(cluster, key)
 	if err != nil {
 		runtime.HandleError(errors.Wrapf(err, "Failed to get %s ingress from %s", key, cluster))
-		return lbStatus, err
+		return lbStatus, hosts, err
 	}
 	if ingressFound {
 		//TODO(shashi): Find better alternative to convert Unstructured to a given type
 		clusterIngress, ok := clusterIngressObj.(*unstructured.Unstructured)
 		if !ok {
 			runtime.HandleError(errors.Errorf("Failed to cast the object to unstructured object: %v", clusterIngressObj))
-			return lbStatus, err
+			return lbStatus, hosts, err
 		}
 		content, err := clusterIngress.MarshalJSON()
 		if err != nil {
 			runtime.HandleError(errors.Wrapf(err, "Failed to marshall the unstructured object: %v", clusterIngress))
-			return lbStatus, err
+			return lbStatus, hosts, err
 		}
 		ingress := extv1b1.Ingress{}
 		err = json.Unmarshal(content, &ingress)
@@ -296,7 +313,30 @@ func (c *Controller) getIngressStatusInCluster(cluster, key string) (*corev1.Loa
 			})
 
 			lbStatus.Ingress = lbIngress
+			hosts = ingressHosts(&ingress)
 		}
 	}
-	return lbStatus, nil
+	return lbStatus, hosts, nil
+}
+
+// ingressHosts returns the sorted hosts of the rules and TLS
+// configuration of the given ingress.
+func ingressHosts(ingress *extv1b1.Ingress) []string {
+	hosts := sets.NewString()
+	for _, rule := range ingress.Spec.Rules {
+		if rule.Host != "" {
+			hosts.Insert(dnsendpoint.NormalizeHost(rule.Host))
+		}
+	}
+	for _, tls := range ingress.Spec.TLS {
+		for _, host := range tls.Hosts {
+			if host != "" {
+				hosts.Insert(dnsendpoint.NormalizeHost(host))
+			}
+		}
+	}
+	if hosts.Len() == 0 {
+		return nil
+	}
+	return hosts.List()
 }
@@ -0,0 +1,543 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package istio
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	dnsv1a1 "sigs.k8s.io/kubefed/pkg/apis/multiclusterdns/v1alpha1"
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+const (
+	allClustersKey = "ALL_CLUSTERS"
+
+	// The east-west gateway through which the services of a member
+	// cluster are reachable from the other clusters of the mesh, as
+	// installed for Istio multi-network meshes.
+	eastWestGatewayNamespace = "istio-system"
+	eastWestGatewayName      = "istio-eastwestgateway"
+	// eastWestGatewayPort is the port of the east-west gateway that
+	// routes mTLS traffic to services by SNI.
+	eastWestGatewayPort = 15443
+
+	// meshHostSuffix is the suffix of the hosts of the generated
+	// ServiceEntries, i.e. <service>.<namespace>.global.
+	meshHostSuffix = "global"
+
+	// localServiceSuffix is the suffix of the name by which a service
+	// is resolved in its own cluster.
+	localServiceSuffix = "svc.cluster.local"
+)
+
+var (
+	serviceAPIResource = &metav1.APIResource{
+		Group:        "",
+		Version:      "v1",
+		Kind:         "Service",
+		Name:         "services",
+		SingularName: "service",
+		Namespaced:   true,
+	}
+	serviceEntryAPIResource = &metav1.APIResource{
+		Group:        "networking.istio.io",
+		Version:      "v1beta1",
+		Kind:         "ServiceEntry",
+		Name:         "serviceentries",
+		SingularName: "serviceentry",
+		Namespaced:   true,
+	}
+)
+
+// serviceShard is a member cluster in which a service exists.
+type serviceShard struct {
+	clusterName string
+	// Locality of the cluster in the region/zone form used by Istio
+	locality string
+	service  *corev1.Service
+	// Address of the east-west gateway of the cluster, if any
+	gatewayAddress string
+}
+
+// Controller maintains Istio ServiceEntries in member clusters for the
+// services with cross-cluster discovery enabled by a ServiceDNSRecord,
+// so that the services are routable across the clusters of a
+// multi-primary mesh through the east-west gateways of the clusters.
+type Controller struct {
+	// For triggering reconciliation of all services. This is used
+	// when a cluster or an east-west gateway changes.
+	clusterDeliverer *util.DelayingDeliverer
+
+	// Informer for service resources in member clusters
+	serviceInformer util.FederatedInformer
+
+	// Informer for ServiceEntry resources in member clusters
+	serviceEntryInformer util.FederatedInformer
+
+	// Store for the ServiceDNSRecord objects
+	serviceDNSStore cache.Store
+	// Informer for the ServiceDNSRecord objects
+	serviceDNSController cache.Controller
+
+	worker util.ReconcileWorker
+
+	clusterAvailableDelay   time.Duration
+	clusterUnavailableDelay time.Duration
+	smallDelay              time.Duration
+}
+
+// StartController starts the Controller maintaining Istio
+// ServiceEntries for federated services.
+func StartController(config *util.ControllerConfig, stopChan <-chan struct{}) error {
+	controller, err := newController(config)
+	if err != nil {
+		return err
+	}
+	if config.MinimizeLatency {
+		controller.minimizeLatency()
+	}
+	klog.Infof("Starting Istio multi-cluster controller")
+	controller.Run(stopChan)
+	return nil
+}
+
+func newController(config *util.ControllerConfig) (*Controller, error) {
+	client := genericclient.NewForConfigOrDieWithUserAgent(config.KubeConfig, "IstioMultiCluster")
+	c := &Controller{
+		clusterAvailableDelay:   config.ClusterAvailableDelay,
+		clusterUnavailableDelay: config.ClusterUnavailableDelay,
+		smallDelay:              time.Second * 3,
+	}
+
+	c.worker = util.NewReconcileWorker(util.WorkerName{Controller: "istiomulticluster"}, c.reconcile, util.WorkerTiming{
+		ClusterSyncDelay: c.clusterAvailableDelay,
+	})
+
+	c.clusterDeliverer = util.NewDelayingDeliverer()
+
+	var err error
+	c.serviceDNSStore, c.serviceDNSController, err = util.NewGenericInformer(
+		config.KubeConfig,
+		config.TargetNamespace,
+		&dnsv1a1.ServiceDNSRecord{},
+		util.NoResyncPeriod,
+		c.worker.EnqueueObject,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	c.serviceInformer, err = util.NewFederatedInformer(
+		config,
+		client,
+		serviceAPIResource,
+		func(obj pkgruntime.Object) {
+			// The services of all the other clusters are routed
+			// through the east-west gateway of a cluster.
+			if isEastWestGateway(obj) {
+				c.clusterDeliverer.DeliverAt(allClustersKey, nil, time.Now())
+				return
+			}
+			c.worker.EnqueueObject(obj)
+		},
+		&util.ClusterLifecycleHandlerFuncs{
+			ClusterAvailable: func(cluster *fedv1b1.KubeFedCluster) {
+				c.clusterDeliverer.DeliverAt(allClustersKey, nil, time.Now().Add(c.clusterAvailableDelay))
+			},
+			ClusterUnavailable: func(cluster *fedv1b1.KubeFedCluster, _ []interface{}) {
+				c.clusterDeliverer.DeliverAt(allClustersKey, nil, time.Now().Add(c.clusterUnavailableDelay))
+			},
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	c.serviceEntryInformer, err = util.NewFederatedInformer(
+		config,
+		client,
+		serviceEntryAPIResource,
+		c.worker.EnqueueObject,
+		&util.ClusterLifecycleHandlerFuncs{},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// minimizeLatency reduces delays and timeouts to make the controller more responsive (useful for testing).
+func (c *Controller) minimizeLatency() {
+	c.clusterAvailableDelay = time.Second
+	c.clusterUnavailableDelay = time.Second
+	c.smallDelay = 20 * time.Millisecond
+	c.worker.SetDelay(50*time.Millisecond, c.clusterAvailableDelay)
+}
+
+// Run runs the Controller.
+func (c *Controller) Run(stopChan <-chan struct{}) {
+	go c.serviceDNSController.Run(stopChan)
+	c.serviceInformer.Start()
+	c.serviceEntryInformer.Start()
+	c.clusterDeliverer.StartWithHandler(func(_ *util.DelayingDelivererItem) {
+		c.reconcileOnClusterChange()
+	})
+
+	c.worker.Run(stopChan)
+
+	// Ensure all goroutines are cleaned up when the stop channel closes
+	go func() {
+		<-stopChan
+		c.serviceInformer.Stop()
+		c.serviceEntryInformer.Stop()
+		c.clusterDeliverer.Stop()
+	}()
+}
+
+// isSynced indicates whether the ServiceDNSRecord and service stores
+// are synced.  The ServiceEntry stores are checked per cluster when
+// writing ServiceEntries so that ServiceEntries are still maintained
+// in the other clusters when Istio is not installed in a cluster.
+func (c *Controller) isSynced() bool {
+	if !c.serviceDNSController.HasSynced() {
+		return false
+	}
+	if !c.serviceInformer.ClustersSynced() {
+		klog.V(2).Infof("Cluster list not synced")
+		return false
+	}
+	clusters, err := c.serviceInformer.GetReadyClusters()
+	if err != nil {
+		runtime.HandleError(errors.Wrap(err, "Failed to get ready clusters"))
+		return false
+	}
+	return c.serviceInformer.GetTargetStore().ClustersSynced(clusters)
+}
+
+// reconcileOnClusterChange triggers reconciliation of all services
+// with cross-cluster discovery enabled.
+func (c *Controller) reconcileOnClusterChange() {
+	if !c.isSynced() {
+		c.clusterDeliverer.DeliverAt(allClustersKey, nil, time.Now().Add(c.clusterAvailableDelay))
+	}
+	for _, obj := range c.serviceDNSStore.List() {
+		qualifiedName := util.NewQualifiedName(obj.(pkgruntime.Object))
+		c.worker.EnqueueWithDelay(qualifiedName, c.smallDelay)
+	}
+}
+
+func (c *Controller) reconcile(qualifiedName util.QualifiedName) util.ReconciliationStatus {
+	if !c.isSynced() {
+		return util.StatusNotSynced
+	}
+
+	key := qualifiedName.String()
+
+	klog.V(4).Infof("Starting to reconcile Istio ServiceEntries for service %v", key)
+	startTime := time.Now()
+	defer func() {
+		klog.V(4).Infof("Finished reconciling Istio ServiceEntries for service %v (duration: %v)", key, time.Since(startTime))
+	}()
+
+	_, enabled, err := c.serviceDNSStore.GetByKey(key)
+	if err != nil {
+		runtime.HandleError(errors.Wrapf(err, "Failed to query ServiceDNS store for %q", key))
+		return util.StatusError
+	}
+
+	clusters, err := c.serviceInformer.GetReadyClusters()
+	if err != nil {
+		runtime.HandleError(errors.Wrap(err, "Failed to get ready cluster list"))
+		return util.StatusError
+	}
+
+	var shards []serviceShard
+	if enabled {
+		shards, err = c.serviceShards(clusters, key)
+		if err != nil {
+			runtime.HandleError(err)
+			return util.StatusError
+		}
+	}
+
+	status := util.StatusAllOK
+	for _, cluster := range clusters {
+		var serviceEntry *unstructured.Unstructured
+		if enabled {
+			namespace := util.ClusterNamespace(cluster.Spec.NamespaceMappings, qualifiedName.Namespace)
+			serviceEntry = newServiceEntry(qualifiedName, namespace, cluster.Name, shards)
+		}
+		if err := c.ensureServiceEntry(cluster, qualifiedName, serviceEntry); err != nil {
+			runtime.HandleError(errors.Wrapf(err, "Failed to reconcile the Istio ServiceEntry for service %q in cluster %q", key, cluster.Name))
+			status = util.StatusError
+		}
+	}
+	return status
+}
+
+// serviceShards returns the shards of the service in the given
+// clusters, sorted by cluster name.
+func (c *Controller) serviceShards(clusters []*fedv1b1.KubeFedCluster, key string) ([]serviceShard, error) {
+	gatewayKey := util.QualifiedName{Namespace: eastWestGatewayNamespace, Name: eastWestGatewayName}.String()
+	var shards []serviceShard
+	for _, cluster := range clusters {
+		service, err := c.clusterService(cluster.Name, key)
+		if err != nil {
+			return nil, err
+		}
+		if service == nil {
+			continue
+		}
+		gateway, err := c.clusterService(cluster.Name, gatewayKey)
+		if err != nil {
+			return nil, err
+		}
+		shards = append(shards, serviceShard{
+			clusterName:    cluster.Name,
+			locality:       clusterLocality(cluster),
+			service:        service,
+			gatewayAddress: gatewayAddress(gateway),
+		})
+	}
+	sort.Slice(shards, func(i, j int) bool {
+		return shards[i].clusterName < shards[j].clusterName
+	})
+	return shards, nil
+}
+
+// clusterService returns the service with the given key in the given
+// cluster, or nil if it does not exist.
+func (c *Controller) clusterService(clusterName, key string) (*corev1.Service, error) {
+	obj, found, err := c.serviceInformer.GetTargetStore().GetByKey(clusterName, key)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get service %q from cluster %q", key, clusterName)
+	}
+	if !found {
+		return nil, nil
+	}
+	service := &corev1.Service{}
+	err = pkgruntime.DefaultUnstructuredConverter.FromUnstructured(obj.(*unstructured.Unstructured).Object, service)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to convert service %q of cluster %q", key, clusterName)
+	}
+	return service, nil
+}
+
+// ensureServiceEntry ensures that the ServiceEntry of the service in
+// the given cluster matches the desired ServiceEntry, or is removed if
+// no ServiceEntry is desired.  ServiceEntries not created by KubeFed
+// are left untouched.
+func (c *Controller) ensureServiceEntry(cluster *fedv1b1.KubeFedCluster, qualifiedName util.QualifiedName,
+	desired *unstructured.Unstructured) error {
+	if !c.serviceEntryInformer.GetTargetStore().ClusterSynced(cluster.Name) {
+		return errors.New("ServiceEntries are not synced, is Istio installed in the cluster?")
+	}
+	existingObj, found, err := c.serviceEntryInformer.GetTargetStore().GetByKey(cluster.Name, qualifiedName.String())
+	if err != nil {
+		return err
+	}
+	if desired == nil && !found {
+		return nil
+	}
+	var existing *unstructured.Unstructured
+	if found {
+		existing = existingObj.(*unstructured.Unstructured)
+		if !util.HasManagedLabel(existing) {
+			klog.V(2).Infof("Not reconciling ServiceEntry %s/%s in cluster %q since it is not managed by KubeFed",
+				existing.GetNamespace(), existing.GetName(), cluster.Name)
+			return nil
+		}
+	}
+
+	client, err := c.serviceEntryInformer.GetClientForCluster(cluster.Name)
+	if err != nil {
+		return err
+	}
+	namespace := util.ClusterNamespace(cluster.Spec.NamespaceMappings, qualifiedName.Namespace)
+	resources := client.Resources(namespace)
+
+	switch {
+	case desired == nil:
+		err = resources.Delete(qualifiedName.Name, &metav1.DeleteOptions{})
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	case !found:
+		_, err = resources.Create(desired, metav1.CreateOptions{})
+		return err
+	case !reflect.DeepEqual(existing.Object["spec"], desired.Object["spec"]):
+		updated := existing.DeepCopy()
+		updated.Object["spec"] = desired.Object["spec"]
+		_, err = resources.Update(updated, metav1.UpdateOptions{})
+		return err
+	}
+	return nil
+}
+
+// newServiceEntry returns the ServiceEntry for the service in the
+// namespace of the given cluster.  The ServiceEntry resolves the mesh
+// host of the service to the service itself, if it exists in the
+// cluster, and to the east-west gateways of the other clusters it
+// exists in.  Nil is returned if the service has no such endpoints.
+func newServiceEntry(qualifiedName util.QualifiedName, namespace, clusterName string, shards []serviceShard) *unstructured.Unstructured {
+	if len(shards) == 0 {
+		return nil
+	}
+	servicePorts := shards[0].service.Spec.Ports
+	for _, shard := range shards {
+		if shard.clusterName == clusterName {
+			servicePorts = shard.service.Spec.Ports
+		}
+	}
+
+	var ports []interface{}
+	gatewayPorts := make(map[string]interface{})
+	for _, port := range servicePorts {
+		if port.Protocol == corev1.ProtocolUDP {
+			// Istio does not route UDP traffic.
+			continue
+		}
+		name := port.Name
+		if name == "" {
+			name = fmt.Sprintf("port-%d", port.Port)
+		}
+		ports = append(ports, map[string]interface{}{
+			"name":     name,
+			"number":   int64(port.Port),
+			"protocol": portProtocol(name),
+		})
+		gatewayPorts[name] = int64(eastWestGatewayPort)
+	}
+	if len(ports) == 0 {
+		return nil
+	}
+
+	var endpoints []interface{}
+	for _, shard := range shards {
+		var endpoint map[string]interface{}
+		if shard.clusterName == clusterName {
+			endpoint = map[string]interface{}{
+				"address": strings.Join([]string{shard.service.Name, shard.service.Namespace, localServiceSuffix}, "."),
+			}
+		} else if shard.gatewayAddress != "" {
+			endpoint = map[string]interface{}{
+				"address": shard.gatewayAddress,
+				"ports":   gatewayPorts,
+			}
+		} else {
+			continue
+		}
+		if shard.locality != "" {
+			endpoint["locality"] = shard.locality
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	if len(endpoints) == 0 {
+		return nil
+	}
+
+	serviceEntry := &unstructured.Unstructured{}
+	serviceEntry.SetAPIVersion(serviceEntryAPIResource.Group + "/" + serviceEntryAPIResource.Version)
+	serviceEntry.SetKind(serviceEntryAPIResource.Kind)
+	serviceEntry.SetNamespace(namespace)
+	serviceEntry.SetName(qualifiedName.Name)
+	util.AddManagedLabel(serviceEntry)
+	serviceEntry.Object["spec"] = map[string]interface{}{
+		"hosts":      []interface{}{meshHost(qualifiedName)},
+		"location":   "MESH_INTERNAL",
+		"resolution": "DNS",
+		"ports":      ports,
+		"endpoints":  endpoints,
+	}
+	return serviceEntry
+}
+
+// meshHost returns the host by which a service with cross-cluster
+// discovery enabled is reachable in all the clusters of the mesh.
+func meshHost(qualifiedName util.QualifiedName) string {
+	return strings.Join([]string{qualifiedName.Name, qualifiedName.Namespace, meshHostSuffix}, ".")
+}
+
+// portProtocol returns the Istio protocol of a service port from the
+// prefix of its name, following the Istio port naming convention.
+func portProtocol(portName string) string {
+	prefix := strings.ToUpper(strings.SplitN(portName, "-", 2)[0])
+	switch prefix {
+	case "HTTP", "HTTP2", "HTTPS", "GRPC", "TLS", "MONGO", "MYSQL", "REDIS":
+		return prefix
+	}
+	return "TCP"
+}
+
+// clusterLocality returns the locality of a cluster in the
+// region/zone form used by Istio.
+func clusterLocality(cluster *fedv1b1.KubeFedCluster) string {
+	if cluster.Status.Region == "" {
+		return ""
+	}
+	if len(cluster.Status.Zones) == 0 {
+		return cluster.Status.Region
+	}
+	return cluster.Status.Region + "/" + cluster.Status.Zones[0]
+}
+
+// gatewayAddress returns the load balancer address of the given
+// east-west gateway, or an empty string if it has none.
+func gatewayAddress(gateway *corev1.Service) string {
+	if gateway == nil {
+		return ""
+	}
+	for _, ingress := range gateway.Status.LoadBalancer.Ingress {
+		if ingress.IP != "" {
+			return ingress.IP
+		}
+		if ingress.Hostname != "" {
+			return ingress.Hostname
+		}
+	}
+	return ""
+}
+
+// isEastWestGateway indicates whether the given object is the
+// east-west gateway service of a cluster.
+func isEastWestGateway(obj pkgruntime.Object) bool {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return false
+	}
+	return accessor.GetNamespace() == eastWestGatewayNamespace && accessor.GetName() == eastWestGatewayName
+}
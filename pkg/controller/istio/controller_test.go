@@ -0,0 +1,141 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package istio
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+func newService(namespace string, ports ...corev1.ServicePort) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "test-service"},
+		Spec:       corev1.ServiceSpec{Ports: ports},
+	}
+}
+
+func TestNewServiceEntry(t *testing.T) {
+	qualifiedName := util.QualifiedName{Namespace: "test-namespace", Name: "test-service"}
+	ports := []corev1.ServicePort{
+		{Name: "http-web", Port: 80, Protocol: corev1.ProtocolTCP},
+		{Name: "dns", Port: 53, Protocol: corev1.ProtocolUDP},
+		{Port: 9000, Protocol: corev1.ProtocolTCP},
+	}
+	shards := []serviceShard{
+		{
+			clusterName:    "cluster1",
+			locality:       "us-west1/us-west1-a",
+			service:        newService("test-namespace", ports...),
+			gatewayAddress: "10.0.0.1",
+		},
+		{
+			clusterName: "cluster2",
+			locality:    "us-east1/us-east1-b",
+			service:     newService("mapped-namespace", ports...),
+		},
+		{
+			clusterName:    "cluster3",
+			service:        newService("test-namespace", ports...),
+			gatewayAddress: "gateway.example.test",
+		},
+	}
+
+	serviceEntry := newServiceEntry(qualifiedName, "mapped-namespace", "cluster2", shards)
+	if !assert.NotNil(t, serviceEntry) {
+		return
+	}
+	assert.Equal(t, "networking.istio.io/v1beta1", serviceEntry.GetAPIVersion())
+	assert.Equal(t, "ServiceEntry", serviceEntry.GetKind())
+	assert.Equal(t, "mapped-namespace", serviceEntry.GetNamespace())
+	assert.Equal(t, "test-service", serviceEntry.GetName())
+	assert.True(t, util.HasManagedLabel(serviceEntry))
+
+	gatewayPorts := map[string]interface{}{
+		"http-web":  int64(eastWestGatewayPort),
+		"port-9000": int64(eastWestGatewayPort),
+	}
+	expectedSpec := map[string]interface{}{
+		"hosts":      []interface{}{"test-service.test-namespace.global"},
+		"location":   "MESH_INTERNAL",
+		"resolution": "DNS",
+		"ports": []interface{}{
+			map[string]interface{}{"name": "http-web", "number": int64(80), "protocol": "HTTP"},
+			map[string]interface{}{"name": "port-9000", "number": int64(9000), "protocol": "TCP"},
+		},
+		"endpoints": []interface{}{
+			map[string]interface{}{"address": "10.0.0.1", "ports": gatewayPorts, "locality": "us-west1/us-west1-a"},
+			map[string]interface{}{"address": "test-service.mapped-namespace.svc.cluster.local", "locality": "us-east1/us-east1-b"},
+			map[string]interface{}{"address": "gateway.example.test", "ports": gatewayPorts},
+		},
+	}
+	assert.Equal(t, expectedSpec, serviceEntry.Object["spec"])
+
+	// A cluster without the service only routes to the gateways of
+	// the other clusters.
+	serviceEntry = newServiceEntry(qualifiedName, "test-namespace", "cluster4", shards)
+	if assert.NotNil(t, serviceEntry) {
+		assert.Len(t, serviceEntry.Object["spec"].(map[string]interface{})["endpoints"], 2)
+	}
+
+	// No ServiceEntry is needed without shards reachable from the cluster.
+	assert.Nil(t, newServiceEntry(qualifiedName, "test-namespace", "cluster4", shards[1:2]))
+	assert.Nil(t, newServiceEntry(qualifiedName, "test-namespace", "cluster1", nil))
+
+	// Nor for a service without ports routed by Istio.
+	udpShards := []serviceShard{{clusterName: "cluster1", service: newService("test-namespace", ports[1])}}
+	assert.Nil(t, newServiceEntry(qualifiedName, "test-namespace", "cluster1", udpShards))
+}
+
+func TestPortProtocol(t *testing.T) {
+	testCases := map[string]string{
+		"http":       "HTTP",
+		"http2-api":  "HTTP2",
+		"grpc-web":   "GRPC",
+		"https":      "HTTPS",
+		"redis-main": "REDIS",
+		"web":        "TCP",
+		"tcp-db":     "TCP",
+		"port-8080":  "TCP",
+	}
+	for portName, expected := range testCases {
+		assert.Equal(t, expected, portProtocol(portName), portName)
+	}
+}
+
+func TestClusterLocality(t *testing.T) {
+	cluster := &fedv1b1.KubeFedCluster{}
+	assert.Equal(t, "", clusterLocality(cluster))
+	cluster.Status.Region = "us-west1"
+	assert.Equal(t, "us-west1", clusterLocality(cluster))
+	cluster.Status.Zones = []string{"us-west1-a", "us-west1-b"}
+	assert.Equal(t, "us-west1/us-west1-a", clusterLocality(cluster))
+}
+
+func TestGatewayAddress(t *testing.T) {
+	assert.Equal(t, "", gatewayAddress(nil))
+	gateway := &corev1.Service{}
+	assert.Equal(t, "", gatewayAddress(gateway))
+	gateway.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{Hostname: "gateway.example.test"}, {IP: "10.0.0.1"}}
+	assert.Equal(t, "gateway.example.test", gatewayAddress(gateway))
+}
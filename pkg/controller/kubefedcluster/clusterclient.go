@@ -17,23 +17,40 @@ limitations under the License.
 package kubefedcluster
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/pkg/errors"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/discovery"
 	kubeclientset "k8s.io/client-go/kubernetes"
 	restclient "k8s.io/client-go/rest"
 	"k8s.io/klog"
+	"sigs.k8s.io/yaml"
 
 	fedcommon "sigs.k8s.io/kubefed/pkg/apis/core/common"
 	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
 	"sigs.k8s.io/kubefed/pkg/client/generic"
 	"sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/metrics"
 )
 
 const (
@@ -42,31 +59,84 @@ const (
 	// Following labels come from k8s.io/kubernetes/pkg/kubelet/apis
 	LabelZoneFailureDomain = "failure-domain.beta.kubernetes.io/zone"
 	LabelZoneRegion        = "failure-domain.beta.kubernetes.io/region"
+
+	// The topology labels that replace the beta labels as of
+	// kubernetes 1.17.  They are also recognized on KubeFedClusters to
+	// set the zone and region of a cluster whose nodes are not
+	// labeled.
+	LabelTopologyZone   = "topology.kubernetes.io/zone"
+	LabelTopologyRegion = "topology.kubernetes.io/region"
+
+	// scaleUpFailureReason is the reason of the events recorded by
+	// the cluster autoscaler for pods that did not trigger a scale-up
+	// because no node group could fit them.
+	scaleUpFailureReason = "NotTriggerScaleUp"
+
+	// autoscalerStatusConfigMap is the name of the ConfigMap in
+	// which the cluster autoscaler reports its status.
+	autoscalerStatusConfigMap = "cluster-autoscaler-status"
+
+	// maxHealthzBodyLength is the length to which a response to
+	// /healthz without ok is truncated in the status of a cluster.
+	maxHealthzBodyLength = 256
+
+	// maxHealthProbeErrorLength is the length to which the error of a
+	// failed health probe, which may include the response of the
+	// probed service, is truncated in the status of a cluster.
+	maxHealthProbeErrorLength = 256
 )
 
+// autoscalerNodeGroupHealth matches the target and size limits of a
+// node group in the human-readable status of the cluster autoscaler.
+var autoscalerNodeGroupHealth = regexp.MustCompile(`cloudProviderTarget=(\d+) \(minSize=\d+, maxSize=(\d+)\)`)
+
+// managedProviderLabels identify the nodes of managed kubernetes
+// services, whose nodes would otherwise be identified by the
+// provider of their infrastructure.
+var managedProviderLabels = map[string]string{
+	"cloud.google.com/gke-nodepool": "gke",
+	"eks.amazonaws.com/nodegroup":   "eks",
+	"kubernetes.azure.com/cluster":  "aks",
+}
+
 // ClusterClient provides methods for determining the status and zones of a
 // particular KubeFedCluster.
 type ClusterClient struct {
 	kubeClient  *kubeclientset.Clientset
 	clusterName string
+
+	// The api endpoints of the cluster in the order they are tried
+	// and a client for each of them.
+	apiEndpoints []string
+	kubeClients  []*kubeclientset.Clientset
 }
 
 // NewClusterClientSet returns a ClusterClient for the given KubeFedCluster.
 // The kubeClient is used to configure the ClusterClient's internal client
-// with information from a kubeconfig stored in a kubernetes secret.
-func NewClusterClientSet(c *fedv1b1.KubeFedCluster, client generic.Client, fedNamespace string, timeout time.Duration) (*ClusterClient, error) {
-	clusterConfig, err := util.BuildClusterConfig(c, client, fedNamespace)
-	if err != nil {
-		return nil, err
-	}
-	clusterConfig.Timeout = timeout
+// with information from a kubeconfig stored in a kubernetes secret, or
+// from the host config if the cluster is local.
+func NewClusterClientSet(c *fedv1b1.KubeFedCluster, hostConfig *restclient.Config, client generic.Client, fedNamespace string, timeout time.Duration) (*ClusterClient, error) {
 	var clusterClientSet = ClusterClient{clusterName: c.Name}
-	if clusterConfig != nil {
-		clusterClientSet.kubeClient = kubeclientset.NewForConfigOrDie((restclient.AddUserAgent(clusterConfig, UserAgentName)))
-		if clusterClientSet.kubeClient == nil {
+	for _, apiEndpoint := range util.ClusterAPIEndpoints(c) {
+		clusterConfig, err := util.BuildClusterConfigForEndpoint(c, hostConfig, client, fedNamespace, apiEndpoint)
+		if err != nil {
+			return nil, err
+		}
+		if clusterConfig == nil {
+			continue
+		}
+		clusterConfig.Timeout = timeout
+		kubeClient := kubeclientset.NewForConfigOrDie((restclient.AddUserAgent(clusterConfig, UserAgentName)))
+		if kubeClient == nil {
 			return nil, nil
 		}
+		clusterClientSet.apiEndpoints = append(clusterClientSet.apiEndpoints, apiEndpoint)
+		clusterClientSet.kubeClients = append(clusterClientSet.kubeClients, kubeClient)
+	}
+	if len(clusterClientSet.kubeClients) == 0 {
+		return nil, nil
 	}
+	clusterClientSet.kubeClient = clusterClientSet.kubeClients[0]
 	return &clusterClientSet, nil
 }
 
@@ -106,13 +176,24 @@ func (self *ClusterClient) GetClusterHealthStatus() *fedv1b1.KubeFedClusterStatu
 		LastProbeTime:      currentTime,
 		LastTransitionTime: currentTime,
 	}
-	body, err := self.kubeClient.DiscoveryClient.RESTClient().Get().AbsPath("/healthz").Do().Raw()
+	body, latency, err := self.healthz()
+	metrics.RecordClusterHealthCheck(self.clusterName, latency, err)
 	if err != nil {
 		runtime.HandleError(errors.Wrapf(err, "Failed to do cluster health check for cluster %q", self.clusterName))
 		clusterStatus.Conditions = append(clusterStatus.Conditions, newClusterOfflineCondition)
+		clusterStatus.HealthCheckFailure = self.healthCheckFailure(err)
 	} else {
+		clusterStatus.APIEndpoint = self.apiEndpoints[self.activeIndex()]
+		clusterStatus.APIHealth = &fedv1b1.ClusterAPIHealth{
+			LatencyMilliseconds: latency.Nanoseconds() / int64(time.Millisecond),
+		}
 		if !strings.EqualFold(string(body), "ok") {
 			clusterStatus.Conditions = append(clusterStatus.Conditions, newClusterNotReadyCondition, newClusterNotOfflineCondition)
+			clusterStatus.HealthCheckFailure = &fedv1b1.ClusterHealthCheckFailure{
+				Reason:      fedv1b1.ClusterHealthCheckNotOk,
+				Message:     fmt.Sprintf("/healthz responded with %q", truncate(string(body), maxHealthzBodyLength)),
+				APIEndpoint: clusterStatus.APIEndpoint,
+			}
 		} else {
 			clusterStatus.Conditions = append(clusterStatus.Conditions, newClusterReadyCondition)
 		}
@@ -121,6 +202,162 @@ func (self *ClusterClient) GetClusterHealthStatus() *fedv1b1.KubeFedClusterStatu
 	return &clusterStatus
 }
 
+// healthz requests "/healthz" from the api endpoints of the cluster
+// in order and returns the response and round-trip latency of the
+// first endpoint that is reachable.  The client of that endpoint is
+// used for subsequent requests.
+func (self *ClusterClient) healthz() ([]byte, time.Duration, error) {
+	var errs []error
+	for i, kubeClient := range self.kubeClients {
+		start := time.Now()
+		body, err := kubeClient.DiscoveryClient.RESTClient().Get().AbsPath("/healthz").Do().Raw()
+		latency := time.Since(start)
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "endpoint %q", self.apiEndpoints[i]))
+			continue
+		}
+		if self.kubeClient != kubeClient {
+			klog.Infof("Using api endpoint %q of cluster %q", self.apiEndpoints[i], self.clusterName)
+			self.kubeClient = kubeClient
+		}
+		return body, latency, nil
+	}
+	return nil, 0, utilerrors.NewAggregate(errs)
+}
+
+// healthCheckFailure describes a health check whose requests to the
+// api endpoints of the cluster failed with the given error.  The
+// endpoints are tried in order, so the error is classified by the
+// error of the primary endpoint.
+func (self *ClusterClient) healthCheckFailure(err error) *fedv1b1.ClusterHealthCheckFailure {
+	primaryErr := err
+	if aggregate, ok := err.(utilerrors.Aggregate); ok && len(aggregate.Errors()) > 0 {
+		primaryErr = aggregate.Errors()[0]
+	}
+	return &fedv1b1.ClusterHealthCheckFailure{
+		Reason:      healthCheckFailureReason(primaryErr),
+		Message:     err.Error(),
+		APIEndpoint: self.apiEndpoints[0],
+	}
+}
+
+// healthCheckFailureReason classifies the error of a health check
+// request.  Errors of the transport are unwrapped until their cause
+// is recognized.
+func healthCheckFailureReason(err error) fedv1b1.ClusterHealthCheckFailureReason {
+	err = errors.Cause(err)
+	if status, ok := err.(apierrors.APIStatus); ok {
+		code := status.Status().Code
+		switch {
+		case code == http.StatusUnauthorized:
+			return fedv1b1.ClusterHealthCheckUnauthorized
+		case code == http.StatusForbidden:
+			return fedv1b1.ClusterHealthCheckForbidden
+		case code == http.StatusGatewayTimeout || apierrors.IsTimeout(err) || apierrors.IsServerTimeout(err):
+			return fedv1b1.ClusterHealthCheckTimeout
+		case code >= http.StatusInternalServerError:
+			return fedv1b1.ClusterHealthCheckServerError
+		}
+		return fedv1b1.ClusterHealthCheckUnknownError
+	}
+
+	timeout := false
+	for err != nil {
+		if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+			timeout = true
+		}
+		switch e := err.(type) {
+		case *net.DNSError:
+			return fedv1b1.ClusterHealthCheckDNSError
+		case x509.UnknownAuthorityError, x509.HostnameError, x509.CertificateInvalidError, tls.RecordHeaderError:
+			return fedv1b1.ClusterHealthCheckTLSError
+		case *url.Error:
+			err = e.Err
+		case *net.OpError:
+			err = e.Err
+		case *os.SyscallError:
+			err = e.Err
+		default:
+			if err == syscall.ECONNREFUSED {
+				return fedv1b1.ClusterHealthCheckConnectionRefused
+			}
+			// Errors of the TLS handshake are not all typed.
+			if message := err.Error(); strings.HasPrefix(message, "x509: ") || strings.HasPrefix(message, "tls: ") {
+				return fedv1b1.ClusterHealthCheckTLSError
+			}
+			err = nil
+		}
+	}
+	if timeout {
+		return fedv1b1.ClusterHealthCheckTimeout
+	}
+	return fedv1b1.ClusterHealthCheckUnknownError
+}
+
+// truncate returns the given string truncated to the given length.
+func truncate(s string, length int) string {
+	if len(s) <= length {
+		return s
+	}
+	return s[:length] + "..."
+}
+
+// activeIndex returns the index of the endpoint whose client is in use.
+func (self *ClusterClient) activeIndex() int {
+	for i, kubeClient := range self.kubeClients {
+		if kubeClient == self.kubeClient {
+			return i
+		}
+	}
+	return 0
+}
+
+// RunHealthProbes runs the given application-level probes of the
+// cluster and returns a description of each probe that failed.
+func (self *ClusterClient) RunHealthProbes(probes []fedv1b1.ClusterHealthProbe) []string {
+	var failures []string
+	for _, probe := range probes {
+		var err error
+		switch {
+		case probe.Service != nil:
+			err = self.probeService(probe.Service)
+		case probe.Deployment != nil:
+			err = self.probeDeployment(probe.Deployment)
+		}
+		if err != nil {
+			klog.V(2).Infof("Health probe %q of cluster %q failed: %v", probe.Name, self.clusterName, err)
+			failures = append(failures, fmt.Sprintf("health probe %q failed: %s", probe.Name, truncate(err.Error(), maxHealthProbeErrorLength)))
+		}
+	}
+	return failures
+}
+
+// probeService requests the path of the probed service through the
+// proxy of the API server, which fails unless the service responds
+// with a 2xx status code.
+func (self *ClusterClient) probeService(probe *fedv1b1.ClusterServiceProbe) error {
+	_, err := self.kubeClient.CoreV1().Services(probe.Namespace).ProxyGet(probe.Scheme, probe.Name, probe.Port, probe.Path, nil).DoRaw()
+	return err
+}
+
+// probeDeployment checks that the probed Deployment exists and that
+// all of its replicas are available.
+func (self *ClusterClient) probeDeployment(probe *fedv1b1.ClusterDeploymentProbe) error {
+	deployment, err := self.kubeClient.AppsV1().Deployments(probe.Namespace).Get(probe.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	if deployment.Status.AvailableReplicas < replicas {
+		return errors.Errorf("%d of %d replicas of Deployment %s/%s are available",
+			deployment.Status.AvailableReplicas, replicas, probe.Namespace, probe.Name)
+	}
+	return nil
+}
+
 // GetClusterZones gets the kubernetes cluster zones and region by inspecting labels on nodes in the cluster.
 func (self *ClusterClient) GetClusterZones() ([]string, string, error) {
 	nodes, err := self.kubeClient.CoreV1().Nodes().List(metav1.ListOptions{})
@@ -131,10 +368,10 @@ func (self *ClusterClient) GetClusterZones() ([]string, string, error) {
 
 	zones := sets.NewString()
 	region := ""
-	for i, node := range nodes.Items {
+	for _, node := range nodes.Items {
 		zone := getZoneNameForNode(node)
-		// region is same for all nodes in the cluster, so just pick the region from first node.
-		if i == 0 {
+		// region is same for all nodes in the cluster, so just pick the region from the first labeled node.
+		if region == "" {
 			region = getRegionNameForNode(node)
 		}
 		if zone != "" && !zones.Has(zone) {
@@ -144,22 +381,199 @@ func (self *ClusterClient) GetClusterZones() ([]string, string, error) {
 	return zones.List(), region, nil
 }
 
-// Find the name of the zone in which a Node is running.
-func getZoneNameForNode(node corev1.Node) string {
-	for key, value := range node.Labels {
-		if key == LabelZoneFailureDomain {
-			return value
+// GetClusterVersionAndProvider gets the kubernetes version reported by
+// the API server of the cluster and the infrastructure provider of the
+// cluster by inspecting one of its nodes.
+func (self *ClusterClient) GetClusterVersionAndProvider() (string, string, error) {
+	serverVersion, err := self.kubeClient.Discovery().ServerVersion()
+	if err != nil {
+		return "", "", errors.Wrap(err, "failed to get server version")
+	}
+	nodes, err := self.kubeClient.CoreV1().Nodes().List(metav1.ListOptions{Limit: 1})
+	if err != nil {
+		return "", "", errors.Wrap(err, "failed to list nodes")
+	}
+	provider := ""
+	if len(nodes.Items) > 0 {
+		provider = getProviderForNode(nodes.Items[0])
+	}
+	return serverVersion.GitVersion, provider, nil
+}
+
+// GetClusterPressure gets the unschedulable pods, the nodes under
+// pressure and the time of the most recent failed scale-up of the
+// cluster.
+func (self *ClusterClient) GetClusterPressure() (*fedv1b1.ClusterPressure, error) {
+	pressure := &fedv1b1.ClusterPressure{}
+
+	pods, err := self.kubeClient.CoreV1().Pods(metav1.NamespaceAll).List(metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("status.phase", string(corev1.PodPending)).String(),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list pending pods")
+	}
+	for _, pod := range pods.Items {
+		if isPodUnschedulable(&pod) {
+			pressure.UnschedulablePods++
+		}
+	}
+
+	nodes, err := self.kubeClient.CoreV1().Nodes().List(metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list nodes")
+	}
+	for _, node := range nodes.Items {
+		if isNodeUnderPressure(&node) {
+			pressure.NodesUnderPressure++
+		}
+	}
+
+	events, err := self.kubeClient.CoreV1().Events(metav1.NamespaceAll).List(metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("reason", scaleUpFailureReason).String(),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list cluster autoscaler events")
+	}
+	for _, event := range events.Items {
+		lastTime := event.LastTimestamp
+		if lastTime.IsZero() {
+			lastTime = metav1.NewTime(event.EventTime.Time)
+		}
+		if pressure.LastScaleUpFailureTime == nil || pressure.LastScaleUpFailureTime.Before(&lastTime) {
+			pressure.LastScaleUpFailureTime = &lastTime
 		}
 	}
+
+	pressure.Autoscaler, err = self.getAutoscalerStatus()
+	if err != nil {
+		klog.V(2).Infof("Failed to get the cluster autoscaler status: %v", err)
+	}
+	return pressure, nil
+}
+
+// getAutoscalerStatus reads the status ConfigMap of the cluster
+// autoscaler.  Scale-up is reported as disabled if the ConfigMap
+// does not exist.
+func (self *ClusterClient) getAutoscalerStatus() (*fedv1b1.ClusterAutoscalerStatus, error) {
+	configMap, err := self.kubeClient.CoreV1().ConfigMaps(metav1.NamespaceSystem).Get(autoscalerStatusConfigMap, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return &fedv1b1.ClusterAutoscalerStatus{ScaleUpDisabled: true}, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the cluster autoscaler status ConfigMap")
+	}
+	return parseAutoscalerStatus(configMap.Data["status"]), nil
+}
+
+// autoscalerYAMLStatus is the structured status reported by recent
+// versions of the cluster autoscaler.
+type autoscalerYAMLStatus struct {
+	NodeGroups []struct {
+		Health struct {
+			CloudProviderTarget int `json:"cloudProviderTarget"`
+			MaxSize             int `json:"maxSize"`
+		} `json:"health"`
+	} `json:"nodeGroups"`
+}
+
+// parseAutoscalerStatus counts the node groups of the given cluster
+// autoscaler status that have reached their maximum size.  Both the
+// structured status of recent versions of the cluster autoscaler and
+// the human-readable status of older versions are supported.
+func parseAutoscalerStatus(data string) *fedv1b1.ClusterAutoscalerStatus {
+	status := &fedv1b1.ClusterAutoscalerStatus{}
+	yamlStatus := &autoscalerYAMLStatus{}
+	if err := yaml.Unmarshal([]byte(data), yamlStatus); err == nil && len(yamlStatus.NodeGroups) > 0 {
+		for _, group := range yamlStatus.NodeGroups {
+			status.NodeGroups++
+			if group.Health.CloudProviderTarget >= group.Health.MaxSize {
+				status.NodeGroupsAtMaxSize++
+			}
+		}
+	} else {
+		for _, match := range autoscalerNodeGroupHealth.FindAllStringSubmatch(data, -1) {
+			target, _ := strconv.Atoi(match[1])
+			maxSize, _ := strconv.Atoi(match[2])
+			status.NodeGroups++
+			if target >= maxSize {
+				status.NodeGroupsAtMaxSize++
+			}
+		}
+	}
+	status.ScaleUpDisabled = status.NodeGroups == 0
+	status.MaxNodesReached = status.NodeGroups > 0 && status.NodeGroupsAtMaxSize == status.NodeGroups
+	return status
+}
+
+// isPodUnschedulable indicates whether the scheduler has failed to
+// find a node for the pod.
+func isPodUnschedulable(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodScheduled {
+			return condition.Status == corev1.ConditionFalse && condition.Reason == corev1.PodReasonUnschedulable
+		}
+	}
+	return false
+}
+
+// isNodeUnderPressure indicates whether the kubelet of the node
+// reports a shortage of memory, disk or process ids.
+func isNodeUnderPressure(node *corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		switch condition.Type {
+		case corev1.NodeMemoryPressure, corev1.NodeDiskPressure, corev1.NodePIDPressure:
+			if condition.Status == corev1.ConditionTrue {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// GetServerResources gets the API resources served by the cluster.
+// Group versions whose discovery failed are returned with their error
+// alongside the resources of the other group versions.
+func (self *ClusterClient) GetServerResources() ([]*metav1.APIResourceList, map[schema.GroupVersion]error, error) {
+	resourceLists, err := self.kubeClient.Discovery().ServerResources()
+	if discoveryErr, ok := err.(*discovery.ErrGroupDiscoveryFailed); ok {
+		return resourceLists, discoveryErr.Groups, nil
+	}
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to get server resources")
+	}
+	return resourceLists, nil, nil
+}
+
+// Find the name of the provider of the infrastructure a Node is
+// running on from its labels or the scheme of its provider id
+// (e.g. "aws:///us-east-1a/i-0123").
+func getProviderForNode(node corev1.Node) string {
+	for key, provider := range managedProviderLabels {
+		if _, ok := node.Labels[key]; ok {
+			return provider
+		}
+	}
+	if index := strings.Index(node.Spec.ProviderID, "://"); index > 0 {
+		return node.Spec.ProviderID[:index]
+	}
 	return ""
 }
 
+// Find the name of the zone in which a Node is running.
+func getZoneNameForNode(node corev1.Node) string {
+	return topologyLabel(node.Labels, LabelTopologyZone, LabelZoneFailureDomain)
+}
+
 // Find the name of the region in which a Node is running.
 func getRegionNameForNode(node corev1.Node) string {
-	for key, value := range node.Labels {
-		if key == LabelZoneRegion {
-			return value
-		}
+	return topologyLabel(node.Labels, LabelTopologyRegion, LabelZoneRegion)
+}
+
+// topologyLabel returns the value of the topology label with the given
+// key, or of the deprecated beta label if it is not set.
+func topologyLabel(labels map[string]string, key, betaKey string) string {
+	if value := labels[key]; len(value) > 0 {
+		return value
 	}
-	return ""
+	return labels[betaKey]
 }
@@ -18,9 +18,17 @@ package kubefedcluster
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -28,13 +36,50 @@ import (
 	restclient "k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 
+	"sigs.k8s.io/kubefed/pkg/apis/core/common"
 	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
 	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
 	"sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/controller/util/apidiscovery"
+	"sigs.k8s.io/kubefed/pkg/controller/util/circuitbreaker"
+	"sigs.k8s.io/kubefed/pkg/controller/util/deletionlimiter"
+	"sigs.k8s.io/kubefed/pkg/controller/util/notifier"
 	"sigs.k8s.io/kubefed/pkg/features"
 )
 
+// The weight given to the most recent health check when averaging the
+// api health of a cluster.
+const apiHealthSampleWeight = 0.2
+
+// The interval at which the API resources of a ready cluster are
+// discovered again while its kubernetes version is unchanged.
+const apiDiscoveryRefreshPeriod = 10 * time.Minute
+
+// The interval at which the zones and region of a ready cluster are
+// determined again from the labels of its nodes.
+const topologyRefreshPeriod = time.Minute
+
+// A health check is considered to be flapping if its result changed
+// at least flappingTransitions times in the most recent flappingWindow
+// checks.
+const (
+	flappingWindow      = 10
+	flappingTransitions = 4
+)
+
+// The period for which throttled requests and clock skew observed in
+// the responses of a cluster are reported by its conditions.
+const requestObservationPeriod = 5 * time.Minute
+
+// The difference between the clocks of a cluster and of the controller
+// manager above which a ClockSkew condition is reported.  Skew of this
+// magnitude may cause tokens and certificates to be considered not yet
+// valid or expired, and the leader election and timestamps of the
+// cluster to be misinterpreted.
+const clockSkewThreshold = 10 * time.Second
+
 // ClusterData stores cluster client and previous health check probe results of individual cluster.
 type ClusterData struct {
 	// clusterKubeClient is the kube client for the cluster.
@@ -45,6 +90,18 @@ type ClusterData struct {
 
 	// How many times in a row the probe has returned the same result.
 	resultRun int64
+
+	// The results of the most recent probes, oldest first, before
+	// they are adjusted by the thresholds.
+	probeResults []bool
+
+	// The time at which the probe was found to be flapping, or zero
+	// if it is not flapping.
+	flappingSince time.Time
+
+	// The time at which the zones and region of the cluster were last
+	// determined from its nodes.
+	topologyRefreshed time.Time
 }
 
 // ClusterController is responsible for maintaining the health status of each
@@ -52,6 +109,9 @@ type ClusterData struct {
 type ClusterController struct {
 	client genericclient.Client
 
+	// hostConfig is used to access local clusters.
+	hostConfig *restclient.Config
+
 	// clusterHealthCheckConfig is the configurable parameters for cluster health check
 	clusterHealthCheckConfig *util.ClusterHealthCheckConfig
 
@@ -60,6 +120,10 @@ type ClusterController struct {
 	// clusterDataMap is a mapping of clusterName and the cluster specific details.
 	clusterDataMap map[string]*ClusterData
 
+	// clusterStore holds the KubeFedClusters of the informer of the
+	// shared manager.
+	clusterStore cache.Store
+
 	// clusterController is the cache.Controller where callbacks are registered
 	// for events on KubeFedClusters.
 	clusterController cache.Controller
@@ -67,40 +131,77 @@ type ClusterController struct {
 	// fedNamespace is the name of the namespace containing
 	// KubeFedCluster resources and their associated secrets.
 	fedNamespace string
+
+	// notifier is informed of cluster readiness transitions.
+	notifier *notifier.Notifier
+
+	// circuitBreaker determines whether dispatch to a cluster is
+	// backed off, which is reported by a Degraded condition.
+	circuitBreaker *circuitbreaker.Breaker
+
+	// apiDiscovery receives the API resources discovered in each
+	// cluster.
+	apiDiscovery *apidiscovery.Cache
+
+	// lifecycleHooks runs the hooks configured for the events of the
+	// lifecycle of clusters.
+	lifecycleHooks *lifecycleHooks
+
+	// lastMonitored is the time at which the status of all clusters
+	// was last updated, or at which the controller started.
+	lastMonitored time.Time
 }
 
 // StartClusterController starts a new cluster controller.
 func StartClusterController(config *util.ControllerConfig, clusterHealthCheckConfig *util.ClusterHealthCheckConfig, stopChan <-chan struct{}) error {
-	controller, err := newClusterController(config, clusterHealthCheckConfig)
+	mgr, shared, err := util.ManagerFor(config)
+	if err != nil {
+		return err
+	}
+	controller, err := newClusterController(config, mgr, clusterHealthCheckConfig)
 	if err != nil {
 		return err
 	}
 	klog.Infof("Starting cluster controller")
 	controller.Run(stopChan)
+	if !shared {
+		util.StartManager(mgr, stopChan)
+	}
+	config.HealthChecks.AddLivenessCheck("cluster-controller", controller.healthCheck)
+	config.Diagnostics.AddSource("cluster-controller", controller.dumpClusters)
 	return nil
 }
 
-// newClusterController returns a new cluster controller
-func newClusterController(config *util.ControllerConfig, clusterHealthCheckConfig *util.ClusterHealthCheckConfig) (*ClusterController, error) {
+// newClusterController returns a new cluster controller that watches
+// KubeFedClusters with the informer of the given manager.
+func newClusterController(config *util.ControllerConfig, mgr manager.Manager, clusterHealthCheckConfig *util.ClusterHealthCheckConfig) (*ClusterController, error) {
 	kubeConfig := restclient.CopyConfig(config.KubeConfig)
 	kubeConfig.Timeout = time.Duration(clusterHealthCheckConfig.TimeoutSeconds) * time.Second
 	client := genericclient.NewForConfigOrDieWithUserAgent(kubeConfig, "cluster-controller")
 
 	cc := &ClusterController{
 		client:                   client,
+		hostConfig:               config.KubeConfig,
 		clusterHealthCheckConfig: clusterHealthCheckConfig,
 		clusterDataMap:           make(map[string]*ClusterData),
 		fedNamespace:             config.KubeFedNamespace,
+		notifier:                 config.Notifier,
+		circuitBreaker:           config.CircuitBreaker,
+		apiDiscovery:             config.APIDiscovery,
+		lifecycleHooks:           newLifecycleHooks(client, config.KubeFedNamespace, config.ClusterLifecycleHooks),
 	}
 	var err error
-	_, cc.clusterController, err = util.NewGenericInformerWithEventHandler(
-		config.KubeConfig,
+	cc.clusterStore, cc.clusterController, err = util.NewManagerInformerWithEventHandler(
+		mgr,
 		config.KubeFedNamespace,
 		&fedv1b1.KubeFedCluster{},
-		util.NoResyncPeriod,
 		&cache.ResourceEventHandlerFuncs{
-			DeleteFunc: cc.delFromClusterSet,
-			AddFunc:    cc.addToClusterSet,
+			DeleteFunc: func(obj interface{}) {
+				cc.delFromClusterSet(obj)
+				cc.apiDiscovery.Remove(obj.(*fedv1b1.KubeFedCluster).Name)
+				util.ForgetClusterRequestObservations(obj.(*fedv1b1.KubeFedCluster).Name)
+			},
+			AddFunc: cc.addToClusterSet,
 			UpdateFunc: func(oldObj, newObj interface{}) {
 				cc.delFromClusterSet(oldObj)
 				cc.addToClusterSet(newObj)
@@ -131,7 +232,7 @@ func (cc *ClusterController) addToClusterSet(obj interface{}) {
 	klog.V(1).Infof("ClusterController observed a new cluster: %v", cluster.Name)
 	// create the restclient of cluster
 	clientTimeout := time.Duration(cc.clusterHealthCheckConfig.TimeoutSeconds) * time.Second
-	restClient, err := NewClusterClientSet(cluster, cc.client, cc.fedNamespace, clientTimeout)
+	restClient, err := NewClusterClientSet(cluster, cc.hostConfig, cc.client, cc.fedNamespace, clientTimeout)
 	if err != nil || restClient == nil {
 		klog.Errorf("Failed to create corresponding restclient of kubernetes cluster: %v", err)
 		return
@@ -142,29 +243,61 @@ func (cc *ClusterController) addToClusterSet(obj interface{}) {
 // Run begins watching and syncing.
 func (cc *ClusterController) Run(stopChan <-chan struct{}) {
 	defer utilruntime.HandleCrash()
-	go cc.clusterController.Run(stopChan)
+	cc.setLastMonitored()
 	// monitor cluster status periodically, in phase 1 we just get the health state from "/healthz"
 	go wait.Until(func() {
-		if err := cc.updateClusterStatus(); err != nil {
-			klog.Errorf("Error monitoring cluster status: %v", err)
+		// The informer is synced once the manager has been started.
+		if !cc.clusterController.HasSynced() {
+			return
 		}
+		cc.updateClusterStatus()
+		cc.setLastMonitored()
 	}, time.Duration(cc.clusterHealthCheckConfig.PeriodSeconds)*time.Second, stopChan)
 }
 
-// updateClusterStatus checks cluster health and updates status of all KubeFedClusters
-func (cc *ClusterController) updateClusterStatus() error {
-	clusters := &fedv1b1.KubeFedClusterList{}
-	err := cc.client.List(context.TODO(), clusters, cc.fedNamespace)
-	if err != nil {
-		return err
+func (cc *ClusterController) setLastMonitored() {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	cc.lastMonitored = time.Now()
+}
+
+// healthCheck returns an error if the controller has not synced its
+// informer or has not updated the status of the clusters for several
+// health check periods, indicating that it has lost its connection to
+// the API of the host cluster or is wedged.
+func (cc *ClusterController) healthCheck() error {
+	if !cc.clusterController.HasSynced() {
+		return errors.New("KubeFedCluster informer has not synced")
+	}
+	cc.mu.RLock()
+	sinceMonitored := time.Since(cc.lastMonitored)
+	cc.mu.RUnlock()
+	// Allow for a couple of failed updates and for the requests to
+	// member clusters made by an update taking up to the timeout.
+	config := cc.clusterHealthCheckConfig
+	maxInterval := time.Duration(3*(config.PeriodSeconds+config.TimeoutSeconds)) * time.Second
+	if sinceMonitored > maxInterval {
+		return errors.Errorf("cluster status has not been updated for %v", sinceMonitored.Round(time.Second))
 	}
+	return nil
+}
 
+// updateClusterStatus checks cluster health and updates status of all KubeFedClusters
+func (cc *ClusterController) updateClusterStatus() {
 	var wg sync.WaitGroup
-	for _, obj := range clusters.Items {
+	for _, obj := range cc.clusterStore.List() {
 		cc.mu.RLock()
-		cluster := obj.DeepCopy()
+		cluster := obj.(*fedv1b1.KubeFedCluster).DeepCopy()
 		clusterData := cc.clusterDataMap[cluster.Name]
 		cc.mu.RUnlock()
+		if cluster.DeletionTimestamp != nil {
+			// A cluster being unjoined is retained by a finalizer
+			// until its Unjoined hooks have completed, and may no
+			// longer be accessible.
+			wg.Add(1)
+			go cc.updateUnjoiningClusterStatus(cluster, &wg)
+			continue
+		}
 		if clusterData == nil {
 			// Retry adding cluster client
 			cc.addToClusterSet(cluster)
@@ -180,26 +313,149 @@ func (cc *ClusterController) updateClusterStatus() error {
 	}
 
 	wg.Wait()
-	return nil
 }
 
 func (cc *ClusterController) updateIndividualClusterStatus(cluster *fedv1b1.KubeFedCluster,
 	storedData *ClusterData, wg *sync.WaitGroup) {
+	var currentClusterStatus *fedv1b1.KubeFedClusterStatus
+	if util.ClusterApprovalRequired() && !util.IsClusterApproved(&cluster.Status) {
+		// A cluster pending approval is not contacted.
+		currentClusterStatus = pendingApprovalStatus(cluster)
+	} else {
+		currentClusterStatus = cc.checkClusterStatus(cluster, storedData)
+	}
+	// The approval of the cluster is set by administrators rather than
+	// determined by the health check.
+	setApprovalCondition(currentClusterStatus, util.ClusterApprovalCondition(&cluster.Status))
+	setDeletionsPausedCondition(currentClusterStatus, &cluster.Status, cluster.Annotations, metav1.Now())
+	cc.lifecycleHooks.update(cluster, currentClusterStatus)
+
+	cc.notifyReadinessTransition(cluster.Name, storedData.clusterStatus, currentClusterStatus)
+
+	// The stored status is read by the diagnostic dump.
+	cc.mu.Lock()
+	storedData.clusterStatus = currentClusterStatus
+	cc.mu.Unlock()
+	cc.updateStatusAndFinalizer(cluster, currentClusterStatus)
+	wg.Done()
+}
+
+// updateUnjoiningClusterStatus runs the Unjoined hooks of a cluster
+// that is being deleted and updates its status, allowing the deletion
+// to complete once the hooks have.
+func (cc *ClusterController) updateUnjoiningClusterStatus(cluster *fedv1b1.KubeFedCluster, wg *sync.WaitGroup) {
+	defer wg.Done()
+	currentClusterStatus := unjoiningStatus(cluster)
+	cc.lifecycleHooks.update(cluster, currentClusterStatus)
+	cc.updateStatusAndFinalizer(cluster, currentClusterStatus)
+}
+
+func (cc *ClusterController) updateStatusAndFinalizer(cluster *fedv1b1.KubeFedCluster, clusterStatus *fedv1b1.KubeFedClusterStatus) {
+	cluster.Status = *clusterStatus
+	if err := cc.client.UpdateStatus(context.TODO(), cluster); err != nil {
+		klog.Warningf("Failed to update the status of cluster %q: %v", cluster.Name, err)
+		return
+	}
+	if err := cc.lifecycleHooks.updateFinalizer(cluster); err != nil {
+		klog.Warningf("Failed to update the finalizers of cluster %q: %v", cluster.Name, err)
+	}
+}
+
+// checkClusterStatus determines the status of the cluster from its
+// health check.
+func (cc *ClusterController) checkClusterStatus(cluster *fedv1b1.KubeFedCluster, storedData *ClusterData) *fedv1b1.KubeFedClusterStatus {
 	clusterClient := storedData.clusterKubeClient
 
+	now := metav1.Now()
 	currentClusterStatus := clusterClient.GetClusterHealthStatus()
+	// The api health is only determined for a cluster that responded.
+	reachable := currentClusterStatus.APIHealth != nil
+	recordProbeResult(storedData, util.IsClusterReady(currentClusterStatus), now.Time)
+	apiHealth := averagedAPIHealth(cluster.Status.APIHealth, currentClusterStatus.APIHealth)
+	// The failure of the health check is reported even if the
+	// readiness of the cluster is left unchanged by the thresholds.
+	healthCheckFailure := countHealthCheckFailure(currentClusterStatus.HealthCheckFailure, cluster.Status.HealthCheckFailure, now)
 	currentClusterStatus = thresholdAdjustedClusterStatus(currentClusterStatus, storedData, cc.clusterHealthCheckConfig)
+	currentClusterStatus.APIHealth = apiHealth
+	currentClusterStatus.HealthCheckFailure = healthCheckFailure
 
-	if utilfeature.DefaultFeatureGate.Enabled(features.CrossClusterServiceDiscovery) {
-		currentClusterStatus = updateClusterZonesAndRegion(currentClusterStatus, cluster, clusterClient)
+	currentClusterStatus = updateClusterZonesAndRegion(currentClusterStatus, cluster, clusterClient, storedData)
+	if utilfeature.DefaultFeatureGate.Enabled(features.ClusterPressure) {
+		currentClusterStatus = updateClusterPressure(currentClusterStatus, cluster, clusterClient)
+	}
+	currentClusterStatus = updateClusterVersionAndProvider(currentClusterStatus, cluster, clusterClient)
+	// The API resources are refreshed before the status is updated so
+	// that controllers observing a change of the kubernetes version
+	// negotiate target versions against the resources of the upgraded
+	// cluster.
+	cc.refreshAPIDiscovery(cluster, currentClusterStatus, clusterClient)
+	// The health probes of a cluster are only meaningful while it is
+	// ready.
+	var probeFailures []string
+	if util.IsClusterReady(currentClusterStatus) && len(cluster.Spec.HealthProbes) > 0 {
+		probeFailures = clusterClient.RunHealthProbes(cluster.Spec.HealthProbes)
 	}
 
-	storedData.clusterStatus = currentClusterStatus
-	cluster.Status = *currentClusterStatus
-	if err := cc.client.UpdateStatus(context.TODO(), cluster); err != nil {
-		klog.Warningf("Failed to update the status of cluster %q: %v", cluster.Name, err)
+	setReachableCondition(currentClusterStatus, &cluster.Status, reachable, now)
+	setDegradedCondition(currentClusterStatus, &cluster.Status, cc.circuitBreaker.OpenState(cluster.Name), storedData.flappingSince, probeFailures)
+	observations := util.GetClusterRequestObservations(cluster.Name)
+	setRateLimitedCondition(currentClusterStatus, &cluster.Status, observations, now)
+	setClockSkewCondition(currentClusterStatus, &cluster.Status, observations, now)
+	return currentClusterStatus
+}
+
+// dumpClusters writes the api endpoints and the most recent health
+// check result of each cluster to a diagnostic dump.
+func (cc *ClusterController) dumpClusters(w io.Writer) {
+	cc.mu.RLock()
+	defer cc.mu.RUnlock()
+	names := make([]string, 0, len(cc.clusterDataMap))
+	for name := range cc.clusterDataMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		data := cc.clusterDataMap[name]
+		fmt.Fprintf(w, "%s: endpoints=%s", name, strings.Join(data.clusterKubeClient.apiEndpoints, ","))
+		status := data.clusterStatus
+		if status == nil {
+			fmt.Fprintln(w, " not yet checked")
+			continue
+		}
+		fmt.Fprintf(w, " ready=%t", util.IsClusterReady(status))
+		if resources, ok := cc.apiDiscovery.Cluster(name); ok {
+			fmt.Fprintf(w, " group_versions=%d discovered=%s", len(resources.GroupVersions()), resources.Refreshed.UTC().Format(time.RFC3339))
+		}
+		if health := status.APIHealth; health != nil {
+			fmt.Fprintf(w, " latency=%dms average_latency=%dms error_rate=%d%%",
+				health.LatencyMilliseconds, health.AverageLatencyMilliseconds, health.ErrorRatePercent)
+		}
+		if failure := status.HealthCheckFailure; failure != nil {
+			fmt.Fprintf(w, " failure=%s failed_endpoint=%s consecutive_failures=%d",
+				failure.Reason, failure.APIEndpoint, failure.ConsecutiveFailures)
+		}
+		fmt.Fprintln(w)
 	}
-	wg.Done()
+}
+
+// notifyReadinessTransition sends a notification if the readiness of
+// the cluster differs from the previously observed status.
+func (cc *ClusterController) notifyReadinessTransition(clusterName string, oldStatus, newStatus *fedv1b1.KubeFedClusterStatus) {
+	if oldStatus == nil || clusterStatusEqual(newStatus, oldStatus) {
+		return
+	}
+	notification := &notifier.Notification{
+		Type:    fedv1b1.ClusterNotReadyNotification,
+		Cluster: clusterName,
+		Message: fmt.Sprintf("Cluster %q is not ready", clusterName),
+	}
+	if util.IsClusterReady(newStatus) {
+		notification.Type = fedv1b1.ClusterReadyNotification
+		notification.Message = fmt.Sprintf("Cluster %q is ready", clusterName)
+	} else if failure := newStatus.HealthCheckFailure; failure != nil {
+		notification.Message = fmt.Sprintf("%s: %s: %s", notification.Message, failure.Reason, failure.Message)
+	}
+	cc.notifier.Notify(notification)
 }
 
 func thresholdAdjustedClusterStatus(clusterStatus *fedv1b1.KubeFedClusterStatus, storedData *ClusterData,
@@ -218,8 +474,14 @@ func thresholdAdjustedClusterStatus(clusterStatus *fedv1b1.KubeFedClusterStatus,
 	if storedData.resultRun < threshold {
 		// Success/Failure is below threshold - leave the probe state unchanged.
 		probeTime := clusterStatus.Conditions[0].LastProbeTime
+		apiEndpoint := clusterStatus.APIEndpoint
 		clusterStatus = storedData.clusterStatus
 		setProbeTime(clusterStatus, probeTime)
+		// The endpoint that was reachable may have changed even though
+		// the readiness has not.
+		if len(apiEndpoint) > 0 {
+			clusterStatus.APIEndpoint = apiEndpoint
+		}
 	} else {
 		if clusterStatusEqual(clusterStatus, storedData.clusterStatus) {
 			// preserve the last transition time
@@ -238,10 +500,76 @@ func thresholdAdjustedClusterStatus(clusterStatus *fedv1b1.KubeFedClusterStatus,
 	return clusterStatus
 }
 
+// countHealthCheckFailure returns the failure of the most recent
+// health check, if any, counted as a consecutive failure of the
+// failure of the previous status.
+func countHealthCheckFailure(failure, previous *fedv1b1.ClusterHealthCheckFailure, now metav1.Time) *fedv1b1.ClusterHealthCheckFailure {
+	if failure == nil {
+		return nil
+	}
+	failure = failure.DeepCopy()
+	failure.ConsecutiveFailures = 1
+	failure.Since = now
+	if previous != nil {
+		failure.ConsecutiveFailures = previous.ConsecutiveFailures + 1
+		failure.Since = previous.Since
+	}
+	return failure
+}
+
+// averagedAPIHealth folds the result of a health check into the
+// moving averages of the previous api health.  A nil probe result
+// indicates that the API server could not be reached.
+func averagedAPIHealth(previous, probe *fedv1b1.ClusterAPIHealth) *fedv1b1.ClusterAPIHealth {
+	if previous == nil {
+		if probe == nil {
+			return &fedv1b1.ClusterAPIHealth{ErrorRatePercent: 100}
+		}
+		return &fedv1b1.ClusterAPIHealth{
+			LatencyMilliseconds:        probe.LatencyMilliseconds,
+			AverageLatencyMilliseconds: probe.LatencyMilliseconds,
+		}
+	}
+	apiHealth := previous.DeepCopy()
+	errorPercent := int64(100)
+	if probe != nil {
+		errorPercent = 0
+		apiHealth.LatencyMilliseconds = probe.LatencyMilliseconds
+		apiHealth.AverageLatencyMilliseconds = movingAverage(previous.AverageLatencyMilliseconds, probe.LatencyMilliseconds)
+	}
+	apiHealth.ErrorRatePercent = int32(movingAverage(int64(previous.ErrorRatePercent), errorPercent))
+	return apiHealth
+}
+
+// movingAverage returns an exponentially weighted moving average that
+// gives the sample a weight of apiHealthSampleWeight.  The average
+// always moves at least one unit towards a differing sample so that
+// it converges on a steady value.
+func movingAverage(average, sample int64) int64 {
+	next := int64(math.Round(float64(average)*(1-apiHealthSampleWeight) + float64(sample)*apiHealthSampleWeight))
+	switch {
+	case next == average && sample > average:
+		next++
+	case next == average && sample < average:
+		next--
+	}
+	return next
+}
+
+// updateClusterZonesAndRegion records the zones and region of the
+// cluster in its status.  They are determined from the topology labels
+// of the nodes of a ready cluster at most once per refresh period, and
+// the last known values are otherwise preserved.  The topology labels
+// of the KubeFedCluster take precedence over those of its nodes.
 func updateClusterZonesAndRegion(clusterStatus *fedv1b1.KubeFedClusterStatus, cluster *fedv1b1.KubeFedCluster,
-	clusterClient *ClusterClient) *fedv1b1.KubeFedClusterStatus {
+	clusterClient *ClusterClient, storedData *ClusterData) *fedv1b1.KubeFedClusterStatus {
 
-	if !util.IsClusterReady(clusterStatus) {
+	clusterStatus.Zones = cluster.Status.Zones
+	clusterStatus.Region = cluster.Status.Region
+	defer setConfiguredZoneAndRegion(clusterStatus, cluster)
+
+	refreshDue := time.Since(storedData.topologyRefreshed) >= topologyRefreshPeriod
+	if !util.IsClusterReady(clusterStatus) || !refreshDue {
 		return clusterStatus
 	}
 
@@ -250,20 +578,341 @@ func updateClusterZonesAndRegion(clusterStatus *fedv1b1.KubeFedClusterStatus, cl
 		klog.Warningf("Failed to get zones and region for cluster %q: %v", clusterClient.clusterName, err)
 		return clusterStatus
 	}
+	storedData.topologyRefreshed = time.Now()
 
 	// If new zone & region are empty, preserve the old ones so that user configured zone & region
 	// labels are effective
-	if len(zones) == 0 {
-		zones = cluster.Status.Zones
+	if len(zones) > 0 {
+		clusterStatus.Zones = zones
+	}
+	if len(region) > 0 {
+		clusterStatus.Region = region
+	}
+	return clusterStatus
+}
+
+// setConfiguredZoneAndRegion sets the zone and region of the cluster
+// status to the values of the topology labels of the KubeFedCluster,
+// if any.
+func setConfiguredZoneAndRegion(clusterStatus *fedv1b1.KubeFedClusterStatus, cluster *fedv1b1.KubeFedCluster) {
+	if zone := cluster.Labels[LabelTopologyZone]; len(zone) > 0 {
+		clusterStatus.Zones = []string{zone}
+	}
+	if region := cluster.Labels[LabelTopologyRegion]; len(region) > 0 {
+		clusterStatus.Region = region
+	}
+}
+
+// updateClusterPressure records the pressure of the cluster in its
+// status.  The last known pressure is preserved while it cannot be
+// determined.
+func updateClusterPressure(clusterStatus *fedv1b1.KubeFedClusterStatus, cluster *fedv1b1.KubeFedCluster,
+	clusterClient *ClusterClient) *fedv1b1.KubeFedClusterStatus {
+
+	clusterStatus.Pressure = cluster.Status.Pressure
+	if !util.IsClusterReady(clusterStatus) {
+		return clusterStatus
+	}
+
+	pressure, err := clusterClient.GetClusterPressure()
+	if err != nil {
+		klog.Warningf("Failed to get the pressure of cluster %q: %v", clusterClient.clusterName, err)
+		return clusterStatus
+	}
+	clusterStatus.Pressure = pressure
+	return clusterStatus
+}
+
+func updateClusterVersionAndProvider(clusterStatus *fedv1b1.KubeFedClusterStatus, cluster *fedv1b1.KubeFedCluster,
+	clusterClient *ClusterClient) *fedv1b1.KubeFedClusterStatus {
+
+	// Preserve the last known values while they cannot be determined.
+	clusterStatus.KubernetesVersion = cluster.Status.KubernetesVersion
+	clusterStatus.Provider = cluster.Status.Provider
+	if !util.IsClusterReady(clusterStatus) {
+		return clusterStatus
 	}
-	if len(region) == 0 {
-		region = cluster.Status.Region
+
+	version, provider, err := clusterClient.GetClusterVersionAndProvider()
+	if err != nil {
+		klog.Warningf("Failed to get kubernetes version and provider for cluster %q: %v", clusterClient.clusterName, err)
+		return clusterStatus
+	}
+	if len(version) > 0 {
+		clusterStatus.KubernetesVersion = version
+	}
+	if len(provider) > 0 {
+		clusterStatus.Provider = provider
 	}
-	clusterStatus.Zones = zones
-	clusterStatus.Region = region
 	return clusterStatus
 }
 
+// refreshAPIDiscovery discovers the API resources of a ready cluster if
+// they have not been discovered, were discovered more than the refresh
+// period ago or may have changed with the kubernetes version of the
+// cluster.
+func (cc *ClusterController) refreshAPIDiscovery(cluster *fedv1b1.KubeFedCluster, clusterStatus *fedv1b1.KubeFedClusterStatus,
+	clusterClient *ClusterClient) {
+
+	if cc.apiDiscovery == nil || !util.IsClusterReady(clusterStatus) {
+		return
+	}
+	versionChanged := clusterStatus.KubernetesVersion != cluster.Status.KubernetesVersion
+	resources, ok := cc.apiDiscovery.Cluster(cluster.Name)
+	if ok && !versionChanged && time.Since(resources.Refreshed) < apiDiscoveryRefreshPeriod {
+		return
+	}
+	resourceLists, failed, err := clusterClient.GetServerResources()
+	if err != nil {
+		klog.Warningf("Failed to discover the API resources of cluster %q: %v", cluster.Name, err)
+		if versionChanged {
+			// Resources discovered before an upgrade are not used to
+			// negotiate versions after it.
+			cc.apiDiscovery.Remove(cluster.Name)
+		}
+		return
+	}
+	for groupVersion, err := range failed {
+		klog.V(2).Infof("Failed to discover the API resources of %q in cluster %q: %v", groupVersion, cluster.Name, err)
+	}
+	cc.apiDiscovery.Update(cluster.Name, resourceLists, failed)
+}
+
+// recordProbeResult records the result of a probe of the cluster and
+// determines whether the probe is flapping.
+func recordProbeResult(storedData *ClusterData, healthy bool, now time.Time) {
+	storedData.probeResults = append(storedData.probeResults, healthy)
+	if len(storedData.probeResults) > flappingWindow {
+		storedData.probeResults = storedData.probeResults[len(storedData.probeResults)-flappingWindow:]
+	}
+	transitions := 0
+	for i := 1; i < len(storedData.probeResults); i++ {
+		if storedData.probeResults[i] != storedData.probeResults[i-1] {
+			transitions++
+		}
+	}
+	switch {
+	case transitions < flappingTransitions:
+		storedData.flappingSince = time.Time{}
+	case storedData.flappingSince.IsZero():
+		storedData.flappingSince = now
+	}
+}
+
+// setDegradedCondition sets a Degraded condition in the cluster status
+// if dispatch to the cluster is backed off, its health check is
+// flapping (indicated by a non-zero flappingSince) or any of its
+// health probes failed, and otherwise removes it.  The reason of the
+// condition is that of the first of these, and its message describes
+// all of them.
+func setDegradedCondition(clusterStatus, previousStatus *fedv1b1.KubeFedClusterStatus, state *circuitbreaker.State,
+	flappingSince time.Time, probeFailures []string) {
+
+	flappingMessage := fmt.Sprintf("the result of the health check changed at least %d times in the last %d checks", flappingTransitions, flappingWindow)
+	probeMessage := strings.Join(probeFailures, "; ")
+	var condition *fedv1b1.ClusterCondition
+	switch {
+	case state != nil:
+		message := "dispatch of resources to the cluster is backed off since requests to the cluster are failing"
+		if state.LastError != nil {
+			message = fmt.Sprintf("%s: %v", message, state.LastError)
+		}
+		if !flappingSince.IsZero() {
+			message = fmt.Sprintf("%s; %s", message, flappingMessage)
+		}
+		if len(probeFailures) > 0 {
+			message = fmt.Sprintf("%s; %s", message, probeMessage)
+		}
+		condition = &fedv1b1.ClusterCondition{
+			Type:               common.ClusterDegraded,
+			Status:             corev1.ConditionTrue,
+			Reason:             "DispatchBackedOff",
+			Message:            message,
+			LastProbeTime:      metav1.Now(),
+			LastTransitionTime: metav1.NewTime(state.Since),
+		}
+	case !flappingSince.IsZero():
+		message := flappingMessage
+		if len(probeFailures) > 0 {
+			message = fmt.Sprintf("%s; %s", message, probeMessage)
+		}
+		condition = &fedv1b1.ClusterCondition{
+			Type:               common.ClusterDegraded,
+			Status:             corev1.ConditionTrue,
+			Reason:             "HealthCheckFlapping",
+			Message:            message,
+			LastProbeTime:      metav1.Now(),
+			LastTransitionTime: metav1.NewTime(flappingSince),
+		}
+	case len(probeFailures) > 0:
+		now := metav1.Now()
+		condition = &fedv1b1.ClusterCondition{
+			Type:               common.ClusterDegraded,
+			Status:             corev1.ConditionTrue,
+			Reason:             "HealthProbeFailed",
+			Message:            probeMessage,
+			LastProbeTime:      now,
+			LastTransitionTime: transitionTime(previousStatus, common.ClusterDegraded, corev1.ConditionTrue, now),
+		}
+	}
+	replaceClusterCondition(clusterStatus, common.ClusterDegraded, condition)
+}
+
+// setReachableCondition sets the Reachable condition of the cluster
+// status to indicate whether the API server of the cluster responded
+// to the most recent health check.  The transition time of the
+// condition in the previous status is retained if its status is
+// unchanged.
+func setReachableCondition(clusterStatus, previousStatus *fedv1b1.KubeFedClusterStatus, reachable bool, now metav1.Time) {
+	condition := &fedv1b1.ClusterCondition{
+		Type:          common.ClusterReachable,
+		Status:        corev1.ConditionTrue,
+		Reason:        "ClusterReachable",
+		Message:       "the API server of the cluster responded to the health check",
+		LastProbeTime: now,
+	}
+	if !reachable {
+		condition.Status = corev1.ConditionFalse
+		condition.Reason = "ClusterNotReachable"
+		condition.Message = "the API server of the cluster did not respond to the health check"
+	}
+	condition.LastTransitionTime = transitionTime(previousStatus, common.ClusterReachable, condition.Status, now)
+	replaceClusterCondition(clusterStatus, common.ClusterReachable, condition)
+}
+
+// setRateLimitedCondition sets a RateLimited condition in the cluster
+// status if requests to the cluster were throttled within the
+// observation period, and otherwise removes it.
+func setRateLimitedCondition(clusterStatus, previousStatus *fedv1b1.KubeFedClusterStatus,
+	observations util.ClusterRequestObservations, now metav1.Time) {
+
+	var condition *fedv1b1.ClusterCondition
+	if !observations.LastThrottled.IsZero() && now.Sub(observations.LastThrottled) < requestObservationPeriod {
+		condition = &fedv1b1.ClusterCondition{
+			Type:   common.ClusterRateLimited,
+			Status: corev1.ConditionTrue,
+			Reason: "RequestsThrottled",
+			Message: fmt.Sprintf("%d requests have been throttled by the API server of the cluster, most recently at %s",
+				observations.ThrottledRequests, observations.LastThrottled.UTC().Format(time.RFC3339)),
+			LastProbeTime:      now,
+			LastTransitionTime: transitionTime(previousStatus, common.ClusterRateLimited, corev1.ConditionTrue, now),
+		}
+	}
+	replaceClusterCondition(clusterStatus, common.ClusterRateLimited, condition)
+}
+
+// setClockSkewCondition sets a ClockSkew condition in the cluster
+// status if the clock skew of the cluster most recently observed
+// within the observation period exceeds the threshold, and otherwise
+// removes it.
+func setClockSkewCondition(clusterStatus, previousStatus *fedv1b1.KubeFedClusterStatus,
+	observations util.ClusterRequestObservations, now metav1.Time) {
+
+	var condition *fedv1b1.ClusterCondition
+	skew := observations.ClockSkew
+	recent := !observations.ClockSkewObserved.IsZero() && now.Sub(observations.ClockSkewObserved) < requestObservationPeriod
+	if recent && (skew > clockSkewThreshold || skew < -clockSkewThreshold) {
+		direction := "ahead of"
+		if skew < 0 {
+			direction = "behind"
+			skew = -skew
+		}
+		condition = &fedv1b1.ClusterCondition{
+			Type:               common.ClusterClockSkew,
+			Status:             corev1.ConditionTrue,
+			Reason:             "ClockSkewDetected",
+			Message:            fmt.Sprintf("the clock of the API server of the cluster is %v %s the clock of the controller manager", skew.Round(time.Second), direction),
+			LastProbeTime:      now,
+			LastTransitionTime: transitionTime(previousStatus, common.ClusterClockSkew, corev1.ConditionTrue, now),
+		}
+	}
+	replaceClusterCondition(clusterStatus, common.ClusterClockSkew, condition)
+}
+
+// setDeletionsPausedCondition adds a DeletionsPaused condition to the
+// cluster status if the sync controller has annotated the cluster to
+// pause deletions from it, and otherwise removes it.
+func setDeletionsPausedCondition(clusterStatus, previousStatus *fedv1b1.KubeFedClusterStatus,
+	annotations map[string]string, now metav1.Time) {
+
+	var condition *fedv1b1.ClusterCondition
+	if pausedAt, ok := annotations[deletionlimiter.PausedAnnotation]; ok {
+		condition = &fedv1b1.ClusterCondition{
+			Type:               common.ClusterDeletionsPaused,
+			Status:             corev1.ConditionTrue,
+			Reason:             "DeletionLimitExceeded",
+			Message:            fmt.Sprintf("deletions from the cluster were paused at %s for exceeding the deletion limit; remove the %q annotation to resume them", pausedAt, deletionlimiter.PausedAnnotation),
+			LastProbeTime:      now,
+			LastTransitionTime: transitionTime(previousStatus, common.ClusterDeletionsPaused, corev1.ConditionTrue, now),
+		}
+	}
+	replaceClusterCondition(clusterStatus, common.ClusterDeletionsPaused, condition)
+}
+
+// transitionTime returns the transition time of the condition of the
+// given type in the previous status if it has the given status, and
+// otherwise now.
+func transitionTime(previousStatus *fedv1b1.KubeFedClusterStatus, conditionType common.ClusterConditionType,
+	status corev1.ConditionStatus, now metav1.Time) metav1.Time {
+
+	for _, condition := range previousStatus.Conditions {
+		if condition.Type == conditionType && condition.Status == status {
+			return condition.LastTransitionTime
+		}
+	}
+	return now
+}
+
+// replaceClusterCondition replaces the condition of the given type in
+// the cluster status with the given condition, or removes it if the
+// condition is nil.  The condition is appended so that the readiness
+// condition remains the first condition.
+func replaceClusterCondition(clusterStatus *fedv1b1.KubeFedClusterStatus, conditionType common.ClusterConditionType, replacement *fedv1b1.ClusterCondition) {
+	conditions := make([]fedv1b1.ClusterCondition, 0, len(clusterStatus.Conditions)+1)
+	for _, condition := range clusterStatus.Conditions {
+		if condition.Type != conditionType {
+			conditions = append(conditions, condition)
+		}
+	}
+	if replacement != nil {
+		conditions = append(conditions, *replacement)
+	}
+	clusterStatus.Conditions = conditions
+}
+
+// pendingApprovalStatus returns the status of a cluster that has not
+// been approved, which is not ready so that it is not used for
+// placement or propagation.  The details last determined by the health
+// check, if any, are retained.
+func pendingApprovalStatus(cluster *fedv1b1.KubeFedCluster) *fedv1b1.KubeFedClusterStatus {
+	clusterStatus := cluster.Status.DeepCopy()
+	currentTime := metav1.Now()
+	readyCondition := fedv1b1.ClusterCondition{
+		Type:               common.ClusterReady,
+		Status:             corev1.ConditionFalse,
+		Reason:             util.ClusterPendingApprovalReason,
+		Message:            "cluster is pending approval",
+		LastProbeTime:      currentTime,
+		LastTransitionTime: currentTime,
+	}
+	for _, condition := range cluster.Status.Conditions {
+		if condition.Type == common.ClusterReady && condition.Status == corev1.ConditionFalse {
+			readyCondition.LastTransitionTime = condition.LastTransitionTime
+		}
+	}
+	clusterStatus.Conditions = []fedv1b1.ClusterCondition{readyCondition}
+	clusterStatus.APIHealth = nil
+	clusterStatus.HealthCheckFailure = nil
+	return clusterStatus
+}
+
+// setApprovalCondition replaces the Approved condition in the cluster
+// status with the given condition, or removes it if the condition is
+// nil.
+func setApprovalCondition(clusterStatus *fedv1b1.KubeFedClusterStatus, approval *fedv1b1.ClusterCondition) {
+	replaceClusterCondition(clusterStatus, common.ClusterApproved, approval)
+}
+
 func clusterStatusEqual(newClusterStatus, oldClusterStatus *fedv1b1.KubeFedClusterStatus) bool {
 	return util.IsClusterReady(newClusterStatus) == util.IsClusterReady(oldClusterStatus)
 }
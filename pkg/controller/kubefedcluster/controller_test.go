@@ -17,16 +17,33 @@ limitations under the License.
 package kubefedcluster
 
 import (
+	"crypto/x509"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
 	"reflect"
+	"strings"
+	"syscall"
 	"testing"
 	"time"
 
+	"github.com/pkg/errors"
+
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	kubeclientset "k8s.io/client-go/kubernetes"
+	restclient "k8s.io/client-go/rest"
 
 	"sigs.k8s.io/kubefed/pkg/apis/core/common"
 	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
 	"sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/controller/util/circuitbreaker"
+	"sigs.k8s.io/kubefed/pkg/controller/util/deletionlimiter"
 )
 
 func TestThresholdCheckedClusterStatus(t *testing.T) {
@@ -104,6 +121,409 @@ func TestThresholdCheckedClusterStatus(t *testing.T) {
 
 }
 
+func TestAveragedAPIHealth(t *testing.T) {
+	testCases := map[string]struct {
+		previous *fedv1b1.ClusterAPIHealth
+		probe    *fedv1b1.ClusterAPIHealth
+		expected *fedv1b1.ClusterAPIHealth
+	}{
+		"FirstProbeSucceeded": {
+			probe:    &fedv1b1.ClusterAPIHealth{LatencyMilliseconds: 50},
+			expected: &fedv1b1.ClusterAPIHealth{LatencyMilliseconds: 50, AverageLatencyMilliseconds: 50},
+		},
+		"FirstProbeFailed": {
+			expected: &fedv1b1.ClusterAPIHealth{ErrorRatePercent: 100},
+		},
+		"ProbeSucceeded": {
+			previous: &fedv1b1.ClusterAPIHealth{LatencyMilliseconds: 50, AverageLatencyMilliseconds: 50, ErrorRatePercent: 50},
+			probe:    &fedv1b1.ClusterAPIHealth{LatencyMilliseconds: 100},
+			expected: &fedv1b1.ClusterAPIHealth{LatencyMilliseconds: 100, AverageLatencyMilliseconds: 60, ErrorRatePercent: 40},
+		},
+		"ProbeFailedRetainsLatency": {
+			previous: &fedv1b1.ClusterAPIHealth{LatencyMilliseconds: 50, AverageLatencyMilliseconds: 50},
+			expected: &fedv1b1.ClusterAPIHealth{LatencyMilliseconds: 50, AverageLatencyMilliseconds: 50, ErrorRatePercent: 20},
+		},
+		"ErrorRateConverges": {
+			previous: &fedv1b1.ClusterAPIHealth{ErrorRatePercent: 98},
+			expected: &fedv1b1.ClusterAPIHealth{ErrorRatePercent: 99},
+		},
+	}
+
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			apiHealth := averagedAPIHealth(tc.previous, tc.probe)
+			if !reflect.DeepEqual(tc.expected, apiHealth) {
+				t.Fatalf("Unexpected api health, expected: %v, got:%v", tc.expected, apiHealth)
+			}
+		})
+	}
+}
+
+// timeoutError is a net.Error that timed out, like the errors of
+// requests exceeding the timeout of their client.
+type timeoutError struct{}
+
+func (timeoutError) Error() string {
+	return "net/http: request canceled (Client.Timeout exceeded while awaiting headers)"
+}
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestHealthCheckFailureReason(t *testing.T) {
+	testCases := map[string]struct {
+		err      error
+		expected fedv1b1.ClusterHealthCheckFailureReason
+	}{
+		"Unauthorized": {
+			err:      apierrors.NewUnauthorized("Unauthorized"),
+			expected: fedv1b1.ClusterHealthCheckUnauthorized,
+		},
+		"Forbidden": {
+			err:      apierrors.NewForbidden(schema.GroupResource{}, "/healthz", errors.New("forbidden")),
+			expected: fedv1b1.ClusterHealthCheckForbidden,
+		},
+		"ServerError": {
+			err:      apierrors.NewInternalError(errors.New("etcd failed")),
+			expected: fedv1b1.ClusterHealthCheckServerError,
+		},
+		"ServerTimeout": {
+			err:      apierrors.NewTimeoutError("timed out", 0),
+			expected: fedv1b1.ClusterHealthCheckTimeout,
+		},
+		"ClientTimeout": {
+			err:      urlError(timeoutError{}),
+			expected: fedv1b1.ClusterHealthCheckTimeout,
+		},
+		"DNSError": {
+			err:      urlError(&net.OpError{Op: "dial", Err: &net.DNSError{Err: "no such host", Name: "cluster1"}}),
+			expected: fedv1b1.ClusterHealthCheckDNSError,
+		},
+		"ConnectionRefused": {
+			err:      urlError(&net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}),
+			expected: fedv1b1.ClusterHealthCheckConnectionRefused,
+		},
+		"UnknownAuthority": {
+			err:      urlError(x509.UnknownAuthorityError{}),
+			expected: fedv1b1.ClusterHealthCheckTLSError,
+		},
+		"UntypedTLSError": {
+			err:      urlError(errors.New("tls: failed to verify certificate: x509: certificate has expired")),
+			expected: fedv1b1.ClusterHealthCheckTLSError,
+		},
+		"WrappedError": {
+			err:      errors.Wrap(apierrors.NewUnauthorized("Unauthorized"), "endpoint \"https://cluster1:6443\""),
+			expected: fedv1b1.ClusterHealthCheckUnauthorized,
+		},
+		"Unknown": {
+			err:      urlError(errors.New("EOF")),
+			expected: fedv1b1.ClusterHealthCheckUnknownError,
+		},
+	}
+
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			reason := healthCheckFailureReason(tc.err)
+			if reason != tc.expected {
+				t.Fatalf("Unexpected reason, expected: %s, got: %s", tc.expected, reason)
+			}
+		})
+	}
+}
+
+func TestHealthCheckFailure(t *testing.T) {
+	client := &ClusterClient{apiEndpoints: []string{"https://cluster1:6443", "https://cluster1.internal:6443"}}
+	err := utilerrors.NewAggregate([]error{
+		errors.Wrap(urlError(timeoutError{}), "endpoint \"https://cluster1:6443\""),
+		errors.Wrap(apierrors.NewUnauthorized("Unauthorized"), "endpoint \"https://cluster1.internal:6443\""),
+	})
+	failure := client.healthCheckFailure(err)
+	if failure.Reason != fedv1b1.ClusterHealthCheckTimeout || failure.APIEndpoint != "https://cluster1:6443" {
+		t.Fatalf("Expected the failure to be classified by the primary endpoint, got %+v", failure)
+	}
+	if failure.Message != err.Error() {
+		t.Fatalf("Expected the message to include the error of each endpoint, got %q", failure.Message)
+	}
+}
+
+func urlError(err error) error {
+	return &url.Error{Op: "Get", URL: "https://cluster1:6443/healthz", Err: err}
+}
+
+func TestCountHealthCheckFailure(t *testing.T) {
+	now := metav1.Now()
+	since := metav1.NewTime(now.Add(-time.Minute))
+	failure := &fedv1b1.ClusterHealthCheckFailure{Reason: fedv1b1.ClusterHealthCheckTimeout}
+
+	if counted := countHealthCheckFailure(nil, failure, now); counted != nil {
+		t.Fatalf("Expected no failure after a successful health check, got %+v", counted)
+	}
+	counted := countHealthCheckFailure(failure, nil, now)
+	if counted.ConsecutiveFailures != 1 || !counted.Since.Equal(&now) {
+		t.Fatalf("Expected a first failure, got %+v", counted)
+	}
+	previous := &fedv1b1.ClusterHealthCheckFailure{Reason: fedv1b1.ClusterHealthCheckDNSError, ConsecutiveFailures: 3, Since: since}
+	counted = countHealthCheckFailure(failure, previous, now)
+	if counted.ConsecutiveFailures != 4 || !counted.Since.Equal(&since) || counted.Reason != fedv1b1.ClusterHealthCheckTimeout {
+		t.Fatalf("Expected a consecutive failure with the most recent reason, got %+v", counted)
+	}
+}
+
+func TestSetDegradedCondition(t *testing.T) {
+	since := metav1.Now()
+	status := clusterStatus(corev1.ConditionTrue, since, since)
+
+	setDegradedCondition(status, status, &circuitbreaker.State{Since: since.Time, LastError: errors.New("connection refused")}, time.Time{}, nil)
+	if len(status.Conditions) != 2 {
+		t.Fatalf("Expected 2 conditions, got %v", status.Conditions)
+	}
+	if status.Conditions[0].Type != common.ClusterReady {
+		t.Fatalf("Expected the ready condition to remain first, got %v", status.Conditions)
+	}
+	degraded := status.Conditions[1]
+	if degraded.Type != common.ClusterDegraded || degraded.Status != corev1.ConditionTrue || !degraded.LastTransitionTime.Equal(&since) {
+		t.Fatalf("Unexpected degraded condition: %v", degraded)
+	}
+
+	// The condition is replaced rather than duplicated.
+	setDegradedCondition(status, status, &circuitbreaker.State{Since: since.Time}, time.Time{}, nil)
+	if len(status.Conditions) != 2 {
+		t.Fatalf("Expected 2 conditions, got %v", status.Conditions)
+	}
+
+	setDegradedCondition(status, status, nil, time.Time{}, nil)
+	if len(status.Conditions) != 1 || status.Conditions[0].Type != common.ClusterReady {
+		t.Fatalf("Expected the degraded condition to be removed, got %v", status.Conditions)
+	}
+}
+
+func TestFlappingHealthCheck(t *testing.T) {
+	storedData := &ClusterData{}
+	start := time.Now()
+	results := []bool{true, false, true, false, true}
+	for i, healthy := range results {
+		recordProbeResult(storedData, healthy, start.Add(time.Duration(i)*time.Second))
+	}
+	if !storedData.flappingSince.Equal(start.Add(4 * time.Second)) {
+		t.Fatalf("Expected the health check to be flapping since the 4th transition, got %v", storedData.flappingSince)
+	}
+
+	since := metav1.Now()
+	status := clusterStatus(corev1.ConditionTrue, since, since)
+	setDegradedCondition(status, status, nil, storedData.flappingSince, nil)
+	if len(status.Conditions) != 2 || status.Conditions[1].Reason != "HealthCheckFlapping" {
+		t.Fatalf("Expected a degraded condition for the flapping health check, got %v", status.Conditions)
+	}
+
+	// The health check stops flapping once the transitions leave the window.
+	for i := 0; i < flappingWindow-flappingTransitions+1; i++ {
+		recordProbeResult(storedData, true, time.Now())
+	}
+	if !storedData.flappingSince.IsZero() || len(storedData.probeResults) != flappingWindow {
+		t.Fatalf("Expected the health check to stop flapping, got %v for %v", storedData.flappingSince, storedData.probeResults)
+	}
+}
+
+func TestHealthProbeDegradedCondition(t *testing.T) {
+	since := metav1.NewTime(time.Now().Add(-time.Hour))
+	previousStatus := clusterStatus(corev1.ConditionTrue, since, since)
+	failures := []string{`health probe "frontend" failed: connection refused`}
+
+	status := clusterStatus(corev1.ConditionTrue, since, since)
+	setDegradedCondition(status, previousStatus, nil, time.Time{}, failures)
+	if len(status.Conditions) != 2 || status.Conditions[0].Status != corev1.ConditionTrue {
+		t.Fatalf("Expected a ready cluster with a degraded condition, got %v", status.Conditions)
+	}
+	degraded := status.Conditions[1]
+	if degraded.Reason != "HealthProbeFailed" || degraded.Message != failures[0] {
+		t.Fatalf("Unexpected degraded condition: %v", degraded)
+	}
+
+	// The transition time is retained while the probe keeps failing.
+	previousStatus = status
+	status = clusterStatus(corev1.ConditionTrue, since, since)
+	setDegradedCondition(status, previousStatus, nil, time.Time{}, failures)
+	if !status.Conditions[1].LastTransitionTime.Equal(&degraded.LastTransitionTime) {
+		t.Fatalf("Expected the transition time to be retained, got %v", status.Conditions[1])
+	}
+
+	// A flapping health check takes precedence as the reason.
+	setDegradedCondition(status, previousStatus, nil, since.Time, failures)
+	degraded = status.Conditions[1]
+	if degraded.Reason != "HealthCheckFlapping" || !strings.HasSuffix(degraded.Message, failures[0]) {
+		t.Fatalf("Expected the flapping condition to describe the failed probe, got %v", degraded)
+	}
+}
+
+func TestRunHealthProbes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/namespaces/canary/services/frontend:http/proxy/ready":
+			w.Write([]byte("ok"))
+		case "/apis/apps/v1/namespaces/canary/deployments/canary":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"apiVersion":"apps/v1","kind":"Deployment","spec":{"replicas":2},"status":{"availableReplicas":1}}`))
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	client := &ClusterClient{
+		kubeClient:  kubeclientset.NewForConfigOrDie(&restclient.Config{Host: server.URL}),
+		clusterName: "cluster1",
+	}
+	failures := client.RunHealthProbes([]fedv1b1.ClusterHealthProbe{
+		{Name: "frontend", Service: &fedv1b1.ClusterServiceProbe{Namespace: "canary", Name: "frontend", Port: "http", Path: "/ready"}},
+		{Name: "backend", Service: &fedv1b1.ClusterServiceProbe{Namespace: "canary", Name: "backend"}},
+		{Name: "canary", Deployment: &fedv1b1.ClusterDeploymentProbe{Namespace: "canary", Name: "canary"}},
+	})
+	if len(failures) != 2 {
+		t.Fatalf("Expected the backend and canary probes to fail, got %v", failures)
+	}
+	if !strings.HasPrefix(failures[0], `health probe "backend" failed: `) {
+		t.Errorf("Unexpected failure of the service probe: %s", failures[0])
+	}
+	if expected := `health probe "canary" failed: 1 of 2 replicas of Deployment canary/canary are available`; failures[1] != expected {
+		t.Errorf("Expected %q, got %q", expected, failures[1])
+	}
+}
+
+func TestSetReachableCondition(t *testing.T) {
+	since := metav1.NewTime(time.Now().Add(-time.Hour))
+	now := metav1.Now()
+	previous := clusterStatus(corev1.ConditionFalse, since, since)
+	setReachableCondition(previous, &fedv1b1.KubeFedClusterStatus{}, true, since)
+
+	// A cluster that responds without being healthy is reachable but not ready.
+	status := clusterStatus(corev1.ConditionFalse, now, now)
+	setReachableCondition(status, previous, true, now)
+	if len(status.Conditions) != 2 || util.IsClusterReady(status) {
+		t.Fatalf("Unexpected conditions: %v", status.Conditions)
+	}
+	reachable := status.Conditions[1]
+	if reachable.Type != common.ClusterReachable || reachable.Status != corev1.ConditionTrue || !reachable.LastTransitionTime.Equal(&since) {
+		t.Fatalf("Expected the reachable condition to retain its transition time, got %v", reachable)
+	}
+
+	setReachableCondition(status, previous, false, now)
+	reachable = status.Conditions[1]
+	if len(status.Conditions) != 2 || reachable.Status != corev1.ConditionFalse || !reachable.LastTransitionTime.Equal(&now) {
+		t.Fatalf("Expected the reachable condition to transition, got %v", status.Conditions)
+	}
+}
+
+func TestSetRateLimitedCondition(t *testing.T) {
+	now := metav1.Now()
+	status := clusterStatus(corev1.ConditionTrue, now, now)
+
+	setRateLimitedCondition(status, status, util.ClusterRequestObservations{ThrottledRequests: 3, LastThrottled: now.Add(-time.Minute)}, now)
+	if len(status.Conditions) != 2 || status.Conditions[1].Type != common.ClusterRateLimited {
+		t.Fatalf("Expected a rate limited condition, got %v", status.Conditions)
+	}
+
+	setRateLimitedCondition(status, status, util.ClusterRequestObservations{ThrottledRequests: 3, LastThrottled: now.Add(-time.Hour)}, now)
+	if len(status.Conditions) != 1 {
+		t.Fatalf("Expected the rate limited condition to be removed, got %v", status.Conditions)
+	}
+}
+
+func TestSetClockSkewCondition(t *testing.T) {
+	now := metav1.Now()
+	testCases := map[string]struct {
+		observations    util.ClusterRequestObservations
+		expectedMessage string
+	}{
+		"Clock ahead is skewed": {
+			observations:    util.ClusterRequestObservations{ClockSkew: time.Minute, ClockSkewObserved: now.Time},
+			expectedMessage: "the clock of the API server of the cluster is 1m0s ahead of the clock of the controller manager",
+		},
+		"Clock behind is skewed": {
+			observations:    util.ClusterRequestObservations{ClockSkew: -20 * time.Second, ClockSkewObserved: now.Time},
+			expectedMessage: "the clock of the API server of the cluster is 20s behind the clock of the controller manager",
+		},
+		"Skew below the threshold is ignored": {
+			observations: util.ClusterRequestObservations{ClockSkew: time.Second, ClockSkewObserved: now.Time},
+		},
+		"Stale skew is ignored": {
+			observations: util.ClusterRequestObservations{ClockSkew: time.Minute, ClockSkewObserved: now.Add(-time.Hour)},
+		},
+	}
+
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			status := clusterStatus(corev1.ConditionTrue, now, now)
+			setClockSkewCondition(status, status, tc.observations, now)
+			message := ""
+			if len(status.Conditions) == 2 && status.Conditions[1].Type == common.ClusterClockSkew {
+				message = status.Conditions[1].Message
+			}
+			if message != tc.expectedMessage {
+				t.Fatalf("Expected clock skew message %q, got conditions %v", tc.expectedMessage, status.Conditions)
+			}
+		})
+	}
+}
+
+func TestSetDeletionsPausedCondition(t *testing.T) {
+	now := metav1.Now()
+	status := clusterStatus(corev1.ConditionTrue, now, now)
+
+	annotations := map[string]string{deletionlimiter.PausedAnnotation: "2019-01-01T00:00:00Z"}
+	setDeletionsPausedCondition(status, status, annotations, now)
+	if len(status.Conditions) != 2 || status.Conditions[1].Type != common.ClusterDeletionsPaused {
+		t.Fatalf("Expected a deletions paused condition, got %v", status.Conditions)
+	}
+
+	setDeletionsPausedCondition(status, status, nil, now)
+	if len(status.Conditions) != 1 {
+		t.Fatalf("Expected the deletions paused condition to be removed, got %v", status.Conditions)
+	}
+}
+
+func TestPendingApprovalStatus(t *testing.T) {
+	since := metav1.NewTime(time.Now().Add(-time.Hour))
+	cluster := &fedv1b1.KubeFedCluster{Status: *clusterStatus(corev1.ConditionTrue, since, since)}
+	cluster.Status.KubernetesVersion = "v1.13.4"
+	util.SetClusterApproval(&cluster.Status, false, "RejectedByAdministrator", "")
+
+	status := pendingApprovalStatus(cluster)
+	setApprovalCondition(status, util.ClusterApprovalCondition(&cluster.Status))
+	if util.IsClusterReady(status) || util.IsClusterApproved(status) {
+		t.Fatalf("Expected a cluster pending approval to be neither ready nor approved, got %v", status.Conditions)
+	}
+	if len(status.Conditions) != 2 || status.Conditions[0].Reason != util.ClusterPendingApprovalReason || status.Conditions[0].LastTransitionTime.Equal(&since) {
+		t.Fatalf("Unexpected conditions: %v", status.Conditions)
+	}
+	if status.KubernetesVersion != "v1.13.4" {
+		t.Fatalf("Expected the kubernetes version to be retained, got %q", status.KubernetesVersion)
+	}
+
+	// The transition time is retained while the cluster remains pending.
+	cluster.Status = *status
+	pending := pendingApprovalStatus(cluster)
+	if !pending.Conditions[0].LastTransitionTime.Equal(&status.Conditions[0].LastTransitionTime) {
+		t.Fatalf("Expected the transition time to be retained, got %v", pending.Conditions[0])
+	}
+}
+
+func TestSetApprovalCondition(t *testing.T) {
+	since := metav1.Now()
+	status := clusterStatus(corev1.ConditionTrue, since, since)
+	approved := &fedv1b1.KubeFedClusterStatus{}
+	util.SetClusterApproval(approved, true, "ApprovedByAdministrator", "")
+
+	setApprovalCondition(status, util.ClusterApprovalCondition(approved))
+	setApprovalCondition(status, util.ClusterApprovalCondition(approved))
+	if len(status.Conditions) != 2 || !util.IsClusterApproved(status) {
+		t.Fatalf("Expected a single approved condition, got %v", status.Conditions)
+	}
+
+	setApprovalCondition(status, nil)
+	if len(status.Conditions) != 1 || status.Conditions[0].Type != common.ClusterReady {
+		t.Fatalf("Expected the approved condition to be removed, got %v", status.Conditions)
+	}
+}
+
 func clusterStatus(status corev1.ConditionStatus, lastProbeTime, lastTransitionTime metav1.Time) *fedv1b1.KubeFedClusterStatus {
 	return &fedv1b1.KubeFedClusterStatus{
 		Conditions: []fedv1b1.ClusterCondition{{
@@ -114,3 +534,205 @@ func clusterStatus(status corev1.ConditionStatus, lastProbeTime, lastTransitionT
 		}},
 	}
 }
+
+func TestGetProviderForNode(t *testing.T) {
+	testCases := map[string]struct {
+		labels     map[string]string
+		providerID string
+		expected   string
+	}{
+		"Managed service is identified by node label": {
+			labels:     map[string]string{"cloud.google.com/gke-nodepool": "default-pool"},
+			providerID: "gce://project/us-east1-b/node-1",
+			expected:   "gke",
+		},
+		"Provider is identified by provider id": {
+			providerID: "aws:///us-east-1a/i-0123",
+			expected:   "aws",
+		},
+		"Provider is unknown without provider id": {},
+	}
+
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			node := corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Labels: tc.labels},
+				Spec:       corev1.NodeSpec{ProviderID: tc.providerID},
+			}
+			if provider := getProviderForNode(node); provider != tc.expected {
+				t.Fatalf("Expected provider %q, got %q", tc.expected, provider)
+			}
+		})
+	}
+}
+
+func TestGetZoneAndRegionForNode(t *testing.T) {
+	testCases := map[string]struct {
+		labels         map[string]string
+		expectedZone   string
+		expectedRegion string
+	}{
+		"Topology labels are used": {
+			labels:         map[string]string{LabelTopologyZone: "us-east1-b", LabelTopologyRegion: "us-east1"},
+			expectedZone:   "us-east1-b",
+			expectedRegion: "us-east1",
+		},
+		"Topology labels take precedence over beta labels": {
+			labels: map[string]string{
+				LabelTopologyZone: "us-east1-b", LabelZoneFailureDomain: "us-east1-c",
+				LabelTopologyRegion: "us-east1", LabelZoneRegion: "us-west1",
+			},
+			expectedZone:   "us-east1-b",
+			expectedRegion: "us-east1",
+		},
+		"Beta labels are used without topology labels": {
+			labels:         map[string]string{LabelZoneFailureDomain: "us-east1-c", LabelZoneRegion: "us-east1"},
+			expectedZone:   "us-east1-c",
+			expectedRegion: "us-east1",
+		},
+		"Unlabeled node has no zone or region": {},
+	}
+
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			node := corev1.Node{ObjectMeta: metav1.ObjectMeta{Labels: tc.labels}}
+			if zone := getZoneNameForNode(node); zone != tc.expectedZone {
+				t.Fatalf("Expected zone %q, got %q", tc.expectedZone, zone)
+			}
+			if region := getRegionNameForNode(node); region != tc.expectedRegion {
+				t.Fatalf("Expected region %q, got %q", tc.expectedRegion, region)
+			}
+		})
+	}
+}
+
+func TestUpdateClusterZonesAndRegion(t *testing.T) {
+	now := metav1.Now()
+	cluster := &fedv1b1.KubeFedCluster{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{LabelTopologyRegion: "eu-west1"}},
+		Status:     fedv1b1.KubeFedClusterStatus{Zones: []string{"us-east1-b"}, Region: "us-east1"},
+	}
+	// The topology was recently refreshed, so the nodes are not listed.
+	storedData := &ClusterData{topologyRefreshed: time.Now()}
+
+	status := updateClusterZonesAndRegion(clusterStatus(corev1.ConditionTrue, now, now), cluster, nil, storedData)
+	if !reflect.DeepEqual(status.Zones, []string{"us-east1-b"}) || status.Region != "eu-west1" {
+		t.Fatalf("Expected the zones to be preserved and the region to be set by the label, got %v and %q", status.Zones, status.Region)
+	}
+}
+
+func TestIsPodUnschedulable(t *testing.T) {
+	testCases := map[string]struct {
+		conditions []corev1.PodCondition
+		expected   bool
+	}{
+		"Pod found unschedulable by the scheduler is unschedulable": {
+			conditions: []corev1.PodCondition{{Type: corev1.PodScheduled, Status: corev1.ConditionFalse, Reason: corev1.PodReasonUnschedulable}},
+			expected:   true,
+		},
+		"Scheduled pod is not unschedulable": {
+			conditions: []corev1.PodCondition{{Type: corev1.PodScheduled, Status: corev1.ConditionTrue}},
+		},
+		"Pod not yet considered by the scheduler is not unschedulable": {},
+	}
+
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			pod := &corev1.Pod{Status: corev1.PodStatus{Conditions: tc.conditions}}
+			if unschedulable := isPodUnschedulable(pod); unschedulable != tc.expected {
+				t.Fatalf("Expected unschedulable to be %t, got %t", tc.expected, unschedulable)
+			}
+		})
+	}
+}
+
+func TestIsNodeUnderPressure(t *testing.T) {
+	testCases := map[string]struct {
+		conditions []corev1.NodeCondition
+		expected   bool
+	}{
+		"Node with memory pressure is under pressure": {
+			conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+				{Type: corev1.NodeMemoryPressure, Status: corev1.ConditionTrue},
+			},
+			expected: true,
+		},
+		"Node with pid pressure is under pressure": {
+			conditions: []corev1.NodeCondition{{Type: corev1.NodePIDPressure, Status: corev1.ConditionTrue}},
+			expected:   true,
+		},
+		"Node without pressure is not under pressure": {
+			conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+				{Type: corev1.NodeDiskPressure, Status: corev1.ConditionFalse},
+			},
+		},
+	}
+
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			node := &corev1.Node{Status: corev1.NodeStatus{Conditions: tc.conditions}}
+			if underPressure := isNodeUnderPressure(node); underPressure != tc.expected {
+				t.Fatalf("Expected under pressure to be %t, got %t", tc.expected, underPressure)
+			}
+		})
+	}
+}
+
+func TestParseAutoscalerStatus(t *testing.T) {
+	testCases := map[string]struct {
+		data     string
+		expected fedv1b1.ClusterAutoscalerStatus
+	}{
+		"Human-readable status with a node group at its maximum size": {
+			data: `Cluster-autoscaler status at 2019-06-01 10:00:00.000000000 +0000 UTC:
+Cluster-wide:
+  Health:      Healthy (ready=5 unready=0 notStarted=0 longNotStarted=0 registered=5 longUnregistered=0)
+  ScaleUp:     NoActivity (ready=5 registered=5)
+
+NodeGroups:
+  Name:        pool-a
+  Health:      Healthy (ready=3 unready=0 notStarted=0 longNotStarted=0 registered=3 longUnregistered=0 cloudProviderTarget=3 (minSize=1, maxSize=3))
+  ScaleUp:     NoActivity (ready=3 cloudProviderTarget=3)
+
+  Name:        pool-b
+  Health:      Healthy (ready=2 unready=0 notStarted=0 longNotStarted=0 registered=2 longUnregistered=0 cloudProviderTarget=2 (minSize=1, maxSize=10))
+  ScaleUp:     NoActivity (ready=2 cloudProviderTarget=2)
+`,
+			expected: fedv1b1.ClusterAutoscalerStatus{NodeGroups: 2, NodeGroupsAtMaxSize: 1},
+		},
+		"Structured status with every node group at its maximum size": {
+			data: `time: 2024-06-01 10:00:00.000000000 +0000 UTC
+autoscalerStatus: Running
+nodeGroups:
+- name: pool-a
+  health:
+    status: Healthy
+    cloudProviderTarget: 3
+    minSize: 1
+    maxSize: 3
+- name: pool-b
+  health:
+    status: Healthy
+    cloudProviderTarget: 5
+    minSize: 5
+    maxSize: 5
+`,
+			expected: fedv1b1.ClusterAutoscalerStatus{MaxNodesReached: true, NodeGroups: 2, NodeGroupsAtMaxSize: 2},
+		},
+		"Status without node groups disables scale-up": {
+			data:     "autoscalerStatus: Running\n",
+			expected: fedv1b1.ClusterAutoscalerStatus{ScaleUpDisabled: true},
+		},
+	}
+
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			status := parseAutoscalerStatus(tc.data)
+			if !reflect.DeepEqual(*status, tc.expected) {
+				t.Fatalf("Expected autoscaler status %+v, got %+v", tc.expected, *status)
+			}
+		})
+	}
+}
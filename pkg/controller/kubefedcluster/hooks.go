@@ -0,0 +1,456 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubefedcluster
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog"
+
+	"sigs.k8s.io/kubefed/pkg/apis/core/common"
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+// lifecycleHooksFinalizer retains a KubeFedCluster that is being
+// unjoined until its Unjoined lifecycle hooks have completed.
+const lifecycleHooksFinalizer = "core.kubefed.k8s.io/cluster-lifecycle-hooks"
+
+// clusterUnjoiningReason is the reason of the Ready condition of a
+// cluster that is retained while its Unjoined hooks run.
+const clusterUnjoiningReason = "ClusterUnjoining"
+
+const defaultHookWebhookTimeout = 10 * time.Second
+
+// The labels identifying the Jobs of job hooks, and the annotation
+// holding the name of the cluster whose event created the Job.
+const (
+	lifecycleHookLabel         = "kubefed.io/cluster-lifecycle-hook"
+	lifecycleEventLabel        = "kubefed.io/cluster-lifecycle-event"
+	lifecycleClusterAnnotation = "kubefed.io/cluster"
+)
+
+// The environment variables set in the containers of the Jobs of job
+// hooks.
+const (
+	clusterNameEnvVar        = "KUBEFED_CLUSTER_NAME"
+	clusterEventEnvVar       = "KUBEFED_CLUSTER_EVENT"
+	clusterAPIEndpointEnvVar = "KUBEFED_CLUSTER_API_ENDPOINT"
+)
+
+// lifecycleHookPayload is POSTed to the webhook of a webhook hook.
+type lifecycleHookPayload struct {
+	Hook  string                        `json:"hook"`
+	Event fedv1b1.ClusterLifecycleEvent `json:"event"`
+	// Time is the RFC3339 time at which the event occurred.
+	Time        string `json:"time"`
+	Cluster     string `json:"cluster"`
+	APIEndpoint string `json:"apiEndpoint"`
+}
+
+// lifecycleHooks runs the lifecycle hooks configured by the
+// KubeFedConfig when clusters are joined, approved, marked unhealthy
+// or unjoined.  The most recent run of each hook for each event is
+// recorded in the status of the cluster, so that a hook is run again
+// only for a later occurrence of the event.
+type lifecycleHooks struct {
+	client     genericclient.Client
+	namespace  string
+	hooks      []fedv1b1.ClusterLifecycleHook
+	httpClient *http.Client
+}
+
+func newLifecycleHooks(client genericclient.Client, namespace string, hooks []fedv1b1.ClusterLifecycleHook) *lifecycleHooks {
+	return &lifecycleHooks{
+		client:     client,
+		namespace:  namespace,
+		hooks:      hooks,
+		httpClient: &http.Client{},
+	}
+}
+
+// runsFor indicates whether any hook runs for the given event.
+func (h *lifecycleHooks) runsFor(event fedv1b1.ClusterLifecycleEvent) bool {
+	for _, hook := range h.hooks {
+		if hookRunsFor(hook, event) {
+			return true
+		}
+	}
+	return false
+}
+
+func hookRunsFor(hook fedv1b1.ClusterLifecycleHook, event fedv1b1.ClusterLifecycleEvent) bool {
+	for _, hookEvent := range hook.Events {
+		if hookEvent == event {
+			return true
+		}
+	}
+	return false
+}
+
+// update runs the hooks of the events of the cluster that occurred
+// after the hooks last ran, refreshes the phase of the runs of job
+// hooks that are in progress and records the runs and their outcome
+// in the given status, which is the status the cluster is updated to.
+func (h *lifecycleHooks) update(cluster *fedv1b1.KubeFedCluster, clusterStatus *fedv1b1.KubeFedClusterStatus) {
+	if len(h.hooks) == 0 {
+		clusterStatus.LifecycleHooks = nil
+		replaceClusterCondition(clusterStatus, common.ClusterLifecycleHooksSucceeded, nil)
+		return
+	}
+
+	now := metav1.Now()
+	eventTimes := lifecycleEventTimes(cluster, clusterStatus, now)
+	previousRuns := cluster.Status.DeepCopy().LifecycleHooks
+	runs := []fedv1b1.ClusterLifecycleHookStatus{}
+	for _, hook := range h.hooks {
+		for _, event := range hook.Events {
+			run := findHookRun(previousRuns, hook.Name, event)
+			eventTime, occurred := eventTimes[event]
+			switch {
+			case occurred && (run == nil || startedBefore(run, eventTime)):
+				run = h.run(cluster, hook, event, eventTime, now)
+			case run != nil && run.Phase == fedv1b1.ClusterLifecycleHookRunning:
+				h.refresh(run, now)
+			}
+			// The run of a hook for an event that did not occur since
+			// the hook was configured is not recorded.
+			if run != nil {
+				runs = append(runs, *run)
+			}
+		}
+	}
+	clusterStatus.LifecycleHooks = runs
+	setLifecycleHooksCondition(clusterStatus, &cluster.Status, now)
+}
+
+// lifecycleEventTimes returns the times of the most recent occurrence
+// of each event of the cluster, truncated to the precision with which
+// times are stored.  An unjoining cluster has no other events, and a
+// cluster is only considered to have been marked unhealthy by the
+// current health check if it was previously ready.
+func lifecycleEventTimes(cluster *fedv1b1.KubeFedCluster, clusterStatus *fedv1b1.KubeFedClusterStatus,
+	now metav1.Time) map[fedv1b1.ClusterLifecycleEvent]metav1.Time {
+
+	if cluster.DeletionTimestamp != nil {
+		return map[fedv1b1.ClusterLifecycleEvent]metav1.Time{
+			fedv1b1.ClusterUnjoinedEvent: cluster.DeletionTimestamp.Rfc3339Copy(),
+		}
+	}
+	eventTimes := map[fedv1b1.ClusterLifecycleEvent]metav1.Time{
+		fedv1b1.ClusterJoinedEvent: cluster.CreationTimestamp.Rfc3339Copy(),
+	}
+	if util.IsClusterApproved(clusterStatus) {
+		eventTimes[fedv1b1.ClusterApprovedEvent] = util.ClusterApprovalCondition(clusterStatus).LastTransitionTime.Rfc3339Copy()
+	}
+	if util.IsClusterReady(&cluster.Status) && !util.IsClusterReady(clusterStatus) {
+		eventTimes[fedv1b1.ClusterUnhealthyEvent] = now.Rfc3339Copy()
+	}
+	return eventTimes
+}
+
+func findHookRun(runs []fedv1b1.ClusterLifecycleHookStatus, hookName string, event fedv1b1.ClusterLifecycleEvent) *fedv1b1.ClusterLifecycleHookStatus {
+	for i := range runs {
+		if runs[i].Name == hookName && runs[i].Event == event {
+			return &runs[i]
+		}
+	}
+	return nil
+}
+
+// startedBefore indicates whether the run started before the given
+// time, to the precision with which times are stored.
+func startedBefore(run *fedv1b1.ClusterLifecycleHookStatus, t metav1.Time) bool {
+	startTime := run.StartTime.Rfc3339Copy()
+	return startTime.Before(&t)
+}
+
+// run starts a run of the hook for an event of the cluster that
+// occurred at the given time.  A webhook is called synchronously,
+// while a job hook completes once its Job does.
+func (h *lifecycleHooks) run(cluster *fedv1b1.KubeFedCluster, hook fedv1b1.ClusterLifecycleHook,
+	event fedv1b1.ClusterLifecycleEvent, eventTime, now metav1.Time) *fedv1b1.ClusterLifecycleHookStatus {
+
+	klog.V(2).Infof("Running lifecycle hook %q for event %s of cluster %q", hook.Name, event, cluster.Name)
+	run := &fedv1b1.ClusterLifecycleHookStatus{
+		Name:      hook.Name,
+		Event:     event,
+		Phase:     fedv1b1.ClusterLifecycleHookRunning,
+		StartTime: now,
+	}
+	var err error
+	if hook.Webhook != nil {
+		err = h.callWebhook(cluster, hook, event, eventTime)
+		if err == nil {
+			completeHookRun(run, fedv1b1.ClusterLifecycleHookSucceeded, "", now)
+		}
+	} else {
+		run.JobName, err = h.createJob(cluster, hook, event, eventTime)
+	}
+	if err != nil {
+		klog.Warningf("Lifecycle hook %q failed for event %s of cluster %q: %v", hook.Name, event, cluster.Name, err)
+		completeHookRun(run, fedv1b1.ClusterLifecycleHookFailed, err.Error(), now)
+	}
+	return run
+}
+
+// refresh completes a run of a job hook once its Job has completed or
+// failed.
+func (h *lifecycleHooks) refresh(run *fedv1b1.ClusterLifecycleHookStatus, now metav1.Time) {
+	job := &batchv1.Job{}
+	err := h.client.Get(context.TODO(), job, h.namespace, run.JobName)
+	switch {
+	case apierrors.IsNotFound(err):
+		completeHookRun(run, fedv1b1.ClusterLifecycleHookFailed, fmt.Sprintf("Job %q was not found", run.JobName), now)
+		return
+	case err != nil:
+		klog.Warningf("Failed to get Job %q of lifecycle hook %q: %v", run.JobName, run.Name, err)
+		return
+	}
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			completeHookRun(run, fedv1b1.ClusterLifecycleHookSucceeded, "", now)
+		case batchv1.JobFailed:
+			completeHookRun(run, fedv1b1.ClusterLifecycleHookFailed, fmt.Sprintf("Job %q failed: %s", run.JobName, condition.Message), now)
+		}
+	}
+}
+
+func completeHookRun(run *fedv1b1.ClusterLifecycleHookStatus, phase fedv1b1.ClusterLifecycleHookPhase, message string, now metav1.Time) {
+	run.Phase = phase
+	run.Message = message
+	run.CompletionTime = &now
+}
+
+// callWebhook POSTs the cluster and the event to the webhook of the
+// hook.
+func (h *lifecycleHooks) callWebhook(cluster *fedv1b1.KubeFedCluster, hook fedv1b1.ClusterLifecycleHook,
+	event fedv1b1.ClusterLifecycleEvent, eventTime metav1.Time) error {
+
+	payload, err := json.Marshal(&lifecycleHookPayload{
+		Hook:        hook.Name,
+		Event:       event,
+		Time:        eventTime.UTC().Format(time.RFC3339),
+		Cluster:     cluster.Name,
+		APIEndpoint: cluster.Spec.APIEndpoint,
+	})
+	if err != nil {
+		return err
+	}
+	timeout := defaultHookWebhookTimeout
+	if hook.Webhook.TimeoutSeconds > 0 {
+		timeout = time.Duration(hook.Webhook.TimeoutSeconds) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	request, err := http.NewRequest(http.MethodPost, hook.Webhook.URL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	response, err := h.httpClient.Do(request.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		body, _ := ioutil.ReadAll(io.LimitReader(response.Body, 256))
+		return errors.Errorf("webhook responded with status %d: %s", response.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// createJob creates the Job of a run of a job hook from the template
+// of the hook.  The name of the Job is derived from the cluster, the
+// hook and the occurrence of the event so that a run that is started
+// again (e.g. after a failed status update) does not create another
+// Job.
+func (h *lifecycleHooks) createJob(cluster *fedv1b1.KubeFedCluster, hook fedv1b1.ClusterLifecycleHook,
+	event fedv1b1.ClusterLifecycleEvent, eventTime metav1.Time) (string, error) {
+
+	template := hook.Job.Template.DeepCopy()
+	job := &batchv1.Job{
+		ObjectMeta: template.ObjectMeta,
+		Spec:       template.Spec,
+	}
+	job.Name = hookJobName(cluster.Name, hook.Name, event, eventTime)
+	job.GenerateName = ""
+	job.Namespace = h.namespace
+	if job.Labels == nil {
+		job.Labels = make(map[string]string)
+	}
+	job.Labels[lifecycleHookLabel] = hook.Name
+	job.Labels[lifecycleEventLabel] = string(event)
+	if job.Annotations == nil {
+		job.Annotations = make(map[string]string)
+	}
+	job.Annotations[lifecycleClusterAnnotation] = cluster.Name
+	// The Jobs of a cluster are removed along with it.
+	job.OwnerReferences = append(job.OwnerReferences, metav1.OwnerReference{
+		APIVersion: fedv1b1.SchemeGroupVersion.String(),
+		Kind:       "KubeFedCluster",
+		Name:       cluster.Name,
+		UID:        cluster.UID,
+	})
+
+	env := []corev1.EnvVar{
+		{Name: clusterNameEnvVar, Value: cluster.Name},
+		{Name: clusterEventEnvVar, Value: string(event)},
+		{Name: clusterAPIEndpointEnvVar, Value: cluster.Spec.APIEndpoint},
+	}
+	podSpec := &job.Spec.Template.Spec
+	for _, containers := range [][]corev1.Container{podSpec.InitContainers, podSpec.Containers} {
+		for i := range containers {
+			containers[i].Env = append(containers[i].Env, env...)
+		}
+	}
+
+	err := h.client.Create(context.TODO(), job)
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return "", errors.Wrapf(err, "failed to create Job %q", job.Name)
+	}
+	return job.Name, nil
+}
+
+// hookJobName returns the name of the Job of a run of a hook.  The
+// name of the hook is at most 32 characters so that the name is a
+// valid DNS label.
+func hookJobName(clusterName, hookName string, event fedv1b1.ClusterLifecycleEvent, eventTime metav1.Time) string {
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s/%s/%s/%d", clusterName, hookName, event, eventTime.Unix())))
+	return fmt.Sprintf("%s-%s-%x", hookName, strings.ToLower(string(event)), hash[:5])
+}
+
+// setLifecycleHooksCondition sets the LifecycleHooksSucceeded
+// condition of the cluster status from the recorded runs of its
+// hooks, or removes it if no hooks have run.  A failed run is
+// reported in preference to runs in progress.
+func setLifecycleHooksCondition(clusterStatus, previousStatus *fedv1b1.KubeFedClusterStatus, now metav1.Time) {
+	if len(clusterStatus.LifecycleHooks) == 0 {
+		replaceClusterCondition(clusterStatus, common.ClusterLifecycleHooksSucceeded, nil)
+		return
+	}
+	var failed, running []string
+	for _, run := range clusterStatus.LifecycleHooks {
+		switch run.Phase {
+		case fedv1b1.ClusterLifecycleHookFailed:
+			failed = append(failed, fmt.Sprintf("%s (%s): %s", run.Name, run.Event, run.Message))
+		case fedv1b1.ClusterLifecycleHookRunning:
+			running = append(running, fmt.Sprintf("%s (%s)", run.Name, run.Event))
+		}
+	}
+	condition := &fedv1b1.ClusterCondition{
+		Type:          common.ClusterLifecycleHooksSucceeded,
+		Status:        corev1.ConditionTrue,
+		Reason:        "HooksSucceeded",
+		Message:       "the lifecycle hooks of the most recent events of the cluster succeeded",
+		LastProbeTime: now,
+	}
+	switch {
+	case len(failed) > 0:
+		condition.Status = corev1.ConditionFalse
+		condition.Reason = "HookFailed"
+		condition.Message = "lifecycle hooks failed: " + strings.Join(failed, "; ")
+	case len(running) > 0:
+		condition.Status = corev1.ConditionUnknown
+		condition.Reason = "HooksRunning"
+		condition.Message = "lifecycle hooks are running: " + strings.Join(running, ", ")
+	}
+	condition.LastTransitionTime = transitionTime(previousStatus, common.ClusterLifecycleHooksSucceeded, condition.Status, now)
+	replaceClusterCondition(clusterStatus, common.ClusterLifecycleHooksSucceeded, condition)
+}
+
+// unjoinedHooksCompleted indicates whether every hook of the Unjoined
+// event has completed a run for the deletion of the cluster.
+func (h *lifecycleHooks) unjoinedHooksCompleted(cluster *fedv1b1.KubeFedCluster) bool {
+	deletionTime := cluster.DeletionTimestamp.Rfc3339Copy()
+	for _, hook := range h.hooks {
+		if !hookRunsFor(hook, fedv1b1.ClusterUnjoinedEvent) {
+			continue
+		}
+		run := findHookRun(cluster.Status.LifecycleHooks, hook.Name, fedv1b1.ClusterUnjoinedEvent)
+		if run == nil || startedBefore(run, deletionTime) || run.Phase == fedv1b1.ClusterLifecycleHookRunning {
+			return false
+		}
+	}
+	return true
+}
+
+// updateFinalizer adds the finalizer retaining the cluster while it is
+// unjoined if any hook runs for the Unjoined event, and removes it
+// once the cluster is being deleted and the hooks have completed or
+// if no hook runs for the event.
+func (h *lifecycleHooks) updateFinalizer(cluster *fedv1b1.KubeFedCluster) error {
+	finalizers := sets.NewString(cluster.Finalizers...)
+	retain := h.runsFor(fedv1b1.ClusterUnjoinedEvent) &&
+		(cluster.DeletionTimestamp == nil || !h.unjoinedHooksCompleted(cluster))
+	if retain == finalizers.Has(lifecycleHooksFinalizer) {
+		return nil
+	}
+	if retain {
+		finalizers.Insert(lifecycleHooksFinalizer)
+	} else {
+		finalizers.Delete(lifecycleHooksFinalizer)
+	}
+	cluster.Finalizers = finalizers.List()
+	return h.client.Update(context.TODO(), cluster)
+}
+
+// unjoiningStatus returns the status of a cluster that is retained
+// while its Unjoined hooks run, which is not ready so that it is no
+// longer used for placement or propagation.  A cluster being unjoined
+// is not health checked.
+func unjoiningStatus(cluster *fedv1b1.KubeFedCluster) *fedv1b1.KubeFedClusterStatus {
+	clusterStatus := cluster.Status.DeepCopy()
+	now := metav1.Now()
+	readyCondition := fedv1b1.ClusterCondition{
+		Type:               common.ClusterReady,
+		Status:             corev1.ConditionFalse,
+		Reason:             clusterUnjoiningReason,
+		Message:            "cluster is being unjoined",
+		LastProbeTime:      now,
+		LastTransitionTime: transitionTime(&cluster.Status, common.ClusterReady, corev1.ConditionFalse, now),
+	}
+	conditions := []fedv1b1.ClusterCondition{readyCondition}
+	for _, condition := range clusterStatus.Conditions {
+		if condition.Type == common.ClusterApproved || condition.Type == common.ClusterLifecycleHooksSucceeded {
+			conditions = append(conditions, condition)
+		}
+	}
+	clusterStatus.Conditions = conditions
+	return clusterStatus
+}
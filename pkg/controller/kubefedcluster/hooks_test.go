@@ -0,0 +1,260 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubefedcluster
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1b1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"sigs.k8s.io/kubefed/pkg/apis/core/common"
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+// fakeHookClient stores the Jobs created by job hooks and counts the
+// updates of clusters.
+type fakeHookClient struct {
+	jobs           map[string]*batchv1.Job
+	clusterUpdates int
+}
+
+func (c *fakeHookClient) Create(ctx context.Context, obj runtime.Object) error {
+	job := obj.(*batchv1.Job)
+	if _, ok := c.jobs[job.Name]; ok {
+		return apierrors.NewAlreadyExists(schema.GroupResource{Group: "batch", Resource: "jobs"}, job.Name)
+	}
+	c.jobs[job.Name] = job.DeepCopy()
+	return nil
+}
+
+func (c *fakeHookClient) Get(ctx context.Context, obj runtime.Object, namespace, name string) error {
+	job, ok := c.jobs[name]
+	if !ok {
+		return apierrors.NewNotFound(schema.GroupResource{Group: "batch", Resource: "jobs"}, name)
+	}
+	job.DeepCopyInto(obj.(*batchv1.Job))
+	return nil
+}
+
+func (c *fakeHookClient) Update(ctx context.Context, obj runtime.Object) error {
+	c.clusterUpdates++
+	return nil
+}
+
+func (c *fakeHookClient) Delete(ctx context.Context, obj runtime.Object, namespace, name string) error {
+	return nil
+}
+
+func (c *fakeHookClient) List(ctx context.Context, obj runtime.Object, namespace string) error {
+	return nil
+}
+
+func (c *fakeHookClient) UpdateStatus(ctx context.Context, obj runtime.Object) error {
+	return nil
+}
+
+func readyStatus(status corev1.ConditionStatus) *fedv1b1.KubeFedClusterStatus {
+	return &fedv1b1.KubeFedClusterStatus{
+		Conditions: []fedv1b1.ClusterCondition{{Type: common.ClusterReady, Status: status}},
+	}
+}
+
+func hooksCondition(clusterStatus *fedv1b1.KubeFedClusterStatus) *fedv1b1.ClusterCondition {
+	for i := range clusterStatus.Conditions {
+		if clusterStatus.Conditions[i].Type == common.ClusterLifecycleHooksSucceeded {
+			return &clusterStatus.Conditions[i]
+		}
+	}
+	return nil
+}
+
+func TestLifecycleHooks(t *testing.T) {
+	payloads := []lifecycleHookPayload{}
+	webhookStatus := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload := lifecycleHookPayload{}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		payloads = append(payloads, payload)
+		w.WriteHeader(webhookStatus)
+	}))
+	defer server.Close()
+
+	client := &fakeHookClient{jobs: make(map[string]*batchv1.Job)}
+	hooks := newLifecycleHooks(client, "kube-federation-system", []fedv1b1.ClusterLifecycleHook{
+		{
+			Name:    "cmdb",
+			Events:  []fedv1b1.ClusterLifecycleEvent{fedv1b1.ClusterJoinedEvent, fedv1b1.ClusterUnhealthyEvent},
+			Webhook: &fedv1b1.ClusterLifecycleWebhook{URL: server.URL},
+		},
+		{
+			Name:   "namespaces",
+			Events: []fedv1b1.ClusterLifecycleEvent{fedv1b1.ClusterApprovedEvent},
+			Job: &fedv1b1.ClusterLifecycleJob{
+				Template: batchv1b1JobTemplate("create-namespaces"),
+			},
+		},
+	})
+
+	cluster := &fedv1b1.KubeFedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "cluster1",
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-time.Minute)),
+		},
+		Spec: fedv1b1.KubeFedClusterSpec{APIEndpoint: "https://cluster1"},
+	}
+
+	// The cluster has joined but is not approved.
+	clusterStatus := readyStatus(corev1.ConditionTrue)
+	hooks.update(cluster, clusterStatus)
+	if len(payloads) != 1 || payloads[0].Event != fedv1b1.ClusterJoinedEvent || payloads[0].Cluster != "cluster1" {
+		t.Fatalf("Expected the webhook to be called for the Joined event, got %+v", payloads)
+	}
+	if len(clusterStatus.LifecycleHooks) != 1 || clusterStatus.LifecycleHooks[0].Phase != fedv1b1.ClusterLifecycleHookSucceeded {
+		t.Fatalf("Expected a succeeded run, got %+v", clusterStatus.LifecycleHooks)
+	}
+	if condition := hooksCondition(clusterStatus); condition == nil || condition.Status != corev1.ConditionTrue {
+		t.Fatalf("Expected a true condition, got %+v", condition)
+	}
+
+	// Once approved, the Job of the job hook is created and the hooks
+	// that already ran are not run again.
+	cluster.Status = *clusterStatus
+	clusterStatus = readyStatus(corev1.ConditionTrue)
+	clusterStatus.Conditions = append(clusterStatus.Conditions, fedv1b1.ClusterCondition{
+		Type:               common.ClusterApproved,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+	})
+	hooks.update(cluster, clusterStatus)
+	if len(payloads) != 1 {
+		t.Fatalf("Expected the webhook not to be called again, got %+v", payloads)
+	}
+	run := findHookRun(clusterStatus.LifecycleHooks, "namespaces", fedv1b1.ClusterApprovedEvent)
+	if run == nil || run.Phase != fedv1b1.ClusterLifecycleHookRunning {
+		t.Fatalf("Expected a running job hook, got %+v", clusterStatus.LifecycleHooks)
+	}
+	job, ok := client.jobs[run.JobName]
+	if !ok {
+		t.Fatalf("Expected Job %q to be created", run.JobName)
+	}
+	env := job.Spec.Template.Spec.Containers[0].Env
+	if len(env) != 3 || env[0].Value != "cluster1" || env[1].Value != string(fedv1b1.ClusterApprovedEvent) {
+		t.Errorf("Unexpected environment of the Job: %+v", env)
+	}
+	if condition := hooksCondition(clusterStatus); condition == nil || condition.Status != corev1.ConditionUnknown {
+		t.Fatalf("Expected an unknown condition, got %+v", condition)
+	}
+
+	// The run completes with its Job.
+	cluster.Status = *clusterStatus
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+	clusterStatus = cluster.Status.DeepCopy()
+	hooks.update(cluster, clusterStatus)
+	run = findHookRun(clusterStatus.LifecycleHooks, "namespaces", fedv1b1.ClusterApprovedEvent)
+	if run.Phase != fedv1b1.ClusterLifecycleHookSucceeded || len(client.jobs) != 1 {
+		t.Fatalf("Expected the job hook to succeed without another Job, got %+v", run)
+	}
+
+	// A failed webhook call is reported by the condition.
+	cluster.Status = *clusterStatus
+	webhookStatus = http.StatusInternalServerError
+	clusterStatus = cluster.Status.DeepCopy()
+	clusterStatus.Conditions[0].Status = corev1.ConditionFalse
+	hooks.update(cluster, clusterStatus)
+	if len(payloads) != 2 || payloads[1].Event != fedv1b1.ClusterUnhealthyEvent {
+		t.Fatalf("Expected the webhook to be called for the Unhealthy event, got %+v", payloads)
+	}
+	run = findHookRun(clusterStatus.LifecycleHooks, "cmdb", fedv1b1.ClusterUnhealthyEvent)
+	if run == nil || run.Phase != fedv1b1.ClusterLifecycleHookFailed {
+		t.Fatalf("Expected a failed run, got %+v", clusterStatus.LifecycleHooks)
+	}
+	if condition := hooksCondition(clusterStatus); condition == nil || condition.Status != corev1.ConditionFalse {
+		t.Fatalf("Expected a false condition, got %+v", condition)
+	}
+}
+
+func batchv1b1JobTemplate(name string) batchv1b1.JobTemplateSpec {
+	return batchv1b1.JobTemplateSpec{
+		Spec: batchv1.JobSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers:    []corev1.Container{{Name: name, Image: "busybox"}},
+					RestartPolicy: corev1.RestartPolicyNever,
+				},
+			},
+		},
+	}
+}
+
+func TestUpdateLifecycleHooksFinalizer(t *testing.T) {
+	client := &fakeHookClient{jobs: make(map[string]*batchv1.Job)}
+	hooks := newLifecycleHooks(client, "kube-federation-system", []fedv1b1.ClusterLifecycleHook{{
+		Name:   "dns",
+		Events: []fedv1b1.ClusterLifecycleEvent{fedv1b1.ClusterUnjoinedEvent},
+		Job:    &fedv1b1.ClusterLifecycleJob{Template: batchv1b1JobTemplate("drain")},
+	}})
+	cluster := &fedv1b1.KubeFedCluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster1"}}
+
+	if err := hooks.updateFinalizer(cluster); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(cluster.Finalizers) != 1 || client.clusterUpdates != 1 {
+		t.Fatalf("Expected the finalizer to be added, got %v", cluster.Finalizers)
+	}
+
+	// The cluster is retained until the Unjoined hook has completed.
+	deletionTime := metav1.Now()
+	cluster.DeletionTimestamp = &deletionTime
+	clusterStatus := unjoiningStatus(cluster)
+	hooks.update(cluster, clusterStatus)
+	if ready := clusterStatus.Conditions[0]; ready.Status != corev1.ConditionFalse || ready.Reason != clusterUnjoiningReason {
+		t.Errorf("Expected an unjoining cluster not to be ready, got %+v", ready)
+	}
+	cluster.Status = *clusterStatus
+	if err := hooks.updateFinalizer(cluster); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(cluster.Finalizers) != 1 || client.clusterUpdates != 1 {
+		t.Fatalf("Expected the finalizer to be retained, got %v", cluster.Finalizers)
+	}
+
+	run := findHookRun(cluster.Status.LifecycleHooks, "dns", fedv1b1.ClusterUnjoinedEvent)
+	client.jobs[run.JobName].Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue}}
+	clusterStatus = cluster.Status.DeepCopy()
+	hooks.update(cluster, clusterStatus)
+	cluster.Status = *clusterStatus
+	if err := hooks.updateFinalizer(cluster); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(cluster.Finalizers) != 0 || client.clusterUpdates != 2 {
+		t.Fatalf("Expected the finalizer to be removed, got %v", cluster.Finalizers)
+	}
+}
@@ -0,0 +1,439 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lighthouse
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog"
+
+	"sigs.k8s.io/kubefed/pkg/apis/core/typeconfig"
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	"sigs.k8s.io/kubefed/pkg/controller/sync/status"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+const (
+	allClustersKey = "ALL_CLUSTERS"
+
+	// multiClusterGroup is the group of the Kubernetes multi-cluster
+	// services API implemented by Submariner Lighthouse.
+	multiClusterGroup   = "multicluster.x-k8s.io"
+	multiClusterVersion = "v1alpha1"
+)
+
+var (
+	serviceExportAPIResource = &metav1.APIResource{
+		Group:        multiClusterGroup,
+		Version:      multiClusterVersion,
+		Kind:         "ServiceExport",
+		Name:         "serviceexports",
+		SingularName: "serviceexport",
+		Namespaced:   true,
+	}
+	serviceImportAPIResource = &metav1.APIResource{
+		Group:        multiClusterGroup,
+		Version:      multiClusterVersion,
+		Kind:         "ServiceImport",
+		Name:         "serviceimports",
+		SingularName: "serviceimport",
+		Namespaced:   true,
+	}
+)
+
+// Controller integrates federated services with Submariner Lighthouse.
+// It maintains a ServiceExport for each service propagated to a member
+// cluster in which Lighthouse is installed, so that the service is
+// discoverable across the cluster set, and records the ServiceImports
+// of the service in the status of its federated resource.
+type Controller struct {
+	typeConfig typeconfig.Interface
+
+	client genericclient.Client
+
+	// For triggering reconciliation of all federated services. This
+	// is used when a cluster becomes available or unavailable.
+	clusterDeliverer *util.DelayingDeliverer
+
+	// Store for the federated services
+	federatedStore cache.Store
+	// Informer for the federated services
+	federatedController cache.Controller
+
+	// Informers for the services, ServiceExports and ServiceImports
+	// of member clusters
+	serviceInformer       util.FederatedInformer
+	serviceExportInformer util.FederatedInformer
+	serviceImportInformer util.FederatedInformer
+
+	worker util.ReconcileWorker
+
+	clusterAvailableDelay   time.Duration
+	clusterUnavailableDelay time.Duration
+	smallDelay              time.Duration
+}
+
+// StartController starts the Lighthouse controller for the type config
+// of services.
+func StartController(controllerConfig *util.ControllerConfig, stopChan <-chan struct{}, typeConfig typeconfig.Interface) error {
+	controller, err := newController(controllerConfig, typeConfig)
+	if err != nil {
+		return err
+	}
+	if controllerConfig.MinimizeLatency {
+		controller.minimizeLatency()
+	}
+	klog.Infof("Starting Lighthouse controller for %q", typeConfig.GetFederatedType().Kind)
+	controller.Run(stopChan)
+	return nil
+}
+
+func newController(controllerConfig *util.ControllerConfig, typeConfig typeconfig.Interface) (*Controller, error) {
+	federatedAPIResource := typeConfig.GetFederatedType()
+	userAgent := fmt.Sprintf("%s-lighthouse-controller", strings.ToLower(federatedAPIResource.Kind))
+	client := genericclient.NewForConfigOrDieWithUserAgent(controllerConfig.KubeConfig, userAgent)
+
+	c := &Controller{
+		typeConfig:              typeConfig,
+		client:                  client,
+		clusterAvailableDelay:   controllerConfig.ClusterAvailableDelay,
+		clusterUnavailableDelay: controllerConfig.ClusterUnavailableDelay,
+		smallDelay:              time.Second * 3,
+	}
+
+	c.worker = util.NewReconcileWorker(util.WorkerName{Controller: "lighthouse"}, c.reconcile, util.WorkerTiming{
+		ClusterSyncDelay: c.clusterAvailableDelay,
+	})
+
+	c.clusterDeliverer = util.NewDelayingDeliverer()
+
+	federatedClient, err := util.NewResourceClient(controllerConfig.KubeConfig, &federatedAPIResource)
+	if err != nil {
+		return nil, err
+	}
+	c.federatedStore, c.federatedController = util.NewResourceInformer(federatedClient, controllerConfig.TargetNamespace, c.worker.EnqueueObject)
+
+	targetAPIResource := typeConfig.GetTargetType()
+	c.serviceInformer, err = util.NewFederatedInformer(
+		controllerConfig,
+		client,
+		&targetAPIResource,
+		c.worker.EnqueueObject,
+		&util.ClusterLifecycleHandlerFuncs{
+			ClusterAvailable: func(cluster *fedv1b1.KubeFedCluster) {
+				c.clusterDeliverer.DeliverAt(allClustersKey, nil, time.Now().Add(c.clusterAvailableDelay))
+			},
+			ClusterUnavailable: func(cluster *fedv1b1.KubeFedCluster, _ []interface{}) {
+				c.clusterDeliverer.DeliverAt(allClustersKey, nil, time.Now().Add(c.clusterUnavailableDelay))
+			},
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	c.serviceExportInformer, err = util.NewFederatedInformer(
+		controllerConfig,
+		client,
+		serviceExportAPIResource,
+		c.worker.EnqueueObject,
+		&util.ClusterLifecycleHandlerFuncs{},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	c.serviceImportInformer, err = util.NewFederatedInformer(
+		controllerConfig,
+		client,
+		serviceImportAPIResource,
+		c.worker.EnqueueObject,
+		&util.ClusterLifecycleHandlerFuncs{},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// minimizeLatency reduces delays and timeouts to make the controller more responsive (useful for testing).
+func (c *Controller) minimizeLatency() {
+	c.clusterAvailableDelay = time.Second
+	c.clusterUnavailableDelay = time.Second
+	c.smallDelay = 20 * time.Millisecond
+	c.worker.SetDelay(50*time.Millisecond, c.clusterAvailableDelay)
+}
+
+// Run runs the Lighthouse controller.
+func (c *Controller) Run(stopChan <-chan struct{}) {
+	go c.federatedController.Run(stopChan)
+	c.serviceInformer.Start()
+	c.serviceExportInformer.Start()
+	c.serviceImportInformer.Start()
+	c.clusterDeliverer.StartWithHandler(func(_ *util.DelayingDelivererItem) {
+		c.reconcileOnClusterChange()
+	})
+
+	c.worker.Run(stopChan)
+
+	// Ensure all goroutines are cleaned up when the stop channel closes
+	go func() {
+		<-stopChan
+		c.serviceInformer.Stop()
+		c.serviceExportInformer.Stop()
+		c.serviceImportInformer.Stop()
+		c.clusterDeliverer.Stop()
+	}()
+}
+
+// isSynced indicates whether the federated service and member service
+// stores are synced.  The ServiceExport and ServiceImport stores only
+// sync for the clusters in which Lighthouse is installed, and are
+// checked per cluster.
+func (c *Controller) isSynced() bool {
+	if !c.federatedController.HasSynced() {
+		return false
+	}
+	if !c.serviceInformer.ClustersSynced() {
+		klog.V(2).Infof("Cluster list not synced")
+		return false
+	}
+	clusters, err := c.serviceInformer.GetReadyClusters()
+	if err != nil {
+		runtime.HandleError(errors.Wrap(err, "Failed to get ready clusters"))
+		return false
+	}
+	return c.serviceInformer.GetTargetStore().ClustersSynced(clusters)
+}
+
+// reconcileOnClusterChange triggers reconciliation of all federated
+// services.
+func (c *Controller) reconcileOnClusterChange() {
+	if !c.isSynced() {
+		c.clusterDeliverer.DeliverAt(allClustersKey, nil, time.Now().Add(c.clusterAvailableDelay))
+	}
+	for _, obj := range c.federatedStore.List() {
+		qualifiedName := util.NewQualifiedName(obj.(pkgruntime.Object))
+		c.worker.EnqueueWithDelay(qualifiedName, c.smallDelay)
+	}
+}
+
+func (c *Controller) reconcile(qualifiedName util.QualifiedName) util.ReconciliationStatus {
+	if !c.isSynced() {
+		return util.StatusNotSynced
+	}
+
+	key := qualifiedName.String()
+
+	klog.V(4).Infof("Starting to reconcile Lighthouse exports of service %v", key)
+	startTime := time.Now()
+	defer func() {
+		klog.V(4).Infof("Finished reconciling Lighthouse exports of service %v (duration: %v)", key, time.Since(startTime))
+	}()
+
+	fedObj, federated, err := c.federatedStore.GetByKey(key)
+	if err != nil {
+		runtime.HandleError(errors.Wrapf(err, "Failed to query the %s store for %q", c.typeConfig.GetFederatedType().Kind, key))
+		return util.StatusError
+	}
+
+	clusters, err := c.serviceInformer.GetReadyClusters()
+	if err != nil {
+		runtime.HandleError(errors.Wrap(err, "Failed to get ready cluster list"))
+		return util.StatusError
+	}
+
+	reconcileStatus := util.StatusAllOK
+	var imports []status.ClusterServiceImport
+	for _, cluster := range clusters {
+		if !c.lighthouseInstalled(cluster) {
+			klog.V(4).Infof("Not exporting service %q in cluster %q since Lighthouse is not installed", key, cluster.Name)
+			continue
+		}
+		exported := false
+		if federated {
+			exported, err = c.propagatedToCluster(cluster.Name, key)
+			if err != nil {
+				runtime.HandleError(err)
+				reconcileStatus = util.StatusError
+				continue
+			}
+		}
+		if err := c.ensureServiceExport(cluster, qualifiedName, exported); err != nil {
+			runtime.HandleError(errors.Wrapf(err, "Failed to reconcile the ServiceExport of service %q in cluster %q", key, cluster.Name))
+			reconcileStatus = util.StatusError
+		}
+		if !federated {
+			continue
+		}
+		clusterImport, err := c.serviceImport(cluster.Name, key)
+		if err != nil {
+			runtime.HandleError(err)
+			reconcileStatus = util.StatusError
+			continue
+		}
+		if clusterImport != nil {
+			imports = append(imports, *clusterImport)
+		}
+	}
+
+	if federated {
+		if err := c.updateStatus(fedObj.(*unstructured.Unstructured), imports); err != nil {
+			runtime.HandleError(errors.Wrapf(err, "Failed to record the ServiceImports of service %q", key))
+			return util.StatusError
+		}
+	}
+	return reconcileStatus
+}
+
+// lighthouseInstalled indicates whether Lighthouse is installed in the
+// cluster, i.e. its ServiceExports could be listed and watched.
+func (c *Controller) lighthouseInstalled(cluster *fedv1b1.KubeFedCluster) bool {
+	return c.serviceExportInformer.GetTargetStore().ClusterSynced(cluster.Name)
+}
+
+// propagatedToCluster indicates whether the service has been
+// propagated to the cluster by KubeFed.
+func (c *Controller) propagatedToCluster(clusterName, key string) (bool, error) {
+	obj, found, err := c.serviceInformer.GetTargetStore().GetByKey(clusterName, key)
+	if err != nil {
+		return false, errors.Wrapf(err, "Failed to get service %q from cluster %q", key, clusterName)
+	}
+	return found && util.HasManagedLabel(obj.(*unstructured.Unstructured)), nil
+}
+
+// ensureServiceExport creates or removes the ServiceExport of the
+// service in the cluster.  ServiceExports not created by KubeFed are
+// left untouched.
+func (c *Controller) ensureServiceExport(cluster *fedv1b1.KubeFedCluster, qualifiedName util.QualifiedName, exported bool) error {
+	obj, found, err := c.serviceExportInformer.GetTargetStore().GetByKey(cluster.Name, qualifiedName.String())
+	if err != nil {
+		return err
+	}
+	if found == exported {
+		return nil
+	}
+	if found && !util.HasManagedLabel(obj.(*unstructured.Unstructured)) {
+		return nil
+	}
+
+	client, err := c.serviceExportInformer.GetClientForCluster(cluster.Name)
+	if err != nil {
+		return err
+	}
+	namespace := util.ClusterNamespace(cluster.Spec.NamespaceMappings, qualifiedName.Namespace)
+	resources := client.Resources(namespace)
+
+	if !exported {
+		err = resources.Delete(qualifiedName.Name, &metav1.DeleteOptions{})
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	_, err = resources.Create(newServiceExport(namespace, qualifiedName.Name), metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		return nil
+	}
+	return err
+}
+
+// serviceImport returns the ServiceImport of the service in the
+// cluster, or nil if the service is not imported in the cluster.
+func (c *Controller) serviceImport(clusterName, key string) (*status.ClusterServiceImport, error) {
+	if !c.serviceImportInformer.GetTargetStore().ClusterSynced(clusterName) {
+		return nil, nil
+	}
+	obj, found, err := c.serviceImportInformer.GetTargetStore().GetByKey(clusterName, key)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get the ServiceImport %q from cluster %q", key, clusterName)
+	}
+	if !found {
+		return nil, nil
+	}
+	return clusterServiceImport(clusterName, obj.(*unstructured.Unstructured))
+}
+
+// updateStatus records the ServiceImports of the service in the status
+// of its federated resource.
+func (c *Controller) updateStatus(fedObj *unstructured.Unstructured, imports []status.ClusterServiceImport) error {
+	fedObj = fedObj.DeepCopy()
+	changed, err := status.SetServiceImports(fedObj, imports)
+	if err != nil || !changed {
+		return err
+	}
+	return c.client.UpdateStatus(context.TODO(), fedObj)
+}
+
+// newServiceExport returns the ServiceExport of a service.
+func newServiceExport(namespace, name string) *unstructured.Unstructured {
+	serviceExport := &unstructured.Unstructured{}
+	serviceExport.SetAPIVersion(multiClusterGroup + "/" + multiClusterVersion)
+	serviceExport.SetKind(serviceExportAPIResource.Kind)
+	serviceExport.SetNamespace(namespace)
+	serviceExport.SetName(name)
+	util.AddManagedLabel(serviceExport)
+	return serviceExport
+}
+
+// clusterServiceImport returns the status of the given ServiceImport
+// of a cluster.
+func clusterServiceImport(clusterName string, serviceImport *unstructured.Unstructured) (*status.ClusterServiceImport, error) {
+	importType, _, err := unstructured.NestedString(serviceImport.Object, "spec", "type")
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get the type of ServiceImport %s/%s", serviceImport.GetNamespace(), serviceImport.GetName())
+	}
+	ips, _, err := unstructured.NestedStringSlice(serviceImport.Object, "spec", "ips")
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get the IPs of ServiceImport %s/%s", serviceImport.GetNamespace(), serviceImport.GetName())
+	}
+	clusters, _, err := unstructured.NestedSlice(serviceImport.Object, "status", "clusters")
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get the clusters of ServiceImport %s/%s", serviceImport.GetNamespace(), serviceImport.GetName())
+	}
+	var exportingClusters []string
+	for _, cluster := range clusters {
+		clusterMap, ok := cluster.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if name, ok := clusterMap["cluster"].(string); ok && name != "" {
+			exportingClusters = append(exportingClusters, name)
+		}
+	}
+	sort.Strings(exportingClusters)
+	return &status.ClusterServiceImport{
+		Cluster:           clusterName,
+		Type:              importType,
+		IPs:               ips,
+		ExportingClusters: exportingClusters,
+	}, nil
+}
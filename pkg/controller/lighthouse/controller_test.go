@@ -0,0 +1,76 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lighthouse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"sigs.k8s.io/kubefed/pkg/controller/sync/status"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+func TestNewServiceExport(t *testing.T) {
+	serviceExport := newServiceExport("test-namespace", "test-service")
+	assert.Equal(t, "multicluster.x-k8s.io/v1alpha1", serviceExport.GetAPIVersion())
+	assert.Equal(t, "ServiceExport", serviceExport.GetKind())
+	assert.Equal(t, "test-namespace", serviceExport.GetNamespace())
+	assert.Equal(t, "test-service", serviceExport.GetName())
+	assert.True(t, util.HasManagedLabel(serviceExport))
+}
+
+func TestClusterServiceImport(t *testing.T) {
+	serviceImport := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"namespace": "test-namespace", "name": "test-service"},
+		"spec": map[string]interface{}{
+			"type": "ClusterSetIP",
+			"ips":  []interface{}{"243.0.0.17"},
+		},
+		"status": map[string]interface{}{
+			"clusters": []interface{}{
+				map[string]interface{}{"cluster": "cluster2"},
+				map[string]interface{}{"cluster": "cluster1"},
+				map[string]interface{}{},
+			},
+		},
+	}}
+	clusterImport, err := clusterServiceImport("cluster1", serviceImport)
+	assert.NoError(t, err)
+	assert.Equal(t, &status.ClusterServiceImport{
+		Cluster:           "cluster1",
+		Type:              "ClusterSetIP",
+		IPs:               []string{"243.0.0.17"},
+		ExportingClusters: []string{"cluster1", "cluster2"},
+	}, clusterImport)
+
+	// A headless import without status has no IPs or exporting clusters.
+	serviceImport = &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"type": "Headless"},
+	}}
+	clusterImport, err = clusterServiceImport("cluster2", serviceImport)
+	assert.NoError(t, err)
+	assert.Equal(t, &status.ClusterServiceImport{Cluster: "cluster2", Type: "Headless"}, clusterImport)
+
+	serviceImport = &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"ips": "243.0.0.17"},
+	}}
+	_, err = clusterServiceImport("cluster1", serviceImport)
+	assert.Error(t, err)
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package propagationquota
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/controller/util/quota"
+)
+
+const (
+	userAgentName = "propagation-quota-controller"
+
+	// statusUpdatePeriod is how often the usage of a quota, which
+	// changes as the sync controllers reconcile federated resources,
+	// is recorded in its status.
+	statusUpdatePeriod = 30 * time.Second
+)
+
+// Controller feeds the PropagationQuota resources of the KubeFed
+// system namespace to the quota tracker shared by the sync
+// controllers, and records the usage of the quotas in their status.
+type Controller struct {
+	client  genericclient.Client
+	tracker *quota.Tracker
+
+	// Store for the PropagationQuota objects
+	store cache.Store
+	// Informer for the PropagationQuota objects
+	controller cache.Controller
+
+	worker util.ReconcileWorker
+}
+
+// StartController starts the controller of propagation quotas.
+func StartController(config *util.ControllerConfig, stopChan <-chan struct{}) error {
+	controller, err := newController(config)
+	if err != nil {
+		return err
+	}
+	klog.Infof("Starting propagation quota controller")
+	controller.Run(stopChan)
+	return nil
+}
+
+func newController(config *util.ControllerConfig) (*Controller, error) {
+	c := &Controller{
+		client:  genericclient.NewForConfigOrDieWithUserAgent(config.KubeConfig, userAgentName),
+		tracker: config.PropagationQuotas,
+	}
+	c.worker = util.NewReconcileWorker(util.WorkerName{Controller: "propagationquota"}, c.reconcile, util.WorkerTiming{})
+
+	var err error
+	c.store, c.controller, err = util.NewGenericInformer(
+		config.KubeConfig,
+		config.KubeFedNamespace,
+		&fedv1b1.PropagationQuota{},
+		util.NoResyncPeriod,
+		c.worker.EnqueueObject,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Run runs the controller.  The tracker is marked synced once the
+// quotas that existed when the controller started have been set, so
+// that the sync controllers do not admit resources before then.
+func (c *Controller) Run(stopChan <-chan struct{}) {
+	go c.controller.Run(stopChan)
+	c.worker.Run(stopChan)
+
+	go func() {
+		if !cache.WaitForCacheSync(stopChan, c.controller.HasSynced) {
+			return
+		}
+		for _, obj := range c.store.List() {
+			quota := obj.(*fedv1b1.PropagationQuota)
+			c.tracker.SetQuota(quota.Name, quota.Spec)
+		}
+		c.tracker.SetSynced()
+	}()
+}
+
+func (c *Controller) reconcile(qualifiedName util.QualifiedName) util.ReconciliationStatus {
+	if !c.controller.HasSynced() {
+		return util.StatusNotSynced
+	}
+
+	key := qualifiedName.String()
+	obj, found, err := c.store.GetByKey(key)
+	if err != nil {
+		runtime.HandleError(errors.Wrapf(err, "Failed to query the PropagationQuota store for %q", key))
+		return util.StatusError
+	}
+	if !found {
+		c.tracker.RemoveQuota(qualifiedName.Name)
+		return util.StatusAllOK
+	}
+
+	propagationQuota := obj.(*fedv1b1.PropagationQuota)
+	c.tracker.SetQuota(propagationQuota.Name, propagationQuota.Spec)
+	if usage, ok := c.tracker.Usage(propagationQuota.Name); ok && usage != propagationQuota.Status {
+		updatedQuota := propagationQuota.DeepCopy()
+		updatedQuota.Status = usage
+		if err := c.client.UpdateStatus(context.TODO(), updatedQuota); err != nil {
+			runtime.HandleError(errors.Wrapf(err, "Failed to update the status of PropagationQuota %q", key))
+			return util.StatusError
+		}
+	}
+
+	c.worker.EnqueueWithDelay(qualifiedName, statusUpdatePeriod)
+	return util.StatusAllOK
+}
@@ -17,12 +17,20 @@ limitations under the License.
 package schedulingmanager
 
 import (
+	"context"
+
 	"github.com/pkg/errors"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/runtime"
-	restclient "k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog"
+	crcache "sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	corev1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
 	"sigs.k8s.io/kubefed/pkg/controller/schedulingpreference"
@@ -31,12 +39,10 @@ import (
 )
 
 type SchedulingManager struct {
-	// Store for the FederatedTypeConfig objects
-	store cache.Store
+	// Cache of the manager holding the FederatedTypeConfig objects
+	cache crcache.Cache
 	// Informer for the FederatedTypeConfig objects
-	controller cache.Controller
-
-	worker util.ReconcileWorker
+	informer cache.SharedIndexInformer
 
 	//schedulers map[string]*SchedulerWrapper
 	schedulers *util.SafeMap
@@ -61,15 +67,44 @@ func (s *SchedulerWrapper) HasPlugin(typeConfigName string) bool {
 	return ok
 }
 
+// StartSchedulingManager starts the scheduling manager.  The manager
+// is registered with the controller-runtime manager of the given
+// config, or with a manager of its own if the config has none.
 func StartSchedulingManager(config *util.ControllerConfig, stopChan <-chan struct{}) (*SchedulingManager, error) {
-	manager, err := newSchedulingManager(config)
+	mgr, shared, err := util.ManagerFor(config)
+	if err != nil {
+		return nil, err
+	}
+	c, err := newSchedulingManager(config, mgr)
 	if err != nil {
 		return nil, err
 	}
 
 	klog.Infof("Starting scheduling manager")
-	manager.Run(stopChan)
-	return manager, nil
+	ctrl, err := controller.New("schedulingmanager", mgr, controller.Options{
+		Reconciler: util.NewReconciler(util.WorkerName{Controller: "schedulingmanager"}, c.reconcile, util.WorkerTiming{}),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to register the scheduling manager")
+	}
+	err = ctrl.Watch(&source.Kind{Type: &corev1b1.FederatedTypeConfig{}}, &handler.EnqueueRequestForObject{},
+		util.NamespacePredicate(config.KubeFedNamespace))
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to watch FederatedTypeConfigs in the scheduling manager")
+	}
+
+	go func() {
+		<-stopChan
+		c.shutdown()
+	}()
+
+	if !shared {
+		util.StartManager(mgr, stopChan)
+		if !cache.WaitForCacheSync(stopChan, c.informer.HasSynced) {
+			return nil, errors.New("Timed out waiting for cache to sync in scheduling manager")
+		}
+	}
+	return c, nil
 }
 
 func newSchedulerWrapper(schedulerInterface schedulingtypes.Scheduler, stopChan chan struct{}) *SchedulerWrapper {
@@ -80,31 +115,20 @@ func newSchedulerWrapper(schedulerInterface schedulingtypes.Scheduler, stopChan
 	}
 }
 
-func newSchedulingManager(config *util.ControllerConfig) (*SchedulingManager, error) {
-	userAgent := "SchedulingManager"
-	kubeConfig := restclient.CopyConfig(config.KubeConfig)
-	restclient.AddUserAgent(kubeConfig, userAgent)
-
-	c := &SchedulingManager{
-		config:     config,
-		schedulers: util.NewSafeMap(),
-	}
-
-	c.worker = util.NewReconcileWorker(c.reconcile, util.WorkerTiming{})
-
-	var err error
-	c.store, c.controller, err = util.NewGenericInformer(
-		kubeConfig,
-		config.KubeFedNamespace,
-		&corev1b1.FederatedTypeConfig{},
-		util.NoResyncPeriod,
-		c.worker.EnqueueObject,
-	)
+func newSchedulingManager(config *util.ControllerConfig, mgr manager.Manager) (*SchedulingManager, error) {
+	// The informer is retrieved so that the cache of the manager
+	// watches FederatedTypeConfigs once it is started.
+	informer, err := mgr.GetCache().GetInformer(&corev1b1.FederatedTypeConfig{})
 	if err != nil {
-		return nil, err
+		return nil, errors.Wrap(err, "Failed to get the FederatedTypeConfig informer")
 	}
 
-	return c, nil
+	return &SchedulingManager{
+		cache:      mgr.GetCache(),
+		informer:   informer,
+		config:     config,
+		schedulers: util.NewSafeMap(),
+	}, nil
 }
 
 func (c *SchedulingManager) GetScheduler(schedulingKind string) *SchedulerWrapper {
@@ -115,24 +139,6 @@ func (c *SchedulingManager) GetScheduler(schedulingKind string) *SchedulerWrappe
 	return scheduler.(*SchedulerWrapper)
 }
 
-// Run runs the Controller.
-func (c *SchedulingManager) Run(stopChan <-chan struct{}) {
-	go c.controller.Run(stopChan)
-
-	// wait for the caches to synchronize before starting the worker
-	if !cache.WaitForCacheSync(stopChan, c.controller.HasSynced) {
-		runtime.HandleError(errors.New("Timed out waiting for cache to sync in scheduling manager"))
-		return
-	}
-
-	c.worker.Run(stopChan)
-
-	go func() {
-		<-stopChan
-		c.shutdown()
-	}()
-}
-
 func (c *SchedulingManager) shutdown() {
 	for _, scheduler := range c.schedulers.GetAll() {
 		// Indicate scheduler and associated goroutines to be stopped in schedulingpreference controller.
@@ -153,19 +159,20 @@ func (c *SchedulingManager) reconcile(qualifiedName util.QualifiedName) util.Rec
 	}
 	schedulingKind := schedulingType.Kind
 
-	cachedObj, exist, err := c.store.GetByKey(key)
+	typeConfig := &corev1b1.FederatedTypeConfig{}
+	err := c.cache.Get(context.TODO(), types.NamespacedName{Namespace: qualifiedName.Namespace, Name: qualifiedName.Name}, typeConfig)
+	if apierrors.IsNotFound(err) {
+		c.stopScheduler(schedulingKind, typeConfigName)
+		return util.StatusAllOK
+	}
 	if err != nil {
 		runtime.HandleError(errors.Wrapf(err, "Failed to query FederatedTypeConfig store for %q in scheduling manager", key))
 		return util.StatusError
 	}
 
-	if !exist {
-		c.stopScheduler(schedulingKind, typeConfigName)
-		return util.StatusAllOK
-	}
-
-	typeConfig := cachedObj.(*corev1b1.FederatedTypeConfig)
-	if !typeConfig.GetPropagationEnabled() || typeConfig.DeletionTimestamp != nil {
+	// Schedulers only update federated resources in the host cluster
+	// and so continue to run while propagation is paused.
+	if !(typeConfig.GetPropagationEnabled() || typeConfig.GetPropagationPaused()) || typeConfig.DeletionTimestamp != nil {
 		c.stopScheduler(schedulingKind, typeConfigName)
 		return util.StatusAllOK
 	}
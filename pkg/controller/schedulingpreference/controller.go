@@ -26,14 +26,15 @@ import (
 	pkgruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	kubeclientset "k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/kubernetes/scheme"
 	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	restclient "k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/kubefed/pkg/client/generic/scheme"
 	"sigs.k8s.io/kubefed/pkg/controller/util"
 	"sigs.k8s.io/kubefed/pkg/schedulingtypes"
 )
@@ -71,7 +72,11 @@ type SchedulingPreferenceController struct {
 
 // SchedulingPreferenceController starts a new controller for given type of SchedulingPreferences
 func StartSchedulingPreferenceController(config *util.ControllerConfig, schedulingType schedulingtypes.SchedulingType, stopChannel <-chan struct{}) (schedulingtypes.Scheduler, error) {
-	controller, err := newSchedulingPreferenceController(config, schedulingType)
+	mgr, shared, err := util.ManagerFor(config)
+	if err != nil {
+		return nil, err
+	}
+	controller, err := newSchedulingPreferenceController(config, mgr, schedulingType, stopChannel)
 	if err != nil {
 		return nil, err
 	}
@@ -80,11 +85,17 @@ func StartSchedulingPreferenceController(config *util.ControllerConfig, scheduli
 	}
 	klog.Infof(fmt.Sprintf("Starting replicaschedulingpreferences controller"))
 	controller.Run(stopChannel)
+	if !shared {
+		util.StartManager(mgr, stopChannel)
+	}
 	return controller.scheduler, nil
 }
 
 // newSchedulingPreferenceController returns a new SchedulingPreference Controller for the given type
-func newSchedulingPreferenceController(config *util.ControllerConfig, schedulingType schedulingtypes.SchedulingType) (*SchedulingPreferenceController, error) {
+// that watches scheduling preferences with the informer of the given manager until the stop channel
+// is closed.
+func newSchedulingPreferenceController(config *util.ControllerConfig, mgr manager.Manager, schedulingType schedulingtypes.SchedulingType,
+	stopChannel <-chan struct{}) (*SchedulingPreferenceController, error) {
 	userAgent := fmt.Sprintf("%s-controller", schedulingType.Kind)
 	kubeConfig := restclient.CopyConfig(config.KubeConfig)
 	restclient.AddUserAgent(kubeConfig, userAgent)
@@ -105,7 +116,7 @@ func newSchedulingPreferenceController(config *util.ControllerConfig, scheduling
 		eventRecorder:           recorder,
 	}
 
-	s.worker = util.NewReconcileWorker(s.reconcile, util.WorkerTiming{
+	s.worker = util.NewReconcileWorker(util.WorkerName{Controller: "schedulingpreference"}, s.reconcile, util.WorkerTiming{
 		ClusterSyncDelay: s.clusterAvailableDelay,
 	})
 
@@ -115,6 +126,7 @@ func newSchedulingPreferenceController(config *util.ControllerConfig, scheduling
 			qualifiedName := util.NewQualifiedName(obj)
 			s.worker.EnqueueForRetry(qualifiedName)
 		},
+		EventRecorder: recorder,
 		ClusterLifecycleHandlers: &util.ClusterLifecycleHandlerFuncs{
 			ClusterAvailable: func(cluster *fedv1b1.KubeFedCluster) {
 				// When new cluster becomes available process all the target resources again.
@@ -124,6 +136,10 @@ func newSchedulingPreferenceController(config *util.ControllerConfig, scheduling
 			ClusterUnavailable: func(cluster *fedv1b1.KubeFedCluster, _ []interface{}) {
 				s.clusterDeliverer.DeliverAt(allClustersKey, nil, time.Now().Add(s.clusterUnavailableDelay))
 			},
+			// Preferences may take their weight from a cluster label.
+			ClusterLabelsChanged: func(cluster *fedv1b1.KubeFedCluster) {
+				s.clusterDeliverer.DeliverAt(allClustersKey, nil, time.Now())
+			},
 		},
 	}
 	scheduler, err := schedulingType.SchedulerFactory(config, eventHandlers)
@@ -135,12 +151,21 @@ func newSchedulingPreferenceController(config *util.ControllerConfig, scheduling
 	// Build deliverer for triggering cluster reconciliations.
 	s.clusterDeliverer = util.NewDelayingDeliverer()
 
-	s.store, s.controller, err = util.NewGenericInformer(
-		config.KubeConfig,
+	// The informer is shared by the controllers of the manager and
+	// outlives this controller, which may be stopped and started again
+	// as FederatedTypeConfigs are disabled and enabled.
+	s.store, s.controller, err = util.NewManagerInformer(
+		mgr,
 		config.TargetNamespace,
 		s.scheduler.ObjectType(),
-		util.NoResyncPeriod,
-		s.worker.EnqueueObject,
+		func(obj pkgruntime.Object) {
+			select {
+			case <-stopChannel:
+				return
+			default:
+			}
+			s.worker.EnqueueObject(obj)
+		},
 	)
 	if err != nil {
 		return nil, err
@@ -159,7 +184,6 @@ func (s *SchedulingPreferenceController) minimizeLatency() {
 }
 
 func (s *SchedulingPreferenceController) Run(stopChan <-chan struct{}) {
-	go s.controller.Run(stopChan)
 	s.scheduler.Start()
 
 	s.clusterDeliverer.StartWithHandler(func(_ *util.DelayingDelivererItem) {
@@ -210,7 +234,11 @@ func (s *SchedulingPreferenceController) reconcile(qualifiedName util.QualifiedN
 		return util.StatusAllOK
 	}
 	if obj == nil {
-		// Nothing to do
+		// The scheduler may report the decisions made for the deleted
+		// scheduling preference.
+		if forgetter, ok := s.scheduler.(interface{ Forget(util.QualifiedName) }); ok {
+			forgetter.Forget(qualifiedName)
+		}
 		return util.StatusAllOK
 	}
 
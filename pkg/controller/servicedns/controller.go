@@ -30,12 +30,15 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	pkgruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
 	dnsv1a1 "sigs.k8s.io/kubefed/pkg/apis/multiclusterdns/v1alpha1"
 	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	"sigs.k8s.io/kubefed/pkg/controller/dnsendpoint"
 	"sigs.k8s.io/kubefed/pkg/controller/util"
 )
 
@@ -78,7 +81,11 @@ type Controller struct {
 
 // StartController starts the Controller for managing ServiceDNSRecord objects.
 func StartController(config *util.ControllerConfig, stopChan <-chan struct{}) error {
-	controller, err := newController(config)
+	mgr, shared, err := util.ManagerFor(config)
+	if err != nil {
+		return err
+	}
+	controller, err := newController(config, mgr)
 	if err != nil {
 		return err
 	}
@@ -87,11 +94,16 @@ func StartController(config *util.ControllerConfig, stopChan <-chan struct{}) er
 	}
 	klog.Infof("Starting ServiceDNS controller")
 	controller.Run(stopChan)
+	if !shared {
+		util.StartManager(mgr, stopChan)
+	}
 	return nil
 }
 
-// newController returns a new controller to manage ServiceDNSRecord objects.
-func newController(config *util.ControllerConfig) (*Controller, error) {
+// newController returns a new controller to manage ServiceDNSRecord objects
+// that watches the objects of the host cluster with the informers of the
+// given manager.
+func newController(config *util.ControllerConfig, mgr manager.Manager) (*Controller, error) {
 	client := genericclient.NewForConfigOrDieWithUserAgent(config.KubeConfig, "ServiceDNS")
 	s := &Controller{
 		client:                  client,
@@ -101,7 +113,7 @@ func newController(config *util.ControllerConfig) (*Controller, error) {
 		fedNamespace:            config.KubeFedNamespace,
 	}
 
-	s.worker = util.NewReconcileWorker(s.reconcile, util.WorkerTiming{
+	s.worker = util.NewReconcileWorker(util.WorkerName{Controller: "servicedns"}, s.reconcile, util.WorkerTiming{
 		ClusterSyncDelay: s.clusterAvailableDelay,
 	})
 
@@ -110,11 +122,10 @@ func newController(config *util.ControllerConfig) (*Controller, error) {
 
 	// Informer for ServiceDNSRecord resources in the host cluster
 	var err error
-	s.serviceDNSStore, s.serviceDNSController, err = util.NewGenericInformer(
-		config.KubeConfig,
+	s.serviceDNSStore, s.serviceDNSController, err = util.NewManagerInformer(
+		mgr,
 		config.TargetNamespace,
 		&dnsv1a1.ServiceDNSRecord{},
-		util.NoResyncPeriod,
 		s.worker.EnqueueObject,
 	)
 	if err != nil {
@@ -122,11 +133,10 @@ func newController(config *util.ControllerConfig) (*Controller, error) {
 	}
 
 	// Informer for the Domain resource
-	s.domainStore, s.domainController, err = util.NewGenericInformer(
-		config.KubeConfig,
+	s.domainStore, s.domainController, err = util.NewManagerInformer(
+		mgr,
 		config.KubeFedNamespace,
 		&dnsv1a1.Domain{},
-		util.NoResyncPeriod,
 		func(pkgruntime.Object) {
 			s.clusterDeliverer.DeliverAt(allClustersKey, nil, time.Now())
 		},
@@ -194,8 +204,6 @@ func (c *Controller) minimizeLatency() {
 
 // Run runs the Controller.
 func (c *Controller) Run(stopChan <-chan struct{}) {
-	go c.serviceDNSController.Run(stopChan)
-	go c.domainController.Run(stopChan)
 	c.serviceInformer.Start()
 	c.endpointInformer.Start()
 	c.clusterDeliverer.StartWithHandler(func(_ *util.DelayingDelivererItem) {
@@ -216,6 +224,10 @@ func (c *Controller) Run(stopChan <-chan struct{}) {
 // Check whether all data stores are in sync. False is returned if any of the serviceInformer/stores is not yet
 // synced with the corresponding api server.
 func (c *Controller) isSynced() bool {
+	if !c.serviceDNSController.HasSynced() || !c.domainController.HasSynced() {
+		klog.V(2).Infof("ServiceDNSRecord or Domain informer not synced")
+		return false
+	}
 	if !c.serviceInformer.ClustersSynced() {
 		klog.V(2).Infof("Cluster list not synced")
 		return false
@@ -303,14 +315,17 @@ func (c *Controller) reconcile(qualifiedName util.QualifiedName) util.Reconcilia
 	// Iterate through all ready clusters and aggregate the service status for the key
 	for _, cluster := range clusters {
 		if cluster.Status.Region == "" || len(cluster.Status.Zones) == 0 {
-			runtime.HandleError(errors.Wrapf(err, "Cluster %q does not have Region or Zones Attributes", cluster.Name))
-			return util.StatusError
+			// Only global records are written for the cluster.
+			klog.V(4).Infof("Cluster %q does not have a region or zones for ServiceDNS resource %v", cluster.Name, key)
 		}
 		clusterDNS := dnsv1a1.ClusterDNS{
 			Cluster: cluster.Name,
 			Region:  cluster.Status.Region,
 			Zones:   cluster.Status.Zones,
 		}
+		if !inLocality(&clusterDNS, &cachedDNS.Spec) {
+			continue
+		}
 
 		// If there are no endpoints for the service, the service is not backed by pods
 		// and traffic is not routable to the service. We avoid such service shards while
@@ -340,7 +355,7 @@ func (c *Controller) reconcile(qualifiedName util.QualifiedName) util.Reconcilia
 	}
 	for _, cluster := range offlineClusters {
 		for _, clusterDNS := range fedDNS.Status.DNS {
-			if clusterDNS.Cluster == cluster.Name {
+			if clusterDNS.Cluster == cluster.Name && inLocality(&clusterDNS, &cachedDNS.Spec) {
 				offlineClusterDNS := clusterDNS
 				offlineClusterDNS.LoadBalancer = corev1.LoadBalancerStatus{}
 				fedDNSStatus = append(fedDNSStatus, offlineClusterDNS)
@@ -355,6 +370,9 @@ func (c *Controller) reconcile(qualifiedName util.QualifiedName) util.Reconcilia
 	})
 	fedDNS.Status.DNS = fedDNSStatus
 	fedDNS.Status.Domain = domainObj.Domain
+	fedDNS.Status.RecordTTL = domainObj.RecordTTL
+	fedDNS.Status.ProviderSpecific = domainObj.ProviderSpecific
+	fedDNS.Status.DomainAnnotations = dnsendpoint.PassthroughAnnotations(domainObj.Annotations)
 
 	if !reflect.DeepEqual(cachedDNS.Status, fedDNS.Status) {
 		err = c.client.UpdateStatus(context.TODO(), fedDNS)
@@ -367,6 +385,21 @@ func (c *Controller) reconcile(qualifiedName util.QualifiedName) util.Reconcilia
 	return util.StatusAllOK
 }
 
+// inLocality indicates whether the cluster of the given ClusterDNS is
+// in the regions and zones the spec restricts the DNS records to.  The
+// zones of the ClusterDNS are restricted to those of the spec.
+func inLocality(clusterDNS *dnsv1a1.ClusterDNS, spec *dnsv1a1.ServiceDNSRecordSpec) bool {
+	if len(spec.Regions) > 0 && !sets.NewString(spec.Regions...).Has(clusterDNS.Region) {
+		return false
+	}
+	if len(spec.Zones) == 0 {
+		return true
+	}
+	zones := sets.NewString(spec.Zones...).Intersection(sets.NewString(clusterDNS.Zones...))
+	clusterDNS.Zones = zones.List()
+	return len(clusterDNS.Zones) > 0
+}
+
 // getServiceStatusInCluster returns service status in federated cluster
 func (c *Controller) getServiceStatusInCluster(cluster, key string) (*corev1.LoadBalancerStatus, error) {
 	lbStatus := &corev1.LoadBalancerStatus{}
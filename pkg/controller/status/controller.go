@@ -74,6 +74,10 @@ type KubeFedStatusController struct {
 	client       genericclient.Client
 	statusClient util.ResourceClient
 
+	// Defers the status updates of resources whose status was
+	// recently updated
+	statusUpdates *util.StatusUpdateCoalescer
+
 	fedNamespace string
 }
 
@@ -115,10 +119,11 @@ func newKubeFedStatusController(controllerConfig *util.ControllerConfig, typeCon
 		typeConfig:              typeConfig,
 		client:                  client,
 		statusClient:            statusClient,
+		statusUpdates:           util.NewStatusUpdateCoalescer(controllerConfig.StatusUpdateIntervalFor(typeConfig)),
 		fedNamespace:            controllerConfig.KubeFedNamespace,
 	}
 
-	s.worker = util.NewReconcileWorker(s.reconcile, util.WorkerTiming{
+	s.worker = util.NewReconcileWorker(util.WorkerName{Controller: "status", TypeConfig: typeConfig.GetObjectMeta().Name}, s.reconcile, util.WorkerTiming{
 		ClusterSyncDelay: s.clusterAvailableDelay,
 	})
 
@@ -136,14 +141,16 @@ func newKubeFedStatusController(controllerConfig *util.ControllerConfig, typeCon
 	targetAPIResource := typeConfig.GetTargetType()
 
 	// Federated informer for resources in member clusters
-	s.informer, err = util.NewFederatedInformer(
+	s.informer, err = util.NewFederatedInformerForVersions(
 		controllerConfig,
 		client,
 		&targetAPIResource,
+		typeConfig.GetTargetVersions(),
 		func(obj pkgruntime.Object) {
 			qualifiedName := util.NewQualifiedName(obj)
 			s.worker.EnqueueForRetry(qualifiedName)
 		},
+		nil,
 		&util.ClusterLifecycleHandlerFuncs{
 			ClusterAvailable: func(cluster *fedv1b1.KubeFedCluster) {
 				// When new cluster becomes available process all the target resources again.
@@ -257,7 +264,15 @@ func (s *KubeFedStatusController) reconcile(qualifiedName util.QualifiedName) ut
 		return util.StatusNotSynced
 	}
 
-	clusterStatus, err := s.clusterStatuses(clusterNames, key)
+	// Resources may have been renamed in member clusters by an
+	// override.
+	overridesMap, err := util.GetOverrides(fedObject)
+	if err != nil {
+		runtime.HandleError(errors.Wrapf(err, "Failed to get overrides for %s %q", federatedKind, key))
+		return util.StatusError
+	}
+
+	clusterStatus, err := s.clusterStatuses(clusterNames, qualifiedName, overridesMap)
 	if err != nil {
 		return util.StatusError
 	}
@@ -294,13 +309,25 @@ func (s *KubeFedStatusController) reconcile(qualifiedName util.QualifiedName) ut
 		return util.StatusError
 	}
 
+	if existingStatus != nil && reflect.DeepEqual(existingStatus.Object["clusterStatus"], status.Object["clusterStatus"]) {
+		return util.StatusAllOK
+	}
+
+	if delay := s.statusUpdates.Delay(qualifiedName, util.RefreshStatusToken(fedObject)); delay > 0 {
+		// The status will reflect the cluster status observed once
+		// the interval since the last update elapses.
+		klog.V(4).Infof("Deferring the update of %s %q by %v", statusKind, key, delay)
+		s.worker.EnqueueWithDelay(qualifiedName, delay)
+		return util.StatusAllOK
+	}
+
 	if existingStatus == nil {
 		_, err = s.statusClient.Resources(qualifiedName.Namespace).Create(status, metav1.CreateOptions{})
 		if err != nil {
 			runtime.HandleError(errors.Wrapf(err, "Failed to create status object for federated type %s %q", statusKind, key))
 			return util.StatusNeedsRecheck
 		}
-	} else if !reflect.DeepEqual(existingStatus.Object["clusterStatus"], status.Object["clusterStatus"]) {
+	} else {
 		if status.Object["clusterStatus"] == nil {
 			status.Object["clusterStatus"] = make([]util.ResourceClusterStatus, 0)
 		}
@@ -353,11 +380,21 @@ func (s *KubeFedStatusController) clusterNames() ([]string, error) {
 }
 
 // clusterStatuses returns the resource status in member cluster.
-func (s *KubeFedStatusController) clusterStatuses(clusterNames []string, key string) ([]util.ResourceClusterStatus, error) {
+func (s *KubeFedStatusController) clusterStatuses(clusterNames []string, qualifiedName util.QualifiedName, overridesMap util.OverridesMap) ([]util.ResourceClusterStatus, error) {
 	clusterStatus := []util.ResourceClusterStatus{}
 
 	targetKind := s.typeConfig.GetTargetType().Kind
 	for _, clusterName := range clusterNames {
+		targetName := qualifiedName
+		if s.typeConfig.IsNamespace() {
+			// The federated namespace is contained by the namespace
+			// it propagates.
+			targetName.Namespace = ""
+		}
+		if name, ok := overridesMap[clusterName][util.NameOverridePath].(string); ok {
+			targetName.Name = name
+		}
+		key := targetName.String()
 		clusterObj, exist, err := s.informer.GetTargetStore().GetByKey(clusterName, key)
 		if err != nil {
 			wrappedErr := errors.Wrapf(err, "Failed to get %s %q from cluster %q", targetKind, key, clusterName)
@@ -17,19 +17,32 @@ limitations under the License.
 package sync
 
 import (
+	"time"
+
+	"github.com/pkg/errors"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog"
 
 	"sigs.k8s.io/kubefed/pkg/apis/core/typeconfig"
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
 	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
 	"sigs.k8s.io/kubefed/pkg/controller/sync/version"
 	"sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/features"
 )
 
+// The interval at which propagated versions that are orphaned or that
+// reference unjoined clusters are garbage collected.
+const versionGCPeriod = 10 * time.Minute
+
 // FederatedResourceAccessor provides a way to retrieve and visit
 // logical federated resources (e.g. FederatedConfigMap)
 type FederatedResourceAccessor interface {
@@ -45,6 +58,16 @@ type resourceAccessor struct {
 	targetIsNamespace bool
 	fedNamespace      string
 
+	// Whether the federated type is the generic FederatedObject type,
+	// in which case only the federated resources whose template is of
+	// the target type are handled.
+	genericFederatedType bool
+
+	// The names of the resources of a cluster-scoped type that a
+	// namespace-scoped control plane may propagate.  Nil if the
+	// resources are not limited by name.
+	allowedNames sets.String
+
 	// The informer for the federated type.
 	federatedStore      cache.Store
 	federatedController cache.Controller
@@ -68,6 +91,24 @@ type resourceAccessor struct {
 
 	// Records events on the federated resource
 	eventRecorder record.EventRecorder
+
+	// Provides the namespace mappings of member clusters
+	clusters util.RegisteredClustersView
+
+	// Labels and annotations injected into propagated resources
+	propagationMetadata *fedv1b1.PropagationMetadataConfig
+
+	// Labels and annotations of host namespaces synced to propagated
+	// namespaces
+	namespaceMetadata *fedv1b1.NamespaceMetadataConfig
+
+	// Retrieves the ConfigMaps and Secrets referenced by the value
+	// sources of overrides.  Nil if the OverrideValueSources feature
+	// is disabled.
+	hostClient genericclient.Client
+
+	// Enqueues a federated resource for reconciliation
+	enqueueObj func(pkgruntime.Object)
 }
 
 func NewFederatedResourceAccessor(
@@ -75,6 +116,7 @@ func NewFederatedResourceAccessor(
 	typeConfig typeconfig.Interface,
 	fedNamespaceAPIResource *metav1.APIResource,
 	client genericclient.Client,
+	clusters util.RegisteredClustersView,
 	enqueueObj func(pkgruntime.Object),
 	eventRecorder record.EventRecorder) (FederatedResourceAccessor, error) {
 
@@ -85,6 +127,25 @@ func NewFederatedResourceAccessor(
 		fedNamespace:            controllerConfig.KubeFedNamespace,
 		fedNamespaceAPIResource: fedNamespaceAPIResource,
 		eventRecorder:           eventRecorder,
+		clusters:                clusters,
+		propagationMetadata:     controllerConfig.PropagationMetadata,
+		namespaceMetadata:       controllerConfig.NamespaceMetadata,
+		enqueueObj:              enqueueObj,
+		genericFederatedType:    typeconfig.IsGenericFederatedType(typeConfig),
+	}
+	if utilfeature.DefaultFeatureGate.Enabled(features.OverrideValueSources) {
+		a.hostClient = client
+	}
+
+	if a.limitedScope && !a.targetIsNamespace && !typeConfig.GetNamespaced() {
+		a.allowedNames = controllerConfig.AllowedClusterScopedNames(typeConfig.GetTargetType())
+		if a.allowedNames == nil {
+			a.allowedNames = sets.NewString()
+		}
+	}
+
+	if a.genericFederatedType && (a.targetIsNamespace || !typeConfig.GetNamespaced()) {
+		return nil, errors.Errorf("%s only supports namespaced target types other than namespaces", typeconfig.GenericFederatedKind)
 	}
 
 	targetNamespace := controllerConfig.TargetNamespace
@@ -94,7 +155,11 @@ func NewFederatedResourceAccessor(
 	if err != nil {
 		return nil, err
 	}
-	a.federatedStore, a.federatedController = util.NewResourceInformer(federatedTypeClient, targetNamespace, enqueueObj)
+	a.federatedStore, a.federatedController = util.NewResourceInformer(federatedTypeClient, targetNamespace, func(obj pkgruntime.Object) {
+		if a.handlesFederatedResource(obj.(*unstructured.Unstructured)) {
+			enqueueObj(obj)
+		}
+	})
 
 	if a.targetIsNamespace {
 		// Initialize an informer for namespaces.  The namespace
@@ -118,9 +183,9 @@ func NewFederatedResourceAccessor(
 			// placement for the federated namespace.
 			namespace := util.NewQualifiedName(fedNamespaceObj).Namespace
 			for _, rawObj := range a.federatedStore.List() {
-				obj := rawObj.(pkgruntime.Object)
+				obj := rawObj.(*unstructured.Unstructured)
 				qualifiedName := util.NewQualifiedName(obj)
-				if qualifiedName.Namespace == namespace {
+				if qualifiedName.Namespace == namespace && a.handlesFederatedResource(obj) {
 					enqueueObj(obj)
 				}
 			}
@@ -155,6 +220,7 @@ func (a *resourceAccessor) Run(stopChan <-chan struct{}) {
 	if a.fedNamespaceController != nil {
 		go a.fedNamespaceController.Run(stopChan)
 	}
+	go wait.Until(a.collectVersionGarbage, versionGCPeriod, stopChan)
 }
 
 func (a *resourceAccessor) HasSynced() bool {
@@ -183,6 +249,10 @@ func (a *resourceAccessor) FederatedResource(eventSource util.QualifiedName) (Fe
 		klog.V(7).Infof("Ignoring system namespace %q", eventSource.Name)
 		return nil, false, nil
 	}
+	if a.allowedNames != nil && !a.allowedNames.Has(eventSource.Name) {
+		klog.V(7).Infof("Ignoring %s %q that is not allowed for a namespace-scoped control plane", a.typeConfig.GetTargetType().Kind, eventSource.Name)
+		return nil, false, nil
+	}
 
 	kind := a.typeConfig.GetFederatedType().Kind
 
@@ -225,6 +295,11 @@ func (a *resourceAccessor) FederatedResource(eventSource util.QualifiedName) (Fe
 	if err != nil {
 		return nil, false, err
 	}
+	if resource != nil && !a.handlesFederatedResource(resource) {
+		// A generic federated resource for another target type is
+		// treated as missing.
+		resource = nil
+	}
 	if resource == nil {
 		// If the target is a namespace and the event source has a
 		// namespace, the event source is guaranteed to be a
@@ -261,6 +336,11 @@ func (a *resourceAccessor) FederatedResource(eventSource util.QualifiedName) (Fe
 		// will be removed.
 	}
 
+	var valueResolver *util.OverrideValueResolver
+	if a.hostClient != nil {
+		valueResolver = util.NewOverrideValueResolver(a.hostClient, a.fedNamespace)
+	}
+
 	return &federatedResource{
 		limitedScope:      a.limitedScope,
 		typeConfig:        a.typeConfig,
@@ -273,13 +353,66 @@ func (a *resourceAccessor) FederatedResource(eventSource util.QualifiedName) (Fe
 		namespace:         namespace,
 		fedNamespace:      fedNamespace,
 		eventRecorder:     a.eventRecorder,
+		clusters:          a.clusters,
+		valueResolver:     valueResolver,
+
+		propagationMetadata: a.propagationMetadata,
+		namespaceMetadata:   a.namespaceMetadata,
 	}, false, nil
 }
 
 func (a *resourceAccessor) VisitFederatedResources(visitFunc func(obj interface{})) {
 	for _, obj := range a.federatedStore.List() {
-		visitFunc(obj)
+		if a.handlesFederatedResource(obj.(*unstructured.Unstructured)) {
+			visitFunc(obj)
+		}
+	}
+}
+
+// collectVersionGarbage deletes orphaned propagated versions and
+// enqueues the federated resources whose versions reference unjoined
+// clusters.  Reconciling such a resource records versions only for
+// registered clusters.
+func (a *resourceAccessor) collectVersionGarbage() {
+	if !a.HasSynced() || !a.clusters.ClustersSynced() {
+		return
+	}
+	clusters, err := a.clusters.GetClusters()
+	if err != nil {
+		klog.Errorf("Failed to retrieve clusters for garbage collection of propagated versions: %v", err)
+		return
+	}
+	clusterNames := sets.NewString()
+	for _, cluster := range clusters {
+		clusterNames.Insert(cluster.Name)
+	}
+
+	lookup := func(qualifiedName util.QualifiedName) (*unstructured.Unstructured, error) {
+		obj, exists, err := a.federatedStore.GetByKey(qualifiedName.String())
+		if err != nil || !exists {
+			return nil, err
+		}
+		fedObj := obj.(*unstructured.Unstructured)
+		if !a.handlesFederatedResource(fedObj) {
+			return nil, nil
+		}
+		return fedObj, nil
+	}
+	for _, obj := range a.versionManager.CollectGarbage(lookup, clusterNames) {
+		a.enqueueObj(obj)
+	}
+}
+
+// handlesFederatedResource indicates whether the given federated
+// resource is handled by the accessor.  All resources of a dedicated
+// federated type are handled unless their names are not allowed for a
+// namespace-scoped control plane, but a generic federated resource is
+// only handled if its template is of the target type.
+func (a *resourceAccessor) handlesFederatedResource(fedObj *unstructured.Unstructured) bool {
+	if a.allowedNames != nil && !a.allowedNames.Has(fedObj.GetName()) {
+		return false
 	}
+	return !a.genericFederatedType || util.TemplateTargetsType(fedObj, a.typeConfig.GetTargetType())
 }
 
 func (a *resourceAccessor) isSystemNamespace(namespace string) bool {
@@ -0,0 +1,64 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// conflictMessage describes the conflicts of a federated resource
+// with other federated resources that target the same resources in
+// member clusters.  ownersByCluster maps the clusters in which the
+// resource could not be managed to the federated resource managing
+// it, and contenders maps the federated resources targeting the
+// resources managed by the federated resource to their clusters.
+// The message is empty if there are no conflicts.
+func conflictMessage(targetKind string, ownersByCluster map[string]string, contenders map[string][]string) string {
+	owners := make(map[string][]string)
+	for clusterName, owner := range ownersByCluster {
+		owners[owner] = append(owners[owner], clusterName)
+	}
+
+	messages := []string{}
+	for _, owner := range sortedKeys(owners) {
+		messages = append(messages, fmt.Sprintf("%s in %s is managed by %s", targetKind, clusterList(owners[owner]), owner))
+	}
+	for _, contender := range sortedKeys(contenders) {
+		messages = append(messages, fmt.Sprintf("%s in %s is also targeted by %s", targetKind, clusterList(contenders[contender]), contender))
+	}
+	return strings.Join(messages, "; ")
+}
+
+func sortedKeys(clustersByKey map[string][]string) []string {
+	keys := make([]string, 0, len(clustersByKey))
+	for key := range clustersByKey {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func clusterList(clusterNames []string) string {
+	names := append([]string{}, clusterNames...)
+	sort.Strings(names)
+	if len(names) == 1 {
+		return fmt.Sprintf("cluster %s", names[0])
+	}
+	return fmt.Sprintf("clusters %s", strings.Join(names, ", "))
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConflictMessage(t *testing.T) {
+	testCases := map[string]struct {
+		ownersByCluster map[string]string
+		contenders      map[string][]string
+		expectedMessage string
+	}{
+		"No conflicts": {},
+		"Managed by another federated resource": {
+			ownersByCluster: map[string]string{
+				"cluster2": "FederatedConfigMap/ns/b",
+				"cluster1": "FederatedConfigMap/ns/b",
+			},
+			expectedMessage: "ConfigMap in clusters cluster1, cluster2 is managed by FederatedConfigMap/ns/b",
+		},
+		"Managed and targeted by other federated resources": {
+			ownersByCluster: map[string]string{
+				"cluster1": "FederatedConfigMap/ns/b",
+			},
+			contenders: map[string][]string{
+				"FederatedConfigMap/ns/d": {"cluster3"},
+				"FederatedConfigMap/ns/c": {"cluster2"},
+			},
+			expectedMessage: "ConfigMap in cluster cluster1 is managed by FederatedConfigMap/ns/b; " +
+				"ConfigMap in cluster cluster2 is also targeted by FederatedConfigMap/ns/c; " +
+				"ConfigMap in cluster cluster3 is also targeted by FederatedConfigMap/ns/d",
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			message := conflictMessage("ConfigMap", tc.ownersByCluster, tc.contenders)
+			assert.Equal(t, tc.expectedMessage, message)
+		})
+	}
+}
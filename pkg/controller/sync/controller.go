@@ -19,7 +19,10 @@ package sync
 import (
 	"context"
 	"fmt"
+	"io"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
@@ -29,9 +32,11 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	kubeclient "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
@@ -42,9 +47,18 @@ import (
 	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
 	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
 	"sigs.k8s.io/kubefed/pkg/controller/sync/dispatch"
+	"sigs.k8s.io/kubefed/pkg/controller/sync/policy"
 	"sigs.k8s.io/kubefed/pkg/controller/sync/status"
 	"sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/controller/util/deletionlimiter"
+	"sigs.k8s.io/kubefed/pkg/controller/util/diagnostics"
+	"sigs.k8s.io/kubefed/pkg/controller/util/dispatchlimiter"
 	finalizersutil "sigs.k8s.io/kubefed/pkg/controller/util/finalizers"
+	"sigs.k8s.io/kubefed/pkg/controller/util/notifier"
+	"sigs.k8s.io/kubefed/pkg/controller/util/ownership"
+	"sigs.k8s.io/kubefed/pkg/controller/util/placementpolicy"
+	"sigs.k8s.io/kubefed/pkg/controller/util/quota"
+	"sigs.k8s.io/kubefed/pkg/features"
 )
 
 const (
@@ -60,6 +74,12 @@ const (
 	// If the annotation is not present (the default), resources in member
 	// clusters will be deleted before the federated resource is deleted.
 	OrphanManagedResources = "kubefed.k8s.io/orphan"
+
+	// Unless this annotation is set to "true" on a federated
+	// namespace, namespaces in member clusters managed by the
+	// federated namespace are orphaned rather than deleted to avoid
+	// the accidental removal of their contents.
+	ConfirmNamespaceDeletion = "kubefed.k8s.io/confirm-namespace-deletion"
 )
 
 // KubeFedSyncController synchronizes the state of federated resources
@@ -75,6 +95,10 @@ type KubeFedSyncController struct {
 	// Informer for resources in member clusters
 	informer util.FederatedInformer
 
+	// Returns the clients used to dispatch operations to member
+	// clusters, subject to the circuit breaker of the member clusters
+	clientForCluster func(clusterName string) (util.ResourceClient, error)
+
 	// For events
 	eventRecorder record.EventRecorder
 
@@ -83,6 +107,11 @@ type KubeFedSyncController struct {
 	smallDelay              time.Duration
 	updateTimeout           time.Duration
 
+	// The delay after which a resource whose overrides have value
+	// sources is reconciled again, since the referenced ConfigMaps
+	// and Secrets are not watched.
+	valueSourceRefreshDelay time.Duration
+
 	typeConfig typeconfig.Interface
 
 	fedAccessor FederatedResourceAccessor
@@ -90,6 +119,53 @@ type KubeFedSyncController struct {
 	hostClusterClient genericclient.Client
 
 	skipAdoptingResources bool
+
+	// Whether existing resources are only reported as adoptable
+	reportAdoptableResources bool
+
+	// Whether propagation of the type is paused, in which case the
+	// controller continues to report the status of federated
+	// resources but does not write to member clusters.
+	paused bool
+
+	notifier *notifier.Notifier
+
+	// Limits the rate of deletions from member clusters
+	deletionLimiter *deletionlimiter.Limiter
+
+	// Bounds the operations dispatched concurrently to member clusters
+	dispatchLimiter *dispatchlimiter.Limiter
+
+	// Defers the propagation status updates of resources whose
+	// status was recently updated
+	statusUpdates *util.StatusUpdateCoalescer
+
+	// Dispatch policies that rendered resources are checked against
+	policies policy.Evaluator
+
+	// Placement policies that restrict the clusters resources may be
+	// placed on
+	placementPolicies placementpolicy.Restrictor
+
+	// Tracks the usage of propagation quotas across types
+	quotas *quota.Tracker
+	// Seeds the usage of propagation quotas once
+	seedQuotasOnce sync.Once
+
+	// Tracks the federated resources of all types that target
+	// resources managed by another federated resource
+	ownership *ownership.Tracker
+
+	// Receives the sizes of the informer caches
+	diagnostics *diagnostics.Registry
+
+	// Whether the status of resources in member clusters is
+	// collected into the status of federated resources
+	rawResourceStatusCollection bool
+
+	// Whether the load balancer status of services in member
+	// clusters is recorded in the status of federated services.
+	loadBalancerStatusCollection bool
 }
 
 // StartKubeFedSyncController starts a new sync controller for a type config
@@ -120,17 +196,28 @@ func newKubeFedSyncController(controllerConfig *util.ControllerConfig, typeConfi
 	recorder := broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: userAgent})
 
 	s := &KubeFedSyncController{
-		clusterAvailableDelay:   controllerConfig.ClusterAvailableDelay,
-		clusterUnavailableDelay: controllerConfig.ClusterUnavailableDelay,
-		smallDelay:              time.Second * 3,
-		updateTimeout:           time.Second * 30,
-		eventRecorder:           recorder,
-		typeConfig:              typeConfig,
-		hostClusterClient:       client,
-		skipAdoptingResources:   controllerConfig.SkipAdoptingResources,
+		clusterAvailableDelay:    controllerConfig.ClusterAvailableDelay,
+		clusterUnavailableDelay:  controllerConfig.ClusterUnavailableDelay,
+		smallDelay:               time.Second * 3,
+		updateTimeout:            time.Second * 30,
+		valueSourceRefreshDelay:  time.Minute,
+		eventRecorder:            recorder,
+		typeConfig:               typeConfig,
+		hostClusterClient:        client,
+		skipAdoptingResources:    controllerConfig.SkipAdoptingResources,
+		reportAdoptableResources: controllerConfig.ReportAdoptableResources,
+		paused:                   typeConfig.GetPropagationPaused(),
+		notifier:                 controllerConfig.Notifier,
+		deletionLimiter:          controllerConfig.DeletionLimiter,
+		dispatchLimiter:          controllerConfig.DispatchLimiter,
+		statusUpdates:            util.NewStatusUpdateCoalescer(controllerConfig.StatusUpdateIntervalFor(typeConfig)),
+		diagnostics:              controllerConfig.Diagnostics,
+		rawResourceStatusCollection: utilfeature.DefaultFeatureGate.Enabled(features.RawResourceStatusCollection) &&
+			typeConfig.GetStatusEnabled() && controllerConfig.ControllerEnabled(fedv1b1.StatusControllerName),
+		loadBalancerStatusCollection: typeConfig.GetTargetType().Kind == util.ServiceKind && typeConfig.GetTargetType().Group == "",
 	}
 
-	s.worker = util.NewReconcileWorker(s.reconcile, util.WorkerTiming{
+	s.worker = util.NewReconcileWorker(util.WorkerName{Controller: "sync", TypeConfig: typeConfig.GetObjectMeta().Name}, s.reconcile, util.WorkerTiming{
 		ClusterSyncDelay: s.clusterAvailableDelay,
 	})
 
@@ -141,14 +228,18 @@ func newKubeFedSyncController(controllerConfig *util.ControllerConfig, typeConfi
 
 	// Federated informer for resources in member clusters
 	var err error
-	s.informer, err = util.NewFederatedInformer(
+	s.informer, err = util.NewFederatedInformerForVersions(
 		controllerConfig,
 		client,
 		&targetAPIResource,
+		typeConfig.GetTargetVersions(),
 		func(obj pkgruntime.Object) {
 			qualifiedName := util.NewQualifiedName(obj)
 			s.worker.EnqueueForRetry(qualifiedName)
 		},
+		// Status changes must trigger reconciliation while
+		// raw status is collected to keep the remote status current.
+		util.NewMemberEventFilter(typeConfig.GetMemberEventFilter(), s.rawResourceStatusCollection || s.loadBalancerStatusCollection),
 		&util.ClusterLifecycleHandlerFuncs{
 			ClusterAvailable: func(cluster *fedv1b1.KubeFedCluster) {
 				// When new cluster becomes available process all the target resources again.
@@ -163,14 +254,36 @@ func newKubeFedSyncController(controllerConfig *util.ControllerConfig, typeConfi
 	if err != nil {
 		return nil, err
 	}
+	s.clientForCluster = dispatch.DeletionRecordingClientAccessor(
+		dispatch.CircuitBreakingClientAccessor(s.informer.GetClientForCluster, controllerConfig.CircuitBreaker),
+		controllerConfig.DeletionLimiter)
 
 	s.fedAccessor, err = NewFederatedResourceAccessor(
 		controllerConfig, typeConfig, fedNamespaceAPIResource,
-		client, s.worker.EnqueueObject, recorder)
+		client, s.informer, s.worker.EnqueueObject, recorder)
+	if err != nil {
+		return nil, err
+	}
+
+	// A change to a dispatch policy may affect any resource.
+	s.policies, err = policy.NewEvaluator(controllerConfig, func(_ pkgruntime.Object) {
+		s.clusterDeliverer.DeliverAt(allClustersKey, nil, time.Now())
+	})
 	if err != nil {
 		return nil, err
 	}
 
+	// A change to a placement policy may affect any resource.
+	s.placementPolicies, err = placementpolicy.NewRestrictor(controllerConfig, func(_ pkgruntime.Object) {
+		s.clusterDeliverer.DeliverAt(allClustersKey, nil, time.Now())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.quotas = controllerConfig.PropagationQuotas
+	s.ownership = controllerConfig.OwnershipConflicts
+
 	return s, nil
 }
 
@@ -180,11 +293,14 @@ func (s *KubeFedSyncController) minimizeLatency() {
 	s.clusterUnavailableDelay = time.Second
 	s.smallDelay = 20 * time.Millisecond
 	s.updateTimeout = 5 * time.Second
+	s.valueSourceRefreshDelay = time.Second
 	s.worker.SetDelay(50*time.Millisecond, s.clusterAvailableDelay)
 }
 
 func (s *KubeFedSyncController) Run(stopChan <-chan struct{}) {
 	s.fedAccessor.Run(stopChan)
+	s.policies.Run(stopChan)
+	s.placementPolicies.Run(stopChan)
 	s.informer.Start()
 	s.clusterDeliverer.StartWithHandler(func(_ *util.DelayingDelivererItem) {
 		s.reconcileOnClusterChange()
@@ -192,14 +308,63 @@ func (s *KubeFedSyncController) Run(stopChan <-chan struct{}) {
 
 	s.worker.Run(stopChan)
 
+	removeDiagnostics := s.diagnostics.AddSource("sync-controller/"+s.typeConfig.GetObjectMeta().Name, s.dumpCaches)
+
+	// Resources that exceeded a quota may fit once the quota changes
+	// or other resources are released.
+	removeQuotaListener := s.quotas.AddListener(func() {
+		s.clusterDeliverer.DeliverAt(allClustersKey, nil, time.Now())
+	})
+
+	// The owner of resources targeted by another federated resource
+	// reports the conflict in its status.
+	federatedKind := s.typeConfig.GetFederatedType().Kind
+	removeOwnershipListener := s.ownership.AddListener(func(owner string) {
+		if kind, qualifiedName, ok := util.ParseOwnerKey(owner); ok && kind == federatedKind {
+			s.worker.Enqueue(qualifiedName)
+		}
+	})
+
 	// Ensure all goroutines are cleaned up when the stop channel closes
 	go func() {
 		<-stopChan
+		removeQuotaListener()
+		removeOwnershipListener()
 		s.informer.Stop()
 		s.clusterDeliverer.Stop()
+		removeDiagnostics()
 	}()
 }
 
+// dumpCaches writes the number of federated resources in the cache
+// of the host cluster and of target resources in the cache of each
+// member cluster to a diagnostic dump.
+func (s *KubeFedSyncController) dumpCaches(w io.Writer) {
+	federatedCount := 0
+	s.fedAccessor.VisitFederatedResources(func(obj interface{}) {
+		federatedCount++
+	})
+	fmt.Fprintf(w, "host: %d %s cached\n", federatedCount, s.typeConfig.GetFederatedType().Kind)
+
+	clusters, err := s.informer.GetClusters()
+	if err != nil {
+		fmt.Fprintf(w, "Failed to list clusters: %v\n", err)
+		return
+	}
+	sort.Slice(clusters, func(i, j int) bool {
+		return clusters[i].Name < clusters[j].Name
+	})
+	targetKind := s.typeConfig.GetTargetType().Kind
+	for _, cluster := range clusters {
+		objs, err := s.informer.GetTargetStore().ListFromCluster(cluster.Name)
+		if err != nil {
+			fmt.Fprintf(w, "%s: failed to list the cache: %v\n", cluster.Name, err)
+			continue
+		}
+		fmt.Fprintf(w, "%s: %d %s cached\n", cluster.Name, len(objs), targetKind)
+	}
+}
+
 // Check whether all data stores are in sync. False is returned if any of the informer/stores is not yet
 // synced with the corresponding api server.
 func (s *KubeFedSyncController) isSynced() bool {
@@ -212,6 +377,19 @@ func (s *KubeFedSyncController) isSynced() bool {
 		// complete.
 		return false
 	}
+	if !s.policies.HasSynced() {
+		klog.V(2).Infof("Dispatch policies not synced")
+		return false
+	}
+	if !s.placementPolicies.HasSynced() {
+		klog.V(2).Infof("Placement policies not synced")
+		return false
+	}
+	if !s.quotas.HasSynced() {
+		klog.V(2).Infof("Propagation quotas not synced")
+		return false
+	}
+	s.seedQuotasOnce.Do(s.seedQuotas)
 
 	// TODO(marun) set clusters as ready in the test fixture?
 	clusters, err := s.informer.GetReadyClusters()
@@ -225,6 +403,28 @@ func (s *KubeFedSyncController) isSynced() bool {
 	return true
 }
 
+// seedQuotas records the usage of the federated resources that were
+// admitted by the propagation quotas before the controller started, so
+// that they are not rejected in favor of resources that happen to be
+// reconciled first.
+func (s *KubeFedSyncController) seedQuotas() {
+	kind := s.typeConfig.GetFederatedType().Kind
+	s.fedAccessor.VisitFederatedResources(func(obj interface{}) {
+		fedObject := obj.(*unstructured.Unstructured)
+		if fedObject.GetDeletionTimestamp() != nil {
+			return
+		}
+		clusterCount, admitted, err := status.GetAdmittedClusterCount(fedObject)
+		if err != nil {
+			runtime.HandleError(errors.Wrapf(err, "Failed to seed the propagation quota usage of %s %q", kind, util.NewQualifiedName(fedObject)))
+			return
+		}
+		if admitted {
+			s.quotas.Seed(fedObject.GetNamespace(), quotaKey(kind, util.NewQualifiedName(fedObject)), clusterCount)
+		}
+	})
+}
+
 // The function triggers reconciliation of all target federated resources.
 func (s *KubeFedSyncController) reconcileOnClusterChange() {
 	if !s.isSynced() {
@@ -249,9 +449,17 @@ func (s *KubeFedSyncController) reconcile(qualifiedName util.QualifiedName) util
 		return util.StatusError
 	}
 	if possibleOrphan {
+		s.quotas.Release(qualifiedName.Namespace, quotaKey(kind, qualifiedName))
+		s.ownership.Release(util.OwnerKey(kind, qualifiedName))
 		targetKind := s.typeConfig.GetTargetType().Kind
+		if s.paused {
+			// The resources are reconciled again when the sync
+			// controller is restarted to resume propagation.
+			klog.V(3).Infof("Propagation of %s is paused. Not removing the label %q from %s %q in member clusters.", kind, util.ManagedByKubeFedLabelKey, targetKind, qualifiedName)
+			return util.StatusAllOK
+		}
 		klog.V(2).Infof("Ensuring the removal of the label %q from %s %q in member clusters.", util.ManagedByKubeFedLabelKey, targetKind, qualifiedName)
-		err = s.removeManagedLabel(targetKind, qualifiedName)
+		err = s.removeManagedLabel(targetKind, util.OwnerKey(kind, qualifiedName), qualifiedName, nil)
 		if err != nil {
 			wrappedErr := errors.Wrapf(err, "failed to remove the label %q from %s %q in member clusters", util.ManagedByKubeFedLabelKey, targetKind, qualifiedName)
 			runtime.HandleError(wrappedErr)
@@ -261,6 +469,8 @@ func (s *KubeFedSyncController) reconcile(qualifiedName util.QualifiedName) util
 		return util.StatusAllOK
 	}
 	if fedResource == nil {
+		s.quotas.Release(qualifiedName.Namespace, quotaKey(kind, qualifiedName))
+		s.ownership.Release(util.OwnerKey(kind, qualifiedName))
 		return util.StatusAllOK
 	}
 
@@ -271,6 +481,14 @@ func (s *KubeFedSyncController) reconcile(qualifiedName util.QualifiedName) util
 	defer klog.V(4).Infof("Finished reconciling %s %q (duration: %v)", kind, key, time.Since(startTime))
 
 	if fedResource.Object().GetDeletionTimestamp() != nil {
+		s.quotas.Release(qualifiedName.Namespace, quotaKey(kind, qualifiedName))
+		s.ownership.Release(util.OwnerKey(kind, qualifiedName))
+		if s.paused {
+			// The finalizer is retained until propagation is resumed
+			// so that the managed resources are then removed.
+			klog.V(3).Infof("Propagation of %s is paused. Deferring the deletion of %q.", kind, key)
+			return util.StatusAllOK
+		}
 		klog.V(3).Infof("Handling deletion of %s %q", kind, key)
 		return s.ensureDeletion(fedResource)
 	}
@@ -290,21 +508,69 @@ func (s *KubeFedSyncController) syncToClusters(fedResource FederatedResource) ut
 	clusters, err := s.informer.GetClusters()
 	if err != nil {
 		fedResource.RecordError(string(status.ClusterRetrievalFailed), errors.Wrap(err, "Failed to retrieve list of clusters"))
-		return s.setPropagationStatus(fedResource, status.ClusterRetrievalFailed, nil)
+		return s.setPropagationStatus(fedResource, status.ClusterRetrievalFailed, nil, status.ClusterDetails{})
 	}
 
 	selectedClusterNames, err := fedResource.ComputePlacement(clusters)
 	if err != nil {
 		fedResource.RecordError(string(status.ComputePlacementFailed), errors.Wrap(err, "Failed to compute placement"))
-		return s.setPropagationStatus(fedResource, status.ComputePlacementFailed, nil)
+		return s.setPropagationStatus(fedResource, status.ComputePlacementFailed, nil, status.ClusterDetails{})
+	}
+
+	// Clusters that the placement policies of the namespace do not
+	// allow are removed from the placement.
+	namespace := fedResource.Object().GetNamespace()
+	deniedClusterNames := sets.NewString()
+	if allowed := s.placementPolicies.AllowedClusters(namespace, clusters); allowed != nil {
+		deniedClusterNames = selectedClusterNames.Difference(allowed)
+		selectedClusterNames = selectedClusterNames.Intersection(allowed)
+	}
+
+	if !s.quotas.Admit(namespace, quotaKey(fedResource.FederatedKind(), fedResource.FederatedName()), selectedClusterNames.Len()) {
+		err := errors.Errorf("Propagating to %d clusters would exceed the propagation quota of namespace %q", selectedClusterNames.Len(), namespace)
+		fedResource.RecordError(string(status.QuotaExceeded), err)
+		return s.setPropagationStatus(fedResource, status.QuotaExceeded, nil, status.ClusterDetails{})
 	}
 
 	kind := fedResource.TargetKind()
 	key := fedResource.TargetName().String()
+	owner := util.OwnerKey(fedResource.FederatedKind(), fedResource.FederatedName())
 	klog.V(4).Infof("Syncing %s %q in underlying clusters, selected clusters are: %s", kind, key, selectedClusterNames)
 
-	dispatcher := dispatch.NewManagedDispatcher(s.informer.GetClientForCluster, fedResource, s.skipAdoptingResources)
+	// Resources are not removed from clusters while the placement
+	// selects fewer ready clusters than it requires, so that a change
+	// of cluster labels or health does not silently reduce the
+	// redundancy of the resource.
+	unsatisfiedMessage, err := fedResource.UnsatisfiedPlacement(clusters, selectedClusterNames)
+	if err != nil {
+		fedResource.RecordError(string(status.ComputePlacementFailed), errors.Wrap(err, "Failed to check placement"))
+		return s.setPropagationStatus(fedResource, status.ComputePlacementFailed, nil, status.ClusterDetails{})
+	}
+	placementUnsatisfied := len(unsatisfiedMessage) > 0
+	if placementUnsatisfied {
+		fedResource.RecordError(string(status.UnsatisfiablePlacement), errors.New(unsatisfiedMessage))
+	}
 
+	// Resources propagated under a name that no longer applies need
+	// to be removed before the recorded names are updated.
+	renamesResolved := !s.paused && s.removeStaleRenamedResources(fedResource, clusters)
+
+	targetType := s.typeConfig.GetTargetType()
+	dispatcher := dispatch.NewManagedDispatcher(s.clientForCluster, s.dispatchLimiter, fedResource, s.skipAdoptingResources, s.reportAdoptableResources, s.paused,
+		s.policies.ValidatorFor(&targetType, clusters))
+
+	deletionsPaused := false
+	// The negotiated version is only recorded for types with more than
+	// one acceptable version.
+	recordAPIVersions := len(s.typeConfig.GetTargetVersions()) > 1
+	details := status.ClusterDetails{
+		RemoteStatus:     make(status.ClusterRemoteStatusMap),
+		APIVersions:      make(status.ClusterAPIVersionMap),
+		ObservedVersions: make(status.ClusterVersionMap),
+	}
+	if s.loadBalancerStatusCollection {
+		details.LoadBalancers = make(status.ClusterLoadBalancerMap)
+	}
 	for _, cluster := range clusters {
 		clusterName := cluster.Name
 		selectedCluster := selectedClusterNames.Has(clusterName)
@@ -319,7 +585,18 @@ func (s *KubeFedSyncController) syncToClusters(fedResource FederatedResource) ut
 			continue
 		}
 
-		rawClusterObj, _, err := s.informer.GetTargetStore().GetByKey(clusterName, key)
+		// Resources in a namespace the cluster does not allow are
+		// neither created nor removed.
+		if clusterNamespace := s.namespaceForCluster(fedResource, clusterName); !util.IsNamespaceAllowed(cluster, clusterNamespace) {
+			if selectedCluster {
+				err := errors.Errorf("Namespace %q is not allowed in the cluster", clusterNamespace)
+				dispatcher.RecordClusterError(status.NamespaceNotAllowed, clusterName, err)
+			}
+			continue
+		}
+
+		clusterKey := s.targetKeyForCluster(fedResource.TargetName(), fedResource.NameForCluster(clusterName))
+		rawClusterObj, _, err := s.informer.GetTargetStore().GetByKey(clusterName, clusterKey)
 		if err != nil {
 			wrappedErr := errors.Wrap(err, "Failed to retrieve cached cluster object")
 			dispatcher.RecordClusterError(status.CachedRetrievalFailed, clusterName, wrappedErr)
@@ -333,8 +610,18 @@ func (s *KubeFedSyncController) syncToClusters(fedResource FederatedResource) ut
 
 		// Resource should not exist in the named cluster
 		if !selectedCluster {
+			deniedCluster := deniedClusterNames.Has(clusterName)
 			if clusterObj == nil {
 				// Resource does not exist in the cluster
+				if deniedCluster {
+					err := errors.Errorf("Cluster is not allowed by the placement policies of namespace %q", namespace)
+					dispatcher.RecordClusterError(status.ClusterNotAllowed, clusterName, err)
+				}
+				continue
+			}
+			if util.OwnedByOther(clusterObj, owner) {
+				// The resource is left to the federated resource
+				// that manages it.
 				continue
 			}
 			if clusterObj.GetDeletionTimestamp() != nil {
@@ -342,18 +629,59 @@ func (s *KubeFedSyncController) syncToClusters(fedResource FederatedResource) ut
 				dispatcher.RecordStatus(clusterName, status.WaitingForRemoval)
 				continue
 			}
+			// Resources in clusters that are not allowed are removed
+			// even if the placement is unsatisfied.
+			if placementUnsatisfied && !deniedCluster {
+				dispatcher.RecordStatus(clusterName, status.RemovalPrevented)
+				continue
+			}
 			if fedResource.IsNamespaceInHostCluster(clusterObj) {
 				// Host cluster namespace needs to have the managed
 				// label removed so it won't be cached anymore.
 				dispatcher.RemoveManagedLabel(clusterName, clusterObj)
+			} else if s.paused || s.deletionLimiter.Allow(cluster) {
+				// A paused dispatcher only records the deletion, so the
+				// deletion limit need not be consulted.
+				dispatcher.Delete(clusterName, clusterObj)
 			} else {
-				dispatcher.Delete(clusterName)
+				err := errors.Errorf("Deletion of %s %q is paused since the deletion limit of the cluster was exceeded", kind, util.NewQualifiedName(clusterObj))
+				dispatcher.RecordClusterError(status.DeletionPaused, clusterName, err)
+				deletionsPaused = true
 			}
 			continue
 		}
 
 		// Resource should appear in the named cluster
 
+		if clusterObj != nil {
+			details.ObservedVersions[clusterName] = util.ObjectVersion(clusterObj)
+		}
+
+		if s.rawResourceStatusCollection && clusterObj != nil {
+			// The status observed before the resource is updated is
+			// recorded.  A subsequent change of the status in the
+			// cluster triggers another reconcile.
+			if remoteStatus, ok := clusterObj.Object[util.StatusField]; ok {
+				details.RemoteStatus[clusterName] = remoteStatus
+			}
+		}
+
+		if s.loadBalancerStatusCollection && clusterObj != nil {
+			lbStatus, err := status.LoadBalancerStatus(clusterObj)
+			if err != nil {
+				klog.Warningf("Failed to determine the load balancer status of %s %q in cluster %q: %v", kind, util.NewQualifiedName(clusterObj), clusterName, err)
+			} else if lbStatus != nil {
+				details.LoadBalancers[clusterName] = lbStatus
+			}
+		}
+
+		if recordAPIVersions {
+			// A failure to negotiate is reported by the dispatcher.
+			if version, err := s.informer.TargetVersionForCluster(clusterName); err == nil {
+				details.APIVersions[clusterName] = schema.GroupVersion{Group: targetType.Group, Version: version}.String()
+			}
+		}
+
 		// TODO(marun) Consider waiting until the result of resource
 		// creation has reached the target store before attempting
 		// subsequent operations.  Otherwise the object won't be found
@@ -369,31 +697,179 @@ func (s *KubeFedSyncController) syncToClusters(fedResource FederatedResource) ut
 		fedResource.RecordError("OperationTimeoutError", timeoutErr)
 	}
 
-	// Write updated versions to the API.
-	updatedVersionMap := dispatcher.VersionMap()
-	err = fedResource.UpdateVersions(selectedClusterNames.List(), updatedVersionMap)
-	if err != nil {
-		// Versioning of federated resources is an optimization to
-		// avoid unnecessary updates, and failure to record version
-		// information does not indicate a failure of propagation.
-		runtime.HandleError(err)
+	// Write updated versions to the API.  Versions are not written if
+	// the removal of renamed resources could not be initiated so that
+	// the names recorded for those resources are retained, or while
+	// propagation is paused since no resources were written.
+	if s.paused {
+		klog.V(4).Infof("Propagation of %s %q is paused", kind, key)
+	} else if renamesResolved {
+		updatedVersionMap := dispatcher.VersionMap()
+		err = fedResource.UpdateVersions(selectedClusterNames.List(), updatedVersionMap)
+		if err != nil {
+			// Versioning of federated resources is an optimization to
+			// avoid unnecessary updates, and failure to record version
+			// information does not indicate a failure of propagation.
+			runtime.HandleError(err)
+		}
+	} else {
+		fedResource.RecordError("RenamedResourceRemovalFailed", errors.Errorf("Failed to remove %s %q from one or more clusters after it was renamed", kind, key))
+	}
+
+	reason := status.AggregateSuccess
+	if s.paused {
+		reason = status.PropagationPaused
+	} else if placementUnsatisfied {
+		reason = status.UnsatisfiablePlacement
 	}
 
 	statusMap := dispatcher.StatusMap()
-	return s.setPropagationStatus(fedResource, status.AggregateSuccess, statusMap)
+	details.Drift = dispatcher.DriftMap()
+	// The versions of the resources written by the dispatcher
+	// supersede those observed before they were written.
+	for clusterName, version := range dispatcher.VersionMap() {
+		details.ObservedVersions[clusterName] = version
+	}
+	details.AppliedTimes = dispatcher.AppliedTimes()
+	conflicts := dispatcher.ConflictMap()
+	s.ownership.SetConflicts(owner, conflicts)
+	details.Conflict = conflictMessage(kind, conflicts, s.ownership.Contenders(owner))
+	if fedResource.HasOverrideValueSources() {
+		s.worker.EnqueueWithDelay(fedResource.FederatedName(), s.valueSourceRefreshDelay)
+	}
+	if dispatchBackedOff(statusMap) {
+		// Recheck so that the resource probes whether the cluster
+		// has recovered once its backoff elapses.
+		reconcileStatus := s.setPropagationStatus(fedResource, reason, statusMap, details)
+		if reconcileStatus == util.StatusAllOK {
+			return util.StatusNeedsRecheck
+		}
+		return reconcileStatus
+	}
+	if deletionsPaused {
+		// Recheck until deletions have been acknowledged.
+		reconcileStatus := s.setPropagationStatus(fedResource, status.DeletionsPaused, statusMap, details)
+		if reconcileStatus == util.StatusAllOK {
+			return util.StatusNeedsRecheck
+		}
+		return reconcileStatus
+	}
+	return s.setPropagationStatus(fedResource, reason, statusMap, details)
+}
+
+// dispatchBackedOff returns true if an operation was not dispatched
+// to a cluster since dispatch to the cluster is backed off.
+func dispatchBackedOff(statusMap status.PropagationStatusMap) bool {
+	for _, propStatus := range statusMap {
+		if propStatus == status.ClusterDegraded {
+			return true
+		}
+	}
+	return false
+}
+
+// removeStaleRenamedResources initiates the removal of resources that
+// were propagated under a name that no longer applies due to a rename
+// override having been added, changed or removed.  False is returned
+// if removal could not be initiated in every ready cluster.
+func (s *KubeFedSyncController) removeStaleRenamedResources(fedResource FederatedResource, clusters []*fedv1b1.KubeFedCluster) bool {
+	kind := fedResource.TargetKind()
+	targetName := fedResource.TargetName()
+	renamedClusters := fedResource.RenamedClusters()
+
+	dispatcher := dispatch.NewUnmanagedDispatcher(s.clientForCluster, s.dispatchLimiter, kind, targetName)
+	ok := true
+	for _, cluster := range clusters {
+		clusterName := cluster.Name
+		if !util.IsClusterReady(&cluster.Status) {
+			continue
+		}
+		previousName, renamed := renamedClusters[clusterName]
+		if !renamed {
+			previousName = targetName.Name
+		}
+		if previousName == fedResource.NameForCluster(clusterName) {
+			continue
+		}
+		key := s.targetKeyForCluster(targetName, previousName)
+		rawClusterObj, _, err := s.informer.GetTargetStore().GetByKey(clusterName, key)
+		if err != nil {
+			runtime.HandleError(errors.Wrapf(err, "failed to retrieve %s %q for cluster %q", kind, key, clusterName))
+			ok = false
+			continue
+		}
+		if rawClusterObj == nil {
+			continue
+		}
+		clusterObj := rawClusterObj.(*unstructured.Unstructured)
+		if clusterObj.GetDeletionTimestamp() != nil {
+			continue
+		}
+		if !s.deletionLimiter.Allow(cluster) {
+			klog.V(2).Infof("Not removing renamed %s %q from cluster %q since deletions from the cluster are paused", kind, key, clusterName)
+			ok = false
+			continue
+		}
+		klog.V(2).Infof("Removing %s %q from cluster %q since it has been renamed", kind, key, clusterName)
+		dispatcher.Delete(clusterName, clusterObj)
+	}
+	dispatchOK, timeoutErr := dispatcher.Wait()
+	if timeoutErr != nil {
+		runtime.HandleError(timeoutErr)
+		return false
+	}
+	return ok && dispatchOK
+}
+
+// targetKeyForCluster returns the key with which a target resource
+// with the given name in a member cluster is cached.  The key retains
+// the host cluster namespace since the target store accounts for
+// namespace mappings.
+func (s *KubeFedSyncController) targetKeyForCluster(targetName util.QualifiedName, name string) string {
+	return util.QualifiedName{Namespace: targetName.Namespace, Name: name}.String()
+}
+
+// quotaKey identifies a federated resource in the propagation quota
+// tracker, which is shared by the sync controllers of all types.
+func quotaKey(kind string, qualifiedName util.QualifiedName) string {
+	return kind + "/" + qualifiedName.String()
+}
+
+// namespaceForCluster returns the namespace of the given member
+// cluster that the target resource is propagated to, or the name of
+// the target resource if it is a namespace.
+func (s *KubeFedSyncController) namespaceForCluster(fedResource FederatedResource, clusterName string) string {
+	targetName := fedResource.TargetNameForCluster(clusterName)
+	if fedResource.TargetKind() == util.NamespaceKind {
+		return targetName.Name
+	}
+	return targetName.Namespace
 }
 
 func (s *KubeFedSyncController) setPropagationStatus(fedResource FederatedResource,
-	reason status.AggregateReason, statusMap status.PropagationStatusMap) util.ReconciliationStatus {
+	reason status.AggregateReason, statusMap status.PropagationStatusMap, details status.ClusterDetails) util.ReconciliationStatus {
 
 	kind := fedResource.FederatedKind()
 	name := fedResource.FederatedName()
 	obj := fedResource.Object()
 
+	if delay := s.statusUpdates.Delay(name, util.RefreshStatusToken(obj)); delay > 0 {
+		// The status will reflect the result of the reconcile
+		// performed once the interval since the last update elapses.
+		klog.V(4).Infof("Deferring the propagation status update of %s %q by %v", kind, name, delay)
+		s.worker.EnqueueWithDelay(name, delay)
+		return util.StatusAllOK
+	}
+
+	previousStatusMap, err := status.GetPropagationStatusMap(obj)
+	if err != nil {
+		klog.Warningf("Failed to determine previous propagation status for %s %q: %v", kind, name, err)
+	}
+
 	// If the underlying resource has changed, attempt to retrieve and
 	// update it repeatedly.
-	err := wait.PollImmediate(1*time.Second, 5*time.Second, func() (bool, error) {
-		if err := status.SetPropagationStatus(obj, reason, statusMap); err != nil {
+	err = wait.PollImmediate(1*time.Second, 5*time.Second, func() (bool, error) {
+		if err := status.SetPropagationStatus(obj, reason, statusMap, details); err != nil {
 			return false, errors.Wrapf(err, "failed to set the status")
 		}
 
@@ -416,9 +892,31 @@ func (s *KubeFedSyncController) setPropagationStatus(fedResource FederatedResour
 		return util.StatusError
 	}
 
+	s.notifyPropagationFailure(kind, name, previousStatusMap, statusMap)
+
 	return util.StatusAllOK
 }
 
+// notifyPropagationFailure sends a notification if the number of
+// clusters the resource failed to propagate to has reached the
+// configured threshold.
+func (s *KubeFedSyncController) notifyPropagationFailure(kind string, name util.QualifiedName,
+	previousStatusMap, statusMap status.PropagationStatusMap) {
+
+	threshold := s.notifier.PropagationFailureThreshold()
+	failedClusters := statusMap.FailedClusters()
+	if len(failedClusters) < threshold || len(previousStatusMap.FailedClusters()) >= threshold {
+		return
+	}
+	s.notifier.Notify(&notifier.Notification{
+		Type:     fedv1b1.PropagationFailedNotification,
+		Kind:     kind,
+		Name:     name.String(),
+		Clusters: failedClusters,
+		Message:  fmt.Sprintf("Failed to propagate %s %q to %d cluster(s): %s", kind, name, len(failedClusters), strings.Join(failedClusters, ", ")),
+	})
+}
+
 func (s *KubeFedSyncController) ensureDeletion(fedResource FederatedResource) util.ReconciliationStatus {
 	fedResource.DeleteVersions()
 
@@ -439,20 +937,13 @@ func (s *KubeFedSyncController) ensureDeletion(fedResource FederatedResource) ut
 	orphanResources := annotations != nil && annotations[OrphanManagedResources] == "true"
 	if orphanResources {
 		klog.V(2).Infof("Found %q annotation on %s %q. Removing the finalizer.", OrphanManagedResources, kind, key)
-		err := s.removeFinalizer(fedResource)
-		if err != nil {
-			wrappedErr := errors.Wrapf(err, "failed to remove finalizer %q from %s %q", OrphanManagedResources, kind, key)
-			runtime.HandleError(wrappedErr)
-			return util.StatusError
-		}
-		klog.V(2).Infof("Initiating the removal of the label %q from resources previously managed by %s %q.", util.ManagedByKubeFedLabelKey, kind, key)
-		err = s.removeManagedLabel(fedResource.TargetKind(), fedResource.TargetName())
-		if err != nil {
-			wrappedErr := errors.Wrapf(err, "failed to remove the label %q from all resources previously managed by %s %q", util.ManagedByKubeFedLabelKey, kind, key)
-			runtime.HandleError(wrappedErr)
-			return util.StatusError
-		}
-		return util.StatusAllOK
+		return s.orphanManagedResources(fedResource)
+	}
+
+	if fedResource.TargetKind() == util.NamespaceKind && annotations[ConfirmNamespaceDeletion] != "true" {
+		klog.V(2).Infof("%s %q does not have the %q annotation. Orphaning namespaces in member clusters.", kind, key, ConfirmNamespaceDeletion)
+		s.setNamespaceOrphanedStatus(fedResource)
+		return s.orphanManagedResources(fedResource)
 	}
 
 	klog.V(2).Infof("Deleting resources managed by %s %q from member clusters.", kind, key)
@@ -468,10 +959,60 @@ func (s *KubeFedSyncController) ensureDeletion(fedResource FederatedResource) ut
 	return util.StatusAllOK
 }
 
+// orphanManagedResources removes the finalizer from a federated
+// resource and the managed label from the resources it manages in
+// member clusters so that they are retained.
+func (s *KubeFedSyncController) orphanManagedResources(fedResource FederatedResource) util.ReconciliationStatus {
+	key := fedResource.FederatedName().String()
+	kind := fedResource.FederatedKind()
+
+	err := s.removeFinalizer(fedResource)
+	if err != nil {
+		wrappedErr := errors.Wrapf(err, "failed to remove finalizer %q from %s %q", FinalizerSyncController, kind, key)
+		runtime.HandleError(wrappedErr)
+		return util.StatusError
+	}
+	klog.V(2).Infof("Initiating the removal of the label %q from resources previously managed by %s %q.", util.ManagedByKubeFedLabelKey, kind, key)
+	err = s.removeManagedLabel(fedResource.TargetKind(), util.OwnerKey(kind, fedResource.FederatedName()), fedResource.TargetName(), fedResource.NameForCluster)
+	if err != nil {
+		wrappedErr := errors.Wrapf(err, "failed to remove the label %q from all resources previously managed by %s %q", util.ManagedByKubeFedLabelKey, kind, key)
+		runtime.HandleError(wrappedErr)
+		return util.StatusError
+	}
+	return util.StatusAllOK
+}
+
+// setNamespaceOrphanedStatus records that the namespaces managed by a
+// federated namespace whose deletion was not confirmed are orphaned.
+// The status is updated on a best-effort basis since the federated
+// namespace is removed once its finalizer is removed.
+func (s *KubeFedSyncController) setNamespaceOrphanedStatus(fedResource FederatedResource) {
+	kind := fedResource.FederatedKind()
+	name := fedResource.FederatedName()
+	obj := fedResource.Object()
+
+	message := fmt.Sprintf("Namespaces in member clusters were orphaned since deletion was not confirmed with the %q annotation", ConfirmNamespaceDeletion)
+	fedResource.RecordError(string(status.NamespaceDeletionNotConfirmed), errors.New(message))
+
+	changed, err := status.SetDeletionStatus(obj, status.NamespaceDeletionNotConfirmed, message, nil)
+	if err != nil {
+		runtime.HandleError(errors.Wrapf(err, "failed to set deletion status for %s %q", kind, name))
+		return
+	}
+	if !changed {
+		return
+	}
+	err = s.hostClusterClient.UpdateStatus(context.TODO(), obj)
+	if err != nil {
+		runtime.HandleError(errors.Wrapf(err, "failed to update deletion status for %s %q", kind, name))
+	}
+}
+
 // removeManagedLabel attempts to remove the managed label from
-// resources with the given name in member clusters.
-func (s *KubeFedSyncController) removeManagedLabel(kind string, qualifiedName util.QualifiedName) error {
-	ok, err := s.handleDeletionInClusters(kind, qualifiedName, func(dispatcher dispatch.UnmanagedDispatcher, clusterName string, clusterObj *unstructured.Unstructured) {
+// resources with the given name in member clusters that are not
+// managed by a federated resource other than the given owner.
+func (s *KubeFedSyncController) removeManagedLabel(kind, owner string, qualifiedName util.QualifiedName, nameForCluster func(string) string) error {
+	ok, err := s.handleDeletionInClusters(kind, owner, qualifiedName, nameForCluster, func(dispatcher dispatch.UnmanagedDispatcher, clusterName string, clusterObj *unstructured.Unstructured) {
 		if clusterObj.GetDeletionTimestamp() != nil {
 			return
 		}
@@ -490,9 +1031,12 @@ func (s *KubeFedSyncController) removeManagedLabel(kind string, qualifiedName ut
 func (s *KubeFedSyncController) deleteFromClusters(fedResource FederatedResource) (bool, error) {
 	kind := fedResource.TargetKind()
 	qualifiedName := fedResource.TargetName()
+	foreground, deletionTimeout := foregroundDeletion(fedResource.Object())
 
 	remainingClusters := []string{}
-	ok, err := s.handleDeletionInClusters(kind, qualifiedName, func(dispatcher dispatch.UnmanagedDispatcher, clusterName string, clusterObj *unstructured.Unstructured) {
+	remainingObjs := make(map[string]*unstructured.Unstructured)
+	owner := util.OwnerKey(fedResource.FederatedKind(), fedResource.FederatedName())
+	ok, err := s.handleDeletionInClusters(kind, owner, qualifiedName, fedResource.NameForCluster, func(dispatcher dispatch.UnmanagedDispatcher, clusterName string, clusterObj *unstructured.Unstructured) {
 		// If the containing namespace of a FederatedNamespace is
 		// marked for deletion, it is impossible to require the
 		// removal of the namespace in advance of removal of the sync
@@ -503,6 +1047,7 @@ func (s *KubeFedSyncController) deleteFromClusters(fedResource FederatedResource
 		}
 
 		remainingClusters = append(remainingClusters, clusterName)
+		remainingObjs[clusterName] = clusterObj
 
 		// Avoid attempting any operation on a deleted resource.
 		if clusterObj.GetDeletionTimestamp() != nil {
@@ -515,13 +1060,21 @@ func (s *KubeFedSyncController) deleteFromClusters(fedResource FederatedResource
 			// Removing the managed label will ensure a host cluster
 			// namespace is no longer cached.
 			dispatcher.RemoveManagedLabel(clusterName, clusterObj)
+		} else if !s.deletionAllowed(clusterName) {
+			err := errors.Errorf("Deletion of %s %q is paused since the deletion limit of cluster %q was exceeded", kind, util.NewQualifiedName(clusterObj), clusterName)
+			fedResource.RecordError(string(status.DeletionPaused), err)
+		} else if foreground {
+			dispatcher.DeleteInForeground(clusterName, clusterObj)
 		} else {
-			dispatcher.Delete(clusterName)
+			dispatcher.Delete(clusterName, clusterObj)
 		}
 	})
 	if err != nil {
 		return false, err
 	}
+	if foreground && len(remainingObjs) > 0 {
+		s.setDeletionStatus(fedResource, remainingObjs, deletionTimeout)
+	}
 	if !ok {
 		return false, errors.Errorf("failed to remove managed resources from one or more clusters.")
 	}
@@ -539,6 +1092,49 @@ func (s *KubeFedSyncController) deleteFromClusters(fedResource FederatedResource
 	return false, s.removeFinalizer(fedResource)
 }
 
+// deletionAllowed returns whether the deletion limit of the named
+// cluster allows a resource to be deleted from it.
+func (s *KubeFedSyncController) deletionAllowed(clusterName string) bool {
+	if s.deletionLimiter == nil {
+		return true
+	}
+	cluster, ok, err := s.informer.GetReadyCluster(clusterName)
+	if err != nil || !ok {
+		// The deletion will be retried.
+		return false
+	}
+	return s.deletionLimiter.Allow(cluster)
+}
+
+// setDeletionStatus reports the progress of the foreground deletion
+// of a federated resource in its status.  An event is recorded if the
+// deletion is found to be stuck.  Failure to update the status does
+// not prevent deletion from proceeding.
+func (s *KubeFedSyncController) setDeletionStatus(fedResource FederatedResource,
+	remainingObjs map[string]*unstructured.Unstructured, timeout time.Duration) {
+
+	kind := fedResource.FederatedKind()
+	name := fedResource.FederatedName()
+	obj := fedResource.Object()
+
+	reason, message, statusMap := deletionProgress(fedResource.TargetKind(), remainingObjs, obj.GetDeletionTimestamp().Time, timeout, time.Now())
+	changed, err := status.SetDeletionStatus(obj, reason, message, statusMap)
+	if err != nil {
+		runtime.HandleError(errors.Wrapf(err, "failed to set deletion status for %s %q", kind, name))
+		return
+	}
+	if !changed {
+		return
+	}
+	if reason == status.DeletionStuck {
+		fedResource.RecordError(string(reason), errors.New(message))
+	}
+	err = s.hostClusterClient.UpdateStatus(context.TODO(), obj)
+	if err != nil {
+		runtime.HandleError(errors.Wrapf(err, "failed to update deletion status for %s %q", kind, name))
+	}
+}
+
 // ensureRemovedOrUnmanaged ensures that no resources in member
 // clusters that could be managed by the given federated resources are
 // present or labeled as managed.  The checks are performed without
@@ -550,14 +1146,15 @@ func (s *KubeFedSyncController) ensureRemovedOrUnmanaged(fedResource FederatedRe
 		return errors.Wrap(err, "failed to get a list of clusters")
 	}
 
-	dispatcher := dispatch.NewCheckUnmanagedDispatcher(s.informer.GetClientForCluster, fedResource.TargetKind(), fedResource.TargetName())
+	owner := util.OwnerKey(fedResource.FederatedKind(), fedResource.FederatedName())
+	dispatcher := dispatch.NewCheckUnmanagedDispatcher(s.clientForCluster, s.dispatchLimiter, fedResource.TargetKind(), fedResource.TargetName())
 	unreadyClusters := []string{}
 	for _, cluster := range clusters {
 		if !util.IsClusterReady(&cluster.Status) {
 			unreadyClusters = append(unreadyClusters, cluster.Name)
 			continue
 		}
-		dispatcher.CheckRemovedOrUnlabeled(cluster.Name, fedResource.IsNamespaceInHostCluster)
+		dispatcher.CheckRemovedOrUnlabeled(cluster.Name, owner, fedResource.TargetNameForCluster(cluster.Name), fedResource.IsNamespaceInHostCluster)
 	}
 	ok, timeoutErr := dispatcher.Wait()
 	if timeoutErr != nil {
@@ -573,8 +1170,10 @@ func (s *KubeFedSyncController) ensureRemovedOrUnmanaged(fedResource FederatedRe
 }
 
 // handleDeletionInClusters invokes the provided deletion handler for
-// each managed resource in member clusters.
-func (s *KubeFedSyncController) handleDeletionInClusters(kind string, qualifiedName util.QualifiedName,
+// each resource in member clusters that is not managed by a federated
+// resource other than the given owner.  If provided, nameForCluster
+// determines the name of the resource in a given cluster.
+func (s *KubeFedSyncController) handleDeletionInClusters(kind, owner string, qualifiedName util.QualifiedName, nameForCluster func(string) string,
 	deletionFunc func(dispatcher dispatch.UnmanagedDispatcher, clusterName string, clusterObj *unstructured.Unstructured)) (bool, error) {
 
 	clusters, err := s.informer.GetClusters()
@@ -582,8 +1181,7 @@ func (s *KubeFedSyncController) handleDeletionInClusters(kind string, qualifiedN
 		return false, errors.Wrap(err, "failed to get a list of clusters")
 	}
 
-	dispatcher := dispatch.NewUnmanagedDispatcher(s.informer.GetClientForCluster, kind, qualifiedName)
-	key := qualifiedName.String()
+	dispatcher := dispatch.NewUnmanagedDispatcher(s.clientForCluster, s.dispatchLimiter, kind, qualifiedName)
 	retrievalFailureClusters := []string{}
 	unreadyClusters := []string{}
 	for _, cluster := range clusters {
@@ -594,6 +1192,11 @@ func (s *KubeFedSyncController) handleDeletionInClusters(kind string, qualifiedN
 			continue
 		}
 
+		key := qualifiedName.String()
+		if nameForCluster != nil {
+			key = s.targetKeyForCluster(qualifiedName, nameForCluster(clusterName))
+		}
+
 		rawClusterObj, _, err := s.informer.GetTargetStore().GetByKey(clusterName, key)
 		if err != nil {
 			wrappedErr := errors.Wrapf(err, "failed to retrieve %s %q for cluster %q", kind, key, clusterName)
@@ -605,6 +1208,9 @@ func (s *KubeFedSyncController) handleDeletionInClusters(kind string, qualifiedN
 			continue
 		}
 		clusterObj := rawClusterObj.(*unstructured.Unstructured)
+		if util.OwnedByOther(clusterObj, owner) {
+			continue
+		}
 		deletionFunc(dispatcher, clusterName, clusterObj)
 	}
 	ok, timeoutErr := dispatcher.Wait()
@@ -0,0 +1,105 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog"
+
+	"sigs.k8s.io/kubefed/pkg/controller/sync/status"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+const (
+	// If this annotation is set to "Foreground" on a federated
+	// resource, resources in member clusters are deleted with
+	// foreground cascading and the progress of their removal is
+	// reported in the status of the federated resource.
+	DeletionPropagationAnnotation = "kubefed.k8s.io/deletion-propagation"
+	DeletionPropagationForeground = "Foreground"
+
+	// The duration after which a foreground deletion whose progress
+	// is blocked by finalizers in member clusters is reported as
+	// stuck.  Defaults to DefaultDeletionTimeout.
+	DeletionTimeoutAnnotation = "kubefed.k8s.io/deletion-timeout"
+
+	DefaultDeletionTimeout = 5 * time.Minute
+)
+
+// foregroundDeletion returns whether the given federated resource
+// requests foreground deletion and, if so, the duration after which
+// its deletion should be considered stuck.
+func foregroundDeletion(fedObj *unstructured.Unstructured) (bool, time.Duration) {
+	annotations := fedObj.GetAnnotations()
+	if annotations[DeletionPropagationAnnotation] != DeletionPropagationForeground {
+		return false, 0
+	}
+	value, ok := annotations[DeletionTimeoutAnnotation]
+	if !ok {
+		return true, DefaultDeletionTimeout
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout <= 0 {
+		klog.Warningf("Ignoring invalid value %q of annotation %q on %s %q", value, DeletionTimeoutAnnotation,
+			fedObj.GetKind(), util.NewQualifiedName(fedObj))
+		return true, DefaultDeletionTimeout
+	}
+	return true, timeout
+}
+
+// deletionProgress determines the deletion status of a federated
+// resource from the resources remaining in member clusters, keyed by
+// cluster name.  A remaining resource is considered blocked if it has
+// been marked for deletion but has finalizers preventing its removal
+// after the given timeout has elapsed since the federated resource was
+// marked for deletion.
+func deletionProgress(targetKind string, remaining map[string]*unstructured.Unstructured,
+	deletionTime time.Time, timeout time.Duration, now time.Time) (status.AggregateReason, string, status.PropagationStatusMap) {
+
+	clusterNames := []string{}
+	for clusterName := range remaining {
+		clusterNames = append(clusterNames, clusterName)
+	}
+	sort.Strings(clusterNames)
+
+	timedOut := now.Sub(deletionTime) > timeout
+	statusMap := make(status.PropagationStatusMap)
+	blocked := []string{}
+	for _, clusterName := range clusterNames {
+		clusterObj := remaining[clusterName]
+		finalizers := clusterObj.GetFinalizers()
+		if timedOut && clusterObj.GetDeletionTimestamp() != nil && len(finalizers) > 0 {
+			statusMap[clusterName] = status.DeletionBlocked
+			blocked = append(blocked, fmt.Sprintf("%s %q in cluster %q is blocked by finalizer(s) %s",
+				targetKind, util.NewQualifiedName(clusterObj), clusterName, strings.Join(finalizers, ", ")))
+			continue
+		}
+		statusMap[clusterName] = status.WaitingForRemoval
+	}
+
+	if len(blocked) > 0 {
+		message := fmt.Sprintf("Deletion did not complete within %v: %s", timeout, strings.Join(blocked, "; "))
+		return status.DeletionStuck, message, statusMap
+	}
+	message := fmt.Sprintf("Waiting for removal from cluster(s): %s", strings.Join(clusterNames, ", "))
+	return status.DeletionInProgress, message, statusMap
+}
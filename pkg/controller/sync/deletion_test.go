@@ -0,0 +1,110 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"sigs.k8s.io/kubefed/pkg/controller/sync/status"
+)
+
+func TestForegroundDeletion(t *testing.T) {
+	testCases := map[string]struct {
+		annotations        map[string]string
+		expectedForeground bool
+		expectedTimeout    time.Duration
+	}{
+		"No annotations": {},
+		"Background deletion": {
+			annotations: map[string]string{DeletionPropagationAnnotation: "Background"},
+		},
+		"Foreground deletion with the default timeout": {
+			annotations:        map[string]string{DeletionPropagationAnnotation: DeletionPropagationForeground},
+			expectedForeground: true,
+			expectedTimeout:    DefaultDeletionTimeout,
+		},
+		"Foreground deletion with a timeout": {
+			annotations: map[string]string{
+				DeletionPropagationAnnotation: DeletionPropagationForeground,
+				DeletionTimeoutAnnotation:     "90s",
+			},
+			expectedForeground: true,
+			expectedTimeout:    90 * time.Second,
+		},
+		"Foreground deletion with an invalid timeout": {
+			annotations: map[string]string{
+				DeletionPropagationAnnotation: DeletionPropagationForeground,
+				DeletionTimeoutAnnotation:     "soon",
+			},
+			expectedForeground: true,
+			expectedTimeout:    DefaultDeletionTimeout,
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			obj := &unstructured.Unstructured{}
+			obj.SetAnnotations(tc.annotations)
+			foreground, timeout := foregroundDeletion(obj)
+			assert.Equal(t, tc.expectedForeground, foreground)
+			assert.Equal(t, tc.expectedTimeout, timeout)
+		})
+	}
+}
+
+func TestDeletionProgress(t *testing.T) {
+	deletionTime := time.Now()
+	newClusterObj := func(deleting bool, finalizers ...string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetNamespace("ns")
+		obj.SetName("web")
+		if deleting {
+			deletionTimestamp := metav1.NewTime(deletionTime)
+			obj.SetDeletionTimestamp(&deletionTimestamp)
+		}
+		obj.SetFinalizers(finalizers)
+		return obj
+	}
+	remaining := map[string]*unstructured.Unstructured{
+		"cluster1": newClusterObj(true, "example.com/protect"),
+		"cluster2": newClusterObj(true),
+		"cluster3": newClusterObj(false),
+	}
+
+	reason, message, statusMap := deletionProgress("Deployment", remaining, deletionTime, time.Minute, deletionTime.Add(time.Second))
+	assert.Equal(t, status.DeletionInProgress, reason)
+	assert.Equal(t, "Waiting for removal from cluster(s): cluster1, cluster2, cluster3", message)
+	assert.Equal(t, status.PropagationStatusMap{
+		"cluster1": status.WaitingForRemoval,
+		"cluster2": status.WaitingForRemoval,
+		"cluster3": status.WaitingForRemoval,
+	}, statusMap)
+
+	reason, message, statusMap = deletionProgress("Deployment", remaining, deletionTime, time.Minute, deletionTime.Add(2*time.Minute))
+	assert.Equal(t, status.DeletionStuck, reason)
+	assert.Equal(t, `Deletion did not complete within 1m0s: Deployment "ns/web" in cluster "cluster1" is blocked by finalizer(s) example.com/protect`, message)
+	assert.Equal(t, status.PropagationStatusMap{
+		"cluster1": status.DeletionBlocked,
+		"cluster2": status.WaitingForRemoval,
+		"cluster3": status.WaitingForRemoval,
+	}, statusMap)
+}
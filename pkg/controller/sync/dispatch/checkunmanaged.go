@@ -26,6 +26,7 @@ import (
 	"k8s.io/klog"
 
 	"sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/controller/util/dispatchlimiter"
 )
 
 type isNamespaceInHostClusterFunc func(clusterObj pkgruntime.Object) bool
@@ -33,7 +34,7 @@ type isNamespaceInHostClusterFunc func(clusterObj pkgruntime.Object) bool
 type CheckUnmanagedDispatcher interface {
 	OperationDispatcher
 
-	CheckRemovedOrUnlabeled(clusterName string, isHostNamespace isNamespaceInHostClusterFunc)
+	CheckRemovedOrUnlabeled(clusterName, owner string, clusterTargetName util.QualifiedName, isHostNamespace isNamespaceInHostClusterFunc)
 }
 
 type checkUnmanagedDispatcherImpl struct {
@@ -43,8 +44,8 @@ type checkUnmanagedDispatcherImpl struct {
 	targetKind string
 }
 
-func NewCheckUnmanagedDispatcher(clientAccessor clientAccessorFunc, targetKind string, targetName util.QualifiedName) CheckUnmanagedDispatcher {
-	dispatcher := newOperationDispatcher(clientAccessor, nil)
+func NewCheckUnmanagedDispatcher(clientAccessor clientAccessorFunc, limiter *dispatchlimiter.Limiter, targetKind string, targetName util.QualifiedName) CheckUnmanagedDispatcher {
+	dispatcher := newOperationDispatcher(clientAccessor, limiter, nil)
 	return &checkUnmanagedDispatcherImpl{
 		dispatcher: dispatcher,
 		targetName: targetName,
@@ -58,15 +59,18 @@ func (d *checkUnmanagedDispatcherImpl) Wait() (bool, error) {
 
 // CheckRemovedOrUnlabeled checks that a resource either does not
 // exist in the given cluster, or if it does exist, that it does not
-// have the managed label.
-func (d *checkUnmanagedDispatcherImpl) CheckRemovedOrUnlabeled(clusterName string, isHostNamespace isNamespaceInHostClusterFunc) {
+// have the managed label.  A resource managed by a federated resource
+// other than the given owner is ignored.  The name of the resource in
+// the cluster is given since it may differ from the target name if its
+// namespace is mapped.
+func (d *checkUnmanagedDispatcherImpl) CheckRemovedOrUnlabeled(clusterName, owner string, clusterTargetName util.QualifiedName, isHostNamespace isNamespaceInHostClusterFunc) {
 	d.dispatcher.incrementOperationsInitiated()
 	const op = "check for deletion of resource or removal of managed label from"
 	const opContinuous = "Checking for deletion of resource or removal of managed label from"
 	go d.dispatcher.clusterOperation(clusterName, op, func(client util.ResourceClient) util.ReconciliationStatus {
 		klog.V(2).Infof(eventTemplate, opContinuous, d.targetKind, d.targetName, clusterName)
 
-		clusterObj, err := client.Resources(d.targetName.Namespace).Get(d.targetName.Name, metav1.GetOptions{})
+		clusterObj, err := client.Resources(clusterTargetName.Namespace).Get(clusterTargetName.Name, metav1.GetOptions{})
 		if apierrors.IsNotFound(err) {
 			return util.StatusAllOK
 		}
@@ -84,7 +88,7 @@ func (d *checkUnmanagedDispatcherImpl) CheckRemovedOrUnlabeled(clusterName strin
 			runtime.HandleError(wrappedErr)
 			return util.StatusError
 		}
-		if !util.HasManagedLabel(clusterObj) {
+		if !util.HasManagedLabel(clusterObj) || util.OwnedByOther(clusterObj, owner) {
 			return util.StatusAllOK
 		}
 		err = errors.Errorf("resource still has the managed label")
@@ -0,0 +1,142 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatch
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/controller/util/circuitbreaker"
+)
+
+// circuitOpenError indicates that an operation was not dispatched
+// since dispatch to the cluster is backed off.
+type circuitOpenError struct {
+	clusterName string
+	state       *circuitbreaker.State
+}
+
+func (e *circuitOpenError) Error() string {
+	if e.state == nil {
+		return fmt.Sprintf("Dispatch to cluster %q is backed off", e.clusterName)
+	}
+	return fmt.Sprintf("Dispatch to cluster %q is backed off until %s since requests to the cluster are failing: %v",
+		e.clusterName, e.state.ProbeAt.Format(time.RFC3339), e.state.LastError)
+}
+
+// CircuitBreakingClientAccessor returns a client accessor that fails
+// to return a client for a cluster whose dispatch is backed off by the
+// given breaker, and whose clients record the result of each request
+// with the breaker.
+func CircuitBreakingClientAccessor(clientAccessor func(clusterName string) (util.ResourceClient, error),
+	breaker *circuitbreaker.Breaker) func(clusterName string) (util.ResourceClient, error) {
+
+	if breaker == nil {
+		return clientAccessor
+	}
+	return func(clusterName string) (util.ResourceClient, error) {
+		if !breaker.Allow(clusterName) {
+			return nil, &circuitOpenError{clusterName: clusterName, state: breaker.OpenState(clusterName)}
+		}
+		client, err := clientAccessor(clusterName)
+		if err != nil {
+			return nil, err
+		}
+		return &circuitBreakingClient{ResourceClient: client, breaker: breaker, clusterName: clusterName}, nil
+	}
+}
+
+type circuitBreakingClient struct {
+	util.ResourceClient
+	breaker     *circuitbreaker.Breaker
+	clusterName string
+}
+
+func (c *circuitBreakingClient) Resources(namespace string) dynamic.ResourceInterface {
+	return &circuitBreakingResources{
+		ResourceInterface: c.ResourceClient.Resources(namespace),
+		record: func(err error) {
+			c.breaker.RecordResult(c.clusterName, err)
+		},
+	}
+}
+
+type circuitBreakingResources struct {
+	dynamic.ResourceInterface
+	record func(err error)
+}
+
+func (r *circuitBreakingResources) Create(obj *unstructured.Unstructured, options metav1.CreateOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	result, err := r.ResourceInterface.Create(obj, options, subresources...)
+	r.record(err)
+	return result, err
+}
+
+func (r *circuitBreakingResources) Update(obj *unstructured.Unstructured, options metav1.UpdateOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	result, err := r.ResourceInterface.Update(obj, options, subresources...)
+	r.record(err)
+	return result, err
+}
+
+func (r *circuitBreakingResources) UpdateStatus(obj *unstructured.Unstructured, options metav1.UpdateOptions) (*unstructured.Unstructured, error) {
+	result, err := r.ResourceInterface.UpdateStatus(obj, options)
+	r.record(err)
+	return result, err
+}
+
+func (r *circuitBreakingResources) Delete(name string, options *metav1.DeleteOptions, subresources ...string) error {
+	err := r.ResourceInterface.Delete(name, options, subresources...)
+	r.record(err)
+	return err
+}
+
+func (r *circuitBreakingResources) DeleteCollection(options *metav1.DeleteOptions, listOptions metav1.ListOptions) error {
+	err := r.ResourceInterface.DeleteCollection(options, listOptions)
+	r.record(err)
+	return err
+}
+
+func (r *circuitBreakingResources) Get(name string, options metav1.GetOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	result, err := r.ResourceInterface.Get(name, options, subresources...)
+	r.record(err)
+	return result, err
+}
+
+func (r *circuitBreakingResources) List(opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	result, err := r.ResourceInterface.List(opts)
+	r.record(err)
+	return result, err
+}
+
+func (r *circuitBreakingResources) Watch(opts metav1.ListOptions) (watch.Interface, error) {
+	result, err := r.ResourceInterface.Watch(opts)
+	r.record(err)
+	return result, err
+}
+
+func (r *circuitBreakingResources) Patch(name string, pt types.PatchType, data []byte, options metav1.UpdateOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	result, err := r.ResourceInterface.Patch(name, pt, data, options, subresources...)
+	r.record(err)
+	return result, err
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatch
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/controller/util/deletionlimiter"
+)
+
+// DeletionRecordingClientAccessor returns a client accessor whose
+// clients record each deletion that succeeds with the given deletion
+// limiter, and release the slot reserved for each deletion that
+// fails, so that only deletions that were made count against the
+// limit of a cluster.
+func DeletionRecordingClientAccessor(clientAccessor func(clusterName string) (util.ResourceClient, error),
+	limiter *deletionlimiter.Limiter) func(clusterName string) (util.ResourceClient, error) {
+
+	if limiter == nil {
+		return clientAccessor
+	}
+	return func(clusterName string) (util.ResourceClient, error) {
+		client, err := clientAccessor(clusterName)
+		if err != nil {
+			return nil, err
+		}
+		return &deletionRecordingClient{ResourceClient: client, limiter: limiter, clusterName: clusterName}, nil
+	}
+}
+
+type deletionRecordingClient struct {
+	util.ResourceClient
+	limiter     *deletionlimiter.Limiter
+	clusterName string
+}
+
+func (c *deletionRecordingClient) Resources(namespace string) dynamic.ResourceInterface {
+	return &deletionRecordingResources{
+		ResourceInterface: c.ResourceClient.Resources(namespace),
+		record: func() {
+			c.limiter.Record(c.clusterName)
+		},
+		release: func() {
+			c.limiter.Release(c.clusterName)
+		},
+	}
+}
+
+type deletionRecordingResources struct {
+	dynamic.ResourceInterface
+	record  func()
+	release func()
+}
+
+func (r *deletionRecordingResources) Delete(name string, options *metav1.DeleteOptions, subresources ...string) error {
+	err := r.ResourceInterface.Delete(name, options, subresources...)
+	if err == nil {
+		r.record()
+	} else {
+		r.release()
+	}
+	return err
+}
@@ -20,30 +20,41 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 
 	"sigs.k8s.io/kubefed/pkg/controller/sync/status"
 	"sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/controller/util/dispatchlimiter"
 )
 
 // FederatedResourceForDispatch is the subset of the FederatedResource
 // interface required for dispatching operations to managed resources.
 type FederatedResourceForDispatch interface {
 	TargetName() util.QualifiedName
+	TargetNameForCluster(clusterName string) util.QualifiedName
 	TargetKind() string
 	Object() *unstructured.Unstructured
 	VersionForCluster(clusterName string) (string, error)
 	ObjectForCluster(clusterName string) (*unstructured.Unstructured, error)
+	LocallyManagedFields() []string
 	RecordError(errorCode string, err error)
 	RecordEvent(reason, messageFmt string, args ...interface{})
 }
 
+// ObjectValidatorFunc validates a resource rendered for the named
+// member cluster before it is written to the cluster.  The resource
+// currently in the cluster is nil for a creation.  An error prevents
+// the resource from being propagated to the cluster.
+type ObjectValidatorFunc func(clusterName string, obj, clusterObj *unstructured.Unstructured) error
+
 // ManagedDispatcher dispatches operations to member clusters for resources
 // managed by a federated resource.
 type ManagedDispatcher interface {
@@ -52,7 +63,10 @@ type ManagedDispatcher interface {
 	Create(clusterName string)
 	Update(clusterName string, clusterObj *unstructured.Unstructured)
 	VersionMap() map[string]string
+	AppliedTimes() status.ClusterAppliedTimeMap
 	StatusMap() status.PropagationStatusMap
+	DriftMap() status.ClusterDriftMap
+	ConflictMap() map[string]string
 
 	RecordClusterError(propStatus status.PropagationStatus, clusterName string, err error)
 	RecordStatus(clusterName string, propStatus status.PropagationStatus)
@@ -65,18 +79,44 @@ type managedDispatcherImpl struct {
 	unmanagedDispatcher   *unmanagedDispatcherImpl
 	fedResource           FederatedResourceForDispatch
 	versionMap            map[string]string
+	appliedTimes          status.ClusterAppliedTimeMap
 	statusMap             status.PropagationStatusMap
+	driftMap              status.ClusterDriftMap
+	conflictMap           map[string]string
 	skipAdoptingResources bool
+	// Whether resources that already exist in member clusters are
+	// only reported as adoptable rather than adopted.
+	reportAdoptableResources bool
+	validateObject           ObjectValidatorFunc
+	// Whether operations that would write to member clusters are
+	// only recorded as paused.
+	paused bool
 }
 
-func NewManagedDispatcher(clientAccessor clientAccessorFunc, fedResource FederatedResourceForDispatch, skipAdoptingResources bool) ManagedDispatcher {
+// NewManagedDispatcher returns a dispatcher for the resources managed
+// by the given federated resource.  The limiter and validator are
+// optional.  A paused dispatcher does not write to member clusters,
+// and instead records the status of clusters whose resource would
+// have been created, updated or removed as paused.  Unless adoption
+// is skipped, a dispatcher reporting adoptable resources records the
+// status of clusters with an existing unmanaged resource as
+// adoptable instead of adopting the resource.
+func NewManagedDispatcher(clientAccessor clientAccessorFunc, limiter *dispatchlimiter.Limiter, fedResource FederatedResourceForDispatch,
+	skipAdoptingResources, reportAdoptableResources, paused bool, validateObject ObjectValidatorFunc) ManagedDispatcher {
+
 	d := &managedDispatcherImpl{
-		fedResource:           fedResource,
-		versionMap:            make(map[string]string),
-		statusMap:             make(status.PropagationStatusMap),
-		skipAdoptingResources: skipAdoptingResources,
+		fedResource:              fedResource,
+		versionMap:               make(map[string]string),
+		appliedTimes:             make(status.ClusterAppliedTimeMap),
+		statusMap:                make(status.PropagationStatusMap),
+		driftMap:                 make(status.ClusterDriftMap),
+		conflictMap:              make(map[string]string),
+		skipAdoptingResources:    skipAdoptingResources,
+		reportAdoptableResources: reportAdoptableResources,
+		validateObject:           validateObject,
+		paused:                   paused,
 	}
-	d.dispatcher = newOperationDispatcher(clientAccessor, d)
+	d.dispatcher = newOperationDispatcher(clientAccessor, limiter, d)
 	d.unmanagedDispatcher = newUnmanagedDispatcher(d.dispatcher, d, fedResource.TargetKind(), fedResource.TargetName())
 	return d
 }
@@ -115,6 +155,11 @@ func (d *managedDispatcherImpl) Wait() (bool, error) {
 }
 
 func (d *managedDispatcherImpl) Create(clusterName string) {
+	if d.paused {
+		d.RecordStatus(clusterName, status.ClusterPropagationPaused)
+		return
+	}
+
 	// Default the status to an operation-specific timeout.  Otherwise
 	// when a timeout occurs it won't be possible to determine which
 	// operation timed out.  The timeout status will be cleared by
@@ -130,10 +175,17 @@ func (d *managedDispatcherImpl) Create(clusterName string) {
 		if err != nil {
 			return d.recordOperationError(status.ComputeResourceFailed, clusterName, op, err)
 		}
+		if err := d.validate(clusterName, obj, nil); err != nil {
+			return d.recordOperationError(status.PolicyViolation, clusterName, op, err)
+		}
+		if err := util.SetLastAppliedConfigAnnotation(obj); err != nil {
+			return d.recordOperationError(status.ComputeResourceFailed, clusterName, op, err)
+		}
 		createdObj, err := client.Resources(obj.GetNamespace()).Create(obj, metav1.CreateOptions{})
 		if err == nil {
 			version := util.ObjectVersion(createdObj)
 			d.recordVersion(clusterName, version)
+			d.recordApplied(clusterName)
 			return util.StatusAllOK
 		}
 
@@ -156,12 +208,41 @@ func (d *managedDispatcherImpl) Create(clusterName string) {
 			wrappedErr := errors.Wrapf(err, "failed to retrieve object potentially requiring adoption")
 			return d.recordOperationError(status.RetrievalFailed, clusterName, op, wrappedErr)
 		}
+		if d.reportAdoptableResources {
+			return d.reportAdoption(clusterName, obj, clusterObj)
+		}
 		d.recordError(clusterName, op, errors.Errorf("An update will be attempted instead of a creation due to an existing resource"))
 		d.Update(clusterName, clusterObj)
 		return util.StatusAllOK
 	})
 }
 
+// reportAdoption records the status of the named cluster as adoptable
+// without modifying the existing resource, along with the fields that
+// adopting the resource would change.
+func (d *managedDispatcherImpl) reportAdoption(clusterName string, obj, clusterObj *unstructured.Unstructured) util.ReconciliationStatus {
+	const op = "adopt"
+	if util.OwnedByOther(clusterObj, util.Owner(obj)) {
+		owner := util.Owner(clusterObj)
+		d.recordConflict(clusterName, owner)
+		err := errors.Errorf("the resource is managed by %s", owner)
+		return d.recordOperationError(status.ClusterOwnershipConflict, clusterName, op, err)
+	}
+	err := RetainClusterFields(d.fedResource.TargetKind(), obj, clusterObj, d.fedResource.Object(), d.fedResource.LocallyManagedFields())
+	if err != nil {
+		wrappedErr := errors.Wrapf(err, "failed to retain fields")
+		return d.recordOperationError(status.FieldRetentionFailed, clusterName, op, wrappedErr)
+	}
+	changes := "no changes"
+	if changedPaths := util.DriftedPaths(obj, clusterObj); len(changedPaths) > 0 {
+		changes = "changes to " + strings.Join(changedPaths, ", ")
+	}
+	d.fedResource.RecordEvent("AdoptableInCluster", "%s %q exists in cluster %q and would be adopted with %s",
+		d.fedResource.TargetKind(), d.fedResource.TargetName(), clusterName, changes)
+	d.RecordStatus(clusterName, status.WouldAdopt)
+	return util.StatusAllOK
+}
+
 func (d *managedDispatcherImpl) Update(clusterName string, clusterObj *unstructured.Unstructured) {
 	d.RecordStatus(clusterName, status.UpdateTimedOut)
 
@@ -173,11 +254,23 @@ func (d *managedDispatcherImpl) Update(clusterName string, clusterObj *unstructu
 			return d.recordOperationError(status.ComputeResourceFailed, clusterName, op, err)
 		}
 
-		err = RetainClusterFields(d.fedResource.TargetKind(), obj, clusterObj, d.fedResource.Object())
+		// A resource managed by another federated resource is left
+		// to its owner rather than being updated in turn by both.
+		if util.OwnedByOther(clusterObj, util.Owner(obj)) {
+			owner := util.Owner(clusterObj)
+			d.recordConflict(clusterName, owner)
+			err := errors.Errorf("the resource is managed by %s", owner)
+			return d.recordOperationError(status.ClusterOwnershipConflict, clusterName, op, err)
+		}
+
+		err = RetainClusterFields(d.fedResource.TargetKind(), obj, clusterObj, d.fedResource.Object(), d.fedResource.LocallyManagedFields())
 		if err != nil {
 			wrappedErr := errors.Wrapf(err, "failed to retain fields")
 			return d.recordOperationError(status.FieldRetentionFailed, clusterName, op, wrappedErr)
 		}
+		if err := util.SetLastAppliedConfigAnnotation(obj); err != nil {
+			return d.recordOperationError(status.ComputeResourceFailed, clusterName, op, err)
+		}
 
 		version, err := d.fedResource.VersionForCluster(clusterName)
 		if err != nil {
@@ -188,26 +281,69 @@ func (d *managedDispatcherImpl) Update(clusterName string, clusterObj *unstructu
 			return util.StatusAllOK
 		}
 
+		patch, err := util.ThreeWayMergePatch(obj, clusterObj)
+		if err != nil {
+			return d.recordOperationError(status.ComputeResourceFailed, clusterName, op, err)
+		}
+		if patch == nil {
+			// The version of the resource has changed (e.g. due to
+			// defaulting or a change to fields not managed by KubeFed)
+			// but the resource still reflects the desired state.
+			// Recording the current version avoids an update.
+			d.recordVersion(clusterName, util.ObjectVersion(clusterObj))
+			return util.StatusAllOK
+		}
+
+		if err := d.validate(clusterName, obj, clusterObj); err != nil {
+			return d.recordOperationError(status.PolicyViolation, clusterName, op, err)
+		}
+
+		d.recordDrift(clusterName, obj, clusterObj, version)
+
+		if d.paused {
+			d.RecordStatus(clusterName, status.ClusterPropagationPaused)
+			return util.StatusAllOK
+		}
+
 		// Only record an event if the resource is not current
 		d.recordEvent(clusterName, op, "Updating")
 
-		updatedObj, err := client.Resources(obj.GetNamespace()).Update(obj, metav1.UpdateOptions{})
+		updatedObj, err := client.Resources(obj.GetNamespace()).Patch(obj.GetName(), types.MergePatchType, patch, metav1.UpdateOptions{})
 		if err != nil {
 			return d.recordOperationError(status.UpdateFailed, clusterName, op, err)
 		}
 		version = util.ObjectVersion(updatedObj)
 		d.recordVersion(clusterName, version)
+		d.recordApplied(clusterName)
 		return util.StatusAllOK
 	})
 }
 
-func (d *managedDispatcherImpl) Delete(clusterName string) {
+func (d *managedDispatcherImpl) Delete(clusterName string, clusterObj *unstructured.Unstructured) {
+	if d.paused {
+		d.RecordStatus(clusterName, status.ClusterPropagationPaused)
+		return
+	}
+	d.RecordStatus(clusterName, status.DeletionTimedOut)
+
+	d.unmanagedDispatcher.Delete(clusterName, clusterObj)
+}
+
+func (d *managedDispatcherImpl) DeleteInForeground(clusterName string, clusterObj *unstructured.Unstructured) {
+	if d.paused {
+		d.RecordStatus(clusterName, status.ClusterPropagationPaused)
+		return
+	}
 	d.RecordStatus(clusterName, status.DeletionTimedOut)
 
-	d.unmanagedDispatcher.Delete(clusterName)
+	d.unmanagedDispatcher.DeleteInForeground(clusterName, clusterObj)
 }
 
 func (d *managedDispatcherImpl) RemoveManagedLabel(clusterName string, clusterObj *unstructured.Unstructured) {
+	if d.paused {
+		d.RecordStatus(clusterName, status.ClusterPropagationPaused)
+		return
+	}
 	d.RecordStatus(clusterName, status.LabelRemovalTimedOut)
 
 	d.unmanagedDispatcher.RemoveManagedLabel(clusterName, clusterObj)
@@ -230,6 +366,15 @@ func (d *managedDispatcherImpl) recordOperationError(propStatus status.Propagati
 	return util.StatusError
 }
 
+// validate checks the resource rendered for the named cluster with
+// the validator of the dispatcher, if any.
+func (d *managedDispatcherImpl) validate(clusterName string, obj, clusterObj *unstructured.Unstructured) error {
+	if d.validateObject == nil {
+		return nil
+	}
+	return d.validateObject(clusterName, obj, clusterObj)
+}
+
 func (d *managedDispatcherImpl) recordError(clusterName, operation string, err error) {
 	args := []interface{}{operation, d.fedResource.TargetKind(), d.fedResource.TargetName(), clusterName}
 	eventType := fmt.Sprintf("%sInClusterFailed", strings.Replace(strings.Title(operation), " ", "", -1))
@@ -258,6 +403,84 @@ func (d *managedDispatcherImpl) recordVersion(clusterName, version string) {
 	d.versionMap[clusterName] = version
 }
 
+// AppliedTimes returns the times the resource was created or updated
+// in the clusters it was written to.
+func (d *managedDispatcherImpl) AppliedTimes() status.ClusterAppliedTimeMap {
+	d.RLock()
+	defer d.RUnlock()
+	appliedTimes := make(status.ClusterAppliedTimeMap)
+	for key, value := range d.appliedTimes {
+		appliedTimes[key] = value
+	}
+	return appliedTimes
+}
+
+func (d *managedDispatcherImpl) recordApplied(clusterName string) {
+	d.Lock()
+	defer d.Unlock()
+	d.appliedTimes[clusterName] = time.Now().UTC().Format(time.RFC3339)
+}
+
+// recordDrift records a summary of the difference between the
+// desired and cluster objects if the cluster object was modified
+// since it was last written by the sync controller.  A recorded
+// version that is empty indicates that the desired state has changed
+// or that the resource has not previously been propagated, and in
+// either case a difference is expected.
+func (d *managedDispatcherImpl) recordDrift(clusterName string, desiredObj, clusterObj *unstructured.Unstructured, recordedVersion string) {
+	if len(recordedVersion) == 0 || recordedVersion == util.ObjectVersion(clusterObj) {
+		return
+	}
+	changedPaths := util.DriftedPaths(desiredObj, clusterObj)
+	if len(changedPaths) == 0 {
+		return
+	}
+	drift := &status.ClusterDrift{
+		ChangedPaths: changedPaths,
+		Managers:     util.DriftManagers(clusterObj),
+		DetectedTime: time.Now().UTC().Format(time.RFC3339),
+	}
+
+	d.Lock()
+	d.driftMap[clusterName] = drift
+	d.Unlock()
+
+	err := errors.Errorf("%s %q in cluster %q was modified outside of KubeFed (changed: %s)",
+		d.fedResource.TargetKind(), d.fedResource.TargetName(), clusterName, strings.Join(changedPaths, ", "))
+	d.fedResource.RecordError("DriftDetected", err)
+}
+
+func (d *managedDispatcherImpl) DriftMap() status.ClusterDriftMap {
+	d.RLock()
+	defer d.RUnlock()
+	driftMap := make(status.ClusterDriftMap)
+	for key, value := range d.driftMap {
+		driftMap[key] = value
+	}
+	return driftMap
+}
+
+// recordConflict records the owner of the resource in the named
+// cluster that is managed by another federated resource.
+func (d *managedDispatcherImpl) recordConflict(clusterName, owner string) {
+	d.Lock()
+	defer d.Unlock()
+	d.conflictMap[clusterName] = owner
+}
+
+// ConflictMap returns the owners of the resources that could not be
+// updated since they are managed by another federated resource, by
+// cluster.
+func (d *managedDispatcherImpl) ConflictMap() map[string]string {
+	d.RLock()
+	defer d.RUnlock()
+	conflictMap := make(map[string]string, len(d.conflictMap))
+	for clusterName, owner := range d.conflictMap {
+		conflictMap[clusterName] = owner
+	}
+	return conflictMap
+}
+
 func (d *managedDispatcherImpl) StatusMap() status.PropagationStatusMap {
 	d.RLock()
 	defer d.RUnlock()
@@ -17,6 +17,7 @@ limitations under the License.
 package dispatch
 
 import (
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -26,6 +27,7 @@ import (
 
 	"sigs.k8s.io/kubefed/pkg/controller/sync/status"
 	"sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/controller/util/dispatchlimiter"
 )
 
 type clientAccessorFunc func(clusterName string) (util.ResourceClient, error)
@@ -46,25 +48,37 @@ type OperationDispatcher interface {
 
 type operationDispatcherImpl struct {
 	clientAccessor clientAccessorFunc
+	limiter        *dispatchlimiter.Limiter
 
 	resultChan          chan util.ReconciliationStatus
 	operationsInitiated int32
 
+	// waitDone is closed once Wait returns so that operations
+	// completing or starting after a timeout do not block.
+	waitDone     chan struct{}
+	waitDoneOnce sync.Once
+
 	timeout time.Duration
 
 	recorder dispatchRecorder
 }
 
-func newOperationDispatcher(clientAccessor clientAccessorFunc, recorder dispatchRecorder) *operationDispatcherImpl {
+func newOperationDispatcher(clientAccessor clientAccessorFunc, limiter *dispatchlimiter.Limiter, recorder dispatchRecorder) *operationDispatcherImpl {
 	return &operationDispatcherImpl{
 		clientAccessor: clientAccessor,
+		limiter:        limiter,
 		resultChan:     make(chan util.ReconciliationStatus),
+		waitDone:       make(chan struct{}),
 		timeout:        30 * time.Second, // TODO(marun) Make this configurable
 		recorder:       recorder,
 	}
 }
 
 func (d *operationDispatcherImpl) Wait() (bool, error) {
+	defer d.waitDoneOnce.Do(func() {
+		close(d.waitDone)
+	})
+
 	ok := true
 	timedOut := false
 	start := time.Now()
@@ -96,19 +110,38 @@ func (d *operationDispatcherImpl) clusterOperation(clusterName, op string, opFun
 	// on timeout.
 	client, err := d.clientAccessor(clusterName)
 	if err != nil {
+		propStatus := status.ClientRetrievalFailed
 		wrappedErr := errors.Wrapf(err, "Error retrieving client for cluster")
+		if _, ok := err.(*circuitOpenError); ok {
+			propStatus = status.ClusterDegraded
+			wrappedErr = err
+		}
 		if d.recorder == nil {
 			runtime.HandleError(wrappedErr)
 		} else {
-			d.recorder.recordOperationError(status.ClientRetrievalFailed, clusterName, op, wrappedErr)
+			d.recorder.recordOperationError(propStatus, clusterName, op, wrappedErr)
 		}
-		d.resultChan <- util.StatusError
+		d.sendResult(util.StatusError)
 		return
 	}
 
+	// Operations that could not be started before Wait timed out are
+	// abandoned, and retain the timed out status of their cluster.
+	if !d.limiter.Acquire(clusterName, d.waitDone) {
+		return
+	}
+	defer d.limiter.Release(clusterName)
+
 	// TODO(marun) Retry on recoverable errors (e.g. IsConflict, AlreadyExists)
 	ok := opFunc(client)
-	d.resultChan <- ok
+	d.sendResult(ok)
+}
+
+func (d *operationDispatcherImpl) sendResult(result util.ReconciliationStatus) {
+	select {
+	case d.resultChan <- result:
+	case <-d.waitDone:
+	}
 }
 
 func (d *operationDispatcherImpl) incrementOperationsInitiated() {
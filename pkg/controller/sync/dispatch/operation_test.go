@@ -0,0 +1,327 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatch
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/kubefed/pkg/controller/sync/status"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/controller/util/dispatchlimiter"
+)
+
+// fakeFederatedResource renders the same configmap for every cluster.
+type fakeFederatedResource struct{}
+
+func (r *fakeFederatedResource) TargetName() util.QualifiedName {
+	return util.QualifiedName{Namespace: "ns", Name: "config"}
+}
+
+func (r *fakeFederatedResource) TargetNameForCluster(clusterName string) util.QualifiedName {
+	return r.TargetName()
+}
+
+func (r *fakeFederatedResource) TargetKind() string {
+	return "ConfigMap"
+}
+
+func (r *fakeFederatedResource) Object() *unstructured.Unstructured {
+	return &unstructured.Unstructured{}
+}
+
+func (r *fakeFederatedResource) VersionForCluster(clusterName string) (string, error) {
+	return "", nil
+}
+
+func (r *fakeFederatedResource) ObjectForCluster(clusterName string) (*unstructured.Unstructured, error) {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("ConfigMap")
+	obj.SetNamespace("ns")
+	obj.SetName("config")
+	return obj, nil
+}
+
+func (r *fakeFederatedResource) LocallyManagedFields() []string {
+	return nil
+}
+
+func (r *fakeFederatedResource) RecordError(errorCode string, err error) {}
+
+func (r *fakeFederatedResource) RecordEvent(reason, messageFmt string, args ...interface{}) {}
+
+// fakeClusters serves the creations of the clusters of a test after
+// a fixed latency, and tracks the number of creations in progress.
+type fakeClusters struct {
+	latency time.Duration
+	// If non-nil, creations block until the channel is closed.
+	block chan struct{}
+	// Resources that already exist, by cluster.  Their creation
+	// fails and they are served by retrieval.
+	existing map[string]*unstructured.Unstructured
+
+	sync.Mutex
+	created              int
+	inProgress           map[string]int
+	maxClusterInProgress int
+	totalInProgress      int
+	maxTotalInProgress   int
+}
+
+func newFakeClusters(latency time.Duration) *fakeClusters {
+	return &fakeClusters{
+		latency:    latency,
+		inProgress: make(map[string]int),
+	}
+}
+
+func (c *fakeClusters) clientForCluster(clusterName string) (util.ResourceClient, error) {
+	return &fakeResourceClient{clusters: c, clusterName: clusterName}, nil
+}
+
+func (c *fakeClusters) create(clusterName string, obj *unstructured.Unstructured) *unstructured.Unstructured {
+	c.Lock()
+	c.created++
+	c.inProgress[clusterName]++
+	c.totalInProgress++
+	if c.inProgress[clusterName] > c.maxClusterInProgress {
+		c.maxClusterInProgress = c.inProgress[clusterName]
+	}
+	if c.totalInProgress > c.maxTotalInProgress {
+		c.maxTotalInProgress = c.totalInProgress
+	}
+	c.Unlock()
+
+	if c.block != nil {
+		<-c.block
+	}
+	time.Sleep(c.latency)
+
+	c.Lock()
+	c.inProgress[clusterName]--
+	c.totalInProgress--
+	c.Unlock()
+
+	createdObj := obj.DeepCopy()
+	createdObj.SetResourceVersion("1")
+	return createdObj
+}
+
+func (c *fakeClusters) createdCount() int {
+	c.Lock()
+	defer c.Unlock()
+	return c.created
+}
+
+type fakeResourceClient struct {
+	clusters    *fakeClusters
+	clusterName string
+}
+
+func (c *fakeResourceClient) Resources(namespace string) dynamic.ResourceInterface {
+	return &fakeResources{client: c}
+}
+
+func (c *fakeResourceClient) Kind() string {
+	return "ConfigMap"
+}
+
+// fakeResources only implements creation and retrieval.
+type fakeResources struct {
+	dynamic.ResourceInterface
+	client *fakeResourceClient
+}
+
+func (r *fakeResources) Create(obj *unstructured.Unstructured, options metav1.CreateOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	if _, ok := r.client.clusters.existing[r.client.clusterName]; ok {
+		return nil, apierrors.NewAlreadyExists(schema.GroupResource{Resource: "configmaps"}, obj.GetName())
+	}
+	return r.client.clusters.create(r.client.clusterName, obj), nil
+}
+
+func (r *fakeResources) Get(name string, options metav1.GetOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	obj, ok := r.client.clusters.existing[r.client.clusterName]
+	if !ok {
+		return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, name)
+	}
+	return obj.DeepCopy(), nil
+}
+
+func clusterNames(count int) []string {
+	names := make([]string, count)
+	for i := range names {
+		names[i] = fmt.Sprintf("cluster%d", i)
+	}
+	return names
+}
+
+// dispatchCreations creates the fake resource in the given clusters
+// and waits for the creations to complete.
+func dispatchCreations(clusters *fakeClusters, limiter *dispatchlimiter.Limiter, names []string) (bool, error) {
+	dispatcher := NewManagedDispatcher(clusters.clientForCluster, limiter, &fakeFederatedResource{}, false, false, false, nil)
+	for _, name := range names {
+		dispatcher.Create(name)
+	}
+	return dispatcher.Wait()
+}
+
+func TestDispatchWithinBounds(t *testing.T) {
+	clusters := newFakeClusters(10 * time.Millisecond)
+	limiter := dispatchlimiter.New(&fedv1b1.DispatchConcurrencyConfig{MaxPerCluster: 2, MaxTotal: 5})
+	names := clusterNames(4)
+
+	// Dispatch several resources concurrently, as the sync
+	// controllers of different types would.
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ok, err := dispatchCreations(clusters, limiter, names)
+			assert.NoError(t, err)
+			assert.True(t, ok)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 20, clusters.createdCount())
+	assert.True(t, clusters.maxClusterInProgress <= 2, "At most 2 operations should be in progress per cluster, got %d", clusters.maxClusterInProgress)
+	assert.True(t, clusters.maxTotalInProgress <= 5, "At most 5 operations should be in progress in total, got %d", clusters.maxTotalInProgress)
+	assert.True(t, clusters.maxTotalInProgress > 1, "Operations against different clusters should be in progress concurrently")
+}
+
+func TestWaitTimeoutAbandonsOperations(t *testing.T) {
+	clusters := newFakeClusters(0)
+	clusters.block = make(chan struct{})
+	limiter := dispatchlimiter.New(&fedv1b1.DispatchConcurrencyConfig{MaxTotal: 1})
+
+	d := NewManagedDispatcher(clusters.clientForCluster, limiter, &fakeFederatedResource{}, false, false, false, nil).(*managedDispatcherImpl)
+	d.dispatcher.timeout = 50 * time.Millisecond
+	d.Create("cluster1")
+	d.Create("cluster2")
+	ok, err := d.Wait()
+	assert.False(t, ok)
+	assert.Error(t, err)
+
+	// Once the creation in progress completes its result must not
+	// block, and the creation that could not be started must not be
+	// attempted.  The slot of the limiter is only released once the
+	// result was sent.
+	close(clusters.block)
+	assert.True(t, limiter.Acquire("cluster3", nil))
+	assert.Equal(t, 1, clusters.createdCount())
+}
+
+func TestPausedDispatcherDoesNotWrite(t *testing.T) {
+	clusters := newFakeClusters(0)
+	d := NewManagedDispatcher(clusters.clientForCluster, nil, &fakeFederatedResource{}, false, false, true, nil)
+
+	// The fake resources only implement creation, so any other write
+	// would panic.
+	clusterObj, _ := (&fakeFederatedResource{}).ObjectForCluster("cluster2")
+	clusterObj.SetResourceVersion("1")
+	clusterObj.Object["data"] = map[string]interface{}{"key": "value"}
+	d.Create("cluster1")
+	d.Update("cluster2", clusterObj)
+	d.Delete("cluster3", clusterObj)
+	d.RemoveManagedLabel("cluster4", clusterObj)
+	ok, err := d.Wait()
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	assert.Equal(t, 0, clusters.createdCount())
+	for _, clusterName := range []string{"cluster1", "cluster2", "cluster3", "cluster4"} {
+		assert.Equal(t, status.ClusterPropagationPaused, d.StatusMap()[clusterName], "Unexpected status for %q", clusterName)
+	}
+	assert.Empty(t, d.VersionMap())
+	assert.Empty(t, d.AppliedTimes())
+}
+
+func TestReportOnlyDispatcherDoesNotAdopt(t *testing.T) {
+	clusters := newFakeClusters(0)
+	existing, _ := (&fakeFederatedResource{}).ObjectForCluster("cluster1")
+	existing.Object["data"] = map[string]interface{}{"key": "value"}
+	owned := existing.DeepCopy()
+	owned.SetAnnotations(map[string]string{util.OwnerAnnotation: "FederatedObject/ns/config"})
+	clusters.existing = map[string]*unstructured.Unstructured{
+		"cluster1": existing,
+		"cluster2": owned,
+	}
+	d := NewManagedDispatcher(clusters.clientForCluster, nil, &fakeFederatedResource{}, false, true, false, nil)
+
+	// The fake resources only implement creation and retrieval, so
+	// adopting the existing resources would panic.
+	d.Create("cluster1")
+	d.Create("cluster2")
+	d.Create("cluster3")
+	ok, err := d.Wait()
+	assert.NoError(t, err)
+	assert.False(t, ok, "The ownership conflict should be reported as an error")
+
+	assert.Equal(t, status.WouldAdopt, d.StatusMap()["cluster1"])
+	assert.Equal(t, status.ClusterOwnershipConflict, d.StatusMap()["cluster2"])
+	assert.Equal(t, status.ClusterPropagationOK, d.StatusMap()["cluster3"])
+	assert.Equal(t, map[string]string{"cluster2": "FederatedObject/ns/config"}, d.ConflictMap())
+	assert.Equal(t, 1, clusters.createdCount())
+	assert.Empty(t, d.AppliedTimes()["cluster1"])
+}
+
+// BenchmarkDispatch measures the time to propagate a resource to all
+// clusters of a placement when each request to a cluster takes
+// 2ms.  Serialized dispatch is emulated by bounding the total to a
+// single operation.
+func BenchmarkDispatch(b *testing.B) {
+	latency := 2 * time.Millisecond
+	limiters := []struct {
+		name   string
+		config *fedv1b1.DispatchConcurrencyConfig
+	}{
+		{"Serialized", &fedv1b1.DispatchConcurrencyConfig{MaxTotal: 1}},
+		{"Bounded", &fedv1b1.DispatchConcurrencyConfig{
+			MaxPerCluster: dispatchlimiter.DefaultMaxPerCluster,
+			MaxTotal:      dispatchlimiter.DefaultMaxTotal,
+		}},
+		{"Unbounded", nil},
+	}
+	for _, clusterCount := range []int{10, 50, 100} {
+		names := clusterNames(clusterCount)
+		for _, l := range limiters {
+			b.Run(fmt.Sprintf("%s/Clusters=%d", l.name, clusterCount), func(b *testing.B) {
+				clusters := newFakeClusters(latency)
+				limiter := dispatchlimiter.New(l.config)
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					if ok, err := dispatchCreations(clusters, limiter, names); !ok || err != nil {
+						b.Fatalf("Dispatch failed: %v", err)
+					}
+				}
+			})
+		}
+	}
+}
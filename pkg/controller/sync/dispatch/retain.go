@@ -17,6 +17,8 @@ limitations under the License.
 package dispatch
 
 import (
+	"strings"
+
 	"github.com/pkg/errors"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -26,17 +28,23 @@ import (
 
 // RetainClusterFields updates the desired object with values retained
 // from the cluster object.
-func RetainClusterFields(targetKind string, desiredObj, clusterObj, fedObj *unstructured.Unstructured) error {
+func RetainClusterFields(targetKind string, desiredObj, clusterObj, fedObj *unstructured.Unstructured, locallyManagedFields []string) error {
 	// Pass the same ResourceVersion as in the cluster object for update operation, otherwise operation will fail.
 	desiredObj.SetResourceVersion(clusterObj.GetResourceVersion())
 
-	if targetKind == util.ServiceKind {
-		return retainServiceFields(desiredObj, clusterObj)
+	var err error
+	switch targetKind {
+	case util.ServiceKind:
+		err = retainServiceFields(desiredObj, clusterObj)
+	case util.ServiceAccountKind:
+		err = retainServiceAccountFields(desiredObj, clusterObj)
+	default:
+		err = retainReplicas(desiredObj, clusterObj, fedObj)
 	}
-	if targetKind == util.ServiceAccountKind {
-		return retainServiceAccountFields(desiredObj, clusterObj)
+	if err != nil {
+		return err
 	}
-	return retainReplicas(desiredObj, clusterObj, fedObj)
+	return retainLocallyManagedFields(desiredObj, clusterObj, locallyManagedFields)
 }
 
 func retainServiceFields(desiredObj, clusterObj *unstructured.Unstructured) error {
@@ -149,3 +157,24 @@ func retainReplicas(desiredObj, clusterObj, fedObj *unstructured.Unstructured) e
 	}
 	return nil
 }
+
+// retainLocallyManagedFields retains the values of the fields that the
+// federated type config identifies as being managed by controllers in
+// member clusters.  A field that is not set in the cluster object
+// retains its desired value.
+func retainLocallyManagedFields(desiredObj, clusterObj *unstructured.Unstructured, paths []string) error {
+	for _, path := range paths {
+		fields := strings.Split(strings.TrimPrefix(path, "."), ".")
+		value, ok, err := unstructured.NestedFieldNoCopy(clusterObj.Object, fields...)
+		if err != nil {
+			return errors.Wrapf(err, "Error retrieving %q from cluster object", path)
+		}
+		if !ok {
+			continue
+		}
+		if err := unstructured.SetNestedField(desiredObj.Object, value, fields...); err != nil {
+			return errors.Wrapf(err, "Error setting %q", path)
+		}
+	}
+	return nil
+}
@@ -68,7 +68,7 @@ func TestRetainClusterFields(t *testing.T) {
 					},
 				},
 			}
-			if err := RetainClusterFields("", desiredObj, clusterObj, fedObj); err != nil {
+			if err := RetainClusterFields("", desiredObj, clusterObj, fedObj, nil); err != nil {
 				t.Fatalf("Unexpected error: %v", err)
 			}
 
@@ -85,3 +85,41 @@ func TestRetainClusterFields(t *testing.T) {
 		})
 	}
 }
+
+func TestRetainLocallyManagedFields(t *testing.T) {
+	desiredObj := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"replicas": int64(1),
+				"paused":   true,
+			},
+		},
+	}
+	clusterObj := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"finalizers": []interface{}{"example.com/cleanup"},
+			},
+			"spec": map[string]interface{}{
+				"replicas": int64(5),
+			},
+		},
+	}
+	fields := []string{".spec.replicas", "metadata.finalizers", ".spec.paused"}
+	if err := RetainClusterFields("", desiredObj, clusterObj, desiredObj, fields); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	replicas, _, _ := unstructured.NestedInt64(desiredObj.Object, util.SpecField, util.ReplicasField)
+	if replicas != 5 {
+		t.Fatalf("Expected replicas to be retained from the cluster object, got %d", replicas)
+	}
+	finalizers := desiredObj.GetFinalizers()
+	if len(finalizers) != 1 || finalizers[0] != "example.com/cleanup" {
+		t.Fatalf("Expected finalizers to be retained from the cluster object, got %v", finalizers)
+	}
+	paused, _, _ := unstructured.NestedBool(desiredObj.Object, util.SpecField, "paused")
+	if !paused {
+		t.Fatalf("Expected a field not set in the cluster object to retain its desired value")
+	}
+}
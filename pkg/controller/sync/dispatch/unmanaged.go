@@ -27,6 +27,7 @@ import (
 
 	"sigs.k8s.io/kubefed/pkg/controller/sync/status"
 	"sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/controller/util/dispatchlimiter"
 )
 
 const eventTemplate = "%s %s %q in cluster %q"
@@ -36,7 +37,8 @@ const eventTemplate = "%s %s %q in cluster %q"
 type UnmanagedDispatcher interface {
 	OperationDispatcher
 
-	Delete(clusterName string)
+	Delete(clusterName string, clusterObj *unstructured.Unstructured)
+	DeleteInForeground(clusterName string, clusterObj *unstructured.Unstructured)
 	RemoveManagedLabel(clusterName string, clusterObj *unstructured.Unstructured)
 }
 
@@ -49,8 +51,8 @@ type unmanagedDispatcherImpl struct {
 	recorder dispatchRecorder
 }
 
-func NewUnmanagedDispatcher(clientAccessor clientAccessorFunc, targetKind string, targetName util.QualifiedName) UnmanagedDispatcher {
-	dispatcher := newOperationDispatcher(clientAccessor, nil)
+func NewUnmanagedDispatcher(clientAccessor clientAccessorFunc, limiter *dispatchlimiter.Limiter, targetKind string, targetName util.QualifiedName) UnmanagedDispatcher {
+	dispatcher := newOperationDispatcher(clientAccessor, limiter, nil)
 	return newUnmanagedDispatcher(dispatcher, nil, targetKind, targetName)
 }
 
@@ -67,7 +69,19 @@ func (d *unmanagedDispatcherImpl) Wait() (bool, error) {
 	return d.dispatcher.Wait()
 }
 
-func (d *unmanagedDispatcherImpl) Delete(clusterName string) {
+func (d *unmanagedDispatcherImpl) Delete(clusterName string, clusterObj *unstructured.Unstructured) {
+	d.delete(clusterName, clusterObj, &metav1.DeleteOptions{})
+}
+
+// DeleteInForeground deletes the resource with foreground cascading
+// so that the resource is only removed from the cluster once its
+// dependents have been removed.
+func (d *unmanagedDispatcherImpl) DeleteInForeground(clusterName string, clusterObj *unstructured.Unstructured) {
+	propagationPolicy := metav1.DeletePropagationForeground
+	d.delete(clusterName, clusterObj, &metav1.DeleteOptions{PropagationPolicy: &propagationPolicy})
+}
+
+func (d *unmanagedDispatcherImpl) delete(clusterName string, clusterObj *unstructured.Unstructured, options *metav1.DeleteOptions) {
 	d.dispatcher.incrementOperationsInitiated()
 	const op = "delete"
 	const opContinuous = "Deleting"
@@ -78,7 +92,9 @@ func (d *unmanagedDispatcherImpl) Delete(clusterName string) {
 			d.recorder.recordEvent(clusterName, op, opContinuous)
 		}
 
-		err := client.Resources(d.targetName.Namespace).Delete(d.targetName.Name, &metav1.DeleteOptions{})
+		// The name of the resource in the cluster may differ from
+		// the target name if its namespace is mapped.
+		err := client.Resources(clusterObj.GetNamespace()).Delete(clusterObj.GetName(), options)
 		if apierrors.IsNotFound(err) {
 			err = nil
 		}
@@ -17,6 +17,11 @@ limitations under the License.
 package sync
 
 import (
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -95,8 +100,16 @@ func selectedClusterNames(resource *unstructured.Unstructured, clusters []*fedv1
 		if err != nil {
 			return nil, err
 		}
+		fieldRequirements := placement.ClusterFieldRequirements()
 		for _, cluster := range clusters {
-			if selector.Matches(labels.Set(cluster.Labels)) {
+			if !selector.Matches(labels.Set(cluster.Labels)) {
+				continue
+			}
+			matches, err := util.MatchesClusterFields(fieldRequirements, cluster)
+			if err != nil {
+				return nil, errors.Wrap(err, "invalid cluster selector")
+			}
+			if matches {
 				selectedNames.Insert(cluster.Name)
 			}
 		}
@@ -105,6 +118,57 @@ func selectedClusterNames(resource *unstructured.Unstructured, clusters []*fedv1
 	return selectedNames, nil
 }
 
+// limitPlacement restricts the selected clusters to the maximum
+// number of clusters of the placement of the resource.  Ready clusters
+// are preferred over clusters that are not ready, and clusters are
+// otherwise chosen in the order of their names so that the selection
+// remains stable across reconciles.
+func limitPlacement(resource *unstructured.Unstructured, selectedClusters sets.String, clusters []*fedv1b1.KubeFedCluster) (sets.String, error) {
+	placement, err := util.UnmarshalGenericPlacement(resource)
+	if err != nil {
+		return nil, err
+	}
+	maxClusters, ok := placement.MaxClusters()
+	if !ok || selectedClusters.Len() <= maxClusters {
+		return selectedClusters, nil
+	}
+	readyClusters := getReadyClusterNames(clusters)
+	names := selectedClusters.List()
+	sort.SliceStable(names, func(i, j int) bool {
+		return readyClusters.Has(names[i]) && !readyClusters.Has(names[j])
+	})
+	return sets.NewString(names[:maxClusters]...), nil
+}
+
+// unsatisfiedPlacement returns a message describing why the selected
+// clusters do not satisfy the minimum number of ready clusters of the
+// placement of the resource, or an empty string if they do.
+func unsatisfiedPlacement(resource *unstructured.Unstructured, selectedClusters sets.String, clusters []*fedv1b1.KubeFedCluster) (string, error) {
+	placement, err := util.UnmarshalGenericPlacement(resource)
+	if err != nil {
+		return "", err
+	}
+	minClusters := placement.MinClusters()
+	if minClusters == 0 {
+		return "", nil
+	}
+	readyClusters := getReadyClusterNames(clusters).Intersection(selectedClusters)
+	if readyClusters.Len() >= minClusters {
+		return "", nil
+	}
+	return fmt.Sprintf("Placement selects %d ready clusters but requires at least %d", readyClusters.Len(), minClusters), nil
+}
+
+func getReadyClusterNames(clusters []*fedv1b1.KubeFedCluster) sets.String {
+	clusterNames := sets.String{}
+	for _, cluster := range clusters {
+		if util.IsClusterReady(&cluster.Status) {
+			clusterNames.Insert(cluster.Name)
+		}
+	}
+	return clusterNames
+}
+
 func getClusterNames(clusters []*fedv1b1.KubeFedCluster) sets.String {
 	clusterNames := sets.String{}
 	for _, cluster := range clusters {
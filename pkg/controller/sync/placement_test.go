@@ -20,10 +20,12 @@ import (
 	"reflect"
 	"testing"
 
+	apiv1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/util/sets"
 
+	"sigs.k8s.io/kubefed/pkg/apis/core/common"
 	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
 	"sigs.k8s.io/kubefed/pkg/controller/util"
 )
@@ -98,3 +100,221 @@ func TestSelectedClusterNames(t *testing.T) {
 		})
 	}
 }
+
+func TestSelectedClusterNamesByStatusFields(t *testing.T) {
+	clusters := []*fedv1b1.KubeFedCluster{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "cluster1",
+			},
+			Status: fedv1b1.KubeFedClusterStatus{
+				Region:            "us-east1",
+				KubernetesVersion: "v1.26.5",
+				Provider:          "gke",
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "cluster2",
+				Labels: map[string]string{
+					"foo": "bar",
+				},
+			},
+			Status: fedv1b1.KubeFedClusterStatus{
+				Region:            "us-west1",
+				KubernetesVersion: "v1.27.1-gke.100",
+				Provider:          "gke",
+			},
+		},
+	}
+
+	testCases := map[string]struct {
+		clusterSelector map[string]interface{}
+		expectedNames   sets.String
+		expectedErr     bool
+	}{
+		"status fields select clusters": {
+			clusterSelector: map[string]interface{}{
+				"matchFields": []interface{}{
+					map[string]interface{}{"key": util.ClusterKubernetesVersionField, "operator": "Gt", "values": []interface{}{"1.26"}},
+					map[string]interface{}{"key": util.ClusterProviderField, "operator": "In", "values": []interface{}{"gke"}},
+				},
+			},
+			expectedNames: sets.NewString("cluster2"),
+		},
+		"labels and status fields must both match": {
+			clusterSelector: map[string]interface{}{
+				"matchLabels": map[string]interface{}{"foo": "bar"},
+				"matchFields": []interface{}{
+					map[string]interface{}{"key": util.ClusterRegionField, "operator": "In", "values": []interface{}{"us-east1"}},
+				},
+			},
+			expectedNames: sets.NewString(),
+		},
+		"invalid requirement is an error": {
+			clusterSelector: map[string]interface{}{
+				"matchFields": []interface{}{
+					map[string]interface{}{"key": "status.unknown", "operator": "Exists"},
+				},
+			},
+			expectedErr: true,
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			obj := &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"spec": make(map[string]interface{}),
+				},
+			}
+			if err := unstructured.SetNestedField(obj.Object, testCase.clusterSelector, util.SpecField, util.PlacementField, util.ClusterSelectorField); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			selectedNames, err := selectedClusterNames(obj, clusters)
+			if testCase.expectedErr {
+				if err == nil {
+					t.Fatalf("Expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(selectedNames, testCase.expectedNames) {
+				t.Fatalf("Expected names %v, got %v", testCase.expectedNames, selectedNames)
+			}
+		})
+	}
+}
+
+func TestLimitPlacement(t *testing.T) {
+	clusters := []*fedv1b1.KubeFedCluster{
+		newPlacementCluster("cluster1", false),
+		newPlacementCluster("cluster2", true),
+		newPlacementCluster("cluster3", true),
+	}
+	selected := sets.NewString("cluster1", "cluster2", "cluster3")
+
+	testCases := map[string]struct {
+		maxClusters   *int64
+		expectedNames sets.String
+	}{
+		"all clusters when maximum absent": {
+			expectedNames: selected,
+		},
+		"all clusters when maximum not exceeded": {
+			maxClusters:   int64Ptr(3),
+			expectedNames: selected,
+		},
+		"ready clusters preferred when maximum exceeded": {
+			maxClusters:   int64Ptr(2),
+			expectedNames: sets.NewString("cluster2", "cluster3"),
+		},
+		"clusters chosen by name when maximum exceeded": {
+			maxClusters:   int64Ptr(1),
+			expectedNames: sets.NewString("cluster2"),
+		},
+		"no clusters when maximum is zero": {
+			maxClusters:   int64Ptr(0),
+			expectedNames: sets.NewString(),
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			obj := newPlacementObject(t, util.MaxClustersField, testCase.maxClusters)
+			selectedNames, err := limitPlacement(obj, selected, clusters)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(selectedNames, testCase.expectedNames) {
+				t.Fatalf("Expected names %v, got %v", testCase.expectedNames, selectedNames)
+			}
+		})
+	}
+}
+
+func TestUnsatisfiedPlacement(t *testing.T) {
+	clusters := []*fedv1b1.KubeFedCluster{
+		newPlacementCluster("cluster1", false),
+		newPlacementCluster("cluster2", true),
+		newPlacementCluster("cluster3", true),
+	}
+
+	testCases := map[string]struct {
+		minClusters *int64
+		selected    sets.String
+		unsatisfied bool
+	}{
+		"satisfied when minimum absent": {
+			selected: sets.NewString(),
+		},
+		"satisfied when enough ready clusters selected": {
+			minClusters: int64Ptr(2),
+			selected:    sets.NewString("cluster2", "cluster3"),
+		},
+		"unsatisfied when too few clusters selected": {
+			minClusters: int64Ptr(2),
+			selected:    sets.NewString("cluster2"),
+			unsatisfied: true,
+		},
+		"unsatisfied when selected clusters are not ready": {
+			minClusters: int64Ptr(2),
+			selected:    sets.NewString("cluster1", "cluster2"),
+			unsatisfied: true,
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			obj := newPlacementObject(t, util.MinClustersField, testCase.minClusters)
+			message, err := unsatisfiedPlacement(obj, testCase.selected, clusters)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if testCase.unsatisfied != (len(message) > 0) {
+				t.Fatalf("Expected unsatisfied to be %v, got message %q", testCase.unsatisfied, message)
+			}
+		})
+	}
+}
+
+func newPlacementCluster(name string, ready bool) *fedv1b1.KubeFedCluster {
+	conditionStatus := apiv1.ConditionFalse
+	if ready {
+		conditionStatus = apiv1.ConditionTrue
+	}
+	return &fedv1b1.KubeFedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Status: fedv1b1.KubeFedClusterStatus{
+			Conditions: []fedv1b1.ClusterCondition{
+				{
+					Type:   common.ClusterReady,
+					Status: conditionStatus,
+				},
+			},
+		},
+	}
+}
+
+func newPlacementObject(t *testing.T, field string, value *int64) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": make(map[string]interface{}),
+		},
+	}
+	if value != nil {
+		if err := unstructured.SetNestedField(obj.Object, *value, util.SpecField, util.PlacementField, field); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	return obj
+}
+
+func int64Ptr(value int64) *int64 {
+	return &value
+}
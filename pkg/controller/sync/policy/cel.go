@@ -0,0 +1,137 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/checker/decls"
+	celtypes "github.com/google/cel-go/common/types"
+	"github.com/pkg/errors"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	apitypes "k8s.io/apimachinery/pkg/types"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+const (
+	// ObjectVariable and ClusterVariable are the names by which the
+	// CEL rules of a dispatch policy refer to the resource rendered
+	// for a member cluster and to the KubeFedCluster of the cluster.
+	ObjectVariable  = "object"
+	ClusterVariable = "cluster"
+)
+
+type compiledRules struct {
+	uid        apitypes.UID
+	generation int64
+	programs   []cel.Program
+	// errs holds the compilation error of each rule without a program.
+	errs []error
+}
+
+// ruleCompiler compiles the CEL rules of dispatch policies.  Programs
+// are cached per policy until the generation of the policy changes.
+type ruleCompiler struct {
+	sync.Mutex
+	env      cel.Env
+	policies map[string]*compiledRules
+}
+
+func newRuleCompiler() (*ruleCompiler, error) {
+	objectType := decls.NewMapType(decls.String, decls.Dyn)
+	env, err := cel.NewEnv(cel.Declarations(
+		decls.NewIdent(ObjectVariable, objectType, nil),
+		decls.NewIdent(ClusterVariable, objectType, nil),
+	))
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create CEL environment")
+	}
+	return &ruleCompiler{
+		env:      env,
+		policies: make(map[string]*compiledRules),
+	}, nil
+}
+
+// rulesFor returns the compiled CEL rules of the policy.
+func (c *ruleCompiler) rulesFor(policy *fedv1b1.DispatchPolicy) *compiledRules {
+	c.Lock()
+	defer c.Unlock()
+	if cached, ok := c.policies[policy.Name]; ok && cached.uid == policy.UID && cached.generation == policy.Generation {
+		return cached
+	}
+	compiled := &compiledRules{
+		uid:        policy.UID,
+		generation: policy.Generation,
+		programs:   make([]cel.Program, len(policy.Spec.Rules)),
+		errs:       make([]error, len(policy.Spec.Rules)),
+	}
+	for i, rule := range policy.Spec.Rules {
+		compiled.programs[i], compiled.errs[i] = c.compile(rule.Expression)
+	}
+	c.policies[policy.Name] = compiled
+	return compiled
+}
+
+func (c *ruleCompiler) compile(expression string) (cel.Program, error) {
+	parsed, issues := c.env.Parse(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, errors.Wrap(issues.Err(), "invalid expression")
+	}
+	checked, issues := c.env.Check(parsed)
+	if issues != nil && issues.Err() != nil {
+		return nil, errors.Wrap(issues.Err(), "invalid expression")
+	}
+	resultType := checked.ResultType()
+	if !proto.Equal(resultType, decls.Bool) && !proto.Equal(resultType, decls.Dyn) {
+		return nil, errors.New("expression must evaluate to a bool")
+	}
+	return c.env.Program(checked)
+}
+
+// checkCELRule returns an error if the rule does not evaluate to true
+// for the object and cluster.  A rule that cannot be compiled or
+// evaluated is considered violated.
+func checkCELRule(rule fedv1b1.DispatchPolicyRule, program cel.Program, compileErr error,
+	obj *unstructured.Unstructured, cluster map[string]interface{}) error {
+	if compileErr != nil {
+		return compileErr
+	}
+	value, _, err := program.Eval(map[string]interface{}{
+		ObjectVariable:  obj.Object,
+		ClusterVariable: cluster,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to evaluate expression")
+	}
+	result, ok := value.(celtypes.Bool)
+	if !ok {
+		return errors.Errorf("expression evaluated to %v instead of a bool", value)
+	}
+	if result {
+		return nil
+	}
+	violation := fmt.Sprintf("%s evaluated to false", rule.Expression)
+	if len(rule.Message) > 0 {
+		return errors.Errorf("%s (%s)", rule.Message, violation)
+	}
+	return errors.New(violation)
+}
@@ -0,0 +1,248 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/jsonpath"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/kubefed/pkg/controller/sync/dispatch"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+// Evaluator checks resources rendered for member clusters against the
+// DispatchPolicy resources in the KubeFed system namespace.
+type Evaluator interface {
+	Run(stopChan <-chan struct{})
+	HasSynced() bool
+	// ValidatorFor returns a validator for resources of the given
+	// type that are rendered for the given clusters.
+	ValidatorFor(targetType *metav1.APIResource, clusters []*fedv1b1.KubeFedCluster) dispatch.ObjectValidatorFunc
+}
+
+type evaluator struct {
+	store      cache.Store
+	controller cache.Controller
+	rules      *ruleCompiler
+	webhooks   *webhookReviewer
+}
+
+// NewEvaluator returns an evaluator of dispatch policies.  The
+// trigger function is invoked when a policy changes.
+func NewEvaluator(controllerConfig *util.ControllerConfig, triggerFunc func(pkgruntime.Object)) (Evaluator, error) {
+	rules, err := newRuleCompiler()
+	if err != nil {
+		return nil, err
+	}
+	e := &evaluator{rules: rules, webhooks: newWebhookReviewer()}
+	e.store, e.controller, err = util.NewGenericInformer(
+		controllerConfig.KubeConfig,
+		controllerConfig.KubeFedNamespace,
+		&fedv1b1.DispatchPolicy{},
+		util.NoResyncPeriod,
+		triggerFunc,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *evaluator) Run(stopChan <-chan struct{}) {
+	go e.controller.Run(stopChan)
+}
+
+func (e *evaluator) HasSynced() bool {
+	return e.controller.HasSynced()
+}
+
+func (e *evaluator) ValidatorFor(targetType *metav1.APIResource, clusters []*fedv1b1.KubeFedCluster) dispatch.ObjectValidatorFunc {
+	var policies []*fedv1b1.DispatchPolicy
+	for _, obj := range e.store.List() {
+		policy := obj.(*fedv1b1.DispatchPolicy)
+		if appliesToKind(policy, targetType.Kind) {
+			policies = append(policies, policy)
+		}
+	}
+	if len(policies) == 0 {
+		return nil
+	}
+	// Evaluate policies in a stable order so that the reported
+	// violation does not change between reconciliations.
+	sort.Slice(policies, func(i, j int) bool {
+		return policies[i].Name < policies[j].Name
+	})
+
+	clusterMap := make(map[string]*fedv1b1.KubeFedCluster)
+	for _, cluster := range clusters {
+		clusterMap[cluster.Name] = cluster
+	}
+	return func(clusterName string, obj, clusterObj *unstructured.Unstructured) error {
+		cluster, ok := clusterMap[clusterName]
+		if !ok {
+			return errors.Errorf("Cluster %q is unknown", clusterName)
+		}
+		return validate(policies, e.rules, e.webhooks, targetType, cluster, obj, clusterObj)
+	}
+}
+
+func appliesToKind(policy *fedv1b1.DispatchPolicy, targetKind string) bool {
+	if len(policy.Spec.TargetKinds) == 0 {
+		return true
+	}
+	for _, kind := range policy.Spec.TargetKinds {
+		if kind == targetKind {
+			return true
+		}
+	}
+	return false
+}
+
+// validate returns an error describing the first of the given
+// policies that the resource rendered for the cluster violates.  The
+// JSONPath and CEL rules of a policy are checked before its webhook is
+// called.
+func validate(policies []*fedv1b1.DispatchPolicy, rules *ruleCompiler, webhooks *webhookReviewer, targetType *metav1.APIResource,
+	cluster *fedv1b1.KubeFedCluster, obj, clusterObj *unstructured.Unstructured) error {
+	var clusterValue map[string]interface{}
+	for _, policy := range policies {
+		applies, err := appliesToCluster(policy, cluster)
+		if err != nil {
+			return errors.Wrapf(err, "Invalid cluster selector of DispatchPolicy %q", policy.Name)
+		}
+		if !applies {
+			continue
+		}
+		for _, rule := range policy.Spec.JSONPathRules {
+			if err := checkRule(rule, obj); err != nil {
+				return errors.Wrapf(err, "DispatchPolicy %q rule %q is violated", policy.Name, rule.Name)
+			}
+		}
+		if len(policy.Spec.Rules) > 0 && clusterValue == nil {
+			clusterValue, err = pkgruntime.DefaultUnstructuredConverter.ToUnstructured(cluster)
+			if err != nil {
+				return errors.Wrapf(err, "Failed to convert cluster %q", cluster.Name)
+			}
+		}
+		compiled := rules.rulesFor(policy)
+		for i, rule := range policy.Spec.Rules {
+			if err := checkCELRule(rule, compiled.programs[i], compiled.errs[i], obj, clusterValue); err != nil {
+				return errors.Wrapf(err, "DispatchPolicy %q rule %q is violated", policy.Name, rule.Name)
+			}
+		}
+		if policy.Spec.Webhook != nil {
+			if err := webhooks.review(policy, targetType, cluster.Name, obj, clusterObj); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func appliesToCluster(policy *fedv1b1.DispatchPolicy, cluster *fedv1b1.KubeFedCluster) (bool, error) {
+	if policy.Spec.ClusterSelector == nil {
+		return true, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(policy.Spec.ClusterSelector)
+	if err != nil {
+		return false, err
+	}
+	return selector.Matches(labels.Set(cluster.Labels)), nil
+}
+
+// checkRule returns an error if the values selected by the path of
+// the rule do not satisfy its operator.
+func checkRule(rule fedv1b1.DispatchPolicyJSONPathRule, obj *unstructured.Unstructured) error {
+	values, err := selectValues(rule.Path, obj)
+	if err != nil {
+		return err
+	}
+
+	var violation string
+	switch rule.Operator {
+	case fedv1b1.DispatchPolicyExists:
+		if len(values) == 0 {
+			violation = fmt.Sprintf("no value found at %s", rule.Path)
+		}
+	case fedv1b1.DispatchPolicyDoesNotExist:
+		if len(values) > 0 {
+			violation = fmt.Sprintf("value %q found at %s", values[0], rule.Path)
+		}
+	case fedv1b1.DispatchPolicyMatches, fedv1b1.DispatchPolicyDoesNotMatch:
+		pattern, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return errors.Wrapf(err, "invalid pattern")
+		}
+		wantMatch := rule.Operator == fedv1b1.DispatchPolicyMatches
+		for _, value := range values {
+			if pattern.MatchString(value) != wantMatch {
+				verb := "matches"
+				if wantMatch {
+					verb = "does not match"
+				}
+				violation = fmt.Sprintf("value %q at %s %s %q", value, rule.Path, verb, rule.Pattern)
+				break
+			}
+		}
+	default:
+		return errors.Errorf("unknown operator %q", rule.Operator)
+	}
+
+	if len(violation) == 0 {
+		return nil
+	}
+	if len(rule.Message) > 0 {
+		return errors.Errorf("%s (%s)", rule.Message, violation)
+	}
+	return errors.New(violation)
+}
+
+// selectValues returns the string representation of the values of
+// the object selected by the given JSONPath expression.
+func selectValues(path string, obj *unstructured.Unstructured) ([]string, error) {
+	j := jsonpath.New("rule")
+	j.AllowMissingKeys(true)
+	if err := j.Parse(path); err != nil {
+		return nil, errors.Wrapf(err, "invalid path %q", path)
+	}
+	results, err := j.FindResults(obj.Object)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to evaluate path %q", path)
+	}
+	var values []string
+	for _, result := range results {
+		for _, value := range result {
+			if !value.IsValid() || value.Interface() == nil {
+				continue
+			}
+			values = append(values, strings.TrimSpace(fmt.Sprint(value.Interface())))
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,256 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+var deploymentType = &metav1.APIResource{
+	Group:   "apps",
+	Version: "v1",
+	Kind:    "Deployment",
+	Name:    "deployments",
+}
+
+func TestValidate(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"replicas": int64(3),
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{"image": "nginx:1.15"},
+						map[string]interface{}{"image": "sidecar:latest"},
+					},
+				},
+			},
+		},
+	}}
+	cluster := &fedv1b1.KubeFedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "cluster1",
+			Labels: map[string]string{"env": "prod"},
+		},
+	}
+	imagePath := "{.spec.template.spec.containers[*].image}"
+
+	testCases := map[string]struct {
+		rule            fedv1b1.DispatchPolicyJSONPathRule
+		clusterSelector *metav1.LabelSelector
+		expectedErr     bool
+	}{
+		"Matches is satisfied when all values match": {
+			rule: fedv1b1.DispatchPolicyJSONPathRule{Path: imagePath, Operator: fedv1b1.DispatchPolicyMatches, Pattern: ":"},
+		},
+		"Matches is violated when a value does not match": {
+			rule:        fedv1b1.DispatchPolicyJSONPathRule{Path: imagePath, Operator: fedv1b1.DispatchPolicyMatches, Pattern: `:\d`},
+			expectedErr: true,
+		},
+		"DoesNotMatch is violated when a value matches": {
+			rule:        fedv1b1.DispatchPolicyJSONPathRule{Path: imagePath, Operator: fedv1b1.DispatchPolicyDoesNotMatch, Pattern: ":latest$"},
+			expectedErr: true,
+		},
+		"DoesNotMatch is satisfied for missing values": {
+			rule: fedv1b1.DispatchPolicyJSONPathRule{Path: "{.spec.missing}", Operator: fedv1b1.DispatchPolicyDoesNotMatch, Pattern: ".*"},
+		},
+		"Exists is satisfied when a value is selected": {
+			rule: fedv1b1.DispatchPolicyJSONPathRule{Path: "{.spec.replicas}", Operator: fedv1b1.DispatchPolicyExists},
+		},
+		"Exists is violated when no value is selected": {
+			rule:        fedv1b1.DispatchPolicyJSONPathRule{Path: "{.spec.missing}", Operator: fedv1b1.DispatchPolicyExists},
+			expectedErr: true,
+		},
+		"DoesNotExist is violated when a value is selected": {
+			rule:        fedv1b1.DispatchPolicyJSONPathRule{Path: "{.spec.replicas}", Operator: fedv1b1.DispatchPolicyDoesNotExist},
+			expectedErr: true,
+		},
+		"Policy does not apply to unselected cluster": {
+			rule:            fedv1b1.DispatchPolicyJSONPathRule{Path: "{.spec.replicas}", Operator: fedv1b1.DispatchPolicyDoesNotExist},
+			clusterSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "dev"}},
+		},
+		"Policy applies to selected cluster": {
+			rule:            fedv1b1.DispatchPolicyJSONPathRule{Path: "{.spec.replicas}", Operator: fedv1b1.DispatchPolicyDoesNotExist},
+			clusterSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}},
+			expectedErr:     true,
+		},
+		"Invalid path is a violation": {
+			rule:        fedv1b1.DispatchPolicyJSONPathRule{Path: "{.spec[", Operator: fedv1b1.DispatchPolicyExists},
+			expectedErr: true,
+		},
+		"Invalid pattern is a violation": {
+			rule:        fedv1b1.DispatchPolicyJSONPathRule{Path: imagePath, Operator: fedv1b1.DispatchPolicyMatches, Pattern: "("},
+			expectedErr: true,
+		},
+		"Unknown operator is a violation": {
+			rule:        fedv1b1.DispatchPolicyJSONPathRule{Path: imagePath, Operator: "Equals"},
+			expectedErr: true,
+		},
+	}
+
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			tc.rule.Name = "rule"
+			policies := []*fedv1b1.DispatchPolicy{{
+				ObjectMeta: metav1.ObjectMeta{Name: "policy"},
+				Spec: fedv1b1.DispatchPolicySpec{
+					ClusterSelector: tc.clusterSelector,
+					JSONPathRules:   []fedv1b1.DispatchPolicyJSONPathRule{tc.rule},
+				},
+			}}
+			err := validate(policies, newTestRuleCompiler(t), newWebhookReviewer(), deploymentType, cluster, obj, nil)
+			if tc.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateCELRules(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": map[string]interface{}{"env": "prod"},
+		},
+		"spec": map[string]interface{}{
+			"replicas": int64(3),
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{"image": "nginx:1.15"},
+						map[string]interface{}{"image": "sidecar:latest"},
+					},
+				},
+			},
+		},
+	}}
+	cluster := &fedv1b1.KubeFedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cluster1",
+			Labels: map[string]string{
+				"env":          "prod",
+				"max-replicas": "5",
+			},
+		},
+	}
+
+	testCases := map[string]struct {
+		expression  string
+		expectedErr bool
+	}{
+		"Expression evaluating to true is satisfied": {
+			expression: "object.spec.replicas <= int(cluster.metadata.labels['max-replicas'])",
+		},
+		"Expression evaluating to false is violated": {
+			expression:  "object.spec.replicas < 3",
+			expectedErr: true,
+		},
+		"Fields of the object can be compared": {
+			expression: "object.metadata.labels.env == cluster.metadata.labels.env",
+		},
+		"Macros are supported": {
+			expression:  "object.spec.template.spec.containers.all(c, !c.image.endsWith(':latest'))",
+			expectedErr: true,
+		},
+		"Missing fields can be tested": {
+			expression: "!has(object.spec.paused)",
+		},
+		"Missing field is a violation": {
+			expression:  "object.spec.paused == false",
+			expectedErr: true,
+		},
+		"Invalid expression is a violation": {
+			expression:  "object.spec.replicas <",
+			expectedErr: true,
+		},
+		"Unknown variable is a violation": {
+			expression:  "resource.spec.replicas > 0",
+			expectedErr: true,
+		},
+		"Non-bool expression is a violation": {
+			expression:  "object.spec.replicas + 1",
+			expectedErr: true,
+		},
+		"Dynamic non-bool result is a violation": {
+			expression:  "object.spec.replicas",
+			expectedErr: true,
+		},
+	}
+
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			policies := []*fedv1b1.DispatchPolicy{{
+				ObjectMeta: metav1.ObjectMeta{Name: "policy"},
+				Spec: fedv1b1.DispatchPolicySpec{
+					Rules: []fedv1b1.DispatchPolicyRule{{Name: "rule", Expression: tc.expression}},
+				},
+			}}
+			err := validate(policies, newTestRuleCompiler(t), newWebhookReviewer(), deploymentType, cluster, obj, nil)
+			if tc.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRuleCompilerCache(t *testing.T) {
+	compiler := newTestRuleCompiler(t)
+	policy := &fedv1b1.DispatchPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "policy", UID: "uid1", Generation: 1},
+		Spec: fedv1b1.DispatchPolicySpec{
+			Rules: []fedv1b1.DispatchPolicyRule{{Name: "rule", Expression: "true"}},
+		},
+	}
+	compiled := compiler.rulesFor(policy)
+	assert.True(t, compiled == compiler.rulesFor(policy.DeepCopy()), "Rules of an unchanged policy should be cached")
+
+	updated := policy.DeepCopy()
+	updated.Generation = 2
+	updated.Spec.Rules[0].Expression = "false"
+	assert.True(t, compiled != compiler.rulesFor(updated), "Rules of an updated policy should be recompiled")
+
+	recreated := policy.DeepCopy()
+	recreated.UID = "uid2"
+	assert.True(t, compiled != compiler.rulesFor(recreated), "Rules of a recreated policy should be recompiled")
+}
+
+func newTestRuleCompiler(t *testing.T) *ruleCompiler {
+	compiler, err := newRuleCompiler()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return compiler
+}
+
+func TestAppliesToKind(t *testing.T) {
+	policy := &fedv1b1.DispatchPolicy{}
+	assert.True(t, appliesToKind(policy, "Deployment"))
+
+	policy.Spec.TargetKinds = []string{"Deployment"}
+	assert.True(t, appliesToKind(policy, "Deployment"))
+	assert.False(t, appliesToKind(policy, "ConfigMap"))
+}
@@ -0,0 +1,209 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/klog"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+const (
+	// ClusterNameExtraKey is the key of the user info extra of an
+	// admission request that holds the name of the member cluster the
+	// resource was rendered for.
+	ClusterNameExtraKey = "kubefed.k8s.io/cluster-name"
+
+	defaultWebhookTimeout = 10 * time.Second
+)
+
+type webhookClient struct {
+	resourceVersion string
+	client          *http.Client
+}
+
+// webhookReviewer calls the webhooks of dispatch policies.  Clients
+// are cached per policy until the policy changes.
+type webhookReviewer struct {
+	sync.Mutex
+	clients map[string]webhookClient
+}
+
+func newWebhookReviewer() *webhookReviewer {
+	return &webhookReviewer{clients: make(map[string]webhookClient)}
+}
+
+// review returns an error if the webhook of the policy denies the
+// resource, or if the webhook cannot be called and the failure
+// policy of the webhook is not to ignore failures.
+func (r *webhookReviewer) review(policy *fedv1b1.DispatchPolicy, targetType *metav1.APIResource, clusterName string, obj, clusterObj *unstructured.Unstructured) error {
+	webhook := policy.Spec.Webhook
+	review, err := newAdmissionReview(targetType, clusterName, obj, clusterObj)
+	if err != nil {
+		return errors.Wrap(err, "Failed to encode admission review")
+	}
+	response, err := r.call(policy, review)
+	if err != nil {
+		if webhook.FailurePolicy == fedv1b1.DispatchPolicyIgnore {
+			klog.Warningf("Ignoring failure to call webhook of DispatchPolicy %q: %v", policy.Name, err)
+			return nil
+		}
+		return errors.Wrapf(err, "Failed to call webhook of DispatchPolicy %q", policy.Name)
+	}
+	if response.Allowed {
+		return nil
+	}
+	message := "no reason given"
+	if response.Result != nil && len(response.Result.Message) > 0 {
+		message = response.Result.Message
+	}
+	return errors.Errorf("DispatchPolicy %q webhook denied the resource: %s", policy.Name, message)
+}
+
+func (r *webhookReviewer) call(policy *fedv1b1.DispatchPolicy, review *admissionv1beta1.AdmissionReview) (*admissionv1beta1.AdmissionResponse, error) {
+	client, err := r.clientFor(policy)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := json.Marshal(review)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Post(policy.Spec.Webhook.URL, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return nil, errors.Wrap(err, "request failed")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, errors.Errorf("unexpected response status %q", resp.Status)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read response")
+	}
+	result := &admissionv1beta1.AdmissionReview{}
+	if err := json.Unmarshal(body, result); err != nil {
+		return nil, errors.Wrap(err, "failed to decode response")
+	}
+	if result.Response == nil {
+		return nil, errors.New("the response does not include an admission response")
+	}
+	if result.Response.UID != review.Request.UID {
+		return nil, errors.Errorf("the response uid %q does not match the request uid %q", result.Response.UID, review.Request.UID)
+	}
+	return result.Response, nil
+}
+
+func (r *webhookReviewer) clientFor(policy *fedv1b1.DispatchPolicy) (*http.Client, error) {
+	r.Lock()
+	defer r.Unlock()
+	if cached, ok := r.clients[policy.Name]; ok && cached.resourceVersion == policy.ResourceVersion {
+		return cached.client, nil
+	}
+	client, err := newHTTPClient(policy.Spec.Webhook)
+	if err != nil {
+		return nil, err
+	}
+	r.clients[policy.Name] = webhookClient{
+		resourceVersion: policy.ResourceVersion,
+		client:          client,
+	}
+	return client, nil
+}
+
+func newHTTPClient(webhook *fedv1b1.DispatchPolicyWebhook) (*http.Client, error) {
+	timeout := defaultWebhookTimeout
+	if webhook.TimeoutSeconds > 0 {
+		timeout = time.Duration(webhook.TimeoutSeconds) * time.Second
+	}
+	tlsConfig := &tls.Config{}
+	if len(webhook.CABundle) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(webhook.CABundle) {
+			return nil, errors.New("the caBundle does not contain a valid PEM encoded certificate")
+		}
+		tlsConfig.RootCAs = pool
+	}
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: &http.Transport{TLSClientConfig: tlsConfig},
+	}, nil
+}
+
+// newAdmissionReview returns the admission review sent to a webhook
+// for a resource rendered for the named cluster.  The resource is
+// reviewed as an update if it exists in the cluster.
+func newAdmissionReview(targetType *metav1.APIResource, clusterName string, obj, clusterObj *unstructured.Unstructured) (*admissionv1beta1.AdmissionReview, error) {
+	raw, err := obj.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	request := &admissionv1beta1.AdmissionRequest{
+		UID: uuid.NewUUID(),
+		Kind: metav1.GroupVersionKind{
+			Group:   targetType.Group,
+			Version: targetType.Version,
+			Kind:    targetType.Kind,
+		},
+		Resource: metav1.GroupVersionResource{
+			Group:    targetType.Group,
+			Version:  targetType.Version,
+			Resource: targetType.Name,
+		},
+		Name:      obj.GetName(),
+		Namespace: obj.GetNamespace(),
+		Operation: admissionv1beta1.Create,
+		UserInfo: authenticationv1.UserInfo{
+			Extra: map[string]authenticationv1.ExtraValue{
+				ClusterNameExtraKey: {clusterName},
+			},
+		},
+		Object: pkgruntime.RawExtension{Raw: raw},
+	}
+	if clusterObj != nil {
+		oldRaw, err := clusterObj.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		request.Operation = admissionv1beta1.Update
+		request.OldObject = pkgruntime.RawExtension{Raw: oldRaw}
+	}
+	return &admissionv1beta1.AdmissionReview{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: admissionv1beta1.SchemeGroupVersion.String(),
+			Kind:       "AdmissionReview",
+		},
+		Request: request,
+	}, nil
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+// newAdmissionServer returns a webhook server that denies resources
+// named "denied" and records the requests it receives.
+func newAdmissionServer(t *testing.T, tls bool, requests chan<- *admissionv1beta1.AdmissionRequest) *httptest.Server {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		review := &admissionv1beta1.AdmissionReview{}
+		if err := json.NewDecoder(r.Body).Decode(review); err != nil {
+			t.Errorf("Failed to decode admission review: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		requests <- review.Request
+		review.Response = &admissionv1beta1.AdmissionResponse{
+			UID:     review.Request.UID,
+			Allowed: review.Request.Name != "denied",
+			Result:  &metav1.Status{Message: "denied by test"},
+		}
+		review.Request = nil
+		assert.NoError(t, json.NewEncoder(w).Encode(review))
+	})
+	if tls {
+		return httptest.NewTLSServer(handler)
+	}
+	return httptest.NewServer(handler)
+}
+
+func newWebhookPolicy(webhook *fedv1b1.DispatchPolicyWebhook) *fedv1b1.DispatchPolicy {
+	return &fedv1b1.DispatchPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "webhook"},
+		Spec:       fedv1b1.DispatchPolicySpec{Webhook: webhook},
+	}
+}
+
+func TestWebhookReview(t *testing.T) {
+	requests := make(chan *admissionv1beta1.AdmissionRequest, 10)
+	server := newAdmissionServer(t, false, requests)
+	defer server.Close()
+
+	reviewer := newWebhookReviewer()
+	policy := newWebhookPolicy(&fedv1b1.DispatchPolicyWebhook{URL: server.URL})
+
+	obj := &unstructured.Unstructured{}
+	obj.SetName("allowed")
+	obj.SetNamespace("ns")
+	assert.NoError(t, reviewer.review(policy, deploymentType, "cluster1", obj, nil))
+	request := <-requests
+	assert.Equal(t, admissionv1beta1.Create, request.Operation)
+	assert.Equal(t, "Deployment", request.Kind.Kind)
+	assert.Equal(t, "deployments", request.Resource.Resource)
+	assert.Equal(t, "ns", request.Namespace)
+	assert.Equal(t, []string{"cluster1"}, []string(request.UserInfo.Extra[ClusterNameExtraKey]))
+	assert.NotEmpty(t, request.Object.Raw)
+
+	clusterObj := obj.DeepCopy()
+	assert.NoError(t, reviewer.review(policy, deploymentType, "cluster1", obj, clusterObj))
+	request = <-requests
+	assert.Equal(t, admissionv1beta1.Update, request.Operation)
+	assert.NotEmpty(t, request.OldObject.Raw)
+
+	obj.SetName("denied")
+	err := reviewer.review(policy, deploymentType, "cluster1", obj, nil)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "denied by test")
+	}
+}
+
+func TestWebhookReviewWithCABundle(t *testing.T) {
+	requests := make(chan *admissionv1beta1.AdmissionRequest, 10)
+	server := newAdmissionServer(t, true, requests)
+	defer server.Close()
+
+	obj := &unstructured.Unstructured{}
+	obj.SetName("allowed")
+
+	// The certificate of the test server is not trusted by default.
+	policy := newWebhookPolicy(&fedv1b1.DispatchPolicyWebhook{URL: server.URL})
+	assert.Error(t, newWebhookReviewer().review(policy, deploymentType, "cluster1", obj, nil))
+
+	caBundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	policy = newWebhookPolicy(&fedv1b1.DispatchPolicyWebhook{URL: server.URL, CABundle: caBundle})
+	assert.NoError(t, newWebhookReviewer().review(policy, deploymentType, "cluster1", obj, nil))
+}
+
+func TestWebhookFailurePolicy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	obj := &unstructured.Unstructured{}
+	obj.SetName("allowed")
+
+	policy := newWebhookPolicy(&fedv1b1.DispatchPolicyWebhook{URL: server.URL})
+	assert.Error(t, newWebhookReviewer().review(policy, deploymentType, "cluster1", obj, nil))
+
+	policy.Spec.Webhook.FailurePolicy = fedv1b1.DispatchPolicyIgnore
+	assert.NoError(t, newWebhookReviewer().review(policy, deploymentType, "cluster1", obj, nil))
+}
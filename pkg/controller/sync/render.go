@@ -0,0 +1,144 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	"github.com/pkg/errors"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"sigs.k8s.io/kubefed/pkg/apis/core/typeconfig"
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+// RenderConfig provides the configuration that the sync controller
+// renders the resources of a federated type for member clusters with,
+// so that they can be rendered outside of the controller.
+type RenderConfig struct {
+	TypeConfig typeconfig.Interface
+
+	// The joined clusters, which provide the namespace mappings and
+	// the cluster profiles referenced by override value sources.
+	Clusters []*fedv1b1.KubeFedCluster
+
+	// The version of the target type served by each cluster.  Only
+	// needed if the type config lists several target versions, and
+	// defaults to the version of the target type.
+	TargetVersions map[string]string
+
+	// The namespace in the host cluster of a federated namespace,
+	// whose labels and annotations may be synced to member clusters.
+	// Only needed if the target type is the namespace type.
+	HostNamespace *unstructured.Unstructured
+
+	// Labels and annotations injected into propagated resources
+	PropagationMetadata *fedv1b1.PropagationMetadataConfig
+
+	// Labels and annotations of host namespaces synced to propagated
+	// namespaces
+	NamespaceMetadata *fedv1b1.NamespaceMetadataConfig
+
+	// Resolves the value sources of overrides.  Should be nil if the
+	// OverrideValueSources feature is disabled.
+	ValueResolver *util.OverrideValueResolver
+}
+
+// RenderForCluster returns the object rendered from the given
+// federated resource for the named cluster, exactly as the sync
+// controller would propagate it before retaining the fields managed
+// by the cluster.  The object is annotated with its checksum.
+func RenderForCluster(config *RenderConfig, fedObj *unstructured.Unstructured, clusterName string) (*unstructured.Unstructured, error) {
+	return newRenderedResource(config, fedObj).ObjectForCluster(clusterName)
+}
+
+// RenderedChecksums returns the checksum of the object rendered from
+// the given federated resource for each of the named clusters.  The
+// checksum is the canonical hash of the rendered object that the sync
+// controller records in the kubefed.k8s.io/desired-hash annotation of
+// the resources it propagates, so a resource in a member cluster whose
+// annotation matches the checksum was last propagated from the
+// current desired state.
+func RenderedChecksums(config *RenderConfig, fedObj *unstructured.Unstructured, clusterNames []string) (map[string]string, error) {
+	resource := newRenderedResource(config, fedObj)
+	checksums := make(map[string]string, len(clusterNames))
+	for _, clusterName := range clusterNames {
+		obj, err := resource.ObjectForCluster(clusterName)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to render %s %q for cluster %q", fedObj.GetKind(), util.NewQualifiedName(fedObj), clusterName)
+		}
+		checksums[clusterName] = util.DesiredHash(obj)
+	}
+	return checksums, nil
+}
+
+func newRenderedResource(config *RenderConfig, fedObj *unstructured.Unstructured) *federatedResource {
+	targetIsNamespace := config.TypeConfig.GetTargetType().Kind == util.NamespaceKind
+	federatedName := util.NewQualifiedName(fedObj)
+	targetName := federatedName
+	if targetIsNamespace {
+		targetName.Namespace = ""
+	}
+	return &federatedResource{
+		typeConfig:        config.TypeConfig,
+		targetIsNamespace: targetIsNamespace,
+		targetName:        targetName,
+		federatedKind:     config.TypeConfig.GetFederatedType().Kind,
+		federatedName:     federatedName,
+		federatedResource: fedObj,
+		namespace:         config.HostNamespace,
+		clusters: &renderClustersView{
+			clusters:       config.Clusters,
+			targetVersions: config.TargetVersions,
+			defaultVersion: config.TypeConfig.GetTargetType().Version,
+		},
+		valueResolver: config.ValueResolver,
+
+		propagationMetadata: config.PropagationMetadata,
+		namespaceMetadata:   config.NamespaceMetadata,
+	}
+}
+
+// renderClustersView is a view of a fixed set of clusters, used to
+// render federated resources outside of the sync controller.  All
+// clusters are treated as ready.
+type renderClustersView struct {
+	util.RegisteredClustersView
+	clusters       []*fedv1b1.KubeFedCluster
+	targetVersions map[string]string
+	defaultVersion string
+}
+
+func (v *renderClustersView) GetClusters() ([]*fedv1b1.KubeFedCluster, error) {
+	return v.clusters, nil
+}
+
+func (v *renderClustersView) GetReadyCluster(name string) (*fedv1b1.KubeFedCluster, bool, error) {
+	for _, cluster := range v.clusters {
+		if cluster.Name == name {
+			return cluster, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+func (v *renderClustersView) TargetVersionForCluster(clusterName string) (string, error) {
+	if version, ok := v.targetVersions[clusterName]; ok {
+		return version, nil
+	}
+	return v.defaultVersion, nil
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+func TestRenderedChecksums(t *testing.T) {
+	typeConfig := &fedv1b1.FederatedTypeConfig{
+		Spec: fedv1b1.FederatedTypeConfigSpec{
+			TargetType:    fedv1b1.APIResource{Version: "v1", Kind: "ConfigMap", Scope: "Namespaced"},
+			FederatedType: fedv1b1.APIResource{Group: "types.kubefed.k8s.io", Version: "v1beta1", Kind: "FederatedConfigMap", Scope: "Namespaced"},
+		},
+	}
+	fedObj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"data": map[string]interface{}{"key": "value"},
+			},
+			"overrides": []interface{}{
+				map[string]interface{}{
+					"clusterName": "cluster3",
+					"clusterOverrides": []interface{}{
+						map[string]interface{}{"path": "data.key", "value": "overridden"},
+					},
+				},
+			},
+		},
+	}}
+	fedObj.SetKind("FederatedConfigMap")
+	fedObj.SetNamespace("ns")
+	fedObj.SetName("config")
+	config := &RenderConfig{
+		TypeConfig: typeConfig,
+		Clusters: []*fedv1b1.KubeFedCluster{
+			{ObjectMeta: metav1.ObjectMeta{Name: "cluster1"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "cluster2"}},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster3"},
+				Spec: fedv1b1.KubeFedClusterSpec{
+					NamespaceMappings: []fedv1b1.NamespaceMapping{{Namespace: "ns", ClusterNamespace: "ns-cluster3"}},
+				},
+			},
+		},
+	}
+
+	checksums, err := RenderedChecksums(config, fedObj, []string{"cluster1", "cluster2", "cluster3"})
+	assert.NoError(t, err)
+	assert.Len(t, checksums, 3)
+	assert.Equal(t, checksums["cluster1"], checksums["cluster2"])
+	assert.NotEqual(t, checksums["cluster1"], checksums["cluster3"])
+
+	// The checksum is the desired hash recorded by the sync
+	// controller on the objects it renders.
+	obj, err := RenderForCluster(config, fedObj, "cluster3")
+	assert.NoError(t, err)
+	assert.Equal(t, checksums["cluster3"], util.DesiredHash(obj))
+	assert.Equal(t, "ns-cluster3", obj.GetNamespace())
+	value, _, _ := unstructured.NestedString(obj.Object, "data", "key")
+	assert.Equal(t, "overridden", value)
+
+	// The checksum changes with the propagation metadata.
+	config.PropagationMetadata = &fedv1b1.PropagationMetadataConfig{HostClusterName: "host"}
+	annotatedChecksums, err := RenderedChecksums(config, fedObj, []string{"cluster1"})
+	assert.NoError(t, err)
+	assert.NotEqual(t, checksums["cluster1"], annotatedChecksums["cluster1"])
+}
@@ -28,6 +28,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/record"
 
@@ -36,6 +37,7 @@ import (
 	"sigs.k8s.io/kubefed/pkg/controller/sync/dispatch"
 	"sigs.k8s.io/kubefed/pkg/controller/sync/version"
 	"sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/features"
 )
 
 // FederatedResource encapsulates the behavior of a logical federated
@@ -49,7 +51,14 @@ type FederatedResource interface {
 	UpdateVersions(selectedClusters []string, versionMap map[string]string) error
 	DeleteVersions()
 	ComputePlacement(clusters []*fedv1b1.KubeFedCluster) (selectedClusters sets.String, err error)
+	UnsatisfiedPlacement(clusters []*fedv1b1.KubeFedCluster, selectedClusters sets.String) (string, error)
 	IsNamespaceInHostCluster(clusterObj pkgruntime.Object) bool
+	NameForCluster(clusterName string) string
+	RenamedClusters() map[string]string
+	// HasOverrideValueSources returns true if the values of any of
+	// the overrides of the resource are sourced from ConfigMaps or
+	// Secrets.
+	HasOverrideValueSources() bool
 }
 
 type federatedResource struct {
@@ -64,10 +73,21 @@ type federatedResource struct {
 	federatedResource *unstructured.Unstructured
 	versionManager    *version.VersionManager
 	overridesMap      util.OverridesMap
+	valuesMap         map[string]map[string]interface{}
+	patchesMap        map[string][]util.ClusterPatch
+	valueFromMap      map[string]map[string]*util.OverrideValueSource
 	versionMap        map[string]string
 	namespace         *unstructured.Unstructured
 	fedNamespace      *unstructured.Unstructured
 	eventRecorder     record.EventRecorder
+	clusters          util.RegisteredClustersView
+
+	// Resolves the value sources of overrides.  Nil if the
+	// OverrideValueSources feature is disabled.
+	valueResolver *util.OverrideValueResolver
+
+	propagationMetadata *fedv1b1.PropagationMetadataConfig
+	namespaceMetadata   *fedv1b1.NamespaceMetadataConfig
 }
 
 func (r *federatedResource) FederatedName() util.QualifiedName {
@@ -82,23 +102,191 @@ func (r *federatedResource) TargetName() util.QualifiedName {
 	return r.targetName
 }
 
+// TargetNameForCluster returns the name of the target resource in the
+// given cluster, which will differ from the target name if the
+// resource is renamed by an override or if its namespace is mapped for
+// the cluster.
+func (r *federatedResource) TargetNameForCluster(clusterName string) util.QualifiedName {
+	targetName := util.QualifiedName{
+		Namespace: r.targetName.Namespace,
+		Name:      r.NameForCluster(clusterName),
+	}
+	if r.clusters == nil {
+		return targetName
+	}
+	cluster, found, err := r.clusters.GetReadyCluster(clusterName)
+	if err != nil || !found {
+		return targetName
+	}
+	return util.ClusterQualifiedName(cluster.Spec.NamespaceMappings, r.targetIsNamespace, targetName)
+}
+
+// NameForCluster returns the name of the target resource in the given
+// cluster, accounting for renaming by an override but not for
+// namespace mappings.
+func (r *federatedResource) NameForCluster(clusterName string) string {
+	if r.targetIsNamespace {
+		// Namespaces can only be renamed via namespace mappings.
+		return r.targetName.Name
+	}
+	overrides, err := r.overridesForCluster(clusterName)
+	if err != nil {
+		// The error will be reported when the object for the
+		// cluster is computed.
+		return r.targetName.Name
+	}
+	if name, ok := overrides[util.NameOverridePath].(string); ok {
+		return name
+	}
+	return r.targetName.Name
+}
+
+// RenamedClusters returns a mapping of cluster names to the names of
+// target resources previously propagated to those clusters under a
+// name other than the target name.
+func (r *federatedResource) RenamedClusters() map[string]string {
+	return r.versionManager.GetRenamedClusters(r)
+}
+
 func (r *federatedResource) TargetKind() string {
 	return r.typeConfig.GetTargetType().Kind
 }
 
+func (r *federatedResource) LocallyManagedFields() []string {
+	return r.typeConfig.GetLocallyManagedFields()
+}
+
 func (r *federatedResource) Object() *unstructured.Unstructured {
 	return r.federatedResource
 }
 
 func (r *federatedResource) TemplateVersion() (string, error) {
 	obj := r.federatedResource
-	return GetTemplateHash(obj.Object)
+	templateHash, err := GetTemplateHash(obj.Object)
+	if err != nil {
+		return "", err
+	}
+	labels, annotations := r.syncedNamespaceMetadata()
+	if len(labels) == 0 && len(annotations) == 0 {
+		return templateHash, nil
+	}
+	// Ensure that a change to the synced metadata of the host
+	// namespace results in an update of the namespaces in member
+	// clusters.
+	hashObj := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"template":    templateHash,
+			"labels":      stringMapToInterface(labels),
+			"annotations": stringMapToInterface(annotations),
+		},
+	}
+	return hashUnstructured(hashObj, "namespace metadata")
+}
+
+// syncedNamespaceMetadata returns the labels and annotations of the
+// host namespace that are synced to the namespaces in member
+// clusters.
+func (r *federatedResource) syncedNamespaceMetadata() (labels, annotations map[string]string) {
+	if !r.targetIsNamespace {
+		return nil, nil
+	}
+	return util.SyncedNamespaceMetadata(r.namespaceMetadata, r.namespace)
+}
+
+func stringMapToInterface(values map[string]string) map[string]interface{} {
+	result := make(map[string]interface{}, len(values))
+	for key, value := range values {
+		result[key] = value
+	}
+	return result
 }
 
 func (r *federatedResource) OverrideVersion() (string, error) {
 	// TODO(marun) Consider hashing overrides per cluster to minimize
 	// unnecessary updates.
-	return GetOverrideHash(r.federatedResource)
+	overrideHash, err := GetOverrideHash(r.federatedResource)
+	if err != nil {
+		return "", err
+	}
+	defaultOverrides := r.typeConfig.GetDefaultOverrides()
+	sourceVersions, err := r.valueSourceVersions()
+	if err != nil {
+		return "", err
+	}
+	if len(defaultOverrides) == 0 && len(sourceVersions) == 0 {
+		return overrideHash, nil
+	}
+	// Ensure that a change to the default overrides of the type or
+	// to the objects referenced by value sources results in an
+	// update of the resources in member clusters.
+	obj := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"overrides": overrideHash,
+		},
+	}
+	if len(defaultOverrides) > 0 {
+		obj.Object["defaultOverrides"] = defaultOverrides
+	}
+	if len(sourceVersions) > 0 {
+		obj.Object["valueSources"] = sourceVersions
+	}
+	return hashUnstructured(obj, "default overrides and value sources")
+}
+
+// valueSourceVersions returns the resource versions of the objects
+// referenced by the value sources of the overrides of the resource.
+func (r *federatedResource) valueSourceVersions() (map[string]interface{}, error) {
+	valueFromMap, err := r.getValueFromMap()
+	if err != nil || len(valueFromMap) == 0 || r.valueResolver == nil {
+		// Errors are reported when the object for a cluster is
+		// computed.
+		return nil, nil
+	}
+	versions := make(map[string]interface{})
+	for clusterName, sourcesByPath := range valueFromMap {
+		cluster, err := r.clusterForValueSources(clusterName)
+		if err != nil {
+			return nil, err
+		}
+		if cluster == nil {
+			continue
+		}
+		sources := make([]*util.OverrideValueSource, 0, len(sourcesByPath))
+		for _, source := range sourcesByPath {
+			sources = append(sources, source)
+		}
+		clusterVersions, err := r.valueResolver.Versions(r.federatedName.Namespace, cluster, sources)
+		if err != nil {
+			return nil, err
+		}
+		for name, version := range clusterVersions {
+			versions[name] = version
+		}
+	}
+	return versions, nil
+}
+
+// clusterForValueSources returns the cluster with the given name, or
+// nil if it is not joined.
+func (r *federatedResource) clusterForValueSources(clusterName string) (*fedv1b1.KubeFedCluster, error) {
+	if r.clusters == nil {
+		return nil, nil
+	}
+	clusters, err := r.clusters.GetClusters()
+	if err != nil {
+		return nil, err
+	}
+	for _, cluster := range clusters {
+		if cluster.Name == clusterName {
+			return cluster, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *federatedResource) HasOverrideValueSources() bool {
+	valueFromMap, err := r.getValueFromMap()
+	return err == nil && len(valueFromMap) > 0
 }
 
 func (r *federatedResource) VersionForCluster(clusterName string) (string, error) {
@@ -123,10 +311,24 @@ func (r *federatedResource) DeleteVersions() {
 }
 
 func (r *federatedResource) ComputePlacement(clusters []*fedv1b1.KubeFedCluster) (sets.String, error) {
+	var selectedClusters sets.String
+	var err error
 	if r.typeConfig.GetNamespaced() {
-		return computeNamespacedPlacement(r.federatedResource, r.fedNamespace, clusters, r.limitedScope)
+		selectedClusters, err = computeNamespacedPlacement(r.federatedResource, r.fedNamespace, clusters, r.limitedScope)
+	} else {
+		selectedClusters, err = computePlacement(r.federatedResource, clusters)
+	}
+	if err != nil {
+		return nil, err
 	}
-	return computePlacement(r.federatedResource, clusters)
+	return limitPlacement(r.federatedResource, selectedClusters, clusters)
+}
+
+// UnsatisfiedPlacement returns a message describing why the selected
+// clusters do not satisfy the placement of the resource, or an empty
+// string if they do.
+func (r *federatedResource) UnsatisfiedPlacement(clusters []*fedv1b1.KubeFedCluster, selectedClusters sets.String) (string, error) {
+	return unsatisfiedPlacement(r.federatedResource, selectedClusters, clusters)
 }
 
 func (r *federatedResource) IsNamespaceInHostCluster(clusterObj pkgruntime.Object) bool {
@@ -154,9 +356,9 @@ func (r *federatedResource) IsNamespaceInHostCluster(clusterObj pkgruntime.Objec
 
 // TODO(marun) Marshall the template once per reconcile, not per-cluster
 func (r *federatedResource) ObjectForCluster(clusterName string) (*unstructured.Unstructured, error) {
-	templateBody, ok, err := unstructured.NestedMap(r.federatedResource.Object, util.SpecField, util.TemplateField)
+	templateBody, ok, err := util.GetTemplate(r.federatedResource)
 	if err != nil {
-		return nil, errors.Wrap(err, "Error retrieving template body")
+		return nil, err
 	}
 	if !ok {
 		// Some resources (like namespaces) can be created from an
@@ -170,18 +372,66 @@ func (r *federatedResource) ObjectForCluster(clusterName string) (*unstructured.
 	// clusters.
 	//
 	// TODO(marun) this should be documented
-	obj.SetName(r.federatedResource.GetName())
+	//
+	// The namespace may be mapped to a different name for the
+	// cluster.
+	targetName := r.TargetNameForCluster(clusterName)
+	obj.SetName(targetName.Name)
 	if !r.targetIsNamespace {
-		obj.SetNamespace(r.federatedResource.GetNamespace())
+		obj.SetNamespace(targetName.Namespace)
 	}
 	targetApiResource := r.typeConfig.GetTargetType()
 	obj.SetKind(targetApiResource.Kind)
 	obj.SetAPIVersion(fmt.Sprintf("%s/%s", targetApiResource.Group, targetApiResource.Version))
 
+	// The template is converted to the version served by the cluster
+	// before overrides are applied, so that the overrides of a
+	// cluster are expressed in the version it serves.
+	if len(r.typeConfig.GetTargetVersions()) > 1 && r.clusters != nil {
+		version, err := r.clusters.TargetVersionForCluster(clusterName)
+		if err != nil {
+			return nil, err
+		}
+		if err := util.ConvertTarget(obj, version); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := r.checkEncryptedOverrides(obj, clusterName, targetName); err != nil {
+		return nil, errors.Wrapf(err, "Failed to compute the resource for cluster %q", clusterName)
+	}
+
+	// Labels and annotations set by the template take precedence
+	// over those synced from the host namespace.
+	labels, annotations := r.syncedNamespaceMetadata()
+	util.AddSyncedNamespaceMetadata(obj, labels, annotations)
+
+	if err := util.ApplyDefaultOverrides(obj, r.typeConfig.GetDefaultOverrides()); err != nil {
+		return nil, err
+	}
+
+	// The values files of a cluster are merged before the overrides
+	// of individual fields are applied.
+	values, err := r.valuesForCluster(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	if valuesConfig := r.typeConfig.GetValuesConfig(); valuesConfig != nil {
+		if err := util.ApplyValuesOverrides(obj, valuesConfig, values); err != nil {
+			return nil, errors.Wrapf(err, "Failed to apply the values for cluster %q", clusterName)
+		}
+	} else if values != nil {
+		return nil, errors.Errorf("values are defined for cluster %q but the FederatedTypeConfig of %s does not configure the chart values of the target type", clusterName, r.FederatedKind())
+	}
+
 	overrides, err := r.overridesForCluster(clusterName)
 	if err != nil {
 		return nil, err
 	}
+	_, renamed := overrides[util.NameOverridePath]
+	if renamed && r.targetIsNamespace {
+		return nil, errors.Errorf("the name of a namespace cannot be overridden for cluster %q; a namespace mapping should be used instead", clusterName)
+	}
 	if overrides != nil {
 		for path, value := range overrides {
 			pathEntries := strings.Split(path, ".")
@@ -190,11 +440,42 @@ func (r *federatedResource) ObjectForCluster(clusterName string) (*unstructured.
 			}
 		}
 	}
+	if err := r.applyOverrideValueSources(obj, clusterName); err != nil {
+		return nil, err
+	}
+
+	// Patches are applied last, to the resource as overridden for
+	// the cluster.
+	patches, err := r.patchesForCluster(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	if err := util.ApplyPatches(obj, patches); err != nil {
+		return nil, errors.Wrapf(err, "Failed to apply the patches for cluster %q", clusterName)
+	}
 
 	// Ensure that resources managed by KubeFed always have the
 	// managed label.  The label is intended to be targeted by all the
 	// KubeFed controllers.
 	util.AddManagedLabel(obj)
+	util.SetOwner(obj, util.OwnerKey(r.FederatedKind(), r.federatedName))
+
+	templateHash, err := r.TemplateVersion()
+	if err != nil {
+		return nil, err
+	}
+	util.AddPropagationMetadata(obj, r.propagationMetadata, util.PropagationOrigin{
+		FederatedKind: r.FederatedKind(),
+		FederatedName: r.federatedName,
+		TemplateHash:  templateHash,
+		Renamed:       renamed,
+	})
+
+	// The desired state is annotated last so that its hash covers
+	// everything rendered for the cluster.
+	if err := util.AddDesiredStateMetadata(obj, r.federatedResource.GetGeneration()); err != nil {
+		return nil, err
+	}
 
 	return obj, nil
 }
@@ -221,17 +502,138 @@ func (r *federatedResource) overridesForCluster(clusterName string) (util.Cluste
 	return r.overridesMap[clusterName], nil
 }
 
+func (r *federatedResource) valuesForCluster(clusterName string) (map[string]interface{}, error) {
+	r.Lock()
+	defer r.Unlock()
+	if r.valuesMap == nil {
+		valuesMap, err := util.GetValuesOverrides(r.federatedResource)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error reading cluster values")
+		}
+		r.valuesMap = valuesMap
+	}
+	return r.valuesMap[clusterName], nil
+}
+
+// checkEncryptedOverrides returns an error if the overrides of the
+// given cluster would prevent the cluster from decrypting the payload
+// of the given object.
+func (r *federatedResource) checkEncryptedOverrides(obj *unstructured.Unstructured, clusterName string, clusterTargetName util.QualifiedName) error {
+	if !util.IsSOPSEncrypted(obj) && !util.IsSealedSecret(obj) {
+		return nil
+	}
+	overrides, err := r.overridesForCluster(clusterName)
+	if err != nil {
+		return err
+	}
+	valueFromMap, err := r.getValueFromMap()
+	if err != nil {
+		return err
+	}
+	paths := []string{}
+	for path := range overrides {
+		paths = append(paths, path)
+	}
+	for path := range valueFromMap[clusterName] {
+		paths = append(paths, path)
+	}
+	values, err := r.valuesForCluster(clusterName)
+	if err != nil {
+		return err
+	}
+	patches, err := r.patchesForCluster(clusterName)
+	if err != nil {
+		return err
+	}
+	return util.CheckEncryptedOverrides(obj, paths, values != nil || len(patches) > 0, r.targetName, clusterTargetName)
+}
+
+// applyOverrideValueSources sets the paths of the overrides of the
+// given cluster whose values are sourced from ConfigMaps or Secrets
+// to the resolved values.  Values may only be sourced from Secrets
+// into Secrets, since the controller reads Secrets with its own
+// credentials on behalf of users that may not be permitted to read
+// them.
+func (r *federatedResource) applyOverrideValueSources(obj *unstructured.Unstructured, clusterName string) error {
+	valueFromMap, err := r.getValueFromMap()
+	if err != nil {
+		return err
+	}
+	sources := valueFromMap[clusterName]
+	if len(sources) == 0 {
+		return nil
+	}
+	targetIsSecret := obj.GroupVersionKind().GroupKind() == schema.GroupKind{Kind: util.SecretKind}
+	for path, source := range sources {
+		if source.SecretKeyRef != nil && !targetIsSecret {
+			return errors.Errorf("the value of path %q for cluster %q cannot be sourced from a Secret since the target resource is a %s", path, clusterName, obj.GetKind())
+		}
+	}
+	if r.valueResolver == nil {
+		return errors.Errorf("the overrides for cluster %q have value sources but the %s feature is not enabled", clusterName, features.OverrideValueSources)
+	}
+	cluster, err := r.clusterForValueSources(clusterName)
+	if err != nil {
+		return err
+	}
+	if cluster == nil {
+		return errors.Errorf("cluster %q is not joined", clusterName)
+	}
+	for path, source := range sources {
+		value, ok, err := r.valueResolver.Resolve(r.federatedName.Namespace, cluster, source)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to resolve the value of path %q for cluster %q", path, clusterName)
+		}
+		if !ok {
+			continue
+		}
+		if err := unstructured.SetNestedField(obj.Object, value, strings.Split(path, ".")...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *federatedResource) getValueFromMap() (map[string]map[string]*util.OverrideValueSource, error) {
+	r.Lock()
+	defer r.Unlock()
+	if r.valueFromMap == nil {
+		valueFromMap, err := util.GetValueFromOverrides(r.federatedResource)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error reading the value sources of cluster overrides")
+		}
+		r.valueFromMap = valueFromMap
+	}
+	return r.valueFromMap, nil
+}
+
+func (r *federatedResource) patchesForCluster(clusterName string) ([]util.ClusterPatch, error) {
+	r.Lock()
+	defer r.Unlock()
+	if r.patchesMap == nil {
+		patchesMap, err := util.GetPatchOverrides(r.federatedResource)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error reading cluster patches")
+		}
+		r.patchesMap = patchesMap
+	}
+	return r.patchesMap[clusterName], nil
+}
+
+// GetTemplateHash computes the hash of the template of the given
+// federated resource.  A compressed template hashes the same as the
+// equivalent template that is not compressed so that changing how a
+// template is stored does not result in propagation.
 func GetTemplateHash(fieldMap map[string]interface{}) (string, error) {
-	fields := []string{util.SpecField, util.TemplateField}
-	fieldMap, ok, err := unstructured.NestedMap(fieldMap, fields...)
+	template, ok, err := util.GetTemplate(&unstructured.Unstructured{Object: fieldMap})
 	if err != nil {
-		return "", errors.Wrapf(err, "Error retrieving %q", strings.Join(fields, "."))
+		return "", err
 	}
 	if !ok {
 		return "", nil
 	}
-	obj := &unstructured.Unstructured{Object: fieldMap}
-	description := strings.Join(fields, ".")
+	obj := &unstructured.Unstructured{Object: template}
+	description := strings.Join([]string{util.SpecField, util.TemplateField}, ".")
 	return hashUnstructured(obj, description)
 }
 
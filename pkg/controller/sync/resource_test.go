@@ -17,11 +17,22 @@ limitations under the License.
 package sync
 
 import (
+	"context"
 	"strings"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
 	kfenable "sigs.k8s.io/kubefed/pkg/kubefedctl/enable"
 )
 
@@ -47,3 +58,301 @@ spec:
 		t.Fatalf("Expected %s, got %s", expectedHash, hash)
 	}
 }
+
+func TestGetTemplateHashOfCompressedTemplate(t *testing.T) {
+	fedObj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	template := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"foo": nil,
+		},
+	}
+	if err := util.SetTemplate(fedObj, template, false); err != nil {
+		t.Fatalf("An unexpected error occurred: %v", err)
+	}
+	expectedHash, err := GetTemplateHash(fedObj.Object)
+	if err != nil {
+		t.Fatalf("An unexpected error occurred: %v", err)
+	}
+	if err := util.CompressTemplate(fedObj); err != nil {
+		t.Fatalf("An unexpected error occurred: %v", err)
+	}
+	hash, err := GetTemplateHash(fedObj.Object)
+	if err != nil {
+		t.Fatalf("An unexpected error occurred: %v", err)
+	}
+	if hash != expectedHash {
+		t.Fatalf("Expected the hash of a compressed template to be %s, got %s", expectedHash, hash)
+	}
+}
+
+func TestSyncedNamespaceMetadata(t *testing.T) {
+	typeConfig := &fedv1b1.FederatedTypeConfig{
+		Spec: fedv1b1.FederatedTypeConfigSpec{
+			TargetType:    fedv1b1.APIResource{Version: "v1", Kind: util.NamespaceKind, Scope: "Cluster"},
+			FederatedType: fedv1b1.APIResource{Group: "types.kubefed.k8s.io", Version: "v1beta1", Kind: "FederatedNamespace", Scope: "Namespaced"},
+		},
+	}
+	fedObj := &unstructured.Unstructured{}
+	fedObj.SetNamespace("ns")
+	fedObj.SetName("ns")
+	err := unstructured.SetNestedStringMap(fedObj.Object, map[string]string{"istio-injection": "disabled"}, "spec", "template", "metadata", "labels")
+	if err != nil {
+		t.Fatalf("An unexpected error occurred: %v", err)
+	}
+	namespace := &unstructured.Unstructured{}
+	namespace.SetName("ns")
+	namespace.SetLabels(map[string]string{
+		"pod-security.kubernetes.io/enforce": "restricted",
+		"istio-injection":                    "enabled",
+		"team":                               "web",
+	})
+
+	newResource := func(config *fedv1b1.NamespaceMetadataConfig) *federatedResource {
+		return &federatedResource{
+			typeConfig:        typeConfig,
+			targetIsNamespace: true,
+			targetName:        util.QualifiedName{Name: "ns"},
+			federatedName:     util.QualifiedName{Namespace: "ns", Name: "ns"},
+			federatedResource: fedObj,
+			namespace:         namespace,
+			namespaceMetadata: config,
+		}
+	}
+
+	unsyncedResource := newResource(nil)
+	obj, err := unsyncedResource.ObjectForCluster("cluster1")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"istio-injection": "disabled", util.ManagedByKubeFedLabelKey: util.ManagedByKubeFedLabelValue}, obj.GetLabels())
+	unsyncedVersion, err := unsyncedResource.TemplateVersion()
+	assert.NoError(t, err)
+	templateHash, err := GetTemplateHash(fedObj.Object)
+	assert.NoError(t, err)
+	assert.Equal(t, templateHash, unsyncedVersion)
+
+	syncedResource := newResource(&fedv1b1.NamespaceMetadataConfig{Labels: []string{"pod-security.kubernetes.io/*", "istio-injection"}})
+	obj, err = syncedResource.ObjectForCluster("cluster1")
+	assert.NoError(t, err)
+	expectedLabels := map[string]string{
+		"pod-security.kubernetes.io/enforce": "restricted",
+		"istio-injection":                    "disabled",
+		util.ManagedByKubeFedLabelKey:        util.ManagedByKubeFedLabelValue,
+	}
+	assert.Equal(t, expectedLabels, obj.GetLabels())
+	syncedVersion, err := syncedResource.TemplateVersion()
+	assert.NoError(t, err)
+	assert.NotEqual(t, unsyncedVersion, syncedVersion)
+
+	// A change to the synced metadata of the host namespace changes
+	// the template version.
+	namespace.SetLabels(map[string]string{"pod-security.kubernetes.io/enforce": "baseline"})
+	changedVersion, err := syncedResource.TemplateVersion()
+	assert.NoError(t, err)
+	assert.NotEqual(t, syncedVersion, changedVersion)
+}
+
+func TestObjectForClusterValues(t *testing.T) {
+	typeConfig := &fedv1b1.FederatedTypeConfig{
+		Spec: fedv1b1.FederatedTypeConfigSpec{
+			TargetType:    fedv1b1.APIResource{Group: "helm.toolkit.fluxcd.io", Version: "v2", Kind: "HelmRelease", Scope: "Namespaced"},
+			FederatedType: fedv1b1.APIResource{Group: "types.kubefed.k8s.io", Version: "v1beta1", Kind: "FederatedHelmRelease", Scope: "Namespaced"},
+		},
+	}
+	fedObj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"values": map[string]interface{}{"replicaCount": int64(1), "region": "default"},
+				},
+			},
+			"overrides": []interface{}{
+				map[string]interface{}{
+					"clusterName": "cluster1",
+					"values":      "replicaCount: 3\n",
+					"clusterOverrides": []interface{}{
+						map[string]interface{}{"path": "spec.values.region", "value": "us-east"},
+					},
+				},
+			},
+		},
+	}}
+	fedObj.SetNamespace("ns")
+	fedObj.SetName("podinfo")
+	newResource := func() *federatedResource {
+		return &federatedResource{
+			typeConfig:        typeConfig,
+			targetName:        util.QualifiedName{Namespace: "ns", Name: "podinfo"},
+			federatedName:     util.QualifiedName{Namespace: "ns", Name: "podinfo"},
+			federatedResource: fedObj,
+		}
+	}
+
+	// Values cannot be overridden without the values configuration
+	// of the type.
+	_, err := newResource().ObjectForCluster("cluster1")
+	assert.Error(t, err)
+
+	typeConfig.Spec.Values = &fedv1b1.ValuesConfig{
+		Path:   "spec.values",
+		Schema: `{"properties": {"replicaCount": {"type": "integer", "maximum": 3}}}`,
+	}
+	resource := newResource()
+	obj, err := resource.ObjectForCluster("cluster1")
+	assert.NoError(t, err)
+	values, _, _ := unstructured.NestedMap(obj.Object, "spec", "values")
+	assert.Equal(t, map[string]interface{}{"replicaCount": int64(3), "region": "us-east"}, values)
+
+	obj, err = resource.ObjectForCluster("cluster2")
+	assert.NoError(t, err)
+	values, _, _ = unstructured.NestedMap(obj.Object, "spec", "values")
+	assert.Equal(t, map[string]interface{}{"replicaCount": int64(1), "region": "default"}, values)
+
+	typeConfig.Spec.Values.Schema = `{"properties": {"replicaCount": {"type": "integer", "maximum": 2}}}`
+	_, err = newResource().ObjectForCluster("cluster1")
+	assert.Error(t, err)
+}
+
+// configMapClient is a generic client that only serves gets of a
+// ConfigMap.
+type configMapClient struct {
+	genericclient.Client
+	configMap *corev1.ConfigMap
+}
+
+func (c *configMapClient) Get(ctx context.Context, obj pkgruntime.Object, namespace, name string) error {
+	if c.configMap.Namespace != namespace || c.configMap.Name != name {
+		return apierrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, name)
+	}
+	c.configMap.DeepCopyInto(obj.(*corev1.ConfigMap))
+	return nil
+}
+
+// staticClustersView is a view of a fixed set of ready clusters.
+type staticClustersView struct {
+	util.RegisteredClustersView
+	clusters []*fedv1b1.KubeFedCluster
+}
+
+func (v *staticClustersView) GetClusters() ([]*fedv1b1.KubeFedCluster, error) {
+	return v.clusters, nil
+}
+
+func (v *staticClustersView) GetReadyCluster(name string) (*fedv1b1.KubeFedCluster, bool, error) {
+	for _, cluster := range v.clusters {
+		if cluster.Name == name {
+			return cluster, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+func TestObjectForClusterValueSources(t *testing.T) {
+	typeConfig := &fedv1b1.FederatedTypeConfig{
+		Spec: fedv1b1.FederatedTypeConfigSpec{
+			TargetType:    fedv1b1.APIResource{Group: "apps", Version: "v1", Kind: "Deployment", Scope: "Namespaced"},
+			FederatedType: fedv1b1.APIResource{Group: "types.kubefed.k8s.io", Version: "v1beta1", Kind: "FederatedDeployment", Scope: "Namespaced"},
+		},
+	}
+	fedObj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{},
+			"overrides": []interface{}{
+				map[string]interface{}{
+					"clusterName": "cluster1",
+					"clusterOverrides": []interface{}{
+						map[string]interface{}{
+							"path": "spec.template.metadata.annotations.image",
+							"valueFrom": map[string]interface{}{
+								"clusterProfileKeyRef": map[string]interface{}{"key": "image"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}}
+	fedObj.SetNamespace("ns")
+	fedObj.SetName("web")
+	profile := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kube-federation-system", Name: "cluster1-profile", ResourceVersion: "1"},
+		Data:       map[string]string{"image": "registry.cluster1.example.com/web:1.0"},
+	}
+	clusters := &staticClustersView{clusters: []*fedv1b1.KubeFedCluster{{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster1"},
+		Spec:       fedv1b1.KubeFedClusterSpec{ProfileRef: &corev1.LocalObjectReference{Name: "cluster1-profile"}},
+	}}}
+	newResource := func(valueResolver *util.OverrideValueResolver) *federatedResource {
+		return &federatedResource{
+			typeConfig:        typeConfig,
+			targetName:        util.QualifiedName{Namespace: "ns", Name: "web"},
+			federatedName:     util.QualifiedName{Namespace: "ns", Name: "web"},
+			federatedResource: fedObj,
+			clusters:          clusters,
+			valueResolver:     valueResolver,
+		}
+	}
+	newResolver := func() *util.OverrideValueResolver {
+		return util.NewOverrideValueResolver(&configMapClient{configMap: profile}, "kube-federation-system")
+	}
+
+	// Value sources are not resolved if the feature is disabled.
+	resource := newResource(nil)
+	assert.True(t, resource.HasOverrideValueSources())
+	_, err := resource.ObjectForCluster("cluster1")
+	assert.Error(t, err)
+
+	resource = newResource(newResolver())
+	obj, err := resource.ObjectForCluster("cluster1")
+	assert.NoError(t, err)
+	image, _, _ := unstructured.NestedString(obj.Object, "spec", "template", "metadata", "annotations", "image")
+	assert.Equal(t, "registry.cluster1.example.com/web:1.0", image)
+	version, err := resource.OverrideVersion()
+	assert.NoError(t, err)
+
+	// A change to the profile changes the override version.
+	profile.Data["image"] = "registry.cluster1.example.com/web:1.1"
+	profile.ResourceVersion = "2"
+	updatedVersion, err := newResource(newResolver()).OverrideVersion()
+	assert.NoError(t, err)
+	assert.NotEqual(t, version, updatedVersion)
+}
+
+func TestObjectForClusterRejectsSecretValueSourcesForNonSecretTargets(t *testing.T) {
+	typeConfig := &fedv1b1.FederatedTypeConfig{
+		Spec: fedv1b1.FederatedTypeConfigSpec{
+			TargetType:    fedv1b1.APIResource{Version: "v1", Kind: "ConfigMap", Scope: "Namespaced"},
+			FederatedType: fedv1b1.APIResource{Group: "types.kubefed.k8s.io", Version: "v1beta1", Kind: "FederatedConfigMap", Scope: "Namespaced"},
+		},
+	}
+	fedObj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{},
+			"overrides": []interface{}{
+				map[string]interface{}{
+					"clusterName": "cluster1",
+					"clusterOverrides": []interface{}{
+						map[string]interface{}{
+							"path": "data.password",
+							"valueFrom": map[string]interface{}{
+								"secretKeyRef": map[string]interface{}{"name": "db", "key": "password"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}}
+	fedObj.SetNamespace("ns")
+	fedObj.SetName("app")
+	resource := &federatedResource{
+		typeConfig:        typeConfig,
+		targetName:        util.QualifiedName{Namespace: "ns", Name: "app"},
+		federatedName:     util.QualifiedName{Namespace: "ns", Name: "app"},
+		federatedResource: fedObj,
+		valueResolver:     util.NewOverrideValueResolver(&configMapClient{}, "kube-federation-system"),
+	}
+
+	_, err := resource.ObjectForCluster("cluster1")
+	if assert.Error(t, err, "A Secret value should not be written into a ConfigMap") {
+		assert.Contains(t, err.Error(), "cannot be sourced from a Secret")
+	}
+}
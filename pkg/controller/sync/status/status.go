@@ -18,6 +18,8 @@ package status
 
 import (
 	"encoding/json"
+	"reflect"
+	"sort"
 	"time"
 
 	"github.com/pkg/errors"
@@ -25,6 +27,7 @@ import (
 	apiv1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 
 	"sigs.k8s.io/kubefed/pkg/controller/util"
 )
@@ -54,6 +57,16 @@ const (
 	FieldRetentionFailed   PropagationStatus = "FieldRetentionFailed"
 	VersionRetrievalFailed PropagationStatus = "VersionRetrievalFailed"
 	ClientRetrievalFailed  PropagationStatus = "ClientRetrievalFailed"
+	NamespaceNotAllowed    PropagationStatus = "NamespaceNotAllowed"
+	ClusterNotAllowed      PropagationStatus = "ClusterNotAllowed"
+	PolicyViolation        PropagationStatus = "PolicyViolation"
+	DeletionPaused         PropagationStatus = "DeletionPaused"
+	RemovalPrevented       PropagationStatus = "RemovalPrevented"
+	ClusterDegraded        PropagationStatus = "ClusterDegraded"
+
+	// The resource in the cluster is managed by another federated
+	// resource.
+	ClusterOwnershipConflict PropagationStatus = "OwnershipConflict"
 
 	// Operation timeout errors
 	CreationTimedOut     PropagationStatus = "CreationTimedOut"
@@ -61,17 +74,84 @@ const (
 	DeletionTimedOut     PropagationStatus = "DeletionTimedOut"
 	LabelRemovalTimedOut PropagationStatus = "LabelRemovalTimedOut"
 
+	// Foreground deletion of the resource is blocked by finalizers
+	// in the cluster.
+	DeletionBlocked PropagationStatus = "DeletionBlocked"
+
+	// The resource in the cluster differs from its desired state but
+	// is not written while propagation of the type is paused.
+	ClusterPropagationPaused PropagationStatus = "PropagationPaused"
+
+	// The resource already exists in the cluster and would be
+	// adopted, but is not modified while resource adoption is
+	// report-only.
+	WouldAdopt PropagationStatus = "WouldAdopt"
+
 	AggregateSuccess       AggregateReason = ""
 	ClusterRetrievalFailed AggregateReason = "ClusterRetrievalFailed"
 	ComputePlacementFailed AggregateReason = "ComputePlacementFailed"
 	CheckClusters          AggregateReason = "CheckClusters"
+	DeletionsPaused        AggregateReason = "DeletionsPaused"
+	UnsatisfiablePlacement AggregateReason = "UnsatisfiablePlacement"
+	QuotaExceeded          AggregateReason = "QuotaExceeded"
+	DeletionInProgress     AggregateReason = "DeletionInProgress"
+	DeletionStuck          AggregateReason = "DeletionStuck"
+	PropagationPaused      AggregateReason = "PropagationPaused"
+	OwnershipConflict      AggregateReason = "OwnershipConflict"
+
+	NamespaceDeletionNotConfirmed AggregateReason = "NamespaceDeletionNotConfirmed"
 
 	PropagationConditionType ConditionType = "Propagation"
+	DeletionConditionType    ConditionType = "Deletion"
+	// The Conflict condition is only present, with a status of True,
+	// while a resource in a member cluster is targeted by more than
+	// one federated resource.
+	ConflictConditionType ConditionType = "Conflict"
 )
 
 type GenericClusterStatus struct {
 	Name   string            `json:"name"`
 	Status PropagationStatus `json:"status,omitempty"`
+	// ObservedVersion is the version of the resource in the cluster
+	// last observed by the sync controller, prefixed by its source
+	// (e.g. `gen:3` for a generation or `rv:1234` for a
+	// resourceVersion).
+	// +optional
+	ObservedVersion string `json:"observedVersion,omitempty"`
+	// LastAppliedTime is the time the sync controller last created
+	// or updated the resource in the cluster.
+	// +optional
+	LastAppliedTime string `json:"lastAppliedTime,omitempty"`
+	// Drift describes the most recent out-of-band change detected
+	// for the resource in the cluster.
+	// +optional
+	Drift *ClusterDrift `json:"drift,omitempty"`
+	// RemoteStatus is the status of the resource in the cluster, as
+	// collected when raw resource status collection is enabled.
+	// +optional
+	RemoteStatus interface{} `json:"remoteStatus,omitempty"`
+	// APIVersion is the version of the target type used for the
+	// cluster, as negotiated when more than one version is acceptable.
+	// +optional
+	APIVersion string `json:"apiVersion,omitempty"`
+	// LoadBalancer is the load balancer status of the service in the
+	// cluster.  Only recorded for services.
+	// +optional
+	LoadBalancer *apiv1.LoadBalancerStatus `json:"loadBalancer,omitempty"`
+}
+
+// ClusterDrift summarizes how a resource in a member cluster diverged
+// from the state last propagated by the sync controller.
+type ClusterDrift struct {
+	// Paths of the fields found to differ from the desired state.
+	// +optional
+	ChangedPaths []string `json:"changedPaths,omitempty"`
+	// Field managers that last modified the resource, most recent
+	// first.  Only populated for clusters that record managedFields.
+	// +optional
+	Managers []string `json:"managers,omitempty"`
+	// Time at which the drift was detected.
+	DetectedTime string `json:"detectedTime"`
 }
 
 type GenericCondition struct {
@@ -88,11 +168,41 @@ type GenericCondition struct {
 	// (brief) reason for the condition's last transition.
 	// +optional
 	Reason AggregateReason `json:"reason,omitempty"`
+	// Human readable message indicating details about the last
+	// transition.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 type GenericPropagationStatus struct {
 	Conditions []*GenericCondition    `json:"conditions,omitempty"`
 	Clusters   []GenericClusterStatus `json:"clusters,omitempty"`
+	// LoadBalancer merges the load balancer ingress points of the
+	// service in all clusters.  Only recorded for services.
+	// +optional
+	LoadBalancer *apiv1.LoadBalancerStatus `json:"loadBalancer,omitempty"`
+	// ServiceImports are the imports of the service by Submariner
+	// Lighthouse in each cluster.  Only recorded for services by the
+	// Lighthouse controller, and retained by the sync controller.
+	// +optional
+	ServiceImports []ClusterServiceImport `json:"serviceImports,omitempty"`
+}
+
+// ClusterServiceImport describes the ServiceImport of a service in a
+// cluster.
+type ClusterServiceImport struct {
+	// Name of the cluster
+	Cluster string `json:"cluster"`
+	// Type of the import, i.e. ClusterSetIP or Headless.
+	// +optional
+	Type string `json:"type,omitempty"`
+	// IPs by which the service is reachable across the cluster set.
+	// +optional
+	IPs []string `json:"ips,omitempty"`
+	// ExportingClusters are the clusters from which the service is
+	// imported, as observed in the cluster.
+	// +optional
+	ExportingClusters []string `json:"exportingClusters,omitempty"`
 }
 
 type GenericFederatedStatus struct {
@@ -104,10 +214,109 @@ type GenericFederatedStatus struct {
 
 type PropagationStatusMap map[string]PropagationStatus
 
+type ClusterDriftMap map[string]*ClusterDrift
+
+// ClusterRemoteStatusMap maps the names of clusters to the status of
+// the resource in the cluster.
+type ClusterRemoteStatusMap map[string]interface{}
+
+// ClusterAPIVersionMap maps the names of clusters to the API version
+// of the target type used for the cluster.
+type ClusterAPIVersionMap map[string]string
+
+// ClusterVersionMap maps the names of clusters to the version of the
+// resource observed in the cluster.
+type ClusterVersionMap map[string]string
+
+// ClusterAppliedTimeMap maps the names of clusters to the time the
+// resource was last applied to the cluster.
+type ClusterAppliedTimeMap map[string]string
+
+// ClusterLoadBalancerMap maps the names of clusters to the load
+// balancer status of the service in the cluster.
+type ClusterLoadBalancerMap map[string]*apiv1.LoadBalancerStatus
+
+// ClusterDetails holds the details of the resource in each cluster
+// that are recorded alongside its propagation status.
+type ClusterDetails struct {
+	Drift            ClusterDriftMap
+	RemoteStatus     ClusterRemoteStatusMap
+	APIVersions      ClusterAPIVersionMap
+	ObservedVersions ClusterVersionMap
+	AppliedTimes     ClusterAppliedTimeMap
+	// LoadBalancers is nil for types other than services.
+	LoadBalancers ClusterLoadBalancerMap
+	// Conflict describes the resources in member clusters that are
+	// targeted by another federated resource, if any.
+	Conflict string
+}
+
+// FailedClusters returns the sorted names of the clusters for which
+// propagation failed.  Clusters that are not ready, awaiting removal
+// of the resource, retaining it for an unsatisfied placement or not
+// written while propagation is paused are not considered to have
+// failed.
+func (m PropagationStatusMap) FailedClusters() []string {
+	clusterNames := []string{}
+	for clusterName, status := range m {
+		switch status {
+		case ClusterPropagationOK, WaitingForRemoval, RemovalPrevented, ClusterNotReady, ClusterPropagationPaused, WouldAdopt:
+			continue
+		}
+		clusterNames = append(clusterNames, clusterName)
+	}
+	sort.Strings(clusterNames)
+	return clusterNames
+}
+
+// GetPropagationStatusMap returns the cluster status currently
+// recorded in the status of the federated resource.
+func GetPropagationStatusMap(fedObject *unstructured.Unstructured) (PropagationStatusMap, error) {
+	status := &GenericFederatedStatus{}
+	err := util.UnstructuredToInterface(fedObject, status)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to unmarshall to generic status")
+	}
+	statusMap := make(PropagationStatusMap)
+	if status.Status == nil {
+		return statusMap, nil
+	}
+	for _, cluster := range status.Status.Clusters {
+		statusMap[cluster.Name] = cluster.Status
+	}
+	return statusMap, nil
+}
+
+// GetAdmittedClusterCount returns the number of clusters recorded in
+// the status of the federated resource and whether the resource was
+// admitted by the propagation quotas of its namespace when its status
+// was last recorded.  A resource whose propagation was not recorded,
+// or failed before quotas were checked, is not considered admitted.
+func GetAdmittedClusterCount(fedObject *unstructured.Unstructured) (int, bool, error) {
+	status := &GenericFederatedStatus{}
+	err := util.UnstructuredToInterface(fedObject, status)
+	if err != nil {
+		return 0, false, errors.Wrapf(err, "Failed to unmarshall to generic status")
+	}
+	if status.Status == nil {
+		return 0, false, nil
+	}
+	condition := status.Status.condition(PropagationConditionType)
+	if condition == nil {
+		return 0, false, nil
+	}
+	switch condition.Reason {
+	case QuotaExceeded, ClusterRetrievalFailed, ComputePlacementFailed:
+		return 0, false, nil
+	}
+	return len(status.Status.Clusters), true, nil
+}
+
 // SetPropagationStatus sets the conditions and clusters fields of the
-// federated resource's object map from the provided reason and
-// cluster status map.
-func SetPropagationStatus(fedObject *unstructured.Unstructured, reason AggregateReason, statusMap PropagationStatusMap) error {
+// federated resource's object map from the provided reason, cluster
+// status map and cluster details.
+func SetPropagationStatus(fedObject *unstructured.Unstructured, reason AggregateReason, statusMap PropagationStatusMap,
+	details ClusterDetails) error {
 	status := &GenericFederatedStatus{}
 	err := util.UnstructuredToInterface(fedObject, status)
 	if err != nil {
@@ -128,9 +337,64 @@ func SetPropagationStatus(fedObject *unstructured.Unstructured, reason Aggregate
 			}
 		}
 	}
-	propStatus.setPropagationCondition(reason)
-	propStatus.setClusterStatus(statusMap)
+	propStatus.setCondition(PropagationConditionType, reason, "")
+	propStatus.setConflictCondition(details.Conflict)
+	propStatus.setClusterStatus(statusMap, details)
 
+	return setStatus(fedObject, status)
+}
+
+// SetDeletionStatus sets the Deletion condition and clusters fields
+// of a federated resource that is being deleted from the provided
+// reason, message and cluster status map.  False is returned if the
+// status already reflected the provided values and was left
+// unchanged.
+func SetDeletionStatus(fedObject *unstructured.Unstructured, reason AggregateReason, message string, statusMap PropagationStatusMap) (bool, error) {
+	status := &GenericFederatedStatus{}
+	err := util.UnstructuredToInterface(fedObject, status)
+	if err != nil {
+		return false, errors.Wrapf(err, "Failed to unmarshall to generic status")
+	}
+	if status.Status == nil {
+		status.Status = &GenericPropagationStatus{}
+	}
+	propStatus := status.Status
+
+	condition := propStatus.condition(DeletionConditionType)
+	if condition != nil && condition.Reason == reason && condition.Message == message && propStatus.hasClusterStatus(statusMap) {
+		return false, nil
+	}
+	propStatus.setCondition(DeletionConditionType, reason, message)
+	propStatus.setClusterStatus(statusMap, ClusterDetails{})
+
+	return true, setStatus(fedObject, status)
+}
+
+// SetServiceImports sets the service imports of the status of the
+// federated resource.  False is returned if the status already
+// reflected the provided imports and was left unchanged.
+func SetServiceImports(fedObject *unstructured.Unstructured, imports []ClusterServiceImport) (bool, error) {
+	status := &GenericFederatedStatus{}
+	err := util.UnstructuredToInterface(fedObject, status)
+	if err != nil {
+		return false, errors.Wrapf(err, "Failed to unmarshall to generic status")
+	}
+	if status.Status == nil {
+		status.Status = &GenericPropagationStatus{}
+	}
+	if len(imports) == 0 {
+		imports = nil
+	}
+	if reflect.DeepEqual(status.Status.ServiceImports, imports) {
+		return false, nil
+	}
+	status.Status.ServiceImports = imports
+	return true, setStatus(fedObject, status)
+}
+
+// setStatus sets the status field of the federated resource's object
+// map from the given generic status.
+func setStatus(fedObject *unstructured.Unstructured, status *GenericFederatedStatus) error {
 	statusJSON, err := json.Marshal(status)
 	if err != nil {
 		return errors.Wrapf(err, "Failed to marshall generic status to json")
@@ -145,10 +409,50 @@ func SetPropagationStatus(fedObject *unstructured.Unstructured, reason Aggregate
 	return nil
 }
 
-// setPropagationCondition ensures that the Propagation condition is
-// updated to reflect the given reason.  The type of the condition is
-// derived from the reason (empty -> True, not empty -> False).
-func (s *GenericPropagationStatus) setPropagationCondition(reason AggregateReason) {
+// setConflictCondition ensures that the Conflict condition reflects
+// the given message, or that it is removed if the message is empty.
+func (s *GenericPropagationStatus) setConflictCondition(message string) {
+	if len(message) == 0 {
+		for i, condition := range s.Conditions {
+			if condition.Type == ConflictConditionType {
+				s.Conditions = append(s.Conditions[:i], s.Conditions[i+1:]...)
+				break
+			}
+		}
+		return
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	condition := s.condition(ConflictConditionType)
+	if condition == nil {
+		condition = &GenericCondition{
+			Type:               ConflictConditionType,
+			Status:             apiv1.ConditionTrue,
+			LastTransitionTime: now,
+		}
+		s.Conditions = append(s.Conditions, condition)
+	}
+	condition.Reason = OwnershipConflict
+	condition.Message = message
+	condition.LastProbeTime = now
+}
+
+// condition returns the condition of the given type, or nil if the
+// status does not include it.
+func (s *GenericPropagationStatus) condition(conditionType ConditionType) *GenericCondition {
+	for _, condition := range s.Conditions {
+		if condition.Type == conditionType {
+			return condition
+		}
+	}
+	return nil
+}
+
+// setCondition ensures that the condition of the given type is
+// updated to reflect the given reason and message.  The status of the
+// condition is derived from the reason (empty -> True, not empty ->
+// False).
+func (s *GenericPropagationStatus) setCondition(conditionType ConditionType, reason AggregateReason, message string) {
 	// Determine the appropriate status from the reason.
 	var newStatus apiv1.ConditionStatus
 	if reason == AggregateSuccess {
@@ -160,18 +464,12 @@ func (s *GenericPropagationStatus) setPropagationCondition(reason AggregateReaso
 	if s.Conditions == nil {
 		s.Conditions = []*GenericCondition{}
 	}
-	var propCondition *GenericCondition
-	for _, condition := range s.Conditions {
-		if condition.Type == PropagationConditionType {
-			propCondition = condition
-			break
-		}
-	}
+	propCondition := s.condition(conditionType)
 
 	newCondition := propCondition == nil
 	if newCondition {
 		propCondition = &GenericCondition{
-			Type: PropagationConditionType,
+			Type: conditionType,
 		}
 		s.Conditions = append(s.Conditions, propCondition)
 	}
@@ -186,18 +484,106 @@ func (s *GenericPropagationStatus) setPropagationCondition(reason AggregateReaso
 	}
 
 	propCondition.Reason = reason
+	propCondition.Message = message
 	propCondition.LastProbeTime = time.Now().UTC().Format(time.RFC3339)
 
 }
 
 // setClusterStatus sets the cluster status slice from a propagation
-// status map.
-func (s *GenericPropagationStatus) setClusterStatus(statusMap PropagationStatusMap) {
+// status map.  Drift and applied times previously recorded for a
+// cluster are retained unless the details contain a more recent entry
+// for the cluster.  Remote status, observed versions, API versions and
+// load balancer status are only recorded for the clusters in their
+// maps.
+func (s *GenericPropagationStatus) setClusterStatus(statusMap PropagationStatusMap, details ClusterDetails) {
+	previousDrift := make(ClusterDriftMap)
+	previousAppliedTimes := make(ClusterAppliedTimeMap)
+	for _, cluster := range s.Clusters {
+		if cluster.Drift != nil {
+			previousDrift[cluster.Name] = cluster.Drift
+		}
+		if len(cluster.LastAppliedTime) > 0 {
+			previousAppliedTimes[cluster.Name] = cluster.LastAppliedTime
+		}
+	}
+
 	s.Clusters = []GenericClusterStatus{}
 	for clusterName, status := range statusMap {
+		drift, ok := details.Drift[clusterName]
+		if !ok {
+			drift = previousDrift[clusterName]
+		}
+		appliedTime, ok := details.AppliedTimes[clusterName]
+		if !ok {
+			appliedTime = previousAppliedTimes[clusterName]
+		}
 		s.Clusters = append(s.Clusters, GenericClusterStatus{
-			Name:   clusterName,
-			Status: status,
+			Name:            clusterName,
+			Status:          status,
+			ObservedVersion: details.ObservedVersions[clusterName],
+			LastAppliedTime: appliedTime,
+			Drift:           drift,
+			RemoteStatus:    details.RemoteStatus[clusterName],
+			APIVersion:      details.APIVersions[clusterName],
+			LoadBalancer:    details.LoadBalancers[clusterName],
 		})
 	}
+	s.LoadBalancer = details.LoadBalancers.merge()
+}
+
+// merge returns the distinct load balancer ingress points of all
+// clusters in a stable order, or nil if the map is nil.
+func (m ClusterLoadBalancerMap) merge() *apiv1.LoadBalancerStatus {
+	if m == nil {
+		return nil
+	}
+	merged := &apiv1.LoadBalancerStatus{}
+	seen := make(map[apiv1.LoadBalancerIngress]bool)
+	for _, lbStatus := range m {
+		for _, ingress := range lbStatus.Ingress {
+			if !seen[ingress] {
+				seen[ingress] = true
+				merged.Ingress = append(merged.Ingress, ingress)
+			}
+		}
+	}
+	sort.Slice(merged.Ingress, func(i, j int) bool {
+		if merged.Ingress[i].IP == merged.Ingress[j].IP {
+			return merged.Ingress[i].Hostname < merged.Ingress[j].Hostname
+		}
+		return merged.Ingress[i].IP < merged.Ingress[j].IP
+	})
+	return merged
+}
+
+// LoadBalancerStatus returns the load balancer status of the given
+// service, or nil if no ingress points are recorded for it.
+func LoadBalancerStatus(service *unstructured.Unstructured) (*apiv1.LoadBalancerStatus, error) {
+	lbObj, ok, err := unstructured.NestedMap(service.Object, util.StatusField, "loadBalancer")
+	if err != nil || !ok {
+		return nil, err
+	}
+	lbStatus := &apiv1.LoadBalancerStatus{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(lbObj, lbStatus); err != nil {
+		return nil, errors.Wrap(err, "Failed to convert the load balancer status")
+	}
+	if len(lbStatus.Ingress) == 0 {
+		return nil, nil
+	}
+	return lbStatus, nil
+}
+
+// hasClusterStatus indicates whether the cluster status slice
+// reflects the given propagation status map.
+func (s *GenericPropagationStatus) hasClusterStatus(statusMap PropagationStatusMap) bool {
+	if len(s.Clusters) != len(statusMap) {
+		return false
+	}
+	for _, cluster := range s.Clusters {
+		status, ok := statusMap[cluster.Name]
+		if !ok || status != cluster.Status {
+			return false
+		}
+	}
+	return true
 }
@@ -0,0 +1,273 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+func TestSetPropagationStatusWithRemoteStatus(t *testing.T) {
+	fedObject := &unstructured.Unstructured{}
+	fedObject.SetAPIVersion("types.kubefed.k8s.io/v1beta1")
+	fedObject.SetKind("FederatedCronTab")
+	statusMap := PropagationStatusMap{
+		"cluster1": ClusterPropagationOK,
+		"cluster2": ClusterPropagationOK,
+	}
+	remoteStatusMap := ClusterRemoteStatusMap{
+		"cluster1": map[string]interface{}{"active": int64(1)},
+	}
+	err := SetPropagationStatus(fedObject, AggregateSuccess, statusMap, ClusterDetails{RemoteStatus: remoteStatusMap})
+	assert.NoError(t, err)
+
+	remoteStatus := clusterStatusField(t, fedObject, "remoteStatus")
+	assert.Equal(t, map[string]interface{}{"active": int64(1)}, remoteStatus["cluster1"])
+	assert.Nil(t, remoteStatus["cluster2"])
+
+	// Remote status is not retained once it is no longer collected.
+	err = SetPropagationStatus(fedObject, AggregateSuccess, statusMap, ClusterDetails{})
+	assert.NoError(t, err)
+	remoteStatus = clusterStatusField(t, fedObject, "remoteStatus")
+	assert.Nil(t, remoteStatus["cluster1"])
+}
+
+func TestSetPropagationStatusWithAPIVersions(t *testing.T) {
+	fedObject := &unstructured.Unstructured{}
+	fedObject.SetAPIVersion("types.kubefed.k8s.io/v1beta1")
+	fedObject.SetKind("FederatedIngress")
+	statusMap := PropagationStatusMap{
+		"cluster1": ClusterPropagationOK,
+		"cluster2": ClusterPropagationOK,
+	}
+	apiVersions := ClusterAPIVersionMap{
+		"cluster1": "networking.k8s.io/v1",
+		"cluster2": "networking.k8s.io/v1beta1",
+	}
+	err := SetPropagationStatus(fedObject, AggregateSuccess, statusMap, ClusterDetails{APIVersions: apiVersions})
+	assert.NoError(t, err)
+
+	versions := clusterStatusField(t, fedObject, "apiVersion")
+	assert.Equal(t, "networking.k8s.io/v1", versions["cluster1"])
+	assert.Equal(t, "networking.k8s.io/v1beta1", versions["cluster2"])
+}
+
+func TestSetPropagationStatusWithVersionsAndAppliedTimes(t *testing.T) {
+	fedObject := &unstructured.Unstructured{}
+	fedObject.SetAPIVersion("types.kubefed.k8s.io/v1beta1")
+	fedObject.SetKind("FederatedDeployment")
+	statusMap := PropagationStatusMap{
+		"cluster1": ClusterPropagationOK,
+		"cluster2": ClusterPropagationOK,
+	}
+	err := SetPropagationStatus(fedObject, AggregateSuccess, statusMap, ClusterDetails{
+		ObservedVersions: ClusterVersionMap{"cluster1": "gen:2", "cluster2": "gen:5"},
+		AppliedTimes:     ClusterAppliedTimeMap{"cluster1": "2019-05-08T01:10:04Z"},
+	})
+	assert.NoError(t, err)
+
+	versions := clusterStatusField(t, fedObject, "observedVersion")
+	assert.Equal(t, "gen:2", versions["cluster1"])
+	assert.Equal(t, "gen:5", versions["cluster2"])
+	appliedTimes := clusterStatusField(t, fedObject, "lastAppliedTime")
+	assert.Equal(t, "2019-05-08T01:10:04Z", appliedTimes["cluster1"])
+	assert.Nil(t, appliedTimes["cluster2"])
+
+	// Applied times are retained until the resource is applied again,
+	// while observed versions reflect the latest observation.
+	err = SetPropagationStatus(fedObject, AggregateSuccess, statusMap, ClusterDetails{
+		ObservedVersions: ClusterVersionMap{"cluster1": "gen:3"},
+		AppliedTimes:     ClusterAppliedTimeMap{"cluster2": "2019-05-08T01:12:00Z"},
+	})
+	assert.NoError(t, err)
+	versions = clusterStatusField(t, fedObject, "observedVersion")
+	assert.Equal(t, "gen:3", versions["cluster1"])
+	assert.Nil(t, versions["cluster2"])
+	appliedTimes = clusterStatusField(t, fedObject, "lastAppliedTime")
+	assert.Equal(t, "2019-05-08T01:10:04Z", appliedTimes["cluster1"])
+	assert.Equal(t, "2019-05-08T01:12:00Z", appliedTimes["cluster2"])
+}
+
+func TestSetPropagationStatusWithConflict(t *testing.T) {
+	fedObject := &unstructured.Unstructured{}
+	fedObject.SetAPIVersion("types.kubefed.k8s.io/v1beta1")
+	fedObject.SetKind("FederatedConfigMap")
+	statusMap := PropagationStatusMap{
+		"cluster1": ClusterPropagationOK,
+		"cluster2": ClusterOwnershipConflict,
+	}
+	message := "ConfigMap in cluster cluster2 is managed by FederatedConfigMap/ns/b"
+	err := SetPropagationStatus(fedObject, AggregateSuccess, statusMap, ClusterDetails{Conflict: message})
+	assert.NoError(t, err)
+
+	status := propagationStatus(t, fedObject)
+	condition := status.condition(ConflictConditionType)
+	if assert.NotNil(t, condition) {
+		assert.Equal(t, apiv1.ConditionTrue, condition.Status)
+		assert.Equal(t, OwnershipConflict, condition.Reason)
+		assert.Equal(t, message, condition.Message)
+	}
+	assert.Equal(t, CheckClusters, status.condition(PropagationConditionType).Reason)
+
+	// The condition is removed once the conflict is resolved.
+	statusMap["cluster2"] = ClusterPropagationOK
+	err = SetPropagationStatus(fedObject, AggregateSuccess, statusMap, ClusterDetails{})
+	assert.NoError(t, err)
+	status = propagationStatus(t, fedObject)
+	assert.Nil(t, status.condition(ConflictConditionType))
+}
+
+func TestGetAdmittedClusterCount(t *testing.T) {
+	fedObject := &unstructured.Unstructured{}
+	fedObject.SetAPIVersion("types.kubefed.k8s.io/v1beta1")
+	fedObject.SetKind("FederatedDeployment")
+
+	// A resource without a recorded propagation was never admitted.
+	_, admitted, err := GetAdmittedClusterCount(fedObject)
+	assert.NoError(t, err)
+	assert.False(t, admitted)
+
+	statusMap := PropagationStatusMap{
+		"cluster1": ClusterPropagationOK,
+		"cluster2": ClusterNotReady,
+	}
+	err = SetPropagationStatus(fedObject, AggregateSuccess, statusMap, ClusterDetails{})
+	assert.NoError(t, err)
+	clusterCount, admitted, err := GetAdmittedClusterCount(fedObject)
+	assert.NoError(t, err)
+	assert.True(t, admitted)
+	assert.Equal(t, 2, clusterCount)
+
+	err = SetPropagationStatus(fedObject, QuotaExceeded, nil, ClusterDetails{})
+	assert.NoError(t, err)
+	_, admitted, err = GetAdmittedClusterCount(fedObject)
+	assert.NoError(t, err)
+	assert.False(t, admitted)
+}
+
+func TestSetPropagationStatusWithLoadBalancers(t *testing.T) {
+	fedObject := &unstructured.Unstructured{}
+	fedObject.SetAPIVersion("types.kubefed.k8s.io/v1beta1")
+	fedObject.SetKind("FederatedService")
+	statusMap := PropagationStatusMap{
+		"cluster1": ClusterPropagationOK,
+		"cluster2": ClusterPropagationOK,
+		"cluster3": ClusterPropagationOK,
+	}
+
+	service := &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{
+			"loadBalancer": map[string]interface{}{
+				"ingress": []interface{}{
+					map[string]interface{}{"ip": "10.0.0.2"},
+					map[string]interface{}{"hostname": "lb.example.com"},
+				},
+			},
+		},
+	}}
+	lbStatus, err := LoadBalancerStatus(service)
+	assert.NoError(t, err)
+	noIngress, err := LoadBalancerStatus(&unstructured.Unstructured{Object: map[string]interface{}{}})
+	assert.NoError(t, err)
+	assert.Nil(t, noIngress)
+
+	loadBalancers := ClusterLoadBalancerMap{
+		"cluster1": lbStatus,
+		"cluster2": {Ingress: []apiv1.LoadBalancerIngress{{IP: "10.0.0.1"}, {IP: "10.0.0.2"}}},
+	}
+	err = SetPropagationStatus(fedObject, AggregateSuccess, statusMap, ClusterDetails{LoadBalancers: loadBalancers})
+	assert.NoError(t, err)
+
+	clusterLoadBalancers := clusterStatusField(t, fedObject, "loadBalancer")
+	assert.Equal(t, map[string]interface{}{"ingress": []interface{}{
+		map[string]interface{}{"ip": "10.0.0.2"},
+		map[string]interface{}{"hostname": "lb.example.com"},
+	}}, clusterLoadBalancers["cluster1"])
+	assert.Nil(t, clusterLoadBalancers["cluster3"])
+
+	// The ingress points of all clusters are merged without duplicates.
+	merged, _, err := unstructured.NestedSlice(fedObject.Object, "status", "loadBalancer", "ingress")
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"hostname": "lb.example.com"},
+		map[string]interface{}{"ip": "10.0.0.1"},
+		map[string]interface{}{"ip": "10.0.0.2"},
+	}, merged)
+}
+
+func clusterStatusField(t *testing.T, fedObject *unstructured.Unstructured, field string) map[string]interface{} {
+	clusters, _, err := unstructured.NestedSlice(fedObject.Object, "status", "clusters")
+	assert.NoError(t, err)
+	values := make(map[string]interface{})
+	for _, cluster := range clusters {
+		fields := cluster.(map[string]interface{})
+		values[fields["name"].(string)] = fields[field]
+	}
+	return values
+}
+
+func propagationStatus(t *testing.T, fedObject *unstructured.Unstructured) *GenericPropagationStatus {
+	status := &GenericFederatedStatus{}
+	err := util.UnstructuredToInterface(fedObject, status)
+	assert.NoError(t, err)
+	return status.Status
+}
+
+func TestSetServiceImports(t *testing.T) {
+	fedObject := &unstructured.Unstructured{}
+	fedObject.SetAPIVersion("types.kubefed.k8s.io/v1beta1")
+	fedObject.SetKind("FederatedService")
+	statusMap := PropagationStatusMap{"cluster1": ClusterPropagationOK}
+	err := SetPropagationStatus(fedObject, AggregateSuccess, statusMap, ClusterDetails{})
+	assert.NoError(t, err)
+
+	imports := []ClusterServiceImport{
+		{Cluster: "cluster1", Type: "ClusterSetIP", IPs: []string{"243.0.0.1"}, ExportingClusters: []string{"cluster1", "cluster2"}},
+	}
+	changed, err := SetServiceImports(fedObject, imports)
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	changed, err = SetServiceImports(fedObject, imports)
+	assert.NoError(t, err)
+	assert.False(t, changed)
+
+	// The imports are retained by the sync controller.
+	err = SetPropagationStatus(fedObject, AggregateSuccess, statusMap, ClusterDetails{})
+	assert.NoError(t, err)
+	serviceImports, found, err := unstructured.NestedSlice(fedObject.Object, "status", "serviceImports")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, []interface{}{map[string]interface{}{
+		"cluster":           "cluster1",
+		"type":              "ClusterSetIP",
+		"ips":               []interface{}{"243.0.0.1"},
+		"exportingClusters": []interface{}{"cluster1", "cluster2"},
+	}}, serviceImports)
+
+	changed, err = SetServiceImports(fedObject, nil)
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	_, found, err = unstructured.NestedSlice(fedObject.Object, "status", "serviceImports")
+	assert.NoError(t, err)
+	assert.False(t, found)
+}
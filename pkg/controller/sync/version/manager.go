@@ -47,6 +47,9 @@ type VersionedResource interface {
 	Object() *unstructured.Unstructured
 	TemplateVersion() (string, error)
 	OverrideVersion() (string, error)
+	// NameForCluster returns the name of the target resource in the
+	// given cluster.
+	NameForCluster(clusterName string) string
 }
 
 type VersionManager struct {
@@ -65,6 +68,10 @@ type VersionManager struct {
 
 	versions map[string]pkgruntime.Object
 
+	// Keys of versions whose latest state has not been successfully
+	// written to the API.
+	pendingWrites sets.String
+
 	client generic.Client
 }
 
@@ -75,6 +82,7 @@ func NewVersionManager(client generic.Client, namespaced bool, federatedKind, ta
 		namespace:     namespace,
 		adapter:       NewVersionAdapter(namespaced),
 		versions:      make(map[string]pkgruntime.Object),
+		pendingWrites: sets.NewString(),
 		client:        client,
 	}
 
@@ -135,8 +143,32 @@ func (m *VersionManager) Get(resource VersionedResource) (map[string]string, err
 	return versionMap, nil
 }
 
+// GetRenamedClusters retrieves a mapping of cluster names to the names
+// recorded for target resources that were renamed in those clusters.
+// Unlike versions, the recorded names remain valid when the template
+// or overrides change so that a resource whose rename was changed or
+// removed can be found in the cluster.
+func (m *VersionManager) GetRenamedClusters(resource VersionedResource) map[string]string {
+	renamedClusters := make(map[string]string)
+
+	qualifiedName := m.versionQualifiedName(resource.FederatedName())
+	m.RLock()
+	defer m.RUnlock()
+	obj, ok := m.versions[qualifiedName.String()]
+	if !ok {
+		return renamedClusters
+	}
+	for _, clusterVersion := range m.adapter.GetStatus(obj).ClusterVersions {
+		if len(clusterVersion.Name) > 0 {
+			renamedClusters[clusterVersion.ClusterName] = clusterVersion.Name
+		}
+	}
+	return renamedClusters
+}
+
 // Update ensures that the propagated version for the given versioned
-// resource is recorded.
+// resource is recorded.  The version is only written to the API if it
+// has changed since it was last written.
 func (m *VersionManager) Update(resource VersionedResource,
 	selectedClusters []string, versionMap map[string]string) error {
 
@@ -167,6 +199,14 @@ func (m *VersionManager) Update(resource VersionedResource,
 	} else {
 		clusterVersions = VersionMapToClusterVersions(versionMap)
 	}
+	for i := range clusterVersions {
+		// Record the names of renamed resources so that they can be
+		// found if the rename is subsequently changed or removed.
+		name := resource.NameForCluster(clusterVersions[i].ClusterName)
+		if name != resource.FederatedName().Name {
+			clusterVersions[i].Name = name
+		}
+	}
 
 	status := &fedv1a1.PropagatedVersionStatus{
 		TemplateVersion: templateVersion,
@@ -175,7 +215,11 @@ func (m *VersionManager) Update(resource VersionedResource,
 	}
 
 	if oldStatus != nil && util.PropagatedVersionStatusEquivalent(oldStatus, status) {
-		klog.V(4).Infof("No update necessary for %s %q", m.adapter.TypeName(), qualifiedName)
+		if !m.pendingWrites.Has(key) {
+			m.Unlock()
+			klog.V(4).Infof("No update necessary for %s %q", m.adapter.TypeName(), qualifiedName)
+			return nil
+		}
 	} else if obj == nil {
 		ownerReference := ownerReferenceForUnstructured(resource.Object())
 		obj = m.adapter.NewVersion(qualifiedName, ownerReference, status)
@@ -183,10 +227,18 @@ func (m *VersionManager) Update(resource VersionedResource,
 	} else {
 		m.adapter.SetStatus(obj, status)
 	}
+	// Ensure the write is retried by the next update if it fails.
+	m.pendingWrites.Insert(key)
 
 	m.Unlock()
 
-	return m.writeVersion(obj, qualifiedName)
+	err = m.writeVersion(obj, qualifiedName)
+	if err == nil {
+		m.Lock()
+		m.pendingWrites.Delete(key)
+		m.Unlock()
+	}
+	return err
 }
 
 // Delete removes the named propagated version from the manager.
@@ -195,8 +247,95 @@ func (m *VersionManager) Update(resource VersionedResource,
 // collector when the resource is removed.
 func (m *VersionManager) Delete(qualifiedName util.QualifiedName) {
 	versionQualifiedName := m.versionQualifiedName(qualifiedName)
+	key := versionQualifiedName.String()
+	m.Lock()
+	delete(m.versions, key)
+	m.pendingWrites.Delete(key)
+	m.Unlock()
+}
+
+// CollectGarbage deletes the propagated versions that are not owned
+// by the federated resource they are named for, either because the
+// resource no longer exists or because it has been recreated.  The
+// lookup function returns nil if the named federated resource does
+// not exist.  The federated resources whose versions reference
+// clusters other than the given registered clusters are returned so
+// that they can be reconciled to prune the stale entries.
+func (m *VersionManager) CollectGarbage(lookup func(util.QualifiedName) (*unstructured.Unstructured, error),
+	clusterNames sets.String) []*unstructured.Unstructured {
+
+	adapterType := m.adapter.TypeName()
+	versionList := m.adapter.NewListObject()
+	err := m.client.List(context.TODO(), versionList, m.namespace)
+	if err != nil {
+		runtime.HandleError(errors.Wrapf(err, "Failed to list propagated versions for %q", m.federatedKind))
+		return nil
+	}
+	items, err := meta.ExtractList(versionList)
+	if err != nil {
+		runtime.HandleError(errors.Wrapf(err, "Failed to understand list result for %q", adapterType))
+		return nil
+	}
+
+	typePrefix := common.PropagatedVersionPrefix(m.targetKind)
+	var staleResources []*unstructured.Unstructured
+	for _, obj := range items {
+		qualifiedName := util.NewQualifiedName(obj)
+		if !strings.HasPrefix(qualifiedName.Name, typePrefix) {
+			continue
+		}
+		metaAccessor, err := meta.Accessor(obj)
+		if err != nil || metaAccessor.GetDeletionTimestamp() != nil {
+			continue
+		}
+
+		federatedName := util.QualifiedName{
+			Namespace: qualifiedName.Namespace,
+			Name:      strings.TrimPrefix(qualifiedName.Name, typePrefix),
+		}
+		resource, err := lookup(federatedName)
+		if err != nil {
+			runtime.HandleError(errors.Wrapf(err, "Failed to retrieve %s %q", m.federatedKind, federatedName))
+			continue
+		}
+		if resource == nil || !isOwnedBy(metaAccessor, resource) {
+			m.deleteVersion(obj, qualifiedName, resource)
+			continue
+		}
+
+		for _, clusterVersion := range m.adapter.GetStatus(obj).ClusterVersions {
+			if !clusterNames.Has(clusterVersion.ClusterName) {
+				staleResources = append(staleResources, resource)
+				break
+			}
+		}
+	}
+	return staleResources
+}
+
+// deleteVersion removes an orphaned propagated version from the API
+// and from the manager.  The resource is the federated resource the
+// version is named for, if it exists.
+func (m *VersionManager) deleteVersion(obj pkgruntime.Object, qualifiedName util.QualifiedName, resource *unstructured.Unstructured) {
+	adapterType := m.adapter.TypeName()
+	klog.V(2).Infof("Deleting orphaned %s %q", adapterType, qualifiedName)
+	err := m.client.Delete(context.TODO(), obj, qualifiedName.Namespace, qualifiedName.Name)
+	if err != nil && !apierrors.IsNotFound(err) {
+		runtime.HandleError(errors.Wrapf(err, "Failed to delete orphaned %s %q", adapterType, qualifiedName))
+		return
+	}
+
+	// The version of a recreated resource needs to be created anew
+	// to reference the new owner.
+	key := qualifiedName.String()
 	m.Lock()
-	delete(m.versions, versionQualifiedName.String())
+	if cached, ok := m.versions[key]; ok {
+		cachedAccessor, err := meta.Accessor(cached)
+		if err != nil || resource == nil || !isOwnedBy(cachedAccessor, resource) {
+			delete(m.versions, key)
+			m.pendingWrites.Delete(key)
+		}
+	}
 	m.Unlock()
 }
 
@@ -412,6 +551,17 @@ func setResourceVersion(obj pkgruntime.Object, resourceVersion string) error {
 	return nil
 }
 
+// isOwnedBy indicates whether the given object has an owner reference
+// to the given resource.
+func isOwnedBy(obj metav1.Object, resource *unstructured.Unstructured) bool {
+	for _, ownerReference := range obj.GetOwnerReferences() {
+		if ownerReference.UID == resource.GetUID() {
+			return true
+		}
+	}
+	return false
+}
+
 func ownerReferenceForUnstructured(obj *unstructured.Unstructured) metav1.OwnerReference {
 	gvk := obj.GetObjectKind().GroupVersionKind()
 	return metav1.OwnerReference{
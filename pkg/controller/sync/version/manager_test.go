@@ -0,0 +1,190 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package version
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+// writeCountingClient is a generic client that accepts all writes and
+// counts them.
+type writeCountingClient struct {
+	writes          int
+	resourceVersion int
+}
+
+func (c *writeCountingClient) write(obj pkgruntime.Object) error {
+	c.writes++
+	c.resourceVersion++
+	return setResourceVersion(obj, strconv.Itoa(c.resourceVersion))
+}
+
+func (c *writeCountingClient) Create(ctx context.Context, obj pkgruntime.Object) error {
+	return c.write(obj)
+}
+
+func (c *writeCountingClient) Get(ctx context.Context, obj pkgruntime.Object, namespace, name string) error {
+	return errors.New("not implemented")
+}
+
+func (c *writeCountingClient) Update(ctx context.Context, obj pkgruntime.Object) error {
+	return c.write(obj)
+}
+
+func (c *writeCountingClient) Delete(ctx context.Context, obj pkgruntime.Object, namespace, name string) error {
+	c.writes++
+	return nil
+}
+
+func (c *writeCountingClient) List(ctx context.Context, obj pkgruntime.Object, namespace string) error {
+	return nil
+}
+
+func (c *writeCountingClient) UpdateStatus(ctx context.Context, obj pkgruntime.Object) error {
+	return c.write(obj)
+}
+
+type testResource struct {
+	obj             *unstructured.Unstructured
+	templateVersion string
+}
+
+func newTestResource(name string) *testResource {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("types.kubefed.k8s.io/v1beta1")
+	obj.SetKind("FederatedConfigMap")
+	obj.SetNamespace("ns")
+	obj.SetName(name)
+	return &testResource{obj: obj, templateVersion: "1"}
+}
+
+func (r *testResource) FederatedName() util.QualifiedName {
+	return util.NewQualifiedName(r.obj)
+}
+
+func (r *testResource) Object() *unstructured.Unstructured {
+	return r.obj
+}
+
+func (r *testResource) TemplateVersion() (string, error) {
+	return r.templateVersion, nil
+}
+
+func (r *testResource) OverrideVersion() (string, error) {
+	return "", nil
+}
+
+func (r *testResource) NameForCluster(clusterName string) string {
+	return r.obj.GetName()
+}
+
+func newTestVersionManager(client *writeCountingClient) *VersionManager {
+	m := NewVersionManager(client, true, "FederatedConfigMap", "ConfigMap", "")
+	m.hasSynced = true
+	return m
+}
+
+func TestUpdateOnlyWritesChangedVersions(t *testing.T) {
+	client := &writeCountingClient{}
+	m := newTestVersionManager(client)
+	resource := newTestResource("foo")
+	clusters := []string{"cluster1", "cluster2"}
+	versionMap := func() map[string]string {
+		return map[string]string{"cluster1": "10", "cluster2": "20"}
+	}
+
+	// A new version is created and its status written.
+	assert.NoError(t, m.Update(resource, clusters, versionMap()))
+	assert.Equal(t, 2, client.writes)
+
+	// Reconciling an unchanged resource does not write the version.
+	for i := 0; i < 10; i++ {
+		assert.NoError(t, m.Update(resource, clusters, versionMap()))
+	}
+	assert.Equal(t, 2, client.writes)
+
+	// A changed version is written once.
+	resource.templateVersion = "2"
+	assert.NoError(t, m.Update(resource, clusters, versionMap()))
+	assert.NoError(t, m.Update(resource, clusters, versionMap()))
+	assert.Equal(t, 3, client.writes)
+}
+
+func TestUpdateRetriesFailedWrites(t *testing.T) {
+	client := &writeCountingClient{}
+	m := newTestVersionManager(client)
+	resource := newTestResource("foo")
+	clusters := []string{"cluster1"}
+	versionMap := func() map[string]string {
+		return map[string]string{"cluster1": "10"}
+	}
+
+	assert.NoError(t, m.Update(resource, clusters, versionMap()))
+	writes := client.writes
+
+	// Simulate the failure of the last write. The unchanged version
+	// should be written by the next update.
+	key := m.versionQualifiedName(resource.FederatedName()).String()
+	m.pendingWrites.Insert(key)
+	assert.NoError(t, m.Update(resource, clusters, versionMap()))
+	assert.Equal(t, writes+1, client.writes)
+	assert.False(t, m.pendingWrites.Has(key))
+
+	assert.NoError(t, m.Update(resource, clusters, versionMap()))
+	assert.Equal(t, writes+1, client.writes)
+}
+
+// BenchmarkUpdateUnchanged measures updating the versions of
+// resources that are reconciled without change, which is the common
+// case for periodic and cluster-triggered reconciliation.
+func BenchmarkUpdateUnchanged(b *testing.B) {
+	const resourceCount = 100
+	client := &writeCountingClient{}
+	m := newTestVersionManager(client)
+	clusters := []string{"cluster1", "cluster2", "cluster3"}
+	versionMap := func() map[string]string {
+		return map[string]string{"cluster1": "1", "cluster2": "2", "cluster3": "3"}
+	}
+	resources := make([]*testResource, resourceCount)
+	for i := range resources {
+		resources[i] = newTestResource(fmt.Sprintf("resource-%d", i))
+		if err := m.Update(resources[i], clusters, versionMap()); err != nil {
+			b.Fatal(err)
+		}
+	}
+	initialWrites := client.writes
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := m.Update(resources[i%resourceCount], clusters, versionMap()); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.StopTimer()
+	b.Logf("%d updates resulted in %d API writes", b.N, client.writes-initialWrites)
+}
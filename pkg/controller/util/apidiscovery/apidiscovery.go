@@ -0,0 +1,125 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apidiscovery
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Cache holds the API resources most recently discovered in each
+// member cluster by the cluster controller, so that the versions of a
+// target type served by a cluster can be determined without a
+// discovery request per controller.  A nil *Cache is valid and holds
+// no clusters.
+type Cache struct {
+	lock     sync.RWMutex
+	clusters map[string]*ClusterResources
+}
+
+// ClusterResources are the API resources discovered in a cluster.
+// They are replaced rather than modified when the cluster is
+// discovered again.
+type ClusterResources struct {
+	// Refreshed is the time at which the resources were discovered.
+	Refreshed time.Time
+
+	resourceLists map[string]*metav1.APIResourceList
+	failed        map[string]error
+}
+
+// New returns an empty cache.
+func New() *Cache {
+	return &Cache{clusters: make(map[string]*ClusterResources)}
+}
+
+// Update replaces the resources of the cluster with the given
+// resource lists.  Group versions whose discovery failed are given
+// with their error so that they are not mistaken for group versions
+// the cluster does not serve.
+func (c *Cache) Update(clusterName string, resourceLists []*metav1.APIResourceList, failed map[schema.GroupVersion]error) {
+	if c == nil {
+		return
+	}
+	resources := &ClusterResources{
+		Refreshed:     time.Now(),
+		resourceLists: make(map[string]*metav1.APIResourceList, len(resourceLists)),
+		failed:        make(map[string]error, len(failed)),
+	}
+	for _, resourceList := range resourceLists {
+		resources.resourceLists[resourceList.GroupVersion] = resourceList
+	}
+	for groupVersion, err := range failed {
+		resources.failed[groupVersion.String()] = err
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.clusters[clusterName] = resources
+}
+
+// Remove removes the resources of the cluster.
+func (c *Cache) Remove(clusterName string) {
+	if c == nil {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	delete(c.clusters, clusterName)
+}
+
+// Cluster returns the resources of the cluster, if it has been
+// discovered.
+func (c *Cache) Cluster(clusterName string) (*ClusterResources, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	resources, ok := c.clusters[clusterName]
+	return resources, ok
+}
+
+// ServerResourcesForGroupVersion returns the resources of the group
+// version in the manner of a discovery client: a NotFound error is
+// returned if the cluster does not serve the group version.
+func (r *ClusterResources) ServerResourcesForGroupVersion(groupVersion string) (*metav1.APIResourceList, error) {
+	if err, ok := r.failed[groupVersion]; ok {
+		return nil, err
+	}
+	resourceList, ok := r.resourceLists[groupVersion]
+	if !ok {
+		gv, _ := schema.ParseGroupVersion(groupVersion)
+		return nil, apierrors.NewNotFound(schema.GroupResource{Group: gv.Group}, gv.Version)
+	}
+	return resourceList, nil
+}
+
+// GroupVersions returns the names of the group versions served by the
+// cluster in sorted order.
+func (r *ClusterResources) GroupVersions() []string {
+	groupVersions := make([]string, 0, len(r.resourceLists))
+	for groupVersion := range r.resourceLists {
+		groupVersions = append(groupVersions, groupVersion)
+	}
+	sort.Strings(groupVersions)
+	return groupVersions
+}
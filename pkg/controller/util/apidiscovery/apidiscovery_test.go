@@ -0,0 +1,72 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apidiscovery
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestCache(t *testing.T) {
+	cache := New()
+	resourceLists := []*metav1.APIResourceList{
+		{GroupVersion: "v1", APIResources: []metav1.APIResource{{Name: "configmaps"}}},
+		{GroupVersion: "networking.k8s.io/v1", APIResources: []metav1.APIResource{{Name: "ingresses"}}},
+	}
+	failed := map[schema.GroupVersion]error{
+		{Group: "metrics.k8s.io", Version: "v1beta1"}: errors.New("service unavailable"),
+	}
+	cache.Update("cluster1", resourceLists, failed)
+
+	_, ok := cache.Cluster("cluster2")
+	assert.False(t, ok)
+
+	resources, ok := cache.Cluster("cluster1")
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.False(t, resources.Refreshed.IsZero())
+	assert.Equal(t, []string{"networking.k8s.io/v1", "v1"}, resources.GroupVersions())
+
+	resourceList, err := resources.ServerResourcesForGroupVersion("networking.k8s.io/v1")
+	assert.NoError(t, err)
+	assert.Equal(t, resourceLists[1], resourceList)
+
+	_, err = resources.ServerResourcesForGroupVersion("networking.k8s.io/v1beta1")
+	assert.True(t, apierrors.IsNotFound(err))
+
+	_, err = resources.ServerResourcesForGroupVersion("metrics.k8s.io/v1beta1")
+	assert.EqualError(t, err, "service unavailable")
+
+	cache.Remove("cluster1")
+	_, ok = cache.Cluster("cluster1")
+	assert.False(t, ok)
+}
+
+func TestNilCache(t *testing.T) {
+	var cache *Cache
+	cache.Update("cluster1", nil, nil)
+	_, ok := cache.Cluster("cluster1")
+	assert.False(t, ok)
+	cache.Remove("cluster1")
+}
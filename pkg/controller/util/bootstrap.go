@@ -0,0 +1,37 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+const (
+	// BootstrapRegistrantGroup is the group of the bootstrap tokens
+	// that allow member clusters to register themselves.  The API
+	// server only accepts extra groups of bootstrap tokens with the
+	// system:bootstrappers: prefix.
+	BootstrapRegistrantGroup = "system:bootstrappers:kubefed:registrants"
+
+	// BootstrapUserPrefix prefixes the ID of a bootstrap token in the
+	// name of the user it authenticates as.
+	BootstrapUserPrefix = "system:bootstrap:"
+
+	// RegisteredClusterAnnotation and RegisteredByAnnotation record
+	// the cluster and the bootstrap token user of the registration
+	// that created a cluster secret.  A KubeFedCluster created with a
+	// bootstrap token is only admitted if it references a secret of
+	// the same registration.
+	RegisteredClusterAnnotation = "kubefed.k8s.io/registered-cluster"
+	RegisteredByAnnotation      = "kubefed.k8s.io/registered-by"
+)
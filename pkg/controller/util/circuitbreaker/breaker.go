@@ -0,0 +1,226 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package circuitbreaker
+
+import (
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+const (
+	// DefaultInitialBackoff is the time for which dispatch is backed
+	// off once the failure threshold is reached if none is configured.
+	DefaultInitialBackoff = 10 * time.Second
+	// DefaultMaxBackoff is the maximum time for which dispatch is
+	// backed off if none is configured.
+	DefaultMaxBackoff = 5 * time.Minute
+)
+
+type clusterCircuit struct {
+	// The number of consecutive failed requests.
+	failures int
+	// Whether dispatch to the cluster is backed off.
+	open bool
+	// The time at which the circuit was opened.
+	openedAt time.Time
+	// The current backoff of the open circuit.
+	backoff time.Duration
+	// The time after which a probe may be attempted.
+	probeAt time.Time
+	// The most recent failure.
+	lastErr error
+}
+
+// State describes an open circuit of a cluster.
+type State struct {
+	// The time at which dispatch to the cluster was backed off.
+	Since time.Time
+	// The time after which the next probe may be attempted.
+	ProbeAt time.Time
+	// The most recent failure of a request to the cluster.
+	LastError error
+}
+
+// Breaker backs off the dispatch of resources to member clusters
+// whose API persistently fails requests, as configured in a
+// KubeFedConfig.  A single breaker is shared by the sync controllers
+// of all federated types so that failures are counted across types.
+// Once the failure threshold of a cluster is reached its circuit is
+// opened and dispatch to the cluster is denied, except for a single
+// probe each time the backoff elapses.  A successful request closes
+// the circuit, and a failed probe doubles the backoff.  A nil
+// *Breaker is valid and allows all dispatch.
+type Breaker struct {
+	failureThreshold int
+	initialBackoff   time.Duration
+	maxBackoff       time.Duration
+
+	sync.Mutex
+	clusters map[string]*clusterCircuit
+
+	now func() time.Time
+}
+
+// New returns a breaker for the given configuration, or nil if
+// dispatch is not backed off.
+func New(config *fedv1b1.CircuitBreakerConfig) *Breaker {
+	if config == nil || config.FailureThreshold <= 0 {
+		return nil
+	}
+	initialBackoff := DefaultInitialBackoff
+	if config.InitialBackoff != nil && config.InitialBackoff.Duration > 0 {
+		initialBackoff = config.InitialBackoff.Duration
+	}
+	maxBackoff := DefaultMaxBackoff
+	if config.MaxBackoff != nil && config.MaxBackoff.Duration > 0 {
+		maxBackoff = config.MaxBackoff.Duration
+	}
+	if maxBackoff < initialBackoff {
+		maxBackoff = initialBackoff
+	}
+	return &Breaker{
+		failureThreshold: int(config.FailureThreshold),
+		initialBackoff:   initialBackoff,
+		maxBackoff:       maxBackoff,
+		clusters:         make(map[string]*clusterCircuit),
+		now:              time.Now,
+	}
+}
+
+// Allow returns true if a request may be dispatched to the given
+// cluster.  While the circuit of the cluster is open, true is only
+// returned for a single probe each time the backoff elapses.
+func (b *Breaker) Allow(clusterName string) bool {
+	if b == nil {
+		return true
+	}
+
+	b.Lock()
+	defer b.Unlock()
+	circuit, ok := b.clusters[clusterName]
+	if !ok || !circuit.open {
+		return true
+	}
+	now := b.now()
+	if now.Before(circuit.probeAt) {
+		return false
+	}
+	// Deny further probes until the backoff elapses again, in case
+	// the result of this probe is never recorded.
+	circuit.probeAt = now.Add(circuit.backoff)
+	return true
+}
+
+// RecordResult records the result of a request to the given cluster.
+// Only errors indicating that the API of the cluster is unavailable
+// or overloaded are counted as failures.
+func (b *Breaker) RecordResult(clusterName string, err error) {
+	if b == nil {
+		return
+	}
+	if IsClusterFailure(err) {
+		b.recordFailure(clusterName, err)
+	} else {
+		b.recordSuccess(clusterName)
+	}
+}
+
+func (b *Breaker) recordSuccess(clusterName string) {
+	b.Lock()
+	defer b.Unlock()
+	circuit, ok := b.clusters[clusterName]
+	if !ok {
+		return
+	}
+	if circuit.open {
+		klog.Infof("Resuming dispatch to cluster %q since a request to the cluster succeeded", clusterName)
+	}
+	delete(b.clusters, clusterName)
+}
+
+func (b *Breaker) recordFailure(clusterName string, err error) {
+	b.Lock()
+	defer b.Unlock()
+	circuit, ok := b.clusters[clusterName]
+	if !ok {
+		circuit = &clusterCircuit{}
+		b.clusters[clusterName] = circuit
+	}
+	circuit.lastErr = err
+	now := b.now()
+	if circuit.open {
+		// A failed probe
+		circuit.backoff *= 2
+		if circuit.backoff > b.maxBackoff {
+			circuit.backoff = b.maxBackoff
+		}
+		circuit.probeAt = now.Add(circuit.backoff)
+		return
+	}
+	circuit.failures++
+	if circuit.failures < b.failureThreshold {
+		return
+	}
+	klog.Warningf("Backing off dispatch to cluster %q for %v since %d consecutive requests to the cluster failed: %v",
+		clusterName, b.initialBackoff, circuit.failures, err)
+	circuit.open = true
+	circuit.openedAt = now
+	circuit.backoff = b.initialBackoff
+	circuit.probeAt = now.Add(circuit.backoff)
+}
+
+// OpenState returns the state of the circuit of the given cluster if
+// it is open, or nil if dispatch to the cluster is not backed off.
+func (b *Breaker) OpenState(clusterName string) *State {
+	if b == nil {
+		return nil
+	}
+
+	b.Lock()
+	defer b.Unlock()
+	circuit, ok := b.clusters[clusterName]
+	if !ok || !circuit.open {
+		return nil
+	}
+	return &State{
+		Since:     circuit.openedAt,
+		ProbeAt:   circuit.probeAt,
+		LastError: circuit.lastErr,
+	}
+}
+
+// IsClusterFailure returns true if the given error of a request to
+// the API of a cluster indicates that the API is unavailable or
+// overloaded rather than that the request itself was rejected.
+func IsClusterFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	if status, ok := err.(apierrors.APIStatus); ok {
+		code := status.Status().Code
+		return code >= 500 || code == 429 || apierrors.IsTimeout(err) || apierrors.IsServerTimeout(err)
+	}
+	// An error other than a status returned by the API (e.g. a
+	// connection error or a client timeout) indicates that the API
+	// could not be reached.
+	return true
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package circuitbreaker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+func TestNewWithoutThreshold(t *testing.T) {
+	assert.Nil(t, New(nil))
+	assert.Nil(t, New(&fedv1b1.CircuitBreakerConfig{}))
+
+	// A nil breaker must be safe to use.
+	var b *Breaker
+	b.RecordResult("cluster1", errors.New("connection refused"))
+	assert.True(t, b.Allow("cluster1"))
+	assert.Nil(t, b.OpenState("cluster1"))
+}
+
+func TestBreaker(t *testing.T) {
+	now := time.Now()
+	b := New(&fedv1b1.CircuitBreakerConfig{
+		FailureThreshold: 2,
+		InitialBackoff:   &metav1.Duration{Duration: time.Minute},
+		MaxBackoff:       &metav1.Duration{Duration: 3 * time.Minute},
+	})
+	b.now = func() time.Time { return now }
+	failure := apierrors.NewServiceUnavailable("unavailable")
+
+	b.RecordResult("cluster1", failure)
+	assert.True(t, b.Allow("cluster1"), "Dispatch should be allowed below the threshold")
+	b.RecordResult("cluster1", nil)
+	b.RecordResult("cluster1", failure)
+	assert.True(t, b.Allow("cluster1"), "A success should reset the count of failures")
+
+	b.RecordResult("cluster1", failure)
+	assert.False(t, b.Allow("cluster1"), "Dispatch should be denied once the threshold is reached")
+	assert.True(t, b.Allow("cluster2"), "Dispatch should be backed off per cluster")
+	state := b.OpenState("cluster1")
+	if assert.NotNil(t, state) {
+		assert.Equal(t, now, state.Since)
+		assert.Equal(t, now.Add(time.Minute), state.ProbeAt)
+		assert.Equal(t, failure, state.LastError)
+	}
+
+	now = now.Add(time.Minute)
+	assert.True(t, b.Allow("cluster1"), "A probe should be allowed once the backoff elapses")
+	assert.False(t, b.Allow("cluster1"), "Only a single probe should be allowed")
+
+	b.RecordResult("cluster1", failure)
+	now = now.Add(time.Minute)
+	assert.False(t, b.Allow("cluster1"), "A failed probe should double the backoff")
+	now = now.Add(time.Minute)
+	assert.True(t, b.Allow("cluster1"))
+
+	b.RecordResult("cluster1", failure)
+	b.RecordResult("cluster1", failure)
+	assert.Equal(t, now.Add(3*time.Minute), b.OpenState("cluster1").ProbeAt, "The backoff should not exceed the maximum")
+
+	now = now.Add(3 * time.Minute)
+	assert.True(t, b.Allow("cluster1"))
+	b.RecordResult("cluster1", apierrors.NewConflict(schema.GroupResource{}, "name", errors.New("conflict")))
+	assert.Nil(t, b.OpenState("cluster1"), "A request rejected by the cluster should close the circuit")
+	assert.True(t, b.Allow("cluster1"))
+}
+
+func TestIsClusterFailure(t *testing.T) {
+	gr := schema.GroupResource{Resource: "configmaps"}
+	assert.False(t, IsClusterFailure(nil))
+	assert.False(t, IsClusterFailure(apierrors.NewNotFound(gr, "name")))
+	assert.False(t, IsClusterFailure(apierrors.NewForbidden(gr, "name", errors.New("forbidden"))))
+	assert.True(t, IsClusterFailure(apierrors.NewInternalError(errors.New("etcd unavailable"))))
+	assert.True(t, IsClusterFailure(apierrors.NewServerTimeout(gr, "create", 1)))
+	assert.True(t, IsClusterFailure(apierrors.NewTooManyRequests("throttled", 1)))
+	assert.True(t, IsClusterFailure(errors.New("dial tcp: connection refused")))
+}
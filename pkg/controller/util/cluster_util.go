@@ -35,6 +35,7 @@ const (
 	DefaultKubeFedSystemNamespace  = "kube-federation-system"
 	DefaultClusterAvailableDelay   = 20 * time.Second
 	DefaultClusterUnavailableDelay = 60 * time.Second
+	DefaultMemberReadCacheTTL      = 5 * time.Second
 
 	KubeAPIQPS   = 20.0
 	KubeAPIBurst = 30
@@ -55,11 +56,23 @@ const (
 
 // BuildClusterConfig returns a restclient.Config that can be used to configure
 // a client for the given KubeFedCluster or an error. The client is used to
-// access kubernetes secrets in the kubefed namespace.
-func BuildClusterConfig(fedCluster *fedv1b1.KubeFedCluster, client generic.Client, fedNamespace string) (*restclient.Config, error) {
+// access kubernetes secrets in the kubefed namespace.  The config
+// targets the endpoint last found to be reachable by the cluster
+// health check.  A local cluster is accessed with a copy of the given
+// host config.
+func BuildClusterConfig(fedCluster *fedv1b1.KubeFedCluster, hostConfig *restclient.Config, client generic.Client, fedNamespace string) (*restclient.Config, error) {
+	return BuildClusterConfigForEndpoint(fedCluster, hostConfig, client, fedNamespace, ActiveAPIEndpoint(fedCluster))
+}
+
+// BuildClusterConfigForEndpoint returns a restclient.Config for the
+// given KubeFedCluster that targets the given api endpoint.
+func BuildClusterConfigForEndpoint(fedCluster *fedv1b1.KubeFedCluster, hostConfig *restclient.Config, client generic.Client, fedNamespace, apiEndpoint string) (*restclient.Config, error) {
 	clusterName := fedCluster.Name
 
-	apiEndpoint := fedCluster.Spec.APIEndpoint
+	if fedCluster.Spec.Local {
+		return buildLocalClusterConfig(clusterName, hostConfig)
+	}
+
 	// TODO(marun) Remove when validation ensures a non-empty value.
 	if apiEndpoint == "" {
 		return nil, errors.Errorf("The api endpoint of cluster %s is empty", clusterName)
@@ -70,7 +83,7 @@ func BuildClusterConfig(fedCluster *fedv1b1.KubeFedCluster, client generic.Clien
 		return nil, errors.Errorf("Cluster %s does not have a secret name", clusterName)
 	}
 	secret := &apiv1.Secret{}
-	err := client.Get(context.TODO(), secret, fedNamespace, secretName)
+	err := client.Get(context.TODO(), secret, ClusterSecretNamespace(fedCluster, fedNamespace), secretName)
 	if err != nil {
 		return nil, err
 	}
@@ -88,10 +101,83 @@ func BuildClusterConfig(fedCluster *fedv1b1.KubeFedCluster, client generic.Clien
 	clusterConfig.BearerToken = string(token)
 	clusterConfig.QPS = KubeAPIQPS
 	clusterConfig.Burst = KubeAPIBurst
+	observeClusterRequests(clusterName, clusterConfig)
 
 	return clusterConfig, nil
 }
 
+// buildLocalClusterConfig returns a restclient.Config for a local
+// cluster, which is accessed with the config of the host cluster
+// instead of via its api endpoint and secret.
+func buildLocalClusterConfig(clusterName string, hostConfig *restclient.Config) (*restclient.Config, error) {
+	if hostConfig == nil {
+		return nil, errors.Errorf("Cluster %s is local but the config of the host cluster is not available", clusterName)
+	}
+	clusterConfig := restclient.CopyConfig(hostConfig)
+	clusterConfig.QPS = KubeAPIQPS
+	clusterConfig.Burst = KubeAPIBurst
+	observeClusterRequests(clusterName, clusterConfig)
+	return clusterConfig, nil
+}
+
+// ClusterSecretNamespace returns the namespace of the secret
+// referenced by the given cluster, which defaults to the kubefed
+// namespace.
+func ClusterSecretNamespace(fedCluster *fedv1b1.KubeFedCluster, fedNamespace string) string {
+	if len(fedCluster.Spec.SecretRef.Namespace) > 0 {
+		return fedCluster.Spec.SecretRef.Namespace
+	}
+	return fedNamespace
+}
+
+// ClusterAPIEndpoints returns the api endpoints of the given cluster
+// in the order they should be tried.  Only the primary endpoint is
+// returned for a local cluster since all of its endpoints are accessed
+// with the same config.
+func ClusterAPIEndpoints(fedCluster *fedv1b1.KubeFedCluster) []string {
+	if fedCluster.Spec.Local {
+		return []string{fedCluster.Spec.APIEndpoint}
+	}
+	return append([]string{fedCluster.Spec.APIEndpoint}, fedCluster.Spec.SecondaryAPIEndpoints...)
+}
+
+// ActiveAPIEndpoint returns the api endpoint recorded in the status of
+// the given cluster if it is still one of the configured endpoints,
+// and otherwise the primary endpoint.
+func ActiveAPIEndpoint(fedCluster *fedv1b1.KubeFedCluster) string {
+	if activeEndpoint := fedCluster.Status.APIEndpoint; len(activeEndpoint) > 0 {
+		for _, endpoint := range ClusterAPIEndpoints(fedCluster) {
+			if endpoint == activeEndpoint {
+				return endpoint
+			}
+		}
+	}
+	return fedCluster.Spec.APIEndpoint
+}
+
+// IsNamespaceAllowed indicates whether resources may be propagated to
+// the given namespace of the cluster.  An empty namespace, as for
+// cluster-scoped resources, is always allowed.
+func IsNamespaceAllowed(fedCluster *fedv1b1.KubeFedCluster, namespace string) bool {
+	if len(namespace) == 0 {
+		return true
+	}
+	for _, denied := range fedCluster.Spec.DeniedNamespaces {
+		if denied == namespace {
+			return false
+		}
+	}
+	if len(fedCluster.Spec.AllowedNamespaces) == 0 {
+		return true
+	}
+	for _, allowed := range fedCluster.Spec.AllowedNamespaces {
+		if allowed == namespace {
+			return true
+		}
+	}
+	return false
+}
+
 // IsPrimaryCluster checks if the caller is working with objects for the
 // primary cluster by checking if the UIDs match for both ObjectMetas passed
 // in.
@@ -0,0 +1,79 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+
+	fedcommon "sigs.k8s.io/kubefed/pkg/apis/core/common"
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/kubefed/pkg/features"
+)
+
+// ClusterPendingApprovalReason is the reason of the Ready condition
+// of a cluster that is not used since it has not been approved.
+const ClusterPendingApprovalReason = "ClusterPendingApproval"
+
+// ClusterApprovalRequired indicates whether clusters must be approved
+// before they are used for placement and propagation.
+func ClusterApprovalRequired() bool {
+	return utilfeature.DefaultFeatureGate.Enabled(features.ClusterJoinApproval)
+}
+
+// ClusterApprovalCondition returns the Approved condition of the
+// cluster, or nil if the cluster has neither been approved nor
+// rejected.
+func ClusterApprovalCondition(clusterStatus *fedv1b1.KubeFedClusterStatus) *fedv1b1.ClusterCondition {
+	for i := range clusterStatus.Conditions {
+		if clusterStatus.Conditions[i].Type == fedcommon.ClusterApproved {
+			return &clusterStatus.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// IsClusterApproved indicates whether the cluster has an Approved
+// condition with a status of True.
+func IsClusterApproved(clusterStatus *fedv1b1.KubeFedClusterStatus) bool {
+	condition := ClusterApprovalCondition(clusterStatus)
+	return condition != nil && condition.Status == apiv1.ConditionTrue
+}
+
+// SetClusterApproval sets the Approved condition of the cluster to
+// approve or reject it.  The transition time is only updated if the
+// approval of the cluster changes.
+func SetClusterApproval(clusterStatus *fedv1b1.KubeFedClusterStatus, approved bool, reason, message string) {
+	status := apiv1.ConditionFalse
+	if approved {
+		status = apiv1.ConditionTrue
+	}
+	now := metav1.Now()
+	condition := ClusterApprovalCondition(clusterStatus)
+	if condition == nil {
+		clusterStatus.Conditions = append(clusterStatus.Conditions, fedv1b1.ClusterCondition{Type: fedcommon.ClusterApproved})
+		condition = &clusterStatus.Conditions[len(clusterStatus.Conditions)-1]
+	}
+	if condition.Status != status {
+		condition.Status = status
+		condition.LastTransitionTime = now
+	}
+	condition.LastProbeTime = now
+	condition.Reason = reason
+	condition.Message = message
+}
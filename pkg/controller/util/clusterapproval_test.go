@@ -0,0 +1,49 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	apiv1 "k8s.io/api/core/v1"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+func TestSetClusterApproval(t *testing.T) {
+	status := &fedv1b1.KubeFedClusterStatus{}
+	assert.False(t, IsClusterApproved(status))
+	assert.Nil(t, ClusterApprovalCondition(status))
+
+	SetClusterApproval(status, true, "ApprovedByAdministrator", "")
+	assert.True(t, IsClusterApproved(status))
+	transitionTime := ClusterApprovalCondition(status).LastTransitionTime
+
+	// Approving an approved cluster does not change its transition time.
+	SetClusterApproval(status, true, "ApprovedByAdministrator", "renewed")
+	condition := ClusterApprovalCondition(status)
+	assert.Len(t, status.Conditions, 1)
+	assert.Equal(t, transitionTime, condition.LastTransitionTime)
+	assert.Equal(t, "renewed", condition.Message)
+
+	SetClusterApproval(status, false, "RejectedByAdministrator", "")
+	assert.False(t, IsClusterApproved(status))
+	assert.Equal(t, apiv1.ConditionFalse, ClusterApprovalCondition(status).Status)
+	assert.Len(t, status.Conditions, 1)
+}
@@ -0,0 +1,218 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+// The fields of the status of a KubeFedCluster that a cluster
+// selector can match.
+const (
+	ClusterRegionField            = "status.region"
+	ClusterZonesField             = "status.zones"
+	ClusterKubernetesVersionField = "status.kubernetesVersion"
+	ClusterProviderField          = "status.provider"
+)
+
+type ClusterFieldOperator string
+
+const (
+	ClusterFieldOpIn           ClusterFieldOperator = "In"
+	ClusterFieldOpNotIn        ClusterFieldOperator = "NotIn"
+	ClusterFieldOpExists       ClusterFieldOperator = "Exists"
+	ClusterFieldOpDoesNotExist ClusterFieldOperator = "DoesNotExist"
+	ClusterFieldOpGt           ClusterFieldOperator = "Gt"
+	ClusterFieldOpLt           ClusterFieldOperator = "Lt"
+)
+
+// ClusterFieldRequirement is a requirement on a field of the status
+// of a KubeFedCluster.  Values of the kubernetes version field are
+// compared as versions at the precision of the requirement value, so
+// that e.g. `Gt 1.26` matches 1.27 and later and `In 1.27` matches
+// any 1.27 patch release.  Gt and Lt are only supported for the
+// kubernetes version field.
+type ClusterFieldRequirement struct {
+	Key      string               `json:"key"`
+	Operator ClusterFieldOperator `json:"operator"`
+	Values   []string             `json:"values,omitempty"`
+}
+
+// clusterFieldValues returns the values of the selectable status
+// fields of the given cluster.  Fields that are not set are omitted.
+func clusterFieldValues(cluster *fedv1b1.KubeFedCluster) map[string][]string {
+	values := make(map[string][]string)
+	status := cluster.Status
+	if len(status.Region) > 0 {
+		values[ClusterRegionField] = []string{status.Region}
+	}
+	if len(status.Zones) > 0 {
+		values[ClusterZonesField] = status.Zones
+	}
+	if len(status.KubernetesVersion) > 0 {
+		values[ClusterKubernetesVersionField] = []string{status.KubernetesVersion}
+	}
+	if len(status.Provider) > 0 {
+		values[ClusterProviderField] = []string{status.Provider}
+	}
+	return values
+}
+
+// ClusterFieldsChanged returns whether the selectable status fields
+// of the two clusters differ.
+func ClusterFieldsChanged(oldCluster, newCluster *fedv1b1.KubeFedCluster) bool {
+	return !reflect.DeepEqual(clusterFieldValues(oldCluster), clusterFieldValues(newCluster))
+}
+
+// MatchesClusterFields returns whether the status of the given cluster
+// satisfies all of the requirements.  An error is returned if a
+// requirement is invalid.
+func MatchesClusterFields(requirements []ClusterFieldRequirement, cluster *fedv1b1.KubeFedCluster) (bool, error) {
+	fieldValues := clusterFieldValues(cluster)
+	for _, requirement := range requirements {
+		matches, err := requirement.matches(fieldValues)
+		if err != nil {
+			return false, err
+		}
+		if !matches {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (r *ClusterFieldRequirement) matches(fieldValues map[string][]string) (bool, error) {
+	switch r.Key {
+	case ClusterRegionField, ClusterZonesField, ClusterKubernetesVersionField, ClusterProviderField:
+	default:
+		return false, errors.Errorf("unsupported cluster field %q", r.Key)
+	}
+	isVersion := r.Key == ClusterKubernetesVersionField
+	values, exists := fieldValues[r.Key]
+
+	switch r.Operator {
+	case ClusterFieldOpExists, ClusterFieldOpDoesNotExist:
+		if len(r.Values) > 0 {
+			return false, errors.Errorf("values must be empty for operator %q of cluster field %q", r.Operator, r.Key)
+		}
+		return exists == (r.Operator == ClusterFieldOpExists), nil
+	case ClusterFieldOpIn, ClusterFieldOpNotIn:
+		if len(r.Values) == 0 {
+			return false, errors.Errorf("values must be provided for operator %q of cluster field %q", r.Operator, r.Key)
+		}
+		found := false
+		for _, value := range values {
+			for _, requiredValue := range r.Values {
+				equal, err := fieldValueEqual(isVersion, value, requiredValue)
+				if err != nil {
+					return false, err
+				}
+				if equal {
+					found = true
+				}
+			}
+		}
+		return found == (r.Operator == ClusterFieldOpIn), nil
+	case ClusterFieldOpGt, ClusterFieldOpLt:
+		if !isVersion {
+			return false, errors.Errorf("operator %q is not supported for cluster field %q", r.Operator, r.Key)
+		}
+		if len(r.Values) != 1 {
+			return false, errors.Errorf("exactly one value must be provided for operator %q of cluster field %q", r.Operator, r.Key)
+		}
+		required, err := ParseVersion(r.Values[0])
+		if err != nil {
+			return false, err
+		}
+		if !exists {
+			return false, nil
+		}
+		actual, err := ParseVersion(values[0])
+		if err != nil {
+			// A cluster reporting a version that cannot be parsed
+			// cannot satisfy a version requirement.
+			return false, nil
+		}
+		comparison := CompareVersions(actual, required)
+		if r.Operator == ClusterFieldOpGt {
+			return comparison > 0, nil
+		}
+		return comparison < 0, nil
+	default:
+		return false, errors.Errorf("unsupported operator %q for cluster field %q", r.Operator, r.Key)
+	}
+}
+
+func fieldValueEqual(isVersion bool, value, requiredValue string) (bool, error) {
+	if !isVersion {
+		return value == requiredValue, nil
+	}
+	required, err := ParseVersion(requiredValue)
+	if err != nil {
+		return false, err
+	}
+	actual, err := ParseVersion(value)
+	if err != nil {
+		return false, nil
+	}
+	return CompareVersions(actual, required) == 0, nil
+}
+
+// ParseVersion parses the numeric components of a version like
+// "v1.27.3-gke.100" and ignores any suffix.
+func ParseVersion(version string) ([]int, error) {
+	trimmed := strings.TrimPrefix(version, "v")
+	if end := strings.IndexFunc(trimmed, func(r rune) bool { return r != '.' && (r < '0' || r > '9') }); end >= 0 {
+		trimmed = trimmed[:end]
+	}
+	if len(trimmed) == 0 {
+		return nil, errors.Errorf("invalid version %q", version)
+	}
+	components := []int{}
+	for _, component := range strings.Split(trimmed, ".") {
+		number, err := strconv.Atoi(component)
+		if err != nil {
+			return nil, errors.Errorf("invalid version %q", version)
+		}
+		components = append(components, number)
+	}
+	return components, nil
+}
+
+// CompareVersions compares the version to the required version at
+// the precision of the required version.
+func CompareVersions(version, required []int) int {
+	for i, requiredComponent := range required {
+		component := 0
+		if i < len(version) {
+			component = version[i]
+		}
+		if component != requiredComponent {
+			if component < requiredComponent {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+func TestMatchesClusterFields(t *testing.T) {
+	cluster := &fedv1b1.KubeFedCluster{
+		Status: fedv1b1.KubeFedClusterStatus{
+			Region:            "europe-west1",
+			Zones:             []string{"europe-west1-b", "europe-west1-c"},
+			KubernetesVersion: "v1.27.3-gke.100",
+		},
+	}
+
+	testCases := map[string]struct {
+		requirement ClusterFieldRequirement
+		expected    bool
+		expectedErr bool
+	}{
+		"In matches value": {
+			requirement: ClusterFieldRequirement{Key: ClusterRegionField, Operator: ClusterFieldOpIn, Values: []string{"us-east1", "europe-west1"}},
+			expected:    true,
+		},
+		"In matches any value of a list field": {
+			requirement: ClusterFieldRequirement{Key: ClusterZonesField, Operator: ClusterFieldOpIn, Values: []string{"europe-west1-c"}},
+			expected:    true,
+		},
+		"NotIn matches missing field": {
+			requirement: ClusterFieldRequirement{Key: ClusterProviderField, Operator: ClusterFieldOpNotIn, Values: []string{"gke"}},
+			expected:    true,
+		},
+		"NotIn does not match value": {
+			requirement: ClusterFieldRequirement{Key: ClusterRegionField, Operator: ClusterFieldOpNotIn, Values: []string{"europe-west1"}},
+		},
+		"Exists matches set field": {
+			requirement: ClusterFieldRequirement{Key: ClusterRegionField, Operator: ClusterFieldOpExists},
+			expected:    true,
+		},
+		"DoesNotExist matches missing field": {
+			requirement: ClusterFieldRequirement{Key: ClusterProviderField, Operator: ClusterFieldOpDoesNotExist},
+			expected:    true,
+		},
+		"In compares versions at the precision of the value": {
+			requirement: ClusterFieldRequirement{Key: ClusterKubernetesVersionField, Operator: ClusterFieldOpIn, Values: []string{"1.27"}},
+			expected:    true,
+		},
+		"Gt is false for versions equal at the precision of the value": {
+			requirement: ClusterFieldRequirement{Key: ClusterKubernetesVersionField, Operator: ClusterFieldOpGt, Values: []string{"1.27"}},
+		},
+		"Gt matches later version": {
+			requirement: ClusterFieldRequirement{Key: ClusterKubernetesVersionField, Operator: ClusterFieldOpGt, Values: []string{"1.26"}},
+			expected:    true,
+		},
+		"Lt compares numerically": {
+			requirement: ClusterFieldRequirement{Key: ClusterKubernetesVersionField, Operator: ClusterFieldOpLt, Values: []string{"1.100"}},
+			expected:    true,
+		},
+		"Gt is not supported for other fields": {
+			requirement: ClusterFieldRequirement{Key: ClusterRegionField, Operator: ClusterFieldOpGt, Values: []string{"a"}},
+			expectedErr: true,
+		},
+		"Invalid version is an error": {
+			requirement: ClusterFieldRequirement{Key: ClusterKubernetesVersionField, Operator: ClusterFieldOpGt, Values: []string{"latest"}},
+			expectedErr: true,
+		},
+		"Unsupported field is an error": {
+			requirement: ClusterFieldRequirement{Key: "spec.apiEndpoint", Operator: ClusterFieldOpExists},
+			expectedErr: true,
+		},
+		"Unsupported operator is an error": {
+			requirement: ClusterFieldRequirement{Key: ClusterRegionField, Operator: "Equals", Values: []string{"a"}},
+			expectedErr: true,
+		},
+	}
+
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			matches, err := MatchesClusterFields([]ClusterFieldRequirement{tc.requirement}, cluster)
+			if tc.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, matches)
+		})
+	}
+}
+
+func TestClusterFieldsChanged(t *testing.T) {
+	oldCluster := &fedv1b1.KubeFedCluster{
+		Status: fedv1b1.KubeFedClusterStatus{KubernetesVersion: "v1.26.5"},
+	}
+	newCluster := oldCluster.DeepCopy()
+	assert.False(t, ClusterFieldsChanged(oldCluster, newCluster))
+
+	newCluster.Status.KubernetesVersion = "v1.27.1"
+	assert.True(t, ClusterFieldsChanged(oldCluster, newCluster))
+}
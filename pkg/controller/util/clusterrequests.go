@@ -0,0 +1,132 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	restclient "k8s.io/client-go/rest"
+)
+
+// maxClockSkewRoundTrip is the longest round trip of a request whose
+// response is used to estimate the clock skew of a cluster.  The
+// estimate assumes the API server responded half way through the
+// round trip, so slower requests give a less precise estimate.
+const maxClockSkewRoundTrip = 2 * time.Second
+
+// ClusterRequestObservations summarizes the responses of the API
+// server of a member cluster to the requests of the controller
+// manager.
+type ClusterRequestObservations struct {
+	// The number of requests throttled by the API server (i.e. with a
+	// response of 429 Too Many Requests), e.g. by API priority and
+	// fairness.
+	ThrottledRequests int64
+	// The time of the most recent throttled request.
+	LastThrottled time.Time
+	// The clock of the API server less the clock of the controller
+	// manager, estimated from the Date header of the most recent
+	// response.
+	ClockSkew time.Duration
+	// The time at which the clock skew was estimated.
+	ClockSkewObserved time.Time
+}
+
+var clusterRequests = struct {
+	sync.RWMutex
+	observations map[string]*ClusterRequestObservations
+}{observations: make(map[string]*ClusterRequestObservations)}
+
+// GetClusterRequestObservations returns the observations of the
+// responses of the named cluster.
+func GetClusterRequestObservations(clusterName string) ClusterRequestObservations {
+	clusterRequests.RLock()
+	defer clusterRequests.RUnlock()
+	if observations, ok := clusterRequests.observations[clusterName]; ok {
+		return *observations
+	}
+	return ClusterRequestObservations{}
+}
+
+// ForgetClusterRequestObservations discards the observations of the
+// responses of the named cluster.
+func ForgetClusterRequestObservations(clusterName string) {
+	clusterRequests.Lock()
+	defer clusterRequests.Unlock()
+	delete(clusterRequests.observations, clusterName)
+}
+
+// observeClusterRequests configures the clients created from the
+// given config to record the responses of the named cluster.
+func observeClusterRequests(clusterName string, config *restclient.Config) {
+	wrapTransport := config.WrapTransport
+	config.WrapTransport = func(rt http.RoundTripper) http.RoundTripper {
+		if wrapTransport != nil {
+			rt = wrapTransport(rt)
+		}
+		return &observingRoundTripper{clusterName: clusterName, delegate: rt}
+	}
+}
+
+type observingRoundTripper struct {
+	clusterName string
+	delegate    http.RoundTripper
+}
+
+func (rt *observingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := rt.delegate.RoundTrip(req)
+	if err == nil {
+		observeClusterResponse(rt.clusterName, resp, start, time.Now())
+	}
+	return resp, err
+}
+
+// observeClusterResponse records a response of the named cluster to a
+// request sent at start whose response was received at end.
+func observeClusterResponse(clusterName string, resp *http.Response, start, end time.Time) {
+	throttled := resp.StatusCode == http.StatusTooManyRequests
+	var serverTime time.Time
+	if end.Sub(start) <= maxClockSkewRoundTrip {
+		// Errors are ignored since the header is optional.
+		serverTime, _ = http.ParseTime(resp.Header.Get("Date"))
+	}
+	if !throttled && serverTime.IsZero() {
+		return
+	}
+
+	clusterRequests.Lock()
+	defer clusterRequests.Unlock()
+	observations, ok := clusterRequests.observations[clusterName]
+	if !ok {
+		observations = &ClusterRequestObservations{}
+		clusterRequests.observations[clusterName] = observations
+	}
+	if throttled {
+		observations.ThrottledRequests++
+		observations.LastThrottled = end
+	}
+	if !serverTime.IsZero() {
+		// The Date header is truncated to the second, so the server
+		// time is on average half a second later.
+		midpoint := start.Add(end.Sub(start) / 2)
+		observations.ClockSkew = serverTime.Add(500 * time.Millisecond).Sub(midpoint)
+		observations.ClockSkewObserved = end
+	}
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestObserveClusterResponse(t *testing.T) {
+	clusterName := "observed"
+	defer ForgetClusterRequestObservations(clusterName)
+
+	start := time.Now()
+	end := start.Add(100 * time.Millisecond)
+	serverTime := start.Add(time.Minute)
+	resp := &http.Response{
+		StatusCode: http.StatusTooManyRequests,
+		Header:     http.Header{"Date": []string{serverTime.UTC().Format(http.TimeFormat)}},
+	}
+	observeClusterResponse(clusterName, resp, start, end)
+
+	observations := GetClusterRequestObservations(clusterName)
+	if observations.ThrottledRequests != 1 || !observations.LastThrottled.Equal(end) {
+		t.Fatalf("Expected a throttled request, got %+v", observations)
+	}
+	if skew := observations.ClockSkew; skew < time.Minute-time.Second || skew > time.Minute+time.Second {
+		t.Fatalf("Expected a clock skew of about a minute, got %v", skew)
+	}
+
+	// A slow response does not update the clock skew.
+	resp = &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Date": []string{start.UTC().Format(http.TimeFormat)}},
+	}
+	observeClusterResponse(clusterName, resp, start, start.Add(time.Minute))
+	if updated := GetClusterRequestObservations(clusterName); updated != observations {
+		t.Fatalf("Expected the observations to be unchanged, got %+v", updated)
+	}
+
+	ForgetClusterRequestObservations(clusterName)
+	if observations := GetClusterRequestObservations(clusterName); observations.ThrottledRequests != 0 {
+		t.Fatalf("Expected the observations to be forgotten, got %+v", observations)
+	}
+}
@@ -31,6 +31,9 @@ const (
 
 	ServiceAccountKind = "ServiceAccount"
 
+	ConfigMapKind = "ConfigMap"
+	SecretKind    = "Secret"
+
 	// The following fields are used to interact with unstructured
 	// resources.
 
@@ -47,12 +50,15 @@ const (
 	RetainReplicasField = "retainReplicas"
 
 	// Template fields
-	TemplateField = "template"
+	TemplateField           = "template"
+	CompressedTemplateField = "compressedTemplate"
 
 	// Placement fields
 	PlacementField       = "placement"
 	ClusterSelectorField = "clusterSelector"
 	MatchLabelsField     = "matchLabels"
+	MinClustersField     = "minClusters"
+	MaxClustersField     = "maxClusters"
 
 	// Override fields
 	OverridesField        = "overrides"
@@ -60,6 +66,9 @@ const (
 	ClusterOverridesField = "clusterOverrides"
 	PathField             = "path"
 	ValueField            = "value"
+	ValueFromField        = "valueFrom"
+	ValuesField           = "values"
+	PatchesField          = "patches"
 
 	// Propagation status fields
 	ConditionsField         = "conditions"
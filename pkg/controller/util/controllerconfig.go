@@ -20,9 +20,23 @@ import (
 	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	restclient "k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 
+	"sigs.k8s.io/kubefed/pkg/apis/core/typeconfig"
 	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/kubefed/pkg/controller/util/apidiscovery"
+	"sigs.k8s.io/kubefed/pkg/controller/util/circuitbreaker"
+	"sigs.k8s.io/kubefed/pkg/controller/util/deletionlimiter"
+	"sigs.k8s.io/kubefed/pkg/controller/util/diagnostics"
+	"sigs.k8s.io/kubefed/pkg/controller/util/dispatchlimiter"
+	"sigs.k8s.io/kubefed/pkg/controller/util/healthcheck"
+	"sigs.k8s.io/kubefed/pkg/controller/util/memberinformer"
+	"sigs.k8s.io/kubefed/pkg/controller/util/memberreads"
+	"sigs.k8s.io/kubefed/pkg/controller/util/notifier"
+	"sigs.k8s.io/kubefed/pkg/controller/util/ownership"
+	"sigs.k8s.io/kubefed/pkg/controller/util/quota"
 )
 
 // LeaderElectionConfiguration defines the configuration of leader election
@@ -71,10 +85,72 @@ type ControllerConfig struct {
 	KubeConfig              *restclient.Config
 	ClusterAvailableDelay   time.Duration
 	ClusterUnavailableDelay time.Duration
+	StatusUpdateInterval    time.Duration
 	MinimizeLatency         bool
 	SkipAdoptingResources   bool
+	Notifier                *notifier.Notifier
+	DeletionLimiter         *deletionlimiter.Limiter
+	CircuitBreaker          *circuitbreaker.Breaker
+	DispatchLimiter         *dispatchlimiter.Limiter
+	HealthChecks            *healthcheck.Registry
+	Diagnostics             *diagnostics.Registry
+	APIDiscovery            *apidiscovery.Cache
+	MemberInformers         *memberinformer.Manager
+	MemberReads             *memberreads.Cache
+	PropagationQuotas       *quota.Tracker
+	OwnershipConflicts      *ownership.Tracker
+	PropagationMetadata     *fedv1b1.PropagationMetadataConfig
+	NamespaceMetadata       *fedv1b1.NamespaceMetadataConfig
+	Scheduling              *fedv1b1.SchedulingConfig
+	// ReportAdoptableResources indicates that resources that already
+	// exist in member clusters are reported as adoptable by the sync
+	// controller instead of being adopted.
+	ReportAdoptableResources bool
+	// ClusterLifecycleHooks are run by the cluster controller when
+	// clusters are joined, approved, marked unhealthy or unjoined.
+	ClusterLifecycleHooks []fedv1b1.ClusterLifecycleHook
+	// AllowedClusterScopedResources lists the cluster-scoped
+	// resources that a namespace-scoped control plane may propagate.
+	AllowedClusterScopedResources []fedv1b1.AllowedClusterScopedResource
+	// DisabledControllers holds the names of the controllers that
+	// are switched off by the KubeFedConfig.
+	DisabledControllers map[fedv1b1.ControllerName]bool
+
+	// Manager is the controller-runtime manager shared by the
+	// controllers registered with it.  It must be started with the
+	// stop channel of those controllers.
+	Manager manager.Manager
 }
 
 func (c *ControllerConfig) LimitedScope() bool {
 	return c.KubeFedNamespaces.TargetNamespace != metav1.NamespaceAll
 }
+
+// AllowedClusterScopedNames returns the names of the resources of the
+// given cluster-scoped target type that a namespace-scoped control
+// plane may propagate, or nil if it may not propagate the type.
+func (c *ControllerConfig) AllowedClusterScopedNames(targetType metav1.APIResource) sets.String {
+	for _, resource := range c.AllowedClusterScopedResources {
+		if resource.Group == targetType.Group && resource.Kind == targetType.Kind {
+			return sets.NewString(resource.Names...)
+		}
+	}
+	return nil
+}
+
+// ControllerEnabled indicates whether the named controller is not
+// switched off by the KubeFedConfig.  Its feature gate, if any, must
+// be checked separately.
+func (c *ControllerConfig) ControllerEnabled(name fedv1b1.ControllerName) bool {
+	return !c.DisabledControllers[name]
+}
+
+// StatusUpdateIntervalFor returns the minimum time between status
+// updates of the federated resources of the given type, preferring
+// the interval of the type if it is set.
+func (c *ControllerConfig) StatusUpdateIntervalFor(typeConfig typeconfig.Interface) time.Duration {
+	if interval := typeConfig.GetStatusUpdateInterval(); interval != nil {
+		return interval.Duration
+	}
+	return c.StatusUpdateInterval
+}
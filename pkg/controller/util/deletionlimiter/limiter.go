@@ -0,0 +1,237 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletionlimiter
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+)
+
+// PausedAnnotation is the annotation of a KubeFedCluster whose
+// deletions are paused for exceeding the deletion limit.  Its value is
+// the time at which deletions were paused, and deletions from the
+// cluster resume once it is removed.
+const PausedAnnotation = "kubefed.k8s.io/deletions-paused"
+
+// DefaultWindow is the window over which deletions are counted if
+// none is configured.
+const DefaultWindow = time.Minute
+
+type clusterDeletions struct {
+	// The times of the deletions recorded within the current window.
+	times []time.Time
+	// The times at which the deletions that were allowed but have yet
+	// to be recorded or released were reserved.  A reservation that
+	// is never settled expires with the window.
+	reserved []time.Time
+	// Whether deletions from the cluster are paused.
+	paused bool
+	// Whether the pause has yet to be observed on the cluster, in
+	// which case the absence of the paused annotation does not
+	// indicate that the pause was acknowledged.
+	pending bool
+	// Whether the paused annotation has been added to the cluster.
+	persisted bool
+}
+
+// Limiter limits the number of deletions from each member cluster
+// within a window of time, as configured in a KubeFedConfig.  A
+// single limiter is shared by the sync controllers of all federated
+// types so that deletions are counted across types.  Once the limit
+// of a cluster is exceeded, deletions from the cluster are paused by
+// annotating its KubeFedCluster so that the pause survives a restart
+// of the controller manager, and remain paused until the annotation
+// is removed.  Each allowed deletion reserves a slot within the limit
+// until it is recorded or released, so that concurrent deletions
+// cannot exceed the limit.  A nil *Limiter is valid and allows all
+// deletions.
+type Limiter struct {
+	maxDeletions int
+	window       time.Duration
+
+	sync.Mutex
+	clusters map[string]*clusterDeletions
+
+	now func() time.Time
+
+	// Adds the paused annotation to the given cluster.
+	setPaused func(cluster *fedv1b1.KubeFedCluster, pausedAt time.Time) error
+}
+
+// New returns a limiter for the given configuration that persists
+// pauses with the given client, or nil if deletions are not limited.
+func New(config *fedv1b1.DeletionLimitConfig, client genericclient.Client) *Limiter {
+	if config == nil || config.MaxDeletions <= 0 {
+		return nil
+	}
+	window := DefaultWindow
+	if config.Window != nil && config.Window.Duration > 0 {
+		window = config.Window.Duration
+	}
+	return &Limiter{
+		maxDeletions: int(config.MaxDeletions),
+		window:       window,
+		clusters:     make(map[string]*clusterDeletions),
+		now:          time.Now,
+		setPaused: func(cluster *fedv1b1.KubeFedCluster, pausedAt time.Time) error {
+			return annotatePaused(client, cluster, pausedAt)
+		},
+	}
+}
+
+// Allow returns true if a deletion from the given cluster is within
+// the limit of the cluster, and reserves a slot for the deletion that
+// must be settled with Record or Release.  Deletions are paused once
+// the deletions recorded or in progress within the window reach the
+// limit, and are not allowed while the cluster has the paused
+// annotation.
+func (l *Limiter) Allow(cluster *fedv1b1.KubeFedCluster) bool {
+	if l == nil {
+		return true
+	}
+
+	l.Lock()
+	deletions := l.deletionsForCluster(cluster.Name)
+	if _, ok := cluster.Annotations[PausedAnnotation]; ok {
+		deletions.paused = true
+		deletions.pending = false
+		deletions.persisted = true
+		l.Unlock()
+		return false
+	}
+	if deletions.paused && !deletions.pending {
+		klog.Infof("Resuming deletions from cluster %q since the %q annotation was removed", cluster.Name, PausedAnnotation)
+		deletions.paused = false
+		deletions.persisted = false
+		deletions.times = nil
+	}
+	if !deletions.paused {
+		now := l.now()
+		windowStart := now.Add(-l.window)
+		deletions.times = withinWindow(deletions.times, windowStart)
+		deletions.reserved = withinWindow(deletions.reserved, windowStart)
+		if len(deletions.times)+len(deletions.reserved) < l.maxDeletions {
+			deletions.reserved = append(deletions.reserved, now)
+			l.Unlock()
+			return true
+		}
+		klog.Warningf("Pausing deletions from cluster %q since %d deletions were made within %v. "+
+			"Remove the %q annotation of the cluster to resume deletions.",
+			cluster.Name, l.maxDeletions, l.window, PausedAnnotation)
+		deletions.paused = true
+		deletions.pending = true
+	}
+	persisted := deletions.persisted
+	l.Unlock()
+
+	// The pause is persisted outside of the lock, and is retried by
+	// subsequent deletions if it could not be.
+	if !persisted {
+		if err := l.setPaused(cluster, l.now()); err != nil {
+			klog.Errorf("Failed to persist the pause of deletions from cluster %q: %v", cluster.Name, err)
+			return false
+		}
+		l.Lock()
+		deletions.persisted = true
+		l.Unlock()
+	}
+	return false
+}
+
+// Record counts a deletion from the named cluster against its limit
+// in place of the slot it reserved.  Deletions are recorded once they
+// have succeeded.
+func (l *Limiter) Record(clusterName string) {
+	if l == nil {
+		return
+	}
+
+	l.Lock()
+	defer l.Unlock()
+	deletions := l.deletionsForCluster(clusterName)
+	deletions.release()
+	deletions.times = append(deletions.times, l.now())
+}
+
+// Release frees the slot reserved for a deletion from the named
+// cluster that failed or was not made.
+func (l *Limiter) Release(clusterName string) {
+	if l == nil {
+		return
+	}
+
+	l.Lock()
+	defer l.Unlock()
+	l.deletionsForCluster(clusterName).release()
+}
+
+// release frees the oldest reserved slot, if any.
+func (d *clusterDeletions) release() {
+	if len(d.reserved) > 0 {
+		d.reserved = d.reserved[1:]
+	}
+}
+
+// withinWindow returns the given times that are after the start of
+// the window.
+func withinWindow(times []time.Time, windowStart time.Time) []time.Time {
+	result := times[:0]
+	for _, t := range times {
+		if t.After(windowStart) {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+func (l *Limiter) deletionsForCluster(clusterName string) *clusterDeletions {
+	deletions, ok := l.clusters[clusterName]
+	if !ok {
+		deletions = &clusterDeletions{}
+		l.clusters[clusterName] = deletions
+	}
+	return deletions
+}
+
+// annotatePaused adds the paused annotation to the given cluster
+// unless it is already present.
+func annotatePaused(client genericclient.Client, cluster *fedv1b1.KubeFedCluster, pausedAt time.Time) error {
+	err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		current := &fedv1b1.KubeFedCluster{}
+		if err := client.Get(context.TODO(), current, cluster.Namespace, cluster.Name); err != nil {
+			return err
+		}
+		if _, ok := current.Annotations[PausedAnnotation]; ok {
+			return nil
+		}
+		if current.Annotations == nil {
+			current.Annotations = make(map[string]string)
+		}
+		current.Annotations[PausedAnnotation] = pausedAt.UTC().Format(time.RFC3339)
+		return client.Update(context.TODO(), current)
+	})
+	return errors.Wrapf(err, "failed to add the %q annotation", PausedAnnotation)
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletionlimiter
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+func TestNewWithoutLimit(t *testing.T) {
+	assert.Nil(t, New(nil, nil))
+	assert.Nil(t, New(&fedv1b1.DeletionLimitConfig{}, nil))
+
+	// A nil limiter must be safe to use.
+	var l *Limiter
+	assert.True(t, l.Allow(&fedv1b1.KubeFedCluster{}))
+	l.Record("cluster1")
+	l.Release("cluster1")
+}
+
+func TestAllow(t *testing.T) {
+	now := time.Now()
+	l := New(&fedv1b1.DeletionLimitConfig{
+		MaxDeletions: 2,
+		Window:       &metav1.Duration{Duration: time.Minute},
+	}, nil)
+	l.now = func() time.Time { return now }
+	pauses := make(map[string]int)
+	failPause := false
+	l.setPaused = func(cluster *fedv1b1.KubeFedCluster, pausedAt time.Time) error {
+		if failPause {
+			return errors.New("conflict")
+		}
+		pauses[cluster.Name]++
+		return nil
+	}
+
+	cluster1 := &fedv1b1.KubeFedCluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster1"}}
+	cluster2 := &fedv1b1.KubeFedCluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster2"}}
+
+	// An allowed deletion reserves a slot until it is recorded or
+	// released.
+	assert.True(t, l.Allow(cluster1))
+	l.Release("cluster1")
+	assert.True(t, l.Allow(cluster1))
+	l.Record("cluster1")
+	assert.True(t, l.Allow(cluster1))
+	l.Record("cluster1")
+	assert.False(t, l.Allow(cluster1), "Deletions exceeding the limit should not be allowed")
+	assert.Equal(t, 1, pauses["cluster1"], "The pause should be persisted")
+	assert.True(t, l.Allow(cluster2), "Deletions should be limited per cluster")
+
+	// The pause is retained until it is observed on the cluster.
+	now = now.Add(2 * time.Minute)
+	assert.False(t, l.Allow(cluster1), "Deletions should remain paused after the window has passed")
+	assert.Equal(t, 1, pauses["cluster1"], "The pause should only be persisted once")
+	cluster1.Annotations = map[string]string{PausedAnnotation: now.Format(time.RFC3339)}
+	assert.False(t, l.Allow(cluster1))
+
+	delete(cluster1.Annotations, PausedAnnotation)
+	assert.True(t, l.Allow(cluster1), "Deletions should resume once the annotation is removed")
+
+	// A pause that could not be persisted is retried.
+	failPause = true
+	l.Record("cluster2")
+	l.Record("cluster2")
+	assert.False(t, l.Allow(cluster2))
+	assert.Equal(t, 0, pauses["cluster2"])
+	failPause = false
+	assert.False(t, l.Allow(cluster2))
+	assert.Equal(t, 1, pauses["cluster2"])
+}
+
+func TestAllowAfterRestart(t *testing.T) {
+	l := New(&fedv1b1.DeletionLimitConfig{MaxDeletions: 2}, nil)
+	l.setPaused = func(cluster *fedv1b1.KubeFedCluster, pausedAt time.Time) error {
+		t.Fatalf("An observed pause should not be persisted again")
+		return nil
+	}
+
+	cluster := &fedv1b1.KubeFedCluster{ObjectMeta: metav1.ObjectMeta{
+		Name:        "cluster1",
+		Annotations: map[string]string{PausedAnnotation: "2019-01-01T00:00:00Z"},
+	}}
+	assert.False(t, l.Allow(cluster), "A persisted pause should be observed by a new limiter")
+
+	delete(cluster.Annotations, PausedAnnotation)
+	assert.True(t, l.Allow(cluster))
+}
+
+func TestWindow(t *testing.T) {
+	now := time.Now()
+	l := New(&fedv1b1.DeletionLimitConfig{MaxDeletions: 2}, nil)
+	l.now = func() time.Time { return now }
+	cluster := &fedv1b1.KubeFedCluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster1"}}
+
+	// Deletions outside of the window are not counted.
+	l.Record("cluster1")
+	now = now.Add(2 * DefaultWindow)
+	l.Record("cluster1")
+	assert.True(t, l.Allow(cluster))
+}
+
+func TestConcurrentAllow(t *testing.T) {
+	l := New(&fedv1b1.DeletionLimitConfig{MaxDeletions: 5}, nil)
+	l.setPaused = func(cluster *fedv1b1.KubeFedCluster, pausedAt time.Time) error {
+		return nil
+	}
+	cluster := &fedv1b1.KubeFedCluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster1"}}
+
+	// Deletions in progress count against the limit before they
+	// are recorded.
+	var wg sync.WaitGroup
+	var lock sync.Mutex
+	allowed := 0
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if l.Allow(cluster) {
+				lock.Lock()
+				allowed++
+				lock.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 5, allowed, "Concurrent deletions should not exceed the limit")
+}
+
+func TestReleaseAndExpiry(t *testing.T) {
+	now := time.Now()
+	l := New(&fedv1b1.DeletionLimitConfig{MaxDeletions: 2}, nil)
+	l.now = func() time.Time { return now }
+	l.setPaused = func(cluster *fedv1b1.KubeFedCluster, pausedAt time.Time) error {
+		t.Fatalf("Deletions should not be paused")
+		return nil
+	}
+	cluster := &fedv1b1.KubeFedCluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster1"}}
+
+	assert.True(t, l.Allow(cluster))
+	assert.True(t, l.Allow(cluster))
+
+	// The slot of a failed deletion is freed.
+	l.Release("cluster1")
+	assert.True(t, l.Allow(cluster))
+
+	// Reservations that are never settled expire with the window.
+	now = now.Add(2 * DefaultWindow)
+	assert.True(t, l.Allow(cluster))
+	assert.True(t, l.Allow(cluster))
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"runtime"
+	"runtime/pprof"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Source writes the state of a component to a diagnostic dump, such
+// as the size of its informer caches.
+type Source func(w io.Writer)
+
+// Registry aggregates the state of the KubeFed controllers into a
+// diagnostic dump to debug memory growth and stuck reconciliation.
+// A nil *Registry is valid and discards all sources.
+type Registry struct {
+	lock    sync.RWMutex
+	sources map[string]*entry
+}
+
+type entry struct {
+	source Source
+}
+
+// New returns an empty registry.
+func New() *Registry {
+	return &Registry{sources: make(map[string]*entry)}
+}
+
+// AddSource adds a source to the dump, replacing any source
+// previously added with the same name.  The returned function
+// removes the source unless it has since been replaced, so that a
+// controller being restarted does not remove the source of its
+// replacement.
+func (r *Registry) AddSource(name string, source Source) func() {
+	if r == nil {
+		return func() {}
+	}
+	e := &entry{source: source}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.sources[name] = e
+	return func() {
+		r.lock.Lock()
+		defer r.lock.Unlock()
+		if r.sources[name] == e {
+			delete(r.sources, name)
+		}
+	}
+}
+
+// Dump writes the memory statistics of the process, the state
+// written by each source in order of name and the stacks of all
+// goroutines.
+func (r *Registry) Dump(w io.Writer) {
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+	fmt.Fprintf(w, "== runtime (%s)\n", time.Now().UTC().Format(time.RFC3339))
+	fmt.Fprintf(w, "goroutines: %d\n", runtime.NumGoroutine())
+	fmt.Fprintf(w, "heap alloc bytes: %d\n", memStats.HeapAlloc)
+	fmt.Fprintf(w, "heap objects: %d\n", memStats.HeapObjects)
+	fmt.Fprintf(w, "heap sys bytes: %d\n", memStats.HeapSys)
+	fmt.Fprintf(w, "gc cycles: %d\n", memStats.NumGC)
+
+	for _, name := range r.sourceNames() {
+		fmt.Fprintf(w, "\n== %s\n", name)
+		r.lock.RLock()
+		e, ok := r.sources[name]
+		r.lock.RUnlock()
+		if ok {
+			e.source(w)
+		}
+	}
+
+	fmt.Fprintf(w, "\n== goroutines\n")
+	_ = pprof.Lookup("goroutine").WriteTo(w, 2)
+}
+
+// Handler returns the handler serving the dump.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		r.Dump(w)
+	})
+}
+
+func (r *Registry) sourceNames() []string {
+	if r == nil {
+		return nil
+	}
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	names := make([]string, 0, len(r.sources))
+	for name := range r.sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
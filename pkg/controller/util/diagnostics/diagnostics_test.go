@@ -0,0 +1,73 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDump(t *testing.T) {
+	r := New()
+	r.AddSource("sync-controller/deployments.apps", func(w io.Writer) {
+		fmt.Fprintln(w, "cluster1: 3 cached")
+	})
+	r.AddSource("cluster-controller", func(w io.Writer) {
+		fmt.Fprintln(w, "cluster1: ready")
+	})
+	remove := r.AddSource("removed", func(w io.Writer) {})
+	remove()
+	removeReplaced := r.AddSource("replaced", func(w io.Writer) {})
+	r.AddSource("replaced", func(w io.Writer) {
+		fmt.Fprintln(w, "replacement")
+	})
+	// Removing a replaced source leaves its replacement.
+	removeReplaced()
+
+	var buf bytes.Buffer
+	r.Dump(&buf)
+	dump := buf.String()
+
+	assert.True(t, strings.HasPrefix(dump, "== runtime"))
+	assert.NotContains(t, dump, "== removed")
+	assert.Contains(t, dump, "== replaced\nreplacement\n")
+	clusterIndex := strings.Index(dump, "== cluster-controller\ncluster1: ready\n")
+	syncIndex := strings.Index(dump, "== sync-controller/deployments.apps\ncluster1: 3 cached\n")
+	goroutinesIndex := strings.Index(dump, "== goroutines\n")
+	assert.True(t, clusterIndex > 0)
+	assert.True(t, syncIndex > clusterIndex, "sources should be dumped in order of name")
+	assert.True(t, goroutinesIndex > syncIndex, "goroutines should be dumped last")
+	assert.Contains(t, dump, "TestDump")
+}
+
+func TestNilRegistry(t *testing.T) {
+	var r *Registry
+	remove := r.AddSource("source", func(w io.Writer) {
+		fmt.Fprintln(w, "discarded")
+	})
+	remove()
+
+	var buf bytes.Buffer
+	r.Dump(&buf)
+	assert.NotContains(t, buf.String(), "discarded")
+	assert.Contains(t, buf.String(), "== goroutines")
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatchlimiter
+
+import (
+	"sync"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+const (
+	// DefaultMaxPerCluster is the number of operations that may be in
+	// progress against a single member cluster if none is configured.
+	// It matches the burst of the rate limiter of cluster clients.
+	DefaultMaxPerCluster = 30
+	// DefaultMaxTotal is the number of operations that may be in
+	// progress against all member clusters if none is configured.
+	DefaultMaxTotal = 300
+)
+
+// Limiter bounds the number of operations the sync controller
+// dispatches concurrently to member clusters, as configured in a
+// KubeFedConfig.  A single limiter is shared by the sync controllers
+// of all federated types so that operations are counted across
+// types.  A nil *Limiter is valid and does not bound dispatch.
+type Limiter struct {
+	maxPerCluster int
+
+	// total holds a token for each operation in progress.  It is nil
+	// if the total is not bounded.
+	total chan struct{}
+
+	sync.Mutex
+	clusters map[string]chan struct{}
+}
+
+// New returns a limiter for the given configuration, or nil if
+// dispatch is not bounded.  A bound that is not positive is not
+// enforced.
+func New(config *fedv1b1.DispatchConcurrencyConfig) *Limiter {
+	if config == nil || config.MaxPerCluster <= 0 && config.MaxTotal <= 0 {
+		return nil
+	}
+	l := &Limiter{
+		maxPerCluster: int(config.MaxPerCluster),
+		clusters:      make(map[string]chan struct{}),
+	}
+	if config.MaxTotal > 0 {
+		l.total = make(chan struct{}, config.MaxTotal)
+	}
+	return l
+}
+
+// Acquire blocks until an operation against the given cluster can be
+// started within the bounds of the limiter and returns true, or
+// returns false if the given channel is closed first.  Release must
+// be called once an acquired operation completes.  A nil *Limiter
+// returns true without checking the channel.
+//
+// The slot of the cluster is acquired before the total slot so that
+// operations waiting on a saturated cluster do not prevent operations
+// against other clusters from starting.
+func (l *Limiter) Acquire(clusterName string, abort <-chan struct{}) bool {
+	if l == nil {
+		return true
+	}
+	cluster := l.clusterSlots(clusterName)
+	if cluster != nil {
+		select {
+		case cluster <- struct{}{}:
+		case <-abort:
+			return false
+		}
+	}
+	if l.total != nil {
+		select {
+		case l.total <- struct{}{}:
+		case <-abort:
+			if cluster != nil {
+				<-cluster
+			}
+			return false
+		}
+	}
+	// Slots may have become available at the same time as the
+	// channel was closed.
+	select {
+	case <-abort:
+		l.Release(clusterName)
+		return false
+	default:
+	}
+	return true
+}
+
+// Release records the completion of an operation against the given
+// cluster that was started by Acquire.
+func (l *Limiter) Release(clusterName string) {
+	if l == nil {
+		return
+	}
+	if l.total != nil {
+		<-l.total
+	}
+	if cluster := l.clusterSlots(clusterName); cluster != nil {
+		<-cluster
+	}
+}
+
+func (l *Limiter) clusterSlots(clusterName string) chan struct{} {
+	if l.maxPerCluster <= 0 {
+		return nil
+	}
+	l.Lock()
+	defer l.Unlock()
+	slots, ok := l.clusters[clusterName]
+	if !ok {
+		slots = make(chan struct{}, l.maxPerCluster)
+		l.clusters[clusterName] = slots
+	}
+	return slots
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatchlimiter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+// tryAcquire attempts to acquire a slot for the given cluster without
+// blocking for longer than needed to observe that none is available.
+func tryAcquire(l *Limiter, clusterName string) bool {
+	abort := make(chan struct{})
+	timer := time.AfterFunc(10*time.Millisecond, func() {
+		close(abort)
+	})
+	defer timer.Stop()
+	return l.Acquire(clusterName, abort)
+}
+
+func TestNewWithoutBounds(t *testing.T) {
+	assert.Nil(t, New(nil))
+	assert.Nil(t, New(&fedv1b1.DispatchConcurrencyConfig{}))
+
+	// A nil limiter must be safe to use.
+	var l *Limiter
+	assert.True(t, l.Acquire("cluster1", nil))
+	l.Release("cluster1")
+}
+
+func TestAcquirePerCluster(t *testing.T) {
+	l := New(&fedv1b1.DispatchConcurrencyConfig{MaxPerCluster: 2})
+	assert.True(t, tryAcquire(l, "cluster1"))
+	assert.True(t, tryAcquire(l, "cluster1"))
+	assert.False(t, tryAcquire(l, "cluster1"), "Operations exceeding the bound of the cluster should not be started")
+	assert.True(t, tryAcquire(l, "cluster2"), "Operations should be bounded per cluster")
+
+	l.Release("cluster1")
+	assert.True(t, tryAcquire(l, "cluster1"), "Released slots should be reused")
+}
+
+func TestAcquireTotal(t *testing.T) {
+	l := New(&fedv1b1.DispatchConcurrencyConfig{MaxPerCluster: 2, MaxTotal: 3})
+	assert.True(t, tryAcquire(l, "cluster1"))
+	assert.True(t, tryAcquire(l, "cluster1"))
+	assert.True(t, tryAcquire(l, "cluster2"))
+	assert.False(t, tryAcquire(l, "cluster3"), "Operations exceeding the total bound should not be started")
+
+	// An operation that failed to start must not hold the slot of
+	// its cluster.
+	l.Release("cluster1")
+	assert.True(t, tryAcquire(l, "cluster3"))
+	assert.False(t, tryAcquire(l, "cluster2"))
+	l.Release("cluster3")
+	assert.True(t, tryAcquire(l, "cluster2"))
+}
+
+func TestAcquireBlocksUntilRelease(t *testing.T) {
+	l := New(&fedv1b1.DispatchConcurrencyConfig{MaxTotal: 1})
+	assert.True(t, l.Acquire("cluster1", nil))
+
+	acquired := make(chan bool)
+	go func() {
+		acquired <- l.Acquire("cluster2", nil)
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("Acquire should block while the total bound is reached")
+	default:
+	}
+	l.Release("cluster1")
+	assert.True(t, <-acquired)
+
+	abort := make(chan struct{})
+	close(abort)
+	l.Release("cluster2")
+	assert.False(t, l.Acquire("cluster1", abort), "Acquire should fail once the channel is closed")
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	// MaxDriftPaths is the maximum number of changed paths that will
+	// be reported for a single drifted resource.
+	MaxDriftPaths = 10
+
+	// MaxDriftManagers is the maximum number of field managers that
+	// will be reported for a single drifted resource.
+	MaxDriftManagers = 3
+
+	managedFieldsField = "managedFields"
+)
+
+// Metadata fields that are compared when determining drift.  Other
+// metadata fields are either set by the member cluster api or do not
+// vary between the desired and cluster representations.
+var driftMetadataFields = sets.NewString("labels", "annotations")
+
+// Top-level fields that are never compared when determining drift.
+var driftIgnoredFields = sets.NewString("apiVersion", "kind", StatusField)
+
+// DriftedPaths returns the sorted dot-separated paths of fields whose
+// value in the cluster object differs from the desired object.  Only
+// fields present in the desired object are compared to avoid
+// reporting fields defaulted by the member cluster.  At most
+// MaxDriftPaths paths are returned.
+func DriftedPaths(desiredObj, clusterObj *unstructured.Unstructured) []string {
+	paths := []string{}
+	for key, desiredValue := range desiredObj.Object {
+		if driftIgnoredFields.Has(key) {
+			continue
+		}
+		clusterValue, ok := clusterObj.Object[key]
+		if key == MetadataField {
+			desiredMeta, _ := desiredValue.(map[string]interface{})
+			clusterMeta, _ := clusterValue.(map[string]interface{})
+			for field := range driftMetadataFields {
+				paths = appendDriftedPaths(paths, []string{MetadataField, field}, desiredMeta[field], clusterMeta[field], ok)
+			}
+			continue
+		}
+		paths = appendDriftedPaths(paths, []string{key}, desiredValue, clusterValue, ok)
+	}
+	sort.Strings(paths)
+	if len(paths) > MaxDriftPaths {
+		paths = paths[:MaxDriftPaths]
+	}
+	return paths
+}
+
+func appendDriftedPaths(paths, path []string, desiredValue, clusterValue interface{}, clusterHasValue bool) []string {
+	if desiredValue == nil {
+		return paths
+	}
+	if !clusterHasValue || clusterValue == nil {
+		return append(paths, strings.Join(path, "."))
+	}
+	desiredMap, desiredIsMap := desiredValue.(map[string]interface{})
+	clusterMap, clusterIsMap := clusterValue.(map[string]interface{})
+	if desiredIsMap && clusterIsMap {
+		for key, value := range desiredMap {
+			childPath := append(append([]string{}, path...), key)
+			clusterChild, ok := clusterMap[key]
+			paths = appendDriftedPaths(paths, childPath, value, clusterChild, ok)
+		}
+		return paths
+	}
+	if !reflect.DeepEqual(desiredValue, clusterValue) {
+		return append(paths, strings.Join(path, "."))
+	}
+	return paths
+}
+
+// DriftManagers returns the names of the field managers recorded in
+// the managedFields of the given cluster object, most recent first.
+// The managedFields field is only populated by member clusters that
+// support server-side apply, and an empty slice will be returned
+// otherwise.  At most MaxDriftManagers names are returned.
+func DriftManagers(clusterObj *unstructured.Unstructured) []string {
+	entries, ok, err := unstructured.NestedSlice(clusterObj.Object, MetadataField, managedFieldsField)
+	if err != nil || !ok {
+		return []string{}
+	}
+
+	type managerEntry struct {
+		name string
+		time string
+	}
+	managerEntries := []managerEntry{}
+	for _, rawEntry := range entries {
+		entry, ok := rawEntry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := entry["manager"].(string)
+		if len(name) == 0 {
+			continue
+		}
+		// RFC3339 timestamps sort lexically.
+		timestamp, _ := entry["time"].(string)
+		managerEntries = append(managerEntries, managerEntry{name: name, time: timestamp})
+	}
+	sort.SliceStable(managerEntries, func(i, j int) bool {
+		return managerEntries[i].time > managerEntries[j].time
+	})
+
+	managers := []string{}
+	seen := sets.NewString()
+	for _, entry := range managerEntries {
+		if seen.Has(entry.name) {
+			continue
+		}
+		seen.Insert(entry.name)
+		managers = append(managers, entry.name)
+		if len(managers) == MaxDriftManagers {
+			break
+		}
+	}
+	return managers
+}
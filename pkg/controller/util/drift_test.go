@@ -0,0 +1,111 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestDriftedPaths(t *testing.T) {
+	desiredObj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]interface{}{
+			"name":   "foo",
+			"labels": map[string]interface{}{"app": "foo"},
+		},
+		"spec": map[string]interface{}{
+			"replicas": int64(3),
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"serviceAccountName": "foo",
+				},
+			},
+		},
+	}}
+
+	testCases := map[string]struct {
+		clusterObj    map[string]interface{}
+		expectedPaths []string
+	}{
+		"No drift for matching object with defaulted fields": {
+			clusterObj: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"name":            "foo",
+					"resourceVersion": "42",
+					"labels":          map[string]interface{}{"app": "foo"},
+				},
+				"spec": map[string]interface{}{
+					"replicas":             int64(3),
+					"revisionHistoryLimit": int64(10),
+					"template": map[string]interface{}{
+						"spec": map[string]interface{}{
+							"serviceAccountName": "foo",
+							"dnsPolicy":          "ClusterFirst",
+						},
+					},
+				},
+				"status": map[string]interface{}{"replicas": int64(1)},
+			},
+			expectedPaths: []string{},
+		},
+		"Drift for changed and removed fields": {
+			clusterObj: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"name": "foo",
+				},
+				"spec": map[string]interface{}{
+					"replicas": int64(5),
+					"template": map[string]interface{}{
+						"spec": map[string]interface{}{},
+					},
+				},
+			},
+			expectedPaths: []string{
+				"metadata.labels",
+				"spec.replicas",
+				"spec.template.spec.serviceAccountName",
+			},
+		},
+	}
+
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			clusterObj := &unstructured.Unstructured{Object: tc.clusterObj}
+			assert.Equal(t, tc.expectedPaths, DriftedPaths(desiredObj, clusterObj))
+		})
+	}
+}
+
+func TestDriftManagers(t *testing.T) {
+	clusterObj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"managedFields": []interface{}{
+				map[string]interface{}{"manager": "kubefed", "time": "2019-07-01T10:00:00Z"},
+				map[string]interface{}{"manager": "kubectl", "time": "2019-07-01T12:00:00Z"},
+				map[string]interface{}{"manager": "kubefed", "time": "2019-07-01T11:00:00Z"},
+			},
+		},
+	}}
+	assert.Equal(t, []string{"kubectl", "kubefed"}, DriftManagers(clusterObj))
+
+	assert.Equal(t, []string{}, DriftManagers(&unstructured.Unstructured{Object: map[string]interface{}{}}))
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// SOPSMetadataField is the top-level field of a document
+	// encrypted by SOPS that holds its encryption metadata and MAC.
+	SOPSMetadataField = "sops"
+
+	SealedSecretGroup = "bitnami.com"
+	SealedSecretKind  = "SealedSecret"
+
+	// Annotations that widen the scope in which the payload of a
+	// SealedSecret can be decrypted.
+	SealedSecretClusterWideAnnotation   = "sealedsecrets.bitnami.com/cluster-wide"
+	SealedSecretNamespaceWideAnnotation = "sealedsecrets.bitnami.com/namespace-wide"
+)
+
+// SealedSecretScope is the scope in which the payload of a
+// SealedSecret can be decrypted.
+type SealedSecretScope string
+
+const (
+	// The payload can only be decrypted for the name and namespace
+	// it was sealed for.
+	SealedSecretStrictScope SealedSecretScope = "strict"
+	// The payload can be decrypted under any name in the namespace
+	// it was sealed for.
+	SealedSecretNamespaceWideScope SealedSecretScope = "namespace-wide"
+	// The payload can be decrypted under any name and namespace.
+	SealedSecretClusterWideScope SealedSecretScope = "cluster-wide"
+)
+
+// IsSOPSEncrypted returns true if the given object is a document
+// encrypted by SOPS.
+func IsSOPSEncrypted(obj *unstructured.Unstructured) bool {
+	metadata, ok := obj.Object[SOPSMetadataField].(map[string]interface{})
+	if !ok {
+		return false
+	}
+	_, ok = metadata["mac"]
+	return ok
+}
+
+// IsSealedSecret returns true if the given object is a SealedSecret,
+// whose payload is decrypted by the sealed-secrets controller of the
+// cluster it is created in.
+func IsSealedSecret(obj *unstructured.Unstructured) bool {
+	return obj.GroupVersionKind().GroupKind() == schema.GroupKind{Group: SealedSecretGroup, Kind: SealedSecretKind}
+}
+
+// GetSealedSecretScope returns the scope of the given SealedSecret.
+func GetSealedSecretScope(obj *unstructured.Unstructured) SealedSecretScope {
+	annotations := obj.GetAnnotations()
+	switch {
+	case annotations[SealedSecretClusterWideAnnotation] == "true":
+		return SealedSecretClusterWideScope
+	case annotations[SealedSecretNamespaceWideAnnotation] == "true":
+		return SealedSecretNamespaceWideScope
+	}
+	return SealedSecretStrictScope
+}
+
+// SealedSecretOwner returns the reference to the SealedSecret the
+// given Secret was decrypted from, if any.
+func SealedSecretOwner(obj *unstructured.Unstructured) *metav1.OwnerReference {
+	if obj.GetAPIVersion() != "v1" || obj.GetKind() != SecretKind {
+		return nil
+	}
+	for _, ref := range obj.GetOwnerReferences() {
+		gv, err := schema.ParseGroupVersion(ref.APIVersion)
+		if err == nil && gv.Group == SealedSecretGroup && ref.Kind == SealedSecretKind {
+			return &ref
+		}
+	}
+	return nil
+}
+
+// CheckEncryptedResource returns an error if federating the given
+// resource would propagate its encrypted payload in clear or in a form
+// that cannot be decrypted in member clusters.
+func CheckEncryptedResource(obj *unstructured.Unstructured) error {
+	qualifiedName := NewQualifiedName(obj)
+	if owner := SealedSecretOwner(obj); owner != nil {
+		return errors.Errorf("%s %q is decrypted from %s %q; the %s should be federated instead so that its payload remains encrypted",
+			obj.GetKind(), qualifiedName, SealedSecretKind, owner.Name, SealedSecretKind)
+	}
+	if IsSOPSEncrypted(obj) && isBuiltInGroup(obj.GroupVersionKind().Group) {
+		return errors.Errorf("%s %q is encrypted by SOPS and cannot be decrypted in member clusters; a type decrypted by a controller of the member clusters (e.g. a SopsSecret or a SealedSecret) should be federated instead",
+			obj.GetKind(), qualifiedName)
+	}
+	return nil
+}
+
+// CheckEncryptedOverrides returns an error if the object for a cluster
+// cannot be decrypted by the cluster, given the paths of the overrides
+// of the cluster, whether the cluster has values or patches and the
+// name of the object in the cluster.
+func CheckEncryptedOverrides(obj *unstructured.Unstructured, overridePaths []string, valuesOrPatches bool, targetName, clusterTargetName QualifiedName) error {
+	switch {
+	case IsSOPSEncrypted(obj):
+		// The MAC of a SOPS document covers its payload, which
+		// therefore cannot vary by cluster.
+		if valuesOrPatches {
+			return errors.New("a resource encrypted by SOPS cannot have values or patches")
+		}
+		for _, path := range overridePaths {
+			if !strings.HasPrefix(path, MetadataField+".") {
+				return errors.Errorf("a resource encrypted by SOPS can only have overrides of its metadata, not of path %q", path)
+			}
+		}
+	case IsSealedSecret(obj):
+		scope := GetSealedSecretScope(obj)
+		if scope == SealedSecretStrictScope && clusterTargetName.Name != targetName.Name {
+			return errors.Errorf("a %s with %s scope cannot be renamed", SealedSecretKind, scope)
+		}
+		if scope != SealedSecretClusterWideScope && clusterTargetName.Namespace != targetName.Namespace {
+			return errors.Errorf("a %s with %s scope cannot be propagated to a mapped namespace", SealedSecretKind, scope)
+		}
+	}
+	return nil
+}
+
+// isBuiltInGroup returns true if the given API group is served by
+// Kubernetes rather than defined by a CRD, in which case the
+// encryption metadata of a SOPS document would not be retained, let
+// alone decrypted.
+func isBuiltInGroup(group string) bool {
+	return !strings.Contains(group, ".") || strings.HasSuffix(group, ".k8s.io")
+}
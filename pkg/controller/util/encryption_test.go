@@ -0,0 +1,162 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newEncryptedObject(apiVersion, kind string, sops bool) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetNamespace("ns")
+	obj.SetName("credentials")
+	if sops {
+		obj.Object[SOPSMetadataField] = map[string]interface{}{
+			"mac":     "ENC[AES256_GCM,data:abc,type:str]",
+			"version": "3.7.3",
+		}
+	}
+	return obj
+}
+
+func TestCheckEncryptedResource(t *testing.T) {
+	decryptedSecret := newEncryptedObject("v1", "Secret", false)
+	decryptedSecret.SetOwnerReferences([]metav1.OwnerReference{{APIVersion: "bitnami.com/v1alpha1", Kind: "SealedSecret", Name: "credentials"}})
+
+	testCases := map[string]struct {
+		obj         *unstructured.Unstructured
+		expectedErr string
+	}{
+		"secret": {
+			obj: newEncryptedObject("v1", "Secret", false),
+		},
+		"sealed secret": {
+			obj: newEncryptedObject("bitnami.com/v1alpha1", "SealedSecret", false),
+		},
+		"sops secret": {
+			obj: newEncryptedObject("isindir.github.com/v1alpha3", "SopsSecret", true),
+		},
+		"secret decrypted from a sealed secret": {
+			obj:         decryptedSecret,
+			expectedErr: `Secret "ns/credentials" is decrypted from SealedSecret "credentials"; the SealedSecret should be federated instead so that its payload remains encrypted`,
+		},
+		"secret encrypted by sops": {
+			obj:         newEncryptedObject("v1", "Secret", true),
+			expectedErr: `Secret "ns/credentials" is encrypted by SOPS and cannot be decrypted in member clusters; a type decrypted by a controller of the member clusters (e.g. a SopsSecret or a SealedSecret) should be federated instead`,
+		},
+		"built-in type encrypted by sops": {
+			obj:         newEncryptedObject("networking.k8s.io/v1", "Ingress", true),
+			expectedErr: `Ingress "ns/credentials" is encrypted by SOPS and cannot be decrypted in member clusters; a type decrypted by a controller of the member clusters (e.g. a SopsSecret or a SealedSecret) should be federated instead`,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := CheckEncryptedResource(tc.obj)
+			if len(tc.expectedErr) == 0 {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedErr)
+			}
+		})
+	}
+}
+
+func TestCheckEncryptedOverrides(t *testing.T) {
+	targetName := QualifiedName{Namespace: "ns", Name: "credentials"}
+	renamed := QualifiedName{Namespace: "ns", Name: "other"}
+	mapped := QualifiedName{Namespace: "ns-east", Name: "credentials"}
+	sealedSecret := func(scopeAnnotation string) *unstructured.Unstructured {
+		obj := newEncryptedObject("bitnami.com/v1alpha1", "SealedSecret", false)
+		if len(scopeAnnotation) > 0 {
+			obj.SetAnnotations(map[string]string{scopeAnnotation: "true"})
+		}
+		return obj
+	}
+	sopsSecret := newEncryptedObject("isindir.github.com/v1alpha3", "SopsSecret", true)
+
+	testCases := map[string]struct {
+		obj               *unstructured.Unstructured
+		paths             []string
+		valuesOrPatches   bool
+		clusterTargetName QualifiedName
+		expectedErr       string
+	}{
+		"unencrypted resource": {
+			obj:               newEncryptedObject("v1", "Secret", false),
+			paths:             []string{"data.password"},
+			valuesOrPatches:   true,
+			clusterTargetName: mapped,
+		},
+		"sops metadata override": {
+			obj:               sopsSecret,
+			paths:             []string{"metadata.labels.region"},
+			clusterTargetName: renamed,
+		},
+		"sops payload override": {
+			obj:               sopsSecret,
+			paths:             []string{"spec.secretTemplates"},
+			clusterTargetName: targetName,
+			expectedErr:       `a resource encrypted by SOPS can only have overrides of its metadata, not of path "spec.secretTemplates"`,
+		},
+		"sops patches": {
+			obj:               sopsSecret,
+			valuesOrPatches:   true,
+			clusterTargetName: targetName,
+			expectedErr:       "a resource encrypted by SOPS cannot have values or patches",
+		},
+		"sealed secret payload override": {
+			obj:               sealedSecret(""),
+			paths:             []string{"spec.encryptedData.password"},
+			clusterTargetName: targetName,
+		},
+		"renamed strict sealed secret": {
+			obj:               sealedSecret(""),
+			clusterTargetName: renamed,
+			expectedErr:       "a SealedSecret with strict scope cannot be renamed",
+		},
+		"renamed namespace-wide sealed secret": {
+			obj:               sealedSecret(SealedSecretNamespaceWideAnnotation),
+			clusterTargetName: renamed,
+		},
+		"mapped namespace-wide sealed secret": {
+			obj:               sealedSecret(SealedSecretNamespaceWideAnnotation),
+			clusterTargetName: mapped,
+			expectedErr:       "a SealedSecret with namespace-wide scope cannot be propagated to a mapped namespace",
+		},
+		"mapped cluster-wide sealed secret": {
+			obj:               sealedSecret(SealedSecretClusterWideAnnotation),
+			clusterTargetName: mapped,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := CheckEncryptedOverrides(tc.obj, tc.paths, tc.valuesOrPatches, targetName, tc.clusterTargetName)
+			if len(tc.expectedErr) == 0 {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+// Metadata fields that are updated by the member cluster api with
+// every change and are never compared by the member event filter.
+var eventFilterIgnoredMetadataFields = []string{"resourceVersion", managedFieldsField}
+
+// NewMemberEventFilter returns a predicate that accepts the updates
+// of resources in member clusters that change more than the fields
+// ignored by the given filter.  Status changes are never ignored if
+// keepStatusChanges is true.  A nil predicate, which accepts all
+// updates, is returned if the filter ignores nothing.
+func NewMemberEventFilter(filter *fedv1b1.MemberEventFilter, keepStatusChanges bool) UpdatePredicate {
+	if filter == nil {
+		return nil
+	}
+	ignoreStatus := filter.IgnoreStatusChanges && !keepStatusChanges
+	if !ignoreStatus && len(filter.IgnoredAnnotations) == 0 {
+		return nil
+	}
+	return func(old, cur pkgruntime.Object) bool {
+		oldObj, ok := old.(*unstructured.Unstructured)
+		if !ok {
+			return true
+		}
+		curObj, ok := cur.(*unstructured.Unstructured)
+		if !ok {
+			return true
+		}
+		return !reflect.DeepEqual(
+			filteredContent(oldObj, ignoreStatus, filter.IgnoredAnnotations),
+			filteredContent(curObj, ignoreStatus, filter.IgnoredAnnotations),
+		)
+	}
+}
+
+// filteredContent returns a copy of the content of the object without
+// the fields ignored by the member event filter.
+func filteredContent(obj *unstructured.Unstructured, ignoreStatus bool, ignoredAnnotations []string) map[string]interface{} {
+	content := obj.DeepCopy().Object
+	if ignoreStatus {
+		delete(content, StatusField)
+	}
+	for _, field := range eventFilterIgnoredMetadataFields {
+		unstructured.RemoveNestedField(content, MetadataField, field)
+	}
+	metadata, _ := content[MetadataField].(map[string]interface{})
+	annotations, ok := metadata["annotations"].(map[string]interface{})
+	if !ok {
+		return content
+	}
+	for _, key := range ignoredAnnotations {
+		delete(annotations, key)
+	}
+	if len(annotations) == 0 {
+		delete(metadata, "annotations")
+	}
+	return content
+}
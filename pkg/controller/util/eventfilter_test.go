@@ -0,0 +1,92 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+func TestMemberEventFilter(t *testing.T) {
+	newObj := func(resourceVersion string, annotations map[string]string, replicas, readyReplicas int64) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+		obj.SetName("d1")
+		obj.SetResourceVersion(resourceVersion)
+		obj.SetAnnotations(annotations)
+		unstructured.SetNestedField(obj.Object, replicas, "spec", "replicas")
+		unstructured.SetNestedField(obj.Object, readyReplicas, "status", "readyReplicas")
+		return obj
+	}
+	heartbeat := "example.com/heartbeat"
+	obj := newObj("1", map[string]string{heartbeat: "1"}, 3, 1)
+
+	testCases := map[string]struct {
+		filter            *fedv1b1.MemberEventFilter
+		keepStatusChanges bool
+		cur               *unstructured.Unstructured
+		accepted          bool
+	}{
+		"status change is accepted without a filter": {
+			cur:      newObj("2", map[string]string{heartbeat: "1"}, 3, 2),
+			accepted: true,
+		},
+		"status change is ignored": {
+			filter: &fedv1b1.MemberEventFilter{IgnoreStatusChanges: true},
+			cur:    newObj("2", map[string]string{heartbeat: "1"}, 3, 2),
+		},
+		"status change is accepted while status is kept": {
+			filter:            &fedv1b1.MemberEventFilter{IgnoreStatusChanges: true},
+			keepStatusChanges: true,
+			cur:               newObj("2", map[string]string{heartbeat: "1"}, 3, 2),
+			accepted:          true,
+		},
+		"spec change is accepted when status changes are ignored": {
+			filter:   &fedv1b1.MemberEventFilter{IgnoreStatusChanges: true},
+			cur:      newObj("2", map[string]string{heartbeat: "1"}, 4, 1),
+			accepted: true,
+		},
+		"ignored annotation change is ignored": {
+			filter: &fedv1b1.MemberEventFilter{IgnoredAnnotations: []string{heartbeat}},
+			cur:    newObj("2", map[string]string{heartbeat: "2"}, 3, 1),
+		},
+		"removal of the last ignored annotation is ignored": {
+			filter: &fedv1b1.MemberEventFilter{IgnoredAnnotations: []string{heartbeat}},
+			cur:    newObj("2", nil, 3, 1),
+		},
+		"other annotation change is accepted": {
+			filter:   &fedv1b1.MemberEventFilter{IgnoredAnnotations: []string{heartbeat}},
+			cur:      newObj("2", map[string]string{heartbeat: "1", "owner": "me"}, 3, 1),
+			accepted: true,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			predicate := NewMemberEventFilter(tc.filter, tc.keepStatusChanges)
+			accepted := predicate == nil || predicate(obj, tc.cur)
+			assert.Equal(t, tc.accepted, accepted)
+		})
+	}
+
+	// The compared objects must not be modified.
+	assert.Equal(t, map[string]string{heartbeat: "1"}, obj.GetAnnotations())
+	assert.Equal(t, "1", obj.GetResourceVersion())
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"sigs.k8s.io/kubefed/pkg/apis/core/typeconfig"
+)
+
+var systemMetadataFields = []string{"selfLink", "uid", "resourceVersion", "generation", "creationTimestamp", "deletionTimestamp", "deletionGracePeriodSeconds"}
+
+// RemoveUnwantedFields removes the fields of a resource that are set
+// by the API server or that are not retained in the template of a
+// federated resource.
+func RemoveUnwantedFields(resource *unstructured.Unstructured) {
+	for _, field := range systemMetadataFields {
+		unstructured.RemoveNestedField(resource.Object, "metadata", field)
+		// For resources with pod template subresource (jobs, deployments, replicasets)
+		unstructured.RemoveNestedField(resource.Object, "spec", "template", "metadata", field)
+	}
+	unstructured.RemoveNestedField(resource.Object, "metadata", "name")
+	unstructured.RemoveNestedField(resource.Object, "metadata", "namespace")
+	unstructured.RemoveNestedField(resource.Object, "apiVersion")
+	unstructured.RemoveNestedField(resource.Object, "kind")
+	unstructured.RemoveNestedField(resource.Object, "status")
+}
+
+// SetBasicMetaFields sets the type, name and namespace of a resource
+// of the given API resource.
+func SetBasicMetaFields(resource *unstructured.Unstructured, apiResource metav1.APIResource, name, namespace, generateName string) {
+	resource.SetKind(apiResource.Kind)
+	gv := schema.GroupVersion{Group: apiResource.Group, Version: apiResource.Version}
+	resource.SetAPIVersion(gv.String())
+	resource.SetName(name)
+	if generateName != "" {
+		resource.SetGenerateName(generateName)
+	}
+	if apiResource.Namespaced {
+		resource.SetNamespace(namespace)
+	}
+}
+
+// FederatedResourceFromTargetResource returns a federated resource of
+// the given type whose template is the given target resource, with the
+// fields set by the API server and by controllers removed.
+func FederatedResourceFromTargetResource(typeConfig typeconfig.Interface, resource *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	// The payload of an encrypted resource is propagated as is, to be
+	// decrypted by the controllers of member clusters.
+	if err := CheckEncryptedResource(resource); err != nil {
+		return nil, err
+	}
+
+	fedAPIResource := typeConfig.GetFederatedType()
+	targetResource := resource.DeepCopy()
+
+	// Special handling is needed for some controller set fields.
+	switch typeConfig.GetTargetType().Kind {
+	case NamespaceKind:
+		{
+			unstructured.RemoveNestedField(targetResource.Object, "spec", "finalizers")
+		}
+	case ServiceAccountKind:
+		{
+			unstructured.RemoveNestedField(targetResource.Object, SecretsField)
+		}
+	case ServiceKind:
+		{
+			var targetPorts []interface{}
+			targetPorts, ok, err := unstructured.NestedSlice(targetResource.Object, "spec", "ports")
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				for index := range targetPorts {
+					port := targetPorts[index].(map[string]interface{})
+					delete(port, "nodePort")
+					targetPorts[index] = port
+				}
+				err := unstructured.SetNestedSlice(targetResource.Object, targetPorts, "spec", "ports")
+				if err != nil {
+					return nil, err
+				}
+			}
+			unstructured.RemoveNestedField(targetResource.Object, "spec", "clusterIP")
+		}
+	}
+
+	qualifiedName := NewQualifiedName(targetResource)
+	resourceNamespace := federatedNamespace(typeConfig, qualifiedName)
+	fedResource := &unstructured.Unstructured{}
+	SetBasicMetaFields(fedResource, fedAPIResource, qualifiedName.Name, resourceNamespace, "")
+	RemoveUnwantedFields(targetResource)
+	if typeconfig.IsGenericFederatedType(typeConfig) {
+		// The template of a generic federated resource identifies
+		// its target type.
+		targetResource.SetAPIVersion(resource.GetAPIVersion())
+		targetResource.SetKind(resource.GetKind())
+	}
+
+	err := unstructured.SetNestedField(fedResource.Object, targetResource.Object, SpecField, TemplateField)
+	if err != nil {
+		return nil, err
+	}
+	err = unstructured.SetNestedStringMap(fedResource.Object, map[string]string{}, SpecField, PlacementField, ClusterSelectorField, MatchLabelsField)
+	if err != nil {
+		return nil, err
+	}
+
+	return fedResource, err
+}
+
+// federatedNamespace returns the namespace of the federated resource
+// for the target resource with the given name.  A federated namespace
+// is contained in the namespace it federates.
+func federatedNamespace(typeConfig typeconfig.Interface, qualifiedName QualifiedName) string {
+	if typeConfig.GetTargetType().Kind == NamespaceKind {
+		return qualifiedName.Name
+	}
+	return qualifiedName.Namespace
+}
@@ -17,7 +17,9 @@ limitations under the License.
 package util
 
 import (
+	"encoding/json"
 	"fmt"
+	"hash/fnv"
 	"reflect"
 	"sync"
 	"time"
@@ -26,7 +28,9 @@ import (
 
 	apiv1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/discovery"
 	restclient "k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog"
@@ -34,6 +38,7 @@ import (
 	fedcommon "sigs.k8s.io/kubefed/pkg/apis/core/common"
 	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
 	"sigs.k8s.io/kubefed/pkg/client/generic"
+	"sigs.k8s.io/kubefed/pkg/controller/util/apidiscovery"
 )
 
 const (
@@ -59,6 +64,8 @@ type FederatedReadOnlyStore interface {
 	GetKeyFor(item interface{}) string
 
 	// GetByKey returns the item stored under the given key in the specified cluster (if exist).
+	// The key is that of the resource in the host cluster and is translated according to the
+	// namespace mappings of the cluster.
 	GetByKey(clusterName string, key string) (interface{}, bool, error)
 
 	// Returns the items stored under the given key in all clusters.
@@ -71,6 +78,10 @@ type FederatedReadOnlyStore interface {
 	// that there may be significant delays in content updates of all kinds and write their
 	// code that it doesn't break if something is slightly out-of-sync.
 	ClustersSynced(clusters []*fedv1b1.KubeFedCluster) bool
+
+	// ClusterSynced checks whether the store of the given cluster is
+	// there and synced, regardless of the stores of other clusters.
+	ClusterSynced(clusterName string) bool
 }
 
 // An interface to retrieve both KubeFedCluster resources and clients
@@ -93,6 +104,10 @@ type RegisteredClustersView interface {
 
 	// ClustersSynced returns true if the view is synced (for the first time).
 	ClustersSynced() bool
+
+	// TargetVersionForCluster returns the version of the target type
+	// that is used for the cluster.
+	TargetVersionForCluster(clusterName string) (string, error)
 }
 
 // FederatedInformer provides access to clusters registered with a
@@ -138,6 +153,8 @@ type ClusterLifecycleHandlerFuncs struct {
 	// Fired when the cluster becomes unavailable. The second arg contains data that was present
 	// in the cluster before deletion.
 	ClusterUnavailable func(*fedv1b1.KubeFedCluster, []interface{})
+	// Fired when the labels of an available cluster change.
+	ClusterLabelsChanged func(*fedv1b1.KubeFedCluster)
 }
 
 // Builds a FederatedInformer for the given configuration.
@@ -148,26 +165,82 @@ func NewFederatedInformer(
 	triggerFunc func(pkgruntime.Object),
 	clusterLifecycle *ClusterLifecycleHandlerFuncs) (FederatedInformer, error) {
 
+	return NewFederatedInformerForVersions(config, client, apiResource, nil, triggerFunc, nil, clusterLifecycle)
+}
+
+// Builds a FederatedInformer for a target type that may be served
+// with any of the given versions, in order of preference.  The version
+// used for each cluster is negotiated when its client is first
+// created.  Updates of resources in member clusters only trigger the
+// given function if they are accepted by the predicate, if any.
+func NewFederatedInformerForVersions(
+	config *ControllerConfig,
+	client generic.Client,
+	apiResource *metav1.APIResource,
+	versions []string,
+	triggerFunc func(pkgruntime.Object),
+	updatePredicate UpdatePredicate,
+	clusterLifecycle *ClusterLifecycleHandlerFuncs) (FederatedInformer, error) {
+
+	targetIsNamespace := apiResource.Kind == NamespaceKind
 	targetInformerFactory := func(cluster *fedv1b1.KubeFedCluster, client ResourceClient) (cache.Store, cache.Controller) {
-		return NewManagedResourceInformer(client, config.TargetNamespace, triggerFunc)
+		mappings := cluster.Spec.NamespaceMappings
+		namespace := ClusterNamespace(mappings, config.TargetNamespace)
+		return NewSharedManagedResourceInformer(config.MemberInformers, cluster.Name, clusterFingerprint(cluster), client, namespace,
+			hostTriggerFunc(mappings, targetIsNamespace, triggerFunc), updatePredicate)
 	}
+	return newFederatedInformer(config, client, apiResource, versions, targetInformerFactory, clusterLifecycle)
+}
+
+// Builds a FederatedInformer that creates the informers of registered
+// clusters with the given factory.  Unlike the informers created by
+// NewFederatedInformer, the informers are not limited to resources
+// managed by KubeFed.
+func NewFederatedInformerWithFactory(
+	config *ControllerConfig,
+	client generic.Client,
+	apiResource *metav1.APIResource,
+	targetInformerFactory TargetInformerFactory,
+	clusterLifecycle *ClusterLifecycleHandlerFuncs) (FederatedInformer, error) {
+
+	return newFederatedInformer(config, client, apiResource, nil, targetInformerFactory, clusterLifecycle)
+}
 
+func newFederatedInformer(
+	config *ControllerConfig,
+	client generic.Client,
+	apiResource *metav1.APIResource,
+	versions []string,
+	targetInformerFactory TargetInformerFactory,
+	clusterLifecycle *ClusterLifecycleHandlerFuncs) (FederatedInformer, error) {
+
+	targetIsNamespace := apiResource.Kind == NamespaceKind
 	federatedInformer := &federatedInformerImpl{
 		targetInformerFactory: targetInformerFactory,
-		clientFactory: func(cluster *fedv1b1.KubeFedCluster) (ResourceClient, error) {
-			config, err := BuildClusterConfig(cluster, client, config.KubeFedNamespace)
-			if err != nil {
-				return nil, err
-			}
-			if config == nil {
-				return nil, errors.Errorf("Unable to load configuration for cluster %q", cluster.Name)
-			}
+		targetInformers:       make(map[string]informer),
+		fedNamespace:          config.KubeFedNamespace,
+		targetIsNamespace:     targetIsNamespace,
+		apiResource:           *apiResource,
+		targetVersions:        versions,
+		clusterVersions:       make(map[string]string),
+		apiDiscovery:          config.APIDiscovery,
+	}
+	federatedInformer.clientFactory = func(cluster *fedv1b1.KubeFedCluster) (ResourceClient, error) {
+		config, err := BuildClusterConfig(cluster, config.KubeConfig, client, config.KubeFedNamespace)
+		if err != nil {
+			return nil, err
+		}
+		if config == nil {
+			return nil, errors.Errorf("Unable to load configuration for cluster %q", cluster.Name)
+		}
 
-			restclient.AddUserAgent(config, userAgentName)
-			return NewResourceClient(config, apiResource)
-		},
-		targetInformers: make(map[string]informer),
-		fedNamespace:    config.KubeFedNamespace,
+		restclient.AddUserAgent(config, userAgentName)
+		clusterAPIResource := *apiResource
+		clusterAPIResource.Version, err = federatedInformer.negotiateTargetVersion(cluster.Name, config)
+		if err != nil {
+			return nil, err
+		}
+		return NewResourceClient(config, &clusterAPIResource)
 	}
 
 	getClusterData := func(name string) []interface{} {
@@ -224,7 +297,7 @@ func NewFederatedInformer(
 					klog.Errorf("Internal error: Cluster %v not updated.  New cluster not of correct type.", cur)
 					return
 				}
-				if IsClusterReady(&oldCluster.Status) != IsClusterReady(&curCluster.Status) || !reflect.DeepEqual(oldCluster.Spec, curCluster.Spec) || !reflect.DeepEqual(oldCluster.ObjectMeta.Annotations, curCluster.ObjectMeta.Annotations) {
+				if IsClusterReady(&oldCluster.Status) != IsClusterReady(&curCluster.Status) || ActiveAPIEndpoint(oldCluster) != ActiveAPIEndpoint(curCluster) || !reflect.DeepEqual(oldCluster.Spec, curCluster.Spec) || !reflect.DeepEqual(oldCluster.ObjectMeta.Annotations, curCluster.ObjectMeta.Annotations) || ClusterFieldsChanged(oldCluster, curCluster) || federatedInformer.targetVersionsMayHaveChanged(oldCluster, curCluster) {
 					var data []interface{}
 					if clusterLifecycle.ClusterUnavailable != nil {
 						data = getClusterData(oldCluster.Name)
@@ -240,6 +313,12 @@ func NewFederatedInformer(
 							clusterLifecycle.ClusterAvailable(curCluster)
 						}
 					}
+				} else if !reflect.DeepEqual(oldCluster.ObjectMeta.Labels, curCluster.ObjectMeta.Labels) {
+					// The informers of the cluster are unaffected by
+					// its labels and are not restarted.
+					if IsClusterReady(&curCluster.Status) && clusterLifecycle.ClusterLabelsChanged != nil {
+						clusterLifecycle.ClusterLabelsChanged(curCluster)
+					}
 				} else {
 					klog.V(7).Infof("Cluster %v not updated to %v as ready status and specs are identical", oldCluster, curCluster)
 				}
@@ -249,6 +328,35 @@ func NewFederatedInformer(
 	return federatedInformer, err
 }
 
+// hostTriggerFunc ensures that the trigger function is invoked with
+// the host cluster name of resources that have been propagated to a
+// mapped namespace of a member cluster or renamed by an override.
+func hostTriggerFunc(mappings []fedv1b1.NamespaceMapping, targetIsNamespace bool, triggerFunc func(pkgruntime.Object)) func(pkgruntime.Object) {
+	return func(obj pkgruntime.Object) {
+		hostName := HostQualifiedNameForObject(mappings, targetIsNamespace, obj)
+		if hostName == NewQualifiedName(obj) {
+			triggerFunc(obj)
+			return
+		}
+		hostObj := &unstructured.Unstructured{}
+		hostObj.SetNamespace(hostName.Namespace)
+		hostObj.SetName(hostName.Name)
+		triggerFunc(hostObj)
+	}
+}
+
+// clusterFingerprint identifies the fields of the cluster that a
+// client for the cluster is created from, so that informers created
+// for a previous configuration of the cluster are not shared.
+func clusterFingerprint(cluster *fedv1b1.KubeFedCluster) string {
+	hash := fnv.New64a()
+	fmt.Fprintf(hash, "%s\n%s\n", cluster.UID, ActiveAPIEndpoint(cluster))
+	if spec, err := json.Marshal(cluster.Spec); err == nil {
+		hash.Write(spec)
+	}
+	return fmt.Sprintf("%x", hash.Sum64())
+}
+
 func IsClusterReady(clusterStatus *fedv1b1.KubeFedClusterStatus) bool {
 	for _, condition := range clusterStatus.Conditions {
 		if condition.Type == fedcommon.ClusterReady {
@@ -264,6 +372,9 @@ type informer struct {
 	controller cache.Controller
 	store      cache.Store
 	stopChan   chan struct{}
+
+	// Namespace mappings of the cluster a target informer watches.
+	namespaceMappings []fedv1b1.NamespaceMapping
 }
 
 type federatedInformerImpl struct {
@@ -283,6 +394,23 @@ type federatedInformerImpl struct {
 
 	// Namespace from which to source KubeFedCluster resources
 	fedNamespace string
+
+	// Whether the target type is a namespace.  Namespace mappings
+	// rename namespaces rather than relocating resources.
+	targetIsNamespace bool
+
+	// The target type and the versions of it that are acceptable, in
+	// order of preference.
+	apiResource    metav1.APIResource
+	targetVersions []string
+
+	// The versions of the target type negotiated for clusters.
+	clusterVersions map[string]string
+
+	// The API resources discovered in clusters by the cluster
+	// controller, used in preference to discovery requests when
+	// negotiating the version of the target type.
+	apiDiscovery *apidiscovery.Cache
 }
 
 // *federatedInformerImpl implements FederatedInformer interface.
@@ -345,6 +473,71 @@ func (f *federatedInformerImpl) getClientForClusterUnlocked(clusterName string)
 	return nil, errors.Errorf("cluster %q not found", clusterName)
 }
 
+// TargetVersionForCluster returns the version of the target type that
+// is used for the cluster, negotiating it if necessary.
+func (f *federatedInformerImpl) TargetVersionForCluster(clusterName string) (string, error) {
+	f.Lock()
+	defer f.Unlock()
+	if len(f.targetVersions) < 2 {
+		return f.apiResource.Version, nil
+	}
+	if version, ok := f.clusterVersions[clusterName]; ok {
+		return version, nil
+	}
+	// Creating a client for the cluster negotiates the version.
+	if _, err := f.getClientForClusterUnlocked(clusterName); err != nil {
+		return "", err
+	}
+	version, ok := f.clusterVersions[clusterName]
+	if !ok {
+		// A client factory set for testing does not negotiate.
+		return f.apiResource.Version, nil
+	}
+	return version, nil
+}
+
+// negotiateTargetVersion returns the version of the target type to use
+// for the cluster, determining the versions served by the cluster the
+// first time it is called for the cluster.  The API resources cached
+// by the cluster controller are used if the cluster has been
+// discovered, and a discovery request is made otherwise.  The lock
+// must be held.
+func (f *federatedInformerImpl) negotiateTargetVersion(clusterName string, config *restclient.Config) (string, error) {
+	if len(f.targetVersions) < 2 {
+		return f.apiResource.Version, nil
+	}
+	if version, ok := f.clusterVersions[clusterName]; ok {
+		return version, nil
+	}
+	var client GroupVersionResourcesGetter
+	if resources, ok := f.apiDiscovery.Cluster(clusterName); ok {
+		client = resources
+	} else {
+		discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+		if err != nil {
+			return "", errors.Wrapf(err, "Failed to create a discovery client for cluster %q", clusterName)
+		}
+		client = discoveryClient
+	}
+	version, err := NegotiateTargetVersion(client, f.apiResource, f.targetVersions)
+	if err != nil {
+		return "", errors.Wrapf(err, "Failed to negotiate the version of %q for cluster %q", f.apiResource.Name, clusterName)
+	}
+	if version != f.apiResource.Version {
+		klog.Infof("Using version %q of %q for cluster %q", version, f.apiResource.Name, clusterName)
+	}
+	f.clusterVersions[clusterName] = version
+	return version, nil
+}
+
+// targetVersionsMayHaveChanged indicates whether the versions of the
+// target type served by a cluster may have changed with an upgrade of
+// the cluster, in which case the cluster is added again so that the
+// version is negotiated again.
+func (f *federatedInformerImpl) targetVersionsMayHaveChanged(oldCluster, curCluster *fedv1b1.KubeFedCluster) bool {
+	return len(f.targetVersions) > 1 && oldCluster.Status.KubernetesVersion != curCluster.Status.KubernetesVersion
+}
+
 func (f *federatedInformerImpl) GetUnreadyClusters() ([]*fedv1b1.KubeFedCluster, error) {
 	f.Lock()
 	defer f.Unlock()
@@ -429,9 +622,10 @@ func (f *federatedInformerImpl) addCluster(cluster *fedv1b1.KubeFedCluster) {
 	if client, err := f.getClientForClusterUnlocked(name); err == nil {
 		store, controller := f.targetInformerFactory(cluster, client)
 		targetInformer := informer{
-			controller: controller,
-			store:      store,
-			stopChan:   make(chan struct{}),
+			controller:        controller,
+			store:             store,
+			stopChan:          make(chan struct{}),
+			namespaceMappings: cluster.Spec.NamespaceMappings,
 		}
 		f.targetInformers[name] = targetInformer
 		go targetInformer.controller.Run(targetInformer.stopChan)
@@ -450,6 +644,9 @@ func (f *federatedInformerImpl) deleteCluster(cluster *fedv1b1.KubeFedCluster) {
 		close(targetInformer.stopChan)
 	}
 	delete(f.targetInformers, name)
+	// The version is negotiated again when the cluster is added, in
+	// case the versions it serves have changed.
+	delete(f.clusterVersions, name)
 }
 
 // Returns a store created over all stores from target informers.
@@ -491,7 +688,7 @@ func (fs *federatedStoreImpl) GetByKey(clusterName string, key string) (interfac
 	fs.federatedInformer.Lock()
 	defer fs.federatedInformer.Unlock()
 	if targetInformer, found := fs.federatedInformer.targetInformers[clusterName]; found {
-		return targetInformer.store.GetByKey(key)
+		return targetInformer.store.GetByKey(fs.clusterKey(targetInformer, key))
 	}
 	return nil, false, nil
 }
@@ -503,7 +700,7 @@ func (fs *federatedStoreImpl) GetFromAllClusters(key string) ([]FederatedObject,
 
 	result := make([]FederatedObject, 0)
 	for clusterName, targetInformer := range fs.federatedInformer.targetInformers {
-		value, exist, err := targetInformer.store.GetByKey(key)
+		value, exist, err := targetInformer.store.GetByKey(fs.clusterKey(targetInformer, key))
 		if err != nil {
 			return nil, err
 		}
@@ -514,6 +711,12 @@ func (fs *federatedStoreImpl) GetFromAllClusters(key string) ([]FederatedObject,
 	return result, nil
 }
 
+// clusterKey returns the key under which the item with the given host
+// cluster key is stored by the target informer.
+func (fs *federatedStoreImpl) clusterKey(targetInformer informer, key string) string {
+	return clusterKey(targetInformer.namespaceMappings, fs.federatedInformer.targetIsNamespace, key)
+}
+
 // GetKeyFor returns the key under which the item would be put in the store.
 func (fs *federatedStoreImpl) GetKeyFor(item interface{}) string {
 	// TODO: support other keying functions.
@@ -554,3 +757,10 @@ func (fs *federatedStoreImpl) ClustersSynced(clusters []*fedv1b1.KubeFedCluster)
 	}
 	return true
 }
+
+func (fs *federatedStoreImpl) ClusterSynced(clusterName string) bool {
+	fs.federatedInformer.Lock()
+	targetInformer, found := fs.federatedInformer.targetInformers[clusterName]
+	fs.federatedInformer.Unlock()
+	return found && targetInformer.controller.HasSynced()
+}
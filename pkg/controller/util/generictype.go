@@ -0,0 +1,45 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// TemplateTargetsType indicates whether the template of the given
+// generic federated resource (i.e. a FederatedObject) is an object of
+// the given target type.  The template of a generic federated
+// resource retains its apiVersion and kind so that the resources of a
+// single generic federated type can be apportioned between the type
+// configs that select it.  The version of the template is not
+// considered since the object will be propagated with the version of
+// the target type.
+func TemplateTargetsType(fedObj *unstructured.Unstructured, targetType metav1.APIResource) bool {
+	template, ok, err := GetTemplate(fedObj)
+	if err != nil || !ok {
+		return false
+	}
+	apiVersion, _, _ := unstructured.NestedString(template, "apiVersion")
+	kind, _, _ := unstructured.NestedString(template, "kind")
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil || len(apiVersion) == 0 {
+		return false
+	}
+	return kind == targetType.Kind && gv.Group == targetType.Group
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestTemplateTargetsType(t *testing.T) {
+	deploymentType := metav1.APIResource{Group: "apps", Version: "v1", Kind: "Deployment"}
+	configMapType := metav1.APIResource{Version: "v1", Kind: "ConfigMap"}
+
+	newFedObj := func(template map[string]interface{}) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+		if template != nil {
+			obj.Object[SpecField] = map[string]interface{}{TemplateField: template}
+		}
+		return obj
+	}
+
+	testCases := map[string]struct {
+		template   map[string]interface{}
+		targetType metav1.APIResource
+		expected   bool
+	}{
+		"Template of the target type": {
+			template:   map[string]interface{}{"apiVersion": "apps/v1", "kind": "Deployment"},
+			targetType: deploymentType,
+			expected:   true,
+		},
+		"Template of another version of the target type": {
+			template:   map[string]interface{}{"apiVersion": "apps/v1beta2", "kind": "Deployment"},
+			targetType: deploymentType,
+			expected:   true,
+		},
+		"Template of a core type": {
+			template:   map[string]interface{}{"apiVersion": "v1", "kind": "ConfigMap"},
+			targetType: configMapType,
+			expected:   true,
+		},
+		"Template of the same kind in another group": {
+			template:   map[string]interface{}{"apiVersion": "extensions/v1beta1", "kind": "Deployment"},
+			targetType: deploymentType,
+			expected:   false,
+		},
+		"Template of another kind": {
+			template:   map[string]interface{}{"apiVersion": "v1", "kind": "Secret"},
+			targetType: configMapType,
+			expected:   false,
+		},
+		"Template without an apiVersion": {
+			template:   map[string]interface{}{"kind": "ConfigMap"},
+			targetType: configMapType,
+			expected:   false,
+		},
+		"Missing template": {
+			targetType: configMapType,
+			expected:   false,
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			assert.Equal(t, tc.expected, TemplateTargetsType(newFedObj(tc.template), tc.targetType))
+		})
+	}
+}
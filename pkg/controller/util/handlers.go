@@ -23,9 +23,20 @@ import (
 	"k8s.io/client-go/tools/cache"
 )
 
+// UpdatePredicate reports whether an update of an object from old to
+// cur should trigger processing of the object.
+type UpdatePredicate func(old, cur pkgruntime.Object) bool
+
 // Returns cache.ResourceEventHandlerFuncs that trigger the given function
 // on all object changes.
 func NewTriggerOnAllChanges(triggerFunc func(pkgruntime.Object)) *cache.ResourceEventHandlerFuncs {
+	return NewTriggerOnChanges(triggerFunc, nil)
+}
+
+// Returns cache.ResourceEventHandlerFuncs that trigger the given function
+// on object additions and deletions, and on the updates accepted by the
+// predicate.  A nil predicate accepts all updates.
+func NewTriggerOnChanges(triggerFunc func(pkgruntime.Object), updatePredicate UpdatePredicate) *cache.ResourceEventHandlerFuncs {
 	return &cache.ResourceEventHandlerFuncs{
 		DeleteFunc: func(old interface{}) {
 			if deleted, ok := old.(cache.DeletedFinalStateUnknown); ok {
@@ -44,7 +55,10 @@ func NewTriggerOnAllChanges(triggerFunc func(pkgruntime.Object)) *cache.Resource
 		},
 		UpdateFunc: func(old, cur interface{}) {
 			curObj := cur.(pkgruntime.Object)
-			if !reflect.DeepEqual(old, cur) {
+			if reflect.DeepEqual(old, cur) {
+				return
+			}
+			if updatePredicate == nil || updatePredicate(old.(pkgruntime.Object), curObj) {
 				triggerFunc(curObj)
 			}
 		},
@@ -82,3 +82,30 @@ func TestHandlers(t *testing.T) {
 	trigger.OnUpdate(&service, &service2)
 	assert.True(t, triggered())
 }
+
+func TestTriggerOnChangesWithPredicate(t *testing.T) {
+	service := apiv1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "s1"}}
+	service2 := apiv1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "s1", ResourceVersion: "2"}}
+	triggerCount := 0
+	accept := false
+	trigger := NewTriggerOnChanges(
+		func(obj pkgruntime.Object) {
+			triggerCount++
+		},
+		func(old, cur pkgruntime.Object) bool {
+			return accept
+		})
+
+	trigger.OnAdd(&service)
+	assert.Equal(t, 1, triggerCount)
+	trigger.OnUpdate(&service, &service2)
+	assert.Equal(t, 1, triggerCount)
+	accept = true
+	trigger.OnUpdate(&service, &service)
+	assert.Equal(t, 1, triggerCount)
+	trigger.OnUpdate(&service, &service2)
+	assert.Equal(t, 2, triggerCount)
+	accept = false
+	trigger.OnDelete(&service2)
+	assert.Equal(t, 3, triggerCount)
+}
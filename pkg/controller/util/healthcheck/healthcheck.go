@@ -0,0 +1,173 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package healthcheck
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"k8s.io/klog"
+)
+
+// The time allowed for a request to an HTTP health endpoint.
+const httpCheckTimeout = 5 * time.Second
+
+// Check returns an error if the component it checks is not healthy.
+type Check func() error
+
+type namedCheck struct {
+	name     string
+	liveness bool
+	check    Check
+}
+
+// Registry aggregates the health checks of the KubeFed controllers
+// for the liveness and readiness endpoints of the controller
+// manager.  Liveness checks are also included in readiness so that
+// a replica that would be restarted is never reported ready.  Checks
+// can be added after the endpoints are serving since the controllers
+// are only started once leadership is acquired.  A nil *Registry is
+// valid and discards all checks.
+type Registry struct {
+	lock   sync.RWMutex
+	checks []namedCheck
+}
+
+// New returns an empty registry.
+func New() *Registry {
+	return &Registry{}
+}
+
+// AddLivenessCheck adds a check that should cause the controller
+// manager to be restarted if it fails.  A check previously added
+// with the same name is replaced.
+func (r *Registry) AddLivenessCheck(name string, check Check) {
+	r.add(namedCheck{name: name, liveness: true, check: check})
+}
+
+// AddReadinessCheck adds a check that should only cause the
+// controller manager to be reported as not ready if it fails.  A
+// check previously added with the same name is replaced.
+func (r *Registry) AddReadinessCheck(name string, check Check) {
+	r.add(namedCheck{name: name, check: check})
+}
+
+func (r *Registry) add(check namedCheck) {
+	if r == nil {
+		return
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	for i := range r.checks {
+		if r.checks[i].name == check.name {
+			r.checks[i] = check
+			return
+		}
+	}
+	r.checks = append(r.checks, check)
+}
+
+// LivenessHandler returns the handler serving the result of the
+// liveness checks.
+func (r *Registry) LivenessHandler() http.Handler {
+	return r.handler("healthz", true)
+}
+
+// ReadinessHandler returns the handler serving the result of all
+// checks.
+func (r *Registry) ReadinessHandler() http.Handler {
+	return r.handler("readyz", false)
+}
+
+// handler serves the result of the selected checks in the format of
+// the health endpoints of the Kubernetes API server: "ok" if all
+// checks pass, or the result of every check with a status of 500 if
+// any fail or if the verbose query parameter is set.
+func (r *Registry) handler(endpoint string, livenessOnly bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		failed := false
+		var output bytes.Buffer
+		for _, c := range r.selectChecks(livenessOnly) {
+			if err := c.check(); err != nil {
+				klog.V(2).Infof("%s check %q failed: %v", endpoint, c.name, err)
+				fmt.Fprintf(&output, "[-]%s failed: %v\n", c.name, err)
+				failed = true
+			} else {
+				fmt.Fprintf(&output, "[+]%s ok\n", c.name)
+			}
+		}
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		if failed {
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprintf(&output, "%s check failed\n", endpoint)
+			_, _ = output.WriteTo(w)
+			return
+		}
+		if _, verbose := req.URL.Query()["verbose"]; verbose {
+			fmt.Fprintf(&output, "%s check passed\n", endpoint)
+			_, _ = output.WriteTo(w)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	})
+}
+
+func (r *Registry) selectChecks(livenessOnly bool) []namedCheck {
+	if r == nil {
+		return nil
+	}
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	checks := []namedCheck{}
+	for _, c := range r.checks {
+		if c.liveness || !livenessOnly {
+			checks = append(checks, c)
+		}
+	}
+	return checks
+}
+
+// HTTPGetCheck returns a check that passes if a GET of the given url
+// returns a status of 200.  The serving certificate is not verified
+// since only the status is of interest and no credentials are sent.
+func HTTPGetCheck(url string) Check {
+	client := &http.Client{
+		Timeout: httpCheckTimeout,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+	}
+	return func() error {
+		resp, err := client.Get(url)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return errors.Errorf("%s returned status %d", url, resp.StatusCode)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package healthcheck
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func get(handler http.Handler, path string) (int, string) {
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", path, nil))
+	return recorder.Code, recorder.Body.String()
+}
+
+func TestNilRegistry(t *testing.T) {
+	var r *Registry
+	r.AddLivenessCheck("check", func() error { return errors.New("failed") })
+
+	code, body := get(r.LivenessHandler(), "/healthz")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "ok", body)
+}
+
+func TestHandlers(t *testing.T) {
+	var readinessErr error
+	r := New()
+	r.AddLivenessCheck("controller", func() error { return nil })
+	r.AddReadinessCheck("webhook", func() error { return readinessErr })
+
+	code, body := get(r.ReadinessHandler(), "/readyz")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "ok", body)
+
+	code, body = get(r.ReadinessHandler(), "/readyz?verbose")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "[+]controller ok\n[+]webhook ok\nreadyz check passed\n", body)
+
+	readinessErr = errors.New("connection refused")
+	code, body = get(r.ReadinessHandler(), "/readyz")
+	assert.Equal(t, http.StatusInternalServerError, code)
+	assert.Equal(t, "[+]controller ok\n[-]webhook failed: connection refused\nreadyz check failed\n", body)
+
+	// A failed readiness check does not affect liveness.
+	code, body = get(r.LivenessHandler(), "/healthz?verbose")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "[+]controller ok\nhealthz check passed\n", body)
+
+	// Adding a check with an existing name replaces it.
+	r.AddLivenessCheck("controller", func() error { return errors.New("wedged") })
+	code, body = get(r.LivenessHandler(), "/healthz")
+	assert.Equal(t, http.StatusInternalServerError, code)
+	assert.Equal(t, "[-]controller failed: wedged\nhealthz check failed\n", body)
+}
+
+func TestHTTPGetCheck(t *testing.T) {
+	status := http.StatusOK
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	check := HTTPGetCheck(server.URL + "/healthz")
+	assert.NoError(t, check())
+
+	status = http.StatusServiceUnavailable
+	assert.Error(t, check())
+
+	server.Close()
+	assert.Error(t, check())
+}
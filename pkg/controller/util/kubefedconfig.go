@@ -0,0 +1,170 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+// MergeKubeFedConfigSpec returns a copy of the given spec in which the
+// fields that are not set are sourced from the default spec.  Feature
+// gates are merged by name, and notifications, scheduling,
+// diagnostics, propagation metadata, cluster lifecycle hooks and the
+// allowlist of cluster-scoped resources are sourced from the default
+// spec only if not set at all.
+func MergeKubeFedConfigSpec(spec, defaultSpec *fedv1b1.KubeFedConfigSpec) *fedv1b1.KubeFedConfigSpec {
+	merged := spec.DeepCopy()
+	defaults := defaultSpec.DeepCopy()
+
+	if len(merged.Scope) == 0 {
+		merged.Scope = defaults.Scope
+	}
+	if merged.AllowedClusterScopedResources == nil {
+		merged.AllowedClusterScopedResources = defaults.AllowedClusterScopedResources
+	}
+
+	duration := &merged.ControllerDuration
+	mergeDuration(&duration.AvailableDelay, defaults.ControllerDuration.AvailableDelay)
+	mergeDuration(&duration.UnavailableDelay, defaults.ControllerDuration.UnavailableDelay)
+	mergeDuration(&duration.StatusUpdateInterval, defaults.ControllerDuration.StatusUpdateInterval)
+	if duration.MemberReadCacheTTL == nil {
+		duration.MemberReadCacheTTL = defaults.ControllerDuration.MemberReadCacheTTL
+	}
+
+	election := &merged.LeaderElect
+	if len(election.ResourceLock) == 0 {
+		election.ResourceLock = defaults.LeaderElect.ResourceLock
+	}
+	mergeDuration(&election.RetryPeriod, defaults.LeaderElect.RetryPeriod)
+	mergeDuration(&election.RenewDeadline, defaults.LeaderElect.RenewDeadline)
+	mergeDuration(&election.LeaseDuration, defaults.LeaderElect.LeaseDuration)
+
+	healthCheck := &merged.ClusterHealthCheck
+	mergeInt64(&healthCheck.PeriodSeconds, defaults.ClusterHealthCheck.PeriodSeconds)
+	mergeInt64(&healthCheck.TimeoutSeconds, defaults.ClusterHealthCheck.TimeoutSeconds)
+	mergeInt64(&healthCheck.FailureThreshold, defaults.ClusterHealthCheck.FailureThreshold)
+	mergeInt64(&healthCheck.SuccessThreshold, defaults.ClusterHealthCheck.SuccessThreshold)
+	if merged.ClusterLifecycleHooks == nil {
+		merged.ClusterLifecycleHooks = defaults.ClusterLifecycleHooks
+	}
+
+	if len(merged.SyncController.AdoptResources) == 0 {
+		merged.SyncController.AdoptResources = defaults.SyncController.AdoptResources
+	}
+	if merged.SyncController.PropagationMetadata == nil {
+		merged.SyncController.PropagationMetadata = defaults.SyncController.PropagationMetadata
+	}
+	if merged.SyncController.NamespaceMetadata == nil {
+		merged.SyncController.NamespaceMetadata = defaults.SyncController.NamespaceMetadata
+	}
+	if merged.SyncController.DeletionLimit == nil {
+		merged.SyncController.DeletionLimit = defaults.SyncController.DeletionLimit
+	}
+	if merged.SyncController.CircuitBreaker == nil {
+		merged.SyncController.CircuitBreaker = defaults.SyncController.CircuitBreaker
+	}
+	if merged.SyncController.DispatchConcurrency == nil {
+		merged.SyncController.DispatchConcurrency = defaults.SyncController.DispatchConcurrency
+	}
+	if merged.Notifications == nil {
+		merged.Notifications = defaults.Notifications
+	}
+	if merged.Scheduling == nil {
+		merged.Scheduling = defaults.Scheduling
+	}
+	if merged.Diagnostics == nil {
+		merged.Diagnostics = defaults.Diagnostics
+	}
+
+	overridden := make(map[string]bool)
+	for _, featureGate := range merged.FeatureGates {
+		overridden[featureGate.Name] = true
+	}
+	for _, featureGate := range defaults.FeatureGates {
+		if !overridden[featureGate.Name] {
+			merged.FeatureGates = append(merged.FeatureGates, featureGate)
+		}
+	}
+
+	overriddenControllers := make(map[fedv1b1.ControllerName]bool)
+	for _, controller := range merged.Controllers {
+		overriddenControllers[controller.Name] = true
+	}
+	for _, controller := range defaults.Controllers {
+		if !overriddenControllers[controller.Name] {
+			merged.Controllers = append(merged.Controllers, controller)
+		}
+	}
+
+	return merged
+}
+
+// GetKubeFedConfigCondition returns the condition of the given type
+// in the given status, or nil if it is not set.
+func GetKubeFedConfigCondition(status *fedv1b1.KubeFedConfigStatus, conditionType fedv1b1.KubeFedConfigConditionType) *fedv1b1.KubeFedConfigCondition {
+	for i := range status.Conditions {
+		if status.Conditions[i].Type == conditionType {
+			return &status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// SetKubeFedConfigCondition sets the condition of the given type in
+// the given status.  The last transition time of an existing
+// condition is retained if its status is unchanged.  Returns whether
+// the status was changed.
+func SetKubeFedConfigCondition(status *fedv1b1.KubeFedConfigStatus, conditionType fedv1b1.KubeFedConfigConditionType,
+	conditionStatus apiv1.ConditionStatus, reason, message string, now metav1.Time) bool {
+
+	existing := GetKubeFedConfigCondition(status, conditionType)
+	if existing == nil {
+		status.Conditions = append(status.Conditions, fedv1b1.KubeFedConfigCondition{
+			Type:               conditionType,
+			Status:             conditionStatus,
+			LastTransitionTime: now,
+			Reason:             reason,
+			Message:            message,
+		})
+		return true
+	}
+	if existing.Status == conditionStatus && existing.Reason == reason && existing.Message == message {
+		return false
+	}
+	if existing.Status != conditionStatus {
+		existing.LastTransitionTime = now
+	}
+	existing.Status = conditionStatus
+	existing.Reason = reason
+	existing.Message = message
+	return true
+}
+
+func mergeDuration(target *metav1.Duration, defaultValue metav1.Duration) {
+	if target.Duration == 0 {
+		*target = defaultValue
+	}
+}
+
+func mergeInt64(target *int64, defaultValue int64) {
+	if *target == 0 {
+		*target = defaultValue
+	}
+}
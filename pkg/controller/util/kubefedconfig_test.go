@@ -0,0 +1,163 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	apiv1 "k8s.io/api/core/v1"
+	apiextv1b1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+func TestMergeKubeFedConfigSpec(t *testing.T) {
+	defaultSpec := &fedv1b1.KubeFedConfigSpec{
+		Scope: apiextv1b1.NamespaceScoped,
+		AllowedClusterScopedResources: []fedv1b1.AllowedClusterScopedResource{
+			{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole", Names: []string{"team-a"}},
+		},
+		ControllerDuration: fedv1b1.DurationConfig{
+			AvailableDelay:       metav1.Duration{Duration: 10 * time.Second},
+			UnavailableDelay:     metav1.Duration{Duration: 30 * time.Second},
+			StatusUpdateInterval: metav1.Duration{Duration: 5 * time.Second},
+			MemberReadCacheTTL:   &metav1.Duration{Duration: 10 * time.Second},
+		},
+		ClusterHealthCheck: fedv1b1.ClusterHealthCheckConfig{
+			PeriodSeconds:  20,
+			TimeoutSeconds: 5,
+		},
+		ClusterLifecycleHooks: []fedv1b1.ClusterLifecycleHook{{
+			Name:    "cmdb",
+			Events:  []fedv1b1.ClusterLifecycleEvent{fedv1b1.ClusterJoinedEvent},
+			Webhook: &fedv1b1.ClusterLifecycleWebhook{URL: "https://cmdb.example.com"},
+		}},
+		FeatureGates: []fedv1b1.FeatureGatesConfig{
+			{Name: "PushReconciler", Configuration: fedv1b1.ConfigurationEnabled},
+			{Name: "FederatedIngress", Configuration: fedv1b1.ConfigurationEnabled},
+		},
+		Controllers: []fedv1b1.ControllerSwitchConfig{
+			{Name: fedv1b1.ServiceDNSName, Configuration: fedv1b1.ConfigurationDisabled},
+			{Name: fedv1b1.IngressDNSName, Configuration: fedv1b1.ConfigurationDisabled},
+		},
+		Notifications: &fedv1b1.NotificationConfig{
+			Sinks: []fedv1b1.NotificationSink{{Name: "default", URL: "https://alerts.example.com"}},
+		},
+		Scheduling: &fedv1b1.SchedulingConfig{
+			Profiles: []fedv1b1.SchedulingProfile{{Name: "latency-first"}},
+		},
+		Diagnostics: &fedv1b1.DiagnosticsConfig{
+			Profiling: fedv1b1.ConfigurationEnabled,
+		},
+		SyncController: fedv1b1.SyncControllerConfig{
+			NamespaceMetadata:   &fedv1b1.NamespaceMetadataConfig{Labels: []string{"pod-security.kubernetes.io/*"}},
+			DeletionLimit:       &fedv1b1.DeletionLimitConfig{MaxDeletions: 10},
+			CircuitBreaker:      &fedv1b1.CircuitBreakerConfig{FailureThreshold: 5},
+			DispatchConcurrency: &fedv1b1.DispatchConcurrencyConfig{MaxPerCluster: 10, MaxTotal: 100},
+		},
+	}
+	spec := &fedv1b1.KubeFedConfigSpec{
+		ControllerDuration: fedv1b1.DurationConfig{
+			AvailableDelay: metav1.Duration{Duration: 5 * time.Second},
+		},
+		ClusterHealthCheck: fedv1b1.ClusterHealthCheckConfig{
+			TimeoutSeconds: 10,
+		},
+		FeatureGates: []fedv1b1.FeatureGatesConfig{
+			{Name: "FederatedIngress", Configuration: fedv1b1.ConfigurationDisabled},
+		},
+		Controllers: []fedv1b1.ControllerSwitchConfig{
+			{Name: fedv1b1.IngressDNSName, Configuration: fedv1b1.ConfigurationEnabled},
+		},
+		SyncController: fedv1b1.SyncControllerConfig{
+			DispatchConcurrency: &fedv1b1.DispatchConcurrencyConfig{MaxPerCluster: 5},
+		},
+	}
+
+	expectedSpec := &fedv1b1.KubeFedConfigSpec{
+		Scope:                         apiextv1b1.NamespaceScoped,
+		AllowedClusterScopedResources: defaultSpec.AllowedClusterScopedResources,
+		ControllerDuration: fedv1b1.DurationConfig{
+			AvailableDelay:       metav1.Duration{Duration: 5 * time.Second},
+			UnavailableDelay:     metav1.Duration{Duration: 30 * time.Second},
+			StatusUpdateInterval: metav1.Duration{Duration: 5 * time.Second},
+			MemberReadCacheTTL:   defaultSpec.ControllerDuration.MemberReadCacheTTL,
+		},
+		ClusterHealthCheck: fedv1b1.ClusterHealthCheckConfig{
+			PeriodSeconds:  20,
+			TimeoutSeconds: 10,
+		},
+		ClusterLifecycleHooks: defaultSpec.ClusterLifecycleHooks,
+		FeatureGates: []fedv1b1.FeatureGatesConfig{
+			{Name: "FederatedIngress", Configuration: fedv1b1.ConfigurationDisabled},
+			{Name: "PushReconciler", Configuration: fedv1b1.ConfigurationEnabled},
+		},
+		Controllers: []fedv1b1.ControllerSwitchConfig{
+			{Name: fedv1b1.IngressDNSName, Configuration: fedv1b1.ConfigurationEnabled},
+			{Name: fedv1b1.ServiceDNSName, Configuration: fedv1b1.ConfigurationDisabled},
+		},
+		Notifications: defaultSpec.Notifications,
+		Scheduling:    defaultSpec.Scheduling,
+		Diagnostics:   defaultSpec.Diagnostics,
+		SyncController: fedv1b1.SyncControllerConfig{
+			NamespaceMetadata:   defaultSpec.SyncController.NamespaceMetadata,
+			DeletionLimit:       defaultSpec.SyncController.DeletionLimit,
+			CircuitBreaker:      defaultSpec.SyncController.CircuitBreaker,
+			DispatchConcurrency: spec.SyncController.DispatchConcurrency,
+		},
+	}
+
+	mergedSpec := MergeKubeFedConfigSpec(spec, defaultSpec)
+	assert.Equal(t, expectedSpec, mergedSpec)
+	// The inputs are not modified.
+	assert.Equal(t, 1, len(spec.FeatureGates))
+	assert.Equal(t, 1, len(spec.Controllers))
+	assert.Nil(t, spec.Notifications)
+}
+
+func TestSetKubeFedConfigCondition(t *testing.T) {
+	status := &fedv1b1.KubeFedConfigStatus{}
+	start := metav1.NewTime(time.Now().Truncate(time.Second))
+	later := metav1.NewTime(start.Add(time.Minute))
+
+	assert.True(t, SetKubeFedConfigCondition(status, fedv1b1.KubeFedConfigApplied, apiv1.ConditionTrue, "Applied", "", start))
+	assert.False(t, SetKubeFedConfigCondition(status, fedv1b1.KubeFedConfigApplied, apiv1.ConditionTrue, "Applied", "", later))
+
+	assert.True(t, SetKubeFedConfigCondition(status, fedv1b1.KubeFedConfigValid, apiv1.ConditionTrue, "", "", start))
+	assert.Len(t, status.Conditions, 2)
+
+	// The transition time is retained unless the status changes.
+	assert.True(t, SetKubeFedConfigCondition(status, fedv1b1.KubeFedConfigApplied, apiv1.ConditionTrue, "Applied", "by replica b", later))
+	condition := GetKubeFedConfigCondition(status, fedv1b1.KubeFedConfigApplied)
+	if assert.NotNil(t, condition) {
+		assert.Equal(t, start, condition.LastTransitionTime)
+		assert.Equal(t, "by replica b", condition.Message)
+	}
+
+	assert.True(t, SetKubeFedConfigCondition(status, fedv1b1.KubeFedConfigApplied, apiv1.ConditionFalse, "RestartRequired", "", later))
+	condition = GetKubeFedConfigCondition(status, fedv1b1.KubeFedConfigApplied)
+	if assert.NotNil(t, condition) {
+		assert.Equal(t, later, condition.LastTransitionTime)
+		assert.Equal(t, apiv1.ConditionFalse, condition.Status)
+	}
+
+	assert.Nil(t, GetKubeFedConfigCondition(status, fedv1b1.KubeFedConfigDegraded))
+}
@@ -0,0 +1,206 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/pkg/errors"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog"
+)
+
+const (
+	// LastAppliedConfigAnnotation records the representation of a
+	// resource last written to a member cluster by the sync
+	// controller.  It allows determining which fields were removed
+	// from the desired state without having to compare against fields
+	// set by the member cluster.
+	LastAppliedConfigAnnotation = "kubefed.k8s.io/last-applied-configuration"
+
+	// The maximum size of a last-applied configuration.  The
+	// annotation is not recorded for larger resources to avoid
+	// exceeding the limit on the total size of annotations.
+	maxLastAppliedConfigSize = 128 * 1024
+)
+
+// SetLastAppliedConfigAnnotation records the desired object in its
+// own last-applied annotation.
+func SetLastAppliedConfigAnnotation(desiredObj *unstructured.Unstructured) error {
+	lastApplied := desiredObj.DeepCopy()
+	annotations := lastApplied.GetAnnotations()
+	delete(annotations, LastAppliedConfigAnnotation)
+	lastApplied.SetAnnotations(annotations)
+	unstructured.RemoveNestedField(lastApplied.Object, MetadataField, "resourceVersion")
+
+	lastAppliedJSON, err := lastApplied.MarshalJSON()
+	if err != nil {
+		return errors.Wrap(err, "Failed to marshal last-applied configuration")
+	}
+	annotations = desiredObj.GetAnnotations()
+	if len(lastAppliedJSON) > maxLastAppliedConfigSize {
+		klog.V(2).Infof("Not recording the last-applied configuration of %s %q since its size exceeds %d bytes",
+			desiredObj.GetKind(), NewQualifiedName(desiredObj), maxLastAppliedConfigSize)
+		delete(annotations, LastAppliedConfigAnnotation)
+	} else {
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		annotations[LastAppliedConfigAnnotation] = string(lastAppliedJSON)
+	}
+	desiredObj.SetAnnotations(annotations)
+	return nil
+}
+
+// ThreeWayMergePatch computes a json merge patch that will update the
+// cluster object to the desired state.  Only fields present in the
+// desired object are compared with the cluster object so that fields
+// defaulted or set by the member cluster do not result in an update.
+// Fields present in the last-applied configuration of the cluster
+// object but absent from the desired object are removed.  A nil
+// patch is returned if the cluster object is already current.
+func ThreeWayMergePatch(desiredObj, clusterObj *unstructured.Unstructured) ([]byte, error) {
+	lastApplied := map[string]interface{}{}
+	if lastAppliedJSON, ok := clusterObj.GetAnnotations()[LastAppliedConfigAnnotation]; ok {
+		if err := json.Unmarshal([]byte(lastAppliedJSON), &lastApplied); err != nil {
+			// Fields that are no longer desired will not be removed,
+			// but the annotation will be corrected by the update.
+			klog.Warningf("Ignoring invalid last-applied configuration of %s %q: %v",
+				clusterObj.GetKind(), NewQualifiedName(clusterObj), err)
+			lastApplied = map[string]interface{}{}
+		}
+	}
+
+	patch := mergeDeletions(lastApplied, desiredObj.Object)
+	for key, value := range mergeChanges(desiredObj.Object, clusterObj.Object) {
+		patch[key] = value
+	}
+	if len(patch) == 0 {
+		return nil, nil
+	}
+	// Ensure the patch fails if the cluster object has changed since
+	// it was cached.
+	if _, ok := patch[MetadataField]; !ok {
+		patch[MetadataField] = map[string]interface{}{}
+	}
+	patch[MetadataField].(map[string]interface{})["resourceVersion"] = clusterObj.GetResourceVersion()
+
+	patchJSON, err := json.Marshal(patch)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to marshal merge patch")
+	}
+	return patchJSON, nil
+}
+
+// mergeDeletions returns a patch that removes the fields present in
+// the last-applied map that are absent from the desired map.
+func mergeDeletions(lastApplied, desired map[string]interface{}) map[string]interface{} {
+	patch := map[string]interface{}{}
+	for key, lastAppliedValue := range lastApplied {
+		desiredValue, ok := desired[key]
+		if !ok {
+			patch[key] = nil
+			continue
+		}
+		lastAppliedMap, lastAppliedIsMap := lastAppliedValue.(map[string]interface{})
+		desiredMap, desiredIsMap := desiredValue.(map[string]interface{})
+		if lastAppliedIsMap && desiredIsMap {
+			if childPatch := mergeDeletions(lastAppliedMap, desiredMap); len(childPatch) > 0 {
+				patch[key] = childPatch
+			}
+		}
+	}
+	return patch
+}
+
+// mergeChanges returns a patch that sets the fields of the desired
+// map whose values are not reflected by the cluster map.
+func mergeChanges(desired, cluster map[string]interface{}) map[string]interface{} {
+	patch := map[string]interface{}{}
+	for key, desiredValue := range desired {
+		if desiredValue == nil {
+			// A null value (e.g. creationTimestamp in a template)
+			// does not express a desired state.
+			continue
+		}
+		clusterValue, ok := cluster[key]
+		if !ok {
+			patch[key] = desiredValue
+			continue
+		}
+		desiredMap, desiredIsMap := desiredValue.(map[string]interface{})
+		clusterMap, clusterIsMap := clusterValue.(map[string]interface{})
+		if desiredIsMap && clusterIsMap {
+			if childPatch := mergeChanges(desiredMap, clusterMap); len(childPatch) > 0 {
+				patch[key] = childPatch
+			}
+			continue
+		}
+		if !valueReflected(desiredValue, clusterValue) {
+			patch[key] = desiredValue
+		}
+	}
+	return patch
+}
+
+// valueReflected determines whether the desired value is reflected by
+// the cluster value.  Maps are reflected if every desired field is
+// reflected, and lists if they are of the same length and every item
+// is reflected.  Since a merge patch replaces lists, a list that is
+// not reflected must be replaced in its entirety.
+func valueReflected(desiredValue, clusterValue interface{}) bool {
+	switch desired := desiredValue.(type) {
+	case map[string]interface{}:
+		cluster, ok := clusterValue.(map[string]interface{})
+		return ok && len(mergeChanges(desired, cluster)) == 0
+	case []interface{}:
+		cluster, ok := clusterValue.([]interface{})
+		if !ok || len(desired) != len(cluster) {
+			return false
+		}
+		for i := range desired {
+			if !valueReflected(desired[i], cluster[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	desiredNumber, desiredIsNumber := toFloat64(desiredValue)
+	clusterNumber, clusterIsNumber := toFloat64(clusterValue)
+	if desiredIsNumber && clusterIsNumber {
+		return desiredNumber == clusterNumber
+	}
+	return reflect.DeepEqual(desiredValue, clusterValue)
+}
+
+// toFloat64 allows comparison of numbers that may have been decoded
+// as either integer or floating point values.
+func toFloat64(value interface{}) (float64, bool) {
+	switch number := value.(type) {
+	case int64:
+		return float64(number), true
+	case int32:
+		return float64(number), true
+	case int:
+		return float64(number), true
+	case float64:
+		return number, true
+	}
+	return 0, false
+}
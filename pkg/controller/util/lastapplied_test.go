@@ -0,0 +1,145 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestThreeWayMergePatch(t *testing.T) {
+	desiredObj := func() *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"name":              "foo",
+				"creationTimestamp": nil,
+				"labels":            map[string]interface{}{"app": "foo"},
+			},
+			"spec": map[string]interface{}{
+				"replicas": float64(3),
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"containers": []interface{}{
+							map[string]interface{}{"name": "foo", "image": "foo:v1"},
+						},
+					},
+				},
+			},
+		}}
+	}
+	clusterObj := func(lastApplied *unstructured.Unstructured) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"name":              "foo",
+				"resourceVersion":   "42",
+				"creationTimestamp": "2019-07-01T10:00:00Z",
+				"labels":            map[string]interface{}{"app": "foo"},
+				"annotations": map[string]interface{}{
+					"deployment.kubernetes.io/revision": "1",
+				},
+			},
+			"spec": map[string]interface{}{
+				"replicas":             int64(3),
+				"revisionHistoryLimit": int64(10),
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"containers": []interface{}{
+							map[string]interface{}{"name": "foo", "image": "foo:v1", "imagePullPolicy": "IfNotPresent"},
+						},
+					},
+				},
+			},
+		}}
+		if lastApplied != nil {
+			assert.NoError(t, SetLastAppliedConfigAnnotation(lastApplied))
+			annotations := obj.GetAnnotations()
+			annotations[LastAppliedConfigAnnotation] = lastApplied.GetAnnotations()[LastAppliedConfigAnnotation]
+			obj.SetAnnotations(annotations)
+		}
+		return obj
+	}
+
+	testCases := map[string]struct {
+		desiredFunc   func(*unstructured.Unstructured)
+		lastApplied   func(*unstructured.Unstructured)
+		expectedPatch map[string]interface{}
+	}{
+		"No patch for defaulted and cluster-managed fields": {},
+		"Changed fields are patched and lists replaced": {
+			desiredFunc: func(obj *unstructured.Unstructured) {
+				containers := []interface{}{
+					map[string]interface{}{"name": "foo", "image": "foo:v2"},
+				}
+				assert.NoError(t, unstructured.SetNestedSlice(obj.Object, containers, "spec", "template", "spec", "containers"))
+			},
+			expectedPatch: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"resourceVersion": "42",
+				},
+				"spec": map[string]interface{}{
+					"template": map[string]interface{}{
+						"spec": map[string]interface{}{
+							"containers": []interface{}{
+								map[string]interface{}{"name": "foo", "image": "foo:v2"},
+							},
+						},
+					},
+				},
+			},
+		},
+		"Fields removed since last applied are deleted": {
+			lastApplied: func(obj *unstructured.Unstructured) {
+				obj.SetLabels(map[string]string{"app": "foo", "tier": "web"})
+			},
+			expectedPatch: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"resourceVersion": "42",
+					"labels": map[string]interface{}{
+						"tier": nil,
+					},
+				},
+			},
+		},
+	}
+
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			desired := desiredObj()
+			if tc.desiredFunc != nil {
+				tc.desiredFunc(desired)
+			}
+			var lastApplied *unstructured.Unstructured
+			if tc.lastApplied != nil {
+				lastApplied = desiredObj()
+				tc.lastApplied(lastApplied)
+			}
+			patch, err := ThreeWayMergePatch(desired, clusterObj(lastApplied))
+			assert.NoError(t, err)
+			if tc.expectedPatch == nil {
+				assert.Nil(t, patch)
+				return
+			}
+			actualPatch := map[string]interface{}{}
+			assert.NoError(t, json.Unmarshal(patch, &actualPatch))
+			assert.Equal(t, tc.expectedPatch, actualPatch)
+		})
+	}
+}
@@ -17,14 +17,66 @@ limitations under the License.
 package util
 
 import (
+	"strings"
+
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 const (
 	ManagedByKubeFedLabelKey   = "kubefed.k8s.io/managed"
 	ManagedByKubeFedLabelValue = "true"
+
+	// OwnerAnnotation identifies the federated resource that manages
+	// a resource labeled as managed in a member cluster by its owner
+	// key, so that federated resources rendering to the same resource
+	// are detected rather than overwriting each other's changes.
+	OwnerAnnotation = "kubefed.k8s.io/owner"
 )
 
+// OwnerKey returns the key identifying the federated resource of the
+// given kind and name as the owner of the resources it manages
+// (e.g. FederatedDeployment/ns/foo).
+func OwnerKey(federatedKind string, federatedName QualifiedName) string {
+	return federatedKind + "/" + federatedName.String()
+}
+
+// ParseOwnerKey returns the kind and name of the federated resource
+// identified by the given owner key.
+func ParseOwnerKey(owner string) (string, QualifiedName, bool) {
+	parts := strings.Split(owner, "/")
+	switch len(parts) {
+	case 2:
+		return parts[0], QualifiedName{Name: parts[1]}, true
+	case 3:
+		return parts[0], QualifiedName{Namespace: parts[1], Name: parts[2]}, true
+	}
+	return "", QualifiedName{}, false
+}
+
+// SetOwner records the given owner key on the given object.
+func SetOwner(obj *unstructured.Unstructured, owner string) {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[OwnerAnnotation] = owner
+	obj.SetAnnotations(annotations)
+}
+
+// OwnedByOther indicates whether the given object in a member cluster
+// is managed by a federated resource other than the given owner.
+// Resources propagated before owners were recorded have no owner and
+// are not considered to be owned by another.
+func OwnedByOther(obj *unstructured.Unstructured, owner string) bool {
+	objOwner, ok := obj.GetAnnotations()[OwnerAnnotation]
+	return ok && len(objOwner) > 0 && objOwner != owner
+}
+
+// Owner returns the owner key recorded on the given object, if any.
+func Owner(obj *unstructured.Unstructured) string {
+	return obj.GetAnnotations()[OwnerAnnotation]
+}
+
 // HasManagedLabel indicates whether the given object has the managed
 // label.
 func HasManagedLabel(obj *unstructured.Unstructured) bool {
@@ -47,8 +99,15 @@ func AddManagedLabel(obj *unstructured.Unstructured) {
 }
 
 // RemoveManagedLabel ensures that the given object does not have the
-// managed label.
+// managed label, or the owner annotation that is only meaningful for
+// a managed object.
 func RemoveManagedLabel(obj *unstructured.Unstructured) {
+	annotations := obj.GetAnnotations()
+	if _, ok := annotations[OwnerAnnotation]; ok {
+		delete(annotations, OwnerAnnotation)
+		obj.SetAnnotations(annotations)
+	}
+
 	labels := obj.GetLabels()
 	if labels == nil || labels[ManagedByKubeFedLabelKey] != ManagedByKubeFedLabelValue {
 		return
@@ -0,0 +1,243 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"sigs.k8s.io/kubefed/pkg/client/generic/scheme"
+	"sigs.k8s.io/kubefed/pkg/metrics"
+)
+
+// NewManager returns a controller-runtime manager for the controllers
+// of the KubeFed control plane.  The cache of the manager is shared by
+// the controllers registered with it and is limited to the target
+// namespace so that restrictive authz can be applied to a namespaced
+// control plane.  Controllers of objects that are only read from the
+// KubeFed system namespace (e.g. KubeFedClusters) must ignore the
+// objects of other namespaces when the control plane is cluster-scoped.
+//
+// Leader election and the serving of metrics are disabled since both
+// are provided by the controller manager.
+func NewManager(config *ControllerConfig) (manager.Manager, error) {
+	kubeConfig := restclient.CopyConfig(config.KubeConfig)
+	restclient.AddUserAgent(kubeConfig, "kubefed-manager")
+	mgr, err := manager.New(kubeConfig, manager.Options{
+		Scheme:             scheme.Scheme,
+		Namespace:          config.TargetNamespace,
+		MetricsBindAddress: "0",
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create controller manager")
+	}
+	return mgr, nil
+}
+
+// ManagerFor returns the manager shared by the controllers of the
+// given config.  A controller started without a shared manager (e.g.
+// by a test fixture) is given a manager of its own, which it must
+// start with StartManager once it has been registered.
+func ManagerFor(config *ControllerConfig) (mgr manager.Manager, shared bool, err error) {
+	if config.Manager != nil {
+		return config.Manager, true, nil
+	}
+	mgr, err = NewManager(config)
+	return mgr, false, err
+}
+
+// StartManager starts the given manager and exits the process if it
+// fails to start one of its controllers.
+func StartManager(mgr manager.Manager, stopChan <-chan struct{}) {
+	go func() {
+		if err := mgr.Start(stopChan); err != nil {
+			klog.Fatalf("Error running controller manager: %v", err)
+		}
+	}()
+}
+
+// NewManagerInformer returns a store and controller for the objects of
+// the given type in the given namespace that are backed by the
+// informer of the cache of the given manager, so that the informer is
+// shared by all controllers registered with the manager.  The informer
+// is run by the manager, and the Run method of the returned controller
+// does nothing.
+func NewManagerInformer(mgr manager.Manager, namespace string, obj pkgruntime.Object, triggerFunc func(pkgruntime.Object)) (cache.Store, cache.Controller, error) {
+	return NewManagerInformerWithEventHandler(mgr, namespace, obj, NewTriggerOnAllChanges(triggerFunc))
+}
+
+// NewManagerInformerWithEventHandler is as NewManagerInformer, but
+// calls the given handler for the objects in the given namespace.
+func NewManagerInformerWithEventHandler(mgr manager.Manager, namespace string, obj pkgruntime.Object, resourceEventHandlerFuncs *cache.ResourceEventHandlerFuncs) (cache.Store, cache.Controller, error) {
+	informer, err := mgr.GetCache().GetInformer(obj)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "Failed to get the informer for %T", obj)
+	}
+	informer.AddEventHandlerWithResyncPeriod(cache.FilteringResourceEventHandler{
+		FilterFunc: func(obj interface{}) bool {
+			return inNamespace(namespace, obj)
+		},
+		Handler: resourceEventHandlerFuncs,
+	}, NoResyncPeriod)
+	store := &namespacedStore{
+		Indexer:   informer.GetIndexer(),
+		namespace: namespace,
+	}
+	return store, &managerInformer{informer}, nil
+}
+
+// NamespacePredicate returns a predicate accepting the events of the
+// objects in the given namespace, for a controller registered with the
+// manager that only reconciles the objects of that namespace.
+func NamespacePredicate(namespace string) predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return inNamespace(namespace, e.Object)
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return inNamespace(namespace, e.ObjectNew)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return inNamespace(namespace, e.Object)
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return inNamespace(namespace, e.Object)
+		},
+	}
+}
+
+func inNamespace(namespace string, obj interface{}) bool {
+	if namespace == metav1.NamespaceAll {
+		return true
+	}
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	metaObj, err := meta.Accessor(obj)
+	if err != nil {
+		return false
+	}
+	return metaObj.GetNamespace() == namespace
+}
+
+// namespacedStore limits the listing of the objects of a shared
+// informer to a namespace.
+type namespacedStore struct {
+	cache.Indexer
+	namespace string
+}
+
+func (s *namespacedStore) List() []interface{} {
+	if s.namespace == metav1.NamespaceAll {
+		return s.Indexer.List()
+	}
+	objs, err := s.Indexer.ByIndex(cache.NamespaceIndex, s.namespace)
+	if err != nil {
+		klog.Errorf("Failed to list the objects of namespace %q: %v", s.namespace, err)
+		return nil
+	}
+	return objs
+}
+
+func (s *namespacedStore) ListKeys() []string {
+	if s.namespace == metav1.NamespaceAll {
+		return s.Indexer.ListKeys()
+	}
+	keys, err := s.Indexer.IndexKeys(cache.NamespaceIndex, s.namespace)
+	if err != nil {
+		klog.Errorf("Failed to list the keys of namespace %q: %v", s.namespace, err)
+		return nil
+	}
+	return keys
+}
+
+// managerInformer adapts an informer run by a manager to a
+// cache.Controller.
+type managerInformer struct {
+	informer cache.SharedIndexInformer
+}
+
+func (i *managerInformer) Run(stopChan <-chan struct{}) {}
+
+func (i *managerInformer) HasSynced() bool {
+	return i.informer.HasSynced()
+}
+
+func (i *managerInformer) LastSyncResourceVersion() string {
+	return i.informer.LastSyncResourceVersion()
+}
+
+type reconciler struct {
+	name      WorkerName
+	reconcile ReconcileFunc
+	timing    WorkerTiming
+}
+
+// NewReconciler adapts a reconcile function to a controller-runtime
+// reconciler.  As for a reconcile worker, reconciliations are recorded
+// in metrics and are not started once the process is shutting down,
+// and the returned status determines when a resource is requeued.
+func NewReconciler(name WorkerName, reconcile ReconcileFunc, timing WorkerTiming) reconcile.Reconciler {
+	timing.setDefaults()
+	return &reconciler{
+		name:      name,
+		reconcile: reconcile,
+		timing:    timing,
+	}
+}
+
+func (r *reconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	if !reconciles.start() {
+		// The process is shutting down.
+		return reconcile.Result{}, nil
+	}
+	qualifiedName := QualifiedName{
+		Namespace: request.Namespace,
+		Name:      request.Name,
+	}
+	startTime := time.Now()
+	status := r.reconcile(qualifiedName)
+	reconciles.done()
+	metrics.RecordReconcile(r.name.Controller, r.name.TypeConfig, time.Since(startTime), status == StatusError)
+
+	switch status {
+	case StatusError:
+		// The reconcile function is responsible for reporting the
+		// error, so the manager is only asked to requeue with backoff.
+		metrics.RecordWorkqueueRetry(r.name.Controller, r.name.TypeConfig, retryReasonError)
+		return reconcile.Result{Requeue: true}, nil
+	case StatusNeedsRecheck:
+		metrics.RecordWorkqueueRetry(r.name.Controller, r.name.TypeConfig, retryReasonRecheck)
+		return reconcile.Result{Requeue: true, RequeueAfter: r.timing.RetryDelay}, nil
+	case StatusNotSynced:
+		metrics.RecordWorkqueueRetry(r.name.Controller, r.name.TypeConfig, retryReasonNotSynced)
+		return reconcile.Result{Requeue: true, RequeueAfter: r.timing.ClusterSyncDelay}, nil
+	}
+	return reconcile.Result{}, nil
+}
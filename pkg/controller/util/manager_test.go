@@ -0,0 +1,101 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestReconcilerResult(t *testing.T) {
+	timing := WorkerTiming{RetryDelay: time.Minute, ClusterSyncDelay: time.Second}
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "name"}}
+
+	testCases := map[string]struct {
+		status   ReconciliationStatus
+		expected reconcile.Result
+	}{
+		"All OK is not requeued": {
+			status:   StatusAllOK,
+			expected: reconcile.Result{},
+		},
+		"Error is requeued with backoff": {
+			status:   StatusError,
+			expected: reconcile.Result{Requeue: true},
+		},
+		"Needs recheck is requeued after the retry delay": {
+			status:   StatusNeedsRecheck,
+			expected: reconcile.Result{Requeue: true, RequeueAfter: time.Minute},
+		},
+		"Not synced is requeued after the cluster sync delay": {
+			status:   StatusNotSynced,
+			expected: reconcile.Result{Requeue: true, RequeueAfter: time.Second},
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			var reconciled QualifiedName
+			r := NewReconciler(WorkerName{Controller: "test"}, func(qualifiedName QualifiedName) ReconciliationStatus {
+				reconciled = qualifiedName
+				return tc.status
+			}, timing)
+			result, err := r.Reconcile(request)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, result)
+			assert.Equal(t, QualifiedName{Namespace: "ns", Name: "name"}, reconciled)
+		})
+	}
+}
+
+func TestNamespacedStore(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{
+		cache.NamespaceIndex: cache.MetaNamespaceIndexFunc,
+	})
+	for _, namespace := range []string{"kube-federation-system", "other"} {
+		err := indexer.Add(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "cm"}})
+		assert.NoError(t, err)
+	}
+
+	store := &namespacedStore{Indexer: indexer, namespace: "kube-federation-system"}
+	assert.Len(t, store.List(), 1)
+	assert.Equal(t, []string{"kube-federation-system/cm"}, store.ListKeys())
+
+	store = &namespacedStore{Indexer: indexer, namespace: metav1.NamespaceAll}
+	assert.Len(t, store.List(), 2)
+	assert.Len(t, store.ListKeys(), 2)
+}
+
+func TestNamespacePredicate(t *testing.T) {
+	obj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "cm"}}
+	p := NamespacePredicate("kube-federation-system")
+	assert.False(t, p.Create(event.CreateEvent{Meta: obj, Object: obj}))
+	assert.False(t, p.Generic(event.GenericEvent{Meta: obj, Object: obj}))
+
+	obj.Namespace = "kube-federation-system"
+	assert.True(t, p.Create(event.CreateEvent{Meta: obj, Object: obj}))
+	assert.True(t, p.Update(event.UpdateEvent{MetaOld: obj, ObjectOld: obj, MetaNew: obj, ObjectNew: obj}))
+	assert.True(t, p.Delete(event.DeleteEvent{Meta: obj, Object: obj}))
+}
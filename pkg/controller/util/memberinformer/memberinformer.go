@@ -0,0 +1,250 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memberinformer
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog"
+)
+
+// Key identifies the informer of a resource type in a member
+// cluster.  Controllers that request informers with the same key
+// share a single watch and cache.
+type Key struct {
+	// Cluster is the name of the member cluster.
+	Cluster string
+	// ClusterFingerprint identifies the configuration the client of
+	// the cluster was created with, so that an informer created
+	// before a change of the configuration is not shared with
+	// controllers that observed the change.
+	ClusterFingerprint string
+	// Resource is the group, version and plural name of the type.
+	Resource      string
+	Namespace     string
+	LabelSelector string
+}
+
+func (k Key) String() string {
+	return fmt.Sprintf("%s/%s (namespace %q, selector %q)", k.Cluster, k.Resource, k.Namespace, k.LabelSelector)
+}
+
+// Manager shares the informers of member cluster resources between
+// the controllers that watch the same types, such as the sync and
+// status controllers.  A shared informer is started when it is first
+// run by a controller and stopped when the last controller using it
+// stops.  A nil *Manager is valid and creates an unshared informer
+// for each request.
+type Manager struct {
+	lock      sync.Mutex
+	informers map[Key]*sharedInformer
+	observers []Observer
+}
+
+// Observer is called with the objects added, updated or deleted in
+// the cache of a shared informer, such as to invalidate other caches
+// of member cluster resources.
+type Observer func(key Key, obj interface{})
+
+// New returns a manager without informers that notifies the given
+// observers of the changes observed by its informers.
+func New(observers ...Observer) *Manager {
+	return &Manager{informers: make(map[Key]*sharedInformer), observers: observers}
+}
+
+// Informer returns a store and controller for the key that deliver
+// the events of the shared informer to the handler while the
+// controller is running.  The controller must be run, and the
+// informer is released when its stop channel is closed.  The
+// ListerWatcher and resync period are only used if no informer
+// exists for the key.  Objects already in the cache of a running
+// informer are delivered to the handler as additions when the
+// controller is run.
+func (m *Manager) Informer(key Key, lw cache.ListerWatcher, resyncPeriod time.Duration, handler cache.ResourceEventHandler) (cache.Store, cache.Controller) {
+	if m == nil {
+		return cache.NewInformer(lw, nil, resyncPeriod, handler)
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	informer, ok := m.informers[key]
+	if !ok {
+		informer = newSharedInformer(key, lw, resyncPeriod, m.observers)
+		m.informers[key] = informer
+		klog.V(4).Infof("Created shared informer for %s", key)
+	}
+	informer.consumers++
+	return informer.store, &consumer{manager: m, key: key, informer: informer, handler: handler}
+}
+
+// release removes a consumer of the informer for the key, stopping
+// the informer if it has no other consumers.
+func (m *Manager) release(key Key, informer *sharedInformer) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	informer.consumers--
+	if informer.consumers > 0 {
+		return
+	}
+	// A new informer may have been created for the key since all
+	// consumers of this one were released before.
+	if m.informers[key] == informer {
+		delete(m.informers, key)
+	}
+	informer.stop()
+	klog.V(4).Infof("Stopped shared informer for %s", key)
+}
+
+// Dump writes the shared informers and the number of their consumers
+// to a diagnostic dump.
+func (m *Manager) Dump(w io.Writer) {
+	if m == nil {
+		return
+	}
+	m.lock.Lock()
+	lines := []string{}
+	for key, informer := range m.informers {
+		lines = append(lines, fmt.Sprintf("%s: consumers=%d objects=%d", key, informer.consumers, len(informer.store.ListKeys())))
+	}
+	m.lock.Unlock()
+	sort.Strings(lines)
+	fmt.Fprintf(w, "shared informers: %d\n", len(lines))
+	for _, line := range lines {
+		fmt.Fprintln(w, line)
+	}
+}
+
+// start runs the informer if it is not already running.
+func (m *Manager) start(informer *sharedInformer) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if informer.started {
+		return
+	}
+	informer.started = true
+	go informer.controller.Run(informer.stopChan)
+}
+
+// sharedInformer is an informer that delivers its events to the
+// handlers of its running consumers.
+type sharedInformer struct {
+	store      cache.Store
+	controller cache.Controller
+	stopChan   chan struct{}
+
+	// The number of consumers and whether the informer has been
+	// started are guarded by the lock of the manager.
+	consumers int
+	started   bool
+
+	// The lock of the handlers is held while events are delivered
+	// so that a consumer being added does not miss events.
+	handlerLock sync.RWMutex
+	handlers    map[*consumer]cache.ResourceEventHandler
+}
+
+func newSharedInformer(key Key, lw cache.ListerWatcher, resyncPeriod time.Duration, observers []Observer) *sharedInformer {
+	informer := &sharedInformer{
+		stopChan: make(chan struct{}),
+		handlers: make(map[*consumer]cache.ResourceEventHandler),
+	}
+	observe := func(obj interface{}) {
+		for _, observer := range observers {
+			observer(key, obj)
+		}
+	}
+	informer.store, informer.controller = cache.NewInformer(lw, nil, resyncPeriod, &cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			observe(obj)
+			informer.distribute(func(handler cache.ResourceEventHandler) { handler.OnAdd(obj) })
+		},
+		UpdateFunc: func(old, cur interface{}) {
+			observe(cur)
+			informer.distribute(func(handler cache.ResourceEventHandler) { handler.OnUpdate(old, cur) })
+		},
+		DeleteFunc: func(obj interface{}) {
+			observe(obj)
+			informer.distribute(func(handler cache.ResourceEventHandler) { handler.OnDelete(obj) })
+		},
+	})
+	return informer
+}
+
+func (s *sharedInformer) distribute(deliver func(cache.ResourceEventHandler)) {
+	s.handlerLock.RLock()
+	defer s.handlerLock.RUnlock()
+	for _, handler := range s.handlers {
+		deliver(handler)
+	}
+}
+
+// addHandler delivers the objects in the cache to the handler of the
+// consumer and adds it to the handlers receiving subsequent events.
+func (s *sharedInformer) addHandler(c *consumer) {
+	s.handlerLock.Lock()
+	defer s.handlerLock.Unlock()
+	for _, obj := range s.store.List() {
+		c.handler.OnAdd(obj)
+	}
+	s.handlers[c] = c.handler
+}
+
+func (s *sharedInformer) removeHandler(c *consumer) {
+	s.handlerLock.Lock()
+	defer s.handlerLock.Unlock()
+	delete(s.handlers, c)
+}
+
+// stop stops the informer if it was started.  The lock of the
+// manager must be held.
+func (s *sharedInformer) stop() {
+	if s.started {
+		close(s.stopChan)
+	}
+}
+
+// consumer is the controller returned for a request of an informer.
+type consumer struct {
+	manager  *Manager
+	key      Key
+	informer *sharedInformer
+	handler  cache.ResourceEventHandler
+}
+
+var _ cache.Controller = &consumer{}
+
+// Run delivers the events of the shared informer to the handler of
+// the consumer until the stop channel is closed.
+func (c *consumer) Run(stopCh <-chan struct{}) {
+	c.informer.addHandler(c)
+	c.manager.start(c.informer)
+	<-stopCh
+	c.informer.removeHandler(c)
+	c.manager.release(c.key, c.informer)
+}
+
+func (c *consumer) HasSynced() bool {
+	return c.informer.controller.HasSynced()
+}
+
+func (c *consumer) LastSyncResourceVersion() string {
+	return c.informer.controller.LastSyncResourceVersion()
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memberinformer
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+)
+
+type fakeListerWatcher struct {
+	lock    sync.Mutex
+	lists   int
+	watcher *watch.FakeWatcher
+}
+
+func (lw *fakeListerWatcher) List(options metav1.ListOptions) (pkgruntime.Object, error) {
+	lw.lock.Lock()
+	defer lw.lock.Unlock()
+	lw.lists++
+	return &apiv1.ConfigMapList{
+		ListMeta: metav1.ListMeta{ResourceVersion: "1"},
+		Items:    []apiv1.ConfigMap{*newConfigMap("cm1")},
+	}, nil
+}
+
+func (lw *fakeListerWatcher) Watch(options metav1.ListOptions) (watch.Interface, error) {
+	lw.lock.Lock()
+	defer lw.lock.Unlock()
+	lw.watcher = watch.NewFake()
+	return lw.watcher, nil
+}
+
+func (lw *fakeListerWatcher) listCount() int {
+	lw.lock.Lock()
+	defer lw.lock.Unlock()
+	return lw.lists
+}
+
+func (lw *fakeListerWatcher) currentWatcher() *watch.FakeWatcher {
+	lw.lock.Lock()
+	defer lw.lock.Unlock()
+	return lw.watcher
+}
+
+func newConfigMap(name string) *apiv1.ConfigMap {
+	return &apiv1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: name, ResourceVersion: "1"}}
+}
+
+// recorder records the names of the objects added to it.
+type recorder struct {
+	lock  sync.Mutex
+	added []string
+}
+
+func (r *recorder) handler() cache.ResourceEventHandler {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			r.lock.Lock()
+			defer r.lock.Unlock()
+			r.added = append(r.added, obj.(*apiv1.ConfigMap).Name)
+		},
+	}
+}
+
+func (r *recorder) waitForAdded(t *testing.T, expected ...string) {
+	err := wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+		r.lock.Lock()
+		defer r.lock.Unlock()
+		return len(r.added) == len(expected), nil
+	})
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	assert.NoError(t, err)
+	assert.Equal(t, expected, r.added)
+}
+
+func informerCount(m *Manager) int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return len(m.informers)
+}
+
+func TestSharedInformer(t *testing.T) {
+	manager := New()
+	key := Key{Cluster: "cluster1", Resource: "/v1, Resource=configmaps"}
+	lw := &fakeListerWatcher{}
+
+	first := &recorder{}
+	store, controller := manager.Informer(key, lw, 0, first.handler())
+	firstStop := make(chan struct{})
+	go controller.Run(firstStop)
+	first.waitForAdded(t, "cm1")
+	assert.NoError(t, wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+		return controller.HasSynced() && lw.currentWatcher() != nil, nil
+	}))
+
+	// A second consumer of the key shares the running informer and
+	// receives the objects already in its cache.
+	second := &recorder{}
+	secondStore, secondController := manager.Informer(key, &fakeListerWatcher{}, 0, second.handler())
+	assert.True(t, store == secondStore)
+	secondStop := make(chan struct{})
+	go secondController.Run(secondStop)
+	second.waitForAdded(t, "cm1")
+
+	lw.currentWatcher().Add(newConfigMap("cm2"))
+	first.waitForAdded(t, "cm1", "cm2")
+	second.waitForAdded(t, "cm1", "cm2")
+	assert.Equal(t, 1, lw.listCount())
+	assert.Equal(t, 1, informerCount(manager))
+
+	var dump bytes.Buffer
+	manager.Dump(&dump)
+	assert.True(t, strings.Contains(dump.String(), "consumers=2 objects=2"), dump.String())
+
+	// Informers of other keys are not shared.
+	otherKey := key
+	otherKey.ClusterFingerprint = "changed"
+	otherLW := &fakeListerWatcher{}
+	_, otherController := manager.Informer(otherKey, otherLW, 0, (&recorder{}).handler())
+	otherStop := make(chan struct{})
+	go otherController.Run(otherStop)
+	assert.NoError(t, wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+		return otherController.HasSynced(), nil
+	}))
+	assert.Equal(t, 1, otherLW.listCount())
+	assert.Equal(t, 2, informerCount(manager))
+	close(otherStop)
+
+	// The informer is stopped once all its consumers have stopped.
+	close(firstStop)
+	lw.currentWatcher().Add(newConfigMap("cm3"))
+	second.waitForAdded(t, "cm1", "cm2", "cm3")
+	first.waitForAdded(t, "cm1", "cm2")
+	close(secondStop)
+	assert.NoError(t, wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+		return informerCount(manager) == 0, nil
+	}))
+}
+
+func TestNilManager(t *testing.T) {
+	var manager *Manager
+	r := &recorder{}
+	_, controller := manager.Informer(Key{}, &fakeListerWatcher{}, 0, r.handler())
+	stopChan := make(chan struct{})
+	defer close(stopChan)
+	go controller.Run(stopChan)
+	r.waitForAdded(t, "cm1")
+	manager.Dump(&bytes.Buffer{})
+}
+
+func TestObservers(t *testing.T) {
+	key := Key{Cluster: "cluster1", Resource: "/v1, Resource=configmaps"}
+	observed := &recorder{}
+	manager := New(func(observedKey Key, obj interface{}) {
+		assert.Equal(t, key, observedKey)
+		observed.handler().OnAdd(obj)
+	})
+	lw := &fakeListerWatcher{}
+	_, controller := manager.Informer(key, lw, 0, (&recorder{}).handler())
+	stopChan := make(chan struct{})
+	defer close(stopChan)
+	go controller.Run(stopChan)
+	observed.waitForAdded(t, "cm1")
+	assert.NoError(t, wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+		return lw.currentWatcher() != nil, nil
+	}))
+
+	lw.currentWatcher().Modify(newConfigMap("cm1"))
+	lw.currentWatcher().Delete(newConfigMap("cm1"))
+	observed.waitForAdded(t, "cm1", "cm1", "cm1")
+}
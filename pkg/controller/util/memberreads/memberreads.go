@@ -0,0 +1,249 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memberreads
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Key identifies the reads of a resource type in a member cluster.
+type Key struct {
+	// Cluster is the name of the member cluster.
+	Cluster string
+	// Resource is the group, version and plural name of the type as
+	// formatted by schema.GroupVersionResource.
+	Resource string
+}
+
+// Read identifies a get or list of a resource type in a member
+// cluster.
+type Read struct {
+	Namespace string
+	// Name is the name of the object of a get, and is empty for a
+	// list.
+	Name string
+	// Options distinguishes reads of the same objects with different
+	// parameters, such as the label selector of a list.
+	Options string
+}
+
+// Cache is a read-through cache of the gets and lists of member
+// cluster resources that is shared by the controllers reading the
+// same resources, so that they do not each issue the same requests to
+// the API servers of member clusters.  A read is reused until its TTL
+// elapses or a watch of the resource observes a change to an object
+// the read may include.  Concurrent identical reads are issued once.
+// A nil *Cache is valid and performs every read.
+type Cache struct {
+	lock  sync.Mutex
+	ttl   time.Duration
+	now   func() time.Time
+	reads map[Key]map[Read]*result
+}
+
+// result is the outcome of a read.  The value and error are set
+// before done is closed.
+type result struct {
+	done   chan struct{}
+	value  interface{}
+	err    error
+	expiry time.Time
+}
+
+// New returns a cache that reuses reads for the given TTL.  Reads are
+// not cached if the TTL is not positive.
+func New(ttl time.Duration) *Cache {
+	return &Cache{
+		ttl:   ttl,
+		now:   time.Now,
+		reads: make(map[Key]map[Read]*result),
+	}
+}
+
+// SetTTL sets the TTL of subsequent reads.
+func (c *Cache) SetTTL(ttl time.Duration) {
+	if c == nil {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.ttl = ttl
+}
+
+// Read returns the value of the read, performing it with readFunc
+// unless an unexpired result is cached.  Values are shared by all
+// readers and must not be modified.  Errors are not cached, except
+// for the not found error of a get.
+func (c *Cache) Read(key Key, read Read, readFunc func() (interface{}, error)) (interface{}, error) {
+	if c == nil {
+		return readFunc()
+	}
+
+	c.lock.Lock()
+	if c.ttl <= 0 {
+		c.lock.Unlock()
+		return readFunc()
+	}
+	reads, ok := c.reads[key]
+	if !ok {
+		reads = make(map[Read]*result)
+		c.reads[key] = reads
+	}
+	if cached, ok := reads[read]; ok && c.unexpired(cached) {
+		c.lock.Unlock()
+		<-cached.done
+		return cached.value, cached.err
+	}
+	// Discard the expired reads of the resource type so that the reads
+	// of objects that are no longer read do not accumulate.
+	for r, cached := range reads {
+		if !c.unexpired(cached) {
+			delete(reads, r)
+		}
+	}
+	pending := &result{done: make(chan struct{})}
+	reads[read] = pending
+	c.lock.Unlock()
+
+	pending.value, pending.err = readFunc()
+
+	c.lock.Lock()
+	pending.expiry = c.now().Add(c.ttl)
+	if pending.err != nil && !(len(read.Name) > 0 && apierrors.IsNotFound(pending.err)) && reads[read] == pending {
+		delete(reads, read)
+	}
+	c.lock.Unlock()
+	close(pending.done)
+
+	return pending.value, pending.err
+}
+
+// unexpired returns whether the result is pending or has not expired.
+// The lock must be held.
+func (c *Cache) unexpired(r *result) bool {
+	select {
+	case <-r.done:
+		return c.now().Before(r.expiry)
+	default:
+		return true
+	}
+}
+
+// Get returns a copy of the object of the given name, reading it with
+// getFunc unless an unexpired read is cached.
+func (c *Cache) Get(key Key, namespace, name string, getFunc func() (pkgruntime.Object, error)) (pkgruntime.Object, error) {
+	value, err := c.Read(key, Read{Namespace: namespace, Name: name}, func() (interface{}, error) {
+		return getFunc()
+	})
+	return copyObject(value, err)
+}
+
+// List returns a copy of the list of objects matching the options,
+// reading it with listFunc unless an unexpired read is cached.
+func (c *Cache) List(key Key, namespace string, options metav1.ListOptions, listFunc func() (pkgruntime.Object, error)) (pkgruntime.Object, error) {
+	read := Read{
+		Namespace: namespace,
+		Options:   fmt.Sprintf("labelSelector=%s&fieldSelector=%s", options.LabelSelector, options.FieldSelector),
+	}
+	value, err := c.Read(key, read, func() (interface{}, error) {
+		return listFunc()
+	})
+	return copyObject(value, err)
+}
+
+func copyObject(value interface{}, err error) (pkgruntime.Object, error) {
+	if err != nil {
+		return nil, err
+	}
+	obj, ok := value.(pkgruntime.Object)
+	if !ok || obj == nil {
+		return nil, nil
+	}
+	return obj.DeepCopyObject(), nil
+}
+
+// Invalidate discards the cached reads of the resource type in the
+// member cluster that may include the given object: gets of the
+// object and lists of its namespace or of all namespaces.  It is
+// intended to be called with the objects observed by watches of
+// member cluster resources.
+func (c *Cache) Invalidate(key Key, obj interface{}) {
+	if c == nil {
+		return
+	}
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return
+	}
+	namespace, name := accessor.GetNamespace(), accessor.GetName()
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for read := range c.reads[key] {
+		if len(read.Name) > 0 {
+			if read.Namespace == namespace && read.Name == name {
+				delete(c.reads[key], read)
+			}
+		} else if len(read.Namespace) == 0 || read.Namespace == namespace {
+			delete(c.reads[key], read)
+		}
+	}
+}
+
+// Dump writes the number of cached reads of each resource type in
+// each member cluster to a diagnostic dump.
+func (c *Cache) Dump(w io.Writer) {
+	if c == nil {
+		return
+	}
+	c.lock.Lock()
+	lines := []string{}
+	total := 0
+	for key, reads := range c.reads {
+		cached := 0
+		for _, r := range reads {
+			if c.unexpired(r) {
+				cached++
+			}
+		}
+		if cached == 0 {
+			continue
+		}
+		total += cached
+		lines = append(lines, fmt.Sprintf("%s/%s: reads=%d", key.Cluster, key.Resource, cached))
+	}
+	ttl := c.ttl
+	c.lock.Unlock()
+	sort.Strings(lines)
+	fmt.Fprintf(w, "cached member reads: %d (ttl %s)\n", total, ttl)
+	for _, line := range lines {
+		fmt.Fprintln(w, line)
+	}
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memberreads
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	apiv1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+)
+
+var podsKey = Key{Cluster: "cluster1", Resource: "/v1, Resource=pods"}
+
+func newPod(namespace, name string) *apiv1.Pod {
+	return &apiv1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+}
+
+// counter returns a read of the given object and counts its calls.
+type counter struct {
+	lock  sync.Mutex
+	reads int
+}
+
+func (c *counter) read(obj pkgruntime.Object, err error) func() (pkgruntime.Object, error) {
+	return func() (pkgruntime.Object, error) {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+		c.reads++
+		return obj, err
+	}
+}
+
+func (c *counter) count() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.reads
+}
+
+func TestCacheGet(t *testing.T) {
+	now := time.Now()
+	c := New(time.Minute)
+	c.now = func() time.Time { return now }
+	reads := &counter{}
+
+	obj, err := c.Get(podsKey, "ns1", "pod1", reads.read(newPod("ns1", "pod1"), nil))
+	assert.NoError(t, err)
+	obj.(*apiv1.Pod).Labels = map[string]string{"modified": "true"}
+
+	obj, err = c.Get(podsKey, "ns1", "pod1", reads.read(nil, nil))
+	assert.NoError(t, err)
+	assert.Equal(t, newPod("ns1", "pod1"), obj, "Cached object should not be modified by readers")
+	assert.Equal(t, 1, reads.count())
+
+	// Reads of other clusters are not shared.
+	_, err = c.Get(Key{Cluster: "cluster2", Resource: podsKey.Resource}, "ns1", "pod1", reads.read(newPod("ns1", "pod1"), nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, reads.count())
+
+	now = now.Add(time.Minute)
+	_, err = c.Get(podsKey, "ns1", "pod1", reads.read(newPod("ns1", "pod1"), nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, reads.count(), "Expired read should be repeated")
+}
+
+func TestCacheErrors(t *testing.T) {
+	c := New(time.Minute)
+	reads := &counter{}
+	notFound := apierrors.NewNotFound(schema.GroupResource{Resource: "pods"}, "pod1")
+
+	for i := 0; i < 2; i++ {
+		_, err := c.Get(podsKey, "ns1", "pod1", reads.read(nil, notFound))
+		assert.True(t, apierrors.IsNotFound(err))
+	}
+	assert.Equal(t, 1, reads.count(), "Not found error of a get should be cached")
+
+	unavailable := apierrors.NewServiceUnavailable("unavailable")
+	for i := 0; i < 2; i++ {
+		_, err := c.List(podsKey, "ns1", metav1.ListOptions{}, reads.read(nil, unavailable))
+		assert.Error(t, err)
+	}
+	assert.Equal(t, 3, reads.count(), "Other errors should not be cached")
+}
+
+func TestCacheInvalidate(t *testing.T) {
+	c := New(time.Minute)
+	reads := &counter{}
+	pod := newPod("ns1", "pod1")
+	list := &apiv1.PodList{Items: []apiv1.Pod{*pod}}
+	selector := metav1.ListOptions{LabelSelector: "app=a"}
+
+	read := func() {
+		_, err := c.Get(podsKey, "ns1", "pod1", reads.read(pod, nil))
+		assert.NoError(t, err)
+		_, err = c.Get(podsKey, "ns1", "pod2", reads.read(pod, nil))
+		assert.NoError(t, err)
+		_, err = c.List(podsKey, "ns1", selector, reads.read(list, nil))
+		assert.NoError(t, err)
+		_, err = c.List(podsKey, "", selector, reads.read(list, nil))
+		assert.NoError(t, err)
+		_, err = c.List(podsKey, "ns2", selector, reads.read(list, nil))
+		assert.NoError(t, err)
+	}
+	read()
+	assert.Equal(t, 5, reads.count())
+
+	// A change to pod1 invalidates its get and the lists that may
+	// include it.
+	c.Invalidate(podsKey, cache.DeletedFinalStateUnknown{Key: "ns1/pod1", Obj: pod})
+	read()
+	assert.Equal(t, 8, reads.count())
+
+	// Changes in other clusters do not invalidate reads.
+	c.Invalidate(Key{Cluster: "cluster2", Resource: podsKey.Resource}, pod)
+	read()
+	assert.Equal(t, 8, reads.count())
+
+	var dump bytes.Buffer
+	c.Dump(&dump)
+	assert.True(t, strings.Contains(dump.String(), "cluster1//v1, Resource=pods: reads=5"), dump.String())
+}
+
+func TestCacheConcurrentReads(t *testing.T) {
+	c := New(time.Minute)
+	reads := &counter{}
+	release := make(chan struct{})
+	read := func() (pkgruntime.Object, error) {
+		<-release
+		return reads.read(newPod("ns1", "pod1"), nil)()
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			obj, err := c.Get(podsKey, "ns1", "pod1", read)
+			assert.NoError(t, err)
+			assert.Equal(t, newPod("ns1", "pod1"), obj)
+		}()
+	}
+	close(release)
+	wg.Wait()
+	assert.Equal(t, 1, reads.count(), "Concurrent reads should be issued once")
+}
+
+func TestCacheDisabled(t *testing.T) {
+	reads := &counter{}
+	var nilCache *Cache
+	for _, c := range []*Cache{nilCache, New(0)} {
+		for i := 0; i < 2; i++ {
+			_, err := c.Get(podsKey, "ns1", "pod1", reads.read(newPod("ns1", "pod1"), nil))
+			assert.NoError(t, err)
+		}
+		c.Invalidate(podsKey, newPod("ns1", "pod1"))
+		c.SetTTL(time.Minute)
+		c.Dump(&bytes.Buffer{})
+	}
+	assert.Equal(t, 4, reads.count())
+}
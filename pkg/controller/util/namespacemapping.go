@@ -0,0 +1,103 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+// ClusterNamespace returns the name of the namespace in a member
+// cluster that resources in the given host cluster namespace are
+// propagated to.
+func ClusterNamespace(mappings []fedv1b1.NamespaceMapping, namespace string) string {
+	for _, mapping := range mappings {
+		if mapping.Namespace == namespace {
+			return mapping.ClusterNamespace
+		}
+	}
+	return namespace
+}
+
+// HostNamespace returns the name of the namespace in the host cluster
+// whose resources are propagated to the given member cluster
+// namespace.
+func HostNamespace(mappings []fedv1b1.NamespaceMapping, clusterNamespace string) string {
+	for _, mapping := range mappings {
+		if mapping.ClusterNamespace == clusterNamespace {
+			return mapping.Namespace
+		}
+	}
+	return clusterNamespace
+}
+
+// ClusterQualifiedName returns the name that a resource with the given
+// host cluster name is propagated with to a member cluster.  A
+// namespace is renamed rather than being relocated.
+func ClusterQualifiedName(mappings []fedv1b1.NamespaceMapping, targetIsNamespace bool, qualifiedName QualifiedName) QualifiedName {
+	return mapQualifiedName(mappings, targetIsNamespace, qualifiedName, ClusterNamespace)
+}
+
+// HostQualifiedName returns the host cluster name of a resource in a
+// member cluster.
+func HostQualifiedName(mappings []fedv1b1.NamespaceMapping, targetIsNamespace bool, qualifiedName QualifiedName) QualifiedName {
+	return mapQualifiedName(mappings, targetIsNamespace, qualifiedName, HostNamespace)
+}
+
+func mapQualifiedName(mappings []fedv1b1.NamespaceMapping, targetIsNamespace bool, qualifiedName QualifiedName,
+	mapFunc func([]fedv1b1.NamespaceMapping, string) string) QualifiedName {
+
+	if len(mappings) == 0 {
+		return qualifiedName
+	}
+	if targetIsNamespace {
+		qualifiedName.Name = mapFunc(mappings, qualifiedName.Name)
+	} else if len(qualifiedName.Namespace) > 0 {
+		qualifiedName.Namespace = mapFunc(mappings, qualifiedName.Namespace)
+	}
+	return qualifiedName
+}
+
+// clusterKey returns the store key of a member cluster resource given
+// the key of the resource in the host cluster.
+func clusterKey(mappings []fedv1b1.NamespaceMapping, targetIsNamespace bool, key string) string {
+	if len(mappings) == 0 {
+		return key
+	}
+	qualifiedName := QualifiedName{Name: key}
+	if parts := strings.SplitN(key, "/", 2); len(parts) == 2 {
+		qualifiedName = QualifiedName{Namespace: parts[0], Name: parts[1]}
+	}
+	return ClusterQualifiedName(mappings, targetIsNamespace, qualifiedName).String()
+}
+
+// HostQualifiedNameForObject returns the host cluster name of the
+// given member cluster resource, accounting for both the namespace
+// mappings of the cluster and renaming by a cluster override.
+func HostQualifiedNameForObject(mappings []fedv1b1.NamespaceMapping, targetIsNamespace bool, obj pkgruntime.Object) QualifiedName {
+	qualifiedName := HostQualifiedName(mappings, targetIsNamespace, NewQualifiedName(obj))
+	if accessor, err := meta.Accessor(obj); err == nil {
+		if federatedName, ok := accessor.GetAnnotations()[FederatedNameAnnotation]; ok && len(federatedName) > 0 {
+			qualifiedName.Name = federatedName
+		}
+	}
+	return qualifiedName
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+func TestNamespaceMapping(t *testing.T) {
+	mappings := []fedv1b1.NamespaceMapping{
+		{Namespace: "team-a", ClusterNamespace: "team-a-prod"},
+	}
+
+	testCases := map[string]struct {
+		targetIsNamespace bool
+		hostName          QualifiedName
+		clusterName       QualifiedName
+	}{
+		"Mapped namespaced resource is relocated": {
+			hostName:    QualifiedName{Namespace: "team-a", Name: "foo"},
+			clusterName: QualifiedName{Namespace: "team-a-prod", Name: "foo"},
+		},
+		"Unmapped namespaced resource is unchanged": {
+			hostName:    QualifiedName{Namespace: "team-b", Name: "foo"},
+			clusterName: QualifiedName{Namespace: "team-b", Name: "foo"},
+		},
+		"Mapped namespace is renamed": {
+			targetIsNamespace: true,
+			hostName:          QualifiedName{Name: "team-a"},
+			clusterName:       QualifiedName{Name: "team-a-prod"},
+		},
+		"Cluster-scoped resource is unchanged": {
+			hostName:    QualifiedName{Name: "team-a"},
+			clusterName: QualifiedName{Name: "team-a"},
+		},
+	}
+
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			assert.Equal(t, tc.clusterName, ClusterQualifiedName(mappings, tc.targetIsNamespace, tc.hostName))
+			assert.Equal(t, tc.hostName, HostQualifiedName(mappings, tc.targetIsNamespace, tc.clusterName))
+			assert.Equal(t, tc.clusterName.String(), clusterKey(mappings, tc.targetIsNamespace, tc.hostName.String()))
+		})
+	}
+}
+
+func TestHostQualifiedNameForObject(t *testing.T) {
+	mappings := []fedv1b1.NamespaceMapping{
+		{Namespace: "team-a", ClusterNamespace: "team-a-prod"},
+	}
+	obj := &unstructured.Unstructured{}
+	obj.SetNamespace("team-a-prod")
+	obj.SetName("foo-legacy")
+	obj.SetAnnotations(map[string]string{FederatedNameAnnotation: "foo"})
+
+	expectedName := QualifiedName{Namespace: "team-a", Name: "foo"}
+	assert.Equal(t, expectedName, HostQualifiedNameForObject(mappings, false, obj))
+}
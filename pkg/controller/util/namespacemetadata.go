@@ -0,0 +1,84 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+// SyncedNamespaceMetadata returns the labels and annotations of the
+// given namespace in the host cluster that are configured to be
+// synced to the namespaces propagated to member clusters.
+func SyncedNamespaceMetadata(config *fedv1b1.NamespaceMetadataConfig, namespace *unstructured.Unstructured) (labels, annotations map[string]string) {
+	if config == nil || namespace == nil {
+		return nil, nil
+	}
+	return matchingEntries(namespace.GetLabels(), config.Labels), matchingEntries(namespace.GetAnnotations(), config.Annotations)
+}
+
+// AddSyncedNamespaceMetadata sets the given labels and annotations on
+// the given object, retaining the values of keys the object already
+// sets.
+func AddSyncedNamespaceMetadata(obj *unstructured.Unstructured, labels, annotations map[string]string) {
+	if len(labels) > 0 {
+		obj.SetLabels(mergeMissing(obj.GetLabels(), labels))
+	}
+	if len(annotations) > 0 {
+		obj.SetAnnotations(mergeMissing(obj.GetAnnotations(), annotations))
+	}
+}
+
+func matchingEntries(values map[string]string, patterns []string) map[string]string {
+	var matches map[string]string
+	for key, value := range values {
+		for _, pattern := range patterns {
+			if keyMatches(key, pattern) {
+				if matches == nil {
+					matches = make(map[string]string)
+				}
+				matches[key] = value
+				break
+			}
+		}
+	}
+	return matches
+}
+
+// keyMatches indicates whether the given key matches the pattern.  A
+// pattern ending in '*' matches keys with the preceding prefix.
+func keyMatches(key, pattern string) bool {
+	if strings.HasSuffix(pattern, "*") {
+		return strings.HasPrefix(key, strings.TrimSuffix(pattern, "*"))
+	}
+	return key == pattern
+}
+
+func mergeMissing(values, defaults map[string]string) map[string]string {
+	if values == nil {
+		values = make(map[string]string)
+	}
+	for key, value := range defaults {
+		if _, ok := values[key]; !ok {
+			values[key] = value
+		}
+	}
+	return values
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+func TestSyncedNamespaceMetadata(t *testing.T) {
+	namespace := &unstructured.Unstructured{}
+	namespace.SetName("ns")
+	namespace.SetLabels(map[string]string{
+		"pod-security.kubernetes.io/enforce": "restricted",
+		"pod-security.kubernetes.io/warn":    "baseline",
+		"istio-injection":                    "enabled",
+		"team":                               "web",
+	})
+	namespace.SetAnnotations(map[string]string{
+		"scheduler.alpha.kubernetes.io/node-selector": "pool=web",
+		"owner": "web-team",
+	})
+
+	testCases := map[string]struct {
+		config              *fedv1b1.NamespaceMetadataConfig
+		expectedLabels      map[string]string
+		expectedAnnotations map[string]string
+	}{
+		"No configuration": {},
+		"Exact keys and prefixes": {
+			config: &fedv1b1.NamespaceMetadataConfig{
+				Labels:      []string{"pod-security.kubernetes.io/*", "istio-injection", "missing"},
+				Annotations: []string{"owner"},
+			},
+			expectedLabels: map[string]string{
+				"pod-security.kubernetes.io/enforce": "restricted",
+				"pod-security.kubernetes.io/warn":    "baseline",
+				"istio-injection":                    "enabled",
+			},
+			expectedAnnotations: map[string]string{"owner": "web-team"},
+		},
+		"Wildcard matches everything": {
+			config: &fedv1b1.NamespaceMetadataConfig{
+				Annotations: []string{"*"},
+			},
+			expectedAnnotations: map[string]string{
+				"scheduler.alpha.kubernetes.io/node-selector": "pool=web",
+				"owner": "web-team",
+			},
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			labels, annotations := SyncedNamespaceMetadata(tc.config, namespace)
+			assert.Equal(t, tc.expectedLabels, labels)
+			assert.Equal(t, tc.expectedAnnotations, annotations)
+		})
+	}
+}
+
+func TestAddSyncedNamespaceMetadata(t *testing.T) {
+	obj := &unstructured.Unstructured{}
+	obj.SetLabels(map[string]string{"istio-injection": "disabled"})
+
+	AddSyncedNamespaceMetadata(obj,
+		map[string]string{"istio-injection": "enabled", "team": "web"},
+		map[string]string{"owner": "web-team"},
+	)
+	assert.Equal(t, map[string]string{"istio-injection": "disabled", "team": "web"}, obj.GetLabels())
+	assert.Equal(t, map[string]string{"owner": "web-team"}, obj.GetAnnotations())
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+const (
+	// The number of notifications that can be pending delivery
+	// before new notifications are dropped.
+	queueSize = 100
+
+	deliveryTimeout = 10 * time.Second
+)
+
+// Notification describes a transition that external systems should
+// be informed of.
+type Notification struct {
+	Type fedv1b1.NotificationType `json:"type"`
+	// Time is the RFC3339 time at which the transition was observed.
+	Time string `json:"time"`
+	// Cluster is the name of the cluster that transitioned, if any.
+	Cluster string `json:"cluster,omitempty"`
+	// Kind and Name identify the federated resource that
+	// transitioned, if any.
+	Kind string `json:"kind,omitempty"`
+	Name string `json:"name,omitempty"`
+	// Clusters lists the clusters that a federated resource failed
+	// to propagate to.
+	Clusters []string `json:"clusters,omitempty"`
+	Message  string   `json:"message"`
+}
+
+type sink struct {
+	name   string
+	url    string
+	format fedv1b1.NotificationFormat
+	types  sets.String
+}
+
+// Notifier delivers notifications to the sinks configured in a
+// KubeFedConfig.  Delivery is asynchronous so that controllers are
+// not blocked by slow or unavailable sinks.  A nil *Notifier is
+// valid and discards all notifications.
+type Notifier struct {
+	sinks                       []sink
+	propagationFailureThreshold int
+	queue                       chan *Notification
+	client                      *http.Client
+}
+
+// New returns a notifier for the given configuration, or nil if no
+// sinks are configured.
+func New(config *fedv1b1.NotificationConfig) *Notifier {
+	if config == nil || len(config.Sinks) == 0 {
+		return nil
+	}
+	n := &Notifier{
+		propagationFailureThreshold: int(config.PropagationFailureThreshold),
+		queue:                       make(chan *Notification, queueSize),
+		client:                      &http.Client{Timeout: deliveryTimeout},
+	}
+	if n.propagationFailureThreshold < 1 {
+		n.propagationFailureThreshold = 1
+	}
+	for _, s := range config.Sinks {
+		format := s.Format
+		if len(format) == 0 {
+			format = fedv1b1.WebhookNotificationFormat
+		}
+		types := sets.NewString()
+		for _, t := range s.Types {
+			types.Insert(string(t))
+		}
+		n.sinks = append(n.sinks, sink{
+			name:   s.Name,
+			url:    s.URL,
+			format: format,
+			types:  types,
+		})
+	}
+	return n
+}
+
+// Run delivers queued notifications until the stop channel is closed.
+func (n *Notifier) Run(stopChan <-chan struct{}) {
+	if n == nil {
+		return
+	}
+	go func() {
+		for {
+			select {
+			case notification := <-n.queue:
+				n.deliver(notification)
+			case <-stopChan:
+				return
+			}
+		}
+	}()
+}
+
+// PropagationFailureThreshold returns the minimum number of clusters
+// a federated resource must fail to propagate to for a
+// PropagationFailed notification to be sent.
+func (n *Notifier) PropagationFailureThreshold() int {
+	if n == nil {
+		return 1
+	}
+	return n.propagationFailureThreshold
+}
+
+// Notify queues the notification for delivery.  The notification is
+// dropped if the queue is full.
+func (n *Notifier) Notify(notification *Notification) {
+	if n == nil {
+		return
+	}
+	if len(notification.Time) == 0 {
+		notification.Time = time.Now().UTC().Format(time.RFC3339)
+	}
+	select {
+	case n.queue <- notification:
+	default:
+		klog.Warningf("Dropping %s notification: the notification queue is full", notification.Type)
+	}
+}
+
+func (n *Notifier) deliver(notification *Notification) {
+	for _, s := range n.sinks {
+		if s.types.Len() > 0 && !s.types.Has(string(notification.Type)) {
+			continue
+		}
+		if err := n.post(s, notification); err != nil {
+			klog.Errorf("Failed to deliver %s notification to sink %q: %v", notification.Type, s.name, err)
+		}
+	}
+}
+
+func (n *Notifier) post(s sink, notification *Notification) error {
+	payload, err := encodePayload(s.format, notification)
+	if err != nil {
+		return err
+	}
+	resp, err := n.client.Post(s.url, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return errors.Wrap(err, "request failed")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Errorf("unexpected response status %q", resp.Status)
+	}
+	return nil
+}
+
+func encodePayload(format fedv1b1.NotificationFormat, notification *Notification) ([]byte, error) {
+	switch format {
+	case fedv1b1.WebhookNotificationFormat:
+		return json.Marshal(notification)
+	case fedv1b1.SlackNotificationFormat:
+		return json.Marshal(map[string]string{
+			"text": fmt.Sprintf("[KubeFed] %s: %s", notification.Type, notification.Message),
+		})
+	}
+	return nil, errors.Errorf("unsupported notification format %q", format)
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifier
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+func TestNewWithoutSinks(t *testing.T) {
+	assert.Nil(t, New(nil))
+	assert.Nil(t, New(&fedv1b1.NotificationConfig{}))
+
+	// A nil notifier must be safe to use.
+	var n *Notifier
+	n.Run(make(chan struct{}))
+	n.Notify(&Notification{Type: fedv1b1.ClusterReadyNotification})
+	assert.Equal(t, 1, n.PropagationFailureThreshold())
+}
+
+func TestNotify(t *testing.T) {
+	received := make(chan map[string]interface{}, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload := map[string]interface{}{}
+		body, err := ioutil.ReadAll(r.Body)
+		if err == nil {
+			err = json.Unmarshal(body, &payload)
+		}
+		assert.NoError(t, err)
+		payload["path"] = r.URL.Path
+		received <- payload
+	}))
+	defer server.Close()
+
+	n := New(&fedv1b1.NotificationConfig{
+		Sinks: []fedv1b1.NotificationSink{
+			{
+				Name: "webhook",
+				URL:  server.URL + "/webhook",
+			},
+			{
+				Name:   "slack",
+				URL:    server.URL + "/slack",
+				Format: fedv1b1.SlackNotificationFormat,
+				Types:  []fedv1b1.NotificationType{fedv1b1.PropagationFailedNotification},
+			},
+		},
+	})
+	if n == nil {
+		t.Fatal("Expected a notifier to be returned")
+	}
+	assert.Equal(t, 1, n.PropagationFailureThreshold())
+
+	stopChan := make(chan struct{})
+	defer close(stopChan)
+	n.Run(stopChan)
+
+	n.Notify(&Notification{
+		Type:    fedv1b1.ClusterNotReadyNotification,
+		Cluster: "cluster1",
+		Message: "Cluster \"cluster1\" is not ready",
+	})
+	payload := receive(t, received)
+	assert.Equal(t, "/webhook", payload["path"])
+	assert.Equal(t, "ClusterNotReady", payload["type"])
+	assert.Equal(t, "cluster1", payload["cluster"])
+	assert.NotEmpty(t, payload["time"])
+
+	n.Notify(&Notification{
+		Type:    fedv1b1.PropagationFailedNotification,
+		Message: "failed",
+	})
+	paths := map[interface{}]map[string]interface{}{}
+	for i := 0; i < 2; i++ {
+		payload := receive(t, received)
+		paths[payload["path"]] = payload
+	}
+	assert.Equal(t, "PropagationFailed", paths["/webhook"]["type"])
+	assert.Equal(t, "[KubeFed] PropagationFailed: failed", paths["/slack"]["text"])
+}
+
+func receive(t *testing.T, received chan map[string]interface{}) map[string]interface{} {
+	select {
+	case payload := <-received:
+		return payload
+	case <-time.After(wait.ForeverTestTimeout):
+		t.Fatal("Timed out waiting for notification")
+	}
+	return nil
+}
@@ -18,22 +18,63 @@ package util
 
 import (
 	"encoding/json"
+	"strings"
 
 	"github.com/pkg/errors"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/util/sets"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
 )
 
 type ClusterOverride struct {
 	Path  string      `json:"path"`
-	Value interface{} `json:"value"`
+	Value interface{} `json:"value,omitempty"`
+	// The source of the value, which is resolved by the sync
+	// controller each time the resource is propagated.  Mutually
+	// exclusive with the value.
+	ValueFrom *OverrideValueSource `json:"valueFrom,omitempty"`
+}
+
+// OverrideValueSource selects the string value of a cluster override
+// from a key of a ConfigMap or Secret of the host cluster.  Exactly
+// one of its fields must be set.
+type OverrideValueSource struct {
+	// Selects a key of a ConfigMap in the namespace of the federated
+	// resource.
+	ConfigMapKeyRef *corev1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+	// Selects a key of a Secret in the namespace of the federated
+	// resource.
+	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef,omitempty"`
+	// Selects a key of the profile ConfigMap of the cluster the
+	// resource is propagated to.
+	ClusterProfileKeyRef *ClusterProfileKeySelector `json:"clusterProfileKeyRef,omitempty"`
+}
+
+// ClusterProfileKeySelector selects a key of the profile ConfigMap
+// of a cluster.
+type ClusterProfileKeySelector struct {
+	// The key to select.
+	Key string `json:"key"`
+	// Whether the override is skipped for a cluster that has no
+	// profile or whose profile does not define the key.
+	Optional *bool `json:"optional,omitempty"`
 }
 
 type GenericOverrideItem struct {
 	ClusterName      string            `json:"clusterName"`
 	ClusterOverrides []ClusterOverride `json:"clusterOverrides,omitempty"`
+	// A values file merged into the chart values of the target
+	// resource for the cluster.  Only supported for target types
+	// whose FederatedTypeConfig configures the location of their
+	// values.
+	Values string `json:"values,omitempty"`
+	// Patches applied in order to the target resource for the
+	// cluster after its cluster overrides.
+	Patches []ClusterPatch `json:"patches,omitempty"`
 }
 
 type GenericOverrideSpec struct {
@@ -47,15 +88,22 @@ type GenericOverride struct {
 	Spec *GenericOverrideSpec `json:"spec,omitempty"`
 }
 
-// Namespace and name may not be overridden since these fields are the
-// primary mechanism of association between a federated resource in
-// the host cluster and the target resources in the member clusters.
+// NameOverridePath is the path of a cluster override that renames the
+// target resource in the cluster.
+const NameOverridePath = "metadata.name"
+
+// Namespace may not be overridden since it is the primary mechanism
+// of association between a federated resource in the host cluster and
+// the target resources in the member clusters.  The name may only be
+// overridden for a specific cluster, since renamed resources must be
+// tracked by the sync controller.
 var invalidPaths = sets.NewString(
 	"metadata.namespace",
-	"metadata.name",
 	"metadata.generateName",
 )
 
+var invalidDefaultOverridePaths = invalidPaths.Union(sets.NewString(NameOverridePath))
+
 // Mapping of qualified path (e.g. spec.replicas) to value
 type ClusterOverridesMap map[string]interface{}
 
@@ -110,16 +158,42 @@ func GetOverrides(rawObj *unstructured.Unstructured) (OverridesMap, error) {
 		}
 		overridesMap[clusterName] = make(ClusterOverridesMap)
 
+		if len(overrideItem.Values) > 0 {
+			if _, err := ParseValues(overrideItem.Values); err != nil {
+				return nil, errors.Wrapf(err, "values for cluster %q are invalid", clusterName)
+			}
+		}
+		for i, patch := range overrideItem.Patches {
+			if _, _, err := decodePatch(patch); err != nil {
+				return nil, errors.Wrapf(err, "patch[%d] for cluster %q is invalid", i, clusterName)
+			}
+		}
+
 		clusterOverrides := overrideItem.ClusterOverrides
 
+		// Overrides whose value is resolved from a source are not
+		// included in the map.
+		sourcedPaths := sets.NewString()
 		for i, clusterOverride := range clusterOverrides {
 			path := clusterOverride.Path
 			if invalidPaths.Has(path) {
 				return nil, errors.Errorf("override[%d] for cluster %q has an invalid path: %s", i, clusterName, path)
 			}
-			if _, ok := overridesMap[clusterName][path]; ok {
+			if _, ok := overridesMap[clusterName][path]; ok || sourcedPaths.Has(path) {
 				return nil, errors.Errorf("path %q appears more than once for cluster %q", path, clusterName)
 			}
+			if clusterOverride.ValueFrom != nil {
+				if err := validateOverrideValueSource(clusterOverride); err != nil {
+					return nil, errors.Wrapf(err, "override[%d] for cluster %q is invalid", i, clusterName)
+				}
+				sourcedPaths.Insert(path)
+				continue
+			}
+			if path == NameOverridePath {
+				if name, ok := clusterOverride.Value.(string); !ok || len(name) == 0 {
+					return nil, errors.Errorf("override[%d] for cluster %q must specify a non-empty string value for path %s", i, clusterName, path)
+				}
+			}
 
 			overridesMap[clusterName][path] = clusterOverride.Value
 		}
@@ -128,8 +202,43 @@ func GetOverrides(rawObj *unstructured.Unstructured) (OverridesMap, error) {
 	return overridesMap, nil
 }
 
+func validateOverrideValueSource(override ClusterOverride) error {
+	if override.Value != nil {
+		return errors.New("value and valueFrom are mutually exclusive")
+	}
+	if override.Path == NameOverridePath {
+		return errors.Errorf("the value of path %s cannot be sourced", NameOverridePath)
+	}
+	source := override.ValueFrom
+	refs := 0
+	if ref := source.ConfigMapKeyRef; ref != nil {
+		refs++
+		if len(ref.Name) == 0 || len(ref.Key) == 0 {
+			return errors.New("configMapKeyRef must specify a name and key")
+		}
+	}
+	if ref := source.SecretKeyRef; ref != nil {
+		refs++
+		if len(ref.Name) == 0 || len(ref.Key) == 0 {
+			return errors.New("secretKeyRef must specify a name and key")
+		}
+	}
+	if ref := source.ClusterProfileKeyRef; ref != nil {
+		refs++
+		if len(ref.Key) == 0 {
+			return errors.New("clusterProfileKeyRef must specify a key")
+		}
+	}
+	if refs != 1 {
+		return errors.New("valueFrom must specify exactly one of configMapKeyRef, secretKeyRef and clusterProfileKeyRef")
+	}
+	return nil
+}
+
 // SetOverrides sets the spec.overrides field of the unstructured
-// object from the provided overrides map.
+// object from the provided overrides map.  The values files, patches
+// and overrides with a value source of the clusters that remain in
+// the map are retained, unless the map overrides the same path.
 func SetOverrides(fedObject *unstructured.Unstructured, overridesMap OverridesMap) error {
 	rawSpec := fedObject.Object[SpecField]
 	if rawSpec == nil {
@@ -141,7 +250,52 @@ func SetOverrides(fedObject *unstructured.Unstructured, overridesMap OverridesMa
 	if !ok {
 		return errors.Errorf("Unable to set overrides since %q is not an object: %T", SpecField, rawSpec)
 	}
-	spec[OverridesField] = overridesMap.ToUnstructuredSlice()
+	retainedFields := map[string]map[string]interface{}{}
+	retainedOverrides := map[string][]map[string]interface{}{}
+	existing, _ := spec[OverridesField].([]interface{})
+	for _, rawItem := range existing {
+		item, ok := rawItem.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		clusterName, _ := item[ClusterNameField].(string)
+		for _, field := range []string{ValuesField, PatchesField} {
+			if value, ok := item[field]; ok {
+				if retainedFields[clusterName] == nil {
+					retainedFields[clusterName] = map[string]interface{}{}
+				}
+				retainedFields[clusterName][field] = value
+			}
+		}
+		clusterOverrides, _ := item[ClusterOverridesField].([]interface{})
+		for _, rawOverride := range clusterOverrides {
+			override, ok := rawOverride.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if _, ok := override[ValueFromField]; ok {
+				retainedOverrides[clusterName] = append(retainedOverrides[clusterName], override)
+			}
+		}
+	}
+
+	overrides := overridesMap.ToUnstructuredSlice()
+	for _, rawItem := range overrides {
+		item := rawItem.(map[string]interface{})
+		clusterName := item[ClusterNameField].(string)
+		for field, value := range retainedFields[clusterName] {
+			item[field] = value
+		}
+		clusterOverrides := item[ClusterOverridesField].([]map[string]interface{})
+		for _, override := range retainedOverrides[clusterName] {
+			path, _ := override[PathField].(string)
+			if _, ok := overridesMap[clusterName][path]; !ok {
+				clusterOverrides = append(clusterOverrides, override)
+			}
+		}
+		item[ClusterOverridesField] = clusterOverrides
+	}
+	spec[OverridesField] = overrides
 	return nil
 }
 
@@ -154,3 +308,28 @@ func UnstructuredToInterface(rawObj *unstructured.Unstructured, obj interface{})
 	}
 	return json.Unmarshal(content, obj)
 }
+
+// ApplyDefaultOverrides applies the default overrides of a federated
+// type config to the given unstructured object in order.
+func ApplyDefaultOverrides(obj *unstructured.Unstructured, defaultOverrides []fedv1b1.DefaultOverride) error {
+	for i, override := range defaultOverrides {
+		if invalidDefaultOverridePaths.Has(override.Path) {
+			return errors.Errorf("default override[%d] has an invalid path: %s", i, override.Path)
+		}
+		pathEntries := strings.Split(override.Path, ".")
+		switch override.Operation {
+		case fedv1b1.DefaultOverrideRemove:
+			unstructured.RemoveNestedField(obj.Object, pathEntries...)
+		case "", fedv1b1.DefaultOverrideSet:
+			if override.Value == nil {
+				return errors.Errorf("default override[%d] for path %q does not specify a value", i, override.Path)
+			}
+			if err := unstructured.SetNestedField(obj.Object, *override.Value, pathEntries...); err != nil {
+				return errors.Wrapf(err, "failed to apply default override[%d] for path %q", i, override.Path)
+			}
+		default:
+			return errors.Errorf("default override[%d] has an unsupported operation: %s", i, override.Operation)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+func TestApplyDefaultOverrides(t *testing.T) {
+	team := "platform"
+	testCases := map[string]struct {
+		defaultOverrides []fedv1b1.DefaultOverride
+		expectedObj      map[string]interface{}
+		expectedErr      bool
+	}{
+		"Fields are set and removed in order": {
+			defaultOverrides: []fedv1b1.DefaultOverride{
+				{
+					Path:  "metadata.labels.team",
+					Value: &team,
+				},
+				{
+					Path:      "spec.template.spec.nodeName",
+					Operation: fedv1b1.DefaultOverrideRemove,
+				},
+				{
+					Path:      "spec.notPresent",
+					Operation: fedv1b1.DefaultOverrideRemove,
+				},
+			},
+			expectedObj: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"name":   "foo",
+					"labels": map[string]interface{}{"team": "platform"},
+				},
+				"spec": map[string]interface{}{
+					"template": map[string]interface{}{
+						"spec": map[string]interface{}{},
+					},
+				},
+			},
+		},
+		"Name cannot be overridden": {
+			defaultOverrides: []fedv1b1.DefaultOverride{
+				{
+					Path:  "metadata.name",
+					Value: &team,
+				},
+			},
+			expectedErr: true,
+		},
+		"Set requires a value": {
+			defaultOverrides: []fedv1b1.DefaultOverride{
+				{
+					Path: "metadata.labels.team",
+				},
+			},
+			expectedErr: true,
+		},
+	}
+
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			obj := &unstructured.Unstructured{Object: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"name": "foo",
+				},
+				"spec": map[string]interface{}{
+					"template": map[string]interface{}{
+						"spec": map[string]interface{}{
+							"nodeName": "node1",
+						},
+					},
+				},
+			}}
+			err := ApplyDefaultOverrides(obj, tc.defaultOverrides)
+			if tc.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedObj, obj.Object)
+		})
+	}
+}
+
+func TestGetOverridesName(t *testing.T) {
+	testCases := map[string]struct {
+		path        string
+		value       interface{}
+		expectedErr bool
+	}{
+		"Name can be overridden for a cluster": {
+			path:  NameOverridePath,
+			value: "bar",
+		},
+		"Name override must be a string": {
+			path:        NameOverridePath,
+			value:       int64(1),
+			expectedErr: true,
+		},
+		"Namespace cannot be overridden": {
+			path:        "metadata.namespace",
+			value:       "bar",
+			expectedErr: true,
+		},
+	}
+
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			obj := &unstructured.Unstructured{Object: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"overrides": []interface{}{
+						map[string]interface{}{
+							"clusterName": "cluster1",
+							"clusterOverrides": []interface{}{
+								map[string]interface{}{
+									"path":  tc.path,
+									"value": tc.value,
+								},
+							},
+						},
+					},
+				},
+			}}
+			overridesMap, err := GetOverrides(obj)
+			if tc.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.value, overridesMap["cluster1"][tc.path])
+		})
+	}
+}
+
+func TestSetOverridesRetainsValuesAndPatches(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"overrides": []interface{}{
+				map[string]interface{}{
+					"clusterName": "cluster1",
+					"values":      "replicaCount: 2\n",
+					"patches":     []interface{}{map[string]interface{}{"patch": "spec: {}"}},
+				},
+				map[string]interface{}{
+					"clusterName": "cluster2",
+					"values":      "replicaCount: 3\n",
+				},
+			},
+		},
+	}}
+	overridesMap, err := GetOverrides(obj)
+	assert.NoError(t, err)
+	delete(overridesMap, "cluster2")
+	overridesMap["cluster1"]["spec.suspend"] = true
+
+	assert.NoError(t, SetOverrides(obj, overridesMap))
+	overrides, _, _ := unstructured.NestedFieldNoCopy(obj.Object, "spec", "overrides")
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{
+			"clusterName": "cluster1",
+			"clusterOverrides": []map[string]interface{}{
+				{"path": "spec.suspend", "value": true},
+			},
+			"values":  "replicaCount: 2\n",
+			"patches": []interface{}{map[string]interface{}{"patch": "spec: {}"}},
+		},
+	}, overrides)
+}
+
+func TestGetOverridesValues(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"overrides": []interface{}{
+				map[string]interface{}{
+					"clusterName": "cluster1",
+					"values":      "- not a map",
+				},
+			},
+		},
+	}}
+	_, err := GetOverrides(obj)
+	assert.Error(t, err)
+}
@@ -0,0 +1,229 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+)
+
+// GetValueFromOverrides returns a map of cluster name to the value
+// sources of the overrides of the cluster, keyed by path, defined by
+// the given federated resource.
+func GetValueFromOverrides(rawObj *unstructured.Unstructured) (map[string]map[string]*OverrideValueSource, error) {
+	sourcesMap := make(map[string]map[string]*OverrideValueSource)
+	if rawObj == nil {
+		return sourcesMap, nil
+	}
+
+	override := GenericOverride{}
+	if err := UnstructuredToInterface(rawObj, &override); err != nil {
+		return nil, err
+	}
+	if override.Spec == nil {
+		return sourcesMap, nil
+	}
+	for _, overrideItem := range override.Spec.Overrides {
+		for _, clusterOverride := range overrideItem.ClusterOverrides {
+			if clusterOverride.ValueFrom == nil {
+				continue
+			}
+			if sourcesMap[overrideItem.ClusterName] == nil {
+				sourcesMap[overrideItem.ClusterName] = make(map[string]*OverrideValueSource)
+			}
+			sourcesMap[overrideItem.ClusterName][clusterOverride.Path] = clusterOverride.ValueFrom
+		}
+	}
+	return sourcesMap, nil
+}
+
+// OverrideValueResolver resolves the value sources of cluster
+// overrides from the ConfigMaps and Secrets of the host cluster.  The
+// objects it retrieves are cached for its lifetime so that a resource
+// is rendered consistently for all clusters, which makes a resolver
+// suitable for a single reconciliation.
+type OverrideValueResolver struct {
+	sync.Mutex
+
+	client genericclient.Client
+
+	// The namespace of the KubeFed control plane, which holds the
+	// profile ConfigMaps of clusters.
+	kubefedNamespace string
+
+	// Retrieved objects keyed by kind and qualified name.  A nil
+	// object was not found.
+	objects map[string]*valueSourceObject
+}
+
+type valueSourceObject struct {
+	resourceVersion string
+	data            map[string]string
+}
+
+func NewOverrideValueResolver(client genericclient.Client, kubefedNamespace string) *OverrideValueResolver {
+	return &OverrideValueResolver{
+		client:           client,
+		kubefedNamespace: kubefedNamespace,
+		objects:          make(map[string]*valueSourceObject),
+	}
+}
+
+// Resolve returns the value of the given source for a resource in the
+// given namespace that is propagated to the given cluster.  False is
+// returned if the value is optional and could not be found, in which
+// case the override should be skipped.
+func (r *OverrideValueResolver) Resolve(namespace string, cluster *fedv1b1.KubeFedCluster, source *OverrideValueSource) (string, bool, error) {
+	kind, name, key, optional, err := r.reference(namespace, cluster, source)
+	if err != nil {
+		return "", false, err
+	}
+	if len(name.Name) == 0 {
+		// The cluster has no profile.
+		if optional {
+			return "", false, nil
+		}
+		return "", false, errors.Errorf("cluster %q does not have a profile", cluster.Name)
+	}
+	obj, err := r.get(kind, name)
+	if err != nil {
+		return "", false, err
+	}
+	if obj == nil {
+		if optional {
+			return "", false, nil
+		}
+		return "", false, errors.Errorf("%s %q not found", kind, name)
+	}
+	value, ok := obj.data[key]
+	if !ok {
+		if optional {
+			return "", false, nil
+		}
+		return "", false, errors.Errorf("key %q not found in %s %q", key, kind, name)
+	}
+	return value, true, nil
+}
+
+// Versions returns the resource versions of the objects referenced by
+// the given sources for a resource in the given namespace that is
+// propagated to the given cluster, keyed by kind and qualified name.
+// Missing objects have an empty version.  The versions change when
+// the resolved values may have changed without revealing the values
+// themselves.
+func (r *OverrideValueResolver) Versions(namespace string, cluster *fedv1b1.KubeFedCluster, sources []*OverrideValueSource) (map[string]string, error) {
+	versions := make(map[string]string)
+	for _, source := range sources {
+		kind, name, _, _, err := r.reference(namespace, cluster, source)
+		if err != nil || len(name.Name) == 0 {
+			// The error is reported when the value is resolved.
+			continue
+		}
+		obj, err := r.get(kind, name)
+		if err != nil {
+			return nil, err
+		}
+		version := ""
+		if obj != nil {
+			version = obj.resourceVersion
+		}
+		versions[fmt.Sprintf("%s/%s", kind, name)] = version
+	}
+	return versions, nil
+}
+
+// reference returns the kind and name of the object and the key
+// selected by the given source.  The name is empty if the source is
+// the profile of a cluster that does not have one.
+func (r *OverrideValueResolver) reference(namespace string, cluster *fedv1b1.KubeFedCluster, source *OverrideValueSource) (string, QualifiedName, string, bool, error) {
+	switch {
+	case source.ConfigMapKeyRef != nil:
+		ref := source.ConfigMapKeyRef
+		if len(namespace) == 0 {
+			return "", QualifiedName{}, "", false, errors.New("a ConfigMap can only be referenced by the overrides of a namespaced resource")
+		}
+		return ConfigMapKind, QualifiedName{Namespace: namespace, Name: ref.Name}, ref.Key, isOptional(ref.Optional), nil
+	case source.SecretKeyRef != nil:
+		ref := source.SecretKeyRef
+		if len(namespace) == 0 {
+			return "", QualifiedName{}, "", false, errors.New("a Secret can only be referenced by the overrides of a namespaced resource")
+		}
+		return SecretKind, QualifiedName{Namespace: namespace, Name: ref.Name}, ref.Key, isOptional(ref.Optional), nil
+	case source.ClusterProfileKeyRef != nil:
+		ref := source.ClusterProfileKeyRef
+		name := QualifiedName{Namespace: r.kubefedNamespace}
+		if cluster.Spec.ProfileRef != nil {
+			name.Name = cluster.Spec.ProfileRef.Name
+		}
+		return ConfigMapKind, name, ref.Key, isOptional(ref.Optional), nil
+	}
+	return "", QualifiedName{}, "", false, errors.New("the value source does not reference a ConfigMap or Secret")
+}
+
+func isOptional(optional *bool) bool {
+	return optional != nil && *optional
+}
+
+func (r *OverrideValueResolver) get(kind string, name QualifiedName) (*valueSourceObject, error) {
+	r.Lock()
+	defer r.Unlock()
+	cacheKey := fmt.Sprintf("%s/%s", kind, name)
+	if obj, ok := r.objects[cacheKey]; ok {
+		return obj, nil
+	}
+
+	obj := &valueSourceObject{}
+	var err error
+	switch kind {
+	case ConfigMapKind:
+		configMap := &corev1.ConfigMap{}
+		err = r.client.Get(context.TODO(), configMap, name.Namespace, name.Name)
+		if err == nil {
+			obj.resourceVersion = configMap.ResourceVersion
+			obj.data = configMap.Data
+		}
+	case SecretKind:
+		secret := &corev1.Secret{}
+		err = r.client.Get(context.TODO(), secret, name.Namespace, name.Name)
+		if err == nil {
+			obj.resourceVersion = secret.ResourceVersion
+			obj.data = make(map[string]string, len(secret.Data))
+			for key, value := range secret.Data {
+				obj.data[key] = string(value)
+			}
+		}
+	}
+	if apierrors.IsNotFound(err) {
+		r.objects[cacheKey] = nil
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to retrieve %s %q", kind, name)
+	}
+	r.objects[cacheKey] = obj
+	return obj, nil
+}
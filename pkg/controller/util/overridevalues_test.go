@@ -0,0 +1,305 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+// valueSourceClient is a generic client that serves gets of
+// ConfigMaps and Secrets and counts them.
+type valueSourceClient struct {
+	configMaps []*corev1.ConfigMap
+	secrets    []*corev1.Secret
+	gets       int
+}
+
+func (c *valueSourceClient) Create(ctx context.Context, obj pkgruntime.Object) error {
+	return errors.New("not implemented")
+}
+
+func (c *valueSourceClient) Get(ctx context.Context, obj pkgruntime.Object, namespace, name string) error {
+	c.gets++
+	switch target := obj.(type) {
+	case *corev1.ConfigMap:
+		for _, configMap := range c.configMaps {
+			if configMap.Namespace == namespace && configMap.Name == name {
+				configMap.DeepCopyInto(target)
+				return nil
+			}
+		}
+	case *corev1.Secret:
+		for _, secret := range c.secrets {
+			if secret.Namespace == namespace && secret.Name == name {
+				secret.DeepCopyInto(target)
+				return nil
+			}
+		}
+	}
+	return apierrors.NewNotFound(schema.GroupResource{}, name)
+}
+
+func (c *valueSourceClient) Update(ctx context.Context, obj pkgruntime.Object) error {
+	return errors.New("not implemented")
+}
+
+func (c *valueSourceClient) Delete(ctx context.Context, obj pkgruntime.Object, namespace, name string) error {
+	return errors.New("not implemented")
+}
+
+func (c *valueSourceClient) List(ctx context.Context, obj pkgruntime.Object, namespace string) error {
+	return errors.New("not implemented")
+}
+
+func (c *valueSourceClient) UpdateStatus(ctx context.Context, obj pkgruntime.Object) error {
+	return errors.New("not implemented")
+}
+
+func TestOverrideValueResolver(t *testing.T) {
+	optional := true
+	client := &valueSourceClient{
+		configMaps: []*corev1.ConfigMap{
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "env", ResourceVersion: "1"},
+				Data:       map[string]string{"domain": "team-a.example.com"},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "kube-federation-system", Name: "cluster1-profile", ResourceVersion: "2"},
+				Data:       map[string]string{"registry": "registry.cluster1.example.com"},
+			},
+		},
+		secrets: []*corev1.Secret{
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "credentials", ResourceVersion: "3"},
+				Data:       map[string][]byte{"token": []byte("s3cr3t")},
+			},
+		},
+	}
+	cluster1 := &fedv1b1.KubeFedCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster1"},
+		Spec: fedv1b1.KubeFedClusterSpec{
+			ProfileRef: &corev1.LocalObjectReference{Name: "cluster1-profile"},
+		},
+	}
+	cluster2 := &fedv1b1.KubeFedCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster2"},
+	}
+	configMapRef := func(name, key string, optional *bool) *OverrideValueSource {
+		return &OverrideValueSource{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: name},
+			Key:                  key,
+			Optional:             optional,
+		}}
+	}
+	profileRef := func(key string, optional *bool) *OverrideValueSource {
+		return &OverrideValueSource{ClusterProfileKeyRef: &ClusterProfileKeySelector{Key: key, Optional: optional}}
+	}
+
+	testCases := map[string]struct {
+		namespace     string
+		cluster       *fedv1b1.KubeFedCluster
+		source        *OverrideValueSource
+		expectedValue string
+		expectedFound bool
+		expectedErr   string
+	}{
+		"configmap key": {
+			namespace:     "team-a",
+			cluster:       cluster1,
+			source:        configMapRef("env", "domain", nil),
+			expectedValue: "team-a.example.com",
+			expectedFound: true,
+		},
+		"secret key": {
+			namespace: "team-a",
+			cluster:   cluster1,
+			source: &OverrideValueSource{SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "credentials"},
+				Key:                  "token",
+			}},
+			expectedValue: "s3cr3t",
+			expectedFound: true,
+		},
+		"cluster profile key": {
+			namespace:     "team-a",
+			cluster:       cluster1,
+			source:        profileRef("registry", nil),
+			expectedValue: "registry.cluster1.example.com",
+			expectedFound: true,
+		},
+		"configmap in another namespace": {
+			namespace:   "team-b",
+			cluster:     cluster1,
+			source:      configMapRef("env", "domain", nil),
+			expectedErr: `ConfigMap "team-b/env" not found`,
+		},
+		"missing key": {
+			namespace:   "team-a",
+			cluster:     cluster1,
+			source:      configMapRef("env", "suffix", nil),
+			expectedErr: `key "suffix" not found in ConfigMap "team-a/env"`,
+		},
+		"missing optional key": {
+			namespace: "team-a",
+			cluster:   cluster1,
+			source:    configMapRef("env", "suffix", &optional),
+		},
+		"cluster without profile": {
+			namespace:   "team-a",
+			cluster:     cluster2,
+			source:      profileRef("registry", nil),
+			expectedErr: `cluster "cluster2" does not have a profile`,
+		},
+		"optional key of cluster without profile": {
+			namespace: "team-a",
+			cluster:   cluster2,
+			source:    profileRef("registry", &optional),
+		},
+		"configmap referenced by cluster-scoped resource": {
+			cluster:     cluster1,
+			source:      configMapRef("env", "domain", nil),
+			expectedErr: "a ConfigMap can only be referenced by the overrides of a namespaced resource",
+		},
+	}
+	resolver := NewOverrideValueResolver(client, "kube-federation-system")
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			value, found, err := resolver.Resolve(tc.namespace, tc.cluster, tc.source)
+			if len(tc.expectedErr) > 0 {
+				assert.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedValue, value)
+			assert.Equal(t, tc.expectedFound, found)
+		})
+	}
+
+	// Objects are retrieved once, whether or not they are found.
+	gets := client.gets
+	_, _, _ = resolver.Resolve("team-a", cluster1, configMapRef("env", "domain", nil))
+	_, _, _ = resolver.Resolve("team-b", cluster1, configMapRef("env", "domain", nil))
+	assert.Equal(t, gets, client.gets)
+
+	versions, err := resolver.Versions("team-a", cluster1, []*OverrideValueSource{
+		configMapRef("env", "domain", nil),
+		configMapRef("missing", "domain", nil),
+		profileRef("registry", nil),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"ConfigMap/team-a/env":                              "1",
+		"ConfigMap/team-a/missing":                          "",
+		"ConfigMap/kube-federation-system/cluster1-profile": "2",
+	}, versions)
+}
+
+func TestGetOverridesValueFrom(t *testing.T) {
+	newFederatedResource := func(clusterOverrides ...interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"overrides": []interface{}{
+					map[string]interface{}{
+						"clusterName":      "cluster1",
+						"clusterOverrides": clusterOverrides,
+					},
+				},
+			},
+		}}
+	}
+	configMapKeyRef := map[string]interface{}{
+		"configMapKeyRef": map[string]interface{}{"name": "env", "key": "domain"},
+	}
+
+	obj := newFederatedResource(
+		map[string]interface{}{"path": "spec.replicas", "value": int64(2)},
+		map[string]interface{}{"path": "spec.domain", "valueFrom": configMapKeyRef},
+	)
+	overridesMap, err := GetOverrides(obj)
+	assert.NoError(t, err)
+	assert.Equal(t, OverridesMap{"cluster1": ClusterOverridesMap{"spec.replicas": float64(2)}}, overridesMap)
+	sourcesMap, err := GetValueFromOverrides(obj)
+	assert.NoError(t, err)
+	assert.Equal(t, "env", sourcesMap["cluster1"]["spec.domain"].ConfigMapKeyRef.Name)
+
+	// Overrides with a value source are retained when the overrides
+	// are set, unless their path is overridden by a value.
+	assert.NoError(t, SetOverrides(obj, OverridesMap{"cluster1": ClusterOverridesMap{"spec.replicas": int64(3)}}))
+	sourcesMap, err = GetValueFromOverrides(obj)
+	assert.NoError(t, err)
+	assert.Len(t, sourcesMap["cluster1"], 1)
+	assert.NoError(t, SetOverrides(obj, OverridesMap{"cluster1": ClusterOverridesMap{"spec.domain": "example.com"}}))
+	sourcesMap, err = GetValueFromOverrides(obj)
+	assert.NoError(t, err)
+	assert.Len(t, sourcesMap, 0)
+
+	testCases := map[string]struct {
+		clusterOverride map[string]interface{}
+		expectedErr     string
+	}{
+		"value and value source": {
+			clusterOverride: map[string]interface{}{"path": "spec.domain", "value": "example.com", "valueFrom": configMapKeyRef},
+			expectedErr:     "value and valueFrom are mutually exclusive",
+		},
+		"no reference": {
+			clusterOverride: map[string]interface{}{"path": "spec.domain", "valueFrom": map[string]interface{}{}},
+			expectedErr:     "valueFrom must specify exactly one of configMapKeyRef, secretKeyRef and clusterProfileKeyRef",
+		},
+		"two references": {
+			clusterOverride: map[string]interface{}{"path": "spec.domain", "valueFrom": map[string]interface{}{
+				"configMapKeyRef":      map[string]interface{}{"name": "env", "key": "domain"},
+				"clusterProfileKeyRef": map[string]interface{}{"key": "domain"},
+			}},
+			expectedErr: "valueFrom must specify exactly one of configMapKeyRef, secretKeyRef and clusterProfileKeyRef",
+		},
+		"secret without key": {
+			clusterOverride: map[string]interface{}{"path": "spec.domain", "valueFrom": map[string]interface{}{
+				"secretKeyRef": map[string]interface{}{"name": "credentials"},
+			}},
+			expectedErr: "secretKeyRef must specify a name and key",
+		},
+		"name": {
+			clusterOverride: map[string]interface{}{"path": "metadata.name", "valueFrom": configMapKeyRef},
+			expectedErr:     "the value of path metadata.name cannot be sourced",
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := GetOverrides(newFederatedResource(tc.clusterOverride))
+			assert.EqualError(t, err, `override[0] for cluster "cluster1" is invalid: `+tc.expectedErr)
+		})
+	}
+
+	_, err = GetOverrides(newFederatedResource(
+		map[string]interface{}{"path": "spec.domain", "valueFrom": configMapKeyRef},
+		map[string]interface{}{"path": "spec.domain", "value": "example.com"},
+	))
+	assert.EqualError(t, err, `path "spec.domain" appears more than once for cluster "cluster1"`)
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ownership
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// Tracker tracks the federated resources that render to a resource in
+// a member cluster that is managed by another federated resource.  The
+// federated resource that manages the resource is its owner and the
+// other federated resources are its contenders.  A single tracker is
+// shared by the sync controllers of all federated types so that the
+// owner of a conflict can report it even if it is of another type.
+// Federated resources are identified by their owner keys.  A nil
+// *Tracker is valid and records no conflicts.
+type Tracker struct {
+	sync.Mutex
+
+	// The clusters in which each contender could not manage a
+	// resource, by contender and owner.
+	conflicts map[string]map[string]sets.String
+
+	listeners      map[int]func(owner string)
+	nextListenerID int
+}
+
+// NewTracker returns a tracker without conflicts.
+func NewTracker() *Tracker {
+	return &Tracker{
+		conflicts: make(map[string]map[string]sets.String),
+		listeners: make(map[int]func(owner string)),
+	}
+}
+
+// AddListener registers a function that is invoked with the key of an
+// owner whose contenders changed.  The returned function unregisters
+// the listener.
+func (t *Tracker) AddListener(listener func(owner string)) func() {
+	if t == nil {
+		return func() {}
+	}
+	t.Lock()
+	defer t.Unlock()
+	id := t.nextListenerID
+	t.nextListenerID++
+	t.listeners[id] = listener
+	return func() {
+		t.Lock()
+		defer t.Unlock()
+		delete(t.listeners, id)
+	}
+}
+
+// SetConflicts records the owner of the resource in each named cluster
+// that the given contender could not manage, replacing the conflicts
+// previously recorded for the contender.
+func (t *Tracker) SetConflicts(contender string, ownersByCluster map[string]string) {
+	if t == nil {
+		return
+	}
+	conflicts := make(map[string]sets.String)
+	for clusterName, owner := range ownersByCluster {
+		if _, ok := conflicts[owner]; !ok {
+			conflicts[owner] = sets.NewString()
+		}
+		conflicts[owner].Insert(clusterName)
+	}
+
+	t.Lock()
+	previous := t.conflicts[contender]
+	if len(conflicts) == 0 {
+		delete(t.conflicts, contender)
+	} else {
+		t.conflicts[contender] = conflicts
+	}
+	changed := sets.NewString()
+	for owner, clusterNames := range conflicts {
+		if previousClusterNames, ok := previous[owner]; !ok || !previousClusterNames.Equal(clusterNames) {
+			changed.Insert(owner)
+		}
+	}
+	for owner := range previous {
+		if _, ok := conflicts[owner]; !ok {
+			changed.Insert(owner)
+		}
+	}
+	t.Unlock()
+
+	for _, owner := range changed.List() {
+		t.notify(owner)
+	}
+}
+
+// Release removes the conflicts recorded for the given contender,
+// e.g. once it is deleted.
+func (t *Tracker) Release(contender string) {
+	t.SetConflicts(contender, nil)
+}
+
+// Conflicts returns the sorted names of the clusters in which the
+// given contender could not manage a resource, by owner.
+func (t *Tracker) Conflicts(contender string) map[string][]string {
+	if t == nil {
+		return nil
+	}
+	t.Lock()
+	defer t.Unlock()
+	var result map[string][]string
+	for owner, clusterNames := range t.conflicts[contender] {
+		if result == nil {
+			result = make(map[string][]string)
+		}
+		result[owner] = clusterNames.List()
+	}
+	return result
+}
+
+// Contenders returns the sorted names of the clusters in which each
+// contender could not manage a resource owned by the given owner, by
+// contender.
+func (t *Tracker) Contenders(owner string) map[string][]string {
+	if t == nil {
+		return nil
+	}
+	t.Lock()
+	defer t.Unlock()
+	var result map[string][]string
+	for contender, conflicts := range t.conflicts {
+		clusterNames, ok := conflicts[owner]
+		if !ok {
+			continue
+		}
+		if result == nil {
+			result = make(map[string][]string)
+		}
+		result[contender] = clusterNames.List()
+	}
+	return result
+}
+
+func (t *Tracker) notify(owner string) {
+	t.Lock()
+	listeners := make([]func(string), 0, len(t.listeners))
+	for _, listener := range t.listeners {
+		listeners = append(listeners, listener)
+	}
+	t.Unlock()
+	for _, listener := range listeners {
+		listener(owner)
+	}
+}
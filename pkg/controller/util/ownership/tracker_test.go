@@ -0,0 +1,66 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ownership
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	owner     = "FederatedConfigMap/ns/foo"
+	contender = "FederatedObject/ns/foo"
+)
+
+func TestNilTrackerRecordsNoConflicts(t *testing.T) {
+	var tracker *Tracker
+	tracker.SetConflicts(contender, map[string]string{"cluster1": owner})
+	assert.Empty(t, tracker.Conflicts(contender))
+	assert.Empty(t, tracker.Contenders(owner))
+	tracker.Release(contender)
+	tracker.AddListener(func(string) {})()
+}
+
+func TestSetConflicts(t *testing.T) {
+	tracker := NewTracker()
+	var notified []string
+	removeListener := tracker.AddListener(func(owner string) { notified = append(notified, owner) })
+	defer removeListener()
+
+	tracker.SetConflicts(contender, map[string]string{"cluster2": owner, "cluster1": owner})
+	assert.Equal(t, map[string][]string{owner: {"cluster1", "cluster2"}}, tracker.Conflicts(contender))
+	assert.Equal(t, map[string][]string{contender: {"cluster1", "cluster2"}}, tracker.Contenders(owner))
+	assert.Equal(t, []string{owner}, notified)
+
+	// Owners are only notified of changes.
+	notified = nil
+	tracker.SetConflicts(contender, map[string]string{"cluster1": owner, "cluster2": owner})
+	assert.Empty(t, notified)
+
+	otherOwner := "FederatedConfigMap/ns/bar"
+	tracker.SetConflicts(contender, map[string]string{"cluster1": otherOwner})
+	assert.Equal(t, []string{otherOwner, owner}, notified)
+	assert.Empty(t, tracker.Contenders(owner))
+	assert.Equal(t, map[string][]string{contender: {"cluster1"}}, tracker.Contenders(otherOwner))
+
+	notified = nil
+	tracker.Release(contender)
+	assert.Equal(t, []string{otherOwner}, notified)
+	assert.Empty(t, tracker.Conflicts(contender))
+	assert.Empty(t, tracker.Contenders(otherOwner))
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/pkg/errors"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utiljson "k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/yaml"
+)
+
+// ClusterPatch is a patch of the target resource in a cluster,
+// written like the patches of a kustomization.
+type ClusterPatch struct {
+	// The type of the patch.  If not set, a patch holding a list of
+	// operations is a JSON patch and any other patch is a strategic
+	// merge patch.
+	Type PatchType `json:"type,omitempty"`
+	// The patch document in YAML or JSON.
+	Patch string `json:"patch"`
+}
+
+// PatchType defines how a cluster patch modifies the target resource.
+type PatchType string
+
+const (
+	// A strategic merge patch merges lists of types known to
+	// Kubernetes (e.g. the containers of a pod template) by their
+	// merge key.  It is applied as a JSON merge patch to other types.
+	StrategicMergePatchType PatchType = "StrategicMerge"
+	// A JSON merge patch (RFC 7386) replaces lists.
+	MergePatchType PatchType = "Merge"
+	// A JSON patch (RFC 6902) is a list of operations.
+	JSONPatchType PatchType = "JSON"
+)
+
+// GetPatchOverrides returns a map of cluster name to the patches
+// defined by the overrides of the given federated resource.
+func GetPatchOverrides(rawObj *unstructured.Unstructured) (map[string][]ClusterPatch, error) {
+	patchesMap := make(map[string][]ClusterPatch)
+	if rawObj == nil {
+		return patchesMap, nil
+	}
+
+	override := GenericOverride{}
+	if err := UnstructuredToInterface(rawObj, &override); err != nil {
+		return nil, err
+	}
+	if override.Spec == nil {
+		return patchesMap, nil
+	}
+	for _, overrideItem := range override.Spec.Overrides {
+		if len(overrideItem.Patches) > 0 {
+			patchesMap[overrideItem.ClusterName] = overrideItem.Patches
+		}
+	}
+	return patchesMap, nil
+}
+
+// ApplyPatches applies the given patches in order to the target
+// resource.  A patch may not change the fields that associate the
+// resource with its federated resource.
+func ApplyPatches(obj *unstructured.Unstructured, patches []ClusterPatch) error {
+	if len(patches) == 0 {
+		return nil
+	}
+	gvk := obj.GroupVersionKind()
+	name, namespace, generateName := obj.GetName(), obj.GetNamespace(), obj.GetGenerateName()
+
+	objJSON, err := obj.MarshalJSON()
+	if err != nil {
+		return errors.Wrap(err, "Failed to encode the target resource")
+	}
+	for i, patch := range patches {
+		patchType, patchJSON, err := decodePatch(patch)
+		if err != nil {
+			return errors.Wrapf(err, "patch[%d] is invalid", i)
+		}
+		objJSON, err = applyPatch(objJSON, patchType, patchJSON, gvk)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to apply patch[%d]", i)
+		}
+	}
+
+	patched := map[string]interface{}{}
+	if err := utiljson.Unmarshal(objJSON, &patched); err != nil {
+		return errors.Wrap(err, "Failed to decode the patched target resource")
+	}
+	obj.Object = patched
+	if obj.GroupVersionKind() != gvk {
+		return errors.New("patches must not change the apiVersion or kind of the target resource")
+	}
+	if obj.GetName() != name || obj.GetNamespace() != namespace || obj.GetGenerateName() != generateName {
+		return errors.New("patches must not change the name, namespace or generateName of the target resource")
+	}
+	return nil
+}
+
+func applyPatch(objJSON []byte, patchType PatchType, patchJSON []byte, gvk schema.GroupVersionKind) ([]byte, error) {
+	switch patchType {
+	case JSONPatchType:
+		patch, err := jsonpatch.DecodePatch(patchJSON)
+		if err != nil {
+			return nil, err
+		}
+		return patch.Apply(objJSON)
+	case StrategicMergePatchType:
+		// Types unknown to the scheme (e.g. those of CRDs) have no
+		// patch strategy and are patched like kustomize patches them.
+		dataStruct, err := scheme.Scheme.New(gvk)
+		if err == nil {
+			return strategicpatch.StrategicMergePatch(objJSON, patchJSON, dataStruct)
+		}
+	}
+	return jsonpatch.MergePatch(objJSON, patchJSON)
+}
+
+// decodePatch returns the type of the given patch and its document
+// converted to JSON.
+func decodePatch(patch ClusterPatch) (PatchType, []byte, error) {
+	patchJSON, err := yaml.YAMLToJSON([]byte(patch.Patch))
+	if err != nil {
+		return "", nil, errors.Wrap(err, "Failed to parse the patch")
+	}
+	isList := bytes.HasPrefix(bytes.TrimSpace(patchJSON), []byte("["))
+	patchType := patch.Type
+	if len(patchType) == 0 {
+		patchType = StrategicMergePatchType
+		if isList {
+			patchType = JSONPatchType
+		}
+	}
+	switch patchType {
+	case JSONPatchType:
+		if _, err := jsonpatch.DecodePatch(patchJSON); err != nil {
+			return "", nil, errors.Wrap(err, "A JSON patch must be a list of operations")
+		}
+	case StrategicMergePatchType, MergePatchType:
+		patchMap := map[string]interface{}{}
+		if err := json.Unmarshal(patchJSON, &patchMap); err != nil {
+			return "", nil, errors.Errorf("A %s patch must be an object", patchType)
+		}
+	default:
+		return "", nil, errors.Errorf("Unsupported patch type %q", patchType)
+	}
+	return patchType, patchJSON, nil
+}
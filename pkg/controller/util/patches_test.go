@@ -0,0 +1,174 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newPatchTestDeployment() *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": "web", "namespace": "ns"},
+		"spec": map[string]interface{}{
+			"replicas": int64(1),
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{"name": "web", "image": "web:1.0"},
+						map[string]interface{}{"name": "proxy", "image": "proxy:1.0"},
+					},
+				},
+			},
+		},
+	}}
+}
+
+func TestApplyPatches(t *testing.T) {
+	testCases := map[string]struct {
+		obj         *unstructured.Unstructured
+		patches     []ClusterPatch
+		expectedErr bool
+		expected    map[string]interface{}
+	}{
+		"Strategic merge patches merge containers by name": {
+			obj: newPatchTestDeployment(),
+			patches: []ClusterPatch{{Patch: `
+spec:
+  template:
+    spec:
+      containers:
+      - name: proxy
+        image: proxy:2.0
+`}},
+			expected: map[string]interface{}{
+				"replicas": int64(1),
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"containers": []interface{}{
+							map[string]interface{}{"name": "web", "image": "web:1.0"},
+							map[string]interface{}{"name": "proxy", "image": "proxy:2.0"},
+						},
+					},
+				},
+			},
+		},
+		"Merge patches replace lists": {
+			obj: newPatchTestDeployment(),
+			patches: []ClusterPatch{{Type: MergePatchType, Patch: `
+spec:
+  template:
+    spec:
+      containers:
+      - name: proxy
+        image: proxy:2.0
+`}},
+			expected: map[string]interface{}{
+				"replicas": int64(1),
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"containers": []interface{}{
+							map[string]interface{}{"name": "proxy", "image": "proxy:2.0"},
+						},
+					},
+				},
+			},
+		},
+		"Lists of operations are applied in order as JSON patches": {
+			obj: newPatchTestDeployment(),
+			patches: []ClusterPatch{
+				{Patch: `[{"op": "remove", "path": "/spec/template/spec/containers/1"}]`},
+				{Patch: "- op: replace\n  path: /spec/replicas\n  value: 3\n"},
+			},
+			expected: map[string]interface{}{
+				"replicas": int64(3),
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"containers": []interface{}{
+							map[string]interface{}{"name": "web", "image": "web:1.0"},
+						},
+					},
+				},
+			},
+		},
+		"Types unknown to the scheme are merge patched": {
+			obj: &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "example.com/v1",
+				"kind":       "Widget",
+				"metadata":   map[string]interface{}{"name": "widget"},
+				"spec":       map[string]interface{}{"sizes": []interface{}{"small"}, "color": "red"},
+			}},
+			patches:  []ClusterPatch{{Patch: "spec:\n  sizes: [large]\n"}},
+			expected: map[string]interface{}{"sizes": []interface{}{"large"}, "color": "red"},
+		},
+		"Patches must not rename the resource": {
+			obj:         newPatchTestDeployment(),
+			patches:     []ClusterPatch{{Patch: "metadata:\n  name: other\n"}},
+			expectedErr: true,
+		},
+		"JSON patches must hold operations": {
+			obj:         newPatchTestDeployment(),
+			patches:     []ClusterPatch{{Type: JSONPatchType, Patch: "spec:\n  replicas: 3\n"}},
+			expectedErr: true,
+		},
+		"Operations must apply": {
+			obj:         newPatchTestDeployment(),
+			patches:     []ClusterPatch{{Patch: `[{"op": "remove", "path": "/spec/paused"}]`}},
+			expectedErr: true,
+		},
+	}
+
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			err := ApplyPatches(tc.obj, tc.patches)
+			if tc.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, tc.obj.Object["spec"])
+		})
+	}
+}
+
+func TestGetOverridesPatches(t *testing.T) {
+	for patchType, patch := range map[PatchType]string{
+		"":                     "- not\n- operations\n",
+		MergePatchType:         "[]",
+		PatchType("Kustomize"): "spec: {}",
+	} {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"overrides": []interface{}{
+					map[string]interface{}{
+						"clusterName": "cluster1",
+						"patches": []interface{}{
+							map[string]interface{}{"type": string(patchType), "patch": patch},
+						},
+					},
+				},
+			},
+		}}
+		_, err := GetOverrides(obj)
+		assert.Error(t, err, "patch of type %q: %s", patchType, patch)
+	}
+}
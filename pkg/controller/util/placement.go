@@ -26,9 +26,23 @@ type GenericClusterReference struct {
 	Name string `json:"name"`
 }
 
+// GenericClusterSelector selects clusters by their labels and by the
+// fields of their status.
+type GenericClusterSelector struct {
+	metav1.LabelSelector `json:",inline"`
+	MatchFields          []ClusterFieldRequirement `json:"matchFields,omitempty"`
+}
+
 type GenericPlacementFields struct {
 	Clusters        []GenericClusterReference `json:"clusters,omitempty"`
-	ClusterSelector *metav1.LabelSelector     `json:"clusterSelector,omitempty"`
+	ClusterSelector *GenericClusterSelector   `json:"clusterSelector,omitempty"`
+	// MinClusters is the minimum number of ready clusters the
+	// placement must select.  Propagated resources are not removed
+	// from clusters while fewer are selected.
+	MinClusters *int32 `json:"minClusters,omitempty"`
+	// MaxClusters is the maximum number of clusters the resource is
+	// propagated to.
+	MaxClusters *int32 `json:"maxClusters,omitempty"`
 }
 
 type GenericPlacementSpec struct {
@@ -60,7 +74,37 @@ func (p *GenericPlacement) ClusterNames() []string {
 }
 
 func (p *GenericPlacement) ClusterSelector() (labels.Selector, error) {
-	return metav1.LabelSelectorAsSelector(p.Spec.Placement.ClusterSelector)
+	if p.Spec.Placement.ClusterSelector == nil {
+		return labels.Nothing(), nil
+	}
+	return metav1.LabelSelectorAsSelector(&p.Spec.Placement.ClusterSelector.LabelSelector)
+}
+
+// ClusterFieldRequirements returns the requirements of the cluster
+// selector on the status fields of clusters.
+func (p *GenericPlacement) ClusterFieldRequirements() []ClusterFieldRequirement {
+	if p.Spec.Placement.ClusterSelector == nil {
+		return nil
+	}
+	return p.Spec.Placement.ClusterSelector.MatchFields
+}
+
+// MinClusters returns the minimum number of ready clusters the
+// placement must select, or 0 if there is no minimum.
+func (p *GenericPlacement) MinClusters() int {
+	if p.Spec.Placement.MinClusters == nil {
+		return 0
+	}
+	return int(*p.Spec.Placement.MinClusters)
+}
+
+// MaxClusters returns the maximum number of clusters of the
+// placement and whether a maximum is set.
+func (p *GenericPlacement) MaxClusters() (int, bool) {
+	if p.Spec.Placement.MaxClusters == nil {
+		return 0, false
+	}
+	return int(*p.Spec.Placement.MaxClusters), true
 }
 
 func GetClusterNames(obj *unstructured.Unstructured) ([]string, error) {
@@ -0,0 +1,115 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package placementpolicy
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+// Restrictor restricts the member clusters that federated resources
+// in a namespace may be placed on according to the PlacementPolicy
+// resources in the KubeFed system namespace.
+type Restrictor interface {
+	Run(stopChan <-chan struct{})
+	HasSynced() bool
+	// AllowedClusters returns the names of the given clusters that
+	// federated resources in the given namespace may be placed on.
+	// Nil is returned if no policy restricts the namespace.
+	AllowedClusters(namespace string, clusters []*fedv1b1.KubeFedCluster) sets.String
+}
+
+type restrictor struct {
+	store      cache.Store
+	controller cache.Controller
+}
+
+// NewRestrictor returns a restrictor of placements.  The trigger
+// function is invoked when a policy changes.
+func NewRestrictor(controllerConfig *util.ControllerConfig, triggerFunc func(pkgruntime.Object)) (Restrictor, error) {
+	r := &restrictor{}
+	var err error
+	r.store, r.controller, err = util.NewGenericInformer(
+		controllerConfig.KubeConfig,
+		controllerConfig.KubeFedNamespace,
+		&fedv1b1.PlacementPolicy{},
+		util.NoResyncPeriod,
+		triggerFunc,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *restrictor) Run(stopChan <-chan struct{}) {
+	go r.controller.Run(stopChan)
+}
+
+func (r *restrictor) HasSynced() bool {
+	return r.controller.HasSynced()
+}
+
+func (r *restrictor) AllowedClusters(namespace string, clusters []*fedv1b1.KubeFedCluster) sets.String {
+	var policies []*fedv1b1.PlacementPolicy
+	for _, obj := range r.store.List() {
+		policies = append(policies, obj.(*fedv1b1.PlacementPolicy))
+	}
+	return allowedClusters(policies, namespace, clusters)
+}
+
+// allowedClusters returns the names of the given clusters that are
+// allowed by at least one of the policies restricting the given
+// namespace, or nil if no policy restricts it.  Cluster-scoped
+// federated resources, with an empty namespace, are never restricted.
+func allowedClusters(policies []*fedv1b1.PlacementPolicy, namespace string, clusters []*fedv1b1.KubeFedCluster) sets.String {
+	if len(namespace) == 0 {
+		return nil
+	}
+	var allowed sets.String
+	for _, policy := range policies {
+		if !sets.NewString(policy.Spec.Namespaces...).Has(namespace) {
+			continue
+		}
+		if allowed == nil {
+			allowed = sets.NewString()
+		}
+		named := sets.NewString(policy.Spec.Clusters...)
+		selector := labels.Nothing()
+		if policy.Spec.ClusterSelector != nil {
+			var err error
+			selector, err = metav1.LabelSelectorAsSelector(policy.Spec.ClusterSelector)
+			if err != nil {
+				klog.Warningf("Ignoring invalid cluster selector of PlacementPolicy %q: %v", policy.Name, err)
+				selector = labels.Nothing()
+			}
+		}
+		for _, cluster := range clusters {
+			if named.Has(cluster.Name) || selector.Matches(labels.Set(cluster.Labels)) {
+				allowed.Insert(cluster.Name)
+			}
+		}
+	}
+	return allowed
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package placementpolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+func TestAllowedClusters(t *testing.T) {
+	newCluster := func(name, team string) *fedv1b1.KubeFedCluster {
+		return &fedv1b1.KubeFedCluster{ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{"team": team},
+		}}
+	}
+	clusters := []*fedv1b1.KubeFedCluster{
+		newCluster("a1", "a"),
+		newCluster("a2", "a"),
+		newCluster("b1", "b"),
+		newCluster("shared", ""),
+	}
+	newPolicy := func(name string, namespaces, clusters []string, selector *metav1.LabelSelector) *fedv1b1.PlacementPolicy {
+		return &fedv1b1.PlacementPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: fedv1b1.PlacementPolicySpec{
+				Namespaces:      namespaces,
+				Clusters:        clusters,
+				ClusterSelector: selector,
+			},
+		}
+	}
+	policies := []*fedv1b1.PlacementPolicy{
+		newPolicy("team-a", []string{"team-a", "team-a-staging"}, nil, &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}}),
+		newPolicy("team-a-shared", []string{"team-a"}, []string{"shared"}, nil),
+		newPolicy("team-b", []string{"team-b"}, []string{"b1"}, nil),
+		newPolicy("frozen", []string{"frozen"}, nil, nil),
+		newPolicy("invalid", []string{"invalid"}, nil, &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "team", Operator: "Unknown"}},
+		}),
+	}
+
+	testCases := map[string]struct {
+		namespace string
+		expected  []string
+	}{
+		"Policies of a namespace are combined": {
+			namespace: "team-a",
+			expected:  []string{"a1", "a2", "shared"},
+		},
+		"Clusters are selected by label": {
+			namespace: "team-a-staging",
+			expected:  []string{"a1", "a2"},
+		},
+		"Clusters are selected by name": {
+			namespace: "team-b",
+			expected:  []string{"b1"},
+		},
+		"Policy without clusters allows none": {
+			namespace: "frozen",
+			expected:  []string{},
+		},
+		"Policy with an invalid selector allows none": {
+			namespace: "invalid",
+			expected:  []string{},
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			allowed := allowedClusters(policies, tc.namespace, clusters)
+			if assert.NotNil(t, allowed) {
+				assert.Equal(t, tc.expected, allowed.List())
+			}
+		})
+	}
+
+	assert.Nil(t, allowedClusters(policies, "unrestricted", clusters))
+	assert.Nil(t, allowedClusters(policies, "", clusters))
+}
@@ -52,6 +52,12 @@ func ObjectNeedsUpdate(desiredObj, clusterObj *unstructured.Unstructured, record
 		return true
 	}
 
+	// A change to the desired state is detected by its hash,
+	// whichever of the sources of the desired state changed.
+	if DesiredHash(desiredObj) != DesiredHash(clusterObj) {
+		return true
+	}
+
 	// If versions match and the version is sourced from the
 	// generation field, a further check of metadata equivalency is
 	// required.
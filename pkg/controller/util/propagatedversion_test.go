@@ -0,0 +1,72 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestObjectNeedsUpdate(t *testing.T) {
+	newObj := func(generation int64, resourceVersion, hash string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+		obj.SetName("foo")
+		obj.SetGeneration(generation)
+		obj.SetResourceVersion(resourceVersion)
+		obj.SetAnnotations(map[string]string{DesiredHashAnnotation: hash})
+		return obj
+	}
+
+	testCases := map[string]struct {
+		desiredObj      *unstructured.Unstructured
+		clusterObj      *unstructured.Unstructured
+		recordedVersion string
+		expected        bool
+	}{
+		"Current resource": {
+			desiredObj:      newObj(0, "", "abc"),
+			clusterObj:      newObj(2, "10", "abc"),
+			recordedVersion: "gen:2",
+		},
+		"Changed version": {
+			desiredObj:      newObj(0, "", "abc"),
+			clusterObj:      newObj(3, "11", "abc"),
+			recordedVersion: "gen:2",
+			expected:        true,
+		},
+		"Changed desired state": {
+			desiredObj:      newObj(0, "", "def"),
+			clusterObj:      newObj(0, "10", "abc"),
+			recordedVersion: "rv:10",
+			expected:        true,
+		},
+		"Unchanged desired state of a resource versioned by resourceVersion": {
+			desiredObj:      newObj(0, "", "abc"),
+			clusterObj:      newObj(0, "10", "abc"),
+			recordedVersion: "rv:10",
+		},
+	}
+
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			assert.Equal(t, tc.expected, ObjectNeedsUpdate(tc.desiredObj, tc.clusterObj, tc.recordedVersion))
+		})
+	}
+}
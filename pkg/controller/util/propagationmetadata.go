@@ -0,0 +1,177 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+const (
+	// FederatedKindAnnotation, FederatedNamespaceAnnotation and
+	// FederatedNameAnnotation identify the federated resource that
+	// manages a resource in a member cluster.  The name annotation is
+	// always set on resources renamed by a cluster override so that
+	// changes to them are associated with the federated resource.
+	FederatedKindAnnotation      = "kubefed.k8s.io/federated-kind"
+	FederatedNamespaceAnnotation = "kubefed.k8s.io/federated-namespace"
+	FederatedNameAnnotation      = "kubefed.k8s.io/federated-name"
+
+	// TemplateHashAnnotation records the hash of the template of the
+	// federated resource a resource in a member cluster was
+	// propagated from.
+	TemplateHashAnnotation = "kubefed.k8s.io/template-hash"
+
+	// DesiredHashAnnotation records the hash of the desired state of
+	// a resource in a member cluster, as rendered for the cluster
+	// from the template, overrides and other sources of the
+	// federated resource. A resource whose annotation differs from
+	// the hash of its current desired state is known to be out of
+	// date without comparing its content.
+	DesiredHashAnnotation = "kubefed.k8s.io/desired-hash"
+
+	// FederatedGenerationAnnotation records the generation of the
+	// federated resource a resource in a member cluster was last
+	// propagated from.
+	FederatedGenerationAnnotation = "kubefed.k8s.io/federated-generation"
+
+	// HostClusterLabelKey identifies the host cluster of the KubeFed
+	// control plane that propagated a resource.
+	HostClusterLabelKey = "kubefed.k8s.io/host-cluster"
+)
+
+// PropagationOrigin describes the federated resource that a resource
+// in a member cluster is propagated from.
+type PropagationOrigin struct {
+	FederatedKind string
+	FederatedName QualifiedName
+	TemplateHash  string
+	// Renamed indicates that the resource was renamed by a cluster
+	// override.
+	Renamed bool
+}
+
+// AddPropagationMetadata sets the labels and annotations configured
+// for propagated resources on the given object.
+func AddPropagationMetadata(obj *unstructured.Unstructured, config *fedv1b1.PropagationMetadataConfig, origin PropagationOrigin) {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	originAnnotations := config != nil && config.OriginAnnotations != fedv1b1.ConfigurationDisabled
+	if originAnnotations {
+		annotations[FederatedKindAnnotation] = origin.FederatedKind
+		if len(origin.FederatedName.Namespace) > 0 {
+			annotations[FederatedNamespaceAnnotation] = origin.FederatedName.Namespace
+		}
+	}
+	if originAnnotations || origin.Renamed {
+		annotations[FederatedNameAnnotation] = origin.FederatedName.Name
+	}
+	if config != nil && config.TemplateHashAnnotation != fedv1b1.ConfigurationDisabled && len(origin.TemplateHash) > 0 {
+		annotations[TemplateHashAnnotation] = origin.TemplateHash
+	}
+	if len(annotations) > 0 {
+		obj.SetAnnotations(annotations)
+	}
+
+	if config != nil && len(config.HostClusterName) > 0 {
+		labels := obj.GetLabels()
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+		labels[HostClusterLabelKey] = config.HostClusterName
+		obj.SetLabels(labels)
+	}
+}
+
+// AddDesiredStateMetadata annotates the given object, rendered as the
+// desired state of a resource in a member cluster, with the hash of
+// its content and the generation of the federated resource it was
+// rendered from. The hash is computed before the annotations are
+// added so that it only changes with the desired state.
+func AddDesiredStateMetadata(obj *unstructured.Unstructured, federatedGeneration int64) error {
+	annotations := obj.GetAnnotations()
+	if _, ok := annotations[DesiredHashAnnotation]; ok {
+		// A hash copied from the template would never match.
+		delete(annotations, DesiredHashAnnotation)
+		delete(annotations, FederatedGenerationAnnotation)
+		if len(annotations) == 0 {
+			annotations = nil
+		}
+		obj.SetAnnotations(annotations)
+	}
+	jsonBytes, err := obj.MarshalJSON()
+	if err != nil {
+		return errors.Wrap(err, "Failed to marshal the desired state to json")
+	}
+	hash := md5.Sum(jsonBytes)
+
+	annotations = obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[DesiredHashAnnotation] = hex.EncodeToString(hash[:])
+	annotations[FederatedGenerationAnnotation] = strconv.FormatInt(federatedGeneration, 10)
+	obj.SetAnnotations(annotations)
+	return nil
+}
+
+// DesiredHash returns the hash of the desired state recorded on the
+// given object, or an empty string if none is recorded.
+func DesiredHash(obj *unstructured.Unstructured) string {
+	return obj.GetAnnotations()[DesiredHashAnnotation]
+}
+
+// FederatedGeneration returns the generation of the federated
+// resource recorded on the given resource in a member cluster and
+// whether it was recorded.
+func FederatedGeneration(obj *unstructured.Unstructured) (int64, bool) {
+	value, ok := obj.GetAnnotations()[FederatedGenerationAnnotation]
+	if !ok {
+		return 0, false
+	}
+	generation, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return generation, true
+}
+
+// RolloutComplete returns whether the given resource in a member
+// cluster has been propagated from the given generation of its
+// federated resource and, for a resource reporting the generation it
+// observed, whether its controller has observed its latest
+// generation.
+func RolloutComplete(clusterObj *unstructured.Unstructured, federatedGeneration int64) bool {
+	generation, ok := FederatedGeneration(clusterObj)
+	if !ok || generation < federatedGeneration {
+		return false
+	}
+	observedGeneration, ok, err := unstructured.NestedInt64(clusterObj.Object, "status", "observedGeneration")
+	if err != nil {
+		return false
+	}
+	return !ok || observedGeneration >= clusterObj.GetGeneration()
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+func TestAddPropagationMetadata(t *testing.T) {
+	origin := PropagationOrigin{
+		FederatedKind: "FederatedDeployment",
+		FederatedName: QualifiedName{Namespace: "team-a", Name: "foo"},
+		TemplateHash:  "abc123",
+	}
+	originAnnotations := map[string]string{
+		FederatedKindAnnotation:      "FederatedDeployment",
+		FederatedNamespaceAnnotation: "team-a",
+		FederatedNameAnnotation:      "foo",
+	}
+
+	testCases := map[string]struct {
+		config              *fedv1b1.PropagationMetadataConfig
+		renamed             bool
+		expectedAnnotations map[string]string
+		expectedLabels      map[string]string
+	}{
+		"No metadata without configuration": {},
+		"Name is recorded for renamed resource without configuration": {
+			renamed:             true,
+			expectedAnnotations: map[string]string{FederatedNameAnnotation: "foo"},
+		},
+		"All metadata is added by default": {
+			config: &fedv1b1.PropagationMetadataConfig{HostClusterName: "host"},
+			expectedAnnotations: map[string]string{
+				FederatedKindAnnotation:      "FederatedDeployment",
+				FederatedNamespaceAnnotation: "team-a",
+				FederatedNameAnnotation:      "foo",
+				TemplateHashAnnotation:       "abc123",
+			},
+			expectedLabels: map[string]string{HostClusterLabelKey: "host"},
+		},
+		"Template hash can be disabled": {
+			config: &fedv1b1.PropagationMetadataConfig{
+				TemplateHashAnnotation: fedv1b1.ConfigurationDisabled,
+			},
+			expectedAnnotations: originAnnotations,
+		},
+		"Origin can be disabled": {
+			config: &fedv1b1.PropagationMetadataConfig{
+				OriginAnnotations:      fedv1b1.ConfigurationDisabled,
+				TemplateHashAnnotation: fedv1b1.ConfigurationDisabled,
+			},
+		},
+	}
+
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			obj := &unstructured.Unstructured{}
+			obj.SetName("foo")
+			tcOrigin := origin
+			tcOrigin.Renamed = tc.renamed
+			AddPropagationMetadata(obj, tc.config, tcOrigin)
+			assert.Equal(t, tc.expectedAnnotations, obj.GetAnnotations())
+			assert.Equal(t, tc.expectedLabels, obj.GetLabels())
+		})
+	}
+}
+
+func TestAddDesiredStateMetadata(t *testing.T) {
+	newObj := func(replicas int64) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{"replicas": replicas},
+		}}
+		obj.SetName("foo")
+		return obj
+	}
+
+	obj := newObj(3)
+	assert.NoError(t, AddDesiredStateMetadata(obj, 4))
+	hash := DesiredHash(obj)
+	assert.NotEmpty(t, hash)
+	generation, ok := FederatedGeneration(obj)
+	assert.True(t, ok)
+	assert.Equal(t, int64(4), generation)
+
+	sameObj := newObj(3)
+	assert.NoError(t, AddDesiredStateMetadata(sameObj, 5))
+	assert.Equal(t, hash, DesiredHash(sameObj), "the hash should not depend on the generation")
+
+	changedObj := newObj(5)
+	assert.NoError(t, AddDesiredStateMetadata(changedObj, 4))
+	assert.NotEqual(t, hash, DesiredHash(changedObj))
+
+	copiedObj := newObj(3)
+	copiedObj.SetAnnotations(map[string]string{DesiredHashAnnotation: "copied"})
+	assert.NoError(t, AddDesiredStateMetadata(copiedObj, 4))
+	assert.Equal(t, hash, DesiredHash(copiedObj), "a hash set by the template should be ignored")
+}
+
+func TestRolloutComplete(t *testing.T) {
+	testCases := map[string]struct {
+		annotations        map[string]string
+		generation         int64
+		observedGeneration *int64
+		expected           bool
+	}{
+		"Not complete without a recorded generation": {},
+		"Not complete for an older federated generation": {
+			annotations: map[string]string{FederatedGenerationAnnotation: "2"},
+		},
+		"Complete for the federated generation": {
+			annotations: map[string]string{FederatedGenerationAnnotation: "3"},
+			expected:    true,
+		},
+		"Not complete until the generation is observed": {
+			annotations:        map[string]string{FederatedGenerationAnnotation: "3"},
+			generation:         7,
+			observedGeneration: int64Ptr(6),
+		},
+		"Complete once the generation is observed": {
+			annotations:        map[string]string{FederatedGenerationAnnotation: "3"},
+			generation:         7,
+			observedGeneration: int64Ptr(7),
+			expected:           true,
+		},
+	}
+
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+			obj.SetAnnotations(tc.annotations)
+			obj.SetGeneration(tc.generation)
+			if tc.observedGeneration != nil {
+				assert.NoError(t, unstructured.SetNestedField(obj.Object, *tc.observedGeneration, "status", "observedGeneration"))
+			}
+			assert.Equal(t, tc.expected, RolloutComplete(obj, 3))
+		})
+	}
+}
+
+func int64Ptr(value int64) *int64 {
+	return &value
+}
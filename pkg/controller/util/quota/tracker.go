@@ -0,0 +1,300 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quota
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+// quotaUsage is the usage of a PropagationQuota.
+type quotaUsage struct {
+	spec       fedv1b1.PropagationQuotaSpec
+	namespaces sets.String
+	// The number of propagated objects of each admitted resource by
+	// key.
+	admitted          map[string]int64
+	propagatedObjects int64
+	// The keys of the resources that were rejected.
+	rejected sets.String
+}
+
+// fits indicates whether the given resource fits the quota with the
+// given number of propagated objects.
+func (q *quotaUsage) fits(key string, objects int64) bool {
+	resources := int64(len(q.admitted))
+	previous, ok := q.admitted[key]
+	if !ok {
+		resources++
+	}
+	if max := q.spec.MaxFederatedResources; max != nil && resources > *max {
+		return false
+	}
+	if max := q.spec.MaxPropagatedObjects; max != nil && q.propagatedObjects-previous+objects > *max {
+		return false
+	}
+	return true
+}
+
+func (q *quotaUsage) admit(key string, objects int64) {
+	q.propagatedObjects += objects - q.admitted[key]
+	q.admitted[key] = objects
+	q.rejected.Delete(key)
+}
+
+// release removes the given resource from the usage of the quota and
+// returns whether it was admitted.
+func (q *quotaUsage) release(key string) bool {
+	q.rejected.Delete(key)
+	objects, ok := q.admitted[key]
+	if !ok {
+		return false
+	}
+	q.propagatedObjects -= objects
+	delete(q.admitted, key)
+	return true
+}
+
+// Tracker tracks the usage of the PropagationQuota resources in the
+// KubeFed system namespace.  A single tracker is shared by the sync
+// controllers of all federated types so that federated resources are
+// counted across types.  The usage of the resources that were admitted
+// before the controller manager started is seeded from their
+// propagation status so that they remain admitted after a restart.
+// Other resources are admitted in the order in which they are
+// reconciled, and an admitted resource keeps its usage until it is
+// released, even if the quota is lowered.  A nil *Tracker is valid and
+// admits all resources.
+type Tracker struct {
+	sync.Mutex
+	quotas         map[string]*quotaUsage
+	synced         bool
+	listeners      map[int]func()
+	nextListenerID int
+}
+
+// NewTracker returns a tracker without quotas.
+func NewTracker() *Tracker {
+	return &Tracker{
+		quotas:    make(map[string]*quotaUsage),
+		listeners: make(map[int]func()),
+	}
+}
+
+// AddListener registers a function that is invoked when resources
+// that were rejected may fit a quota, i.e. when a quota is changed or
+// removed or when an admitted resource is released.  The returned
+// function unregisters the listener.
+func (t *Tracker) AddListener(listener func()) func() {
+	if t == nil {
+		return func() {}
+	}
+	t.Lock()
+	defer t.Unlock()
+	id := t.nextListenerID
+	t.nextListenerID++
+	t.listeners[id] = listener
+	return func() {
+		t.Lock()
+		defer t.Unlock()
+		delete(t.listeners, id)
+	}
+}
+
+// SetSynced records that all quotas have been set.
+func (t *Tracker) SetSynced() {
+	if t == nil {
+		return
+	}
+	t.Lock()
+	defer t.Unlock()
+	t.synced = true
+}
+
+// HasSynced indicates whether all quotas have been set, so that
+// resources are not admitted before the quotas applying to them are
+// known.
+func (t *Tracker) HasSynced() bool {
+	if t == nil {
+		return true
+	}
+	t.Lock()
+	defer t.Unlock()
+	return t.synced
+}
+
+// SetQuota adds or updates the quota with the given name.
+func (t *Tracker) SetQuota(name string, spec fedv1b1.PropagationQuotaSpec) {
+	if t == nil {
+		return
+	}
+	t.Lock()
+	namespaces := sets.NewString(spec.Namespaces...)
+	quota, ok := t.quotas[name]
+	// The usage of a quota whose namespaces change is counted anew.
+	if !ok || !quota.namespaces.Equal(namespaces) {
+		quota = &quotaUsage{
+			admitted: make(map[string]int64),
+			rejected: sets.NewString(),
+		}
+		t.quotas[name] = quota
+	}
+	changed := !ok || !equalSpecs(quota.spec, spec)
+	quota.spec = *spec.DeepCopy()
+	quota.namespaces = namespaces
+	t.Unlock()
+
+	if changed {
+		t.notify()
+	}
+}
+
+// RemoveQuota removes the quota with the given name.
+func (t *Tracker) RemoveQuota(name string) {
+	if t == nil {
+		return
+	}
+	t.Lock()
+	_, ok := t.quotas[name]
+	delete(t.quotas, name)
+	t.Unlock()
+
+	if ok {
+		t.notify()
+	}
+}
+
+// Admit records the given number of propagated objects for the
+// federated resource with the given key in the given namespace and
+// returns true if the resource fits all quotas of the namespace.  If
+// it does not, false is returned and the usage of a resource that was
+// already admitted is left unchanged.
+func (t *Tracker) Admit(namespace, key string, objects int) bool {
+	if t == nil || len(namespace) == 0 {
+		return true
+	}
+	t.Lock()
+	defer t.Unlock()
+
+	var quotas []*quotaUsage
+	for _, quota := range t.quotas {
+		if quota.namespaces.Has(namespace) {
+			quotas = append(quotas, quota)
+		}
+	}
+	for _, quota := range quotas {
+		if !quota.fits(key, int64(objects)) {
+			klog.V(4).Infof("Federated resource %s exceeds the propagation quota of namespace %q", key, namespace)
+			for _, quota := range quotas {
+				quota.rejected.Insert(key)
+			}
+			return false
+		}
+	}
+	for _, quota := range quotas {
+		quota.admit(key, int64(objects))
+	}
+	return true
+}
+
+// Seed records the given number of propagated objects for a federated
+// resource that was admitted before the controller manager started,
+// without checking the limits of the quotas of its namespace.  The
+// usage of a resource that is already tracked is left unchanged.
+func (t *Tracker) Seed(namespace, key string, objects int) {
+	if t == nil || len(namespace) == 0 {
+		return
+	}
+	t.Lock()
+	defer t.Unlock()
+	for _, quota := range t.quotas {
+		if !quota.namespaces.Has(namespace) {
+			continue
+		}
+		if _, ok := quota.admitted[key]; ok {
+			continue
+		}
+		quota.admit(key, int64(objects))
+	}
+}
+
+// Release removes the federated resource with the given key in the
+// given namespace from the usage of the quotas of the namespace.
+func (t *Tracker) Release(namespace, key string) {
+	if t == nil || len(namespace) == 0 {
+		return
+	}
+	t.Lock()
+	freed := false
+	for _, quota := range t.quotas {
+		if quota.namespaces.Has(namespace) && quota.release(key) && quota.rejected.Len() > 0 {
+			freed = true
+		}
+	}
+	t.Unlock()
+
+	if freed {
+		t.notify()
+	}
+}
+
+// Usage returns the usage of the quota with the given name.
+func (t *Tracker) Usage(name string) (fedv1b1.PropagationQuotaStatus, bool) {
+	if t == nil {
+		return fedv1b1.PropagationQuotaStatus{}, false
+	}
+	t.Lock()
+	defer t.Unlock()
+	quota, ok := t.quotas[name]
+	if !ok {
+		return fedv1b1.PropagationQuotaStatus{}, false
+	}
+	return fedv1b1.PropagationQuotaStatus{
+		FederatedResources: int64(len(quota.admitted)),
+		PropagatedObjects:  quota.propagatedObjects,
+		RejectedResources:  int64(quota.rejected.Len()),
+	}, true
+}
+
+func (t *Tracker) notify() {
+	t.Lock()
+	listeners := make([]func(), 0, len(t.listeners))
+	for _, listener := range t.listeners {
+		listeners = append(listeners, listener)
+	}
+	t.Unlock()
+	for _, listener := range listeners {
+		listener()
+	}
+}
+
+func equalSpecs(a, b fedv1b1.PropagationQuotaSpec) bool {
+	return sets.NewString(a.Namespaces...).Equal(sets.NewString(b.Namespaces...)) &&
+		equalLimits(a.MaxFederatedResources, b.MaxFederatedResources) &&
+		equalLimits(a.MaxPropagatedObjects, b.MaxPropagatedObjects)
+}
+
+func equalLimits(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
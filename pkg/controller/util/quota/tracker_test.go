@@ -0,0 +1,141 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quota
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+func limit(value int64) *int64 {
+	return &value
+}
+
+func TestNilTrackerAdmitsAll(t *testing.T) {
+	var tracker *Tracker
+	assert.True(t, tracker.HasSynced())
+	assert.True(t, tracker.Admit("team-a", "FederatedDeployment/team-a/foo", 3))
+	tracker.Release("team-a", "FederatedDeployment/team-a/foo")
+}
+
+func TestAdmitFederatedResources(t *testing.T) {
+	tracker := NewTracker()
+	tracker.SetQuota("team-a", fedv1b1.PropagationQuotaSpec{
+		Namespaces:            []string{"team-a", "team-a-staging"},
+		MaxFederatedResources: limit(2),
+	})
+
+	assert.True(t, tracker.Admit("team-a", "FederatedDeployment/team-a/foo", 3))
+	assert.True(t, tracker.Admit("team-a-staging", "FederatedConfigMap/team-a-staging/foo", 3))
+	// An admitted resource stays admitted.
+	assert.True(t, tracker.Admit("team-a", "FederatedDeployment/team-a/foo", 5))
+	assert.False(t, tracker.Admit("team-a", "FederatedSecret/team-a/foo", 1))
+	// Other namespaces are not restricted.
+	assert.True(t, tracker.Admit("team-b", "FederatedSecret/team-b/foo", 1))
+
+	usage, ok := tracker.Usage("team-a")
+	assert.True(t, ok)
+	assert.Equal(t, fedv1b1.PropagationQuotaStatus{FederatedResources: 2, PropagatedObjects: 8, RejectedResources: 1}, usage)
+
+	notified := false
+	removeListener := tracker.AddListener(func() { notified = true })
+	tracker.Release("team-a", "FederatedDeployment/team-a/foo")
+	assert.True(t, notified)
+	assert.True(t, tracker.Admit("team-a", "FederatedSecret/team-a/foo", 1))
+
+	notified = false
+	removeListener()
+	tracker.Release("team-a", "FederatedSecret/team-a/foo")
+	assert.False(t, notified)
+
+	usage, _ = tracker.Usage("team-a")
+	assert.Equal(t, fedv1b1.PropagationQuotaStatus{FederatedResources: 1, PropagatedObjects: 3}, usage)
+}
+
+func TestAdmitPropagatedObjects(t *testing.T) {
+	tracker := NewTracker()
+	tracker.SetQuota("objects", fedv1b1.PropagationQuotaSpec{
+		Namespaces:           []string{"team-a"},
+		MaxPropagatedObjects: limit(5),
+	})
+	tracker.SetQuota("resources", fedv1b1.PropagationQuotaSpec{
+		Namespaces:            []string{"team-a"},
+		MaxFederatedResources: limit(3),
+	})
+
+	assert.True(t, tracker.Admit("team-a", "FederatedDeployment/team-a/foo", 3))
+	assert.False(t, tracker.Admit("team-a", "FederatedDeployment/team-a/bar", 3))
+	assert.True(t, tracker.Admit("team-a", "FederatedDeployment/team-a/bar", 2))
+	// A resource whose placement grows beyond the quota keeps the
+	// usage it was admitted with.
+	assert.False(t, tracker.Admit("team-a", "FederatedDeployment/team-a/foo", 4))
+
+	usage, _ := tracker.Usage("objects")
+	assert.Equal(t, fedv1b1.PropagationQuotaStatus{FederatedResources: 2, PropagatedObjects: 5, RejectedResources: 1}, usage)
+	// Resources rejected by one quota are reported by all quotas of
+	// the namespace.
+	usage, _ = tracker.Usage("resources")
+	assert.Equal(t, fedv1b1.PropagationQuotaStatus{FederatedResources: 2, PropagatedObjects: 5, RejectedResources: 1}, usage)
+
+	tracker.RemoveQuota("objects")
+	assert.True(t, tracker.Admit("team-a", "FederatedDeployment/team-a/foo", 4))
+	_, ok := tracker.Usage("objects")
+	assert.False(t, ok)
+}
+
+func TestSeedAdmittedResources(t *testing.T) {
+	tracker := NewTracker()
+	tracker.SetQuota("team-a", fedv1b1.PropagationQuotaSpec{
+		Namespaces:            []string{"team-a"},
+		MaxFederatedResources: limit(1),
+	})
+
+	// A resource admitted before a restart keeps its admission even
+	// if another resource is reconciled first.
+	tracker.Seed("team-a", "FederatedDeployment/team-a/foo", 2)
+	assert.False(t, tracker.Admit("team-a", "FederatedDeployment/team-a/bar", 1))
+	assert.True(t, tracker.Admit("team-a", "FederatedDeployment/team-a/foo", 3))
+
+	// Seeding does not change the usage of a tracked resource.
+	tracker.Seed("team-a", "FederatedDeployment/team-a/foo", 1)
+	usage, _ := tracker.Usage("team-a")
+	assert.Equal(t, fedv1b1.PropagationQuotaStatus{FederatedResources: 1, PropagatedObjects: 3, RejectedResources: 1}, usage)
+}
+
+func TestSetQuotaWithOtherNamespaces(t *testing.T) {
+	tracker := NewTracker()
+	spec := fedv1b1.PropagationQuotaSpec{
+		Namespaces:            []string{"team-a"},
+		MaxFederatedResources: limit(1),
+	}
+	tracker.SetQuota("team", spec)
+	assert.True(t, tracker.Admit("team-a", "FederatedDeployment/team-a/foo", 1))
+
+	notified := false
+	tracker.AddListener(func() { notified = true })
+	tracker.SetQuota("team", spec)
+	assert.False(t, notified)
+
+	spec.Namespaces = []string{"team-b"}
+	tracker.SetQuota("team", spec)
+	assert.True(t, notified)
+	usage, _ := tracker.Usage("team")
+	assert.Equal(t, fedv1b1.PropagationQuotaStatus{}, usage)
+}
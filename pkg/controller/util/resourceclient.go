@@ -68,3 +68,8 @@ func (c *resourceClient) Resources(namespace string) dynamic.ResourceInterface {
 func (c *resourceClient) Kind() string {
 	return c.kind
 }
+
+// GroupVersionResource returns the resource the client accesses.
+func (c *resourceClient) GroupVersionResource() schema.GroupVersionResource {
+	return c.apiResource
+}
@@ -23,41 +23,81 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/tools/cache"
+
+	"sigs.k8s.io/kubefed/pkg/controller/util/memberinformer"
 )
 
 // NewManagedResourceInformer returns an unfiltered informer.
 func NewResourceInformer(client ResourceClient, namespace string, triggerFunc func(pkgruntime.Object)) (cache.Store, cache.Controller) {
-	return newResourceInformer(client, namespace, triggerFunc, "")
+	return newResourceInformer(client, namespace, triggerFunc, nil, "")
 }
 
 // NewManagedResourceInformer returns an informer limited to resources
-// managed by KubeFed as indicated by labeling.
-func NewManagedResourceInformer(client ResourceClient, namespace string, triggerFunc func(pkgruntime.Object)) (cache.Store, cache.Controller) {
+// managed by KubeFed as indicated by labeling.  Updates only trigger
+// the given function if they are accepted by the predicate, if any.
+func NewManagedResourceInformer(client ResourceClient, namespace string, triggerFunc func(pkgruntime.Object), updatePredicate UpdatePredicate) (cache.Store, cache.Controller) {
 	labelSelector := labels.Set(map[string]string{ManagedByKubeFedLabelKey: ManagedByKubeFedLabelValue}).AsSelector().String()
-	return newResourceInformer(client, namespace, triggerFunc, labelSelector)
+	return newResourceInformer(client, namespace, triggerFunc, updatePredicate, labelSelector)
+}
+
+// NewLabeledResourceInformer returns an informer limited to resources
+// matching the given label selector.
+func NewLabeledResourceInformer(client ResourceClient, namespace, labelSelector string, triggerFunc func(pkgruntime.Object)) (cache.Store, cache.Controller) {
+	return newResourceInformer(client, namespace, triggerFunc, nil, labelSelector)
 }
 
-func newResourceInformer(client ResourceClient, namespace string, triggerFunc func(pkgruntime.Object), labelSelector string) (cache.Store, cache.Controller) {
+// NewSharedManagedResourceInformer returns an informer limited to
+// resources managed by KubeFed in the given cluster whose watch and
+// cache are shared by the manager with the other controllers that
+// watch the same resources of the cluster.  An unshared informer is
+// returned if the manager is nil or the resource of the client is
+// not known.
+func NewSharedManagedResourceInformer(manager *memberinformer.Manager, clusterName, clusterFingerprint string, client ResourceClient,
+	namespace string, triggerFunc func(pkgruntime.Object), updatePredicate UpdatePredicate) (cache.Store, cache.Controller) {
+
+	gvrClient, ok := client.(interface {
+		GroupVersionResource() schema.GroupVersionResource
+	})
+	if manager == nil || !ok {
+		return NewManagedResourceInformer(client, namespace, triggerFunc, updatePredicate)
+	}
+	labelSelector := labels.Set(map[string]string{ManagedByKubeFedLabelKey: ManagedByKubeFedLabelValue}).AsSelector().String()
+	key := memberinformer.Key{
+		Cluster:            clusterName,
+		ClusterFingerprint: clusterFingerprint,
+		Resource:           gvrClient.GroupVersionResource().String(),
+		Namespace:          namespace,
+		LabelSelector:      labelSelector,
+	}
+	return manager.Informer(key, newListWatch(client, namespace, labelSelector), NoResyncPeriod, NewTriggerOnChanges(triggerFunc, updatePredicate))
+}
+
+func newResourceInformer(client ResourceClient, namespace string, triggerFunc func(pkgruntime.Object), updatePredicate UpdatePredicate, labelSelector string) (cache.Store, cache.Controller) {
 	return cache.NewInformer(
-		&cache.ListWatch{
-			ListFunc: func(options metav1.ListOptions) (pkgruntime.Object, error) {
-				options.LabelSelector = labelSelector
-				return client.Resources(namespace).List(options)
-			},
-			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
-				options.LabelSelector = labelSelector
-				return client.Resources(namespace).Watch(options)
-			},
-		},
+		newListWatch(client, namespace, labelSelector),
 		nil, // Skip checks for expected type since the type will depend on the client
 		NoResyncPeriod,
-		NewTriggerOnAllChanges(triggerFunc),
+		NewTriggerOnChanges(triggerFunc, updatePredicate),
 	)
 }
 
+func newListWatch(client ResourceClient, namespace, labelSelector string) *cache.ListWatch {
+	return &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (pkgruntime.Object, error) {
+			options.LabelSelector = labelSelector
+			return client.Resources(namespace).List(options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.LabelSelector = labelSelector
+			return client.Resources(namespace).Watch(options)
+		},
+	}
+}
+
 func ObjFromCache(store cache.Store, kind, key string) (*unstructured.Unstructured, error) {
 	obj, err := rawObjFromCache(store, kind, key)
 	if err != nil {
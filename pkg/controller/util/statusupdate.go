@@ -0,0 +1,114 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+)
+
+// RefreshStatusAnnotation may be set on a federated resource to
+// request that its status be updated without waiting for the status
+// update interval to elapse.  A status update is requested each time
+// the value of the annotation changes, e.g. to the current time.
+const RefreshStatusAnnotation = "kubefed.k8s.io/refresh-status"
+
+// RefreshStatusToken returns the value of the refresh status
+// annotation of the given object, if any.
+func RefreshStatusToken(obj pkgruntime.Object) string {
+	metaObj, err := meta.Accessor(obj)
+	if err != nil {
+		return ""
+	}
+	return metaObj.GetAnnotations()[RefreshStatusAnnotation]
+}
+
+// StatusUpdateCoalescer limits the status updates of each resource
+// reconciled by a controller to one per interval.  A controller
+// reconciling a resource whose status was updated within the interval
+// defers the update until the interval elapses, and the status
+// written then reflects all the changes observed in the meantime.
+// An update requested by a change of the refresh status annotation of
+// a resource is not deferred.  A nil *StatusUpdateCoalescer is valid and does not defer updates.
+type StatusUpdateCoalescer struct {
+	interval time.Duration
+
+	sync.Mutex
+	lastUpdates map[QualifiedName]statusUpdate
+	lastSweep   time.Time
+
+	now func() time.Time
+}
+
+type statusUpdate struct {
+	time         time.Time
+	refreshToken string
+}
+
+// NewStatusUpdateCoalescer returns a coalescer for the given
+// interval, or nil if the interval is not positive.
+func NewStatusUpdateCoalescer(interval time.Duration) *StatusUpdateCoalescer {
+	if interval <= 0 {
+		return nil
+	}
+	return &StatusUpdateCoalescer{
+		interval:    interval,
+		lastUpdates: make(map[QualifiedName]statusUpdate),
+		now:         time.Now,
+	}
+}
+
+// Delay returns the time after which the status of the named resource
+// may be updated.  If the status may be updated immediately, zero is
+// returned and the update is recorded.  The status may always be
+// updated immediately if the refresh token of the resource differs
+// from the one of its last update.
+func (c *StatusUpdateCoalescer) Delay(qualifiedName QualifiedName, refreshToken string) time.Duration {
+	if c == nil {
+		return 0
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	now := c.now()
+	c.sweep(now)
+	if lastUpdate, ok := c.lastUpdates[qualifiedName]; ok && lastUpdate.refreshToken == refreshToken {
+		if delay := lastUpdate.time.Add(c.interval).Sub(now); delay > 0 {
+			return delay
+		}
+	}
+	c.lastUpdates[qualifiedName] = statusUpdate{time: now, refreshToken: refreshToken}
+	return 0
+}
+
+// sweep forgets the updates that no longer defer further updates so
+// that the updates of deleted resources are not retained.
+func (c *StatusUpdateCoalescer) sweep(now time.Time) {
+	if now.Sub(c.lastSweep) < c.interval {
+		return
+	}
+	for qualifiedName, lastUpdate := range c.lastUpdates {
+		if !now.Before(lastUpdate.time.Add(c.interval)) {
+			delete(c.lastUpdates, qualifiedName)
+		}
+	}
+	c.lastSweep = now
+}
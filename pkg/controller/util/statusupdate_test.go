@@ -0,0 +1,60 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusUpdateCoalescer(t *testing.T) {
+	assert.Nil(t, NewStatusUpdateCoalescer(0))
+
+	// A nil coalescer must be safe to use.
+	var nilCoalescer *StatusUpdateCoalescer
+	assert.Equal(t, time.Duration(0), nilCoalescer.Delay(QualifiedName{Name: "foo"}, ""))
+
+	now := time.Now()
+	c := NewStatusUpdateCoalescer(10 * time.Second)
+	c.now = func() time.Time { return now }
+	foo := QualifiedName{Namespace: "ns", Name: "foo"}
+	bar := QualifiedName{Namespace: "ns", Name: "bar"}
+
+	assert.Equal(t, time.Duration(0), c.Delay(foo, ""), "The first update should not be deferred")
+	now = now.Add(4 * time.Second)
+	assert.Equal(t, 6*time.Second, c.Delay(foo, ""), "An update within the interval should be deferred until it elapses")
+	assert.Equal(t, time.Duration(0), c.Delay(bar, ""), "Updates should be coalesced per resource")
+
+	now = now.Add(6 * time.Second)
+	assert.Equal(t, time.Duration(0), c.Delay(foo, ""), "An update should not be deferred once the interval elapsed")
+	assert.Equal(t, 4*time.Second, c.Delay(bar, ""))
+
+	// A change of the refresh token is not deferred, but an unchanged
+	// token does not bypass the interval.
+	now = now.Add(time.Second)
+	assert.Equal(t, time.Duration(0), c.Delay(foo, "1"), "An update requested by a refresh should not be deferred")
+	now = now.Add(time.Second)
+	assert.Equal(t, 9*time.Second, c.Delay(foo, "1"))
+	assert.Equal(t, time.Duration(0), c.Delay(foo, "2"))
+
+	// Updates are forgotten once they no longer defer updates.
+	now = now.Add(time.Minute)
+	c.Delay(foo, "")
+	assert.Len(t, c.lastUpdates, 1)
+}
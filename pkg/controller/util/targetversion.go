@@ -0,0 +1,186 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"github.com/pkg/errors"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// GroupVersionResourcesGetter returns the resources served by a
+// cluster for a group version, or a NotFound error if the group
+// version is not served.  It is implemented by discovery clients and
+// by the resources cached for a cluster by the cluster controller.
+type GroupVersionResourcesGetter interface {
+	ServerResourcesForGroupVersion(groupVersion string) (*metav1.APIResourceList, error)
+}
+
+// NegotiateTargetVersion returns the first of the given versions of
+// the target type, in order of preference, that is served by the
+// cluster.
+func NegotiateTargetVersion(client GroupVersionResourcesGetter, apiResource metav1.APIResource, versions []string) (string, error) {
+	for _, version := range versions {
+		groupVersion := schema.GroupVersion{Group: apiResource.Group, Version: version}.String()
+		resourceList, err := client.ServerResourcesForGroupVersion(groupVersion)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return "", errors.Wrapf(err, "Failed to discover the resources of %q", groupVersion)
+		}
+		for _, resource := range resourceList.APIResources {
+			if resource.Name == apiResource.Name {
+				return version, nil
+			}
+		}
+	}
+	return "", errors.Errorf("None of the versions %v of %q are served", versions, apiResource.Name)
+}
+
+// targetConversion identifies a conversion of a target type between
+// two of its versions.
+type targetConversion struct {
+	schema.GroupKind
+	fromVersion string
+	toVersion   string
+}
+
+// targetConversions holds the conversions between versions of target
+// types whose fields differ.  Versions without a conversion are
+// assumed to have the same fields and are converted by changing the
+// apiVersion only.
+var targetConversions = map[targetConversion]func(obj *unstructured.Unstructured) error{
+	{GroupKind: schema.GroupKind{Group: "networking.k8s.io", Kind: "Ingress"}, fromVersion: "v1beta1", toVersion: "v1"}: convertIngressToV1,
+	{GroupKind: schema.GroupKind{Group: "networking.k8s.io", Kind: "Ingress"}, fromVersion: "v1", toVersion: "v1beta1"}: convertIngressToV1beta1,
+}
+
+// ConvertTarget converts the given resource of a target type to the
+// given version of the type.
+func ConvertTarget(obj *unstructured.Unstructured, version string) error {
+	gvk := obj.GroupVersionKind()
+	if gvk.Version == version {
+		return nil
+	}
+	conversion := targetConversion{GroupKind: gvk.GroupKind(), fromVersion: gvk.Version, toVersion: version}
+	if convert, ok := targetConversions[conversion]; ok {
+		if err := convert(obj); err != nil {
+			return errors.Wrapf(err, "Failed to convert %s from %s to %s", gvk.Kind, gvk.Version, version)
+		}
+	}
+	obj.SetAPIVersion(schema.GroupVersion{Group: gvk.Group, Version: version}.String())
+	return nil
+}
+
+// convertIngressToV1 converts the backends of an ingress from the
+// serviceName and servicePort fields of v1beta1 to the service field
+// of v1.  Paths without a pathType are given the default of v1beta1.
+func convertIngressToV1(obj *unstructured.Unstructured) error {
+	return convertIngressBackends(obj, "backend", "defaultBackend", func(backend map[string]interface{}) {
+		serviceName, hasName := backend["serviceName"]
+		servicePort, hasPort := backend["servicePort"]
+		if !hasName && !hasPort {
+			return
+		}
+		delete(backend, "serviceName")
+		delete(backend, "servicePort")
+		service := map[string]interface{}{}
+		if hasName {
+			service["name"] = serviceName
+		}
+		if hasPort {
+			if portName, ok := servicePort.(string); ok {
+				service["port"] = map[string]interface{}{"name": portName}
+			} else {
+				service["port"] = map[string]interface{}{"number": servicePort}
+			}
+		}
+		backend["service"] = service
+	}, func(path map[string]interface{}) {
+		if _, ok := path["pathType"]; !ok {
+			path["pathType"] = "ImplementationSpecific"
+		}
+	})
+}
+
+// convertIngressToV1beta1 converts the backends of an ingress from the
+// service field of v1 to the serviceName and servicePort fields of
+// v1beta1.
+func convertIngressToV1beta1(obj *unstructured.Unstructured) error {
+	return convertIngressBackends(obj, "defaultBackend", "backend", func(backend map[string]interface{}) {
+		service, ok := backend["service"].(map[string]interface{})
+		if !ok {
+			return
+		}
+		delete(backend, "service")
+		if name, ok := service["name"]; ok {
+			backend["serviceName"] = name
+		}
+		if port, ok := service["port"].(map[string]interface{}); ok {
+			if number, ok := port["number"]; ok {
+				backend["servicePort"] = number
+			} else if name, ok := port["name"]; ok {
+				backend["servicePort"] = name
+			}
+		}
+	}, nil)
+}
+
+// convertIngressBackends renames the default backend of an ingress and
+// converts it and the backends of all its paths with the given
+// function.  The optional path function is invoked for each path.
+func convertIngressBackends(obj *unstructured.Unstructured, fromDefaultField, toDefaultField string,
+	convertBackend func(map[string]interface{}), convertPath func(map[string]interface{})) error {
+
+	spec, ok, err := unstructured.NestedMap(obj.Object, "spec")
+	if err != nil || !ok {
+		return err
+	}
+	if backend, ok := spec[fromDefaultField].(map[string]interface{}); ok {
+		convertBackend(backend)
+		delete(spec, fromDefaultField)
+		spec[toDefaultField] = backend
+	}
+	rules, _ := spec["rules"].([]interface{})
+	for _, rule := range rules {
+		rule, ok := rule.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		http, ok := rule["http"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		paths, _ := http["paths"].([]interface{})
+		for _, path := range paths {
+			path, ok := path.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if backend, ok := path["backend"].(map[string]interface{}); ok {
+				convertBackend(backend)
+			}
+			if convertPath != nil {
+				convertPath(path)
+			}
+		}
+	}
+	return unstructured.SetNestedMap(obj.Object, spec, "spec")
+}
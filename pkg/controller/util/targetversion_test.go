@@ -0,0 +1,122 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+)
+
+// fakeServerResources serves the resources of a fixed set of group
+// versions.
+type fakeServerResources struct {
+	discovery.ServerResourcesInterface
+	resourceLists []*metav1.APIResourceList
+}
+
+func (f *fakeServerResources) ServerResourcesForGroupVersion(groupVersion string) (*metav1.APIResourceList, error) {
+	for _, resourceList := range f.resourceLists {
+		if resourceList.GroupVersion == groupVersion {
+			return resourceList, nil
+		}
+	}
+	return nil, apierrors.NewNotFound(schema.GroupResource{}, groupVersion)
+}
+
+func TestNegotiateTargetVersion(t *testing.T) {
+	ingress := metav1.APIResource{Group: "networking.k8s.io", Version: "v1", Name: "ingresses", Kind: "Ingress"}
+	client := &fakeServerResources{resourceLists: []*metav1.APIResourceList{{
+		GroupVersion: "networking.k8s.io/v1",
+		APIResources: []metav1.APIResource{{Name: "networkpolicies"}},
+	}, {
+		GroupVersion: "networking.k8s.io/v1beta1",
+		APIResources: []metav1.APIResource{{Name: "ingresses"}},
+	}}}
+
+	version, err := NegotiateTargetVersion(client, ingress, []string{"v1", "v1beta1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "v1beta1", version)
+
+	_, err = NegotiateTargetVersion(client, ingress, []string{"v1", "v2"})
+	assert.Error(t, err)
+}
+
+func TestConvertIngress(t *testing.T) {
+	v1beta1Ingress := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "networking.k8s.io/v1beta1",
+		"kind":       "Ingress",
+		"spec": map[string]interface{}{
+			"backend": map[string]interface{}{"serviceName": "default", "servicePort": int64(80)},
+			"rules": []interface{}{map[string]interface{}{
+				"host": "example.com",
+				"http": map[string]interface{}{"paths": []interface{}{map[string]interface{}{
+					"path":    "/",
+					"backend": map[string]interface{}{"serviceName": "web", "servicePort": "http"},
+				}}},
+			}},
+		},
+	}}
+	v1Ingress := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "networking.k8s.io/v1",
+		"kind":       "Ingress",
+		"spec": map[string]interface{}{
+			"defaultBackend": map[string]interface{}{
+				"service": map[string]interface{}{"name": "default", "port": map[string]interface{}{"number": int64(80)}},
+			},
+			"rules": []interface{}{map[string]interface{}{
+				"host": "example.com",
+				"http": map[string]interface{}{"paths": []interface{}{map[string]interface{}{
+					"path":     "/",
+					"pathType": "ImplementationSpecific",
+					"backend": map[string]interface{}{
+						"service": map[string]interface{}{"name": "web", "port": map[string]interface{}{"name": "http"}},
+					},
+				}}},
+			}},
+		},
+	}}
+
+	obj := v1beta1Ingress.DeepCopy()
+	assert.NoError(t, ConvertTarget(obj, "v1"))
+	assert.Equal(t, v1Ingress, obj)
+
+	// The pathType defaulted by the conversion to v1 is retained.
+	expected := v1beta1Ingress.DeepCopy()
+	rules, _, _ := unstructured.NestedSlice(expected.Object, "spec", "rules")
+	rules[0].(map[string]interface{})["http"].(map[string]interface{})["paths"].([]interface{})[0].(map[string]interface{})["pathType"] = "ImplementationSpecific"
+	assert.NoError(t, unstructured.SetNestedSlice(expected.Object, rules, "spec", "rules"))
+	assert.NoError(t, ConvertTarget(obj, "v1beta1"))
+	assert.Equal(t, expected, obj)
+}
+
+func TestConvertTargetWithoutConversion(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "batch/v1beta1",
+		"kind":       "CronJob",
+		"spec":       map[string]interface{}{"schedule": "*/5 * * * *"},
+	}}
+	assert.NoError(t, ConvertTarget(obj, "v1"))
+	assert.Equal(t, "batch/v1", obj.GetAPIVersion())
+	assert.Equal(t, map[string]interface{}{"schedule": "*/5 * * * *"}, obj.Object["spec"])
+}
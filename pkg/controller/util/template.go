@@ -0,0 +1,139 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"io"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/json"
+)
+
+// The maximum size of a decompressed template.  Decompression is
+// bounded so that a small compressed template cannot exhaust the
+// memory of the controller manager.
+const maxDecompressedTemplateSize = 64 * 1024 * 1024
+
+// GetTemplate returns the template of the given federated resource,
+// decompressing it if it is stored in the compressed template field.
+// The boolean return value indicates whether the resource has a
+// template.
+func GetTemplate(fedObj *unstructured.Unstructured) (map[string]interface{}, bool, error) {
+	template, hasTemplate, err := unstructured.NestedMap(fedObj.Object, SpecField, TemplateField)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "Error retrieving template body")
+	}
+	compressed, hasCompressed, err := unstructured.NestedString(fedObj.Object, SpecField, CompressedTemplateField)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "Error retrieving compressed template body")
+	}
+	if !hasCompressed {
+		return template, hasTemplate, nil
+	}
+	if hasTemplate {
+		return nil, false, errors.Errorf("Only one of %q and %q may be set", TemplateField, CompressedTemplateField)
+	}
+	template, err = decompressTemplate(compressed)
+	if err != nil {
+		return nil, false, err
+	}
+	return template, true, nil
+}
+
+// SetTemplate sets the template of the given federated resource,
+// storing it in the compressed template field if compress is true.
+// Only one of the template fields is retained.
+func SetTemplate(fedObj *unstructured.Unstructured, template map[string]interface{}, compress bool) error {
+	if !compress {
+		unstructured.RemoveNestedField(fedObj.Object, SpecField, CompressedTemplateField)
+		return unstructured.SetNestedField(fedObj.Object, template, SpecField, TemplateField)
+	}
+	compressed, err := compressTemplate(template)
+	if err != nil {
+		return err
+	}
+	unstructured.RemoveNestedField(fedObj.Object, SpecField, TemplateField)
+	return unstructured.SetNestedField(fedObj.Object, compressed, SpecField, CompressedTemplateField)
+}
+
+// IsTemplateCompressed indicates whether the template of the given
+// federated resource is stored in the compressed template field.
+func IsTemplateCompressed(fedObj *unstructured.Unstructured) bool {
+	_, ok, _ := unstructured.NestedFieldNoCopy(fedObj.Object, SpecField, CompressedTemplateField)
+	return ok
+}
+
+// CompressTemplate moves the template of the given federated resource
+// to the compressed template field.  A resource without a template
+// is not modified.
+func CompressTemplate(fedObj *unstructured.Unstructured) error {
+	template, ok, err := GetTemplate(fedObj)
+	if err != nil || !ok {
+		return err
+	}
+	return SetTemplate(fedObj, template, true)
+}
+
+// compressTemplate returns the base64 encoding of the gzipped json of
+// the given template.
+func compressTemplate(template map[string]interface{}) (string, error) {
+	templateJSON, err := json.Marshal(template)
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to marshal template")
+	}
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(templateJSON); err != nil {
+		return "", errors.Wrap(err, "Failed to compress template")
+	}
+	if err := writer.Close(); err != nil {
+		return "", errors.Wrap(err, "Failed to compress template")
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+func decompressTemplate(compressed string) (map[string]interface{}, error) {
+	gzipped, err := base64.StdEncoding.DecodeString(compressed)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to decode compressed template")
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(gzipped))
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to decompress template")
+	}
+	defer reader.Close()
+	templateJSON, err := ioutil.ReadAll(io.LimitReader(reader, maxDecompressedTemplateSize+1))
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to decompress template")
+	}
+	if len(templateJSON) > maxDecompressedTemplateSize {
+		return nil, errors.Errorf("Decompressed template exceeds %d bytes", maxDecompressedTemplateSize)
+	}
+	// Numbers are decoded as int64 where possible for consistency
+	// with templates that are not compressed.
+	template := map[string]interface{}{}
+	if err := json.Unmarshal(templateJSON, &template); err != nil {
+		return nil, errors.Wrap(err, "Failed to unmarshal decompressed template")
+	}
+	return template, nil
+}
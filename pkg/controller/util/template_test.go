@@ -0,0 +1,115 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newTemplate() map[string]interface{} {
+	return map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"data": map[string]interface{}{
+			"payload": strings.Repeat("x", 1024*1024),
+		},
+		"immutable": false,
+		"replicas":  int64(3),
+	}
+}
+
+func TestSetTemplateCompressed(t *testing.T) {
+	template := newTemplate()
+	fedObj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+
+	assert.NoError(t, SetTemplate(fedObj, template, true))
+	assert.True(t, IsTemplateCompressed(fedObj))
+	_, ok, _ := unstructured.NestedFieldNoCopy(fedObj.Object, SpecField, TemplateField)
+	assert.False(t, ok)
+	compressed, _, _ := unstructured.NestedString(fedObj.Object, SpecField, CompressedTemplateField)
+	assert.True(t, len(compressed) < 10*1024, "Expected a repetitive template to compress well, got %d bytes", len(compressed))
+
+	decoded, ok, err := GetTemplate(fedObj)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, template, decoded)
+
+	assert.NoError(t, SetTemplate(fedObj, template, false))
+	assert.False(t, IsTemplateCompressed(fedObj))
+	decoded, ok, err = GetTemplate(fedObj)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, template, decoded)
+}
+
+func TestCompressTemplate(t *testing.T) {
+	fedObj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	assert.NoError(t, CompressTemplate(fedObj))
+	assert.False(t, IsTemplateCompressed(fedObj), "A resource without a template should not be modified")
+
+	template := newTemplate()
+	assert.NoError(t, SetTemplate(fedObj, template, false))
+	assert.NoError(t, CompressTemplate(fedObj))
+	assert.True(t, IsTemplateCompressed(fedObj))
+	decoded, _, err := GetTemplate(fedObj)
+	assert.NoError(t, err)
+	assert.Equal(t, template, decoded)
+}
+
+func TestGetTemplateErrors(t *testing.T) {
+	gzipped := func(data []byte) string {
+		var buf bytes.Buffer
+		writer := gzip.NewWriter(&buf)
+		_, _ = writer.Write(data)
+		_ = writer.Close()
+		return base64.StdEncoding.EncodeToString(buf.Bytes())
+	}
+
+	testCases := map[string]map[string]interface{}{
+		"Both template fields set": {
+			TemplateField:           map[string]interface{}{},
+			CompressedTemplateField: gzipped([]byte("{}")),
+		},
+		"Invalid base64": {
+			CompressedTemplateField: "not base64!",
+		},
+		"Not gzipped": {
+			CompressedTemplateField: base64.StdEncoding.EncodeToString([]byte("{}")),
+		},
+		"Not a json object": {
+			CompressedTemplateField: gzipped([]byte("[]")),
+		},
+		"Exceeds the decompressed size limit": {
+			CompressedTemplateField: gzipped(make([]byte, maxDecompressedTemplateSize+1)),
+		},
+	}
+	for testName, spec := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			fedObj := &unstructured.Unstructured{Object: map[string]interface{}{SpecField: spec}}
+			_, _, err := GetTemplate(fedObj)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/yaml"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/kubefed/pkg/apis/core/v1beta1/validation/valuesschema"
+)
+
+// ParseValues parses a Helm values file.  An empty file holds no
+// values.
+func ParseValues(valuesFile string) (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+	jsonBytes, err := yaml.YAMLToJSON([]byte(valuesFile))
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to parse the values file")
+	}
+	// The values of an empty file convert to null.
+	if strings.TrimSpace(string(jsonBytes)) == "null" {
+		return values, nil
+	}
+	// Integers are decoded as int64 like those of unstructured
+	// objects.
+	if err := json.Unmarshal(jsonBytes, &values); err != nil {
+		return nil, errors.Wrap(err, "The values file must contain a map of values")
+	}
+	return values, nil
+}
+
+// GetValuesOverrides returns a map of cluster name to the values
+// defined by the overrides of the given federated resource.
+func GetValuesOverrides(rawObj *unstructured.Unstructured) (map[string]map[string]interface{}, error) {
+	valuesMap := make(map[string]map[string]interface{})
+	if rawObj == nil {
+		return valuesMap, nil
+	}
+
+	override := GenericOverride{}
+	if err := UnstructuredToInterface(rawObj, &override); err != nil {
+		return nil, err
+	}
+	if override.Spec == nil {
+		return valuesMap, nil
+	}
+	for _, overrideItem := range override.Spec.Overrides {
+		if len(overrideItem.Values) == 0 {
+			continue
+		}
+		values, err := ParseValues(overrideItem.Values)
+		if err != nil {
+			return nil, errors.Wrapf(err, "values for cluster %q are invalid", overrideItem.ClusterName)
+		}
+		valuesMap[overrideItem.ClusterName] = values
+	}
+	return valuesMap, nil
+}
+
+// MergeValues merges the given override values into a copy of the
+// base values the way Helm merges values files: maps are merged
+// recursively, other values are replaced and a null value removes
+// the key.
+func MergeValues(base, overrides map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(base))
+	for key, value := range base {
+		merged[key] = value
+	}
+	for key, value := range overrides {
+		if value == nil {
+			delete(merged, key)
+			continue
+		}
+		overrideMap, isMap := value.(map[string]interface{})
+		if baseMap, ok := merged[key].(map[string]interface{}); ok && isMap {
+			merged[key] = MergeValues(baseMap, overrideMap)
+			continue
+		}
+		merged[key] = value
+	}
+	return merged
+}
+
+// ApplyValuesOverrides merges the given values into the chart values
+// of the target resource at the location defined by the values
+// configuration of its type, and validates the resulting values
+// against the schema of the configuration.
+func ApplyValuesOverrides(obj *unstructured.Unstructured, config *fedv1b1.ValuesConfig, overrides map[string]interface{}) error {
+	pathEntries := strings.Split(config.Path, ".")
+	values, found, err := getChartValues(obj, config.Format, pathEntries)
+	if err != nil {
+		return err
+	}
+	if !found && overrides == nil {
+		return nil
+	}
+	if overrides != nil {
+		values = MergeValues(values, overrides)
+	}
+
+	if len(config.Schema) > 0 {
+		schema, err := valuesschema.Parse(config.Schema)
+		if err != nil {
+			return err
+		}
+		if errs := valuesschema.Validate(values, schema, field.NewPath(config.Path)); len(errs) > 0 {
+			return errors.Wrap(errs.ToAggregate(), "The chart values do not match the values schema")
+		}
+	}
+
+	if overrides == nil {
+		return nil
+	}
+	return setChartValues(obj, config.Format, pathEntries, values)
+}
+
+func getChartValues(obj *unstructured.Unstructured, format fedv1b1.ValuesFormat, pathEntries []string) (map[string]interface{}, bool, error) {
+	if format == fedv1b1.ValuesFormatYAML {
+		valuesFile, found, err := unstructured.NestedString(obj.Object, pathEntries...)
+		if err != nil || !found {
+			return map[string]interface{}{}, found, err
+		}
+		values, err := ParseValues(valuesFile)
+		return values, true, err
+	}
+	values, found, err := unstructured.NestedMap(obj.Object, pathEntries...)
+	if err != nil {
+		return nil, false, err
+	}
+	if values == nil {
+		values = map[string]interface{}{}
+	}
+	return values, found, nil
+}
+
+func setChartValues(obj *unstructured.Unstructured, format fedv1b1.ValuesFormat, pathEntries []string, values map[string]interface{}) error {
+	if format == fedv1b1.ValuesFormatYAML {
+		valuesFile, err := yaml.Marshal(values)
+		if err != nil {
+			return errors.Wrap(err, "Failed to encode the chart values")
+		}
+		return unstructured.SetNestedField(obj.Object, string(valuesFile), pathEntries...)
+	}
+	return unstructured.SetNestedMap(obj.Object, values, pathEntries...)
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+)
+
+func TestParseValues(t *testing.T) {
+	values, err := ParseValues("replicaCount: 2\nimage:\n  tag: \"1.0\"\nratio: 0.5\n")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"replicaCount": int64(2),
+		"image":        map[string]interface{}{"tag": "1.0"},
+		"ratio":        0.5,
+	}, values)
+
+	values, err = ParseValues("# no values\n")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{}, values)
+
+	_, err = ParseValues("- replicaCount")
+	assert.Error(t, err)
+}
+
+func TestMergeValues(t *testing.T) {
+	base := map[string]interface{}{
+		"replicaCount": int64(1),
+		"image": map[string]interface{}{
+			"repository": "nginx",
+			"tag":        "1.0",
+		},
+		"tolerations": []interface{}{"a", "b"},
+		"ingress":     map[string]interface{}{"enabled": true},
+	}
+	merged := MergeValues(base, map[string]interface{}{
+		"replicaCount": int64(3),
+		"image":        map[string]interface{}{"tag": "2.0"},
+		"tolerations":  []interface{}{"c"},
+		"ingress":      nil,
+		"region":       "us-east",
+	})
+	assert.Equal(t, map[string]interface{}{
+		"replicaCount": int64(3),
+		"image": map[string]interface{}{
+			"repository": "nginx",
+			"tag":        "2.0",
+		},
+		"tolerations": []interface{}{"c"},
+		"region":      "us-east",
+	}, merged)
+	// The base values are not modified.
+	assert.Equal(t, "1.0", base["image"].(map[string]interface{})["tag"])
+	assert.Contains(t, base, "ingress")
+}
+
+func TestApplyValuesOverrides(t *testing.T) {
+	schema := `{"type": "object", "properties": {"replicaCount": {"type": "integer", "maximum": 5}}}`
+	testCases := map[string]struct {
+		config         fedv1b1.ValuesConfig
+		obj            map[string]interface{}
+		overrides      map[string]interface{}
+		expectedValues interface{}
+		expectedErr    bool
+	}{
+		"Values of an object field are merged": {
+			config: fedv1b1.ValuesConfig{Path: "spec.values", Schema: schema},
+			obj: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"values": map[string]interface{}{"replicaCount": int64(1), "region": "default"},
+				},
+			},
+			overrides: map[string]interface{}{"replicaCount": int64(3)},
+			expectedValues: map[string]interface{}{
+				"replicaCount": int64(3),
+				"region":       "default",
+			},
+		},
+		"Values are set if the template defines none": {
+			config:         fedv1b1.ValuesConfig{Path: "spec.values"},
+			obj:            map[string]interface{}{"spec": map[string]interface{}{}},
+			overrides:      map[string]interface{}{"replicaCount": int64(3)},
+			expectedValues: map[string]interface{}{"replicaCount": int64(3)},
+		},
+		"Values of a values file are merged": {
+			config: fedv1b1.ValuesConfig{Path: "spec.source.helm.values", Format: fedv1b1.ValuesFormatYAML},
+			obj: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"source": map[string]interface{}{
+						"helm": map[string]interface{}{"values": "replicaCount: 1\nregion: default\n"},
+					},
+				},
+			},
+			overrides:      map[string]interface{}{"replicaCount": int64(3)},
+			expectedValues: "region: default\nreplicaCount: 3\n",
+		},
+		"Merged values must match the schema": {
+			config: fedv1b1.ValuesConfig{Path: "spec.values", Schema: schema},
+			obj: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"values": map[string]interface{}{"replicaCount": int64(1)},
+				},
+			},
+			overrides:   map[string]interface{}{"replicaCount": int64(10)},
+			expectedErr: true,
+		},
+		"Template values must match the schema without overrides": {
+			config: fedv1b1.ValuesConfig{Path: "spec.values", Schema: schema},
+			obj: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"values": map[string]interface{}{"replicaCount": "one"},
+				},
+			},
+			expectedErr: true,
+		},
+		"A resource without values is unchanged": {
+			config:         fedv1b1.ValuesConfig{Path: "spec.values", Schema: `{"type": "object", "required": ["replicaCount"]}`},
+			obj:            map[string]interface{}{"spec": map[string]interface{}{}},
+			expectedValues: nil,
+		},
+	}
+
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			config := tc.config
+			obj := &unstructured.Unstructured{Object: tc.obj}
+			err := ApplyValuesOverrides(obj, &config, tc.overrides)
+			if tc.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			values, _, err := unstructured.NestedFieldNoCopy(obj.Object, strings.Split(config.Path, ".")...)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedValues, values)
+		})
+	}
+}
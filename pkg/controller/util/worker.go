@@ -17,16 +17,81 @@ limitations under the License.
 package util
 
 import (
+	"sync"
 	"time"
 
 	pkgruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/util/flowcontrol"
 	"k8s.io/client-go/util/workqueue"
+
+	"sigs.k8s.io/kubefed/pkg/metrics"
+)
+
+const (
+	retryReasonError     = "error"
+	retryReasonRecheck   = "recheck"
+	retryReasonNotSynced = "not_synced"
 )
 
 type ReconcileFunc func(qualifiedName QualifiedName) ReconciliationStatus
 
+// reconciles tracks the reconciliations in progress in all workers of
+// the process so that shutdown can wait for them to complete.
+var reconciles = &reconcileTracker{}
+
+type reconcileTracker struct {
+	lock     sync.Mutex
+	stopped  bool
+	inFlight int
+}
+
+// start records the start of a reconciliation and returns false if
+// no new reconciliations may be started.
+func (t *reconcileTracker) start() bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.stopped {
+		return false
+	}
+	t.inFlight++
+	return true
+}
+
+func (t *reconcileTracker) done() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.inFlight--
+}
+
+// drain prevents new reconciliations from being started and waits
+// until those in progress have completed or the timeout has elapsed.
+// Returns the number of reconciliations still in progress.
+func (t *reconcileTracker) drain(timeout time.Duration) int {
+	t.lock.Lock()
+	t.stopped = true
+	t.lock.Unlock()
+
+	inFlight := 0
+	_ = wait.PollImmediate(50*time.Millisecond, timeout, func() (bool, error) {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		inFlight = t.inFlight
+		return inFlight == 0, nil
+	})
+	return inFlight
+}
+
+// DrainReconciles prevents the workers of the process from starting
+// new reconciliations and waits up to the given timeout for those in
+// progress, including their dispatch of changes to member clusters and
+// updates to the status of federated resources, to complete.  Returns
+// the number of reconciliations that did not complete in time.  It is
+// intended to be called once on shutdown.
+func DrainReconciles(timeout time.Duration) int {
+	return reconciles.drain(timeout)
+}
+
 type ReconcileWorker interface {
 	Enqueue(qualifiedName QualifiedName)
 	EnqueueForClusterSync(qualifiedName QualifiedName)
@@ -38,6 +103,15 @@ type ReconcileWorker interface {
 	SetDelay(retryDelay, clusterSyncDelay time.Duration)
 }
 
+// WorkerName identifies a reconcile worker in the metrics it records.
+type WorkerName struct {
+	// Controller is the name of the controller running the worker.
+	Controller string
+	// TypeConfig is the name of the FederatedTypeConfig the worker is
+	// reconciling resources of, if any.
+	TypeConfig string
+}
+
 type WorkerTiming struct {
 	Interval         time.Duration
 	RetryDelay       time.Duration
@@ -47,6 +121,8 @@ type WorkerTiming struct {
 }
 
 type asyncWorker struct {
+	name WorkerName
+
 	reconcile ReconcileFunc
 
 	timing WorkerTiming
@@ -64,20 +140,25 @@ type asyncWorker struct {
 	backoff *flowcontrol.Backoff
 }
 
-func NewReconcileWorker(reconcile ReconcileFunc, timing WorkerTiming) ReconcileWorker {
-	if timing.Interval == 0 {
-		timing.Interval = time.Second * 1
+func (t *WorkerTiming) setDefaults() {
+	if t.Interval == 0 {
+		t.Interval = time.Second * 1
 	}
-	if timing.RetryDelay == 0 {
-		timing.RetryDelay = time.Second * 10
+	if t.RetryDelay == 0 {
+		t.RetryDelay = time.Second * 10
 	}
-	if timing.InitialBackoff == 0 {
-		timing.InitialBackoff = time.Second * 5
+	if t.InitialBackoff == 0 {
+		t.InitialBackoff = time.Second * 5
 	}
-	if timing.MaxBackoff == 0 {
-		timing.MaxBackoff = time.Minute
+	if t.MaxBackoff == 0 {
+		t.MaxBackoff = time.Minute
 	}
+}
+
+func NewReconcileWorker(name WorkerName, reconcile ReconcileFunc, timing WorkerTiming) ReconcileWorker {
+	timing.setDefaults()
 	return &asyncWorker{
+		name:      name,
 		reconcile: reconcile,
 		timing:    timing,
 		deliverer: NewDelayingDeliverer(),
@@ -115,6 +196,7 @@ func (w *asyncWorker) Run(stopChan <-chan struct{}) {
 	StartBackoffGC(w.backoff, stopChan)
 	w.deliverer.StartWithHandler(func(item *DelayingDelivererItem) {
 		w.queue.Add(item)
+		metrics.RecordWorkqueueAdd(w.name.Controller, w.name.TypeConfig, w.queue.Len())
 	})
 	go wait.Until(w.worker, w.timing.Interval, stopChan)
 
@@ -151,19 +233,32 @@ func (w *asyncWorker) worker() {
 			return
 		}
 
+		metrics.RecordWorkqueueDepth(w.name.Controller, w.name.TypeConfig, w.queue.Len())
+
 		item := obj.(*DelayingDelivererItem)
+		if !reconciles.start() {
+			// The process is shutting down.
+			w.queue.Done(item)
+			return
+		}
 		qualifiedName := item.Value.(*QualifiedName)
+		startTime := time.Now()
 		status := w.reconcile(*qualifiedName)
 		w.queue.Done(item)
+		reconciles.done()
+		metrics.RecordReconcile(w.name.Controller, w.name.TypeConfig, time.Since(startTime), status == StatusError)
 
 		switch status {
 		case StatusAllOK:
 			break
 		case StatusError:
+			metrics.RecordWorkqueueRetry(w.name.Controller, w.name.TypeConfig, retryReasonError)
 			w.EnqueueForError(*qualifiedName)
 		case StatusNeedsRecheck:
+			metrics.RecordWorkqueueRetry(w.name.Controller, w.name.TypeConfig, retryReasonRecheck)
 			w.EnqueueForRetry(*qualifiedName)
 		case StatusNotSynced:
+			metrics.RecordWorkqueueRetry(w.name.Controller, w.name.TypeConfig, retryReasonNotSynced)
 			w.EnqueueForClusterSync(*qualifiedName)
 		}
 	}
@@ -0,0 +1,48 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+func TestReconcileTrackerDrain(t *testing.T) {
+	tracker := &reconcileTracker{}
+	assert.True(t, tracker.start())
+	assert.True(t, tracker.start())
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		tracker.done()
+		tracker.done()
+	}()
+	assert.Equal(t, 0, tracker.drain(wait.ForeverTestTimeout))
+
+	// No reconciliations are started once draining.
+	assert.False(t, tracker.start())
+}
+
+func TestReconcileTrackerDrainTimeout(t *testing.T) {
+	tracker := &reconcileTracker{}
+	assert.True(t, tracker.start())
+	assert.Equal(t, 1, tracker.drain(100*time.Millisecond))
+}
@@ -18,13 +18,17 @@ package federatedtypeconfig
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strings"
 	"sync"
 
 	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/rest"
 	"k8s.io/klog"
@@ -40,7 +44,8 @@ const (
 )
 
 type FederatedTypeConfigValidationHook struct {
-	client dynamic.ResourceInterface
+	client          dynamic.ResourceInterface
+	discoveryClient discovery.DiscoveryInterface
 
 	lock        sync.RWMutex
 	initialized bool
@@ -85,8 +90,35 @@ func (a *FederatedTypeConfigValidationHook) Validate(admissionSpec *admissionv1b
 		return status
 	}
 
+	unknownFieldErrs, unknownFieldWarnings, err := validation.ValidateUnknownFields(admissionSpec.Object.Raw, admissionSpec.OldObject.Raw, admittingObject)
+	if err != nil {
+		status.Allowed = false
+		status.Result = &metav1.Status{
+			Status: metav1.StatusFailure, Code: http.StatusBadRequest, Reason: metav1.StatusReasonBadRequest,
+			Message: err.Error(),
+		}
+		return status
+	}
+
 	isStatusSubResource := len(admissionSpec.SubResource) != 0
 	errs := validation.ValidateFederatedTypeConfig(admittingObject, isStatusSubResource)
+	errs = append(errs, unknownFieldErrs...)
+	if len(errs) == 0 && !isStatusSubResource && targetTypeChanged(admittingObject, admissionSpec.OldObject.Raw) {
+		// Only a well-formed target type is worth looking up, and
+		// an unchanged one is not checked again so that a type
+		// config can still be updated (e.g. to disable
+		// propagation) after its target type has been removed.
+		servedErrs, err := a.validateTargetTypeServed(&admittingObject.Spec.TargetType)
+		if err != nil {
+			status.Allowed = false
+			status.Result = &metav1.Status{
+				Status: metav1.StatusFailure, Code: http.StatusInternalServerError, Reason: metav1.StatusReasonInternalError,
+				Message: err.Error(),
+			}
+			return status
+		}
+		errs = append(errs, servedErrs...)
+	}
 	if len(errs) != 0 {
 		status.Allowed = false
 		status.Result = &metav1.Status{
@@ -97,6 +129,8 @@ func (a *FederatedTypeConfigValidationHook) Validate(admissionSpec *admissionv1b
 	}
 
 	status.Allowed = true
+	warnings := validation.FederatedTypeConfigWarnings(admittingObject, isStatusSubResource)
+	webhook.AddWarnings(admissionSpec, status, append(warnings, unknownFieldWarnings...))
 	return status
 }
 
@@ -106,6 +140,12 @@ func (a *FederatedTypeConfigValidationHook) Initialize(kubeClientConfig *rest.Co
 
 	a.initialized = true
 
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(kubeClientConfig)
+	if err != nil {
+		return err
+	}
+	a.discoveryClient = discoveryClient
+
 	shallowClientConfigCopy := *kubeClientConfig
 	shallowClientConfigCopy.GroupVersion = &schema.GroupVersion{
 		Group:   v1beta1.SchemeGroupVersion.Group,
@@ -124,3 +164,30 @@ func (a *FederatedTypeConfigValidationHook) Initialize(kubeClientConfig *rest.Co
 
 	return nil
 }
+
+// validateTargetTypeServed uses API discovery to check that the given
+// target type is served by the host cluster.
+func (a *FederatedTypeConfigValidationHook) validateTargetTypeServed(targetType *v1beta1.APIResource) (field.ErrorList, error) {
+	groupVersion := schema.GroupVersion{Group: targetType.Group, Version: targetType.Version}.String()
+	resources, err := a.discoveryClient.ServerResourcesForGroupVersion(groupVersion)
+	if apierrors.IsNotFound(err) {
+		resources = nil
+	} else if err != nil {
+		return nil, fmt.Errorf("unable to discover the resources served for %s: %v", groupVersion, err)
+	}
+	return validation.ValidateTargetTypeServed(targetType, resources, field.NewPath("spec", "targetType")), nil
+}
+
+// targetTypeChanged returns whether the target type of the admitted
+// type config differs from that of the previous version of the object,
+// if any.
+func targetTypeChanged(obj *v1beta1.FederatedTypeConfig, oldRaw []byte) bool {
+	if len(oldRaw) == 0 {
+		return true
+	}
+	oldObj := &v1beta1.FederatedTypeConfig{}
+	if err := json.Unmarshal(oldRaw, oldObj); err != nil {
+		return true
+	}
+	return obj.Spec.TargetType != oldObj.Spec.TargetType
+}
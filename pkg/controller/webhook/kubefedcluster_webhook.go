@@ -18,22 +18,32 @@ package webhook
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"sync"
 
+	"github.com/pkg/errors"
+
 	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
+	authorizationclient "k8s.io/client-go/kubernetes/typed/authorization/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/rest"
 	"k8s.io/klog"
 
 	"sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
 	"sigs.k8s.io/kubefed/pkg/apis/core/v1beta1/validation"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
 )
 
 type KubeFedClusterValidationHook struct {
-	client dynamic.ResourceInterface
+	client    dynamic.ResourceInterface
+	sarClient authorizationclient.SubjectAccessReviewInterface
+	secrets   corev1client.SecretsGetter
 
 	lock        sync.RWMutex
 	initialized bool
@@ -86,7 +96,18 @@ func (a *KubeFedClusterValidationHook) Validate(admissionSpec *admissionv1beta1.
 		return status
 	}
 
+	unknownFieldErrs, unknownFieldWarnings, err := validation.ValidateUnknownFields(admissionSpec.Object.Raw, admissionSpec.OldObject.Raw, admittingObject)
+	if err != nil {
+		status.Allowed = false
+		status.Result = &metav1.Status{
+			Status: metav1.StatusFailure, Code: http.StatusBadRequest, Reason: metav1.StatusReasonBadRequest,
+			Message: err.Error(),
+		}
+		return status
+	}
+
 	errs := validation.ValidateKubeFedCluster(admittingObject)
+	errs = append(errs, unknownFieldErrs...)
 	if len(errs) != 0 {
 		status.Allowed = false
 		status.Result = &metav1.Status{
@@ -96,10 +117,130 @@ func (a *KubeFedClusterValidationHook) Validate(admissionSpec *admissionv1beta1.
 		return status
 	}
 
+	allowed, err := a.secretAccessAllowed(admissionSpec, admittingObject)
+	switch {
+	case err != nil:
+		status.Allowed = false
+		status.Result = &metav1.Status{
+			Status: metav1.StatusFailure, Code: http.StatusInternalServerError, Reason: metav1.StatusReasonInternalError,
+			Message: err.Error(),
+		}
+		return status
+	case !allowed:
+		secretRef := admittingObject.Spec.SecretRef
+		status.Allowed = false
+		status.Result = &metav1.Status{
+			Status: metav1.StatusFailure, Code: http.StatusForbidden, Reason: metav1.StatusReasonForbidden,
+			Message: fmt.Sprintf("spec.secretRef.namespace: user %q is not permitted to get secret %s/%s",
+				admissionSpec.UserInfo.Username, secretRef.Namespace, secretRef.Name),
+		}
+		return status
+	}
+
+	allowed, err = a.registrationAllowed(admissionSpec, admittingObject)
+	switch {
+	case err != nil:
+		status.Allowed = false
+		status.Result = &metav1.Status{
+			Status: metav1.StatusFailure, Code: http.StatusInternalServerError, Reason: metav1.StatusReasonInternalError,
+			Message: err.Error(),
+		}
+		return status
+	case !allowed:
+		status.Allowed = false
+		status.Result = &metav1.Status{
+			Status: metav1.StatusFailure, Code: http.StatusForbidden, Reason: metav1.StatusReasonForbidden,
+			Message: fmt.Sprintf("spec.secretRef.name: user %q may only register cluster %q with a secret created by the same registration",
+				admissionSpec.UserInfo.Username, admittingObject.Name),
+		}
+		return status
+	}
+
 	status.Allowed = true
+	warnings := validation.KubeFedClusterWarnings(admittingObject)
+	AddWarnings(admissionSpec, status, append(warnings, unknownFieldWarnings...))
 	return status
 }
 
+// secretAccessAllowed checks that the requesting user is permitted to
+// get a secret referenced in a namespace other than that of the
+// cluster.  Otherwise a user permitted to create clusters could have
+// the controller manager use credentials the user cannot read.  The
+// check is skipped for updates that do not change the reference and
+// for local clusters, whose secret is not used.
+func (a *KubeFedClusterValidationHook) secretAccessAllowed(admissionSpec *admissionv1beta1.AdmissionRequest, cluster *v1beta1.KubeFedCluster) (bool, error) {
+	secretRef := cluster.Spec.SecretRef
+	if cluster.Spec.Local || len(secretRef.Namespace) == 0 || secretRef.Namespace == cluster.Namespace {
+		return true, nil
+	}
+	if admissionSpec.Operation == admissionv1beta1.Update {
+		oldCluster := &v1beta1.KubeFedCluster{}
+		if err := json.Unmarshal(admissionSpec.OldObject.Raw, oldCluster); err == nil && oldCluster.Spec.SecretRef == secretRef {
+			return true, nil
+		}
+	}
+
+	userInfo := admissionSpec.UserInfo
+	extra := make(map[string]authorizationv1.ExtraValue, len(userInfo.Extra))
+	for key, value := range userInfo.Extra {
+		extra[key] = authorizationv1.ExtraValue(value)
+	}
+	review := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: secretRef.Namespace,
+				Verb:      "get",
+				Resource:  "secrets",
+				Name:      secretRef.Name,
+			},
+			User:   userInfo.Username,
+			Groups: userInfo.Groups,
+			UID:    userInfo.UID,
+			Extra:  extra,
+		},
+	}
+	result, err := a.sarClient.Create(review)
+	if err != nil {
+		return false, errors.Wrapf(err, "Failed to check access to secret %s/%s", secretRef.Namespace, secretRef.Name)
+	}
+	return result.Status.Allowed, nil
+}
+
+// registrationAllowed checks that a cluster admitted for a member of
+// the bootstrap registrant group references a secret created by the
+// same registration: a secret in the namespace of the cluster that is
+// annotated with the name of the cluster and the user of the bootstrap
+// token.  Otherwise a token holder could have the controller manager
+// access the API endpoint of its choosing with the credentials of a
+// cluster that is already registered.
+func (a *KubeFedClusterValidationHook) registrationAllowed(admissionSpec *admissionv1beta1.AdmissionRequest, cluster *v1beta1.KubeFedCluster) (bool, error) {
+	userInfo := admissionSpec.UserInfo
+	registrant := false
+	for _, group := range userInfo.Groups {
+		if group == util.BootstrapRegistrantGroup {
+			registrant = true
+			break
+		}
+	}
+	if !registrant {
+		return true, nil
+	}
+
+	secretRef := cluster.Spec.SecretRef
+	if cluster.Spec.Local || (len(secretRef.Namespace) != 0 && secretRef.Namespace != admissionSpec.Namespace) {
+		return false, nil
+	}
+	secret, err := a.secrets.Secrets(admissionSpec.Namespace).Get(secretRef.Name, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		return false, nil
+	case err != nil:
+		return false, errors.Wrapf(err, "Failed to get secret %s/%s", admissionSpec.Namespace, secretRef.Name)
+	}
+	return secret.Annotations[util.RegisteredClusterAnnotation] == cluster.Name &&
+		secret.Annotations[util.RegisteredByAnnotation] == userInfo.Username, nil
+}
+
 func (a *KubeFedClusterValidationHook) Initialize(kubeClientConfig *rest.Config, stopCh <-chan struct{}) error {
 	a.lock.Lock()
 	defer a.lock.Unlock()
@@ -122,5 +263,17 @@ func (a *KubeFedClusterValidationHook) Initialize(kubeClientConfig *rest.Config,
 		Resource: "KubeFedCluster",
 	})
 
+	authorizationClient, err := authorizationclient.NewForConfig(kubeClientConfig)
+	if err != nil {
+		return err
+	}
+	a.sarClient = authorizationClient.SubjectAccessReviews()
+
+	coreClient, err := corev1client.NewForConfig(kubeClientConfig)
+	if err != nil {
+		return err
+	}
+	a.secrets = coreClient
+
 	return nil
 }
@@ -0,0 +1,111 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+func TestRegistrationAllowed(t *testing.T) {
+	namespace := "kube-federation-system"
+	registrant := util.BootstrapUserPrefix + "abcdef"
+	newSecret := func(name, clusterName, registeredBy string) *corev1.Secret {
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+		if clusterName != "" {
+			secret.Annotations = map[string]string{
+				util.RegisteredClusterAnnotation: clusterName,
+				util.RegisteredByAnnotation:      registeredBy,
+			}
+		}
+		return secret
+	}
+	hook := &KubeFedClusterValidationHook{
+		secrets: fake.NewSimpleClientset(
+			newSecret("cluster1-joined", "", ""),
+			newSecret("cluster2-abcde", "cluster2", registrant),
+			newSecret("cluster3-abcde", "cluster3", util.BootstrapUserPrefix+"ghijkl"),
+		).CoreV1(),
+	}
+
+	testCases := map[string]struct {
+		groups    []string
+		secretRef v1beta1.LocalSecretReference
+		local     bool
+		allowed   bool
+	}{
+		"cluster created by another user": {
+			groups:    []string{"system:masters"},
+			secretRef: v1beta1.LocalSecretReference{Name: "cluster1-joined"},
+			allowed:   true,
+		},
+		"secret of the registration": {
+			groups:    []string{util.BootstrapRegistrantGroup},
+			secretRef: v1beta1.LocalSecretReference{Name: "cluster2-abcde"},
+			allowed:   true,
+		},
+		"secret of a joined cluster": {
+			groups:    []string{util.BootstrapRegistrantGroup},
+			secretRef: v1beta1.LocalSecretReference{Name: "cluster1-joined"},
+		},
+		"secret of another registrant": {
+			groups:    []string{util.BootstrapRegistrantGroup},
+			secretRef: v1beta1.LocalSecretReference{Name: "cluster3-abcde"},
+		},
+		"secret in another namespace": {
+			groups:    []string{util.BootstrapRegistrantGroup},
+			secretRef: v1beta1.LocalSecretReference{Name: "cluster2-abcde", Namespace: "other"},
+		},
+		"missing secret": {
+			groups:    []string{util.BootstrapRegistrantGroup},
+			secretRef: v1beta1.LocalSecretReference{Name: "missing"},
+		},
+		"local cluster": {
+			groups:    []string{util.BootstrapRegistrantGroup},
+			secretRef: v1beta1.LocalSecretReference{Name: "cluster2-abcde"},
+			local:     true,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			request := &admissionv1beta1.AdmissionRequest{
+				Operation: admissionv1beta1.Create,
+				Namespace: namespace,
+				UserInfo:  authenticationv1.UserInfo{Username: registrant, Groups: tc.groups},
+			}
+			cluster := &v1beta1.KubeFedCluster{
+				ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "cluster2"},
+				Spec:       v1beta1.KubeFedClusterSpec{SecretRef: tc.secretRef, Local: tc.local},
+			}
+			allowed, err := hook.registrationAllowed(request, cluster)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, tc.allowed, allowed)
+		})
+	}
+}
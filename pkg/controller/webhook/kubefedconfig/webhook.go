@@ -0,0 +1,119 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubefedconfig
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog"
+
+	"sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/kubefed/pkg/apis/core/v1beta1/validation"
+	"sigs.k8s.io/kubefed/pkg/controller/webhook"
+)
+
+const (
+	resourceName       = "KubeFedConfig"
+	resourcePluralName = "kubefedconfigs"
+)
+
+// KubeFedConfigValidationHook rejects KubeFedConfigs with unknown
+// fields.  The spec is otherwise validated by the controller manager
+// when it applies the configuration, since fields that are not set
+// are defaulted at that point.
+type KubeFedConfigValidationHook struct {
+	lock        sync.RWMutex
+	initialized bool
+}
+
+func (a *KubeFedConfigValidationHook) ValidatingResource() (plural schema.GroupVersionResource, singular string) {
+	return webhook.NewValidatingResource(resourcePluralName), strings.ToLower(resourceName)
+}
+
+func (a *KubeFedConfigValidationHook) Validate(admissionSpec *admissionv1beta1.AdmissionRequest) *admissionv1beta1.AdmissionResponse {
+	status := &admissionv1beta1.AdmissionResponse{}
+
+	// We want to let through:
+	// - Requests that are not for create, update
+	// - Requests for subresources
+	// - Requests for things that are not KubeFedConfigs
+	if webhook.Allowed(admissionSpec, resourcePluralName) || len(admissionSpec.SubResource) != 0 {
+		status.Allowed = true
+		return status
+	}
+
+	klog.V(4).Infof("Validating AdmissionRequest = %v", admissionSpec)
+
+	admittingObject := &v1beta1.KubeFedConfig{}
+	err := json.Unmarshal(admissionSpec.Object.Raw, admittingObject)
+	if err != nil {
+		status.Allowed = false
+		status.Result = &metav1.Status{
+			Status: metav1.StatusFailure, Code: http.StatusBadRequest, Reason: metav1.StatusReasonBadRequest,
+			Message: err.Error(),
+		}
+		return status
+	}
+
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+	if !a.initialized {
+		status.Allowed = false
+		status.Result = &metav1.Status{
+			Status: metav1.StatusFailure, Code: http.StatusInternalServerError, Reason: metav1.StatusReasonInternalError,
+			Message: "not initialized",
+		}
+		return status
+	}
+
+	errs, warnings, err := validation.ValidateUnknownFields(admissionSpec.Object.Raw, admissionSpec.OldObject.Raw, admittingObject)
+	if err != nil {
+		status.Allowed = false
+		status.Result = &metav1.Status{
+			Status: metav1.StatusFailure, Code: http.StatusBadRequest, Reason: metav1.StatusReasonBadRequest,
+			Message: err.Error(),
+		}
+		return status
+	}
+	if len(errs) != 0 {
+		status.Allowed = false
+		status.Result = &metav1.Status{
+			Status: metav1.StatusFailure, Code: http.StatusForbidden, Reason: metav1.StatusReasonForbidden,
+			Message: errs.ToAggregate().Error(),
+		}
+		return status
+	}
+
+	status.Allowed = true
+	webhook.AddWarnings(admissionSpec, status, warnings)
+	return status
+}
+
+func (a *KubeFedConfigValidationHook) Initialize(kubeClientConfig *rest.Config, stopCh <-chan struct{}) error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	a.initialized = true
+	return nil
+}
@@ -17,8 +17,11 @@ limitations under the License.
 package webhook
 
 import (
+	"fmt"
+
 	admissionv1beta1 "k8s.io/api/admission/v1beta1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog"
 
 	"sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
 )
@@ -46,3 +49,27 @@ func Allowed(a *admissionv1beta1.AdmissionRequest, pluralResourceName string) bo
 	isMyGroupAndResource := a.Resource.Group == v1beta1.SchemeGroupVersion.Group && a.Resource.Resource == pluralResourceName
 	return !createOrUpdate || !isMyGroupAndResource
 }
+
+// AddWarnings records non-fatal problems found while validating an
+// admitted request.  The vendored admission API predates the warnings
+// field of admission responses, so warnings are recorded as audit
+// annotations of the request (e.g. warning-0) and logged.
+func AddWarnings(a *admissionv1beta1.AdmissionRequest, status *admissionv1beta1.AdmissionResponse, warnings []string) {
+	if len(warnings) == 0 {
+		return
+	}
+	if status.AuditAnnotations == nil {
+		status.AuditAnnotations = make(map[string]string)
+	}
+	for i, warning := range warnings {
+		status.AuditAnnotations[fmt.Sprintf("warning-%d", i)] = warning
+		klog.Warningf("Admitting %s %s %q with warning: %s", a.Operation, a.Kind.Kind, qualifiedName(a), warning)
+	}
+}
+
+func qualifiedName(a *admissionv1beta1.AdmissionRequest) string {
+	if len(a.Namespace) == 0 {
+		return a.Name
+	}
+	return a.Namespace + "/" + a.Name
+}
@@ -52,6 +52,67 @@ const (
 	//
 	// DNS based federated ingress feature.
 	FederatedIngress utilfeature.Feature = "FederatedIngress"
+
+	// owner: @kubernetes-sigs/kubefed-maintainers
+	// alpha: v0.1
+	//
+	// Warning events about propagated resources in member clusters
+	// are mirrored to the federated resources in the host cluster.
+	FederatedEvents utilfeature.Feature = "FederatedEvents"
+
+	// owner: @kubernetes-sigs/kubefed-maintainers
+	// alpha: v0.1
+	//
+	// Resources in the host cluster labeled for auto-federation are
+	// propagated by federated resources maintained by KubeFed.
+	AutoFederation utilfeature.Feature = "AutoFederation"
+
+	// owner: @kubernetes-sigs/kubefed-maintainers
+	// alpha: v0.1
+	//
+	// The status of resources in member clusters is collected by the
+	// sync controller into the status of the federated resources of
+	// any type with status collection enabled.
+	RawResourceStatusCollection utilfeature.Feature = "RawResourceStatusCollection"
+
+	// owner: @kubernetes-sigs/kubefed-maintainers
+	// alpha: v0.1
+	//
+	// Member clusters are not used for placement or propagation until
+	// they are approved by an Approved condition in their status.
+	ClusterJoinApproval utilfeature.Feature = "ClusterJoinApproval"
+
+	// owner: @kubernetes-sigs/kubefed-maintainers
+	// alpha: v0.1
+	//
+	// The cluster health check collects the unschedulable pods, the
+	// nodes under pressure and the failed scale-ups of each member
+	// cluster into the status of its KubeFedCluster.
+	ClusterPressure utilfeature.Feature = "ClusterPressure"
+
+	// owner: @kubernetes-sigs/kubefed-maintainers
+	// alpha: v0.1
+	//
+	// Istio ServiceEntries are maintained in member clusters for the
+	// services with cross-cluster discovery enabled, routing them
+	// across the clusters of the mesh through east-west gateways.
+	IstioMultiCluster utilfeature.Feature = "IstioMultiCluster"
+
+	// owner: @kubernetes-sigs/kubefed-maintainers
+	// alpha: v0.1
+	//
+	// Federated services are exported by ServiceExports in the member
+	// clusters in which Submariner Lighthouse is installed, and their
+	// ServiceImports are recorded in the status of federated services.
+	SubmarinerLighthouse utilfeature.Feature = "SubmarinerLighthouse"
+
+	// owner: @kubernetes-sigs/kubefed-maintainers
+	// alpha: v0.1
+	//
+	// The values of cluster overrides may be sourced from ConfigMaps
+	// and Secrets of the host cluster and from the profile ConfigMaps
+	// of member clusters.
+	OverrideValueSources utilfeature.Feature = "OverrideValueSources"
 )
 
 func init() {
@@ -68,4 +129,12 @@ var defaultKubeFedFeatureGates = map[utilfeature.Feature]utilfeature.FeatureSpec
 	PushReconciler:               {Default: true, PreRelease: utilfeature.Alpha},
 	CrossClusterServiceDiscovery: {Default: true, PreRelease: utilfeature.Alpha},
 	FederatedIngress:             {Default: true, PreRelease: utilfeature.Alpha},
+	FederatedEvents:              {Default: false, PreRelease: utilfeature.Alpha},
+	AutoFederation:               {Default: false, PreRelease: utilfeature.Alpha},
+	RawResourceStatusCollection:  {Default: false, PreRelease: utilfeature.Alpha},
+	ClusterJoinApproval:          {Default: false, PreRelease: utilfeature.Alpha},
+	ClusterPressure:              {Default: false, PreRelease: utilfeature.Alpha},
+	IstioMultiCluster:            {Default: false, PreRelease: utilfeature.Alpha},
+	SubmarinerLighthouse:         {Default: false, PreRelease: utilfeature.Alpha},
+	OverrideValueSources:         {Default: false, PreRelease: utilfeature.Alpha},
 }
@@ -0,0 +1,209 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admissionpolicy
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/kubefed/pkg/apis/core/v1beta1/validation"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/util"
+)
+
+const (
+	admissionRegistrationGroup = "admissionregistration.k8s.io"
+)
+
+var (
+	admission_policies_long = `
+		Generate admission policies outputs ValidatingAdmissionPolicies,
+		and bindings for them, that validate FederatedTypeConfigs,
+		KubeFedClusters and KubeFedConfigs with CEL expressions
+		evaluated by the API server of the host cluster.
+
+		The policies can be installed instead of the KubeFed admission
+		webhook where admission webhooks are not permitted. They are
+		generated from the validation of the webhook and require a
+		host cluster that serves ValidatingAdmissionPolicies.`
+
+	admission_policies_example = `
+		# Install the admission policies in the host cluster
+		kubefedctl generate-admission-policies | kubectl apply -f -
+
+		# Generate policies that only warn of invalid resources
+		kubefedctl generate-admission-policies --validation-actions=Warn`
+
+	supportedAPIVersions      = []string{"v1", "v1beta1"}
+	supportedValidationAction = []string{"Deny", "Warn", "Audit"}
+)
+
+type admissionPolicyOptions struct {
+	apiVersion        string
+	validationActions []string
+}
+
+// Bind adds the generate-admission-policies specific arguments to the
+// flagset passed in as an argument.
+func (o *admissionPolicyOptions) Bind(flags *pflag.FlagSet) {
+	flags.StringVar(&o.apiVersion, "api-version", "v1",
+		"The version of the admissionregistration.k8s.io API of the generated policies, one of v1 or v1beta1.")
+	flags.StringSliceVar(&o.validationActions, "validation-actions", []string{"Deny"},
+		"The actions taken for resources that fail validation, one or more of Deny, Warn or Audit.")
+}
+
+// Complete ensures that options are valid.
+func (o *admissionPolicyOptions) Complete(args []string) error {
+	if len(args) != 0 {
+		return errors.New("No arguments are expected")
+	}
+	if !contains(supportedAPIVersions, o.apiVersion) {
+		return errors.Errorf("Unsupported api version %q", o.apiVersion)
+	}
+	if len(o.validationActions) == 0 {
+		return errors.New("At least one validation action is required")
+	}
+	for _, action := range o.validationActions {
+		if !contains(supportedValidationAction, action) {
+			return errors.Errorf("Unsupported validation action %q", action)
+		}
+	}
+	return nil
+}
+
+// NewCmdAdmissionPolicies defines the `generate-admission-policies`
+// command.
+func NewCmdAdmissionPolicies(cmdOut io.Writer) *cobra.Command {
+	opts := &admissionPolicyOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "generate-admission-policies",
+		Short:   "Generate admission policies that validate KubeFed resources without a webhook",
+		Long:    admission_policies_long,
+		Example: admission_policies_example,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := opts.Complete(args)
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+
+			err = opts.Run(cmdOut)
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+		},
+	}
+
+	opts.Bind(cmd.Flags())
+
+	return cmd
+}
+
+// Run is the implementation of the `generate-admission-policies`
+// command.
+func (o *admissionPolicyOptions) Run(cmdOut io.Writer) error {
+	for _, obj := range PolicyObjects(o.apiVersion, o.validationActions) {
+		_, err := io.WriteString(cmdOut, "---\n")
+		if err != nil {
+			return errors.Wrap(err, "Error writing admission policies")
+		}
+		err = util.WriteUnstructuredToYaml(obj, cmdOut)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PolicyObjects returns the ValidatingAdmissionPolicies implementing
+// the validation of KubeFed resources, each followed by a binding
+// that applies the given validation actions.
+func PolicyObjects(apiVersion string, validationActions []string) []*unstructured.Unstructured {
+	groupVersion := fmt.Sprintf("%s/%s", admissionRegistrationGroup, apiVersion)
+	actions := []interface{}{}
+	for _, action := range validationActions {
+		actions = append(actions, action)
+	}
+
+	objs := []*unstructured.Unstructured{}
+	for _, policy := range validation.AdmissionPolicies() {
+		name := fmt.Sprintf("%s.%s", policy.Name, fedv1b1.SchemeGroupVersion.Group)
+
+		validations := []interface{}{}
+		for _, rule := range policy.Rules {
+			reason := rule.Reason
+			if len(reason) == 0 {
+				reason = "Invalid"
+			}
+			validations = append(validations, map[string]interface{}{
+				"expression": rule.Expression,
+				"message":    rule.Message,
+				"fieldPath":  rule.FieldPath,
+				"reason":     reason,
+			})
+		}
+		objs = append(objs, &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": groupVersion,
+			"kind":       "ValidatingAdmissionPolicy",
+			"metadata": map[string]interface{}{
+				"name": name,
+			},
+			"spec": map[string]interface{}{
+				"failurePolicy": "Fail",
+				"matchConstraints": map[string]interface{}{
+					"resourceRules": []interface{}{
+						map[string]interface{}{
+							"apiGroups":   []interface{}{fedv1b1.SchemeGroupVersion.Group},
+							"apiVersions": []interface{}{fedv1b1.SchemeGroupVersion.Version},
+							"operations":  []interface{}{"CREATE", "UPDATE"},
+							"resources":   []interface{}{policy.Resource},
+						},
+					},
+				},
+				"validations": validations,
+			},
+		}})
+		objs = append(objs, &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": groupVersion,
+			"kind":       "ValidatingAdmissionPolicyBinding",
+			"metadata": map[string]interface{}{
+				"name": name,
+			},
+			"spec": map[string]interface{}{
+				"policyName":        name,
+				"validationActions": actions,
+			},
+		}})
+	}
+	return objs
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,348 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adoption
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/kubefed/pkg/apis/core/typeconfig"
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	"sigs.k8s.io/kubefed/pkg/controller/sync/status"
+	ctlutil "sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/enable"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/options"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/util"
+)
+
+const (
+	tableOutput = "table"
+	yamlOutput  = "yaml"
+	jsonOutput  = "json"
+)
+
+var (
+	adoption_long = `
+		Adoption-report summarizes for each member cluster how many of
+		the resources that federated resources place in the cluster
+		are managed by KubeFed, and how many already existed in the
+		cluster and would be adopted. The report is computed from the
+		propagation status recorded by the sync controller, which
+		reports pre-existing resources as WouldAdopt without modifying
+		them while spec.syncController.adoptResources of the
+		KubeFedConfig is ReportOnly. Once adoption is enabled and the
+		controller manager restarted, the adoptable resources are
+		adopted in bulk.
+
+		The coverage of a cluster is the share of the resources placed
+		in the cluster that are managed or would be adopted. The
+		remainder are in conflict with another federated resource or
+		failed to propagate, and are worth resolving before adoption
+		is enabled.
+
+		The federated resources of all enabled types are reported
+		unless one or more TYPEs are given, where TYPE is the
+		federated type (e.g. federateddeployment) or its target type
+		(e.g. deployment). With --output=yaml|json, the report lists
+		the adoptable and conflicting resources of each cluster.
+
+		Current context is assumed to be a Kubernetes cluster hosting
+		the kubefed control plane. Please use the --host-cluster-context
+		flag otherwise.`
+
+	adoption_example = `
+		# Report the adoption coverage of all member clusters
+		kubefedctl adoption-report --host-cluster-context=cluster1
+
+		# List the deployments and configmaps of namespace my-ns that would be adopted, as yaml
+		kubefedctl adoption-report deployment configmap -n my-ns -o yaml --host-cluster-context=cluster1`
+)
+
+type adoptionOptions struct {
+	options.GlobalSubcommandOptions
+	typeNames []string
+	namespace string
+	output    string
+}
+
+// Bind adds the adoption-report specific arguments to the flagset
+// passed in as an argument.
+func (o *adoptionOptions) Bind(flags *pflag.FlagSet) {
+	flags.StringVarP(&o.namespace, "namespace", "n", "", "The namespace of the federated resources. Defaults to all namespaces.")
+	flags.StringVarP(&o.output, "output", "o", tableOutput, "The output format. One of: table|yaml|json.")
+}
+
+// Complete ensures that options are valid.
+func (o *adoptionOptions) Complete(args []string) error {
+	o.typeNames = args
+	if !sets.NewString(tableOutput, yamlOutput, jsonOutput).Has(o.output) {
+		return errors.Errorf("Invalid output format %q. Must be one of: table|yaml|json", o.output)
+	}
+	return nil
+}
+
+// NewCmdAdoptionReport defines the `adoption-report` command that
+// reports the coverage of the resources in member clusters that are
+// managed or would be adopted by KubeFed.
+func NewCmdAdoptionReport(cmdOut io.Writer, config util.FedConfig) *cobra.Command {
+	opts := &adoptionOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "adoption-report [TYPE...]",
+		Short:   "Report the resources of member clusters that are managed or would be adopted by KubeFed",
+		Long:    adoption_long,
+		Example: adoption_example,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := opts.Complete(args)
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+
+			err = opts.Run(cmdOut, config)
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+		},
+	}
+
+	flags := cmd.Flags()
+	opts.GlobalSubcommandBind(flags)
+	opts.Bind(flags)
+
+	return cmd
+}
+
+// report is the adoption report of the member clusters.
+type report struct {
+	// The resource adoption mode applied by the controller manager.
+	AdoptResources fedv1b1.ResourceAdoption `json:"adoptResources"`
+	Clusters       []*clusterReport         `json:"clusters"`
+}
+
+// clusterReport counts the resources placed in a member cluster by
+// their adoption state.
+type clusterReport struct {
+	Cluster string `json:"cluster"`
+	// Resources propagated or adopted by KubeFed.
+	Managed int `json:"managed"`
+	// Pre-existing resources that would be adopted.
+	Adoptable int `json:"adoptable"`
+	// Resources managed by another federated resource.
+	Conflicts int `json:"conflicts"`
+	// Resources that failed to propagate or were not propagated for
+	// another reason.
+	Other int `json:"other"`
+	// The percentage of resources that are managed or adoptable.
+	Coverage int `json:"coverage"`
+	// The adoptable and conflicting resources.
+	Resources []resourceEntry `json:"resources,omitempty"`
+}
+
+// resourceEntry identifies a federated resource and its propagation
+// status in a member cluster.
+type resourceEntry struct {
+	Kind      string                   `json:"kind"`
+	Namespace string                   `json:"namespace,omitempty"`
+	Name      string                   `json:"name"`
+	Status    status.PropagationStatus `json:"status"`
+}
+
+// Run is the implementation of the `adoption-report` command.
+func (o *adoptionOptions) Run(cmdOut io.Writer, config util.FedConfig) error {
+	hostConfig, err := config.HostConfig(o.HostClusterContext, o.Kubeconfig)
+	if err != nil {
+		return errors.Wrap(err, "Failed to get host cluster config")
+	}
+	client, err := genericclient.New(hostConfig)
+	if err != nil {
+		return errors.Wrap(err, "Failed to get kubefed clientset")
+	}
+
+	spec, applied, err := options.GetEffectiveKubeFedConfigSpec(hostConfig, o.KubeFedNamespace)
+	if err != nil {
+		return err
+	}
+	if !applied {
+		klog.Warning("The controller manager has not reported the KubeFedConfig it applied; reporting the adoption mode of its spec")
+	}
+	r := &report{AdoptResources: spec.SyncController.AdoptResources}
+	if len(r.AdoptResources) == 0 {
+		r.AdoptResources = fedv1b1.AdoptResourcesEnabled
+	}
+
+	clusterList := &fedv1b1.KubeFedClusterList{}
+	err = client.List(context.TODO(), clusterList, o.KubeFedNamespace)
+	if err != nil {
+		return errors.Wrap(err, "Failed to list member clusters")
+	}
+	clusterReports := make(map[string]*clusterReport)
+	for _, cluster := range clusterList.Items {
+		clusterReports[cluster.Name] = &clusterReport{Cluster: cluster.Name}
+	}
+
+	typeConfigs, err := o.typeConfigs(hostConfig, client)
+	if err != nil {
+		return err
+	}
+	for _, typeConfig := range typeConfigs {
+		err := o.addResources(hostConfig, typeConfig, clusterReports)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, name := range sets.StringKeySet(clusterReports).List() {
+		clusterReport := clusterReports[name]
+		clusterReport.Coverage = coverage(clusterReport)
+		r.Clusters = append(r.Clusters, clusterReport)
+	}
+
+	if o.output == tableOutput {
+		return writeTable(cmdOut, r)
+	}
+	var data []byte
+	if o.output == jsonOutput {
+		data, err = json.MarshalIndent(r, "", "    ")
+		data = append(data, '\n')
+	} else {
+		data, err = yaml.Marshal(r)
+	}
+	if err != nil {
+		return errors.Wrap(err, "Failed to marshal adoption report")
+	}
+	_, err = cmdOut.Write(data)
+	return err
+}
+
+// typeConfigs returns the type configs of the named types, or of all
+// types whose propagation is enabled if no types were named.
+func (o *adoptionOptions) typeConfigs(hostConfig *rest.Config, client genericclient.Client) ([]typeconfig.Interface, error) {
+	typeConfigs := []typeconfig.Interface{}
+	if len(o.typeNames) > 0 {
+		for _, typeName := range o.typeNames {
+			typeConfig, err := enable.LookupTypeConfig(hostConfig, client, typeName, o.KubeFedNamespace)
+			if err != nil {
+				return nil, err
+			}
+			typeConfigs = append(typeConfigs, typeConfig)
+		}
+		return typeConfigs, nil
+	}
+
+	typeConfigList := &fedv1b1.FederatedTypeConfigList{}
+	err := client.List(context.TODO(), typeConfigList, o.KubeFedNamespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list FederatedTypeConfigs")
+	}
+	for i := range typeConfigList.Items {
+		typeConfig := &typeConfigList.Items[i]
+		if typeConfig.GetPropagationEnabled() {
+			typeConfigs = append(typeConfigs, typeConfig)
+		}
+	}
+	sort.Slice(typeConfigs, func(i, j int) bool {
+		return typeConfigs[i].GetObjectMeta().Name < typeConfigs[j].GetObjectMeta().Name
+	})
+	return typeConfigs, nil
+}
+
+// addResources counts the federated resources of the given type in
+// the reports of the clusters they are placed in.
+func (o *adoptionOptions) addResources(hostConfig *rest.Config, typeConfig typeconfig.Interface, clusterReports map[string]*clusterReport) error {
+	federatedType := typeConfig.GetFederatedType()
+	fedClient, err := ctlutil.NewResourceClient(hostConfig, &federatedType)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to create a client for %s", federatedType.Kind)
+	}
+	namespace := o.namespace
+	if !federatedType.Namespaced {
+		namespace = ""
+	}
+	fedList, err := fedClient.Resources(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "Failed to list %s resources", federatedType.Kind)
+	}
+	for i := range fedList.Items {
+		fedObj := &fedList.Items[i]
+		statusMap, err := status.GetPropagationStatusMap(fedObj)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to read the status of %s %q", federatedType.Kind, ctlutil.NewQualifiedName(fedObj))
+		}
+		for clusterName, propStatus := range statusMap {
+			clusterReport, ok := clusterReports[clusterName]
+			if !ok {
+				continue
+			}
+			entry := resourceEntry{
+				Kind:      federatedType.Kind,
+				Namespace: fedObj.GetNamespace(),
+				Name:      fedObj.GetName(),
+				Status:    propStatus,
+			}
+			switch propStatus {
+			case status.ClusterPropagationOK:
+				clusterReport.Managed++
+			case status.WouldAdopt, status.AlreadyExists:
+				clusterReport.Adoptable++
+				clusterReport.Resources = append(clusterReport.Resources, entry)
+			case status.ClusterOwnershipConflict:
+				clusterReport.Conflicts++
+				clusterReport.Resources = append(clusterReport.Resources, entry)
+			case status.WaitingForRemoval, status.RemovalPrevented, status.ClusterNotAllowed:
+				// The resource is not placed in the cluster.
+			default:
+				clusterReport.Other++
+			}
+		}
+	}
+	return nil
+}
+
+// coverage returns the percentage of the resources placed in the
+// cluster that are managed or adoptable.
+func coverage(r *clusterReport) int {
+	total := r.Managed + r.Adoptable + r.Conflicts + r.Other
+	if total == 0 {
+		return 100
+	}
+	return (r.Managed + r.Adoptable) * 100 / total
+}
+
+func writeTable(w io.Writer, r *report) error {
+	fmt.Fprintf(w, "Resource adoption: %s\n\n", r.AdoptResources)
+	tw := tabwriter.NewWriter(w, 0, 8, 3, ' ', 0)
+	fmt.Fprintln(tw, "CLUSTER\tMANAGED\tADOPTABLE\tCONFLICTS\tOTHER\tCOVERAGE")
+	for _, c := range r.Clusters {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\t%d%%\n", c.Cluster, c.Managed, c.Adoptable, c.Conflicts, c.Other, c.Coverage)
+	}
+	return tw.Flush()
+}
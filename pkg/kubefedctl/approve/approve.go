@@ -0,0 +1,166 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package approve
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"k8s.io/klog"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	ctlutil "sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/options"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/util"
+)
+
+var (
+	approve_long = `
+		Approve allows the use of resources registered with the
+		kubefed control plane that require the approval of an
+		administrator, such as member clusters joined while the
+		ClusterJoinApproval feature is enabled.
+
+		Current context is assumed to be a Kubernetes cluster hosting
+		the kubefed control plane. Please use the --host-cluster-context
+		flag otherwise.`
+
+	approve_cluster_long = `
+		Approve cluster sets the Approved condition of the named
+		KubeFedClusters so that they are used for placement and
+		propagation. A cluster that is rejected, or whose approval is
+		revoked, is no longer used but is not removed from the
+		federation.
+
+		Approval requires permission to update the status of
+		KubeFedClusters, which can be granted separately from the
+		permission to register them with kubefedctl join.`
+
+	approve_cluster_example = `
+		# Approve the use of cluster1 and cluster2
+		kubefedctl approve cluster cluster1 cluster2
+
+		# Reject or revoke the approval of cluster3
+		kubefedctl approve cluster cluster3 --reject --message="Not compliant with the network policy baseline"`
+)
+
+// NewCmdApprove defines the `approve` command and its subcommands.
+func NewCmdApprove(cmdOut io.Writer, config util.FedConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "approve SUBCOMMAND",
+		Short: "Approve the use of resources registered with the kubefed control plane",
+		Long:  approve_long,
+		Run: func(cmd *cobra.Command, args []string) {
+			_ = cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(newCmdApproveCluster(cmdOut, config))
+
+	return cmd
+}
+
+type approveClusterOptions struct {
+	options.GlobalSubcommandOptions
+	clusterNames []string
+	reject       bool
+	message      string
+}
+
+// Bind adds the approve cluster specific arguments to the flagset
+// passed in as an argument.
+func (o *approveClusterOptions) Bind(flags *pflag.FlagSet) {
+	o.GlobalSubcommandBind(flags)
+	flags.BoolVar(&o.reject, "reject", false, "Reject the clusters, or revoke their approval, rather than approving them.")
+	flags.StringVar(&o.message, "message", "", "A message recorded with the approval, e.g. the reason for rejecting the clusters.")
+}
+
+// Complete ensures that options are valid.
+func (o *approveClusterOptions) Complete(args []string) error {
+	if len(args) == 0 {
+		return errors.New("At least one CLUSTER_NAME is required")
+	}
+	o.clusterNames = args
+	return nil
+}
+
+func newCmdApproveCluster(cmdOut io.Writer, config util.FedConfig) *cobra.Command {
+	opts := &approveClusterOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "cluster CLUSTER_NAME...",
+		Short:   "Approve the use of member clusters",
+		Long:    approve_cluster_long,
+		Example: approve_cluster_example,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := opts.Complete(args)
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+
+			err = opts.Run(cmdOut, config)
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+		},
+	}
+
+	opts.Bind(cmd.Flags())
+
+	return cmd
+}
+
+// Run is the implementation of the `approve cluster` command.
+func (o *approveClusterOptions) Run(cmdOut io.Writer, config util.FedConfig) error {
+	hostConfig, err := config.HostConfig(o.HostClusterContext, o.Kubeconfig)
+	if err != nil {
+		return errors.Wrap(err, "Failed to get host cluster config")
+	}
+	client, err := genericclient.New(hostConfig)
+	if err != nil {
+		return errors.Wrap(err, "Failed to get kubefed clientset")
+	}
+
+	reason, verb := "ApprovedByAdministrator", "approved"
+	if o.reject {
+		reason, verb = "RejectedByAdministrator", "rejected"
+	}
+	for _, clusterName := range o.clusterNames {
+		cluster := &fedv1b1.KubeFedCluster{}
+		err := client.Get(context.TODO(), cluster, o.KubeFedNamespace, clusterName)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to retrieve KubeFedCluster %q", clusterName)
+		}
+		ctlutil.SetClusterApproval(&cluster.Status, !o.reject, reason, o.message)
+		if o.DryRun {
+			fmt.Fprintf(cmdOut, "KubeFedCluster %q %s (dry run)\n", clusterName, verb)
+			continue
+		}
+		err = client.UpdateStatus(context.TODO(), cluster)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to update the status of KubeFedCluster %q", clusterName)
+		}
+		fmt.Fprintf(cmdOut, "KubeFedCluster %q %s\n", clusterName, verb)
+	}
+	return nil
+}
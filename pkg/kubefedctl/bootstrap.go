@@ -0,0 +1,552 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubefedctl
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"math/big"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apiextv1b1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeclient "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog"
+
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	ctlutil "sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/options"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/util"
+)
+
+const (
+	// The name of the Role and RoleBinding granting the bootstrap
+	// token group the permissions required to register a cluster.
+	bootstrapRoleName = "kubefed-cluster-bootstrap"
+
+	bootstrapTokenSecretPrefix = "bootstrap-token-"
+	bootstrapTokenChars        = "0123456789abcdefghijklmnopqrstuvwxyz"
+	defaultBootstrapTokenTTL   = time.Hour
+)
+
+var (
+	bootstrap_long = `
+		Bootstrap allows member clusters to register themselves with
+		a KubeFed control plane using a short-lived bootstrap token,
+		rather than requiring the credentials of both clusters to run
+		kubefedctl join.`
+
+	bootstrap_create_token_long = `
+		Create token creates a bootstrap token in the host cluster
+		that allows a member cluster to register itself with
+		kubefedctl bootstrap register until the token expires.
+
+		The token can only be used to create KubeFedClusters and
+		their secrets, and the admission webhook only admits a
+		KubeFedCluster that references the secret created by its
+		registration. The token cannot be used to read, update or
+		remove registered clusters. The API server of the host
+		cluster must be run with --enable-bootstrap-token-auth.
+
+		Current context is assumed to be a Kubernetes cluster hosting
+		the kubefed control plane. Please use the --host-cluster-context
+		flag otherwise.`
+
+	bootstrap_create_token_example = `
+		# Create a token that expires in 30 minutes
+		kubefedctl bootstrap create-token --ttl=30m`
+
+	bootstrap_register_long = `
+		Register registers the member cluster it is run against with
+		a KubeFed control plane, authenticating to the host cluster
+		with a bootstrap token. It is intended to be run by a job in
+		the member cluster, whose service account must be allowed to
+		create the service account and RBAC resources created by
+		kubefedctl join. The in-cluster configuration is used unless
+		a kubeconfig or cluster context is given.`
+
+	bootstrap_register_example = `
+		# Register the cluster as "cluster2", to be accessed by the
+		# control plane at https://cluster2.example.com:6443
+		kubefedctl bootstrap register cluster2 --host-cluster-name=cluster1 \
+		    --host-api-endpoint=https://cluster1.example.com:6443 --host-ca-file=/etc/kubefed/host-ca.crt \
+		    --token=abcdef.0123456789abcdef --cluster-api-endpoint=https://cluster2.example.com:6443`
+
+	bootstrapTokenRegexp = regexp.MustCompile(`^([a-z0-9]{6})\.([a-z0-9]{16})$`)
+
+	// Policy rules allowing a cluster to be registered but not an
+	// existing cluster to be read or modified.
+	bootstrapPolicyRules = []rbacv1.PolicyRule{
+		{
+			Verbs:     []string{"create"},
+			APIGroups: []string{"core.kubefed.k8s.io"},
+			Resources: []string{"kubefedclusters"},
+		},
+		{
+			Verbs:     []string{"get"},
+			APIGroups: []string{"core.kubefed.k8s.io"},
+			Resources: []string{"kubefedconfigs"},
+		},
+		{
+			Verbs:     []string{"create"},
+			APIGroups: []string{""},
+			Resources: []string{"secrets"},
+		},
+	}
+)
+
+// NewCmdBootstrap defines the `bootstrap` command and its subcommands.
+func NewCmdBootstrap(cmdOut io.Writer, config util.FedConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bootstrap SUBCOMMAND",
+		Short: "Register member clusters with bootstrap tokens",
+		Long:  bootstrap_long,
+		Run: func(cmd *cobra.Command, args []string) {
+			_ = cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(newCmdBootstrapCreateToken(cmdOut, config))
+	cmd.AddCommand(newCmdBootstrapRegister(cmdOut, config))
+
+	return cmd
+}
+
+type createTokenOptions struct {
+	options.GlobalSubcommandOptions
+	ttl             time.Duration
+	hostClusterName string
+}
+
+// Bind adds the create token specific arguments to the flagset passed
+// in as an argument.
+func (o *createTokenOptions) Bind(flags *pflag.FlagSet) {
+	o.GlobalSubcommandBind(flags)
+	flags.DurationVar(&o.ttl, "ttl", defaultBootstrapTokenTTL, "The duration after which the token expires.")
+	flags.StringVar(&o.hostClusterName, "host-cluster-name", "",
+		"The name of the host cluster included in the register command that is output. Defaults to the host cluster context.")
+}
+
+// Complete ensures that options are valid.
+func (o *createTokenOptions) Complete(args []string) error {
+	if len(args) != 0 {
+		return errors.New("create-token does not accept arguments")
+	}
+	if o.ttl <= 0 {
+		return errors.New("--ttl must be positive")
+	}
+	if o.hostClusterName == "" {
+		o.hostClusterName = o.HostClusterContext
+	}
+	return nil
+}
+
+func newCmdBootstrapCreateToken(cmdOut io.Writer, config util.FedConfig) *cobra.Command {
+	opts := &createTokenOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "create-token",
+		Short:   "Create a bootstrap token for registering a member cluster",
+		Long:    bootstrap_create_token_long,
+		Example: bootstrap_create_token_example,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := opts.Complete(args)
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+
+			err = opts.Run(cmdOut, config)
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+		},
+	}
+
+	opts.Bind(cmd.Flags())
+
+	return cmd
+}
+
+// Run is the implementation of the `bootstrap create-token` command.
+func (o *createTokenOptions) Run(cmdOut io.Writer, config util.FedConfig) error {
+	hostConfig, err := config.HostConfig(o.HostClusterContext, o.Kubeconfig)
+	if err != nil {
+		return errors.Wrap(err, "Failed to get host cluster config")
+	}
+	hostClientset, err := util.HostClientset(hostConfig)
+	if err != nil {
+		return errors.Wrap(err, "Failed to get host cluster clientset")
+	}
+
+	tokenID, err := randomTokenString(6)
+	if err != nil {
+		return err
+	}
+	tokenSecret, err := randomTokenString(16)
+	if err != nil {
+		return err
+	}
+	expiration := time.Now().Add(o.ttl).UTC()
+	secret := bootstrapTokenSecret(tokenID, tokenSecret, expiration)
+
+	if !o.DryRun {
+		err = ensureBootstrapRBAC(hostClientset, o.KubeFedNamespace)
+		if err != nil {
+			return err
+		}
+		_, err = hostClientset.CoreV1().Secrets(secret.Namespace).Create(secret)
+		if err != nil {
+			return errors.Wrap(err, "Failed to create the bootstrap token secret")
+		}
+	}
+
+	hostClusterName := o.hostClusterName
+	if hostClusterName == "" {
+		hostClusterName = "HOST_CLUSTER_NAME"
+	}
+	token := fmt.Sprintf("%s.%s", tokenID, tokenSecret)
+	fmt.Fprintf(cmdOut, "Bootstrap token %s created. It expires at %s.\n\n", token, expiration.Format(time.RFC3339))
+	fmt.Fprintf(cmdOut, "Register a member cluster by running the following against it:\n\n")
+	fmt.Fprintf(cmdOut, "  kubefedctl bootstrap register CLUSTER_NAME --cluster-api-endpoint=CLUSTER_API_ENDPOINT \\\n")
+	fmt.Fprintf(cmdOut, "    --host-api-endpoint=%s --host-ca-file=HOST_CA_FILE \\\n", hostConfig.Host)
+	fmt.Fprintf(cmdOut, "    --host-cluster-name=%s --kubefed-namespace=%s --token=%s\n", hostClusterName, o.KubeFedNamespace, token)
+	return nil
+}
+
+// randomTokenString returns a random string of the given length made
+// of the characters allowed in a bootstrap token.
+func randomTokenString(length int) (string, error) {
+	max := big.NewInt(int64(len(bootstrapTokenChars)))
+	token := make([]byte, length)
+	for i := range token {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", errors.Wrap(err, "Failed to generate a bootstrap token")
+		}
+		token[i] = bootstrapTokenChars[n.Int64()]
+	}
+	return string(token), nil
+}
+
+// bootstrapTokenSecret returns the secret of a bootstrap token that
+// authenticates as a member of the registrant group until it expires.
+func bootstrapTokenSecret(tokenID, tokenSecret string, expiration time.Time) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      bootstrapTokenSecretPrefix + tokenID,
+			Namespace: metav1.NamespaceSystem,
+		},
+		Type: corev1.SecretTypeBootstrapToken,
+		StringData: map[string]string{
+			"description":                    "Registration of a member cluster with KubeFed",
+			"token-id":                       tokenID,
+			"token-secret":                   tokenSecret,
+			"expiration":                     expiration.Format(time.RFC3339),
+			"usage-bootstrap-authentication": "true",
+			"auth-extra-groups":              ctlutil.BootstrapRegistrantGroup,
+		},
+	}
+}
+
+// ensureBootstrapRBAC ensures that the registrant group of bootstrap
+// tokens is allowed to register clusters in the kubefed namespace.
+func ensureBootstrapRBAC(clientset kubeclient.Interface, namespace string) error {
+	role := &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      bootstrapRoleName,
+			Namespace: namespace,
+		},
+		Rules: bootstrapPolicyRules,
+	}
+	existingRole, err := clientset.RbacV1().Roles(namespace).Get(role.Name, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		_, err = clientset.RbacV1().Roles(namespace).Create(role)
+	case err == nil:
+		existingRole.Rules = role.Rules
+		_, err = clientset.RbacV1().Roles(namespace).Update(existingRole)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "Failed to ensure role %q", role.Name)
+	}
+
+	binding := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      bootstrapRoleName,
+			Namespace: namespace,
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:     rbacv1.GroupKind,
+				APIGroup: rbacv1.GroupName,
+				Name:     ctlutil.BootstrapRegistrantGroup,
+			},
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     bootstrapRoleName,
+		},
+	}
+	existingBinding, err := clientset.RbacV1().RoleBindings(namespace).Get(binding.Name, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		_, err = clientset.RbacV1().RoleBindings(namespace).Create(binding)
+	case err == nil:
+		// The roleRef of the binding is fixed, so only its subjects
+		// can differ.
+		existingBinding.Subjects = binding.Subjects
+		_, err = clientset.RbacV1().RoleBindings(namespace).Update(existingBinding)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "Failed to ensure role binding %q", binding.Name)
+	}
+	return nil
+}
+
+type registerOptions struct {
+	options.GlobalSubcommandOptions
+	options.CommonJoinOptions
+	hostAPIEndpoint     string
+	hostCAFile          string
+	token               string
+	clusterAPIEndpoint  string
+	skipPreflightChecks bool
+}
+
+// Bind adds the register specific arguments to the flagset passed in
+// as an argument.
+func (o *registerOptions) Bind(flags *pflag.FlagSet) {
+	flags.StringVar(&o.Kubeconfig, "kubeconfig", "", "Path to the kubeconfig file of the member cluster. The in-cluster configuration is used if unspecified.")
+	flags.StringVar(&o.ClusterContext, "cluster-context", "", "Name of the member cluster's context in the kubeconfig.")
+	flags.StringVar(&o.KubeFedNamespace, "kubefed-namespace", ctlutil.DefaultKubeFedSystemNamespace,
+		"Namespace in the host cluster where the KubeFed system components are installed.")
+	flags.BoolVar(&o.DryRun, "dry-run", false,
+		"Run the command in dry-run mode, without making any server requests.")
+	flags.StringVar(&o.HostClusterName, "host-cluster-name", "",
+		"The name of the host cluster, used in the names of the resources created in the member cluster.")
+	flags.StringVar(&o.hostAPIEndpoint, "host-api-endpoint", "", "The API endpoint of the host cluster.")
+	flags.StringVar(&o.hostCAFile, "host-ca-file", "", "Path to the CA bundle used to verify the API server of the host cluster.")
+	flags.StringVar(&o.token, "token", "", "The bootstrap token created with kubefedctl bootstrap create-token.")
+	flags.StringVar(&o.clusterAPIEndpoint, "cluster-api-endpoint", "",
+		"The API endpoint at which the control plane accesses the member cluster.")
+	flags.BoolVar(&o.skipPreflightChecks, "skip-preflight-checks", false,
+		"Whether to skip the checks of cluster versions and permissions performed before any resources are created.")
+}
+
+// Complete ensures that options are valid.
+func (o *registerOptions) Complete(args []string) error {
+	if len(args) > 1 {
+		return errors.New("register accepts a single cluster name")
+	}
+	// A single cluster is registered.
+	o.Concurrency = 1
+	err := o.SetName(args)
+	if err != nil {
+		return err
+	}
+	for flag, value := range map[string]string{
+		"host-cluster-name":    o.HostClusterName,
+		"host-api-endpoint":    o.hostAPIEndpoint,
+		"host-ca-file":         o.hostCAFile,
+		"token":                o.token,
+		"cluster-api-endpoint": o.clusterAPIEndpoint,
+	} {
+		if value == "" {
+			return errors.Errorf("--%s is required", flag)
+		}
+	}
+	if !bootstrapTokenRegexp.MatchString(o.token) {
+		return errors.New("--token must be a bootstrap token of the form [a-z0-9]{6}.[a-z0-9]{16}")
+	}
+	return nil
+}
+
+func newCmdBootstrapRegister(cmdOut io.Writer, config util.FedConfig) *cobra.Command {
+	opts := &registerOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "register CLUSTER_NAME --host-api-endpoint=ENDPOINT --token=TOKEN",
+		Short:   "Register the member cluster with a KubeFed control plane using a bootstrap token",
+		Long:    bootstrap_register_long,
+		Example: bootstrap_register_example,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := opts.Complete(args)
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+
+			err = opts.Run(cmdOut, config)
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+		},
+	}
+
+	opts.Bind(cmd.Flags())
+
+	return cmd
+}
+
+// Run is the implementation of the `bootstrap register` command.
+func (o *registerOptions) Run(cmdOut io.Writer, config util.FedConfig) error {
+	clusterConfig, err := config.ClusterConfig(o.ClusterContext, o.Kubeconfig)
+	if err != nil {
+		return errors.Wrap(err, "Failed to get member cluster config")
+	}
+
+	// The bootstrap token is validated by the API server of the host
+	// cluster, which authenticates it as a member of the registrant
+	// group.
+	hostConfig := &rest.Config{
+		Host:        o.hostAPIEndpoint,
+		BearerToken: o.token,
+		TLSClientConfig: rest.TLSClientConfig{
+			CAFile: o.hostCAFile,
+		},
+	}
+	scope, err := options.GetScopeFromKubeFedConfig(hostConfig, o.KubeFedNamespace)
+	if err != nil {
+		return err
+	}
+
+	// The token authenticates as the user named after its ID, which
+	// is recorded in the secret of the cluster.
+	registrant := ctlutil.BootstrapUserPrefix + strings.SplitN(o.token, ".", 2)[0]
+	err = registerCluster(hostConfig, clusterConfig, o.clusterAPIEndpoint, o.KubeFedNamespace,
+		o.HostClusterName, o.ClusterName, registrant, scope, o.DryRun, o.skipPreflightChecks)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(cmdOut, "Cluster %q registered with the KubeFed control plane at %s\n", o.ClusterName, o.hostAPIEndpoint)
+	return nil
+}
+
+// registerCluster registers a cluster with a KubeFed control plane on
+// behalf of the user of a bootstrap token.  The resources of the
+// member cluster are created as by join, and existing ones are updated
+// so that a failed registration can be retried.  Only the secret and
+// the KubeFedCluster are created in the host cluster, since the token
+// can neither read nor update existing clusters.
+func registerCluster(hostConfig, clusterConfig *rest.Config, apiEndpoint, kubefedNamespace,
+	hostClusterName, clusterName, registrant string, scope apiextv1b1.ResourceScope, dryRun, skipPreflightChecks bool) error {
+	hostClientset, err := util.HostClientset(hostConfig)
+	if err != nil {
+		klog.V(2).Infof("Failed to get host cluster clientset: %v", err)
+		return err
+	}
+
+	clusterClientset, err := util.ClusterClientset(clusterConfig)
+	if err != nil {
+		klog.V(2).Infof("Failed to get registering cluster clientset: %v", err)
+		return err
+	}
+
+	client, err := genericclient.New(hostConfig)
+	if err != nil {
+		klog.V(2).Infof("Failed to get kubefed clientset: %v", err)
+		return err
+	}
+
+	klog.V(2).Infof("Performing preflight checks.")
+	err = performPreflightChecks(hostClientset, clusterClientset, clusterName, hostClusterName,
+		kubefedNamespace, kubefedNamespace, scope, false, false, false, skipPreflightChecks)
+	if err != nil {
+		return err
+	}
+
+	_, err = createKubeFedNamespace(clusterClientset, kubefedNamespace, clusterName, dryRun)
+	if err != nil {
+		return err
+	}
+
+	saName, err := createServiceAccount(clusterClientset, kubefedNamespace, clusterName, hostClusterName, dryRun, false)
+	if err != nil {
+		return err
+	}
+	err = createMemberRoleBindings(clusterClientset, saName, kubefedNamespace, clusterName, scope, dryRun, false)
+	if err != nil {
+		return err
+	}
+	if scope == apiextv1b1.NamespaceScoped {
+		err = createClusterScopedRoleAndBinding(hostConfig, clusterClientset, saName, kubefedNamespace, clusterName, dryRun, false)
+		if err != nil {
+			return err
+		}
+	}
+
+	secret, caBundle, err := createRegistrationSecret(clusterConfig, clusterClientset, hostClientset,
+		saName, kubefedNamespace, clusterName, registrant, dryRun)
+	if err != nil {
+		return err
+	}
+
+	_, err = createKubeFedCluster(client, nil, clusterName, apiEndpoint,
+		secret.Name, kubefedNamespace, kubefedNamespace, caBundle, false, dryRun, true)
+	if apierrors.IsAlreadyExists(err) {
+		return errors.Errorf("Cluster %q is already registered and can only be joined again with kubefedctl join", clusterName)
+	}
+	return err
+}
+
+// createRegistrationSecret creates a secret in the KubeFed namespace
+// of the host cluster containing the token of the given service
+// account of the registering cluster.  The secret is annotated with
+// the cluster and the registrant so that the admission webhook admits
+// the KubeFedCluster of the same registration that references it.
+func createRegistrationSecret(clusterConfig *rest.Config, clusterClientset, hostClientset kubeclient.Interface,
+	saName, namespace, clusterName, registrant string, dryRun bool) (*corev1.Secret, []byte, error) {
+	if dryRun {
+		return &corev1.Secret{}, nil, nil
+	}
+
+	token, caBundle, err := serviceAccountToken(clusterConfig, clusterClientset, saName, namespace, clusterName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:    namespace,
+			GenerateName: clusterName + "-",
+			Annotations: map[string]string{
+				ctlutil.RegisteredClusterAnnotation: clusterName,
+				ctlutil.RegisteredByAnnotation:      registrant,
+			},
+		},
+		Data: map[string][]byte{
+			ctlutil.TokenKey: token,
+		},
+	}
+	secret, err = hostClientset.CoreV1().Secrets(namespace).Create(secret)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Failed to create the secret of the registering cluster")
+	}
+	klog.V(2).Infof("Created secret %s in host cluster", secret.Name)
+	return secret, caBundle, nil
+}
@@ -0,0 +1,230 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubefedctl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	ctlutil "sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+func TestCreateTokenOptionsComplete(t *testing.T) {
+	testCases := map[string]struct {
+		args            []string
+		ttl             time.Duration
+		hostClusterName string
+		expectedName    string
+		expectedErr     string
+	}{
+		"host cluster name defaults to the host cluster context": {
+			ttl:          time.Hour,
+			expectedName: "cluster1",
+		},
+		"host cluster name is retained": {
+			ttl:             time.Hour,
+			hostClusterName: "host",
+			expectedName:    "host",
+		},
+		"arguments are rejected": {
+			args:        []string{"cluster2"},
+			ttl:         time.Hour,
+			expectedErr: "create-token does not accept arguments",
+		},
+		"ttl must be positive": {
+			expectedErr: "--ttl must be positive",
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			o := &createTokenOptions{ttl: tc.ttl, hostClusterName: tc.hostClusterName}
+			o.HostClusterContext = "cluster1"
+			err := o.Complete(tc.args)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, tc.expectedName, o.hostClusterName)
+		})
+	}
+}
+
+func TestRegisterOptionsComplete(t *testing.T) {
+	newOptions := func() *registerOptions {
+		o := &registerOptions{
+			hostAPIEndpoint:    "https://cluster1.example.com:6443",
+			hostCAFile:         "/etc/kubefed/host-ca.crt",
+			token:              "abcdef.0123456789abcdef",
+			clusterAPIEndpoint: "https://cluster2.example.com:6443",
+		}
+		o.HostClusterName = "cluster1"
+		return o
+	}
+	testCases := map[string]struct {
+		args        []string
+		mutate      func(*registerOptions)
+		expectedErr string
+	}{
+		"valid": {
+			args: []string{"cluster2"},
+		},
+		"cluster name is required": {
+			expectedErr: "NAME is required",
+		},
+		"a single cluster is registered": {
+			args:        []string{"cluster2", "cluster3"},
+			expectedErr: "register accepts a single cluster name",
+		},
+		"host cluster name is required": {
+			args:        []string{"cluster2"},
+			mutate:      func(o *registerOptions) { o.HostClusterName = "" },
+			expectedErr: "--host-cluster-name is required",
+		},
+		"token is required": {
+			args:        []string{"cluster2"},
+			mutate:      func(o *registerOptions) { o.token = "" },
+			expectedErr: "--token is required",
+		},
+		"cluster API endpoint is required": {
+			args:        []string{"cluster2"},
+			mutate:      func(o *registerOptions) { o.clusterAPIEndpoint = "" },
+			expectedErr: "--cluster-api-endpoint is required",
+		},
+		"token must be a bootstrap token": {
+			args:        []string{"cluster2"},
+			mutate:      func(o *registerOptions) { o.token = "ABCDEF.0123456789abcdef" },
+			expectedErr: "--token must be a bootstrap token of the form [a-z0-9]{6}.[a-z0-9]{16}",
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			o := newOptions()
+			if tc.mutate != nil {
+				tc.mutate(o)
+			}
+			err := o.Complete(tc.args)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, "cluster2", o.ClusterName)
+		})
+	}
+}
+
+func TestBootstrapTokenSecret(t *testing.T) {
+	expiration := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+	secret := bootstrapTokenSecret("abcdef", "0123456789abcdef", expiration)
+
+	assert.Equal(t, "bootstrap-token-abcdef", secret.Name)
+	assert.Equal(t, metav1.NamespaceSystem, secret.Namespace)
+	assert.Equal(t, corev1.SecretTypeBootstrapToken, secret.Type)
+	assert.Equal(t, map[string]string{
+		"description":                    "Registration of a member cluster with KubeFed",
+		"token-id":                       "abcdef",
+		"token-secret":                   "0123456789abcdef",
+		"expiration":                     "2019-06-01T12:00:00Z",
+		"usage-bootstrap-authentication": "true",
+		"auth-extra-groups":              ctlutil.BootstrapRegistrantGroup,
+	}, secret.StringData)
+}
+
+func TestEnsureBootstrapRBAC(t *testing.T) {
+	namespace := "kube-federation-system"
+	clientset := fake.NewSimpleClientset()
+
+	assertRBAC := func() {
+		role, err := clientset.RbacV1().Roles(namespace).Get(bootstrapRoleName, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		assert.Equal(t, bootstrapPolicyRules, role.Rules)
+
+		binding, err := clientset.RbacV1().RoleBindings(namespace).Get(bootstrapRoleName, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		assert.Equal(t, []rbacv1.Subject{
+			{Kind: rbacv1.GroupKind, APIGroup: rbacv1.GroupName, Name: ctlutil.BootstrapRegistrantGroup},
+		}, binding.Subjects)
+		assert.Equal(t, rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: bootstrapRoleName}, binding.RoleRef)
+	}
+
+	if err := ensureBootstrapRBAC(clientset, namespace); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	assertRBAC()
+
+	// Existing resources are restored.
+	role, err := clientset.RbacV1().Roles(namespace).Get(bootstrapRoleName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	role.Rules = append(role.Rules, rbacv1.PolicyRule{
+		Verbs:     []string{"get", "update"},
+		APIGroups: []string{"core.kubefed.k8s.io"},
+		Resources: []string{"kubefedclusters"},
+	})
+	_, err = clientset.RbacV1().Roles(namespace).Update(role)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	binding, err := clientset.RbacV1().RoleBindings(namespace).Get(bootstrapRoleName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	binding.Subjects = append(binding.Subjects, rbacv1.Subject{Kind: rbacv1.GroupKind, APIGroup: rbacv1.GroupName, Name: "system:authenticated"})
+	_, err = clientset.RbacV1().RoleBindings(namespace).Update(binding)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := ensureBootstrapRBAC(clientset, namespace); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	assertRBAC()
+}
+
+func TestBootstrapPolicyRules(t *testing.T) {
+	// Clusters can be registered, but registered clusters and their
+	// secrets can be neither read nor modified.
+	for _, rule := range bootstrapPolicyRules {
+		for _, resource := range rule.Resources {
+			switch resource {
+			case "kubefedclusters", "secrets":
+				assert.Equal(t, []string{"create"}, rule.Verbs, "Verbs of %s", resource)
+			case "kubefedconfigs":
+				assert.Equal(t, []string{"get"}, rule.Verbs, "Verbs of %s", resource)
+			default:
+				t.Errorf("Unexpected resource %q", resource)
+			}
+		}
+	}
+}
@@ -0,0 +1,468 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checksum
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/kubefed/pkg/apis/core/typeconfig"
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	"sigs.k8s.io/kubefed/pkg/controller/sync"
+	"sigs.k8s.io/kubefed/pkg/controller/sync/dispatch"
+	ctlutil "sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/features"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/enable"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/options"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/util"
+)
+
+const (
+	allClusters = "all"
+
+	tableOutput = "table"
+	yamlOutput  = "yaml"
+	jsonOutput  = "json"
+)
+
+// The outcomes of verifying the resource in a member cluster.
+const (
+	// The resource was propagated from the current desired state
+	// and has not drifted from it.
+	verifiedStatus = "Verified"
+	// The resource was propagated from another desired state.
+	outdatedStatus = "Outdated"
+	// The resource was propagated from the current desired state but
+	// was modified since.
+	driftedStatus  = "Drifted"
+	notFoundStatus = "NotFound"
+	errorStatus    = "Error"
+)
+
+var (
+	checksum_long = `
+		Checksum computes the checksum of the resource rendered from a
+		federated resource for each member cluster, as the sync
+		controller renders it from the template, overrides and other
+		sources of the federated resource. The checksum is the
+		canonical hash of the rendered resource that the sync
+		controller records in the kubefed.k8s.io/desired-hash
+		annotation of the resources it propagates.
+
+		With --verify, the resource in each member cluster is
+		retrieved and verified to have been propagated from the
+		current desired state (its recorded checksum matches) and not
+		to have been modified since (the fields it is rendered with
+		have the rendered values).
+
+		The federated resource is identified as TYPE/NAME, where TYPE
+		is the federated type (e.g. federateddeployment) or its target
+		type (e.g. deployment). The checksums are computed for the
+		clusters the resource has been propagated to unless --cluster
+		is provided.
+
+		Current context is assumed to be a Kubernetes cluster hosting
+		the kubefed control plane. Please use the --host-cluster-context
+		flag otherwise.`
+
+	checksum_example = `
+		# Compute the checksums of federated deployment "foo" for the clusters it is propagated to
+		kubefedctl checksum federateddeployment/foo -n my-ns --host-cluster-context=cluster1
+
+		# Verify the deployment "foo" in cluster2 and cluster3 and print the result as json
+		kubefedctl checksum deployment/foo -n my-ns --cluster=cluster2,cluster3 --verify -o json --host-cluster-context=cluster1`
+)
+
+type checksumOptions struct {
+	options.GlobalSubcommandOptions
+	typeName     string
+	resourceName string
+	namespace    string
+	clusters     []string
+	verify       bool
+	output       string
+}
+
+// Bind adds the checksum specific arguments to the flagset passed in
+// as an argument.
+func (o *checksumOptions) Bind(flags *pflag.FlagSet) {
+	flags.StringVarP(&o.namespace, "namespace", "n", "default", "The namespace of the federated resource.")
+	flags.StringSliceVar(&o.clusters, "cluster", []string{allClusters},
+		"Comma separated names of the clusters to compute checksums for, or 'all' for the clusters the resource has been propagated to.")
+	flags.BoolVar(&o.verify, "verify", false, "Whether to verify the resources in member clusters against the checksums.")
+	flags.StringVarP(&o.output, "output", "o", tableOutput, "The output format. One of: table|yaml|json.")
+}
+
+// Complete ensures that options are valid.
+func (o *checksumOptions) Complete(args []string) error {
+	if len(args) != 1 {
+		return errors.New("TYPE/NAME is required")
+	}
+	parts := strings.SplitN(args[0], "/", 2)
+	if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+		return errors.Errorf("Invalid resource %q. The resource must be given as TYPE/NAME", args[0])
+	}
+	o.typeName, o.resourceName = parts[0], parts[1]
+	if len(o.clusters) == 0 {
+		return errors.New("--cluster must not be empty")
+	}
+	if len(o.clusters) > 1 && sets.NewString(o.clusters...).Has(allClusters) {
+		return errors.Errorf("--cluster=%s may not be combined with cluster names", allClusters)
+	}
+	if !sets.NewString(tableOutput, yamlOutput, jsonOutput).Has(o.output) {
+		return errors.Errorf("Invalid output format %q. Must be one of: table|yaml|json", o.output)
+	}
+	return nil
+}
+
+// NewCmdChecksum defines the `checksum` command that computes the
+// checksums of the resources rendered from a federated resource for
+// member clusters.
+func NewCmdChecksum(cmdOut io.Writer, config util.FedConfig) *cobra.Command {
+	opts := &checksumOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "checksum TYPE/NAME",
+		Short:   "Compute the checksums of the resources rendered from a federated resource for member clusters",
+		Long:    checksum_long,
+		Example: checksum_example,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := opts.Complete(args)
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+
+			err = opts.Run(cmdOut, config)
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+		},
+	}
+
+	flags := cmd.Flags()
+	opts.GlobalSubcommandBind(flags)
+	opts.Bind(flags)
+
+	return cmd
+}
+
+// clusterChecksum is the checksum computed for a member cluster and,
+// with --verify, the outcome of verifying the resource in the cluster.
+type clusterChecksum struct {
+	Cluster  string `json:"cluster"`
+	Checksum string `json:"checksum,omitempty"`
+	// The checksum recorded on the resource in the cluster.
+	RecordedChecksum string `json:"recordedChecksum,omitempty"`
+	Status           string `json:"status,omitempty"`
+	// The paths of the fields of the resource in the cluster that
+	// differ from the rendered resource.
+	DriftedPaths []string `json:"driftedPaths,omitempty"`
+	Error        string   `json:"error,omitempty"`
+}
+
+// Run is the implementation of the `checksum` command.
+func (o *checksumOptions) Run(cmdOut io.Writer, config util.FedConfig) error {
+	hostConfig, err := config.HostConfig(o.HostClusterContext, o.Kubeconfig)
+	if err != nil {
+		return errors.Wrap(err, "Failed to get host cluster config")
+	}
+	client, err := genericclient.New(hostConfig)
+	if err != nil {
+		return errors.Wrap(err, "Failed to get kubefed clientset")
+	}
+
+	typeConfig, err := enable.LookupTypeConfig(hostConfig, client, o.typeName, o.KubeFedNamespace)
+	if err != nil {
+		return err
+	}
+	targetType := typeConfig.GetTargetType()
+	targetIsNamespace := targetType.Kind == ctlutil.NamespaceKind
+	federatedType := typeConfig.GetFederatedType()
+	namespace := o.namespace
+	if targetIsNamespace {
+		namespace = o.resourceName
+	}
+	fedObj := &unstructured.Unstructured{}
+	fedObj.SetAPIVersion(fmt.Sprintf("%s/%s", federatedType.Group, federatedType.Version))
+	fedObj.SetKind(federatedType.Kind)
+	err = client.Get(context.TODO(), fedObj, namespace, o.resourceName)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to retrieve %s %q", federatedType.Kind,
+			ctlutil.QualifiedName{Namespace: namespace, Name: o.resourceName})
+	}
+
+	clusters, err := o.checksumClusters(client, fedObj)
+	if err != nil {
+		return err
+	}
+	renderConfig, err := o.renderConfig(hostConfig, client, typeConfig, clusters, fedObj)
+	if err != nil {
+		return err
+	}
+
+	results := []clusterChecksum{}
+	for _, cluster := range clusters {
+		result := clusterChecksum{Cluster: cluster.Name}
+		rendered, err := sync.RenderForCluster(renderConfig, fedObj, cluster.Name)
+		if err != nil {
+			result.Status, result.Error = errorStatus, err.Error()
+			results = append(results, result)
+			continue
+		}
+		result.Checksum = ctlutil.DesiredHash(rendered)
+		if o.verify {
+			o.verifyCluster(hostConfig, client, typeConfig, cluster, fedObj, rendered, &result)
+		}
+		results = append(results, result)
+	}
+
+	if o.output == tableOutput {
+		return writeTable(cmdOut, results, o.verify)
+	}
+	var data []byte
+	if o.output == jsonOutput {
+		data, err = json.MarshalIndent(results, "", "    ")
+		data = append(data, '\n')
+	} else {
+		data, err = yaml.Marshal(results)
+	}
+	if err != nil {
+		return errors.Wrap(err, "Failed to marshal checksums")
+	}
+	_, err = cmdOut.Write(data)
+	return err
+}
+
+// checksumClusters returns the clusters to compute checksums for.
+func (o *checksumOptions) checksumClusters(client genericclient.Client, fedObj *unstructured.Unstructured) ([]*fedv1b1.KubeFedCluster, error) {
+	clusterList := &fedv1b1.KubeFedClusterList{}
+	err := client.List(context.TODO(), clusterList, o.KubeFedNamespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list member clusters")
+	}
+	joined := make(map[string]*fedv1b1.KubeFedCluster)
+	for i := range clusterList.Items {
+		joined[clusterList.Items[i].Name] = &clusterList.Items[i]
+	}
+
+	names := o.clusters
+	if len(names) == 1 && names[0] == allClusters {
+		names, err = util.PropagatedClusterNames(fedObj)
+		if err != nil {
+			return nil, err
+		}
+	}
+	clusters := []*fedv1b1.KubeFedCluster{}
+	for _, name := range sets.NewString(names...).List() {
+		cluster, ok := joined[name]
+		if !ok {
+			return nil, errors.Errorf("Cluster %q is not joined", name)
+		}
+		clusters = append(clusters, cluster)
+	}
+	return clusters, nil
+}
+
+// renderConfig returns the configuration to render the federated
+// resource with, as sourced by the sync controller from the
+// KubeFedConfig, the clusters and the host namespace.
+func (o *checksumOptions) renderConfig(hostConfig *rest.Config, client genericclient.Client, typeConfig typeconfig.Interface,
+	clusters []*fedv1b1.KubeFedCluster, fedObj *unstructured.Unstructured) (*sync.RenderConfig, error) {
+
+	spec, applied, err := options.GetEffectiveKubeFedConfigSpec(hostConfig, o.KubeFedNamespace)
+	if err != nil {
+		return nil, err
+	}
+	if !applied {
+		klog.Warningf("The KubeFedConfig in namespace %q has not been applied by the controller manager, so the checksums may differ from those it records", o.KubeFedNamespace)
+	}
+	renderConfig := &sync.RenderConfig{
+		TypeConfig:          typeConfig,
+		Clusters:            clusters,
+		PropagationMetadata: spec.SyncController.PropagationMetadata,
+		NamespaceMetadata:   spec.SyncController.NamespaceMetadata,
+	}
+	for _, featureGate := range spec.FeatureGates {
+		if featureGate.Name == string(features.OverrideValueSources) && featureGate.Configuration == fedv1b1.ConfigurationEnabled {
+			renderConfig.ValueResolver = ctlutil.NewOverrideValueResolver(client, o.KubeFedNamespace)
+		}
+	}
+
+	if typeConfig.GetTargetType().Kind == ctlutil.NamespaceKind {
+		hostClientset, err := util.HostClientset(hostConfig)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to create host clientset")
+		}
+		namespace, err := hostClientset.CoreV1().Namespaces().Get(fedObj.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to retrieve namespace %q", fedObj.GetName())
+		}
+		renderConfig.HostNamespace = &unstructured.Unstructured{}
+		renderConfig.HostNamespace.SetName(namespace.Name)
+		renderConfig.HostNamespace.SetLabels(namespace.Labels)
+		renderConfig.HostNamespace.SetAnnotations(namespace.Annotations)
+	}
+
+	// The template is rendered in the version of the target type
+	// served by each cluster.
+	targetVersions := typeConfig.GetTargetVersions()
+	if len(targetVersions) > 1 {
+		renderConfig.TargetVersions = make(map[string]string)
+		for _, cluster := range clusters {
+			version, err := o.negotiateTargetVersion(hostConfig, client, typeConfig, cluster)
+			if err != nil {
+				return nil, err
+			}
+			renderConfig.TargetVersions[cluster.Name] = version
+		}
+	}
+	return renderConfig, nil
+}
+
+func (o *checksumOptions) negotiateTargetVersion(hostConfig *rest.Config, client genericclient.Client, typeConfig typeconfig.Interface,
+	cluster *fedv1b1.KubeFedCluster) (string, error) {
+
+	clusterConfig, err := o.clusterConfig(hostConfig, client, cluster)
+	if err != nil {
+		return "", err
+	}
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(clusterConfig)
+	if err != nil {
+		return "", errors.Wrapf(err, "Failed to create a discovery client for cluster %q", cluster.Name)
+	}
+	version, err := ctlutil.NegotiateTargetVersion(discoveryClient, typeConfig.GetTargetType(), typeConfig.GetTargetVersions())
+	if err != nil {
+		return "", errors.Wrapf(err, "Failed to negotiate the version of %q for cluster %q", typeConfig.GetTargetType().Name, cluster.Name)
+	}
+	return version, nil
+}
+
+func (o *checksumOptions) clusterConfig(hostConfig *rest.Config, client genericclient.Client, cluster *fedv1b1.KubeFedCluster) (*rest.Config, error) {
+	clusterConfig, err := ctlutil.BuildClusterConfig(cluster, hostConfig, client, o.KubeFedNamespace)
+	if err != nil {
+		return nil, err
+	}
+	if clusterConfig == nil {
+		return nil, errors.Errorf("Unable to load configuration for cluster %q", cluster.Name)
+	}
+	return clusterConfig, nil
+}
+
+// verifyCluster verifies the resource in the given cluster against the
+// resource rendered for the cluster.  As when the sync controller
+// compares them, the fields of the resource managed by the cluster are
+// retained in the rendered resource.
+func (o *checksumOptions) verifyCluster(hostConfig *rest.Config, client genericclient.Client, typeConfig typeconfig.Interface,
+	cluster *fedv1b1.KubeFedCluster, fedObj, rendered *unstructured.Unstructured, result *clusterChecksum) {
+
+	clusterObj, err := o.clusterObject(hostConfig, client, cluster, rendered)
+	if apierrors.IsNotFound(err) {
+		result.Status = notFoundStatus
+		return
+	}
+	if err != nil {
+		result.Status, result.Error = errorStatus, err.Error()
+		return
+	}
+	result.RecordedChecksum = ctlutil.DesiredHash(clusterObj)
+	if result.RecordedChecksum != result.Checksum {
+		result.Status = outdatedStatus
+		return
+	}
+
+	desired := rendered.DeepCopy()
+	err = dispatch.RetainClusterFields(typeConfig.GetTargetType().Kind, desired, clusterObj, fedObj, typeConfig.GetLocallyManagedFields())
+	if err != nil {
+		result.Status, result.Error = errorStatus, err.Error()
+		return
+	}
+	// The recorded generation of the federated resource may differ
+	// without the desired state having changed.
+	annotations := desired.GetAnnotations()
+	delete(annotations, ctlutil.FederatedGenerationAnnotation)
+	desired.SetAnnotations(annotations)
+	result.DriftedPaths = ctlutil.DriftedPaths(desired, clusterObj)
+	if len(result.DriftedPaths) > 0 {
+		result.Status = driftedStatus
+		return
+	}
+	result.Status = verifiedStatus
+}
+
+// clusterObject retrieves the resource rendered as the given object
+// from the given cluster.
+func (o *checksumOptions) clusterObject(hostConfig *rest.Config, client genericclient.Client, cluster *fedv1b1.KubeFedCluster,
+	rendered *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+
+	if !ctlutil.IsClusterReady(&cluster.Status) {
+		return nil, errors.New("Cluster not ready")
+	}
+	clusterConfig, err := o.clusterConfig(hostConfig, client, cluster)
+	if err != nil {
+		return nil, err
+	}
+	gvk := rendered.GroupVersionKind()
+	targetType := &metav1.APIResource{
+		Group:   gvk.Group,
+		Version: gvk.Version,
+		Kind:    gvk.Kind,
+	}
+	targetClient, err := ctlutil.NewResourceClient(clusterConfig, targetType)
+	if err != nil {
+		return nil, err
+	}
+	return targetClient.Resources(rendered.GetNamespace()).Get(rendered.GetName(), metav1.GetOptions{})
+}
+
+func writeTable(w io.Writer, results []clusterChecksum, verify bool) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 3, ' ', 0)
+	if verify {
+		fmt.Fprintln(tw, "CLUSTER\tCHECKSUM\tRECORDED\tSTATUS\tMESSAGE")
+	} else {
+		fmt.Fprintln(tw, "CLUSTER\tCHECKSUM\tMESSAGE")
+	}
+	for _, result := range results {
+		message := result.Error
+		if len(result.DriftedPaths) > 0 {
+			message = fmt.Sprintf("Changed paths: %s", strings.Join(result.DriftedPaths, ", "))
+		}
+		if verify {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", result.Cluster, result.Checksum, result.RecordedChecksum, result.Status, message)
+		} else {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", result.Cluster, result.Checksum, message)
+		}
+	}
+	return tw.Flush()
+}
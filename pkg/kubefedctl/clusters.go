@@ -0,0 +1,175 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubefedctl
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/options"
+)
+
+// clusterEntry identifies a cluster that is joined or unjoined, as
+// listed in a clusters file.
+type clusterEntry struct {
+	// Name is the name of the cluster in the control plane.
+	Name string `json:"name"`
+	// Context is the context of the cluster in the local kubeconfig.
+	// Defaults to the name.
+	Context string `json:"context,omitempty"`
+	// SecretName is the name of the secret storing the credentials of
+	// the cluster in the host cluster.  Only used by join.
+	SecretName string `json:"secretName,omitempty"`
+}
+
+// clusterOperation describes an operation performed on clusters for
+// the progress and summary output.
+type clusterOperation struct {
+	// verb is the infinitive of the operation, e.g. "join".
+	verb string
+	// pastTense is the past tense of the operation, e.g. "Joined".
+	pastTense string
+}
+
+// clusterEntries returns the clusters named by the given options,
+// either as arguments or in the clusters file.  A single cluster passed
+// as an argument may set its context and secret name with flags, which
+// may not be set for multiple clusters.
+func clusterEntries(o *options.CommonJoinOptions, secretName string) ([]clusterEntry, error) {
+	var clusters []clusterEntry
+	if len(o.ClustersFile) > 0 {
+		data, err := ioutil.ReadFile(o.ClustersFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read clusters file %q", o.ClustersFile)
+		}
+		if err := yaml.UnmarshalStrict(data, &clusters); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse clusters file %q", o.ClustersFile)
+		}
+		if len(clusters) == 0 {
+			return nil, errors.Errorf("clusters file %q does not list any clusters", o.ClustersFile)
+		}
+	} else {
+		for _, name := range o.ClusterNames {
+			clusters = append(clusters, clusterEntry{Name: name})
+		}
+	}
+
+	if len(clusters) == 1 && len(o.ClustersFile) == 0 {
+		clusters[0].Context = o.ClusterContext
+		clusters[0].SecretName = secretName
+	} else if len(o.ClusterContext) > 0 || len(secretName) > 0 {
+		return nil, errors.New("cluster-context and secret-name may only be set for a single cluster passed as an argument")
+	}
+
+	names := sets.NewString()
+	for i := range clusters {
+		cluster := &clusters[i]
+		if len(cluster.Name) == 0 {
+			return nil, errors.Errorf("entry %d of the clusters does not have a name", i)
+		}
+		if names.Has(cluster.Name) {
+			return nil, errors.Errorf("cluster %q is listed more than once", cluster.Name)
+		}
+		names.Insert(cluster.Name)
+		if len(cluster.Context) == 0 {
+			cluster.Context = cluster.Name
+		}
+	}
+	return clusters, nil
+}
+
+type clusterOperationResult struct {
+	cluster  string
+	err      error
+	duration time.Duration
+}
+
+// runForClusters performs the given operation for each of the given
+// clusters, running at most concurrency operations at a time.  The
+// completion of each operation is reported to the given writer,
+// followed by a summary of the results.  A single cluster is operated
+// on without reporting progress.
+func runForClusters(w io.Writer, clusters []clusterEntry, concurrency int, operation clusterOperation,
+	operate func(clusterEntry) error) error {
+	if len(clusters) == 1 {
+		return operate(clusters[0])
+	}
+
+	results := make([]clusterOperationResult, len(clusters))
+	indexes := make(chan int)
+	var lock sync.Mutex
+	completed := 0
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency && i < len(clusters); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				cluster := clusters[index]
+				start := time.Now()
+				err := operate(cluster)
+				result := clusterOperationResult{cluster: cluster.Name, err: err, duration: time.Since(start)}
+				results[index] = result
+
+				lock.Lock()
+				completed++
+				if err != nil {
+					fmt.Fprintf(w, "[%d/%d] Failed to %s cluster %q: %v\n", completed, len(clusters), operation.verb, cluster.Name, err)
+				} else {
+					fmt.Fprintf(w, "[%d/%d] %s cluster %q in %v\n", completed, len(clusters), operation.pastTense, cluster.Name,
+						result.duration.Round(time.Millisecond))
+				}
+				lock.Unlock()
+			}
+		}()
+	}
+	for i := range clusters {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	failed := 0
+	fmt.Fprintln(w)
+	tw := tabwriter.NewWriter(w, 0, 8, 3, ' ', 0)
+	fmt.Fprintln(tw, "CLUSTER\tRESULT\tDURATION\tMESSAGE")
+	for _, result := range results {
+		status, message := "Succeeded", ""
+		if result.err != nil {
+			failed++
+			status, message = "Failed", result.err.Error()
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%v\t%s\n", result.cluster, status, result.duration.Round(time.Millisecond), message)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return errors.Errorf("failed to %s %d of %d clusters", operation.verb, failed, len(clusters))
+	}
+	return nil
+}
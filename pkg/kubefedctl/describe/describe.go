@@ -0,0 +1,468 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package describe
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog"
+
+	"sigs.k8s.io/kubefed/pkg/apis/core/common"
+	fedv1a1 "sigs.k8s.io/kubefed/pkg/apis/core/v1alpha1"
+	schedulingv1a1 "sigs.k8s.io/kubefed/pkg/apis/scheduling/v1alpha1"
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	"sigs.k8s.io/kubefed/pkg/controller/sync/status"
+	ctlutil "sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/enable"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/options"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/util"
+)
+
+var (
+	describe_long = `
+		Describe shows a report of a federated resource for debugging
+		its propagation. The report combines the placement of the
+		resource, the scheduling preference it is scheduled by, its
+		propagation conditions, the propagation status and propagated
+		version of each member cluster and the events recorded for the
+		resource, including the events mirrored from member clusters.
+
+		The federated resource is identified as TYPE/NAME, where TYPE
+		is the federated type (e.g. federateddeployment) or its target
+		type (e.g. deployment).
+
+		Current context is assumed to be a Kubernetes cluster hosting
+		the kubefed control plane. Please use the --host-cluster-context
+		flag otherwise.`
+
+	describe_example = `
+		# Describe federated deployment "foo"
+		kubefedctl describe federateddeployment/foo -n my-ns --host-cluster-context=cluster1`
+
+	// The kinds of the scheduling preferences that may schedule a
+	// federated resource of the same name.
+	preferenceKinds = []string{
+		"ReplicaSchedulingPreference",
+		"JobSchedulingPreference",
+		"CronJobSchedulingPreference",
+	}
+)
+
+type describeOptions struct {
+	options.GlobalSubcommandOptions
+	typeName     string
+	resourceName string
+	namespace    string
+}
+
+// Bind adds the describe specific arguments to the flagset passed in
+// as an argument.
+func (o *describeOptions) Bind(flags *pflag.FlagSet) {
+	flags.StringVarP(&o.namespace, "namespace", "n", "default", "The namespace of the federated resource.")
+}
+
+// Complete ensures that options are valid.
+func (o *describeOptions) Complete(args []string) error {
+	if len(args) != 1 {
+		return errors.New("TYPE/NAME is required")
+	}
+	parts := strings.SplitN(args[0], "/", 2)
+	if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+		return errors.Errorf("Invalid resource %q. The resource must be given as TYPE/NAME", args[0])
+	}
+	o.typeName, o.resourceName = parts[0], parts[1]
+	return nil
+}
+
+// NewCmdDescribe defines the `describe` command that shows a report
+// of a federated resource.
+func NewCmdDescribe(cmdOut io.Writer, config util.FedConfig) *cobra.Command {
+	opts := &describeOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "describe TYPE/NAME",
+		Short:   "Show a report of a federated resource and its propagation to member clusters",
+		Long:    describe_long,
+		Example: describe_example,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := opts.Complete(args)
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+
+			err = opts.Run(cmdOut, config)
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+		},
+	}
+
+	flags := cmd.Flags()
+	opts.GlobalSubcommandBind(flags)
+	opts.Bind(flags)
+
+	return cmd
+}
+
+// report holds what is shown by the describe command.
+type report struct {
+	fedObj     *unstructured.Unstructured
+	placement  *ctlutil.GenericPlacement
+	overrides  ctlutil.OverridesMap
+	status     *status.GenericPropagationStatus
+	preference *unstructured.Unstructured
+	version    *fedv1a1.PropagatedVersionStatus
+	events     []corev1.Event
+}
+
+// Run is the implementation of the `describe` command.
+func (o *describeOptions) Run(cmdOut io.Writer, config util.FedConfig) error {
+	hostConfig, err := config.HostConfig(o.HostClusterContext, o.Kubeconfig)
+	if err != nil {
+		return errors.Wrap(err, "Failed to get host cluster config")
+	}
+	client, err := genericclient.New(hostConfig)
+	if err != nil {
+		return errors.Wrap(err, "Failed to get kubefed clientset")
+	}
+	hostClientset, err := util.HostClientset(hostConfig)
+	if err != nil {
+		return errors.Wrap(err, "Failed to get host cluster clientset")
+	}
+
+	typeConfig, err := enable.LookupTypeConfig(hostConfig, client, o.typeName, o.KubeFedNamespace)
+	if err != nil {
+		return err
+	}
+	federatedType := typeConfig.GetFederatedType()
+	namespace := o.namespace
+	if !typeConfig.GetFederatedNamespaced() {
+		namespace = ""
+	}
+	fedObj := &unstructured.Unstructured{}
+	fedObj.SetAPIVersion(fmt.Sprintf("%s/%s", federatedType.Group, federatedType.Version))
+	fedObj.SetKind(federatedType.Kind)
+	err = client.Get(context.TODO(), fedObj, namespace, o.resourceName)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to retrieve %s %q", federatedType.Kind,
+			ctlutil.QualifiedName{Namespace: namespace, Name: o.resourceName})
+	}
+	qualifiedName := ctlutil.NewQualifiedName(fedObj)
+
+	r := &report{fedObj: fedObj}
+	r.placement, err = ctlutil.UnmarshalGenericPlacement(fedObj)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to read the placement of %s %q", federatedType.Kind, qualifiedName)
+	}
+	r.overrides, err = ctlutil.GetOverrides(fedObj)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to read the overrides of %s %q", federatedType.Kind, qualifiedName)
+	}
+	fedStatus := &status.GenericFederatedStatus{}
+	err = pkgruntime.DefaultUnstructuredConverter.FromUnstructured(fedObj.Object, fedStatus)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to read the status of %s %q", federatedType.Kind, qualifiedName)
+	}
+	r.status = fedStatus.Status
+
+	// The remaining sections are best-effort so that the report can
+	// be shown even if the user is not allowed to read all of them.
+	r.preference, err = schedulingPreference(client, fedObj)
+	if err != nil {
+		klog.Warningf("Failed to retrieve the scheduling preference of %s %q: %v", federatedType.Kind, qualifiedName, err)
+	}
+	r.version, err = propagatedVersion(client, typeConfig.GetFederatedNamespaced(), typeConfig.GetTargetType().Kind, qualifiedName)
+	if err != nil {
+		klog.Warningf("Failed to retrieve the propagated version of %s %q: %v", federatedType.Kind, qualifiedName, err)
+	}
+	eventNamespace := qualifiedName.Namespace
+	if len(eventNamespace) == 0 {
+		eventNamespace = metav1.NamespaceDefault
+	}
+	eventList, err := hostClientset.CoreV1().Events(eventNamespace).List(metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("involvedObject.uid", string(fedObj.GetUID())).String(),
+	})
+	if err != nil {
+		klog.Warningf("Failed to retrieve the events of %s %q: %v", federatedType.Kind, qualifiedName, err)
+	} else {
+		r.events = eventList.Items
+	}
+
+	return writeReport(cmdOut, r, time.Now())
+}
+
+// schedulingPreference returns the scheduling preference targeting
+// the given federated resource, or nil if it is not scheduled by a
+// preference.
+func schedulingPreference(client genericclient.Client, fedObj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	if len(fedObj.GetNamespace()) == 0 {
+		return nil, nil
+	}
+	for _, kind := range preferenceKinds {
+		preference := &unstructured.Unstructured{}
+		preference.SetAPIVersion(schedulingv1a1.SchemeGroupVersion.String())
+		preference.SetKind(kind)
+		err := client.Get(context.TODO(), preference, fedObj.GetNamespace(), fedObj.GetName())
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		targetKind, _, _ := unstructured.NestedString(preference.Object, ctlutil.SpecField, "targetKind")
+		if targetKind == fedObj.GetKind() {
+			return preference, nil
+		}
+	}
+	return nil, nil
+}
+
+// propagatedVersion returns the status of the propagated version of
+// the given federated resource, or nil if no version has been
+// recorded.
+func propagatedVersion(client genericclient.Client, namespaced bool, targetKind string, qualifiedName ctlutil.QualifiedName) (*fedv1a1.PropagatedVersionStatus, error) {
+	versionName := common.PropagatedVersionName(targetKind, qualifiedName.Name)
+	var err error
+	var versionStatus *fedv1a1.PropagatedVersionStatus
+	if namespaced {
+		version := &fedv1a1.PropagatedVersion{}
+		err = client.Get(context.TODO(), version, qualifiedName.Namespace, versionName)
+		versionStatus = &version.Status
+	} else {
+		version := &fedv1a1.ClusterPropagatedVersion{}
+		err = client.Get(context.TODO(), version, "", versionName)
+		versionStatus = &version.Status
+	}
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return versionStatus, nil
+}
+
+// writeReport writes the sections of the report in the style of
+// `kubectl describe`.
+func writeReport(w io.Writer, r *report, now time.Time) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fedObj := r.fedObj
+
+	fmt.Fprintf(tw, "Name:\t%s\n", fedObj.GetName())
+	if len(fedObj.GetNamespace()) > 0 {
+		fmt.Fprintf(tw, "Namespace:\t%s\n", fedObj.GetNamespace())
+	}
+	fmt.Fprintf(tw, "Kind:\t%s\n", fedObj.GetKind())
+	created := fedObj.GetCreationTimestamp()
+	fmt.Fprintf(tw, "Created:\t%s (%s ago)\n", created.UTC().Format(time.RFC3339), util.FormatAge(now.Sub(created.Time)))
+	fmt.Fprintf(tw, "Generation:\t%d\n", fedObj.GetGeneration())
+	if deleted := fedObj.GetDeletionTimestamp(); deleted != nil {
+		fmt.Fprintf(tw, "Deleting Since:\t%s\n", deleted.UTC().Format(time.RFC3339))
+	}
+
+	fmt.Fprintf(tw, "Placement:\n")
+	clusterNames := r.placement.ClusterNames()
+	if len(clusterNames) > 0 || r.placement.Spec.Placement.ClusterSelector == nil {
+		fmt.Fprintf(tw, "  Clusters:\t%s\n", joinOrNone(clusterNames))
+	}
+	if selector := r.placement.Spec.Placement.ClusterSelector; selector != nil {
+		fmt.Fprintf(tw, "  Cluster Selector:\t%s\n", metav1.FormatLabelSelector(&selector.LabelSelector))
+		for _, requirement := range selector.MatchFields {
+			fmt.Fprintf(tw, "  Field Requirement:\t%s %s %s\n", requirement.Key, requirement.Operator, strings.Join(requirement.Values, ","))
+		}
+	}
+	if minClusters := r.placement.MinClusters(); minClusters > 0 {
+		fmt.Fprintf(tw, "  Min Clusters:\t%d\n", minClusters)
+	}
+	if maxClusters, ok := r.placement.MaxClusters(); ok {
+		fmt.Fprintf(tw, "  Max Clusters:\t%d\n", maxClusters)
+	}
+
+	fmt.Fprintf(tw, "Scheduling:\n")
+	if r.preference == nil {
+		fmt.Fprintf(tw, "  Preference:\t<none>\n")
+	} else {
+		fmt.Fprintf(tw, "  Preference:\t%s %s\n", r.preference.GetKind(), ctlutil.NewQualifiedName(r.preference))
+		if totalReplicas, ok, _ := unstructured.NestedInt64(r.preference.Object, ctlutil.SpecField, "totalReplicas"); ok {
+			fmt.Fprintf(tw, "  Total Replicas:\t%d\n", totalReplicas)
+		}
+	}
+	if scheduled := scheduledReplicas(r.overrides); len(scheduled) > 0 {
+		fmt.Fprintf(tw, "  Replicas:\t%s\n", strings.Join(scheduled, ", "))
+	}
+
+	fmt.Fprintf(tw, "Conditions:\n")
+	if r.status == nil || len(r.status.Conditions) == 0 {
+		fmt.Fprintf(tw, "  <none>\n")
+	} else {
+		fmt.Fprintf(tw, "  Type\tStatus\tReason\tLast Transition\tMessage\n")
+		fmt.Fprintf(tw, "  ----\t------\t------\t---------------\t-------\n")
+		for _, condition := range r.status.Conditions {
+			fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\t%s\n", condition.Type, condition.Status, condition.Reason,
+				condition.LastTransitionTime, condition.Message)
+		}
+	}
+
+	if r.version != nil {
+		fmt.Fprintf(tw, "Propagated Version:\n")
+		fmt.Fprintf(tw, "  Template Version:\t%s\n", r.version.TemplateVersion)
+		fmt.Fprintf(tw, "  Overrides Version:\t%s\n", r.version.OverrideVersion)
+	}
+
+	fmt.Fprintf(tw, "Clusters:\n")
+	rows := clusterRows(r)
+	if len(rows) == 0 {
+		fmt.Fprintf(tw, "  <none>\n")
+	} else {
+		fmt.Fprintf(tw, "  Cluster\tStatus\tVersion\tLast Applied\tDrift\n")
+		fmt.Fprintf(tw, "  -------\t------\t-------\t------------\t-----\n")
+		for _, row := range rows {
+			fmt.Fprintf(tw, "  %s\n", strings.Join(row, "\t"))
+		}
+	}
+
+	fmt.Fprintf(tw, "Events:\n")
+	if len(r.events) == 0 {
+		fmt.Fprintf(tw, "  <none>\n")
+	} else {
+		events := r.events
+		sort.Slice(events, func(i, j int) bool {
+			return eventTime(&events[i]).Before(eventTime(&events[j]))
+		})
+		fmt.Fprintf(tw, "  Type\tReason\tAge\tFrom\tMessage\n")
+		fmt.Fprintf(tw, "  ----\t------\t---\t----\t-------\n")
+		for i := range events {
+			event := &events[i]
+			age := util.FormatAge(now.Sub(eventTime(event)))
+			if event.Count > 1 {
+				age = fmt.Sprintf("%s (x%d)", age, event.Count)
+			}
+			fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\t%s\n", event.Type, event.Reason, age, event.Source.Component,
+				strings.TrimSpace(event.Message))
+		}
+	}
+
+	return tw.Flush()
+}
+
+// clusterRows returns a row for each cluster that the federated
+// resource is placed on, has a status for or has a propagated version
+// for.
+func clusterRows(r *report) [][]string {
+	statuses := make(map[string]status.GenericClusterStatus)
+	if r.status != nil {
+		for _, clusterStatus := range r.status.Clusters {
+			statuses[clusterStatus.Name] = clusterStatus
+		}
+	}
+	versions := make(map[string]string)
+	if r.version != nil {
+		for _, clusterVersion := range r.version.ClusterVersions {
+			versions[clusterVersion.ClusterName] = clusterVersion.Version
+		}
+	}
+
+	names := make(map[string]bool)
+	for _, name := range r.placement.ClusterNames() {
+		names[name] = true
+	}
+	for name := range statuses {
+		names[name] = true
+	}
+	for name := range versions {
+		names[name] = true
+	}
+	sortedNames := []string{}
+	for name := range names {
+		sortedNames = append(sortedNames, name)
+	}
+	sort.Strings(sortedNames)
+
+	rows := [][]string{}
+	for _, name := range sortedNames {
+		propagationStatus := "<unknown>"
+		drift := "<none>"
+		lastApplied := "<unknown>"
+		version, ok := versions[name]
+		if !ok {
+			version = "<none>"
+		}
+		if clusterStatus, ok := statuses[name]; ok {
+			// The version observed by the sync controller is preferred
+			// to the version recorded when it last wrote the resource.
+			if len(clusterStatus.ObservedVersion) > 0 {
+				version = clusterStatus.ObservedVersion
+			}
+			if len(clusterStatus.LastAppliedTime) > 0 {
+				lastApplied = clusterStatus.LastAppliedTime
+			}
+			propagationStatus = string(clusterStatus.Status)
+			if clusterStatus.Status == status.ClusterPropagationOK {
+				propagationStatus = "OK"
+			}
+			if clusterStatus.Drift != nil {
+				drift = strings.Join(clusterStatus.Drift.ChangedPaths, ",")
+			}
+		}
+		rows = append(rows, []string{name, propagationStatus, version, lastApplied, drift})
+	}
+	return rows
+}
+
+// scheduledReplicas returns the replicas overridden per cluster, as
+// written by the scheduling of replicas.
+func scheduledReplicas(overrides ctlutil.OverridesMap) []string {
+	replicasPath := "/" + ctlutil.SpecField + "/" + ctlutil.ReplicasField
+	scheduled := []string{}
+	for clusterName, clusterOverrides := range overrides {
+		if replicas, ok := clusterOverrides[replicasPath]; ok {
+			scheduled = append(scheduled, fmt.Sprintf("%s=%v", clusterName, replicas))
+		}
+	}
+	sort.Strings(scheduled)
+	return scheduled
+}
+
+func eventTime(event *corev1.Event) time.Time {
+	if !event.LastTimestamp.IsZero() {
+		return event.LastTimestamp.Time
+	}
+	return event.EventTime.Time
+}
+
+func joinOrNone(values []string) string {
+	if len(values) == 0 {
+		return "<none>"
+	}
+	return strings.Join(values, ", ")
+}
@@ -83,7 +83,7 @@ type disableTypeOptions struct {
 // Bind adds the disable specific arguments to the flagset passed in as an
 // argument.
 func (o *disableTypeOptions) Bind(flags *pflag.FlagSet) {
-	flags.BoolVar(&o.deleteCRD, "delete-crd", false, "Whether to remove the API resource added by 'enable'.")
+	flags.BoolVar(&o.deleteCRD, "delete-crd", false, "Whether to remove the API resource and the aggregated ClusterRoles added by 'enable'.")
 }
 
 // NewCmdTypeDisable defines the `disable` command that
@@ -194,6 +194,10 @@ func DisableFederation(cmdOut io.Writer, config *rest.Config, enableTypeDirectiv
 		return err
 	}
 
+	if ftcExists && deleteCRD && typeconfig.IsGenericFederatedType(typeConfig) {
+		return errors.Errorf("FederatedTypeConfig %q uses the %s type whose CRD is shared with other types and cannot be deleted. Please try kubefedctl disable again without the '--delete-crd' option", typeConfigName, typeconfig.GenericFederatedKind)
+	}
+
 	if dryRun {
 		return nil
 	}
@@ -209,7 +213,7 @@ func DisableFederation(cmdOut io.Writer, config *rest.Config, enableTypeDirectiv
 				return err
 			}
 		}
-		if typeConfig.GetPropagationEnabled() {
+		if typeConfig.Spec.Propagation != fedv1b1.PropagationDisabled {
 			err = disablePropagation(client, typeConfig, typeConfigName, write)
 			if err != nil {
 				return err
@@ -261,7 +265,7 @@ func checkFederatedTypeConfigExists(client genericclient.Client, typeConfig *fed
 }
 
 func disablePropagation(client genericclient.Client, typeConfig *fedv1b1.FederatedTypeConfig, typeConfigName ctlutil.QualifiedName, write func(string)) error {
-	if typeConfig.GetPropagationEnabled() {
+	if typeConfig.Spec.Propagation != fedv1b1.PropagationDisabled {
 		typeConfig.Spec.Propagation = fedv1b1.PropagationDisabled
 		err := client.Update(context.TODO(), typeConfig)
 		if err != nil {
@@ -329,7 +333,7 @@ func deleteFederatedType(config *rest.Config, typeConfig typeconfig.Interface, w
 		return err
 	}
 
-	return nil
+	return enable.DeleteAggregatedClusterRoles(config, typeConfig.GetFederatedType(), write)
 }
 
 func checkFederatedTypeCustomResourcesExist(config *rest.Config, typeConfig typeconfig.Interface, write func(string)) error {
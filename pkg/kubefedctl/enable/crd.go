@@ -0,0 +1,145 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package enable
+
+import (
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	apiextv1b1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/apimachinery/pkg/version"
+)
+
+// LoadCRDFromFile returns the CustomResourceDefinition of the given
+// file that is matched by name. The file may contain multiple
+// documents, in which case a name is required if more than one of
+// them is a CustomResourceDefinition.
+func LoadCRDFromFile(filename, name string) (*apiextv1b1.CustomResourceDefinition, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	decoder := yaml.NewYAMLOrJSONDecoder(f, 4096)
+	var matched []*apiextv1b1.CustomResourceDefinition
+	var candidates []string
+	for {
+		crd := &apiextv1b1.CustomResourceDefinition{}
+		err := decoder.Decode(crd)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to decode yaml from file %q", filename)
+		}
+		if crd.Kind != "CustomResourceDefinition" {
+			continue
+		}
+		candidates = append(candidates, crd.Name)
+		if len(name) == 0 || crdMatchesName(crd, name) {
+			matched = append(matched, crd)
+		}
+	}
+
+	switch {
+	case len(candidates) == 0:
+		return nil, errors.Errorf("No CustomResourceDefinition found in file %q", filename)
+	case len(matched) == 0:
+		return nil, errors.Errorf("No CustomResourceDefinition named %q found in file %q", name, filename)
+	case len(matched) > 1:
+		return nil, errors.Errorf("Multiple CustomResourceDefinitions found in file %q: %s. A name must be provided.",
+			filename, strings.Join(candidates, ", "))
+	}
+	return matched[0], nil
+}
+
+func crdMatchesName(crd *apiextv1b1.CustomResourceDefinition, name string) bool {
+	names := crd.Spec.Names
+	apiResource := metav1.APIResource{
+		Name:         names.Plural,
+		SingularName: names.Singular,
+		Kind:         names.Kind,
+		ShortNames:   names.ShortNames,
+	}
+	return strings.ToLower(name) == crd.Name || NameMatchesResource(name, apiResource, crd.Spec.Group)
+}
+
+// crdAPIResource returns the API resource served for the given
+// CustomResourceDefinition, as API discovery would for the target
+// version or, if none is given, the preferred version.
+func crdAPIResource(crd *apiextv1b1.CustomResourceDefinition, targetVersion string) (*metav1.APIResource, error) {
+	servedVersions := []string{}
+	for _, crdVersion := range crd.Spec.Versions {
+		if crdVersion.Served {
+			servedVersions = append(servedVersions, crdVersion.Name)
+		}
+	}
+	if len(crd.Spec.Versions) == 0 && len(crd.Spec.Version) > 0 {
+		servedVersions = append(servedVersions, crd.Spec.Version)
+	}
+	if len(servedVersions) == 0 {
+		return nil, errors.Errorf("CustomResourceDefinition %q does not serve any version", crd.Name)
+	}
+	// Discovery prefers the highest priority version.
+	sort.Slice(servedVersions, func(i, j int) bool {
+		return version.CompareKubeAwareVersionStrings(servedVersions[i], servedVersions[j]) > 0
+	})
+
+	targetResourceVersion := servedVersions[0]
+	if len(targetVersion) > 0 {
+		found := false
+		for _, servedVersion := range servedVersions {
+			if servedVersion == targetVersion {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, errors.Errorf("CustomResourceDefinition %q does not serve version %q", crd.Name, targetVersion)
+		}
+		targetResourceVersion = targetVersion
+	}
+
+	names := crd.Spec.Names
+	return &metav1.APIResource{
+		Group:        crd.Spec.Group,
+		Version:      targetResourceVersion,
+		Name:         names.Plural,
+		SingularName: names.Singular,
+		Kind:         names.Kind,
+		ShortNames:   names.ShortNames,
+		Namespaced:   crd.Spec.Scope == apiextv1b1.NamespaceScoped,
+	}, nil
+}
+
+// crdValidation returns the validation of the given version of a
+// CustomResourceDefinition.
+func crdValidation(crd *apiextv1b1.CustomResourceDefinition, crdVersion string) *apiextv1b1.CustomResourceValidation {
+	for _, v := range crd.Spec.Versions {
+		if v.Name == crdVersion && v.Schema != nil {
+			return v.Schema
+		}
+	}
+	return crd.Spec.Validation
+}
@@ -35,6 +35,25 @@ type EnableTypeDirectiveSpec struct {
 	// The API version to use for generated federated types.
 	// +optional
 	FederatedVersion string `json:"federatedVersion,omitempty"`
+
+	// Whether to propagate the target type with the generic
+	// FederatedObject type instead of generating a federated type.
+	// The federated group and version are ignored if true.
+	// +optional
+	Generic bool `json:"generic,omitempty"`
+
+	// Whether to generate a status type to which the status of the
+	// target resources in member clusters is collected.  Not
+	// supported for the generic FederatedObject type.
+	// +optional
+	EnableStatus bool `json:"enableStatus,omitempty"`
+
+	// A JSON schema (e.g. the values.schema.json of a chart) that the
+	// per-cluster chart values of the target resources must satisfy.
+	// Only supported for target types that deploy Helm charts (Flux
+	// HelmReleases and Argo CD Applications).
+	// +optional
+	ValuesSchema string `json:"valuesSchema,omitempty"`
 }
 
 // TODO(marun) This should become a proper API type and drive enabling
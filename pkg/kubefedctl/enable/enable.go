@@ -20,24 +20,29 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
 
 	jsoniter "github.com/json-iterator/go"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 
+	rbacv1 "k8s.io/api/rbac/v1"
 	apiextv1b1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
 	apiextv1b1client "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/typed/apiextensions/v1beta1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/rest"
 	"k8s.io/klog"
 
 	"sigs.k8s.io/kubefed/pkg/apis/core/typeconfig"
 	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/kubefed/pkg/apis/core/v1beta1/validation/valuesschema"
 	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	ctlutil "sigs.k8s.io/kubefed/pkg/controller/util"
 	"sigs.k8s.io/kubefed/pkg/kubefedctl/options"
 	"sigs.k8s.io/kubefed/pkg/kubefedctl/util"
 )
@@ -52,7 +57,33 @@ var (
 		Enables a Kubernetes API type (including a CRD) to be propagated
 		to clusters registered with a KubeFed control plane.  A CRD for
 		the federated type will be generated and a FederatedTypeConfig will
-		be created to configure a sync controller.
+		be created to configure a sync controller.  If --generic is
+		specified, no CRD will be generated and resources of the type
+		will instead be propagated with the generic FederatedObject type.
+		If --enable-status is specified, a CRD for a status type will
+		also be generated and the status of the target resources in
+		member clusters will be collected to it.
+
+		For namespaced types, ClusterRoles are also created that
+		aggregate the permissions on the generated types to the
+		default view, edit and admin ClusterRoles, so that users
+		granted one of them in a namespace may access the federated
+		resources of the namespace.
+
+		For types that deploy Helm charts (Flux HelmReleases and Argo
+		CD Applications), the FederatedTypeConfig configures the
+		location of the chart values so that overrides can provide a
+		values file per cluster.  If --values-schema is specified, the
+		values propagated to every cluster are validated against the
+		given JSON schema.
+
+		If --from-crd-file is specified, the type is read from the
+		given CRD manifest instead of being discovered from the host
+		cluster, and NAME is only required to select one of multiple
+		CRDs in the file. With --output=yaml, no access to a host
+		cluster is required so that the resources can be generated
+		offline (e.g. for a GitOps repository). Otherwise the type
+		must already be installed in the host cluster.
 
 		Current context is assumed to be a Kubernetes cluster hosting
 		the kubefed control plane. Please use the
@@ -64,7 +95,21 @@ var (
 
 		# Enable federation of Deployments identified by name specified in
 		# deployment.yaml
-		kubefedctl enable -f deployment.yaml`
+		kubefedctl enable -f deployment.yaml
+
+		# Enable federation of ConfigMaps with the generic FederatedObject type
+		kubefedctl enable configmaps --generic
+
+		# Enable federation of ClusterIssuers and collection of their status
+		kubefedctl enable clusterissuers.cert-manager.io --enable-status
+
+		# Enable federation of Flux HelmReleases whose values are
+		# validated against the schema of the chart
+		kubefedctl enable helmreleases.helm.toolkit.fluxcd.io --values-schema values.schema.json
+
+		# Generate the resources enabling federation of the CRD defined
+		# in widget-crd.yaml without accessing a cluster
+		kubefedctl enable --from-crd-file widget-crd.yaml -o yaml`
 )
 
 type enableType struct {
@@ -75,9 +120,14 @@ type enableType struct {
 
 type enableTypeOptions struct {
 	federatedVersion    string
+	generic             bool
+	enableStatus        bool
 	output              string
 	outputYAML          bool
 	filename            string
+	valuesSchemaFile    string
+	crdFile             string
+	crd                 *apiextv1b1.CustomResourceDefinition
 	enableTypeDirective *EnableTypeDirective
 }
 
@@ -85,7 +135,11 @@ type enableTypeOptions struct {
 // argument.
 func (o *enableTypeOptions) Bind(flags *pflag.FlagSet) {
 	flags.StringVar(&o.federatedVersion, "federated-version", options.DefaultFederatedVersion, "The API version to use for the generated federated type.")
+	flags.BoolVar(&o.generic, "generic", false, "Whether to propagate the type with the generic FederatedObject type instead of generating a federated type.")
+	flags.BoolVar(&o.enableStatus, "enable-status", false, "Whether to generate a status type and collect the status of the target resources in member clusters.")
 	flags.StringVarP(&o.output, "output", "o", "", "If provided, the resources that would be created in the API by the command are instead output to stdout in the provided format.  Valid values are ['yaml'].")
+	flags.StringVar(&o.valuesSchemaFile, "values-schema", "", "If provided, the JSON schema file (e.g. values.schema.json) that the per-cluster chart values of a type deploying Helm charts must satisfy.")
+	flags.StringVar(&o.crdFile, "from-crd-file", "", "If provided, the CRD manifest defining the type to enable. API discovery against the host cluster is not performed.")
 	flags.StringVarP(&o.filename, "filename", "f", "", "If provided, the command will be configured from the provided yaml file.  Only --output will be accepted from the command line")
 }
 
@@ -95,7 +149,7 @@ func NewCmdTypeEnable(cmdOut io.Writer, config util.FedConfig) *cobra.Command {
 	opts := &enableType{}
 
 	cmd := &cobra.Command{
-		Use:     "enable (NAME | -f FILENAME)",
+		Use:     "enable (NAME | -f FILENAME | --from-crd-file FILENAME [NAME])",
 		Short:   "Enables propagation of a Kubernetes API type",
 		Long:    enable_long,
 		Example: enable_example,
@@ -136,14 +190,22 @@ func (j *enableType) Complete(args []string) error {
 		if err != nil {
 			return errors.Wrapf(err, "Failed to load yaml from file %q", j.filename)
 		}
-		return nil
+		return j.loadCRD(fd.Name)
 	}
 
-	if err := j.SetName(args); err != nil {
+	if len(j.crdFile) == 0 || len(args) > 0 {
+		if err := j.SetName(args); err != nil {
+			return err
+		}
+	}
+	if err := j.loadCRD(j.TargetName); err != nil {
 		return err
 	}
 
 	fd.Name = j.TargetName
+	if j.crd != nil {
+		fd.Name = j.crd.Name
+	}
 
 	if len(j.TargetVersion) > 0 {
 		fd.Spec.TargetVersion = j.TargetVersion
@@ -154,25 +216,75 @@ func (j *enableType) Complete(args []string) error {
 	if len(j.federatedVersion) > 0 {
 		fd.Spec.FederatedVersion = j.federatedVersion
 	}
+	fd.Spec.Generic = j.generic
+	fd.Spec.EnableStatus = j.enableStatus
+
+	if len(j.valuesSchemaFile) > 0 {
+		valuesSchema, err := ioutil.ReadFile(j.valuesSchemaFile)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to read the values schema from file %q", j.valuesSchemaFile)
+		}
+		if _, err := valuesschema.Parse(string(valuesSchema)); err != nil {
+			return errors.Wrapf(err, "Invalid values schema in file %q", j.valuesSchemaFile)
+		}
+		fd.Spec.ValuesSchema = string(valuesSchema)
+	}
 
 	return nil
 }
 
 // Run is the implementation of the `enable` command.
-func (j *enableType) Run(cmdOut io.Writer, config util.FedConfig) error {
-	hostConfig, err := config.HostConfig(j.HostClusterContext, j.Kubeconfig)
+// loadCRD loads the CRD matching the given name from the file
+// provided by --from-crd-file, if any.
+func (j *enableType) loadCRD(name string) error {
+	if len(j.crdFile) == 0 {
+		return nil
+	}
+	crd, err := LoadCRDFromFile(j.crdFile, name)
 	if err != nil {
-		return errors.Wrap(err, "Failed to get host cluster config")
+		return errors.Wrapf(err, "Failed to load CustomResourceDefinition from file %q", j.crdFile)
+	}
+	j.crd = crd
+	return nil
+}
+
+func (j *enableType) Run(cmdOut io.Writer, config util.FedConfig) error {
+	// Generating the resources from a CRD manifest does not require a
+	// host cluster unless they are to be created.
+	var hostConfig *rest.Config
+	var err error
+	if j.crd == nil || !j.outputYAML {
+		hostConfig, err = config.HostConfig(j.HostClusterContext, j.Kubeconfig)
+		if err != nil {
+			return errors.Wrap(err, "Failed to get host cluster config")
+		}
 	}
 
-	resources, err := GetResources(hostConfig, j.enableTypeDirective)
+	var resources *typeResources
+	if j.crd != nil {
+		resources, err = GetResourcesFromCRD(j.crd, j.enableTypeDirective)
+	} else {
+		resources, err = GetResources(hostConfig, j.enableTypeDirective)
+	}
 	if err != nil {
 		return err
 	}
+	for _, warning := range LintTargetType(resources.TypeConfig.GetTargetType()) {
+		klog.Warning(warning)
+	}
 
 	if j.outputYAML {
 		concreteTypeConfig := resources.TypeConfig.(*fedv1b1.FederatedTypeConfig)
-		objects := []pkgruntime.Object{concreteTypeConfig, resources.CRD}
+		objects := []pkgruntime.Object{concreteTypeConfig}
+		if resources.CRD != nil {
+			objects = append(objects, resources.CRD)
+		}
+		if resources.StatusCRD != nil {
+			objects = append(objects, resources.StatusCRD)
+		}
+		for _, role := range resources.ClusterRoles {
+			objects = append(objects, role)
+		}
 		err := writeObjectsToYAML(objects, cmdOut)
 		if err != nil {
 			return errors.Wrap(err, "Failed to write objects to YAML")
@@ -186,12 +298,32 @@ func (j *enableType) Run(cmdOut io.Writer, config util.FedConfig) error {
 		return nil
 	}
 
+	if j.crd != nil {
+		// The admission webhook rejects a type config whose target
+		// type is not served, so fail before any resources are
+		// created.
+		targetType := resources.TypeConfig.GetTargetType()
+		_, err := LookupAPIResource(hostConfig, typeconfig.GroupQualifiedName(targetType), targetType.Version)
+		if err != nil {
+			return errors.Wrapf(err, "Type %q must be installed in the host cluster before it is enabled (use --output=yaml to generate the resources without installing it)",
+				resourceKey(targetType))
+		}
+	}
+
 	return CreateResources(cmdOut, hostConfig, resources, j.KubeFedNamespace)
 }
 
 type typeResources struct {
 	TypeConfig typeconfig.Interface
-	CRD        *apiextv1b1.CustomResourceDefinition
+	// The CRD of the generated federated type.  Will be nil if the
+	// type is propagated with the generic FederatedObject type.
+	CRD *apiextv1b1.CustomResourceDefinition
+	// The CRD of the generated status type.  Will be nil if status
+	// collection was not requested.
+	StatusCRD *apiextv1b1.CustomResourceDefinition
+	// The ClusterRoles aggregating the permissions on the generated
+	// types to the default view, edit and admin ClusterRoles.
+	ClusterRoles []*rbacv1.ClusterRole
 }
 
 func GetResources(config *rest.Config, enableTypeDirective *EnableTypeDirective) (*typeResources, error) {
@@ -201,9 +333,50 @@ func GetResources(config *rest.Config, enableTypeDirective *EnableTypeDirective)
 	}
 	klog.V(2).Infof("Found type %q", resourceKey(*apiResource))
 
-	typeConfig := GenerateTypeConfigForTarget(*apiResource, enableTypeDirective)
+	return typeResourcesForTarget(*apiResource, enableTypeDirective, func() (schemaAccessor, error) {
+		return newSchemaAccessor(config, *apiResource)
+	})
+}
 
-	accessor, err := newSchemaAccessor(config, *apiResource)
+// GetResourcesFromCRD returns the resources enabling federation of the
+// type defined by the given CustomResourceDefinition. Unlike
+// GetResources, API discovery is not used so that the resources can be
+// generated without access to a host cluster. Whether the type is
+// served is instead checked when the FederatedTypeConfig is admitted.
+func GetResourcesFromCRD(crd *apiextv1b1.CustomResourceDefinition, enableTypeDirective *EnableTypeDirective) (*typeResources, error) {
+	apiResource, err := crdAPIResource(crd, enableTypeDirective.Spec.TargetVersion)
+	if err != nil {
+		return nil, err
+	}
+	klog.V(2).Infof("Read type %q from CustomResourceDefinition %q", resourceKey(*apiResource), crd.Name)
+
+	return typeResourcesForTarget(*apiResource, enableTypeDirective, func() (schemaAccessor, error) {
+		return &crdSchemaAccessor{validation: crdValidation(crd, apiResource.Version)}, nil
+	})
+}
+
+// typeResourcesForTarget returns the resources enabling federation of
+// the given target type, using the provided function to access the
+// schema of the target type.
+func typeResourcesForTarget(apiResource metav1.APIResource, enableTypeDirective *EnableTypeDirective,
+	newAccessor func() (schemaAccessor, error)) (*typeResources, error) {
+
+	typeConfig := GenerateTypeConfigForTarget(apiResource, enableTypeDirective)
+	if len(enableTypeDirective.Spec.ValuesSchema) > 0 && typeConfig.GetValuesConfig() == nil {
+		return nil, errors.Errorf("A values schema is not supported for %s since it does not deploy Helm charts", resourceKey(apiResource))
+	}
+
+	if enableTypeDirective.Spec.Generic {
+		if !apiResource.Namespaced || apiResource.Kind == ctlutil.NamespaceKind {
+			return nil, errors.Errorf("The %s type only supports namespaced types other than namespaces", typeconfig.GenericFederatedKind)
+		}
+		if enableTypeDirective.Spec.EnableStatus {
+			return nil, errors.Errorf("Status collection is not supported for the %s type", typeconfig.GenericFederatedKind)
+		}
+		return &typeResources{TypeConfig: typeConfig}, nil
+	}
+
+	accessor, err := newAccessor()
 	if err != nil {
 		return nil, errors.Wrap(err, "Error initializing validation schema accessor")
 	}
@@ -215,9 +388,17 @@ func GetResources(config *rest.Config, enableTypeDirective *EnableTypeDirective)
 
 	crd := federatedTypeCRD(typeConfig, accessor, shortNames)
 
+	var statusCRD *apiextv1b1.CustomResourceDefinition
+	if statusType := typeConfig.GetStatusType(); statusType != nil {
+		statusCRD = CrdForAPIResource(*statusType, statusTypeValidationSchema(), nil)
+		statusCRD.Spec.Subresources = nil
+	}
+
 	return &typeResources{
-		TypeConfig: typeConfig,
-		CRD:        crd,
+		TypeConfig:   typeConfig,
+		CRD:          crd,
+		StatusCRD:    statusCRD,
+		ClusterRoles: aggregatedClusterRoles(typeConfig),
 	}, nil
 }
 
@@ -267,27 +448,21 @@ func CreateResources(cmdOut io.Writer, config *rest.Config, resources *typeResou
 		}
 	}
 
-	crdClient, err := apiextv1b1client.NewForConfig(config)
-	if err != nil {
-		return errors.Wrap(err, "Failed to create crd clientset")
-	}
-
-	existingCRD, err := crdClient.CustomResourceDefinitions().Get(resources.CRD.Name, metav1.GetOptions{})
-	if apierrors.IsNotFound(err) {
-		_, err = crdClient.CustomResourceDefinitions().Create(resources.CRD)
+	for _, crd := range []*apiextv1b1.CustomResourceDefinition{resources.CRD, resources.StatusCRD} {
+		if crd == nil {
+			continue
+		}
+		err = createOrUpdateCRD(config, crd, write)
 		if err != nil {
-			return errors.Wrapf(err, "Error creating CRD %q", resources.CRD.Name)
+			return err
 		}
-		write(fmt.Sprintf("customresourcedefinition.apiextensions.k8s.io/%s created\n", resources.CRD.Name))
-	} else if err != nil {
-		return errors.Wrapf(err, "Error getting CRD %q", resources.CRD.Name)
-	} else {
-		existingCRD.Spec = resources.CRD.Spec
-		_, err = crdClient.CustomResourceDefinitions().Update(existingCRD)
+	}
+
+	for _, role := range resources.ClusterRoles {
+		err = createOrUpdateClusterRole(config, role, write)
 		if err != nil {
-			return errors.Wrapf(err, "Error updating CRD %q", resources.CRD.Name)
+			return err
 		}
-		write(fmt.Sprintf("customresourcedefinition.apiextensions.k8s.io/%s updated\n", resources.CRD.Name))
 	}
 
 	concreteTypeConfig.Namespace = namespace
@@ -314,6 +489,32 @@ func CreateResources(cmdOut io.Writer, config *rest.Config, resources *typeResou
 	return nil
 }
 
+func createOrUpdateCRD(config *rest.Config, crd *apiextv1b1.CustomResourceDefinition, write func(string)) error {
+	crdClient, err := apiextv1b1client.NewForConfig(config)
+	if err != nil {
+		return errors.Wrap(err, "Failed to create crd clientset")
+	}
+
+	existingCRD, err := crdClient.CustomResourceDefinitions().Get(crd.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = crdClient.CustomResourceDefinitions().Create(crd)
+		if err != nil {
+			return errors.Wrapf(err, "Error creating CRD %q", crd.Name)
+		}
+		write(fmt.Sprintf("customresourcedefinition.apiextensions.k8s.io/%s created\n", crd.Name))
+	} else if err != nil {
+		return errors.Wrapf(err, "Error getting CRD %q", crd.Name)
+	} else {
+		existingCRD.Spec = crd.Spec
+		_, err = crdClient.CustomResourceDefinitions().Update(existingCRD)
+		if err != nil {
+			return errors.Wrapf(err, "Error updating CRD %q", crd.Name)
+		}
+		write(fmt.Sprintf("customresourcedefinition.apiextensions.k8s.io/%s updated\n", crd.Name))
+	}
+	return nil
+}
+
 func GenerateTypeConfigForTarget(apiResource metav1.APIResource, enableTypeDirective *EnableTypeDirective) typeconfig.Interface {
 	spec := enableTypeDirective.Spec
 	kind := apiResource.Kind
@@ -344,12 +545,50 @@ func GenerateTypeConfigForTarget(apiResource metav1.APIResource, enableTypeDirec
 			},
 		},
 	}
+	if spec.EnableStatus {
+		// The status of a resource is collected to an object of the
+		// same name and scope as the federated resource.
+		statusCollection := fedv1b1.StatusCollectionEnabled
+		typeConfig.Spec.StatusCollection = &statusCollection
+		typeConfig.Spec.StatusType = &fedv1b1.APIResource{
+			Group:   spec.FederatedGroup,
+			Version: spec.FederatedVersion,
+			Kind:    fmt.Sprintf("Federated%sStatus", kind),
+			Scope:   FederatedNamespacedToScope(apiResource),
+		}
+	}
+	if valuesConfig, ok := chartValuesConfigs[schema.GroupKind{Group: apiResource.Group, Kind: kind}]; ok {
+		valuesConfig.Schema = spec.ValuesSchema
+		typeConfig.Spec.Values = &valuesConfig
+	}
+	if spec.Generic {
+		typeConfig.Spec.FederatedType = fedv1b1.APIResource{
+			Group:      typeconfig.GenericFederatedGroup,
+			Version:    typeconfig.GenericFederatedVersion,
+			Kind:       typeconfig.GenericFederatedKind,
+			PluralName: typeconfig.GenericFederatedPluralName,
+			Scope:      apiextv1b1.NamespaceScoped,
+		}
+	}
 
 	// Set defaults that would normally be set by the api
 	fedv1b1.SetFederatedTypeConfigDefaults(typeConfig)
 	return typeConfig
 }
 
+// The location of the chart values of target types that deploy Helm
+// charts.
+var chartValuesConfigs = map[schema.GroupKind]fedv1b1.ValuesConfig{
+	{Group: "helm.toolkit.fluxcd.io", Kind: "HelmRelease"}: {
+		Path:   "spec.values",
+		Format: fedv1b1.ValuesFormatObject,
+	},
+	{Group: "argoproj.io", Kind: "Application"}: {
+		Path:   "spec.source.helm.values",
+		Format: fedv1b1.ValuesFormatYAML,
+	},
+}
+
 func qualifiedAPIResourceName(resource metav1.APIResource) string {
 	if resource.Group == "" {
 		return fmt.Sprintf("%s/%s", resource.Name, resource.Version)
@@ -360,6 +599,13 @@ func qualifiedAPIResourceName(resource metav1.APIResource) string {
 func federatedTypeCRD(typeConfig typeconfig.Interface, accessor schemaAccessor, shortNames []string) *apiextv1b1.CustomResourceDefinition {
 	templateSchema := accessor.templateSchema()
 	schema := federatedTypeValidationSchema(templateSchema)
+	if target := typeConfig.GetTargetType(); target.Kind == ctlutil.ServiceKind && target.Group == "" {
+		addLoadBalancerStatus(schema)
+		addServiceImportStatus(schema)
+	}
+	if target := typeConfig.GetTargetType(); target.Kind != ctlutil.SecretKind || target.Group != "" {
+		disallowSecretValueSources(schema)
+	}
 	return CrdForAPIResource(typeConfig.GetFederatedType(), schema, shortNames)
 }
 
@@ -0,0 +1,50 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package enable
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/kubefed/pkg/apis/core/typeconfig"
+)
+
+// memberControllerOwnedTypes maps the group-qualified names of the
+// types whose resources are usually created by the components of each
+// cluster rather than by users to the components creating them.
+var memberControllerOwnedTypes = map[string]string{
+	"pods":                            "the controllers of Deployments, ReplicaSets, StatefulSets, DaemonSets and Jobs",
+	"replicasets.apps":                "the controller of Deployments",
+	"controllerrevisions.apps":        "the controllers of StatefulSets and DaemonSets",
+	"endpoints":                       "the controller of Services",
+	"endpointslices.discovery.k8s.io": "the controller of Services",
+	"events":                          "the components of the cluster",
+	"events.events.k8s.io":            "the components of the cluster",
+}
+
+// LintTargetType returns warnings about enabling federation of the
+// given target type whose resources are usually created and managed by
+// the components of member clusters.
+func LintTargetType(apiResource metav1.APIResource) []string {
+	creators, ok := memberControllerOwnedTypes[typeconfig.GroupQualifiedName(apiResource)]
+	if !ok {
+		return nil
+	}
+	return []string{fmt.Sprintf("Resources of type %q are usually created by %s in each cluster, which may modify or delete propagated resources; consider federating the resources they are created for instead",
+		resourceKey(apiResource), creators)}
+}
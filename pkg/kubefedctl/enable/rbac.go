@@ -0,0 +1,163 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package enable
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+
+	"sigs.k8s.io/kubefed/pkg/apis/core/typeconfig"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/util"
+)
+
+const (
+	aggregateToViewLabel  = "rbac.authorization.k8s.io/aggregate-to-view"
+	aggregateToEditLabel  = "rbac.authorization.k8s.io/aggregate-to-edit"
+	aggregateToAdminLabel = "rbac.authorization.k8s.io/aggregate-to-admin"
+
+	// federatedTypeLabel identifies the federated type that an
+	// aggregated ClusterRole grants access to.
+	federatedTypeLabel = "kubefed.io/federated-type"
+)
+
+// aggregatedClusterRoleNames returns the names of the ClusterRoles
+// that aggregate the permissions on the given federated type to the
+// default view, edit and admin ClusterRoles.
+func aggregatedClusterRoleNames(federatedType metav1.APIResource) []string {
+	name := typeconfig.GroupQualifiedName(federatedType)
+	return []string{
+		fmt.Sprintf("kubefed:aggregate-to-view:%s", name),
+		fmt.Sprintf("kubefed:aggregate-to-edit:%s", name),
+	}
+}
+
+// aggregatedClusterRoles returns the ClusterRoles that aggregate the
+// permissions on the federated and status types of the given type
+// config to the default view, edit and admin ClusterRoles, so that
+// users granted one of them in a namespace are granted the same access
+// to the federated resources of the namespace.  As for the target
+// types of Kubernetes, viewers may read federated resources and
+// editors and admins may also modify them.  The status type is only
+// written by the controller manager and is read-only.
+//
+// No ClusterRoles are returned for cluster-scoped federated types,
+// which are not accessible with namespaced role bindings, or for the
+// generic FederatedObject type whose ClusterRoles are installed with
+// KubeFed.
+func aggregatedClusterRoles(typeConfig typeconfig.Interface) []*rbacv1.ClusterRole {
+	federatedType := typeConfig.GetFederatedType()
+	if !federatedType.Namespaced || typeconfig.IsGenericFederatedType(typeConfig) {
+		return nil
+	}
+
+	viewRules := []rbacv1.PolicyRule{{
+		APIGroups: []string{federatedType.Group},
+		Resources: []string{federatedType.Name, federatedType.Name + "/status"},
+		Verbs:     []string{"get", "list", "watch"},
+	}}
+	if statusType := typeConfig.GetStatusType(); statusType != nil {
+		viewRules = append(viewRules, rbacv1.PolicyRule{
+			APIGroups: []string{statusType.Group},
+			Resources: []string{statusType.Name},
+			Verbs:     []string{"get", "list", "watch"},
+		})
+	}
+
+	names := aggregatedClusterRoleNames(federatedType)
+	crdName := typeconfig.GroupQualifiedName(federatedType)
+	return []*rbacv1.ClusterRole{
+		aggregatedClusterRole(names[0], crdName, viewRules, aggregateToViewLabel, aggregateToEditLabel, aggregateToAdminLabel),
+		aggregatedClusterRole(names[1], crdName, []rbacv1.PolicyRule{{
+			APIGroups: []string{federatedType.Group},
+			Resources: []string{federatedType.Name},
+			Verbs:     []string{"create", "update", "patch", "delete", "deletecollection"},
+		}}, aggregateToEditLabel, aggregateToAdminLabel),
+	}
+}
+
+func aggregatedClusterRole(name, crdName string, rules []rbacv1.PolicyRule, aggregateLabels ...string) *rbacv1.ClusterRole {
+	labels := map[string]string{federatedTypeLabel: crdName}
+	for _, label := range aggregateLabels {
+		labels[label] = "true"
+	}
+	return &rbacv1.ClusterRole{
+		// Explicitly including TypeMeta will ensure it will be
+		// serialized properly to yaml.
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ClusterRole",
+			APIVersion: "rbac.authorization.k8s.io/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: labels,
+		},
+		Rules: rules,
+	}
+}
+
+func createOrUpdateClusterRole(config *rest.Config, role *rbacv1.ClusterRole, write func(string)) error {
+	client, err := util.HostClientset(config)
+	if err != nil {
+		return errors.Wrap(err, "Failed to create host clientset")
+	}
+
+	existingRole, err := client.RbacV1().ClusterRoles().Get(role.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = client.RbacV1().ClusterRoles().Create(role)
+		if err != nil {
+			return errors.Wrapf(err, "Error creating ClusterRole %q", role.Name)
+		}
+		write(fmt.Sprintf("clusterrole.rbac.authorization.k8s.io/%s created\n", role.Name))
+	} else if err != nil {
+		return errors.Wrapf(err, "Error getting ClusterRole %q", role.Name)
+	} else {
+		existingRole.Labels = role.Labels
+		existingRole.Rules = role.Rules
+		_, err = client.RbacV1().ClusterRoles().Update(existingRole)
+		if err != nil {
+			return errors.Wrapf(err, "Error updating ClusterRole %q", role.Name)
+		}
+		write(fmt.Sprintf("clusterrole.rbac.authorization.k8s.io/%s updated\n", role.Name))
+	}
+	return nil
+}
+
+// DeleteAggregatedClusterRoles deletes the ClusterRoles aggregating the
+// permissions on the given federated type, if they exist.
+func DeleteAggregatedClusterRoles(config *rest.Config, federatedType metav1.APIResource, write func(string)) error {
+	client, err := util.HostClientset(config)
+	if err != nil {
+		return errors.Wrap(err, "Failed to create host clientset")
+	}
+
+	for _, name := range aggregatedClusterRoleNames(federatedType) {
+		err := client.RbacV1().ClusterRoles().Delete(name, nil)
+		if apierrors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return errors.Wrapf(err, "Error deleting ClusterRole %q", name)
+		}
+		write(fmt.Sprintf("clusterrole %q deleted\n", name))
+	}
+	return nil
+}
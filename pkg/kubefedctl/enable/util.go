@@ -17,6 +17,7 @@ limitations under the License.
 package enable
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -33,6 +34,8 @@ import (
 
 	"sigs.k8s.io/kubefed/pkg/apis/core/common"
 	"sigs.k8s.io/kubefed/pkg/apis/core/typeconfig"
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
 )
 
 func DecodeYAMLFromFile(filename string, obj interface{}) error {
@@ -197,3 +200,27 @@ func groupQualifiedName(name, group string) string {
 
 	return typeconfig.GroupQualifiedName(apiResource)
 }
+
+// LookupTypeConfig returns the FederatedTypeConfig whose federated or
+// target type is identified by the given name.
+func LookupTypeConfig(hostConfig *rest.Config, client genericclient.Client, typeName, kubefedNamespace string) (typeconfig.Interface, error) {
+	apiResource, err := LookupAPIResource(hostConfig, typeName, "")
+	if err != nil {
+		return nil, err
+	}
+	typeConfigList := &fedv1b1.FederatedTypeConfigList{}
+	err = client.List(context.TODO(), typeConfigList, kubefedNamespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list FederatedTypeConfigs")
+	}
+	for i := range typeConfigList.Items {
+		typeConfig := &typeConfigList.Items[i]
+		federatedType := typeConfig.GetFederatedType()
+		targetType := typeConfig.GetTargetType()
+		if (federatedType.Group == apiResource.Group && federatedType.Kind == apiResource.Kind) ||
+			(targetType.Group == apiResource.Group && targetType.Kind == apiResource.Kind) {
+			return typeConfig, nil
+		}
+	}
+	return nil, errors.Errorf("Federation of type %q is not enabled. Consider using 'kubefedctl enable %s'", typeName, typeName)
+}
@@ -23,6 +23,7 @@ import (
 )
 
 func federatedTypeValidationSchema(templateSchema map[string]v1beta1.JSONSchemaProps) *v1beta1.CustomResourceValidation {
+	nonNegative := float64(0)
 	schema := ValidationSchema(v1beta1.JSONSchemaProps{
 		Type: "object",
 		Properties: map[string]v1beta1.JSONSchemaProps{
@@ -80,6 +81,34 @@ func federatedTypeValidationSchema(templateSchema map[string]v1beta1.JSONSchemaP
 									},
 								},
 							},
+							"matchFields": {
+								Type: "array",
+								Items: &v1beta1.JSONSchemaPropsOrArray{
+									Schema: &v1beta1.JSONSchemaProps{
+										Type: "object",
+										Properties: map[string]v1beta1.JSONSchemaProps{
+											"key": {
+												Type: "string",
+											},
+											"operator": {
+												Type: "string",
+											},
+											"values": {
+												Type: "array",
+												Items: &v1beta1.JSONSchemaPropsOrArray{
+													Schema: &v1beta1.JSONSchemaProps{
+														Type: "string",
+													},
+												},
+											},
+										},
+										Required: []string{
+											"key",
+											"operator",
+										},
+									},
+								},
+							},
 							"matchLabels": {
 								Type: "object",
 								AdditionalProperties: &v1beta1.JSONSchemaPropsOrBool{
@@ -90,6 +119,21 @@ func federatedTypeValidationSchema(templateSchema map[string]v1beta1.JSONSchemaP
 							},
 						},
 					},
+					// The minimum number of ready clusters the placement
+					// must select. Resources are not removed from clusters
+					// while fewer are selected.
+					"minClusters": {
+						Type:    "integer",
+						Format:  "int32",
+						Minimum: &nonNegative,
+					},
+					// The maximum number of clusters the resource is
+					// propagated to.
+					"maxClusters": {
+						Type:    "integer",
+						Format:  "int32",
+						Minimum: &nonNegative,
+					},
 				},
 			},
 			"overrides": {
@@ -133,6 +177,47 @@ func federatedTypeValidationSchema(templateSchema map[string]v1beta1.JSONSchemaP
 													},
 												},
 											},
+											// A string value resolved from a
+											// ConfigMap or Secret at propagation.
+											"valueFrom": {
+												Type: "object",
+												Properties: map[string]v1beta1.JSONSchemaProps{
+													"configMapKeyRef":      keySelectorSchema(true),
+													"secretKeyRef":         keySelectorSchema(true),
+													"clusterProfileKeyRef": keySelectorSchema(false),
+												},
+											},
+										},
+									},
+								},
+							},
+							// A values file merged into the chart
+							// values of the target resource.
+							"values": {
+								Type: "string",
+							},
+							// Strategic merge, JSON merge or JSON
+							// patches of the target resource.
+							"patches": {
+								Type: "array",
+								Items: &v1beta1.JSONSchemaPropsOrArray{
+									Schema: &v1beta1.JSONSchemaProps{
+										Type: "object",
+										Properties: map[string]v1beta1.JSONSchemaProps{
+											"type": {
+												Type: "string",
+												Enum: []v1beta1.JSON{
+													{Raw: []byte(`"StrategicMerge"`)},
+													{Raw: []byte(`"Merge"`)},
+													{Raw: []byte(`"JSON"`)},
+												},
+											},
+											"patch": {
+												Type: "string",
+											},
+										},
+										Required: []string{
+											"patch",
 										},
 									},
 								},
@@ -141,6 +226,12 @@ func federatedTypeValidationSchema(templateSchema map[string]v1beta1.JSONSchemaP
 					},
 				},
 			},
+			// The gzipped and base64-encoded json of a template too
+			// large to be stored uncompressed.  Mutually exclusive
+			// with the template field.
+			util.CompressedTemplateField: {
+				Type: "string",
+			},
 		},
 	})
 	if templateSchema != nil {
@@ -198,6 +289,9 @@ func ValidationSchema(specProps v1beta1.JSONSchemaProps) *v1beta1.CustomResource
 										"reason": {
 											Type: "string",
 										},
+										"message": {
+											Type: "string",
+										},
 										"lastProbeTime": {
 											Format: "date-time",
 											Type:   "string",
@@ -226,6 +320,44 @@ func ValidationSchema(specProps v1beta1.JSONSchemaProps) *v1beta1.CustomResource
 										"status": {
 											Type: "string",
 										},
+										"remoteStatus": {
+											Type: "object",
+										},
+										"observedVersion": {
+											Type: "string",
+										},
+										"lastAppliedTime": {
+											Format: "date-time",
+											Type:   "string",
+										},
+										"apiVersion": {
+											Type: "string",
+										},
+										"drift": {
+											Type: "object",
+											Properties: map[string]v1beta1.JSONSchemaProps{
+												"changedPaths": {
+													Type: "array",
+													Items: &v1beta1.JSONSchemaPropsOrArray{
+														Schema: &v1beta1.JSONSchemaProps{
+															Type: "string",
+														},
+													},
+												},
+												"managers": {
+													Type: "array",
+													Items: &v1beta1.JSONSchemaPropsOrArray{
+														Schema: &v1beta1.JSONSchemaProps{
+															Type: "string",
+														},
+													},
+												},
+												"detectedTime": {
+													Format: "date-time",
+													Type:   "string",
+												},
+											},
+										},
 									},
 									Required: []string{
 										"name",
@@ -239,3 +371,150 @@ func ValidationSchema(specProps v1beta1.JSONSchemaProps) *v1beta1.CustomResource
 		},
 	}
 }
+
+// statusTypeValidationSchema returns the schema of a generated status
+// type, which records the status of a target resource in each member
+// cluster.
+func statusTypeValidationSchema() *v1beta1.CustomResourceValidation {
+	return &v1beta1.CustomResourceValidation{
+		OpenAPIV3Schema: &v1beta1.JSONSchemaProps{
+			Properties: map[string]v1beta1.JSONSchemaProps{
+				"apiVersion": {
+					Type: "string",
+				},
+				"kind": {
+					Type: "string",
+				},
+				"metadata": {
+					Type: "object",
+				},
+				"clusterStatus": {
+					Type: "array",
+					Items: &v1beta1.JSONSchemaPropsOrArray{
+						Schema: &v1beta1.JSONSchemaProps{
+							Type: "object",
+							Properties: map[string]v1beta1.JSONSchemaProps{
+								"clusterName": {
+									Type: "string",
+								},
+								"status": {
+									Type: "object",
+								},
+							},
+							Required: []string{
+								"clusterName",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// addLoadBalancerStatus adds the load balancer status recorded for
+// federated services to the status of the given schema.
+func addLoadBalancerStatus(validation *v1beta1.CustomResourceValidation) {
+	lbStatus := v1beta1.JSONSchemaProps{
+		Type: "object",
+		Properties: map[string]v1beta1.JSONSchemaProps{
+			"ingress": {
+				Type: "array",
+				Items: &v1beta1.JSONSchemaPropsOrArray{
+					Schema: &v1beta1.JSONSchemaProps{
+						Type: "object",
+						Properties: map[string]v1beta1.JSONSchemaProps{
+							"ip": {
+								Type: "string",
+							},
+							"hostname": {
+								Type: "string",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	status := validation.OpenAPIV3Schema.Properties["status"]
+	status.Properties["loadBalancer"] = lbStatus
+	status.Properties["clusters"].Items.Schema.Properties["loadBalancer"] = lbStatus
+}
+
+// disallowSecretValueSources rejects override values sourced from
+// Secrets in the given schema.  The sync controller reads Secrets with
+// its own credentials, so their values may only be written into target
+// resources that are themselves Secrets.
+func disallowSecretValueSources(validation *v1beta1.CustomResourceValidation) {
+	overrides := validation.OpenAPIV3Schema.Properties["spec"].Properties["overrides"]
+	clusterOverrides := overrides.Items.Schema.Properties["clusterOverrides"]
+	valueFrom := clusterOverrides.Items.Schema.Properties["valueFrom"]
+	valueFrom.Not = &v1beta1.JSONSchemaProps{
+		Required: []string{
+			"secretKeyRef",
+		},
+	}
+	clusterOverrides.Items.Schema.Properties["valueFrom"] = valueFrom
+}
+
+// addServiceImportStatus adds the Lighthouse service imports recorded
+// for federated services to the status of the given schema.
+func addServiceImportStatus(validation *v1beta1.CustomResourceValidation) {
+	stringArray := v1beta1.JSONSchemaProps{
+		Type: "array",
+		Items: &v1beta1.JSONSchemaPropsOrArray{
+			Schema: &v1beta1.JSONSchemaProps{
+				Type: "string",
+			},
+		},
+	}
+	status := validation.OpenAPIV3Schema.Properties["status"]
+	status.Properties["serviceImports"] = v1beta1.JSONSchemaProps{
+		Type: "array",
+		Items: &v1beta1.JSONSchemaPropsOrArray{
+			Schema: &v1beta1.JSONSchemaProps{
+				Type: "object",
+				Properties: map[string]v1beta1.JSONSchemaProps{
+					"cluster": {
+						Type: "string",
+					},
+					"type": {
+						Type: "string",
+					},
+					"ips":               stringArray,
+					"exportingClusters": stringArray,
+				},
+				Required: []string{
+					"cluster",
+				},
+			},
+		},
+	}
+}
+
+// keySelectorSchema returns the schema of a selector of a key of a
+// ConfigMap or Secret, which is named unless it is selected from the
+// profile of a cluster.
+func keySelectorSchema(named bool) v1beta1.JSONSchemaProps {
+	selector := v1beta1.JSONSchemaProps{
+		Type: "object",
+		Properties: map[string]v1beta1.JSONSchemaProps{
+			"key": {
+				Type: "string",
+			},
+			"optional": {
+				Type: "boolean",
+			},
+		},
+		Required: []string{
+			"key",
+		},
+	}
+	if named {
+		selector.Properties["name"] = v1beta1.JSONSchemaProps{
+			Type: "string",
+		}
+		selector.Required = append(selector.Required, "name")
+	}
+	return selector
+}
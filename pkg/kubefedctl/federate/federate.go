@@ -86,6 +86,7 @@ type federateResource struct {
 	federateContents     bool
 	filename             string
 	skipAPIResourceNames []string
+	compressTemplate     bool
 }
 
 func (j *federateResource) Bind(flags *pflag.FlagSet) {
@@ -96,6 +97,7 @@ func (j *federateResource) Bind(flags *pflag.FlagSet) {
 	flags.StringVarP(&j.filename, "filename", "f", "", "If specified, the provided yaml file will be used as the input for target resources to federate. This mode will only emit federated resource yaml to standard output. Other flag options if provided will be ignored.")
 	flags.StringSliceVarP(&j.skipAPIResourceNames, "skip-api-resources", "s", []string{}, "Comma separated names of the api resources to skip when federating contents in a namespace. Name could be short name "+
 		"(e.g. 'deploy), kind (e.g. 'deployment'), plural name (e.g. 'deployments'), group qualified plural name (e.g. 'deployments.apps') or group name itself (e.g. 'apps') to skip the whole group.")
+	flags.BoolVar(&j.compressTemplate, "compress-template", false, "If true, the template of each federated resource is stored gzip-compressed in spec.compressedTemplate to allow federating resources that would otherwise exceed the size limit of the API server.")
 }
 
 // Complete ensures that options are valid.
@@ -176,6 +178,11 @@ func (j *federateResource) Run(cmdOut io.Writer, config util.FedConfig) error {
 		if err != nil {
 			return err
 		}
+		if j.compressTemplate {
+			if err := compressTemplates(federatedResources); err != nil {
+				return err
+			}
+		}
 
 		err = WriteUnstructuredObjsToYaml(federatedResources, cmdOut)
 		if err != nil {
@@ -209,6 +216,14 @@ func (j *federateResource) Run(cmdOut io.Writer, config util.FedConfig) error {
 		artifactsList = append(artifactsList, containedArtifactsList...)
 	}
 
+	if j.compressTemplate {
+		for _, artifacts := range artifactsList {
+			if err := compressTemplates(artifacts.federatedResources); err != nil {
+				return err
+			}
+		}
+	}
+
 	if j.outputYAML {
 		for _, artifacts := range artifactsList {
 			err := WriteUnstructuredObjsToYaml(artifacts.federatedResources, cmdOut)
@@ -222,6 +237,15 @@ func (j *federateResource) Run(cmdOut io.Writer, config util.FedConfig) error {
 	return CreateResources(cmdOut, hostConfig, artifactsList, j.KubeFedNamespace, j.enableType, j.DryRun)
 }
 
+func compressTemplates(federatedResources []*unstructured.Unstructured) error {
+	for _, federatedResource := range federatedResources {
+		if err := ctlutil.CompressTemplate(federatedResource); err != nil {
+			return errors.Wrapf(err, "Failed to compress the template of %s %q", federatedResource.GetKind(), ctlutil.NewQualifiedName(federatedResource))
+		}
+	}
+	return nil
+}
+
 func FederateResources(resources []*unstructured.Unstructured) ([]*unstructured.Unstructured, error) {
 	var federatedResources []*unstructured.Unstructured
 	for _, targetResource := range resources {
@@ -243,7 +267,8 @@ func FederateResources(resources []*unstructured.Unstructured) ([]*unstructured.
 
 		qualifiedName := ctlutil.NewQualifiedName(targetResource)
 		typeConfig := enable.GenerateTypeConfigForTarget(apiResource, enable.NewEnableTypeDirective())
-		federatedResource, err := FederatedResourceFromTargetResource(typeConfig, targetResource)
+		warnTargetResource(typeConfig, targetResource, false)
+		federatedResource, err := ctlutil.FederatedResourceFromTargetResource(typeConfig, targetResource)
 		if err != nil {
 			return nil, errors.Wrapf(err, "Error getting %s from %s %q", typeConfig.GetFederatedType().Kind, typeConfig.GetTargetType().Kind, qualifiedName)
 		}
@@ -281,8 +306,10 @@ func GetFederateArtifacts(hostConfig *rest.Config, typeName, kubefedNamespace st
 	if err != nil {
 		return nil, err
 	}
+	warnTargetType(typeConfig)
+	warnTargetResource(typeConfig, targetResource, true)
 
-	federatedResource, err := FederatedResourceFromTargetResource(typeConfig, targetResource)
+	federatedResource, err := ctlutil.FederatedResourceFromTargetResource(typeConfig, targetResource)
 	if err != nil {
 		return nil, errors.Wrapf(err, "Error getting %s from %s %q", typeConfig.GetFederatedType().Kind, typeConfig.GetTargetType().Kind, qualifiedName)
 	}
@@ -349,67 +376,6 @@ func getTargetResource(hostConfig *rest.Config, typeConfig typeconfig.Interface,
 	return resource, nil
 }
 
-func FederatedResourceFromTargetResource(typeConfig typeconfig.Interface, resource *unstructured.Unstructured) (*unstructured.Unstructured, error) {
-	fedAPIResource := typeConfig.GetFederatedType()
-	targetResource := resource.DeepCopy()
-
-	// Special handling is needed for some controller set fields.
-	switch typeConfig.GetTargetType().Kind {
-	case ctlutil.NamespaceKind:
-		{
-			unstructured.RemoveNestedField(targetResource.Object, "spec", "finalizers")
-		}
-	case ctlutil.ServiceAccountKind:
-		{
-			unstructured.RemoveNestedField(targetResource.Object, ctlutil.SecretsField)
-		}
-	case ctlutil.ServiceKind:
-		{
-			var targetPorts []interface{}
-			targetPorts, ok, err := unstructured.NestedSlice(targetResource.Object, "spec", "ports")
-			if err != nil {
-				return nil, err
-			}
-			if ok {
-				for index := range targetPorts {
-					port := targetPorts[index].(map[string]interface{})
-					delete(port, "nodePort")
-					targetPorts[index] = port
-				}
-				err := unstructured.SetNestedSlice(targetResource.Object, targetPorts, "spec", "ports")
-				if err != nil {
-					return nil, err
-				}
-			}
-			unstructured.RemoveNestedField(targetResource.Object, "spec", "clusterIP")
-		}
-	}
-
-	qualifiedName := ctlutil.NewQualifiedName(targetResource)
-	resourceNamespace := getNamespace(typeConfig, qualifiedName)
-	fedResource := &unstructured.Unstructured{}
-	SetBasicMetaFields(fedResource, fedAPIResource, qualifiedName.Name, resourceNamespace, "")
-	RemoveUnwantedFields(targetResource)
-
-	err := unstructured.SetNestedField(fedResource.Object, targetResource.Object, ctlutil.SpecField, ctlutil.TemplateField)
-	if err != nil {
-		return nil, err
-	}
-	err = unstructured.SetNestedStringMap(fedResource.Object, map[string]string{}, ctlutil.SpecField, ctlutil.PlacementField, ctlutil.ClusterSelectorField, ctlutil.MatchLabelsField)
-	if err != nil {
-		return nil, err
-	}
-
-	return fedResource, err
-}
-
-func getNamespace(typeConfig typeconfig.Interface, qualifiedName ctlutil.QualifiedName) string {
-	if typeConfig.GetTargetType().Kind == ctlutil.NamespaceKind {
-		return qualifiedName.Name
-	}
-	return qualifiedName.Namespace
-}
-
 func CreateResources(cmdOut io.Writer, hostConfig *rest.Config, artifactsList []*FederateArtifacts, namespace string, enableType, dryRun bool) error {
 	for _, artifacts := range artifactsList {
 		if enableType && !artifacts.typeConfigInstalled {
@@ -494,9 +460,19 @@ func GetContainedArtifactsList(hostConfig *rest.Config, containerNamespace, kube
 		if err != nil {
 			return nil, err
 		}
+		if len(targetResources.resources) > 0 {
+			warnTargetType(typeConfig)
+		}
 		var federatedResources []*unstructured.Unstructured
 		for _, targetResource := range targetResources.resources {
-			federatedResource, err := FederatedResourceFromTargetResource(typeConfig, targetResource)
+			if owner := ctlutil.SealedSecretOwner(targetResource); owner != nil {
+				// The SealedSecret is federated with the other
+				// contents of the namespace if its type is enabled.
+				klog.Warningf("Skipping %s %q decrypted from %s %q", targetResource.GetKind(), ctlutil.NewQualifiedName(targetResource), owner.Kind, owner.Name)
+				continue
+			}
+			warnTargetResource(typeConfig, targetResource, true)
+			federatedResource, err := ctlutil.FederatedResourceFromTargetResource(typeConfig, targetResource)
 			if err != nil {
 				return nil, err
 			}
@@ -0,0 +1,187 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package federate
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog"
+
+	"sigs.k8s.io/kubefed/pkg/apis/core/typeconfig"
+	ctlutil "sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/enable"
+)
+
+const lastAppliedConfigAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+var (
+	// Annotations that controllers of the host cluster set on the
+	// resources they manage and that the same controllers of member
+	// clusters will manage in turn.
+	controllerAnnotations = []string{
+		"deployment.kubernetes.io/revision",
+		"pv.kubernetes.io/bind-completed",
+		"pv.kubernetes.io/bound-by-controller",
+		"volume.beta.kubernetes.io/storage-provisioner",
+		"volume.kubernetes.io/storage-provisioner",
+		"volume.kubernetes.io/selected-node",
+	}
+
+	// Labels that the job controller adds to the pod template of a
+	// Job along with its generated selector.
+	jobControllerLabels = []string{
+		"controller-uid",
+		"job-name",
+		"batch.kubernetes.io/controller-uid",
+		"batch.kubernetes.io/job-name",
+	}
+
+	// Group-qualified kinds of the common cluster-scoped types, used
+	// to tell whether a resource read from a file is missing its
+	// namespace without access to API discovery.
+	clusterScopedKinds = sets.NewString(
+		"Namespace",
+		"Node",
+		"PersistentVolume",
+		"ClusterRole.rbac.authorization.k8s.io",
+		"ClusterRoleBinding.rbac.authorization.k8s.io",
+		"CustomResourceDefinition.apiextensions.k8s.io",
+		"StorageClass.storage.k8s.io",
+		"PriorityClass.scheduling.k8s.io",
+		"PodSecurityPolicy.policy",
+		"MutatingWebhookConfiguration.admissionregistration.k8s.io",
+		"ValidatingWebhookConfiguration.admissionregistration.k8s.io",
+	)
+)
+
+// LintTargetResource returns warnings about the fields of a target
+// resource that commonly prevent the federated resource created from
+// it from propagating: fields generated in the host cluster that are
+// immutable, fields that controllers of member clusters will manage
+// and a missing namespace.  If the scope of the target type is not
+// known, as for resources read from a file, a resource without a
+// namespace is reported unless its kind is commonly cluster-scoped.
+func LintTargetResource(typeConfig typeconfig.Interface, resource *unstructured.Unstructured, scopeKnown bool) []string {
+	var warnings []string
+	warn := func(format string, args ...interface{}) {
+		warnings = append(warnings, fmt.Sprintf(format, args...))
+	}
+
+	if len(resource.GetNamespace()) == 0 {
+		if scopeKnown && typeConfig.GetNamespaced() {
+			warn("metadata.namespace is not set; set it to the namespace the resource should be propagated to")
+		} else if !scopeKnown && !clusterScopedKinds.Has(resource.GroupVersionKind().GroupKind().String()) {
+			warn("metadata.namespace is not set; if the type is namespaced, set it to the namespace the resource should be propagated to")
+		}
+	}
+
+	if len(resource.GetOwnerReferences()) > 0 {
+		warn("metadata.ownerReferences refers to objects of the host cluster that do not exist in member clusters, which may cause the propagated resource to be garbage collected; remove the field or federate the owner instead")
+	}
+	if len(resource.GetFinalizers()) > 0 {
+		warn("metadata.finalizers are removed by controllers of the host cluster and may block deletion in member clusters; remove the field")
+	}
+	annotations := resource.GetAnnotations()
+	if _, ok := annotations[lastAppliedConfigAnnotation]; ok {
+		warn("annotation %q records the configuration applied to the host cluster; remove it so that it is not propagated", lastAppliedConfigAnnotation)
+	}
+	for _, annotation := range controllerAnnotations {
+		if _, ok := annotations[annotation]; ok {
+			warn("annotation %q is set by controllers of the host cluster and will be managed by controllers of member clusters; remove it", annotation)
+		}
+	}
+
+	targetType := typeConfig.GetTargetType()
+	switch typeconfig.GroupQualifiedName(targetType) {
+	case "jobs.batch":
+		warnings = append(warnings, lintJob(resource)...)
+	case "persistentvolumeclaims":
+		if hasNestedField(resource, "spec", "volumeName") {
+			warn("spec.volumeName binds the claim to a PersistentVolume of the host cluster and is immutable; remove it so that the claim is bound in each member cluster")
+		}
+	case "pods":
+		if hasNestedField(resource, "spec", "nodeName") {
+			warn("spec.nodeName names a node of the host cluster; remove it so that the pod is scheduled in each member cluster")
+		}
+	case "services":
+		if hasNestedField(resource, "spec", "clusterIPs") {
+			warn("spec.clusterIPs were allocated in the host cluster and are immutable; remove the field so that they are allocated in each member cluster")
+		}
+		if hasNestedField(resource, "spec", "healthCheckNodePort") {
+			warn("spec.healthCheckNodePort was allocated in the host cluster; remove it so that it is allocated in each member cluster")
+		}
+	case "secrets":
+		secretType, _, _ := unstructured.NestedString(resource.Object, "type")
+		if secretType == "kubernetes.io/service-account-token" {
+			warn("the token of the Secret is generated by the host cluster and is not valid in member clusters; federate the ServiceAccount instead")
+		}
+	}
+	if targetType.Kind == ctlutil.ConfigMapKind || targetType.Kind == ctlutil.SecretKind {
+		if immutable, _, _ := unstructured.NestedBool(resource.Object, "immutable"); immutable {
+			warn("the resource is immutable, so changes to the template will fail to propagate to clusters it was already propagated to; propagate a resource with a different name instead of changing it")
+		}
+	}
+
+	for _, path := range typeConfig.GetLocallyManagedFields() {
+		fields := strings.Split(strings.TrimPrefix(path, "."), ".")
+		if hasNestedField(resource, fields...) {
+			warn("%s is managed by controllers of member clusters according to the type config, so its value in the template is only used when the resource is created", path)
+		}
+	}
+
+	return warnings
+}
+
+func lintJob(resource *unstructured.Unstructured) []string {
+	manualSelector, _, _ := unstructured.NestedBool(resource.Object, "spec", "manualSelector")
+	if manualSelector || !hasNestedField(resource, "spec", "selector") {
+		return nil
+	}
+	fields := []string{"spec.selector"}
+	labels, _, _ := unstructured.NestedStringMap(resource.Object, "spec", "template", "metadata", "labels")
+	for _, label := range jobControllerLabels {
+		if _, ok := labels[label]; ok {
+			fields = append(fields, fmt.Sprintf("label %q of spec.template", label))
+		}
+	}
+	return []string{fmt.Sprintf("the fields generated by the job controller of the host cluster (%s) are immutable and will not match the Job in member clusters; remove them so that they are generated in each member cluster",
+		strings.Join(fields, ", "))}
+}
+
+func hasNestedField(resource *unstructured.Unstructured, fields ...string) bool {
+	_, ok, _ := unstructured.NestedFieldNoCopy(resource.Object, fields...)
+	return ok
+}
+
+// warnTargetResource logs the warnings about the given target resource
+// returned by LintTargetResource.
+func warnTargetResource(typeConfig typeconfig.Interface, resource *unstructured.Unstructured, scopeKnown bool) {
+	for _, warning := range LintTargetResource(typeConfig, resource, scopeKnown) {
+		klog.Warningf("%s %q: %s", resource.GetKind(), ctlutil.NewQualifiedName(resource), warning)
+	}
+}
+
+// warnTargetType logs the warnings about the target type of the given
+// type config returned by enable.LintTargetType.
+func warnTargetType(typeConfig typeconfig.Interface) {
+	for _, warning := range enable.LintTargetType(typeConfig.GetTargetType()) {
+		klog.Warning(warning)
+	}
+}
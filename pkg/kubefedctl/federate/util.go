@@ -39,34 +39,6 @@ import (
 	"sigs.k8s.io/kubefed/pkg/kubefedctl/options"
 )
 
-var systemMetadataFields = []string{"selfLink", "uid", "resourceVersion", "generation", "creationTimestamp", "deletionTimestamp", "deletionGracePeriodSeconds"}
-
-func RemoveUnwantedFields(resource *unstructured.Unstructured) {
-	for _, field := range systemMetadataFields {
-		unstructured.RemoveNestedField(resource.Object, "metadata", field)
-		// For resources with pod template subresource (jobs, deployments, replicasets)
-		unstructured.RemoveNestedField(resource.Object, "spec", "template", "metadata", field)
-	}
-	unstructured.RemoveNestedField(resource.Object, "metadata", "name")
-	unstructured.RemoveNestedField(resource.Object, "metadata", "namespace")
-	unstructured.RemoveNestedField(resource.Object, "apiVersion")
-	unstructured.RemoveNestedField(resource.Object, "kind")
-	unstructured.RemoveNestedField(resource.Object, "status")
-}
-
-func SetBasicMetaFields(resource *unstructured.Unstructured, apiResource metav1.APIResource, name, namespace, generateName string) {
-	resource.SetKind(apiResource.Kind)
-	gv := schema.GroupVersion{Group: apiResource.Group, Version: apiResource.Version}
-	resource.SetAPIVersion(gv.String())
-	resource.SetName(name)
-	if generateName != "" {
-		resource.SetGenerateName(generateName)
-	}
-	if apiResource.Namespaced {
-		resource.SetNamespace(namespace)
-	}
-}
-
 func namespacedAPIResourceMap(config *rest.Config, skipAPIResourceNames []string) (map[string]metav1.APIResource, error) {
 	apiResourceLists, err := enable.GetServerPreferredResources(config)
 	if err != nil {
@@ -0,0 +1,419 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package get
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog"
+	"sigs.k8s.io/yaml"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	ctlutil "sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/enable"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/options"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/util"
+	"sigs.k8s.io/kubefed/pkg/views"
+)
+
+const (
+	allClusters = "all"
+
+	tableOutput = "table"
+	yamlOutput  = "yaml"
+	jsonOutput  = "json"
+)
+
+var (
+	get_long = `
+		Get retrieves the resources of a federated type from member
+		clusters using the credentials of the joined clusters, saving
+		the need for a kubeconfig context per member cluster. The
+		resources of all clusters are printed as a single table or,
+		with --output=yaml|json, as a single list whose items are
+		annotated with the cluster they were retrieved from.
+
+		TYPE is the federated type (e.g. federateddeployment) or its
+		target type (e.g. deployment). If NAME is given, the resource
+		propagated for the federated resource of that name is
+		retrieved, accounting for renaming overrides and namespace
+		mappings. Otherwise all resources of the type in the namespace
+		are listed. All joined clusters are queried unless --clusters
+		is provided.
+
+		Current context is assumed to be a Kubernetes cluster hosting
+		the kubefed control plane. Please use the --host-cluster-context
+		flag otherwise.`
+
+	get_example = `
+		# Get the deployment "foo" propagated to all member clusters
+		kubefedctl get deployment foo -n my-ns --host-cluster-context=cluster1
+
+		# Get the deployments of namespace my-ns in cluster2 and cluster3 as yaml
+		kubefedctl get deployment -n my-ns --clusters=cluster2,cluster3 -o yaml --host-cluster-context=cluster1`
+)
+
+type getOptions struct {
+	options.GlobalSubcommandOptions
+	typeName     string
+	resourceName string
+	namespace    string
+	clusters     []string
+	output       string
+}
+
+// Bind adds the get specific arguments to the flagset passed in as an
+// argument.
+func (o *getOptions) Bind(flags *pflag.FlagSet) {
+	flags.StringVarP(&o.namespace, "namespace", "n", "default", "The namespace of the resources.")
+	flags.StringSliceVar(&o.clusters, "clusters", []string{allClusters},
+		"Comma separated names of the clusters to query, or 'all' for all joined clusters.")
+	flags.StringVarP(&o.output, "output", "o", tableOutput, "The output format. One of: table|yaml|json.")
+}
+
+// Complete ensures that options are valid.
+func (o *getOptions) Complete(args []string) error {
+	if len(args) == 0 || len(args) > 2 {
+		return errors.New("TYPE and optionally NAME are required")
+	}
+	o.typeName = args[0]
+	if len(args) == 2 {
+		o.resourceName = args[1]
+	}
+	if len(o.clusters) == 0 {
+		return errors.New("--clusters must not be empty")
+	}
+	if len(o.clusters) > 1 && sets.NewString(o.clusters...).Has(allClusters) {
+		return errors.Errorf("--clusters=%s may not be combined with cluster names", allClusters)
+	}
+	if !sets.NewString(tableOutput, yamlOutput, jsonOutput).Has(o.output) {
+		return errors.Errorf("Invalid output format %q. Must be one of: table|yaml|json", o.output)
+	}
+	return nil
+}
+
+// NewCmdGet defines the `get` command that retrieves the resources
+// of a federated type from member clusters.
+func NewCmdGet(cmdOut io.Writer, config util.FedConfig) *cobra.Command {
+	opts := &getOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "get TYPE [NAME]",
+		Short:   "Get the resources of a federated type from member clusters",
+		Long:    get_long,
+		Example: get_example,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := opts.Complete(args)
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+
+			err = opts.Run(cmdOut, config)
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+		},
+	}
+
+	flags := cmd.Flags()
+	opts.GlobalSubcommandBind(flags)
+	opts.Bind(flags)
+
+	return cmd
+}
+
+// clusterResult is the outcome of retrieving resources from a member
+// cluster.
+type clusterResult struct {
+	clusterName string
+	objects     []unstructured.Unstructured
+	err         error
+}
+
+// Run is the implementation of the `get` command.
+func (o *getOptions) Run(cmdOut io.Writer, config util.FedConfig) error {
+	hostConfig, err := config.HostConfig(o.HostClusterContext, o.Kubeconfig)
+	if err != nil {
+		return errors.Wrap(err, "Failed to get host cluster config")
+	}
+	client, err := genericclient.New(hostConfig)
+	if err != nil {
+		return errors.Wrap(err, "Failed to get kubefed clientset")
+	}
+
+	typeConfig, err := enable.LookupTypeConfig(hostConfig, client, o.typeName, o.KubeFedNamespace)
+	if err != nil {
+		return err
+	}
+	clusters, err := o.getClusters(client)
+	if err != nil {
+		return err
+	}
+
+	// The federated resource is only needed to determine the name of
+	// the propagated resource in each cluster. Resources that are not
+	// (or no longer) federated are still retrieved by their name.
+	var overrides ctlutil.OverridesMap
+	targetType := typeConfig.GetTargetType()
+	targetIsNamespace := targetType.Kind == ctlutil.NamespaceKind
+	qualifiedName := ctlutil.QualifiedName{Namespace: o.namespace, Name: o.resourceName}
+	switch {
+	case targetIsNamespace:
+		qualifiedName.Namespace = o.resourceName
+	case !targetType.Namespaced:
+		qualifiedName.Namespace = ""
+	}
+	if len(o.resourceName) > 0 {
+		federatedType := typeConfig.GetFederatedType()
+		fedObj := &unstructured.Unstructured{}
+		fedObj.SetAPIVersion(fmt.Sprintf("%s/%s", federatedType.Group, federatedType.Version))
+		fedObj.SetKind(federatedType.Kind)
+		err = client.Get(context.TODO(), fedObj, qualifiedName.Namespace, o.resourceName)
+		switch {
+		case apierrors.IsNotFound(err):
+			klog.V(2).Infof("%s %q not found, retrieving %s by name", federatedType.Kind, qualifiedName, targetType.Kind)
+		case err != nil:
+			return errors.Wrapf(err, "Failed to retrieve %s %q", federatedType.Kind, qualifiedName)
+		default:
+			overrides, err = ctlutil.GetOverrides(fedObj)
+			if err != nil {
+				return errors.Wrapf(err, "Failed to read the overrides of %s %q", federatedType.Kind, qualifiedName)
+			}
+		}
+	}
+
+	results := make([]clusterResult, len(clusters))
+	var wg sync.WaitGroup
+	for i, cluster := range clusters {
+		wg.Add(1)
+		go func(i int, cluster *fedv1b1.KubeFedCluster) {
+			defer wg.Done()
+			results[i] = clusterResult{clusterName: cluster.Name}
+			if len(o.resourceName) == 0 {
+				results[i].objects, results[i].err = o.listObjects(hostConfig, client, cluster, &targetType)
+				return
+			}
+			targetName := util.ClusterTargetName(cluster, overrides[cluster.Name], targetIsNamespace, qualifiedName)
+			if targetIsNamespace {
+				targetName.Namespace = ""
+			}
+			results[i].objects, results[i].err = o.getObject(hostConfig, client, cluster, &targetType, targetName)
+		}(i, cluster)
+	}
+	wg.Wait()
+
+	if o.output == tableOutput {
+		return writeTable(cmdOut, results, !targetType.Namespaced)
+	}
+	return o.writeList(cmdOut, results)
+}
+
+// getClusters returns the clusters to query.
+func (o *getOptions) getClusters(client genericclient.Client) ([]*fedv1b1.KubeFedCluster, error) {
+	clusterList := &fedv1b1.KubeFedClusterList{}
+	err := client.List(context.TODO(), clusterList, o.KubeFedNamespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list member clusters")
+	}
+	joined := make(map[string]*fedv1b1.KubeFedCluster)
+	for i := range clusterList.Items {
+		joined[clusterList.Items[i].Name] = &clusterList.Items[i]
+	}
+
+	names := sets.NewString(o.clusters...)
+	if names.Has(allClusters) {
+		names = sets.StringKeySet(joined)
+	}
+	clusters := []*fedv1b1.KubeFedCluster{}
+	for _, name := range names.List() {
+		cluster, ok := joined[name]
+		if !ok {
+			return nil, errors.Errorf("Cluster %q is not joined", name)
+		}
+		clusters = append(clusters, cluster)
+	}
+	return clusters, nil
+}
+
+// resourceClient returns a client of the target type in the given
+// cluster.
+func (o *getOptions) resourceClient(hostConfig *rest.Config, client genericclient.Client,
+	cluster *fedv1b1.KubeFedCluster, targetType *metav1.APIResource) (ctlutil.ResourceClient, error) {
+
+	if !ctlutil.IsClusterReady(&cluster.Status) {
+		return nil, errors.New("Cluster not ready")
+	}
+	clusterConfig, err := ctlutil.BuildClusterConfig(cluster, hostConfig, client, o.KubeFedNamespace)
+	if err != nil {
+		return nil, err
+	}
+	if clusterConfig == nil {
+		return nil, errors.Errorf("Unable to load configuration for cluster %q", cluster.Name)
+	}
+	return ctlutil.NewResourceClient(clusterConfig, targetType)
+}
+
+// getObject retrieves the named resource from the given cluster. A
+// resource that is not found is reported as an empty result.
+func (o *getOptions) getObject(hostConfig *rest.Config, client genericclient.Client, cluster *fedv1b1.KubeFedCluster,
+	targetType *metav1.APIResource, targetName ctlutil.QualifiedName) ([]unstructured.Unstructured, error) {
+
+	targetClient, err := o.resourceClient(hostConfig, client, cluster, targetType)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := targetClient.Resources(targetName.Namespace).Get(targetName.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []unstructured.Unstructured{*obj}, nil
+}
+
+// listObjects lists the resources of the namespace in the given
+// cluster.
+func (o *getOptions) listObjects(hostConfig *rest.Config, client genericclient.Client, cluster *fedv1b1.KubeFedCluster,
+	targetType *metav1.APIResource) ([]unstructured.Unstructured, error) {
+
+	targetClient, err := o.resourceClient(hostConfig, client, cluster, targetType)
+	if err != nil {
+		return nil, err
+	}
+	namespace := o.namespace
+	if !targetType.Namespaced {
+		namespace = ""
+	}
+	list, err := targetClient.Resources(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(list.Items, func(i, j int) bool {
+		return list.Items[i].GetName() < list.Items[j].GetName()
+	})
+	return list.Items, nil
+}
+
+// writeTable writes one row per retrieved resource, and a row for
+// each cluster the resource was not found in or could not be
+// retrieved from.
+func writeTable(w io.Writer, results []clusterResult, clusterScoped bool) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 3, ' ', 0)
+	if clusterScoped {
+		fmt.Fprintln(tw, "CLUSTER\tNAME\tMANAGED\tAGE\tMESSAGE")
+	} else {
+		fmt.Fprintln(tw, "CLUSTER\tNAMESPACE\tNAME\tMANAGED\tAGE\tMESSAGE")
+	}
+	now := time.Now()
+	for _, result := range results {
+		var message string
+		switch {
+		case result.err != nil:
+			message = result.err.Error()
+		case len(result.objects) == 0:
+			message = "Not found"
+		}
+		if len(message) > 0 {
+			if clusterScoped {
+				fmt.Fprintf(tw, "%s\t\t\t\t%s\n", result.clusterName, message)
+			} else {
+				fmt.Fprintf(tw, "%s\t\t\t\t\t%s\n", result.clusterName, message)
+			}
+			continue
+		}
+		for i := range result.objects {
+			obj := &result.objects[i]
+			managed := "false"
+			if ctlutil.HasManagedLabel(obj) {
+				managed = "true"
+			}
+			age := util.FormatAge(now.Sub(obj.GetCreationTimestamp().Time))
+			if clusterScoped {
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t\n", result.clusterName, obj.GetName(), managed, age)
+			} else {
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t\n", result.clusterName, obj.GetNamespace(), obj.GetName(), managed, age)
+			}
+		}
+	}
+	return tw.Flush()
+}
+
+// writeList writes the retrieved resources as a single list, with
+// each item annotated with the cluster it was retrieved from. Errors
+// are reported on stderr by klog since a list has no place for them.
+func (o *getOptions) writeList(w io.Writer, results []clusterResult) error {
+	list := &unstructured.UnstructuredList{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "List",
+	}}
+	for _, result := range results {
+		if result.err != nil {
+			klog.Errorf("Failed to retrieve resources from cluster %q: %v", result.clusterName, result.err)
+			continue
+		}
+		for _, obj := range result.objects {
+			annotations := obj.GetAnnotations()
+			if annotations == nil {
+				annotations = make(map[string]string)
+			}
+			annotations[views.ClusterAnnotation] = result.clusterName
+			obj.SetAnnotations(annotations)
+			list.Items = append(list.Items, obj)
+		}
+	}
+
+	data, err := list.MarshalJSON()
+	if err != nil {
+		return errors.Wrap(err, "Failed to marshal resources")
+	}
+	if o.output == yamlOutput {
+		data, err = yaml.JSONToYAML(data)
+		if err != nil {
+			return errors.Wrap(err, "Failed to marshal resources as yaml")
+		}
+		_, err = w.Write(data)
+		return err
+	}
+	var indented interface{}
+	err = json.Unmarshal(data, &indented)
+	if err == nil {
+		data, err = json.MarshalIndent(indented, "", "    ")
+	}
+	if err != nil {
+		return errors.Wrap(err, "Failed to marshal resources as json")
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
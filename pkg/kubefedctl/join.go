@@ -33,6 +33,7 @@ import (
 	apiextv1b1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/wait"
 	kubeclient "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -47,6 +48,11 @@ import (
 
 const (
 	serviceAccountSecretTimeout = 30 * time.Second
+
+	// controllerServiceAccountName is the name of the service account
+	// of the controller manager deployed by the chart, which is used
+	// to access a local cluster.
+	controllerServiceAccountName = "kubefed-controller"
 )
 
 var (
@@ -64,7 +70,21 @@ var (
 		# be a valid RFC 1123 subdomain name. Cluster context
 		# must be specified if the cluster name is different
 		# than the cluster's context in the local kubeconfig.
-		kubefedctl join foo --host-cluster-context=bar`
+		kubefedctl join foo --host-cluster-context=bar
+
+		# Register the host cluster itself, which the control
+		# plane then accesses with its in-cluster configuration.
+		kubefedctl join bar --host-cluster-context=bar --local
+
+		# Register several clusters named after their contexts,
+		# joining up to 10 clusters at a time.
+		kubefedctl join foo baz qux --host-cluster-context=bar --concurrency=10
+
+		# Register the clusters listed in a file, e.g.
+		#   - name: foo
+		#     context: foo-admin
+		#   - name: baz
+		kubefedctl join --clusters-file=clusters.yaml --host-cluster-context=bar`
 
 	// Policy rules allowing full access to resources in the cluster
 	// or namespace.
@@ -88,12 +108,18 @@ type joinFederation struct {
 	options.GlobalSubcommandOptions
 	options.CommonJoinOptions
 	joinFederationOptions
+
+	// clusters are the clusters to join.
+	clusters []clusterEntry
 }
 
 type joinFederationOptions struct {
-	secretName      string
-	Scope           apiextv1b1.ResourceScope
-	errorOnExisting bool
+	secretName          string
+	secretNamespace     string
+	local               bool
+	Scope               apiextv1b1.ResourceScope
+	errorOnExisting     bool
+	skipPreflightChecks bool
 }
 
 // Bind adds the join specific arguments to the flagset passed in as an
@@ -101,8 +127,14 @@ type joinFederationOptions struct {
 func (o *joinFederationOptions) Bind(flags *pflag.FlagSet) {
 	flags.StringVar(&o.secretName, "secret-name", "",
 		"Name of the secret where the cluster's credentials will be stored in the host cluster. This name should be a valid RFC 1035 label. If unspecified, defaults to a generated name containing the cluster name.")
+	flags.StringVar(&o.secretNamespace, "secret-namespace", "",
+		"Namespace of the host cluster in which the cluster's credentials will be stored. If unspecified, defaults to the KubeFed namespace, or to the namespace of the secret of an already joined cluster.")
+	flags.BoolVar(&o.local, "local", false,
+		"Whether the joining cluster is the host cluster. A local cluster is accessed by the control plane with its in-cluster configuration instead of a service account token stored in a secret.")
 	flags.BoolVar(&o.errorOnExisting, "error-on-existing", false,
 		"Whether the join operation will throw an error if it encounters existing artifacts with the same name as those it's trying to create. If false, the join operation will update existing artifacts to match its own specification.")
+	flags.BoolVar(&o.skipPreflightChecks, "skip-preflight-checks", false,
+		"Whether to skip the checks of cluster versions, permissions and admission webhooks performed before any resources are created.")
 }
 
 // NewCmdJoin defines the `join` command that registers a cluster with
@@ -111,7 +143,7 @@ func NewCmdJoin(cmdOut io.Writer, config util.FedConfig) *cobra.Command {
 	opts := &joinFederation{}
 
 	cmd := &cobra.Command{
-		Use:     "join CLUSTER_NAME --host-cluster-context=HOST_CONTEXT",
+		Use:     "join CLUSTER_NAME... --host-cluster-context=HOST_CONTEXT",
 		Short:   "Register a cluster with a KubeFed control plane",
 		Long:    join_long,
 		Example: join_example,
@@ -143,7 +175,7 @@ func (j *joinFederation) Complete(args []string) error {
 		return err
 	}
 
-	if j.ClusterContext == "" {
+	if j.ClusterContext == "" && len(j.ClusterNames) == 1 {
 		klog.V(2).Infof("Defaulting cluster context to joining cluster name %s", j.ClusterName)
 		j.ClusterContext = j.ClusterName
 	}
@@ -156,9 +188,21 @@ func (j *joinFederation) Complete(args []string) error {
 		klog.Fatal("host-cluster-name must be set if the name of the host cluster context contains one of \":\" or \"/\"")
 	}
 
-	klog.V(2).Infof("Args and flags: name %s, host: %s, host-system-namespace: %s, kubeconfig: %s, cluster-context: %s, secret-name: %s, dry-run: %v",
+	if j.local && (j.secretName != "" || j.secretNamespace != "") {
+		return goerrors.New("secret-name and secret-namespace may not be set for a local cluster")
+	}
+
+	j.clusters, err = clusterEntries(&j.CommonJoinOptions, j.secretName)
+	if err != nil {
+		return err
+	}
+	if j.local && len(j.clusters) > 1 {
+		return goerrors.New("local may only be set for a single cluster")
+	}
+
+	klog.V(2).Infof("Args and flags: name %s, host: %s, host-system-namespace: %s, kubeconfig: %s, cluster-context: %s, secret-name: %s, secret-namespace: %s, local: %v, dry-run: %v",
 		j.ClusterName, j.HostClusterContext, j.KubeFedNamespace, j.Kubeconfig, j.ClusterContext,
-		j.secretName, j.DryRun)
+		j.secretName, j.secretNamespace, j.local, j.DryRun)
 
 	return nil
 }
@@ -178,26 +222,43 @@ func (j *joinFederation) Run(cmdOut io.Writer, config util.FedConfig) error {
 		return err
 	}
 
-	clusterConfig, err := config.ClusterConfig(j.ClusterContext, j.Kubeconfig)
-	if err != nil {
-		klog.V(2).Infof("Failed to get joining cluster config: %v", err)
-		return err
-	}
-
 	hostClusterName := j.HostClusterContext
 	if j.HostClusterName != "" {
 		hostClusterName = j.HostClusterName
 	}
 
-	return JoinCluster(hostConfig, clusterConfig, j.KubeFedNamespace,
-		hostClusterName, j.ClusterName, j.secretName, j.Scope, j.DryRun, j.errorOnExisting)
+	operation := clusterOperation{verb: "join", pastTense: "Joined"}
+	return runForClusters(cmdOut, j.clusters, j.Concurrency, operation, func(cluster clusterEntry) error {
+		clusterConfig, err := config.ClusterConfig(cluster.Context, j.Kubeconfig)
+		if err != nil {
+			klog.V(2).Infof("Failed to get joining cluster config: %v", err)
+			return err
+		}
+
+		return JoinCluster(hostConfig, clusterConfig, j.KubeFedNamespace,
+			hostClusterName, cluster.Name, cluster.SecretName, j.secretNamespace, j.Scope, j.local, j.DryRun, j.errorOnExisting, j.skipPreflightChecks)
+	})
 }
 
 // JoinCluster performs all the necessary steps to register a cluster
 // with a KubeFed control plane provided the required set of
-// parameters are passed in.
+// parameters are passed in.  The credentials of the cluster are
+// stored in secretNamespace of the host cluster, which defaults to
+// the KubeFed namespace.  A local cluster is the host cluster itself,
+// which the control plane accesses with its in-cluster configuration
+// and for which no credentials are stored.
 func JoinCluster(hostConfig, clusterConfig *rest.Config, kubefedNamespace,
-	hostClusterName, joiningClusterName, secretName string, Scope apiextv1b1.ResourceScope, dryRun, errorOnExisting bool) error {
+	hostClusterName, joiningClusterName, secretName, secretNamespace string, Scope apiextv1b1.ResourceScope, local, dryRun, errorOnExisting, skipPreflightChecks bool) error {
+	return joinCluster(hostConfig, clusterConfig, clusterConfig.Host, kubefedNamespace,
+		hostClusterName, joiningClusterName, secretName, secretNamespace, Scope, local, dryRun, errorOnExisting, skipPreflightChecks)
+}
+
+// joinCluster registers a cluster with a KubeFed control plane that
+// accesses the cluster at the given API endpoint, which may differ
+// from the endpoint used to configure the cluster, e.g. when the
+// cluster is configured from within.
+func joinCluster(hostConfig, clusterConfig *rest.Config, apiEndpoint, kubefedNamespace,
+	hostClusterName, joiningClusterName, secretName, secretNamespace string, Scope apiextv1b1.ResourceScope, local, dryRun, errorOnExisting, skipPreflightChecks bool) error {
 	hostClientset, err := util.HostClientset(hostConfig)
 	if err != nil {
 		klog.V(2).Infof("Failed to get host cluster clientset: %v", err)
@@ -216,8 +277,26 @@ func JoinCluster(hostConfig, clusterConfig *rest.Config, kubefedNamespace,
 		return err
 	}
 
+	existingFedCluster, err := getKubeFedCluster(client, kubefedNamespace, joiningClusterName)
+	if err != nil {
+		return err
+	}
+	rejoin := existingFedCluster != nil
+	if rejoin && secretName == "" && !local {
+		// Refresh the credentials of an already joined cluster in
+		// the secret it references.
+		secretName = existingFedCluster.Spec.SecretRef.Name
+		if secretNamespace == "" {
+			secretNamespace = existingFedCluster.Spec.SecretRef.Namespace
+		}
+	}
+	if secretNamespace == "" {
+		secretNamespace = kubefedNamespace
+	}
+
 	klog.V(2).Infof("Performing preflight checks.")
-	err = performPreflightChecks(clusterClientset, joiningClusterName, hostClusterName, kubefedNamespace, errorOnExisting)
+	err = performPreflightChecks(hostClientset, clusterClientset, joiningClusterName, hostClusterName,
+		kubefedNamespace, secretNamespace, Scope, local, rejoin, errorOnExisting, skipPreflightChecks)
 	if err != nil {
 		return err
 	}
@@ -232,23 +311,54 @@ func JoinCluster(hostConfig, clusterConfig *rest.Config, kubefedNamespace,
 	}
 	klog.V(2).Infof("Created %s namespace in joining cluster", kubefedNamespace)
 
-	// Create a service account and use its credentials.
-	klog.V(2).Info("Creating cluster credentials secret")
+	var secret *corev1.Secret
+	var caBundle []byte
+	if local {
+		// The control plane accesses a local cluster with the
+		// credentials of its own service account.
+		klog.V(2).Infof("Granting access to joining cluster to service account: %s", controllerServiceAccountName)
+
+		err = createMemberRoleBindings(clusterClientset, controllerServiceAccountName, kubefedNamespace,
+			joiningClusterName, Scope, dryRun, errorOnExisting)
+		if err != nil {
+			klog.V(2).Infof("Could not grant access to joining cluster to service account: %s due to: %v", controllerServiceAccountName, err)
+			return err
+		}
 
-	secret, caBundle, err := createRBACSecret(hostClientset, clusterClientset,
-		kubefedNamespace, joiningClusterName, hostClusterName,
-		secretName, Scope, dryRun, errorOnExisting)
-	if err != nil {
-		klog.V(2).Infof("Could not create cluster credentials secret: %v", err)
-		return err
+		klog.V(2).Infof("Granted access to joining cluster to service account: %s", controllerServiceAccountName)
+		secret = &corev1.Secret{}
+		secretNamespace = kubefedNamespace
+	} else {
+		// Create a service account and use its credentials.
+		klog.V(2).Info("Creating cluster credentials secret")
+
+		secret, caBundle, err = createRBACSecret(clusterConfig, hostClientset, clusterClientset,
+			kubefedNamespace, joiningClusterName, hostClusterName,
+			secretName, secretNamespace, Scope, dryRun, errorOnExisting)
+		if err != nil {
+			klog.V(2).Infof("Could not create cluster credentials secret: %v", err)
+			return err
+		}
+
+		klog.V(2).Info("Cluster credentials secret created")
 	}
 
-	klog.V(2).Info("Cluster credentials secret created")
+	if Scope == apiextv1b1.NamespaceScoped {
+		saName := controllerServiceAccountName
+		if !local {
+			saName = util.ClusterServiceAccountName(joiningClusterName, hostClusterName)
+		}
+		err = createClusterScopedRoleAndBinding(hostConfig, clusterClientset, saName, kubefedNamespace, joiningClusterName, dryRun, errorOnExisting)
+		if err != nil {
+			klog.V(2).Infof("Could not grant access to the allowed cluster-scoped resources of joining cluster: %v", err)
+			return err
+		}
+	}
 
 	klog.V(2).Info("Creating federated cluster resource")
 
-	_, err = createKubeFedCluster(client, joiningClusterName, clusterConfig.Host,
-		secret.Name, kubefedNamespace, caBundle, dryRun, errorOnExisting)
+	_, err = createKubeFedCluster(client, existingFedCluster, joiningClusterName, apiEndpoint,
+		secret.Name, secretNamespace, kubefedNamespace, caBundle, local, dryRun, errorOnExisting)
 	if err != nil {
 		klog.V(2).Infof("Failed to create federated cluster resource: %v", err)
 		return err
@@ -258,32 +368,31 @@ func JoinCluster(hostConfig, clusterConfig *rest.Config, kubefedNamespace,
 	return nil
 }
 
-// performPreflightChecks checks that the host and joining clusters are in
-// a consistent state.
-func performPreflightChecks(clusterClientset kubeclient.Interface, name, hostClusterName,
-	kubefedNamespace string, errorOnExisting bool) error {
-	// Make sure there is no existing service account in the joining cluster.
-	saName := util.ClusterServiceAccountName(name, hostClusterName)
-	_, err := clusterClientset.CoreV1().ServiceAccounts(kubefedNamespace).Get(saName,
-		metav1.GetOptions{})
-
+// getKubeFedCluster returns the federated cluster resource of an
+// already joined cluster, or nil if the cluster has not been joined.
+func getKubeFedCluster(client genericclient.Client, kubefedNamespace, clusterName string) (*fedv1b1.KubeFedCluster, error) {
+	fedCluster := &fedv1b1.KubeFedCluster{}
+	err := client.Get(context.TODO(), fedCluster, kubefedNamespace, clusterName)
 	switch {
 	case apierrors.IsNotFound(err):
-		return nil
+		return nil, nil
 	case err != nil:
-		return err
-	case errorOnExisting:
-		return errors.Errorf("service account: %s already exists in joining cluster: %s", saName, name)
+		klog.V(2).Infof("Could not retrieve federated cluster %s due to %v", clusterName, err)
+		return nil, err
 	default:
-		klog.V(2).Infof("Service account %s already exists in joining cluster %s", saName, name)
-		return nil
+		return fedCluster, nil
 	}
 }
 
 // createKubeFedCluster creates a federated cluster resource that associates
-// the cluster and secret.
-func createKubeFedCluster(client genericclient.Client, joiningClusterName, apiEndpoint,
-	secretName, kubefedNamespace string, caBundle []byte, dryRun, errorOnExisting bool) (*fedv1b1.KubeFedCluster, error) {
+// the cluster and secret, or updates the existing federated cluster
+// resource in place.  The namespace of the secret is only recorded if
+// it is not the KubeFed namespace.
+func createKubeFedCluster(client genericclient.Client, existingFedCluster *fedv1b1.KubeFedCluster, joiningClusterName, apiEndpoint,
+	secretName, secretNamespace, kubefedNamespace string, caBundle []byte, local, dryRun, errorOnExisting bool) (*fedv1b1.KubeFedCluster, error) {
+	if secretNamespace == kubefedNamespace {
+		secretNamespace = ""
+	}
 	fedCluster := &fedv1b1.KubeFedCluster{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: kubefedNamespace,
@@ -293,8 +402,10 @@ func createKubeFedCluster(client genericclient.Client, joiningClusterName, apiEn
 			APIEndpoint: apiEndpoint,
 			CABundle:    caBundle,
 			SecretRef: fedv1b1.LocalSecretReference{
-				Name: secretName,
+				Name:      secretName,
+				Namespace: secretNamespace,
 			},
+			Local: local,
 		},
 	}
 
@@ -302,24 +413,25 @@ func createKubeFedCluster(client genericclient.Client, joiningClusterName, apiEn
 		return fedCluster, nil
 	}
 
-	existingFedCluster := &fedv1b1.KubeFedCluster{}
-	err := client.Get(context.TODO(), existingFedCluster, kubefedNamespace, joiningClusterName)
 	switch {
-	case err != nil && !apierrors.IsNotFound(err):
-		klog.V(2).Infof("Could not retrieve federated cluster %s due to %v", joiningClusterName, err)
-		return nil, err
-	case err == nil && errorOnExisting:
+	case existingFedCluster != nil && errorOnExisting:
 		return nil, errors.Errorf("federated cluster %s already exists in host cluster", joiningClusterName)
-	case err == nil:
-		existingFedCluster.Spec = fedCluster.Spec
-		err = client.Update(context.TODO(), existingFedCluster)
+	case existingFedCluster != nil:
+		// Only the fields managed by join are updated so that
+		// configuration added after the cluster was joined is
+		// retained.
+		existingFedCluster.Spec.APIEndpoint = fedCluster.Spec.APIEndpoint
+		existingFedCluster.Spec.CABundle = fedCluster.Spec.CABundle
+		existingFedCluster.Spec.SecretRef = fedCluster.Spec.SecretRef
+		existingFedCluster.Spec.Local = fedCluster.Spec.Local
+		err := client.Update(context.TODO(), existingFedCluster)
 		if err != nil {
 			klog.V(2).Infof("Could not update federated cluster %s due to %v", fedCluster.Name, err)
 			return nil, err
 		}
 		return existingFedCluster, nil
 	default:
-		err = client.Create(context.TODO(), fedCluster)
+		err := client.Create(context.TODO(), fedCluster)
 		if err != nil {
 			klog.V(2).Infof("Could not create federated cluster %s due to %v", fedCluster.Name, err)
 			return nil, err
@@ -365,9 +477,9 @@ func createKubeFedNamespace(clusterClientset kubeclient.Interface, kubefedNamesp
 // createRBACSecret creates a secret in the joining cluster using a service
 // account, and populate that secret into the host cluster to allow it to
 // access the joining cluster.
-func createRBACSecret(hostClusterClientset, joiningClusterClientset kubeclient.Interface,
+func createRBACSecret(joiningClusterConfig *rest.Config, hostClusterClientset, joiningClusterClientset kubeclient.Interface,
 	namespace, joiningClusterName, hostClusterName,
-	secretName string, Scope apiextv1b1.ResourceScope, dryRun, errorOnExisting bool) (*corev1.Secret, []byte, error) {
+	secretName, secretNamespace string, Scope apiextv1b1.ResourceScope, dryRun, errorOnExisting bool) (*corev1.Secret, []byte, error) {
 
 	klog.V(2).Infof("Creating service account in joining cluster: %s", joiningClusterName)
 
@@ -381,13 +493,38 @@ func createRBACSecret(hostClusterClientset, joiningClusterClientset kubeclient.I
 
 	klog.V(2).Infof("Created service account: %s in joining cluster: %s", saName, joiningClusterName)
 
+	err = createMemberRoleBindings(joiningClusterClientset, saName, namespace, joiningClusterName, Scope, dryRun, errorOnExisting)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	klog.V(2).Infof("Creating secret in host cluster: %s", hostClusterName)
+
+	secret, caBundle, err := populateSecretInHostCluster(joiningClusterConfig, joiningClusterClientset, hostClusterClientset,
+		saName, namespace, joiningClusterName, secretName, secretNamespace, dryRun, errorOnExisting)
+	if err != nil {
+		klog.V(2).Infof("Error creating secret in host cluster: %s due to: %v", hostClusterName, err)
+		return nil, nil, err
+	}
+
+	klog.V(2).Infof("Created secret in host cluster: %s", hostClusterName)
+
+	return secret, caBundle, nil
+}
+
+// createMemberRoleBindings grants the given service account of the
+// host cluster access to the joining cluster, limited to the KubeFed
+// namespace for a namespace-scoped control plane.
+func createMemberRoleBindings(joiningClusterClientset kubeclient.Interface, saName, namespace, joiningClusterName string,
+	Scope apiextv1b1.ResourceScope, dryRun, errorOnExisting bool) error {
+	var err error
 	if Scope == apiextv1b1.NamespaceScoped {
 		klog.V(2).Infof("Creating role and binding for service account: %s in joining cluster: %s", saName, joiningClusterName)
 
 		err = createRoleAndBinding(joiningClusterClientset, saName, namespace, joiningClusterName, dryRun, errorOnExisting)
 		if err != nil {
 			klog.V(2).Infof("Error creating role and binding for service account: %s in joining cluster: %s due to: %v", saName, joiningClusterName, err)
-			return nil, nil, err
+			return err
 		}
 
 		klog.V(2).Infof("Created role and binding for service account: %s in joining cluster: %s",
@@ -400,7 +537,7 @@ func createRBACSecret(hostClusterClientset, joiningClusterClientset kubeclient.I
 		if err != nil {
 			klog.V(2).Infof("Error creating health check cluster role and binding for service account: %s in joining cluster: %s due to: %v",
 				saName, joiningClusterName, err)
-			return nil, nil, err
+			return err
 		}
 
 		klog.V(2).Infof("Created health check cluster role and binding for service account: %s in joining cluster: %s",
@@ -413,25 +550,13 @@ func createRBACSecret(hostClusterClientset, joiningClusterClientset kubeclient.I
 		if err != nil {
 			klog.V(2).Infof("Error creating cluster role and binding for service account: %s in joining cluster: %s due to: %v",
 				saName, joiningClusterName, err)
-			return nil, nil, err
+			return err
 		}
 
 		klog.V(2).Infof("Created cluster role and binding for service account: %s in joining cluster: %s",
 			saName, joiningClusterName)
 	}
-
-	klog.V(2).Infof("Creating secret in host cluster: %s", hostClusterName)
-
-	secret, caBundle, err := populateSecretInHostCluster(joiningClusterClientset, hostClusterClientset,
-		saName, namespace, joiningClusterName, secretName, dryRun)
-	if err != nil {
-		klog.V(2).Infof("Error creating secret in host cluster: %s due to: %v", hostClusterName, err)
-		return nil, nil, err
-	}
-
-	klog.V(2).Infof("Created secret in host cluster: %s", hostClusterName)
-
-	return secret, caBundle, nil
+	return nil
 }
 
 // createServiceAccount creates a service account in the cluster associated
@@ -698,27 +823,117 @@ func createHealthCheckClusterRoleAndBinding(clientset kubeclient.Interface, saNa
 			},
 		},
 	}
+	return ensureClusterRoleAndBinding(clientset, role, "health check", saName, namespace, clusterName, errorOnExisting)
+}
+
+// createClusterScopedRoleAndBinding creates an RBAC cluster role and
+// binding that allows the service account identified by saName to
+// propagate the cluster-scoped resources that the KubeFedConfig of a
+// namespace-scoped control plane allows. Nothing is created if no
+// cluster-scoped resources are allowed.
+func createClusterScopedRoleAndBinding(hostConfig *rest.Config, clientset kubeclient.Interface, saName, namespace, clusterName string, dryRun, errorOnExisting bool) error {
+	if dryRun {
+		return nil
+	}
+
+	allowlist, err := options.GetAllowedClusterScopedResourcesFromKubeFedConfig(hostConfig, namespace)
+	if err != nil {
+		return err
+	}
+	if len(allowlist) == 0 {
+		return nil
+	}
+
+	// Partial discovery failures are tolerated, and kinds that could
+	// not be discovered are not granted.
+	resourceLists, err := clientset.Discovery().ServerPreferredResources()
+	if err != nil && len(resourceLists) == 0 {
+		return errors.Wrapf(err, "Failed to discover the resources of joining cluster %s", clusterName)
+	}
+
+	rules := []rbacv1.PolicyRule{}
+	for _, allowed := range allowlist {
+		groupKind := schema.GroupKind{Group: allowed.Group, Kind: allowed.Kind}
+		resource, ok := clusterScopedResourceName(resourceLists, groupKind)
+		if !ok {
+			klog.Warningf("%s is not served as a cluster-scoped resource by joining cluster %s and will not be propagated to it", groupKind, clusterName)
+			continue
+		}
+		// The creation and listing of resources cannot be limited to
+		// resource names.
+		rules = append(rules, rbacv1.PolicyRule{
+			Verbs:     []string{"get", "list", "watch", "create"},
+			APIGroups: []string{allowed.Group},
+			Resources: []string{resource},
+		})
+		verbs := []string{"update", "patch", "delete"}
+		if allowed.Group == rbacv1.GroupName {
+			// Roles granting permissions that the service account
+			// does not hold can only be propagated with escalate and
+			// bind.
+			verbs = append(verbs, "escalate", "bind")
+		}
+		rules = append(rules, rbacv1.PolicyRule{
+			Verbs:         verbs,
+			APIGroups:     []string{allowed.Group},
+			Resources:     []string{resource},
+			ResourceNames: allowed.Names,
+		})
+	}
+
+	role := &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: util.ClusterScopedRoleName(saName, namespace),
+		},
+		Rules: rules,
+	}
+	return ensureClusterRoleAndBinding(clientset, role, "cluster-scoped resource", saName, namespace, clusterName, errorOnExisting)
+}
+
+// clusterScopedResourceName returns the name of the cluster-scoped
+// resource of the given kind in the discovered resource lists.
+func clusterScopedResourceName(resourceLists []*metav1.APIResourceList, groupKind schema.GroupKind) (string, bool) {
+	for _, resourceList := range resourceLists {
+		gv, err := schema.ParseGroupVersion(resourceList.GroupVersion)
+		if err != nil || gv.Group != groupKind.Group {
+			continue
+		}
+		for _, resource := range resourceList.APIResources {
+			if resource.Kind == groupKind.Kind && !resource.Namespaced && !strings.Contains(resource.Name, "/") {
+				return resource.Name, true
+			}
+		}
+	}
+	return "", false
+}
+
+// ensureClusterRoleAndBinding creates or updates the given RBAC cluster
+// role and a binding of the same name that grants it to the service
+// account identified by saName. The description of the role is used
+// in logs and errors.
+func ensureClusterRoleAndBinding(clientset kubeclient.Interface, role *rbacv1.ClusterRole, description, saName, namespace, clusterName string, errorOnExisting bool) error {
+	roleName := role.Name
 	existingRole, err := clientset.RbacV1().ClusterRoles().Get(role.Name, metav1.GetOptions{})
 	switch {
 	case err != nil && !apierrors.IsNotFound(err):
-		klog.V(2).Infof("Could not get health check cluster role for service account %s in joining cluster %s due to %v",
-			saName, clusterName, err)
+		klog.V(2).Infof("Could not get %s cluster role for service account %s in joining cluster %s due to %v",
+			description, saName, clusterName, err)
 		return err
 	case err == nil && errorOnExisting:
-		return errors.Errorf("health check cluster role for service account %s in joining cluster %s already exists", saName, clusterName)
+		return errors.Errorf("%s cluster role for service account %s in joining cluster %s already exists", description, saName, clusterName)
 	case err == nil:
 		existingRole.Rules = role.Rules
 		_, err := clientset.RbacV1().ClusterRoles().Update(existingRole)
 		if err != nil {
-			klog.V(2).Infof("Could not update health check cluster role for service account: %s in joining cluster: %s due to: %v",
-				saName, clusterName, err)
+			klog.V(2).Infof("Could not update %s cluster role for service account: %s in joining cluster: %s due to: %v",
+				description, saName, clusterName, err)
 			return err
 		}
 	default: // role was not found
 		_, err := clientset.RbacV1().ClusterRoles().Create(role)
 		if err != nil {
-			klog.V(2).Infof("Could not create health check cluster role for service account: %s in joining cluster: %s due to: %v",
-				saName, clusterName, err)
+			klog.V(2).Infof("Could not create %s cluster role for service account: %s in joining cluster: %s due to: %v",
+				description, saName, clusterName, err)
 			return err
 		}
 	}
@@ -737,41 +952,41 @@ func createHealthCheckClusterRoleAndBinding(clientset kubeclient.Interface, saNa
 	existingBinding, err := clientset.RbacV1().ClusterRoleBindings().Get(binding.Name, metav1.GetOptions{})
 	switch {
 	case err != nil && !apierrors.IsNotFound(err):
-		klog.V(2).Infof("Could not get health check cluster role binding for service account %s in joining cluster %s due to %v",
-			saName, clusterName, err)
+		klog.V(2).Infof("Could not get %s cluster role binding for service account %s in joining cluster %s due to %v",
+			description, saName, clusterName, err)
 		return err
 	case err == nil && errorOnExisting:
-		return errors.Errorf("health check cluster role binding for service account %s in joining cluster %s already exists", saName, clusterName)
+		return errors.Errorf("%s cluster role binding for service account %s in joining cluster %s already exists", description, saName, clusterName)
 	case err == nil:
 		// The roleRef cannot be updated, therefore if the existing roleRef is different, the existing rolebinding
 		// must be deleted and recreated with the correct roleRef
 		if !reflect.DeepEqual(existingBinding.RoleRef, binding.RoleRef) {
 			err = clientset.RbacV1().ClusterRoleBindings().Delete(existingBinding.Name, &metav1.DeleteOptions{})
 			if err != nil {
-				klog.V(2).Infof("Could not delete existing health check cluster role binding for service account %s in joining cluster %s due to: %v",
-					saName, clusterName, err)
+				klog.V(2).Infof("Could not delete existing %s cluster role binding for service account %s in joining cluster %s due to: %v",
+					description, saName, clusterName, err)
 				return err
 			}
 			_, err = clientset.RbacV1().ClusterRoleBindings().Create(binding)
 			if err != nil {
-				klog.V(2).Infof("Could not create health check cluster role binding for service account: %s in joining cluster: %s due to: %v",
-					saName, clusterName, err)
+				klog.V(2).Infof("Could not create %s cluster role binding for service account: %s in joining cluster: %s due to: %v",
+					description, saName, clusterName, err)
 				return err
 			}
 		} else {
 			existingBinding.Subjects = binding.Subjects
 			_, err := clientset.RbacV1().ClusterRoleBindings().Update(existingBinding)
 			if err != nil {
-				klog.V(2).Infof("Could not update health check cluster role binding for service account: %s in joining cluster: %s due to: %v",
-					saName, clusterName, err)
+				klog.V(2).Infof("Could not update %s cluster role binding for service account: %s in joining cluster: %s due to: %v",
+					description, saName, clusterName, err)
 				return err
 			}
 		}
 	default:
 		_, err = clientset.RbacV1().ClusterRoleBindings().Create(binding)
 		if err != nil {
-			klog.V(2).Infof("Could not create health check cluster role binding for service account: %s in joining cluster: %s due to: %v",
-				saName, clusterName, err)
+			klog.V(2).Infof("Could not create %s cluster role binding for service account: %s in joining cluster: %s due to: %v",
+				description, saName, clusterName, err)
 			return err
 		}
 	}
@@ -780,18 +995,120 @@ func createHealthCheckClusterRoleAndBinding(clientset kubeclient.Interface, saNa
 
 // populateSecretInHostCluster copies the service account secret for saName
 // from the cluster referenced by clusterClientset to the client referenced by
-// hostClientset, putting it in a secret named secretName in the provided
-// namespace.
-func populateSecretInHostCluster(clusterClientset, hostClientset kubeclient.Interface,
-	saName, namespace, joiningClusterName, secretName string,
-	dryRun bool) (*corev1.Secret, []byte, error) {
+// hostClientset, putting it in a secret named secretName in secretNamespace
+// of the host cluster. A token that is no longer accepted by the joining
+// cluster is regenerated, and an existing secret in the host cluster is
+// updated.
+func populateSecretInHostCluster(clusterConfig *rest.Config, clusterClientset, hostClientset kubeclient.Interface,
+	saName, namespace, joiningClusterName, secretName, secretNamespace string,
+	dryRun, errorOnExisting bool) (*corev1.Secret, []byte, error) {
 	if dryRun {
 		dryRunSecret := &corev1.Secret{}
 		dryRunSecret.Name = secretName
 		return dryRunSecret, nil, nil
 	}
 
+	token, caBundle, err := serviceAccountToken(clusterConfig, clusterClientset, saName, namespace, joiningClusterName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if secretName != "" {
+		existingSecret, err := hostClientset.CoreV1().Secrets(secretNamespace).Get(secretName, metav1.GetOptions{})
+		switch {
+		case err != nil && !apierrors.IsNotFound(err):
+			klog.V(2).Infof("Could not get secret %s in host cluster: %v", secretName, err)
+			return nil, nil, err
+		case err == nil && errorOnExisting:
+			return nil, nil, errors.Errorf("secret %s already exists in host cluster", secretName)
+		case err == nil:
+			if existingSecret.Data == nil {
+				existingSecret.Data = map[string][]byte{}
+			}
+			existingSecret.Data[ctlutil.TokenKey] = token
+			v1SecretResult, err := hostClientset.CoreV1().Secrets(secretNamespace).Update(existingSecret)
+			if err != nil {
+				klog.V(2).Infof("Could not update secret in host cluster: %v", err)
+				return nil, nil, err
+			}
+			klog.V(2).Infof("Updated secret in host cluster named: %s", v1SecretResult.Name)
+			return v1SecretResult, caBundle, nil
+		}
+	}
+
+	// Create a secret in the host cluster containing the token.
+	v1Secret := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: secretNamespace,
+		},
+		Data: map[string][]byte{
+			ctlutil.TokenKey: token,
+		},
+	}
+
+	if secretName == "" {
+		v1Secret.GenerateName = joiningClusterName + "-"
+	} else {
+		v1Secret.Name = secretName
+	}
+
+	v1SecretResult, err := hostClientset.CoreV1().Secrets(secretNamespace).Create(&v1Secret)
+	if err != nil {
+		klog.V(2).Infof("Could not create secret in host cluster: %v", err)
+		return nil, nil, err
+	}
+
+	klog.V(2).Infof("Created secret in host cluster named: %s", v1SecretResult.Name)
+	return v1SecretResult, caBundle, nil
+}
+
+// serviceAccountToken returns the token of the given service account
+// of the joining cluster and the CA bundle of the cluster.  A token
+// that is no longer accepted by the joining cluster is regenerated.
+func serviceAccountToken(clusterConfig *rest.Config, clusterClientset kubeclient.Interface,
+	saName, namespace, joiningClusterName string) ([]byte, []byte, error) {
 	// Get the secret from the joining cluster.
+	secret, err := getServiceAccountTokenSecret(clusterClientset, saName, namespace, "")
+	if err != nil {
+		klog.V(2).Infof("Could not get service account secret from joining cluster: %v", err)
+		return nil, nil, err
+	}
+
+	token, ok := secret.Data[ctlutil.TokenKey]
+	if !ok {
+		return nil, nil, errors.Errorf("Key %q not found in service account secret", ctlutil.TokenKey)
+	}
+
+	if !serviceAccountTokenIsValid(clusterConfig, token) {
+		// Deleting the secret causes the token controller to
+		// generate a new token for the service account.
+		klog.V(2).Infof("Regenerating invalid token of service account %s in joining cluster %s", saName, joiningClusterName)
+		err = clusterClientset.CoreV1().Secrets(namespace).Delete(secret.Name, &metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			klog.V(2).Infof("Could not delete service account secret %s from joining cluster: %v", secret.Name, err)
+			return nil, nil, err
+		}
+		secret, err = getServiceAccountTokenSecret(clusterClientset, saName, namespace, secret.Name)
+		if err != nil {
+			klog.V(2).Infof("Could not get regenerated service account secret from joining cluster: %v", err)
+			return nil, nil, err
+		}
+		token, ok = secret.Data[ctlutil.TokenKey]
+		if !ok {
+			return nil, nil, errors.Errorf("Key %q not found in service account secret", ctlutil.TokenKey)
+		}
+	}
+
+	// caBundle is optional so no error is suggested if it is not
+	// found in the secret.
+	return token, secret.Data["ca.crt"], nil
+}
+
+// getServiceAccountTokenSecret waits for a token secret of the service
+// account other than the one named ignoredSecretName, which may be
+// pending deletion.
+func getServiceAccountTokenSecret(clusterClientset kubeclient.Interface, saName, namespace,
+	ignoredSecretName string) (*corev1.Secret, error) {
 	var secret *corev1.Secret
 	err := wait.PollImmediate(1*time.Second, serviceAccountSecretTimeout, func() (bool, error) {
 		sa, err := clusterClientset.CoreV1().ServiceAccounts(namespace).Get(saName,
@@ -802,6 +1119,9 @@ func populateSecretInHostCluster(clusterClientset, hostClientset kubeclient.Inte
 
 		for _, objReference := range sa.Secrets {
 			saSecretName := objReference.Name
+			if saSecretName == ignoredSecretName {
+				continue
+			}
 			var err error
 			secret, err = clusterClientset.CoreV1().Secrets(namespace).Get(saSecretName,
 				metav1.GetOptions{})
@@ -815,43 +1135,26 @@ func populateSecretInHostCluster(clusterClientset, hostClientset kubeclient.Inte
 		}
 		return false, nil
 	})
+	return secret, err
+}
 
+// serviceAccountTokenIsValid returns whether the API server of the
+// joining cluster accepts the token. A token is assumed to be valid if
+// the check fails for a reason other than authentication.
+func serviceAccountTokenIsValid(clusterConfig *rest.Config, token []byte) bool {
+	config := rest.AnonymousClientConfig(clusterConfig)
+	config.BearerToken = string(token)
+	client, err := kubeclient.NewForConfig(config)
 	if err != nil {
-		klog.V(2).Infof("Could not get service account secret from joining cluster: %v", err)
-		return nil, nil, err
+		klog.V(2).Infof("Could not create client to check service account token: %v", err)
+		return true
 	}
-
-	token, ok := secret.Data[ctlutil.TokenKey]
-	if !ok {
-		return nil, nil, errors.Errorf("Key %q not found in service account secret", ctlutil.TokenKey)
-	}
-
-	// Create a secret in the host cluster containing the token.
-	v1Secret := corev1.Secret{
-		ObjectMeta: metav1.ObjectMeta{
-			Namespace: namespace,
-		},
-		Data: map[string][]byte{
-			ctlutil.TokenKey: token,
-		},
+	_, err = client.Discovery().ServerVersion()
+	if apierrors.IsUnauthorized(err) {
+		return false
 	}
-
-	if secretName == "" {
-		v1Secret.GenerateName = joiningClusterName + "-"
-	} else {
-		v1Secret.Name = secretName
-	}
-
-	v1SecretResult, err := hostClientset.CoreV1().Secrets(namespace).Create(&v1Secret)
 	if err != nil {
-		klog.V(2).Infof("Could not create secret in host cluster: %v", err)
-		return nil, nil, err
+		klog.V(2).Infof("Could not check service account token: %v", err)
 	}
-
-	// caBundle is optional so no error is suggested if it is not
-	// found in the secret.
-	caBundle := secret.Data["ca.crt"]
-
-	klog.V(2).Infof("Created secret in host cluster named: %s", v1SecretResult.Name)
-	return v1SecretResult, caBundle, nil
+	return true
 }
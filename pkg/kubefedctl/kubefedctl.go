@@ -26,8 +26,18 @@ import (
 	apiserverflag "k8s.io/apiserver/pkg/util/flag"
 	"k8s.io/client-go/tools/clientcmd"
 
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/admissionpolicy"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/adoption"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/approve"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/checksum"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/describe"
 	"sigs.k8s.io/kubefed/pkg/kubefedctl/enable"
 	"sigs.k8s.io/kubefed/pkg/kubefedctl/federate"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/get"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/loadtest"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/logs"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/set"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/tree"
 	"sigs.k8s.io/kubefed/pkg/kubefedctl/util"
 )
 
@@ -60,6 +70,17 @@ func NewKubeFedCtlCommand(out io.Writer) *cobra.Command {
 	rootCmd.AddCommand(federate.NewCmdFederateResource(out, fedConfig))
 	rootCmd.AddCommand(NewCmdJoin(out, fedConfig))
 	rootCmd.AddCommand(NewCmdUnjoin(out, fedConfig))
+	rootCmd.AddCommand(approve.NewCmdApprove(out, fedConfig))
+	rootCmd.AddCommand(NewCmdBootstrap(out, fedConfig))
+	rootCmd.AddCommand(admissionpolicy.NewCmdAdmissionPolicies(out))
+	rootCmd.AddCommand(loadtest.NewCmdLoadTest(out, fedConfig))
+	rootCmd.AddCommand(get.NewCmdGet(out, fedConfig))
+	rootCmd.AddCommand(logs.NewCmdLogs(out, fedConfig))
+	rootCmd.AddCommand(tree.NewCmdTree(out, fedConfig))
+	rootCmd.AddCommand(describe.NewCmdDescribe(out, fedConfig))
+	rootCmd.AddCommand(set.NewCmdSet(out, fedConfig))
+	rootCmd.AddCommand(checksum.NewCmdChecksum(out, fedConfig))
+	rootCmd.AddCommand(adoption.NewCmdAdoptionReport(out, fedConfig))
 	rootCmd.AddCommand(NewCmdVersion(out))
 
 	return rootCmd
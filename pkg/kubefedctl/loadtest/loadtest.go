@@ -0,0 +1,534 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadtest
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	apiv1 "k8s.io/api/core/v1"
+	apiextv1b1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog"
+
+	"sigs.k8s.io/kubefed/pkg/apis/core/typeconfig"
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	"sigs.k8s.io/kubefed/pkg/controller/sync/status"
+	ctlutil "sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/options"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/util"
+)
+
+const (
+	namespaceTypeName = "namespaces"
+	resourceName      = "loadtest"
+	payloadKey        = "payload"
+)
+
+var (
+	// The types whose resources can be generated with a payload of a
+	// given size.
+	supportedTypeNames = []string{"configmaps", "secrets"}
+
+	loadtest_long = `
+		Loadtest creates a number of federated resources placed on
+		member clusters, waits for them to be propagated and reports
+		percentiles of the time taken for each resource to be propagated
+		to all of its clusters.
+
+		Resources are created in a test namespace that is federated to
+		the same clusters, and the federated types of the resources
+		(including namespaces) must be enabled. Once the test completes,
+		its resources are deleted (along with the test namespace if it
+		was created by the test) unless --cleanup=false is provided.
+
+		Current context is assumed to be a Kubernetes cluster hosting
+		the kubefed control plane. Please use the --host-cluster-context
+		flag otherwise.`
+
+	loadtest_example = `
+		# Create 500 federated configmaps and secrets of 4KB placed on all member clusters
+		kubefedctl loadtest --count=500 --types=configmaps,secrets --size=4096 --host-cluster-context=cluster1
+
+		# Create 100 federated configmaps placed on clusters cluster2 and cluster3
+		kubefedctl loadtest --count=100 --clusters=cluster2,cluster3 --host-cluster-context=cluster1`
+)
+
+type loadTest struct {
+	options.GlobalSubcommandOptions
+	count       int
+	typeNames   []string
+	size        int
+	clusters    []string
+	namespace   string
+	concurrency int
+	timeout     time.Duration
+	cleanup     bool
+}
+
+// Bind adds the loadtest specific arguments to the flagset passed in as an
+// argument.
+func (l *loadTest) Bind(flags *pflag.FlagSet) {
+	flags.IntVar(&l.count, "count", 100, "The number of federated resources to create.")
+	flags.StringSliceVar(&l.typeNames, "types", []string{"configmaps"},
+		fmt.Sprintf("Comma separated names of the types of resources to create. Resources are created in equal numbers of each type. Supported types are %v.", supportedTypeNames))
+	flags.IntVar(&l.size, "size", 1024, "The size in bytes of the data of each resource.")
+	flags.StringSliceVar(&l.clusters, "clusters", []string{}, "Comma separated names of the clusters to place resources on. Defaults to all member clusters.")
+	flags.StringVar(&l.namespace, "namespace", "kubefed-loadtest",
+		"The namespace to create resources in. Ignored if the control plane is namespace-scoped, in which case resources are created in the KubeFed system namespace.")
+	flags.IntVar(&l.concurrency, "concurrency", 10, "The number of resources to create concurrently.")
+	flags.DurationVar(&l.timeout, "timeout", 5*time.Minute, "The maximum time to wait for resources to be propagated.")
+	flags.BoolVar(&l.cleanup, "cleanup", true, "Whether to delete the resources created by the test once it completes.")
+}
+
+// Complete ensures that options are valid.
+func (l *loadTest) Complete(args []string) error {
+	if len(args) > 0 {
+		return errors.Errorf("The loadtest command does not take any args. Got args: %v", args)
+	}
+	if l.count < 1 {
+		return errors.New("--count must be at least 1")
+	}
+	if l.size < 0 {
+		return errors.New("--size must not be negative")
+	}
+	if l.concurrency < 1 {
+		return errors.New("--concurrency must be at least 1")
+	}
+	if len(l.typeNames) == 0 {
+		return errors.New("--types must not be empty")
+	}
+	supported := sets.NewString(supportedTypeNames...)
+	for _, typeName := range l.typeNames {
+		if !supported.Has(typeName) {
+			return errors.Errorf("Unsupported type %q. Supported types are %v", typeName, supportedTypeNames)
+		}
+	}
+	return nil
+}
+
+// NewCmdLoadTest defines the `loadtest` command that measures the
+// propagation latency of federated resources.
+func NewCmdLoadTest(cmdOut io.Writer, config util.FedConfig) *cobra.Command {
+	opts := &loadTest{}
+
+	cmd := &cobra.Command{
+		Use:     "loadtest",
+		Short:   "Loadtest measures the propagation latency of generated federated resources",
+		Long:    loadtest_long,
+		Example: loadtest_example,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := opts.Complete(args)
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+
+			err = opts.Run(cmdOut, config)
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+		},
+	}
+
+	flags := cmd.Flags()
+	opts.GlobalSubcommandBind(flags)
+	opts.Bind(flags)
+
+	return cmd
+}
+
+// loadTestType holds the clients and results for one type of resource
+// created by the test.
+type loadTestType struct {
+	typeConfig typeconfig.Interface
+	client     ctlutil.ResourceClient
+
+	sync.Mutex
+	// Times at which the resources were created, by name.
+	created map[string]time.Time
+	// Times taken to propagate the resources, by name.
+	propagated map[string]time.Duration
+	// Resources that could not be created.
+	failed int
+}
+
+// Run is the implementation of the `loadtest` command.
+func (l *loadTest) Run(cmdOut io.Writer, config util.FedConfig) error {
+	hostConfig, err := config.HostConfig(l.HostClusterContext, l.Kubeconfig)
+	if err != nil {
+		return errors.Wrap(err, "Failed to get host cluster config")
+	}
+	client, err := genericclient.New(hostConfig)
+	if err != nil {
+		return errors.Wrap(err, "Failed to get kubefed clientset")
+	}
+
+	clusterNames, err := l.targetClusterNames(client)
+	if err != nil {
+		return err
+	}
+
+	scope, err := options.GetScopeFromKubeFedConfig(hostConfig, l.KubeFedNamespace)
+	if err != nil {
+		return err
+	}
+	namespaced := scope == apiextv1b1.NamespaceScoped
+	if namespaced {
+		l.namespace = l.KubeFedNamespace
+	}
+
+	fmt.Fprintf(cmdOut, "Creating %d federated resources of types %v with %d bytes of data in namespace %q placed on clusters %v\n",
+		l.count, l.typeNames, l.size, l.namespace, clusterNames)
+	if l.DryRun {
+		return nil
+	}
+
+	types := make([]*loadTestType, len(l.typeNames))
+	for i, typeName := range l.typeNames {
+		types[i], err = newLoadTestType(hostConfig, client, typeName, l.KubeFedNamespace)
+		if err != nil {
+			return err
+		}
+	}
+
+	createdNamespace := false
+	if !namespaced {
+		createdNamespace, err = l.createNamespace(hostConfig, client, clusterNames)
+		if err != nil {
+			return err
+		}
+	}
+	if l.cleanup {
+		defer l.deleteResources(hostConfig, types, createdNamespace)
+	}
+
+	stopChan := make(chan struct{})
+	defer close(stopChan)
+	for _, t := range types {
+		t.watch(l.namespace, clusterNames, stopChan)
+	}
+
+	start := time.Now()
+	l.createResources(types, clusterNames)
+	l.waitForPropagation(types)
+	elapsed := time.Since(start)
+
+	writeReport(cmdOut, types, l.count, elapsed)
+	return nil
+}
+
+// targetClusterNames returns the names of the clusters that
+// resources should be placed on.
+func (l *loadTest) targetClusterNames(client genericclient.Client) ([]string, error) {
+	clusterList := &fedv1b1.KubeFedClusterList{}
+	err := client.List(context.TODO(), clusterList, l.KubeFedNamespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list member clusters")
+	}
+	joined := sets.NewString()
+	for _, cluster := range clusterList.Items {
+		joined.Insert(cluster.Name)
+	}
+	if len(l.clusters) == 0 {
+		if joined.Len() == 0 {
+			return nil, errors.New("No member clusters are joined")
+		}
+		return joined.List(), nil
+	}
+	for _, clusterName := range l.clusters {
+		if !joined.Has(clusterName) {
+			return nil, errors.Errorf("Cluster %q is not joined", clusterName)
+		}
+	}
+	return sets.NewString(l.clusters...).List(), nil
+}
+
+func newLoadTestType(hostConfig *rest.Config, client genericclient.Client, typeName, kubefedNamespace string) (*loadTestType, error) {
+	typeConfig, err := getTypeConfig(client, typeName, kubefedNamespace)
+	if err != nil {
+		return nil, err
+	}
+	federatedType := typeConfig.GetFederatedType()
+	resourceClient, err := ctlutil.NewResourceClient(hostConfig, &federatedType)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error creating client for %s", federatedType.Kind)
+	}
+	return &loadTestType{
+		typeConfig: typeConfig,
+		client:     resourceClient,
+		created:    make(map[string]time.Time),
+		propagated: make(map[string]time.Duration),
+	}, nil
+}
+
+func getTypeConfig(client genericclient.Client, typeName, kubefedNamespace string) (typeconfig.Interface, error) {
+	typeConfig := &fedv1b1.FederatedTypeConfig{}
+	err := client.Get(context.TODO(), typeConfig, kubefedNamespace, typeName)
+	if apierrors.IsNotFound(err) {
+		return nil, errors.Errorf("Federation of type %q is not enabled. Consider using 'kubefedctl enable %s'", typeName, typeName)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to retrieve FederatedTypeConfig %q", typeName)
+	}
+	return typeConfig, nil
+}
+
+// createNamespace ensures the test namespace exists and is federated
+// to the given clusters.  Whether the namespace was created by the
+// test is returned.
+func (l *loadTest) createNamespace(hostConfig *rest.Config, client genericclient.Client, clusterNames []string) (bool, error) {
+	hostClientset, err := util.HostClientset(hostConfig)
+	if err != nil {
+		return false, errors.Wrap(err, "Failed to get host cluster clientset")
+	}
+	namespace := &apiv1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: l.namespace},
+	}
+	created := true
+	_, err = hostClientset.CoreV1().Namespaces().Create(namespace)
+	if apierrors.IsAlreadyExists(err) {
+		created = false
+	} else if err != nil {
+		return false, errors.Wrapf(err, "Failed to create namespace %q", l.namespace)
+	}
+
+	namespaceType, err := newLoadTestType(hostConfig, client, namespaceTypeName, l.KubeFedNamespace)
+	if err != nil {
+		return created, err
+	}
+	fedNamespace := newFederatedResource(namespaceType.typeConfig, l.namespace, l.namespace)
+	err = ctlutil.SetClusterNames(fedNamespace, clusterNames)
+	if err != nil {
+		return created, err
+	}
+	_, err = namespaceType.client.Resources(l.namespace).Create(fedNamespace, metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return created, errors.Wrapf(err, "Failed to federate namespace %q", l.namespace)
+	}
+	return created, nil
+}
+
+// createResources creates the federated resources of the test,
+// distributing them evenly across the given types.
+func (l *loadTest) createResources(types []*loadTestType, clusterNames []string) {
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < l.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				t := types[index%len(types)]
+				name := fmt.Sprintf("%s-%d", resourceName, index)
+				t.create(l.namespace, name, l.size, clusterNames)
+			}
+		}()
+	}
+	for i := 0; i < l.count; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
+
+// waitForPropagation waits until all created resources have been
+// propagated or the timeout is reached.
+func (l *loadTest) waitForPropagation(types []*loadTestType) {
+	err := wait.PollImmediate(time.Second, l.timeout, func() (bool, error) {
+		for _, t := range types {
+			if !t.allPropagated() {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		klog.Warningf("Timed out after %v waiting for resources to be propagated", l.timeout)
+	}
+}
+
+// deleteResources removes the resources created by the test.
+func (l *loadTest) deleteResources(hostConfig *rest.Config, types []*loadTestType, deleteNamespace bool) {
+	if deleteNamespace {
+		// Deleting the namespace removes the resources it contains.
+		hostClientset, err := util.HostClientset(hostConfig)
+		if err == nil {
+			err = hostClientset.CoreV1().Namespaces().Delete(l.namespace, &metav1.DeleteOptions{})
+		}
+		if err != nil && !apierrors.IsNotFound(err) {
+			klog.Errorf("Failed to delete namespace %q: %v", l.namespace, err)
+		}
+		return
+	}
+	for _, t := range types {
+		for name := range t.created {
+			err := t.client.Resources(l.namespace).Delete(name, &metav1.DeleteOptions{})
+			if err != nil && !apierrors.IsNotFound(err) {
+				klog.Errorf("Failed to delete %s %q: %v", t.client.Kind(), name, err)
+			}
+		}
+	}
+}
+
+func newFederatedResource(typeConfig typeconfig.Interface, namespace, name string) *unstructured.Unstructured {
+	federatedType := typeConfig.GetFederatedType()
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	obj.SetAPIVersion(fmt.Sprintf("%s/%s", federatedType.Group, federatedType.Version))
+	obj.SetKind(federatedType.Kind)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	return obj
+}
+
+func (t *loadTestType) create(namespace, name string, size int, clusterNames []string) {
+	obj := newFederatedResource(t.typeConfig, namespace, name)
+	payload := strings.Repeat("x", size)
+	if t.typeConfig.GetTargetType().Kind == "Secret" {
+		payload = base64.StdEncoding.EncodeToString([]byte(payload))
+	}
+	err := unstructured.SetNestedField(obj.Object, map[string]interface{}{payloadKey: payload}, ctlutil.SpecField, ctlutil.TemplateField, "data")
+	if err == nil {
+		err = ctlutil.SetClusterNames(obj, clusterNames)
+	}
+	if err != nil {
+		klog.Errorf("Failed to generate %s %q: %v", t.client.Kind(), name, err)
+		t.recordFailure()
+		return
+	}
+
+	// Record the creation time before the resource is created so that
+	// its propagation can be observed as soon as it is created.
+	t.Lock()
+	t.created[name] = time.Now()
+	t.Unlock()
+	_, err = t.client.Resources(namespace).Create(obj, metav1.CreateOptions{})
+	if err != nil {
+		klog.Errorf("Failed to create %s %q: %v", t.client.Kind(), name, err)
+		t.Lock()
+		delete(t.created, name)
+		t.Unlock()
+		t.recordFailure()
+	}
+}
+
+func (t *loadTestType) recordFailure() {
+	t.Lock()
+	defer t.Unlock()
+	t.failed++
+}
+
+// watch records the time at which created resources are observed to
+// be propagated to all of the given clusters.
+func (t *loadTestType) watch(namespace string, clusterNames []string, stopChan <-chan struct{}) {
+	_, controller := ctlutil.NewResourceInformer(t.client, namespace, func(obj pkgruntime.Object) {
+		observed := time.Now()
+		fedObj := obj.(*unstructured.Unstructured)
+		if !isPropagated(fedObj, clusterNames) {
+			return
+		}
+		name := fedObj.GetName()
+		t.Lock()
+		defer t.Unlock()
+		created, ok := t.created[name]
+		if _, done := t.propagated[name]; ok && !done {
+			t.propagated[name] = observed.Sub(created)
+		}
+	})
+	go controller.Run(stopChan)
+}
+
+func (t *loadTestType) allPropagated() bool {
+	t.Lock()
+	defer t.Unlock()
+	return len(t.propagated) == len(t.created)
+}
+
+// isPropagated indicates whether the status of the federated resource
+// reports successful propagation to all of the given clusters.
+func isPropagated(obj *unstructured.Unstructured, clusterNames []string) bool {
+	fedStatus := &status.GenericFederatedStatus{}
+	err := pkgruntime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, fedStatus)
+	if err != nil || fedStatus.Status == nil {
+		return false
+	}
+	propagated := false
+	for _, condition := range fedStatus.Status.Conditions {
+		if condition.Type == status.PropagationConditionType && condition.Status == apiv1.ConditionTrue {
+			propagated = true
+		}
+	}
+	if !propagated {
+		return false
+	}
+	okClusters := sets.NewString()
+	for _, cluster := range fedStatus.Status.Clusters {
+		if cluster.Status == status.ClusterPropagationOK {
+			okClusters.Insert(cluster.Name)
+		}
+	}
+	return okClusters.HasAll(clusterNames...)
+}
+
+func writeReport(cmdOut io.Writer, types []*loadTestType, count int, elapsed time.Duration) {
+	var all []time.Duration
+	failed := 0
+	for _, t := range types {
+		t.Lock()
+		for _, latency := range t.propagated {
+			all = append(all, latency)
+		}
+		failed += t.failed
+		t.Unlock()
+	}
+
+	fmt.Fprintf(cmdOut, "Propagated %d of %d resources in %v (%d failed to be created)\n", len(all), count, elapsed.Round(time.Millisecond), failed)
+	if len(all) == 0 {
+		return
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i] < all[j] })
+	fmt.Fprintf(cmdOut, "Propagation latency: p50=%v p90=%v p99=%v max=%v\n",
+		percentile(all, 50), percentile(all, 90), percentile(all, 99), all[len(all)-1].Round(time.Millisecond))
+	fmt.Fprintf(cmdOut, "Throughput: %.1f resources/s\n", float64(len(all))/elapsed.Seconds())
+}
+
+// percentile returns the given percentile of the sorted durations
+// using the nearest-rank method.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1].Round(time.Millisecond)
+}
@@ -0,0 +1,394 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logs
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	apiv1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	kubeclient "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog"
+
+	"sigs.k8s.io/kubefed/pkg/apis/core/typeconfig"
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	ctlutil "sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/enable"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/options"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/util"
+)
+
+const (
+	allClusters = "all"
+	podKind     = "Pod"
+
+	// The maximum length of a log line that can be printed.
+	maxLineSize = 1024 * 1024
+)
+
+var (
+	logs_long = `
+		Logs prints the logs of the pods of a federated workload in its
+		member clusters, prefixing each line with the name of the
+		cluster, pod and container that logged it.
+
+		The federated resource is identified as TYPE/NAME, where TYPE
+		is the federated type (e.g. federateddeployment) or its target
+		type (e.g. deployment). The pods of a propagated resource are
+		found with the label selector of the resource, or the resource
+		itself if it is a pod. Logs are retrieved from the clusters the
+		resource has been propagated to unless --cluster is provided.
+
+		Current context is assumed to be a Kubernetes cluster hosting
+		the kubefed control plane. Please use the --host-cluster-context
+		flag otherwise.`
+
+	logs_example = `
+		# Print the logs of the pods of federated deployment "foo" in all clusters
+		kubefedctl logs federateddeployment/foo -n my-ns --host-cluster-context=cluster1
+
+		# Follow the logs of container "web" of deployment "foo" in clusters cluster2 and cluster3
+		kubefedctl logs deployment/foo -n my-ns -c web -f --cluster=cluster2,cluster3 --host-cluster-context=cluster1`
+)
+
+type logsOptions struct {
+	options.GlobalSubcommandOptions
+	typeName     string
+	resourceName string
+	namespace    string
+	clusters     []string
+	container    string
+	follow       bool
+	tail         int64
+	since        time.Duration
+	timestamps   bool
+	prefix       bool
+}
+
+// Bind adds the logs specific arguments to the flagset passed in as an
+// argument.
+func (o *logsOptions) Bind(flags *pflag.FlagSet) {
+	flags.StringVarP(&o.namespace, "namespace", "n", "default", "The namespace of the federated resource.")
+	flags.StringSliceVar(&o.clusters, "cluster", []string{allClusters},
+		"Comma separated names of the clusters to retrieve logs from, or 'all' for the clusters the resource has been propagated to.")
+	flags.StringVarP(&o.container, "container", "c", "", "The container to print the logs of. Defaults to all containers of each pod.")
+	flags.BoolVarP(&o.follow, "follow", "f", false, "Whether to stream the logs as they are written.")
+	flags.Int64Var(&o.tail, "tail", -1, "The number of most recent lines of each container to print. Defaults to all lines.")
+	flags.DurationVar(&o.since, "since", 0, "Only print logs newer than the given duration (e.g. 5s, 2m or 3h). Defaults to all logs.")
+	flags.BoolVar(&o.timestamps, "timestamps", false, "Whether to include the timestamp of each line.")
+	flags.BoolVar(&o.prefix, "prefix", true, "Whether to prefix each line with the cluster, pod and container that logged it.")
+}
+
+// Complete ensures that options are valid.
+func (o *logsOptions) Complete(args []string) error {
+	if len(args) != 1 {
+		return errors.New("TYPE/NAME is required")
+	}
+	parts := strings.SplitN(args[0], "/", 2)
+	if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+		return errors.Errorf("Invalid resource %q. The resource must be given as TYPE/NAME", args[0])
+	}
+	o.typeName, o.resourceName = parts[0], parts[1]
+	if len(o.clusters) == 0 {
+		return errors.New("--cluster must not be empty")
+	}
+	if len(o.clusters) > 1 && sets.NewString(o.clusters...).Has(allClusters) {
+		return errors.Errorf("--cluster=%s may not be combined with cluster names", allClusters)
+	}
+	if o.since < 0 {
+		return errors.New("--since must not be negative")
+	}
+	return nil
+}
+
+// NewCmdLogs defines the `logs` command that prints the logs of the
+// pods of a federated workload in member clusters.
+func NewCmdLogs(cmdOut io.Writer, config util.FedConfig) *cobra.Command {
+	opts := &logsOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "logs TYPE/NAME",
+		Short:   "Print the logs of the pods of a federated workload in member clusters",
+		Long:    logs_long,
+		Example: logs_example,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := opts.Complete(args)
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+
+			err = opts.Run(cmdOut, config)
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+		},
+	}
+
+	flags := cmd.Flags()
+	opts.GlobalSubcommandBind(flags)
+	opts.Bind(flags)
+
+	return cmd
+}
+
+// logStream is a log of a container in a member cluster.
+type logStream struct {
+	clusterName string
+	clientset   kubeclient.Interface
+	pod         *apiv1.Pod
+	container   string
+}
+
+// Run is the implementation of the `logs` command.
+func (o *logsOptions) Run(cmdOut io.Writer, config util.FedConfig) error {
+	hostConfig, err := config.HostConfig(o.HostClusterContext, o.Kubeconfig)
+	if err != nil {
+		return errors.Wrap(err, "Failed to get host cluster config")
+	}
+	client, err := genericclient.New(hostConfig)
+	if err != nil {
+		return errors.Wrap(err, "Failed to get kubefed clientset")
+	}
+
+	typeConfig, err := enable.LookupTypeConfig(hostConfig, client, o.typeName, o.KubeFedNamespace)
+	if err != nil {
+		return err
+	}
+	federatedType := typeConfig.GetFederatedType()
+	fedObj := &unstructured.Unstructured{}
+	fedObj.SetAPIVersion(fmt.Sprintf("%s/%s", federatedType.Group, federatedType.Version))
+	fedObj.SetKind(federatedType.Kind)
+	err = client.Get(context.TODO(), fedObj, o.namespace, o.resourceName)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to retrieve %s %q", federatedType.Kind,
+			ctlutil.QualifiedName{Namespace: o.namespace, Name: o.resourceName})
+	}
+
+	clusters, err := o.logClusters(client, fedObj)
+	if err != nil {
+		return err
+	}
+	overrides, err := ctlutil.GetOverrides(fedObj)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to read the overrides of %s %q", federatedType.Kind, o.resourceName)
+	}
+
+	streams := []logStream{}
+	for _, cluster := range clusters {
+		clusterStreams, err := o.clusterLogStreams(hostConfig, client, typeConfig, cluster, overrides[cluster.Name])
+		if err != nil {
+			klog.Errorf("Failed to find the pods of %s %q in cluster %q: %v", federatedType.Kind, o.resourceName, cluster.Name, err)
+			continue
+		}
+		streams = append(streams, clusterStreams...)
+	}
+	if len(streams) == 0 {
+		return errors.Errorf("No pods of %s %q were found in clusters %v", federatedType.Kind, o.resourceName, clusterNames(clusters))
+	}
+	if o.DryRun {
+		for _, stream := range streams {
+			fmt.Fprintf(cmdOut, "Would print the logs of container %q of pod %q in cluster %q\n", stream.container, stream.pod.Name, stream.clusterName)
+		}
+		return nil
+	}
+
+	var outLock sync.Mutex
+	var wg sync.WaitGroup
+	for _, stream := range streams {
+		wg.Add(1)
+		go func(stream logStream) {
+			defer wg.Done()
+			if err := o.printLogs(cmdOut, &outLock, stream); err != nil {
+				klog.Errorf("Failed to retrieve the logs of container %q of pod %q in cluster %q: %v", stream.container, stream.pod.Name, stream.clusterName, err)
+			}
+		}(stream)
+	}
+	wg.Wait()
+	return nil
+}
+
+// logClusters returns the clusters to retrieve logs from.
+func (o *logsOptions) logClusters(client genericclient.Client, fedObj *unstructured.Unstructured) ([]*fedv1b1.KubeFedCluster, error) {
+	clusterList := &fedv1b1.KubeFedClusterList{}
+	err := client.List(context.TODO(), clusterList, o.KubeFedNamespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list member clusters")
+	}
+	joined := make(map[string]*fedv1b1.KubeFedCluster)
+	for i := range clusterList.Items {
+		joined[clusterList.Items[i].Name] = &clusterList.Items[i]
+	}
+
+	names := o.clusters
+	if len(names) == 1 && names[0] == allClusters {
+		names, err = util.PropagatedClusterNames(fedObj)
+		if err != nil {
+			return nil, err
+		}
+	}
+	clusters := []*fedv1b1.KubeFedCluster{}
+	for _, name := range sets.NewString(names...).List() {
+		cluster, ok := joined[name]
+		if !ok {
+			return nil, errors.Errorf("Cluster %q is not joined", name)
+		}
+		clusters = append(clusters, cluster)
+	}
+	return clusters, nil
+}
+
+// clusterLogStreams returns the logs of the containers of the pods of
+// the propagated resource in the given cluster.
+func (o *logsOptions) clusterLogStreams(hostConfig *rest.Config, client genericclient.Client, typeConfig typeconfig.Interface,
+	cluster *fedv1b1.KubeFedCluster, overrides ctlutil.ClusterOverridesMap) ([]logStream, error) {
+
+	clusterConfig, err := ctlutil.BuildClusterConfig(cluster, hostConfig, client, o.KubeFedNamespace)
+	if err != nil {
+		return nil, err
+	}
+	if clusterConfig == nil {
+		return nil, errors.Errorf("Unable to load configuration for cluster %q", cluster.Name)
+	}
+	clientset, err := util.ClusterClientset(clusterConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	targetType := typeConfig.GetTargetType()
+	targetName := util.ClusterTargetName(cluster, overrides, false, ctlutil.QualifiedName{Namespace: o.namespace, Name: o.resourceName})
+
+	pods := []apiv1.Pod{}
+	if targetType.Group == "" && targetType.Kind == podKind {
+		pod, err := clientset.CoreV1().Pods(targetName.Namespace).Get(targetName.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		pods = append(pods, *pod)
+	} else {
+		selector, err := targetSelector(clusterConfig, &targetType, targetName)
+		if err != nil {
+			return nil, err
+		}
+		podList, err := clientset.CoreV1().Pods(targetName.Namespace).List(metav1.ListOptions{LabelSelector: selector.String()})
+		if err != nil {
+			return nil, err
+		}
+		pods = podList.Items
+	}
+
+	streams := []logStream{}
+	for i := range pods {
+		pod := &pods[i]
+		for _, container := range pod.Spec.Containers {
+			if len(o.container) > 0 && container.Name != o.container {
+				continue
+			}
+			streams = append(streams, logStream{
+				clusterName: cluster.Name,
+				clientset:   clientset,
+				pod:         pod,
+				container:   container.Name,
+			})
+		}
+	}
+	return streams, nil
+}
+
+// targetSelector returns the pod selector of the named resource of
+// the target type in a member cluster.
+func targetSelector(clusterConfig *rest.Config, targetType *metav1.APIResource, targetName ctlutil.QualifiedName) (labels.Selector, error) {
+	targetClient, err := ctlutil.NewResourceClient(clusterConfig, targetType)
+	if err != nil {
+		return nil, err
+	}
+	target, err := targetClient.Resources(targetName.Namespace).Get(targetName.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, errors.Errorf("%s %q has not been propagated", targetType.Kind, targetName)
+	}
+	if err != nil {
+		return nil, err
+	}
+	selectorMap, ok, err := unstructured.NestedMap(target.Object, "spec", "selector")
+	if err != nil || !ok {
+		return nil, errors.Errorf("%s %q does not have a pod selector", targetType.Kind, targetName)
+	}
+	labelSelector := &metav1.LabelSelector{}
+	err = ctlutil.UnstructuredToInterface(&unstructured.Unstructured{Object: selectorMap}, labelSelector)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to read the pod selector of %s %q", targetType.Kind, targetName)
+	}
+	return metav1.LabelSelectorAsSelector(labelSelector)
+}
+
+// printLogs writes the lines of the given log to the output with a
+// prefix identifying the cluster, pod and container.
+func (o *logsOptions) printLogs(cmdOut io.Writer, outLock *sync.Mutex, stream logStream) error {
+	logOptions := &apiv1.PodLogOptions{
+		Container:  stream.container,
+		Follow:     o.follow,
+		Timestamps: o.timestamps,
+	}
+	if o.tail >= 0 {
+		logOptions.TailLines = &o.tail
+	}
+	if o.since > 0 {
+		sinceSeconds := int64(o.since.Seconds())
+		logOptions.SinceSeconds = &sinceSeconds
+	}
+	reader, err := stream.clientset.CoreV1().Pods(stream.pod.Namespace).GetLogs(stream.pod.Name, logOptions).Stream()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	prefix := ""
+	if o.prefix {
+		prefix = fmt.Sprintf("[%s/%s/%s] ", stream.clusterName, stream.pod.Name, stream.container)
+	}
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	for scanner.Scan() {
+		outLock.Lock()
+		fmt.Fprintf(cmdOut, "%s%s\n", prefix, scanner.Text())
+		outLock.Unlock()
+	}
+	return scanner.Err()
+}
+
+func clusterNames(clusters []*fedv1b1.KubeFedCluster) []string {
+	names := []string{}
+	for _, cluster := range clusters {
+		names = append(names, cluster.Name)
+	}
+	return names
+}
@@ -55,32 +55,96 @@ type CommonJoinOptions struct {
 	ClusterName     string
 	ClusterContext  string
 	HostClusterName string
+	// ClusterNames are the names of all the clusters passed as
+	// positional arguments, of which ClusterName is the first.
+	ClusterNames []string
+	ClustersFile string
+	Concurrency  int
 }
 
 // CommonSubcommandBind adds the common subcommand flags to the flagset passed in.
 func (o *CommonJoinOptions) CommonSubcommandBind(flags *pflag.FlagSet) {
 	flags.StringVar(&o.ClusterContext, "cluster-context", "",
-		"Name of the cluster's context in the local kubeconfig. Defaults to cluster name if unspecified.")
+		"Name of the cluster's context in the local kubeconfig. Defaults to cluster name if unspecified. May only be set for a single cluster.")
 	flags.StringVar(&o.HostClusterName, "host-cluster-name", "",
 		"If set, overrides the use of host-cluster-context name in resource names created in the target cluster. This option must be used when the context name has characters invalid for kubernetes resources like \"/\" and \":\".")
+	flags.StringVar(&o.ClustersFile, "clusters-file", "",
+		"Path to a YAML file listing the clusters to operate on, as a list of entries with a name and, optionally, a context and secretName. May not be combined with cluster names passed as arguments.")
+	flags.IntVar(&o.Concurrency, "concurrency", 5,
+		"The number of clusters to operate on concurrently when more than one cluster is given.")
 }
 
 // SetName sets the name from the args passed in for the required positional
-// argument.
+// argument.  Names are not required if a clusters file is given.
 func (o *CommonJoinOptions) SetName(args []string) error {
-	if len(args) == 0 {
+	if len(o.ClustersFile) > 0 {
+		if len(args) > 0 {
+			return errors.New("cluster names may not be passed with --clusters-file")
+		}
+	} else if len(args) == 0 {
 		return errors.New("NAME is required")
 	}
+	if o.Concurrency < 1 {
+		return errors.New("--concurrency must be at least 1")
+	}
 
-	o.ClusterName = args[0]
+	o.ClusterNames = args
+	if len(args) > 0 {
+		o.ClusterName = args[0]
+	}
 	return nil
 }
 
 func GetScopeFromKubeFedConfig(hostConfig *rest.Config, namespace string) (apiextv1b1.ResourceScope, error) {
+	fedConfig, err := getKubeFedConfig(hostConfig, namespace)
+	if err != nil {
+		return "", err
+	}
+
+	// The scope may be sourced from a default KubeFedConfig.
+	if len(fedConfig.Spec.Scope) == 0 && fedConfig.Status.EffectiveSpec != nil {
+		return fedConfig.Status.EffectiveSpec.Scope, nil
+	}
+	return fedConfig.Spec.Scope, nil
+}
+
+// GetAllowedClusterScopedResourcesFromKubeFedConfig returns the
+// cluster-scoped resources that a namespace-scoped control plane is
+// allowed to propagate.
+func GetAllowedClusterScopedResourcesFromKubeFedConfig(hostConfig *rest.Config, namespace string) ([]fedv1b1.AllowedClusterScopedResource, error) {
+	fedConfig, err := getKubeFedConfig(hostConfig, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	// The allowlist may be sourced from a default KubeFedConfig.
+	if fedConfig.Spec.AllowedClusterScopedResources == nil && fedConfig.Status.EffectiveSpec != nil {
+		return fedConfig.Status.EffectiveSpec.AllowedClusterScopedResources, nil
+	}
+	return fedConfig.Spec.AllowedClusterScopedResources, nil
+}
+
+// GetEffectiveKubeFedConfigSpec returns the configuration of the
+// KubeFed control plane in the given namespace as applied by the
+// controller manager, including defaults, and whether it was applied.
+// The spec of the KubeFedConfig is returned if the controller manager
+// has not recorded the applied configuration.
+func GetEffectiveKubeFedConfigSpec(hostConfig *rest.Config, namespace string) (*fedv1b1.KubeFedConfigSpec, bool, error) {
+	fedConfig, err := getKubeFedConfig(hostConfig, namespace)
+	if err != nil {
+		return nil, false, err
+	}
+	if fedConfig.Status.EffectiveSpec != nil {
+		return fedConfig.Status.EffectiveSpec, true, nil
+	}
+	return &fedConfig.Spec, false, nil
+}
+
+func getKubeFedConfig(hostConfig *rest.Config, namespace string) (*fedv1b1.KubeFedConfig, error) {
 	client, err := genericclient.New(hostConfig)
 	if err != nil {
 		err = errors.Wrap(err, "Failed to get kubefed clientset")
-		return "", err
+		return nil, err
 	}
 
 	fedConfig := &fedv1b1.KubeFedConfig{}
@@ -91,10 +155,9 @@ func GetScopeFromKubeFedConfig(hostConfig *rest.Config, namespace string) (apiex
 			Name:      util.KubeFedConfigName,
 		}
 		err = errors.Wrapf(err, "Error retrieving KubeFedConfig %q", config)
-		return "", err
+		return nil, err
 	}
-
-	return fedConfig.Spec.Scope, nil
+	return fedConfig, nil
 }
 
 // CommonEnableOptions holds the common configuration required by the enable
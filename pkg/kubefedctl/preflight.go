@@ -0,0 +1,418 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubefedctl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	admissionv1b1 "k8s.io/api/admissionregistration/v1beta1"
+	authv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apiextv1b1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	kubeclient "k8s.io/client-go/kubernetes"
+	"k8s.io/klog"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	ctlutil "sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/util"
+)
+
+const (
+	// minSupportedKubernetesVersion is the oldest version of
+	// kubernetes supported for host and member clusters.
+	minSupportedKubernetesVersion = "1.13"
+
+	// maxSupportedMinorVersionSkew is the largest difference between
+	// the minor versions of the host and a member cluster that is
+	// supported.
+	maxSupportedMinorVersionSkew = 2
+)
+
+// accessCheck describes an action that join needs to be permitted to
+// perform in a cluster.
+type accessCheck struct {
+	verb      string
+	group     string
+	resource  string
+	namespace string
+}
+
+func (c accessCheck) String() string {
+	resource := c.resource
+	if c.group != "" {
+		resource = fmt.Sprintf("%s.%s", c.resource, c.group)
+	}
+	if c.namespace == "" {
+		return fmt.Sprintf("%s %s", c.verb, resource)
+	}
+	return fmt.Sprintf("%s %s in namespace %s", c.verb, resource, c.namespace)
+}
+
+// performPreflightChecks checks that the host and joining clusters are in
+// a consistent state and, unless skipChecks is true, that the join can be
+// completed. rejoin indicates whether the cluster has already been
+// joined, and secretNamespace is the namespace of the host cluster in
+// which its credentials are stored. local indicates that the cluster
+// is joined as the host cluster itself, which requires no service
+// account or secret. All problems found are reported together so that they can be
+// addressed before any resources are created.
+func performPreflightChecks(hostClientset, clusterClientset kubeclient.Interface, name, hostClusterName,
+	kubefedNamespace, secretNamespace string, scope apiextv1b1.ResourceScope, local, rejoin, errorOnExisting, skipChecks bool) error {
+	var problems []error
+	if rejoin && errorOnExisting {
+		problems = append(problems, errors.Errorf("federated cluster %s already exists in host cluster", name))
+	}
+	if !local {
+		if err := checkExistingServiceAccount(clusterClientset, name, hostClusterName, kubefedNamespace, errorOnExisting); err != nil {
+			problems = append(problems, err)
+		}
+	}
+	if !skipChecks {
+		if err := checkLocalCluster(hostClientset, clusterClientset, name, kubefedNamespace, local); err != nil {
+			problems = append(problems, err)
+		}
+		problems = append(problems, checkVersionSkew(hostClientset, clusterClientset, hostClusterName, name)...)
+		problems = append(problems, checkJoinPermissions(hostClientset, clusterClientset, hostClusterName, name, kubefedNamespace, secretNamespace, scope, local, rejoin)...)
+		problems = append(problems, checkAdmissionWebhooks(hostClientset, hostClusterName)...)
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+
+	messages := []string{}
+	for _, problem := range problems {
+		messages = append(messages, problem.Error())
+	}
+	return errors.Errorf("Preflight checks failed:\n\t- %s", strings.Join(messages, "\n\t- "))
+}
+
+// checkExistingServiceAccount makes sure there is no existing service
+// account in the joining cluster if existing artifacts are an error.
+func checkExistingServiceAccount(clusterClientset kubeclient.Interface, name, hostClusterName,
+	kubefedNamespace string, errorOnExisting bool) error {
+	saName := util.ClusterServiceAccountName(name, hostClusterName)
+	_, err := clusterClientset.CoreV1().ServiceAccounts(kubefedNamespace).Get(saName,
+		metav1.GetOptions{})
+
+	switch {
+	case apierrors.IsNotFound(err):
+		return nil
+	case err != nil:
+		return err
+	case errorOnExisting:
+		return errors.Errorf("service account: %s already exists in joining cluster: %s", saName, name)
+	default:
+		klog.V(2).Infof("Service account %s already exists in joining cluster %s", saName, name)
+		return nil
+	}
+}
+
+// checkLocalCluster checks that a cluster joined as local is the host
+// cluster, and suggests joining the host cluster as local if it is
+// joined via its api endpoint.  As for unjoin, the host cluster is
+// identified by the uid of the KubeFed namespace, and the check is
+// skipped if the namespace cannot be read.
+func checkLocalCluster(hostClientset, clusterClientset kubeclient.Interface, name, kubefedNamespace string, local bool) error {
+	isHost, err := isHostCluster(hostClientset, clusterClientset, kubefedNamespace)
+	if err != nil {
+		klog.V(2).Infof("Unable to determine whether cluster %s is the host cluster: %v", name, err)
+		return nil
+	}
+	switch {
+	case local && !isHost:
+		return errors.Errorf("cluster %s is not the host cluster and cannot be joined as local", name)
+	case !local && isHost:
+		klog.Warningf("Cluster %s is the host cluster. Joining it with --local allows the control plane to access it without a service account token.", name)
+	}
+	return nil
+}
+
+// isHostCluster indicates whether the joining cluster is the host
+// cluster.
+func isHostCluster(hostClientset, clusterClientset kubeclient.Interface, kubefedNamespace string) (bool, error) {
+	hostNamespace, err := hostClientset.CoreV1().Namespaces().Get(kubefedNamespace, metav1.GetOptions{})
+	if err != nil {
+		return false, err
+	}
+	clusterNamespace, err := clusterClientset.CoreV1().Namespaces().Get(kubefedNamespace, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+	return ctlutil.IsPrimaryCluster(hostNamespace, clusterNamespace), nil
+}
+
+// checkVersionSkew checks that the versions of the host and joining
+// clusters are supported and that the skew between them is within the
+// supported range.
+func checkVersionSkew(hostClientset, clusterClientset kubeclient.Interface, hostClusterName, name string) []error {
+	var problems []error
+	hostVersion, err := serverMinorVersion(hostClientset, "host cluster", hostClusterName)
+	if err != nil {
+		problems = append(problems, err)
+	}
+	clusterVersion, err := serverMinorVersion(clusterClientset, "joining cluster", name)
+	if err != nil {
+		problems = append(problems, err)
+	}
+	if hostVersion == nil || clusterVersion == nil {
+		return problems
+	}
+
+	skew := hostVersion[1] - clusterVersion[1]
+	if skew < 0 {
+		skew = -skew
+	}
+	if hostVersion[0] != clusterVersion[0] || skew > maxSupportedMinorVersionSkew {
+		problems = append(problems, errors.Errorf("the skew between kubernetes version %d.%d of host cluster %s and version %d.%d of joining cluster %s is larger than the supported %d minor versions",
+			hostVersion[0], hostVersion[1], hostClusterName, clusterVersion[0], clusterVersion[1], name, maxSupportedMinorVersionSkew))
+	}
+	return problems
+}
+
+// serverMinorVersion returns the major and minor components of the
+// kubernetes version of a cluster, or an error if the version cannot
+// be determined or is not supported.
+func serverMinorVersion(clientset kubeclient.Interface, description, clusterName string) ([]int, error) {
+	info, err := clientset.Discovery().ServerVersion()
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to determine the kubernetes version of %s %s", description, clusterName)
+	}
+	version, err := ctlutil.ParseVersion(info.GitVersion)
+	if err != nil || len(version) < 2 {
+		return nil, errors.Errorf("unable to parse kubernetes version %q of %s %s", info.GitVersion, description, clusterName)
+	}
+	minVersion, err := ctlutil.ParseVersion(minSupportedKubernetesVersion)
+	if err != nil {
+		return nil, err
+	}
+	if ctlutil.CompareVersions(version, minVersion) < 0 {
+		return nil, errors.Errorf("kubernetes version %s of %s %s is older than the minimum supported version %s",
+			info.GitVersion, description, clusterName, minSupportedKubernetesVersion)
+	}
+	return version[:2], nil
+}
+
+// checkJoinPermissions checks that the credentials for the host and
+// joining clusters permit the creation of the resources required to
+// join a cluster with the given scope, or their update if the cluster
+// is being rejoined.
+func checkJoinPermissions(hostClientset, clusterClientset kubeclient.Interface, hostClusterName, name,
+	kubefedNamespace, secretNamespace string, scope apiextv1b1.ResourceScope, local, rejoin bool) []error {
+	var problems []error
+	clusterChecks := []accessCheck{}
+	if !local {
+		clusterChecks = append(clusterChecks,
+			accessCheck{verb: "create", resource: "serviceaccounts", namespace: kubefedNamespace},
+			accessCheck{verb: "get", resource: "serviceaccounts", namespace: kubefedNamespace},
+			accessCheck{verb: "get", resource: "secrets", namespace: kubefedNamespace},
+		)
+	}
+	if rejoin && !local {
+		// An invalid service account token is regenerated by
+		// deleting its secret.
+		clusterChecks = append(clusterChecks, accessCheck{verb: "delete", resource: "secrets", namespace: kubefedNamespace})
+	}
+
+	// The namespace is only created if it does not already exist.
+	_, err := clusterClientset.CoreV1().Namespaces().Get(kubefedNamespace, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		clusterChecks = append(clusterChecks, accessCheck{verb: "create", resource: "namespaces"})
+	case err != nil:
+		clusterChecks = append(clusterChecks, accessCheck{verb: "get", resource: "namespaces"})
+	}
+
+	// A cluster role and binding is created for health checks
+	// regardless of scope.
+	rbacResources := []accessCheck{
+		{group: rbacv1.GroupName, resource: "clusterroles"},
+		{group: rbacv1.GroupName, resource: "clusterrolebindings"},
+	}
+	if scope == apiextv1b1.NamespaceScoped {
+		rbacResources = append(rbacResources,
+			accessCheck{group: rbacv1.GroupName, resource: "roles", namespace: kubefedNamespace},
+			accessCheck{group: rbacv1.GroupName, resource: "rolebindings", namespace: kubefedNamespace},
+		)
+	}
+	for _, check := range rbacResources {
+		for _, verb := range []string{"get", "create", "update"} {
+			check.verb = verb
+			clusterChecks = append(clusterChecks, check)
+		}
+	}
+	problems = append(problems, checkAccess(clusterClientset, "joining cluster", name, clusterChecks)...)
+
+	hostChecks := []accessCheck{
+		{verb: "get", group: fedv1b1.SchemeGroupVersion.Group, resource: "kubefedclusters", namespace: kubefedNamespace},
+		{verb: "create", group: fedv1b1.SchemeGroupVersion.Group, resource: "kubefedclusters", namespace: kubefedNamespace},
+	}
+	if !local {
+		hostChecks = append(hostChecks, accessCheck{verb: "create", resource: "secrets", namespace: secretNamespace})
+	}
+	if rejoin {
+		hostChecks = append(hostChecks,
+			accessCheck{verb: "update", group: fedv1b1.SchemeGroupVersion.Group, resource: "kubefedclusters", namespace: kubefedNamespace},
+		)
+		if !local {
+			hostChecks = append(hostChecks,
+				accessCheck{verb: "get", resource: "secrets", namespace: secretNamespace},
+				accessCheck{verb: "update", resource: "secrets", namespace: secretNamespace},
+			)
+		}
+	}
+	problems = append(problems, checkAccess(hostClientset, "host cluster", hostClusterName, hostChecks)...)
+	return problems
+}
+
+// checkAccess uses self subject access reviews to check that the
+// credentials of the given clientset permit the actions described by
+// checks.
+func checkAccess(clientset kubeclient.Interface, description, clusterName string, checks []accessCheck) []error {
+	var problems []error
+	for _, check := range checks {
+		review := &authv1.SelfSubjectAccessReview{
+			Spec: authv1.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: &authv1.ResourceAttributes{
+					Namespace: check.namespace,
+					Verb:      check.verb,
+					Group:     check.group,
+					Resource:  check.resource,
+				},
+			},
+		}
+		result, err := clientset.AuthorizationV1().SelfSubjectAccessReviews().Create(review)
+		if err != nil {
+			problems = append(problems, errors.Wrapf(err, "Failed to check permission to %s in %s %s", check, description, clusterName))
+			continue
+		}
+		if !result.Status.Allowed {
+			problems = append(problems, errors.Errorf("not permitted to %s in %s %s", check, description, clusterName))
+		}
+	}
+	return problems
+}
+
+// checkAdmissionWebhooks checks that the services of the validating
+// webhooks that must admit the creation of a KubeFedCluster in the
+// host cluster have endpoints to receive requests. The check is
+// skipped if the credentials do not permit reading the webhook
+// configuration.
+func checkAdmissionWebhooks(hostClientset kubeclient.Interface, hostClusterName string) []error {
+	configs, err := hostClientset.AdmissionregistrationV1beta1().ValidatingWebhookConfigurations().List(metav1.ListOptions{})
+	if apierrors.IsForbidden(err) {
+		klog.V(2).Infof("Skipping check of admission webhooks in host cluster %s: %v", hostClusterName, err)
+		return nil
+	}
+	if err != nil {
+		return []error{errors.Wrapf(err, "Failed to list validating webhook configurations in host cluster %s", hostClusterName)}
+	}
+
+	var problems []error
+	checked := sets.NewString()
+	for _, config := range configs.Items {
+		for _, webhook := range config.Webhooks {
+			service := webhook.ClientConfig.Service
+			// Webhooks configured with a url cannot be checked, and
+			// the failure of a webhook that is ignored on failure
+			// does not prevent admission.
+			if service == nil || !webhookMustAdmitKubeFedClusters(webhook) {
+				continue
+			}
+			key := fmt.Sprintf("%s/%s", service.Namespace, service.Name)
+			if checked.Has(key) {
+				continue
+			}
+			checked.Insert(key)
+
+			endpoints, err := hostClientset.CoreV1().Endpoints(service.Namespace).Get(service.Name, metav1.GetOptions{})
+			switch {
+			case apierrors.IsForbidden(err):
+				klog.V(2).Infof("Skipping check of service %s of webhook %s in host cluster %s: %v", key, webhook.Name, hostClusterName, err)
+				continue
+			case apierrors.IsNotFound(err):
+			case err != nil:
+				problems = append(problems, errors.Wrapf(err, "Failed to get endpoints of service %s of webhook %s in host cluster %s",
+					key, webhook.Name, hostClusterName))
+				continue
+			default:
+				if endpointsReady(endpoints.Subsets) {
+					continue
+				}
+			}
+			problems = append(problems, errors.Errorf("service %s of webhook %s in host cluster %s has no ready endpoints",
+				key, webhook.Name, hostClusterName))
+		}
+	}
+	return problems
+}
+
+// webhookMustAdmitKubeFedClusters returns whether the webhook must
+// successfully admit the creation of a KubeFedCluster. Namespace
+// selectors are not evaluated, so a webhook is assumed to apply to
+// all namespaces.
+func webhookMustAdmitKubeFedClusters(webhook admissionv1b1.Webhook) bool {
+	if webhook.FailurePolicy == nil || *webhook.FailurePolicy != admissionv1b1.Fail {
+		return false
+	}
+	for _, rule := range webhook.Rules {
+		if matchesAny(rule.APIGroups, fedv1b1.SchemeGroupVersion.Group) &&
+			matchesAny(rule.Resources, "kubefedclusters") &&
+			matchesAnyOperation(rule.Operations, admissionv1b1.Create) {
+			return true
+		}
+	}
+	return false
+}
+
+// endpointsReady returns whether any of the subsets has a ready
+// address.
+func endpointsReady(subsets []corev1.EndpointSubset) bool {
+	for _, subset := range subsets {
+		if len(subset.Addresses) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAny(values []string, value string) bool {
+	for _, v := range values {
+		if v == value || v == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAnyOperation(operations []admissionv1b1.OperationType, operation admissionv1b1.OperationType) bool {
+	for _, o := range operations {
+		if o == operation || o == admissionv1b1.OperationAll {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,216 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package set
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/klog"
+
+	ctlutil "sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/util"
+)
+
+var (
+	set_override_long = `
+		Set override sets the value of a field of a federated resource
+		in a single member cluster, or removes the override with
+		--remove. Other overrides of the resource are left unchanged.
+
+		The path of the field may be given with dots (spec.replicas)
+		or slashes (/spec/replicas). The value is parsed as JSON (e.g.
+		5, true or {"key": "value"}) and used as a string otherwise.
+
+		The federated resource is identified as TYPE/NAME, where TYPE
+		is the federated type (e.g. federateddeployment) or its target
+		type (e.g. deployment).`
+
+	set_override_example = `
+		# Run 5 replicas of federated deployment "foo" in cluster1
+		kubefedctl set override deploy/foo -n my-ns --cluster=cluster1 --path=/spec/replicas --value=5
+
+		# Remove the override of the replicas of federated deployment "foo" in cluster1
+		kubefedctl set override deploy/foo -n my-ns --cluster=cluster1 --path=/spec/replicas --remove`
+)
+
+type overrideOptions struct {
+	resourceOptions
+	clusterName string
+	path        string
+	rawValue    string
+	remove      bool
+
+	value interface{}
+}
+
+// Bind adds the override specific arguments to the flagset passed in
+// as an argument.
+func (o *overrideOptions) Bind(flags *pflag.FlagSet) {
+	o.resourceOptions.Bind(flags)
+	flags.StringVar(&o.clusterName, "cluster", "", "The name of the cluster the override applies to.")
+	flags.StringVar(&o.path, "path", "", "The path of the overridden field.")
+	flags.StringVar(&o.rawValue, "value", "", "The value of the overridden field.")
+	flags.BoolVar(&o.remove, "remove", false, "Whether to remove the override instead of setting it.")
+}
+
+// Complete ensures that options are valid.
+func (o *overrideOptions) Complete(flags *pflag.FlagSet, args []string) error {
+	if len(args) != 1 {
+		return errors.New("TYPE/NAME is required")
+	}
+	if len(o.clusterName) == 0 {
+		return errors.New("--cluster is required")
+	}
+	o.path = strings.Replace(strings.TrimPrefix(o.path, "/"), "/", ".", -1)
+	if len(o.path) == 0 {
+		return errors.New("--path is required")
+	}
+	valueSet := flags.Changed("value")
+	if o.remove == valueSet {
+		return errors.New("Exactly one of --value or --remove is required")
+	}
+	if valueSet {
+		o.value = parseValue(o.rawValue)
+	}
+	return o.setResource(args[0])
+}
+
+// parseValue returns the given value parsed as JSON, or as a string
+// if it is not valid JSON.
+func parseValue(rawValue string) interface{} {
+	// Numbers are only parsed as integers when nested in an object.
+	wrapper := map[string]interface{}{}
+	err := json.Unmarshal([]byte(fmt.Sprintf(`{"value": %s}`, rawValue)), &wrapper)
+	if err != nil {
+		return rawValue
+	}
+	return wrapper["value"]
+}
+
+func newCmdSetOverride(cmdOut io.Writer, config util.FedConfig) *cobra.Command {
+	opts := &overrideOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "override TYPE/NAME --cluster=CLUSTER --path=PATH (--value=VALUE | --remove)",
+		Short:   "Set or remove a cluster override of a federated resource",
+		Long:    set_override_long,
+		Example: set_override_example,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := opts.Complete(cmd.Flags(), args)
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+
+			err = opts.Run(cmdOut, config)
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+		},
+	}
+
+	opts.Bind(cmd.Flags())
+
+	return cmd
+}
+
+// Run is the implementation of the `set override` command.
+func (o *overrideOptions) Run(cmdOut io.Writer, config util.FedConfig) error {
+	return o.updateFederatedResource(cmdOut, config, func(fedObj *unstructured.Unstructured) error {
+		err := setClusterOverride(fedObj, o.clusterName, o.path, o.value, o.remove)
+		if err != nil {
+			return err
+		}
+		// Ensure the resulting overrides are valid.
+		_, err = ctlutil.GetOverrides(fedObj)
+		return err
+	})
+}
+
+// setClusterOverride sets or removes the override of the given path
+// for the named cluster.  The order of existing overrides is
+// preserved.
+func setClusterOverride(fedObj *unstructured.Unstructured, clusterName, path string, value interface{}, remove bool) error {
+	overrides, _, err := unstructured.NestedSlice(fedObj.Object, ctlutil.SpecField, ctlutil.OverridesField)
+	if err != nil {
+		return errors.Wrap(err, "Failed to read the overrides")
+	}
+
+	clusterIndex := -1
+	for i, rawItem := range overrides {
+		item, ok := rawItem.(map[string]interface{})
+		if ok && item[ctlutil.ClusterNameField] == clusterName {
+			clusterIndex = i
+			break
+		}
+	}
+	if clusterIndex == -1 {
+		if remove {
+			return errors.Errorf("Cluster %q has no overrides", clusterName)
+		}
+		overrides = append(overrides, map[string]interface{}{ctlutil.ClusterNameField: clusterName})
+		clusterIndex = len(overrides) - 1
+	}
+	item := overrides[clusterIndex].(map[string]interface{})
+
+	clusterOverrides, _, err := unstructured.NestedSlice(item, ctlutil.ClusterOverridesField)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to read the overrides of cluster %q", clusterName)
+	}
+	updatedOverrides := []interface{}{}
+	found := false
+	for _, rawOverride := range clusterOverrides {
+		override, ok := rawOverride.(map[string]interface{})
+		if !ok || override[ctlutil.PathField] != path {
+			updatedOverrides = append(updatedOverrides, rawOverride)
+			continue
+		}
+		found = true
+		if !remove {
+			override[ctlutil.ValueField] = value
+			updatedOverrides = append(updatedOverrides, override)
+		}
+	}
+	if !found {
+		if remove {
+			return errors.Errorf("Cluster %q has no override for path %q", clusterName, path)
+		}
+		updatedOverrides = append(updatedOverrides, map[string]interface{}{
+			ctlutil.PathField:  path,
+			ctlutil.ValueField: value,
+		})
+	}
+
+	if len(updatedOverrides) == 0 {
+		// Remove the cluster from the overrides once it has none left.
+		overrides = append(overrides[:clusterIndex], overrides[clusterIndex+1:]...)
+	} else {
+		item[ctlutil.ClusterOverridesField] = updatedOverrides
+	}
+	if len(overrides) == 0 {
+		unstructured.RemoveNestedField(fedObj.Object, ctlutil.SpecField, ctlutil.OverridesField)
+		return nil
+	}
+	return unstructured.SetNestedSlice(fedObj.Object, overrides, ctlutil.SpecField, ctlutil.OverridesField)
+}
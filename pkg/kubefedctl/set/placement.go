@@ -0,0 +1,136 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package set
+
+import (
+	"io"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog"
+
+	ctlutil "sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/util"
+)
+
+var (
+	set_placement_long = `
+		Set placement sets the clusters a federated resource is
+		propagated to, either as a list of cluster names or as a
+		selector of cluster labels. Setting one replaces the other.
+
+		The federated resource is identified as TYPE/NAME, where TYPE
+		is the federated type (e.g. federateddeployment) or its target
+		type (e.g. deployment).`
+
+	set_placement_example = `
+		# Propagate federated deployment "foo" to cluster1 and cluster2
+		kubefedctl set placement deploy/foo -n my-ns --clusters=cluster1,cluster2
+
+		# Propagate federated deployment "foo" to all clusters labeled env=prod
+		kubefedctl set placement deploy/foo -n my-ns --cluster-selector=env=prod
+
+		# Propagate federated deployment "foo" to all clusters
+		kubefedctl set placement deploy/foo -n my-ns --cluster-selector=""`
+)
+
+type placementOptions struct {
+	resourceOptions
+	clusters        []string
+	clusterSelector string
+
+	clustersSet        bool
+	clusterSelectorSet bool
+}
+
+// Bind adds the placement specific arguments to the flagset passed in
+// as an argument.
+func (o *placementOptions) Bind(flags *pflag.FlagSet) {
+	o.resourceOptions.Bind(flags)
+	flags.StringSliceVar(&o.clusters, "clusters", nil, "Comma separated names of the clusters to propagate to.")
+	flags.StringVar(&o.clusterSelector, "cluster-selector", "",
+		"Comma separated key=value labels of the clusters to propagate to. An empty selector selects all clusters.")
+}
+
+// Complete ensures that options are valid.
+func (o *placementOptions) Complete(flags *pflag.FlagSet, args []string) error {
+	if len(args) != 1 {
+		return errors.New("TYPE/NAME is required")
+	}
+	o.clustersSet = flags.Changed("clusters")
+	o.clusterSelectorSet = flags.Changed("cluster-selector")
+	if o.clustersSet == o.clusterSelectorSet {
+		return errors.New("Exactly one of --clusters or --cluster-selector is required")
+	}
+	return o.setResource(args[0])
+}
+
+func newCmdSetPlacement(cmdOut io.Writer, config util.FedConfig) *cobra.Command {
+	opts := &placementOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "placement TYPE/NAME (--clusters=CLUSTER,... | --cluster-selector=KEY=VALUE,...)",
+		Short:   "Set the placement of a federated resource",
+		Long:    set_placement_long,
+		Example: set_placement_example,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := opts.Complete(cmd.Flags(), args)
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+
+			err = opts.Run(cmdOut, config)
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+		},
+	}
+
+	opts.Bind(cmd.Flags())
+
+	return cmd
+}
+
+// Run is the implementation of the `set placement` command.
+func (o *placementOptions) Run(cmdOut io.Writer, config util.FedConfig) error {
+	if o.clustersSet {
+		return o.updateFederatedResource(cmdOut, config, func(fedObj *unstructured.Unstructured) error {
+			unstructured.RemoveNestedField(fedObj.Object, ctlutil.SpecField, ctlutil.PlacementField, ctlutil.ClusterSelectorField)
+			return ctlutil.SetClusterNames(fedObj, o.clusters)
+		})
+	}
+
+	matchLabels, err := labels.ConvertSelectorToLabelsMap(o.clusterSelector)
+	if err != nil {
+		return errors.Wrapf(err, "Invalid cluster selector %q", o.clusterSelector)
+	}
+	return o.updateFederatedResource(cmdOut, config, func(fedObj *unstructured.Unstructured) error {
+		unstructured.RemoveNestedField(fedObj.Object, ctlutil.SpecField, ctlutil.PlacementField, ctlutil.ClustersField)
+		// Replace any existing selector, including requirements
+		// other than labels.
+		selectorLabels := map[string]interface{}{}
+		for key, value := range matchLabels {
+			selectorLabels[key] = value
+		}
+		return unstructured.SetNestedField(fedObj.Object, map[string]interface{}{ctlutil.MatchLabelsField: selectorLabels},
+			ctlutil.SpecField, ctlutil.PlacementField, ctlutil.ClusterSelectorField)
+	})
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package set
+
+import (
+	"io"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog"
+
+	schedulingv1a1 "sigs.k8s.io/kubefed/pkg/apis/scheduling/v1alpha1"
+	ctlutil "sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/util"
+)
+
+var (
+	set_replicas_long = `
+		Set replicas sets the total number of replicas of a
+		ReplicaSchedulingPreference, identified as rsp/NAME, or the
+		replicas of the template of a federated resource, identified
+		as TYPE/NAME where TYPE is the federated type (e.g.
+		federateddeployment) or its target type (e.g. deployment).`
+
+	set_replicas_example = `
+		# Schedule 30 replicas across clusters with ReplicaSchedulingPreference "foo"
+		kubefedctl set replicas rsp/foo 30 -n my-ns
+
+		# Set the replicas of the template of federated deployment "foo"
+		kubefedctl set replicas deploy/foo 3 -n my-ns`
+
+	// The names identifying the ReplicaSchedulingPreference type.
+	rspTypeNames = sets.NewString(
+		"rsp",
+		"replicaschedulingpreference",
+		"replicaschedulingpreferences",
+		"replicaschedulingpreference.scheduling.kubefed.k8s.io",
+		"replicaschedulingpreferences.scheduling.kubefed.k8s.io",
+	)
+)
+
+type replicasOptions struct {
+	resourceOptions
+	replicas int32
+}
+
+// Complete ensures that options are valid.
+func (o *replicasOptions) Complete(args []string) error {
+	if len(args) != 2 {
+		return errors.New("TYPE/NAME and REPLICAS are required")
+	}
+	replicas, err := strconv.ParseInt(args[1], 10, 32)
+	if err != nil || replicas < 0 {
+		return errors.Errorf("Invalid replicas %q. The replicas must be a non-negative integer", args[1])
+	}
+	o.replicas = int32(replicas)
+	return o.setResource(args[0])
+}
+
+func newCmdSetReplicas(cmdOut io.Writer, config util.FedConfig) *cobra.Command {
+	opts := &replicasOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "replicas TYPE/NAME REPLICAS",
+		Short:   "Set the replicas of a ReplicaSchedulingPreference or federated resource",
+		Long:    set_replicas_long,
+		Example: set_replicas_example,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := opts.Complete(args)
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+
+			err = opts.Run(cmdOut, config)
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+		},
+	}
+
+	opts.Bind(cmd.Flags())
+
+	return cmd
+}
+
+// Run is the implementation of the `set replicas` command.
+func (o *replicasOptions) Run(cmdOut io.Writer, config util.FedConfig) error {
+	if !rspTypeNames.Has(o.typeName) {
+		return o.updateFederatedResource(cmdOut, config, func(fedObj *unstructured.Unstructured) error {
+			template, _, err := ctlutil.GetTemplate(fedObj)
+			if err != nil {
+				return err
+			}
+			_, ok, err := unstructured.NestedFieldNoCopy(template, ctlutil.SpecField, ctlutil.ReplicasField)
+			if err != nil || !ok {
+				return errors.Errorf("The template of %s %q does not have replicas", fedObj.GetKind(), ctlutil.NewQualifiedName(fedObj))
+			}
+			err = unstructured.SetNestedField(template, int64(o.replicas), ctlutil.SpecField, ctlutil.ReplicasField)
+			if err != nil {
+				return err
+			}
+			return ctlutil.SetTemplate(fedObj, template, ctlutil.IsTemplateCompressed(fedObj))
+		})
+	}
+
+	_, client, err := o.hostClient(config)
+	if err != nil {
+		return err
+	}
+	rsp := &unstructured.Unstructured{}
+	rsp.SetAPIVersion(schedulingv1a1.SchemeGroupVersion.String())
+	rsp.SetKind("ReplicaSchedulingPreference")
+	return o.updateResource(cmdOut, client, rsp, func(rsp *unstructured.Unstructured) error {
+		return unstructured.SetNestedField(rsp.Object, int64(o.replicas), ctlutil.SpecField, "totalReplicas")
+	})
+}
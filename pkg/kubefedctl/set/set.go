@@ -0,0 +1,151 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package set
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/rest"
+
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	ctlutil "sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/enable"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/options"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/util"
+)
+
+var (
+	set_long = `
+		Set updates fields of federated resources that are error-prone
+		to edit by hand, such as placement, cluster overrides and
+		replicas.
+
+		Current context is assumed to be a Kubernetes cluster hosting
+		the kubefed control plane. Please use the --host-cluster-context
+		flag otherwise.`
+)
+
+// NewCmdSet defines the `set` command and its subcommands.
+func NewCmdSet(cmdOut io.Writer, config util.FedConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set SUBCOMMAND",
+		Short: "Set the placement, overrides or replicas of federated resources",
+		Long:  set_long,
+		Run: func(cmd *cobra.Command, args []string) {
+			_ = cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(newCmdSetPlacement(cmdOut, config))
+	cmd.AddCommand(newCmdSetOverride(cmdOut, config))
+	cmd.AddCommand(newCmdSetReplicas(cmdOut, config))
+
+	return cmd
+}
+
+// resourceOptions holds the options common to the subcommands of
+// `set` that update a resource identified as TYPE/NAME.
+type resourceOptions struct {
+	options.GlobalSubcommandOptions
+	typeName     string
+	resourceName string
+	namespace    string
+}
+
+// Bind adds the arguments common to the set subcommands to the
+// flagset passed in as an argument.
+func (o *resourceOptions) Bind(flags *pflag.FlagSet) {
+	o.GlobalSubcommandBind(flags)
+	flags.StringVarP(&o.namespace, "namespace", "n", "default", "The namespace of the resource.")
+}
+
+// setResource parses the TYPE/NAME argument identifying the resource
+// to update.
+func (o *resourceOptions) setResource(arg string) error {
+	parts := strings.SplitN(arg, "/", 2)
+	if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+		return errors.Errorf("Invalid resource %q. The resource must be given as TYPE/NAME", arg)
+	}
+	o.typeName, o.resourceName = parts[0], parts[1]
+	return nil
+}
+
+// hostClient returns a client for the host cluster.
+func (o *resourceOptions) hostClient(config util.FedConfig) (*rest.Config, genericclient.Client, error) {
+	hostConfig, err := config.HostConfig(o.HostClusterContext, o.Kubeconfig)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Failed to get host cluster config")
+	}
+	client, err := genericclient.New(hostConfig)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Failed to get kubefed clientset")
+	}
+	return hostConfig, client, nil
+}
+
+// updateFederatedResource updates the federated resource with the
+// given update.
+func (o *resourceOptions) updateFederatedResource(cmdOut io.Writer, config util.FedConfig, update func(fedObj *unstructured.Unstructured) error) error {
+	hostConfig, client, err := o.hostClient(config)
+	if err != nil {
+		return err
+	}
+	typeConfig, err := enable.LookupTypeConfig(hostConfig, client, o.typeName, o.KubeFedNamespace)
+	if err != nil {
+		return err
+	}
+	federatedType := typeConfig.GetFederatedType()
+	fedObj := &unstructured.Unstructured{}
+	fedObj.SetAPIVersion(fmt.Sprintf("%s/%s", federatedType.Group, federatedType.Version))
+	fedObj.SetKind(federatedType.Kind)
+	return o.updateResource(cmdOut, client, fedObj, update)
+}
+
+// updateResource retrieves the resource of the kind of the given
+// object, applies the given update to it and either updates it in the
+// host cluster or, in dry-run mode, writes it to the output.
+func (o *resourceOptions) updateResource(cmdOut io.Writer, client genericclient.Client, obj *unstructured.Unstructured, update func(obj *unstructured.Unstructured) error) error {
+	kind := obj.GetKind()
+	qualifiedName := ctlutil.QualifiedName{Namespace: o.namespace, Name: o.resourceName}
+	err := client.Get(context.TODO(), obj, o.namespace, o.resourceName)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to retrieve %s %q", kind, qualifiedName)
+	}
+
+	err = update(obj)
+	if err != nil {
+		return err
+	}
+
+	if o.DryRun {
+		return util.WriteUnstructuredToYaml(obj, cmdOut)
+	}
+	err = client.Update(context.TODO(), obj)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to update %s %q", kind, qualifiedName)
+	}
+	fmt.Fprintf(cmdOut, "%s %q updated\n", kind, qualifiedName)
+	return nil
+}
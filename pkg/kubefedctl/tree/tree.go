@@ -0,0 +1,452 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tree
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	apiv1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	"sigs.k8s.io/kubefed/pkg/controller/sync/status"
+	ctlutil "sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/enable"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/options"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/util"
+)
+
+const (
+	allClusters = "all"
+
+	readyGlyph    = "✔"
+	notReadyGlyph = "…"
+	failedGlyph   = "✖"
+)
+
+var (
+	tree_long = `
+		Tree shows a federated resource, the resources it has been
+		propagated to in member clusters and, with --children, the
+		resources owned by the propagated resources (e.g. the
+		replicasets and pods of a deployment). Each resource is shown
+		with a glyph indicating whether it is ready (✔), not yet ready
+		(…) or failed (✖).
+
+		The federated resource is identified as TYPE/NAME, where TYPE
+		is the federated type (e.g. federateddeployment) or its target
+		type (e.g. deployment). All member clusters the resource has
+		been propagated to are shown unless --cluster is provided.
+
+		Current context is assumed to be a Kubernetes cluster hosting
+		the kubefed control plane. Please use the --host-cluster-context
+		flag otherwise.`
+
+	tree_example = `
+		# Show federated deployment "foo" and its deployments in member clusters
+		kubefedctl tree federateddeployment/foo -n my-ns --host-cluster-context=cluster1
+
+		# Also show the replicasets and pods of the deployments in cluster2
+		kubefedctl tree deployment/foo -n my-ns --children --cluster=cluster2 --host-cluster-context=cluster1`
+
+	// The types of the resources owned by resources of a target kind
+	// that are shown by --children.
+	childTypes = map[string][]metav1.APIResource{
+		"Deployment":  {{Group: "apps", Version: "v1", Kind: "ReplicaSet", Name: "replicasets", Namespaced: true}},
+		"ReplicaSet":  {podType},
+		"StatefulSet": {podType},
+		"DaemonSet":   {podType},
+		"Job":         {podType},
+		"CronJob":     {{Group: "batch", Version: "v1", Kind: "Job", Name: "jobs", Namespaced: true}},
+	}
+
+	podType = metav1.APIResource{Version: "v1", Kind: "Pod", Name: "pods", Namespaced: true}
+)
+
+type treeOptions struct {
+	options.GlobalSubcommandOptions
+	typeName     string
+	resourceName string
+	namespace    string
+	clusters     []string
+	children     bool
+}
+
+// Bind adds the tree specific arguments to the flagset passed in as an
+// argument.
+func (o *treeOptions) Bind(flags *pflag.FlagSet) {
+	flags.StringVarP(&o.namespace, "namespace", "n", "default", "The namespace of the federated resource.")
+	flags.StringSliceVar(&o.clusters, "cluster", []string{allClusters},
+		"Comma separated names of the clusters to show, or 'all' for the clusters the resource has been propagated to.")
+	flags.BoolVar(&o.children, "children", false, "Whether to show the resources owned by the propagated resources.")
+}
+
+// Complete ensures that options are valid.
+func (o *treeOptions) Complete(args []string) error {
+	if len(args) != 1 {
+		return errors.New("TYPE/NAME is required")
+	}
+	parts := strings.SplitN(args[0], "/", 2)
+	if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+		return errors.Errorf("Invalid resource %q. The resource must be given as TYPE/NAME", args[0])
+	}
+	o.typeName, o.resourceName = parts[0], parts[1]
+	if len(o.clusters) == 0 {
+		return errors.New("--cluster must not be empty")
+	}
+	if len(o.clusters) > 1 && sets.NewString(o.clusters...).Has(allClusters) {
+		return errors.Errorf("--cluster=%s may not be combined with cluster names", allClusters)
+	}
+	return nil
+}
+
+// NewCmdTree defines the `tree` command that shows a federated
+// resource and the resources it has been propagated to.
+func NewCmdTree(cmdOut io.Writer, config util.FedConfig) *cobra.Command {
+	opts := &treeOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "tree TYPE/NAME",
+		Short:   "Show a federated resource and the resources it has been propagated to in member clusters",
+		Long:    tree_long,
+		Example: tree_example,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := opts.Complete(args)
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+
+			err = opts.Run(cmdOut, config)
+			if err != nil {
+				klog.Fatalf("Error: %v", err)
+			}
+		},
+	}
+
+	flags := cmd.Flags()
+	opts.GlobalSubcommandBind(flags)
+	opts.Bind(flags)
+
+	return cmd
+}
+
+// node is a resource shown in the tree.
+type node struct {
+	label    string
+	glyph    string
+	status   string
+	children []*node
+}
+
+// Run is the implementation of the `tree` command.
+func (o *treeOptions) Run(cmdOut io.Writer, config util.FedConfig) error {
+	hostConfig, err := config.HostConfig(o.HostClusterContext, o.Kubeconfig)
+	if err != nil {
+		return errors.Wrap(err, "Failed to get host cluster config")
+	}
+	client, err := genericclient.New(hostConfig)
+	if err != nil {
+		return errors.Wrap(err, "Failed to get kubefed clientset")
+	}
+
+	typeConfig, err := enable.LookupTypeConfig(hostConfig, client, o.typeName, o.KubeFedNamespace)
+	if err != nil {
+		return err
+	}
+	federatedType := typeConfig.GetFederatedType()
+	fedObj := &unstructured.Unstructured{}
+	fedObj.SetAPIVersion(fmt.Sprintf("%s/%s", federatedType.Group, federatedType.Version))
+	fedObj.SetKind(federatedType.Kind)
+	err = client.Get(context.TODO(), fedObj, o.namespace, o.resourceName)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to retrieve %s %q", federatedType.Kind,
+			ctlutil.QualifiedName{Namespace: o.namespace, Name: o.resourceName})
+	}
+
+	fedStatus := &status.GenericFederatedStatus{}
+	err = pkgruntime.DefaultUnstructuredConverter.FromUnstructured(fedObj.Object, fedStatus)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to read the status of %s %q", federatedType.Kind, o.resourceName)
+	}
+	clusterStatuses := make(map[string]status.PropagationStatus)
+	if fedStatus.Status != nil {
+		for _, cluster := range fedStatus.Status.Clusters {
+			clusterStatuses[cluster.Name] = cluster.Status
+		}
+	}
+
+	clusters, err := o.treeClusters(client, fedObj)
+	if err != nil {
+		return err
+	}
+	overrides, err := ctlutil.GetOverrides(fedObj)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to read the overrides of %s %q", federatedType.Kind, o.resourceName)
+	}
+
+	root := federatedNode(fedObj, fedStatus)
+	targetType := typeConfig.GetTargetType()
+	targetIsNamespace := targetType.Kind == ctlutil.NamespaceKind
+	for _, cluster := range clusters {
+		targetName := util.ClusterTargetName(cluster, overrides[cluster.Name], targetIsNamespace, ctlutil.NewQualifiedName(fedObj))
+		if targetIsNamespace {
+			targetName.Namespace = ""
+		}
+		root.children = append(root.children, o.clusterNode(hostConfig, client, cluster, &targetType, targetName, fedObj.GetGeneration(), clusterStatuses))
+	}
+
+	writeTree(cmdOut, root)
+	return nil
+}
+
+// treeClusters returns the clusters to show.
+func (o *treeOptions) treeClusters(client genericclient.Client, fedObj *unstructured.Unstructured) ([]*fedv1b1.KubeFedCluster, error) {
+	clusterList := &fedv1b1.KubeFedClusterList{}
+	err := client.List(context.TODO(), clusterList, o.KubeFedNamespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list member clusters")
+	}
+	joined := make(map[string]*fedv1b1.KubeFedCluster)
+	for i := range clusterList.Items {
+		joined[clusterList.Items[i].Name] = &clusterList.Items[i]
+	}
+
+	names := o.clusters
+	if len(names) == 1 && names[0] == allClusters {
+		names, err = util.PropagatedClusterNames(fedObj)
+		if err != nil {
+			return nil, err
+		}
+	}
+	clusters := []*fedv1b1.KubeFedCluster{}
+	for _, name := range sets.NewString(names...).List() {
+		cluster, ok := joined[name]
+		if !ok {
+			return nil, errors.Errorf("Cluster %q is not joined", name)
+		}
+		clusters = append(clusters, cluster)
+	}
+	return clusters, nil
+}
+
+func federatedNode(fedObj *unstructured.Unstructured, fedStatus *status.GenericFederatedStatus) *node {
+	n := &node{
+		label:  fmt.Sprintf("%s %s", fedObj.GetKind(), ctlutil.NewQualifiedName(fedObj)),
+		glyph:  notReadyGlyph,
+		status: "Not yet propagated",
+	}
+	if fedStatus.Status == nil {
+		return n
+	}
+	for _, condition := range fedStatus.Status.Conditions {
+		if condition.Type != status.PropagationConditionType {
+			continue
+		}
+		if condition.Status == apiv1.ConditionTrue {
+			n.glyph, n.status = readyGlyph, "Propagated"
+		} else {
+			n.glyph, n.status = failedGlyph, string(condition.Reason)
+		}
+	}
+	return n
+}
+
+// clusterNode returns the node of the target resource in the given
+// cluster.
+func (o *treeOptions) clusterNode(hostConfig *rest.Config, client genericclient.Client, cluster *fedv1b1.KubeFedCluster, targetType *metav1.APIResource,
+	targetName ctlutil.QualifiedName, federatedGeneration int64, clusterStatuses map[string]status.PropagationStatus) *node {
+
+	n := &node{label: fmt.Sprintf("%s: %s %s", cluster.Name, targetType.Kind, targetName)}
+	if propagationStatus, ok := clusterStatuses[cluster.Name]; ok && propagationStatus != status.ClusterPropagationOK {
+		n.glyph, n.status = failedGlyph, string(propagationStatus)
+	}
+
+	if !ctlutil.IsClusterReady(&cluster.Status) {
+		n.glyph, n.status = failedGlyph, "Cluster not ready"
+		return n
+	}
+	clusterConfig, err := ctlutil.BuildClusterConfig(cluster, hostConfig, client, o.KubeFedNamespace)
+	if err == nil && clusterConfig == nil {
+		err = errors.Errorf("Unable to load configuration for cluster %q", cluster.Name)
+	}
+	if err != nil {
+		n.glyph, n.status = failedGlyph, err.Error()
+		return n
+	}
+
+	targetClient, err := ctlutil.NewResourceClient(clusterConfig, targetType)
+	if err != nil {
+		n.glyph, n.status = failedGlyph, err.Error()
+		return n
+	}
+	target, err := targetClient.Resources(targetName.Namespace).Get(targetName.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		n.glyph, n.status = failedGlyph, "Not found"
+		return n
+	}
+	if err != nil {
+		n.glyph, n.status = failedGlyph, err.Error()
+		return n
+	}
+	glyph, objStatus := objectStatus(target)
+	if !ctlutil.RolloutComplete(target, federatedGeneration) {
+		glyph, objStatus = notReadyGlyph, fmt.Sprintf("Rolling out, %s", objStatus)
+	}
+	if len(n.glyph) == 0 {
+		n.glyph = glyph
+	}
+	if len(n.status) == 0 {
+		n.status = objStatus
+	}
+
+	if o.children {
+		n.children = ownedNodes(clusterConfig, target)
+	}
+	return n
+}
+
+// ownedNodes returns the nodes of the resources owned by the given
+// resource in a member cluster.
+func ownedNodes(clusterConfig *rest.Config, owner *unstructured.Unstructured) []*node {
+	nodes := []*node{}
+	for _, childType := range childTypes[owner.GetKind()] {
+		childType := childType
+		childClient, err := ctlutil.NewResourceClient(clusterConfig, &childType)
+		if err != nil {
+			klog.Errorf("Failed to create client for %s: %v", childType.Kind, err)
+			continue
+		}
+		list, err := childClient.Resources(owner.GetNamespace()).List(metav1.ListOptions{})
+		if err != nil {
+			klog.Errorf("Failed to list %s in namespace %q: %v", childType.Kind, owner.GetNamespace(), err)
+			continue
+		}
+		children := ownedBy(list.Items, owner.GetUID())
+		for i := range children {
+			child := &children[i]
+			glyph, childStatus := objectStatus(child)
+			nodes = append(nodes, &node{
+				label:    fmt.Sprintf("%s %s", childType.Kind, child.GetName()),
+				glyph:    glyph,
+				status:   childStatus,
+				children: ownedNodes(clusterConfig, child),
+			})
+		}
+	}
+	return nodes
+}
+
+// ownedBy returns the objects controlled by the owner with the given
+// uid sorted by name.
+func ownedBy(objs []unstructured.Unstructured, uid types.UID) []unstructured.Unstructured {
+	owned := []unstructured.Unstructured{}
+	for _, obj := range objs {
+		if controller := metav1.GetControllerOf(&obj); controller != nil && controller.UID == uid {
+			owned = append(owned, obj)
+		}
+	}
+	sort.Slice(owned, func(i, j int) bool { return owned[i].GetName() < owned[j].GetName() })
+	return owned
+}
+
+// objectStatus returns the glyph and a summary of the status of the
+// given resource in a member cluster.
+func objectStatus(obj *unstructured.Unstructured) (string, string) {
+	switch obj.GetKind() {
+	case "Pod":
+		return podStatus(obj)
+	case "Deployment", "ReplicaSet", "StatefulSet":
+		desired, ok, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+		if !ok {
+			desired = 1
+		}
+		ready, _, _ := unstructured.NestedInt64(obj.Object, "status", "readyReplicas")
+		return replicaStatus(ready, desired)
+	case "DaemonSet":
+		desired, _, _ := unstructured.NestedInt64(obj.Object, "status", "desiredNumberScheduled")
+		ready, _, _ := unstructured.NestedInt64(obj.Object, "status", "numberReady")
+		return replicaStatus(ready, desired)
+	}
+	return readyGlyph, "Propagated"
+}
+
+func replicaStatus(ready, desired int64) (string, string) {
+	glyph := readyGlyph
+	if ready < desired {
+		glyph = notReadyGlyph
+	}
+	return glyph, fmt.Sprintf("%d/%d ready", ready, desired)
+}
+
+func podStatus(obj *unstructured.Unstructured) (string, string) {
+	pod := &apiv1.Pod{}
+	if err := pkgruntime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, pod); err != nil {
+		return failedGlyph, err.Error()
+	}
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		if waiting := containerStatus.State.Waiting; waiting != nil && waiting.Reason != "ContainerCreating" && waiting.Reason != "PodInitializing" {
+			return failedGlyph, waiting.Reason
+		}
+	}
+	switch pod.Status.Phase {
+	case apiv1.PodSucceeded:
+		return readyGlyph, string(pod.Status.Phase)
+	case apiv1.PodFailed:
+		return failedGlyph, string(pod.Status.Phase)
+	case apiv1.PodRunning:
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == apiv1.PodReady && condition.Status == apiv1.ConditionTrue {
+				return readyGlyph, string(pod.Status.Phase)
+			}
+		}
+		return notReadyGlyph, "Running, not ready"
+	}
+	return notReadyGlyph, string(pod.Status.Phase)
+}
+
+// writeTree writes the given tree with box-drawing connectors.
+func writeTree(cmdOut io.Writer, root *node) {
+	fmt.Fprintf(cmdOut, "%s  %s %s\n", root.label, root.glyph, root.status)
+	writeChildren(cmdOut, root.children, "")
+}
+
+func writeChildren(cmdOut io.Writer, children []*node, indent string) {
+	for i, child := range children {
+		connector, childIndent := "├── ", "│   "
+		if i == len(children)-1 {
+			connector, childIndent = "└── ", "    "
+		}
+		fmt.Fprintf(cmdOut, "%s%s%s  %s %s\n", indent, connector, child.label, child.glyph, child.status)
+		writeChildren(cmdOut, child.children, indent+childIndent)
+	}
+}
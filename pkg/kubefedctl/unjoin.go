@@ -54,13 +54,21 @@ var (
 		# valid RFC 1123 subdomain name. Cluster context
 		# must be specified if the cluster name is different
 		# than the cluster's context in the local kubeconfig.
-		kubefedctl unjoin foo --host-cluster-context=bar`
+		kubefedctl unjoin foo --host-cluster-context=bar
+
+		# Remove the registration of several clusters named
+		# after their contexts, or listed in a clusters file.
+		kubefedctl unjoin foo baz qux --host-cluster-context=bar
+		kubefedctl unjoin --clusters-file=clusters.yaml --host-cluster-context=bar`
 )
 
 type unjoinFederation struct {
 	options.GlobalSubcommandOptions
 	options.CommonJoinOptions
 	unjoinFederationOptions
+
+	// clusters are the clusters to unjoin.
+	clusters []clusterEntry
 }
 
 type unjoinFederationOptions struct {
@@ -80,7 +88,7 @@ func NewCmdUnjoin(cmdOut io.Writer, config util.FedConfig) *cobra.Command {
 	opts := &unjoinFederation{}
 
 	cmd := &cobra.Command{
-		Use:     "unjoin CLUSTER_NAME --host-cluster-context=HOST_CONTEXT",
+		Use:     "unjoin CLUSTER_NAME... --host-cluster-context=HOST_CONTEXT",
 		Short:   "Remove the registration of a cluster from a KubeFed control plane",
 		Long:    unjoin_long,
 		Example: unjoin_example,
@@ -112,7 +120,7 @@ func (j *unjoinFederation) Complete(args []string) error {
 		return err
 	}
 
-	if j.ClusterContext == "" {
+	if j.ClusterContext == "" && len(j.ClusterNames) == 1 {
 		klog.V(2).Infof("Defaulting cluster context to unjoining cluster name %s", j.ClusterName)
 		j.ClusterContext = j.ClusterName
 	}
@@ -125,6 +133,11 @@ func (j *unjoinFederation) Complete(args []string) error {
 		return goerrors.New("host-cluster-name must be set if the name of the host cluster context contains one of \":\" or \"/\"")
 	}
 
+	j.clusters, err = clusterEntries(&j.CommonJoinOptions, "")
+	if err != nil {
+		return err
+	}
+
 	klog.V(2).Infof("Args and flags: name %s, host-cluster-context: %s, host-system-namespace: %s, kubeconfig: %s, cluster-context: %s, dry-run: %v",
 		j.ClusterName, j.HostClusterContext, j.KubeFedNamespace, j.Kubeconfig, j.ClusterContext, j.DryRun)
 
@@ -141,25 +154,28 @@ func (j *unjoinFederation) Run(cmdOut io.Writer, config util.FedConfig) error {
 		return err
 	}
 
-	clusterConfig, err := config.ClusterConfig(j.ClusterContext, j.Kubeconfig)
-	if err != nil {
-		klog.V(2).Infof("Failed to get unjoining cluster config: %v", err)
-
-		if !j.forceDeletion {
-			return err
-		}
-		// If configuration for the member cluster cannot be successfully loaded,
-		// forceDeletion indicates that resources associated with the member cluster
-		// should still be removed from the host cluster.
-	}
-
 	hostClusterName := j.HostClusterContext
 	if j.HostClusterName != "" {
 		hostClusterName = j.HostClusterName
 	}
 
-	return UnjoinCluster(hostConfig, clusterConfig, j.KubeFedNamespace,
-		hostClusterName, j.HostClusterContext, j.ClusterContext, j.ClusterName, j.forceDeletion, j.DryRun)
+	operation := clusterOperation{verb: "unjoin", pastTense: "Unjoined"}
+	return runForClusters(cmdOut, j.clusters, j.Concurrency, operation, func(cluster clusterEntry) error {
+		clusterConfig, err := config.ClusterConfig(cluster.Context, j.Kubeconfig)
+		if err != nil {
+			klog.V(2).Infof("Failed to get unjoining cluster config: %v", err)
+
+			if !j.forceDeletion {
+				return err
+			}
+			// If configuration for the member cluster cannot be successfully loaded,
+			// forceDeletion indicates that resources associated with the member cluster
+			// should still be removed from the host cluster.
+		}
+
+		return UnjoinCluster(hostConfig, clusterConfig, j.KubeFedNamespace,
+			hostClusterName, j.HostClusterContext, cluster.Context, cluster.Name, j.forceDeletion, j.DryRun)
+	})
 }
 
 // UnjoinCluster performs all the necessary steps to remove the
@@ -191,8 +207,20 @@ func UnjoinCluster(hostConfig, clusterConfig *rest.Config, kubefedNamespace, hos
 		return err
 	}
 
+	// A local cluster is accessed by the service account of the
+	// controller manager, whose bindings are deleted instead of a
+	// service account created by join.
+	local := false
+	fedCluster, err := getKubeFedCluster(client, kubefedNamespace, unjoiningClusterName)
+	if err != nil && !forceDeletion {
+		return err
+	}
+	if fedCluster != nil {
+		local = fedCluster.Spec.Local
+	}
+
 	if clusterClientset != nil {
-		err := deleteRBACResources(clusterClientset, kubefedNamespace, unjoiningClusterName, hostClusterName, forceDeletion, dryRun)
+		err := deleteRBACResources(clusterClientset, kubefedNamespace, unjoiningClusterName, hostClusterName, local, forceDeletion, dryRun)
 		if err != nil {
 			if !forceDeletion {
 				return err
@@ -230,41 +258,61 @@ func deleteFederatedClusterAndSecret(hostClientset kubeclient.Interface, client
 		return errors.Wrapf(err, "Failed to get kubefed cluster \"%s/%s\"", kubefedNamespace, unjoiningClusterName)
 	}
 
-	err = hostClientset.CoreV1().Secrets(kubefedNamespace).Delete(fedCluster.Spec.SecretRef.Name,
-		&metav1.DeleteOptions{})
+	if len(fedCluster.Spec.SecretRef.Name) == 0 {
+		// A local cluster has no secret, and deleting a secret with
+		// an empty name would target every secret in the namespace.
+		klog.V(2).Infof("Cluster %q has no secret to delete", unjoiningClusterName)
+	} else if err := deleteClusterSecret(hostClientset, fedCluster, kubefedNamespace, unjoiningClusterName, forceDeletion); err != nil {
+		return err
+	}
+
+	err = client.Delete(context.TODO(), fedCluster, fedCluster.Namespace, fedCluster.Name)
 	if apierrors.IsNotFound(err) {
-		klog.V(2).Infof("Secret \"%s/%s\" does not exist in the host cluster.", kubefedNamespace, fedCluster.Spec.SecretRef.Name)
+		klog.V(2).Infof("KubeFed cluster \"%s/%s\" does not exist in the host cluster.", fedCluster.Namespace, fedCluster.Name)
 	} else if err != nil {
-		wrappedErr := errors.Wrapf(err, "Failed to delete secret \"%s/%s\" for unjoin cluster %q",
-			kubefedNamespace, fedCluster.Spec.SecretRef.Name, unjoiningClusterName)
+		wrappedErr := errors.Wrapf(err, "Failed to delete kubefed cluster \"%s/%s\" for unjoin cluster %q", fedCluster.Namespace, fedCluster.Name, unjoiningClusterName)
 		if !forceDeletion {
 			return wrappedErr
 		}
 		klog.V(2).Infof("%v", wrappedErr)
 	} else {
-		klog.V(2).Infof("Deleted secret \"%s/%s\" for unjoin cluster %q", kubefedNamespace, fedCluster.Spec.SecretRef.Name, unjoiningClusterName)
+		klog.V(2).Infof("Deleted kubefed cluster \"%s/%s\" for unjoin cluster %q.", fedCluster.Namespace, fedCluster.Name, unjoiningClusterName)
 	}
 
-	err = client.Delete(context.TODO(), fedCluster, fedCluster.Namespace, fedCluster.Name)
+	return nil
+}
+
+// deleteClusterSecret deletes the secret referenced by a federated
+// cluster resource from the host cluster.
+func deleteClusterSecret(hostClientset kubeclient.Interface, fedCluster *fedv1b1.KubeFedCluster,
+	kubefedNamespace, unjoiningClusterName string, forceDeletion bool) error {
+	secretNamespace := controllerutil.ClusterSecretNamespace(fedCluster, kubefedNamespace)
+	err := hostClientset.CoreV1().Secrets(secretNamespace).Delete(fedCluster.Spec.SecretRef.Name,
+		&metav1.DeleteOptions{})
 	if apierrors.IsNotFound(err) {
-		klog.V(2).Infof("KubeFed cluster \"%s/%s\" does not exist in the host cluster.", fedCluster.Namespace, fedCluster.Name)
+		klog.V(2).Infof("Secret \"%s/%s\" does not exist in the host cluster.", secretNamespace, fedCluster.Spec.SecretRef.Name)
 	} else if err != nil {
-		wrappedErr := errors.Wrapf(err, "Failed to delete kubefed cluster \"%s/%s\" for unjoin cluster %q", fedCluster.Namespace, fedCluster.Name, unjoiningClusterName)
+		wrappedErr := errors.Wrapf(err, "Failed to delete secret \"%s/%s\" for unjoin cluster %q",
+			secretNamespace, fedCluster.Spec.SecretRef.Name, unjoiningClusterName)
 		if !forceDeletion {
 			return wrappedErr
 		}
 		klog.V(2).Infof("%v", wrappedErr)
 	} else {
-		klog.V(2).Infof("Deleted kubefed cluster \"%s/%s\" for unjoin cluster %q.", fedCluster.Namespace, fedCluster.Name, unjoiningClusterName)
+		klog.V(2).Infof("Deleted secret \"%s/%s\" for unjoin cluster %q", secretNamespace, fedCluster.Spec.SecretRef.Name, unjoiningClusterName)
 	}
-
 	return nil
 }
 
 // deleteRBACResources deletes the cluster role, cluster rolebindings and service account
-// from the unjoining cluster.
+// from the unjoining cluster.  Only the cluster role and bindings of the
+// controller manager's service account are deleted for a local cluster.
 func deleteRBACResources(unjoiningClusterClientset kubeclient.Interface,
-	namespace, unjoiningClusterName, hostClusterName string, forceDeletion, dryRun bool) error {
+	namespace, unjoiningClusterName, hostClusterName string, local, forceDeletion, dryRun bool) error {
+
+	if local {
+		return deleteClusterRoleAndBinding(unjoiningClusterClientset, controllerServiceAccountName, namespace, unjoiningClusterName, forceDeletion, dryRun)
+	}
 
 	saName := util.ClusterServiceAccountName(unjoiningClusterName, hostClusterName)
 
@@ -356,9 +404,10 @@ func deleteClusterRoleAndBinding(clusterClientset kubeclient.Interface,
 
 	roleName := util.RoleName(saName)
 	healthCheckRoleName := util.HealthCheckRoleName(saName, namespace)
+	clusterScopedRoleName := util.ClusterScopedRoleName(saName, namespace)
 
 	// Attempt to delete all role and role bindings created by join
-	for _, name := range []string{roleName, healthCheckRoleName} {
+	for _, name := range []string{roleName, healthCheckRoleName, clusterScopedRoleName} {
 		klog.V(2).Infof("Deleting cluster role binding %q for service account %q in unjoining cluster %q.",
 			name, saName, unjoiningClusterName)
 
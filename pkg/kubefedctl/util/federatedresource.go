@@ -0,0 +1,60 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"github.com/pkg/errors"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	ctlutil "sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+// PropagatedClusterNames returns the names of the clusters reported by
+// the propagation status of the federated resource, or the clusters
+// it is placed on if it has no status.
+func PropagatedClusterNames(fedObj *unstructured.Unstructured) ([]string, error) {
+	statusClusters, ok, err := unstructured.NestedSlice(fedObj.Object, "status", "clusters")
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to read the propagation status")
+	}
+	if !ok {
+		return ctlutil.GetClusterNames(fedObj)
+	}
+	names := []string{}
+	for _, statusCluster := range statusClusters {
+		if fields, ok := statusCluster.(map[string]interface{}); ok {
+			if name, ok := fields["name"].(string); ok {
+				names = append(names, name)
+			}
+		}
+	}
+	return names, nil
+}
+
+// ClusterTargetName returns the name of the target resource of a
+// federated resource in the given cluster, accounting for renaming by
+// the overrides of the cluster and for its namespace mappings.
+func ClusterTargetName(cluster *fedv1b1.KubeFedCluster, overrides ctlutil.ClusterOverridesMap,
+	targetIsNamespace bool, targetName ctlutil.QualifiedName) ctlutil.QualifiedName {
+
+	if name, ok := overrides[ctlutil.NameOverridePath].(string); ok && !targetIsNamespace {
+		targetName.Name = name
+	}
+	return ctlutil.ClusterQualifiedName(cluster.Spec.NamespaceMappings, targetIsNamespace, targetName)
+}
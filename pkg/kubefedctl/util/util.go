@@ -112,3 +112,11 @@ func RoleName(serviceAccountName string) string {
 func HealthCheckRoleName(serviceAccountName, namespace string) string {
 	return fmt.Sprintf("kubefed-controller-manager:%s:healthcheck-%s", namespace, serviceAccountName)
 }
+
+// ClusterScopedRoleName returns the name of a ClusterRole and its
+// associated ClusterRoleBinding that is used to allow the service
+// account of a namespace-scoped control plane to propagate the
+// cluster-scoped resources allowed by its KubeFedConfig.
+func ClusterScopedRoleName(serviceAccountName, namespace string) string {
+	return fmt.Sprintf("kubefed-controller-manager:%s:cluster-scoped-%s", namespace, serviceAccountName)
+}
@@ -0,0 +1,209 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"k8s.io/apimachinery/pkg/util/sets"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	healthCheckSucceeded = "success"
+	healthCheckFailed    = "error"
+)
+
+var (
+	clusterAPILatency = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "kubefed_cluster_api_latency_seconds",
+			Help:    "Round-trip latency of health checks of the API server of a member cluster.",
+			Buckets: prometheus.ExponentialBuckets(0.005, 2, 12),
+		},
+		[]string{"cluster_name"},
+	)
+
+	clusterHealthChecks = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kubefed_cluster_health_checks_total",
+			Help: "Number of health checks of the API server of a member cluster by result.",
+		},
+		[]string{"cluster_name", "result"},
+	)
+
+	workqueueDepth = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kubefed_workqueue_depth",
+			Help: "Number of items waiting in the work queue of a controller.",
+		},
+		[]string{"controller", "type_config"},
+	)
+
+	workqueueAdds = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kubefed_workqueue_adds_total",
+			Help: "Number of items added to the work queue of a controller.",
+		},
+		[]string{"controller", "type_config"},
+	)
+
+	workqueueRetries = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kubefed_workqueue_retries_total",
+			Help: "Number of items requeued by a controller after reconciliation by reason.",
+		},
+		[]string{"controller", "type_config", "reason"},
+	)
+
+	reconcileDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "kubefed_reconcile_duration_seconds",
+			Help:    "Time taken by a controller to reconcile an item.",
+			Buckets: prometheus.ExponentialBuckets(0.001, 2, 15),
+		},
+		[]string{"controller", "type_config"},
+	)
+
+	reconcileErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kubefed_reconcile_errors_total",
+			Help: "Number of reconciliations of an item by a controller that resulted in an error.",
+		},
+		[]string{"controller", "type_config"},
+	)
+
+	scheduledReplicas = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kubefed_scheduled_replicas",
+			Help: "Number of replicas assigned to a member cluster by a ReplicaSchedulingPreference.",
+		},
+		[]string{"namespace", "name", "cluster_name"},
+	)
+
+	replicaDistributionChanges = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kubefed_replica_distribution_changes_total",
+			Help: "Number of changes to the distribution of replicas by a ReplicaSchedulingPreference by trigger.",
+		},
+		[]string{"trigger"},
+	)
+
+	// The clusters for which replicas are recorded, by the namespaced
+	// name of the ReplicaSchedulingPreference.
+	scheduledClusters     = map[string]sets.String{}
+	scheduledClustersLock sync.Mutex
+)
+
+func init() {
+	prometheus.MustRegister(clusterAPILatency, clusterHealthChecks,
+		workqueueDepth, workqueueAdds, workqueueRetries,
+		reconcileDuration, reconcileErrors,
+		scheduledReplicas, replicaDistributionChanges)
+}
+
+// Handler returns the handler serving the registered metrics,
+// including those of the controller-runtime manager and of the client
+// and work queues it instruments.
+func Handler() http.Handler {
+	gatherers := prometheus.Gatherers{prometheus.DefaultGatherer, crmetrics.Registry}
+	return promhttp.HandlerFor(gatherers, promhttp.HandlerOpts{ErrorHandling: promhttp.ContinueOnError})
+}
+
+// RecordClusterHealthCheck records the result of a health check of the
+// API server of the named cluster.  The latency is only recorded for
+// checks that reached the API server.
+func RecordClusterHealthCheck(clusterName string, latency time.Duration, err error) {
+	if err != nil {
+		clusterHealthChecks.WithLabelValues(clusterName, healthCheckFailed).Inc()
+		return
+	}
+	clusterHealthChecks.WithLabelValues(clusterName, healthCheckSucceeded).Inc()
+	clusterAPILatency.WithLabelValues(clusterName).Observe(latency.Seconds())
+}
+
+// RecordWorkqueueAdd records the addition of an item to the work queue
+// of the given controller and the resulting depth of the queue.
+// typeConfig is the name of the FederatedTypeConfig the controller is
+// running for, or empty for controllers not specific to a type.
+func RecordWorkqueueAdd(controller, typeConfig string, depth int) {
+	workqueueAdds.WithLabelValues(controller, typeConfig).Inc()
+	workqueueDepth.WithLabelValues(controller, typeConfig).Set(float64(depth))
+}
+
+// RecordWorkqueueDepth records the depth of the work queue of the
+// given controller.
+func RecordWorkqueueDepth(controller, typeConfig string, depth int) {
+	workqueueDepth.WithLabelValues(controller, typeConfig).Set(float64(depth))
+}
+
+// RecordWorkqueueRetry records the requeueing of an item by the given
+// controller for the given reason.
+func RecordWorkqueueRetry(controller, typeConfig, reason string) {
+	workqueueRetries.WithLabelValues(controller, typeConfig, reason).Inc()
+}
+
+// RecordReconcile records the duration of a reconciliation by the given
+// controller and whether it resulted in an error.
+func RecordReconcile(controller, typeConfig string, duration time.Duration, failed bool) {
+	reconcileDuration.WithLabelValues(controller, typeConfig).Observe(duration.Seconds())
+	if failed {
+		reconcileErrors.WithLabelValues(controller, typeConfig).Inc()
+	}
+}
+
+// RecordScheduledReplicas records the replicas assigned to each member
+// cluster by the named ReplicaSchedulingPreference.  The replicas of
+// clusters that are no longer assigned any are no longer reported.
+func RecordScheduledReplicas(namespace, name string, replicas map[string]int64) {
+	scheduledClustersLock.Lock()
+	defer scheduledClustersLock.Unlock()
+
+	key := namespace + "/" + name
+	clusterNames := sets.NewString()
+	for clusterName, clusterReplicas := range replicas {
+		scheduledReplicas.WithLabelValues(namespace, name, clusterName).Set(float64(clusterReplicas))
+		clusterNames.Insert(clusterName)
+	}
+	for clusterName := range scheduledClusters[key].Difference(clusterNames) {
+		scheduledReplicas.DeleteLabelValues(namespace, name, clusterName)
+	}
+	scheduledClusters[key] = clusterNames
+}
+
+// DeleteScheduledReplicas stops reporting the replicas assigned by the
+// named ReplicaSchedulingPreference.
+func DeleteScheduledReplicas(namespace, name string) {
+	scheduledClustersLock.Lock()
+	defer scheduledClustersLock.Unlock()
+
+	key := namespace + "/" + name
+	for clusterName := range scheduledClusters[key] {
+		scheduledReplicas.DeleteLabelValues(namespace, name, clusterName)
+	}
+	delete(scheduledClusters, key)
+}
+
+// RecordReplicaDistributionChange records a change to the distribution
+// of replicas by a ReplicaSchedulingPreference for the given trigger.
+func RecordReplicaDistributionChange(trigger string) {
+	replicaDistributionChanges.WithLabelValues(trigger).Inc()
+}
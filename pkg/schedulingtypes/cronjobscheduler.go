@@ -0,0 +1,266 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedulingtypes
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/pkg/errors"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	fedschedulingv1a1 "sigs.k8s.io/kubefed/pkg/apis/scheduling/v1alpha1"
+	ctlutil "sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+const (
+	CSPKind = "CronJobSchedulingPreference"
+
+	suspendPath = "spec.suspend"
+)
+
+func init() {
+	RegisterSchedulingType("cronjobs.batch", SchedulingType{
+		Kind:             CSPKind,
+		SchedulerFactory: NewCronJobScheduler,
+	})
+}
+
+// CronJobScheduler ensures that the occurrences of federated cron jobs
+// are only run by a single cluster by suspending the cron job in all
+// other clusters according to their CronJobSchedulingPreference.
+type CronJobScheduler struct {
+	pluginScheduler
+}
+
+func NewCronJobScheduler(controllerConfig *ctlutil.ControllerConfig, eventHandlers SchedulerEventHandlers) (Scheduler, error) {
+	return &CronJobScheduler{
+		pluginScheduler: newPluginScheduler(controllerConfig, eventHandlers, "cronjob-scheduler"),
+	}, nil
+}
+
+func (s *CronJobScheduler) SchedulingKind() string {
+	return CSPKind
+}
+
+func (s *CronJobScheduler) ObjectType() pkgruntime.Object {
+	return &fedschedulingv1a1.CronJobSchedulingPreference{}
+}
+
+func (s *CronJobScheduler) Reconcile(obj pkgruntime.Object, qualifiedName ctlutil.QualifiedName) ctlutil.ReconciliationStatus {
+	csp, ok := obj.(*fedschedulingv1a1.CronJobSchedulingPreference)
+	if !ok {
+		runtime.HandleError(errors.Errorf("Incorrect runtime object for CSP: %v", obj))
+		return ctlutil.StatusError
+	}
+
+	kind := csp.Spec.TargetKind
+	if kind != "FederatedCronJob" {
+		runtime.HandleError(errors.Errorf("CSP target kind: %s is incorrect", kind))
+		return ctlutil.StatusNeedsRecheck
+	}
+
+	plugin, ok := s.plugin(kind)
+	if !ok {
+		return ctlutil.StatusAllOK
+	}
+
+	key := qualifiedName.String()
+	if !plugin.FederatedTypeExists(key) {
+		// target FederatedType does not exist, nothing to do
+		return ctlutil.StatusAllOK
+	}
+
+	clusters, err := plugin.targetInformer.GetClusters()
+	if err != nil {
+		runtime.HandleError(errors.Wrap(err, "Failed to get cluster list"))
+		return ctlutil.StatusError
+	}
+	readyClusters, err := plugin.targetInformer.GetReadyClusters()
+	if err != nil {
+		runtime.HandleError(errors.Wrap(err, "Failed to get ready cluster list"))
+		return ctlutil.StatusError
+	}
+	if len(clusters) == 0 {
+		// no joined clusters, nothing to do
+		return ctlutil.StatusAllOK
+	}
+
+	readyClusterNames := sets.NewString()
+	for _, cluster := range readyClusters {
+		readyClusterNames.Insert(cluster.Name)
+	}
+	lastScheduleTimes, err := cronJobLastScheduleTimes(readyClusterNames.List(), key, plugin.ClusterObject)
+	if err != nil {
+		runtime.HandleError(errors.Wrapf(err, "Failed to retrieve the cron jobs of CSP named %q", key))
+		return ctlutil.StatusError
+	}
+
+	candidates := cronJobClusters(csp, clusters)
+	status := cronJobStatus(csp, candidates, readyClusterNames, lastScheduleTimes)
+	if status.ActiveCluster != csp.Status.ActiveCluster {
+		klog.V(2).Infof("Moving the execution of the cron job of CSP %q from cluster %q to cluster %q", key, csp.Status.ActiveCluster, status.ActiveCluster)
+	}
+
+	suspend := make(map[string]interface{})
+	for _, clusterName := range candidates {
+		suspend[clusterName] = clusterName != status.ActiveCluster
+	}
+	err = plugin.ReconcileOverrides(qualifiedName, candidates, map[string]map[string]interface{}{suspendPath: suspend})
+	if err != nil {
+		runtime.HandleError(errors.Wrapf(err, "Failed to reconcile federated targets for CSP named %q", key))
+		return ctlutil.StatusError
+	}
+
+	if reflect.DeepEqual(status, csp.Status) {
+		return ctlutil.StatusAllOK
+	}
+	csp.Status = status
+	err = s.client.UpdateStatus(context.TODO(), csp)
+	if err != nil {
+		runtime.HandleError(errors.Wrapf(err, "Failed to update the status of CSP named %q", key))
+		return ctlutil.StatusError
+	}
+
+	return ctlutil.StatusAllOK
+}
+
+// cronJobClusters returns the names of the clusters the cron job of
+// the given CSP is placed in, in order of preference.
+func cronJobClusters(csp *fedschedulingv1a1.CronJobSchedulingPreference, clusters []*fedv1b1.KubeFedCluster) []string {
+	clusterNames := sets.NewString()
+	for _, cluster := range clusters {
+		clusterNames.Insert(cluster.Name)
+	}
+	if len(csp.Spec.Clusters) == 0 {
+		return clusterNames.List()
+	}
+
+	result := []string{}
+	added := sets.NewString()
+	for _, clusterName := range csp.Spec.Clusters {
+		if clusterNames.Has(clusterName) && !added.Has(clusterName) {
+			result = append(result, clusterName)
+			added.Insert(clusterName)
+		}
+	}
+	return result
+}
+
+// cronJobLastScheduleTimes returns the last schedule time of the cron
+// job with the given key in each of the given clusters that has
+// scheduled it.
+func cronJobLastScheduleTimes(clusterNames []string, key string,
+	objectGetter func(clusterName, key string) (*unstructured.Unstructured, error)) (map[string]metav1.Time, error) {
+
+	result := make(map[string]metav1.Time)
+	for _, clusterName := range clusterNames {
+		obj, err := objectGetter(clusterName, key)
+		if err != nil {
+			return nil, err
+		}
+		if obj == nil {
+			continue
+		}
+		value, ok, err := unstructured.NestedString(obj.Object, "status", "lastScheduleTime")
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error retrieving 'lastScheduleTime' field in cluster %q", clusterName)
+		}
+		if !ok {
+			continue
+		}
+		lastScheduleTime, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Invalid 'lastScheduleTime' in cluster %q", clusterName)
+		}
+		result[clusterName] = metav1.NewTime(lastScheduleTime)
+	}
+	return result, nil
+}
+
+// cronJobStatus returns the status of the given CSP that identifies
+// the cluster that should run the occurrences of its cron job.
+//
+// In Pinned mode, the first ready cluster of the given candidates is
+// active.  In Rotated mode, the active cluster remains active until
+// its cron job has been scheduled, after which the next ready
+// candidate becomes active.  No cluster is active if no candidate is
+// ready.
+func cronJobStatus(csp *fedschedulingv1a1.CronJobSchedulingPreference, candidates []string, readyClusters sets.String,
+	lastScheduleTimes map[string]metav1.Time) fedschedulingv1a1.CronJobSchedulingPreferenceStatus {
+
+	current := csp.Status.ActiveCluster
+	currentIndex := -1
+	for i, clusterName := range candidates {
+		if clusterName == current {
+			currentIndex = i
+			break
+		}
+	}
+
+	active := ""
+	if csp.Spec.Mode == fedschedulingv1a1.RotatedExecution {
+		if currentIndex >= 0 && readyClusters.Has(current) && !hasScheduledSince(lastScheduleTimes, current, csp.Status.LastScheduleTime) {
+			return csp.Status
+		}
+		// Start from the cluster after the current one, wrapping
+		// around to the current cluster if it is the only one ready.
+		for i := 1; i <= len(candidates); i++ {
+			clusterName := candidates[(currentIndex+i+len(candidates))%len(candidates)]
+			if readyClusters.Has(clusterName) {
+				active = clusterName
+				break
+			}
+		}
+	} else {
+		for _, clusterName := range candidates {
+			if readyClusters.Has(clusterName) {
+				active = clusterName
+				break
+			}
+		}
+		if active == current {
+			return csp.Status
+		}
+	}
+
+	status := fedschedulingv1a1.CronJobSchedulingPreferenceStatus{
+		ActiveCluster: active,
+	}
+	if lastScheduleTime, ok := lastScheduleTimes[active]; ok {
+		status.LastScheduleTime = &lastScheduleTime
+	}
+	return status
+}
+
+// hasScheduledSince returns whether the cron job in the given cluster
+// has been scheduled after the given time.
+func hasScheduledSince(lastScheduleTimes map[string]metav1.Time, clusterName string, since *metav1.Time) bool {
+	lastScheduleTime, ok := lastScheduleTimes[clusterName]
+	if !ok {
+		return false
+	}
+	return since == nil || since.Before(&lastScheduleTime)
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedulingtypes
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	fedschedulingv1a1 "sigs.k8s.io/kubefed/pkg/apis/scheduling/v1alpha1"
+)
+
+func newCSP(mode fedschedulingv1a1.CronJobExecutionMode, activeCluster string, lastScheduleTime *metav1.Time) *fedschedulingv1a1.CronJobSchedulingPreference {
+	return &fedschedulingv1a1.CronJobSchedulingPreference{
+		Spec: fedschedulingv1a1.CronJobSchedulingPreferenceSpec{
+			TargetKind: "FederatedCronJob",
+			Mode:       mode,
+		},
+		Status: fedschedulingv1a1.CronJobSchedulingPreferenceStatus{
+			ActiveCluster:    activeCluster,
+			LastScheduleTime: lastScheduleTime,
+		},
+	}
+}
+
+func TestCronJobClusters(t *testing.T) {
+	clusters := []*fedv1b1.KubeFedCluster{
+		newCluster("C", ""),
+		newCluster("A", ""),
+		newCluster("B", ""),
+	}
+
+	csp := newCSP(fedschedulingv1a1.PinnedExecution, "", nil)
+	assert.Equal(t, []string{"A", "B", "C"}, cronJobClusters(csp, clusters))
+
+	csp.Spec.Clusters = []string{"C", "D", "A", "C"}
+	assert.Equal(t, []string{"C", "A"}, cronJobClusters(csp, clusters))
+}
+
+func TestCronJobStatus(t *testing.T) {
+	earlier := metav1.NewTime(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC))
+	later := metav1.NewTime(earlier.Add(time.Hour))
+	candidates := []string{"A", "B", "C"}
+
+	testCases := map[string]struct {
+		csp               *fedschedulingv1a1.CronJobSchedulingPreference
+		readyClusters     sets.String
+		lastScheduleTimes map[string]metav1.Time
+		expected          fedschedulingv1a1.CronJobSchedulingPreferenceStatus
+	}{
+		"Pinned execution starts in the first ready cluster": {
+			csp:               newCSP(fedschedulingv1a1.PinnedExecution, "", nil),
+			readyClusters:     sets.NewString("A", "B", "C"),
+			lastScheduleTimes: map[string]metav1.Time{"A": earlier},
+			expected:          fedschedulingv1a1.CronJobSchedulingPreferenceStatus{ActiveCluster: "A", LastScheduleTime: &earlier},
+		},
+		"Pinned execution remains in the active cluster after it runs": {
+			csp:               newCSP(fedschedulingv1a1.PinnedExecution, "A", &earlier),
+			readyClusters:     sets.NewString("A", "B", "C"),
+			lastScheduleTimes: map[string]metav1.Time{"A": later},
+			expected:          fedschedulingv1a1.CronJobSchedulingPreferenceStatus{ActiveCluster: "A", LastScheduleTime: &earlier},
+		},
+		"Pinned execution fails over to the next ready cluster": {
+			csp:           newCSP(fedschedulingv1a1.PinnedExecution, "A", &earlier),
+			readyClusters: sets.NewString("C"),
+			expected:      fedschedulingv1a1.CronJobSchedulingPreferenceStatus{ActiveCluster: "C"},
+		},
+		"Pinned execution fails back to the preferred cluster": {
+			csp:           newCSP(fedschedulingv1a1.PinnedExecution, "C", nil),
+			readyClusters: sets.NewString("A", "C"),
+			expected:      fedschedulingv1a1.CronJobSchedulingPreferenceStatus{ActiveCluster: "A"},
+		},
+		"Rotated execution remains in the active cluster until it runs": {
+			csp:               newCSP(fedschedulingv1a1.RotatedExecution, "A", &earlier),
+			readyClusters:     sets.NewString("A", "B", "C"),
+			lastScheduleTimes: map[string]metav1.Time{"A": earlier},
+			expected:          fedschedulingv1a1.CronJobSchedulingPreferenceStatus{ActiveCluster: "A", LastScheduleTime: &earlier},
+		},
+		"Rotated execution moves to the next cluster after it runs": {
+			csp:               newCSP(fedschedulingv1a1.RotatedExecution, "A", &earlier),
+			readyClusters:     sets.NewString("A", "B", "C"),
+			lastScheduleTimes: map[string]metav1.Time{"A": later, "B": earlier},
+			expected:          fedschedulingv1a1.CronJobSchedulingPreferenceStatus{ActiveCluster: "B", LastScheduleTime: &earlier},
+		},
+		"Rotated execution skips clusters that are not ready": {
+			csp:               newCSP(fedschedulingv1a1.RotatedExecution, "B", nil),
+			readyClusters:     sets.NewString("A", "B"),
+			lastScheduleTimes: map[string]metav1.Time{"B": later},
+			expected:          fedschedulingv1a1.CronJobSchedulingPreferenceStatus{ActiveCluster: "A"},
+		},
+		"Rotated execution remains in the only ready cluster": {
+			csp:               newCSP(fedschedulingv1a1.RotatedExecution, "B", &earlier),
+			readyClusters:     sets.NewString("B"),
+			lastScheduleTimes: map[string]metav1.Time{"B": later},
+			expected:          fedschedulingv1a1.CronJobSchedulingPreferenceStatus{ActiveCluster: "B", LastScheduleTime: &later},
+		},
+		"No cluster is active if none is ready": {
+			csp:           newCSP(fedschedulingv1a1.RotatedExecution, "A", &earlier),
+			readyClusters: sets.NewString(),
+			expected:      fedschedulingv1a1.CronJobSchedulingPreferenceStatus{},
+		},
+	}
+
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			status := cronJobStatus(tc.csp, candidates, tc.readyClusters, tc.lastScheduleTimes)
+			assert.Equal(t, tc.expected, status)
+		})
+	}
+}
@@ -18,6 +18,7 @@ package schedulingtypes
 
 import (
 	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 
 	"sigs.k8s.io/kubefed/pkg/apis/core/typeconfig"
 	. "sigs.k8s.io/kubefed/pkg/controller/util"
@@ -40,6 +41,9 @@ type SchedulerEventHandlers struct {
 	KubeFedEventHandler      func(pkgruntime.Object)
 	ClusterEventHandler      func(pkgruntime.Object)
 	ClusterLifecycleHandlers *ClusterLifecycleHandlerFuncs
+	// EventRecorder records the events of scheduling decisions on
+	// the scheduling preferences.
+	EventRecorder record.EventRecorder
 }
 
 type SchedulerFactory func(controllerConfig *ControllerConfig, eventHandlers SchedulerEventHandlers) (Scheduler, error)
@@ -0,0 +1,333 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedulingtypes
+
+import (
+	"context"
+	"reflect"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	batchv1 "k8s.io/api/batch/v1"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/klog"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	fedschedulingv1a1 "sigs.k8s.io/kubefed/pkg/apis/scheduling/v1alpha1"
+	ctlutil "sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/controller/util/planner"
+)
+
+const (
+	JSPKind = "JobSchedulingPreference"
+
+	completionsPath = "spec.completions"
+	parallelismPath = "spec.parallelism"
+)
+
+func init() {
+	RegisterSchedulingType("jobs.batch", SchedulingType{
+		Kind:             JSPKind,
+		SchedulerFactory: NewJobScheduler,
+	})
+}
+
+// JobScheduler splits the completions and parallelism of federated
+// jobs across clusters according to their JobSchedulingPreference,
+// and aggregates the status of the jobs in the clusters in the status
+// of the preference.
+type JobScheduler struct {
+	pluginScheduler
+}
+
+func NewJobScheduler(controllerConfig *ctlutil.ControllerConfig, eventHandlers SchedulerEventHandlers) (Scheduler, error) {
+	return &JobScheduler{
+		pluginScheduler: newPluginScheduler(controllerConfig, eventHandlers, "job-scheduler"),
+	}, nil
+}
+
+func (s *JobScheduler) SchedulingKind() string {
+	return JSPKind
+}
+
+func (s *JobScheduler) ObjectType() pkgruntime.Object {
+	return &fedschedulingv1a1.JobSchedulingPreference{}
+}
+
+func (s *JobScheduler) Reconcile(obj pkgruntime.Object, qualifiedName ctlutil.QualifiedName) ctlutil.ReconciliationStatus {
+	jsp, ok := obj.(*fedschedulingv1a1.JobSchedulingPreference)
+	if !ok {
+		runtime.HandleError(errors.Errorf("Incorrect runtime object for JSP: %v", obj))
+		return ctlutil.StatusError
+	}
+
+	kind := jsp.Spec.TargetKind
+	if kind != "FederatedJob" {
+		runtime.HandleError(errors.Errorf("JSP target kind: %s is incorrect", kind))
+		return ctlutil.StatusNeedsRecheck
+	}
+
+	plugin, ok := s.plugin(kind)
+	if !ok {
+		return ctlutil.StatusAllOK
+	}
+
+	key := qualifiedName.String()
+	if !plugin.FederatedTypeExists(key) {
+		// target FederatedType does not exist, nothing to do
+		return ctlutil.StatusAllOK
+	}
+
+	clusters, err := plugin.targetInformer.GetReadyClusters()
+	if err != nil {
+		runtime.HandleError(errors.Wrap(err, "Failed to get cluster list"))
+		return ctlutil.StatusError
+	}
+	if len(clusters) == 0 {
+		// no joined clusters, nothing to do
+		return ctlutil.StatusAllOK
+	}
+
+	jobs, err := clusterJobs(clusters, key, plugin.ClusterObject)
+	if err != nil {
+		runtime.HandleError(errors.Wrapf(err, "Failed to retrieve the jobs of JSP named %q", key))
+		return ctlutil.StatusError
+	}
+
+	preference := jobPlanningPreference(jsp)
+	clusters = schedulableClusters(preference, clusters, profileFilters(&fedv1b1.SchedulingProfile{}))
+	clusterNames := []string{}
+	for _, cluster := range clusters {
+		clusterNames = append(clusterNames, cluster.Name)
+	}
+	preference = resolveClusterWeights(preference, clusters)
+
+	completions, parallelism, err := scheduleJob(preference, jsp, key, clusterNames, jobs)
+	if err != nil {
+		runtime.HandleError(errors.Wrapf(err, "Failed to compute the schedule information while reconciling JSP named %q", key))
+		return ctlutil.StatusError
+	}
+
+	scheduledClusters := []string{}
+	for clusterName := range completions {
+		scheduledClusters = append(scheduledClusters, clusterName)
+	}
+	err = plugin.ReconcileOverrides(qualifiedName, scheduledClusters, map[string]map[string]interface{}{
+		completionsPath: overrideValues(completions),
+		parallelismPath: overrideValues(parallelism),
+	})
+	if err != nil {
+		runtime.HandleError(errors.Wrapf(err, "Failed to reconcile federated targets for JSP named %q", key))
+		return ctlutil.StatusError
+	}
+
+	status := jobStatus(jsp, completions, parallelism, jobs)
+	if reflect.DeepEqual(status, jsp.Status) {
+		return ctlutil.StatusAllOK
+	}
+	jsp.Status = status
+	err = s.client.UpdateStatus(context.TODO(), jsp)
+	if err != nil {
+		runtime.HandleError(errors.Wrapf(err, "Failed to update the status of JSP named %q", key))
+		return ctlutil.StatusError
+	}
+
+	return ctlutil.StatusAllOK
+}
+
+// clusterJobs returns the jobs with the given key in each of the given
+// clusters.  Jobs that are being deleted are ignored.
+func clusterJobs(clusters []*fedv1b1.KubeFedCluster, key string,
+	objectGetter func(clusterName, key string) (*unstructured.Unstructured, error)) (map[string]*batchv1.Job, error) {
+
+	jobs := make(map[string]*batchv1.Job)
+	for _, cluster := range clusters {
+		obj, err := objectGetter(cluster.Name, key)
+		if err != nil {
+			return nil, err
+		}
+		if obj == nil || obj.GetDeletionTimestamp() != nil {
+			continue
+		}
+		job := &batchv1.Job{}
+		if err := ctlutil.UnstructuredToInterface(obj, job); err != nil {
+			return nil, errors.Wrapf(err, "Failed to convert the job in cluster %q", cluster.Name)
+		}
+		jobs[cluster.Name] = job
+	}
+	return jobs, nil
+}
+
+// jobPlanningPreference returns the replica scheduling preference used
+// to plan the completions of the job of the given JSP.
+func jobPlanningPreference(jsp *fedschedulingv1a1.JobSchedulingPreference) *fedschedulingv1a1.ReplicaSchedulingPreference {
+	preference := &fedschedulingv1a1.ReplicaSchedulingPreference{
+		ObjectMeta: jsp.ObjectMeta,
+		Spec: fedschedulingv1a1.ReplicaSchedulingPreferenceSpec{
+			TargetKind:    jsp.Spec.TargetKind,
+			TotalReplicas: jsp.Spec.TotalCompletions,
+			Rebalance:     true,
+			Clusters:      make(map[string]fedschedulingv1a1.ClusterPreferences),
+			Tolerations:   jsp.Spec.Tolerations,
+		},
+	}
+	for clusterName, clusterPreference := range jsp.Spec.Clusters {
+		preference.Spec.Clusters[clusterName] = clusterPreference
+	}
+	if len(preference.Spec.Clusters) == 0 {
+		preference.Spec.Clusters["*"] = fedschedulingv1a1.ClusterPreferences{Weight: 1}
+	}
+	return preference
+}
+
+// scheduleJob returns the completions and parallelism of the job of
+// the given JSP in each cluster it is scheduled to.  The completions
+// of the job cannot be changed once it has been created, so the jobs
+// that exist in clusters retain their completions and only the
+// remaining completions are planned across the given clusters that do
+// not run the job yet.  Parallelism is planned by the weights of the
+// clusters, limited to the completions the job has yet to achieve in
+// each cluster.
+func scheduleJob(preference *fedschedulingv1a1.ReplicaSchedulingPreference, jsp *fedschedulingv1a1.JobSchedulingPreference,
+	key string, clusterNames []string, jobs map[string]*batchv1.Job) (map[string]int64, map[string]int64, error) {
+
+	completions := make(map[string]int64)
+	remaining := int64(jsp.Spec.TotalCompletions)
+	for clusterName, job := range jobs {
+		jobCompletions := int64(1)
+		if job.Spec.Completions != nil {
+			jobCompletions = int64(*job.Spec.Completions)
+		}
+		completions[clusterName] = jobCompletions
+		remaining -= jobCompletions
+	}
+	if remaining < 0 {
+		klog.Warningf("The jobs of JSP %s/%s have %d more completions than the total of %d since the completions of jobs cannot be reduced",
+			jsp.Namespace, jsp.Name, -remaining, jsp.Spec.TotalCompletions)
+	}
+
+	availableClusters := []string{}
+	for _, clusterName := range clusterNames {
+		if _, ok := jobs[clusterName]; !ok {
+			availableClusters = append(availableClusters, clusterName)
+		}
+	}
+	if remaining > 0 && len(availableClusters) > 0 {
+		completionsPreference := preference.DeepCopy()
+		completionsPreference.Spec.TotalReplicas = int32(remaining)
+		plan, _, err := planner.NewPlanner(completionsPreference).Plan(availableClusters, nil, nil, key)
+		if err != nil {
+			return nil, nil, err
+		}
+		for clusterName, clusterCompletions := range plan {
+			if clusterCompletions > 0 {
+				completions[clusterName] = clusterCompletions
+			}
+		}
+	}
+
+	totalParallelism := jsp.Spec.TotalCompletions
+	if jsp.Spec.TotalParallelism != nil {
+		totalParallelism = *jsp.Spec.TotalParallelism
+	}
+	// Parallelism is only bounded by the completions remaining in
+	// each cluster.
+	parallelismPreference := preference.DeepCopy()
+	parallelismPreference.Spec.TotalReplicas = totalParallelism
+	for clusterName, clusterPreference := range parallelismPreference.Spec.Clusters {
+		parallelismPreference.Spec.Clusters[clusterName] = fedschedulingv1a1.ClusterPreferences{Weight: clusterPreference.Weight}
+	}
+	scheduledClusters := []string{}
+	remainingCompletions := make(map[string]int64)
+	for clusterName, clusterCompletions := range completions {
+		scheduledClusters = append(scheduledClusters, clusterName)
+		clusterRemaining := clusterCompletions
+		if job, ok := jobs[clusterName]; ok {
+			clusterRemaining -= int64(job.Status.Succeeded)
+		}
+		if clusterRemaining < 0 {
+			clusterRemaining = 0
+		}
+		remainingCompletions[clusterName] = clusterRemaining
+	}
+	sort.Strings(scheduledClusters)
+	plan, _, err := planner.NewPlanner(parallelismPreference).Plan(scheduledClusters, nil, remainingCompletions, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	parallelism := make(map[string]int64)
+	for _, clusterName := range scheduledClusters {
+		clusterParallelism := plan[clusterName]
+		// A job with completions remaining needs at least one pod
+		// to make progress.
+		if clusterParallelism < 1 && remainingCompletions[clusterName] > 0 {
+			clusterParallelism = 1
+		}
+		parallelism[clusterName] = clusterParallelism
+	}
+
+	return completions, parallelism, nil
+}
+
+// jobStatus returns the status of the given JSP that aggregates the
+// status of its jobs in the clusters they are scheduled to.
+func jobStatus(jsp *fedschedulingv1a1.JobSchedulingPreference, completions, parallelism map[string]int64,
+	jobs map[string]*batchv1.Job) fedschedulingv1a1.JobSchedulingPreferenceStatus {
+
+	clusterNames := []string{}
+	for clusterName := range completions {
+		clusterNames = append(clusterNames, clusterName)
+	}
+	sort.Strings(clusterNames)
+
+	status := fedschedulingv1a1.JobSchedulingPreferenceStatus{}
+	for _, clusterName := range clusterNames {
+		clusterStatus := fedschedulingv1a1.JobClusterStatus{
+			Name:        clusterName,
+			Completions: int32(completions[clusterName]),
+			Parallelism: int32(parallelism[clusterName]),
+		}
+		if job, ok := jobs[clusterName]; ok {
+			clusterStatus.Active = job.Status.Active
+			clusterStatus.Succeeded = job.Status.Succeeded
+			clusterStatus.Failed = job.Status.Failed
+			clusterStatus.Condition = jobFinishedCondition(job)
+		}
+		status.Active += clusterStatus.Active
+		status.Succeeded += clusterStatus.Succeeded
+		status.Failed += clusterStatus.Failed
+		status.Clusters = append(status.Clusters, clusterStatus)
+	}
+	status.Complete = status.Succeeded >= jsp.Spec.TotalCompletions
+	return status
+}
+
+// jobFinishedCondition returns the type of the condition that
+// indicates that the given job has finished, or an empty string if it
+// has not.
+func jobFinishedCondition(job *batchv1.Job) batchv1.JobConditionType {
+	for _, condition := range job.Status.Conditions {
+		if (condition.Type == batchv1.JobComplete || condition.Type == batchv1.JobFailed) && condition.Status == apiv1.ConditionTrue {
+			return condition.Type
+		}
+	}
+	return ""
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedulingtypes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	batchv1 "k8s.io/api/batch/v1"
+	apiv1 "k8s.io/api/core/v1"
+
+	fedschedulingv1a1 "sigs.k8s.io/kubefed/pkg/apis/scheduling/v1alpha1"
+)
+
+func newJSP(totalCompletions int32, totalParallelism *int32, clusters map[string]fedschedulingv1a1.ClusterPreferences) *fedschedulingv1a1.JobSchedulingPreference {
+	return &fedschedulingv1a1.JobSchedulingPreference{
+		Spec: fedschedulingv1a1.JobSchedulingPreferenceSpec{
+			TargetKind:       "FederatedJob",
+			TotalCompletions: totalCompletions,
+			TotalParallelism: totalParallelism,
+			Clusters:         clusters,
+		},
+	}
+}
+
+func newJob(completions, succeeded int32, finished batchv1.JobConditionType) *batchv1.Job {
+	job := &batchv1.Job{
+		Spec: batchv1.JobSpec{
+			Completions: &completions,
+		},
+		Status: batchv1.JobStatus{
+			Succeeded: succeeded,
+		},
+	}
+	if len(finished) > 0 {
+		job.Status.Conditions = []batchv1.JobCondition{
+			{Type: finished, Status: apiv1.ConditionTrue},
+		}
+	}
+	return job
+}
+
+func int32Ptr(value int32) *int32 {
+	return &value
+}
+
+func int64Ptr(value int64) *int64 {
+	return &value
+}
+
+func TestScheduleJob(t *testing.T) {
+	testCases := map[string]struct {
+		jsp                 *fedschedulingv1a1.JobSchedulingPreference
+		jobs                map[string]*batchv1.Job
+		expectedCompletions map[string]int64
+		expectedParallelism map[string]int64
+	}{
+		"Completions and parallelism are split evenly by default": {
+			jsp:                 newJSP(9, nil, nil),
+			expectedCompletions: map[string]int64{"A": 3, "B": 3, "C": 3},
+			expectedParallelism: map[string]int64{"A": 3, "B": 3, "C": 3},
+		},
+		"Completions are split by weight": {
+			jsp: newJSP(8, nil, map[string]fedschedulingv1a1.ClusterPreferences{
+				"A": {Weight: 3},
+				"B": {Weight: 1},
+			}),
+			expectedCompletions: map[string]int64{"A": 6, "B": 2},
+			expectedParallelism: map[string]int64{"A": 6, "B": 2},
+		},
+		"Parallelism is limited to the completions of each cluster": {
+			jsp: newJSP(4, int32Ptr(6), map[string]fedschedulingv1a1.ClusterPreferences{
+				"A": {Weight: 1, MaxReplicas: int64Ptr(1)},
+				"B": {Weight: 1},
+			}),
+			expectedCompletions: map[string]int64{"A": 1, "B": 3},
+			expectedParallelism: map[string]int64{"A": 1, "B": 3},
+		},
+		"Each cluster runs at least one pod": {
+			jsp:                 newJSP(9, int32Ptr(1), nil),
+			expectedCompletions: map[string]int64{"A": 3, "B": 3, "C": 3},
+			expectedParallelism: map[string]int64{"A": 1, "B": 1, "C": 1},
+		},
+		"Completions of existing jobs are retained": {
+			jsp: newJSP(9, nil, nil),
+			jobs: map[string]*batchv1.Job{
+				"A": newJob(5, 0, ""),
+			},
+			expectedCompletions: map[string]int64{"A": 5, "B": 2, "C": 2},
+			expectedParallelism: map[string]int64{"A": 5, "B": 2, "C": 2},
+		},
+		"Completed jobs run no pods": {
+			jsp: newJSP(9, nil, nil),
+			jobs: map[string]*batchv1.Job{
+				"A": newJob(3, 3, batchv1.JobComplete),
+				"B": newJob(3, 1, ""),
+				"C": newJob(3, 0, ""),
+			},
+			expectedCompletions: map[string]int64{"A": 3, "B": 3, "C": 3},
+			expectedParallelism: map[string]int64{"A": 0, "B": 2, "C": 3},
+		},
+	}
+
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			preference := jobPlanningPreference(tc.jsp)
+			completions, parallelism, err := scheduleJob(preference, tc.jsp, "ns/job", []string{"A", "B", "C"}, tc.jobs)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, tc.expectedCompletions, completions)
+			assert.Equal(t, tc.expectedParallelism, parallelism)
+		})
+	}
+}
+
+func TestJobStatus(t *testing.T) {
+	jsp := newJSP(6, nil, nil)
+	completions := map[string]int64{"A": 3, "B": 3}
+	parallelism := map[string]int64{"A": 0, "B": 2}
+
+	jobB := newJob(3, 1, "")
+	jobB.Status.Active = 2
+	jobB.Status.Failed = 1
+	jobs := map[string]*batchv1.Job{
+		"A": newJob(3, 3, batchv1.JobComplete),
+		"B": jobB,
+	}
+
+	status := jobStatus(jsp, completions, parallelism, jobs)
+	assert.Equal(t, fedschedulingv1a1.JobSchedulingPreferenceStatus{
+		Active:    2,
+		Succeeded: 4,
+		Failed:    1,
+		Clusters: []fedschedulingv1a1.JobClusterStatus{
+			{Name: "A", Completions: 3, Succeeded: 3, Condition: batchv1.JobComplete},
+			{Name: "B", Completions: 3, Parallelism: 2, Active: 2, Succeeded: 1, Failed: 1},
+		},
+	}, status)
+
+	jobB.Status.Succeeded = 3
+	status = jobStatus(jsp, completions, parallelism, jobs)
+	assert.True(t, status.Complete)
+}
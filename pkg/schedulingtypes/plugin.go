@@ -132,7 +132,60 @@ func (p *Plugin) FederatedTypeExists(key string) bool {
 	return exist
 }
 
+// FederatedAnnotations returns the annotations of the federated
+// resource with the given key, or nil if the resource does not exist.
+func (p *Plugin) FederatedAnnotations(key string) map[string]string {
+	obj, exist, err := p.federatedStore.GetByKey(key)
+	if err != nil || !exist {
+		return nil
+	}
+	return obj.(*unstructured.Unstructured).GetAnnotations()
+}
+
+// ScheduledReplicas returns the replicas of each cluster in the
+// overrides of the federated resource with the given key, or nil if
+// the resource does not exist or has no replica overrides.
+func (p *Plugin) ScheduledReplicas(key string) map[string]int64 {
+	obj, exist, err := p.federatedStore.GetByKey(key)
+	if err != nil || !exist {
+		return nil
+	}
+	overridesMap, err := util.GetOverrides(obj.(*unstructured.Unstructured))
+	if err != nil {
+		return nil
+	}
+	var result map[string]int64
+	for clusterName, clusterOverridesMap := range overridesMap {
+		rawValue, ok := clusterOverridesMap[replicasPath]
+		if !ok {
+			continue
+		}
+		if result == nil {
+			result = make(map[string]int64)
+		}
+		switch value := rawValue.(type) {
+		case float64:
+			result[clusterName] = int64(value)
+		case int64:
+			result[clusterName] = value
+		}
+	}
+	return result
+}
+
 func (p *Plugin) Reconcile(qualifiedName util.QualifiedName, result map[string]int64) error {
+	clusterNames := []string{}
+	for name := range result {
+		clusterNames = append(clusterNames, name)
+	}
+	return p.ReconcileOverrides(qualifiedName, clusterNames, map[string]map[string]interface{}{replicasPath: overrideValues(result)})
+}
+
+// ReconcileOverrides ensures that the federated resource is placed in
+// the given clusters and that, for each of the given paths, the
+// override of the path in each cluster has the value of the cluster
+// in the values of the path.
+func (p *Plugin) ReconcileOverrides(qualifiedName util.QualifiedName, newClusterNames []string, pathValues map[string]map[string]interface{}) error {
 	fedObject, err := p.federatedTypeClient.Resources(qualifiedName.Namespace).Get(qualifiedName.Name, metav1.GetOptions{})
 	if err != nil && apierrors.IsNotFound(err) {
 		// Federated resource has been deleted - no further action required
@@ -144,10 +197,6 @@ func (p *Plugin) Reconcile(qualifiedName util.QualifiedName, result map[string]i
 
 	isDirty := false
 
-	newClusterNames := []string{}
-	for name := range result {
-		newClusterNames = append(newClusterNames, name)
-	}
 	clusterNames, err := util.GetClusterNames(fedObject)
 	if err != nil {
 		return err
@@ -164,9 +213,18 @@ func (p *Plugin) Reconcile(qualifiedName util.QualifiedName, result map[string]i
 	if err != nil {
 		return errors.Wrapf(err, "Error reading cluster overrides for %s %q", p.typeConfig.GetFederatedType().Kind, qualifiedName)
 	}
-	if OverrideUpdateNeeded(overridesMap, result) {
-		err := setOverrides(fedObject, overridesMap, result)
-		if err != nil {
+	if overridesMap == nil {
+		overridesMap = make(util.OverridesMap)
+	}
+	overridesDirty := false
+	for path, values := range pathValues {
+		if overrideUpdateNeeded(overridesMap, path, values) {
+			updateOverridesMap(overridesMap, path, values)
+			overridesDirty = true
+		}
+	}
+	if overridesDirty {
+		if err := util.SetOverrides(fedObject, overridesMap); err != nil {
 			return err
 		}
 		isDirty = true
@@ -182,6 +240,16 @@ func (p *Plugin) Reconcile(qualifiedName util.QualifiedName, result map[string]i
 	return nil
 }
 
+// ClusterObject returns the target resource with the given key in the
+// given cluster, or nil if it does not exist.
+func (p *Plugin) ClusterObject(clusterName, key string) (*unstructured.Unstructured, error) {
+	obj, exists, err := p.targetInformer.GetTargetStore().GetByKey(clusterName, key)
+	if err != nil || !exists {
+		return nil, err
+	}
+	return obj.(*unstructured.Unstructured), nil
+}
+
 // These assume that there would be no duplicate clusternames
 func PlacementUpdateNeeded(names, newNames []string) bool {
 	sort.Strings(names)
@@ -189,49 +257,48 @@ func PlacementUpdateNeeded(names, newNames []string) bool {
 	return !reflect.DeepEqual(names, newNames)
 }
 
-func setOverrides(obj *unstructured.Unstructured, overridesMap util.OverridesMap, replicasMap map[string]int64) error {
-	if overridesMap == nil {
-		overridesMap = make(util.OverridesMap)
+// overrideValues returns the given values of clusters as override
+// values.
+func overrideValues(values map[string]int64) map[string]interface{} {
+	result := make(map[string]interface{}, len(values))
+	for clusterName, value := range values {
+		result[clusterName] = value
 	}
-	updateOverridesMap(overridesMap, replicasMap)
-	return util.SetOverrides(obj, overridesMap)
+	return result
 }
 
-func updateOverridesMap(overridesMap util.OverridesMap, replicasMap map[string]int64) {
-	// Remove replicas override for clusters that are not scheduled
+func updateOverridesMap(overridesMap util.OverridesMap, path string, valuesMap map[string]interface{}) {
+	// Remove the override for clusters that are not scheduled
 	for clusterName, clusterOverridesMap := range overridesMap {
-		if _, ok := replicasMap[clusterName]; !ok {
-			delete(clusterOverridesMap, replicasPath)
+		if _, ok := valuesMap[clusterName]; !ok {
+			delete(clusterOverridesMap, path)
 		}
 	}
-	// Add/update replicas override for clusters that are scheduled
-	for clusterName, replicas := range replicasMap {
+	// Add/update the override for clusters that are scheduled
+	for clusterName, value := range valuesMap {
 		clusterOverridesMap, ok := overridesMap[clusterName]
 		if !ok {
 			clusterOverridesMap = make(util.ClusterOverridesMap)
 			overridesMap[clusterName] = clusterOverridesMap
 		}
-		clusterOverridesMap[replicasPath] = replicas
+		clusterOverridesMap[path] = value
 	}
 }
 
 func OverrideUpdateNeeded(overridesMap util.OverridesMap, result map[string]int64) bool {
+	return overrideUpdateNeeded(overridesMap, replicasPath, overrideValues(result))
+}
+
+func overrideUpdateNeeded(overridesMap util.OverridesMap, overridePath string, result map[string]interface{}) bool {
 	resultLen := len(result)
 	checkLen := 0
 	for clusterName, clusterOverridesMap := range overridesMap {
 		for path, rawValue := range clusterOverridesMap {
-			if path != replicasPath {
+			if path != overridePath {
 				continue
 			}
-			// The type of the value will be float64 due to how json
-			// marshalling works for interfaces.
-			floatValue, ok := rawValue.(float64)
-			if !ok {
-				return true
-			}
-			value := int64(floatValue)
-			replicas, ok := result[clusterName]
-			if !ok || value != int64(replicas) {
+			value, ok := result[clusterName]
+			if !ok || !overrideValueEqual(rawValue, value) {
 				return true
 			}
 			checkLen += 1
@@ -240,3 +307,16 @@ func OverrideUpdateNeeded(overridesMap util.OverridesMap, result map[string]int6
 
 	return checkLen != resultLen
 }
+
+// overrideValueEqual returns whether the raw value of an override is
+// equal to the given value.
+func overrideValueEqual(rawValue, value interface{}) bool {
+	intValue, ok := value.(int64)
+	if !ok {
+		return reflect.DeepEqual(rawValue, value)
+	}
+	// The type of the value will be float64 due to how json
+	// marshalling works for interfaces.
+	floatValue, ok := rawValue.(float64)
+	return ok && int64(floatValue) == intValue
+}
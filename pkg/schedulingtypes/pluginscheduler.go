@@ -0,0 +1,98 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedulingtypes
+
+import (
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/kubefed/pkg/apis/core/typeconfig"
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	ctlutil "sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+// pluginScheduler manages the plugins of a scheduler that only relies
+// on the target informers of its plugins.
+type pluginScheduler struct {
+	controllerConfig *ctlutil.ControllerConfig
+
+	eventHandlers SchedulerEventHandlers
+
+	plugins *ctlutil.SafeMap
+
+	client genericclient.Client
+}
+
+func newPluginScheduler(controllerConfig *ctlutil.ControllerConfig, eventHandlers SchedulerEventHandlers, userAgent string) pluginScheduler {
+	return pluginScheduler{
+		controllerConfig: controllerConfig,
+		eventHandlers:    eventHandlers,
+		plugins:          ctlutil.NewSafeMap(),
+		client:           genericclient.NewForConfigOrDieWithUserAgent(controllerConfig.KubeConfig, userAgent),
+	}
+}
+
+func (s *pluginScheduler) StartPlugin(typeConfig typeconfig.Interface) error {
+	kind := typeConfig.GetFederatedType().Kind
+
+	plugin, err := NewPlugin(s.controllerConfig, s.eventHandlers, typeConfig)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to initialize scheduling plugin for %q", kind)
+	}
+
+	plugin.Start()
+	s.plugins.Store(kind, plugin)
+
+	return nil
+}
+
+func (s *pluginScheduler) StopPlugin(kind string) {
+	plugin, ok := s.plugins.Get(kind)
+	if !ok {
+		return
+	}
+
+	plugin.(*Plugin).Stop()
+	s.plugins.Delete(kind)
+}
+
+func (s *pluginScheduler) Start() {
+}
+
+func (s *pluginScheduler) HasSynced() bool {
+	for _, plugin := range s.plugins.GetAll() {
+		if !plugin.(*Plugin).HasSynced() {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *pluginScheduler) Stop() {
+	for _, plugin := range s.plugins.GetAll() {
+		plugin.(*Plugin).Stop()
+	}
+	s.plugins.DeleteAll()
+}
+
+// plugin returns the plugin for the given federated kind.
+func (s *pluginScheduler) plugin(kind string) (*Plugin, bool) {
+	plugin, ok := s.plugins.Get(kind)
+	if !ok {
+		return nil, false
+	}
+	return plugin.(*Plugin), true
+}
@@ -0,0 +1,414 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedulingtypes
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog"
+
+	fedcommon "sigs.k8s.io/kubefed/pkg/apis/core/common"
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	fedschedulingv1a1 "sigs.k8s.io/kubefed/pkg/apis/scheduling/v1alpha1"
+)
+
+const (
+	// SchedulingProfileAnnotation selects the scheduling profile of a
+	// ReplicaSchedulingPreference.  It can be set on the RSP or on
+	// the federated resource it targets, with the RSP taking
+	// precedence.
+	SchedulingProfileAnnotation = "scheduling.kubefed.k8s.io/profile"
+
+	maxClusterScore = 100
+
+	// scaleUpFailureWindow is how long a failed scale-up reported by
+	// the cluster autoscaler of a cluster is considered to explain
+	// the unschedulable pods of the cluster.
+	scaleUpFailureWindow = 10 * time.Minute
+)
+
+// newSchedulingProfiles returns the profiles of the given
+// configuration by name.  The profiles are validated with the
+// KubeFedConfig.
+func newSchedulingProfiles(config *fedv1b1.SchedulingConfig) map[string]*fedv1b1.SchedulingProfile {
+	profiles := make(map[string]*fedv1b1.SchedulingProfile)
+	if config == nil {
+		return profiles
+	}
+	for i := range config.Profiles {
+		profile := &config.Profiles[i]
+		profiles[profile.Name] = profile
+	}
+	return profiles
+}
+
+// profileFor returns the scheduling profile selected by the given RSP
+// or, if the RSP does not select one, by the annotations of the
+// federated resource it targets.  The default profile is returned if
+// neither selects a profile.
+func (s *ReplicaScheduler) profileFor(rsp *fedschedulingv1a1.ReplicaSchedulingPreference, federatedAnnotations map[string]string) (*fedv1b1.SchedulingProfile, error) {
+	name, ok := rsp.Annotations[SchedulingProfileAnnotation]
+	if !ok {
+		name, ok = federatedAnnotations[SchedulingProfileAnnotation]
+	}
+	if !ok {
+		name = s.defaultProfile
+	}
+	if len(name) == 0 {
+		return &fedv1b1.SchedulingProfile{}, nil
+	}
+	profile, ok := s.profiles[name]
+	if !ok {
+		return nil, errors.Errorf("scheduling profile %q is not defined in the KubeFedConfig", name)
+	}
+	return profile, nil
+}
+
+// profileFilters returns the filters of the given profile, or the
+// default filters if the profile does not configure any.  No filters
+// are returned for a profile with the None filter.
+func profileFilters(profile *fedv1b1.SchedulingProfile) []fedv1b1.SchedulingFilter {
+	for _, filter := range profile.Filters {
+		if filter == fedv1b1.NoneSchedulingFilter {
+			return nil
+		}
+	}
+	if len(profile.Filters) > 0 {
+		return profile.Filters
+	}
+	return []fedv1b1.SchedulingFilter{fedv1b1.DegradedSchedulingFilter, fedv1b1.TaintsSchedulingFilter}
+}
+
+// schedulableClusters returns the given ready clusters that pass the
+// given filters: clusters that are not degraded for the Degraded
+// filter, and clusters that have no NoSchedule or NoExecute taint that
+// is not tolerated by the RSP for the Taints filter.  Offline clusters
+// are never ready and so are never scheduled to.
+func schedulableClusters(rsp *fedschedulingv1a1.ReplicaSchedulingPreference, clusters []*fedv1b1.KubeFedCluster, filters []fedv1b1.SchedulingFilter) []*fedv1b1.KubeFedCluster {
+	filterDegraded, filterTaints := false, false
+	for _, filter := range filters {
+		switch filter {
+		case fedv1b1.DegradedSchedulingFilter:
+			filterDegraded = true
+		case fedv1b1.TaintsSchedulingFilter:
+			filterTaints = true
+		}
+	}
+
+	result := []*fedv1b1.KubeFedCluster{}
+	for _, cluster := range clusters {
+		if filterDegraded && isClusterDegraded(cluster) {
+			klog.V(4).Infof("Not scheduling replicas of RSP %s/%s to degraded cluster %q", rsp.Namespace, rsp.Name, cluster.Name)
+			continue
+		}
+		if !filterTaints {
+			result = append(result, cluster)
+			continue
+		}
+		if taint, ok := untoleratedTaint(rsp.Spec.Tolerations, cluster.Spec.Taints); ok {
+			klog.V(4).Infof("Not scheduling replicas of RSP %s/%s to cluster %q with untolerated taint %s", rsp.Namespace, rsp.Name, cluster.Name, taint.ToString())
+			continue
+		}
+		result = append(result, cluster)
+	}
+	return result
+}
+
+// allowedClusters returns the given clusters that are in the given
+// set of allowed clusters, or all of them if the set is nil.
+func allowedClusters(rsp *fedschedulingv1a1.ReplicaSchedulingPreference, clusters []*fedv1b1.KubeFedCluster, allowed sets.String) []*fedv1b1.KubeFedCluster {
+	if allowed == nil {
+		return clusters
+	}
+	result := []*fedv1b1.KubeFedCluster{}
+	for _, cluster := range clusters {
+		if !allowed.Has(cluster.Name) {
+			klog.V(4).Infof("Not scheduling replicas of RSP %s/%s to cluster %q that is not allowed by placement policies", rsp.Namespace, rsp.Name, cluster.Name)
+			continue
+		}
+		result = append(result, cluster)
+	}
+	return result
+}
+
+// clustersUnderPressure returns the names of the given clusters that
+// are under pressure if the Pressure filter is one of the given
+// filters, and of those that have no headroom to grow if the Headroom
+// filter is.  These clusters are not removed from scheduling but
+// their replicas are limited to those already running in them.
+func clustersUnderPressure(rsp *fedschedulingv1a1.ReplicaSchedulingPreference, clusters []*fedv1b1.KubeFedCluster,
+	filters []fedv1b1.SchedulingFilter, now time.Time) sets.String {
+
+	result := sets.NewString()
+	filterPressure, filterHeadroom := false, false
+	for _, filter := range filters {
+		switch filter {
+		case fedv1b1.PressureSchedulingFilter:
+			filterPressure = true
+		case fedv1b1.HeadroomSchedulingFilter:
+			filterHeadroom = true
+		}
+	}
+	for _, cluster := range clusters {
+		if filterPressure && isClusterUnderPressure(cluster, now) {
+			klog.V(4).Infof("Limiting replicas of RSP %s/%s to those running in cluster %q under pressure", rsp.Namespace, rsp.Name, cluster.Name)
+			result.Insert(cluster.Name)
+		} else if filterHeadroom && isClusterWithoutHeadroom(cluster) {
+			klog.V(4).Infof("Limiting replicas of RSP %s/%s to those running in cluster %q that cannot grow", rsp.Namespace, rsp.Name, cluster.Name)
+			result.Insert(cluster.Name)
+		}
+	}
+	return result
+}
+
+// isClusterUnderPressure indicates whether the cluster has
+// unschedulable pods that are not expected to be scheduled, either
+// because some of its nodes are under pressure or because its cluster
+// autoscaler recently failed to scale up the cluster.
+func isClusterUnderPressure(cluster *fedv1b1.KubeFedCluster, now time.Time) bool {
+	pressure := cluster.Status.Pressure
+	if pressure == nil || pressure.UnschedulablePods == 0 {
+		return false
+	}
+	if pressure.NodesUnderPressure > 0 {
+		return true
+	}
+	return pressure.LastScaleUpFailureTime != nil && now.Sub(pressure.LastScaleUpFailureTime.Time) < scaleUpFailureWindow
+}
+
+// isClusterWithoutHeadroom indicates whether the cluster has
+// unschedulable pods and a cluster autoscaler that cannot add nodes
+// to it, so that additional replicas would never be scheduled.
+func isClusterWithoutHeadroom(cluster *fedv1b1.KubeFedCluster) bool {
+	pressure := cluster.Status.Pressure
+	if pressure == nil || pressure.UnschedulablePods == 0 || pressure.Autoscaler == nil {
+		return false
+	}
+	return pressure.Autoscaler.ScaleUpDisabled || pressure.Autoscaler.MaxNodesReached
+}
+
+func isClusterDegraded(cluster *fedv1b1.KubeFedCluster) bool {
+	return hasTrueCondition(cluster, fedcommon.ClusterDegraded)
+}
+
+func hasTrueCondition(cluster *fedv1b1.KubeFedCluster, conditionType fedcommon.ClusterConditionType) bool {
+	for _, condition := range cluster.Status.Conditions {
+		if condition.Type == conditionType && condition.Status == apiv1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// untoleratedTaint returns the first taint with the NoSchedule or
+// NoExecute effect that is not tolerated by any of the tolerations.
+func untoleratedTaint(tolerations []apiv1.Toleration, taints []apiv1.Taint) (*apiv1.Taint, bool) {
+	for i := range taints {
+		taint := &taints[i]
+		if taint.Effect != apiv1.TaintEffectNoSchedule && taint.Effect != apiv1.TaintEffectNoExecute {
+			continue
+		}
+		tolerated := false
+		for j := range tolerations {
+			if tolerations[j].ToleratesTaint(taint) {
+				tolerated = true
+				break
+			}
+		}
+		if !tolerated {
+			return taint, true
+		}
+	}
+	return nil, false
+}
+
+// scoreClusters returns the combined score, from 0 to 100, of each of
+// the given clusters.  The score is the weighted average of the
+// scores of the scorers.  Nil is returned if there are no scorers.
+func scoreClusters(scorers []fedv1b1.SchedulingScorer, clusters []*fedv1b1.KubeFedCluster) map[string]int64 {
+	if len(scorers) == 0 {
+		return nil
+	}
+	totals := make(map[string]int64, len(clusters))
+	totalWeight := int64(0)
+	for _, scorer := range scorers {
+		weight := scorer.Weight
+		if weight == 0 {
+			weight = 1
+		}
+		totalWeight += weight
+
+		var scores map[string]int64
+		switch scorer.Type {
+		case fedv1b1.APILatencySchedulingScorer:
+			scores = apiLatencyScores(clusters)
+		case fedv1b1.HeadroomSchedulingScorer:
+			scores = headroomScores(clusters)
+		case fedv1b1.LabelSchedulingScorer:
+			scores = labelScores(scorer.Label, clusters)
+		}
+		for name, score := range scores {
+			totals[name] += weight * score
+		}
+	}
+	result := make(map[string]int64, len(clusters))
+	for _, cluster := range clusters {
+		result[cluster.Name] = totals[cluster.Name] / totalWeight
+	}
+	return result
+}
+
+// apiLatencyScores scores clusters by how close the average latency
+// of their API server is to the lowest average latency.  Clusters
+// whose latency has not been observed yet score as low as the
+// cluster with the highest latency.
+func apiLatencyScores(clusters []*fedv1b1.KubeFedCluster) map[string]int64 {
+	minLatency, maxLatency := int64(0), int64(0)
+	for _, cluster := range clusters {
+		latency, ok := averageAPILatency(cluster)
+		if !ok {
+			continue
+		}
+		if minLatency == 0 || latency < minLatency {
+			minLatency = latency
+		}
+		if latency > maxLatency {
+			maxLatency = latency
+		}
+	}
+
+	scores := make(map[string]int64, len(clusters))
+	for _, cluster := range clusters {
+		if minLatency == 0 {
+			scores[cluster.Name] = maxClusterScore
+			continue
+		}
+		latency, ok := averageAPILatency(cluster)
+		if !ok {
+			latency = maxLatency
+		}
+		score := maxClusterScore * minLatency / latency
+		if score < 1 {
+			score = 1
+		}
+		scores[cluster.Name] = score
+	}
+	return scores
+}
+
+func averageAPILatency(cluster *fedv1b1.KubeFedCluster) (int64, bool) {
+	health := cluster.Status.APIHealth
+	if health == nil {
+		return 0, false
+	}
+	if health.AverageLatencyMilliseconds < 1 {
+		return 1, true
+	}
+	return health.AverageLatencyMilliseconds, true
+}
+
+// headroomScores scores clusters by the share of the node groups of
+// their cluster autoscaler that have not reached their maximum size.
+// Clusters whose autoscaler cannot add nodes score 0, and clusters
+// whose autoscaler status is unknown score as if they could grow.
+func headroomScores(clusters []*fedv1b1.KubeFedCluster) map[string]int64 {
+	scores := make(map[string]int64, len(clusters))
+	for _, cluster := range clusters {
+		pressure := cluster.Status.Pressure
+		if pressure == nil || pressure.Autoscaler == nil {
+			scores[cluster.Name] = maxClusterScore
+			continue
+		}
+		autoscaler := pressure.Autoscaler
+		if autoscaler.ScaleUpDisabled || autoscaler.NodeGroups == 0 {
+			continue
+		}
+		scores[cluster.Name] = maxClusterScore * int64(autoscaler.NodeGroups-autoscaler.NodeGroupsAtMaxSize) / int64(autoscaler.NodeGroups)
+	}
+	return scores
+}
+
+// labelScores scores clusters by the value of the given label.
+// Values are limited to the range of scores, and clusters without a
+// valid value score 0.
+func labelScores(label string, clusters []*fedv1b1.KubeFedCluster) map[string]int64 {
+	scores := make(map[string]int64, len(clusters))
+	for _, cluster := range clusters {
+		value, ok := cluster.Labels[label]
+		if !ok {
+			continue
+		}
+		score, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			klog.Warningf("Ignoring invalid score %q in label %q of cluster %q", value, label, cluster.Name)
+			continue
+		}
+		if score < 0 {
+			score = 0
+		} else if score > maxClusterScore {
+			score = maxClusterScore
+		}
+		scores[cluster.Name] = score
+	}
+	return scores
+}
+
+// applyClusterScores returns a copy of the given RSP in which the
+// weights of the given clusters are scaled by their scores.  The RSP
+// is returned unchanged if there are no scores or if every cluster
+// scores 0.
+func applyClusterScores(rsp *fedschedulingv1a1.ReplicaSchedulingPreference, clusters []*fedv1b1.KubeFedCluster, scores map[string]int64) *fedschedulingv1a1.ReplicaSchedulingPreference {
+	scored := false
+	for _, score := range scores {
+		if score > 0 {
+			scored = true
+			break
+		}
+	}
+	if !scored {
+		return rsp
+	}
+
+	preferences := rsp.Spec.Clusters
+	if len(preferences) == 0 {
+		preferences = map[string]fedschedulingv1a1.ClusterPreferences{
+			"*": {Weight: 1},
+		}
+	}
+	result := rsp.DeepCopy()
+	result.Spec.Clusters = make(map[string]fedschedulingv1a1.ClusterPreferences, len(preferences))
+	for name, preference := range preferences {
+		result.Spec.Clusters[name] = preference
+	}
+	for _, cluster := range clusters {
+		preference, ok := preferences[cluster.Name]
+		if !ok {
+			preference, ok = preferences["*"]
+		}
+		if !ok {
+			continue
+		}
+		preference.Weight *= scores[cluster.Name]
+		result.Spec.Clusters[cluster.Name] = preference
+	}
+	return result
+}
@@ -0,0 +1,322 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedulingtypes
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	fedcommon "sigs.k8s.io/kubefed/pkg/apis/core/common"
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	fedschedulingv1a1 "sigs.k8s.io/kubefed/pkg/apis/scheduling/v1alpha1"
+)
+
+func TestSchedulableClusters(t *testing.T) {
+	degraded := newCluster("degraded", "")
+	degraded.Status.Conditions = []fedv1b1.ClusterCondition{
+		{Type: fedcommon.ClusterDegraded, Status: apiv1.ConditionTrue},
+	}
+	noSchedule := newCluster("noschedule", "")
+	noSchedule.Spec.Taints = []apiv1.Taint{
+		{Key: "maintenance", Value: "true", Effect: apiv1.TaintEffectNoSchedule},
+	}
+	noExecute := newCluster("noexecute", "")
+	noExecute.Spec.Taints = []apiv1.Taint{
+		{Key: "evacuate", Effect: apiv1.TaintEffectNoExecute},
+	}
+	preferNoSchedule := newCluster("prefernoschedule", "")
+	preferNoSchedule.Spec.Taints = []apiv1.Taint{
+		{Key: "expensive", Effect: apiv1.TaintEffectPreferNoSchedule},
+	}
+	clusters := []*fedv1b1.KubeFedCluster{
+		newCluster("healthy", ""),
+		degraded,
+		noSchedule,
+		noExecute,
+		preferNoSchedule,
+	}
+
+	testCases := map[string]struct {
+		tolerations []apiv1.Toleration
+		expected    []string
+	}{
+		"Degraded clusters and untolerated taints are excluded": {
+			expected: []string{"healthy", "prefernoschedule"},
+		},
+		"Taint with matching value is tolerated": {
+			tolerations: []apiv1.Toleration{
+				{Key: "maintenance", Operator: apiv1.TolerationOpEqual, Value: "true", Effect: apiv1.TaintEffectNoSchedule},
+			},
+			expected: []string{"healthy", "noschedule", "prefernoschedule"},
+		},
+		"Taint with other value is not tolerated": {
+			tolerations: []apiv1.Toleration{
+				{Key: "maintenance", Operator: apiv1.TolerationOpEqual, Value: "false"},
+			},
+			expected: []string{"healthy", "prefernoschedule"},
+		},
+		"Toleration without key tolerates all taints": {
+			tolerations: []apiv1.Toleration{
+				{Operator: apiv1.TolerationOpExists},
+			},
+			expected: []string{"healthy", "noschedule", "noexecute", "prefernoschedule"},
+		},
+	}
+
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			rsp := newRSP(nil)
+			rsp.Spec.Tolerations = tc.tolerations
+			names := []string{}
+			for _, cluster := range schedulableClusters(rsp, clusters, profileFilters(&fedv1b1.SchedulingProfile{})) {
+				names = append(names, cluster.Name)
+			}
+			assert.Equal(t, tc.expected, names)
+		})
+	}
+}
+
+func TestSchedulableClustersWithProfileFilters(t *testing.T) {
+	tainted := newCluster("tainted", "")
+	tainted.Spec.Taints = []apiv1.Taint{
+		{Key: "maintenance", Effect: apiv1.TaintEffectNoSchedule},
+	}
+	degraded := newCluster("degraded", "")
+	degraded.Status.Conditions = []fedv1b1.ClusterCondition{
+		{Type: fedcommon.ClusterDegraded, Status: apiv1.ConditionTrue},
+	}
+	clusters := []*fedv1b1.KubeFedCluster{tainted, degraded}
+
+	profile := &fedv1b1.SchedulingProfile{
+		Filters: []fedv1b1.SchedulingFilter{fedv1b1.TaintsSchedulingFilter},
+	}
+	result := schedulableClusters(newRSP(nil), clusters, profileFilters(profile))
+	if assert.Len(t, result, 1) {
+		assert.Equal(t, "degraded", result[0].Name)
+	}
+
+	profile.Filters = []fedv1b1.SchedulingFilter{fedv1b1.DegradedSchedulingFilter}
+	result = schedulableClusters(newRSP(nil), clusters, profileFilters(profile))
+	if assert.Len(t, result, 1) {
+		assert.Equal(t, "tainted", result[0].Name)
+	}
+
+	// Profiles without filters use the default filters.
+	result = schedulableClusters(newRSP(nil), clusters, profileFilters(&fedv1b1.SchedulingProfile{}))
+	assert.Empty(t, result)
+
+	// The None filter disables filtering.
+	profile.Filters = []fedv1b1.SchedulingFilter{fedv1b1.NoneSchedulingFilter}
+	assert.Empty(t, profileFilters(profile))
+	result = schedulableClusters(newRSP(nil), clusters, profileFilters(profile))
+	assert.Len(t, result, 2)
+}
+
+func TestAllowedClusters(t *testing.T) {
+	clusters := []*fedv1b1.KubeFedCluster{newCluster("A", ""), newCluster("B", "")}
+
+	assert.Equal(t, clusters, allowedClusters(newRSP(nil), clusters, nil))
+
+	result := allowedClusters(newRSP(nil), clusters, sets.NewString("B", "C"))
+	if assert.Len(t, result, 1) {
+		assert.Equal(t, "B", result[0].Name)
+	}
+}
+
+func TestClustersUnderPressure(t *testing.T) {
+	now := time.Now()
+	newClusterWithPressure := func(name string, pressure *fedv1b1.ClusterPressure) *fedv1b1.KubeFedCluster {
+		cluster := newCluster(name, "")
+		cluster.Status.Pressure = pressure
+		return cluster
+	}
+	recentFailure := metav1.NewTime(now.Add(-time.Minute))
+	staleFailure := metav1.NewTime(now.Add(-time.Hour))
+	clusters := []*fedv1b1.KubeFedCluster{
+		newClusterWithPressure("unknown", nil),
+		newClusterWithPressure("idle", &fedv1b1.ClusterPressure{NodesUnderPressure: 1, LastScaleUpFailureTime: &recentFailure}),
+		newClusterWithPressure("nodes-under-pressure", &fedv1b1.ClusterPressure{UnschedulablePods: 3, NodesUnderPressure: 1}),
+		newClusterWithPressure("recent-scale-up-failure", &fedv1b1.ClusterPressure{UnschedulablePods: 3, LastScaleUpFailureTime: &recentFailure}),
+		newClusterWithPressure("stale-scale-up-failure", &fedv1b1.ClusterPressure{UnschedulablePods: 3, LastScaleUpFailureTime: &staleFailure}),
+		newClusterWithPressure("scaling-up", &fedv1b1.ClusterPressure{UnschedulablePods: 3}),
+	}
+
+	result := clustersUnderPressure(newRSP(nil), clusters, []fedv1b1.SchedulingFilter{fedv1b1.PressureSchedulingFilter}, now)
+	assert.Equal(t, []string{"nodes-under-pressure", "recent-scale-up-failure"}, result.List())
+
+	result = clustersUnderPressure(newRSP(nil), clusters, []fedv1b1.SchedulingFilter{fedv1b1.DegradedSchedulingFilter}, now)
+	assert.Empty(t, result.List())
+}
+
+func TestClustersWithoutHeadroom(t *testing.T) {
+	newClusterWithAutoscaler := func(name string, unschedulablePods int32, autoscaler *fedv1b1.ClusterAutoscalerStatus) *fedv1b1.KubeFedCluster {
+		cluster := newCluster(name, "")
+		cluster.Status.Pressure = &fedv1b1.ClusterPressure{UnschedulablePods: unschedulablePods, Autoscaler: autoscaler}
+		return cluster
+	}
+	clusters := []*fedv1b1.KubeFedCluster{
+		newClusterWithAutoscaler("unknown", 3, nil),
+		newClusterWithAutoscaler("idle", 0, &fedv1b1.ClusterAutoscalerStatus{ScaleUpDisabled: true}),
+		newClusterWithAutoscaler("scale-up-disabled", 3, &fedv1b1.ClusterAutoscalerStatus{ScaleUpDisabled: true}),
+		newClusterWithAutoscaler("max-nodes-reached", 3, &fedv1b1.ClusterAutoscalerStatus{MaxNodesReached: true, NodeGroups: 2, NodeGroupsAtMaxSize: 2}),
+		newClusterWithAutoscaler("scaling-up", 3, &fedv1b1.ClusterAutoscalerStatus{NodeGroups: 2, NodeGroupsAtMaxSize: 1}),
+	}
+
+	result := clustersUnderPressure(newRSP(nil), clusters, []fedv1b1.SchedulingFilter{fedv1b1.HeadroomSchedulingFilter}, time.Now())
+	assert.Equal(t, []string{"max-nodes-reached", "scale-up-disabled"}, result.List())
+
+	result = clustersUnderPressure(newRSP(nil), clusters, []fedv1b1.SchedulingFilter{fedv1b1.PressureSchedulingFilter}, time.Now())
+	assert.Empty(t, result.List())
+}
+
+func TestProfileFor(t *testing.T) {
+	profiles := newSchedulingProfiles(&fedv1b1.SchedulingConfig{
+		Profiles: []fedv1b1.SchedulingProfile{{Name: "batch"}, {Name: "latency-first"}},
+	})
+	s := &ReplicaScheduler{profiles: profiles}
+
+	rsp := newRSP(nil)
+	profile, err := s.profileFor(rsp, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "", profile.Name)
+
+	federatedAnnotations := map[string]string{SchedulingProfileAnnotation: "batch"}
+	profile, err = s.profileFor(rsp, federatedAnnotations)
+	assert.NoError(t, err)
+	assert.Equal(t, "batch", profile.Name)
+
+	rsp.Annotations = map[string]string{SchedulingProfileAnnotation: "latency-first"}
+	profile, err = s.profileFor(rsp, federatedAnnotations)
+	assert.NoError(t, err)
+	assert.Equal(t, "latency-first", profile.Name)
+
+	rsp.Annotations[SchedulingProfileAnnotation] = "undefined"
+	_, err = s.profileFor(rsp, federatedAnnotations)
+	assert.Error(t, err)
+
+	s.defaultProfile = "batch"
+	profile, err = s.profileFor(newRSP(nil), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "batch", profile.Name)
+}
+
+func newClusterWithLatency(name string, latency int64) *fedv1b1.KubeFedCluster {
+	cluster := newCluster(name, "")
+	cluster.Status.APIHealth = &fedv1b1.ClusterAPIHealth{AverageLatencyMilliseconds: latency}
+	return cluster
+}
+
+func TestScoreClusters(t *testing.T) {
+	labeled := func(name, score string) *fedv1b1.KubeFedCluster {
+		cluster := newClusterWithLatency(name, 10)
+		cluster.Labels = map[string]string{"cost": score}
+		return cluster
+	}
+	withAutoscaler := func(name string, autoscaler *fedv1b1.ClusterAutoscalerStatus) *fedv1b1.KubeFedCluster {
+		cluster := newCluster(name, "")
+		cluster.Status.Pressure = &fedv1b1.ClusterPressure{Autoscaler: autoscaler}
+		return cluster
+	}
+
+	testCases := map[string]struct {
+		scorers  []fedv1b1.SchedulingScorer
+		clusters []*fedv1b1.KubeFedCluster
+		expected map[string]int64
+	}{
+		"No scorers": {
+			clusters: []*fedv1b1.KubeFedCluster{newCluster("A", "")},
+		},
+		"Lowest latency scores highest": {
+			scorers: []fedv1b1.SchedulingScorer{{Type: fedv1b1.APILatencySchedulingScorer}},
+			clusters: []*fedv1b1.KubeFedCluster{
+				newClusterWithLatency("A", 10),
+				newClusterWithLatency("B", 40),
+				newCluster("C", ""),
+			},
+			expected: map[string]int64{"A": 100, "B": 25, "C": 25},
+		},
+		"Unobserved latencies score equally": {
+			scorers:  []fedv1b1.SchedulingScorer{{Type: fedv1b1.APILatencySchedulingScorer}},
+			clusters: []*fedv1b1.KubeFedCluster{newCluster("A", ""), newCluster("B", "")},
+			expected: map[string]int64{"A": 100, "B": 100},
+		},
+		"Label values are limited to the range of scores": {
+			scorers: []fedv1b1.SchedulingScorer{{Type: fedv1b1.LabelSchedulingScorer, Label: "cost"}},
+			clusters: []*fedv1b1.KubeFedCluster{
+				labeled("A", "80"),
+				labeled("B", "200"),
+				labeled("C", "-5"),
+				labeled("D", "cheap"),
+			},
+			expected: map[string]int64{"A": 80, "B": 100, "C": 0, "D": 0},
+		},
+		"Clusters that cannot grow score lowest": {
+			scorers: []fedv1b1.SchedulingScorer{{Type: fedv1b1.HeadroomSchedulingScorer}},
+			clusters: []*fedv1b1.KubeFedCluster{
+				withAutoscaler("A", nil),
+				withAutoscaler("B", &fedv1b1.ClusterAutoscalerStatus{NodeGroups: 4, NodeGroupsAtMaxSize: 1}),
+				withAutoscaler("C", &fedv1b1.ClusterAutoscalerStatus{MaxNodesReached: true, NodeGroups: 2, NodeGroupsAtMaxSize: 2}),
+				withAutoscaler("D", &fedv1b1.ClusterAutoscalerStatus{ScaleUpDisabled: true}),
+			},
+			expected: map[string]int64{"A": 100, "B": 75, "C": 0, "D": 0},
+		},
+		"Scores are averaged by weight": {
+			scorers: []fedv1b1.SchedulingScorer{
+				{Type: fedv1b1.LabelSchedulingScorer, Label: "cost", Weight: 3},
+				{Type: fedv1b1.APILatencySchedulingScorer},
+			},
+			clusters: []*fedv1b1.KubeFedCluster{labeled("A", "20"), labeled("B", "60")},
+			expected: map[string]int64{"A": 40, "B": 70},
+		},
+	}
+
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			assert.Equal(t, tc.expected, scoreClusters(tc.scorers, tc.clusters))
+		})
+	}
+}
+
+func TestApplyClusterScores(t *testing.T) {
+	clusters := []*fedv1b1.KubeFedCluster{newCluster("A", ""), newCluster("B", "")}
+
+	rsp := newRSP(nil)
+	scored := applyClusterScores(rsp, clusters, map[string]int64{"A": 100, "B": 25})
+	assert.Equal(t, map[string]fedschedulingv1a1.ClusterPreferences{
+		"*": {Weight: 1},
+		"A": {Weight: 100},
+		"B": {Weight: 25},
+	}, scored.Spec.Clusters)
+	assert.Nil(t, rsp.Spec.Clusters, "the given RSP should not be modified")
+
+	rsp = newRSP(map[string]fedschedulingv1a1.ClusterPreferences{
+		"A": {Weight: 2, MinReplicas: 1},
+	})
+	scored = applyClusterScores(rsp, clusters, map[string]int64{"A": 50, "B": 100})
+	assert.Equal(t, map[string]fedschedulingv1a1.ClusterPreferences{
+		"A": {Weight: 100, MinReplicas: 1},
+	}, scored.Spec.Clusters)
+
+	// Weights are not scaled if every cluster scores 0.
+	assert.Equal(t, rsp, applyClusterScores(rsp, clusters, map[string]int64{"A": 0, "B": 0}))
+}
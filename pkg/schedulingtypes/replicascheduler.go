@@ -19,30 +19,63 @@ package schedulingtypes
 import (
 	"bytes"
 	"fmt"
+	"reflect"
 	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog"
 
 	"sigs.k8s.io/kubefed/pkg/apis/core/typeconfig"
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
 	fedschedulingv1a1 "sigs.k8s.io/kubefed/pkg/apis/scheduling/v1alpha1"
 	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
 	ctlutil "sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/controller/util/memberreads"
+	"sigs.k8s.io/kubefed/pkg/controller/util/placementpolicy"
 	"sigs.k8s.io/kubefed/pkg/controller/util/planner"
 	"sigs.k8s.io/kubefed/pkg/controller/util/podanalyzer"
+	"sigs.k8s.io/kubefed/pkg/metrics"
 )
 
 const (
 	RSPKind = "ReplicaSchedulingPreference"
+
+	// The reason of the event recorded on an RSP when the
+	// distribution of its replicas changes.
+	replicasRescheduledReason = "ReplicasRescheduled"
+)
+
+// The triggers of a change to the distribution of the replicas of an
+// RSP, in order of precedence.
+const (
+	triggerInitial            = "Initial"
+	triggerClusterUnavailable = "ClusterUnavailable"
+	triggerClusterAvailable   = "ClusterAvailable"
+	triggerReplicasChanged    = "ReplicasChanged"
+	triggerWeightChanged      = "WeightChanged"
+	triggerCapacity           = "Capacity"
+	triggerRebalance          = "Rebalance"
 )
 
+var podGroupVersionResource = schema.GroupVersionResource{
+	Group:    PodResource.Group,
+	Version:  PodResource.Version,
+	Resource: PodResource.Name,
+}
+
 func init() {
 	schedulingType := SchedulingType{
 		Kind:             RSPKind,
@@ -55,12 +88,22 @@ func init() {
 type ReplicaScheduler struct {
 	controllerConfig *ctlutil.ControllerConfig
 
+	profiles       map[string]*fedv1b1.SchedulingProfile
+	defaultProfile string
+
 	eventHandlers SchedulerEventHandlers
+	eventRecorder record.EventRecorder
 
 	plugins *ctlutil.SafeMap
 
+	// The last distribution of the replicas of each RSP, by key.
+	distributions *ctlutil.SafeMap
+
 	client      genericclient.Client
 	podInformer ctlutil.FederatedInformer
+
+	placementPolicies placementpolicy.Restrictor
+	stopChannel       chan struct{}
 }
 
 func NewReplicaScheduler(controllerConfig *ctlutil.ControllerConfig, eventHandlers SchedulerEventHandlers) (Scheduler, error) {
@@ -68,8 +111,15 @@ func NewReplicaScheduler(controllerConfig *ctlutil.ControllerConfig, eventHandle
 	scheduler := &ReplicaScheduler{
 		plugins:          ctlutil.NewSafeMap(),
 		controllerConfig: controllerConfig,
+		profiles:         newSchedulingProfiles(controllerConfig.Scheduling),
 		eventHandlers:    eventHandlers,
+		eventRecorder:    eventHandlers.EventRecorder,
+		distributions:    ctlutil.NewSafeMap(),
 		client:           client,
+		stopChannel:      make(chan struct{}),
+	}
+	if controllerConfig.Scheduling != nil {
+		scheduler.defaultProfile = controllerConfig.Scheduling.DefaultProfile
 	}
 
 	// TODO: Update this to use a typed client from single target informer.
@@ -88,6 +138,11 @@ func NewReplicaScheduler(controllerConfig *ctlutil.ControllerConfig, eventHandle
 		return nil, err
 	}
 
+	scheduler.placementPolicies, err = placementpolicy.NewRestrictor(controllerConfig, func(pkgruntime.Object) {})
+	if err != nil {
+		return nil, err
+	}
+
 	return scheduler, nil
 }
 
@@ -126,6 +181,7 @@ func (s *ReplicaScheduler) ObjectType() pkgruntime.Object {
 
 func (s *ReplicaScheduler) Start() {
 	s.podInformer.Start()
+	s.placementPolicies.Run(s.stopChannel)
 }
 
 func (s *ReplicaScheduler) HasSynced() bool {
@@ -135,6 +191,10 @@ func (s *ReplicaScheduler) HasSynced() bool {
 		}
 	}
 
+	if !s.placementPolicies.HasSynced() {
+		klog.V(2).Infof("Placement policies not synced")
+		return false
+	}
 	if !s.podInformer.ClustersSynced() {
 		klog.V(2).Infof("Cluster list not synced")
 		return false
@@ -153,6 +213,7 @@ func (s *ReplicaScheduler) Stop() {
 	}
 	s.plugins.DeleteAll()
 	s.podInformer.Stop()
+	close(s.stopChannel)
 }
 
 func (s *ReplicaScheduler) Reconcile(obj pkgruntime.Object, qualifiedName ctlutil.QualifiedName) ctlutil.ReconciliationStatus {
@@ -162,12 +223,12 @@ func (s *ReplicaScheduler) Reconcile(obj pkgruntime.Object, qualifiedName ctluti
 		return ctlutil.StatusError
 	}
 
-	clusterNames, err := s.clusterNames()
+	clusters, err := s.podInformer.GetReadyClusters()
 	if err != nil {
 		runtime.HandleError(errors.Wrap(err, "Failed to get cluster list"))
 		return ctlutil.StatusError
 	}
-	if len(clusterNames) == 0 {
+	if len(clusters) == 0 {
 		// no joined clusters, nothing to do
 		return ctlutil.StatusAllOK
 	}
@@ -189,36 +250,206 @@ func (s *ReplicaScheduler) Reconcile(obj pkgruntime.Object, qualifiedName ctluti
 	}
 
 	key := qualifiedName.String()
-	result, err := s.GetSchedulingResult(rsp, qualifiedName, clusterNames)
+	profile, err := s.profileFor(rsp, plugin.(*Plugin).FederatedAnnotations(key))
+	if err != nil {
+		runtime.HandleError(errors.Wrapf(err, "Failed to determine the scheduling profile of RSP named %q", key))
+		return ctlutil.StatusError
+	}
+	filters := profileFilters(profile)
+	clusters = schedulableClusters(rsp, clusters, filters)
+	clusters = allowedClusters(rsp, clusters, s.placementPolicies.AllowedClusters(rsp.Namespace, clusters))
+	clusterNames := []string{}
+	for _, cluster := range clusters {
+		clusterNames = append(clusterNames, cluster.Name)
+	}
+	underPressure := clustersUnderPressure(rsp, clusters, filters, time.Now())
+	rsp = resolveClusterWeights(rsp, clusters)
+	rsp = applyClusterScores(rsp, clusters, scoreClusters(profile.Scorers, clusters))
+
+	result, capacityLimited, err := s.GetSchedulingResult(rsp, qualifiedName, clusterNames, underPressure)
 	if err != nil {
 		runtime.HandleError(errors.Wrapf(err, "Failed to compute the schedule information while reconciling RSP named %q", key))
 		return ctlutil.StatusError
 	}
 
+	previous := s.lastDistribution(plugin.(*Plugin), key)
 	err = plugin.(*Plugin).Reconcile(qualifiedName, result)
 	if err != nil {
 		runtime.HandleError(errors.Wrapf(err, "Failed to reconcile federated targets for RSP named %q", key))
 		return ctlutil.StatusError
 	}
+	s.recordDistribution(rsp, key, previous, &replicaDistribution{
+		preferences: rsp.DeepCopy().Spec.Clusters,
+		replicas:    result,
+	}, clusterNames, capacityLimited)
 
 	return ctlutil.StatusAllOK
 }
 
-// The list of clusters could come from any target informer
-func (s *ReplicaScheduler) clusterNames() ([]string, error) {
-	clusters, err := s.podInformer.GetReadyClusters()
-	if err != nil {
-		return nil, err
+// Forget stops reporting the distribution of the replicas of the RSP
+// with the given name once the RSP has been deleted.
+func (s *ReplicaScheduler) Forget(qualifiedName ctlutil.QualifiedName) {
+	s.distributions.Delete(qualifiedName.String())
+	metrics.DeleteScheduledReplicas(qualifiedName.Namespace, qualifiedName.Name)
+}
+
+// replicaDistribution is the distribution of the replicas of an RSP
+// to clusters and the preferences it was computed from.
+type replicaDistribution struct {
+	// The preferences of the RSP after resolving the weights from
+	// cluster labels and scores.  Nil if not known.
+	preferences map[string]fedschedulingv1a1.ClusterPreferences
+	replicas    map[string]int64
+}
+
+// lastDistribution returns the distribution of the replicas of the RSP
+// with the given key that was last computed by the scheduler, or that
+// is found in the overrides of the federated resource if the scheduler
+// has not computed one since it was started.  Nil is returned if the
+// replicas of the RSP have never been distributed.
+func (s *ReplicaScheduler) lastDistribution(plugin *Plugin, key string) *replicaDistribution {
+	if distribution, ok := s.distributions.Get(key); ok {
+		return distribution.(*replicaDistribution)
 	}
-	clusterNames := []string{}
-	for _, cluster := range clusters {
-		clusterNames = append(clusterNames, cluster.Name)
+	replicas := plugin.ScheduledReplicas(key)
+	if replicas == nil {
+		return nil
 	}
+	return &replicaDistribution{replicas: replicas}
+}
+
+// recordDistribution reports the replicas assigned to each cluster by
+// the RSP and, if the distribution of the replicas changed, records an
+// event on the RSP attributing the change to its trigger.
+func (s *ReplicaScheduler) recordDistribution(rsp *fedschedulingv1a1.ReplicaSchedulingPreference, key string,
+	previous, current *replicaDistribution, clusterNames []string, capacityLimited sets.String) {
 
-	return clusterNames, nil
+	s.distributions.Store(key, current)
+	metrics.RecordScheduledReplicas(rsp.Namespace, rsp.Name, current.replicas)
+
+	var previousReplicas map[string]int64
+	if previous != nil {
+		previousReplicas = previous.replicas
+	}
+	changes := distributionChanges(previousReplicas, current.replicas)
+	if previous != nil && len(changes) == 0 {
+		return
+	}
+
+	trigger := distributionTrigger(previous, current, clusterNames, int64(rsp.Spec.TotalReplicas), capacityLimited)
+	metrics.RecordReplicaDistributionChange(trigger)
+	klog.V(2).Infof("Distribution of the replicas of RSP %q changed (trigger: %s): %s", key, trigger, strings.Join(changes, ", "))
+	if s.eventRecorder != nil {
+		s.eventRecorder.Eventf(rsp, corev1.EventTypeNormal, replicasRescheduledReason,
+			"Distributed %d replicas (trigger: %s): %s", rsp.Spec.TotalReplicas, trigger, strings.Join(changes, ", "))
+	}
 }
 
-func (s *ReplicaScheduler) GetSchedulingResult(rsp *fedschedulingv1a1.ReplicaSchedulingPreference, qualifiedName ctlutil.QualifiedName, clusterNames []string) (map[string]int64, error) {
+// distributionChanges describes the clusters whose replicas differ
+// between the given distributions, in order of cluster name.  A
+// cluster missing from a distribution is assigned no replicas.
+func distributionChanges(previous, current map[string]int64) []string {
+	clusterNames := sets.NewString()
+	for clusterName := range previous {
+		clusterNames.Insert(clusterName)
+	}
+	for clusterName := range current {
+		clusterNames.Insert(clusterName)
+	}
+	changes := []string{}
+	for _, clusterName := range clusterNames.List() {
+		if previous[clusterName] != current[clusterName] {
+			changes = append(changes, fmt.Sprintf("%s %d -> %d", clusterName, previous[clusterName], current[clusterName]))
+		}
+	}
+	return changes
+}
+
+// distributionTrigger returns what triggered the change from the
+// previous to the current distribution of the replicas of an RSP to the
+// given schedulable clusters.
+func distributionTrigger(previous, current *replicaDistribution, clusterNames []string, totalReplicas int64, capacityLimited sets.String) string {
+	if previous == nil {
+		return triggerInitial
+	}
+
+	schedulable := sets.NewString(clusterNames...)
+	for clusterName, replicas := range previous.replicas {
+		if replicas > 0 && !schedulable.Has(clusterName) {
+			return triggerClusterUnavailable
+		}
+	}
+	for clusterName, replicas := range current.replicas {
+		if _, ok := previous.replicas[clusterName]; !ok && replicas > 0 {
+			return triggerClusterAvailable
+		}
+	}
+
+	previousTotal := int64(0)
+	for _, replicas := range previous.replicas {
+		previousTotal += replicas
+	}
+	if previousTotal != totalReplicas {
+		return triggerReplicasChanged
+	}
+	if previous.preferences != nil && !reflect.DeepEqual(previous.preferences, current.preferences) {
+		return triggerWeightChanged
+	}
+	if capacityLimited.Len() > 0 {
+		return triggerCapacity
+	}
+	return triggerRebalance
+}
+
+// resolveClusterWeights returns a copy of the given RSP in which the
+// preferences that take their weight from a label of the KubeFedCluster
+// are replaced by explicit per-cluster preferences for the given
+// clusters.  The RSP is returned unchanged if no preference references
+// a label.
+func resolveClusterWeights(rsp *fedschedulingv1a1.ReplicaSchedulingPreference, clusters []*fedv1b1.KubeFedCluster) *fedschedulingv1a1.ReplicaSchedulingPreference {
+	usesLabels := false
+	for _, preference := range rsp.Spec.Clusters {
+		if len(preference.WeightFromLabel) > 0 {
+			usesLabels = true
+			break
+		}
+	}
+	if !usesLabels {
+		return rsp
+	}
+
+	resolved := rsp.DeepCopy()
+	for _, cluster := range clusters {
+		preference, ok := rsp.Spec.Clusters[cluster.Name]
+		if !ok {
+			preference, ok = rsp.Spec.Clusters["*"]
+		}
+		if !ok || len(preference.WeightFromLabel) == 0 {
+			continue
+		}
+		value, ok := cluster.Labels[preference.WeightFromLabel]
+		if ok {
+			weight, err := strconv.ParseInt(value, 10, 64)
+			if err == nil && weight >= 0 {
+				preference.Weight = weight
+			} else {
+				klog.Warningf("Ignoring invalid weight %q in label %q of cluster %q for RSP %s/%s: the value must be a non-negative integer",
+					value, preference.WeightFromLabel, cluster.Name, rsp.Namespace, rsp.Name)
+			}
+		}
+		preference.WeightFromLabel = ""
+		resolved.Spec.Clusters[cluster.Name] = preference
+	}
+	return resolved
+}
+
+// GetSchedulingResult returns the replicas of the target of the RSP
+// for each of the given clusters and the clusters whose estimated
+// capacity limited the replicas they could be assigned.  The clusters
+// under pressure are not assigned more replicas than are running in
+// them.
+func (s *ReplicaScheduler) GetSchedulingResult(rsp *fedschedulingv1a1.ReplicaSchedulingPreference, qualifiedName ctlutil.QualifiedName,
+	clusterNames []string, underPressure sets.String) (map[string]int64, sets.String, error) {
 	key := qualifiedName.String()
 
 	objectGetter := func(clusterName, key string) (interface{}, bool, error) {
@@ -243,16 +474,24 @@ func (s *ReplicaScheduler) GetSchedulingResult(rsp *fedschedulingv1a1.ReplicaSch
 
 		label := labels.SelectorFromSet(labels.Set(selectorLabels))
 
-		unstructuredPodList, err := client.Resources(unstructuredObj.GetNamespace()).List(metav1.ListOptions{LabelSelector: label.String()})
-		if err != nil || unstructuredPodList == nil {
-			return nil, err
-		}
-		return unstructuredPodList, nil
+		// Pods are listed through the shared cache of member reads so
+		// that frequent reconciles of the same target do not each list
+		// its pods.
+		namespace := unstructuredObj.GetNamespace()
+		options := metav1.ListOptions{LabelSelector: label.String()}
+		readKey := memberreads.Key{Cluster: clusterName, Resource: podGroupVersionResource.String()}
+		return s.controllerConfig.MemberReads.List(readKey, namespace, options, func() (pkgruntime.Object, error) {
+			unstructuredPodList, err := client.Resources(namespace).List(options)
+			if err != nil || unstructuredPodList == nil {
+				return nil, err
+			}
+			return unstructuredPodList, nil
+		})
 	}
 
-	currentReplicasPerCluster, estimatedCapacity, err := clustersReplicaState(clusterNames, key, objectGetter, podsGetter)
+	currentReplicasPerCluster, estimatedCapacity, err := clustersReplicaState(clusterNames, key, underPressure, objectGetter, podsGetter)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// TODO: Move this to API defaulting logic
@@ -263,7 +502,15 @@ func (s *ReplicaScheduler) GetSchedulingResult(rsp *fedschedulingv1a1.ReplicaSch
 	}
 
 	plnr := planner.NewPlanner(rsp)
-	return schedule(plnr, key, clusterNames, currentReplicasPerCluster, estimatedCapacity)
+	result, err := schedule(plnr, key, clusterNames, currentReplicasPerCluster, estimatedCapacity)
+	if err != nil {
+		return nil, nil, err
+	}
+	capacityLimited := sets.NewString()
+	for clusterName := range estimatedCapacity {
+		capacityLimited.Insert(clusterName)
+	}
+	return result, capacityLimited, nil
 }
 
 func schedule(planner *planner.Planner, key string, clusterNames []string, currentReplicasPerCluster map[string]int64, estimatedCapacity map[string]int64) (map[string]int64, error) {
@@ -307,9 +554,11 @@ func schedule(planner *planner.Planner, key string, clusterNames []string, curre
 }
 
 // clustersReplicaState returns information about the scheduling state of the pods running in the federated clusters.
+// The estimated capacity of a cluster under pressure is limited to the replicas currently running in it.
 func clustersReplicaState(
 	clusterNames []string,
 	key string,
+	underPressure sets.String,
 	objectGetter func(clusterName string, key string) (interface{}, bool, error),
 	podsGetter func(clusterName string, obj *unstructured.Unstructured) (pkgruntime.Object, error)) (currentReplicasPerCluster map[string]int64, estimatedCapacity map[string]int64, err error) {
 
@@ -365,5 +614,15 @@ func clustersReplicaState(
 			}
 		}
 	}
+
+	for _, clusterName := range clusterNames {
+		if !underPressure.Has(clusterName) {
+			continue
+		}
+		capacity := currentReplicasPerCluster[clusterName]
+		if estimate, ok := estimatedCapacity[clusterName]; !ok || capacity < estimate {
+			estimatedCapacity[clusterName] = capacity
+		}
+	}
 	return currentReplicasPerCluster, estimatedCapacity, nil
 }
@@ -0,0 +1,295 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedulingtypes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	fedschedulingv1a1 "sigs.k8s.io/kubefed/pkg/apis/scheduling/v1alpha1"
+	"sigs.k8s.io/kubefed/pkg/controller/util/planner"
+)
+
+const weightLabel = "scheduling.kubefed.io/weight"
+
+func newCluster(name, weight string) *fedv1b1.KubeFedCluster {
+	cluster := &fedv1b1.KubeFedCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+	}
+	if len(weight) > 0 {
+		cluster.Labels = map[string]string{weightLabel: weight}
+	}
+	return cluster
+}
+
+func newRSP(clusters map[string]fedschedulingv1a1.ClusterPreferences) *fedschedulingv1a1.ReplicaSchedulingPreference {
+	return &fedschedulingv1a1.ReplicaSchedulingPreference{
+		Spec: fedschedulingv1a1.ReplicaSchedulingPreferenceSpec{
+			TargetKind:    "FederatedDeployment",
+			TotalReplicas: 10,
+			Clusters:      clusters,
+		},
+	}
+}
+
+func TestResolveClusterWeights(t *testing.T) {
+	clusters := []*fedv1b1.KubeFedCluster{
+		newCluster("A", "3"),
+		newCluster("B", ""),
+		newCluster("C", "invalid"),
+		newCluster("D", "-1"),
+		newCluster("E", "0"),
+	}
+
+	testCases := map[string]struct {
+		preferences map[string]fedschedulingv1a1.ClusterPreferences
+		expected    map[string]fedschedulingv1a1.ClusterPreferences
+	}{
+		"Preferences without label references are unchanged": {
+			preferences: map[string]fedschedulingv1a1.ClusterPreferences{
+				"*": {Weight: 1},
+			},
+			expected: map[string]fedschedulingv1a1.ClusterPreferences{
+				"*": {Weight: 1},
+			},
+		},
+		"Wildcard preference is resolved for each cluster": {
+			preferences: map[string]fedschedulingv1a1.ClusterPreferences{
+				"*": {Weight: 1, MinReplicas: 1, WeightFromLabel: weightLabel},
+			},
+			expected: map[string]fedschedulingv1a1.ClusterPreferences{
+				"*": {Weight: 1, MinReplicas: 1, WeightFromLabel: weightLabel},
+				"A": {Weight: 3, MinReplicas: 1},
+				"B": {Weight: 1, MinReplicas: 1},
+				"C": {Weight: 1, MinReplicas: 1},
+				"D": {Weight: 1, MinReplicas: 1},
+				"E": {Weight: 0, MinReplicas: 1},
+			},
+		},
+		"Explicit preferences take precedence over the wildcard": {
+			preferences: map[string]fedschedulingv1a1.ClusterPreferences{
+				"*": {Weight: 2},
+				"A": {Weight: 1, WeightFromLabel: weightLabel},
+				"E": {Weight: 5},
+			},
+			expected: map[string]fedschedulingv1a1.ClusterPreferences{
+				"*": {Weight: 2},
+				"A": {Weight: 3},
+				"E": {Weight: 5},
+			},
+		},
+	}
+
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			rsp := newRSP(tc.preferences)
+			original := rsp.DeepCopy()
+			resolved := resolveClusterWeights(rsp, clusters)
+			assert.Equal(t, tc.expected, resolved.Spec.Clusters)
+			assert.Equal(t, original, rsp, "the given RSP should not be modified")
+		})
+	}
+}
+
+func TestScheduleWithWeightsFromLabels(t *testing.T) {
+	clusters := []*fedv1b1.KubeFedCluster{
+		newCluster("A", "1"),
+		newCluster("B", "4"),
+	}
+	rsp := newRSP(map[string]fedschedulingv1a1.ClusterPreferences{
+		"*": {Weight: 1, WeightFromLabel: weightLabel},
+	})
+	rsp = resolveClusterWeights(rsp, clusters)
+
+	result, err := schedule(planner.NewPlanner(rsp), "ns/foo", []string{"A", "B"}, map[string]int64{}, map[string]int64{})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int64{"A": 2, "B": 8}, result)
+}
+
+func newTargetObject(replicas, readyReplicas int64) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	_ = unstructured.SetNestedField(obj.Object, replicas, "spec", "replicas")
+	_ = unstructured.SetNestedField(obj.Object, readyReplicas, "status", "readyreplicas")
+	_ = unstructured.SetNestedStringMap(obj.Object, map[string]string{"app": "foo"}, "spec", "selector", "matchLabels")
+	return obj
+}
+
+func newReadyPod(t *testing.T) unstructured.Unstructured {
+	pod := &apiv1.Pod{
+		Status: apiv1.PodStatus{
+			Phase:      apiv1.PodRunning,
+			Conditions: []apiv1.PodCondition{{Type: apiv1.PodReady, Status: apiv1.ConditionTrue}},
+		},
+	}
+	content, err := pkgruntime.DefaultUnstructuredConverter.ToUnstructured(pod)
+	if err != nil {
+		t.Fatalf("Failed to convert pod: %v", err)
+	}
+	return unstructured.Unstructured{Object: content}
+}
+
+func TestScheduleAwayFromClustersUnderPressure(t *testing.T) {
+	objects := map[string]*unstructured.Unstructured{
+		// A is scaled up but only two of its replicas are ready.
+		"A": newTargetObject(5, 2),
+		"B": newTargetObject(2, 2),
+	}
+	objectGetter := func(clusterName, key string) (interface{}, bool, error) {
+		obj, ok := objects[clusterName]
+		return obj, ok, nil
+	}
+	podsGetter := func(clusterName string, obj *unstructured.Unstructured) (pkgruntime.Object, error) {
+		return &unstructured.UnstructuredList{Items: []unstructured.Unstructured{newReadyPod(t), newReadyPod(t)}}, nil
+	}
+	clusterNames := []string{"A", "B", "C"}
+
+	testCases := map[string]struct {
+		underPressure    sets.String
+		expectedCapacity map[string]int64
+		expectedResult   map[string]int64
+	}{
+		"Replicas are scheduled by weight without pressure": {
+			underPressure:    sets.NewString(),
+			expectedCapacity: map[string]int64{},
+			expectedResult:   map[string]int64{"A": 2, "B": 4, "C": 4},
+		},
+		"Replicas are limited to ready replicas in clusters under pressure": {
+			underPressure:    sets.NewString("B", "C"),
+			expectedCapacity: map[string]int64{"B": 2, "C": 0},
+			expectedResult:   map[string]int64{"A": 8, "B": 2, "C": 0},
+		},
+	}
+
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			current, capacity, err := clustersReplicaState(clusterNames, "ns/foo", tc.underPressure, objectGetter, podsGetter)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, tc.expectedCapacity, capacity)
+
+			rsp := newRSP(map[string]fedschedulingv1a1.ClusterPreferences{"*": {Weight: 1}})
+			result, err := schedule(planner.NewPlanner(rsp), "ns/foo", clusterNames, current, capacity)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedResult, result)
+		})
+	}
+}
+
+func TestDistributionTrigger(t *testing.T) {
+	weights := map[string]fedschedulingv1a1.ClusterPreferences{"*": {Weight: 1}}
+	previous := &replicaDistribution{
+		preferences: weights,
+		replicas:    map[string]int64{"A": 5, "B": 5},
+	}
+
+	testCases := map[string]struct {
+		previous        *replicaDistribution
+		current         *replicaDistribution
+		clusterNames    []string
+		totalReplicas   int64
+		capacityLimited sets.String
+		expected        string
+	}{
+		"Replicas distributed for the first time": {
+			current:       &replicaDistribution{preferences: weights, replicas: map[string]int64{"A": 5, "B": 5}},
+			clusterNames:  []string{"A", "B"},
+			totalReplicas: 10,
+			expected:      triggerInitial,
+		},
+		"Cluster holding replicas is no longer schedulable": {
+			previous:      previous,
+			current:       &replicaDistribution{preferences: weights, replicas: map[string]int64{"A": 10}},
+			clusterNames:  []string{"A"},
+			totalReplicas: 10,
+			expected:      triggerClusterUnavailable,
+		},
+		"Replicas moved to a new cluster": {
+			previous:      previous,
+			current:       &replicaDistribution{preferences: weights, replicas: map[string]int64{"A": 4, "B": 3, "C": 3}},
+			clusterNames:  []string{"A", "B", "C"},
+			totalReplicas: 10,
+			expected:      triggerClusterAvailable,
+		},
+		"Total replicas changed": {
+			previous:      previous,
+			current:       &replicaDistribution{preferences: weights, replicas: map[string]int64{"A": 6, "B": 6}},
+			clusterNames:  []string{"A", "B"},
+			totalReplicas: 12,
+			expected:      triggerReplicasChanged,
+		},
+		"Weights changed": {
+			previous: previous,
+			current: &replicaDistribution{
+				preferences: map[string]fedschedulingv1a1.ClusterPreferences{"A": {Weight: 1}, "B": {Weight: 4}},
+				replicas:    map[string]int64{"A": 2, "B": 8},
+			},
+			clusterNames:  []string{"A", "B"},
+			totalReplicas: 10,
+			expected:      triggerWeightChanged,
+		},
+		"Weights are not known after a restart": {
+			previous:        &replicaDistribution{replicas: previous.replicas},
+			current:         &replicaDistribution{preferences: weights, replicas: map[string]int64{"A": 8, "B": 2}},
+			clusterNames:    []string{"A", "B"},
+			totalReplicas:   10,
+			capacityLimited: sets.NewString("B"),
+			expected:        triggerCapacity,
+		},
+		"Cluster capacity limited the replicas": {
+			previous:        previous,
+			current:         &replicaDistribution{preferences: weights, replicas: map[string]int64{"A": 8, "B": 2}},
+			clusterNames:    []string{"A", "B"},
+			totalReplicas:   10,
+			capacityLimited: sets.NewString("B"),
+			expected:        triggerCapacity,
+		},
+		"Replicas rebalanced": {
+			previous:      previous,
+			current:       &replicaDistribution{preferences: weights, replicas: map[string]int64{"A": 6, "B": 4}},
+			clusterNames:  []string{"A", "B"},
+			totalReplicas: 10,
+			expected:      triggerRebalance,
+		},
+	}
+
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			capacityLimited := tc.capacityLimited
+			if capacityLimited == nil {
+				capacityLimited = sets.NewString()
+			}
+			trigger := distributionTrigger(tc.previous, tc.current, tc.clusterNames, tc.totalReplicas, capacityLimited)
+			assert.Equal(t, tc.expected, trigger)
+		})
+	}
+}
+
+func TestDistributionChanges(t *testing.T) {
+	previous := map[string]int64{"A": 5, "B": 5, "C": 0}
+	current := map[string]int64{"A": 5, "B": 2, "D": 3}
+	assert.Equal(t, []string{"B 5 -> 2", "D 0 -> 3"}, distributionChanges(previous, current))
+	assert.Empty(t, distributionChanges(previous, map[string]int64{"A": 5, "B": 5}))
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testenv
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/rest"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+// insecureToken is the token of the secrets of joined fake clusters.
+// Their api servers serve an insecure port that does not
+// authenticate requests, but the token is required to build the
+// configuration of a cluster.
+const insecureToken = "testenv"
+
+// JoinCluster registers the cluster with the given name and
+// configuration with the control plane in the given namespace.
+// Unlike `kubefedctl join`, no service account is created in the
+// cluster since the configuration is expected to be that of a fake
+// cluster that does not authenticate requests.
+func JoinCluster(client genericclient.Client, kubefedNamespace, name string, config *rest.Config) error {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: kubefedNamespace,
+			Name:      name,
+		},
+		Data: map[string][]byte{
+			util.TokenKey: []byte(insecureToken),
+		},
+	}
+	err := client.Create(context.TODO(), secret)
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return errors.Wrapf(err, "Failed to create the secret for cluster %q", name)
+	}
+
+	fedCluster := &fedv1b1.KubeFedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: kubefedNamespace,
+			Name:      name,
+		},
+		Spec: fedv1b1.KubeFedClusterSpec{
+			APIEndpoint: config.Host,
+			CABundle:    config.CAData,
+			SecretRef: fedv1b1.LocalSecretReference{
+				Name: secret.Name,
+			},
+		},
+	}
+	err = client.Create(context.TODO(), fedCluster)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to create KubeFedCluster %q", name)
+	}
+	return nil
+}
+
+// ClusterConfigs returns the configuration of each of the clusters
+// joined to the control plane in the given namespace, keyed by
+// cluster name.  The given host config is used for local clusters.
+func ClusterConfigs(client genericclient.Client, hostConfig *rest.Config, kubefedNamespace string) (map[string]*rest.Config, error) {
+	clusterList := &fedv1b1.KubeFedClusterList{}
+	err := client.List(context.TODO(), clusterList, kubefedNamespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error retrieving list of federated clusters")
+	}
+	configs := make(map[string]*rest.Config)
+	for _, cluster := range clusterList.Items {
+		config, err := util.BuildClusterConfig(&cluster, hostConfig, client, kubefedNamespace)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error building the configuration of cluster %q", cluster.Name)
+		}
+		configs[cluster.Name] = config
+	}
+	return configs, nil
+}
+
+// WaitForClusterReadiness waits until the clusters joined to the
+// control plane in the given namespace are ready.  An error is
+// returned if no clusters are joined.
+func WaitForClusterReadiness(client genericclient.Client, kubefedNamespace string, interval, timeout time.Duration) error {
+	clusterList := &fedv1b1.KubeFedClusterList{}
+	err := client.List(context.TODO(), clusterList, kubefedNamespace)
+	if err != nil {
+		return errors.Wrap(err, "Error retrieving list of federated clusters")
+	}
+	if len(clusterList.Items) == 0 {
+		return errors.New("No federated clusters found")
+	}
+	for _, cluster := range clusterList.Items {
+		if util.IsClusterReady(&cluster.Status) {
+			continue
+		}
+		clusterName := cluster.Name
+		err := wait.Poll(interval, timeout, func() (bool, error) {
+			cluster := &fedv1b1.KubeFedCluster{}
+			err := client.Get(context.TODO(), cluster, kubefedNamespace, clusterName)
+			if err != nil {
+				return false, err
+			}
+			return util.IsClusterReady(&cluster.Status), nil
+		})
+		if err != nil {
+			return errors.Wrapf(err, "Error determining readiness for cluster %q", clusterName)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testenv
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	apiextv1b1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	apiextv1b1client "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/typed/apiextensions/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/rest"
+
+	"sigs.k8s.io/kubefed/pkg/apis/core/typeconfig"
+	fedv1a1 "sigs.k8s.io/kubefed/pkg/apis/core/v1alpha1"
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	schedulingv1a1 "sigs.k8s.io/kubefed/pkg/apis/scheduling/v1alpha1"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/enable"
+)
+
+// kubefedResource describes a KubeFed API type installed in the host
+// cluster.
+type kubefedResource struct {
+	metav1.APIResource
+	status bool
+}
+
+// kubefedResources are the KubeFed API types used by the controllers
+// started by the environment.  The DNS types are not installed since
+// the DNS controllers are not started.
+var kubefedResources = []kubefedResource{
+	{coreResource(fedv1b1.SchemeGroupVersion.Version, "KubeFedCluster", "kubefedclusters", true), true},
+	{coreResource(fedv1b1.SchemeGroupVersion.Version, "FederatedTypeConfig", "federatedtypeconfigs", true), true},
+	{coreResource(fedv1b1.SchemeGroupVersion.Version, "KubeFedConfig", "kubefedconfigs", true), true},
+	{coreResource(fedv1b1.SchemeGroupVersion.Version, "DispatchPolicy", "dispatchpolicies", true), false},
+	{coreResource(fedv1b1.SchemeGroupVersion.Version, "PlacementPolicy", "placementpolicies", true), false},
+	{coreResource(fedv1b1.SchemeGroupVersion.Version, "PropagationQuota", "propagationquotas", true), true},
+	{coreResource(fedv1a1.SchemeGroupVersion.Version, "PropagatedVersion", "propagatedversions", true), true},
+	{coreResource(fedv1a1.SchemeGroupVersion.Version, "ClusterPropagatedVersion", "clusterpropagatedversions", false), true},
+	{coreResource(fedv1a1.SchemeGroupVersion.Version, "FederatedServiceStatus", "federatedservicestatuses", true), false},
+	{
+		metav1.APIResource{
+			Group:      schedulingv1a1.SchemeGroupVersion.Group,
+			Version:    schedulingv1a1.SchemeGroupVersion.Version,
+			Kind:       "ReplicaSchedulingPreference",
+			Name:       "replicaschedulingpreferences",
+			Namespaced: true,
+		},
+		false,
+	},
+	{
+		metav1.APIResource{
+			Group:      schedulingv1a1.SchemeGroupVersion.Group,
+			Version:    schedulingv1a1.SchemeGroupVersion.Version,
+			Kind:       "JobSchedulingPreference",
+			Name:       "jobschedulingpreferences",
+			Namespaced: true,
+		},
+		true,
+	},
+	{
+		metav1.APIResource{
+			Group:      schedulingv1a1.SchemeGroupVersion.Group,
+			Version:    schedulingv1a1.SchemeGroupVersion.Version,
+			Kind:       "CronJobSchedulingPreference",
+			Name:       "cronjobschedulingpreferences",
+			Namespaced: true,
+		},
+		true,
+	},
+}
+
+func coreResource(version, kind, name string, namespaced bool) metav1.APIResource {
+	return metav1.APIResource{
+		Group:      fedv1b1.SchemeGroupVersion.Group,
+		Version:    version,
+		Kind:       kind,
+		Name:       name,
+		Namespaced: namespaced,
+	}
+}
+
+// kubefedCRDs returns the CRDs of the KubeFed API types.  The CRDs
+// have no validation since the environment is not intended to test
+// the validation of KubeFed resources.
+func kubefedCRDs() []*apiextv1b1.CustomResourceDefinition {
+	crds := []*apiextv1b1.CustomResourceDefinition{}
+	for _, resource := range kubefedResources {
+		crd := enable.CrdForAPIResource(resource.APIResource, nil, nil)
+		if !resource.status {
+			crd.Spec.Subresources = nil
+		}
+		crds = append(crds, crd)
+	}
+	return crds
+}
+
+func installCRDs(config *rest.Config, timeout time.Duration) error {
+	client, err := apiextv1b1client.NewForConfig(configWithUserAgent(config))
+	if err != nil {
+		return err
+	}
+	for _, crd := range kubefedCRDs() {
+		_, err := client.CustomResourceDefinitions().Create(crd)
+		if err != nil && !apierrors.IsAlreadyExists(err) {
+			return errors.Wrapf(err, "Failed to create CRD %q", crd.Name)
+		}
+	}
+	for _, resource := range kubefedResources {
+		err := waitForCRD(config, resource.APIResource, timeout)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// waitForCRD waits until the CRD of the given type is established.
+func waitForCRD(config *rest.Config, apiResource metav1.APIResource, timeout time.Duration) error {
+	client, err := apiextv1b1client.NewForConfig(configWithUserAgent(config))
+	if err != nil {
+		return err
+	}
+	name := typeconfig.GroupQualifiedName(apiResource)
+	err = wait.PollImmediate(PollInterval, timeout, func() (bool, error) {
+		crd, err := client.CustomResourceDefinitions().Get(name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		for _, condition := range crd.Status.Conditions {
+			if condition.Type == apiextv1b1.Established && condition.Status == apiextv1b1.ConditionTrue {
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+	if err != nil {
+		return errors.Wrapf(err, "CRD %q was not established", name)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testenv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	apiextv1b1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+)
+
+func TestKubeFedCRDs(t *testing.T) {
+	crds := map[string]*apiextv1b1.CustomResourceDefinition{}
+	for _, crd := range kubefedCRDs() {
+		crds[crd.Name] = crd
+	}
+
+	cluster := crds["kubefedclusters.core.kubefed.k8s.io"]
+	if assert.NotNil(t, cluster) {
+		assert.Equal(t, apiextv1b1.NamespaceScoped, cluster.Spec.Scope)
+		assert.Equal(t, "KubeFedCluster", cluster.Spec.Names.Kind)
+		assert.NotNil(t, cluster.Spec.Subresources)
+	}
+
+	version := crds["clusterpropagatedversions.core.kubefed.k8s.io"]
+	if assert.NotNil(t, version) {
+		assert.Equal(t, apiextv1b1.ClusterScoped, version.Spec.Scope)
+	}
+
+	// The status of types without a status subresource must be
+	// updated with the resource.
+	rsp := crds["replicaschedulingpreferences.scheduling.kubefed.k8s.io"]
+	if assert.NotNil(t, rsp) {
+		assert.Nil(t, rsp.Spec.Subresources)
+	}
+}
@@ -0,0 +1,371 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package testenv runs a KubeFed control plane in-process against
+// fake member clusters so that integration tests can exercise
+// placement policies and type configs without deploying KubeFed.
+//
+// Each cluster is a kube-apiserver and etcd started with the test
+// control plane used by controller-runtime's envtest.  As for envtest,
+// the binaries are located with the TEST_ASSET_KUBE_APISERVER and
+// TEST_ASSET_ETCD environment variables, or in the KUBEBUILDER_ASSETS
+// directory, which defaults to /usr/local/kubebuilder/bin.
+package testenv
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeclientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog"
+	"sigs.k8s.io/testing_frameworks/integration"
+
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	"sigs.k8s.io/kubefed/pkg/controller/federatedtypeconfig"
+	"sigs.k8s.io/kubefed/pkg/controller/kubefedcluster"
+	"sigs.k8s.io/kubefed/pkg/controller/schedulingmanager"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/kubefedctl/enable"
+)
+
+const (
+	// DefaultHostClusterName is the name the host cluster is joined
+	// with if it is also a member cluster.
+	DefaultHostClusterName = "host"
+
+	// PollInterval is the interval at which the environment polls
+	// for the readiness of clusters and resources.
+	PollInterval = 100 * time.Millisecond
+	// DefaultTimeout is the default duration the environment waits
+	// for clusters and resources to become ready.
+	DefaultTimeout = 30 * time.Second
+
+	envKubeAPIServerBin    = "TEST_ASSET_KUBE_APISERVER"
+	envEtcdBin             = "TEST_ASSET_ETCD"
+	envKubebuilderPath     = "KUBEBUILDER_ASSETS"
+	defaultKubebuilderPath = "/usr/local/kubebuilder/bin"
+
+	userAgent = "kubefed-testenv"
+)
+
+// apiServerArgs are the arguments of the kube-apiserver of each
+// cluster.  Admission is disabled since no controller manager runs
+// to create the service accounts that pods would otherwise require.
+var apiServerArgs = []string{
+	"--etcd-servers={{ if .EtcdURL }}{{ .EtcdURL.String }}{{ end }}",
+	"--cert-dir={{ .CertDir }}",
+	"--insecure-port={{ if .URL }}{{ .URL.Port }}{{ end }}",
+	"--insecure-bind-address={{ if .URL }}{{ .URL.Hostname }}{{ end }}",
+	"--secure-port={{ if .SecurePort }}{{ .SecurePort }}{{ end }}",
+	"--admission-control=AlwaysAdmit",
+}
+
+// Cluster is a fake cluster of the environment.
+type Cluster struct {
+	Name   string
+	Config *rest.Config
+
+	controlPlane *integration.ControlPlane
+}
+
+// Environment is a KubeFed control plane running in-process against a
+// fake host cluster and fake member clusters.
+type Environment struct {
+	// KubeFedNamespace is the namespace of the control plane in the
+	// host cluster.  Defaults to kube-federation-system.
+	KubeFedNamespace string
+
+	// MemberClusterNames are the names of the fake member clusters
+	// started and joined by Start.
+	MemberClusterNames []string
+
+	// JoinHostCluster indicates whether the host cluster is also
+	// joined as a member cluster named HostClusterName.
+	JoinHostCluster bool
+	// HostClusterName defaults to DefaultHostClusterName.
+	HostClusterName string
+
+	// EnabledTypes are the names of the target types that are
+	// enabled for federation by Start (e.g. configmaps or
+	// deployments.apps), as accepted by `kubefedctl enable`.
+	// Namespaces are always enabled since the propagation of
+	// namespaced types depends on them.
+	EnabledTypes []string
+
+	// ControllerConfig configures the KubeFed controllers.  Its
+	// kubeconfig and namespaces are set by Start and other fields
+	// are used as given.
+	ControllerConfig *util.ControllerConfig
+
+	// Timeout is the duration to wait for clusters and types to
+	// become ready.  Defaults to DefaultTimeout.
+	Timeout time.Duration
+
+	// Host is the host cluster, available once Start has returned.
+	Host *Cluster
+	// Members are the member clusters by name, including the host
+	// cluster if it is joined.
+	Members map[string]*Cluster
+
+	stopChan chan struct{}
+}
+
+// Start starts the host and member clusters, joins the member
+// clusters, enables the configured types and starts the KubeFed
+// controllers.  Stop should be called even if Start fails to ensure
+// the clusters that were started are stopped.
+func (e *Environment) Start() error {
+	e.setDefaults()
+
+	host, err := startCluster(e.HostClusterName)
+	if err != nil {
+		return err
+	}
+	e.Host = host
+
+	err = installCRDs(host.Config, e.Timeout)
+	if err != nil {
+		return errors.Wrap(err, "Failed to install the KubeFed CRDs in the host cluster")
+	}
+	err = ensureNamespace(host.Config, e.KubeFedNamespace)
+	if err != nil {
+		return err
+	}
+
+	if e.JoinHostCluster {
+		err = e.joinCluster(host)
+		if err != nil {
+			return err
+		}
+	}
+	for _, name := range e.MemberClusterNames {
+		_, err = e.AddMemberCluster(name)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, typeName := range append([]string{util.NamespaceName}, e.EnabledTypes...) {
+		err = e.EnableType(typeName)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = e.startControllers()
+	if err != nil {
+		return err
+	}
+
+	client, err := e.Client()
+	if err != nil {
+		return err
+	}
+	return WaitForClusterReadiness(client, e.KubeFedNamespace, PollInterval, e.Timeout)
+}
+
+// Stop stops the KubeFed controllers and the clusters of the
+// environment.
+func (e *Environment) Stop() error {
+	if e.stopChan != nil {
+		close(e.stopChan)
+		e.stopChan = nil
+	}
+	var stopErr error
+	for name, cluster := range e.Members {
+		if cluster == e.Host {
+			continue
+		}
+		if err := cluster.controlPlane.Stop(); err != nil {
+			klog.Errorf("Failed to stop member cluster %q: %v", name, err)
+			stopErr = err
+		}
+	}
+	if e.Host != nil {
+		if err := e.Host.controlPlane.Stop(); err != nil {
+			klog.Errorf("Failed to stop the host cluster: %v", err)
+			stopErr = err
+		}
+	}
+	return stopErr
+}
+
+// AddMemberCluster starts a fake member cluster with the given name
+// and joins it to the control plane.
+func (e *Environment) AddMemberCluster(name string) (*Cluster, error) {
+	if _, ok := e.Members[name]; ok {
+		return nil, errors.Errorf("Member cluster %q already exists", name)
+	}
+	cluster, err := startCluster(name)
+	if err != nil {
+		return nil, err
+	}
+	err = e.joinCluster(cluster)
+	if err != nil {
+		_ = cluster.controlPlane.Stop()
+		return nil, err
+	}
+	return cluster, nil
+}
+
+// EnableType enables federation of the target type with the given
+// name (e.g. deployments.apps) with a generated federated type.
+func (e *Environment) EnableType(typeName string) error {
+	directive := enable.NewEnableTypeDirective()
+	directive.Name = typeName
+	resources, err := enable.GetResources(e.Host.Config, directive)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to generate the resources to enable %q", typeName)
+	}
+	err = enable.CreateResources(nil, e.Host.Config, resources, e.KubeFedNamespace)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to enable %q", typeName)
+	}
+	return waitForCRD(e.Host.Config, resources.TypeConfig.GetFederatedType(), e.Timeout)
+}
+
+// Client returns a generic client for the host cluster.
+func (e *Environment) Client() (genericclient.Client, error) {
+	return genericclient.New(configWithUserAgent(e.Host.Config))
+}
+
+func (e *Environment) setDefaults() {
+	if len(e.KubeFedNamespace) == 0 {
+		e.KubeFedNamespace = util.DefaultKubeFedSystemNamespace
+	}
+	if len(e.HostClusterName) == 0 {
+		e.HostClusterName = DefaultHostClusterName
+	}
+	if e.Timeout == 0 {
+		e.Timeout = DefaultTimeout
+	}
+	if e.ControllerConfig == nil {
+		e.ControllerConfig = &util.ControllerConfig{MinimizeLatency: true}
+	}
+	e.Members = make(map[string]*Cluster)
+}
+
+func (e *Environment) joinCluster(cluster *Cluster) error {
+	client, err := e.Client()
+	if err != nil {
+		return err
+	}
+	err = JoinCluster(client, e.KubeFedNamespace, cluster.Name, cluster.Config)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to join cluster %q", cluster.Name)
+	}
+	err = ensureNamespace(cluster.Config, e.KubeFedNamespace)
+	if err != nil {
+		return err
+	}
+	e.Members[cluster.Name] = cluster
+	return nil
+}
+
+func (e *Environment) startControllers() error {
+	config := e.ControllerConfig
+	config.KubeConfig = e.Host.Config
+	config.KubeFedNamespace = e.KubeFedNamespace
+	config.TargetNamespace = metav1.NamespaceAll
+
+	mgr, err := util.NewManager(config)
+	if err != nil {
+		return err
+	}
+	config.Manager = mgr
+
+	e.stopChan = make(chan struct{})
+	healthCheckConfig := &util.ClusterHealthCheckConfig{PeriodSeconds: 1, FailureThreshold: 1}
+	if err := kubefedcluster.StartClusterController(config, healthCheckConfig, e.stopChan); err != nil {
+		return errors.Wrap(err, "Error starting cluster controller")
+	}
+	if _, err := schedulingmanager.StartSchedulingManager(config, e.stopChan); err != nil {
+		return errors.Wrap(err, "Error starting scheduling manager")
+	}
+	if err := federatedtypeconfig.StartController(config, e.stopChan); err != nil {
+		return errors.Wrap(err, "Error starting federated type config controller")
+	}
+	util.StartManager(mgr, e.stopChan)
+	return nil
+}
+
+func startCluster(name string) (*Cluster, error) {
+	etcd := &integration.Etcd{
+		Path: binaryPath(envEtcdBin, "etcd"),
+	}
+	err := etcd.Start()
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to start etcd of cluster %q", name)
+	}
+	apiServer := &integration.APIServer{
+		Path:    binaryPath(envKubeAPIServerBin, "kube-apiserver"),
+		Args:    apiServerArgs,
+		EtcdURL: etcd.URL,
+	}
+	err = apiServer.Start()
+	if err != nil {
+		_ = etcd.Stop()
+		return nil, errors.Wrapf(err, "Failed to start the api server of cluster %q", name)
+	}
+	controlPlane := &integration.ControlPlane{
+		APIServer: apiServer,
+		Etcd:      etcd,
+	}
+	return &Cluster{
+		Name:         name,
+		Config:       &rest.Config{Host: controlPlane.APIURL().String()},
+		controlPlane: controlPlane,
+	}, nil
+}
+
+// binaryPath returns the path of the given binary, or an empty
+// string if it is set by the given environment variable.
+func binaryPath(envVar, name string) string {
+	if len(os.Getenv(envVar)) > 0 {
+		return ""
+	}
+	assetPath := os.Getenv(envKubebuilderPath)
+	if len(assetPath) == 0 {
+		assetPath = defaultKubebuilderPath
+	}
+	return filepath.Join(assetPath, name)
+}
+
+func ensureNamespace(config *rest.Config, name string) error {
+	client, err := kubeclientset.NewForConfig(configWithUserAgent(config))
+	if err != nil {
+		return err
+	}
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	_, err = client.CoreV1().Namespaces().Create(namespace)
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return errors.Wrapf(err, "Failed to create namespace %q", name)
+	}
+	return nil
+}
+
+func configWithUserAgent(config *rest.Config) *rest.Config {
+	config = rest.CopyConfig(config)
+	rest.AddUserAgent(config, userAgent)
+	return config
+}
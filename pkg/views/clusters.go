@@ -0,0 +1,211 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package views
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+
+	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
+	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+)
+
+// memberCluster is a member cluster queried by the view.
+type memberCluster struct {
+	Name   string
+	Host   string
+	Client *http.Client
+}
+
+// memberClusters provides the member clusters queried by the view.
+type memberClusters interface {
+	ReadyClusters() ([]memberCluster, error)
+}
+
+// kubeFedClusters provides the ready clusters registered with the
+// KubeFed control plane.
+type kubeFedClusters struct {
+	sync.Mutex
+	hostConfig   *restclient.Config
+	client       genericclient.Client
+	fedNamespace string
+	// Clients of the member clusters, rebuilt when the resource version
+	// of their KubeFedCluster changes.
+	members map[string]cachedMember
+}
+
+type cachedMember struct {
+	resourceVersion string
+	member          memberCluster
+}
+
+func newKubeFedClusters(hostConfig *restclient.Config, fedNamespace string) (*kubeFedClusters, error) {
+	client, err := genericclient.New(restclient.AddUserAgent(hostConfig, "kubefed-view-apiserver"))
+	if err != nil {
+		return nil, err
+	}
+	return &kubeFedClusters{
+		hostConfig:   hostConfig,
+		client:       client,
+		fedNamespace: fedNamespace,
+		members:      make(map[string]cachedMember),
+	}, nil
+}
+
+// ReadyClusters returns the ready member clusters sorted by name.
+func (c *kubeFedClusters) ReadyClusters() ([]memberCluster, error) {
+	clusterList := &fedv1b1.KubeFedClusterList{}
+	err := c.client.List(context.TODO(), clusterList, c.fedNamespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list KubeFedClusters")
+	}
+
+	c.Lock()
+	defer c.Unlock()
+	members := []memberCluster{}
+	names := make(map[string]bool)
+	for i := range clusterList.Items {
+		cluster := &clusterList.Items[i]
+		if !util.IsClusterReady(&cluster.Status) {
+			continue
+		}
+		names[cluster.Name] = true
+		cached, ok := c.members[cluster.Name]
+		if !ok || cached.resourceVersion != cluster.ResourceVersion {
+			member, err := c.newMember(cluster)
+			if err != nil {
+				return nil, err
+			}
+			cached = cachedMember{resourceVersion: cluster.ResourceVersion, member: member}
+			c.members[cluster.Name] = cached
+		}
+		members = append(members, cached.member)
+	}
+	for name := range c.members {
+		if !names[name] {
+			delete(c.members, name)
+		}
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].Name < members[j].Name
+	})
+	return members, nil
+}
+
+func (c *kubeFedClusters) newMember(cluster *fedv1b1.KubeFedCluster) (memberCluster, error) {
+	config, err := util.BuildClusterConfig(cluster, c.hostConfig, c.client, c.fedNamespace)
+	if err != nil {
+		return memberCluster{}, errors.Wrapf(err, "Failed to build the config of cluster %q", cluster.Name)
+	}
+	transport, err := restclient.TransportFor(config)
+	if err != nil {
+		return memberCluster{}, errors.Wrapf(err, "Failed to build the transport of cluster %q", cluster.Name)
+	}
+	return memberCluster{
+		Name: cluster.Name,
+		Host: strings.TrimSuffix(config.Host, "/"),
+		Client: &http.Client{
+			Transport: transport,
+			Timeout:   config.Timeout,
+		},
+	}, nil
+}
+
+// viewResource is a resource of the member clusters served by the view.
+type viewResource struct {
+	metav1.APIResource
+	// Resource queried in the member clusters.
+	Target schema.GroupVersionResource
+}
+
+// resolveResources resolves the given resources, in the form
+// `<plural>[.<version>][.<group>]`, with the discovery of the host
+// cluster. The resources are served by the view under their plural
+// name, so the plural names of the resources must be unique.
+func resolveResources(hostConfig *restclient.Config, names []string) (map[string]viewResource, error) {
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(hostConfig)
+	if err != nil {
+		return nil, err
+	}
+	groupResources, err := restmapper.GetAPIGroupResources(discoveryClient)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to discover the resources of the host cluster")
+	}
+	mapper := restmapper.NewDiscoveryRESTMapper(groupResources)
+
+	resources := make(map[string]viewResource)
+	for _, name := range names {
+		resource, err := resolveResource(mapper, name)
+		if err != nil {
+			return nil, err
+		}
+		if existing, ok := resources[resource.Name]; ok {
+			return nil, errors.Errorf("Resources %q and %q would both be served as %q",
+				existing.Target.String(), resource.Target.String(), resource.Name)
+		}
+		resources[resource.Name] = resource
+	}
+	return resources, nil
+}
+
+func resolveResource(mapper meta.RESTMapper, name string) (viewResource, error) {
+	gvr, gr := schema.ParseResourceArg(strings.ToLower(name))
+	if gvr != nil {
+		if _, err := mapper.KindFor(*gvr); err != nil {
+			// The name may instead be a resource of a group with dots.
+			gvr = nil
+		}
+	}
+	if gvr == nil {
+		resolved, err := mapper.ResourceFor(gr.WithVersion(""))
+		if err != nil {
+			return viewResource{}, errors.Wrapf(err, "Failed to resolve resource %q", name)
+		}
+		gvr = &resolved
+	}
+	gvk, err := mapper.KindFor(*gvr)
+	if err != nil {
+		return viewResource{}, errors.Wrapf(err, "Failed to resolve the kind of resource %q", name)
+	}
+	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return viewResource{}, errors.Wrapf(err, "Failed to resolve the scope of resource %q", name)
+	}
+	return viewResource{
+		APIResource: metav1.APIResource{
+			Name:         gvr.Resource,
+			SingularName: strings.ToLower(gvk.Kind),
+			Namespaced:   mapping.Scope.Name() == meta.RESTScopeNameNamespace,
+			Kind:         gvk.Kind,
+			Verbs:        metav1.Verbs{"get", "list"},
+		},
+		Target: *gvr,
+	}, nil
+}
@@ -0,0 +1,392 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package views
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	metav1beta1 "k8s.io/apimachinery/pkg/apis/meta/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/klog"
+
+	"sigs.k8s.io/kubefed/pkg/controller/util/memberreads"
+)
+
+const (
+	GroupName = "views.kubefed.io"
+	Version   = "v1alpha1"
+
+	// ClusterAnnotation is added to the objects served by the view to
+	// identify the member cluster they were read from.
+	ClusterAnnotation = GroupName + "/cluster"
+
+	tableAccept = "application/json;as=Table;v=v1beta1;g=meta.k8s.io"
+	jsonAccept  = "application/json"
+)
+
+var SchemeGroupVersion = schema.GroupVersion{Group: GroupName, Version: Version}
+
+// forwardedParameters are the query parameters of a request of the
+// view that are forwarded to the member clusters. Paging and watching
+// are not supported across member clusters.
+var forwardedParameters = []string{"labelSelector", "fieldSelector", "includeObject"}
+
+// viewHandler serves read-only list and get of resources across the
+// ready member clusters.
+type viewHandler struct {
+	resources map[string]viewResource
+	clusters  memberClusters
+	cache     *memberreads.Cache
+}
+
+// clusterResponse is the response of a member cluster to a request
+// fanned out by the view.
+type clusterResponse struct {
+	clusterName string
+	statusCode  int
+	body        []byte
+	err         error
+}
+
+func (h *viewHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	info, ok := request.RequestInfoFrom(req.Context())
+	if !ok {
+		writeError(w, apierrors.NewInternalError(errors.New("No request info found")))
+		return
+	}
+
+	if !info.IsResourceRequest {
+		h.serveDiscovery(w, req)
+		return
+	}
+
+	resource, ok := h.resources[info.Resource]
+	if !ok || info.APIVersion != Version || len(info.Subresource) > 0 {
+		writeError(w, apierrors.NewNotFound(schema.GroupResource{Group: GroupName, Resource: info.Resource}, info.Name))
+		return
+	}
+	groupResource := schema.GroupResource{Group: GroupName, Resource: resource.Name}
+	if info.Verb != "get" && info.Verb != "list" {
+		writeError(w, apierrors.NewMethodNotSupported(groupResource, info.Verb))
+		return
+	}
+	if !resource.Namespaced && len(info.Namespace) > 0 {
+		writeError(w, apierrors.NewNotFound(groupResource, info.Name))
+		return
+	}
+
+	table := strings.Contains(req.Header.Get("Accept"), "as=Table")
+	responses, err := h.fanOut(resource, info, req.URL.Query(), table)
+	if err != nil {
+		writeError(w, apierrors.NewServiceUnavailable(err.Error()))
+		return
+	}
+
+	var body interface{}
+	if table {
+		body, err = mergeTables(responses)
+	} else {
+		body, err = mergeObjects(resource, responses)
+	}
+	if err != nil {
+		writeError(w, apierrors.NewInternalError(err))
+		return
+	}
+	if info.Verb == "get" && isEmpty(body) {
+		writeError(w, apierrors.NewNotFound(groupResource, info.Name))
+		return
+	}
+	writeJSON(w, http.StatusOK, body)
+}
+
+// serveDiscovery serves the group and version of the view.
+func (h *viewHandler) serveDiscovery(w http.ResponseWriter, req *http.Request) {
+	switch strings.TrimSuffix(req.URL.Path, "/") {
+	case "/apis/" + GroupName:
+		writeJSON(w, http.StatusOK, apiGroup())
+	case "/apis/" + SchemeGroupVersion.String():
+		resourceList := &metav1.APIResourceList{
+			TypeMeta:     metav1.TypeMeta{Kind: "APIResourceList", APIVersion: "v1"},
+			GroupVersion: SchemeGroupVersion.String(),
+			APIResources: []metav1.APIResource{},
+		}
+		for _, resource := range h.resources {
+			resourceList.APIResources = append(resourceList.APIResources, resource.APIResource)
+		}
+		sort.Slice(resourceList.APIResources, func(i, j int) bool {
+			return resourceList.APIResources[i].Name < resourceList.APIResources[j].Name
+		})
+		writeJSON(w, http.StatusOK, resourceList)
+	default:
+		writeError(w, apierrors.NewNotFound(schema.GroupResource{}, req.URL.Path))
+	}
+}
+
+// fanOut issues the given request to every ready member cluster. An
+// error is returned only if none of the member clusters responded.
+func (h *viewHandler) fanOut(resource viewResource, info *request.RequestInfo, query url.Values, table bool) ([]clusterResponse, error) {
+	clusters, err := h.clusters.ReadyClusters()
+	if err != nil {
+		return nil, err
+	}
+
+	memberPath := targetPath(resource.Target, info.Namespace, info.Name)
+	forwarded := url.Values{}
+	for _, parameter := range forwardedParameters {
+		if values, ok := query[parameter]; ok {
+			forwarded[parameter] = values
+		}
+	}
+	accept := jsonAccept
+	if table {
+		accept = tableAccept
+	}
+
+	responses := make([]clusterResponse, len(clusters))
+	var wg sync.WaitGroup
+	for i := range clusters {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			responses[i] = h.query(clusters[i], resource, info, memberPath, forwarded.Encode(), accept)
+		}(i)
+	}
+	wg.Wait()
+
+	found := []clusterResponse{}
+	responded := 0
+	for _, response := range responses {
+		switch {
+		case response.err != nil:
+			klog.Warningf("Omitting cluster %q from view of %s: %v", response.clusterName, memberPath, response.err)
+		case response.statusCode == http.StatusOK:
+			responded++
+			found = append(found, response)
+		case response.statusCode == http.StatusNotFound && len(info.Name) > 0:
+			// The object does not exist in this cluster.
+			responded++
+		default:
+			klog.Warningf("Omitting cluster %q from view of %s: resource not found", response.clusterName, memberPath)
+		}
+	}
+	if responded == 0 && len(clusters) > 0 {
+		return nil, errors.Errorf("None of the %d ready member clusters could be queried", len(clusters))
+	}
+	return found, nil
+}
+
+// query issues a request to a member cluster, or returns the response
+// cached for the request.  Responses other than success and not found
+// are returned as errors and are not cached.
+func (h *viewHandler) query(cluster memberCluster, resource viewResource, info *request.RequestInfo, memberPath, rawQuery, accept string) clusterResponse {
+	requestURL := cluster.Host + memberPath
+	if len(rawQuery) > 0 {
+		requestURL = requestURL + "?" + rawQuery
+	}
+	key := memberreads.Key{Cluster: cluster.Name, Resource: resource.Target.String()}
+	read := memberreads.Read{Namespace: info.Namespace, Name: info.Name, Options: accept + " " + rawQuery}
+	value, err := h.cache.Read(key, read, func() (interface{}, error) {
+		memberRequest, err := http.NewRequest(http.MethodGet, requestURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		memberRequest.Header.Set("Accept", accept)
+		memberResponse, err := cluster.Client.Do(memberRequest)
+		if err != nil {
+			return nil, err
+		}
+		defer memberResponse.Body.Close()
+		body, err := ioutil.ReadAll(memberResponse.Body)
+		if err != nil {
+			return nil, err
+		}
+		if memberResponse.StatusCode != http.StatusOK && memberResponse.StatusCode != http.StatusNotFound {
+			return nil, errors.Errorf("Received status %d: %s", memberResponse.StatusCode, string(body))
+		}
+		return clusterResponse{clusterName: cluster.Name, statusCode: memberResponse.StatusCode, body: body}, nil
+	})
+	if err != nil {
+		return clusterResponse{clusterName: cluster.Name, err: err}
+	}
+	return value.(clusterResponse)
+}
+
+// targetPath returns the path of the given resource in a member
+// cluster.
+func targetPath(gvr schema.GroupVersionResource, namespace, name string) string {
+	elements := []string{"/apis", gvr.Group, gvr.Version}
+	if len(gvr.Group) == 0 {
+		elements = []string{"/api", gvr.Version}
+	}
+	if len(namespace) > 0 {
+		elements = append(elements, "namespaces", namespace)
+	}
+	elements = append(elements, gvr.Resource)
+	if len(name) > 0 {
+		elements = append(elements, name)
+	}
+	return path.Join(elements...)
+}
+
+// mergeObjects returns a list of the objects returned by the member
+// clusters, each annotated with the name of its cluster.
+func mergeObjects(resource viewResource, responses []clusterResponse) (*unstructured.UnstructuredList, error) {
+	list := &unstructured.UnstructuredList{Object: map[string]interface{}{}}
+	list.SetAPIVersion(SchemeGroupVersion.String())
+	list.SetKind(resource.Kind + "List")
+	for _, response := range responses {
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(response.body); err != nil {
+			return nil, errors.Wrapf(err, "Failed to decode the response of cluster %q", response.clusterName)
+		}
+		items := []unstructured.Unstructured{*obj}
+		if obj.IsList() {
+			memberList, err := obj.ToList()
+			if err != nil {
+				return nil, errors.Wrapf(err, "Failed to decode the response of cluster %q", response.clusterName)
+			}
+			items = memberList.Items
+		}
+		for i := range items {
+			setClusterAnnotation(&items[i], response.clusterName)
+			list.Items = append(list.Items, items[i])
+		}
+	}
+	return list, nil
+}
+
+// mergeTables returns a table of the rows returned by the member
+// clusters, with a leading column holding the name of the cluster of
+// each row. The columns of the first cluster are used, and the rows of
+// clusters returning different columns are omitted.
+func mergeTables(responses []clusterResponse) (*metav1beta1.Table, error) {
+	table := &metav1beta1.Table{
+		TypeMeta: metav1.TypeMeta{Kind: "Table", APIVersion: metav1beta1.SchemeGroupVersion.String()},
+		Rows:     []metav1beta1.TableRow{},
+	}
+	var columnNames []string
+	for _, response := range responses {
+		memberTable := &metav1beta1.Table{}
+		if err := json.Unmarshal(response.body, memberTable); err != nil {
+			return nil, errors.Wrapf(err, "Failed to decode the table of cluster %q", response.clusterName)
+		}
+		names := []string{}
+		for _, column := range memberTable.ColumnDefinitions {
+			names = append(names, column.Name)
+		}
+		if columnNames == nil {
+			columnNames = names
+			table.ColumnDefinitions = append([]metav1beta1.TableColumnDefinition{{
+				Name:        "Cluster",
+				Type:        "string",
+				Description: "Name of the member cluster the object was read from.",
+			}}, memberTable.ColumnDefinitions...)
+		} else if strings.Join(names, ",") != strings.Join(columnNames, ",") {
+			klog.Warningf("Omitting rows of cluster %q whose columns %v differ from %v", response.clusterName, names, columnNames)
+			continue
+		}
+		for _, row := range memberTable.Rows {
+			row.Cells = append([]interface{}{response.clusterName}, row.Cells...)
+			row.Object.Raw = annotateRawObject(row.Object.Raw, response.clusterName)
+			table.Rows = append(table.Rows, row)
+		}
+	}
+	return table, nil
+}
+
+// annotateRawObject adds the cluster annotation to the serialized
+// object of a table row, leaving it unchanged if it cannot be decoded.
+func annotateRawObject(raw []byte, clusterName string) []byte {
+	if len(raw) == 0 {
+		return raw
+	}
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(raw); err != nil {
+		return raw
+	}
+	setClusterAnnotation(obj, clusterName)
+	annotated, err := obj.MarshalJSON()
+	if err != nil {
+		return raw
+	}
+	return annotated
+}
+
+func setClusterAnnotation(obj *unstructured.Unstructured, clusterName string) {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[ClusterAnnotation] = clusterName
+	obj.SetAnnotations(annotations)
+}
+
+func isEmpty(body interface{}) bool {
+	switch typed := body.(type) {
+	case *unstructured.UnstructuredList:
+		return len(typed.Items) == 0
+	case *metav1beta1.Table:
+		return len(typed.Rows) == 0
+	}
+	return false
+}
+
+func apiGroup() *metav1.APIGroup {
+	version := metav1.GroupVersionForDiscovery{
+		GroupVersion: SchemeGroupVersion.String(),
+		Version:      Version,
+	}
+	return &metav1.APIGroup{
+		TypeMeta:         metav1.TypeMeta{Kind: "APIGroup", APIVersion: "v1"},
+		Name:             GroupName,
+		Versions:         []metav1.GroupVersionForDiscovery{version},
+		PreferredVersion: version,
+	}
+}
+
+func writeError(w http.ResponseWriter, err *apierrors.StatusError) {
+	status := err.Status()
+	status.TypeMeta = metav1.TypeMeta{Kind: "Status", APIVersion: "v1"}
+	writeJSON(w, int(status.Code), &status)
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, body interface{}) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to encode the response: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", jsonAccept)
+	w.WriteHeader(statusCode)
+	if _, err := w.Write(data); err != nil {
+		klog.Errorf("Failed to write the response: %v", err)
+	}
+}
@@ -0,0 +1,239 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package views
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	metav1beta1 "k8s.io/apimachinery/pkg/apis/meta/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/endpoints/filters"
+	"k8s.io/apiserver/pkg/endpoints/request"
+
+	"sigs.k8s.io/kubefed/pkg/controller/util/memberreads"
+)
+
+type fakeClusters []memberCluster
+
+func (c fakeClusters) ReadyClusters() ([]memberCluster, error) {
+	return c, nil
+}
+
+// newFakeCluster returns a member cluster serving the given pods, and
+// a counter of the requests it received.
+func newFakeCluster(t *testing.T, name string, podNames ...string) (memberCluster, *int) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests++
+		var body interface{}
+		switch req.URL.Path {
+		case "/api/v1/namespaces/ns/pods":
+			if req.Header.Get("Accept") == tableAccept {
+				rows := []metav1beta1.TableRow{}
+				for _, podName := range podNames {
+					rows = append(rows, metav1beta1.TableRow{Cells: []interface{}{podName, "Running"}})
+				}
+				body = &metav1beta1.Table{
+					ColumnDefinitions: []metav1beta1.TableColumnDefinition{{Name: "Name"}, {Name: "Status"}},
+					Rows:              rows,
+				}
+				break
+			}
+			items := []interface{}{}
+			for _, podName := range podNames {
+				items = append(items, newPod(podName))
+			}
+			body = map[string]interface{}{"apiVersion": "v1", "kind": "PodList", "items": items}
+		default:
+			for _, podName := range podNames {
+				if req.URL.Path == "/api/v1/namespaces/ns/pods/"+podName {
+					body = newPod(podName)
+				}
+			}
+		}
+		if body == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("Failed to encode response: %v", err)
+		}
+		if _, err := w.Write(data); err != nil {
+			t.Fatalf("Failed to write response: %v", err)
+		}
+	}))
+	return memberCluster{Name: name, Host: server.URL, Client: server.Client()}, &requests
+}
+
+func newPod(name string) map[string]interface{} {
+	return map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata":   map[string]interface{}{"name": name, "namespace": "ns"},
+	}
+}
+
+func newTestHandler(clusters ...memberCluster) http.Handler {
+	handler := &viewHandler{
+		resources: map[string]viewResource{
+			"pods": {
+				APIResource: metav1.APIResource{Name: "pods", Namespaced: true, Kind: "Pod", Verbs: metav1.Verbs{"get", "list"}},
+				Target:      schema.GroupVersionResource{Version: "v1", Resource: "pods"},
+			},
+		},
+		clusters: fakeClusters(clusters),
+		cache:    memberreads.New(time.Minute),
+	}
+	resolver := &request.RequestInfoFactory{
+		APIPrefixes:          sets.NewString("api", "apis"),
+		GrouplessAPIPrefixes: sets.NewString("api"),
+	}
+	return filters.WithRequestInfo(handler, resolver)
+}
+
+func serve(handler http.Handler, method, path, accept string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	if len(accept) > 0 {
+		req.Header.Set("Accept", accept)
+	}
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func decodeList(t *testing.T, recorder *httptest.ResponseRecorder) map[string]string {
+	list := &unstructured.UnstructuredList{}
+	if err := list.UnmarshalJSON(recorder.Body.Bytes()); err != nil {
+		t.Fatalf("Failed to decode list: %v", err)
+	}
+	clustersByName := make(map[string]string)
+	for _, item := range list.Items {
+		clustersByName[item.GetName()] = item.GetAnnotations()[ClusterAnnotation]
+	}
+	return clustersByName
+}
+
+func TestViewList(t *testing.T) {
+	cluster1, requests1 := newFakeCluster(t, "cluster1", "a", "b")
+	cluster2, _ := newFakeCluster(t, "cluster2", "c")
+	handler := newTestHandler(cluster1, cluster2)
+
+	path := "/apis/views.kubefed.io/v1alpha1/namespaces/ns/pods"
+	recorder := serve(handler, http.MethodGet, path, "")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, map[string]string{"a": "cluster1", "b": "cluster1", "c": "cluster2"}, decodeList(t, recorder))
+
+	serve(handler, http.MethodGet, path, "")
+	assert.Equal(t, 1, *requests1, "Responses should be cached")
+}
+
+func TestViewGet(t *testing.T) {
+	cluster1, _ := newFakeCluster(t, "cluster1", "a")
+	cluster2, _ := newFakeCluster(t, "cluster2", "a", "b")
+	handler := newTestHandler(cluster1, cluster2)
+
+	recorder := serve(handler, http.MethodGet, "/apis/views.kubefed.io/v1alpha1/namespaces/ns/pods/b", "")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, map[string]string{"b": "cluster2"}, decodeList(t, recorder))
+
+	recorder = serve(handler, http.MethodGet, "/apis/views.kubefed.io/v1alpha1/namespaces/ns/pods/z", "")
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestViewTable(t *testing.T) {
+	cluster1, _ := newFakeCluster(t, "cluster1", "a")
+	cluster2, _ := newFakeCluster(t, "cluster2", "b")
+	handler := newTestHandler(cluster1, cluster2)
+
+	recorder := serve(handler, http.MethodGet, "/apis/views.kubefed.io/v1alpha1/namespaces/ns/pods", tableAccept+", application/json")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	table := &metav1beta1.Table{}
+	if err := json.Unmarshal(recorder.Body.Bytes(), table); err != nil {
+		t.Fatalf("Failed to decode table: %v", err)
+	}
+	columns := []string{}
+	for _, column := range table.ColumnDefinitions {
+		columns = append(columns, column.Name)
+	}
+	assert.Equal(t, []string{"Cluster", "Name", "Status"}, columns)
+	rows := []string{}
+	for _, row := range table.Rows {
+		rows = append(rows, fmt.Sprintf("%v", row.Cells))
+	}
+	assert.Equal(t, []string{"[cluster1 a Running]", "[cluster2 b Running]"}, rows)
+}
+
+func TestViewRequests(t *testing.T) {
+	cluster, _ := newFakeCluster(t, "cluster1", "a")
+	handler := newTestHandler(cluster)
+
+	testCases := map[string]struct {
+		method     string
+		path       string
+		statusCode int
+	}{
+		"Group is discovered": {
+			method:     http.MethodGet,
+			path:       "/apis/views.kubefed.io",
+			statusCode: http.StatusOK,
+		},
+		"Version is discovered": {
+			method:     http.MethodGet,
+			path:       "/apis/views.kubefed.io/v1alpha1",
+			statusCode: http.StatusOK,
+		},
+		"Unknown resource is not found": {
+			method:     http.MethodGet,
+			path:       "/apis/views.kubefed.io/v1alpha1/secrets",
+			statusCode: http.StatusNotFound,
+		},
+		"Delete is not supported": {
+			method:     http.MethodDelete,
+			path:       "/apis/views.kubefed.io/v1alpha1/namespaces/ns/pods/a",
+			statusCode: http.StatusMethodNotAllowed,
+		},
+		"Watch is not supported": {
+			method:     http.MethodGet,
+			path:       "/apis/views.kubefed.io/v1alpha1/pods?watch=true",
+			statusCode: http.StatusMethodNotAllowed,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			recorder := serve(handler, tc.method, tc.path, "")
+			assert.Equal(t, tc.statusCode, recorder.Code)
+		})
+	}
+}
+
+func TestTargetPath(t *testing.T) {
+	assert.Equal(t, "/api/v1/namespaces/ns/pods/a",
+		targetPath(schema.GroupVersionResource{Version: "v1", Resource: "pods"}, "ns", "a"))
+	assert.Equal(t, "/apis/apps/v1/deployments",
+		targetPath(schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}, "", ""))
+}
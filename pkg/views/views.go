@@ -0,0 +1,153 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package views
+
+import (
+	"net"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	apimachineryversion "k8s.io/apimachinery/pkg/version"
+	genericapiserver "k8s.io/apiserver/pkg/server"
+	genericoptions "k8s.io/apiserver/pkg/server/options"
+
+	"sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/controller/util/memberreads"
+	"sigs.k8s.io/kubefed/pkg/version"
+)
+
+var (
+	scheme = runtime.NewScheme()
+	codecs = serializer.NewCodecFactory(scheme)
+)
+
+func init() {
+	metav1.AddToGroupVersion(scheme, schema.GroupVersion{Version: "v1"})
+	unversioned := schema.GroupVersion{Group: "", Version: "v1"}
+	scheme.AddUnversionedTypes(unversioned,
+		&metav1.Status{},
+		&metav1.APIVersions{},
+		&metav1.APIGroupList{},
+		&metav1.APIGroup{},
+		&metav1.APIResourceList{},
+	)
+}
+
+// Options are the options of the view API server.
+type Options struct {
+	RecommendedOptions *genericoptions.RecommendedOptions
+
+	// Namespace of the KubeFedClusters of the member clusters.
+	KubeFedNamespace string
+	// Resources served by the view.
+	Resources []string
+	// Period for which the responses of member clusters are reused.
+	CacheTTL time.Duration
+}
+
+func NewOptions() *Options {
+	o := &Options{
+		RecommendedOptions: genericoptions.NewRecommendedOptions("", codecs.LegacyCodec(metav1.SchemeGroupVersion), nil),
+		KubeFedNamespace:   util.DefaultKubeFedSystemNamespace,
+		Resources:          []string{"pods", "services", "deployments.apps"},
+		CacheTTL:           5 * time.Second,
+	}
+	o.RecommendedOptions.Etcd = nil
+	o.RecommendedOptions.Admission = nil
+	return o
+}
+
+// NewViewAPIServerCommand returns a command that serves the
+// views.kubefed.io aggregated API: read-only list and get of resources
+// across the ready member clusters.
+func NewViewAPIServerCommand(stopChan <-chan struct{}) *cobra.Command {
+	o := NewOptions()
+
+	cmd := &cobra.Command{
+		Use:   "view-apiserver",
+		Short: "Start a kubefed view API server",
+		Long:  "Start an API server serving read-only views of resources across kubefed member clusters",
+		RunE: func(c *cobra.Command, args []string) error {
+			return o.Run(stopChan)
+		},
+	}
+
+	flags := cmd.Flags()
+	o.RecommendedOptions.AddFlags(flags)
+	flags.StringVar(&o.KubeFedNamespace, "kubefed-namespace", o.KubeFedNamespace, "The namespace of the KubeFedClusters of the member clusters.")
+	flags.StringSliceVar(&o.Resources, "resources", o.Resources, "The resources served by the view, in the form <plural>[.<version>][.<group>].")
+	flags.DurationVar(&o.CacheTTL, "cache-ttl", o.CacheTTL, "The period for which the responses of member clusters are reused. A period of 0 disables caching.")
+
+	return cmd
+}
+
+// Run starts the view API server and blocks until the stop channel is
+// closed.
+func (o *Options) Run(stopChan <-chan struct{}) error {
+	if len(o.Resources) == 0 {
+		return errors.New("At least one resource must be served")
+	}
+	if err := o.RecommendedOptions.SecureServing.MaybeDefaultWithSelfSignedCerts("localhost", nil, []net.IP{net.ParseIP("127.0.0.1")}); err != nil {
+		return errors.Wrap(err, "Error creating self-signed certificates")
+	}
+
+	serverConfig := genericapiserver.NewRecommendedConfig(codecs)
+	if err := o.RecommendedOptions.ApplyTo(serverConfig, scheme); err != nil {
+		return err
+	}
+	versionInfo := version.Get()
+	serverConfig.Version = &apimachineryversion.Info{
+		GitVersion:   versionInfo.Version,
+		GitCommit:    versionInfo.GitCommit,
+		GitTreeState: versionInfo.GitTreeState,
+		BuildDate:    versionInfo.BuildDate,
+		GoVersion:    versionInfo.GoVersion,
+		Compiler:     versionInfo.Compiler,
+		Platform:     versionInfo.Platform,
+	}
+
+	server, err := serverConfig.Complete().New("kubefed-view-apiserver", genericapiserver.NewEmptyDelegate())
+	if err != nil {
+		return err
+	}
+
+	hostConfig := serverConfig.ClientConfig
+	resources, err := resolveResources(hostConfig, o.Resources)
+	if err != nil {
+		return err
+	}
+	clusters, err := newKubeFedClusters(hostConfig, o.KubeFedNamespace)
+	if err != nil {
+		return err
+	}
+	handler := &viewHandler{
+		resources: resources,
+		clusters:  clusters,
+		cache:     memberreads.New(o.CacheTTL),
+	}
+	server.Handler.NonGoRestfulMux.Handle("/apis/"+GroupName, handler)
+	server.Handler.NonGoRestfulMux.HandlePrefix("/apis/"+GroupName+"/", handler)
+	server.DiscoveryGroupManager.AddGroup(*apiGroup())
+
+	return server.PrepareRun().Run(stopChan)
+}
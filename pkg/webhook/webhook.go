@@ -25,12 +25,14 @@ import (
 
 	"sigs.k8s.io/kubefed/pkg/controller/webhook"
 	"sigs.k8s.io/kubefed/pkg/controller/webhook/federatedtypeconfig"
+	"sigs.k8s.io/kubefed/pkg/controller/webhook/kubefedconfig"
 )
 
 func NewWebhookCommand(stopChan <-chan struct{}) *cobra.Command {
 	admissionHooks := []apiserver.AdmissionHook{
 		&federatedtypeconfig.FederatedTypeConfigValidationHook{},
 		&webhook.KubeFedClusterValidationHook{},
+		&kubefedconfig.KubeFedConfigValidationHook{},
 	}
 
 	cmd := server.NewCommandStartAdmissionServer(os.Stdout, os.Stderr, stopChan, admissionHooks...)
@@ -154,6 +154,7 @@ run-e2e-tests
 
 echo "Validating KubeFed walkthrough"
 ./scripts/deploy-federated-nginx.sh
+kubectl -n test-namespace annotate federatednamespace test-namespace kubefed.k8s.io/confirm-namespace-deletion=true
 kubectl delete ns test-namespace
 
 echo "Scaling down cluster-scoped controller manager"
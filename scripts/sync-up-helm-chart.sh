@@ -27,6 +27,16 @@ CHART_FEDERATED_CRD_DIR="${CHART_FEDERATED_CRD_DIR:-charts/kubefed/charts/contro
 CHART_FEDERATED_PROPAGATION_DIR="${CHART_FEDERATED_PROPAGATION_DIR:-charts/kubefed/templates}"
 TEMP_CRDS_YAML="/tmp/kubefed-crds.yaml"
 
+# Print the documents of the given kind in the given multi-document
+# yaml file.
+function extract-kind() {
+  awk -v kind="kind: $1" '
+    /^---$/ { if (found) printf "%s", doc; doc = ""; found = 0 }
+    { doc = doc $0 "\n" }
+    $0 == kind { found = 1 }
+    END { if (found) printf "%s", doc }' "$2"
+}
+
 # Check for existence of kube-apiserver and etcd binaries in bin directory
 if [[ ! -f ${ROOT_DIR}/bin/etcd || ! -f ${ROOT_DIR}/bin/kube-apiserver ]];
 then
@@ -59,6 +69,11 @@ if [ -n "${crd_diff}" ]; then
   sed -i '$a{{ end }}' ${CHART_FEDERATED_CRD_DIR}/crds.yaml
 fi
 
+# Generate the admission policies installed instead of the admission webhook.
+./bin/kubefedctl generate-admission-policies > ${CHART_FEDERATED_CRD_DIR}/validatingadmissionpolicies.yaml
+sed -i '1i{{- if .Values.admissionPolicies }}' ${CHART_FEDERATED_CRD_DIR}/validatingadmissionpolicies.yaml
+sed -i '$a{{- end }}' ${CHART_FEDERATED_CRD_DIR}/validatingadmissionpolicies.yaml
+
 # Generate kubeconfig to access kube-apiserver. It is cleaned when script is done.
 cat <<EOF > ${WORKDIR}/kubeconfig
 apiVersion: v1
@@ -87,21 +102,24 @@ util::wait-for-condition 'ok' "kubectl --kubeconfig ${WORKDIR}/kubeconfig --cont
 # Generate YAML templates to enable resource propagation for helm chart.
 echo -n > ${CHART_FEDERATED_PROPAGATION_DIR}/federatedtypeconfig.yaml
 echo -n > ${CHART_FEDERATED_PROPAGATION_DIR}/crds.yaml
+echo -n > ${CHART_FEDERATED_PROPAGATION_DIR}/aggregatedclusterroles.yaml
 for filename in ./config/enabletypedirectives/*.yaml; do
   full_name=${CHART_FEDERATED_PROPAGATION_DIR}/$(basename $filename)
 
   ./bin/kubefedctl --kubeconfig ${WORKDIR}/kubeconfig enable -f "${filename}" --kubefed-namespace="${NS}" --host-cluster-context kubefed -o yaml > ${full_name}
-  sed -n '/^---/,/^---/p' ${full_name} >> ${CHART_FEDERATED_PROPAGATION_DIR}/federatedtypeconfig.yaml
-  sed -i '$d' ${CHART_FEDERATED_PROPAGATION_DIR}/federatedtypeconfig.yaml
-
-  echo "---" >> ${CHART_FEDERATED_PROPAGATION_DIR}/crds.yaml
-  sed -n '/^apiVersion: apiextensions.k8s.io\/v1beta1/,$p' ${full_name} >> ${CHART_FEDERATED_PROPAGATION_DIR}/crds.yaml
+  extract-kind FederatedTypeConfig ${full_name} >> ${CHART_FEDERATED_PROPAGATION_DIR}/federatedtypeconfig.yaml
+  extract-kind CustomResourceDefinition ${full_name} >> ${CHART_FEDERATED_PROPAGATION_DIR}/crds.yaml
+  extract-kind ClusterRole ${full_name} >> ${CHART_FEDERATED_PROPAGATION_DIR}/aggregatedclusterroles.yaml
 
   rm ${full_name}
 done
 sed -i 's/^metadata:/metadata:\n  annotations:\n    "helm.sh\/hook": crd-install/'  ${CHART_FEDERATED_PROPAGATION_DIR}/crds.yaml
 sed -i '1i{{ if (or (or (not .Values.global.scope) (eq .Values.global.scope "Cluster")) (not (.Capabilities.APIVersions.Has "types.kubefed.k8s.io\/v1beta1"))) }}' ${CHART_FEDERATED_PROPAGATION_DIR}/crds.yaml
 sed -i '$a{{ end }}' ${CHART_FEDERATED_PROPAGATION_DIR}/crds.yaml
+# The aggregated ClusterRoles are installed with the CRDs they grant
+# access to.
+sed -i '1i{{ if (or (or (not .Values.global.scope) (eq .Values.global.scope "Cluster")) (not (.Capabilities.APIVersions.Has "types.kubefed.k8s.io\/v1beta1"))) }}' ${CHART_FEDERATED_PROPAGATION_DIR}/aggregatedclusterroles.yaml
+sed -i '$a{{ end }}' ${CHART_FEDERATED_PROPAGATION_DIR}/aggregatedclusterroles.yaml
 
 # Clean kube-apiserver daemons and temporary files
 kill %1 # etcd
@@ -41,7 +41,6 @@ import (
 	"sigs.k8s.io/kubefed/pkg/controller/sync/status"
 	versionmanager "sigs.k8s.io/kubefed/pkg/controller/sync/version"
 	"sigs.k8s.io/kubefed/pkg/controller/util"
-	"sigs.k8s.io/kubefed/pkg/kubefedctl/federate"
 )
 
 // FederatedTypeCrudTester exercises Create/Read/Update/Delete
@@ -103,7 +102,7 @@ func (c *FederatedTypeCrudTester) Create(targetObject *unstructured.Unstructured
 	qualifiedName := util.NewQualifiedName(targetObject)
 	kind := c.typeConfig.GetTargetType().Kind
 	fedKind := c.typeConfig.GetFederatedType().Kind
-	fedObject, err := federate.FederatedResourceFromTargetResource(c.typeConfig, targetObject)
+	fedObject, err := util.FederatedResourceFromTargetResource(c.typeConfig, targetObject)
 	if err != nil {
 		c.tl.Fatalf("Error obtaining %s from %s %q: %v", fedKind, kind, qualifiedName, err)
 	}
@@ -260,38 +259,10 @@ func (c *FederatedTypeCrudTester) CheckDelete(fedObject *unstructured.Unstructur
 	client := c.resourceClient(apiResource)
 
 	if orphanDependents {
-		orphanKey := sync.OrphanManagedResources
-		err := wait.PollImmediate(c.waitInterval, wait.ForeverTestTimeout, func() (bool, error) {
-			var err error
-			if fedObject == nil {
-				fedObject, err = client.Resources(namespace).Get(name, metav1.GetOptions{})
-				if err != nil {
-					c.tl.Logf("Error retrieving %s %q to add the %q annotation: %v", federatedKind, qualifiedName, orphanKey, err)
-					return false, nil
-				}
-			}
-			// Set the orphan annotation if necessary
-			annotations := fedObject.GetAnnotations()
-			if annotations == nil {
-				annotations = make(map[string]string)
-			}
-			if annotations[orphanKey] == "true" {
-				return true, nil
-			}
-			annotations[orphanKey] = "true"
-			fedObject.SetAnnotations(annotations)
-			fedObject, err = client.Resources(namespace).Update(fedObject, metav1.UpdateOptions{})
-			if err == nil {
-				return true, nil
-			}
-			c.tl.Logf("Will retry updating %s %q to include the %q annotation after error: %v", federatedKind, qualifiedName, orphanKey, err)
-			// Clear fedObject to ensure its attempted retrieval in the next iteration
-			fedObject = nil
-			return false, nil
-		})
-		if err != nil {
-			c.tl.Fatalf("Timed out trying to add %q annotation to %s %q", orphanKey, federatedKind, qualifiedName)
-		}
+		c.ensureAnnotation(fedObject, sync.OrphanManagedResources)
+	} else if c.targetIsNamespace {
+		// Deletion of namespaces in member clusters must be confirmed.
+		c.ensureAnnotation(fedObject, sync.ConfirmNamespaceDeletion)
 	}
 
 	c.tl.Logf("Deleting %s %q", federatedKind, qualifiedName)
@@ -363,6 +334,50 @@ func (c *FederatedTypeCrudTester) CheckDelete(fedObject *unstructured.Unstructur
 	}
 }
 
+// ensureAnnotation ensures that the given federated resource has the
+// given annotation set to "true".
+func (c *FederatedTypeCrudTester) ensureAnnotation(fedObject *unstructured.Unstructured, annotationKey string) {
+	apiResource := c.typeConfig.GetFederatedType()
+	federatedKind := apiResource.Kind
+	qualifiedName := util.NewQualifiedName(fedObject)
+	name := qualifiedName.Name
+	namespace := qualifiedName.Namespace
+
+	client := c.resourceClient(apiResource)
+
+	err := wait.PollImmediate(c.waitInterval, wait.ForeverTestTimeout, func() (bool, error) {
+		var err error
+		if fedObject == nil {
+			fedObject, err = client.Resources(namespace).Get(name, metav1.GetOptions{})
+			if err != nil {
+				c.tl.Logf("Error retrieving %s %q to add the %q annotation: %v", federatedKind, qualifiedName, annotationKey, err)
+				return false, nil
+			}
+		}
+		// Set the annotation if necessary
+		annotations := fedObject.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		if annotations[annotationKey] == "true" {
+			return true, nil
+		}
+		annotations[annotationKey] = "true"
+		fedObject.SetAnnotations(annotations)
+		fedObject, err = client.Resources(namespace).Update(fedObject, metav1.UpdateOptions{})
+		if err == nil {
+			return true, nil
+		}
+		c.tl.Logf("Will retry updating %s %q to include the %q annotation after error: %v", federatedKind, qualifiedName, annotationKey, err)
+		// Clear fedObject to ensure its attempted retrieval in the next iteration
+		fedObject = nil
+		return false, nil
+	})
+	if err != nil {
+		c.tl.Fatalf("Timed out trying to add %q annotation to %s %q", annotationKey, federatedKind, qualifiedName)
+	}
+}
+
 // CheckPropagation checks propagation for the crud tester's clients
 func (c *FederatedTypeCrudTester) CheckPropagation(fedObject *unstructured.Unstructured) {
 	federatedKind := c.typeConfig.GetFederatedType().Kind
@@ -27,7 +27,6 @@ import (
 
 	"sigs.k8s.io/kubefed/pkg/apis/core/typeconfig"
 	"sigs.k8s.io/kubefed/pkg/controller/util"
-	"sigs.k8s.io/kubefed/pkg/kubefedctl/federate"
 )
 
 func NewTestObject(typeConfig typeconfig.Interface, namespace string, clusterNames []string, fixture *unstructured.Unstructured) (*unstructured.Unstructured, error) {
@@ -87,13 +86,13 @@ func NewTestTargetObject(typeConfig typeconfig.Interface, namespace string, fixt
 		obj.Object = template.(map[string]interface{})
 	}
 
-	federate.SetBasicMetaFields(obj, typeConfig.GetTargetType(), "", namespace, "test-e2e-")
+	util.SetBasicMetaFields(obj, typeConfig.GetTargetType(), "", namespace, "test-e2e-")
 	return obj, nil
 }
 
 func newTestUnstructured(apiResource metav1.APIResource, namespace string) *unstructured.Unstructured {
 	obj := &unstructured.Unstructured{}
-	federate.SetBasicMetaFields(obj, apiResource, "", namespace, "test-e2e-")
+	util.SetBasicMetaFields(obj, apiResource, "", namespace, "test-e2e-")
 	return obj
 }
 
@@ -30,7 +30,6 @@ import (
 	"sigs.k8s.io/kubefed/pkg/apis/core/typeconfig"
 	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
 	"sigs.k8s.io/kubefed/pkg/controller/util"
-	"sigs.k8s.io/kubefed/pkg/kubefedctl/federate"
 	"sigs.k8s.io/kubefed/test/common"
 	"sigs.k8s.io/kubefed/test/e2e/framework"
 
@@ -149,7 +148,7 @@ var _ = Describe("Federated", func() {
 				}()
 
 				By("Intitializing a federated resource with placement excluding all clusters")
-				fedObject, err := federate.FederatedResourceFromTargetResource(typeConfig, unlabeledObj)
+				fedObject, err := util.FederatedResourceFromTargetResource(typeConfig, unlabeledObj)
 				if err != nil {
 					tl.Fatalf("Error generating federated resource: %v", err)
 				}
@@ -264,8 +264,8 @@ func validateTemplateEquality(tl common.TestLogger, fedResource, targetResource
 
 	expectedResource := &unstructured.Unstructured{}
 	expectedResource.Object = templateMap.(map[string]interface{})
-	federate.RemoveUnwantedFields(expectedResource)
-	federate.RemoveUnwantedFields(targetResource)
+	util.RemoveUnwantedFields(expectedResource)
+	util.RemoveUnwantedFields(targetResource)
 	if kind == util.NamespaceKind {
 		unstructured.RemoveNestedField(targetResource.Object, "spec", "finalizers")
 	}
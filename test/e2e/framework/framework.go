@@ -32,6 +32,7 @@ import (
 	"sigs.k8s.io/kubefed/pkg/apis/core/typeconfig"
 	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
 	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
+	"sigs.k8s.io/kubefed/pkg/controller/sync"
 	"sigs.k8s.io/kubefed/pkg/controller/util"
 	"sigs.k8s.io/kubefed/test/common"
 
@@ -254,6 +255,9 @@ func (f *frameworkWrapper) EnsureTestFederatedNamespace(allClusters bool) *unstr
 	// Othewise create it.
 	obj.SetName(namespace)
 	obj.SetNamespace(namespace)
+	// Ensure the test namespace is removed from member clusters when
+	// the federated namespace is deleted with the test namespace.
+	obj.SetAnnotations(map[string]string{sync.ConfirmNamespaceDeletion: "true"})
 	if allClusters {
 		// An empty cluster selector field selects all clusters
 		obj.Object[util.SpecField] = map[string]interface{}{
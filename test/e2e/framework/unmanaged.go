@@ -36,6 +36,7 @@ import (
 	fedv1b1 "sigs.k8s.io/kubefed/pkg/apis/core/v1beta1"
 	genericclient "sigs.k8s.io/kubefed/pkg/client/generic"
 	"sigs.k8s.io/kubefed/pkg/controller/util"
+	"sigs.k8s.io/kubefed/pkg/testenv"
 	"sigs.k8s.io/kubefed/test/common"
 
 	. "github.com/onsi/ginkgo"
@@ -239,19 +240,21 @@ func (f *UnmanagedFramework) ClusterConfigs(userAgent string) map[string]common.
 
 	By("Obtaining a list of federated clusters")
 	client := f.Client(userAgent)
-	clusterList := ListKubeFedClusters(NewE2ELogger(), client, TestContext.KubeFedSystemNamespace)
+	configs, err := testenv.ClusterConfigs(client, f.Config, TestContext.KubeFedSystemNamespace)
+	Expect(err).NotTo(HaveOccurred())
+	if len(configs) == 0 {
+		Failf("No federated clusters found")
+	}
 
 	// Assume host cluster name is the same as the current context name.
 	hostClusterName := f.Kubeconfig.CurrentContext
 
 	clusterConfigs := make(map[string]common.TestClusterConfig)
-	for _, cluster := range clusterList.Items {
-		config, err := util.BuildClusterConfig(&cluster, client, TestContext.KubeFedSystemNamespace)
-		Expect(err).NotTo(HaveOccurred())
+	for clusterName, config := range configs {
 		restclient.AddUserAgent(config, userAgent)
-		clusterConfigs[cluster.Name] = common.TestClusterConfig{
+		clusterConfigs[clusterName] = common.TestClusterConfig{
 			Config:    config,
-			IsPrimary: (cluster.Name == hostClusterName),
+			IsPrimary: (clusterName == hostClusterName),
 		}
 	}
 
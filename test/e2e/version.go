@@ -154,6 +154,10 @@ func (r *testVersionedResource) OverrideVersion() (string, error) {
 	return r.overrideVersion, nil
 }
 
+func (r *testVersionedResource) NameForCluster(clusterName string) string {
+	return r.federatedName.Name
+}
+
 func newTestVersionAdapter(client genericclient.Client, kubeClient kubeclientset.Interface, namespaced bool) testVersionAdapter {
 	adapter := version.NewVersionAdapter(namespaced)
 	if namespaced {